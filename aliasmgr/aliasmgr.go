@@ -0,0 +1,365 @@
+package aliasmgr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// aliasBucket stores aliases as keys and their base SCIDs as values.
+	aliasBucket = []byte("alias-bucket")
+
+	// peerAliasBucket stores the alias our peer wants us to use for a
+	// channel, keyed by the channel's ChannelID.
+	peerAliasBucket = []byte("peer-alias-bucket")
+
+	// aliasAllocBucket is a root-level bucket that stores the last alias
+	// that was allocated.
+	aliasAllocBucket = []byte("alias-alloc-bucket")
+
+	// lastAliasKey is a key in the aliasAllocBucket whose value is the
+	// last allocated alias ShortChannelID.
+	lastAliasKey = []byte("last-alias-key")
+
+	// byteOrder denotes the byte order of database (de)-serialization
+	// operations.
+	byteOrder = binary.BigEndian
+
+	// ErrNoBase is returned when a base SCID isn't found for an alias.
+	ErrNoBase = fmt.Errorf("no base scid found for alias")
+
+	// ErrNoPeerAlias is returned when the peer hasn't sent us an alias for
+	// the channel.
+	ErrNoPeerAlias = fmt.Errorf("no peer alias found")
+
+	// ErrAliasesExhausted is returned when the alias range has been used
+	// up.
+	ErrAliasesExhausted = fmt.Errorf("alias range exhausted")
+)
+
+const (
+	// startBlockHeight is the starting block height of the alias range.
+	startBlockHeight = 16_000_000
+
+	// endBlockHeight is the ending block height of the alias range.
+	endBlockHeight = 16_250_000
+)
+
+// StartingAlias is the first alias ShortChannelID that will get used by the
+// alias manager. The block height is far in the future so that aliases can't
+// clash with confirmed channels.
+var StartingAlias = lnwire.ShortChannelID{
+	BlockHeight: startBlockHeight,
+	TxIndex:     0,
+	TxPosition:  0,
+}
+
+// IsAlias returns true if the passed ShortChannelID is within the alias
+// range.
+func IsAlias(scid lnwire.ShortChannelID) bool {
+	return scid.BlockHeight >= startBlockHeight &&
+		scid.BlockHeight < endBlockHeight
+}
+
+// Manager is a struct that handles aliases for channels. A channel that uses
+// an alias is identified by its base SCID, which is the ShortChannelID
+// stored in the database. Other aliases, as well as the confirmed SCID of a
+// zero-conf channel, map back to the base so that forwards using any of them
+// can be resolved to the channel's link.
+type Manager struct {
+	backend kvdb.Backend
+
+	// baseToSet is a mapping from the base SCID to the set of aliases
+	// that can be used for the channel.
+	baseToSet map[lnwire.ShortChannelID][]lnwire.ShortChannelID
+
+	// aliasToBase is a mapping from an alias back to its base SCID.
+	aliasToBase map[lnwire.ShortChannelID]lnwire.ShortChannelID
+
+	// peerAlias is a mapping from a channel to the alias our peer wants
+	// us to use in routing hints for the channel.
+	peerAlias map[lnwire.ChannelID]lnwire.ShortChannelID
+
+	sync.RWMutex
+}
+
+// NewManager initializes an alias Manager from the passed database backend.
+func NewManager(db kvdb.Backend) (*Manager, error) {
+	m := &Manager{
+		backend:     db,
+		baseToSet:   make(map[lnwire.ShortChannelID][]lnwire.ShortChannelID),
+		aliasToBase: make(map[lnwire.ShortChannelID]lnwire.ShortChannelID),
+		peerAlias:   make(map[lnwire.ChannelID]lnwire.ShortChannelID),
+	}
+
+	if err := m.populateMaps(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// populateMaps reads the database state and populates the in-memory maps.
+func (m *Manager) populateMaps() error {
+	baseToSet := make(map[lnwire.ShortChannelID][]lnwire.ShortChannelID)
+	aliasToBase := make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
+	peerAlias := make(map[lnwire.ChannelID]lnwire.ShortChannelID)
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		aliases, err := tx.CreateTopLevelBucket(aliasBucket)
+		if err != nil {
+			return err
+		}
+
+		err = aliases.ForEach(func(k, v []byte) error {
+			alias := lnwire.NewShortChanIDFromInt(byteOrder.Uint64(k))
+			base := lnwire.NewShortChanIDFromInt(byteOrder.Uint64(v))
+
+			baseToSet[base] = append(baseToSet[base], alias)
+			aliasToBase[alias] = base
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		peerAliases, err := tx.CreateTopLevelBucket(peerAliasBucket)
+		if err != nil {
+			return err
+		}
+
+		err = peerAliases.ForEach(func(k, v []byte) error {
+			var chanID lnwire.ChannelID
+			copy(chanID[:], k)
+
+			peerAlias[chanID] = lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(v),
+			)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(aliasAllocBucket)
+		return err
+	}, func() {
+		baseToSet = make(map[lnwire.ShortChannelID][]lnwire.ShortChannelID)
+		aliasToBase = make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
+		peerAlias = make(map[lnwire.ChannelID]lnwire.ShortChannelID)
+	})
+	if err != nil {
+		return err
+	}
+
+	m.baseToSet = baseToSet
+	m.aliasToBase = aliasToBase
+	m.peerAlias = peerAlias
+
+	return nil
+}
+
+// AddLocalAlias adds a database mapping from the passed alias to the passed
+// base SCID.
+func (m *Manager) AddLocalAlias(alias, baseScid lnwire.ShortChannelID) error {
+	m.Lock()
+	defer m.Unlock()
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		aliases, err := tx.CreateTopLevelBucket(aliasBucket)
+		if err != nil {
+			return err
+		}
+
+		var aliasBytes, baseBytes [8]byte
+		byteOrder.PutUint64(aliasBytes[:], alias.ToUint64())
+		byteOrder.PutUint64(baseBytes[:], baseScid.ToUint64())
+
+		return aliases.Put(aliasBytes[:], baseBytes[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	// Only add the alias to the set once, so that re-adding an existing
+	// mapping is a no-op.
+	if _, ok := m.aliasToBase[alias]; !ok {
+		m.baseToSet[baseScid] = append(m.baseToSet[baseScid], alias)
+	}
+	m.aliasToBase[alias] = baseScid
+
+	return nil
+}
+
+// GetAliases fetches the set of aliases stored under a given base SCID.
+func (m *Manager) GetAliases(
+	base lnwire.ShortChannelID) []lnwire.ShortChannelID {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	aliasSet, ok := m.baseToSet[base]
+	if !ok {
+		return nil
+	}
+
+	// Copy the set so callers can't modify our state.
+	aliasCopy := make([]lnwire.ShortChannelID, len(aliasSet))
+	copy(aliasCopy, aliasSet)
+
+	return aliasCopy
+}
+
+// FindBaseSCID finds the base SCID for a given alias. ErrNoBase is returned
+// if the alias is unknown.
+func (m *Manager) FindBaseSCID(
+	alias lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	base, ok := m.aliasToBase[alias]
+	if !ok {
+		return lnwire.ShortChannelID{}, ErrNoBase
+	}
+
+	return base, nil
+}
+
+// PutPeerAlias stores the peer's alias SCID once we learn of it in the
+// funding_locked message.
+func (m *Manager) PutPeerAlias(chanID lnwire.ChannelID,
+	alias lnwire.ShortChannelID) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		peerAliases, err := tx.CreateTopLevelBucket(peerAliasBucket)
+		if err != nil {
+			return err
+		}
+
+		var scratch [8]byte
+		byteOrder.PutUint64(scratch[:], alias.ToUint64())
+
+		return peerAliases.Put(chanID[:], scratch[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	m.peerAlias[chanID] = alias
+
+	return nil
+}
+
+// GetPeerAlias retrieves the peer's alias SCID for a given channel.
+// ErrNoPeerAlias is returned if the peer hasn't sent us an alias.
+func (m *Manager) GetPeerAlias(
+	chanID lnwire.ChannelID) (lnwire.ShortChannelID, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	alias, ok := m.peerAlias[chanID]
+	if !ok || alias == (lnwire.ShortChannelID{}) {
+		return lnwire.ShortChannelID{}, ErrNoPeerAlias
+	}
+
+	return alias, nil
+}
+
+// RequestAlias returns a new ALIAS ShortChannelID to the caller by
+// allocating the next un-allocated ShortChannelID. The last-allocated
+// ShortChannelID is stored in the database.
+func (m *Manager) RequestAlias() (lnwire.ShortChannelID, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var nextAlias lnwire.ShortChannelID
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(aliasAllocBucket)
+		if err != nil {
+			return err
+		}
+
+		lastBytes := bucket.Get(lastAliasKey)
+		if lastBytes == nil {
+			// This is our first time calling RequestAlias, so we
+			// hand out the starting alias.
+			nextAlias = StartingAlias
+		} else {
+			lastScid := lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(lastBytes),
+			)
+			nextAlias = getNextScid(lastScid)
+		}
+
+		if !IsAlias(nextAlias) {
+			return ErrAliasesExhausted
+		}
+
+		var scratch [8]byte
+		byteOrder.PutUint64(scratch[:], nextAlias.ToUint64())
+
+		return bucket.Put(lastAliasKey, scratch[:])
+	}, func() {
+		nextAlias = lnwire.ShortChannelID{}
+	})
+	if err != nil {
+		return lnwire.ShortChannelID{}, err
+	}
+
+	return nextAlias, nil
+}
+
+// ListAliases returns a carbon copy of baseToSet. This is used by the rpc
+// layer.
+func (m *Manager) ListAliases() map[lnwire.ShortChannelID][]lnwire.ShortChannelID { // nolint:lll
+	m.RLock()
+	defer m.RUnlock()
+
+	baseCopy := make(map[lnwire.ShortChannelID][]lnwire.ShortChannelID)
+
+	for k, v := range m.baseToSet {
+		setCopy := make([]lnwire.ShortChannelID, len(v))
+		copy(setCopy, v)
+		baseCopy[k] = setCopy
+	}
+
+	return baseCopy
+}
+
+// getNextScid is a utility function that returns the next SCID for a given
+// alias SCID. The TxPosition is incremented first, overflowing into the
+// TxIndex and finally the BlockHeight.
+func getNextScid(last lnwire.ShortChannelID) lnwire.ShortChannelID {
+	var (
+		next                 = last
+		maxTxPosition uint16 = 1<<16 - 1
+		maxTxIndex    uint32 = 1<<24 - 1
+	)
+
+	switch {
+	case last.TxPosition < maxTxPosition:
+		next.TxPosition++
+
+	case last.TxIndex < maxTxIndex:
+		next.TxPosition = 0
+		next.TxIndex++
+
+	default:
+		next.TxPosition = 0
+		next.TxIndex = 0
+		next.BlockHeight++
+	}
+
+	return next
+}
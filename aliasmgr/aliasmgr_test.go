@@ -0,0 +1,188 @@
+package aliasmgr
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// makeTestBackend creates a fresh database backend for the alias manager.
+func makeTestBackend(t *testing.T) (kvdb.Backend, func()) {
+	tempDir, err := ioutil.TempDir("", "aliasmgr")
+	require.NoError(t, err)
+
+	db, cleanUp, err := kvdb.GetTestBackend(tempDir, "aliasmgr")
+	require.NoError(t, err)
+
+	return db, func() {
+		db.Close()
+		cleanUp()
+		os.RemoveAll(tempDir)
+	}
+}
+
+// TestAliasStorePeerAlias tests that putting and retrieving a peer's alias
+// works properly and survives a restart.
+func TestAliasStorePeerAlias(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestBackend(t)
+	defer cleanUp()
+
+	aliasStore, err := NewManager(db)
+	require.NoError(t, err)
+
+	var chanID1 [32]byte
+	chanID1[0] = 0x01
+
+	_, err = aliasStore.GetPeerAlias(chanID1)
+	require.Equal(t, ErrNoPeerAlias, err)
+
+	startingAlias := lnwire.ShortChannelID{
+		BlockHeight: 16_000_000,
+		TxIndex:     0,
+		TxPosition:  0,
+	}
+
+	err = aliasStore.PutPeerAlias(chanID1, startingAlias)
+	require.NoError(t, err)
+
+	alias, err := aliasStore.GetPeerAlias(chanID1)
+	require.NoError(t, err)
+	require.Equal(t, startingAlias, alias)
+
+	// The alias should be loaded from disk on restart.
+	aliasStore, err = NewManager(db)
+	require.NoError(t, err)
+
+	alias, err = aliasStore.GetPeerAlias(chanID1)
+	require.NoError(t, err)
+	require.Equal(t, startingAlias, alias)
+}
+
+// TestAliasStoreRequest tests the RequestAlias function to ensure that it
+// hands out unique aliases in order.
+func TestAliasStoreRequest(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestBackend(t)
+	defer cleanUp()
+
+	aliasStore, err := NewManager(db)
+	require.NoError(t, err)
+
+	alias, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	require.Equal(t, StartingAlias, alias)
+	require.True(t, IsAlias(alias))
+
+	alias, err = aliasStore.RequestAlias()
+	require.NoError(t, err)
+	require.Equal(t, getNextScid(StartingAlias), alias)
+
+	// A restart shouldn't lead to aliases being handed out twice.
+	aliasStore, err = NewManager(db)
+	require.NoError(t, err)
+
+	nextAlias, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	require.Equal(t, getNextScid(alias), nextAlias)
+}
+
+// TestAliasStoreLocalAlias tests that local aliases map to their base SCID
+// and are restored on restart.
+func TestAliasStoreLocalAlias(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestBackend(t)
+	defer cleanUp()
+
+	aliasStore, err := NewManager(db)
+	require.NoError(t, err)
+
+	base := StartingAlias
+	realScid := lnwire.ShortChannelID{
+		BlockHeight: 700_000,
+		TxIndex:     10,
+		TxPosition:  1,
+	}
+
+	_, err = aliasStore.FindBaseSCID(realScid)
+	require.Equal(t, ErrNoBase, err)
+
+	require.NoError(t, aliasStore.AddLocalAlias(base, base))
+	require.NoError(t, aliasStore.AddLocalAlias(realScid, base))
+
+	// Adding the same mapping twice shouldn't duplicate it.
+	require.NoError(t, aliasStore.AddLocalAlias(realScid, base))
+
+	expected := []lnwire.ShortChannelID{base, realScid}
+	require.Equal(t, expected, aliasStore.GetAliases(base))
+
+	foundBase, err := aliasStore.FindBaseSCID(realScid)
+	require.NoError(t, err)
+	require.Equal(t, base, foundBase)
+
+	aliasStore, err = NewManager(db)
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, expected, aliasStore.GetAliases(base))
+	require.Len(t, aliasStore.ListAliases(), 1)
+}
+
+// TestGetNextScid tests that getNextScid returns the expected SCID values
+// when overflowing.
+func TestGetNextScid(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  lnwire.ShortChannelID
+		expected lnwire.ShortChannelID
+	}{
+		{
+			name:    "starting alias",
+			current: StartingAlias,
+			expected: lnwire.ShortChannelID{
+				BlockHeight: startBlockHeight,
+				TxIndex:     0,
+				TxPosition:  1,
+			},
+		},
+		{
+			name: "tx position rollover",
+			current: lnwire.ShortChannelID{
+				BlockHeight: startBlockHeight,
+				TxIndex:     0,
+				TxPosition:  1<<16 - 1,
+			},
+			expected: lnwire.ShortChannelID{
+				BlockHeight: startBlockHeight,
+				TxIndex:     1,
+				TxPosition:  0,
+			},
+		},
+		{
+			name: "tx index rollover",
+			current: lnwire.ShortChannelID{
+				BlockHeight: startBlockHeight,
+				TxIndex:     1<<24 - 1,
+				TxPosition:  1<<16 - 1,
+			},
+			expected: lnwire.ShortChannelID{
+				BlockHeight: startBlockHeight + 1,
+				TxIndex:     0,
+				TxPosition:  0,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, getNextScid(test.current))
+		})
+	}
+}
@@ -304,7 +304,12 @@ func convertToSecondLevelRevoke(bo *breachedOutput, breachInfo *retributionInfo,
 
 	// In this case, we'll modify the witness type of this output to
 	// actually prepare for a second level revoke.
-	bo.witnessType = input.HtlcSecondLevelRevoke
+	isTaproot := bo.signDesc.SignMethod.IsTaproot()
+	if isTaproot {
+		bo.witnessType = input.TaprootHtlcSecondLevelRevoke
+	} else {
+		bo.witnessType = input.HtlcSecondLevelRevoke
+	}
 
 	// We'll also redirect the outpoint to this second level output, so the
 	// spending transaction updates it inputs accordingly.
@@ -325,8 +330,15 @@ func convertToSecondLevelRevoke(bo *breachedOutput, breachInfo *retributionInfo,
 	bo.signDesc.Output.PkScript = spendingTx.TxOut[0].PkScript
 
 	// Finally, we'll need to adjust the witness program in the
-	// SignDescriptor.
-	bo.signDesc.WitnessScript = bo.secondLevelWitnessScript
+	// SignDescriptor. The revocation key is the internal key of a taproot
+	// second level output, which has the delay leaf as its only leaf, so
+	// we sweep it over the key path tweaked with the hash of that leaf.
+	if isTaproot {
+		tapTweak := input.TapLeafHash(bo.secondLevelWitnessScript)
+		bo.signDesc.TapTweak = tapTweak[:]
+	} else {
+		bo.signDesc.WitnessScript = bo.secondLevelWitnessScript
+	}
 
 	brarLog.Warnf("HTLC(%v) for ChannelPoint(%v) has been spent to the "+
 		"second-level, adjusting -> %v", oldOp, breachInfo.chanPoint,
@@ -482,9 +494,10 @@ func updateBreachInfo(breachInfo *retributionInfo, spends []spend) (
 		txIn := s.detail.SpendingTx.TxIn[s.detail.SpenderInputIndex]
 
 		switch breachedOutput.witnessType {
-		case input.HtlcAcceptedRevoke:
-			fallthrough
-		case input.HtlcOfferedRevoke:
+		case input.HtlcAcceptedRevoke, input.HtlcOfferedRevoke,
+			input.TaprootHtlcAcceptedRevoke,
+			input.TaprootHtlcOfferedRevoke:
+
 			// If the HTLC output was spent using the revocation
 			// key, it is our own spend, and we can forget the
 			// output. Otherwise it has been taken to the second
@@ -530,7 +543,9 @@ func updateBreachInfo(breachInfo *retributionInfo, spends []spend) (
 		// contributes to the value of funds being revoked from
 		// the counter party.
 		case input.CommitmentRevoke, input.HtlcSecondLevelRevoke,
-			input.HtlcOfferedRevoke:
+			input.HtlcOfferedRevoke, input.TaprootCommitmentRevoke,
+			input.TaprootHtlcSecondLevelRevoke,
+			input.TaprootHtlcOfferedRevoke:
 
 			revokedFunds += breachedOutput.Amount()
 		}
@@ -1008,7 +1023,10 @@ func (bo *breachedOutput) CraftInputScript(signer input.Signer, txn *wire.MsgTx,
 func (bo *breachedOutput) BlocksToMaturity() uint32 {
 	// If the output is a to_remote output we can claim, and it's of the
 	// confirmed type, we must wait one block before claiming it.
-	if bo.witnessType == input.CommitmentToRemoteConfirmed {
+	switch bo.witnessType {
+	case input.CommitmentToRemoteConfirmed,
+		input.TaprootCommitmentToRemoteConfirmed:
+
 		return 1
 	}
 
@@ -1080,6 +1098,11 @@ func newRetributionInfo(chanPoint *wire.OutPoint,
 			witnessType = input.CommitmentToRemoteConfirmed
 		}
 
+		// Taproot to_remote outputs are always delayed by one block.
+		if breachInfo.LocalOutputSignDesc.SignMethod.IsTaproot() {
+			witnessType = input.TaprootCommitmentToRemoteConfirmed
+		}
+
 		localOutput := makeBreachedOutput(
 			&breachInfo.LocalOutpoint,
 			witnessType,
@@ -1099,9 +1122,14 @@ func newRetributionInfo(chanPoint *wire.OutPoint,
 	// CommitmentRevoke, since we will be using a revoke key, withdrawing
 	// the funds from the commitment transaction immediately.
 	if breachInfo.RemoteOutputSignDesc != nil {
+		witnessType := input.CommitmentRevoke
+		if breachInfo.RemoteOutputSignDesc.SignMethod.IsTaproot() {
+			witnessType = input.TaprootCommitmentRevoke
+		}
+
 		remoteOutput := makeBreachedOutput(
 			&breachInfo.RemoteOutpoint,
-			input.CommitmentRevoke,
+			witnessType,
 			// No second level script as this is a commitment
 			// output.
 			nil,
@@ -1121,9 +1149,18 @@ func newRetributionInfo(chanPoint *wire.OutPoint,
 		// appropriate witness type that needs to be generated in order
 		// to sweep the HTLC output.
 		var htlcWitnessType input.StandardWitnessType
-		if breachedHtlc.IsIncoming {
+		isTaproot := breachedHtlc.SignDesc.SignMethod.IsTaproot()
+		switch {
+		case isTaproot && breachedHtlc.IsIncoming:
+			htlcWitnessType = input.TaprootHtlcAcceptedRevoke
+
+		case isTaproot:
+			htlcWitnessType = input.TaprootHtlcOfferedRevoke
+
+		case breachedHtlc.IsIncoming:
 			htlcWitnessType = input.HtlcAcceptedRevoke
-		} else {
+
+		default:
 			htlcWitnessType = input.HtlcOfferedRevoke
 		}
 
@@ -1187,12 +1224,16 @@ func (b *breachArbiter) createJusticeTx(
 		allInputs = append(allInputs, inp)
 
 		// Check if the input is from an HTLC or a commitment output.
-		if inp.WitnessType() == input.HtlcAcceptedRevoke ||
-			inp.WitnessType() == input.HtlcOfferedRevoke ||
-			inp.WitnessType() == input.HtlcSecondLevelRevoke {
+		switch inp.WitnessType() {
+		case input.HtlcAcceptedRevoke, input.HtlcOfferedRevoke,
+			input.HtlcSecondLevelRevoke,
+			input.TaprootHtlcAcceptedRevoke,
+			input.TaprootHtlcOfferedRevoke,
+			input.TaprootHtlcSecondLevelRevoke:
 
 			htlcInputs = append(htlcInputs, inp)
-		} else {
+
+		default:
 			commitInputs = append(commitInputs, inp)
 		}
 	}
@@ -1335,6 +1376,9 @@ func (b *breachArbiter) sweepSpendableOutputsTxn(txWeight int64,
 	// signing SigHashAll inputs.
 	hashCache := txscript.NewTxSigHashes(txn)
 
+	// Taproot inputs commit to all the outputs spent by the transaction.
+	input.SetPrevOutputFetcher(inputs...)
+
 	// Create a closure that encapsulates the process of initializing a
 	// particular output's witness generation function, computing the
 	// witness, and attaching it to the transaction. This function accepts
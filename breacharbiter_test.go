@@ -1269,6 +1269,90 @@ func TestBreachCreateJusticeTx(t *testing.T) {
 	require.Len(t, justiceTxs.spendHTLCs.TxIn, 3)
 }
 
+// TestConvertToSecondLevelRevokeTaproot tests that a revoked taproot HTLC
+// output that the remote party took to the second level is swept from the
+// second level output over the key path.
+func TestConvertToSecondLevelRevokeTaproot(t *testing.T) {
+	revokePriv, revokePub := btcec.PrivKeyFromBytes(
+		btcec.S256(), channels.AlicesPrivKey,
+	)
+	_, delayPub := btcec.PrivKeyFromBytes(
+		btcec.S256(), channels.BobsPrivKey,
+	)
+
+	// The revoked HTLC output is swept over the key path of the
+	// revocation key, which we mimic with an arbitrary tap tweak here.
+	htlcPkScript := bytes.Repeat([]byte{1}, input.P2TRSize)
+	signDesc := input.SignDescriptor{
+		KeyDesc: keychain.KeyDescriptor{
+			PubKey: revokePub,
+		},
+		Output: &wire.TxOut{
+			PkScript: htlcPkScript,
+			Value:    100000,
+		},
+		SignMethod: input.TaprootKeySpendSignMethod,
+		TapTweak:   bytes.Repeat([]byte{2}, 32),
+		HashType:   input.SigHashDefault,
+	}
+
+	secondLevelTree, err := input.TaprootSecondLevelHtlcScript(
+		revokePub, delayPub, 144,
+	)
+	require.NoError(t, err)
+
+	bo := makeBreachedOutput(
+		&breachOutPoints[0], input.TaprootHtlcOfferedRevoke,
+		secondLevelTree.Leaves[input.TaprootDelayLeaf], &signDesc, 1,
+	)
+
+	// The remote party spends the HTLC output with their second level
+	// transaction.
+	secondLevelTx := wire.NewMsgTx(2)
+	secondLevelTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: breachOutPoints[0],
+	})
+	secondLevelTx.AddTxOut(&wire.TxOut{
+		PkScript: secondLevelTree.PkScript,
+		Value:    90000,
+	})
+
+	convertToSecondLevelRevoke(
+		&bo, &retributionInfo{}, &chainntnfs.SpendDetail{
+			SpendingTx: secondLevelTx,
+		},
+	)
+
+	require.Equal(
+		t, input.TaprootHtlcSecondLevelRevoke, bo.WitnessType(),
+	)
+	require.Equal(t, secondLevelTx.TxHash(), bo.OutPoint().Hash)
+	require.Equal(t, secondLevelTree.RootHash, bo.SignDesc().TapTweak)
+
+	// Finally, the converted output must be spendable with a valid
+	// signature of the revocation key.
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *bo.OutPoint(),
+	})
+	sweepTx.AddTxOut(&wire.TxOut{
+		PkScript: htlcPkScript,
+		Value:    80000,
+	})
+
+	signer := &mock.SingleSigner{Privkey: revokePriv}
+	witness, err := bo.CraftInputScript(
+		signer, sweepTx, txscript.NewTxSigHashes(sweepTx), 0,
+	)
+	require.NoError(t, err)
+	sweepTx.TxIn[0].Witness = witness.Witness
+
+	prevOuts := input.MultiPrevOutFetcher{
+		*bo.OutPoint(): secondLevelTx.TxOut[0],
+	}
+	require.NoError(t, input.VerifyTaprootSpend(sweepTx, 0, prevOuts))
+}
+
 type publAssertion func(*testing.T, map[wire.OutPoint]struct{},
 	chan *wire.MsgTx, chainhash.Hash) *wire.MsgTx
 
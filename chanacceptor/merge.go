@@ -18,6 +18,7 @@ const (
 	fieldMinIn           = "min htlc in"
 	fieldInFlightTotal   = "in flight total"
 	fieldUpfrontShutdown = "upfront shutdown"
+	fieldZeroConf        = "zero conf"
 )
 
 // fieldMismatchError returns a merge error for a named field when we get two
@@ -148,5 +149,14 @@ func mergeResponse(current, new ChannelAcceptResponse) (ChannelAcceptResponse,
 		return current, err
 	}
 
+	// A zero-conf channel requires a min depth of zero, so we can't merge
+	// a zero-conf response with a response that sets a min depth.
+	current.ZeroConf = current.ZeroConf || new.ZeroConf
+	if current.ZeroConf && current.MinAcceptDepth != 0 {
+		return current, fieldMismatchError(
+			fieldZeroConf, current.ZeroConf, current.MinAcceptDepth,
+		)
+	}
+
 	return current, nil
 }
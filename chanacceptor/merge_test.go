@@ -114,6 +114,30 @@ func TestMergeResponse(t *testing.T) {
 			},
 			err: fieldMismatchError(fieldMinDep, 1, 2),
 		},
+		{
+			name: "zero conf with depth",
+			current: ChannelAcceptResponse{
+				ZeroConf: true,
+			},
+			new: ChannelAcceptResponse{
+				MinAcceptDepth: 3,
+			},
+			err: fieldMismatchError(fieldZeroConf, true, uint16(3)),
+		},
+		{
+			name: "zero conf",
+			current: ChannelAcceptResponse{
+				CSVDelay: 1,
+			},
+			new: ChannelAcceptResponse{
+				ZeroConf: true,
+			},
+			merged: ChannelAcceptResponse{
+				CSVDelay: 1,
+				ZeroConf: true,
+			},
+			err: nil,
+		},
 		{
 			name: "merge all values",
 			current: ChannelAcceptResponse{
@@ -40,6 +40,10 @@ const (
 	// AnchorsZeroFeeHtlcTxCommitVersion is a version that denotes this
 	// channel is using the zero-fee second-level anchor commitment format.
 	AnchorsZeroFeeHtlcTxCommitVersion = 3
+
+	// SimpleTaprootVersion is a version that denotes this channel is
+	// using the simple taproot commitment format.
+	SimpleTaprootVersion = 4
 )
 
 // Single is a static description of an existing channel that can be used for
@@ -177,6 +181,9 @@ func NewSingle(channel *channeldb.OpenChannel,
 	}
 
 	switch {
+	case channel.ChanType.IsTaproot():
+		single.Version = SimpleTaprootVersion
+
 	case channel.ChanType.ZeroHtlcTxFee():
 		single.Version = AnchorsZeroFeeHtlcTxCommitVersion
 
@@ -203,6 +210,7 @@ func (s *Single) Serialize(w io.Writer) error {
 	case TweaklessCommitVersion:
 	case AnchorsCommitVersion:
 	case AnchorsZeroFeeHtlcTxCommitVersion:
+	case SimpleTaprootVersion:
 	default:
 		return fmt.Errorf("unable to serialize w/ unknown "+
 			"version: %v", s.Version)
@@ -363,6 +371,7 @@ func (s *Single) Deserialize(r io.Reader) error {
 	case TweaklessCommitVersion:
 	case AnchorsCommitVersion:
 	case AnchorsZeroFeeHtlcTxCommitVersion:
+	case SimpleTaprootVersion:
 	default:
 		return fmt.Errorf("unable to de-serialize w/ unknown "+
 			"version: %v", s.Version)
@@ -243,6 +243,13 @@ func TestSinglePackUnpack(t *testing.T) {
 			valid:   true,
 		},
 
+		// The simple taproot version, should pack/unpack with no
+		// problem.
+		{
+			version: SimpleTaprootVersion,
+			valid:   true,
+		},
+
 		// A non-default version, atm this should result in a failure.
 		{
 			version: 99,
@@ -294,7 +301,7 @@ func TestSinglePackUnpack(t *testing.T) {
 			}
 
 			rawBytes := rawSingle.Bytes()
-			rawBytes[0] ^= 5
+			rawBytes[0] ^= 0xff
 
 			newReader := bytes.NewReader(rawBytes)
 			err = unpackedSingle.Deserialize(newReader)
@@ -133,6 +133,24 @@ func (c *channelNotifier) SubscribeChans(startingChans map[wire.OutPoint]struct{
 					case <-quit:
 						return
 					}
+
+				// A channel has been moved to a new funding
+				// output by a splice, so we'll replace the
+				// backup of the old channel point.
+				case channelnotifier.SplicedChannelEvent:
+					chanEvent := chanbackup.ChannelEvent{
+						ClosedChans: []wire.OutPoint{
+							event.OldChannelPoint,
+						},
+					}
+
+					select {
+					case chanUpdates <- chanEvent:
+					case <-quit:
+						return
+					}
+
+					sendChanOpenUpdate(event.Channel)
 				}
 
 			// The cancel method has been called, signalling us to
@@ -198,6 +198,14 @@ const (
 	// A tlv type definition used to serialize and deserialize a KeyLocator
 	// from the database.
 	keyLocType tlv.Type = 1
+
+	// A tlv type definition used to serialize and deserialize the
+	// confirmed ShortChannelID of a zero-conf channel.
+	realScidType tlv.Type = 3
+
+	// A tlv type definition used to serialize and deserialize the bits of
+	// a channel's ChannelType that don't fit in its legacy one byte field.
+	chanTypeExtType tlv.Type = 5
 )
 
 // indexStatus is an enum-like type that describes what state the
@@ -219,7 +227,10 @@ const (
 // fee negotiation, channel closing, the format of HTLCs, etc. Structure-wise,
 // a ChannelType is a bit field, with each bit denoting a modification from the
 // base channel type of single funder.
-type ChannelType uint8
+//
+// NOTE: Only the lower eight bits are stored in the channel's info record, any
+// higher bits are persisted in its trailing tlv stream.
+type ChannelType uint64
 
 const (
 	// NOTE: iota isn't used here for this enum needs to be stable
@@ -257,6 +268,20 @@ const (
 	// ZeroHtlcTxFeeBit indicates that the channel should use zero-fee
 	// second-level HTLC transactions.
 	ZeroHtlcTxFeeBit ChannelType = 1 << 5
+
+	// ZeroConfBit indicates that the channel is a zero-conf channel, which
+	// can be used before its funding transaction confirms.
+	ZeroConfBit ChannelType = 1 << 6
+
+	// ScidAliasChanBit indicates that the channel uses an alias as its
+	// ShortChannelID until (and for zero-conf channels, also after) the
+	// funding transaction confirms.
+	ScidAliasChanBit ChannelType = 1 << 7
+
+	// SimpleTaprootBit indicates that the channel uses a P2TR funding
+	// output and taproot scripts for all outputs of its commitment
+	// transactions.
+	SimpleTaprootBit ChannelType = 1 << 8
 )
 
 // IsSingleFunder returns true if the channel type if one of the known single
@@ -294,6 +319,23 @@ func (c ChannelType) ZeroHtlcTxFee() bool {
 	return c&ZeroHtlcTxFeeBit == ZeroHtlcTxFeeBit
 }
 
+// IsZeroConf returns true if the channel is a zero-conf channel.
+func (c ChannelType) IsZeroConf() bool {
+	return c&ZeroConfBit == ZeroConfBit
+}
+
+// HasScidAliasChan returns true if the channel uses an alias as its
+// ShortChannelID.
+func (c ChannelType) HasScidAliasChan() bool {
+	return c&ScidAliasChanBit == ScidAliasChanBit
+}
+
+// IsTaproot returns true if the channel uses a taproot funding output and
+// taproot commitment scripts.
+func (c ChannelType) IsTaproot() bool {
+	return c&SimpleTaprootBit == SimpleTaprootBit
+}
+
 // IsFrozen returns true if the channel is considered to be "frozen". A frozen
 // channel means that only the responder can initiate a cooperative channel
 // closure.
@@ -604,6 +646,12 @@ type OpenChannel struct {
 	// transaction index, and the output within the target transaction.
 	ShortChannelID lnwire.ShortChannelID
 
+	// confirmedScid is the confirmed ShortChannelID of a zero-conf
+	// channel. Zero-conf channels keep using their alias as the
+	// ShortChannelID after the funding transaction confirms, so the real
+	// location of the funding output is stored separately.
+	confirmedScid lnwire.ShortChannelID
+
 	// IsPending indicates whether a channel's funding transaction has been
 	// confirmed.
 	IsPending bool
@@ -992,6 +1040,95 @@ func (c *OpenChannel) MarkAsOpen(openLoc lnwire.ShortChannelID) error {
 	return nil
 }
 
+// ZeroConfRealScid returns the confirmed ShortChannelID of a zero-conf
+// channel. The zero value is returned if the funding transaction hasn't
+// confirmed yet.
+func (c *OpenChannel) ZeroConfRealScid() lnwire.ShortChannelID {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.confirmedScid
+}
+
+// ZeroConfConfirmed returns whether the funding transaction of a zero-conf
+// channel has confirmed.
+func (c *OpenChannel) ZeroConfConfirmed() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.confirmedScid != (lnwire.ShortChannelID{})
+}
+
+// MarkRealScid persists the confirmed ShortChannelID of a zero-conf channel
+// once its funding transaction has confirmed. The ShortChannelID of the
+// channel itself remains the alias it was opened with.
+func (c *OpenChannel) MarkRealScid(realScid lnwire.ShortChannelID) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		channel.confirmedScid = realScid
+
+		return putOpenChannel(chanBucket, channel)
+	}, func() {}); err != nil {
+		return err
+	}
+
+	c.confirmedScid = realScid
+
+	return nil
+}
+
+// UpdateFundingTxn replaces the stored funding transaction of a pending
+// channel. A dual funded channel is persisted before the remote party signed
+// its inputs to the funding transaction, so the fully signed transaction is
+// only known once the signatures have been exchanged.
+func (c *OpenChannel) UpdateFundingTxn(fundingTxn *wire.MsgTx) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if fundingTxn.TxHash() != c.FundingOutpoint.Hash {
+		return fmt.Errorf("funding tx %v doesn't match channel point %v",
+			fundingTxn.TxHash(), c.FundingOutpoint)
+	}
+
+	if err := kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		channel.FundingTxn = fundingTxn
+
+		return putOpenChannel(chanBucket, channel)
+	}, func() {}); err != nil {
+		return err
+	}
+
+	c.FundingTxn = fundingTxn
+
+	return nil
+}
+
 // MarkDataLoss marks sets the channel status to LocalDataLoss and stores the
 // passed commitPoint for use to retrieve funds in case the remote force closes
 // the channel.
@@ -1713,6 +1850,66 @@ type HTLC struct {
 	// from the HtlcIndex as this will be incremented for each new log
 	// update added.
 	LogIndex uint64
+
+	// BlindingPoint is the blinding point that was sent along with an
+	// HTLC that is part of a blinded route. It is nil for HTLCs that
+	// aren't.
+	BlindingPoint *btcec.PublicKey
+}
+
+// htlcBlindingPointType is the type of the TLV record that stores the
+// blinding point of an HTLC.
+const htlcBlindingPointType tlv.Type = 0
+
+// encodeHtlcExtraData returns the TLV stream with the optional fields of the
+// HTLC. It is empty if none of them are set.
+func encodeHtlcExtraData(htlc *HTLC) ([]byte, error) {
+	var records []tlv.Record
+	if htlc.BlindingPoint != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			htlcBlindingPointType, &htlc.BlindingPoint,
+		))
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := tlvStream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeHtlcExtraData decodes the TLV stream with the optional fields of the
+// HTLC into the HTLC.
+func decodeHtlcExtraData(htlc *HTLC, extraData []byte) error {
+	var blindingPoint *btcec.PublicKey
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(htlcBlindingPointType, &blindingPoint),
+	)
+	if err != nil {
+		return err
+	}
+
+	typeMap, err := tlvStream.DecodeWithParsedTypes(
+		bytes.NewReader(extraData),
+	)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[htlcBlindingPointType]; ok && val == nil {
+		htlc.BlindingPoint = blindingPoint
+	}
+
+	return nil
 }
 
 // SerializeHtlcs writes out the passed set of HTLC's into the passed writer
@@ -1727,9 +1924,33 @@ func SerializeHtlcs(b io.Writer, htlcs ...HTLC) error {
 	}
 
 	for _, htlc := range htlcs {
+		// The optional fields are stored as a TLV stream after the
+		// onion blob. As the onion blob of an HTLC has a fixed size,
+		// they can be split off again when reading, and older versions
+		// of the format don't need a migration.
+		extraData, err := encodeHtlcExtraData(&htlc)
+		if err != nil {
+			return err
+		}
+
+		onionBlob := htlc.OnionBlob
+		if len(extraData) > 0 {
+			if len(onionBlob) != lnwire.OnionPacketSize {
+				return fmt.Errorf("onion blob of htlc with "+
+					"extra data must be %v bytes, got %v",
+					lnwire.OnionPacketSize, len(onionBlob))
+			}
+
+			onionBlob = make(
+				[]byte, 0, len(htlc.OnionBlob)+len(extraData),
+			)
+			onionBlob = append(onionBlob, htlc.OnionBlob...)
+			onionBlob = append(onionBlob, extraData...)
+		}
+
 		if err := WriteElements(b,
 			htlc.Signature, htlc.RHash, htlc.Amt, htlc.RefundTimeout,
-			htlc.OutputIndex, htlc.Incoming, htlc.OnionBlob[:],
+			htlc.OutputIndex, htlc.Incoming, onionBlob,
 			htlc.HtlcIndex, htlc.LogIndex,
 		); err != nil {
 			return err
@@ -1766,6 +1987,21 @@ func DeserializeHtlcs(r io.Reader) ([]HTLC, error) {
 		); err != nil {
 			return htlcs, err
 		}
+
+		// Anything after the onion blob is the TLV stream with the
+		// optional fields of the HTLC.
+		onionBlob := htlcs[i].OnionBlob
+		if len(onionBlob) <= lnwire.OnionPacketSize {
+			continue
+		}
+
+		htlcs[i].OnionBlob = onionBlob[:lnwire.OnionPacketSize]
+		err := decodeHtlcExtraData(
+			&htlcs[i], onionBlob[lnwire.OnionPacketSize:],
+		)
+		if err != nil {
+			return htlcs, err
+		}
 	}
 
 	return htlcs, nil
@@ -1778,6 +2014,7 @@ func (h *HTLC) Copy() HTLC {
 		Amt:           h.Amt,
 		RefundTimeout: h.RefundTimeout,
 		OutputIndex:   h.OutputIndex,
+		BlindingPoint: h.BlindingPoint,
 	}
 	copy(clone.Signature[:], h.Signature)
 	copy(clone.RHash[:], h.RHash[:])
@@ -3296,12 +3533,19 @@ func putChanInfo(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
 		return err
 	}
 
-	// Write the RevocationKeyLocator as the first entry in a tlv stream.
+	// Write the RevocationKeyLocator as the first entry in a tlv stream,
+	// followed by the confirmed ShortChannelID of zero-conf channels.
 	keyLocRecord := MakeKeyLocRecord(
 		keyLocType, &channel.RevocationKeyLocator,
 	)
+	realScid := channel.confirmedScid.ToUint64()
+	chanTypeExt := uint64(channel.ChanType >> 8)
 
-	tlvStream, err := tlv.NewStream(keyLocRecord)
+	tlvStream, err := tlv.NewStream(
+		keyLocRecord,
+		tlv.MakePrimitiveRecord(realScidType, &realScid),
+		tlv.MakePrimitiveRecord(chanTypeExtType, &chanTypeExt),
+	)
 	if err != nil {
 		return err
 	}
@@ -3499,8 +3743,13 @@ func fetchChanInfo(chanBucket kvdb.RBucket, channel *OpenChannel) error {
 		}
 	}
 
+	var realScid, chanTypeExt uint64
 	keyLocRecord := MakeKeyLocRecord(keyLocType, &channel.RevocationKeyLocator)
-	tlvStream, err := tlv.NewStream(keyLocRecord)
+	tlvStream, err := tlv.NewStream(
+		keyLocRecord,
+		tlv.MakePrimitiveRecord(realScidType, &realScid),
+		tlv.MakePrimitiveRecord(chanTypeExtType, &chanTypeExt),
+	)
 	if err != nil {
 		return err
 	}
@@ -3509,6 +3758,9 @@ func fetchChanInfo(chanBucket kvdb.RBucket, channel *OpenChannel) error {
 		return err
 	}
 
+	channel.confirmedScid = lnwire.NewShortChanIDFromInt(realScid)
+	channel.ChanType |= ChannelType(chanTypeExt << 8)
+
 	channel.Packager = NewChannelPackager(channel.ShortChannelID)
 
 	// Finally, read the optional shutdown scripts.
@@ -1622,3 +1622,141 @@ func TestKeyLocatorEncoding(t *testing.T) {
 	// version are equal.
 	require.Equal(t, keyLoc, decodedKeyLoc)
 }
+
+// TestHtlcBlindingPointEncoding asserts that the blinding point of an HTLC
+// survives a serialization round trip, and that HTLCs without one are
+// serialized the same way as before.
+func TestHtlcBlindingPointEncoding(t *testing.T) {
+	t.Parallel()
+
+	onionBlob := bytes.Repeat([]byte{2}, lnwire.OnionPacketSize)
+	htlcs := []HTLC{
+		{
+			Signature:     testSig.Serialize(),
+			Incoming:      true,
+			Amt:           10,
+			RHash:         key,
+			RefundTimeout: 1,
+			OnionBlob:     onionBlob,
+			BlindingPoint: pubKey,
+		},
+		{
+			Signature:     testSig.Serialize(),
+			Amt:           20,
+			RHash:         key,
+			RefundTimeout: 2,
+			OnionBlob:     onionBlob,
+			HtlcIndex:     1,
+			LogIndex:      1,
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, SerializeHtlcs(&b, htlcs...))
+
+	decoded, err := DeserializeHtlcs(&b)
+	require.NoError(t, err)
+	require.Equal(t, htlcs, decoded)
+
+	// An HTLC without a blinding point is stored with only its onion
+	// blob.
+	b.Reset()
+	require.NoError(t, SerializeHtlcs(&b, htlcs[1]))
+	require.NotContains(
+		t, string(b.Bytes()), string(pubKey.SerializeCompressed()),
+	)
+
+	// The blinding point can't be split off an onion blob that doesn't
+	// have the size of an onion packet.
+	htlc := htlcs[0]
+	htlc.OnionBlob = []byte("onionblob")
+	require.Error(t, SerializeHtlcs(&b, htlc))
+}
+
+// TestMarkRealScid asserts that the confirmed ShortChannelID of a zero-conf
+// channel is persisted without touching the alias the channel uses as its
+// ShortChannelID.
+func TestMarkRealScid(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	alias := lnwire.ShortChannelID{BlockHeight: 16_000_000}
+	state := createTestChannel(t, cdb)
+	require.NoError(t, state.MarkAsOpen(alias))
+	require.False(t, state.ZeroConfConfirmed())
+
+	realScid := lnwire.ShortChannelID{
+		BlockHeight: 105,
+		TxIndex:     10,
+		TxPosition:  15,
+	}
+	require.NoError(t, state.MarkRealScid(realScid))
+	require.True(t, state.ZeroConfConfirmed())
+	require.Equal(t, realScid, state.ZeroConfRealScid())
+
+	// Reloading the channel from disk should yield the same real scid,
+	// while the alias is still used as the ShortChannelID.
+	openChans, err := cdb.FetchAllChannels()
+	require.NoError(t, err)
+	require.Len(t, openChans, 1)
+	require.Equal(t, realScid, openChans[0].ZeroConfRealScid())
+	require.Equal(t, alias, openChans[0].ShortChanID())
+}
+
+// TestTaprootChanTypeEncoding asserts that channel type bits that don't fit in
+// the legacy one byte field survive a round trip through the database.
+func TestTaprootChanTypeEncoding(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	chanType := SingleFunderTweaklessBit | AnchorOutputsBit |
+		ZeroHtlcTxFeeBit | SimpleTaprootBit
+	state := createTestChannel(t, cdb, func(params *testChannelParams) {
+		params.channel.ChanType = chanType
+	})
+	require.True(t, state.ChanType.IsTaproot())
+
+	openChans, err := cdb.FetchAllChannels()
+	require.NoError(t, err)
+	require.Len(t, openChans, 1)
+	require.Equal(t, chanType, openChans[0].ChanType)
+	require.True(t, openChans[0].ChanType.IsTaproot())
+}
+
+// TestUpdateFundingTxn asserts that the funding transaction of a pending
+// channel can be replaced by a version with more witnesses, but not by an
+// unrelated transaction.
+func TestUpdateFundingTxn(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	fundingTx := channels.TestFundingTx.Copy()
+	fundingTx.TxIn[0].Witness = nil
+	chanPoint := wire.OutPoint{Hash: fundingTx.TxHash()}
+	state := createTestChannel(t, cdb, fundingPointOption(chanPoint))
+
+	// Once the remote party's inputs are signed, the funding transaction
+	// keeps its txid and can be stored in place of the old one.
+	signedTx := fundingTx.Copy()
+	signedTx.TxIn[0].Witness = wire.TxWitness{{0x01}, {0x02}}
+	require.NoError(t, state.UpdateFundingTxn(signedTx))
+
+	pendingChans, err := cdb.FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, pendingChans, 1)
+	require.Equal(t, signedTx, pendingChans[0].FundingTxn)
+
+	// A transaction that doesn't create the channel output is rejected.
+	otherTx := fundingTx.Copy()
+	otherTx.LockTime++
+	require.Error(t, state.UpdateFundingTxn(otherTx))
+}
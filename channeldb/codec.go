@@ -85,7 +85,9 @@ func WriteElement(w io.Writer, element interface{}) error {
 
 		return binary.Write(w, byteOrder, false)
 	case ChannelType:
-		if err := binary.Write(w, byteOrder, e); err != nil {
+		// Only the legacy lower byte is written here, the remaining
+		// bits are stored in the channel's tlv stream.
+		if err := binary.Write(w, byteOrder, uint8(e)); err != nil {
 			return err
 		}
 
@@ -270,9 +272,11 @@ func ReadElement(r io.Reader, element interface{}) error {
 		}
 
 	case *ChannelType:
-		if err := binary.Read(r, byteOrder, e); err != nil {
+		var chanType uint8
+		if err := binary.Read(r, byteOrder, &chanType); err != nil {
 			return err
 		}
+		*e = ChannelType(chanType)
 
 	case *chainhash.Hash:
 		if _, err := io.ReadFull(r, e[:]); err != nil {
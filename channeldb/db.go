@@ -18,6 +18,8 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb/migration16"
 	"github.com/lightningnetwork/lnd/channeldb/migration20"
 	"github.com/lightningnetwork/lnd/channeldb/migration21"
+	"github.com/lightningnetwork/lnd/channeldb/migration23"
+	"github.com/lightningnetwork/lnd/channeldb/migration24"
 	"github.com/lightningnetwork/lnd/channeldb/migration_01_to_11"
 	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/kvdb"
@@ -195,6 +197,18 @@ var (
 			number:    22,
 			migration: mig.CreateTLB(setIDIndexBucket),
 		},
+		{
+			// Index payments and invoices by their creation time,
+			// so that they can be queried by time.
+			number:    23,
+			migration: migration23.MigrateTimeIndex,
+		},
+		{
+			// Sum up the forwarding log per hour and channel pair,
+			// so that it can be queried in aggregate.
+			number:    24,
+			migration: migration24.MigrateForwardingAggregates,
+		},
 	}
 
 	// Big endian is the preferred byte order, due to cursor scans over
@@ -263,7 +277,11 @@ func Open(dbPath string, modifiers ...OptionModifier) (*DB, error) {
 		modifier(&opts)
 	}
 
-	backend, err := kvdb.GetLdbBackend(dbPath, dbName)
+	backend, err := kvdb.GetLdbBackend(&kvdb.LdbBackendConfig{
+		DBPath:     dbPath,
+		DBFileName: dbName,
+		LdbConfig:  opts.LdbConfig,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -315,11 +333,14 @@ var topLevelBuckets = [][]byte{
 	openChannelBucket,
 	closedChannelBucket,
 	forwardingLogBucket,
+	forwardingAggregateBucket,
+	forwardingFailureLogBucket,
 	fwdPackagesKey,
 	invoiceBucket,
 	payAddrIndexBucket,
 	setIDIndexBucket,
 	paymentsIndexBucket,
+	paymentsTimeIndexBucket,
 	peersBucket,
 	nodeInfoBucket,
 	nodeBucket,
@@ -0,0 +1,255 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// forwardingAggregateBucket is the bucket that we'll use to store the
+	// forwarding aggregate index. The index sums up the forwarding log per
+	// hour and channel pair, so that aggregate queries don't need to read
+	// every single forwarding event. Each key within the bucket is the
+	// start of the hour (in seconds since the unix epoch) followed by the
+	// incoming and outgoing channel ID, and the value the number of
+	// forwards, the total incoming amount and the total outgoing amount.
+	forwardingAggregateBucket = []byte("circuit-fwd-aggregate")
+
+	// ErrInvalidAggregateInterval is returned when the interval of an
+	// aggregate query isn't a multiple of ForwardingAggregateResolution.
+	ErrInvalidAggregateInterval = errors.New("aggregate interval must " +
+		"be a multiple of one hour")
+)
+
+const (
+	// ForwardingAggregateResolution is the time resolution of the
+	// forwarding aggregate index. Aggregate queries are rounded to whole
+	// hours.
+	ForwardingAggregateResolution = time.Hour
+
+	// forwardingAggregateKeySize is the size of a key in the forwarding
+	// aggregate index. The breakdown is as follows:
+	//
+	//  * 8 byte hour || 8 byte incoming chan ID || 8 byte outgoing chan ID
+	forwardingAggregateKeySize = 24
+
+	// forwardingAggregateValueSize is the size of a value in the
+	// forwarding aggregate index. The breakdown is as follows:
+	//
+	//  * 8 byte count || 8 byte total value in || 8 byte total value out
+	forwardingAggregateValueSize = 24
+)
+
+// ForwardingAggregateQuery represents a query to the forwarding aggregate
+// index. The start and end time are rounded down to whole hours, so the query
+// covers all hours from the one of the start time up to and including the one
+// of the end time.
+type ForwardingAggregateQuery struct {
+	// StartTime is the start time of the time slice.
+	StartTime time.Time
+
+	// EndTime is the end time of the time slice.
+	EndTime time.Time
+
+	// Interval is the width of the time buckets that the forwards are
+	// grouped in. It must be a multiple of ForwardingAggregateResolution.
+	// If zero, the forwards aren't grouped by time.
+	Interval time.Duration
+}
+
+// ForwardingAggregate sums up the forwards between a pair of channels within a
+// time bucket.
+type ForwardingAggregate struct {
+	// BucketStart is the start of the time bucket. It is the zero time if
+	// the forwards aren't grouped by time.
+	BucketStart time.Time
+
+	// IncomingChanID is the incoming channel ID of the forwards.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the outgoing channel ID of the forwards.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// Count is the number of forwards.
+	Count uint64
+
+	// AmtIn is the total amount of the incoming HTLCs.
+	AmtIn lnwire.MilliSatoshi
+
+	// AmtOut is the total amount of the outgoing HTLCs.
+	AmtOut lnwire.MilliSatoshi
+}
+
+// Fee returns the total fees earned by the forwards.
+func (a *ForwardingAggregate) Fee() lnwire.MilliSatoshi {
+	return a.AmtIn - a.AmtOut
+}
+
+// add adds the forwards of the given aggregate to this aggregate.
+func (a *ForwardingAggregate) add(other *ForwardingAggregate) {
+	a.Count += other.Count
+	a.AmtIn += other.AmtIn
+	a.AmtOut += other.AmtOut
+}
+
+// aggregateKey returns the key of the forwarding aggregate index that the
+// given event is summed up under.
+func aggregateKey(event *ForwardingEvent) []byte {
+	var key [forwardingAggregateKeySize]byte
+
+	hour := event.Timestamp.Truncate(ForwardingAggregateResolution)
+	byteOrder.PutUint64(key[:8], uint64(hour.Unix()))
+	byteOrder.PutUint64(key[8:16], event.IncomingChanID.ToUint64())
+	byteOrder.PutUint64(key[16:], event.OutgoingChanID.ToUint64())
+
+	return key[:]
+}
+
+// decodeAggregate decodes an entry of the forwarding aggregate index.
+func decodeAggregate(key, value []byte) (*ForwardingAggregate, error) {
+	if len(key) != forwardingAggregateKeySize ||
+		len(value) != forwardingAggregateValueSize {
+
+		return nil, errors.New("invalid forwarding aggregate entry")
+	}
+
+	return &ForwardingAggregate{
+		BucketStart: time.Unix(int64(byteOrder.Uint64(key[:8])), 0),
+		IncomingChanID: lnwire.NewShortChanIDFromInt(
+			byteOrder.Uint64(key[8:16]),
+		),
+		OutgoingChanID: lnwire.NewShortChanIDFromInt(
+			byteOrder.Uint64(key[16:]),
+		),
+		Count:  byteOrder.Uint64(value[:8]),
+		AmtIn:  lnwire.MilliSatoshi(byteOrder.Uint64(value[8:16])),
+		AmtOut: lnwire.MilliSatoshi(byteOrder.Uint64(value[16:])),
+	}, nil
+}
+
+// addToAggregateIndex adds a forwarding event to the forwarding aggregate
+// index.
+func addToAggregateIndex(bucket kvdb.RwBucket, event *ForwardingEvent) error {
+	key := aggregateKey(event)
+
+	aggregate := &ForwardingAggregate{}
+	if value := bucket.Get(key); value != nil {
+		var err error
+		aggregate, err = decodeAggregate(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	aggregate.add(&ForwardingAggregate{
+		Count:  1,
+		AmtIn:  event.AmtIn,
+		AmtOut: event.AmtOut,
+	})
+
+	var value [forwardingAggregateValueSize]byte
+	byteOrder.PutUint64(value[:8], aggregate.Count)
+	byteOrder.PutUint64(value[8:16], uint64(aggregate.AmtIn))
+	byteOrder.PutUint64(value[16:], uint64(aggregate.AmtOut))
+
+	return bucket.Put(key, value[:])
+}
+
+// QueryAggregates returns the forwards within the queried time slice, summed
+// up per channel pair and time bucket. The aggregates are sorted by time
+// bucket and channel pair.
+func (f *ForwardingLog) QueryAggregates(
+	q ForwardingAggregateQuery) ([]ForwardingAggregate, error) {
+
+	if q.Interval < 0 || q.Interval%ForwardingAggregateResolution != 0 {
+		return nil, ErrInvalidAggregateInterval
+	}
+
+	// aggregateGroup identifies the aggregate that an entry of the index
+	// is added to.
+	type aggregateGroup struct {
+		bucketStart int64
+		incoming    uint64
+		outgoing    uint64
+	}
+
+	var aggregates []ForwardingAggregate
+	err := kvdb.View(f.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(forwardingAggregateBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		var startHour, endHour [8]byte
+		byteOrder.PutUint64(startHour[:], uint64(q.StartTime.Truncate(
+			ForwardingAggregateResolution,
+		).Unix()))
+		byteOrder.PutUint64(endHour[:], uint64(q.EndTime.Truncate(
+			ForwardingAggregateResolution,
+		).Unix()))
+
+		groups := make(map[aggregateGroup]int)
+
+		cursor := bucket.ReadCursor()
+		key, value := cursor.Seek(startHour[:])
+		for key != nil && bytes.Compare(key[:8], endHour[:]) <= 0 {
+			aggregate, err := decodeAggregate(key, value)
+			if err != nil {
+				return err
+			}
+
+			if q.Interval == 0 {
+				aggregate.BucketStart = time.Time{}
+			} else {
+				aggregate.BucketStart = aggregate.BucketStart.
+					Truncate(q.Interval)
+			}
+
+			group := aggregateGroup{
+				bucketStart: aggregate.BucketStart.Unix(),
+				incoming:    aggregate.IncomingChanID.ToUint64(),
+				outgoing:    aggregate.OutgoingChanID.ToUint64(),
+			}
+
+			if i, ok := groups[group]; ok {
+				aggregates[i].add(aggregate)
+			} else {
+				groups[group] = len(aggregates)
+				aggregates = append(aggregates, *aggregate)
+			}
+
+			key, value = cursor.Next()
+		}
+
+		return nil
+	}, func() {
+		aggregates = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(aggregates, func(i, j int) bool {
+		a, b := aggregates[i], aggregates[j]
+
+		switch {
+		case !a.BucketStart.Equal(b.BucketStart):
+			return a.BucketStart.Before(b.BucketStart)
+
+		case a.IncomingChanID != b.IncomingChanID:
+			return a.IncomingChanID.ToUint64() <
+				b.IncomingChanID.ToUint64()
+
+		default:
+			return a.OutgoingChanID.ToUint64() <
+				b.OutgoingChanID.ToUint64()
+		}
+	})
+
+	return aggregates, nil
+}
@@ -0,0 +1,171 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testAggregateTime  = time.Date(2021, 3, 4, 10, 15, 0, 0, time.UTC)
+	testAggregateChan1 = lnwire.NewShortChanIDFromInt(1)
+	testAggregateChan2 = lnwire.NewShortChanIDFromInt(2)
+	testAggregateChan3 = lnwire.NewShortChanIDFromInt(3)
+)
+
+// testAggregateEvents returns forwarding events that span two days and two
+// channel pairs.
+func testAggregateEvents() []ForwardingEvent {
+	newEvent := func(offset time.Duration, in,
+		out lnwire.ShortChannelID, amtOut int) ForwardingEvent {
+
+		return ForwardingEvent{
+			Timestamp:      testAggregateTime.Add(offset),
+			IncomingChanID: in,
+			OutgoingChanID: out,
+			AmtIn:          lnwire.MilliSatoshi(amtOut + 10),
+			AmtOut:         lnwire.MilliSatoshi(amtOut),
+		}
+	}
+
+	return []ForwardingEvent{
+		// Two forwards in the same hour on the same pair.
+		newEvent(0, testAggregateChan1, testAggregateChan2, 1000),
+		newEvent(time.Minute, testAggregateChan1, testAggregateChan2,
+			2000),
+
+		// A forward on another pair in the same hour.
+		newEvent(time.Minute, testAggregateChan1, testAggregateChan3,
+			3000),
+
+		// A forward in the next hour of the same day.
+		newEvent(time.Hour, testAggregateChan1, testAggregateChan2,
+			4000),
+
+		// A forward on the next day.
+		newEvent(24*time.Hour, testAggregateChan1, testAggregateChan2,
+			5000),
+	}
+}
+
+// assertAggregates queries the forwarding aggregates with different intervals
+// and asserts the results.
+func assertAggregates(t *testing.T, log *ForwardingLog) {
+	t.Helper()
+
+	hour := testAggregateTime.Truncate(time.Hour)
+	day := testAggregateTime.Truncate(24 * time.Hour)
+
+	newAggregate := func(start time.Time, in, out lnwire.ShortChannelID,
+		count uint64, amtOut lnwire.MilliSatoshi) ForwardingAggregate {
+
+		return ForwardingAggregate{
+			BucketStart:    start,
+			IncomingChanID: in,
+			OutgoingChanID: out,
+			Count:          count,
+			AmtIn:          amtOut + lnwire.MilliSatoshi(count*10),
+			AmtOut:         amtOut,
+		}
+	}
+
+	// Without an interval, the forwards are summed up per channel pair.
+	aggregates, err := log.QueryAggregates(ForwardingAggregateQuery{
+		StartTime: testAggregateTime,
+		EndTime:   testAggregateTime.Add(48 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ForwardingAggregate{
+		newAggregate(
+			time.Time{}, testAggregateChan1, testAggregateChan2,
+			4, 12000,
+		),
+		newAggregate(
+			time.Time{}, testAggregateChan1, testAggregateChan3,
+			1, 3000,
+		),
+	}, aggregates)
+	require.EqualValues(t, 40, aggregates[0].Fee())
+
+	// Grouped by hour, the forwards of each hour are summed up. The end
+	// time includes its whole hour.
+	aggregates, err = log.QueryAggregates(ForwardingAggregateQuery{
+		StartTime: testAggregateTime.Add(30 * time.Minute),
+		EndTime:   testAggregateTime.Add(time.Hour),
+		Interval:  time.Hour,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ForwardingAggregate{
+		newAggregate(
+			hour.Local(), testAggregateChan1, testAggregateChan2,
+			2, 3000,
+		),
+		newAggregate(
+			hour.Local(), testAggregateChan1, testAggregateChan3,
+			1, 3000,
+		),
+		newAggregate(
+			hour.Add(time.Hour).Local(), testAggregateChan1,
+			testAggregateChan2, 1, 4000,
+		),
+	}, aggregates)
+
+	// Grouped by day, the forwards of each day are summed up.
+	aggregates, err = log.QueryAggregates(ForwardingAggregateQuery{
+		StartTime: testAggregateTime,
+		EndTime:   testAggregateTime.Add(48 * time.Hour),
+		Interval:  24 * time.Hour,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ForwardingAggregate{
+		newAggregate(
+			day.Local(), testAggregateChan1, testAggregateChan2,
+			3, 7000,
+		),
+		newAggregate(
+			day.Local(), testAggregateChan1, testAggregateChan3,
+			1, 3000,
+		),
+		newAggregate(
+			day.Add(24*time.Hour).Local(), testAggregateChan1,
+			testAggregateChan2, 1, 5000,
+		),
+	}, aggregates)
+
+	// Intervals that aren't whole hours are rejected.
+	_, err = log.QueryAggregates(ForwardingAggregateQuery{
+		Interval: time.Minute,
+	})
+	require.Equal(t, ErrInvalidAggregateInterval, err)
+}
+
+// TestForwardingLogAggregates tests that forwarding events are added to the
+// aggregate index and that aggregate queries sum them up correctly.
+func TestForwardingLogAggregates(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	log := ForwardingLog{
+		db: db,
+	}
+
+	// An empty log has no aggregates.
+	aggregates, err := log.QueryAggregates(ForwardingAggregateQuery{
+		EndTime: testAggregateTime,
+	})
+	require.NoError(t, err)
+	require.Empty(t, aggregates)
+
+	// Add the events in two batches to make sure existing aggregates are
+	// added to.
+	events := testAggregateEvents()
+	require.NoError(t, log.AddForwardingEvents(events[:2]))
+	require.NoError(t, log.AddForwardingEvents(events[2:]))
+
+	assertAggregates(t, &log)
+}
@@ -0,0 +1,289 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// forwardingFailureLogBucket is the bucket that we'll use to store the
+	// failed forwards. Like the forwarding log, it is a time series
+	// database where each key within the bucket is a timestamp (in nano
+	// seconds since the unix epoch), and the value the failed forwarding
+	// events for that timestamp.
+	forwardingFailureLogBucket = []byte("circuit-fwd-failure-log")
+)
+
+// FailedForwardingEvent is an event in the failed forwarding log's time
+// series. Each event logs a forward that was failed back to the incoming
+// channel, either by our node or by a node further down the route.
+type FailedForwardingEvent struct {
+	// Timestamp is the time at which the forward was failed.
+	Timestamp time.Time
+
+	// IncomingChanID is the incoming channel ID of the forward.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the requested outgoing channel ID of the forward.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// AmtIn is the amount of the incoming HTLC.
+	AmtIn lnwire.MilliSatoshi
+
+	// AmtOut is the amount of the outgoing HTLC.
+	AmtOut lnwire.MilliSatoshi
+
+	// Downstream is true if the forward was failed by a node further down
+	// the route. As the failure is encrypted for the sender, the failure
+	// code and detail aren't known in that case.
+	Downstream bool
+
+	// FailureCode is the wire failure code of forwards that were failed
+	// by our node.
+	FailureCode lnwire.FailCode
+
+	// FailureDetail describes why our node failed the forward in more
+	// detail than the wire failure code, if available.
+	FailureDetail string
+}
+
+// encodeFailedForwardingEvent writes out the target failed forwarding event to
+// the passed io.Writer. Note that the timestamp isn't serialized as this will
+// be the key value within the bucket.
+func encodeFailedForwardingEvent(w io.Writer, f *FailedForwardingEvent) error {
+	return WriteElements(
+		w, f.IncomingChanID, f.OutgoingChanID, f.AmtIn, f.AmtOut,
+		f.Downstream, uint16(f.FailureCode), []byte(f.FailureDetail),
+	)
+}
+
+// decodeFailedForwardingEvent attempts to decode a serialized failed
+// forwarding event. Note that the timestamp won't be decoded, as the caller is
+// expected to set it from the key of the event.
+func decodeFailedForwardingEvent(r io.Reader,
+	f *FailedForwardingEvent) error {
+
+	var (
+		code   uint16
+		detail []byte
+	)
+	err := ReadElements(
+		r, &f.IncomingChanID, &f.OutgoingChanID, &f.AmtIn, &f.AmtOut,
+		&f.Downstream, &code, &detail,
+	)
+	if err != nil {
+		return err
+	}
+
+	f.FailureCode = lnwire.FailCode(code)
+	f.FailureDetail = string(detail)
+
+	return nil
+}
+
+// AddFailedForwardingEvents adds a series of failed forwarding events to the
+// database. Events with the same timestamp are stored together under that
+// timestamp.
+func (f *ForwardingLog) AddFailedForwardingEvents(
+	events []FailedForwardingEvent) error {
+
+	// Sort the events, so that all writes to disk are sequential.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	var timestamp [8]byte
+
+	return kvdb.Batch(f.db.Backend, func(tx kvdb.RwTx) error {
+		logBucket, err := tx.CreateTopLevelBucket(
+			forwardingFailureLogBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		for i := range events {
+			event := &events[i]
+
+			nanos := event.Timestamp.UnixNano()
+			byteOrder.PutUint64(timestamp[:], uint64(nanos))
+
+			// Append the event to the ones that are already
+			// stored under its timestamp, if any.
+			var eventBuf bytes.Buffer
+			eventBuf.Write(logBucket.Get(timestamp[:]))
+
+			err := encodeFailedForwardingEvent(&eventBuf, event)
+			if err != nil {
+				return err
+			}
+
+			err = logBucket.Put(timestamp[:], eventBuf.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FailedForwardingLogTimeSlice is the response to a failed forwarding query.
+// It includes the original query, the set of events that match the query, and
+// the offset index of the last returned event, which allows callers to resume
+// their query in the event that the query's response exceeds the max number
+// of returnable events.
+type FailedForwardingLogTimeSlice struct {
+	ForwardingEventQuery
+
+	// FailedForwardingEvents is the set of failed forwarding events in our
+	// time series that answer the query embedded above.
+	FailedForwardingEvents []FailedForwardingEvent
+
+	// LastIndexOffset is the index of the last element in the set of
+	// returned FailedForwardingEvents above.
+	LastIndexOffset uint32
+}
+
+// QueryFailed allows a caller to query the failed forwarding event time series
+// for a particular time slice. The query is paginated in the same way as the
+// forwarding log query.
+func (f *ForwardingLog) QueryFailed(
+	q ForwardingEventQuery) (FailedForwardingLogTimeSlice, error) {
+
+	var resp FailedForwardingLogTimeSlice
+
+	recordOffset := q.IndexOffset
+
+	err := kvdb.View(f.db, func(tx kvdb.RTx) error {
+		// If the bucket wasn't found, then there aren't any events to
+		// be returned.
+		logBucket := tx.ReadBucket(forwardingFailureLogBucket)
+		if logBucket == nil {
+			return ErrNoForwardingEvents
+		}
+
+		var startTime, endTime [8]byte
+		byteOrder.PutUint64(
+			startTime[:], uint64(q.StartTime.UnixNano()),
+		)
+		byteOrder.PutUint64(endTime[:], uint64(q.EndTime.UnixNano()))
+
+		// Unlike the forwarding log, several events can be stored
+		// under a single timestamp, so we'll count the skipped events
+		// rather than the skipped timestamps.
+		recordsToSkip := q.IndexOffset
+
+		logCursor := logBucket.ReadCursor()
+		timestamp, events := logCursor.Seek(startTime[:])
+		for timestamp != nil &&
+			bytes.Compare(timestamp, endTime[:]) <= 0 {
+
+			currentTime := time.Unix(
+				0, int64(byteOrder.Uint64(timestamp)),
+			)
+
+			readBuf := bytes.NewReader(events)
+			for readBuf.Len() != 0 {
+				// If our current return payload reached the
+				// max number of events, then we'll exit now.
+				numEvents := len(resp.FailedForwardingEvents)
+				if uint32(numEvents) >= q.NumMaxEvents {
+					return nil
+				}
+
+				var event FailedForwardingEvent
+				err := decodeFailedForwardingEvent(
+					readBuf, &event,
+				)
+				if err != nil {
+					return err
+				}
+
+				if recordsToSkip > 0 {
+					recordsToSkip--
+					continue
+				}
+
+				event.Timestamp = currentTime
+				resp.FailedForwardingEvents = append(
+					resp.FailedForwardingEvents, event,
+				)
+
+				recordOffset++
+			}
+
+			timestamp, events = logCursor.Next()
+		}
+
+		return nil
+	}, func() {
+		resp = FailedForwardingLogTimeSlice{
+			ForwardingEventQuery: q,
+		}
+		recordOffset = q.IndexOffset
+	})
+	if err != nil && err != ErrNoForwardingEvents {
+		return FailedForwardingLogTimeSlice{}, err
+	}
+
+	resp.LastIndexOffset = recordOffset
+
+	return resp, nil
+}
+
+// PruneFailedForwardingEvents deletes all failed forwarding events that
+// happened before the given time.
+func (f *ForwardingLog) PruneFailedForwardingEvents(before time.Time) error {
+	var beforeTime [8]byte
+	byteOrder.PutUint64(beforeTime[:], uint64(before.UnixNano()))
+
+	// We'll look up the stale events in a read transaction first, so that
+	// we only need a write transaction if there is anything to delete.
+	var staleKeys [][]byte
+	err := kvdb.View(f.db, func(tx kvdb.RTx) error {
+		logBucket := tx.ReadBucket(forwardingFailureLogBucket)
+		if logBucket == nil {
+			return nil
+		}
+
+		logCursor := logBucket.ReadCursor()
+		timestamp, _ := logCursor.First()
+		for timestamp != nil &&
+			bytes.Compare(timestamp, beforeTime[:]) < 0 {
+
+			staleKeys = append(
+				staleKeys, append([]byte(nil), timestamp...),
+			)
+
+			timestamp, _ = logCursor.Next()
+		}
+
+		return nil
+	}, func() {
+		staleKeys = nil
+	})
+	if err != nil || len(staleKeys) == 0 {
+		return err
+	}
+
+	return kvdb.Update(f.db, func(tx kvdb.RwTx) error {
+		logBucket := tx.ReadWriteBucket(forwardingFailureLogBucket)
+		if logBucket == nil {
+			return nil
+		}
+
+		for _, key := range staleKeys {
+			if err := logBucket.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
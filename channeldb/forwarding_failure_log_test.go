@@ -0,0 +1,167 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// testFailedEvents returns failed forwarding events, the first two of which
+// share a timestamp.
+func testFailedEvents() []FailedForwardingEvent {
+	start := time.Unix(1234, 0)
+
+	return []FailedForwardingEvent{
+		{
+			Timestamp:      start,
+			IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+			AmtIn:          1010,
+			AmtOut:         1000,
+			FailureCode:    lnwire.CodeTemporaryChannelFailure,
+			FailureDetail:  "insufficient bandwidth",
+		},
+		{
+			Timestamp:      start,
+			IncomingChanID: lnwire.NewShortChanIDFromInt(3),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+			AmtIn:          2010,
+			AmtOut:         2000,
+			Downstream:     true,
+		},
+		{
+			Timestamp:      start.Add(time.Minute),
+			IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(4),
+			AmtIn:          3010,
+			AmtOut:         3000,
+			FailureCode:    lnwire.CodeUnknownNextPeer,
+		},
+		{
+			Timestamp:      start.Add(time.Hour),
+			IncomingChanID: lnwire.NewShortChanIDFromInt(2),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(1),
+			AmtIn:          4010,
+			AmtOut:         4000,
+			Downstream:     true,
+		},
+	}
+}
+
+// TestFailedForwardingLogQuery tests that failed forwarding events can be
+// added and queried, including events that share a timestamp.
+func TestFailedForwardingLogQuery(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	log := ForwardingLog{
+		db: db,
+	}
+
+	events := testFailedEvents()
+	query := ForwardingEventQuery{
+		StartTime:    events[0].Timestamp,
+		EndTime:      events[len(events)-1].Timestamp,
+		NumMaxEvents: 100,
+	}
+
+	// An empty log returns no events.
+	timeSlice, err := log.QueryFailed(query)
+	require.NoError(t, err)
+	require.Empty(t, timeSlice.FailedForwardingEvents)
+	require.Zero(t, timeSlice.LastIndexOffset)
+
+	// Add the events in two batches, so that the second batch adds to the
+	// events already stored under the first timestamp.
+	require.NoError(t, log.AddFailedForwardingEvents(events[:1]))
+	require.NoError(t, log.AddFailedForwardingEvents(events[1:]))
+
+	timeSlice, err = log.QueryFailed(query)
+	require.NoError(t, err)
+	require.Equal(t, events, timeSlice.FailedForwardingEvents)
+	require.EqualValues(t, len(events), timeSlice.LastIndexOffset)
+
+	// The end time is inclusive, so a query up to the second timestamp
+	// returns the first three events.
+	query.EndTime = events[2].Timestamp
+	timeSlice, err = log.QueryFailed(query)
+	require.NoError(t, err)
+	require.Equal(t, events[:3], timeSlice.FailedForwardingEvents)
+}
+
+// TestFailedForwardingLogPagination tests that failed forwarding queries are
+// paginated by event, even when a page ends in the middle of the events that
+// share a timestamp.
+func TestFailedForwardingLogPagination(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	log := ForwardingLog{
+		db: db,
+	}
+
+	events := testFailedEvents()
+	require.NoError(t, log.AddFailedForwardingEvents(events))
+
+	query := ForwardingEventQuery{
+		StartTime:    events[0].Timestamp,
+		EndTime:      events[len(events)-1].Timestamp,
+		NumMaxEvents: 1,
+	}
+
+	// Page through the events one at a time, resuming each query at the
+	// offset returned by the previous one.
+	for i := range events {
+		timeSlice, err := log.QueryFailed(query)
+		require.NoError(t, err)
+		require.Equal(
+			t, events[i:i+1], timeSlice.FailedForwardingEvents,
+		)
+		require.EqualValues(t, i+1, timeSlice.LastIndexOffset)
+
+		query.IndexOffset = timeSlice.LastIndexOffset
+	}
+
+	// Once all events are returned, the query comes back empty.
+	timeSlice, err := log.QueryFailed(query)
+	require.NoError(t, err)
+	require.Empty(t, timeSlice.FailedForwardingEvents)
+	require.EqualValues(t, len(events), timeSlice.LastIndexOffset)
+}
+
+// TestFailedForwardingLogPrune tests that pruning the failed forwarding log
+// only deletes the events before the given time.
+func TestFailedForwardingLogPrune(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	log := ForwardingLog{
+		db: db,
+	}
+
+	// Pruning an empty log is a no-op.
+	events := testFailedEvents()
+	require.NoError(t, log.PruneFailedForwardingEvents(events[2].Timestamp))
+
+	require.NoError(t, log.AddFailedForwardingEvents(events))
+	require.NoError(t, log.PruneFailedForwardingEvents(events[2].Timestamp))
+
+	timeSlice, err := log.QueryFailed(ForwardingEventQuery{
+		StartTime:    events[0].Timestamp,
+		EndTime:      events[len(events)-1].Timestamp,
+		NumMaxEvents: 100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, events[2:], timeSlice.FailedForwardingEvents)
+}
@@ -101,7 +101,8 @@ func decodeForwardingEvent(r io.Reader, f *ForwardingEvent) error {
 
 // AddForwardingEvents adds a series of forwarding events to the database.
 // Before inserting, the set of events will be sorted according to their
-// timestamp. This ensures that all writes to disk are sequential.
+// timestamp. This ensures that all writes to disk are sequential. The events
+// are also added to the forwarding aggregate index.
 func (f *ForwardingLog) AddForwardingEvents(events []ForwardingEvent) error {
 	// Before we create the database transaction, we'll ensure that the set
 	// of forwarding events are properly sorted according to their
@@ -121,13 +122,29 @@ func (f *ForwardingLog) AddForwardingEvents(events []ForwardingEvent) error {
 			return err
 		}
 
-		// With the bucket obtained, we can now begin to write out the
+		// We'll also fetch the aggregate index, which the events are
+		// summed up in.
+		aggregateBucket, err := tx.CreateTopLevelBucket(
+			forwardingAggregateBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		// With the buckets obtained, we can now begin to write out the
 		// series of events.
-		for _, event := range events {
+		for i := range events {
+			event := &events[i]
+
 			err := storeEvent(logBucket, event, timestamp[:])
 			if err != nil {
 				return err
 			}
+
+			err = addToAggregateIndex(aggregateBucket, event)
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -137,8 +154,9 @@ func (f *ForwardingLog) AddForwardingEvents(events []ForwardingEvent) error {
 // storeEvent tries to store a forwarding event into the given bucket by trying
 // to avoid collisions. If a key for the event timestamp already exists in the
 // database, the timestamp is incremented in nanosecond intervals until a "free"
-// slot is found.
-func storeEvent(bucket walletdb.ReadWriteBucket, event ForwardingEvent,
+// slot is found. The timestamp of the event is updated to the one it is stored
+// under.
+func storeEvent(bucket walletdb.ReadWriteBucket, event *ForwardingEvent,
 	timestampScratchSpace []byte) error {
 
 	// First, we'll serialize this timestamp into our
@@ -176,7 +194,7 @@ func storeEvent(bucket walletdb.ReadWriteBucket, event ForwardingEvent,
 	// into our buffer, then write it out to disk.
 	var eventBytes [forwardingEventSize]byte
 	eventBuf := bytes.NewBuffer(eventBytes[0:0:forwardingEventSize])
-	err := encodeForwardingEvent(eventBuf, &event)
+	err := encodeForwardingEvent(eventBuf, event)
 	if err != nil {
 		return err
 	}
@@ -23,6 +23,7 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
 var (
@@ -3048,6 +3049,60 @@ func (c *ChannelEdgePolicy) ComputeFeeFromIncoming(
 	)
 }
 
+// InboundFee returns the fee that the node that announced this policy charges
+// for htlcs that it receives through the channel. It is carried in the extra
+// opaque data of the channel update and can be negative to give a discount.
+// A zero fee is returned if the policy doesn't contain an inbound fee.
+func (c *ChannelEdgePolicy) InboundFee() (lnwire.Fee, error) {
+	var fee lnwire.Fee
+	if len(c.ExtraOpaqueData) == 0 {
+		return fee, nil
+	}
+
+	extraData := lnwire.ExtraOpaqueData(c.ExtraOpaqueData)
+	if _, err := extraData.ExtractRecords(fee.NewRecord()); err != nil {
+		return lnwire.Fee{}, err
+	}
+
+	return fee, nil
+}
+
+// SetInboundFee stores the given inbound fee in the extra opaque data of the
+// policy, preserving all other records. A zero fee removes the inbound fee
+// record.
+func (c *ChannelEdgePolicy) SetInboundFee(fee lnwire.Fee) error {
+	var (
+		extraData = lnwire.ExtraOpaqueData(c.ExtraOpaqueData)
+		oldFee    lnwire.Fee
+	)
+	typeMap, err := extraData.ExtractRecords(oldFee.NewRecord())
+	if err != nil {
+		return err
+	}
+
+	// All records other than the fee are unknown to us, so their raw
+	// values are available in the type map.
+	otherRecords := make(map[uint64][]byte, len(typeMap))
+	for typ, value := range typeMap {
+		if typ != lnwire.FeeRecordType {
+			otherRecords[uint64(typ)] = value
+		}
+	}
+
+	records := tlv.MapToRecords(otherRecords)
+	if fee != (lnwire.Fee{}) {
+		records = append(records, fee.NewRecord())
+		tlv.SortRecords(records)
+	}
+
+	if err := extraData.PackRecords(records...); err != nil {
+		return err
+	}
+	c.ExtraOpaqueData = extraData
+
+	return nil
+}
+
 // FetchChannelEdgesByOutpoint attempts to lookup the two directed edges for
 // the channel identified by the funding outpoint. If the channel can't be
 // found, then ErrEdgeNotFound is returned. A struct which houses the general
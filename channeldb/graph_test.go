@@ -23,6 +23,7 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/stretchr/testify/require"
 )
 
@@ -3228,6 +3229,50 @@ func TestComputeFee(t *testing.T) {
 	}
 }
 
+// TestInboundFee tests that the inbound fee is stored in the extra opaque data
+// of a policy without losing other records.
+func TestInboundFee(t *testing.T) {
+	t.Parallel()
+
+	// A policy without extra opaque data has no inbound fee.
+	var policy ChannelEdgePolicy
+	fee, err := policy.InboundFee()
+	require.NoError(t, err)
+	require.Equal(t, lnwire.Fee{}, fee)
+
+	// Add an unknown record that must be preserved.
+	unknownRecord := []byte{1, 2, 3}
+	extraData := lnwire.ExtraOpaqueData{}
+	require.NoError(t, extraData.PackRecords(tlv.MakePrimitiveRecord(
+		101, &unknownRecord,
+	)))
+	policy.ExtraOpaqueData = extraData
+
+	inboundFee := lnwire.Fee{BaseFee: -500, FeeRate: 100}
+	require.NoError(t, policy.SetInboundFee(inboundFee))
+
+	fee, err = policy.InboundFee()
+	require.NoError(t, err)
+	require.Equal(t, inboundFee, fee)
+
+	var unknownRecord2 []byte
+	extraData = policy.ExtraOpaqueData
+	_, err = extraData.ExtractRecords(tlv.MakePrimitiveRecord(
+		101, &unknownRecord2,
+	))
+	require.NoError(t, err)
+	require.Equal(t, unknownRecord, unknownRecord2)
+
+	// Setting a zero fee removes the inbound fee record only.
+	require.NoError(t, policy.SetInboundFee(lnwire.Fee{}))
+	require.Equal(t, lnwire.ExtraOpaqueData(extraData[:5]),
+		lnwire.ExtraOpaqueData(policy.ExtraOpaqueData))
+
+	fee, err = policy.InboundFee()
+	require.NoError(t, err)
+	require.Equal(t, lnwire.Fee{}, fee)
+}
+
 // TestBatchedAddChannelEdge asserts that BatchedAddChannelEdge properly
 // executes multiple AddChannelEdge requests in a single txn.
 func TestBatchedAddChannelEdge(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/feature"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
@@ -433,6 +434,51 @@ func TestInvRefEquivocation(t *testing.T) {
 	require.Error(t, err, ErrInvRefEquivocation)
 }
 
+// TestInvoiceCancelDate tests that the time an invoice is canceled is
+// recorded and persisted.
+func TestInvoiceCancelDate(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB(OptionClock(testClock))
+	defer cleanUp()
+	require.NoError(t, err, "unable to make test db")
+
+	preimage := lntypes.Preimage{1}
+	paymentHash := preimage.Hash()
+
+	testInvoice := &Invoice{
+		Htlcs: map[CircuitKey]*InvoiceHTLC{},
+		Terms: ContractTerm{
+			Value:           lnwire.NewMSatFromSatoshis(10000),
+			Features:        emptyFeatures,
+			PaymentPreimage: &preimage,
+		},
+	}
+
+	_, err = db.AddInvoice(testInvoice, paymentHash)
+	require.NoError(t, err)
+
+	ref := InvoiceRefByHash(paymentHash)
+	invoice, err := db.LookupInvoice(ref)
+	require.NoError(t, err)
+	require.True(t, invoice.CancelDate.IsZero())
+
+	_, err = db.UpdateInvoice(ref,
+		func(invoice *Invoice) (*InvoiceUpdateDesc, error) {
+			return &InvoiceUpdateDesc{
+				State: &InvoiceStateUpdateDesc{
+					NewState: ContractCanceled,
+				},
+			}, nil
+		})
+	require.NoError(t, err)
+
+	invoice, err = db.LookupInvoice(ref)
+	require.NoError(t, err)
+	require.Equal(t, ContractCanceled, invoice.State)
+	require.Equal(t, testNow, invoice.CancelDate)
+}
+
 // TestInvoiceCancelSingleHtlc tests that a single htlc can be canceled on the
 // invoice.
 func TestInvoiceCancelSingleHtlc(t *testing.T) {
@@ -1077,6 +1123,112 @@ func TestQueryInvoices(t *testing.T) {
 	}
 }
 
+// TestQueryInvoicesFilters tests that QueryInvoices only returns the invoices
+// that match the state and creation time filters of the query, and that the
+// time index is kept up to date.
+func TestQueryInvoicesFilters(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	defer cleanUp()
+	require.NoError(t, err, "unable to make test db")
+
+	// Add four invoices with add indexes 1 to 4, created at 1000 to 4000
+	// seconds. The second and the fourth invoice are settled.
+	var invoices []*Invoice
+	for i := 1; i <= 4; i++ {
+		amt := lnwire.MilliSatoshi(i)
+		invoice, err := randInvoice(amt)
+		require.NoError(t, err)
+		invoice.CreationDate = time.Unix(int64(i)*1000, 0)
+		invoices = append(invoices, invoice)
+
+		paymentHash := invoice.Terms.PaymentPreimage.Hash()
+		_, err = db.AddInvoice(invoice, paymentHash)
+		require.NoError(t, err)
+
+		if i%2 == 0 {
+			ref := InvoiceRefByHash(paymentHash)
+			_, err := db.UpdateInvoice(ref, getUpdateInvoice(amt))
+			require.NoError(t, err)
+		}
+	}
+
+	assertQuery := func(query InvoiceQuery, expAddIndexes ...uint64) {
+		t.Helper()
+
+		query.NumMaxInvoices = math.MaxUint64
+		resp, err := db.QueryInvoices(query)
+		require.NoError(t, err)
+
+		var addIndexes []uint64
+		for _, invoice := range resp.Invoices {
+			addIndexes = append(addIndexes, invoice.AddIndex)
+		}
+		require.Equal(t, expAddIndexes, addIndexes)
+	}
+
+	// Query by creation time, in both directions.
+	assertQuery(InvoiceQuery{
+		CreationDateStart: 2000,
+		CreationDateEnd:   3000,
+	}, 2, 3)
+	assertQuery(InvoiceQuery{
+		CreationDateEnd: 1500,
+		Reversed:        true,
+	}, 1)
+	assertQuery(InvoiceQuery{
+		IndexOffset:       4,
+		CreationDateStart: 2000,
+		Reversed:          true,
+	}, 2, 3)
+	assertQuery(InvoiceQuery{
+		CreationDateStart: 5000,
+	})
+
+	// Query by state.
+	assertQuery(InvoiceQuery{
+		States: []ContractState{ContractSettled},
+	}, 2, 4)
+	assertQuery(InvoiceQuery{
+		States:            []ContractState{ContractOpen},
+		CreationDateStart: 2000,
+	}, 3)
+	assertQuery(InvoiceQuery{
+		States:      []ContractState{ContractSettled},
+		PendingOnly: true,
+	})
+
+	// Deleting an invoice removes it from the time index.
+	err = db.DeleteInvoice([]InvoiceDeleteRef{{
+		PayHash:      invoices[2].Terms.PaymentPreimage.Hash(),
+		PayAddr:      &invoices[2].Terms.PaymentAddr,
+		AddIndex:     3,
+		CreationDate: invoices[2].CreationDate,
+	}})
+	require.NoError(t, err)
+
+	var timeIndexSize int
+	err = kvdb.View(db, func(tx kvdb.RTx) error {
+		timeIndex := tx.ReadBucket(invoiceBucket).NestedReadBucket(
+			invoiceTimeIndexBucket,
+		)
+		return timeIndex.ForEach(func(_, _ []byte) error {
+			timeIndexSize++
+			return nil
+		})
+	}, func() {
+		timeIndexSize = 0
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, timeIndexSize)
+
+	assertQuery(InvoiceQuery{
+		CreationDateStart: 2000,
+		CreationDateEnd:   3000,
+	}, 2)
+}
+
 // getUpdateInvoice returns an invoice update callback that, when called,
 // settles the invoice with the given amount.
 func getUpdateInvoice(amt lnwire.MilliSatoshi) InvoiceUpdateCallback {
@@ -1173,6 +1325,33 @@ func TestCustomRecords(t *testing.T) {
 	)
 }
 
+// TestInvoiceOfferID asserts that the offer ID of an invoice is persisted,
+// and that invoices without an offer don't get one.
+func TestInvoiceOfferID(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	defer cleanUp()
+	require.NoError(t, err)
+
+	offerInvoice, err := randInvoice(1000)
+	require.NoError(t, err)
+	offerInvoice.OfferID = &[32]byte{1, 2, 3}
+
+	plainInvoice, err := randInvoice(2000)
+	require.NoError(t, err)
+
+	for _, invoice := range []*Invoice{offerInvoice, plainInvoice} {
+		hash := invoice.Terms.PaymentPreimage.Hash()
+		_, err := db.AddInvoice(invoice, hash)
+		require.NoError(t, err)
+
+		dbInvoice, err := db.LookupInvoice(InvoiceRefByHash(hash))
+		require.NoError(t, err)
+		require.Equal(t, invoice.OfferID, dbInvoice.OfferID)
+	}
+}
+
 // TestInvoiceHtlcAMPFields asserts that the set id and preimage fields are
 // properly recorded when updating an invoice.
 func TestInvoiceHtlcAMPFields(t *testing.T) {
@@ -2395,10 +2574,11 @@ func TestDeleteInvoices(t *testing.T) {
 
 		// store the delete ref for later.
 		invoicesToDelete[i] = InvoiceDeleteRef{
-			PayHash:     paymentHash,
-			PayAddr:     &invoice.Terms.PaymentAddr,
-			AddIndex:    addIndex,
-			SettleIndex: invoice.SettleIndex,
+			PayHash:      paymentHash,
+			PayAddr:      &invoice.Terms.PaymentAddr,
+			AddIndex:     addIndex,
+			SettleIndex:  invoice.SettleIndex,
+			CreationDate: invoice.CreationDate,
 		}
 	}
 
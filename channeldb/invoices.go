@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"strings"
 	"time"
 
@@ -90,6 +91,13 @@ var (
 	//   settleIndexNo => invoiceKey
 	settleIndexBucket = []byte("invoice-settle-index")
 
+	// invoiceTimeIndexBucket is an index bucket that indexes the add
+	// indexes of the invoices by their creation time, so that invoices
+	// can be queried by time. We map:
+	//
+	//   creationTime || addIndexNo => nil
+	invoiceTimeIndexBucket = []byte("invoice-time-index")
+
 	// ErrInvoiceAlreadySettled is returned when the invoice is already
 	// settled.
 	ErrInvoiceAlreadySettled = errors.New("invoice already settled")
@@ -215,6 +223,8 @@ const (
 	invStateType    tlv.Type = 12
 	amtPaidType     tlv.Type = 13
 	hodlInvoiceType tlv.Type = 14
+	offerIDType     tlv.Type = 15
+	cancelTimeType  tlv.Type = 16
 )
 
 // InvoiceRef is a composite identifier for invoices. Invoices can be referenced
@@ -427,6 +437,11 @@ type Invoice struct {
 	// SettleDate is the exact time the invoice was settled.
 	SettleDate time.Time
 
+	// CancelDate is the exact time the invoice was canceled. It is zero
+	// for invoices that aren't canceled and for invoices that were
+	// canceled before the cancel time was recorded.
+	CancelDate time.Time
+
 	// Terms are the contractual payment terms of the invoice. Once all the
 	// terms have been satisfied by the payer, then the invoice can be
 	// considered fully fulfilled.
@@ -469,6 +484,10 @@ type Invoice struct {
 	// HodlInvoice indicates whether the invoice should be held in the
 	// Accepted state or be settled right away.
 	HodlInvoice bool
+
+	// OfferID is the ID of the BOLT 12 offer the invoice was created for.
+	// It is nil for invoices that don't belong to an offer.
+	OfferID *[32]byte
 }
 
 // HTLCSet returns the set of HTLCs belonging to setID and in the provided
@@ -1107,6 +1126,47 @@ type InvoiceQuery struct {
 	// Reversed, if set, indicates that the invoices returned should start
 	// from the IndexOffset and go backwards.
 	Reversed bool
+
+	// States, if set, restricts the query to invoices in one of the given
+	// states.
+	States []ContractState
+
+	// CreationDateStart, if set, restricts the query to invoices created
+	// at or after this unix timestamp in seconds.
+	CreationDateStart int64
+
+	// CreationDateEnd, if set, restricts the query to invoices created at
+	// or before this unix timestamp in seconds.
+	CreationDateEnd int64
+}
+
+// matches returns true if the given invoice passes the state and creation
+// time filters of the query.
+func (q *InvoiceQuery) matches(invoice *Invoice) bool {
+	// Skip any settled or canceled invoices if the caller is only
+	// interested in pending ones.
+	if q.PendingOnly && !invoice.IsPending() {
+		return false
+	}
+
+	if !createdWithin(
+		invoice.CreationDate, q.CreationDateStart, q.CreationDateEnd,
+	) {
+
+		return false
+	}
+
+	if len(q.States) == 0 {
+		return true
+	}
+
+	for _, state := range q.States {
+		if invoice.State == state {
+			return true
+		}
+	}
+
+	return false
 }
 
 // InvoiceSlice is the response to a invoice query. It includes the original
@@ -1154,11 +1214,34 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 			return ErrNoInvoicesCreated
 		}
 
+		// If the query is restricted to a time range, we look up the
+		// add indexes of the invoices created within it in the time
+		// index, so that only that part of the add index needs to be
+		// iterated.
+		minAddIndex, maxAddIndex := uint64(0), uint64(math.MaxUint64)
+		if q.CreationDateStart > 0 || q.CreationDateEnd > 0 {
+			timeIndex := invoices.NestedReadBucket(
+				invoiceTimeIndexBucket,
+			)
+			if timeIndex == nil {
+				return nil
+			}
+
+			var found bool
+			minAddIndex, maxAddIndex, found = timeIndexBounds(
+				timeIndex.ReadCursor(), q.CreationDateStart,
+				q.CreationDateEnd,
+			)
+			if !found {
+				return nil
+			}
+		}
+
 		// Create a paginator which reads from our add index bucket with
 		// the parameters provided by the invoice query.
-		paginator := newPaginator(
+		paginator := newBoundedPaginator(
 			invoiceAddIndex.ReadCursor(), q.Reversed, q.IndexOffset,
-			q.NumMaxInvoices,
+			q.NumMaxInvoices, minAddIndex, maxAddIndex,
 		)
 
 		// accumulateInvoices looks up an invoice based on the index we
@@ -1171,9 +1254,7 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 				return false, err
 			}
 
-			// Skip any settled or canceled invoices if the caller
-			// is only interested in pending ones.
-			if q.PendingOnly && !invoice.IsPending() {
+			if !q.matches(&invoice) {
 				return false, nil
 			}
 
@@ -1393,6 +1474,19 @@ func putInvoice(invoices, invoiceIndex, payAddrIndex, addIndex kvdb.RwBucket,
 
 	i.AddIndex = nextAddSeqNo
 
+	// We also index the add index by the creation time of the invoice, so
+	// that invoices can be queried by time.
+	timeIndex, err := invoices.CreateBucketIfNotExists(
+		invoiceTimeIndexBucket,
+	)
+	if err != nil {
+		return 0, err
+	}
+	err = timeIndex.Put(timeIndexKey(i.CreationDate, nextAddSeqNo), nil)
+	if err != nil {
+		return 0, err
+	}
+
 	// Finally, serialize the invoice itself to be written to the disk.
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, i); err != nil {
@@ -1448,7 +1542,7 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 		hodlInvoice = 1
 	}
 
-	tlvStream, err := tlv.NewStream(
+	records := []tlv.Record{
 		// Memo and payreq.
 		tlv.MakePrimitiveRecord(memoType, &i.Memo),
 		tlv.MakePrimitiveRecord(payReqType, &i.PaymentRequest),
@@ -1472,7 +1566,28 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 		tlv.MakePrimitiveRecord(amtPaidType, &amtPaid),
 
 		tlv.MakePrimitiveRecord(hodlInvoiceType, &hodlInvoice),
-	)
+	}
+
+	// The offer ID is only stored for invoices created for an offer.
+	if i.OfferID != nil {
+		records = append(
+			records, tlv.MakePrimitiveRecord(offerIDType, i.OfferID),
+		)
+	}
+
+	// The cancel time is only stored for canceled invoices.
+	if !i.CancelDate.IsZero() {
+		cancelDateBytes, err := i.CancelDate.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			cancelTimeType, &cancelDateBytes,
+		))
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
 	if err != nil {
 		return err
 	}
@@ -1616,9 +1731,11 @@ func deserializeInvoice(r io.Reader) (Invoice, error) {
 		amtPaid       uint64
 		state         uint8
 		hodlInvoice   uint8
+		offerID       [32]byte
 
 		creationDateBytes []byte
 		settleDateBytes   []byte
+		cancelDateBytes   []byte
 		featureBytes      []byte
 	)
 
@@ -1647,6 +1764,8 @@ func deserializeInvoice(r io.Reader) (Invoice, error) {
 		tlv.MakePrimitiveRecord(amtPaidType, &amtPaid),
 
 		tlv.MakePrimitiveRecord(hodlInvoiceType, &hodlInvoice),
+		tlv.MakePrimitiveRecord(offerIDType, &offerID),
+		tlv.MakePrimitiveRecord(cancelTimeType, &cancelDateBytes),
 	)
 	if err != nil {
 		return i, err
@@ -1659,10 +1778,15 @@ func deserializeInvoice(r io.Reader) (Invoice, error) {
 	}
 
 	lr := io.LimitReader(r, bodyLen)
-	if err = tlvStream.Decode(lr); err != nil {
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(lr)
+	if err != nil {
 		return i, err
 	}
 
+	if _, ok := parsedTypes[offerIDType]; ok {
+		i.OfferID = &offerID
+	}
+
 	preimage := lntypes.Preimage(preimageBytes)
 	if preimage != unknownPreimage {
 		i.Terms.PaymentPreimage = &preimage
@@ -1688,6 +1812,13 @@ func deserializeInvoice(r io.Reader) (Invoice, error) {
 		return i, err
 	}
 
+	if _, ok := parsedTypes[cancelTimeType]; ok {
+		err = i.CancelDate.UnmarshalBinary(cancelDateBytes)
+		if err != nil {
+			return i, err
+		}
+	}
+
 	rawFeatures := lnwire.NewRawFeatureVector()
 	err = rawFeatures.DecodeBase256(
 		bytes.NewReader(featureBytes), len(featureBytes),
@@ -1818,6 +1949,7 @@ func copyInvoice(src *Invoice) *Invoice {
 		PaymentRequest: copySlice(src.PaymentRequest),
 		CreationDate:   src.CreationDate,
 		SettleDate:     src.SettleDate,
+		CancelDate:     src.CancelDate,
 		Terms:          src.Terms,
 		AddIndex:       src.AddIndex,
 		SettleIndex:    src.SettleIndex,
@@ -1836,6 +1968,11 @@ func copyInvoice(src *Invoice) *Invoice {
 		dest.Terms.PaymentPreimage = &preimage
 	}
 
+	if src.OfferID != nil {
+		offerID := *src.OfferID
+		dest.OfferID = &offerID
+	}
+
 	for k, v := range src.Htlcs {
 		dest.Htlcs[k] = v.Copy()
 	}
@@ -1965,13 +2102,17 @@ func (d *DB) updateInvoice(hash *lntypes.Hash, invoices,
 			return nil, err
 		}
 
-		if update.State.NewState == ContractSettled {
+		switch update.State.NewState {
+		case ContractSettled:
 			err := setSettleMetaFields(
 				settleIndex, invoiceNum, &invoice, now,
 			)
 			if err != nil {
 				return nil, err
 			}
+
+		case ContractCanceled:
+			invoice.CancelDate = now
 		}
 	}
 
@@ -2278,6 +2419,10 @@ type InvoiceDeleteRef struct {
 
 	// SettleIndex is the settle index of the invoice.
 	SettleIndex uint64
+
+	// CreationDate is the creation date of the invoice, which locates the
+	// invoice in the time index.
+	CreationDate time.Time
 }
 
 // DeleteInvoice attempts to delete the passed invoices from the database in
@@ -2363,6 +2508,20 @@ func (d *DB) DeleteInvoice(invoicesToDelete []InvoiceDeleteRef) error {
 				return err
 			}
 
+			// Remove from the time index, which may not exist
+			// yet if no invoice was added since it was introduced.
+			timeIndex := invoices.NestedReadWriteBucket(
+				invoiceTimeIndexBucket,
+			)
+			if timeIndex != nil {
+				err := timeIndex.Delete(timeIndexKey(
+					ref.CreationDate, ref.AddIndex,
+				))
+				if err != nil {
+					return err
+				}
+			}
+
 			// Remove from the settle index if available and
 			// if the invoice is settled.
 			if settleIndex != nil && ref.SettleIndex > 0 {
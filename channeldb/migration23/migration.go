@@ -0,0 +1,232 @@
+package migration23
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	paymentsRootBucket = []byte("payments-root-bucket")
+
+	paymentSequenceKey = []byte("payment-sequence-key")
+
+	paymentCreationInfoKey = []byte("payment-creation-info")
+
+	duplicatePaymentsBucket = []byte("payment-duplicate-bucket")
+
+	paymentsTimeIndexBucket = []byte("payments-time-index-bucket")
+
+	invoiceBucket = []byte("invoices")
+
+	addIndexBucket = []byte("invoice-add-index")
+
+	invoiceTimeIndexBucket = []byte("invoice-time-index")
+
+	byteOrder = binary.BigEndian
+)
+
+const (
+	// creationTimeOffset is the offset of the creation time within the
+	// serialized creation info of a payment, which starts with the payment
+	// hash and the value of the payment.
+	creationTimeOffset = 32 + 8
+
+	// createTimeType is the tlv type of the creation date of an invoice.
+	createTimeType tlv.Type = 2
+)
+
+// timeIndexKey returns the key of an entry in a time index, which is the
+// creation time in unix nano seconds followed by the index of the entry.
+func timeIndexKey(unixNano int64, index []byte) []byte {
+	var key [16]byte
+	byteOrder.PutUint64(key[:8], uint64(unixNano))
+	copy(key[8:], index)
+
+	return key[:]
+}
+
+// MigrateTimeIndex migrates the database to contain time indexes for payments
+// and invoices, which index the payment sequence numbers and the invoice add
+// indexes by creation time. This allows payments and invoices to be queried
+// by time without scanning all of them.
+func MigrateTimeIndex(tx kvdb.RwTx) error {
+	log.Infof("Migrating payments and invoices to add time index")
+
+	if err := migratePayments(tx); err != nil {
+		return err
+	}
+
+	return migrateInvoices(tx)
+}
+
+// migratePayments indexes all payments, including duplicate payments, by
+// their creation time.
+func migratePayments(tx kvdb.RwTx) error {
+	timeIndex, err := tx.CreateTopLevelBucket(paymentsTimeIndexBucket)
+	if err != nil {
+		return err
+	}
+
+	payments := tx.ReadBucket(paymentsRootBucket)
+	if payments == nil {
+		return nil
+	}
+
+	var keys [][]byte
+	err = payments.ForEach(func(hash, v []byte) error {
+		// Each payment is stored in a bucket keyed by its hash.
+		bucket := payments.NestedReadBucket(hash)
+		if bucket == nil {
+			return fmt.Errorf("non bucket element in payments " +
+				"bucket")
+		}
+
+		seqNr := bucket.Get(paymentSequenceKey)
+		if seqNr == nil {
+			return fmt.Errorf("sequence number not found for "+
+				"payment %x", hash)
+		}
+
+		info := bucket.Get(paymentCreationInfoKey)
+		if len(info) < creationTimeOffset+8 {
+			return fmt.Errorf("invalid creation info for payment "+
+				"%x", hash)
+		}
+
+		unixNano := int64(byteOrder.Uint64(info[creationTimeOffset:]))
+		keys = append(keys, timeIndexKey(unixNano, seqNr))
+
+		// Older versions of lnd stored duplicate payments in a
+		// sub-bucket, keyed by their sequence number. Their creation
+		// time is stored in unix seconds.
+		duplicates := bucket.NestedReadBucket(duplicatePaymentsBucket)
+		if duplicates == nil {
+			return nil
+		}
+
+		return duplicates.ForEach(func(seqNr, v []byte) error {
+			duplicate := duplicates.NestedReadBucket(seqNr)
+			if duplicate == nil {
+				return fmt.Errorf("non bucket element in " +
+					"duplicate bucket")
+			}
+
+			info := duplicate.Get(paymentCreationInfoKey)
+			if len(info) < creationTimeOffset+8 {
+				return fmt.Errorf("invalid creation info "+
+					"for duplicate payment %x", hash)
+			}
+
+			unixSeconds := int64(
+				byteOrder.Uint64(info[creationTimeOffset:]),
+			)
+			unixNano := time.Unix(unixSeconds, 0).UnixNano()
+			keys = append(keys, timeIndexKey(unixNano, seqNr))
+
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Adding %v payments to the time index", len(keys))
+
+	for _, key := range keys {
+		if err := timeIndex.Put(key, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateInvoices indexes all invoices by their creation time.
+func migrateInvoices(tx kvdb.RwTx) error {
+	invoices := tx.ReadWriteBucket(invoiceBucket)
+	if invoices == nil {
+		return nil
+	}
+
+	addIndex := invoices.NestedReadBucket(addIndexBucket)
+	if addIndex == nil {
+		return nil
+	}
+
+	var keys [][]byte
+	err := addIndex.ForEach(func(addIndexNo, invoiceKey []byte) error {
+		invoiceBytes := invoices.Get(invoiceKey)
+		if invoiceBytes == nil {
+			return fmt.Errorf("invoice %x not found", invoiceKey)
+		}
+
+		creationDate, err := deserializeCreationDate(
+			bytes.NewReader(invoiceBytes),
+		)
+		if err != nil {
+			return err
+		}
+
+		var unixNano int64
+		if !creationDate.IsZero() {
+			unixNano = creationDate.UnixNano()
+		}
+		keys = append(keys, timeIndexKey(unixNano, addIndexNo))
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	timeIndex, err := invoices.CreateBucketIfNotExists(
+		invoiceTimeIndexBucket,
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Adding %v invoices to the time index", len(keys))
+
+	for _, key := range keys {
+		if err := timeIndex.Put(key, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeCreationDate reads the creation date from a serialized invoice,
+// skipping all other records of the invoice.
+func deserializeCreationDate(r io.Reader) (time.Time, error) {
+	var creationDateBytes []byte
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(createTimeType, &creationDateBytes),
+	)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var bodyLen int64
+	if err := binary.Read(r, byteOrder, &bodyLen); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := tlvStream.Decode(io.LimitReader(r, bodyLen)); err != nil {
+		return time.Time{}, err
+	}
+
+	var creationDate time.Time
+	if err := creationDate.UnmarshalBinary(creationDateBytes); err != nil {
+		return time.Time{}, err
+	}
+
+	return creationDate, nil
+}
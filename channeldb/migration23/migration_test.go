@@ -0,0 +1,185 @@
+package migration23
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb/migtest"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	hexStr = migtest.Hex
+
+	seqNr1 = hexStr("0000000000000001")
+	seqNr2 = hexStr("0000000000000002")
+	seqNr3 = hexStr("0000000000000003")
+
+	hash1 = hexStr("0101010101010101010101010101010101010101010101010101010101010101")
+	hash2 = hexStr("0202020202020202020202020202020202020202020202020202020202020202")
+
+	addIndex1   = hexStr("0000000000000001")
+	addIndex2   = hexStr("0000000000000002")
+	invoiceKey1 = hexStr("00000000")
+	invoiceKey2 = hexStr("00000001")
+
+	creationTime1 = time.Unix(1000, 500)
+	creationTime2 = time.Unix(2000, 0)
+	creationTime3 = time.Unix(3000, 0)
+)
+
+// creationInfo returns a serialized payment creation info with the given
+// creation time, which is stored in unix nano seconds for regular payments
+// and in unix seconds for duplicate payments.
+func creationInfo(hash string, creationTime uint64) string {
+	var b bytes.Buffer
+	b.WriteString(hash)
+
+	var scratch [8]byte
+	binary.BigEndian.PutUint64(scratch[:], 1000)
+	b.Write(scratch[:])
+
+	binary.BigEndian.PutUint64(scratch[:], creationTime)
+	b.Write(scratch[:])
+
+	// An empty payment request.
+	b.Write([]byte{0, 0, 0, 0})
+
+	return b.String()
+}
+
+// invoice returns a serialized invoice that contains the given creation date
+// and a memo.
+func invoice(t *testing.T, creationDate time.Time) string {
+	creationDateBytes, err := creationDate.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	memo := []byte("memo")
+
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(0, &memo),
+		tlv.MakePrimitiveRecord(createTimeType, &creationDateBytes),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body bytes.Buffer
+	if err := tlvStream.Encode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	err = binary.Write(&b, binary.BigEndian, uint64(body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Write(body.Bytes())
+
+	return b.String()
+}
+
+// timeIndexEntry returns the expected key of a time index entry.
+func timeIndexEntry(creationTime time.Time, index string) string {
+	return string(timeIndexKey(creationTime.UnixNano(), []byte(index)))
+}
+
+// TestMigrateTimeIndex asserts that the time indexes of payments and invoices
+// are populated correctly.
+func TestMigrateTimeIndex(t *testing.T) {
+	payments := map[string]interface{}{
+		hash1: map[string]interface{}{
+			string(paymentSequenceKey): seqNr1,
+			string(paymentCreationInfoKey): creationInfo(
+				hash1, uint64(creationTime1.UnixNano()),
+			),
+		},
+		hash2: map[string]interface{}{
+			string(paymentSequenceKey): seqNr3,
+			string(paymentCreationInfoKey): creationInfo(
+				hash2, uint64(creationTime3.UnixNano()),
+			),
+			string(duplicatePaymentsBucket): map[string]interface{}{
+				seqNr2: map[string]interface{}{
+					string(paymentCreationInfoKey): creationInfo(
+						hash2, uint64(creationTime2.Unix()),
+					),
+				},
+			},
+		},
+	}
+
+	invoices := map[string]interface{}{
+		invoiceKey1: invoice(t, creationTime1),
+		invoiceKey2: invoice(t, creationTime2),
+		string(addIndexBucket): map[string]interface{}{
+			addIndex1: invoiceKey1,
+			addIndex2: invoiceKey2,
+		},
+	}
+
+	paymentsTimeIndex := map[string]interface{}{
+		timeIndexEntry(creationTime1, seqNr1): "",
+		timeIndexEntry(creationTime2, seqNr2): "",
+		timeIndexEntry(creationTime3, seqNr3): "",
+	}
+
+	invoicesAfter := map[string]interface{}{
+		invoiceKey1: invoices[invoiceKey1],
+		invoiceKey2: invoices[invoiceKey2],
+		string(addIndexBucket): map[string]interface{}{
+			addIndex1: invoiceKey1,
+			addIndex2: invoiceKey2,
+		},
+		string(invoiceTimeIndexBucket): map[string]interface{}{
+			timeIndexEntry(creationTime1, addIndex1): "",
+			timeIndexEntry(creationTime2, addIndex2): "",
+		},
+	}
+
+	before := func(tx kvdb.RwTx) error {
+		err := migtest.RestoreDB(tx, paymentsRootBucket, payments)
+		if err != nil {
+			return err
+		}
+
+		return migtest.RestoreDB(tx, invoiceBucket, invoices)
+	}
+
+	after := func(tx kvdb.RwTx) error {
+		err := migtest.VerifyDB(tx, paymentsRootBucket, payments)
+		if err != nil {
+			return err
+		}
+
+		err = migtest.VerifyDB(
+			tx, paymentsTimeIndexBucket, paymentsTimeIndex,
+		)
+		if err != nil {
+			return err
+		}
+
+		return migtest.VerifyDB(tx, invoiceBucket, invoicesAfter)
+	}
+
+	migtest.ApplyMigration(t, before, after, MigrateTimeIndex, false)
+}
+
+// TestMigrateTimeIndexEmpty asserts that the migration succeeds on a database
+// without payments and invoices.
+func TestMigrateTimeIndexEmpty(t *testing.T) {
+	after := func(tx kvdb.RwTx) error {
+		return migtest.VerifyDB(
+			tx, paymentsTimeIndexBucket, map[string]interface{}{},
+		)
+	}
+
+	migtest.ApplyMigration(
+		t, func(kvdb.RwTx) error { return nil }, after,
+		MigrateTimeIndex, false,
+	)
+}
@@ -0,0 +1,125 @@
+package migration24
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	forwardingLogBucket = []byte("circuit-fwd-log")
+
+	forwardingAggregateBucket = []byte("circuit-fwd-aggregate")
+
+	byteOrder = binary.BigEndian
+)
+
+const (
+	// aggregateResolution is the time resolution of the forwarding
+	// aggregate index.
+	aggregateResolution = time.Hour
+
+	// forwardingEventSize is the size of a serialized forwarding event,
+	// which consists of the incoming and outgoing channel ID, the incoming
+	// amount and the outgoing amount.
+	forwardingEventSize = 32
+
+	// aggregateKeySize is the size of a key in the forwarding aggregate
+	// index, which consists of the hour and the incoming and outgoing
+	// channel ID.
+	aggregateKeySize = 24
+
+	// aggregateValueSize is the size of a value in the forwarding
+	// aggregate index, which consists of the number of forwards and the
+	// total incoming and outgoing amount.
+	aggregateValueSize = 24
+)
+
+// aggregate sums up the forwards between a pair of channels within an hour.
+type aggregate struct {
+	count  uint64
+	amtIn  uint64
+	amtOut uint64
+}
+
+// MigrateForwardingAggregates migrates the database to contain the forwarding
+// aggregate index, which sums up the forwarding log per hour and channel pair.
+// This allows the forwarding log to be queried in aggregate without reading
+// every single forwarding event.
+func MigrateForwardingAggregates(tx kvdb.RwTx) error {
+	log.Infof("Migrating forwarding log to add aggregate index")
+
+	// Earlier versions created the index on first use, so an existing
+	// index is rebuilt to make sure that no forward is counted twice.
+	err := tx.DeleteTopLevelBucket(forwardingAggregateBucket)
+	if err != nil && err != kvdb.ErrBucketNotFound {
+		return err
+	}
+
+	aggregateIndex, err := tx.CreateTopLevelBucket(
+		forwardingAggregateBucket,
+	)
+	if err != nil {
+		return err
+	}
+
+	logBucket := tx.ReadBucket(forwardingLogBucket)
+	if logBucket == nil {
+		return nil
+	}
+
+	aggregates := make(map[[aggregateKeySize]byte]*aggregate)
+	err = logBucket.ForEach(func(timestamp, events []byte) error {
+		if len(timestamp) != 8 || len(events)%forwardingEventSize != 0 {
+			return fmt.Errorf("invalid forwarding log entry %x",
+				timestamp)
+		}
+
+		hour := time.Unix(0, int64(byteOrder.Uint64(timestamp))).
+			Truncate(aggregateResolution)
+
+		// Each entry holds one or more events, which all share the
+		// timestamp of the entry.
+		for len(events) != 0 {
+			var key [aggregateKeySize]byte
+			byteOrder.PutUint64(key[:8], uint64(hour.Unix()))
+			copy(key[8:], events[:16])
+
+			a, ok := aggregates[key]
+			if !ok {
+				a = &aggregate{}
+				aggregates[key] = a
+			}
+
+			a.count++
+			a.amtIn += byteOrder.Uint64(events[16:24])
+			a.amtOut += byteOrder.Uint64(events[24:32])
+
+			events = events[forwardingEventSize:]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Adding %v forwarding aggregates to the aggregate index",
+		len(aggregates))
+
+	for key, a := range aggregates {
+		var value [aggregateValueSize]byte
+		byteOrder.PutUint64(value[:8], a.count)
+		byteOrder.PutUint64(value[8:16], a.amtIn)
+		byteOrder.PutUint64(value[16:], a.amtOut)
+
+		key := key
+		if err := aggregateIndex.Put(key[:], value[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
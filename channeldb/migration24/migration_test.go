@@ -0,0 +1,132 @@
+package migration24
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb/migtest"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	hexStr = migtest.Hex
+
+	// hour is the start of the hour that most test events are logged in.
+	hour = time.Unix(1614852000, 0)
+
+	// nextHour is the start of the following hour.
+	nextHour = hour.Add(time.Hour)
+
+	chan1 = hexStr("0000000000000001")
+	chan2 = hexStr("0000000000000002")
+	chan3 = hexStr("0000000000000003")
+)
+
+// timestamp returns a forwarding log key of the given time.
+func timestamp(t time.Time) string {
+	return uint64Str(uint64(t.UnixNano()))
+}
+
+// uint64Str returns the big endian serialization of the given number.
+func uint64Str(n uint64) string {
+	var b [8]byte
+	byteOrder.PutUint64(b[:], n)
+
+	return string(b[:])
+}
+
+// event returns a serialized forwarding event.
+func event(in, out string, amtIn, amtOut uint64) string {
+	return in + out + uint64Str(amtIn) + uint64Str(amtOut)
+}
+
+// aggregateEntry returns the key of an entry in the aggregate index.
+func aggregateEntry(hour time.Time, in, out string) string {
+	return uint64Str(uint64(hour.Unix())) + in + out
+}
+
+// aggregateValue returns the value of an entry in the aggregate index.
+func aggregateValue(count, amtIn, amtOut uint64) string {
+	return uint64Str(count) + uint64Str(amtIn) + uint64Str(amtOut)
+}
+
+// TestMigrateForwardingAggregates asserts that the forwarding log is summed
+// up per hour and channel pair, including log entries that hold multiple
+// events.
+func TestMigrateForwardingAggregates(t *testing.T) {
+	forwardingLog := map[string]interface{}{
+		// Two events that share a timestamp.
+		timestamp(hour): event(chan1, chan2, 1010, 1000) +
+			event(chan1, chan3, 2010, 2000),
+
+		// Another forward on the first pair within the same hour.
+		timestamp(hour.Add(59 * time.Minute)): event(
+			chan1, chan2, 3010, 3000,
+		),
+
+		// A forward in the next hour.
+		timestamp(nextHour): event(
+			chan1, chan2, 4010, 4000,
+		),
+	}
+
+	// A stale index is rebuilt from the forwarding log.
+	staleIndex := map[string]interface{}{
+		aggregateEntry(hour, chan1, chan2): aggregateValue(1, 1, 1),
+	}
+
+	aggregateIndex := map[string]interface{}{
+		aggregateEntry(hour, chan1, chan2): aggregateValue(
+			2, 4020, 4000,
+		),
+		aggregateEntry(hour, chan1, chan3): aggregateValue(
+			1, 2010, 2000,
+		),
+		aggregateEntry(nextHour, chan1, chan2): aggregateValue(
+			1, 4010, 4000,
+		),
+	}
+
+	before := func(tx kvdb.RwTx) error {
+		err := migtest.RestoreDB(
+			tx, forwardingLogBucket, forwardingLog,
+		)
+		if err != nil {
+			return err
+		}
+
+		return migtest.RestoreDB(
+			tx, forwardingAggregateBucket, staleIndex,
+		)
+	}
+
+	after := func(tx kvdb.RwTx) error {
+		err := migtest.VerifyDB(tx, forwardingLogBucket, forwardingLog)
+		if err != nil {
+			return err
+		}
+
+		return migtest.VerifyDB(
+			tx, forwardingAggregateBucket, aggregateIndex,
+		)
+	}
+
+	migtest.ApplyMigration(
+		t, before, after, MigrateForwardingAggregates, false,
+	)
+}
+
+// TestMigrateForwardingAggregatesEmpty asserts that the migration creates an
+// empty index on a database without forwarding log.
+func TestMigrateForwardingAggregatesEmpty(t *testing.T) {
+	after := func(tx kvdb.RwTx) error {
+		return migtest.VerifyDB(
+			tx, forwardingAggregateBucket, map[string]interface{}{},
+		)
+	}
+
+	migtest.ApplyMigration(
+		t, func(kvdb.RwTx) error { return nil }, after,
+		MigrateForwardingAggregates, false,
+	)
+}
@@ -25,6 +25,10 @@ const (
 type Options struct {
 	kvdb.BoltBackendConfig
 
+	// LdbConfig holds the tuning and compaction settings used when the
+	// database is opened with the leveldb backend.
+	LdbConfig kvdb.LdbConfig
+
 	// RejectCacheSize is the maximum number of rejectCacheEntries to hold
 	// in the rejection cache.
 	RejectCacheSize int
@@ -54,6 +58,7 @@ func DefaultOptions() Options {
 			AutoCompactMinAge: kvdb.DefaultBoltAutoCompactMinAge,
 			DBTimeout:         kvdb.DefaultDBTimeout,
 		},
+		LdbConfig:        *kvdb.DefaultLdbConfig(),
 		RejectCacheSize:  DefaultRejectCacheSize,
 		ChannelCacheSize: DefaultChannelCacheSize,
 		clock:            clock.NewDefaultClock(),
@@ -99,6 +104,14 @@ func OptionAutoCompactMinAge(minAge time.Duration) OptionModifier {
 	}
 }
 
+// OptionSetLdbConfig sets the tuning and compaction settings of the leveldb
+// backend.
+func OptionSetLdbConfig(cfg kvdb.LdbConfig) OptionModifier {
+	return func(o *Options) {
+		o.LdbConfig = cfg
+	}
+}
+
 // OptionSetBatchCommitInterval sets the batch commit interval for the internval
 // batch schedulers.
 func OptionSetBatchCommitInterval(interval time.Duration) OptionModifier {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
 )
 
 const (
@@ -22,6 +23,10 @@ const (
 
 // Options holds parameters for tuning and customizing a channeldb.DB.
 type Options struct {
+	// LdbConfig holds the tuning and compaction settings used when the
+	// database is opened with the leveldb backend.
+	LdbConfig kvdb.LdbConfig
+
 	// RejectCacheSize is the maximum number of rejectCacheEntries to hold
 	// in the rejection cache.
 	RejectCacheSize int
@@ -45,6 +50,7 @@ type Options struct {
 // DefaultOptions returns an Options populated with default values.
 func DefaultOptions() Options {
 	return Options{
+		LdbConfig:        *kvdb.DefaultLdbConfig(),
 		RejectCacheSize:  DefaultRejectCacheSize,
 		ChannelCacheSize: DefaultChannelCacheSize,
 		clock:            clock.NewDefaultClock(),
@@ -87,6 +93,14 @@ func OptionAutoCompactMinAge(minAge time.Duration) OptionModifier {
 	}
 }
 
+// OptionSetLdbConfig sets the tuning and compaction settings of the leveldb
+// backend.
+func OptionSetLdbConfig(cfg kvdb.LdbConfig) OptionModifier {
+	return func(o *Options) {
+		o.LdbConfig = cfg
+	}
+}
+
 // OptionSetBatchCommitInterval sets the batch commit interval for the internval
 // batch schedulers.
 func OptionSetBatchCommitInterval(interval time.Duration) OptionModifier {
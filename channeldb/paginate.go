@@ -1,6 +1,10 @@
 package channeldb
 
-import "github.com/lightningnetwork/lnd/kvdb"
+import (
+	"math"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
 
 type paginator struct {
 	// cursor is the cursor which we are using to iterate through a bucket.
@@ -14,6 +18,10 @@ type paginator struct {
 
 	// totalItems is the total number of items we allow in our response.
 	totalItems uint64
+
+	// minIndex and maxIndex restrict the query to the items with indexes
+	// within [minIndex, maxIndex].
+	minIndex, maxIndex uint64
 }
 
 // newPaginator returns a struct which can be used to query an indexed bucket
@@ -21,11 +29,36 @@ type paginator struct {
 func newPaginator(c kvdb.RCursor, reversed bool,
 	indexOffset, totalItems uint64) paginator {
 
+	return newBoundedPaginator(
+		c, reversed, indexOffset, totalItems, 0, math.MaxUint64,
+	)
+}
+
+// newBoundedPaginator returns a paginator which only returns the items with
+// indexes within [minIndex, maxIndex]. The query stops as soon as it leaves
+// this range, so that only the part of the bucket within it is iterated.
+func newBoundedPaginator(c kvdb.RCursor, reversed bool, indexOffset,
+	totalItems, minIndex, maxIndex uint64) paginator {
+
+	// Move the (exclusive) index offset to the start of the range if it
+	// lies before it, so that the cursor is seeked right into the range.
+	switch {
+	case !reversed && minIndex > 0 && indexOffset < minIndex-1:
+		indexOffset = minIndex - 1
+
+	case reversed && maxIndex < math.MaxUint64 &&
+		(indexOffset == 0 || indexOffset > maxIndex+1):
+
+		indexOffset = maxIndex + 1
+	}
+
 	return paginator{
 		cursor:      c,
 		reversed:    reversed,
 		indexOffset: indexOffset,
 		totalItems:  totalItems,
+		minIndex:    minIndex,
+		maxIndex:    maxIndex,
 	}
 }
 
@@ -124,6 +157,13 @@ func (p paginator) query(fetchAndAppend func(k, v []byte) (bool, error)) error {
 			break
 		}
 
+		// The items are iterated in order of their index, so we can
+		// exit as soon as we left the range of the query.
+		index := byteOrder.Uint64(indexKey)
+		if index < p.minIndex || index > p.maxIndex {
+			break
+		}
+
 		added, err := fetchAndAppend(indexKey, indexValue)
 		if err != nil {
 			return err
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -169,6 +170,11 @@ func (p *PaymentControl) InitPayment(paymentHash lntypes.Hash,
 			if err := indexBucket.Delete(seqBytes); err != nil {
 				return err
 			}
+
+			err := deletePaymentTimeIndexEntry(tx, bucket, seqBytes)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Once we have obtained a sequence number, we add an entry
@@ -181,6 +187,18 @@ func (p *PaymentControl) InitPayment(paymentHash lntypes.Hash,
 			return err
 		}
 
+		// We also index the sequence number by the creation time of
+		// the payment, so that payments can be queried by time.
+		timeIndex := tx.ReadWriteBucket(paymentsTimeIndexBucket)
+		err = timeIndex.Put(
+			timeIndexKey(
+				info.CreationTime, byteOrder.Uint64(sequenceNum),
+			), nil,
+		)
+		if err != nil {
+			return err
+		}
+
 		err = bucket.Put(paymentSequenceKey, sequenceNum)
 		if err != nil {
 			return err
@@ -235,6 +253,50 @@ func createPaymentIndexEntry(tx kvdb.RwTx, sequenceNumber []byte,
 	return indexes.Put(sequenceNumber, b.Bytes())
 }
 
+// deletePaymentTimeIndexEntry deletes the time index entry of the payment with
+// the given sequence number, which is either the payment stored in the given
+// payment bucket or one of its duplicates.
+func deletePaymentTimeIndexEntry(tx kvdb.RwTx, bucket kvdb.RBucket,
+	seqBytes []byte) error {
+
+	var creationTime time.Time
+	if bytes.Equal(bucket.Get(paymentSequenceKey), seqBytes) {
+		info, err := fetchCreationInfo(bucket)
+		if err != nil {
+			return err
+		}
+		creationTime = info.CreationTime
+	} else {
+		duplicates := bucket.NestedReadBucket(duplicatePaymentsBucket)
+		if duplicates == nil {
+			return nil
+		}
+
+		duplicate := duplicates.NestedReadBucket(seqBytes)
+		if duplicate == nil {
+			return nil
+		}
+
+		b := duplicate.Get(duplicatePaymentCreationInfoKey)
+		if b == nil {
+			return fmt.Errorf("creation info not found")
+		}
+
+		info, err := deserializeDuplicatePaymentCreationInfo(
+			bytes.NewReader(b),
+		)
+		if err != nil {
+			return err
+		}
+		creationTime = info.CreationTime
+	}
+
+	timeIndex := tx.ReadWriteBucket(paymentsTimeIndexBucket)
+	return timeIndex.Delete(
+		timeIndexKey(creationTime, byteOrder.Uint64(seqBytes)),
+	)
+}
+
 // deserializePaymentIndex deserializes a payment index entry. This function
 // currently only supports deserialization of payment hash indexes, and will
 // fail for other types.
@@ -563,6 +625,57 @@ func (p *PaymentControl) FetchPayment(paymentHash lntypes.Hash) (
 	return payment, nil
 }
 
+// DeletePayment deletes the payment with the given hash. If failedHtlcsOnly is
+// set, the payment itself is kept and only its failed HTLC attempts are
+// deleted. Payments that are in flight can't be deleted.
+func (p *PaymentControl) DeletePayment(paymentHash lntypes.Hash,
+	failedHtlcsOnly bool) error {
+
+	return kvdb.Update(p.db, func(tx kvdb.RwTx) error {
+		bucket, err := fetchPaymentBucket(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		paymentStatus, err := fetchPaymentStatus(bucket)
+		if err != nil {
+			return err
+		}
+
+		if paymentStatus == StatusInFlight {
+			return ErrPaymentInFlight
+		}
+
+		_, err = removePayment(tx, paymentHash, failedHtlcsOnly)
+		return err
+	}, func() {})
+}
+
+// DeletePayments deletes the payments selected by the query and returns the
+// number of deleted payments. If only failed HTLC attempts are deleted, the
+// number of payments that had failed attempts is returned instead.
+func (p *PaymentControl) DeletePayments(query DeletePaymentsQuery) (int,
+	error) {
+
+	if query.Status == StatusInFlight {
+		return 0, ErrPaymentInFlight
+	}
+
+	var numDeleted int
+	err := kvdb.Update(p.db, func(tx kvdb.RwTx) error {
+		var err error
+		numDeleted, err = deletePayments(tx, query)
+		return err
+	}, func() {
+		numDeleted = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numDeleted, nil
+}
+
 // createPaymentBucket creates or fetches the sub-bucket assigned to this
 // payment hash.
 func createPaymentBucket(tx kvdb.RwTx, paymentHash lntypes.Hash) (
@@ -551,6 +551,131 @@ func TestPaymentControlDeleteNonInFligt(t *testing.T) {
 	require.Equal(t, 1, indexCount)
 }
 
+// TestPaymentControlDeleteSingle tests that single payments and payments
+// selected by a query can be deleted, and that the payment index used for
+// pagination is kept consistent.
+func TestPaymentControlDeleteSingle(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := MakeTestDB()
+	defer cleanup()
+	require.NoError(t, err)
+
+	pControl := NewPaymentControl(db)
+
+	// Register three payments, each with a failed attempt. The first one
+	// fails, the second one succeeds and the third one stays in flight.
+	var (
+		infos     []*PaymentCreationInfo
+		attemptID uint64
+	)
+	for i := 0; i < 3; i++ {
+		info, attempt, preimg, err := genInfo()
+		require.NoError(t, err)
+		infos = append(infos, info)
+
+		hash := info.PaymentIdentifier
+		require.NoError(t, pControl.InitPayment(hash, info))
+
+		attempt.AttemptID = attemptID
+		attemptID++
+		_, err = pControl.RegisterAttempt(hash, attempt)
+		require.NoError(t, err)
+		_, err = pControl.FailAttempt(
+			hash, attempt.AttemptID, &HTLCFailInfo{
+				Reason: HTLCFailUnreadable,
+			},
+		)
+		require.NoError(t, err)
+
+		switch i {
+		case 0:
+			_, err = pControl.Fail(hash, FailureReasonNoRoute)
+			require.NoError(t, err)
+
+		case 1:
+			attempt.AttemptID = attemptID
+			attemptID++
+			_, err = pControl.RegisterAttempt(hash, attempt)
+			require.NoError(t, err)
+			_, err = pControl.SettleAttempt(
+				hash, attempt.AttemptID, &HTLCSettleInfo{
+					Preimage: preimg,
+				},
+			)
+			require.NoError(t, err)
+
+		case 2:
+			attempt.AttemptID = attemptID
+			attemptID++
+			_, err = pControl.RegisterAttempt(hash, attempt)
+			require.NoError(t, err)
+		}
+	}
+
+	assertPayments := func(expHashes ...lntypes.Hash) {
+		t.Helper()
+
+		resp, err := db.QueryPayments(PaymentsQuery{
+			MaxPayments:       10,
+			IncludeIncomplete: true,
+		})
+		require.NoError(t, err)
+
+		var hashes []lntypes.Hash
+		for _, payment := range resp.Payments {
+			hashes = append(hashes, payment.Info.PaymentIdentifier)
+		}
+		require.Equal(t, expHashes, hashes)
+	}
+
+	// Payments that are in flight or unknown can't be deleted.
+	err = pControl.DeletePayment(infos[2].PaymentIdentifier, false)
+	require.Equal(t, ErrPaymentInFlight, err)
+	err = pControl.DeletePayment(lntypes.Hash{1}, false)
+	require.Equal(t, ErrPaymentNotInitiated, err)
+
+	// Only delete the failed attempt of the succeeded payment.
+	err = pControl.DeletePayment(infos[1].PaymentIdentifier, true)
+	require.NoError(t, err)
+	payment, err := pControl.FetchPayment(infos[1].PaymentIdentifier)
+	require.NoError(t, err)
+	require.Len(t, payment.HTLCs, 1)
+	require.Nil(t, payment.HTLCs[0].Failure)
+
+	// The failed payment was created at its creation time, so it is not
+	// selected by a query for payments created before that time.
+	createdAt := infos[0].CreationTime
+	numDeleted, err := pControl.DeletePayments(DeletePaymentsQuery{
+		Status:        StatusFailed,
+		CreatedBefore: createdAt,
+	})
+	require.NoError(t, err)
+	require.Zero(t, numDeleted)
+
+	numDeleted, err = pControl.DeletePayments(DeletePaymentsQuery{
+		Status:        StatusFailed,
+		CreatedBefore: createdAt.Add(time.Second),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, numDeleted)
+	assertPayments(infos[1].PaymentIdentifier, infos[2].PaymentIdentifier)
+
+	// In-flight payments can't be selected.
+	_, err = pControl.DeletePayments(DeletePaymentsQuery{
+		Status: StatusInFlight,
+	})
+	require.Equal(t, ErrPaymentInFlight, err)
+
+	// Delete the succeeded payment, which leaves the in-flight payment.
+	err = pControl.DeletePayment(infos[1].PaymentIdentifier, false)
+	require.NoError(t, err)
+	assertPayments(infos[2].PaymentIdentifier)
+
+	_, err = pControl.FetchPayment(infos[1].PaymentIdentifier)
+	require.Equal(t, ErrPaymentNotInitiated, err)
+}
+
 // TestPaymentControlDeletePayments tests that DeletePayments correcly deletes
 // information about completed payments from the database.
 func TestPaymentControlDeletePayments(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"sort"
 	"time"
 
@@ -101,6 +102,15 @@ var (
 	// 	|--...
 	// 	|--<sequence-number>: <payment hash>
 	paymentsIndexBucket = []byte("payments-index-bucket")
+
+	// paymentsTimeIndexBucket is the name of the top-level bucket within
+	// the database that indexes the payment sequence numbers by the
+	// creation time of the payments. The values are empty.
+	// payments-time-index-bucket
+	// 	|--<creation-time><sequence-number>:
+	// 	|--...
+	// 	|--<creation-time><sequence-number>:
+	paymentsTimeIndexBucket = []byte("payments-time-index-bucket")
 )
 
 var (
@@ -482,6 +492,48 @@ type PaymentsQuery struct {
 	// fully completed. This means that pending payments, as well as failed
 	// payments will show up if this field is set to true.
 	IncludeIncomplete bool
+
+	// Statuses, if set, restricts the query to payments with one of the
+	// given statuses. In that case IncludeIncomplete is ignored.
+	Statuses []PaymentStatus
+
+	// CreationDateStart, if set, restricts the query to payments created
+	// at or after this unix timestamp in seconds.
+	CreationDateStart int64
+
+	// CreationDateEnd, if set, restricts the query to payments created at
+	// or before this unix timestamp in seconds.
+	CreationDateEnd int64
+
+	// CountTotal, if set, makes the query return the total number of
+	// payments in the database as well.
+	CountTotal bool
+}
+
+// matches returns true if the given payment passes the status and creation
+// time filters of the query.
+func (q *PaymentsQuery) matches(payment *MPPayment) bool {
+	if !createdWithin(
+		payment.Info.CreationTime, q.CreationDateStart,
+		q.CreationDateEnd,
+	) {
+
+		return false
+	}
+
+	// To keep compatibility with the old API, we only return
+	// non-succeeded payments if requested.
+	if len(q.Statuses) == 0 {
+		return payment.Status == StatusSucceeded || q.IncludeIncomplete
+	}
+
+	for _, status := range q.Statuses {
+		if payment.Status == status {
+			return true
+		}
+	}
+
+	return false
 }
 
 // PaymentsResponse contains the result of a query to the payments database.
@@ -505,6 +557,10 @@ type PaymentsResponse struct {
 	// in the event that the slice has too many events to fit into a single
 	// response. The offset can be used to continue forward pagination.
 	LastIndexOffset uint64
+
+	// TotalCount is the total number of payments in the database. It is
+	// only set if CountTotal is set in the query.
+	TotalCount uint64
 }
 
 // QueryPayments is a query to the payments database which is restricted
@@ -548,12 +604,8 @@ func (db *DB) QueryPayments(query PaymentsQuery) (PaymentsResponse, error) {
 				return false, err
 			}
 
-			// To keep compatibility with the old API, we only
-			// return non-succeeded payments if requested.
-			if payment.Status != StatusSucceeded &&
-				!query.IncludeIncomplete {
-
-				return false, err
+			if !query.matches(payment) {
+				return false, nil
 			}
 
 			// At this point, we've exhausted the offset, so we'll
@@ -562,11 +614,45 @@ func (db *DB) QueryPayments(query PaymentsQuery) (PaymentsResponse, error) {
 			return true, nil
 		}
 
+		// Counting the payments only iterates over the keys of the
+		// index, so that no payment needs to be loaded.
+		if query.CountTotal {
+			err := indexes.ForEach(func(_, _ []byte) error {
+				resp.TotalCount++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// If the query is restricted to a time range, we look up the
+		// sequence numbers of the payments created within it in the
+		// time index, so that only that part of the sequence index
+		// needs to be iterated.
+		minSeqNr, maxSeqNr := uint64(0), uint64(math.MaxUint64)
+		if query.CreationDateStart > 0 || query.CreationDateEnd > 0 {
+			timeIndex := tx.ReadBucket(paymentsTimeIndexBucket)
+			if timeIndex == nil {
+				return fmt.Errorf("time index bucket does " +
+					"not exist")
+			}
+
+			var found bool
+			minSeqNr, maxSeqNr, found = timeIndexBounds(
+				timeIndex.ReadCursor(), query.CreationDateStart,
+				query.CreationDateEnd,
+			)
+			if !found {
+				return nil
+			}
+		}
+
 		// Create a paginator which reads from our sequence index bucket
 		// with the parameters provided by the payments query.
-		paginator := newPaginator(
+		paginator := newBoundedPaginator(
 			indexes.ReadCursor(), query.Reversed, query.IndexOffset,
-			query.MaxPayments,
+			query.MaxPayments, minSeqNr, maxSeqNr,
 		)
 
 		// Run a paginated query, adding payments to our response.
@@ -677,150 +763,197 @@ func fetchPaymentWithSequenceNumber(tx kvdb.RTx, paymentHash lntypes.Hash,
 	return duplicatePayment, nil
 }
 
+// DeletePaymentsQuery selects the payments that are deleted by
+// DeletePayments. In-flight payments are never deleted.
+type DeletePaymentsQuery struct {
+	// Status only selects the payments with this status. If it is
+	// StatusUnknown, all completed payments are selected.
+	Status PaymentStatus
+
+	// CreatedBefore only selects the payments that were created before
+	// this time. If it is zero, payments of any age are selected.
+	CreatedBefore time.Time
+
+	// FailedHtlcsOnly only deletes the failed HTLC attempts of the
+	// selected payments, rather than the payments themselves.
+	FailedHtlcsOnly bool
+}
+
 // DeletePayments deletes all completed and failed payments from the DB. If
 // failedOnly is set, only failed payments will be considered for deletion. If
 // failedHtlsOnly is set, the payment itself won't be deleted, only failed HTLC
 // attempts.
 func (db *DB) DeletePayments(failedOnly, failedHtlcsOnly bool) error {
+	query := DeletePaymentsQuery{
+		FailedHtlcsOnly: failedHtlcsOnly,
+	}
+	if failedOnly {
+		query.Status = StatusFailed
+	}
+
 	return kvdb.Update(db, func(tx kvdb.RwTx) error {
-		payments := tx.ReadWriteBucket(paymentsRootBucket)
-		if payments == nil {
-			return nil
+		_, err := deletePayments(tx, query)
+		return err
+	}, func() {})
+}
+
+// deletePayments deletes the payments selected by the query, and returns the
+// number of deleted payments. If only failed HTLC attempts are deleted, the
+// number of payments that had failed attempts is returned instead.
+func deletePayments(tx kvdb.RwTx, query DeletePaymentsQuery) (int, error) {
+	payments := tx.ReadWriteBucket(paymentsRootBucket)
+	if payments == nil {
+		return 0, nil
+	}
+
+	// We can't delete the payments while iterating over them, so we
+	// collect the payment hashes first.
+	var hashes []lntypes.Hash
+	err := payments.ForEach(func(k, _ []byte) error {
+		bucket := payments.NestedReadBucket(k)
+		if bucket == nil {
+			// We only expect sub-buckets to be found in this
+			// top-level bucket.
+			return fmt.Errorf("non bucket element in payments " +
+				"bucket")
 		}
 
-		var (
-			// deleteBuckets is the set of payment buckets we need
-			// to delete.
-			deleteBuckets [][]byte
+		paymentStatus, err := fetchPaymentStatus(bucket)
+		if err != nil {
+			return err
+		}
 
-			// deleteIndexes is the set of indexes pointing to these
-			// payments that need to be deleted.
-			deleteIndexes [][]byte
+		switch {
+		// If the status is InFlight, we cannot safely delete the
+		// payment information, so we skip it.
+		case paymentStatus == StatusInFlight:
+			return nil
 
-			// deleteHtlcs maps a payment hash to the HTLC IDs we
-			// want to delete for that payment.
-			deleteHtlcs = make(map[lntypes.Hash][][]byte)
-		)
-		err := payments.ForEach(func(k, _ []byte) error {
-			bucket := payments.NestedReadBucket(k)
-			if bucket == nil {
-				// We only expect sub-buckets to be found in
-				// this top-level bucket.
-				return fmt.Errorf("non bucket element in " +
-					"payments bucket")
-			}
+		// Skip the payments that don't have the requested status.
+		case query.Status != StatusUnknown &&
+			paymentStatus != query.Status:
 
-			// If the status is InFlight, we cannot safely delete
-			// the payment information, so we return early.
-			paymentStatus, err := fetchPaymentStatus(bucket)
+			return nil
+		}
+
+		// Skip the payments that are too recent.
+		if !query.CreatedBefore.IsZero() {
+			creationInfo, err := fetchCreationInfo(bucket)
 			if err != nil {
 				return err
 			}
 
-			// If the status is InFlight, we cannot safely delete
-			// the payment information, so we return early.
-			if paymentStatus == StatusInFlight {
-				return nil
-			}
+			if !creationInfo.CreationTime.Before(
+				query.CreatedBefore,
+			) {
 
-			// If we requested to only delete failed payments, we
-			// can return if this one is not.
-			if failedOnly && paymentStatus != StatusFailed {
 				return nil
 			}
+		}
 
-			// If we are only deleting failed HTLCs, fetch them.
-			if failedHtlcsOnly {
-				htlcsBucket := bucket.NestedReadBucket(
-					paymentHtlcsBucket,
-				)
-
-				var htlcs []HTLCAttempt
-				if htlcsBucket != nil {
-					htlcs, err = fetchHtlcAttempts(
-						htlcsBucket,
-					)
-					if err != nil {
-						return err
-					}
-				}
-
-				// Now iterate though them and save the bucket
-				// keys for the failed HTLCs.
-				var toDelete [][]byte
-				for _, h := range htlcs {
-					if h.Failure == nil {
-						continue
-					}
-
-					htlcIDBytes := make([]byte, 8)
-					binary.BigEndian.PutUint64(
-						htlcIDBytes, h.AttemptID,
-					)
-
-					toDelete = append(toDelete, htlcIDBytes)
-				}
-
-				hash, err := lntypes.MakeHash(k)
-				if err != nil {
-					return err
-				}
-
-				deleteHtlcs[hash] = toDelete
-
-				// We return, we are only deleting attempts.
-				return nil
-			}
+		hash, err := lntypes.MakeHash(k)
+		if err != nil {
+			return err
+		}
+
+		hashes = append(hashes, hash)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
 
-			// Add the bucket to the set of buckets we can delete.
-			deleteBuckets = append(deleteBuckets, k)
+	var numDeleted int
+	for _, hash := range hashes {
+		deleted, err := removePayment(tx, hash, query.FailedHtlcsOnly)
+		if err != nil {
+			return 0, err
+		}
 
-			// Get all the sequence number associated with the
-			// payment, including duplicates.
-			seqNrs, err := fetchSequenceNumbers(bucket)
-			if err != nil {
-				return err
-			}
+		if deleted {
+			numDeleted++
+		}
+	}
 
-			deleteIndexes = append(deleteIndexes, seqNrs...)
-			return nil
-		})
+	return numDeleted, nil
+}
+
+// removePayment deletes the payment with the given hash, along with the
+// entries of the payment index that point to it. If failedHtlcsOnly is set,
+// only the failed HTLC attempts of the payment are deleted. It returns false
+// if there was nothing to delete. The caller must make sure the payment isn't
+// in flight.
+func removePayment(tx kvdb.RwTx, paymentHash lntypes.Hash,
+	failedHtlcsOnly bool) (bool, error) {
+
+	payments := tx.ReadWriteBucket(paymentsRootBucket)
+	if payments == nil {
+		return false, ErrPaymentNotInitiated
+	}
+
+	bucket := payments.NestedReadWriteBucket(paymentHash[:])
+	if bucket == nil {
+		return false, ErrPaymentNotInitiated
+	}
+
+	// If we are only deleting failed HTLCs, delete their buckets and keep
+	// the payment.
+	if failedHtlcsOnly {
+		htlcsBucket := bucket.NestedReadWriteBucket(paymentHtlcsBucket)
+		if htlcsBucket == nil {
+			return false, nil
+		}
+
+		htlcs, err := fetchHtlcAttempts(htlcsBucket)
 		if err != nil {
-			return err
+			return false, err
 		}
 
-		// Delete the failed HTLC attempts we found.
-		for hash, htlcIDs := range deleteHtlcs {
-			bucket := payments.NestedReadWriteBucket(hash[:])
-			htlcsBucket := bucket.NestedReadWriteBucket(
-				paymentHtlcsBucket,
-			)
+		var deleted bool
+		for _, h := range htlcs {
+			if h.Failure == nil {
+				continue
+			}
 
-			for _, aid := range htlcIDs {
-				err := htlcsBucket.DeleteNestedBucket(aid)
-				if err != nil {
-					return err
-				}
+			htlcIDBytes := make([]byte, 8)
+			binary.BigEndian.PutUint64(htlcIDBytes, h.AttemptID)
+
+			err := htlcsBucket.DeleteNestedBucket(htlcIDBytes)
+			if err != nil {
+				return false, err
 			}
+			deleted = true
 		}
 
-		for _, k := range deleteBuckets {
-			if err := payments.DeleteNestedBucket(k); err != nil {
-				return err
-			}
+		return deleted, nil
+	}
+
+	// Get all the sequence numbers associated with the payment, including
+	// duplicates, so that ListPayments no longer finds the payment.
+	seqNrs, err := fetchSequenceNumbers(bucket)
+	if err != nil {
+		return false, err
+	}
+
+	for _, k := range seqNrs {
+		err := deletePaymentTimeIndexEntry(tx, bucket, k)
+		if err != nil {
+			return false, err
 		}
+	}
 
-		// Get our index bucket and delete all indexes pointing to the
-		// payments we are deleting.
-		indexBucket := tx.ReadWriteBucket(paymentsIndexBucket)
-		for _, k := range deleteIndexes {
-			if err := indexBucket.Delete(k); err != nil {
-				return err
-			}
+	if err := payments.DeleteNestedBucket(paymentHash[:]); err != nil {
+		return false, err
+	}
+
+	indexBucket := tx.ReadWriteBucket(paymentsIndexBucket)
+	for _, k := range seqNrs {
+		if err := indexBucket.Delete(k); err != nil {
+			return false, err
 		}
+	}
 
-		return nil
-	}, func() {})
+	return true, nil
 }
 
 // fetchSequenceNumbers fetches all the sequence numbers associated with a
@@ -1014,6 +1147,33 @@ func serializeHop(w io.Writer, h *route.Hop) error {
 		records = append(records, h.MPP.Record())
 	}
 
+	// Add the fields of hops inside of a blinded route.
+	if h.EncryptedData != nil {
+		records = append(
+			records, record.NewEncryptedDataRecord(&h.EncryptedData),
+		)
+	}
+	if h.BlindingPoint != nil {
+		records = append(
+			records, record.NewBlindingPointRecord(&h.BlindingPoint),
+		)
+	}
+	if h.TotalAmtMsat != 0 {
+		totalAmt := uint64(h.TotalAmtMsat)
+		records = append(
+			records, record.NewTotalAmtMsatBlindedRecord(&totalAmt),
+		)
+	}
+
+	// Add the trampoline onion of a delegated payment.
+	if h.TrampolineOnion != nil {
+		records = append(
+			records, record.NewTrampolineOnionRecord(
+				&h.TrampolineOnion,
+			),
+		)
+	}
+
 	// Final sanity check to absolutely rule out custom records that are not
 	// custom and write into the standard range.
 	if err := h.CustomRecords.Validate(); err != nil {
@@ -1128,6 +1288,47 @@ func deserializeHop(r io.Reader) (*route.Hop, error) {
 		h.MPP = mpp
 	}
 
+	// Parse the fields of hops inside of a blinded route.
+	encryptedDataType := uint64(record.EncryptedDataOnionType)
+	if data, ok := tlvMap[encryptedDataType]; ok {
+		delete(tlvMap, encryptedDataType)
+		h.EncryptedData = data
+	}
+
+	blindingPointType := uint64(record.BlindingPointOnionType)
+	if pointBytes, ok := tlvMap[blindingPointType]; ok {
+		delete(tlvMap, blindingPointType)
+
+		rec := record.NewBlindingPointRecord(&h.BlindingPoint)
+		err := rec.Decode(
+			bytes.NewReader(pointBytes), uint64(len(pointBytes)),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	totalAmtType := uint64(record.TotalAmtMsatBlindedType)
+	if amtBytes, ok := tlvMap[totalAmtType]; ok {
+		delete(tlvMap, totalAmtType)
+
+		var totalAmt uint64
+		rec := record.NewTotalAmtMsatBlindedRecord(&totalAmt)
+		err := rec.Decode(
+			bytes.NewReader(amtBytes), uint64(len(amtBytes)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		h.TotalAmtMsat = lnwire.MilliSatoshi(totalAmt)
+	}
+
+	trampolineOnionType := uint64(record.TrampolineOnionType)
+	if onion, ok := tlvMap[trampolineOnionType]; ok {
+		delete(tlvMap, trampolineOnionType)
+		h.TrampolineOnion = onion
+	}
+
 	h.CustomRecords = tlvMap
 
 	return h, nil
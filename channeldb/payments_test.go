@@ -166,6 +166,59 @@ func TestRouteSerialization(t *testing.T) {
 	}
 }
 
+// TestBlindedRouteSerialization tests that the fields of hops inside of a
+// blinded route are persisted.
+func TestBlindedRouteSerialization(t *testing.T) {
+	t.Parallel()
+
+	blindedRoute := testRoute.Copy()
+	blindedRoute.Hops = append(blindedRoute.Hops, &route.Hop{
+		PubKeyBytes:   route.NewVertex(pub),
+		ChannelID:     54321,
+		EncryptedData: []byte{1, 2, 3},
+		BlindingPoint: pub,
+		CustomRecords: record.CustomSet{
+			65536: []byte{1},
+		},
+	}, &route.Hop{
+		PubKeyBytes:      route.NewVertex(pub),
+		OutgoingTimeLock: 111,
+		AmtToForward:     555,
+		EncryptedData:    []byte{4, 5},
+		TotalAmtMsat:     555,
+		CustomRecords: record.CustomSet{
+			65536: []byte{2},
+		},
+	})
+
+	var b bytes.Buffer
+	require.NoError(t, SerializeRoute(&b, *blindedRoute))
+
+	route2, err := DeserializeRoute(bytes.NewReader(b.Bytes()))
+	require.NoError(t, err)
+	require.NoError(t, assertRouteEqual(blindedRoute, &route2))
+}
+
+// TestTrampolineRouteSerialization tests that the trampoline onion of a
+// delegated payment is persisted.
+func TestTrampolineRouteSerialization(t *testing.T) {
+	t.Parallel()
+
+	trampolineRoute := testRoute.Copy()
+	trampolineRoute.Hops[len(trampolineRoute.Hops)-1].TrampolineOnion =
+		[]byte{1, 2, 3}
+
+	var b bytes.Buffer
+	require.NoError(t, SerializeRoute(&b, *trampolineRoute))
+
+	route2, err := DeserializeRoute(bytes.NewReader(b.Bytes()))
+	require.NoError(t, err)
+	require.NoError(t, assertRouteEqual(trampolineRoute, &route2))
+	require.Equal(
+		t, []byte{1, 2, 3}, route2.Hops[len(route2.Hops)-1].TrampolineOnion,
+	)
+}
+
 // deletePayment removes a payment with paymentHash from the payments database.
 func deletePayment(t *testing.T, db *DB, paymentHash lntypes.Hash, seqNr uint64) {
 	t.Helper()
@@ -510,6 +563,151 @@ func TestQueryPayments(t *testing.T) {
 	}
 }
 
+// TestQueryPaymentsFilters tests that QueryPayments only returns the payments
+// that match the status and creation time filters of the query, and that the
+// time index is kept up to date.
+func TestQueryPaymentsFilters(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanup()
+
+	pControl := NewPaymentControl(db)
+
+	// Create four payments with sequence numbers 1 to 4, created at 1000
+	// to 4000 seconds. The first payment fails, the third one stays in
+	// flight and the others succeed.
+	var infos []*PaymentCreationInfo
+	for i := 0; i < 4; i++ {
+		info, attempt, preimg, err := genInfo()
+		require.NoError(t, err)
+		info.CreationTime = time.Unix(int64(i+1)*1000, 0)
+		infos = append(infos, info)
+
+		hash := info.PaymentIdentifier
+		require.NoError(t, pControl.InitPayment(hash, info))
+
+		switch i {
+		case 0:
+			_, err = pControl.Fail(hash, FailureReasonNoRoute)
+			require.NoError(t, err)
+
+		case 2:
+
+		default:
+			attempt.AttemptID = uint64(i)
+			_, err = pControl.RegisterAttempt(hash, attempt)
+			require.NoError(t, err)
+			_, err = pControl.SettleAttempt(
+				hash, attempt.AttemptID, &HTLCSettleInfo{
+					Preimage: preimg,
+				},
+			)
+			require.NoError(t, err)
+		}
+	}
+
+	assertQuery := func(query PaymentsQuery, expSeqNrs ...uint64) {
+		t.Helper()
+
+		query.MaxPayments = math.MaxUint64
+		query.CountTotal = true
+		resp, err := db.QueryPayments(query)
+		require.NoError(t, err)
+
+		var seqNrs []uint64
+		for _, payment := range resp.Payments {
+			seqNrs = append(seqNrs, payment.SequenceNum)
+		}
+		require.Equal(t, expSeqNrs, seqNrs)
+
+		// The total count doesn't depend on the filters.
+		require.EqualValues(t, 4, resp.TotalCount)
+	}
+
+	assertTimeIndexSize := func(expSize int) {
+		t.Helper()
+
+		var size int
+		err := kvdb.View(db, func(tx kvdb.RTx) error {
+			timeIndex := tx.ReadBucket(paymentsTimeIndexBucket)
+			return timeIndex.ForEach(func(_, _ []byte) error {
+				size++
+				return nil
+			})
+		}, func() {
+			size = 0
+		})
+		require.NoError(t, err)
+		require.Equal(t, expSize, size)
+	}
+	assertTimeIndexSize(4)
+
+	// Query by creation time, in both directions.
+	assertQuery(PaymentsQuery{
+		CreationDateStart: 2000,
+		CreationDateEnd:   3000,
+		IncludeIncomplete: true,
+	}, 2, 3)
+	assertQuery(PaymentsQuery{
+		CreationDateEnd: 2500,
+	}, 2)
+	assertQuery(PaymentsQuery{
+		CreationDateStart: 2000,
+		Reversed:          true,
+		IncludeIncomplete: true,
+	}, 2, 3, 4)
+	assertQuery(PaymentsQuery{
+		CreationDateStart: 5000,
+		IncludeIncomplete: true,
+	})
+
+	// The index offset is still respected within the time range.
+	assertQuery(PaymentsQuery{
+		IndexOffset:       2,
+		CreationDateStart: 2000,
+		IncludeIncomplete: true,
+	}, 3, 4)
+	assertQuery(PaymentsQuery{
+		IndexOffset:       3,
+		CreationDateStart: 1000,
+		CreationDateEnd:   4000,
+		Reversed:          true,
+		IncludeIncomplete: true,
+	}, 1, 2)
+
+	// Query by status, which takes precedence over IncludeIncomplete.
+	assertQuery(PaymentsQuery{
+		Statuses: []PaymentStatus{StatusFailed, StatusInFlight},
+	}, 1, 3)
+	assertQuery(PaymentsQuery{
+		Statuses:          []PaymentStatus{StatusSucceeded},
+		CreationDateStart: 3000,
+		IncludeIncomplete: true,
+	}, 4)
+
+	// Retrying the failed payment moves it to a new sequence number and
+	// creation time.
+	infos[0].CreationTime = time.Unix(5000, 0)
+	err = pControl.InitPayment(infos[0].PaymentIdentifier, infos[0])
+	require.NoError(t, err)
+	assertTimeIndexSize(4)
+	assertQuery(PaymentsQuery{
+		CreationDateEnd:   1000,
+		IncludeIncomplete: true,
+	})
+	assertQuery(PaymentsQuery{
+		CreationDateStart: 5000,
+		IncludeIncomplete: true,
+	}, 5)
+
+	// Deleting a payment removes it from the time index.
+	err = pControl.DeletePayment(infos[1].PaymentIdentifier, false)
+	require.NoError(t, err)
+	assertTimeIndexSize(3)
+}
+
 // TestFetchPaymentWithSequenceNumber tests lookup of payments with their
 // sequence number. It sets up one payment with no duplicates, and another with
 // two duplicates in its duplicates bucket then uses these payments to test the
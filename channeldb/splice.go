@@ -0,0 +1,532 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// spliceCandidatesKey stores the pending splice transactions of a
+	// channel within its channel bucket.
+	spliceCandidatesKey = []byte("splice-candidates-key")
+
+	// spliceChanUpdateKey stores our last channel update of a channel
+	// before it was spliced within its channel bucket.
+	spliceChanUpdateKey = []byte("splice-chan-update-key")
+
+	// ErrSpliceCandidateNotFound is returned when a splice transaction
+	// isn't among the pending splices of a channel.
+	ErrSpliceCandidateNotFound = fmt.Errorf("splice candidate not found")
+)
+
+// SpliceCandidate is a pending splice transaction of a channel. A splice
+// transaction spends the current funding output of a channel and creates a
+// new funding output with a different capacity. Besides the splice
+// transaction itself, the candidate holds the commitments of the current
+// state that spend the new funding output, such that the channel can be
+// moved to the new funding output once the splice transaction confirms.
+type SpliceCandidate struct {
+	// SpliceTx is the splice transaction.
+	SpliceTx *wire.MsgTx
+
+	// FundingOutpoint is the new funding outpoint created by the splice
+	// transaction.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the capacity of the channel once the splice
+	// transaction confirms.
+	Capacity btcutil.Amount
+
+	// LocalCommitment is our commitment of the current state spending the
+	// new funding output, including the remote party's signature.
+	LocalCommitment ChannelCommitment
+
+	// RemoteCommitment is the remote party's commitment of the current
+	// state spending the new funding output, including our signature.
+	RemoteCommitment ChannelCommitment
+
+	// ChannelUpdate is our last channel update of the channel before the
+	// splice. It's used to carry our routing policy over to the new
+	// funding output. It's nil if we didn't have one.
+	ChannelUpdate *lnwire.ChannelUpdate
+}
+
+// serializeSpliceCandidates serializes the passed splice candidates.
+func serializeSpliceCandidates(w io.Writer,
+	candidates []*SpliceCandidate) error {
+
+	if err := WriteElement(w, uint16(len(candidates))); err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		err := WriteElements(
+			w, c.SpliceTx, c.FundingOutpoint, c.Capacity,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = serializeChanCommit(w, &c.LocalCommitment)
+		if err != nil {
+			return err
+		}
+		err = serializeChanCommit(w, &c.RemoteCommitment)
+		if err != nil {
+			return err
+		}
+
+		hasUpdate := c.ChannelUpdate != nil
+		if err := WriteElement(w, hasUpdate); err != nil {
+			return err
+		}
+		if hasUpdate {
+			if err := WriteElement(w, c.ChannelUpdate); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deserializeSpliceCandidates deserializes a set of splice candidates.
+func deserializeSpliceCandidates(r io.Reader) ([]*SpliceCandidate, error) {
+	var numCandidates uint16
+	if err := ReadElement(r, &numCandidates); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*SpliceCandidate, 0, numCandidates)
+	for i := uint16(0); i < numCandidates; i++ {
+		var (
+			c   SpliceCandidate
+			err error
+		)
+		err = ReadElements(
+			r, &c.SpliceTx, &c.FundingOutpoint, &c.Capacity,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		c.LocalCommitment, err = deserializeChanCommit(r)
+		if err != nil {
+			return nil, err
+		}
+		c.RemoteCommitment, err = deserializeChanCommit(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var hasUpdate bool
+		if err := ReadElement(r, &hasUpdate); err != nil {
+			return nil, err
+		}
+		if hasUpdate {
+			var msg lnwire.Message
+			if err := ReadElement(r, &msg); err != nil {
+				return nil, err
+			}
+
+			update, ok := msg.(*lnwire.ChannelUpdate)
+			if !ok {
+				return nil, fmt.Errorf("expected channel "+
+					"update, got %T", msg)
+			}
+			c.ChannelUpdate = update
+		}
+
+		candidates = append(candidates, &c)
+	}
+
+	return candidates, nil
+}
+
+// fetchSpliceCandidates reads the splice candidates stored within the passed
+// channel bucket.
+func fetchSpliceCandidates(chanBucket kvdb.RBucket) ([]*SpliceCandidate,
+	error) {
+
+	candidateBytes := chanBucket.Get(spliceCandidatesKey)
+	if candidateBytes == nil {
+		return nil, nil
+	}
+
+	return deserializeSpliceCandidates(bytes.NewReader(candidateBytes))
+}
+
+// AddSpliceCandidate persists a pending splice transaction of the channel.
+// The candidate must spend the current funding output of the channel.
+// Multiple candidates may be pending at once, of which at most one will
+// confirm.
+func (c *OpenChannel) AddSpliceCandidate(splice *SpliceCandidate) error {
+	c.Lock()
+	defer c.Unlock()
+
+	spendsFunding := false
+	for _, txIn := range splice.SpliceTx.TxIn {
+		if txIn.PreviousOutPoint == c.FundingOutpoint {
+			spendsFunding = true
+			break
+		}
+	}
+	if !spendsFunding {
+		return fmt.Errorf("splice tx %v doesn't spend funding output "+
+			"%v", splice.SpliceTx.TxHash(), c.FundingOutpoint)
+	}
+
+	return kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		candidates, err := fetchSpliceCandidates(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, candidate := range candidates {
+			if candidate.FundingOutpoint == splice.FundingOutpoint {
+				return fmt.Errorf("splice candidate %v "+
+					"already exists",
+					splice.FundingOutpoint)
+			}
+		}
+		candidates = append(candidates, splice)
+
+		var b bytes.Buffer
+		err = serializeSpliceCandidates(&b, candidates)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(spliceCandidatesKey, b.Bytes())
+	}, func() {})
+}
+
+// RemoveSpliceCandidate removes the pending splice transaction creating the
+// given funding outpoint. It's used to abort a splice before we signed the
+// splice transaction.
+func (c *OpenChannel) RemoveSpliceCandidate(
+	fundingOutpoint wire.OutPoint) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	return kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		candidates, err := fetchSpliceCandidates(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		remaining := make([]*SpliceCandidate, 0, len(candidates))
+		for _, candidate := range candidates {
+			if candidate.FundingOutpoint != fundingOutpoint {
+				remaining = append(remaining, candidate)
+			}
+		}
+		if len(remaining) == len(candidates) {
+			return ErrSpliceCandidateNotFound
+		}
+
+		if len(remaining) == 0 {
+			return chanBucket.Delete(spliceCandidatesKey)
+		}
+
+		var b bytes.Buffer
+		err = serializeSpliceCandidates(&b, remaining)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(spliceCandidatesKey, b.Bytes())
+	}, func() {})
+}
+
+// SpliceCandidates returns the pending splice transactions of the channel.
+func (c *OpenChannel) SpliceCandidates() ([]*SpliceCandidate, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var candidates []*SpliceCandidate
+	err := kvdb.View(c.Db, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		candidates, err = fetchSpliceCandidates(chanBucket)
+		return err
+	}, func() {
+		candidates = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// SplicedChannelUpdate returns our last channel update of the channel before
+// it was moved to its current funding output by a splice. It's nil if the
+// channel wasn't spliced or we didn't have a channel update.
+func (c *OpenChannel) SplicedChannelUpdate() (*lnwire.ChannelUpdate, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var update *lnwire.ChannelUpdate
+	err := kvdb.View(c.Db, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		updateBytes := chanBucket.Get(spliceChanUpdateKey)
+		if updateBytes == nil {
+			return nil
+		}
+
+		var msg lnwire.Message
+		err = ReadElement(bytes.NewReader(updateBytes), &msg)
+		if err != nil {
+			return err
+		}
+
+		var ok bool
+		update, ok = msg.(*lnwire.ChannelUpdate)
+		if !ok {
+			return fmt.Errorf("expected channel update, got %T",
+				msg)
+		}
+
+		return nil
+	}, func() {
+		update = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
+// ApplySplice moves the channel to the funding output created by the
+// confirmed splice transaction with the given txid. The channel data is moved
+// to the bucket of the new funding outpoint, the capacity, short channel ID
+// and commitments are replaced with those of the splice candidate and all
+// remaining candidates are discarded, as they spend the same funding output.
+func (c *OpenChannel) ApplySplice(spliceTxid chainhash.Hash,
+	shortChanID lnwire.ShortChannelID) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	var spliced *OpenChannel
+	err := kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		openChanBucket := tx.ReadWriteBucket(openChannelBucket)
+		if openChanBucket == nil {
+			return ErrNoChanDBExists
+		}
+
+		nodePub := c.IdentityPub.SerializeCompressed()
+		nodeChanBucket := openChanBucket.NestedReadWriteBucket(nodePub)
+		if nodeChanBucket == nil {
+			return ErrNoActiveChannels
+		}
+
+		chainBucket := nodeChanBucket.NestedReadWriteBucket(
+			c.ChainHash[:],
+		)
+		if chainBucket == nil {
+			return ErrNoActiveChannels
+		}
+
+		var oldKey bytes.Buffer
+		err := writeOutpoint(&oldKey, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+		chanBucket := chainBucket.NestedReadWriteBucket(oldKey.Bytes())
+		if chanBucket == nil {
+			return ErrChannelNotFound
+		}
+
+		candidates, err := fetchSpliceCandidates(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		var splice *SpliceCandidate
+		for _, candidate := range candidates {
+			if candidate.FundingOutpoint.Hash == spliceTxid {
+				splice = candidate
+				break
+			}
+		}
+		if splice == nil {
+			return ErrSpliceCandidateNotFound
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		// Move all channel data, including the revocation log, to the
+		// bucket of the new funding outpoint.
+		var newKey bytes.Buffer
+		err = writeOutpoint(&newKey, &splice.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+		newChanBucket, err := chainBucket.CreateBucket(newKey.Bytes())
+		switch {
+		case err == kvdb.ErrBucketExists:
+			return ErrChanAlreadyExists
+		case err != nil:
+			return err
+		}
+
+		if err := copyBucket(newChanBucket, chanBucket); err != nil {
+			return err
+		}
+		err = chainBucket.DeleteNestedBucket(oldKey.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = newChanBucket.Delete(spliceCandidatesKey)
+		if err != nil {
+			return err
+		}
+
+		// Our channel update from before the splice is kept, so that
+		// the new funding output is announced with the same routing
+		// policy.
+		err = newChanBucket.Delete(spliceChanUpdateKey)
+		if err != nil {
+			return err
+		}
+		if splice.ChannelUpdate != nil {
+			var b bytes.Buffer
+			err := WriteElement(&b, splice.ChannelUpdate)
+			if err != nil {
+				return err
+			}
+
+			err = newChanBucket.Put(spliceChanUpdateKey, b.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		channel.FundingOutpoint = splice.FundingOutpoint
+		channel.Capacity = splice.Capacity
+		channel.ShortChannelID = shortChanID
+		channel.FundingBroadcastHeight = shortChanID.BlockHeight
+		channel.FundingTxn = splice.SpliceTx
+		channel.LocalCommitment = splice.LocalCommitment
+		channel.RemoteCommitment = splice.RemoteCommitment
+
+		if err := putChanInfo(newChanBucket, channel); err != nil {
+			return err
+		}
+		err = putChanCommitments(newChanBucket, channel)
+		if err != nil {
+			return err
+		}
+
+		// Finally, the old funding outpoint is marked closed within the
+		// outpoint index and the new one is added.
+		opBucket := tx.ReadWriteBucket(outpointBucket)
+		err = putOutpointStatus(
+			opBucket, oldKey.Bytes(), outpointClosed,
+		)
+		if err != nil {
+			return err
+		}
+		err = putOutpointStatus(opBucket, newKey.Bytes(), outpointOpen)
+		if err != nil {
+			return err
+		}
+
+		spliced = channel
+
+		return nil
+	}, func() {
+		spliced = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.FundingOutpoint = spliced.FundingOutpoint
+	c.Capacity = spliced.Capacity
+	c.ShortChannelID = spliced.ShortChannelID
+	c.FundingBroadcastHeight = spliced.FundingBroadcastHeight
+	c.FundingTxn = spliced.FundingTxn
+	c.LocalCommitment = spliced.LocalCommitment
+	c.RemoteCommitment = spliced.RemoteCommitment
+	c.Packager = NewChannelPackager(spliced.ShortChannelID)
+
+	return nil
+}
+
+// copyBucket recursively copies all keys and nested buckets of src into dst.
+func copyBucket(dst, src kvdb.RwBucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+
+		nestedDst, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+
+		return copyBucket(nestedDst, src.NestedReadWriteBucket(k))
+	})
+}
+
+// putOutpointStatus writes the status of the given serialized outpoint to the
+// outpoint index.
+func putOutpointStatus(opBucket kvdb.RwBucket, chanPoint []byte,
+	status indexStatus) error {
+
+	statusByte := uint8(status)
+	statusRecord := tlv.MakePrimitiveRecord(indexStatusType, &statusByte)
+	opStream, err := tlv.NewStream(statusRecord)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := opStream.Encode(&b); err != nil {
+		return err
+	}
+
+	return opBucket.Put(chanPoint, b.Bytes())
+}
@@ -0,0 +1,225 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSplice creates a splice candidate spending the funding output of the
+// given channel.
+func newTestSplice(channel *OpenChannel,
+	capacity btcutil.Amount) *SpliceCandidate {
+
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(
+		wire.NewTxIn(&channel.FundingOutpoint, []byte{}, nil),
+	)
+	spliceTx.AddTxOut(wire.NewTxOut(int64(capacity), []byte{0x00, 0x20}))
+
+	localCommit := channel.LocalCommitment
+	localCommit.LocalBalance += 1000
+	remoteCommit := channel.RemoteCommitment
+	remoteCommit.RemoteBalance += 1000
+
+	return &SpliceCandidate{
+		SpliceTx: spliceTx,
+		FundingOutpoint: wire.OutPoint{
+			Hash:  spliceTx.TxHash(),
+			Index: 0,
+		},
+		Capacity:         capacity,
+		LocalCommitment:  localCommit,
+		RemoteCommitment: remoteCommit,
+	}
+}
+
+// newTestSpliceChanUpdate returns a channel update of the given channel, as
+// it is stored with a splice candidate.
+func newTestSpliceChanUpdate(channel *OpenChannel) *lnwire.ChannelUpdate {
+	return &lnwire.ChannelUpdate{
+		ShortChannelID:  channel.ShortChannelID,
+		Timestamp:       1000,
+		MessageFlags:    lnwire.ChanUpdateOptionMaxHtlc,
+		TimeLockDelta:   80,
+		HtlcMinimumMsat: 1000,
+		HtlcMaximumMsat: 100000,
+		BaseFee:         2000,
+		FeeRate:         300,
+		ExtraOpaqueData: []byte{0x01, 0x02},
+	}
+}
+
+// outpointStatus returns the raw entry of the given outpoint within the
+// outpoint index.
+func outpointStatus(t *testing.T, cdb *DB, chanPoint wire.OutPoint) []byte {
+	var b bytes.Buffer
+	require.NoError(t, writeOutpoint(&b, &chanPoint))
+
+	var status []byte
+	err := kvdb.View(cdb, func(tx kvdb.RTx) error {
+		status = tx.ReadBucket(outpointBucket).Get(b.Bytes())
+		return nil
+	}, func() {
+		status = nil
+	})
+	require.NoError(t, err)
+
+	return status
+}
+
+// TestSpliceCandidates asserts that splice candidates are persisted and that
+// only candidates spending the funding output are accepted.
+func TestSpliceCandidates(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+
+	candidates, err := channel.SpliceCandidates()
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	// A transaction not spending the funding output is rejected.
+	invalid := newTestSplice(channel, 20000)
+	invalid.SpliceTx.TxIn[0].PreviousOutPoint.Index++
+	require.Error(t, channel.AddSpliceCandidate(invalid))
+
+	splice1 := newTestSplice(channel, 20000)
+	splice2 := newTestSplice(channel, 30000)
+	splice2.ChannelUpdate = newTestSpliceChanUpdate(channel)
+	require.NoError(t, channel.AddSpliceCandidate(splice1))
+	require.NoError(t, channel.AddSpliceCandidate(splice2))
+
+	// Adding the same candidate twice fails.
+	require.Error(t, channel.AddSpliceCandidate(splice1))
+
+	candidates, err = channel.SpliceCandidates()
+	require.NoError(t, err)
+	require.Equal(t, []*SpliceCandidate{splice1, splice2}, candidates)
+
+	// Removing a candidate keeps the others, and removing an unknown one
+	// fails.
+	err = channel.RemoveSpliceCandidate(splice1.FundingOutpoint)
+	require.NoError(t, err)
+	err = channel.RemoveSpliceCandidate(splice1.FundingOutpoint)
+	require.ErrorIs(t, err, ErrSpliceCandidateNotFound)
+
+	candidates, err = channel.SpliceCandidates()
+	require.NoError(t, err)
+	require.Equal(t, []*SpliceCandidate{splice2}, candidates)
+
+	// The channel wasn't spliced yet, so it has no channel update from
+	// before a splice.
+	update, err := channel.SplicedChannelUpdate()
+	require.NoError(t, err)
+	require.Nil(t, update)
+}
+
+// TestApplySplice asserts that applying a confirmed splice moves the channel
+// to the new funding outpoint.
+func TestApplySplice(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+	oldChanPoint := channel.FundingOutpoint
+
+	// Add an entry to the revocation log, which must be moved along with
+	// the rest of the channel data.
+	err = kvdb.Update(cdb, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, channel.IdentityPub, &oldChanPoint,
+			channel.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		logBucket, err := chanBucket.CreateBucket(revocationLogBucket)
+		if err != nil {
+			return err
+		}
+
+		return logBucket.Put([]byte{1}, []byte{2})
+	}, func() {})
+	require.NoError(t, err)
+
+	splice := newTestSplice(channel, 20000)
+	splice.ChannelUpdate = newTestSpliceChanUpdate(channel)
+	require.NoError(t, channel.AddSpliceCandidate(splice))
+	require.NoError(t, channel.AddSpliceCandidate(
+		newTestSplice(channel, 30000),
+	))
+
+	// Applying an unknown splice fails.
+	scid := lnwire.ShortChannelID{BlockHeight: 200, TxIndex: 1}
+	err = channel.ApplySplice(chainhash.Hash{}, scid)
+	require.ErrorIs(t, err, ErrSpliceCandidateNotFound)
+
+	err = channel.ApplySplice(splice.FundingOutpoint.Hash, scid)
+	require.NoError(t, err)
+	require.Equal(t, splice.FundingOutpoint, channel.FundingOutpoint)
+	require.Equal(t, splice.Capacity, channel.Capacity)
+	require.Equal(t, scid, channel.ShortChannelID)
+
+	// The channel can no longer be found by its old funding outpoint.
+	_, err = cdb.FetchChannel(oldChanPoint)
+	require.ErrorIs(t, err, ErrChannelNotFound)
+
+	spliced, err := cdb.FetchChannel(splice.FundingOutpoint)
+	require.NoError(t, err)
+	require.Equal(t, splice.Capacity, spliced.Capacity)
+	require.Equal(t, scid, spliced.ShortChannelID)
+	require.Equal(t, splice.LocalCommitment, spliced.LocalCommitment)
+	require.Equal(t, splice.RemoteCommitment, spliced.RemoteCommitment)
+	require.Equal(t, channel.RevocationStore, spliced.RevocationStore)
+
+	// Our channel update from before the splice is kept.
+	update, err := spliced.SplicedChannelUpdate()
+	require.NoError(t, err)
+	require.Equal(t, splice.ChannelUpdate, update)
+
+	// The remaining candidates were discarded.
+	candidates, err := spliced.SpliceCandidates()
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	// The revocation log was moved.
+	err = kvdb.View(cdb, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, spliced.IdentityPub, &spliced.FundingOutpoint,
+			spliced.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		logBucket := chanBucket.NestedReadBucket(revocationLogBucket)
+		require.NotNil(t, logBucket)
+		require.Equal(t, []byte{2}, logBucket.Get([]byte{1}))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// Finally, the outpoint index marks the old outpoint closed and the
+	// new one open.
+	require.NotEqual(
+		t, outpointStatus(t, cdb, oldChanPoint),
+		outpointStatus(t, cdb, splice.FundingOutpoint),
+	)
+	require.NotNil(t, outpointStatus(t, cdb, splice.FundingOutpoint))
+}
@@ -0,0 +1,92 @@
+package channeldb
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// timeIndexKeyLen is the length of the keys of a time index, which are the
+// creation time in unix nano seconds followed by the index of the entry.
+const timeIndexKeyLen = 16
+
+// timeIndexKey returns the key of an entry in a time index. The creation time
+// comes first so that the cursor iterates over the entries in the order they
+// were created, and the index of the entry makes keys of entries that were
+// created at the same time unique.
+func timeIndexKey(creationTime time.Time, index uint64) []byte {
+	var unixNano int64
+	if !creationTime.IsZero() {
+		unixNano = creationTime.UnixNano()
+	}
+
+	var key [timeIndexKeyLen]byte
+	byteOrder.PutUint64(key[:8], uint64(unixNano))
+	byteOrder.PutUint64(key[8:], index)
+
+	return key[:]
+}
+
+// timeIndexBounds returns the lowest and the highest index of the entries in
+// a time index that were created within the given range of unix timestamps in
+// seconds. Both ends of the range are inclusive, and a zero end leaves the
+// range open on that side. The returned bool is false if no entry was created
+// within the range.
+//
+// The indexes are handed out in order of creation, so the entries that are
+// returned for the bounds are usually the first and the last entry in the
+// range. We still look at every entry in the range, so that the bounds are
+// exact even if the clock went backwards at some point.
+func timeIndexBounds(c kvdb.RCursor, start, end int64) (uint64, uint64,
+	bool) {
+
+	var startKey [8]byte
+	if start > 0 {
+		byteOrder.PutUint64(
+			startKey[:], uint64(time.Unix(start, 0).UnixNano()),
+		)
+	}
+
+	var (
+		minIndex, maxIndex uint64
+		found              bool
+	)
+	for k, _ := c.Seek(startKey[:]); k != nil; k, _ = c.Next() {
+		if len(k) != timeIndexKeyLen {
+			continue
+		}
+
+		// The keys are ordered by creation time, so we can stop once we
+		// moved past the end of the range.
+		unixNano := int64(byteOrder.Uint64(k[:8]))
+		if end > 0 && time.Unix(0, unixNano).Unix() > end {
+			break
+		}
+
+		index := byteOrder.Uint64(k[8:])
+		if !found || index < minIndex {
+			minIndex = index
+		}
+		if !found || index > maxIndex {
+			maxIndex = index
+		}
+		found = true
+	}
+
+	return minIndex, maxIndex, found
+}
+
+// createdWithin returns true if the given creation time lies within the given
+// range of unix timestamps in seconds. Both ends of the range are inclusive,
+// and a zero end leaves the range open on that side.
+func createdWithin(creationTime time.Time, start, end int64) bool {
+	switch {
+	case start > 0 && creationTime.Unix() < start:
+		return false
+
+	case end > 0 && creationTime.Unix() > end:
+		return false
+	}
+
+	return true
+}
@@ -65,6 +65,17 @@ type ClosedChannelEvent struct {
 	CloseSummary *channeldb.ChannelCloseSummary
 }
 
+// SplicedChannelEvent represents a new event where a channel was moved to the
+// funding output of a confirmed splice transaction.
+type SplicedChannelEvent struct {
+	// OldChannelPoint is the funding outpoint spent by the splice
+	// transaction.
+	OldChannelPoint wire.OutPoint
+
+	// Channel is the channel spending the new funding outpoint.
+	Channel *channeldb.OpenChannel
+}
+
 // New creates a new channel notifier. The ChannelNotifier gets channel
 // events from peers and from the chain arbitrator, and dispatches them to
 // its clients.
@@ -155,6 +166,20 @@ func (c *ChannelNotifier) NotifyClosedChannelEvent(chanPoint wire.OutPoint) {
 	}
 }
 
+// NotifySplicedChannelEvent notifies the channelEventNotifier goroutine that a
+// channel was moved to the funding output of a confirmed splice transaction.
+func (c *ChannelNotifier) NotifySplicedChannelEvent(oldChanPoint wire.OutPoint,
+	channel *channeldb.OpenChannel) {
+
+	event := SplicedChannelEvent{
+		OldChannelPoint: oldChanPoint,
+		Channel:         channel,
+	}
+	if err := c.ntfnServer.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send spliced channel update: %v", err)
+	}
+}
+
 // NotifyActiveLinkEvent notifies the channelEventNotifier goroutine that a
 // link has been added to the switch.
 func (c *ChannelNotifier) NotifyActiveLinkEvent(chanPoint wire.OutPoint) {
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var getCircuitBreakerCommand = cli.Command{
+	Name:     "getcircuitbreaker",
+	Category: "Channels",
+	Usage:    "Display the htlc circuit breaker limits and counters.",
+	Description: `
+	Returns the default circuit breaker limits together with the limits
+	and live counters of every peer that has custom limits or that
+	forwarded htlcs through the node.
+	`,
+	Action: actionDecorator(getCircuitBreaker),
+}
+
+func getCircuitBreaker(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	resp, err := client.GetCircuitBreaker(
+		ctxc, &routerrpc.GetCircuitBreakerRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var setCircuitBreakerLimitsCommand = cli.Command{
+	Name:     "setcircuitbreakerlimits",
+	Category: "Channels",
+	Usage:    "Set the htlc circuit breaker limits.",
+	Description: `
+	Sets the circuit breaker limits that apply to the forwards of incoming
+	peers. Without --peer, the default limits are set. With --peer, the
+	custom limits of that peer are set. Use --clear together with --peer
+	to remove the custom limits of a peer.
+
+	A limit of zero disables that limit. Forwards that exceed the limits
+	are failed, or queued until they can proceed if the mode is "queue".
+	The limits are persisted and restored when lnd restarts.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "peer",
+			Usage: "the hex encoded public key of the peer to set " +
+				"the limits for",
+		},
+		cli.BoolFlag{
+			Name: "clear",
+			Usage: "clear the custom limits of the peer, so that " +
+				"the default limits apply again",
+		},
+		cli.Uint64Flag{
+			Name: "max_pending_htlcs",
+			Usage: "the maximum number of htlcs from the peer that " +
+				"may be in flight",
+		},
+		cli.Uint64Flag{
+			Name: "max_pending_msat",
+			Usage: "the maximum total value in msat of the htlcs " +
+				"from the peer that may be in flight",
+		},
+		cli.Float64Flag{
+			Name: "htlc_rate",
+			Usage: "the number of new forwards per second that are " +
+				"accepted from the peer",
+		},
+		cli.Uint64Flag{
+			Name: "htlc_burst",
+			Usage: "the number of forwards that may exceed " +
+				"htlc_rate in a burst",
+		},
+		cli.StringFlag{
+			Name: "mode",
+			Usage: `what happens to forwards that exceed the ` +
+				`limits: must be one of "fail" or "queue"`,
+			Value: "fail",
+		},
+	},
+	Action: actionDecorator(setCircuitBreakerLimits),
+}
+
+func setCircuitBreakerLimits(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.SetCircuitBreakerLimitsRequest{}
+	if ctx.IsSet("peer") {
+		peer, err := hex.DecodeString(ctx.String("peer"))
+		if err != nil {
+			return err
+		}
+		req.Peer = peer
+	}
+
+	if ctx.Bool("clear") {
+		if len(req.Peer) == 0 {
+			return errors.New("--clear requires --peer")
+		}
+
+		_, err := client.SetCircuitBreakerLimits(ctxc, req)
+
+		return err
+	}
+
+	var mode routerrpc.CircuitBreakerLimits_Mode
+	switch ctx.String("mode") {
+	case "fail":
+		mode = routerrpc.CircuitBreakerLimits_FAIL
+	case "queue":
+		mode = routerrpc.CircuitBreakerLimits_QUEUE
+	default:
+		return errors.New(`mode must be one of "fail" or "queue"`)
+	}
+
+	req.Limits = &routerrpc.CircuitBreakerLimits{
+		MaxPendingHtlcs: uint32(ctx.Uint64("max_pending_htlcs")),
+		MaxPendingMsat:  ctx.Uint64("max_pending_msat"),
+		HtlcRate:        ctx.Float64("htlc_rate"),
+		HtlcBurst:       uint32(ctx.Uint64("htlc_burst")),
+		Mode:            mode,
+	}
+
+	_, err := client.SetCircuitBreakerLimits(ctxc, req)
+
+	return err
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var exportDBSnapshotCommand = cli.Command{
+	Name:     "exportdbsnapshot",
+	Category: "Channels",
+	Usage:    "Export a consistent snapshot of the channel database.",
+	Description: `
+	Export a consistent snapshot of the channel database while lnd is
+	running. The snapshot is created within a single read transaction of
+	the active database backend and doesn't depend on it, so it can be
+	restored into a bolt, leveldb or etcd database using the lnd-dbmigrate
+	tool:
+
+	    lnd-dbmigrate --restorefile=<output_file> --dest.backend=...
+
+	The snapshot contains the full state of all channels as well as all
+	invoices and payments and should be stored securely. Restoring an
+	outdated snapshot and then starting lnd with it can lead to a loss of
+	funds, as old channel states may be broadcast.
+
+	Only the channel database is exported. The wallet, macaroon and
+	watchtower databases are not part of the snapshot and need to be
+	backed up separately.
+	`,
+	ArgsUsage: "--output_file",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the snapshot to",
+		},
+		cli.BoolFlag{
+			Name: "remote",
+			Usage: "export the replicated remote database (etcd) " +
+				"instead of the local channel database",
+		},
+	},
+	Action: actionDecorator(exportDBSnapshot),
+}
+
+func exportDBSnapshot(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("output_file") {
+		return fmt.Errorf("output_file must be set")
+	}
+	outputFile := lncfg.CleanAndExpandPath(ctx.String("output_file"))
+
+	stream, err := client.ExportDatabaseSnapshot(
+		ctxc, &lnrpc.DatabaseSnapshotRequest{
+			Remote: ctx.Bool("remote"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(
+		outputFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600,
+	)
+	if err != nil {
+		return err
+	}
+
+	var written int
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(outputFile)
+			return err
+		}
+
+		n, err := f.Write(chunk.Data)
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(outputFile)
+			return err
+		}
+		written += n
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d byte database snapshot to %s\n", written,
+		outputFile)
+
+	return nil
+}
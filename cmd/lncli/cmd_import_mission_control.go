@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/urfave/cli"
@@ -14,9 +16,26 @@ import (
 const argsStr = "[source node] [dest node] [unix ts seconds] [amount in msat]"
 
 var importMissionControlCommand = cli.Command{
-	Name:      "importmc",
-	Category:  "Payments",
-	Usage:     "Import a result to the internal mission control state.",
+	Name:     "importmc",
+	Category: "Payments",
+	Usage: "Import a result or an export file to the internal mission " +
+		"control state.",
+	Description: `
+	Import a single result to the internal mission control state, or all
+	results of a file that was created with querymc --export if --file is
+	set.
+
+	The imported results are merged with the existing state of each pair
+	according to the merge policy:
+	    keep-newer: only use results that are more recent than the
+	                existing ones (default)
+	    overwrite:  replace the existing results of a pair
+	    average:    average the amounts and timestamps of the imported
+	                and the existing results
+
+	Imported results are persisted and survive a restart. They are only
+	removed by resetting mission control.
+	`,
 	ArgsUsage: fmt.Sprintf("importmc %v", argsStr),
 	Action:    actionDecorator(importMissionControl),
 	Flags: []cli.Flag{
@@ -24,6 +43,18 @@ var importMissionControlCommand = cli.Command{
 			Name:  "failure",
 			Usage: "whether the routing history entry was a failure",
 		},
+		cli.StringFlag{
+			Name: "file",
+			Usage: "the mission control export file to import " +
+				"instead of a single result",
+		},
+		cli.StringFlag{
+			Name: "merge",
+			Usage: "the policy used to merge the imported results " +
+				"with the existing state: keep-newer, " +
+				"overwrite or average",
+			Value: "keep-newer",
+		},
 	},
 }
 
@@ -31,6 +62,25 @@ func importMissionControl(ctx *cli.Context) error {
 	conn := getClientConn(ctx, false)
 	defer conn.Close()
 
+	policy, err := parseMergePolicy(ctx.String("merge"))
+	if err != nil {
+		return err
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+
+	if ctx.IsSet("file") {
+		if ctx.NArg() != 0 || ctx.IsSet("failure") {
+			return errors.New("a single result can't be imported " +
+				"together with a file")
+		}
+
+		return importMissionControlFile(
+			client, lncfg.CleanAndExpandPath(ctx.String("file")),
+			policy,
+		)
+	}
+
 	if ctx.NArg() != 4 {
 		return fmt.Errorf("please provide args: %v", argsStr)
 	}
@@ -66,8 +116,6 @@ func importMissionControl(ctx *cli.Context) error {
 		return errors.New("amount must be >0")
 	}
 
-	client := routerrpc.NewRouterClient(conn)
-
 	importResult := &routerrpc.PairHistory{
 		NodeFrom: sourceNode[:],
 		NodeTo:   destNode[:],
@@ -86,9 +134,64 @@ func importMissionControl(ctx *cli.Context) error {
 		Pairs: []*routerrpc.PairHistory{
 			importResult,
 		},
+		MergePolicy: policy,
 	}
 
 	rpcCtx := context.Background()
 	_, err = client.XImportMissionControl(rpcCtx, req)
 	return err
 }
+
+// importMissionControlFile imports all pairs of a mission control export file.
+func importMissionControlFile(client routerrpc.RouterClient, path string,
+	policy routerrpc.XImportMissionControlRequest_MergePolicy) error {
+
+	export, err := readMcExport(path)
+	if err != nil {
+		return err
+	}
+
+	pairs, err := export.rpcPairs()
+	if err != nil {
+		return err
+	}
+
+	if len(pairs) == 0 {
+		return errors.New("export file doesn't contain any pairs")
+	}
+
+	req := &routerrpc.XImportMissionControlRequest{
+		Pairs:       pairs,
+		MergePolicy: policy,
+	}
+
+	rpcCtx := context.Background()
+	if _, err := client.XImportMissionControl(rpcCtx, req); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %v pairs exported at %v\n", len(pairs),
+		time.Unix(export.ExportTime, 0))
+
+	return nil
+}
+
+// parseMergePolicy parses the name of a mission control merge policy.
+func parseMergePolicy(
+	policy string) (routerrpc.XImportMissionControlRequest_MergePolicy,
+	error) {
+
+	switch policy {
+	case "keep-newer":
+		return routerrpc.XImportMissionControlRequest_KEEP_NEWER, nil
+
+	case "overwrite":
+		return routerrpc.XImportMissionControlRequest_OVERWRITE, nil
+
+	case "average":
+		return routerrpc.XImportMissionControlRequest_AVERAGE, nil
+
+	default:
+		return 0, fmt.Errorf("unknown merge policy %v", policy)
+	}
+}
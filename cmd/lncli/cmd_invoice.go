@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/urfave/cli"
@@ -71,6 +72,12 @@ var addInvoiceCommand = cli.Command{
 			Usage: "creates an AMP invoice. If true, preimage " +
 				"should not be set.",
 		},
+		cli.BoolFlag{
+			Name: "blind",
+			Usage: "encode blinded payment paths instead of " +
+				"routing hints in the invoice to hide the " +
+				"identity of this node from the payer",
+		},
 	},
 	Action: actionDecorator(addInvoice),
 }
@@ -123,8 +130,9 @@ func addInvoice(ctx *cli.Context) error {
 		DescriptionHash: descHash,
 		FallbackAddr:    ctx.String("fallback_addr"),
 		Expiry:          ctx.Int64("expiry"),
-		Private:         ctx.Bool("private"),
+		Private:         ctx.Bool("private") && !ctx.Bool("blind"),
 		IsAmp:           ctx.Bool("amp"),
+		Blind:           ctx.Bool("blind"),
 	}
 
 	resp, err := client.AddInvoice(ctxc, invoice)
@@ -189,6 +197,54 @@ func lookupInvoice(ctx *cli.Context) error {
 	return nil
 }
 
+var deleteCanceledInvoiceCommand = cli.Command{
+	Name:      "deletecanceledinvoice",
+	Category:  "Invoices",
+	Usage:     "Delete a canceled invoice from the database.",
+	ArgsUsage: "rhash",
+	Description: `
+	Deletes the canceled invoice with the given payment hash from the
+	database. Invoices that are not canceled can't be deleted.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "rhash",
+			Usage: "the 32 byte payment hash of the canceled invoice " +
+				"to delete, the hash should be a hex-encoded " +
+				"string",
+		},
+	},
+	Action: actionDecorator(deleteCanceledInvoice),
+}
+
+func deleteCanceledInvoice(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var rHash string
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash = ctx.String("rhash")
+	case ctx.Args().Present():
+		rHash = ctx.Args().First()
+	default:
+		return fmt.Errorf("rhash argument missing")
+	}
+
+	resp, err := client.DeleteCanceledInvoice(
+		ctxc, &lnrpc.DelCanceledInvoiceReq{
+			InvoiceHash: rHash,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var listInvoicesCommand = cli.Command{
 	Name:     "listinvoices",
 	Category: "Invoices",
@@ -231,6 +287,23 @@ var listInvoicesCommand = cli.Command{
 			Usage: "if set, invoices succeeding the " +
 				"index_offset will be returned",
 		},
+		cli.Uint64Flag{
+			Name: "creation_date_start",
+			Usage: "if set, only invoices created at or after " +
+				"this unix timestamp are returned",
+		},
+		cli.Uint64Flag{
+			Name: "creation_date_end",
+			Usage: "if set, only invoices created at or before " +
+				"this unix timestamp are returned",
+		},
+		cli.StringSliceFlag{
+			Name: "state",
+			Usage: "if set, only invoices in this state are " +
+				"returned, one of 'open', 'settled', " +
+				"'canceled' or 'accepted'; can be specified " +
+				"multiple times",
+		},
 	},
 	Action: actionDecorator(listInvoices),
 }
@@ -241,10 +314,24 @@ func listInvoices(ctx *cli.Context) error {
 	defer cleanUp()
 
 	req := &lnrpc.ListInvoiceRequest{
-		PendingOnly:    ctx.Bool("pending_only"),
-		IndexOffset:    ctx.Uint64("index_offset"),
-		NumMaxInvoices: ctx.Uint64("max_invoices"),
-		Reversed:       !ctx.Bool("paginate-forwards"),
+		PendingOnly:       ctx.Bool("pending_only"),
+		IndexOffset:       ctx.Uint64("index_offset"),
+		NumMaxInvoices:    ctx.Uint64("max_invoices"),
+		Reversed:          !ctx.Bool("paginate-forwards"),
+		CreationDateStart: ctx.Uint64("creation_date_start"),
+		CreationDateEnd:   ctx.Uint64("creation_date_end"),
+	}
+
+	for _, state := range ctx.StringSlice("state") {
+		name := strings.ToUpper(state)
+		rpcState, ok := lnrpc.Invoice_InvoiceState_value[name]
+		if !ok {
+			return fmt.Errorf("invalid state: %v", state)
+		}
+
+		req.States = append(
+			req.States, lnrpc.Invoice_InvoiceState(rpcState),
+		)
 	}
 
 	invoices, err := client.ListInvoices(ctxc, req)
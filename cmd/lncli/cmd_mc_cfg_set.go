@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/urfave/cli"
 )
 
@@ -11,8 +14,17 @@ var setCfgCommand = cli.Command{
 	Description: `
 	Update the config values being used by mission control to calculate 
 	the probability that payment routes will succeed.
+
+	The estimator flag selects the probability model. The apriori model is
+	configured through the halflife, hopprob and weight flags, the bimodal
+	model through the bimodal flags.
 	`,
 	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "estimator",
+			Usage: "the probability estimator to use, either " +
+				"'apriori' or 'bimodal'",
+		},
 		cli.DurationFlag{
 			Name: "halflife",
 			Usage: "the amount of time taken to restore a node " +
@@ -38,6 +50,23 @@ var setCfgCommand = cli.Command{
 			Usage: "the amount of time to wait after a failure " +
 				"before raising failure amount",
 		},
+		cli.Uint64Flag{
+			Name: "bimodalscale",
+			Usage: "the bimodal liquidity scale in msat, which " +
+				"describes how unbalanced channels are " +
+				"assumed to be",
+		},
+		cli.Float64Flag{
+			Name: "bimodalweight",
+			Usage: "the degree to which results of other " +
+				"channels of a node are taken into account, " +
+				"expressed as value in [0;1]",
+		},
+		cli.DurationFlag{
+			Name: "bimodaldecaytime",
+			Usage: "the amount of time after which learned " +
+				"liquidity bounds are mostly forgotten",
+		},
 	},
 	Action: actionDecorator(setCfg),
 }
@@ -58,6 +87,48 @@ func setCfg(ctx *cli.Context) error {
 
 	var haveValue bool
 
+	if ctx.IsSet("estimator") {
+		haveValue = true
+
+		switch ctx.String("estimator") {
+		case routing.AprioriEstimatorName:
+			resp.Config.Model = routerrpc.MissionControlConfig_APRIORI
+
+		case routing.BimodalEstimatorName:
+			resp.Config.Model = routerrpc.MissionControlConfig_BIMODAL
+
+		default:
+			return fmt.Errorf("unknown estimator %v",
+				ctx.String("estimator"))
+		}
+	}
+
+	// When switching models, the parameters of the new model may not be
+	// set yet. We start from the defaults in that case.
+	if resp.Config.Model == routerrpc.MissionControlConfig_APRIORI &&
+		resp.Config.HalfLifeSeconds == 0 {
+
+		resp.Config.HalfLifeSeconds = uint64(
+			routing.DefaultPenaltyHalfLife.Seconds(),
+		)
+		resp.Config.HopProbability = float32(
+			routing.DefaultAprioriHopProbability,
+		)
+		resp.Config.Weight = float32(routing.DefaultAprioriWeight)
+	}
+
+	if resp.Config.Model == routerrpc.MissionControlConfig_BIMODAL &&
+		resp.Config.Bimodal == nil {
+
+		resp.Config.Bimodal = &routerrpc.BimodalParameters{
+			NodeWeight: routing.DefaultBimodalNodeWeight,
+			ScaleMsat:  uint64(routing.DefaultBimodalScaleMsat),
+			DecayTime: uint64(
+				routing.DefaultBimodalDecayTime.Seconds(),
+			),
+		}
+	}
+
 	if ctx.IsSet("halflife") {
 		haveValue = true
 		resp.Config.HalfLifeSeconds = uint64(ctx.Duration(
@@ -87,6 +158,35 @@ func setCfg(ctx *cli.Context) error {
 		).Seconds())
 	}
 
+	bimodalFlags := []string{
+		"bimodalscale", "bimodalweight", "bimodaldecaytime",
+	}
+	for _, flag := range bimodalFlags {
+		if ctx.IsSet(flag) &&
+			resp.Config.Model != routerrpc.MissionControlConfig_BIMODAL {
+
+			return fmt.Errorf("%v can only be set for the bimodal "+
+				"estimator", flag)
+		}
+	}
+
+	if ctx.IsSet("bimodalscale") {
+		haveValue = true
+		resp.Config.Bimodal.ScaleMsat = ctx.Uint64("bimodalscale")
+	}
+
+	if ctx.IsSet("bimodalweight") {
+		haveValue = true
+		resp.Config.Bimodal.NodeWeight = ctx.Float64("bimodalweight")
+	}
+
+	if ctx.IsSet("bimodaldecaytime") {
+		haveValue = true
+		resp.Config.Bimodal.DecayTime = uint64(ctx.Duration(
+			"bimodaldecaytime",
+		).Seconds())
+	}
+
 	if !haveValue {
 		return cli.ShowCommandHelp(ctx, "setmccfg")
 	}
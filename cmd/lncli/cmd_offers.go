@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var decodeOfferCommand = cli.Command{
+	Name:        "decodeoffer",
+	Category:    "Offers",
+	Usage:       "Decode a BOLT 12 offer.",
+	Description: "Decode the passed offer revealing the issuer, description and amount of the offer",
+	ArgsUsage:   "offer",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the bech32 encoded offer",
+		},
+	},
+	Action: actionDecorator(decodeOffer),
+}
+
+func decodeOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var offer string
+
+	switch {
+	case ctx.IsSet("offer"):
+		offer = ctx.String("offer")
+	case ctx.Args().Present():
+		offer = ctx.Args().First()
+	default:
+		return fmt.Errorf("offer argument missing")
+	}
+
+	resp, err := client.DecodeOffer(ctxc, &lnrpc.OfferString{
+		Offer: offer,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var createOfferCommand = cli.Command{
+	Name:     "createoffer",
+	Category: "Offers",
+	Usage:    "Create a new BOLT 12 offer.",
+	Description: `
+	Create a new offer that can be paid multiple times. Every payer fetches
+	a new invoice for the offer from this node using onion messages.
+
+	Offers are not stored by the node. The invoices created for an offer
+	carry the ID of the offer.
+	`,
+	ArgsUsage: "description [amt_msat]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "description",
+			Usage: "a description of what is offered",
+		},
+		cli.Uint64Flag{
+			Name: "amt_msat",
+			Usage: "the amount per item in millisatoshis, if " +
+				"not set the payer chooses the amount",
+		},
+		cli.StringFlag{
+			Name:  "issuer",
+			Usage: "an optional issuer of the offer",
+		},
+		cli.BoolFlag{
+			Name: "allow_quantity",
+			Usage: "allow the payer to request multiple items " +
+				"per invoice",
+		},
+		cli.Uint64Flag{
+			Name: "quantity_max",
+			Usage: "the maximum quantity of items per invoice if " +
+				"allow_quantity is set, unlimited if not set",
+		},
+		cli.Int64Flag{
+			Name: "absolute_expiry",
+			Usage: "the unix timestamp after which the offer " +
+				"expires",
+		},
+	},
+	Action: actionDecorator(createOffer),
+}
+
+func createOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.CreateOfferRequest{
+		Issuer:         ctx.String("issuer"),
+		AllowQuantity:  ctx.Bool("allow_quantity"),
+		QuantityMax:    ctx.Uint64("quantity_max"),
+		AbsoluteExpiry: ctx.Int64("absolute_expiry"),
+	}
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("description"):
+		req.Description = ctx.String("description")
+	case args.Present():
+		req.Description = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("description argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("amt_msat"):
+		req.AmountMsat = ctx.Uint64("amt_msat")
+	case args.Present():
+		_, err := fmt.Sscan(args.First(), &req.AmountMsat)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt_msat: %v", err)
+		}
+	}
+
+	resp, err := client.CreateOffer(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var payOfferCommand = cli.Command{
+	Name:     "payoffer",
+	Category: "Offers",
+	Usage:    "Pay a BOLT 12 offer.",
+	Description: `
+	Fetch an invoice for the offer from its issuer and pay it. The amount
+	is only required if the offer doesn't specify one.
+	`,
+	ArgsUsage: "offer [amt_msat]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the bech32 encoded offer to pay",
+		},
+		cli.Uint64Flag{
+			Name:  "amt_msat",
+			Usage: "the amount to pay in millisatoshis",
+		},
+		cli.Uint64Flag{
+			Name: "quantity",
+			Usage: "the quantity of items to request, if the " +
+				"offer supports quantities",
+		},
+		cli.StringFlag{
+			Name:  "payer_note",
+			Usage: "an optional note to the issuer",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "maximum fee allowed in satoshis when " +
+				"sending the payment",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the payment's amount used as " +
+				"the maximum fee allowed when sending the " +
+				"payment",
+		},
+	},
+	Action: actionDecorator(payOffer),
+}
+
+func payOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.PayOfferRequest{
+		Quantity:  ctx.Uint64("quantity"),
+		PayerNote: ctx.String("payer_note"),
+	}
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("offer"):
+		req.Offer = ctx.String("offer")
+	case args.Present():
+		req.Offer = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("offer argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("amt_msat"):
+		req.AmtMsat = ctx.Uint64("amt_msat")
+	case args.Present():
+		_, err := fmt.Sscan(args.First(), &req.AmtMsat)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt_msat: %v", err)
+		}
+	}
+
+	// The amount of the invoice is only known once it is fetched, so the
+	// fee limit is passed on as is.
+	switch {
+	case ctx.IsSet("fee_limit") && ctx.IsSet("fee_limit_percent"):
+		return fmt.Errorf("either fee_limit or fee_limit_percent " +
+			"can be set, but not both")
+
+	case ctx.IsSet("fee_limit"):
+		req.FeeLimit = &lnrpc.FeeLimit{
+			Limit: &lnrpc.FeeLimit_Fixed{
+				Fixed: ctx.Int64("fee_limit"),
+			},
+		}
+
+	case ctx.IsSet("fee_limit_percent"):
+		req.FeeLimit = &lnrpc.FeeLimit{
+			Limit: &lnrpc.FeeLimit_Percent{
+				Percent: ctx.Int64("fee_limit_percent"),
+			},
+		}
+	}
+
+	resp, err := client.PayOffer(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lightninglabs/protobuf-hex-display/jsonpb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var sendOnionMessageCommand = cli.Command{
+	Name:     "sendonionmessage",
+	Category: "Peers",
+	Usage:    "Send an onion message through the network.",
+	Description: `
+	Send an onion message along a path of nodes. The first node of the path
+	must be a connected peer that supports onion messages. Unless a blinded
+	destination is set, the last node of the path is the recipient.
+
+	The blinded destination is the json encoded reply path of a message
+	received with subscribeonionmessages.
+	`,
+	ArgsUsage: "--path=<pubkey>,<pubkey>,.. --data=<record_id>=<hex_value>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "path",
+			Usage: "a comma separated list of hex encoded node " +
+				"pubkeys the message is sent through",
+		},
+		cli.StringFlag{
+			Name: "blinded_destination",
+			Usage: "the json encoded blinded path the message is " +
+				"sent to after the path",
+		},
+		cli.StringFlag{
+			Name: "data",
+			Usage: "the records of the message. The required " +
+				"format is: <record_id>=<hex_value>,<record_id>=" +
+				"<hex_value>,.. Record ids start from 64.",
+		},
+		cli.BoolFlag{
+			Name: "reply",
+			Usage: "include a reply path along the reversed path " +
+				"in the message",
+		},
+	},
+	Action: actionDecorator(sendOnionMessage),
+}
+
+func sendOnionMessage(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.SendOnionMessageRequest{
+		CustomRecords:    make(map[uint64][]byte),
+		IncludeReplyPath: ctx.Bool("reply"),
+	}
+
+	if ctx.IsSet("path") {
+		for _, node := range strings.Split(ctx.String("path"), ",") {
+			pubKey, err := hex.DecodeString(node)
+			if err != nil {
+				return fmt.Errorf("unable to decode node "+
+					"pubkey: %v", err)
+			}
+			req.Path = append(req.Path, pubKey)
+		}
+	}
+
+	if ctx.IsSet("blinded_destination") {
+		req.BlindedDestination = &lnrpc.BlindedPath{}
+		err := jsonpb.UnmarshalString(
+			ctx.String("blinded_destination"),
+			req.BlindedDestination,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode blinded "+
+				"destination: %v", err)
+		}
+	}
+
+	if len(req.Path) == 0 && req.BlindedDestination == nil {
+		return errors.New("either a path or a blinded destination " +
+			"must be set")
+	}
+
+	data := ctx.String("data")
+	if data != "" {
+		for _, r := range strings.Split(data, ",") {
+			kv := strings.Split(r, "=")
+			if len(kv) != 2 {
+				return errors.New("invalid data format: " +
+					"multiple equal signs in record")
+			}
+
+			recordID, err := strconv.ParseUint(kv[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid data format: %v",
+					err)
+			}
+
+			hexValue, err := hex.DecodeString(kv[1])
+			if err != nil {
+				return fmt.Errorf("invalid data format: %v",
+					err)
+			}
+
+			req.CustomRecords[recordID] = hexValue
+		}
+	}
+
+	_, err := client.SendOnionMessage(ctxc, req)
+
+	return err
+}
+
+var subscribeOnionMessagesCommand = cli.Command{
+	Name:     "subscribeonionmessages",
+	Category: "Peers",
+	Usage:    "Subscribe to onion messages addressed to this node.",
+	Action:   actionDecorator(subscribeOnionMessages),
+}
+
+func subscribeOnionMessages(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeOnionMessages(
+		ctxc, &lnrpc.SubscribeOnionMessagesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -57,6 +58,13 @@ Signed base64 encoded PSBT or hex encoded raw wire TX (or path to text file): `
 	// the user from choosing a large file by accident and running into out
 	// of memory issues or other weird errors.
 	psbtMaxFileSize = 1024 * 1024
+
+	// The following are the channel types that can be requested with the
+	// channel_type flag.
+	channelTypeLegacy    = "legacy"
+	channelTypeTweakless = "tweakless"
+	channelTypeAnchors   = "anchors"
+	channelTypeTaproot   = "taproot"
 )
 
 // TODO(roasbeef): change default number of confirmations
@@ -200,6 +208,21 @@ var openChannelCommand = cli.Command{
 			Usage: "(optional) the maximum value in msat that " +
 				"can be pending within the channel at any given time",
 		},
+		cli.StringFlag{
+			Name: "channel_type",
+			Usage: fmt.Sprintf("(optional) the type of channel to "+
+				"propose to the remote peer (%q, %q, %q, %q), "+
+				"taproot channels must be private",
+				channelTypeLegacy, channelTypeTweakless,
+				channelTypeAnchors, channelTypeTaproot),
+		},
+		cli.BoolFlag{
+			Name: "dual_fund",
+			Usage: "(optional) open the channel with the v2 " +
+				"channel establishment protocol, allowing " +
+				"the remote peer to contribute funds to the " +
+				"channel as well",
+		},
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -239,6 +262,7 @@ func openChannel(ctx *cli.Context) error {
 		CloseAddress:               ctx.String("close_address"),
 		RemoteMaxValueInFlightMsat: ctx.Uint64("remote_max_value_in_flight_msat"),
 		MaxLocalCsv:                uint32(ctx.Uint64("max_local_csv")),
+		DualFund:                   ctx.Bool("dual_fund"),
 	}
 
 	switch {
@@ -307,6 +331,12 @@ func openChannel(ctx *cli.Context) error {
 
 	req.Private = ctx.Bool("private")
 
+	// Parse the channel type, if one was requested.
+	req.CommitmentType, err = parseChannelType(ctx.String("channel_type"))
+	if err != nil {
+		return err
+	}
+
 	// PSBT funding is a more involved, interactive process that is too
 	// large to also fit into this already long function.
 	if ctx.Bool("psbt") {
@@ -605,6 +635,201 @@ func openChannelPsbt(rpcCtx context.Context, ctx *cli.Context,
 	}
 }
 
+// parseChannelType parses the channel type given on the command line into the
+// commitment type to request.
+func parseChannelType(channelType string) (lnrpc.CommitmentType, error) {
+	switch channelType {
+	case "":
+		return lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE, nil
+	case channelTypeLegacy:
+		return lnrpc.CommitmentType_LEGACY, nil
+	case channelTypeTweakless:
+		return lnrpc.CommitmentType_STATIC_REMOTE_KEY, nil
+	case channelTypeAnchors:
+		return lnrpc.CommitmentType_ANCHORS, nil
+	case channelTypeTaproot:
+		return lnrpc.CommitmentType_SIMPLE_TAPROOT, nil
+	default:
+		return 0, fmt.Errorf("unsupported channel type %v",
+			channelType)
+	}
+}
+
+var batchOpenChannelCommand = cli.Command{
+	Name:     "batchopenchannel",
+	Category: "Channels",
+	Usage: "Open multiple channels to existing peers in a single " +
+		"transaction.",
+	Description: `
+	Attempt to open one or more new channels to an existing peer with the
+	given node-keys.
+
+	Example:
+	lncli batchopenchannel --sat_per_vbyte=5 '[{
+		"node_pubkey": "02abcdef...",
+		"local_funding_amount": 500000,
+		"private": true,
+		"close_address": "bc1qxxx..."
+	}, {
+		"node_pubkey": "03fedcba...",
+		"local_funding_amount": 200000,
+		"remote_csv_delay": 288,
+		"channel_type": "anchors"
+	}]'
+
+	All nodes listed must already be connected peers, otherwise funding will
+	fail.
+
+	The channel will be initialized with local-amt satoshis local and
+	push-amt satoshis for the remote node. Note that specifying push-amt
+	means you give that amount to the remote node as part of the channel
+	opening. Once the channel is open, a channelPoint (txid:vout) of the
+	funding output is returned.
+
+	If the remote peer supports the option upfront shutdown feature bit
+	(query listpeers to see their supported feature bits), an address to
+	enforce payout of funds on cooperative close can optionally be provided.
+	Note that if you set this value, you will not be able to cooperatively
+	close out to another address.
+
+	One can manually set the fee to be used for the funding transaction via
+	either the --conf_target or --sat_per_vbyte arguments. This is optional.
+	`,
+	ArgsUsage: "channels-json",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Int64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vByte that should be used when crafting " +
+				"the transaction",
+		},
+		cli.Uint64Flag{
+			Name: "min_confs",
+			Usage: "(optional) the minimum number of " +
+				"confirmations each one of your outputs used " +
+				"for the funding transaction must satisfy",
+			Value: defaultUtxoMinConf,
+		},
+		cli.StringFlag{
+			Name: "label",
+			Usage: "(optional) a label to attach to the batch " +
+				"transaction when storing it to the local " +
+				"wallet after publishing it",
+		},
+	},
+	Action: actionDecorator(batchOpenChannel),
+}
+
+type batchChannelJSON struct {
+	NodePubkey         string `json:"node_pubkey,omitempty"`
+	LocalFundingAmount int64  `json:"local_funding_amount,omitempty"`
+	PushSat            int64  `json:"push_sat,omitempty"`
+	Private            bool   `json:"private,omitempty"`
+	MinHtlcMsat        int64  `json:"min_htlc_msat,omitempty"`
+	RemoteCsvDelay     uint32 `json:"remote_csv_delay,omitempty"`
+	CloseAddress       string `json:"close_address,omitempty"`
+	PendingChanID      string `json:"pending_chan_id,omitempty"`
+	ChannelType        string `json:"channel_type,omitempty"`
+}
+
+func batchOpenChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	// Show command help if no arguments provided
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "batchopenchannel")
+		return nil
+	}
+
+	minConfs := int32(ctx.Uint64("min_confs"))
+	req := &lnrpc.BatchOpenChannelRequest{
+		TargetConf:       int32(ctx.Int64("conf_target")),
+		SatPerVbyte:      ctx.Int64("sat_per_vbyte"),
+		MinConfs:         minConfs,
+		SpendUnconfirmed: minConfs == 0,
+		Label:            ctx.String("label"),
+	}
+
+	// Let's try and parse the JSON part of the CLI now. The byte fields
+	// are hex encoded, so we can't unmarshal directly into the RPC struct.
+	var jsonChannels []*batchChannelJSON
+	if err := json.Unmarshal([]byte(args.First()), &jsonChannels); err != nil {
+		return fmt.Errorf("error parsing channels JSON: %v", err)
+	}
+
+	req.Channels = make([]*lnrpc.BatchOpenChannel, len(jsonChannels))
+	for idx, jsonChannel := range jsonChannels {
+		pubKeyBytes, err := hex.DecodeString(jsonChannel.NodePubkey)
+		if err != nil {
+			return fmt.Errorf("error parsing node pubkey hex: %v",
+				err)
+		}
+		pendingChanBytes, err := hex.DecodeString(
+			jsonChannel.PendingChanID,
+		)
+		if err != nil {
+			return fmt.Errorf("error parsing pending chan ID: %v",
+				err)
+		}
+		commitmentType, err := parseChannelType(jsonChannel.ChannelType)
+		if err != nil {
+			return err
+		}
+
+		req.Channels[idx] = &lnrpc.BatchOpenChannel{
+			NodePubkey:         pubKeyBytes,
+			LocalFundingAmount: jsonChannel.LocalFundingAmount,
+			PushSat:            jsonChannel.PushSat,
+			Private:            jsonChannel.Private,
+			MinHtlcMsat:        jsonChannel.MinHtlcMsat,
+			RemoteCsvDelay:     jsonChannel.RemoteCsvDelay,
+			CloseAddress:       jsonChannel.CloseAddress,
+			PendingChanId:      pendingChanBytes,
+			CommitmentType:     commitmentType,
+		}
+	}
+
+	resp, err := client.BatchOpenChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	type pendingChannel struct {
+		FundingTxid string `json:"funding_txid"`
+		OutputIndex uint32 `json:"output_index"`
+	}
+	pendingChannels := make([]pendingChannel, len(resp.PendingChannels))
+	for idx, pending := range resp.PendingChannels {
+		txid, err := chainhash.NewHash(pending.Txid)
+		if err != nil {
+			return err
+		}
+
+		pendingChannels[idx] = pendingChannel{
+			FundingTxid: txid.String(),
+			OutputIndex: pending.OutputIndex,
+		}
+	}
+
+	printJSON(struct {
+		PendingChannels []pendingChannel `json:"pending_channels"`
+	}{
+		PendingChannels: pendingChannels,
+	})
+
+	return nil
+}
+
 // printChanOpen prints the channel point of the channel open message.
 func printChanOpen(update *lnrpc.OpenStatusUpdate_ChanOpen) error {
 	channelPoint := update.ChanOpen.ChannelPoint
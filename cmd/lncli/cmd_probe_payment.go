@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/urfave/cli"
+)
+
+var probePaymentCommand = cli.Command{
+	Name:     "probepayment",
+	Category: "Payments",
+	Usage:    "Probe whether a payment can be delivered.",
+	Description: `
+	Send a probe to find out whether a payment can be delivered, without
+	risking any funds. The probe uses a random payment hash that the
+	destination doesn't know, so it can't be settled. Amounts that are too
+	large for a single route are probed with multiple shards.
+
+	Either a destination and an amount or a payment request must be
+	specified. If the probe succeeds, the routes that reached the
+	destination and their total fee are returned.
+	`,
+	ArgsUsage: "[--dest=D --amt=A | --pay_req=R]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "dest, d",
+			Usage: "the compressed identity pubkey of the " +
+				"destination",
+		},
+		cli.Int64Flag{
+			Name:  "amt, a",
+			Usage: "the number of satoshis to probe",
+		},
+		cli.StringFlag{
+			Name: "pay_req",
+			Usage: "a zpay32 encoded payment request to probe, its " +
+				"payment hash is not used",
+		},
+		cli.IntFlag{
+			Name: "final_cltv_delta",
+			Usage: "the number of blocks the last hop has to " +
+				"reveal the preimage",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "maximum fee allowed in satoshis for the " +
+				"probed payment",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the probed amount used as the " +
+				"maximum fee allowed",
+		},
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "the maximum amount of time we should spend " +
+				"on the probe",
+			Value: paymentTimeout,
+		},
+		cltvLimitFlag,
+		lastHopFlag,
+		cli.Uint64Flag{
+			Name: "outgoing_chan_id",
+			Usage: "short channel id of the outgoing channel to " +
+				"use for the first hop of the probe",
+		},
+		maxPartsFlag, maxShardSizeSatFlag, maxShardSizeMsatFlag,
+	},
+	Action: actionDecorator(probePayment),
+}
+
+func probePayment(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := lnrpc.NewLightningClient(conn)
+	routerClient := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.ProbePaymentRequest{
+		Amt:            ctx.Int64("amt"),
+		PaymentRequest: ctx.String("pay_req"),
+		FinalCltvDelta: int32(ctx.Int("final_cltv_delta")),
+		CltvLimit:      int32(ctx.Int(cltvLimitFlag.Name)),
+		MaxParts:       uint32(ctx.Uint(maxPartsFlag.Name)),
+	}
+
+	switch {
+	case ctx.IsSet("dest") && req.PaymentRequest != "":
+		return errors.New("either dest or pay_req can be set, but " +
+			"not both")
+
+	case ctx.IsSet("dest"):
+		dest, err := route.NewVertexFromStr(ctx.String("dest"))
+		if err != nil {
+			return err
+		}
+		req.Dest = dest[:]
+
+	case req.PaymentRequest == "":
+		return errors.New("dest or pay_req must be set")
+	}
+
+	// The fee limit is relative to the probed amount, which is taken from
+	// the invoice unless it is specified.
+	amt := req.Amt
+	if amt == 0 && req.PaymentRequest != "" {
+		payReq, err := client.DecodePayReq(ctxc, &lnrpc.PayReqString{
+			PayReq: req.PaymentRequest,
+		})
+		if err != nil {
+			return err
+		}
+		amt = payReq.NumSatoshis
+	}
+
+	feeLimit, err := retrieveFeeLimit(ctx, amt)
+	if err != nil {
+		return err
+	}
+	req.FeeLimitSat = feeLimit
+
+	timeout := ctx.Duration("timeout")
+	if timeout <= 0 {
+		return errors.New("probe timeout must be greater than zero")
+	}
+	req.TimeoutSeconds = int32(timeout.Seconds())
+
+	if outChan := ctx.Uint64("outgoing_chan_id"); outChan != 0 {
+		req.OutgoingChanIds = []uint64{outChan}
+	}
+
+	if ctx.IsSet(lastHopFlag.Name) {
+		lastHop, err := route.NewVertexFromStr(
+			ctx.String(lastHopFlag.Name),
+		)
+		if err != nil {
+			return err
+		}
+		req.LastHopPubkey = lastHop[:]
+	}
+
+	switch {
+	case ctx.Uint64(maxShardSizeMsatFlag.Name) != 0 &&
+		ctx.Uint64(maxShardSizeSatFlag.Name) != 0:
+		return fmt.Errorf("only --max_shard_size_msat or " +
+			"--max_shard_size_sat should be set, but not both")
+
+	case ctx.Uint64(maxShardSizeMsatFlag.Name) != 0:
+		req.MaxShardSizeMsat = ctx.Uint64(maxShardSizeMsatFlag.Name)
+
+	case ctx.Uint64(maxShardSizeSatFlag.Name) != 0:
+		req.MaxShardSizeMsat = uint64(lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(ctx.Uint64(maxShardSizeSatFlag.Name)),
+		))
+	}
+
+	resp, err := routerClient.ProbePayment(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
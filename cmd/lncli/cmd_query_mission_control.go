@@ -1,6 +1,11 @@
 package main
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 
 	"github.com/urfave/cli"
@@ -10,7 +15,19 @@ var queryMissionControlCommand = cli.Command{
 	Name:     "querymc",
 	Category: "Payments",
 	Usage:    "Query the internal mission control state.",
-	Action:   actionDecorator(queryMissionControl),
+	Description: `
+	Query the internal mission control state. If --export is set, the state
+	is written to the given file in a versioned format that can be imported
+	into this or other nodes with importmc --file.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "export",
+			Usage: "the file to export the mission control state " +
+				"to instead of printing it",
+		},
+	},
+	Action: actionDecorator(queryMissionControl),
 }
 
 func queryMissionControl(ctx *cli.Context) error {
@@ -26,7 +43,27 @@ func queryMissionControl(ctx *cli.Context) error {
 		return err
 	}
 
-	printRespJSON(snapshot)
+	if !ctx.IsSet("export") {
+		printRespJSON(snapshot)
+
+		return nil
+	}
+
+	// Record the node that the state is exported from, so that the
+	// origin of an export can be told apart when it is shared.
+	lnClient := lnrpc.NewLightningClient(conn)
+	info, err := lnClient.GetInfo(ctxc, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		return err
+	}
+
+	export := newMcExport(info.IdentityPubkey, time.Now(), snapshot.Pairs)
+	exportFile := lncfg.CleanAndExpandPath(ctx.String("export"))
+	if err := writeMcExport(exportFile, export); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %v pairs to %v\n", len(export.Pairs), exportFile)
 
 	return nil
 }
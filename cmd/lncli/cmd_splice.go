@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+// spliceFlags are the flags shared by the splicein and spliceout commands.
+var spliceFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "funding_txid",
+		Usage: "the txid of the channel's funding transaction",
+	},
+	cli.IntFlag{
+		Name: "output_index",
+		Usage: "the output index for the funding output of the funding " +
+			"transaction",
+	},
+	cli.Int64Flag{
+		Name: "amt",
+		Usage: "the number of satoshis to splice into or out of " +
+			"the channel",
+	},
+	cli.Int64Flag{
+		Name: "conf_target",
+		Usage: "(optional) the number of blocks that the splice " +
+			"transaction *should* confirm in, will be used for " +
+			"fee estimation",
+	},
+	cli.Uint64Flag{
+		Name: "sat_per_vbyte",
+		Usage: "(optional) a manual fee expressed in sat/vbyte that " +
+			"should be used when crafting the splice transaction",
+	},
+}
+
+var spliceInCommand = cli.Command{
+	Name:     "splicein",
+	Category: "Channels",
+	Usage:    "Add funds from the wallet to an existing channel.",
+	Description: `
+	Add amt satoshis from the wallet to an existing channel by negotiating
+	a splice transaction with the peer. Both nodes must have
+	protocol.splice set.
+
+	The channel can't be used until the splice transaction confirms, at
+	which point the channel moves to the new funding output and is
+	announced again.
+
+	To view which funding_txids/output_indexes can be used for this command,
+	see the channel_point values within the listchannels command output.
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags:     spliceFlags,
+	Action:    actionDecorator(spliceIn),
+}
+
+func spliceIn(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "splicein")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.IsSet("amt") {
+		return fmt.Errorf("amt argument missing")
+	}
+
+	resp, err := client.SpliceIn(ctxc, &lnrpc.SpliceInRequest{
+		ChannelPoint: channelPoint,
+		Amount:       ctx.Int64("amt"),
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var spliceOutCommand = cli.Command{
+	Name:     "spliceout",
+	Category: "Channels",
+	Usage:    "Remove funds from an existing channel.",
+	Description: `
+	Remove amt satoshis from an existing channel and send them to the
+	on-chain address addr by negotiating a splice transaction with the
+	peer. The fee of the splice transaction is paid from the local channel
+	balance. Both nodes must have protocol.splice set.
+
+	The channel can't be used until the splice transaction confirms, at
+	which point the channel moves to the new funding output and is
+	announced again.
+
+	To view which funding_txids/output_indexes can be used for this command,
+	see the channel_point values within the listchannels command output.
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name: "addr",
+			Usage: "the base58 or bech32 encoded bitcoin address " +
+				"to send the removed funds to",
+		},
+	}, spliceFlags...),
+	Action: actionDecorator(spliceOut),
+}
+
+func spliceOut(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "spliceout")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !ctx.IsSet("amt"):
+		return fmt.Errorf("amt argument missing")
+
+	case !ctx.IsSet("addr"):
+		return fmt.Errorf("addr argument missing")
+	}
+
+	resp, err := client.SpliceOut(ctxc, &lnrpc.SpliceOutRequest{
+		ChannelPoint: channelPoint,
+		Amount:       ctx.Int64("amt"),
+		Address:      ctx.String("addr"),
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
@@ -1476,6 +1476,32 @@ func closedChannels(ctx *cli.Context) error {
 	return nil
 }
 
+var listAliasesCommand = cli.Command{
+	Name:     "listaliases",
+	Category: "Channels",
+	Usage:    "List all scid aliases.",
+	Description: `
+	List the scid aliases of all channels that use them, keyed by the
+	base scid of each channel.
+	`,
+	Action: actionDecorator(listAliases),
+}
+
+func listAliases(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListAliases(ctxc, &lnrpc.ListAliasesRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var describeGraphCommand = cli.Command{
 	Name:     "describegraph",
 	Category: "Graph",
@@ -1578,6 +1604,29 @@ var listPaymentsCommand = cli.Command{
 				"index_offset will be returned, allowing " +
 				"forwards pagination",
 		},
+		cli.BoolFlag{
+			Name: "count_total_payments",
+			Usage: "if set, the total number of payments in the " +
+				"database is returned as well, which can " +
+				"take a while if there are a lot of payments",
+		},
+		cli.Uint64Flag{
+			Name: "creation_date_start",
+			Usage: "if set, only payments created at or after " +
+				"this unix timestamp are returned",
+		},
+		cli.Uint64Flag{
+			Name: "creation_date_end",
+			Usage: "if set, only payments created at or before " +
+				"this unix timestamp are returned",
+		},
+		cli.StringSliceFlag{
+			Name: "status",
+			Usage: "if set, only payments with this status are " +
+				"returned, one of 'unknown', 'in_flight', " +
+				"'succeeded' or 'failed'; can be specified " +
+				"multiple times",
+		},
 	},
 	Action: actionDecorator(listPayments),
 }
@@ -1588,10 +1637,25 @@ func listPayments(ctx *cli.Context) error {
 	defer cleanUp()
 
 	req := &lnrpc.ListPaymentsRequest{
-		IncludeIncomplete: ctx.Bool("include_incomplete"),
-		IndexOffset:       uint64(ctx.Uint("index_offset")),
-		MaxPayments:       uint64(ctx.Uint("max_payments")),
-		Reversed:          !ctx.Bool("paginate_forwards"),
+		IncludeIncomplete:  ctx.Bool("include_incomplete"),
+		IndexOffset:        uint64(ctx.Uint("index_offset")),
+		MaxPayments:        uint64(ctx.Uint("max_payments")),
+		Reversed:           !ctx.Bool("paginate_forwards"),
+		CountTotalPayments: ctx.Bool("count_total_payments"),
+		CreationDateStart:  ctx.Uint64("creation_date_start"),
+		CreationDateEnd:    ctx.Uint64("creation_date_end"),
+	}
+
+	for _, status := range ctx.StringSlice("status") {
+		name := strings.ToUpper(status)
+		rpcStatus, ok := lnrpc.Payment_PaymentStatus_value[name]
+		if !ok {
+			return fmt.Errorf("invalid status: %v", status)
+		}
+
+		req.Statuses = append(
+			req.Statuses, lnrpc.Payment_PaymentStatus(rpcStatus),
+		)
 	}
 
 	payments, err := client.ListPayments(ctxc, req)
@@ -1603,6 +1667,122 @@ func listPayments(ctx *cli.Context) error {
 	return nil
 }
 
+var deletePaymentCommand = cli.Command{
+	Name:      "deletepayment",
+	Category:  "Payments",
+	Usage:     "Delete a single outgoing payment.",
+	ArgsUsage: "payment_hash",
+	Description: "Deletes the payment with the given payment hash from the " +
+		"database. In-flight payments can't be deleted. If " +
+		"--failed_htlcs_only is set, only the failed htlc attempts " +
+		"of the payment are deleted.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_hash",
+			Usage: "the hex encoded hash of the payment to delete",
+		},
+		cli.BoolFlag{
+			Name: "failed_htlcs_only",
+			Usage: "if set, only the failed htlc attempts of the " +
+				"payment are deleted",
+		},
+	},
+	Action: actionDecorator(deletePayment),
+}
+
+func deletePayment(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var hashStr string
+	switch {
+	case ctx.IsSet("payment_hash"):
+		hashStr = ctx.String("payment_hash")
+	case ctx.Args().Present():
+		hashStr = ctx.Args().First()
+	default:
+		return fmt.Errorf("payment_hash argument missing")
+	}
+
+	hash, err := hex.DecodeString(hashStr)
+	if err != nil {
+		return fmt.Errorf("error parsing payment_hash: %v", err)
+	}
+
+	resp, err := client.DeletePayment(ctxc, &lnrpc.DeletePaymentRequest{
+		PaymentHash:     hash,
+		FailedHtlcsOnly: ctx.Bool("failed_htlcs_only"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var deletePaymentsCommand = cli.Command{
+	Name:     "deletepayments",
+	Category: "Payments",
+	Usage:    "Delete the outgoing payments matching the given filters.",
+	Description: "Deletes all completed payments with the given status " +
+		"from the database, optionally restricted to payments " +
+		"created before the given unix timestamp. In-flight " +
+		"payments are never deleted. If --failed_htlcs_only is set, " +
+		"only the failed htlc attempts of the matching payments are " +
+		"deleted.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "status",
+			Usage: "the status of the payments to delete, one of " +
+				"'failed', 'succeeded' or 'unknown'",
+			Value: "failed",
+		},
+		cli.Int64Flag{
+			Name: "created_before",
+			Usage: "if set, only payments created before this " +
+				"unix timestamp are deleted",
+		},
+		cli.BoolFlag{
+			Name: "failed_htlcs_only",
+			Usage: "if set, only the failed htlc attempts of the " +
+				"matching payments are deleted",
+		},
+	},
+	Action: actionDecorator(deletePayments),
+}
+
+func deletePayments(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var status lnrpc.Payment_PaymentStatus
+	switch ctx.String("status") {
+	case "failed":
+		status = lnrpc.Payment_FAILED
+	case "succeeded":
+		status = lnrpc.Payment_SUCCEEDED
+	case "unknown":
+		status = lnrpc.Payment_UNKNOWN
+	default:
+		return fmt.Errorf("invalid status: %v", ctx.String("status"))
+	}
+
+	resp, err := client.DeletePayments(ctxc, &lnrpc.DeletePaymentsRequest{
+		Status:          status,
+		CreatedBefore:   ctx.Int64("created_before"),
+		FailedHtlcsOnly: ctx.Bool("failed_htlcs_only"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var getChanInfoCommand = cli.Command{
 	Name:     "getchaninfo",
 	Category: "Graph",
@@ -2162,6 +2342,25 @@ var updateChannelPolicyCommand = cli.Command{
 				"to all forwarded HTLCs. If unset, the max HTLC " +
 				"is left unchanged.",
 		},
+		cli.Int64Flag{
+			Name: "inbound_base_fee_msat",
+			Usage: "the base fee in milli-satoshis that will be " +
+				"charged for each HTLC that enters the node " +
+				"through the channel, a negative value gives " +
+				"a discount. If neither inbound fee flag is " +
+				"set, the inbound fee is left unchanged, " +
+				"otherwise an unset one is applied as zero",
+		},
+		cli.Int64Flag{
+			Name: "inbound_fee_rate_ppm",
+			Usage: "the fee rate in parts per million that will " +
+				"be charged proportionally for each HTLC that " +
+				"enters the node through the channel, a " +
+				"negative value gives a discount. If neither " +
+				"inbound fee flag is set, the inbound fee is " +
+				"left unchanged, otherwise an unset one is " +
+				"applied as zero",
+		},
 		cli.StringFlag{
 			Name: "chan_point",
 			Usage: "The channel whose fee policy should be " +
@@ -2278,6 +2477,18 @@ func updateChannelPolicy(ctx *cli.Context) error {
 		MaxHtlcMsat:   ctx.Uint64("max_htlc_msat"),
 	}
 
+	if ctx.IsSet("inbound_base_fee_msat") ||
+		ctx.IsSet("inbound_fee_rate_ppm") {
+
+		req.InboundBaseFeeMsat = int32(
+			ctx.Int64("inbound_base_fee_msat"),
+		)
+		req.InboundFeeRatePpm = int32(
+			ctx.Int64("inbound_fee_rate_ppm"),
+		)
+		req.InboundFeeSpecified = true
+	}
+
 	if ctx.IsSet("min_htlc_msat") {
 		req.MinHtlcMsat = ctx.Uint64("min_htlc_msat")
 		req.MinHtlcMsatSpecified = true
@@ -2353,6 +2564,24 @@ func forwardingHistory(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
+	req, err := parseForwardingHistoryRequest(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := client.ForwardingHistory(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// parseForwardingHistoryRequest parses the time range and pagination of the
+// forwarding history commands from their flags and arguments.
+func parseForwardingHistoryRequest(
+	ctx *cli.Context) (*lnrpc.ForwardingHistoryRequest, error) {
+
 	var (
 		startTime, endTime     uint64
 		indexOffset, maxEvents uint32
@@ -2372,7 +2601,7 @@ func forwardingHistory(ctx *cli.Context) error {
 		startTime = uint64(now.Add(-time.Hour * 24).Unix())
 	}
 	if err != nil {
-		return fmt.Errorf("unable to decode start_time: %v", err)
+		return nil, fmt.Errorf("unable to decode start_time: %v", err)
 	}
 
 	switch {
@@ -2385,7 +2614,7 @@ func forwardingHistory(ctx *cli.Context) error {
 		endTime = uint64(now.Unix())
 	}
 	if err != nil {
-		return fmt.Errorf("unable to decode end_time: %v", err)
+		return nil, fmt.Errorf("unable to decode end_time: %v", err)
 	}
 
 	switch {
@@ -2394,7 +2623,8 @@ func forwardingHistory(ctx *cli.Context) error {
 	case args.Present():
 		i, err := strconv.ParseInt(args.First(), 10, 64)
 		if err != nil {
-			return fmt.Errorf("unable to decode index_offset: %v", err)
+			return nil, fmt.Errorf("unable to decode "+
+				"index_offset: %v", err)
 		}
 		indexOffset = uint32(i)
 		args = args.Tail()
@@ -2406,19 +2636,168 @@ func forwardingHistory(ctx *cli.Context) error {
 	case args.Present():
 		m, err := strconv.ParseInt(args.First(), 10, 64)
 		if err != nil {
-			return fmt.Errorf("unable to decode max_events: %v", err)
+			return nil, fmt.Errorf("unable to decode "+
+				"max_events: %v", err)
 		}
 		maxEvents = uint32(m)
-		args = args.Tail()
 	}
 
-	req := &lnrpc.ForwardingHistoryRequest{
+	return &lnrpc.ForwardingHistoryRequest{
 		StartTime:    startTime,
 		EndTime:      endTime,
 		IndexOffset:  indexOffset,
 		NumMaxEvents: maxEvents,
+	}, nil
+}
+
+var failedForwardingHistoryCommand = cli.Command{
+	Name:      "failedfwdinghistory",
+	Category:  "Payments",
+	Usage:     "Query the history of all failed forwards.",
+	ArgsUsage: "start_time [end_time] [index_offset] [max_events]",
+	Description: `
+	Query the HTLC switch's forwarding log for all forwards that were
+	failed back over a particular time range (--start_time and
+	--end_time). Each event reports whether the forward was failed by our
+	node or further down the route, and the failure reason if it was failed
+	by our node.
+
+	The time range and pagination work the same as for fwdinghistory.
+	Failed forwards are kept for the duration set by the
+	failed-fwd-retention option of lnd.
+	`,
+	Flags:  forwardingHistoryCommand.Flags,
+	Action: actionDecorator(failedForwardingHistory),
+}
+
+func failedForwardingHistory(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req, err := parseForwardingHistoryRequest(ctx)
+	if err != nil {
+		return err
 	}
-	resp, err := client.ForwardingHistory(ctxc, req)
+	resp, err := client.FailedForwardingHistory(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var forwardingAggregatesCommand = cli.Command{
+	Name:     "fwdingaggregates",
+	Category: "Payments",
+	Usage: "Query the number, volume and fees of forwarded HTLCs " +
+		"grouped by channel, peer and time.",
+	Description: `
+	Query the HTLC switch's forwarding log for all completed payment
+	circuits over a particular time range (--start_time and --end_time),
+	summed up per group. Each group reports the number of forwards, the
+	forwarded volume and the fees earned.
+
+	The start and end times accept the same formats as fwdinghistory. They
+	are rounded down to whole hours. If --start_time isn't provided, then
+	24 hours ago is used. If --end_time isn't provided, then the current
+	time is used.
+
+	The forwards can be grouped by time with --time_bucket, and by any
+	combination of the incoming and outgoing channel and peer with
+	--group_by, e.g. "--group_by=incoming_peer --group_by=outgoing_peer".
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "start_time",
+			Usage: "the starting time for the query " +
+				`as unix timestamp or relative e.g. "-1w"`,
+		},
+		cli.StringFlag{
+			Name: "end_time",
+			Usage: "the end time for the query " +
+				`as unix timestamp or relative e.g. "-1w"`,
+		},
+		cli.StringFlag{
+			Name: "time_bucket",
+			Usage: `the time buckets to group by: one of "none", ` +
+				`"hour" or "day"`,
+			Value: "none",
+		},
+		cli.StringSliceFlag{
+			Name: "group_by",
+			Usage: `the dimension to group by: one of ` +
+				`"incoming_channel", "outgoing_channel", ` +
+				`"incoming_peer" or "outgoing_peer", can be ` +
+				`specified multiple times`,
+		},
+	},
+	Action: actionDecorator(forwardingAggregates),
+}
+
+func forwardingAggregates(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		startTime, endTime uint64
+		err                error
+	)
+	now := time.Now()
+
+	if ctx.IsSet("start_time") {
+		startTime, err = parseTime(ctx.String("start_time"), now)
+		if err != nil {
+			return fmt.Errorf("unable to decode start_time: %v",
+				err)
+		}
+	} else {
+		startTime = uint64(now.Add(-time.Hour * 24).Unix())
+	}
+
+	if ctx.IsSet("end_time") {
+		endTime, err = parseTime(ctx.String("end_time"), now)
+		if err != nil {
+			return fmt.Errorf("unable to decode end_time: %v", err)
+		}
+	} else {
+		endTime = uint64(now.Unix())
+	}
+
+	var timeBucket lnrpc.ForwardingTimeBucket
+	switch ctx.String("time_bucket") {
+	case "none":
+		timeBucket = lnrpc.ForwardingTimeBucket_NO_TIME_BUCKET
+	case "hour":
+		timeBucket = lnrpc.ForwardingTimeBucket_HOUR
+	case "day":
+		timeBucket = lnrpc.ForwardingTimeBucket_DAY
+	default:
+		return fmt.Errorf("unknown time_bucket %v",
+			ctx.String("time_bucket"))
+	}
+
+	var groupBy []lnrpc.ForwardingGroupBy
+	for _, group := range ctx.StringSlice("group_by") {
+		value, ok := lnrpc.ForwardingGroupBy_value[strings.ToUpper(
+			group,
+		)]
+		if !ok {
+			return fmt.Errorf("unknown group_by %v", group)
+		}
+
+		groupBy = append(groupBy, lnrpc.ForwardingGroupBy(value))
+	}
+
+	req := &lnrpc.ForwardingAggregatesRequest{
+		StartTime:  startTime,
+		EndTime:    endTime,
+		TimeBucket: timeBucket,
+		GroupBy:    groupBy,
+	}
+	resp, err := client.ForwardingAggregates(ctxc, req)
 	if err != nil {
 		return err
 	}
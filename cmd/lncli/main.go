@@ -334,9 +334,12 @@ func main() {
 		connectCommand,
 		disconnectCommand,
 		openChannelCommand,
+		batchOpenChannelCommand,
 		closeChannelCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
+		spliceInCommand,
+		spliceOutCommand,
 		listPeersCommand,
 		walletBalanceCommand,
 		channelBalanceCommand,
@@ -348,10 +351,14 @@ func main() {
 		sendToRouteCommand,
 		addInvoiceCommand,
 		lookupInvoiceCommand,
+		deleteCanceledInvoiceCommand,
 		listInvoicesCommand,
 		listChannelsCommand,
 		closedChannelsCommand,
+		listAliasesCommand,
 		listPaymentsCommand,
+		deletePaymentCommand,
+		deletePaymentsCommand,
 		describeGraphCommand,
 		getNodeMetricsCommand,
 		getChanInfoCommand,
@@ -360,6 +367,9 @@ func main() {
 		getNetworkInfoCommand,
 		debugLevelCommand,
 		decodePayReqCommand,
+		decodeOfferCommand,
+		createOfferCommand,
+		payOfferCommand,
 		listChainTxnsCommand,
 		stopCommand,
 		signMessageCommand,
@@ -367,9 +377,12 @@ func main() {
 		feeReportCommand,
 		updateChannelPolicyCommand,
 		forwardingHistoryCommand,
+		forwardingAggregatesCommand,
+		failedForwardingHistoryCommand,
 		exportChanBackupCommand,
 		verifyChanBackupCommand,
 		restoreChanBackupCommand,
+		exportDBSnapshotCommand,
 		bakeMacaroonCommand,
 		listMacaroonIDsCommand,
 		deleteMacaroonIDCommand,
@@ -379,6 +392,10 @@ func main() {
 		versionCommand,
 		profileSubCommand,
 		getStateCommand,
+		sendCustomCommand,
+		subscribeCustomCommand,
+		sendOnionMessageCommand,
+		subscribeOnionMessagesCommand,
 	}
 
 	// Add any extra commands determined by build flags.
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// mcExportVersion is the current version of the mission control export file
+// format. It must be increased whenever the format changes in a way that
+// older versions of lncli can't read.
+const mcExportVersion = 1
+
+// mcExport is the file format that mission control state is exported to with
+// querymc --export and imported from with importmc --file.
+type mcExport struct {
+	// Version is the version of the file format.
+	Version uint32 `json:"version"`
+
+	// ExportTime is the unix timestamp in seconds at which the state was
+	// exported.
+	ExportTime int64 `json:"export_time"`
+
+	// Node is the hex encoded pubkey of the node that the state was
+	// exported from.
+	Node string `json:"node,omitempty"`
+
+	// Pairs are the node pair results of the exported state.
+	Pairs []mcExportPair `json:"pairs"`
+}
+
+// mcExportPair holds the last success and failure results of a node pair.
+// Timestamps are unix timestamps in seconds and are zero if the pair has no
+// result of that kind.
+type mcExportPair struct {
+	NodeFrom       string `json:"node_from"`
+	NodeTo         string `json:"node_to"`
+	FailTime       int64  `json:"fail_time,omitempty"`
+	FailAmtMsat    int64  `json:"fail_amt_msat,omitempty"`
+	SuccessTime    int64  `json:"success_time,omitempty"`
+	SuccessAmtMsat int64  `json:"success_amt_msat,omitempty"`
+}
+
+// newMcExport creates an export of the given mission control pairs.
+func newMcExport(node string, exportTime time.Time,
+	pairs []*routerrpc.PairHistory) *mcExport {
+
+	export := &mcExport{
+		Version:    mcExportVersion,
+		ExportTime: exportTime.Unix(),
+		Node:       node,
+		Pairs:      make([]mcExportPair, 0, len(pairs)),
+	}
+
+	for _, pair := range pairs {
+		history := pair.History
+		if history == nil {
+			continue
+		}
+
+		export.Pairs = append(export.Pairs, mcExportPair{
+			NodeFrom:       hex.EncodeToString(pair.NodeFrom),
+			NodeTo:         hex.EncodeToString(pair.NodeTo),
+			FailTime:       history.FailTime,
+			FailAmtMsat:    history.FailAmtMsat,
+			SuccessTime:    history.SuccessTime,
+			SuccessAmtMsat: history.SuccessAmtMsat,
+		})
+	}
+
+	return export
+}
+
+// rpcPairs validates the export and returns its pairs in the form that is
+// expected by the mission control import rpc.
+func (e *mcExport) rpcPairs() ([]*routerrpc.PairHistory, error) {
+	if e.Version == 0 || e.Version > mcExportVersion {
+		return nil, fmt.Errorf("unsupported mission control export "+
+			"version %v, max supported version is %v", e.Version,
+			mcExportVersion)
+	}
+
+	pairs := make([]*routerrpc.PairHistory, 0, len(e.Pairs))
+	for i, pair := range e.Pairs {
+		from, err := route.NewVertexFromStr(pair.NodeFrom)
+		if err != nil {
+			return nil, fmt.Errorf("pair %v: invalid source node: "+
+				"%v", i, err)
+		}
+
+		to, err := route.NewVertexFromStr(pair.NodeTo)
+		if err != nil {
+			return nil, fmt.Errorf("pair %v: invalid dest node: %v",
+				i, err)
+		}
+
+		pairs = append(pairs, &routerrpc.PairHistory{
+			NodeFrom: from[:],
+			NodeTo:   to[:],
+			History: &routerrpc.PairData{
+				FailTime:       pair.FailTime,
+				FailAmtMsat:    pair.FailAmtMsat,
+				SuccessTime:    pair.SuccessTime,
+				SuccessAmtMsat: pair.SuccessAmtMsat,
+			},
+		})
+	}
+
+	return pairs, nil
+}
+
+// writeMcExport writes a mission control export to the given file.
+func writeMcExport(path string, export *mcExport) error {
+	b, err := json.MarshalIndent(export, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// readMcExport reads a mission control export from the given file.
+func readMcExport(path string) (*mcExport, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var export mcExport
+	if err := json.Unmarshal(b, &export); err != nil {
+		return nil, fmt.Errorf("unable to parse mission control "+
+			"export: %v", err)
+	}
+
+	return &export, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testNodeFrom = []byte{
+		0x02, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+		0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+		0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+		0x01, 0x01, 0x01,
+	}
+	testNodeTo = []byte{
+		0x03, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+		0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+		0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+		0x02, 0x02, 0x02,
+	}
+)
+
+// TestMcExportRoundTrip asserts that mission control pairs survive being
+// written to and read from an export file.
+func TestMcExportRoundTrip(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "mcexport")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	pairs := []*routerrpc.PairHistory{{
+		NodeFrom: testNodeFrom,
+		NodeTo:   testNodeTo,
+		History: &routerrpc.PairData{
+			FailTime:       1000,
+			FailAmtMsat:    3000,
+			SuccessTime:    2000,
+			SuccessAmtMsat: 1000,
+		},
+	}}
+
+	exportTime := time.Unix(3000, 0)
+	export := newMcExport("node", exportTime, pairs)
+	require.Equal(t, uint32(mcExportVersion), export.Version)
+
+	path := filepath.Join(tempDir, "mc.json")
+	require.NoError(t, writeMcExport(path, export))
+
+	read, err := readMcExport(path)
+	require.NoError(t, err)
+	require.Equal(t, export, read)
+	require.Equal(t, exportTime.Unix(), read.ExportTime)
+
+	rpcPairs, err := read.rpcPairs()
+	require.NoError(t, err)
+	require.Equal(t, pairs, rpcPairs)
+}
+
+// TestMcExportVersion asserts that exports of unknown versions are rejected.
+func TestMcExportVersion(t *testing.T) {
+	export := newMcExport("", time.Now(), nil)
+
+	export.Version = 0
+	_, err := export.rpcPairs()
+	require.Error(t, err)
+
+	export.Version = mcExportVersion + 1
+	_, err = export.rpcPairs()
+	require.Error(t, err)
+}
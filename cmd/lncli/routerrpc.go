@@ -8,10 +8,13 @@ func routerCommands() []cli.Command {
 		queryMissionControlCommand,
 		importMissionControlCommand,
 		queryProbCommand,
+		probePaymentCommand,
 		resetMissionControlCommand,
 		buildRouteCommand,
 		getCfgCommand,
 		setCfgCommand,
 		updateChanStatusCommand,
+		getCircuitBreakerCommand,
+		setCircuitBreakerLimitsCommand,
 	}
 }
@@ -0,0 +1,278 @@
+// +build !js !wasm
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/btcsuite/btclog"
+	"github.com/jessevdk/go-flags"
+	"github.com/lightningnetwork/lnd/cluster"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/kvdb/etcd"
+	"github.com/lightningnetwork/lnd/lncfg"
+)
+
+const (
+	boltBackend = "bolt"
+	ldbBackend  = "leveldb"
+	etcdBackend = "etcd"
+
+	defaultDBFileName  = "channel.db"
+	defaultLockTimeout = 5 * time.Second
+
+	// clusterID is the ID we use in the leader election of an lnd cluster
+	// while migrating one of its etcd databases.
+	clusterID = "lnd-dbmigrate"
+)
+
+// dbConfig describes how to reach one of the two databases involved in a
+// migration.
+type dbConfig struct {
+	Backend string `long:"backend" description:"The database backend." choice:"bolt" choice:"leveldb" choice:"etcd"`
+
+	DBPath string `long:"dbpath" description:"The directory that contains the database (bolt and leveldb only)."`
+
+	DBFileName string `long:"dbfile" description:"The name of the database, for example channel.db, wallet.db or macaroons.db (bolt and leveldb only)."`
+
+	Etcd *etcd.Config `group:"etcd" namespace:"etcd" description:"Etcd settings (etcd only)."`
+}
+
+// config holds all command line options of lnd-dbmigrate.
+type config struct {
+	Source *dbConfig `group:"source" namespace:"source" description:"The database to copy the data from."`
+
+	Dest *dbConfig `group:"dest" namespace:"dest" description:"The database to copy the data to, it must not exist yet or be empty."`
+
+	LockTimeout time.Duration `long:"locktimeout" description:"How long to wait for the lock of a bolt database, or the leadership of an etcd cluster, before assuming lnd is still running."`
+
+	EtcdElectionPrefix string `long:"etcd-election-prefix" description:"The election key prefix of the lnd cluster that uses an etcd database. It must match the prefix lnd was configured with."`
+
+	TxMaxSize int64 `long:"txmaxsize" description:"The maximum number of key and value bytes to write to the destination in a single transaction."`
+
+	NoVerify bool `long:"noverify" description:"Skip comparing the checksums of all buckets after the copy."`
+
+	RestoreFile string `long:"restorefile" description:"Restore a database snapshot created with 'lncli exportdbsnapshot' into the destination database instead of copying the source database."`
+
+	Debug bool `long:"debug" description:"Log the name of every top-level bucket while walking the databases."`
+}
+
+func main() {
+	if err := run(); err != nil {
+		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
+			os.Exit(0)
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "[lnd-dbmigrate] %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg := &config{
+		Source: &dbConfig{
+			Backend:    boltBackend,
+			DBFileName: defaultDBFileName,
+			Etcd:       &etcd.Config{},
+		},
+		Dest: &dbConfig{
+			Backend:    ldbBackend,
+			DBFileName: defaultDBFileName,
+			Etcd:       &etcd.Config{},
+		},
+		LockTimeout:        defaultLockTimeout,
+		EtcdElectionPrefix: lncfg.DefaultEtcdElectionPrefix,
+		TxMaxSize:          kvdb.DefaultCopyTxMaxSize,
+	}
+	if _, err := flags.Parse(cfg); err != nil {
+		return err
+	}
+
+	if cfg.Debug {
+		backend := btclog.NewBackend(os.Stdout)
+		logger := backend.Logger("KVDB")
+		logger.SetLevel(btclog.LevelDebug)
+		kvdb.UseLogger(logger)
+	}
+
+	ctx := context.Background()
+
+	if cfg.RestoreFile != "" {
+		return restore(ctx, cfg)
+	}
+
+	// Etcd databases have no file lock, instead we take the leadership of
+	// the lnd cluster that uses them and fail if lnd still holds it.
+	for _, dbCfg := range []*dbConfig{cfg.Source, cfg.Dest} {
+		resign, err := lockEtcd(ctx, cfg, dbCfg)
+		if err != nil {
+			return err
+		}
+		defer resign()
+	}
+
+	// The source must exist already. Opening it acquires the same lock lnd
+	// holds while running, so we fail here if lnd still has it open.
+	src, err := openDB(ctx, cfg.Source, false, cfg.LockTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to open source database (make sure "+
+			"lnd is not running): %v", err)
+	}
+	defer src.Close()
+
+	dst, err := openDB(ctx, cfg.Dest, true, cfg.LockTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to open destination database: %v",
+			err)
+	}
+	defer dst.Close()
+
+	fmt.Printf("Copying %s database to %s database\n", cfg.Source.Backend,
+		cfg.Dest.Backend)
+
+	if err := kvdb.CopyBackend(src, dst, cfg.TxMaxSize); err != nil {
+		return fmt.Errorf("unable to copy database: %v", err)
+	}
+
+	if cfg.NoVerify {
+		fmt.Println("Copy complete, skipping verification")
+		return nil
+	}
+
+	fmt.Println("Copy complete, verifying checksums")
+	if err := kvdb.VerifyCopy(src, dst); err != nil {
+		return fmt.Errorf("verification failed: %v", err)
+	}
+
+	fmt.Println("Verification successful, migration complete")
+	return nil
+}
+
+// restore restores the snapshot file given in the config into the
+// destination database.
+func restore(ctx context.Context, cfg *config) error {
+	snapshot, err := os.Open(cfg.RestoreFile)
+	if err != nil {
+		return fmt.Errorf("unable to open snapshot: %v", err)
+	}
+	defer snapshot.Close()
+
+	resign, err := lockEtcd(ctx, cfg, cfg.Dest)
+	if err != nil {
+		return err
+	}
+	defer resign()
+
+	dst, err := openDB(ctx, cfg.Dest, true, cfg.LockTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to open destination database: %v",
+			err)
+	}
+	defer dst.Close()
+
+	fmt.Printf("Restoring %s into %s database\n", cfg.RestoreFile,
+		cfg.Dest.Backend)
+
+	err = kvdb.RestoreBackend(dst, snapshot, cfg.TxMaxSize)
+	if err != nil {
+		return fmt.Errorf("unable to restore snapshot: %v", err)
+	}
+
+	fmt.Println("Restore complete")
+	return nil
+}
+
+// lockEtcd becomes the leader of the lnd cluster that uses the given etcd
+// database, so that no lnd instance of the cluster can use the database while
+// we migrate it. It fails if we aren't elected within the lock timeout, which
+// is the case while lnd is still running. The returned function resigns from
+// the leader role. Databases that don't use etcd are locked when they are
+// opened instead, so nothing is done for them.
+func lockEtcd(ctx context.Context, cfg *config, dbCfg *dbConfig) (func(),
+	error) {
+
+	if dbCfg.Backend != etcdBackend {
+		return func() {}, nil
+	}
+
+	elector, err := cluster.MakeLeaderElector(
+		ctx, cluster.EtcdLeaderElector, clusterID,
+		cfg.EtcdElectionPrefix, dbCfg.Etcd,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create etcd leader elector: "+
+			"%v", err)
+	}
+
+	campaignCtx, cancel := context.WithTimeout(ctx, cfg.LockTimeout)
+	defer cancel()
+
+	if err := elector.Campaign(campaignCtx); err != nil {
+		return nil, fmt.Errorf("unable to become leader of the etcd "+
+			"cluster at %v (make sure lnd is not running): %v",
+			dbCfg.Etcd.Host, err)
+	}
+
+	return func() {
+		if err := elector.Resign(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "[lnd-dbmigrate] unable "+
+				"to resign from the etcd leader role: %v\n",
+				err)
+		}
+	}, nil
+}
+
+// openDB opens the database described by the given config. File based
+// databases are created if create is true and must already exist otherwise.
+func openDB(ctx context.Context, cfg *dbConfig, create bool,
+	lockTimeout time.Duration) (kvdb.Backend, error) {
+
+	if cfg.Backend == etcdBackend {
+		if cfg.Etcd.Host == "" {
+			return nil, fmt.Errorf("etcd host must be set")
+		}
+
+		return kvdb.Open(kvdb.EtcdBackendName, ctx, cfg.Etcd)
+	}
+
+	if cfg.DBPath == "" || cfg.DBFileName == "" {
+		return nil, fmt.Errorf("dbpath and dbfile must be set for the "+
+			"%s backend", cfg.Backend)
+	}
+
+	dbFilePath := filepath.Join(cfg.DBPath, cfg.DBFileName)
+	_, err := os.Stat(dbFilePath)
+	switch {
+	case err == nil && create:
+		return nil, fmt.Errorf("%v already exists", dbFilePath)
+
+	case os.IsNotExist(err) && !create:
+		return nil, fmt.Errorf("%v does not exist", dbFilePath)
+
+	case err != nil && !os.IsNotExist(err):
+		return nil, err
+	}
+
+	switch cfg.Backend {
+	case boltBackend:
+		return kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+			DBPath:     cfg.DBPath,
+			DBFileName: cfg.DBFileName,
+			DBTimeout:  lockTimeout,
+		})
+
+	case ldbBackend:
+		return kvdb.GetLdbBackend(&kvdb.LdbBackendConfig{
+			DBPath:     cfg.DBPath,
+			DBFileName: cfg.DBFileName,
+			LdbConfig:  *kvdb.DefaultLdbConfig(),
+		})
+
+	default:
+		return nil, fmt.Errorf("unknown backend %v", cfg.Backend)
+	}
+}
@@ -329,8 +329,14 @@ type Config struct {
 
 	RejectPush bool `long:"rejectpush" description:"If true, lnd will not accept channel opening requests with non-zero push amounts. This should prevent accidental pushes to merchant nodes."`
 
+	DualFundMaxContribution int64 `long:"dualfund-max-contribution" description:"The largest amount (in satoshis) that we contribute to a dual funded channel opened by a remote peer. We match the opener's contribution up to this amount, if our wallet can fund it. Requires protocol.dual-fund to be set."`
+
 	RejectHTLC bool `long:"rejecthtlc" description:"If true, lnd will not forward any HTLCs that are meant as onward payments. This option will still allow lnd to send HTLCs and receive HTLCs but lnd won't be used as a hop."`
 
+	RequireInterceptor bool `long:"requireinterceptor" description:"If true, HTLCs that are forwarded while no HTLC interceptor is connected are held until an interceptor connects, instead of being forwarded. HTLCs held by an interceptor that disconnects are handed to the next interceptor instead of being resumed."`
+
+	FailedFwdRetention time.Duration `long:"failed-fwd-retention" description:"The duration that failed forwards are kept in the forwarding log. Set to 0 to keep them forever. Valid time units are {s, m, h}."`
+
 	StaggerInitialReconnect bool `long:"stagger-initial-reconnect" description:"If true, will apply a randomized staggering between 0s and 30s when reconnecting to persistent peers on startup. The first 10 reconnections will be attempted instantly, regardless of the flag's value"`
 
 	MaxOutgoingCltvExpiry uint32 `long:"max-cltv-expiry" description:"The maximum number of blocks funds could be locked up for when forwarding payments."`
@@ -355,10 +361,14 @@ type Config struct {
 
 	GcCanceledInvoicesOnTheFly bool `long:"gc-canceled-invoices-on-the-fly" description:"If true, we'll delete newly canceled invoices on the fly."`
 
+	GcCanceledInvoicesAfter time.Duration `long:"gc-canceled-invoices-after" description:"If non-zero, we'll delete canceled invoices in the background once this duration has passed since they were canceled. Also applies to expired invoices, which are canceled automatically."`
+
 	Invoices *lncfg.Invoices `group:"invoices" namespace:"invoices"`
 
 	Routing *lncfg.Routing `group:"routing" namespace:"routing"`
 
+	Trampoline *lncfg.Trampoline `group:"trampoline" namespace:"trampoline"`
+
 	Gossip *lncfg.Gossip `group:"gossip" namespace:"gossip"`
 
 	Workers *lncfg.Workers `group:"workers" namespace:"workers"`
@@ -545,7 +555,9 @@ func DefaultConfig() Config {
 		Invoices: &lncfg.Invoices{
 			HoldExpiryDelta: lncfg.DefaultHoldInvoiceExpiryDelta,
 		},
+		Trampoline:              lncfg.DefaultTrampoline(),
 		MaxOutgoingCltvExpiry:   htlcswitch.DefaultMaxOutgoingCltvExpiry,
+		FailedFwdRetention:      htlcswitch.DefaultFailedFwdRetention,
 		MaxChannelFeeAllocation: htlcswitch.DefaultMaxLinkFeeAllocation,
 		MaxCommitFeeRateAnchors: lnwallet.DefaultAnchorsCommitMaxFeeRateSatPerVByte,
 		LogWriter:               build.NewRotatingLogWriter(),
@@ -800,6 +812,11 @@ func ValidateConfig(cfg Config, usageMessage string,
 		)
 	}
 
+	if cfg.DualFundMaxContribution < 0 {
+		return nil, fmt.Errorf("dualfund-max-contribution must be " +
+			"non-negative")
+	}
+
 	// Don't allow superflous --maxchansize greater than
 	// BOLT 02 soft-limit for non-wumbo channel
 	if !cfg.ProtocolOptions.Wumbo() && cfg.MaxChanSize > int64(MaxFundingAmount) {
@@ -810,6 +827,22 @@ func ValidateConfig(cfg Config, usageMessage string,
 		)
 	}
 
+	// Zero-conf channels are identified by an alias until their funding
+	// transaction confirms, so they require the scid alias feature.
+	if cfg.ProtocolOptions.ZeroConf() && !cfg.ProtocolOptions.ScidAlias() {
+		return nil, fmt.Errorf("protocol.zero-conf requires " +
+			"protocol.option-scid-alias to be set")
+	}
+
+	// Taproot channels are built on top of the zero-fee-htlc-tx anchors
+	// commitment, so they can't be used if anchors are disabled.
+	if cfg.ProtocolOptions.TaprootChans() &&
+		cfg.ProtocolOptions.NoAnchorCommitments() {
+
+		return nil, fmt.Errorf("protocol.simple-taproot-chans " +
+			"requires anchor commitments to be enabled")
+	}
+
 	// Ensure a valid max channel fee allocation was set.
 	if cfg.MaxChannelFeeAllocation <= 0 || cfg.MaxChannelFeeAllocation > 1 {
 		return nil, fmt.Errorf("invalid max channel fee allocation: "+
@@ -817,6 +850,11 @@ func ValidateConfig(cfg Config, usageMessage string,
 			cfg.MaxChannelFeeAllocation)
 	}
 
+	if cfg.FailedFwdRetention < 0 {
+		return nil, fmt.Errorf("invalid failed forward retention: "+
+			"%v, must not be negative", cfg.FailedFwdRetention)
+	}
+
 	if cfg.MaxCommitFeeRateAnchors < 1 {
 		return nil, fmt.Errorf("invalid max commit fee rate anchors: "+
 			"%v, must be at least 1 sat/vbyte",
@@ -1469,6 +1507,7 @@ func ValidateConfig(cfg Config, usageMessage string,
 		cfg.DB,
 		cfg.Cluster,
 		cfg.HealthChecks,
+		cfg.Trampoline,
 	)
 	if err != nil {
 		return nil, err
@@ -72,6 +72,16 @@ func newAnchorResolver(anchorSignDescriptor input.SignDescriptor,
 	return r
 }
 
+// anchorWitnessType returns the witness type used to sweep the anchor output
+// described by the sign descriptor.
+func anchorWitnessType(signDesc *input.SignDescriptor) input.WitnessType {
+	if signDesc.SignMethod.IsTaproot() {
+		return input.TaprootCommitmentAnchor
+	}
+
+	return input.CommitmentAnchor
+}
+
 // ResolverKey returns an identifier which should be globally unique for this
 // particular resolver within the chain the original contract resides within.
 func (c *anchorResolver) ResolverKey() []byte {
@@ -99,7 +109,7 @@ func (c *anchorResolver) Resolve() (ContractResolver, error) {
 
 	anchorInput := input.MakeBaseInput(
 		&c.anchor,
-		input.CommitmentAnchor,
+		anchorWitnessType(&c.anchorSignDescriptor),
 		&c.anchorSignDescriptor,
 		c.broadcastHeight,
 		nil,
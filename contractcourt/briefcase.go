@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 // ContractResolutions is a wrapper struct around the two forms of resolutions
@@ -1068,7 +1069,8 @@ func encodeSignDetails(w io.Writer, s *input.SignDetails) error {
 		return err
 	}
 
-	// Write the DER-encoded signature.
+	// Write the DER-encoded signature, or the 64-byte schnorr signature
+	// for taproot channels.
 	b := s.PeerSig.Serialize()
 	if err := wire.WriteVarBytes(w, 0, b); err != nil {
 		return err
@@ -1104,16 +1106,25 @@ func decodeSignDetails(r io.Reader) (*input.SignDetails, error) {
 	}
 	s.SigHashType = txscript.SigHashType(sigHash)
 
-	// Read DER-encoded signature.
+	// Read the signature, which is schnorr encoded if the sign descriptor
+	// spends a taproot output and DER-encoded otherwise.
 	rawSig, err := wire.ReadVarBytes(r, 0, 200, "signature")
 	if err != nil {
 		return nil, err
 	}
-	sig, err := btcec.ParseDERSignature(rawSig, btcec.S256())
-	if err != nil {
-		return nil, err
+	if s.SignDesc.SignMethod.IsTaproot() {
+		sig, err := schnorr.ParseSignature(rawSig)
+		if err != nil {
+			return nil, err
+		}
+		s.PeerSig = sig
+	} else {
+		sig, err := btcec.ParseDERSignature(rawSig, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+		s.PeerSig = sig
 	}
-	s.PeerSig = sig
 
 	return &s, nil
 }
@@ -1,6 +1,7 @@
 package contractcourt
 
 import (
+	"bytes"
 	"crypto/rand"
 	"io/ioutil"
 	"os"
@@ -20,6 +21,7 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lntest/channels"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 var (
@@ -143,6 +145,25 @@ var (
 		SigHashType: txscript.SigHashSingle,
 		PeerSig:     testSig,
 	}
+
+	testTaprootSig, _ = schnorr.ParseSignature(bytes.Repeat([]byte{1}, 64))
+
+	testTaprootSignDetails = &input.SignDetails{
+		SignDesc: input.SignDescriptor{
+			KeyDesc:       testSignDesc.KeyDesc,
+			SingleTweak:   testSignDesc.SingleTweak,
+			WitnessScript: testSignDesc.WitnessScript,
+			Output:        testSignDesc.Output,
+			HashType:      input.SigHashDefault,
+			SignMethod:    input.TaprootScriptSpendSignMethod,
+			ControlBlock: bytes.Repeat(
+				[]byte{2}, input.ControlBlockBaseSize,
+			),
+		},
+		SigHashType: txscript.SigHashSingle |
+			txscript.SigHashAnyOneCanPay,
+		PeerSig: testTaprootSig,
+	}
 )
 
 func makeTestDB() (kvdb.Backend, func(), error) {
@@ -639,6 +660,15 @@ func TestContractResolutionsStorage(t *testing.T) {
 					ClaimOutpoint:   randOutPoint(),
 					SweepSignDesc:   testSignDesc,
 				},
+				// Taproot resolution with SignDetails.
+				{
+					Expiry:          103,
+					SignedTimeoutTx: testTx,
+					SignDetails:     testTaprootSignDetails,
+					CsvDelay:        923923,
+					ClaimOutpoint:   randOutPoint(),
+					SweepSignDesc:   testSignDesc,
+				},
 			},
 		},
 		AnchorResolution: &lnwallet.AnchorResolution{
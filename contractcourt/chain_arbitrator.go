@@ -158,6 +158,13 @@ type ChainArbitratorConfig struct {
 	// will use to notify the ChannelNotifier about a newly closed channel.
 	NotifyClosedChannel func(wire.OutPoint)
 
+	// NotifySplicedChannel is a function closure that the ChainArbitrator
+	// will use to notify the rest of the daemon once a splice transaction
+	// of a channel confirmed and the channel moved to its new funding
+	// output.
+	NotifySplicedChannel func(oldChanPoint wire.OutPoint,
+		newChan *channeldb.OpenChannel)
+
 	// OnionProcessor is used to decode onion payloads for on-chain
 	// resolution.
 	OnionProcessor OnionProcessor
@@ -499,6 +506,7 @@ func (c *ChainArbitrator) Start() error {
 					)
 				},
 				extractStateNumHint: lnwallet.GetStateNumHint,
+				spliceConfirmed:     c.spliceContract,
 			},
 		)
 		if err != nil {
@@ -1093,6 +1101,7 @@ func (c *ChainArbitrator) WatchNewChannel(newChan *channeldb.OpenChannel) error
 				)
 			},
 			extractStateNumHint: lnwallet.GetStateNumHint,
+			spliceConfirmed:     c.spliceContract,
 		},
 	)
 	if err != nil {
@@ -1141,5 +1150,55 @@ func (c *ChainArbitrator) SubscribeChannelEvents(
 	return watcher.SubscribeChannelEvents(), nil
 }
 
+// spliceContract is called by the chain watcher of a channel once one of its
+// splice transactions confirmed and the channel was moved to the new funding
+// output. The arbitrator and chain watcher of the old funding output are
+// replaced by ones watching the new funding output.
+func (c *ChainArbitrator) spliceContract(oldChanPoint wire.OutPoint,
+	newChan *channeldb.OpenChannel) {
+
+	log.Infof("Replacing ChannelArbitrator of ChannelPoint(%v) with "+
+		"spliced ChannelPoint(%v)", oldChanPoint,
+		newChan.FundingOutpoint)
+
+	c.Lock()
+	chainArb := c.activeChannels[oldChanPoint]
+	delete(c.activeChannels, oldChanPoint)
+
+	chainWatcher := c.activeWatchers[oldChanPoint]
+	delete(c.activeWatchers, oldChanPoint)
+	c.Unlock()
+
+	if chainArb != nil {
+		if err := chainArb.Stop(); err != nil {
+			log.Warnf("unable to stop ChannelArbitrator(%v): %v",
+				oldChanPoint, err)
+		}
+
+		// The log of the old funding output is no longer needed, as
+		// the arbitrator of the new funding output uses a fresh one.
+		if err := chainArb.log.WipeHistory(); err != nil {
+			log.Warnf("unable to wipe log of "+
+				"ChannelArbitrator(%v): %v", oldChanPoint, err)
+		}
+	}
+	if chainWatcher != nil {
+		if err := chainWatcher.Stop(); err != nil {
+			log.Warnf("unable to stop ChainWatcher(%v): %v",
+				oldChanPoint, err)
+		}
+	}
+
+	if err := c.WatchNewChannel(newChan); err != nil {
+		log.Errorf("Unable to watch spliced ChannelPoint(%v): %v",
+			newChan.FundingOutpoint, err)
+		return
+	}
+
+	if c.cfg.NotifySplicedChannel != nil {
+		c.cfg.NotifySplicedChannel(oldChanPoint, newChan)
+	}
+}
+
 // TODO(roasbeef): arbitration reports
 //  * types: contested, waiting for success conf, etc
@@ -17,6 +17,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 const (
@@ -166,6 +167,13 @@ type chainWatcherConfig struct {
 	// obfuscater. This is used by the chain watcher to identify which
 	// state was broadcast and confirmed on-chain.
 	extractStateNumHint func(*wire.MsgTx, [lnwallet.StateHintSize]byte) uint64
+
+	// spliceConfirmed is called once a splice transaction of the channel
+	// confirmed and the channel was moved to the new funding output. The
+	// chain watcher exits afterwards, so the caller is expected to start
+	// watching the new funding output.
+	spliceConfirmed func(oldChanPoint wire.OutPoint,
+		newChan *channeldb.OpenChannel)
 }
 
 // chainWatcher is a system that's assigned to every active channel. The duty
@@ -253,15 +261,27 @@ func (c *chainWatcher) Start() error {
 
 	localKey := chanState.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed()
 	remoteKey := chanState.RemoteChanCfg.MultiSigKey.PubKey.SerializeCompressed()
-	multiSigScript, err := input.GenMultiSigScript(
-		localKey, remoteKey,
-	)
-	if err != nil {
-		return err
-	}
-	pkScript, err := input.WitnessScriptHash(multiSigScript)
-	if err != nil {
-		return err
+
+	var pkScript []byte
+	if chanState.ChanType.IsTaproot() {
+		tree, _, err := input.GenTaprootFundingPkScript(
+			localKey, remoteKey, int64(chanState.Capacity),
+		)
+		if err != nil {
+			return err
+		}
+		pkScript = tree.PkScript
+	} else {
+		multiSigScript, err := input.GenMultiSigScript(
+			localKey, remoteKey,
+		)
+		if err != nil {
+			return err
+		}
+		pkScript, err = input.WitnessScriptHash(multiSigScript)
+		if err != nil {
+			return err
+		}
 	}
 
 	spendNtfn, err := c.cfg.notifier.RegisterSpendNtfn(
@@ -326,6 +346,84 @@ func (c *chainWatcher) SubscribeChannelEvents() *ChainEventSubscription {
 	return sub
 }
 
+// handleSpliceSpend checks whether the passed spend of the funding output is
+// one of the persisted splice candidates of the channel. If so, it waits for
+// the splice transaction to confirm, moves the channel to the new funding
+// output and returns true. Otherwise false is returned.
+func (c *chainWatcher) handleSpliceSpend(
+	spend *chainntnfs.SpendDetail) (bool, error) {
+
+	chanState := c.cfg.chanState
+	candidates, err := lnwallet.FetchSpliceCandidates(chanState)
+	if err != nil {
+		return false, err
+	}
+
+	var splice *lnwallet.SpliceCandidate
+	for _, candidate := range candidates {
+		if candidate.FundingOutpoint.Hash == *spend.SpenderTxHash {
+			splice = candidate
+			break
+		}
+	}
+	if splice == nil {
+		return false, nil
+	}
+
+	oldChanPoint := chanState.FundingOutpoint
+
+	log.Infof("Funding output of ChannelPoint(%v) spent by splice "+
+		"transaction, waiting for %v to confirm", oldChanPoint,
+		splice.FundingOutpoint)
+
+	// The short channel ID of the channel changes to the location of the
+	// new funding output, so we'll need the confirmation details of the
+	// splice transaction.
+	confNtfn, err := c.cfg.notifier.RegisterConfirmationsNtfn(
+		spend.SpenderTxHash, splice.FundingPkScript, 1,
+		uint32(spend.SpendingHeight),
+	)
+	if err != nil {
+		return false, err
+	}
+	defer confNtfn.Cancel()
+
+	var confDetails *chainntnfs.TxConfirmation
+	select {
+	case details, ok := <-confNtfn.Confirmed:
+		if !ok {
+			return false, fmt.Errorf("confirmation notifier for "+
+				"splice %v exited", splice.FundingOutpoint)
+		}
+		confDetails = details
+
+	case <-c.quit:
+		return false, fmt.Errorf("chain watcher shutting down")
+	}
+
+	shortChanID := lnwire.ShortChannelID{
+		BlockHeight: confDetails.BlockHeight,
+		TxIndex:     confDetails.TxIndex,
+		TxPosition:  uint16(splice.FundingOutpoint.Index),
+	}
+	err = chanState.ApplySplice(splice.FundingOutpoint.Hash, shortChanID)
+	if err != nil {
+		return false, err
+	}
+
+	log.Infof("ChannelPoint(%v) moved to spliced funding output %v "+
+		"with ShortChanID %v", oldChanPoint, chanState.FundingOutpoint,
+		shortChanID)
+
+	// The callback stops this chain watcher, so it must not be called
+	// from within the close observer.
+	if c.cfg.spliceConfirmed != nil {
+		go c.cfg.spliceConfirmed(oldChanPoint, chanState)
+	}
+
+	return true, nil
+}
+
 // handleUnknownLocalState checks whether the passed spend _could_ be a local
 // state that for some reason is unknown to us. This could be a state published
 // by us before we lost state, which we will try to sweep. Or it could be one
@@ -372,18 +470,15 @@ func (c *chainWatcher) handleUnknownLocalState(
 	// Next, we'll derive our script that includes the revocation base for
 	// the remote party allowing them to claim this output before the CSV
 	// delay if we breach.
-	localScript, err := input.CommitScriptToSelf(
+	localScript, err := lnwallet.CommitScriptToSelf(
+		c.cfg.chanState.ChanType,
 		uint32(c.cfg.chanState.LocalChanCfg.CsvDelay),
 		commitKeyRing.ToLocalKey, commitKeyRing.RevocationKey,
 	)
 	if err != nil {
 		return false, err
 	}
-
-	localPkScript, err := input.WitnessScriptHash(localScript)
-	if err != nil {
-		return false, err
-	}
+	localPkScript := localScript.PkScript
 
 	// With all our scripts assembled, we'll examine the outputs of the
 	// commitment transaction to determine if this is a local force close
@@ -545,6 +640,20 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 			return
 		}
 
+		// If the funding output was spent by a splice transaction,
+		// the channel isn't closed, but moved to the new funding
+		// output.
+		spliced, err := c.handleSpliceSpend(commitSpend)
+		if err != nil {
+			log.Errorf("Unable to handle splice of "+
+				"ChannelPoint(%v): %v",
+				c.cfg.chanState.FundingOutpoint, err)
+			return
+		}
+		if spliced {
+			return
+		}
+
 		// Otherwise, the remote party might have broadcast a prior
 		// revoked state...!!!
 		commitTxBroadcast := commitSpend.SpendingTx
@@ -625,3 +625,174 @@ func TestChainWatcherLocalForceCloseDetect(t *testing.T) {
 		})
 	}
 }
+
+// TestChainWatcherSpliceSpend tests that the chain watcher doesn't treat a
+// spend of the funding output by a persisted splice transaction as a channel
+// close, and instead moves the channel to the new funding output once the
+// splice transaction confirms.
+func TestChainWatcherSpliceSpend(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// We'll create a splice transaction spending the funding output. Its
+	// input has a final sequence, so it would be mistaken for a
+	// cooperative close if the chain watcher didn't know about it.
+	_, fundingOut, err := input.GenFundingPkScript(
+		aliceChannel.LocalFundingKey.SerializeCompressed(),
+		aliceChannel.RemoteFundingKey.SerializeCompressed(),
+		int64(aliceChannel.Capacity)*2,
+	)
+	if err != nil {
+		t.Fatalf("unable to create funding output: %v", err)
+	}
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(wire.NewTxIn(aliceChannel.ChanPoint, nil, nil))
+	spliceTx.AddTxOut(fundingOut)
+
+	aliceSplice, err := lnwallet.NewSpliceCandidate(
+		spliceTx, aliceChannel.LocalFundingKey,
+		aliceChannel.RemoteFundingKey,
+	)
+	if err != nil {
+		t.Fatalf("unable to create splice candidate: %v", err)
+	}
+	bobSplice, err := lnwallet.NewSpliceCandidate(
+		spliceTx, bobChannel.LocalFundingKey,
+		bobChannel.RemoteFundingKey,
+	)
+	if err != nil {
+		t.Fatalf("unable to create splice candidate: %v", err)
+	}
+
+	// Alice splices in funds doubling the capacity of the channel. Both
+	// parties sign the commitments spending the new funding output, after
+	// which Alice persists the splice.
+	contribution := aliceSplice.Capacity - aliceChannel.Capacity
+	aliceSig, err := aliceChannel.SignSpliceCommitment(
+		aliceSplice, contribution, 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to sign splice commitment: %v", err)
+	}
+	bobSig, err := bobChannel.SignSpliceCommitment(
+		bobSplice, 0, contribution,
+	)
+	if err != nil {
+		t.Fatalf("unable to sign splice commitment: %v", err)
+	}
+	err = aliceChannel.ReceiveSpliceCommitSig(aliceSplice, bobSig)
+	if err != nil {
+		t.Fatalf("unable to receive splice commitment sig: %v", err)
+	}
+	err = bobChannel.ReceiveSpliceCommitSig(bobSplice, aliceSig)
+	if err != nil {
+		t.Fatalf("unable to receive splice commitment sig: %v", err)
+	}
+	if err := aliceChannel.AddPendingSplice(aliceSplice); err != nil {
+		t.Fatalf("unable to add splice: %v", err)
+	}
+
+	// The chain watcher only learns about the splice through the
+	// database, as would be the case after a restart.
+	chanState, err := aliceChannel.State().Db.FetchChannel(
+		*aliceChannel.ChanPoint,
+	)
+	if err != nil {
+		t.Fatalf("unable to fetch channel: %v", err)
+	}
+
+	type splicedChannel struct {
+		oldChanPoint wire.OutPoint
+		newChan      *channeldb.OpenChannel
+	}
+	spliced := make(chan splicedChannel, 1)
+
+	aliceNotifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	aliceChainWatcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           chanState,
+		notifier:            aliceNotifier,
+		signer:              aliceChannel.Signer,
+		extractStateNumHint: lnwallet.GetStateNumHint,
+		spliceConfirmed: func(oldChanPoint wire.OutPoint,
+			newChan *channeldb.OpenChannel) {
+
+			spliced <- splicedChannel{oldChanPoint, newChan}
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create chain watcher: %v", err)
+	}
+	if err := aliceChainWatcher.Start(); err != nil {
+		t.Fatalf("unable to start chain watcher: %v", err)
+	}
+	defer aliceChainWatcher.Stop()
+
+	chanEvents := aliceChainWatcher.SubscribeChannelEvents()
+
+	spliceTxHash := spliceTx.TxHash()
+	aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash:  &spliceTxHash,
+		SpendingTx:     spliceTx,
+		SpendingHeight: 100,
+	}
+
+	select {
+	case <-chanEvents.CooperativeClosure:
+		t.Fatalf("splice detected as cooperative close")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// Once the splice transaction confirms, the channel is moved to the
+	// new funding output.
+	aliceNotifier.ConfChan <- &chainntnfs.TxConfirmation{
+		BlockHeight: 101,
+		TxIndex:     3,
+	}
+
+	var event splicedChannel
+	select {
+	case event = <-spliced:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("splice not confirmed")
+	}
+
+	if event.oldChanPoint != *aliceChannel.ChanPoint {
+		t.Fatalf("expected old chan point %v, got %v",
+			aliceChannel.ChanPoint, event.oldChanPoint)
+	}
+	if event.newChan.FundingOutpoint != aliceSplice.FundingOutpoint {
+		t.Fatalf("expected funding outpoint %v, got %v",
+			aliceSplice.FundingOutpoint,
+			event.newChan.FundingOutpoint)
+	}
+	expectedScid := lnwire.ShortChannelID{
+		BlockHeight: 101,
+		TxIndex:     3,
+		TxPosition:  uint16(aliceSplice.FundingOutpoint.Index),
+	}
+	if event.newChan.ShortChannelID != expectedScid {
+		t.Fatalf("expected short chan id %v, got %v", expectedScid,
+			event.newChan.ShortChannelID)
+	}
+
+	// The spliced channel is persisted under its new funding outpoint.
+	newChan, err := chanState.Db.FetchChannel(aliceSplice.FundingOutpoint)
+	if err != nil {
+		t.Fatalf("unable to fetch spliced channel: %v", err)
+	}
+	if newChan.Capacity != aliceSplice.Capacity {
+		t.Fatalf("expected capacity %v, got %v", aliceSplice.Capacity,
+			newChan.Capacity)
+	}
+}
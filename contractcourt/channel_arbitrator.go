@@ -1101,7 +1101,7 @@ func (c *ChannelArbitrator) sweepAnchors(anchors []*lnwallet.AnchorResolution,
 		// Prepare anchor output for sweeping.
 		anchorInput := input.MakeBaseInput(
 			&anchor.CommitAnchor,
-			input.CommitmentAnchor,
+			anchorWitnessType(&anchor.AnchorSignDescriptor),
 			&anchor.AnchorSignDescriptor,
 			heightHint,
 			&input.TxInfo{
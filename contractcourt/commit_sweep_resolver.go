@@ -207,7 +207,15 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 	// The output is on our local commitment if the script starts with
 	// OP_IF for the revocation clause. On the remote commitment it will
 	// either be a regular P2WKH or a simple sig spend with a CSV delay.
-	isLocalCommitTx := c.commitResolution.SelfOutputSignDesc.WitnessScript[0] == txscript.OP_IF
+	// Taproot outputs are spent over a single leaf in both cases, but only
+	// the delay key on our local commitment is tweaked, as taproot
+	// channels are always tweakless.
+	signDesc := &c.commitResolution.SelfOutputSignDesc
+	isTaproot := signDesc.SignMethod.IsTaproot()
+	isLocalCommitTx := signDesc.WitnessScript[0] == txscript.OP_IF
+	if isTaproot {
+		isLocalCommitTx = signDesc.SingleTweak != nil
+	}
 	isDelayedOutput := c.commitResolution.MaturityDelay != 0
 
 	c.log.Debugf("isDelayedOutput=%v, isLocalCommitTx=%v", isDelayedOutput,
@@ -222,6 +230,15 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 	var witnessType input.WitnessType
 	switch {
 
+	// Delayed taproot output to us on our local commitment.
+	case isTaproot && isLocalCommitTx:
+		witnessType = input.TaprootCommitmentTimeLock
+
+	// Taproot output to us on the remote commitment, which is always
+	// delayed by one block.
+	case isTaproot:
+		witnessType = input.TaprootCommitmentToRemoteConfirmed
+
 	// Delayed output to us on our local commitment.
 	case isLocalCommitTx:
 		witnessType = input.CommitmentTimeLock
@@ -163,10 +163,22 @@ func (h *htlcIncomingContestResolver) Resolve() (ContractResolver, error) {
 			//
 			//  * <sender sig> <recvr sig> <preimage> <witness script>
 			//
+			// For taproot channels the stack doesn't start with a
+			// zero, so the preimage is the 3rd element:
+			//
+			//  * <sender sig> <recvr sig> <preimage> <leaf> <ctrl>
+			//
 			// We'll populate it within the witness, as since this
 			// was a "contest" resolver, we didn't yet know of the
 			// preimage.
-			h.htlcResolution.SignedSuccessTx.TxIn[0].Witness[3] = preimage[:]
+			preimageIndex := 3
+			signDesc := &h.htlcResolution.SweepSignDesc
+			if signDesc.SignMethod.IsTaproot() {
+				preimageIndex = 2
+			}
+
+			successTx := h.htlcResolution.SignedSuccessTx
+			successTx.TxIn[0].Witness[preimageIndex] = preimage[:]
 		}
 
 		return nil
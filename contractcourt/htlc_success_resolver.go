@@ -327,8 +327,13 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 	log.Infof("%T(%x): CSV lock expired, offering second-layer "+
 		"output to sweeper: %v", h, h.htlc.RHash[:], op)
 
+	witnessType := input.HtlcAcceptedSuccessSecondLevel
+	if h.htlcResolution.SweepSignDesc.SignMethod.IsTaproot() {
+		witnessType = input.TaprootHtlcAcceptedSuccessSecondLevel
+	}
+
 	inp := input.NewCsvInput(
-		op, input.HtlcAcceptedSuccessSecondLevel,
+		op, witnessType,
 		&h.htlcResolution.SweepSignDesc, h.broadcastHeight,
 		h.htlcResolution.CsvDelay,
 	)
@@ -107,12 +107,24 @@ const (
 	// sweep it on chain.
 	remotePreimageIndex = 3
 
+	// taprootRemotePreimageIndex is the index within the witness on the
+	// remote taproot commitment transaction that will hold the pre-image
+	// if they go to sweep it on chain. Taproot script spends carry no
+	// leading zero, but end with the leaf script and control block.
+	taprootRemotePreimageIndex = 2
+
 	// localPreimageIndex is the index within the witness on the local
 	// commitment transaction for an outgoing HTLC that will hold the
 	// pre-image if the remote party sweeps it.
 	localPreimageIndex = 1
 )
 
+// isTaproot returns true if the HTLC is an output of a taproot commitment
+// transaction.
+func (h *htlcTimeoutResolver) isTaproot() bool {
+	return h.htlcResolution.SweepSignDesc.SignMethod.IsTaproot()
+}
+
 // claimCleanUp is a helper method that's called once the HTLC output is spent
 // by the remote party. It'll extract the preimage, add it to the global cache,
 // and finally send the appropriate clean up message.
@@ -136,12 +148,22 @@ func (h *htlcTimeoutResolver) claimCleanUp(
 		// them looks like:
 		//
 		//  * <0> <sender sig> <recvr sig> <preimage> <witness script>
-		preimageBytes = spendingInput.Witness[remotePreimageIndex]
+		//
+		// Or for taproot channels:
+		//
+		//  * <sender sig> <recvr sig> <preimage> <leaf> <control block>
+		preimageIndex := remotePreimageIndex
+		if h.isTaproot() {
+			preimageIndex = taprootRemotePreimageIndex
+		}
+		preimageBytes = spendingInput.Witness[preimageIndex]
 	} else {
 		// Otherwise, they'll be spending directly from our commitment
 		// output. In which case the witness stack looks like:
 		//
 		//  * <sig> <preimage> <witness script>
+		//
+		// Taproot channels append the control block to this stack.
 		preimageBytes = spendingInput.Witness[localPreimageIndex]
 	}
 
@@ -210,6 +232,20 @@ func (h *htlcTimeoutResolver) chainDetailsToWatch() (*wire.OutPoint, []byte, err
 	// (the last element of the witness stack) to re-construct the pkScript
 	// we need to watch.
 	outPointToWatch := h.htlcResolution.SignedTimeoutTx.TxIn[0].PreviousOutPoint
+
+	// The witness of a taproot spend ends with the control block rather
+	// than the witness script, so we take the output from the sign details
+	// of the timeout transaction instead.
+	if h.isTaproot() {
+		if h.htlcResolution.SignDetails == nil {
+			return nil, nil, fmt.Errorf("taproot htlc %v has no "+
+				"sign details", outPointToWatch)
+		}
+
+		signDesc := &h.htlcResolution.SignDetails.SignDesc
+		return &outPointToWatch, signDesc.Output.PkScript, nil
+	}
+
 	witness := h.htlcResolution.SignedTimeoutTx.TxIn[0].Witness
 	scriptToWatch, err := input.WitnessScriptHash(witness[len(witness)-1])
 	if err != nil {
@@ -221,7 +257,9 @@ func (h *htlcTimeoutResolver) chainDetailsToWatch() (*wire.OutPoint, []byte, err
 
 // isSuccessSpend returns true if the passed spend on the specified commitment
 // is a success spend that reveals the pre-image or not.
-func isSuccessSpend(spend *chainntnfs.SpendDetail, localCommit bool) bool {
+func isSuccessSpend(spend *chainntnfs.SpendDetail, localCommit,
+	taproot bool) bool {
+
 	// Based on the spending input index and transaction, obtain the
 	// witness that tells us what type of spend this is.
 	spenderIndex := spend.SpenderInputIndex
@@ -239,9 +277,19 @@ func isSuccessSpend(spend *chainntnfs.SpendDetail, localCommit bool) bool {
 	// witness script), and the 3rd element is the size of the pre-image,
 	// then this is a remote spend. If not, then we swept it ourselves, or
 	// revoked their output.
+	//
+	// Taproot channels drop the leading zero and revoke over the key path
+	// with a single signature, but add the control block, so a remote
+	// success spend has the same number of elements with the pre-image
+	// as the 3rd (index 2) element.
 	if !localCommit {
+		preimageIndex := remotePreimageIndex
+		if taproot {
+			preimageIndex = taprootRemotePreimageIndex
+		}
+
 		return len(spendingWitness) == expectedRemoteWitnessSuccessSize &&
-			len(spendingWitness[remotePreimageIndex]) == lntypes.HashSize
+			len(spendingWitness[preimageIndex]) == lntypes.HashSize
 	}
 
 	// Otherwise, for our commitment, the only possible spends for an
@@ -252,8 +300,11 @@ func isSuccessSpend(spend *chainntnfs.SpendDetail, localCommit bool) bool {
 	//  REVOK: <revoke sig> <revoke key>
 	//
 	// So the only success case has the pre-image as the 2nd (index 1)
-	// element in the witness.
-	return len(spendingWitness[localPreimageIndex]) == lntypes.HashSize
+	// element in the witness. A revoked taproot output is spent over the
+	// key path with a single signature, so we check the witness length
+	// first.
+	return len(spendingWitness) > localPreimageIndex &&
+		len(spendingWitness[localPreimageIndex]) == lntypes.HashSize
 }
 
 // Resolve kicks off full resolution of an outgoing HTLC output. If it's our
@@ -279,7 +330,8 @@ func (h *htlcTimeoutResolver) Resolve() (ContractResolver, error) {
 	// If the spend reveals the pre-image, then we'll enter the clean up
 	// workflow to pass the pre-image back to the incoming link, add it to
 	// the witness cache, and exit.
-	if isSuccessSpend(commitSpend, h.htlcResolution.SignedTimeoutTx != nil) {
+	localCommit := h.htlcResolution.SignedTimeoutTx != nil
+	if isSuccessSpend(commitSpend, localCommit, h.isTaproot()) {
 		log.Infof("%T(%v): HTLC has been swept with pre-image by "+
 			"remote party during timeout flow! Adding pre-image to "+
 			"witness cache", h.htlcResolution.ClaimOutpoint)
@@ -463,8 +515,13 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 		log.Infof("%T(%x): CSV lock expired, offering second-layer "+
 			"output to sweeper: %v", h, h.htlc.RHash[:], op)
 
+		witnessType := input.HtlcOfferedTimeoutSecondLevel
+		if h.htlcResolution.SweepSignDesc.SignMethod.IsTaproot() {
+			witnessType = input.TaprootHtlcOfferedTimeoutSecondLevel
+		}
+
 		inp := input.NewCsvInput(
-			op, input.HtlcOfferedTimeoutSecondLevel,
+			op, witnessType,
 			&h.htlcResolution.SweepSignDesc,
 			h.broadcastHeight,
 			h.htlcResolution.CsvDelay,
@@ -56,6 +56,68 @@ func (m *mockWitnessBeacon) AddPreimages(preimages ...lntypes.Preimage) error {
 	return nil
 }
 
+// TestIsSuccessSpendTaproot tests that the pre-image is found in the witness
+// of the spends of taproot HTLC outputs, and that neither our timeout spends
+// nor revocation key path spends are mistaken for a success spend.
+func TestIsSuccessSpendTaproot(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sig      = bytes.Repeat([]byte{1}, 64)
+		preimage = bytes.Repeat([]byte{2}, lntypes.HashSize)
+		leaf     = bytes.Repeat([]byte{3}, 40)
+		ctrl     = bytes.Repeat([]byte{4}, input.ControlBlockBaseSize)
+	)
+
+	testCases := []struct {
+		name        string
+		witness     wire.TxWitness
+		localCommit bool
+		success     bool
+	}{
+		{
+			name: "remote commit second level success",
+			witness: wire.TxWitness{
+				sig, sig, preimage, leaf, ctrl,
+			},
+			success: true,
+		},
+		{
+			name:    "remote commit timeout",
+			witness: wire.TxWitness{sig, leaf, ctrl},
+		},
+		{
+			name:    "remote commit revoke",
+			witness: wire.TxWitness{sig},
+		},
+		{
+			name:        "local commit redeem",
+			witness:     wire.TxWitness{sig, preimage, leaf, ctrl},
+			localCommit: true,
+			success:     true,
+		},
+		{
+			name:        "local commit second level timeout",
+			witness:     wire.TxWitness{sig, sig, leaf, ctrl},
+			localCommit: true,
+		},
+		{
+			name:        "local commit revoke",
+			witness:     wire.TxWitness{sig},
+			localCommit: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		spendTx := wire.NewMsgTx(2)
+		spendTx.AddTxIn(&wire.TxIn{Witness: testCase.witness})
+		spend := &chainntnfs.SpendDetail{SpendingTx: spendTx}
+
+		success := isSuccessSpend(spend, testCase.localCommit, true)
+		require.Equal(t, testCase.success, success, testCase.name)
+	}
+}
+
 // TestHtlcTimeoutResolver tests that the timeout resolver properly handles all
 // variations of possible local+remote spends.
 func TestHtlcTimeoutResolver(t *testing.T) {
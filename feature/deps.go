@@ -61,6 +61,22 @@ var deps = depDesc{
 	lnwire.AMPOptional: {
 		lnwire.PaymentAddrOptional: {},
 	},
+	lnwire.ZeroConfOptional: {
+		lnwire.ScidAliasOptional: {},
+	},
+	lnwire.RouteBlindingOptional: {
+		lnwire.TLVOnionPayloadOptional: {},
+	},
+	lnwire.OnionMessagesOptional: {
+		lnwire.TLVOnionPayloadOptional: {},
+	},
+	lnwire.TrampolineRoutingOptional: {
+		lnwire.PaymentAddrOptional: {},
+	},
+	lnwire.SimpleTaprootChansOptional: {
+		lnwire.AnchorsZeroFeeHtlcTxOptional: {},
+		lnwire.ExplicitChannelTypeOptional:  {},
+	},
 }
 
 // ValidateDeps asserts that a feature vector sets all features and their
@@ -23,6 +23,29 @@ type Config struct {
 
 	// NoWumbo unsets any bits signalling support for wumbo channels.
 	NoWumbo bool
+
+	// NoScidAlias unsets any bits signalling support for short channel ID
+	// aliases.
+	NoScidAlias bool
+
+	// NoZeroConf unsets any bits signalling support for zero-conf
+	// channels.
+	NoZeroConf bool
+
+	// NoDualFund unsets any bits signalling support for dual funded
+	// channels.
+	NoDualFund bool
+
+	// NoSplice unsets any bits signalling support for splicing channels.
+	NoSplice bool
+
+	// NoTaprootChans unsets any bits signalling support for simple
+	// taproot channels.
+	NoTaprootChans bool
+
+	// NoTrampoline unsets any bits signalling support for trampoline
+	// payments.
+	NoTrampoline bool
 }
 
 // Manager is responsible for generating feature vectors for different requested
@@ -79,6 +102,8 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.MPPRequired)
 			raw.Unset(lnwire.AMPOptional)
 			raw.Unset(lnwire.AMPRequired)
+			raw.Unset(lnwire.TrampolineRoutingOptional)
+			raw.Unset(lnwire.TrampolineRoutingRequired)
 		}
 		if cfg.NoStaticRemoteKey {
 			raw.Unset(lnwire.StaticRemoteKeyOptional)
@@ -92,6 +117,30 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.WumboChannelsOptional)
 			raw.Unset(lnwire.WumboChannelsRequired)
 		}
+		if cfg.NoScidAlias {
+			raw.Unset(lnwire.ScidAliasOptional)
+			raw.Unset(lnwire.ScidAliasRequired)
+		}
+		if cfg.NoZeroConf {
+			raw.Unset(lnwire.ZeroConfOptional)
+			raw.Unset(lnwire.ZeroConfRequired)
+		}
+		if cfg.NoDualFund {
+			raw.Unset(lnwire.DualFundOptional)
+			raw.Unset(lnwire.DualFundRequired)
+		}
+		if cfg.NoSplice {
+			raw.Unset(lnwire.SpliceOptional)
+			raw.Unset(lnwire.SpliceRequired)
+		}
+		if cfg.NoTaprootChans {
+			raw.Unset(lnwire.SimpleTaprootChansOptional)
+			raw.Unset(lnwire.SimpleTaprootChansRequired)
+		}
+		if cfg.NoTrampoline {
+			raw.Unset(lnwire.TrampolineRoutingOptional)
+			raw.Unset(lnwire.TrampolineRoutingRequired)
+		}
 
 		// Ensure that all of our feature sets properly set any
 		// dependent features.
@@ -0,0 +1,519 @@
+package funding
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/psbt"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+)
+
+var (
+	// errShuttingDown is the error that is returned if a signal on the
+	// quit channel is received which means the whole server is shutting
+	// down.
+	errShuttingDown = errors.New("shutting down")
+
+	// emptyChannelID is a channel ID that consists of all zeros.
+	emptyChannelID = [32]byte{}
+)
+
+// batchChannel is a struct that keeps track of a single channel's state within
+// the batch funding process.
+type batchChannel struct {
+	fundingReq    *InitFundingMsg
+	pendingChanID [32]byte
+	updateChan    chan *lnrpc.OpenStatusUpdate
+	errChan       chan error
+	fundingAddr   string
+	chanPoint     *wire.OutPoint
+	isPending     bool
+}
+
+// processPsbtUpdate processes the first channel update message that is sent
+// once the initial part of the negotiation has completed and the funding
+// output (and therefore address) is known.
+func (c *batchChannel) processPsbtUpdate(u *lnrpc.OpenStatusUpdate) error {
+	psbtUpdate := u.GetPsbtFund()
+	if psbtUpdate == nil {
+		return fmt.Errorf("got unexpected channel update %v", u.Update)
+	}
+
+	if psbtUpdate.FundingAmount != int64(c.fundingReq.LocalFundingAmt) {
+		return fmt.Errorf("got unexpected funding amount %d, wanted "+
+			"%d", psbtUpdate.FundingAmount,
+			c.fundingReq.LocalFundingAmt)
+	}
+
+	c.fundingAddr = psbtUpdate.FundingAddress
+
+	return nil
+}
+
+// processPendingUpdate is the second channel update message that is sent once
+// the negotiation with the peer has completed and the channel is now pending.
+func (c *batchChannel) processPendingUpdate(u *lnrpc.OpenStatusUpdate) error {
+	pendingUpd := u.GetChanPending()
+	if pendingUpd == nil {
+		return fmt.Errorf("got unexpected channel update %v", u.Update)
+	}
+
+	hash, err := chainhash.NewHash(pendingUpd.Txid)
+	if err != nil {
+		return fmt.Errorf("could not parse outpoint TX hash: %v", err)
+	}
+
+	c.chanPoint = &wire.OutPoint{
+		Index: pendingUpd.OutputIndex,
+		Hash:  *hash,
+	}
+	c.isPending = true
+
+	return nil
+}
+
+// RequestParser is a function that parses an incoming RPC request into the
+// lnd internal channel open request format.
+type RequestParser func(*lnrpc.OpenChannelRequest) (*InitFundingMsg, error)
+
+// ChannelOpener is a function that kicks off the initial channel open
+// negotiation with the peer.
+type ChannelOpener func(*InitFundingMsg) (chan *lnrpc.OpenStatusUpdate,
+	chan error)
+
+// ChannelAbandoner is a function that can abandon a channel in the local
+// database, graph and arbitrator state.
+type ChannelAbandoner func(*wire.OutPoint) error
+
+// WalletKitServer is a restricted interface of the wallet kit RPC server that
+// is needed by the batch funder.
+type WalletKitServer interface {
+	// FundPsbt creates a fully populated PSBT that contains enough inputs
+	// to fund the outputs specified in the template.
+	FundPsbt(context.Context,
+		*walletrpc.FundPsbtRequest) (*walletrpc.FundPsbtResponse, error)
+
+	// FinalizePsbt expects a partial transaction with all inputs and
+	// outputs fully declared and tries to sign all inputs that belong to
+	// the wallet.
+	FinalizePsbt(context.Context,
+		*walletrpc.FinalizePsbtRequest) (*walletrpc.FinalizePsbtResponse,
+		error)
+
+	// ReleaseOutput unlocks an output, allowing it to be available for
+	// coin selection if it remains unspent.
+	ReleaseOutput(context.Context,
+		*walletrpc.ReleaseOutputRequest) (*walletrpc.ReleaseOutputResponse,
+		error)
+}
+
+// Wallet is a local interface that describes the functionality of the
+// lnwallet.LightningWallet that is needed by the batch funder.
+type Wallet interface {
+	// PsbtFundingVerify looks up a previously registered funding intent
+	// by its pending channel ID and tries to advance the state machine by
+	// verifying the passed PSBT.
+	PsbtFundingVerify([32]byte, *psbt.Packet) error
+
+	// PsbtFundingFinalize looks up a previously registered funding intent
+	// by its pending channel ID and tries to advance the state machine by
+	// finalizing the passed PSBT.
+	PsbtFundingFinalize([32]byte, *psbt.Packet, *wire.MsgTx) error
+
+	// PublishTransaction performs cursory validation (dust checks, etc),
+	// then finally broadcasts the passed transaction to the Bitcoin
+	// network.
+	PublishTransaction(*wire.MsgTx, string) error
+
+	// CancelFundingIntent allows a caller to cancel a previously
+	// registered funding intent. If no intent was found, then an error
+	// will be returned.
+	CancelFundingIntent([32]byte) error
+}
+
+// BatchConfig is the configuration for executing a single batch transaction
+// for opening multiple channels atomically.
+type BatchConfig struct {
+	// RequestParser is the function that parses an incoming RPC request
+	// into the lnd internal channel open request format.
+	RequestParser RequestParser
+
+	// ChannelOpener is the function that kicks off the initial channel
+	// open negotiation with the peer.
+	ChannelOpener ChannelOpener
+
+	// ChannelAbandoner is the function that can abandon a channel in the
+	// local database, graph and arbitrator state.
+	ChannelAbandoner ChannelAbandoner
+
+	// WalletKitServer is an instance of the wallet kit sub RPC server that
+	// implements the PSBT funding and signing methods.
+	WalletKitServer WalletKitServer
+
+	// Wallet is an instance of the internal lightning wallet.
+	Wallet Wallet
+
+	// NetParams contains the current bitcoin network parameters.
+	NetParams *chaincfg.Params
+
+	// Quit is the channel that is selected on to recognize if the main
+	// server is shutting down.
+	Quit chan struct{}
+}
+
+// Batcher is a type that can be used to perform an atomic funding of multiple
+// channels within a single on-chain transaction.
+type Batcher struct {
+	cfg *BatchConfig
+
+	channels    []*batchChannel
+	lockedUTXOs []*walletrpc.UtxoLease
+
+	didPublish bool
+}
+
+// NewBatcher returns a new batch channel funding helper.
+func NewBatcher(cfg *BatchConfig) *Batcher {
+	return &Batcher{
+		cfg: cfg,
+	}
+}
+
+// BatchFund starts the atomic batch channel funding process.
+//
+// NOTE: This method should only be called once per instance.
+func (b *Batcher) BatchFund(ctx context.Context,
+	req *lnrpc.BatchOpenChannelRequest) ([]*lnrpc.PendingUpdate, error) {
+
+	label, err := labels.ValidateAPI(req.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse and validate each individual channel.
+	b.channels = make([]*batchChannel, 0, len(req.Channels))
+	for idx, rpcChannel := range req.Channels {
+		// If the user specifies a channel ID, it must be exactly 32
+		// bytes long.
+		if len(rpcChannel.PendingChanId) > 0 &&
+			len(rpcChannel.PendingChanId) != 32 {
+
+			return nil, fmt.Errorf("invalid temp chan ID %x",
+				rpcChannel.PendingChanId)
+		}
+
+		var pendingChanID [32]byte
+		if len(rpcChannel.PendingChanId) == 32 {
+			copy(pendingChanID[:], rpcChannel.PendingChanId)
+
+			// Don't allow the user to be clever by just setting an
+			// all zero channel ID, we need a "real" value here.
+			if pendingChanID == emptyChannelID {
+				return nil, fmt.Errorf("invalid empty temp " +
+					"chan ID")
+			}
+		} else if _, err := rand.Read(pendingChanID[:]); err != nil {
+			return nil, fmt.Errorf("error making temp chan ID: %v",
+				err)
+		}
+
+		fundingReq, err := b.cfg.RequestParser(&lnrpc.OpenChannelRequest{
+			NodePubkey:         rpcChannel.NodePubkey,
+			LocalFundingAmount: rpcChannel.LocalFundingAmount,
+			PushSat:            rpcChannel.PushSat,
+			Private:            rpcChannel.Private,
+			MinHtlcMsat:        rpcChannel.MinHtlcMsat,
+			RemoteCsvDelay:     rpcChannel.RemoteCsvDelay,
+			MinConfs:           req.MinConfs,
+			SpendUnconfirmed:   req.SpendUnconfirmed,
+			CloseAddress:       rpcChannel.CloseAddress,
+			CommitmentType:     rpcChannel.CommitmentType,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error parsing channel %d: %v",
+				idx, err)
+		}
+
+		// Prepare the stuff that we'll need for the internal PSBT
+		// funding. None of the channels must publish the funding
+		// transaction on their own, we'll do that once all of them
+		// are ready.
+		fundingReq.PendingChanID = pendingChanID
+		fundingReq.ChanFunder = chanfunding.NewPsbtAssembler(
+			btcutil.Amount(rpcChannel.LocalFundingAmount), nil,
+			b.cfg.NetParams, false,
+		)
+
+		b.channels = append(b.channels, &batchChannel{
+			pendingChanID: pendingChanID,
+			fundingReq:    fundingReq,
+		})
+	}
+
+	// From this point on we can fail for any of the channels and for any
+	// number of reasons. This deferred function makes sure that the full
+	// operation is actually atomic: We either succeed and publish a
+	// transaction for the full batch or we clean up everything.
+	defer b.cleanup(ctx)
+
+	// Now that we know the user input is sane, we need to kick off the
+	// channel funding negotiation with the peers. Because we specified a
+	// PSBT assembler, we'll get a special response in the channel once
+	// the funding output script is known (which we need to craft the
+	// TX).
+	for _, channel := range b.channels {
+		channel.updateChan, channel.errChan = b.cfg.ChannelOpener(
+			channel.fundingReq,
+		)
+	}
+
+	// Wait for all channels to report back. Any error at this stage means
+	// we need to abort.
+	if err := b.waitForUpdates(true); err != nil {
+		return nil, fmt.Errorf("error batch opening channel, initial "+
+			"negotiation failed: %v", err)
+	}
+
+	// We can now assemble all outputs that we're going to give to the
+	// PSBT funding RPC.
+	outputs := make(map[string]uint64)
+	for _, channel := range b.channels {
+		if _, ok := outputs[channel.fundingAddr]; ok {
+			return nil, fmt.Errorf("duplicate funding address %v",
+				channel.fundingAddr)
+		}
+
+		outputs[channel.fundingAddr] = uint64(
+			channel.fundingReq.LocalFundingAmt,
+		)
+	}
+
+	// We verified the channel parameters, now we can fund the PSBT.
+	fundPsbtReq := &walletrpc.FundPsbtRequest{
+		Template: &walletrpc.FundPsbtRequest_Raw{
+			Raw: &walletrpc.TxTemplate{
+				Outputs: outputs,
+			},
+		},
+		MinConfs:         req.MinConfs,
+		SpendUnconfirmed: req.SpendUnconfirmed,
+	}
+	if req.SatPerVbyte != 0 {
+		fundPsbtReq.Fees = &walletrpc.FundPsbtRequest_SatPerVbyte{
+			SatPerVbyte: uint64(req.SatPerVbyte),
+		}
+	} else {
+		fundPsbtReq.Fees = &walletrpc.FundPsbtRequest_TargetConf{
+			TargetConf: uint32(req.TargetConf),
+		}
+	}
+	fundPsbtResp, err := b.cfg.WalletKitServer.FundPsbt(ctx, fundPsbtReq)
+	if err != nil {
+		return nil, fmt.Errorf("error funding PSBT for batch channel "+
+			"open: %v", err)
+	}
+
+	// Funding was successful. This means there are some UTXOs that are
+	// now locked for us. We need to make sure we release them if we don't
+	// complete the publish process.
+	b.lockedUTXOs = fundPsbtResp.LockedUtxos
+
+	// Parse and log the funded PSBT for debugging purposes.
+	unsignedPacket, err := psbt.NewFromRawBytes(
+		bytes.NewReader(fundPsbtResp.FundedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing funded PSBT for batch "+
+			"channel open: %v", err)
+	}
+	log.Tracef("[batchopenchannel] funded PSBT: %s",
+		base64.StdEncoding.EncodeToString(fundPsbtResp.FundedPsbt))
+
+	// We've now successfully funded the PSBT. We need to verify it
+	// against each individual channel.
+	for _, channel := range b.channels {
+		err = b.cfg.Wallet.PsbtFundingVerify(
+			channel.pendingChanID, unsignedPacket,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error verifying PSBT: %v", err)
+		}
+	}
+
+	// The funded PSBT was accepted by each channel. We can now sign it.
+	finalizePsbtResp, err := b.cfg.WalletKitServer.FinalizePsbt(
+		ctx, &walletrpc.FinalizePsbtRequest{
+			FundedPsbt: fundPsbtResp.FundedPsbt,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error finalizing PSBT for batch "+
+			"channel open: %v", err)
+	}
+
+	signedPacket, err := psbt.NewFromRawBytes(
+		bytes.NewReader(finalizePsbtResp.SignedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signed PSBT for batch "+
+			"channel open: %v", err)
+	}
+	log.Tracef("[batchopenchannel] signed PSBT: %s",
+		base64.StdEncoding.EncodeToString(finalizePsbtResp.SignedPsbt))
+
+	// Now that we have the signed PSBT, we hand it to each channel. This
+	// completes the negotiation with the peers, each of which will send
+	// us the final pending update once we've received their signature
+	// for our commitment transaction.
+	for _, channel := range b.channels {
+		err = b.cfg.Wallet.PsbtFundingFinalize(
+			channel.pendingChanID, signedPacket, nil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error finalizing PSBT: %v", err)
+		}
+	}
+
+	// Now every channel should be ready for the funding transaction to be
+	// broadcast. Let's wait for the updates that actually confirm this
+	// state.
+	if err := b.waitForUpdates(false); err != nil {
+		return nil, fmt.Errorf("error batch opening channel, final "+
+			"negotiation failed: %v", err)
+	}
+
+	// At this point we know that the funding transaction is ready to be
+	// published, so we publish it.
+	finalTx := &wire.MsgTx{}
+	txReader := bytes.NewReader(finalizePsbtResp.RawFinalTx)
+	if err := finalTx.Deserialize(txReader); err != nil {
+		return nil, fmt.Errorf("error parsing signed raw TX: %v", err)
+	}
+	err = b.cfg.Wallet.PublishTransaction(finalTx, label)
+	if err != nil {
+		return nil, fmt.Errorf("error publishing final batch "+
+			"transaction: %v", err)
+	}
+	b.didPublish = true
+
+	// Now we just need to report the pending channels back to the user.
+	rpcPoints := make([]*lnrpc.PendingUpdate, len(b.channels))
+	for idx, channel := range b.channels {
+		rpcPoints[idx] = &lnrpc.PendingUpdate{
+			Txid:        channel.chanPoint.Hash.CloneBytes(),
+			OutputIndex: channel.chanPoint.Index,
+		}
+	}
+
+	return rpcPoints, nil
+}
+
+// waitForUpdates waits for the next update of every channel of the batch in
+// parallel. The first error reported by any of the channels is returned.
+func (b *Batcher) waitForUpdates(firstUpdate bool) error {
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, len(b.channels))
+	)
+	for _, channel := range b.channels {
+		wg.Add(1)
+		go func(channel *batchChannel) {
+			defer wg.Done()
+
+			if err := b.waitForUpdate(channel, firstUpdate); err != nil {
+				errs <- err
+			}
+		}(channel)
+	}
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+// waitForUpdate waits for an incoming channel update (or error) for a single
+// channel.
+func (b *Batcher) waitForUpdate(channel *batchChannel, firstUpdate bool) error {
+	select {
+	case err := <-channel.errChan:
+		return err
+
+	case update := <-channel.updateChan:
+		if firstUpdate {
+			return channel.processPsbtUpdate(update)
+		}
+
+		return channel.processPendingUpdate(update)
+
+	case <-b.cfg.Quit:
+		return errShuttingDown
+	}
+}
+
+// cleanup tries to remove any pending state or UTXO locks in case we had to
+// abort before finalizing and publishing the funding transaction.
+func (b *Batcher) cleanup(ctx context.Context) {
+	// Did we publish a transaction? Then there's nothing to clean up since
+	// we succeeded.
+	if b.didPublish {
+		return
+	}
+
+	// Make sure the error message doesn't sound too scary. These might be
+	// logged quite frequently depending on where exactly things were
+	// aborted.
+	const errMsgTpl = "Attempted to clean up after failed batch channel " +
+		"open but could not %s: %v"
+
+	// If we failed, we clean up in reverse order. First, let's unlock the
+	// leased outputs.
+	for _, lockedUTXO := range b.lockedUTXOs {
+		_, err := b.cfg.WalletKitServer.ReleaseOutput(
+			ctx, &walletrpc.ReleaseOutputRequest{
+				Id:       lockedUTXO.Id,
+				Outpoint: lockedUTXO.Outpoint,
+			},
+		)
+		if err != nil {
+			log.Debugf(errMsgTpl, "release locked output", err)
+		}
+	}
+
+	// Then go through all channels that ever got into a pending state and
+	// remove the pending channel by abandoning them.
+	for _, channel := range b.channels {
+		if !channel.isPending {
+			continue
+		}
+
+		if err := b.cfg.ChannelAbandoner(channel.chanPoint); err != nil {
+			log.Debugf(errMsgTpl, "abandon pending open channel",
+				err)
+		}
+	}
+
+	// And finally clean up the funding shim for each channel that didn't
+	// make it into a pending state.
+	for _, channel := range b.channels {
+		if channel.isPending {
+			continue
+		}
+
+		err := b.cfg.Wallet.CancelFundingIntent(channel.pendingChanID)
+		if err != nil {
+			log.Debugf(errMsgTpl, "cancel funding shim", err)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package funding
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/psbt"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errFundingFailed = errors.New("funding failed")
+	errPublishFailed = errors.New("publish failed")
+)
+
+// mockBatchWalletKit is a mock implementation of the WalletKitServer
+// interface.
+type mockBatchWalletKit struct {
+	fundedTx    *wire.MsgTx
+	lockedUtxos []*walletrpc.UtxoLease
+	released    [][]byte
+}
+
+func (m *mockBatchWalletKit) FundPsbt(_ context.Context,
+	req *walletrpc.FundPsbtRequest) (*walletrpc.FundPsbtResponse, error) {
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{})
+	for addr, amt := range req.GetRaw().Outputs {
+		tx.AddTxOut(wire.NewTxOut(int64(amt), []byte(addr)))
+	}
+	m.fundedTx = tx
+
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := packet.Serialize(&b); err != nil {
+		return nil, err
+	}
+
+	return &walletrpc.FundPsbtResponse{
+		FundedPsbt:  b.Bytes(),
+		LockedUtxos: m.lockedUtxos,
+	}, nil
+}
+
+func (m *mockBatchWalletKit) FinalizePsbt(_ context.Context,
+	req *walletrpc.FinalizePsbtRequest) (*walletrpc.FinalizePsbtResponse,
+	error) {
+
+	var b bytes.Buffer
+	if err := m.fundedTx.Serialize(&b); err != nil {
+		return nil, err
+	}
+
+	return &walletrpc.FinalizePsbtResponse{
+		SignedPsbt: req.FundedPsbt,
+		RawFinalTx: b.Bytes(),
+	}, nil
+}
+
+func (m *mockBatchWalletKit) ReleaseOutput(_ context.Context,
+	req *walletrpc.ReleaseOutputRequest) (*walletrpc.ReleaseOutputResponse,
+	error) {
+
+	m.released = append(m.released, req.Id)
+
+	return &walletrpc.ReleaseOutputResponse{}, nil
+}
+
+// mockBatchWallet is a mock implementation of the Wallet interface.
+type mockBatchWallet struct {
+	verified   int
+	finalized  int
+	publishErr error
+	published  *wire.MsgTx
+	canceled   [][32]byte
+}
+
+func (m *mockBatchWallet) PsbtFundingVerify([32]byte, *psbt.Packet) error {
+	m.verified++
+	return nil
+}
+
+func (m *mockBatchWallet) PsbtFundingFinalize([32]byte, *psbt.Packet,
+	*wire.MsgTx) error {
+
+	m.finalized++
+	return nil
+}
+
+func (m *mockBatchWallet) PublishTransaction(tx *wire.MsgTx, _ string) error {
+	if m.publishErr != nil {
+		return m.publishErr
+	}
+
+	m.published = tx
+	return nil
+}
+
+func (m *mockBatchWallet) CancelFundingIntent(pid [32]byte) error {
+	m.canceled = append(m.canceled, pid)
+	return nil
+}
+
+// batchTestCase describes a single batch funding test case.
+type batchTestCase struct {
+	name string
+
+	// failInitial is the index of the channel that fails during the
+	// initial negotiation, or -1.
+	failInitial int
+
+	// publishErr is the error returned when publishing the batch
+	// transaction.
+	publishErr error
+
+	expectErr error
+}
+
+// TestBatchFund tests the atomic batch funding of multiple channels.
+func TestBatchFund(t *testing.T) {
+	t.Parallel()
+
+	testCases := []batchTestCase{{
+		name:        "success",
+		failInitial: -1,
+	}, {
+		name:        "initial negotiation fails",
+		failInitial: 1,
+		expectErr:   errFundingFailed,
+	}, {
+		name:        "publish fails",
+		failInitial: -1,
+		publishErr:  errPublishFailed,
+		expectErr:   errPublishFailed,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			testBatchFund(t, tc)
+		})
+	}
+}
+
+func testBatchFund(t *testing.T, tc batchTestCase) {
+	const numChannels = 3
+
+	walletKit := &mockBatchWalletKit{
+		lockedUtxos: []*walletrpc.UtxoLease{{
+			Id:       []byte{1},
+			Outpoint: &lnrpc.OutPoint{},
+		}},
+	}
+	wallet := &mockBatchWallet{
+		publishErr: tc.publishErr,
+	}
+
+	var (
+		numOpened  int
+		abandoned  []*wire.OutPoint
+		fundingTxs = make(chan *wire.MsgTx, 1)
+	)
+	opener := func(req *InitFundingMsg) (chan *lnrpc.OpenStatusUpdate,
+		chan error) {
+
+		idx := numOpened
+		numOpened++
+
+		updates := make(chan *lnrpc.OpenStatusUpdate, 2)
+		errChan := make(chan error, 1)
+
+		if idx == tc.failInitial {
+			errChan <- errFundingFailed
+			return updates, errChan
+		}
+
+		updates <- &lnrpc.OpenStatusUpdate{
+			Update: &lnrpc.OpenStatusUpdate_PsbtFund{
+				PsbtFund: &lnrpc.ReadyForPsbtFunding{
+					FundingAddress: fmt.Sprintf(
+						"addr%d", idx,
+					),
+					FundingAmount: int64(
+						req.LocalFundingAmt,
+					),
+				},
+			},
+		}
+
+		// The pending update is sent once the wallet finalized the
+		// PSBT, which we simulate by sending it as soon as the
+		// funding transaction is known.
+		go func() {
+			tx := <-fundingTxs
+			fundingTxs <- tx
+
+			txid := tx.TxHash()
+			updates <- &lnrpc.OpenStatusUpdate{
+				Update: &lnrpc.OpenStatusUpdate_ChanPending{
+					ChanPending: &lnrpc.PendingUpdate{
+						Txid:        txid[:],
+						OutputIndex: uint32(idx),
+					},
+				},
+			}
+		}()
+
+		return updates, errChan
+	}
+
+	quit := make(chan struct{})
+	defer close(quit)
+
+	batcher := NewBatcher(&BatchConfig{
+		RequestParser: func(req *lnrpc.OpenChannelRequest) (
+			*InitFundingMsg, error) {
+
+			return &InitFundingMsg{
+				LocalFundingAmt: btcutil.Amount(
+					req.LocalFundingAmount,
+				),
+			}, nil
+		},
+		ChannelOpener: opener,
+		ChannelAbandoner: func(op *wire.OutPoint) error {
+			abandoned = append(abandoned, op)
+			return nil
+		},
+		WalletKitServer: &fundingTxNotifier{
+			mockBatchWalletKit: walletKit,
+			fundingTxs:         fundingTxs,
+		},
+		Wallet:    wallet,
+		NetParams: &chaincfg.RegressionNetParams,
+		Quit:      quit,
+	})
+
+	req := &lnrpc.BatchOpenChannelRequest{
+		SatPerVbyte: 5,
+	}
+	for i := 0; i < numChannels; i++ {
+		req.Channels = append(req.Channels, &lnrpc.BatchOpenChannel{
+			NodePubkey:         []byte{byte(i)},
+			LocalFundingAmount: int64(100_000 * (i + 1)),
+		})
+	}
+
+	pending, err := batcher.BatchFund(context.Background(), req)
+	if tc.expectErr != nil {
+		require.Error(t, err)
+		require.Contains(t, err.Error(), tc.expectErr.Error())
+		require.Nil(t, wallet.published)
+	}
+
+	switch {
+	// All channels failed before becoming pending, so all funding intents
+	// must be canceled.
+	case tc.failInitial >= 0:
+		require.Len(t, wallet.canceled, numChannels)
+		require.Empty(t, abandoned)
+		require.Empty(t, walletKit.released)
+
+	// All channels became pending, but the batch transaction couldn't be
+	// published, so all channels must be abandoned and the locked
+	// outputs released.
+	case tc.publishErr != nil:
+		require.Empty(t, wallet.canceled)
+		require.Len(t, abandoned, numChannels)
+		require.Equal(t, [][]byte{{1}}, walletKit.released)
+
+	default:
+		require.NoError(t, err)
+		require.Equal(t, numChannels, wallet.verified)
+		require.Equal(t, numChannels, wallet.finalized)
+		require.NotNil(t, wallet.published)
+		require.Len(t, wallet.published.TxOut, numChannels)
+		require.Len(t, pending, numChannels)
+		require.Empty(t, wallet.canceled)
+		require.Empty(t, abandoned)
+		require.Empty(t, walletKit.released)
+
+		txid := wallet.published.TxHash()
+		for idx, p := range pending {
+			require.Equal(t, txid[:], p.Txid)
+			require.EqualValues(t, idx, p.OutputIndex)
+		}
+	}
+}
+
+// fundingTxNotifier wraps the mock wallet kit and notifies the mock channel
+// opener of the funding transaction once the PSBT is finalized.
+type fundingTxNotifier struct {
+	*mockBatchWalletKit
+
+	fundingTxs chan *wire.MsgTx
+}
+
+func (f *fundingTxNotifier) FinalizePsbt(ctx context.Context,
+	req *walletrpc.FinalizePsbtRequest) (*walletrpc.FinalizePsbtResponse,
+	error) {
+
+	resp, err := f.mockBatchWalletKit.FinalizePsbt(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	f.fundingTxs <- f.fundedTx
+
+	return resp, nil
+}
@@ -0,0 +1,203 @@
+package funding
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// errUnsupportedExplicitNegotiation is an error returned when explicit
+	// channel commitment negotiation is attempted but either peer of the
+	// channel does not support it.
+	errUnsupportedExplicitNegotiation = errors.New("explicit channel " +
+		"type negotiation not supported")
+
+	// errUnsupportedChannelType is an error returned when a specific
+	// channel commitment type is being explicitly negotiated but either
+	// peer of the channel does not support it.
+	errUnsupportedChannelType = errors.New("requested channel type " +
+		"not supported")
+
+	// errChannelTypeMismatch is an error returned when the channel type
+	// echoed by the responder of a channel doesn't match the one we sent.
+	errChannelTypeMismatch = errors.New("channel type mismatch")
+)
+
+// negotiateCommitmentType negotiates the commitment type of a newly opened
+// channel. If a channelType is provided, explicit negotiation for said type
+// will be attempted if the set of both local and remote features support it.
+// Otherwise, implicit negotiation will be attempted. The returned boolean is
+// true if the explicit channel type asks for a zero-conf channel.
+func negotiateCommitmentType(channelType *lnwire.ChannelType,
+	local, remote *lnwire.FeatureVector) (lnwallet.CommitmentType, bool,
+	error) {
+
+	if channelType != nil {
+		if !hasFeatures(local, remote, lnwire.ExplicitChannelTypeOptional) {
+			return 0, false, errUnsupportedExplicitNegotiation
+		}
+		return explicitNegotiateCommitmentType(
+			*channelType, local, remote,
+		)
+	}
+
+	return implicitNegotiateCommitmentType(local, remote), false, nil
+}
+
+// explicitNegotiateCommitmentType attempts to explicitly negotiate for a
+// specific channel type. Since the channel type is comprised of a set of even
+// feature bits, we also make sure each feature is supported by both peers. An
+// error is returned if either peer does not support said channel type.
+//
+// A zero-conf channel is identified by an alias until its funding transaction
+// confirms, so the zero-conf and scid-alias bits can only be set together, on
+// top of the bits of the commitment type.
+func explicitNegotiateCommitmentType(channelType lnwire.ChannelType,
+	local, remote *lnwire.FeatureVector) (lnwallet.CommitmentType, bool,
+	error) {
+
+	channelFeatures := (*lnwire.RawFeatureVector)(&channelType).Clone()
+
+	zeroConf := channelFeatures.IsSet(lnwire.ZeroConfRequired)
+	scidAlias := channelFeatures.IsSet(lnwire.ScidAliasRequired)
+	switch {
+	case zeroConf != scidAlias:
+		return 0, false, errUnsupportedChannelType
+
+	case zeroConf && !zeroConfSupported(local, remote):
+		return 0, false, errZeroConfNotSupported
+	}
+	channelFeatures.Unset(lnwire.ZeroConfRequired)
+	channelFeatures.Unset(lnwire.ScidAliasRequired)
+
+	switch {
+	// Simple taproot channel feature only. The taproot commitment builds
+	// on top of the zero fee anchors one, which is guaranteed by the
+	// feature dependencies of the taproot bit.
+	case channelFeatures.OnlyContains(lnwire.SimpleTaprootChansRequired):
+		if !hasFeatures(local, remote,
+			lnwire.SimpleTaprootChansOptional) {
+
+			return 0, false, errUnsupportedChannelType
+		}
+		return lnwallet.CommitmentTypeSimpleTaproot, zeroConf, nil
+
+	// Anchors zero fee + static remote key features only.
+	case channelFeatures.OnlyContains(
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+		lnwire.StaticRemoteKeyRequired,
+	):
+		if !hasFeatures(local, remote,
+			lnwire.AnchorsZeroFeeHtlcTxOptional,
+			lnwire.StaticRemoteKeyOptional) {
+
+			return 0, false, errUnsupportedChannelType
+		}
+		return lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx, zeroConf,
+			nil
+
+	// Static remote key feature only.
+	case channelFeatures.OnlyContains(lnwire.StaticRemoteKeyRequired):
+		if !hasFeatures(local, remote, lnwire.StaticRemoteKeyOptional) {
+			return 0, false, errUnsupportedChannelType
+		}
+		return lnwallet.CommitmentTypeTweakless, zeroConf, nil
+
+	// No features, use legacy commitment type.
+	case channelFeatures.IsEmpty():
+		return lnwallet.CommitmentTypeLegacy, zeroConf, nil
+
+	default:
+		return 0, false, errUnsupportedChannelType
+	}
+}
+
+// validateAcceptChannelType checks that the channel type received in the
+// AcceptChannel message matches the one we sent in the OpenChannel message.
+// If we didn't send an explicit channel type, the responder must not send one
+// either.
+func validateAcceptChannelType(sent, received *lnwire.ChannelType) error {
+	switch {
+	case sent == nil && received == nil:
+		return nil
+
+	case sent == nil || received == nil:
+		return errChannelTypeMismatch
+	}
+
+	sentFeatures := lnwire.RawFeatureVector(*sent)
+	receivedFeatures := lnwire.RawFeatureVector(*received)
+	if !sentFeatures.Equals(&receivedFeatures) {
+		return errChannelTypeMismatch
+	}
+
+	return nil
+}
+
+// implicitNegotiateCommitmentType negotiates the commitment type of a channel
+// implicitly by choosing the latest type supported by the local and remote
+// features.
+func implicitNegotiateCommitmentType(local,
+	remote *lnwire.FeatureVector) lnwallet.CommitmentType {
+
+	// If both peers are signalling support for anchor commitments with
+	// zero-fee HTLC transactions, we'll use this type.
+	if hasFeatures(local, remote, lnwire.AnchorsZeroFeeHtlcTxOptional) {
+		return lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx
+	}
+
+	// Since we don't want to support the "legacy" anchor type, we will
+	// fall back to static remote key if the nodes don't support the zero
+	// fee HTLC tx anchor type.
+	//
+	// If both nodes are signaling the proper feature bit for tweakless
+	// commitments, we'll use that.
+	if hasFeatures(local, remote, lnwire.StaticRemoteKeyOptional) {
+		return lnwallet.CommitmentTypeTweakless
+	}
+
+	// Otherwise we'll fall back to the legacy type.
+	return lnwallet.CommitmentTypeLegacy
+}
+
+// commitmentTypeToChannelType returns the explicit channel type that
+// corresponds to the passed commitment type. The zero-conf and scid-alias bits
+// are added for zero-conf channels.
+func commitmentTypeToChannelType(commitType lnwallet.CommitmentType,
+	zeroConf bool) *lnwire.ChannelType {
+
+	var bits []lnwire.FeatureBit
+	switch commitType {
+	case lnwallet.CommitmentTypeSimpleTaproot:
+		bits = append(bits, lnwire.SimpleTaprootChansRequired)
+
+	case lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx:
+		bits = append(bits, lnwire.StaticRemoteKeyRequired,
+			lnwire.AnchorsZeroFeeHtlcTxRequired)
+
+	case lnwallet.CommitmentTypeTweakless:
+		bits = append(bits, lnwire.StaticRemoteKeyRequired)
+	}
+
+	if zeroConf {
+		bits = append(bits, lnwire.ZeroConfRequired,
+			lnwire.ScidAliasRequired)
+	}
+
+	return lnwire.NewChannelType(bits...)
+}
+
+// hasFeatures determines whether a set of features is supported by both the
+// set of local and remote features.
+func hasFeatures(local, remote *lnwire.FeatureVector,
+	features ...lnwire.FeatureBit) bool {
+
+	for _, feature := range features {
+		if !local.HasFeature(feature) || !remote.HasFeature(feature) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,410 @@
+package funding
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommitmentTypeNegotiation tests all of the possible paths of a channel
+// commitment type negotiation.
+func TestCommitmentTypeNegotiation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		channelType     *lnwire.ChannelType
+		localFeatures   *lnwire.FeatureVector
+		remoteFeatures  *lnwire.FeatureVector
+		expectsRes      lnwallet.CommitmentType
+		expectsZeroConf bool
+		expectsErr      error
+	}{
+		{
+			name: "explicit zero-conf",
+			channelType: lnwire.NewChannelType(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+				lnwire.ZeroConfRequired,
+				lnwire.ScidAliasRequired,
+			),
+			localFeatures:  zeroConfFeatures(),
+			remoteFeatures: zeroConfFeatures(),
+			expectsRes: lnwallet.
+				CommitmentTypeAnchorsZeroFeeHtlcTx,
+			expectsZeroConf: true,
+		},
+		{
+			name: "explicit zero-conf missing remote feature",
+			channelType: lnwire.NewChannelType(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+				lnwire.ZeroConfRequired,
+				lnwire.ScidAliasRequired,
+			),
+			localFeatures: zeroConfFeatures(),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+					lnwire.ScidAliasOptional,
+				), nil,
+			),
+			expectsErr: errZeroConfNotSupported,
+		},
+		{
+			name: "explicit zero-conf without scid-alias",
+			channelType: lnwire.NewChannelType(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+				lnwire.ZeroConfRequired,
+			),
+			localFeatures:  zeroConfFeatures(),
+			remoteFeatures: zeroConfFeatures(),
+			expectsErr:     errUnsupportedChannelType,
+		},
+		{
+			name: "explicit scid-alias without zero-conf",
+			channelType: lnwire.NewChannelType(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.ScidAliasRequired,
+			),
+			localFeatures:  zeroConfFeatures(),
+			remoteFeatures: zeroConfFeatures(),
+			expectsErr:     errUnsupportedChannelType,
+		},
+		{
+			name: "explicit taproot",
+			channelType: lnwire.NewChannelType(
+				lnwire.SimpleTaprootChansRequired,
+			),
+			localFeatures:  taprootFeatures(),
+			remoteFeatures: taprootFeatures(),
+			expectsRes:     lnwallet.CommitmentTypeSimpleTaproot,
+		},
+		{
+			name: "explicit taproot zero-conf",
+			channelType: lnwire.NewChannelType(
+				lnwire.SimpleTaprootChansRequired,
+				lnwire.ZeroConfRequired,
+				lnwire.ScidAliasRequired,
+			),
+			localFeatures:   taprootFeatures(),
+			remoteFeatures:  taprootFeatures(),
+			expectsRes:      lnwallet.CommitmentTypeSimpleTaproot,
+			expectsZeroConf: true,
+		},
+		{
+			name: "explicit taproot missing remote feature",
+			channelType: lnwire.NewChannelType(
+				lnwire.SimpleTaprootChansRequired,
+			),
+			localFeatures:  taprootFeatures(),
+			remoteFeatures: zeroConfFeatures(),
+			expectsErr:     errUnsupportedChannelType,
+		},
+		{
+			name: "explicit taproot with anchors bits",
+			channelType: lnwire.NewChannelType(
+				lnwire.SimpleTaprootChansRequired,
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+			),
+			localFeatures:  taprootFeatures(),
+			remoteFeatures: taprootFeatures(),
+			expectsErr:     errUnsupportedChannelType,
+		},
+		{
+			name: "implicit never taproot",
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.SimpleTaprootChansOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.SimpleTaprootChansOptional,
+				), nil,
+			),
+			expectsRes: lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+		},
+		{
+			name: "explicit missing remote negotiation feature",
+			channelType: lnwire.NewChannelType(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+			),
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+				), nil,
+			),
+			expectsErr: errUnsupportedExplicitNegotiation,
+		},
+		{
+			name: "explicit missing remote commitment feature",
+			channelType: lnwire.NewChannelType(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+			),
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			expectsErr: errUnsupportedChannelType,
+		},
+		{
+			name: "explicit unknown channel type",
+			channelType: lnwire.NewChannelType(
+				lnwire.AnchorsRequired,
+			),
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.AnchorsOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.AnchorsOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			expectsErr: errUnsupportedChannelType,
+		},
+		{
+			name: "explicit anchors",
+			channelType: lnwire.NewChannelType(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+			),
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			expectsRes: lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+		},
+		{
+			name: "explicit tweakless",
+			channelType: lnwire.NewChannelType(
+				lnwire.StaticRemoteKeyRequired,
+			),
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			expectsRes: lnwallet.CommitmentTypeTweakless,
+		},
+		{
+			name:        "explicit legacy",
+			channelType: lnwire.NewChannelType(),
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+					lnwire.ExplicitChannelTypeOptional,
+				), nil,
+			),
+			expectsRes: lnwallet.CommitmentTypeLegacy,
+		},
+		{
+			name: "implicit anchors",
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+				), nil,
+			),
+			expectsRes: lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+		},
+		{
+			name: "implicit tweakless",
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+				), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+				), nil,
+			),
+			expectsRes: lnwallet.CommitmentTypeTweakless,
+		},
+		{
+			name: "implicit legacy",
+			localFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(), nil,
+			),
+			remoteFeatures: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+					lnwire.AnchorsZeroFeeHtlcTxOptional,
+				), nil,
+			),
+			expectsRes: lnwallet.CommitmentTypeLegacy,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		ok := t.Run(testCase.name, func(t *testing.T) {
+			localType, localZeroConf, err :=
+				negotiateCommitmentType(
+					testCase.channelType,
+					testCase.localFeatures,
+					testCase.remoteFeatures,
+				)
+			require.Equal(t, testCase.expectsErr, err)
+
+			remoteType, remoteZeroConf, err :=
+				negotiateCommitmentType(
+					testCase.channelType,
+					testCase.remoteFeatures,
+					testCase.localFeatures,
+				)
+			require.Equal(t, testCase.expectsErr, err)
+
+			if testCase.expectsErr != nil {
+				return
+			}
+
+			require.Equal(t, testCase.expectsRes, localType)
+			require.Equal(t, testCase.expectsRes, remoteType)
+			require.Equal(
+				t, testCase.expectsZeroConf, localZeroConf,
+			)
+			require.Equal(
+				t, testCase.expectsZeroConf, remoteZeroConf,
+			)
+
+			// The channel type we'd send for the negotiated
+			// commitment type must be the one we negotiated.
+			if testCase.channelType != nil {
+				chanType := commitmentTypeToChannelType(
+					localType, localZeroConf,
+				)
+				require.NoError(t, validateAcceptChannelType(
+					testCase.channelType, chanType,
+				))
+			}
+		})
+		if !ok {
+			return
+		}
+	}
+}
+
+// zeroConfFeatures returns a feature vector that signals all features
+// required for zero-conf anchor channels.
+func zeroConfFeatures() *lnwire.FeatureVector {
+	return lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyOptional,
+			lnwire.AnchorsZeroFeeHtlcTxOptional,
+			lnwire.ExplicitChannelTypeOptional,
+			lnwire.ScidAliasOptional,
+			lnwire.ZeroConfOptional,
+		), nil,
+	)
+}
+
+// taprootFeatures returns a feature vector that signals all features required
+// for zero-conf simple taproot channels.
+func taprootFeatures() *lnwire.FeatureVector {
+	return lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyOptional,
+			lnwire.AnchorsZeroFeeHtlcTxOptional,
+			lnwire.ExplicitChannelTypeOptional,
+			lnwire.ScidAliasOptional,
+			lnwire.ZeroConfOptional,
+			lnwire.SimpleTaprootChansOptional,
+		), nil,
+	)
+}
+
+// TestValidateAcceptChannelType tests that the channel type echoed by the
+// responder must match the one we sent.
+func TestValidateAcceptChannelType(t *testing.T) {
+	t.Parallel()
+
+	tweakless := lnwire.NewChannelType(lnwire.StaticRemoteKeyRequired)
+	anchors := lnwire.NewChannelType(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	)
+
+	require.NoError(t, validateAcceptChannelType(nil, nil))
+	require.NoError(t, validateAcceptChannelType(tweakless, tweakless))
+	require.Equal(
+		t, errChannelTypeMismatch,
+		validateAcceptChannelType(tweakless, anchors),
+	)
+	require.Equal(
+		t, errChannelTypeMismatch,
+		validateAcceptChannelType(tweakless, nil),
+	)
+	require.Equal(
+		t, errChannelTypeMismatch,
+		validateAcceptChannelType(nil, tweakless),
+	)
+}
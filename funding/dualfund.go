@@ -0,0 +1,1596 @@
+package funding
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// errDualFundNotSupported is returned if a dual funded channel is
+	// requested, but either we or the peer don't signal the dual-fund
+	// feature bit.
+	errDualFundNotSupported = errors.New("dual funded channels require " +
+		"both peers to signal the dual-fund feature")
+
+	// dualFundTxSigsBucket is the database bucket used to store the state
+	// of the tx signatures exchange of each dual funded channel that is
+	// stored as pending, but whose funding transaction isn't fully signed
+	// yet.
+	dualFundTxSigsBucket = []byte("dualFundTxSigs")
+)
+
+// dualFundFlow tracks a channel that is funded by both parties through the v2
+// channel establishment protocol. It's created along with the reservation,
+// and lives until both parties exchanged the signatures of their inputs to
+// the funding transaction.
+type dualFundFlow struct {
+	// chanID identifies the channel in all messages following the
+	// accept_channel2 message. It's derived from the revocation basepoints
+	// of both parties.
+	chanID lnwire.ChannelID
+
+	// pendingChanID is the pending channel ID of the reservation.
+	pendingChanID [32]byte
+
+	// resCtx is the reservation of the channel.
+	resCtx *reservationWithCtx
+
+	// lockTime is the locktime of the funding transaction, as chosen by
+	// the initiator.
+	lockTime uint32
+
+	// remoteContribution is the remote party's contribution to the
+	// channel, which is handed to the reservation along with the funding
+	// transaction once it's constructed.
+	remoteContribution *lnwallet.ChannelContribution
+
+	// fundingScript is the pkScript of the funding output.
+	fundingScript []byte
+
+	// tx is the interactive construction of the funding transaction.
+	tx *interactiveTx
+
+	// pendingMsgs are the messages adding our inputs and outputs that we
+	// have yet to send. Only a single message is sent per turn.
+	pendingMsgs []lnwire.Message
+
+	// ourTurn is true if the next interactive tx message is ours to send.
+	ourTurn bool
+
+	// fundingTx is the constructed funding transaction. It's set once
+	// both parties signaled that the construction is complete.
+	fundingTx *wire.MsgTx
+
+	// completeChan is the pending channel as stored in the database once
+	// both commitment transactions are signed.
+	completeChan *channeldb.OpenChannel
+
+	// txSigs is the state of the exchange of the signatures of both
+	// parties' inputs to the funding transaction. It's set along with
+	// completeChan.
+	txSigs *dualFundTxSigs
+
+	// done is closed once the exchange of the signatures is complete or
+	// given up on. It's set along with completeChan.
+	done chan struct{}
+}
+
+// remoteKey returns the identity key of the remote party. A flow that was
+// restored after a restart has no reservation, but a pending channel.
+func (d *dualFundFlow) remoteKey() *btcec.PublicKey {
+	if d.resCtx != nil {
+		return d.resCtx.peer.IdentityKey()
+	}
+
+	return d.completeChan.IdentityPub
+}
+
+// dualFundReserve returns the channel reserve of a dual funded channel. Both
+// parties must hold 1% of the channel capacity, but at least the dust limit
+// of the other party, so the reserve doesn't end up trimmed from its
+// commitment transaction.
+func dualFundReserve(capacity, dustLimit btcutil.Amount) btcutil.Amount {
+	reserve := capacity / 100
+	if reserve < dustLimit {
+		reserve = dustLimit
+	}
+
+	return reserve
+}
+
+// validateDualFundInit checks whether a locally initiated funding flow can be
+// carried out with the v2 channel establishment protocol. The zeroConf flag is
+// true if either the request or its explicit channel type asks for a
+// zero-conf channel.
+func validateDualFundInit(msg *InitFundingMsg, zeroConf bool) error {
+	switch {
+	case !hasFeatures(
+		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
+		lnwire.DualFundOptional,
+	):
+		return errDualFundNotSupported
+
+	// The funding transaction is constructed together with the remote
+	// party, so it has to come from our wallet.
+	case msg.ChanFunder != nil:
+		return fmt.Errorf("dual funded channels can't use a custom " +
+			"channel funder")
+
+	case msg.PushAmt != 0:
+		return fmt.Errorf("dual funded channels can't push funds")
+
+	case zeroConf:
+		return fmt.Errorf("dual funded channels can't be zero-conf")
+	}
+
+	return nil
+}
+
+// newOpenChannel2 creates the open_channel2 message equivalent to the
+// open_channel message we'd send for a single funded channel.
+func newOpenChannel2(msg *lnwire.OpenChannel,
+	fundingFeePerKw chainfee.SatPerKWeight,
+	lockTime uint32) *lnwire.OpenChannel2 {
+
+	return &lnwire.OpenChannel2{
+		ChainHash:             msg.ChainHash,
+		PendingChannelID:      msg.PendingChannelID,
+		FundingFeePerKWeight:  uint32(fundingFeePerKw),
+		CommitFeePerKWeight:   msg.FeePerKiloWeight,
+		FundingAmount:         msg.FundingAmount,
+		DustLimit:             msg.DustLimit,
+		MaxValueInFlight:      msg.MaxValueInFlight,
+		HtlcMinimum:           msg.HtlcMinimum,
+		CsvDelay:              msg.CsvDelay,
+		MaxAcceptedHTLCs:      msg.MaxAcceptedHTLCs,
+		LockTime:              lockTime,
+		FundingKey:            msg.FundingKey,
+		RevocationPoint:       msg.RevocationPoint,
+		PaymentPoint:          msg.PaymentPoint,
+		DelayedPaymentPoint:   msg.DelayedPaymentPoint,
+		HtlcPoint:             msg.HtlcPoint,
+		FirstCommitmentPoint:  msg.FirstCommitmentPoint,
+		ChannelFlags:          msg.ChannelFlags,
+		UpfrontShutdownScript: msg.UpfrontShutdownScript,
+		ChannelType:           msg.ChannelType,
+	}
+}
+
+// openChannelFromV2 converts an open_channel2 message into the equivalent
+// open_channel message, so that inbound dual funded channels pass through the
+// same channel acceptor as single funded ones. The reserve is computed from
+// the initiator's contribution only, as we don't know ours yet.
+func openChannelFromV2(msg *lnwire.OpenChannel2) *lnwire.OpenChannel {
+	return &lnwire.OpenChannel{
+		ChainHash:        msg.ChainHash,
+		PendingChannelID: msg.PendingChannelID,
+		FundingAmount:    msg.FundingAmount,
+		DustLimit:        msg.DustLimit,
+		MaxValueInFlight: msg.MaxValueInFlight,
+		ChannelReserve: dualFundReserve(
+			msg.FundingAmount, msg.DustLimit,
+		),
+		HtlcMinimum:           msg.HtlcMinimum,
+		FeePerKiloWeight:      msg.CommitFeePerKWeight,
+		CsvDelay:              msg.CsvDelay,
+		MaxAcceptedHTLCs:      msg.MaxAcceptedHTLCs,
+		FundingKey:            msg.FundingKey,
+		RevocationPoint:       msg.RevocationPoint,
+		PaymentPoint:          msg.PaymentPoint,
+		DelayedPaymentPoint:   msg.DelayedPaymentPoint,
+		HtlcPoint:             msg.HtlcPoint,
+		FirstCommitmentPoint:  msg.FirstCommitmentPoint,
+		ChannelFlags:          msg.ChannelFlags,
+		UpfrontShutdownScript: msg.UpfrontShutdownScript,
+		ChannelType:           msg.ChannelType,
+	}
+}
+
+// handleFundingOpen2 creates a reservation for a dual funded channel opened
+// by the remote peer, contributing the amount our liquidity policy decides
+// on, and responds with an accept_channel2 message. The initiator then starts
+// the interactive construction of the funding transaction.
+func (f *Manager) handleFundingOpen2(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel2) {
+
+	peerIDKey := newSerializedKey(peer.IdentityKey())
+	amt := msg.FundingAmount
+
+	if !hasFeatures(
+		peer.LocalFeatures(), peer.RemoteFeatures(),
+		lnwire.DualFundOptional,
+	) {
+
+		f.failFundingFlow(
+			peer, msg.PendingChannelID, errDualFundNotSupported,
+		)
+		return
+	}
+
+	if err := f.checkInboundChannel(peer, amt); err != nil {
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// Query our channel acceptor with the equivalent open_channel message
+	// to determine whether we'll accept the channel.
+	chanReq := &chanacceptor.ChannelAcceptRequest{
+		Node:        peer.IdentityKey(),
+		OpenChanMsg: openChannelFromV2(msg),
+	}
+	acceptorResp := f.cfg.OpenChannelPredicate.Accept(chanReq)
+	if acceptorResp.RejectChannel() {
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			acceptorResp.ChanAcceptError,
+		)
+		return
+	}
+	if acceptorResp.ZeroConf {
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			fmt.Errorf("dual funded channels can't be zero-conf"),
+		)
+		return
+	}
+
+	commitType, zeroConf, err := negotiateCommitmentType(
+		msg.ChannelType, peer.LocalFeatures(), peer.RemoteFeatures(),
+	)
+	if err != nil {
+		log.Errorf("Unable to negotiate commitment type for "+
+			"pendingId=%x: %v", msg.PendingChannelID, err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+	if zeroConf {
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			fmt.Errorf("dual funded channels can't be zero-conf"),
+		)
+		return
+	}
+
+	// Our liquidity policy decides how much we add to the channel, as
+	// long as the channel stays within our maximum channel size.
+	var localAmt btcutil.Amount
+	if f.cfg.DualFundContribution != nil {
+		localAmt = f.cfg.DualFundContribution(peer.IdentityKey(), msg)
+	}
+	if amt+localAmt > f.cfg.MaxChanSize {
+		localAmt = f.cfg.MaxChanSize - amt
+	}
+
+	log.Infof("Recv'd dual fundingRequest(amt=%v, local_amt=%v, "+
+		"delay=%v, pendingId=%x) from peer(%x)", amt, localAmt,
+		msg.CsvDelay, msg.PendingChannelID,
+		peer.IdentityKey().SerializeCompressed())
+
+	chainHash := chainhash.Hash(msg.ChainHash)
+	req := &lnwallet.InitFundingReserveMsg{
+		ChainHash:        &chainHash,
+		PendingChanID:    msg.PendingChannelID,
+		NodeID:           peer.IdentityKey(),
+		NodeAddr:         peer.Address(),
+		LocalFundingAmt:  localAmt,
+		RemoteFundingAmt: amt,
+		CommitFeePerKw:   chainfee.SatPerKWeight(msg.CommitFeePerKWeight),
+		FundingFeePerKw:  chainfee.SatPerKWeight(msg.FundingFeePerKWeight),
+		Flags:            msg.ChannelFlags,
+		MinConfs:         1,
+		CommitType:       commitType,
+		DualFund:         true,
+	}
+	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
+
+	// If we can't fund our contribution, then we'll still accept the
+	// channel, just without adding any funds to it.
+	if err != nil && localAmt != 0 {
+		log.Warnf("Unable to contribute %v to pendingId=%x, accepting "+
+			"without contribution: %v", localAmt,
+			msg.PendingChannelID, err)
+
+		_ = f.cfg.Wallet.CancelFundingIntent(msg.PendingChannelID)
+
+		req.LocalFundingAmt = 0
+		reservation, err = f.cfg.Wallet.InitChannelReservation(req)
+	}
+	if err != nil {
+		log.Errorf("Unable to initialize reservation: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+	capacity := reservation.Capacity()
+	localAmt = capacity - amt
+
+	numConfsReq := f.cfg.NumRequiredConfs(capacity, 0)
+	if acceptorResp.MinAcceptDepth != 0 {
+		numConfsReq = acceptorResp.MinAcceptDepth
+	}
+	reservation.SetNumConfsRequired(numConfsReq)
+
+	// Both parties hold a reserve of 1% of the total channel capacity, so
+	// it isn't negotiated in the v2 protocol.
+	channelConstraints := &channeldb.ChannelConstraints{
+		DustLimit:        msg.DustLimit,
+		ChanReserve:      dualFundReserve(capacity, msg.DustLimit),
+		MaxPendingAmount: msg.MaxValueInFlight,
+		MinHTLC:          msg.HtlcMinimum,
+		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+		CsvDelay:         msg.CsvDelay,
+	}
+	err = reservation.CommitConstraints(
+		channelConstraints, f.cfg.MaxLocalCSVDelay,
+	)
+	if err != nil {
+		log.Errorf("Unacceptable channel constraints: %v", err)
+		f.cancelDualFundReservation(reservation)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	shutdown, err := getUpfrontShutdownScript(
+		f.cfg.EnableUpfrontShutdown, peer, acceptorResp.UpfrontShutdown,
+		func() (lnwire.DeliveryAddress, error) {
+			addr, err := f.cfg.Wallet.NewAddress(
+				lnwallet.WitnessPubKey, false,
+				lnwallet.DefaultAccountName,
+			)
+			if err != nil {
+				return nil, err
+			}
+			return txscript.PayToAddrScript(addr)
+		},
+	)
+	if err != nil {
+		f.cancelDualFundReservation(reservation)
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			fmt.Errorf("getUpfrontShutdownScript error: %v", err),
+		)
+		return
+	}
+	reservation.SetOurUpfrontShutdown(shutdown)
+
+	// Generate our required constraints for the remote party, using the
+	// values provided by the channel acceptor if they are non-zero.
+	remoteCsvDelay := f.cfg.RequiredRemoteDelay(capacity)
+	if acceptorResp.CSVDelay != 0 {
+		remoteCsvDelay = acceptorResp.CSVDelay
+	}
+
+	remoteMaxValue := f.cfg.RequiredRemoteMaxValue(capacity)
+	if acceptorResp.InFlightTotal != 0 {
+		remoteMaxValue = acceptorResp.InFlightTotal
+	}
+
+	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(capacity)
+	if acceptorResp.HtlcLimit != 0 {
+		maxHtlcs = acceptorResp.HtlcLimit
+	}
+
+	minHtlc := f.cfg.DefaultMinHtlcIn
+	if acceptorResp.MinHtlcIn != 0 {
+		minHtlc = acceptorResp.MinHtlcIn
+	}
+
+	ourContribution := reservation.OurContribution()
+	chanReserve := dualFundReserve(capacity, ourContribution.DustLimit)
+
+	remoteContribution := &lnwallet.ChannelContribution{
+		FundingAmount:        amt,
+		FirstCommitmentPoint: msg.FirstCommitmentPoint,
+		ChannelConfig: &channeldb.ChannelConfig{
+			ChannelConstraints: channeldb.ChannelConstraints{
+				DustLimit:        msg.DustLimit,
+				MaxPendingAmount: remoteMaxValue,
+				ChanReserve:      chanReserve,
+				MinHTLC:          minHtlc,
+				MaxAcceptedHtlcs: maxHtlcs,
+				CsvDelay:         remoteCsvDelay,
+			},
+			MultiSigKey: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.FundingKey),
+			},
+			RevocationBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.RevocationPoint),
+			},
+			PaymentBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.PaymentPoint),
+			},
+			DelayBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.DelayedPaymentPoint),
+			},
+			HtlcBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.HtlcPoint),
+			},
+		},
+		UpfrontShutdown: msg.UpfrontShutdownScript,
+	}
+
+	resCtx := &reservationWithCtx{
+		reservation:    reservation,
+		chanAmt:        capacity,
+		remoteCsvDelay: remoteCsvDelay,
+		remoteMinHtlc:  minHtlc,
+		remoteMaxValue: remoteMaxValue,
+		remoteMaxHtlcs: maxHtlcs,
+		maxLocalCsv:    f.cfg.MaxLocalCSVDelay,
+		err:            make(chan error, 1),
+		peer:           peer,
+	}
+	resCtx.dualFund = &dualFundFlow{
+		pendingChanID: msg.PendingChannelID,
+		resCtx:        resCtx,
+		lockTime:      msg.LockTime,
+	}
+
+	f.resMtx.Lock()
+	if _, ok := f.activeReservations[peerIDKey]; !ok {
+		f.activeReservations[peerIDKey] = make(pendingChannels)
+	}
+	f.activeReservations[peerIDKey][msg.PendingChannelID] = resCtx
+	f.resMtx.Unlock()
+
+	// Update the timestamp once the open_channel2 message has been
+	// handled.
+	defer resCtx.updateTimestamp()
+
+	err = f.startDualFundFlow(resCtx.dualFund, remoteContribution, false)
+	if err != nil {
+		log.Errorf("Unable to start interactive funding tx for "+
+			"pendingId=%x: %v", msg.PendingChannelID, err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	log.Infof("Sending dual fundingResp for pending_id(%x), "+
+		"contributing %v", msg.PendingChannelID, localAmt)
+
+	fundingAccept := &lnwire.AcceptChannel2{
+		PendingChannelID:      msg.PendingChannelID,
+		FundingAmount:         localAmt,
+		DustLimit:             ourContribution.DustLimit,
+		MaxValueInFlight:      remoteMaxValue,
+		HtlcMinimum:           minHtlc,
+		MinAcceptDepth:        uint32(numConfsReq),
+		CsvDelay:              remoteCsvDelay,
+		MaxAcceptedHTLCs:      maxHtlcs,
+		FundingKey:            ourContribution.MultiSigKey.PubKey,
+		RevocationPoint:       ourContribution.RevocationBasePoint.PubKey,
+		PaymentPoint:          ourContribution.PaymentBasePoint.PubKey,
+		DelayedPaymentPoint:   ourContribution.DelayBasePoint.PubKey,
+		HtlcPoint:             ourContribution.HtlcBasePoint.PubKey,
+		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
+		UpfrontShutdownScript: ourContribution.UpfrontShutdown,
+		ChannelType:           msg.ChannelType,
+	}
+	if err := peer.SendMessage(true, fundingAccept); err != nil {
+		log.Errorf("unable to send funding response to peer: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+}
+
+// cancelDualFundReservation cancels a reservation that was created for an
+// inbound dual funded channel, but not yet added to the set of active
+// reservations.
+func (f *Manager) cancelDualFundReservation(
+	reservation *lnwallet.ChannelReservation) {
+
+	if err := reservation.Cancel(); err != nil {
+		log.Errorf("unable to cancel reservation: %v", err)
+	}
+}
+
+// handleFundingAccept2 processes the remote party's response to a dual funded
+// channel we initiated. Their contribution is added to the channel, and we
+// start the interactive construction of the funding transaction by sending
+// our first input or output.
+func (f *Manager) handleFundingAccept2(peer lnpeer.Peer,
+	msg *lnwire.AcceptChannel2) {
+
+	pendingChanID := msg.PendingChannelID
+	peerKey := peer.IdentityKey()
+
+	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+	if err != nil {
+		log.Warnf("Can't find reservation (peerKey:%v, chan_id:%v)",
+			peerKey, pendingChanID)
+		return
+	}
+
+	// Update the timestamp once the accept_channel2 message has been
+	// handled.
+	defer resCtx.updateTimestamp()
+
+	if resCtx.dualFund == nil {
+		err := fmt.Errorf("received accept_channel2 for single " +
+			"funded channel")
+		log.Warnf("Unexpected funding response: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	log.Infof("Recv'd dual fundingResponse for pending_id(%x), remote "+
+		"contributes %v", pendingChanID[:], msg.FundingAmount)
+
+	// Dual funded channels always wait for the funding transaction to
+	// confirm.
+	switch {
+	case msg.MinAcceptDepth > chainntnfs.MaxNumConfs:
+		err = lnwallet.ErrNumConfsTooLarge(
+			msg.MinAcceptDepth, chainntnfs.MaxNumConfs,
+		)
+
+	case msg.MinAcceptDepth == 0:
+		err = fmt.Errorf("dual funded channel has min_depth of zero")
+
+	default:
+		err = validateAcceptChannelType(
+			resCtx.channelType, msg.ChannelType,
+		)
+	}
+	if err != nil {
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	reservation := resCtx.reservation
+	reservation.SetNumConfsRequired(uint16(msg.MinAcceptDepth))
+
+	// Add the remote party's contribution to the channel before we commit
+	// to the constraints, as the reserve depends on the total capacity.
+	if err := reservation.AddRemoteFunding(msg.FundingAmount); err != nil {
+		log.Errorf("Unable to add remote funding: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+	capacity := reservation.Capacity()
+	resCtx.chanAmt = capacity
+
+	channelConstraints := &channeldb.ChannelConstraints{
+		DustLimit:        msg.DustLimit,
+		ChanReserve:      dualFundReserve(capacity, msg.DustLimit),
+		MaxPendingAmount: msg.MaxValueInFlight,
+		MinHTLC:          msg.HtlcMinimum,
+		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+		CsvDelay:         msg.CsvDelay,
+	}
+	err = reservation.CommitConstraints(
+		channelConstraints, resCtx.maxLocalCsv,
+	)
+	if err != nil {
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	ourContribution := reservation.OurContribution()
+	chanReserve := dualFundReserve(capacity, ourContribution.DustLimit)
+
+	remoteContribution := &lnwallet.ChannelContribution{
+		FundingAmount:        msg.FundingAmount,
+		FirstCommitmentPoint: msg.FirstCommitmentPoint,
+		ChannelConfig: &channeldb.ChannelConfig{
+			ChannelConstraints: channeldb.ChannelConstraints{
+				DustLimit:        msg.DustLimit,
+				MaxPendingAmount: resCtx.remoteMaxValue,
+				ChanReserve:      chanReserve,
+				MinHTLC:          resCtx.remoteMinHtlc,
+				MaxAcceptedHtlcs: resCtx.remoteMaxHtlcs,
+				CsvDelay:         resCtx.remoteCsvDelay,
+			},
+			MultiSigKey: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.FundingKey),
+			},
+			RevocationBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.RevocationPoint),
+			},
+			PaymentBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.PaymentPoint),
+			},
+			DelayBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.DelayedPaymentPoint),
+			},
+			HtlcBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.HtlcPoint),
+			},
+		},
+		UpfrontShutdown: msg.UpfrontShutdownScript,
+	}
+
+	flow := resCtx.dualFund
+	if err := f.startDualFundFlow(flow, remoteContribution, true); err != nil {
+		log.Errorf("Unable to start interactive funding tx for "+
+			"pendingId=%x: %v", pendingChanID, err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	// As the initiator, we send the first message of the interactive
+	// transaction construction.
+	if err := f.sendInteractiveTxMsg(flow); err != nil {
+		log.Errorf("Unable to send interactive tx message: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+	}
+}
+
+// startDualFundFlow prepares the interactive construction of the funding
+// transaction of a dual funded channel once both parties know each other's
+// keys. The messages adding our inputs, our change and, as the initiator, the
+// funding output are queued, and the flow is indexed by its channel ID.
+func (f *Manager) startDualFundFlow(flow *dualFundFlow,
+	remoteContribution *lnwallet.ChannelContribution,
+	isInitiator bool) error {
+
+	reservation := flow.resCtx.reservation
+	ourContribution := reservation.OurContribution()
+
+	flow.remoteContribution = remoteContribution
+	flow.chanID = lnwire.NewChanIDFromRevocationBasepoints(
+		ourContribution.RevocationBasePoint.PubKey,
+		remoteContribution.RevocationBasePoint.PubKey,
+	)
+
+	_, fundingOutput, err := input.GenFundingPkScript(
+		ourContribution.MultiSigKey.PubKey.SerializeCompressed(),
+		remoteContribution.MultiSigKey.PubKey.SerializeCompressed(),
+		int64(reservation.Capacity()),
+	)
+	if err != nil {
+		return err
+	}
+	flow.fundingScript = fundingOutput.PkScript
+
+	flow.tx = newInteractiveTx(
+		flow.chanID, isInitiator, flow.lockTime,
+		ourContribution.DustLimit,
+	)
+	flow.ourTurn = isInitiator
+
+	// Our inputs are announced along with the transactions they spend, so
+	// the remote party can verify they're native segwit outputs.
+	for _, txIn := range ourContribution.Inputs {
+		prevOut := txIn.PreviousOutPoint
+		utxo, err := f.cfg.Wallet.FetchInputInfo(&prevOut)
+		if err != nil {
+			return err
+		}
+		if utxo.PrevTx == nil {
+			return fmt.Errorf("previous tx of input %v unknown",
+				prevOut)
+		}
+
+		msg, err := flow.tx.AddLocalInput(
+			utxo.PrevTx, prevOut.Index, maxInteractiveTxSequence,
+		)
+		if err != nil {
+			return err
+		}
+		flow.pendingMsgs = append(flow.pendingMsgs, msg)
+	}
+
+	txOuts := ourContribution.ChangeOutputs
+	if isInitiator {
+		txOuts = append(txOuts, fundingOutput)
+	}
+	for _, txOut := range txOuts {
+		msg, err := flow.tx.AddLocalOutput(txOut)
+		if err != nil {
+			return err
+		}
+		flow.pendingMsgs = append(flow.pendingMsgs, msg)
+	}
+
+	f.resMtx.Lock()
+	defer f.resMtx.Unlock()
+
+	if _, ok := f.dualFundFlows[flow.chanID]; ok {
+		return fmt.Errorf("dual funded channel %v already pending",
+			flow.chanID)
+	}
+	f.dualFundFlows[flow.chanID] = flow
+
+	return nil
+}
+
+// getDualFundFlow returns the dual funded channel flow with the given channel
+// ID, if the peer is the remote party of that channel.
+func (f *Manager) getDualFundFlow(peer lnpeer.Peer,
+	chanID lnwire.ChannelID) (*dualFundFlow, error) {
+
+	f.resMtx.RLock()
+	flow, ok := f.dualFundFlows[chanID]
+	f.resMtx.RUnlock()
+
+	if !ok || !flow.remoteKey().IsEqual(peer.IdentityKey()) {
+		return nil, fmt.Errorf("unknown dual funded channel %v for "+
+			"peer(%x)", chanID,
+			peer.IdentityKey().SerializeCompressed())
+	}
+
+	return flow, nil
+}
+
+// failDualFundFlow forgets about a dual funded channel flow and fails its
+// funding flow with the remote party.
+func (f *Manager) failDualFundFlow(flow *dualFundFlow, err error) {
+	log.Errorf("Failing dual funded channel %v for pending_id(%x): %v",
+		flow.chanID, flow.pendingChanID[:], err)
+
+	f.resMtx.Lock()
+	delete(f.dualFundFlows, flow.chanID)
+	f.resMtx.Unlock()
+
+	f.failFundingFlow(flow.resCtx.peer, flow.pendingChanID, err)
+}
+
+// sendInteractiveTxMsg sends our next message of the interactive construction
+// of the funding transaction. Once all our inputs and outputs are added, we
+// signal that we're done with a tx_complete message.
+func (f *Manager) sendInteractiveTxMsg(flow *dualFundFlow) error {
+	var msg lnwire.Message
+	if len(flow.pendingMsgs) > 0 {
+		msg = flow.pendingMsgs[0]
+		flow.pendingMsgs = flow.pendingMsgs[1:]
+
+		// Any change to the transaction restarts the final round of
+		// tx_complete messages.
+		flow.tx.resetComplete()
+	} else {
+		msg = flow.tx.LocalComplete()
+	}
+	flow.ourTurn = false
+
+	return flow.resCtx.peer.SendMessage(true, msg)
+}
+
+// interactiveTxChanID returns the channel ID of an interactive transaction
+// construction message.
+func interactiveTxChanID(msg lnwire.Message) lnwire.ChannelID {
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		return msg.ChanID
+	case *lnwire.TxAddOutput:
+		return msg.ChanID
+	case *lnwire.TxRemoveInput:
+		return msg.ChanID
+	case *lnwire.TxRemoveOutput:
+		return msg.ChanID
+	case *lnwire.TxComplete:
+		return msg.ChanID
+	default:
+		return lnwire.ChannelID{}
+	}
+}
+
+// handleInteractiveTxMsg processes a message of the interactive construction
+// of the funding transaction sent by the remote party, and replies with our
+// next message. Both parties take turns until both sent a tx_complete message
+// in a row, after which the commitment transactions are signed.
+func (f *Manager) handleInteractiveTxMsg(peer lnpeer.Peer,
+	msg lnwire.Message) {
+
+	flow, err := f.getDualFundFlow(peer, interactiveTxChanID(msg))
+	if err != nil {
+		log.Warnf("Received %v for unknown channel: %v", msg.MsgType(),
+			err)
+		return
+	}
+	defer flow.resCtx.updateTimestamp()
+
+	// Each party may only send a single message per turn, and none once
+	// the transaction is complete.
+	if flow.ourTurn || flow.fundingTx != nil {
+		f.failDualFundFlow(flow, fmt.Errorf("unexpected %v",
+			msg.MsgType()))
+		return
+	}
+
+	if err := flow.tx.ProcessMsg(msg); err != nil {
+		f.failDualFundFlow(flow, err)
+		return
+	}
+	flow.ourTurn = true
+
+	// If the remote party's tx_complete follows ours, the transaction is
+	// complete. Otherwise, it's our turn to reply, which may complete the
+	// transaction as well.
+	if !flow.tx.IsComplete() {
+		if err := f.sendInteractiveTxMsg(flow); err != nil {
+			f.failDualFundFlow(flow, err)
+			return
+		}
+		if !flow.tx.IsComplete() {
+			return
+		}
+	}
+
+	f.finishInteractiveTx(flow)
+}
+
+// finishInteractiveTx is called once the funding transaction is constructed.
+// After making sure the remote party pays for its contribution, our inputs and
+// the remote party's commitment transaction are signed, and our commitment
+// signature is sent to the remote party.
+func (f *Manager) finishInteractiveTx(flow *dualFundFlow) {
+	fundingTx, err := flow.tx.Tx()
+	if err != nil {
+		f.failDualFundFlow(flow, err)
+		return
+	}
+
+	// The inputs the remote party added must cover the outputs it added
+	// along with its contribution to the funding output.
+	remoteAmt := flow.remoteContribution.FundingAmount
+	remoteNet := flow.tx.NetContribution(false, flow.fundingScript)
+	if remoteNet < remoteAmt {
+		f.failDualFundFlow(flow, fmt.Errorf("remote inputs fund %v, "+
+			"expected at least %v", remoteNet, remoteAmt))
+		return
+	}
+
+	log.Debugf("Constructed funding tx for dual funded channel %v: %v",
+		flow.chanID, spew.Sdump(fundingTx))
+
+	reservation := flow.resCtx.reservation
+	err = reservation.ProcessInteractiveContribution(
+		flow.remoteContribution, fundingTx,
+	)
+	if err != nil {
+		f.failDualFundFlow(flow, err)
+		return
+	}
+	flow.fundingTx = fundingTx
+
+	_, sig := reservation.OurSignatures()
+	commitSig, err := lnwire.NewSigFromSignature(sig)
+	if err != nil {
+		f.failDualFundFlow(flow, err)
+		return
+	}
+
+	log.Infof("Generated ChannelPoint(%v) for pending_id(%x)",
+		reservation.FundingOutpoint(), flow.pendingChanID[:])
+
+	err = flow.resCtx.peer.SendMessage(true, &lnwire.CommitSig{
+		ChanID:    flow.chanID,
+		CommitSig: commitSig,
+	})
+	if err != nil {
+		f.failDualFundFlow(flow, err)
+	}
+}
+
+// handleDualFundCommitSig processes the remote party's signature for our
+// initial commitment transaction of a dual funded channel. Once it's verified
+// the channel is stored as pending along with the state needed to exchange
+// the signatures of the inputs to the funding transaction, and ours are sent
+// if it's our turn to send them first.
+func (f *Manager) handleDualFundCommitSig(peer lnpeer.Peer,
+	msg *lnwire.CommitSig) {
+
+	flow, err := f.getDualFundFlow(peer, msg.ChanID)
+	if err != nil {
+		log.Warnf("Received commitment signature for unknown "+
+			"channel: %v", err)
+		return
+	}
+
+	if flow.fundingTx == nil || flow.completeChan != nil {
+		f.failDualFundFlow(flow, fmt.Errorf("unexpected commitment "+
+			"signature"))
+		return
+	}
+
+	commitSig, err := msg.CommitSig.ToSignature()
+	if err != nil {
+		f.failDualFundFlow(flow, err)
+		return
+	}
+
+	// The state of the signature exchange is derived from the constructed
+	// transaction, which isn't stored along with the channel.
+	txSigs := &dualFundTxSigs{
+		chanID:        flow.chanID,
+		pendingChanID: flow.pendingChanID,
+		localInputs:   flow.tx.LocalInputs(),
+		remoteInputs:  flow.tx.RemoteInputs(),
+		sendsFirst:    f.sendsTxSignaturesFirst(flow),
+	}
+	for _, prevOut := range txSigs.remoteInputs {
+		output, ok := flow.tx.PrevOutput(prevOut)
+		if !ok {
+			f.failDualFundFlow(flow, fmt.Errorf("unknown remote "+
+				"input %v", prevOut))
+			return
+		}
+		txSigs.remotePrevOuts = append(txSigs.remotePrevOuts, output)
+	}
+
+	// Completing the reservation verifies their signature and stores the
+	// channel as pending. The signatures of the remote party's inputs are
+	// only exchanged after this.
+	reservation := flow.resCtx.reservation
+	completeChan, err := reservation.CompleteReservation(nil, commitSig)
+	if err != nil {
+		f.failDualFundFlow(flow, err)
+		return
+	}
+
+	// The channel is marked IsPending in the database, and can be removed
+	// from the set of active reservations.
+	f.deleteReservationCtx(peer.IdentityKey(), flow.pendingChanID)
+
+	// We persist the state of the signature exchange, so it can be
+	// resumed if we disconnect or restart before it's complete. If that
+	// fails, nobody can sign the funding transaction yet, so we can still
+	// back out of the channel.
+	fundingPoint := completeChan.FundingOutpoint
+	if err := f.saveDualFundTxSigs(&fundingPoint, txSigs); err != nil {
+		f.failDualFundFlow(flow, err)
+		f.cancelDualFundChannel(completeChan)
+		return
+	}
+
+	f.resMtx.Lock()
+	flow.completeChan = completeChan
+	flow.txSigs = txSigs
+	flow.done = make(chan struct{})
+	f.resMtx.Unlock()
+
+	// Past this point, the channel is referenced by the channel ID
+	// derived from its funding outpoint, as with single funded channels.
+	// We register the barrier and local discovery signal for it now, so
+	// the link and the funding locked message wait for the channel to be
+	// fully open.
+	f.addDualFundBarriers(completeChan)
+
+	f.wg.Add(1)
+	go f.resumeDualFundTxSigs(flow, peer)
+
+	if !txSigs.sendsFirst {
+		return
+	}
+	if err := f.sendTxSignatures(peer, flow); err != nil {
+		log.Warnf("Unable to send tx signatures for ChannelPoint(%v), "+
+			"will retry when the peer reconnects: %v", fundingPoint,
+			err)
+	}
+}
+
+// addDualFundBarriers creates the channel barrier and local discovery signal
+// of a dual funded channel that is stored as pending.
+func (f *Manager) addDualFundBarriers(channel *channeldb.OpenChannel) {
+	chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+
+	f.barrierMtx.Lock()
+	log.Debugf("Creating chan barrier for ChanID(%v)", chanID)
+	f.newChanBarriers[chanID] = make(chan struct{})
+	f.barrierMtx.Unlock()
+
+	f.localDiscoveryMtx.Lock()
+	f.localDiscoverySignals[chanID] = make(chan struct{})
+	f.localDiscoveryMtx.Unlock()
+}
+
+// sendsTxSignaturesFirst returns true if we must send the signatures of our
+// inputs before the remote party sends theirs. The party that contributed
+// the lower input amount goes first, or the one with the lower node ID if both
+// contributed the same amount.
+func (f *Manager) sendsTxSignaturesFirst(flow *dualFundFlow) bool {
+	ourAmt := flow.tx.InputAmount(true)
+	theirAmt := flow.tx.InputAmount(false)
+	if ourAmt != theirAmt {
+		return ourAmt < theirAmt
+	}
+
+	ourKey := f.cfg.IDKey.SerializeCompressed()
+	theirKey := flow.remoteKey().SerializeCompressed()
+
+	return bytes.Compare(ourKey, theirKey) < 0
+}
+
+// sendTxSignatures sends the witnesses of our inputs to the funding
+// transaction, ordered by the serial IDs of the inputs. Before the first
+// time they're sent, the funding transaction is watched for confirmation, as
+// the remote party can broadcast it from then on.
+func (f *Manager) sendTxSignatures(peer lnpeer.Peer,
+	flow *dualFundFlow) error {
+
+	fundingTx := flow.completeChan.FundingTxn
+	txSigs := flow.txSigs
+
+	inputIndex := make(map[wire.OutPoint]int, len(fundingTx.TxIn))
+	for i, txIn := range fundingTx.TxIn {
+		inputIndex[txIn.PreviousOutPoint] = i
+	}
+
+	msg := &lnwire.TxSignatures{
+		ChanID: flow.chanID,
+		TxHash: fundingTx.TxHash(),
+	}
+	for _, prevOut := range txSigs.localInputs {
+		witness := fundingTx.TxIn[inputIndex[prevOut]].Witness
+		msg.Witnesses = append(msg.Witnesses, witness)
+	}
+
+	if !txSigs.sigsSent {
+		txSigs.sigsSent = true
+
+		fundingPoint := flow.completeChan.FundingOutpoint
+		err := f.saveDualFundTxSigs(&fundingPoint, txSigs)
+		if err != nil {
+			txSigs.sigsSent = false
+			return err
+		}
+
+		f.watchDualFundTx(flow)
+	}
+
+	return peer.SendMessage(true, msg)
+}
+
+// watchDualFundTx hands a dual funded channel over to the same steps a single
+// funded channel goes through once its funding transaction is ready to be
+// broadcast: the channel is watched on chain and the funding state machine
+// waits for the funding transaction to confirm.
+func (f *Manager) watchDualFundTx(flow *dualFundFlow) {
+	completeChan := flow.completeChan
+	fundingPoint := completeChan.FundingOutpoint
+
+	if err := f.cfg.WatchNewChannel(
+		completeChan, completeChan.IdentityPub,
+	); err != nil {
+		log.Errorf("Unable to send new ChannelPoint(%v) for "+
+			"arbitration: %v", fundingPoint, err)
+	}
+
+	// Only the initiator has a caller waiting for updates, unless the
+	// flow was resumed after a restart.
+	var updates chan *lnrpc.OpenStatusUpdate
+	if flow.resCtx != nil {
+		updates = flow.resCtx.updates
+	}
+	if updates != nil {
+		upd := &lnrpc.OpenStatusUpdate{
+			Update: &lnrpc.OpenStatusUpdate_ChanPending{
+				ChanPending: &lnrpc.PendingUpdate{
+					Txid:        fundingPoint.Hash[:],
+					OutputIndex: fundingPoint.Index,
+				},
+			},
+			PendingChanId: flow.pendingChanID[:],
+		}
+
+		select {
+		case updates <- upd:
+		case <-f.quit:
+			return
+		}
+	}
+
+	f.cfg.NotifyPendingOpenChannelEvent(fundingPoint, completeChan)
+
+	f.wg.Add(1)
+	go f.advanceFundingState(completeChan, flow.pendingChanID, updates)
+}
+
+// handleTxSignatures processes the witnesses of the remote party's inputs to
+// the funding transaction of a dual funded channel. Once they are verified,
+// we send ours if we haven't already, and the now fully signed funding
+// transaction is broadcast. The channel then proceeds like a single funded
+// channel, waiting for the funding transaction to confirm.
+func (f *Manager) handleTxSignatures(peer lnpeer.Peer,
+	msg *lnwire.TxSignatures) {
+
+	flow, err := f.getDualFundFlow(peer, msg.ChanID)
+	if err != nil {
+		log.Warnf("Received tx signatures for unknown channel: %v", err)
+		return
+	}
+
+	if flow.completeChan == nil {
+		f.failDualFundFlow(flow, fmt.Errorf("received tx signatures "+
+			"before commitment signature"))
+		return
+	}
+
+	fundingTx, err := f.verifyTxSignatures(flow, msg)
+	if err != nil {
+		f.abortDualFundTxSigs(flow, err)
+
+		errMsg := &lnwire.Error{
+			ChanID: flow.chanID,
+			Data:   lnwire.ErrorData(err.Error()),
+		}
+		if err := peer.SendMessage(false, errMsg); err != nil {
+			log.Errorf("Unable to send error message to peer: %v",
+				err)
+		}
+		return
+	}
+
+	// If the remote party signed first, it's our turn now. We have all
+	// signatures at this point, so we broadcast the funding transaction
+	// even if the remote party doesn't get ours.
+	if !flow.txSigs.sigsSent {
+		if err := f.sendTxSignatures(peer, flow); err != nil {
+			log.Warnf("Unable to send tx signatures for "+
+				"ChannelPoint(%v): %v",
+				flow.completeChan.FundingOutpoint, err)
+		}
+	}
+
+	completeChan := flow.completeChan
+	fundingPoint := completeChan.FundingOutpoint
+	if err := completeChan.UpdateFundingTxn(fundingTx); err != nil {
+		log.Errorf("Unable to store funding tx for ChannelPoint(%v): "+
+			"%v", fundingPoint, err)
+	}
+	f.forgetDualFundTxSigs(completeChan)
+
+	log.Infof("Broadcasting dual funded tx for ChannelPoint(%v)",
+		fundingPoint)
+
+	// Both parties broadcast the funding transaction, as both have
+	// funds at stake.
+	label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
+	if err := f.cfg.PublishTransaction(fundingTx, label); err != nil {
+		log.Errorf("Unable to broadcast funding tx for "+
+			"ChannelPoint(%v): %v", fundingPoint, err)
+	}
+}
+
+// verifyTxSignatures adds the remote party's witnesses to a copy of the
+// funding transaction and returns it, once each of them is verified.
+func (f *Manager) verifyTxSignatures(flow *dualFundFlow,
+	msg *lnwire.TxSignatures) (*wire.MsgTx, error) {
+
+	fundingTx := flow.completeChan.FundingTxn.Copy()
+	if msg.TxHash != fundingTx.TxHash() {
+		return nil, fmt.Errorf("tx signatures for tx %v, expected %v",
+			msg.TxHash, fundingTx.TxHash())
+	}
+
+	txSigs := flow.txSigs
+	if len(msg.Witnesses) != len(txSigs.remoteInputs) {
+		return nil, fmt.Errorf("received %d witnesses for %d inputs",
+			len(msg.Witnesses), len(txSigs.remoteInputs))
+	}
+
+	inputIndex := make(map[wire.OutPoint]int, len(fundingTx.TxIn))
+	for i, txIn := range fundingTx.TxIn {
+		inputIndex[txIn.PreviousOutPoint] = i
+	}
+	for i, prevOut := range txSigs.remoteInputs {
+		fundingTx.TxIn[inputIndex[prevOut]].Witness = msg.Witnesses[i]
+	}
+
+	// Make sure each of the remote party's witnesses is valid, as we
+	// couldn't publish the funding transaction otherwise.
+	hashCache := txscript.NewTxSigHashes(fundingTx)
+	for i, prevOut := range txSigs.remoteInputs {
+		output := txSigs.remotePrevOuts[i]
+		vm, err := txscript.NewEngine(
+			output.PkScript, fundingTx, inputIndex[prevOut],
+			txscript.StandardVerifyFlags, nil, hashCache,
+			output.Value,
+		)
+		if err == nil {
+			err = vm.Execute()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid witness for input "+
+				"%v: %v", prevOut, err)
+		}
+	}
+
+	return fundingTx, nil
+}
+
+// abortDualFundTxSigs gives up on the signature exchange of a dual funded
+// channel. If we haven't sent the signatures of our inputs yet, the funding
+// transaction can't be broadcast, so the pending channel is deleted.
+// Otherwise the remote party may still broadcast it, and the channel stays
+// pending until the funding transaction confirms or the funding flow times
+// out.
+func (f *Manager) abortDualFundTxSigs(flow *dualFundFlow, err error) {
+
+	completeChan := flow.completeChan
+
+	log.Errorf("Aborting tx signatures exchange for ChannelPoint(%v): %v",
+		completeChan.FundingOutpoint, err)
+
+	sigsSent := flow.txSigs.sigsSent
+	f.forgetDualFundTxSigs(completeChan)
+	if !sigsSent {
+		f.cancelDualFundChannel(completeChan)
+	}
+}
+
+// cancelDualFundChannel deletes a pending dual funded channel whose funding
+// transaction can't be broadcast, as we never sent the signatures of our
+// inputs.
+func (f *Manager) cancelDualFundChannel(channel *channeldb.OpenChannel) {
+	localBalance := channel.LocalCommitment.LocalBalance.ToSatoshis()
+	closeInfo := &channeldb.ChannelCloseSummary{
+		ChanPoint:               channel.FundingOutpoint,
+		ChainHash:               channel.ChainHash,
+		RemotePub:               channel.IdentityPub,
+		CloseType:               channeldb.FundingCanceled,
+		Capacity:                channel.Capacity,
+		SettledBalance:          localBalance,
+		RemoteCurrentRevocation: channel.RemoteCurrentRevocation,
+		RemoteNextRevocation:    channel.RemoteNextRevocation,
+		LocalChanConfig:         channel.LocalChanCfg,
+	}
+
+	// Close the channel with us as the initiator because we are
+	// deciding to exit the funding flow.
+	if err := channel.CloseChannel(
+		closeInfo, channeldb.ChanStatusLocalCloseInitiator,
+	); err != nil {
+		log.Errorf("Failed closing channel %v: %v",
+			channel.FundingOutpoint, err)
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+
+	f.barrierMtx.Lock()
+	delete(f.newChanBarriers, chanID)
+	f.barrierMtx.Unlock()
+
+	f.localDiscoveryMtx.Lock()
+	delete(f.localDiscoverySignals, chanID)
+	f.localDiscoveryMtx.Unlock()
+}
+
+// dualFundResume is sent to the reservation coordinator when the remote party
+// of a dual funded channel awaiting the exchange of tx signatures comes
+// online.
+type dualFundResume struct {
+	flow *dualFundFlow
+	peer lnpeer.Peer
+}
+
+// resumeDualFundTxSigs resumes the exchange of the signatures of the inputs
+// to the funding transaction of a dual funded channel each time the remote
+// party comes back online, until the exchange is complete. If the peer is
+// set, the remote party is online already, and we wait for it to disconnect
+// first.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *Manager) resumeDualFundTxSigs(flow *dualFundFlow, peer lnpeer.Peer) {
+	defer f.wg.Done()
+
+	var peerKey [33]byte
+	copy(peerKey[:], flow.remoteKey().SerializeCompressed())
+
+	for {
+		if peer != nil {
+			select {
+			case <-peer.QuitSignal():
+			case <-flow.done:
+				return
+			case <-f.quit:
+				return
+			}
+		}
+
+		peerChan := make(chan lnpeer.Peer, 1)
+		f.cfg.NotifyWhenOnline(peerKey, peerChan)
+
+		select {
+		case peer = <-peerChan:
+		case <-flow.done:
+			return
+		case <-f.quit:
+			return
+		}
+
+		select {
+		case f.dualFundResumes <- &dualFundResume{
+			flow: flow,
+			peer: peer,
+		}:
+		case <-flow.done:
+			return
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// handleDualFundResume resends the signatures of our inputs to the funding
+// transaction of a dual funded channel once the remote party is back online,
+// if we either sent them before or are the first to send them. Otherwise we
+// keep waiting for the remote party to send theirs.
+func (f *Manager) handleDualFundResume(resume *dualFundResume) {
+	flow := resume.flow
+
+	// The exchange may have completed while the peer reconnected.
+	f.resMtx.RLock()
+	_, ok := f.dualFundFlows[flow.chanID]
+	f.resMtx.RUnlock()
+	if !ok {
+		return
+	}
+
+	if !flow.txSigs.sigsSent && !flow.txSigs.sendsFirst {
+		return
+	}
+
+	log.Infof("Peer(%x) is online, resending tx signatures for "+
+		"ChannelPoint(%v)", flow.remoteKey().SerializeCompressed(),
+		flow.completeChan.FundingOutpoint)
+
+	if err := f.sendTxSignatures(resume.peer, flow); err != nil {
+		log.Warnf("Unable to resend tx signatures for "+
+			"ChannelPoint(%v): %v",
+			flow.completeChan.FundingOutpoint, err)
+	}
+}
+
+// restoreDualFundTxSigs restores the exchange of tx signatures of a pending
+// dual funded channel after a restart, and returns its state. If the exchange
+// was completed before, nil is returned.
+func (f *Manager) restoreDualFundTxSigs(
+	channel *channeldb.OpenChannel) (*dualFundTxSigs, error) {
+
+	txSigs, err := f.fetchDualFundTxSigs(&channel.FundingOutpoint)
+	switch {
+	case err == ErrChannelNotFound:
+		return nil, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	flow := &dualFundFlow{
+		chanID:        txSigs.chanID,
+		pendingChanID: txSigs.pendingChanID,
+		completeChan:  channel,
+		txSigs:        txSigs,
+		done:          make(chan struct{}),
+	}
+
+	f.resMtx.Lock()
+	f.dualFundFlows[flow.chanID] = flow
+	f.resMtx.Unlock()
+
+	log.Infof("Restored tx signatures exchange for ChannelPoint(%v)",
+		channel.FundingOutpoint)
+
+	f.wg.Add(1)
+	go f.resumeDualFundTxSigs(flow, nil)
+
+	return txSigs, nil
+}
+
+// forgetDualFundTxSigs removes the state of the tx signatures exchange of a
+// dual funded channel, once it's complete or given up on.
+func (f *Manager) forgetDualFundTxSigs(channel *channeldb.OpenChannel) {
+	err := f.deleteDualFundTxSigs(&channel.FundingOutpoint)
+	if err != nil && err != ErrChannelNotFound {
+		log.Errorf("Unable to delete tx signatures state of "+
+			"ChannelPoint(%v): %v", channel.FundingOutpoint, err)
+	}
+
+	f.resMtx.Lock()
+	defer f.resMtx.Unlock()
+
+	for chanID, flow := range f.dualFundFlows {
+		if flow.completeChan == nil ||
+			flow.completeChan.FundingOutpoint !=
+				channel.FundingOutpoint {
+
+			continue
+		}
+
+		delete(f.dualFundFlows, chanID)
+		close(flow.done)
+	}
+}
+
+// dualFundTxSigs is the state of a dual funded channel between storing it as
+// pending and exchanging the signatures of both parties' inputs to the
+// funding transaction. It's persisted, so the exchange can be resumed after a
+// disconnect or restart.
+type dualFundTxSigs struct {
+	// chanID is the channel ID used in the tx_signatures messages.
+	chanID lnwire.ChannelID
+
+	// pendingChanID is the pending channel ID of the funding flow.
+	pendingChanID [32]byte
+
+	// localInputs are our inputs, ordered by their serial IDs.
+	localInputs []wire.OutPoint
+
+	// remoteInputs are the remote party's inputs, ordered by their serial
+	// IDs.
+	remoteInputs []wire.OutPoint
+
+	// remotePrevOuts are the outputs spent by the remote party's inputs,
+	// which their witnesses are verified against.
+	remotePrevOuts []*wire.TxOut
+
+	// sendsFirst is true if we send our signatures before the remote
+	// party sends theirs.
+	sendsFirst bool
+
+	// sigsSent is true once we sent our signatures.
+	sigsSent bool
+}
+
+// encode writes the tx signatures state to the given writer.
+func (d *dualFundTxSigs) encode(w io.Writer) error {
+	err := channeldb.WriteElements(
+		w, d.chanID, d.pendingChanID, d.sendsFirst, d.sigsSent,
+		uint16(len(d.localInputs)),
+	)
+	if err != nil {
+		return err
+	}
+	for _, prevOut := range d.localInputs {
+		if err := channeldb.WriteElement(w, prevOut); err != nil {
+			return err
+		}
+	}
+
+	err = channeldb.WriteElement(w, uint16(len(d.remoteInputs)))
+	if err != nil {
+		return err
+	}
+	for i, prevOut := range d.remoteInputs {
+		output := d.remotePrevOuts[i]
+		err := channeldb.WriteElements(
+			w, prevOut, output.Value, output.PkScript,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decode reads the tx signatures state from the given reader.
+func (d *dualFundTxSigs) decode(r io.Reader) error {
+	var numLocal, numRemote uint16
+	err := channeldb.ReadElements(
+		r, &d.chanID, &d.pendingChanID, &d.sendsFirst, &d.sigsSent,
+		&numLocal,
+	)
+	if err != nil {
+		return err
+	}
+
+	d.localInputs = make([]wire.OutPoint, numLocal)
+	for i := range d.localInputs {
+		err := channeldb.ReadElement(r, &d.localInputs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := channeldb.ReadElement(r, &numRemote); err != nil {
+		return err
+	}
+
+	d.remoteInputs = make([]wire.OutPoint, numRemote)
+	d.remotePrevOuts = make([]*wire.TxOut, numRemote)
+	for i := range d.remoteInputs {
+		output := &wire.TxOut{}
+		err := channeldb.ReadElements(
+			r, &d.remoteInputs[i], &output.Value, &output.PkScript,
+		)
+		if err != nil {
+			return err
+		}
+		d.remotePrevOuts[i] = output
+	}
+
+	return nil
+}
+
+// saveDualFundTxSigs saves the tx signatures state of the dual funded channel
+// with the given funding outpoint to the dualFundTxSigsBucket.
+func (f *Manager) saveDualFundTxSigs(chanPoint *wire.OutPoint,
+	txSigs *dualFundTxSigs) error {
+
+	return kvdb.Update(f.cfg.Wallet.Cfg.Database, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(dualFundTxSigsBucket)
+		if err != nil {
+			return err
+		}
+
+		var outpointBytes bytes.Buffer
+		if err := WriteOutpoint(&outpointBytes, chanPoint); err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := txSigs.encode(&b); err != nil {
+			return err
+		}
+
+		return bucket.Put(outpointBytes.Bytes(), b.Bytes())
+	}, func() {})
+}
+
+// fetchDualFundTxSigs fetches the tx signatures state of the dual funded
+// channel with the given funding outpoint, or returns ErrChannelNotFound if
+// there is none.
+func (f *Manager) fetchDualFundTxSigs(
+	chanPoint *wire.OutPoint) (*dualFundTxSigs, error) {
+
+	var txSigs *dualFundTxSigs
+	err := kvdb.View(f.cfg.Wallet.Cfg.Database, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(dualFundTxSigsBucket)
+		if bucket == nil {
+			return ErrChannelNotFound
+		}
+
+		var outpointBytes bytes.Buffer
+		if err := WriteOutpoint(&outpointBytes, chanPoint); err != nil {
+			return err
+		}
+
+		value := bucket.Get(outpointBytes.Bytes())
+		if value == nil {
+			return ErrChannelNotFound
+		}
+
+		txSigs = &dualFundTxSigs{}
+		return txSigs.decode(bytes.NewReader(value))
+	}, func() {
+		txSigs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txSigs, nil
+}
+
+// deleteDualFundTxSigs removes the tx signatures state of the dual funded
+// channel with the given funding outpoint from the database.
+func (f *Manager) deleteDualFundTxSigs(chanPoint *wire.OutPoint) error {
+	return kvdb.Update(f.cfg.Wallet.Cfg.Database, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(dualFundTxSigsBucket)
+		if bucket == nil {
+			return ErrChannelNotFound
+		}
+
+		var outpointBytes bytes.Buffer
+		if err := WriteOutpoint(&outpointBytes, chanPoint); err != nil {
+			return err
+		}
+
+		return bucket.Delete(outpointBytes.Bytes())
+	}, func() {})
+}
@@ -0,0 +1,648 @@
+package funding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// maxInteractiveTxMsgs is the maximum number of tx_add_input or
+	// tx_add_output messages we'll accept from the remote party during
+	// the construction of a single transaction.
+	maxInteractiveTxMsgs = 4096
+
+	// maxInteractiveTxInOuts is the maximum number of inputs or outputs
+	// the constructed transaction may have.
+	maxInteractiveTxInOuts = 252
+
+	// maxInteractiveTxSequence is the maximum nSequence an input of the
+	// constructed transaction may have. Larger values would disable RBF.
+	maxInteractiveTxSequence = wire.MaxTxInSequenceNum - 2
+)
+
+var (
+	// errInvalidSerialID is returned when the remote party uses a serial
+	// ID with the wrong parity or a serial ID that is already in use.
+	errInvalidSerialID = errors.New("invalid serial id")
+
+	// errUnknownSerialID is returned when the remote party tries to remove
+	// an input or output that it didn't add.
+	errUnknownSerialID = errors.New("unknown serial id")
+
+	// errTooManyInteractiveMsgs is returned when the remote party sends
+	// too many tx_add_input or tx_add_output messages.
+	errTooManyInteractiveMsgs = errors.New("too many interactive tx " +
+		"messages")
+
+	// errDuplicateInput is returned when an input spending an already
+	// spent outpoint is added.
+	errDuplicateInput = errors.New("duplicate input")
+
+	// errInteractiveTxNotComplete is returned when the transaction is
+	// requested before both parties signaled completion.
+	errInteractiveTxNotComplete = errors.New("interactive tx " +
+		"construction not complete")
+)
+
+// interactiveInput is an input added to the transaction under construction.
+type interactiveInput struct {
+	// prevOut is the outpoint being spent.
+	prevOut wire.OutPoint
+
+	// txOut is the output being spent.
+	txOut *wire.TxOut
+
+	// sequence is the nSequence of the input.
+	sequence uint32
+
+	// local denotes whether we added the input.
+	local bool
+
+	// shared denotes whether the input spends the funding output of the
+	// channel being spliced, which is owned by both parties.
+	shared bool
+}
+
+// interactiveOutput is an output added to the transaction under
+// construction.
+type interactiveOutput struct {
+	// txOut is the output itself.
+	txOut *wire.TxOut
+
+	// local denotes whether we added the output.
+	local bool
+}
+
+// interactiveTx tracks the state of the interactive construction of a
+// funding transaction as defined by the v2 channel establishment protocol.
+// Both parties add and remove inputs and outputs, each identified by a serial
+// ID, until both sent a tx_complete message in a row. The inputs and outputs
+// of the final transaction are sorted by their serial ID.
+type interactiveTx struct {
+	chanID      lnwire.ChannelID
+	isInitiator bool
+	lockTime    uint32
+	dustLimit   btcutil.Amount
+
+	inputs  map[uint64]*interactiveInput
+	outputs map[uint64]*interactiveOutput
+
+	// sharedInput is the funding output of the channel being spliced, if
+	// any. The initiator of a splice adds it as the shared input without
+	// sending its previous transaction, as both parties know it.
+	sharedInput *interactiveInput
+
+	// nextSerialID is the next serial ID we'll use for a local input or
+	// output. The initiator uses even serial IDs, the non-initiator odd
+	// ones.
+	nextSerialID uint64
+
+	// numRemoteInputs and numRemoteOutputs count the tx_add_input and
+	// tx_add_output messages received from the remote party.
+	numRemoteInputs  int
+	numRemoteOutputs int
+
+	localComplete  bool
+	remoteComplete bool
+}
+
+// newInteractiveTx creates a new interactive transaction construction for the
+// given channel.
+func newInteractiveTx(chanID lnwire.ChannelID, isInitiator bool,
+	lockTime uint32, dustLimit btcutil.Amount) *interactiveTx {
+
+	nextSerialID := uint64(1)
+	if isInitiator {
+		nextSerialID = 0
+	}
+
+	return &interactiveTx{
+		chanID:       chanID,
+		isInitiator:  isInitiator,
+		lockTime:     lockTime,
+		dustLimit:    dustLimit,
+		inputs:       make(map[uint64]*interactiveInput),
+		outputs:      make(map[uint64]*interactiveOutput),
+		nextSerialID: nextSerialID,
+	}
+}
+
+// isRemoteSerialID returns true if the serial ID has the parity the remote
+// party must use.
+func (i *interactiveTx) isRemoteSerialID(serialID uint64) bool {
+	isEven := serialID%2 == 0
+	return isEven != i.isInitiator
+}
+
+// newSerialID returns the next local serial ID.
+func (i *interactiveTx) newSerialID() uint64 {
+	serialID := i.nextSerialID
+	i.nextSerialID += 2
+
+	return serialID
+}
+
+// hasPrevOut returns true if an input already spends the given outpoint.
+func (i *interactiveTx) hasPrevOut(prevOut wire.OutPoint) bool {
+	for _, in := range i.inputs {
+		if in.prevOut == prevOut {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resetComplete resets the completion state of both parties, as any change
+// to the transaction restarts the final tx_complete round.
+func (i *interactiveTx) resetComplete() {
+	i.localComplete = false
+	i.remoteComplete = false
+}
+
+// AddLocalInput adds an input spending the given output of prevTx and returns
+// the message that must be sent to the remote party.
+func (i *interactiveTx) AddLocalInput(prevTx *wire.MsgTx, prevTxOut uint32,
+	sequence uint32) (*lnwire.TxAddInput, error) {
+
+	var b bytes.Buffer
+	if err := prevTx.Serialize(&b); err != nil {
+		return nil, err
+	}
+
+	msg := &lnwire.TxAddInput{
+		ChanID:    i.chanID,
+		SerialID:  i.newSerialID(),
+		PrevTx:    b.Bytes(),
+		PrevTxOut: prevTxOut,
+		Sequence:  sequence,
+	}
+
+	in, err := i.parseInput(msg)
+	if err != nil {
+		return nil, err
+	}
+	in.local = true
+
+	i.inputs[msg.SerialID] = in
+	i.resetComplete()
+
+	return msg, nil
+}
+
+// SetSharedInput sets the funding output of the channel being spliced. It
+// must be called by both parties before the construction starts. Only the
+// initiator adds the shared input to the transaction using AddSharedInput.
+func (i *interactiveTx) SetSharedInput(prevOut wire.OutPoint,
+	txOut *wire.TxOut) {
+
+	i.sharedInput = &interactiveInput{
+		prevOut:  prevOut,
+		txOut:    txOut,
+		sequence: maxInteractiveTxSequence,
+		shared:   true,
+	}
+}
+
+// AddSharedInput adds the shared input set by SetSharedInput and returns the
+// message that must be sent to the remote party.
+func (i *interactiveTx) AddSharedInput() (*lnwire.TxAddInput, error) {
+	if i.sharedInput == nil || !i.isInitiator {
+		return nil, errors.New("no shared input to add")
+	}
+	if i.hasPrevOut(i.sharedInput.prevOut) {
+		return nil, errDuplicateInput
+	}
+
+	txid := i.sharedInput.prevOut.Hash
+	msg := &lnwire.TxAddInput{
+		ChanID:          i.chanID,
+		SerialID:        i.newSerialID(),
+		PrevTxOut:       i.sharedInput.prevOut.Index,
+		Sequence:        i.sharedInput.sequence,
+		SharedInputTxid: &txid,
+	}
+
+	in := *i.sharedInput
+	in.local = true
+
+	i.inputs[msg.SerialID] = &in
+	i.resetComplete()
+
+	return msg, nil
+}
+
+// SharedInputIndex returns the index of the shared input within the
+// transaction returned by Tx.
+func (i *interactiveTx) SharedInputIndex(tx *wire.MsgTx) (int, bool) {
+	if i.sharedInput == nil {
+		return 0, false
+	}
+
+	for idx, txIn := range tx.TxIn {
+		if txIn.PreviousOutPoint == i.sharedInput.prevOut {
+			return idx, true
+		}
+	}
+
+	return 0, false
+}
+
+// AddLocalOutput adds the given output and returns the message that must be
+// sent to the remote party.
+func (i *interactiveTx) AddLocalOutput(
+	txOut *wire.TxOut) (*lnwire.TxAddOutput, error) {
+
+	msg := &lnwire.TxAddOutput{
+		ChanID:   i.chanID,
+		SerialID: i.newSerialID(),
+		Amount:   btcutil.Amount(txOut.Value),
+		PkScript: txOut.PkScript,
+	}
+
+	if err := i.validateOutput(msg); err != nil {
+		return nil, err
+	}
+
+	i.outputs[msg.SerialID] = &interactiveOutput{
+		txOut: txOut,
+		local: true,
+	}
+	i.resetComplete()
+
+	return msg, nil
+}
+
+// RemoveLocalInput removes an input we previously added and returns the
+// message that must be sent to the remote party.
+func (i *interactiveTx) RemoveLocalInput(
+	serialID uint64) (*lnwire.TxRemoveInput, error) {
+
+	in, ok := i.inputs[serialID]
+	if !ok || !in.local {
+		return nil, errUnknownSerialID
+	}
+
+	delete(i.inputs, serialID)
+	i.resetComplete()
+
+	return &lnwire.TxRemoveInput{
+		ChanID:   i.chanID,
+		SerialID: serialID,
+	}, nil
+}
+
+// RemoveLocalOutput removes an output we previously added and returns the
+// message that must be sent to the remote party.
+func (i *interactiveTx) RemoveLocalOutput(
+	serialID uint64) (*lnwire.TxRemoveOutput, error) {
+
+	out, ok := i.outputs[serialID]
+	if !ok || !out.local {
+		return nil, errUnknownSerialID
+	}
+
+	delete(i.outputs, serialID)
+	i.resetComplete()
+
+	return &lnwire.TxRemoveOutput{
+		ChanID:   i.chanID,
+		SerialID: serialID,
+	}, nil
+}
+
+// LocalComplete marks that we have no further inputs or outputs to add and
+// returns the message that must be sent to the remote party.
+func (i *interactiveTx) LocalComplete() *lnwire.TxComplete {
+	i.localComplete = true
+
+	return &lnwire.TxComplete{
+		ChanID: i.chanID,
+	}
+}
+
+// IsComplete returns true once both parties signaled that they have no
+// further inputs or outputs to add.
+func (i *interactiveTx) IsComplete() bool {
+	return i.localComplete && i.remoteComplete
+}
+
+// parseInput validates a tx_add_input message and parses the input it adds.
+func (i *interactiveTx) parseInput(
+	msg *lnwire.TxAddInput) (*interactiveInput, error) {
+
+	if _, ok := i.inputs[msg.SerialID]; ok {
+		return nil, errInvalidSerialID
+	}
+
+	// The shared input of a splice is known to both parties, so there is
+	// no previous transaction to validate.
+	if msg.SharedInputTxid != nil {
+		prevOut := wire.OutPoint{
+			Hash:  *msg.SharedInputTxid,
+			Index: msg.PrevTxOut,
+		}
+		if i.sharedInput == nil || i.sharedInput.prevOut != prevOut {
+			return nil, fmt.Errorf("unknown shared input %v",
+				prevOut)
+		}
+		if i.hasPrevOut(prevOut) {
+			return nil, errDuplicateInput
+		}
+
+		in := *i.sharedInput
+		in.sequence = msg.Sequence
+
+		return &in, nil
+	}
+
+	var prevTx wire.MsgTx
+	if err := prevTx.Deserialize(bytes.NewReader(msg.PrevTx)); err != nil {
+		return nil, fmt.Errorf("invalid prevtx: %v", err)
+	}
+
+	if int(msg.PrevTxOut) >= len(prevTx.TxOut) {
+		return nil, fmt.Errorf("prevtx_vout %d out of range",
+			msg.PrevTxOut)
+	}
+
+	// Only native segwit inputs are allowed, as their txid can't be
+	// malleated.
+	pkScript := prevTx.TxOut[msg.PrevTxOut].PkScript
+	if !txscript.IsWitnessProgram(pkScript) {
+		return nil, fmt.Errorf("input %d of %v is not native segwit",
+			msg.PrevTxOut, prevTx.TxHash())
+	}
+
+	if msg.Sequence > maxInteractiveTxSequence {
+		return nil, fmt.Errorf("invalid sequence %d", msg.Sequence)
+	}
+
+	prevOut := wire.OutPoint{
+		Hash:  prevTx.TxHash(),
+		Index: msg.PrevTxOut,
+	}
+	if i.hasPrevOut(prevOut) {
+		return nil, errDuplicateInput
+	}
+
+	return &interactiveInput{
+		prevOut:  prevOut,
+		txOut:    prevTx.TxOut[msg.PrevTxOut],
+		sequence: msg.Sequence,
+	}, nil
+}
+
+// validateOutput validates a tx_add_output message.
+func (i *interactiveTx) validateOutput(msg *lnwire.TxAddOutput) error {
+	if _, ok := i.outputs[msg.SerialID]; ok {
+		return errInvalidSerialID
+	}
+
+	if msg.Amount < i.dustLimit {
+		return fmt.Errorf("output amount %v below dust limit %v",
+			msg.Amount, i.dustLimit)
+	}
+
+	if msg.Amount > btcutil.MaxSatoshi {
+		return fmt.Errorf("output amount %v above max money",
+			msg.Amount)
+	}
+
+	class := txscript.GetScriptClass(msg.PkScript)
+	if class == txscript.NonStandardTy {
+		return fmt.Errorf("non-standard output script %x",
+			msg.PkScript)
+	}
+
+	return nil
+}
+
+// ProcessMsg processes an interactive transaction construction message
+// received from the remote party. An error is returned if the message
+// violates the protocol, in which case the negotiation must be failed.
+func (i *interactiveTx) ProcessMsg(msg lnwire.Message) error {
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		if !i.isRemoteSerialID(msg.SerialID) {
+			return errInvalidSerialID
+		}
+
+		i.numRemoteInputs++
+		if i.numRemoteInputs > maxInteractiveTxMsgs {
+			return errTooManyInteractiveMsgs
+		}
+
+		in, err := i.parseInput(msg)
+		if err != nil {
+			return err
+		}
+		i.inputs[msg.SerialID] = in
+
+	case *lnwire.TxAddOutput:
+		if !i.isRemoteSerialID(msg.SerialID) {
+			return errInvalidSerialID
+		}
+
+		i.numRemoteOutputs++
+		if i.numRemoteOutputs > maxInteractiveTxMsgs {
+			return errTooManyInteractiveMsgs
+		}
+
+		if err := i.validateOutput(msg); err != nil {
+			return err
+		}
+		i.outputs[msg.SerialID] = &interactiveOutput{
+			txOut: wire.NewTxOut(int64(msg.Amount), msg.PkScript),
+		}
+
+	case *lnwire.TxRemoveInput:
+		in, ok := i.inputs[msg.SerialID]
+		if !ok || in.local {
+			return errUnknownSerialID
+		}
+		delete(i.inputs, msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		out, ok := i.outputs[msg.SerialID]
+		if !ok || out.local {
+			return errUnknownSerialID
+		}
+		delete(i.outputs, msg.SerialID)
+
+	case *lnwire.TxComplete:
+		i.remoteComplete = true
+		return nil
+
+	default:
+		return fmt.Errorf("unexpected interactive tx message %T", msg)
+	}
+
+	i.resetComplete()
+
+	return nil
+}
+
+// sortedSerialIDs sorts the passed serial IDs in ascending order.
+func sortedSerialIDs(ids []uint64) []uint64 {
+	sort.Slice(ids, func(a, b int) bool {
+		return ids[a] < ids[b]
+	})
+
+	return ids
+}
+
+// inputSerialIDs returns the serial IDs of the inputs added by us or by the
+// remote party, in ascending order. The shared input is not included, as it
+// isn't owned by a single party.
+func (i *interactiveTx) inputSerialIDs(local bool) []uint64 {
+	var ids []uint64
+	for id, in := range i.inputs {
+		if in.local == local && !in.shared {
+			ids = append(ids, id)
+		}
+	}
+
+	return sortedSerialIDs(ids)
+}
+
+// LocalInputs returns the outpoints of the inputs we added, sorted by their
+// serial ID. This is the order in which the witnesses of our inputs are sent
+// in the tx_signatures message.
+func (i *interactiveTx) LocalInputs() []wire.OutPoint {
+	var prevOuts []wire.OutPoint
+	for _, id := range i.inputSerialIDs(true) {
+		prevOuts = append(prevOuts, i.inputs[id].prevOut)
+	}
+
+	return prevOuts
+}
+
+// RemoteInputs returns the outpoints of the inputs the remote party added,
+// sorted by their serial ID. This is the order in which the witnesses of
+// their inputs are received in the tx_signatures message.
+func (i *interactiveTx) RemoteInputs() []wire.OutPoint {
+	var prevOuts []wire.OutPoint
+	for _, id := range i.inputSerialIDs(false) {
+		prevOuts = append(prevOuts, i.inputs[id].prevOut)
+	}
+
+	return prevOuts
+}
+
+// PrevOutput returns the output spent by the input spending the given
+// outpoint.
+func (i *interactiveTx) PrevOutput(prevOut wire.OutPoint) (*wire.TxOut,
+	bool) {
+
+	for _, in := range i.inputs {
+		if in.prevOut == prevOut {
+			return in.txOut, true
+		}
+	}
+
+	return nil, false
+}
+
+// InputAmount returns the total value of the inputs added by us or by the
+// remote party. The shared input is not included.
+func (i *interactiveTx) InputAmount(local bool) btcutil.Amount {
+	var total btcutil.Amount
+	for _, in := range i.inputs {
+		if in.local == local && !in.shared {
+			total += btcutil.Amount(in.txOut.Value)
+		}
+	}
+
+	return total
+}
+
+// NetContribution returns the total value of the inputs added by us or by
+// the remote party minus the value of the outputs that party added. Outputs
+// paying to sharedScript, such as the funding output, are not counted as they
+// are funded by both parties.
+func (i *interactiveTx) NetContribution(local bool,
+	sharedScript []byte) btcutil.Amount {
+
+	total := i.InputAmount(local)
+	for _, out := range i.outputs {
+		if out.local != local ||
+			bytes.Equal(out.txOut.PkScript, sharedScript) {
+
+			continue
+		}
+
+		total -= btcutil.Amount(out.txOut.Value)
+	}
+
+	return total
+}
+
+// Tx returns the constructed transaction once both parties signaled
+// completion. The inputs and outputs are sorted by their serial ID.
+func (i *interactiveTx) Tx() (*wire.MsgTx, error) {
+	if !i.IsComplete() {
+		return nil, errInteractiveTxNotComplete
+	}
+
+	if len(i.inputs) > maxInteractiveTxInOuts ||
+		len(i.outputs) > maxInteractiveTxInOuts {
+
+		return nil, fmt.Errorf("transaction has too many inputs or " +
+			"outputs")
+	}
+
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = i.lockTime
+
+	var (
+		totalIn  btcutil.Amount
+		inputIDs []uint64
+	)
+	for id := range i.inputs {
+		inputIDs = append(inputIDs, id)
+	}
+	for _, id := range sortedSerialIDs(inputIDs) {
+		in := i.inputs[id]
+		txIn := wire.NewTxIn(&in.prevOut, nil, nil)
+		txIn.Sequence = in.sequence
+		tx.AddTxIn(txIn)
+
+		totalIn += btcutil.Amount(in.txOut.Value)
+	}
+
+	var (
+		totalOut  btcutil.Amount
+		outputIDs []uint64
+	)
+	for id := range i.outputs {
+		outputIDs = append(outputIDs, id)
+	}
+	for _, id := range sortedSerialIDs(outputIDs) {
+		txOut := i.outputs[id].txOut
+		tx.AddTxOut(txOut)
+
+		totalOut += btcutil.Amount(txOut.Value)
+	}
+
+	if totalIn < totalOut {
+		return nil, fmt.Errorf("total input amount %v below total "+
+			"output amount %v", totalIn, totalOut)
+	}
+
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(tx))
+	if weight > blockchain.MaxBlockWeight/10 {
+		return nil, fmt.Errorf("transaction weight %d too large",
+			weight)
+	}
+
+	return tx, nil
+}
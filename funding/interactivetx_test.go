@@ -0,0 +1,289 @@
+package funding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// testP2WKHScript is a p2wkh script used for the inputs and outputs of
+	// the interactive tx tests.
+	testP2WKHScript = append([]byte{0x00, 0x14}, bytes.Repeat(
+		[]byte{0x01}, 20,
+	)...)
+
+	// testInteractiveChanID is the channel ID used in the interactive tx
+	// tests.
+	testInteractiveChanID = lnwire.ChannelID{1}
+)
+
+// newPrevTx creates a transaction with a single p2wkh output of the given
+// value that can be spent by an interactive tx input.
+func newPrevTx(value int64, lockTime uint32) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{})
+	tx.AddTxOut(wire.NewTxOut(value, testP2WKHScript))
+	tx.LockTime = lockTime
+
+	return tx
+}
+
+// remoteInput creates a tx_add_input message spending the first output of
+// prevTx.
+func remoteInput(t *testing.T, serialID uint64,
+	prevTx *wire.MsgTx) *lnwire.TxAddInput {
+
+	var b bytes.Buffer
+	require.NoError(t, prevTx.Serialize(&b))
+
+	return &lnwire.TxAddInput{
+		ChanID:   testInteractiveChanID,
+		SerialID: serialID,
+		PrevTx:   b.Bytes(),
+		Sequence: wire.MaxTxInSequenceNum - 2,
+	}
+}
+
+// TestInteractiveTxConstruction tests that inputs and outputs added by both
+// parties end up in the final transaction sorted by their serial ID.
+func TestInteractiveTxConstruction(t *testing.T) {
+	t.Parallel()
+
+	const lockTime = 100
+	initiator := newInteractiveTx(testInteractiveChanID, true, lockTime, 354)
+	responder := newInteractiveTx(testInteractiveChanID, false, lockTime, 354)
+
+	// The initiator adds an input and an output.
+	prevTx1 := newPrevTx(100_000, 1)
+	addInput, err := initiator.AddLocalInput(
+		prevTx1, 0, wire.MaxTxInSequenceNum-2,
+	)
+	require.NoError(t, err)
+	require.Zero(t, addInput.SerialID%2)
+	require.NoError(t, responder.ProcessMsg(addInput))
+
+	addOutput, err := initiator.AddLocalOutput(
+		wire.NewTxOut(150_000, testP2WKHScript),
+	)
+	require.NoError(t, err)
+	require.NoError(t, responder.ProcessMsg(addOutput))
+
+	// The responder adds an input, which it then removes and replaces
+	// with another one.
+	prevTx2 := newPrevTx(60_000, 2)
+	addInput, err = responder.AddLocalInput(
+		prevTx2, 0, wire.MaxTxInSequenceNum-2,
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), addInput.SerialID%2)
+	require.NoError(t, initiator.ProcessMsg(addInput))
+
+	removeInput, err := responder.RemoveLocalInput(addInput.SerialID)
+	require.NoError(t, err)
+	require.NoError(t, initiator.ProcessMsg(removeInput))
+
+	_, err = responder.RemoveLocalInput(addInput.SerialID)
+	require.Equal(t, errUnknownSerialID, err)
+
+	prevTx3 := newPrevTx(70_000, 3)
+	addInput, err = responder.AddLocalInput(
+		prevTx3, 0, wire.MaxTxInSequenceNum-2,
+	)
+	require.NoError(t, err)
+	require.NoError(t, initiator.ProcessMsg(addInput))
+
+	// Both parties now signal completion.
+	require.NoError(t, responder.ProcessMsg(initiator.LocalComplete()))
+	require.False(t, initiator.IsComplete())
+	require.True(t, responder.remoteComplete)
+
+	_, err = initiator.Tx()
+	require.Equal(t, errInteractiveTxNotComplete, err)
+
+	require.NoError(t, initiator.ProcessMsg(responder.LocalComplete()))
+	require.True(t, initiator.IsComplete())
+	require.True(t, responder.IsComplete())
+
+	initiatorTx, err := initiator.Tx()
+	require.NoError(t, err)
+	responderTx, err := responder.Tx()
+	require.NoError(t, err)
+	require.Equal(t, initiatorTx.TxHash(), responderTx.TxHash())
+
+	require.EqualValues(t, lockTime, initiatorTx.LockTime)
+	require.Len(t, initiatorTx.TxIn, 2)
+	require.Len(t, initiatorTx.TxOut, 1)
+	require.Equal(
+		t, prevTx1.TxHash(), initiatorTx.TxIn[0].PreviousOutPoint.Hash,
+	)
+	require.Equal(
+		t, prevTx3.TxHash(), initiatorTx.TxIn[1].PreviousOutPoint.Hash,
+	)
+
+	require.Equal(t, []wire.OutPoint{{
+		Hash: prevTx1.TxHash(),
+	}}, initiator.LocalInputs())
+	require.Equal(t, []wire.OutPoint{{
+		Hash: prevTx3.TxHash(),
+	}}, responder.LocalInputs())
+	require.Equal(t, responder.LocalInputs(), initiator.RemoteInputs())
+	require.Equal(t, initiator.LocalInputs(), responder.RemoteInputs())
+
+	// Each party's contribution is the value of its inputs minus the
+	// value of its outputs, unless the output is shared by both.
+	require.EqualValues(t, 100_000, initiator.InputAmount(true))
+	require.EqualValues(t, 70_000, initiator.InputAmount(false))
+	require.EqualValues(t, -50_000, initiator.NetContribution(true, nil))
+	require.EqualValues(
+		t, 100_000, responder.NetContribution(false, testP2WKHScript),
+	)
+	require.EqualValues(t, 70_000, responder.NetContribution(true, nil))
+
+	prevOut, ok := responder.PrevOutput(initiator.LocalInputs()[0])
+	require.True(t, ok)
+	require.Equal(t, prevTx1.TxOut[0], prevOut)
+}
+
+// TestInteractiveTxResetComplete tests that adding an input after a
+// tx_complete was exchanged restarts the completion round.
+func TestInteractiveTxResetComplete(t *testing.T) {
+	t.Parallel()
+
+	tx := newInteractiveTx(testInteractiveChanID, true, 0, 354)
+	tx.LocalComplete()
+	require.NoError(t, tx.ProcessMsg(&lnwire.TxComplete{}))
+	require.True(t, tx.IsComplete())
+
+	require.NoError(t, tx.ProcessMsg(remoteInput(t, 1, newPrevTx(1, 0))))
+	require.False(t, tx.IsComplete())
+}
+
+// TestInteractiveTxInvalidMsgs tests that messages violating the interactive
+// tx construction protocol are rejected.
+func TestInteractiveTxInvalidMsgs(t *testing.T) {
+	t.Parallel()
+
+	nonSegwitTx := newPrevTx(1000, 0)
+	nonSegwitTx.TxOut[0].PkScript = []byte{0x51}
+
+	testCases := []struct {
+		name string
+		msgs func(t *testing.T) []lnwire.Message
+		err  error
+	}{
+		{
+			name: "wrong serial id parity",
+			msgs: func(t *testing.T) []lnwire.Message {
+				return []lnwire.Message{
+					remoteInput(t, 2, newPrevTx(1000, 0)),
+				}
+			},
+			err: errInvalidSerialID,
+		},
+		{
+			name: "duplicate serial id",
+			msgs: func(t *testing.T) []lnwire.Message {
+				return []lnwire.Message{
+					remoteInput(t, 1, newPrevTx(1000, 0)),
+					remoteInput(t, 1, newPrevTx(1000, 1)),
+				}
+			},
+			err: errInvalidSerialID,
+		},
+		{
+			name: "duplicate input",
+			msgs: func(t *testing.T) []lnwire.Message {
+				return []lnwire.Message{
+					remoteInput(t, 1, newPrevTx(1000, 0)),
+					remoteInput(t, 3, newPrevTx(1000, 0)),
+				}
+			},
+			err: errDuplicateInput,
+		},
+		{
+			name: "remove unknown input",
+			msgs: func(t *testing.T) []lnwire.Message {
+				return []lnwire.Message{
+					&lnwire.TxRemoveInput{SerialID: 1},
+				}
+			},
+			err: errUnknownSerialID,
+		},
+		{
+			name: "remove unknown output",
+			msgs: func(t *testing.T) []lnwire.Message {
+				return []lnwire.Message{
+					&lnwire.TxRemoveOutput{SerialID: 1},
+				}
+			},
+			err: errUnknownSerialID,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			tx := newInteractiveTx(
+				testInteractiveChanID, true, 0, 354,
+			)
+
+			var err error
+			for _, msg := range testCase.msgs(t) {
+				err = tx.ProcessMsg(msg)
+				if err != nil {
+					break
+				}
+			}
+			require.Equal(t, testCase.err, err)
+		})
+	}
+
+	// The remaining failures don't have a sentinel error, so we only
+	// check that they're rejected.
+	tx := newInteractiveTx(testInteractiveChanID, true, 0, 354)
+
+	input := remoteInput(t, 1, newPrevTx(1000, 0))
+	input.PrevTxOut = 1
+	require.Error(t, tx.ProcessMsg(input), "vout out of range")
+
+	input = remoteInput(t, 1, nonSegwitTx)
+	require.Error(t, tx.ProcessMsg(input), "non segwit input")
+
+	input = remoteInput(t, 1, newPrevTx(1000, 0))
+	input.Sequence = wire.MaxTxInSequenceNum
+	require.Error(t, tx.ProcessMsg(input), "invalid sequence")
+
+	input = remoteInput(t, 1, newPrevTx(1000, 0))
+	input.PrevTx = []byte{0x01}
+	require.Error(t, tx.ProcessMsg(input), "invalid prevtx")
+
+	require.Error(t, tx.ProcessMsg(&lnwire.TxAddOutput{
+		SerialID: 1,
+		Amount:   100,
+		PkScript: testP2WKHScript,
+	}), "dust output")
+}
+
+// TestInteractiveTxMaxMsgs tests that the remote party can't send more than
+// the maximum number of tx_add_input messages.
+func TestInteractiveTxMaxMsgs(t *testing.T) {
+	t.Parallel()
+
+	tx := newInteractiveTx(testInteractiveChanID, true, 0, 354)
+	prevTx := newPrevTx(1000, 0)
+
+	for i := 0; i < maxInteractiveTxMsgs; i++ {
+		serialID := uint64(i*2 + 1)
+		require.NoError(t, tx.ProcessMsg(remoteInput(t, serialID, prevTx)))
+		require.NoError(t, tx.ProcessMsg(&lnwire.TxRemoveInput{
+			SerialID: serialID,
+		}))
+	}
+
+	err := tx.ProcessMsg(remoteInput(t, 1, prevTx))
+	require.Equal(t, errTooManyInteractiveMsgs, err)
+}
@@ -120,6 +120,23 @@ var (
 	errUpfrontShutdownScriptNotSupported = errors.New("peer does not support" +
 		"option upfront shutdown script")
 
+	// errZeroConfNotSupported is returned if a zero-conf channel is
+	// requested, but either we or the peer don't signal the required
+	// feature bits.
+	errZeroConfNotSupported = errors.New("zero-conf channels require " +
+		"both peers to signal the explicit channel type, scid-alias " +
+		"and zero-conf features")
+
+	// errZeroConfNotRequested is returned if our channel acceptor wants
+	// a zero-conf channel, but the initiator didn't ask for one in the
+	// channel type.
+	errZeroConfNotRequested = errors.New("zero-conf channel not " +
+		"requested by the initiator")
+
+	// errZeroConfNotAccepted is returned if the initiator asks for a
+	// zero-conf channel, but our channel acceptor didn't allow it.
+	errZeroConfNotAccepted = errors.New("zero-conf channel not accepted")
+
 	zeroID [32]byte
 )
 
@@ -147,6 +164,14 @@ type reservationWithCtx struct {
 	// maxLocalCsv is the maximum csv we will accept from the remote.
 	maxLocalCsv uint16
 
+	// channelType is the explicit channel type proposed by the initiator of
+	// the channel, if any.
+	channelType *lnwire.ChannelType
+
+	// dualFund is set if the channel is funded by both parties through
+	// the v2 channel establishment protocol.
+	dualFund *dualFundFlow
+
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
 
@@ -240,6 +265,22 @@ type InitFundingMsg struct {
 	// protocol.
 	PendingChanID [32]byte
 
+	// ZeroConf indicates whether a zero-conf channel should be opened. The
+	// channel is identified by an alias until its funding transaction
+	// confirms.
+	ZeroConf bool
+
+	// DualFund indicates whether the channel should be opened with the v2
+	// channel establishment protocol, allowing the remote party to
+	// contribute funds to the channel as well.
+	DualFund bool
+
+	// ChannelType allows the caller to use an explicit channel type for the
+	// funding negotiation. This type will only be observed if BOTH sides
+	// support explicit channel type negotiation, otherwise the funding flow
+	// fails.
+	ChannelType *lnwire.ChannelType
+
 	// Updates is a channel which updates to the opening status of the channel
 	// are sent on.
 	Updates chan *lnrpc.OpenStatusUpdate
@@ -256,6 +297,20 @@ type fundingMsg struct {
 	peer lnpeer.Peer
 }
 
+// aliasHandler is an interface that abstracts the handling of channel
+// aliases that are used by zero-conf channels.
+type aliasHandler interface {
+	// RequestAlias returns the next available alias for use.
+	RequestAlias() (lnwire.ShortChannelID, error)
+
+	// AddLocalAlias persists an alias to the base short channel ID of a
+	// channel.
+	AddLocalAlias(alias, base lnwire.ShortChannelID) error
+
+	// PutPeerAlias stores the alias the peer sent us for a channel.
+	PutPeerAlias(lnwire.ChannelID, lnwire.ShortChannelID) error
+}
+
 // pendingChannels is a map instantiated per-peer which tracks all active
 // pending single funded channels indexed by their pending channel identifier,
 // which is a set of 32-bytes generated via a CSPRNG.
@@ -426,6 +481,13 @@ type Config struct {
 	// commit output. Channels that exceed this value will be failed.
 	MaxLocalCSVDelay uint16
 
+	// DualFundContribution is the liquidity policy for inbound dual funded
+	// channels. It returns the amount we contribute to the channel opened
+	// by the given peer. If nil, or if our wallet can't fund the returned
+	// amount, we accept dual funded channels without contributing.
+	DualFundContribution func(peer *btcec.PublicKey,
+		msg *lnwire.OpenChannel2) btcutil.Amount
+
 	// NotifyOpenChannelEvent informs the ChannelNotifier when channels
 	// transition from pending open to open.
 	NotifyOpenChannelEvent func(wire.OutPoint)
@@ -450,6 +512,15 @@ type Config struct {
 	// MaxAnchorsCommitFeeRate is the max commitment fee rate we'll use as
 	// the initiator for channels of the anchor type.
 	MaxAnchorsCommitFeeRate chainfee.SatPerKWeight
+
+	// AliasManager allocates and stores the aliases used by zero-conf
+	// channels.
+	AliasManager aliasHandler
+
+	// DeleteAliasEdge removes the edge of a public zero-conf channel that
+	// was added to the graph with the channel's alias, so that it can be
+	// re-added with the confirmed short channel ID.
+	DeleteAliasEdge func(scid lnwire.ShortChannelID) error
 }
 
 // Manager acts as an orchestrator/bridge between the wallet's
@@ -489,7 +560,16 @@ type Manager struct {
 	// signed by both parties.
 	signedReservations map[lnwire.ChannelID][32]byte
 
-	// resMtx guards both of the maps above to ensure that all access is
+	// dualFundFlows maps the channel ID of a dual funded channel to its
+	// funding flow, from the moment both parties know each other's keys
+	// until the signatures of the funding transaction are exchanged.
+	dualFundFlows map[lnwire.ChannelID]*dualFundFlow
+
+	// dualFundResumes receives the dual funded channels whose remote
+	// party came back online while the tx signatures are exchanged.
+	dualFundResumes chan *dualFundResume
+
+	// resMtx guards the maps above to ensure that all access is
 	// goroutine safe.
 	resMtx sync.RWMutex
 
@@ -562,6 +642,8 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		chanIDKey:                   cfg.TempChanIDSeed,
 		activeReservations:          make(map[serializedPubKey]pendingChannels),
 		signedReservations:          make(map[lnwire.ChannelID][32]byte),
+		dualFundFlows:               make(map[lnwire.ChannelID]*dualFundFlow),
+		dualFundResumes:             make(chan *dualFundResume),
 		newChanBarriers:             make(map[lnwire.ChannelID]chan struct{}),
 		fundingMsgs:                 make(chan *fundingMsg, msgBufferSize),
 		fundingRequests:             make(chan *InitFundingMsg, msgBufferSize),
@@ -613,12 +695,25 @@ func (f *Manager) start() error {
 
 			f.localDiscoverySignals[chanID] = make(chan struct{})
 
+			// A dual funded channel may still be waiting for the
+			// signatures of the funding transaction, in which case
+			// their exchange is resumed. Until we sent ours, the
+			// funding transaction can't confirm.
+			txSigs, err := f.restoreDualFundTxSigs(channel)
+			if err != nil {
+				return err
+			}
+			if txSigs != nil && !txSigs.sigsSent {
+				continue
+			}
+
 			// Rebroadcast the funding transaction for any pending
-			// channel that we initiated. No error will be returned
-			// if the transaction already has been broadcast.
+			// channel that we initiated, once it's fully signed.
+			// No error will be returned if the transaction already
+			// has been broadcast.
 			chanType := channel.ChanType
 			if chanType.IsSingleFunder() && chanType.HasFundingTx() &&
-				channel.IsInitiator {
+				channel.IsInitiator && txSigs == nil {
 
 				var fundingTxBuf bytes.Buffer
 				err := channel.FundingTxn.Serialize(&fundingTxBuf)
@@ -824,12 +919,28 @@ func (f *Manager) reservationCoordinator() {
 			case *lnwire.FundingLocked:
 				f.wg.Add(1)
 				go f.handleFundingLocked(fmsg.peer, msg)
+			case *lnwire.OpenChannel2:
+				f.handleFundingOpen2(fmsg.peer, msg)
+			case *lnwire.AcceptChannel2:
+				f.handleFundingAccept2(fmsg.peer, msg)
+			case *lnwire.TxAddInput, *lnwire.TxAddOutput,
+				*lnwire.TxRemoveInput, *lnwire.TxRemoveOutput,
+				*lnwire.TxComplete:
+
+				f.handleInteractiveTxMsg(fmsg.peer, msg)
+			case *lnwire.CommitSig:
+				f.handleDualFundCommitSig(fmsg.peer, msg)
+			case *lnwire.TxSignatures:
+				f.handleTxSignatures(fmsg.peer, msg)
 			case *lnwire.Error:
 				f.handleErrorMsg(fmsg.peer, msg)
 			}
 		case req := <-f.fundingRequests:
 			f.handleInitFundingMsg(req)
 
+		case resume := <-f.dualFundResumes:
+			f.handleDualFundResume(resume)
+
 		case <-zombieSweepTicker.C:
 			f.pruneZombieReservations()
 
@@ -853,9 +964,17 @@ func (f *Manager) advanceFundingState(channel *channeldb.OpenChannel,
 	defer f.wg.Done()
 
 	// If the channel is still pending we must wait for the funding
-	// transaction to confirm.
+	// transaction to confirm, unless it's a zero-conf channel which can
+	// be marked open right away.
 	if channel.IsPending {
-		err := f.advancePendingChannelState(channel, pendingChanID)
+		var err error
+		if channel.ChanType.IsZeroConf() {
+			err = f.markZeroConfOpen(channel)
+		} else {
+			err = f.advancePendingChannelState(
+				channel, pendingChanID,
+			)
+		}
 		if err != nil {
 			log.Errorf("Unable to advance pending state of "+
 				"ChannelPoint(%v): %v",
@@ -1013,6 +1132,18 @@ func (f *Manager) stateStep(channel *channeldb.OpenChannel,
 	// The channel was added to the Router's topology, but the channel
 	// announcement was not sent.
 	case addedToRouterGraph:
+		// A zero-conf channel can only be announced once its funding
+		// transaction has confirmed, so we'll wait for that to happen
+		// and continue with the confirmed short channel ID.
+		if channel.ChanType.IsZeroConf() {
+			realScid, err := f.waitForZeroConfChannel(channel)
+			if err != nil {
+				return fmt.Errorf("failed waiting for zero-conf "+
+					"channel confirmation: %v", err)
+			}
+			shortChanID = &realScid
+		}
+
 		err := f.annAfterSixConfs(channel, shortChanID)
 		if err != nil {
 			return fmt.Errorf("error sending channel "+
@@ -1107,7 +1238,11 @@ func (f *Manager) advancePendingChannelState(
 			channel.FundingOutpoint, err)
 	}
 
-	// Success, funding transaction was confirmed.
+	// Success, funding transaction was confirmed. If it's dual funded, the
+	// remote party may have broadcast it without us receiving their tx
+	// signatures, so we stop waiting for them.
+	f.forgetDualFundTxSigs(channel)
+
 	chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
 	log.Debugf("ChannelID(%v) is now fully confirmed! "+
 		"(shortChanID=%v)", chanID, confChannel.shortChanID)
@@ -1122,6 +1257,134 @@ func (f *Manager) advancePendingChannelState(
 	return nil
 }
 
+// markZeroConfOpen marks a pending zero-conf channel as open using its alias
+// and sets the channelOpeningState markedOpen, without waiting for the
+// funding transaction to confirm.
+func (f *Manager) markZeroConfOpen(completeChan *channeldb.OpenChannel) error {
+	fundingPoint := completeChan.FundingOutpoint
+	chanID := lnwire.NewChanIDFromOutPoint(&fundingPoint)
+	alias := completeChan.ShortChanID()
+
+	// We store the alias as its own base, so it shows up along with the
+	// confirmed short channel ID once we learn about it.
+	err := f.cfg.AliasManager.AddLocalAlias(alias, alias)
+	if err != nil {
+		return fmt.Errorf("unable to store alias: %v", err)
+	}
+
+	err = f.saveChannelOpeningState(&fundingPoint, markedOpen, &alias)
+	if err != nil {
+		return fmt.Errorf("error setting channel state to markedOpen: %v",
+			err)
+	}
+
+	err = completeChan.MarkAsOpen(alias)
+	if err != nil {
+		return fmt.Errorf("error setting channel pending flag to false: "+
+			"%v", err)
+	}
+
+	log.Infof("Zero-conf ChannelPoint(%v) is now active with alias %v",
+		fundingPoint, alias)
+
+	// Inform the ChannelNotifier that the channel has transitioned from
+	// pending open to open.
+	f.cfg.NotifyOpenChannelEvent(fundingPoint)
+
+	// Close the discoverySignal channel, indicating to a separate
+	// goroutine that the channel now is marked as open in the database
+	// and that it is acceptable to process funding locked messages
+	// from the peer.
+	f.localDiscoveryMtx.Lock()
+	if discoverySignal, ok := f.localDiscoverySignals[chanID]; ok {
+		close(discoverySignal)
+	}
+	f.localDiscoveryMtx.Unlock()
+
+	return nil
+}
+
+// waitForZeroConfChannel waits for the funding transaction of a zero-conf
+// channel to confirm and stores the confirmed short channel ID as an alias of
+// the channel. Public channels are re-added to the graph with the confirmed
+// short channel ID so they can be announced. The confirmed short channel ID
+// is returned.
+func (f *Manager) waitForZeroConfChannel(
+	completeChan *channeldb.OpenChannel) (lnwire.ShortChannelID, error) {
+
+	alias := completeChan.ShortChanID()
+
+	if !completeChan.ZeroConfConfirmed() {
+		confChannel, err := f.waitForFundingWithTimeout(completeChan)
+		if err != nil {
+			return lnwire.ShortChannelID{}, err
+		}
+
+		// Now that the channel has confirmed, we can verify the
+		// funding transaction we've been using the channel with.
+		err = f.cfg.Wallet.ValidateChannel(
+			completeChan, confChannel.fundingTx,
+		)
+		if err != nil {
+			return lnwire.ShortChannelID{}, fmt.Errorf("unable to "+
+				"validate channel: %v", err)
+		}
+
+		err = completeChan.MarkRealScid(confChannel.shortChanID)
+		if err != nil {
+			return lnwire.ShortChannelID{}, fmt.Errorf("unable to "+
+				"store confirmed scid: %v", err)
+		}
+
+		// Label the funding transaction with the confirmed short
+		// channel ID in case we published it.
+		if completeChan.IsInitiator &&
+			completeChan.ChanType.HasFundingTx() {
+
+			label := labels.MakeLabel(
+				labels.LabelTypeChannelOpen,
+				&confChannel.shortChanID,
+			)
+			err = f.cfg.UpdateLabel(
+				completeChan.FundingOutpoint.Hash, label,
+			)
+			if err != nil {
+				log.Errorf("unable to update label: %v", err)
+			}
+		}
+	}
+
+	realScid := completeChan.ZeroConfRealScid()
+
+	log.Infof("Zero-conf ChannelPoint(%v) with alias %v confirmed at %v",
+		completeChan.FundingOutpoint, alias, realScid)
+
+	// Forwards using the confirmed short channel ID need to find the link,
+	// which is still indexed by the alias.
+	if err := f.cfg.AliasManager.AddLocalAlias(realScid, alias); err != nil {
+		return lnwire.ShortChannelID{}, fmt.Errorf("unable to store "+
+			"alias: %v", err)
+	}
+
+	// Private channels are only known by their alias, but public channels
+	// need to be announced with the confirmed short channel ID. So we'll
+	// replace the edge we added with the alias.
+	if completeChan.ChannelFlags&lnwire.FFAnnounceChannel != 0 {
+		err := f.cfg.DeleteAliasEdge(alias)
+		if err != nil && err != channeldb.ErrEdgeNotFound {
+			return lnwire.ShortChannelID{}, fmt.Errorf("unable to "+
+				"delete alias edge: %v", err)
+		}
+
+		if err := f.addToRouterGraph(completeChan, &realScid); err != nil {
+			return lnwire.ShortChannelID{}, fmt.Errorf("failed "+
+				"adding to router graph: %v", err)
+		}
+	}
+
+	return realScid, nil
+}
+
 // ProcessFundingMsg sends a message to the internal fundingManager goroutine,
 // allowing it to handle the lnwire.Message.
 func (f *Manager) ProcessFundingMsg(msg lnwire.Message, peer lnpeer.Peer) {
@@ -1132,60 +1395,39 @@ func (f *Manager) ProcessFundingMsg(msg lnwire.Message, peer lnpeer.Peer) {
 	}
 }
 
-// commitmentType returns the commitment type to use for the channel, based on
-// the features the two peers have available.
-func commitmentType(localFeatures,
-	remoteFeatures *lnwire.FeatureVector) lnwallet.CommitmentType {
+// zeroConfSupported returns whether both the local and remote feature
+// vectors signal the features required for zero-conf channels. Zero-conf
+// channels are negotiated through the explicit channel type.
+func zeroConfSupported(localFeatures,
+	remoteFeatures *lnwire.FeatureVector) bool {
 
-	// If both peers are signalling support for anchor commitments with
-	// zero-fee HTLC transactions, we'll use this type.
-	localZeroFee := localFeatures.HasFeature(
-		lnwire.AnchorsZeroFeeHtlcTxOptional,
-	)
-	remoteZeroFee := remoteFeatures.HasFeature(
-		lnwire.AnchorsZeroFeeHtlcTxOptional,
-	)
-	if localZeroFee && remoteZeroFee {
-		return lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx
-	}
-
-	// Since we don't want to support the "legacy" anchor type, we will
-	// fall back to static remote key if the nodes don't support the zero
-	// fee HTLC tx anchor type.
-	localTweakless := localFeatures.HasFeature(
-		lnwire.StaticRemoteKeyOptional,
-	)
-	remoteTweakless := remoteFeatures.HasFeature(
-		lnwire.StaticRemoteKeyOptional,
-	)
+	return hasFeatures(localFeatures, remoteFeatures,
+		lnwire.ExplicitChannelTypeOptional, lnwire.ZeroConfOptional,
+		lnwire.ScidAliasOptional)
+}
 
-	// If both nodes are signaling the proper feature bit for tweakless
-	// copmmitments, we'll use that.
-	if localTweakless && remoteTweakless {
-		return lnwallet.CommitmentTypeTweakless
+// parseCommitSig parses a commitment signature received from the remote party,
+// which is a BIP-340 signature for taproot channels and an ECDSA signature for
+// all other channel types.
+func parseCommitSig(sig lnwire.Sig, taproot bool) (input.Signature, error) {
+	if taproot {
+		return sig.ToSchnorrSignature()
 	}
 
-	// Otherwise we'll fall back to the legacy type.
-	return lnwallet.CommitmentTypeLegacy
+	return sig.ToSignature()
 }
 
-// handleFundingOpen creates an initial 'ChannelReservation' within the wallet,
-// then responds to the source peer with an accept channel message progressing
-// the funding workflow.
-//
-// TODO(roasbeef): add error chan to all, let channelManager handle
-// error+propagate
-func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
-	msg *lnwire.OpenChannel) {
+// checkInboundChannel checks whether we're willing to accept a new inbound
+// channel of the given size from the peer. An error to fail the funding flow
+// with is returned if we aren't.
+func (f *Manager) checkInboundChannel(peer lnpeer.Peer,
+	amt btcutil.Amount) error {
 
 	// Check number of pending channels to be smaller than maximum allowed
-	// number and send ErrorGeneric to remote peer if condition is
-	// violated.
+	// number.
 	peerPubKey := peer.IdentityKey()
 	peerIDKey := newSerializedKey(peerPubKey)
 
-	amt := msg.FundingAmount
-
 	// We get all pending channels for this peer. This is the list of the
 	// active reservations and the channels pending open in the database.
 	f.resMtx.RLock()
@@ -1206,10 +1448,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	// the underlying intent anymore, unfortunately.
 	channels, err := f.cfg.Wallet.Cfg.Database.FetchOpenChannels(peerPubKey)
 	if err != nil {
-		f.failFundingFlow(
-			peer, msg.PendingChannelID, err,
-		)
-		return
+		return err
 	}
 
 	for _, c := range channels {
@@ -1227,11 +1466,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	// TODO(roasbeef): modify to only accept a _single_ pending channel per
 	// block unless white listed
 	if numPending >= f.cfg.MaxPendingChannels {
-		f.failFundingFlow(
-			peer, msg.PendingChannelID,
-			lnwire.ErrMaxPendingChannels,
-		)
-		return
+		return lnwire.ErrMaxPendingChannels
 	}
 
 	// We'll also reject any requests to create channels until we're fully
@@ -1242,29 +1477,41 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		if err != nil {
 			log.Errorf("unable to query wallet: %v", err)
 		}
-		f.failFundingFlow(
-			peer, msg.PendingChannelID,
-			lnwire.ErrSynchronizingChain,
-		)
-		return
+		return lnwire.ErrSynchronizingChain
 	}
 
 	// Ensure that the remote party respects our maximum channel size.
 	if amt > f.cfg.MaxChanSize {
-		f.failFundingFlow(
-			peer, msg.PendingChannelID,
-			lnwallet.ErrChanTooLarge(amt, f.cfg.MaxChanSize),
-		)
-		return
+		return lnwallet.ErrChanTooLarge(amt, f.cfg.MaxChanSize)
 	}
 
 	// We'll, also ensure that the remote party isn't attempting to propose
 	// a channel that's below our current min channel size.
 	if amt < f.cfg.MinChanSize {
-		f.failFundingFlow(
-			peer, msg.PendingChannelID,
-			lnwallet.ErrChanTooSmall(amt, btcutil.Amount(f.cfg.MinChanSize)),
+		return lnwallet.ErrChanTooSmall(
+			amt, btcutil.Amount(f.cfg.MinChanSize),
 		)
+	}
+
+	return nil
+}
+
+// handleFundingOpen creates an initial 'ChannelReservation' within the wallet,
+// then responds to the source peer with an accept channel message progressing
+// the funding workflow.
+//
+// TODO(roasbeef): add error chan to all, let channelManager handle
+// error+propagate
+func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel) {
+
+	peerIDKey := newSerializedKey(peer.IdentityKey())
+	amt := msg.FundingAmount
+
+	// Before creating a reservation, we'll make sure we're willing to
+	// accept another channel of this size from the peer.
+	if err := f.checkInboundChannel(peer, amt); err != nil {
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
 		return
 	}
 
@@ -1310,9 +1557,35 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	// Before we init the channel, we'll also check to see what commitment
 	// format we can use with this peer. This is dependent on *both* us and
 	// the remote peer are signaling the proper feature bit.
-	commitType := commitmentType(
-		peer.LocalFeatures(), peer.RemoteFeatures(),
+	//
+	// If the initiator sent an explicit channel type, we'll negotiate for
+	// that type and fail the funding flow if we can't support it.
+	commitType, zeroConf, err := negotiateCommitmentType(
+		msg.ChannelType, peer.LocalFeatures(), peer.RemoteFeatures(),
 	)
+	if err != nil {
+		log.Errorf("Unable to negotiate commitment type for "+
+			"pendingId=%x: %v", msg.PendingChannelID, err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// A zero-conf channel is only opened if the initiator asked for it in
+	// the channel type and our channel acceptor allows it.
+	switch {
+	case acceptorResp.ZeroConf && !zeroConf:
+		f.failFundingFlow(
+			peer, msg.PendingChannelID, errZeroConfNotRequested,
+		)
+		return
+
+	case zeroConf && !acceptorResp.ZeroConf:
+		f.failFundingFlow(
+			peer, msg.PendingChannelID, errZeroConfNotAccepted,
+		)
+		return
+	}
+
 	chainHash := chainhash.Hash(msg.ChainHash)
 	req := &lnwallet.InitFundingReserveMsg{
 		ChainHash:        &chainHash,
@@ -1327,6 +1600,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		Flags:            msg.ChannelFlags,
 		MinConfs:         1,
 		CommitType:       commitType,
+		ZeroConf:         zeroConf,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -1336,6 +1610,18 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		return
 	}
 
+	// A zero-conf channel is identified by an alias until its funding
+	// transaction confirms.
+	if zeroConf {
+		alias, err := f.cfg.AliasManager.RequestAlias()
+		if err != nil {
+			log.Errorf("Unable to request alias: %v", err)
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+			return
+		}
+		reservation.AddAlias(alias)
+	}
+
 	// As we're the responder, we get to specify the number of confirmations
 	// that we require before both of us consider the channel open. We'll
 	// use our mapping to derive the proper number of confirmations based on
@@ -1346,6 +1632,11 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	if acceptorResp.MinAcceptDepth != 0 {
 		numConfsReq = acceptorResp.MinAcceptDepth
 	}
+
+	// Zero-conf channels don't require any confirmations.
+	if zeroConf {
+		numConfsReq = 0
+	}
 	reservation.SetNumConfsRequired(numConfsReq)
 
 	// We'll also validate and apply all the constraints the initiating
@@ -1514,6 +1805,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		HtlcPoint:             ourContribution.HtlcBasePoint.PubKey,
 		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
 		UpfrontShutdownScript: ourContribution.UpfrontShutdown,
+		ChannelType:           msg.ChannelType,
 	}
 
 	if err := peer.SendMessage(true, &fundingAccept); err != nil {
@@ -1542,6 +1834,14 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 	// Update the timestamp once the fundingAcceptMsg has been handled.
 	defer resCtx.updateTimestamp()
 
+	if resCtx.dualFund != nil {
+		err := fmt.Errorf("received accept_channel for dual funded " +
+			"channel")
+		log.Warnf("Unexpected funding response: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
 	log.Infof("Recv'd fundingResponse for pending_id(%x)",
 		pendingChanID[:])
 
@@ -1557,6 +1857,34 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 		return
 	}
 
+	// If we proposed an explicit channel type, the responder must have
+	// echoed it back to us, otherwise the channel would end up with a type
+	// the user didn't ask for.
+	err = validateAcceptChannelType(resCtx.channelType, msg.ChannelType)
+	if err != nil {
+		log.Warnf("Unacceptable channel type: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// A zero-conf channel requires a min depth of zero, while any other
+	// channel must have at least one confirmation. We only allow a min
+	// depth of zero if we explicitly asked for a zero-conf channel.
+	zeroConf := resCtx.reservation.IsZeroConf()
+	if zeroConf && msg.MinAcceptDepth != 0 {
+		err := fmt.Errorf("zero-conf channel has min_depth %v",
+			msg.MinAcceptDepth)
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+	if !zeroConf && msg.MinAcceptDepth == 0 {
+		err := fmt.Errorf("non-zero-conf channel has min_depth of zero")
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
 	// We'll also specify the responder's preference for the number of
 	// required confirmations, and also the set of channel constraints
 	// they've specified for commitment states we can create.
@@ -1825,6 +2153,16 @@ func (f *Manager) handleFundingCreated(peer lnpeer.Peer,
 		return
 	}
 
+	// The funding transaction of a dual funded channel is constructed
+	// interactively, so it's never sent by the initiator.
+	if resCtx.dualFund != nil {
+		err := fmt.Errorf("received funding_created for dual funded " +
+			"channel")
+		log.Warnf("Unexpected funding created: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
 	// The channel initiator has responded with the funding outpoint of the
 	// final funding transaction, as well as a signature for our version of
 	// the commitment transaction. So at this point, we can validate the
@@ -1834,7 +2172,9 @@ func (f *Manager) handleFundingCreated(peer lnpeer.Peer,
 	log.Infof("completing pending_id(%x) with ChannelPoint(%v)",
 		pendingChanID[:], fundingOut)
 
-	commitSig, err := msg.CommitSig.ToSignature()
+	commitSig, err := parseCommitSig(
+		msg.CommitSig, resCtx.reservation.IsTaproot(),
+	)
 	if err != nil {
 		log.Errorf("unable to parse signature: %v", err)
 		f.failFundingFlow(peer, pendingChanID, err)
@@ -2006,7 +2346,9 @@ func (f *Manager) handleFundingSigned(peer lnpeer.Peer,
 	// The remote peer has responded with a signature for our commitment
 	// transaction. We'll verify the signature for validity, then commit
 	// the state to disk as we can now open the channel.
-	commitSig, err := msg.CommitSig.ToSignature()
+	commitSig, err := parseCommitSig(
+		msg.CommitSig, resCtx.reservation.IsTaproot(),
+	)
 	if err != nil {
 		log.Errorf("Unable to parse signature: %v", err)
 		f.failFundingFlow(peer, pendingChanID, err)
@@ -2208,7 +2550,12 @@ func (f *Manager) waitForFundingConfirmation(
 			err)
 		return
 	}
+	// Zero-conf channels don't require any confirmations, but we still
+	// wait for the first one to learn the confirmed short channel ID.
 	numConfs := uint32(completeChan.NumConfsRequired)
+	if numConfs == 0 {
+		numConfs = 1
+	}
 	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
 		&txid, fundingScript, numConfs,
 		completeChan.FundingBroadcastHeight,
@@ -2445,6 +2792,13 @@ func (f *Manager) sendFundingLocked(
 	}
 	fundingLockedMsg := lnwire.NewFundingLocked(chanID, nextRevocation)
 
+	// Channels that use an alias send it to the peer, so the peer can use
+	// it in routing hints before the funding transaction confirms.
+	if completeChan.ChanType.HasScidAliasChan() {
+		alias := completeChan.ShortChanID()
+		fundingLockedMsg.AliasScid = &alias
+	}
+
 	// If the peer has disconnected before we reach this point, we will need
 	// to wait for him to come back online before sending the fundingLocked
 	// message. This is special for fundingLocked, since failing to send any
@@ -2514,11 +2868,22 @@ func (f *Manager) addToRouterGraph(completeChan *channeldb.OpenChannel,
 		fwdMaxHTLC = capacityMSat
 	}
 
+	// A spliced channel keeps the routing policy it had before the
+	// splice. Only the max HTLC value is derived from the new capacity.
+	prevUpdate, err := completeChan.SplicedChannelUpdate()
+	if err != nil {
+		return fmt.Errorf("unable to fetch channel update of spliced "+
+			"channel: %v", err)
+	}
+	if prevUpdate != nil {
+		fwdMinHTLC = prevUpdate.HtlcMinimumMsat
+	}
+
 	ann, err := f.newChanAnnouncement(
 		f.cfg.IDKey, completeChan.IdentityPub,
 		completeChan.LocalChanCfg.MultiSigKey.PubKey,
 		completeChan.RemoteChanCfg.MultiSigKey.PubKey, *shortChanID,
-		chanID, fwdMinHTLC, fwdMaxHTLC,
+		chanID, fwdMinHTLC, fwdMaxHTLC, prevUpdate,
 	)
 	if err != nil {
 		return fmt.Errorf("error generating channel "+
@@ -2761,6 +3126,18 @@ func (f *Manager) handleFundingLocked(peer lnpeer.Peer,
 		return
 	}
 
+	// If the peer sent us an alias for the channel, we'll store it so we
+	// can use it in routing hints. We do this before checking for a
+	// duplicate message, as the alias may only be part of a resent one.
+	if msg.AliasScid != nil {
+		err := f.cfg.AliasManager.PutPeerAlias(chanID, *msg.AliasScid)
+		if err != nil {
+			log.Errorf("Unable to store peer alias for "+
+				"ChannelID(%v): %v", chanID, err)
+			return
+		}
+	}
+
 	// If the RemoteNextRevocation is non-nil, it means that we have
 	// already processed fundingLocked for this channel, so ignore.
 	if channel.RemoteNextRevocation != nil {
@@ -2818,11 +3195,13 @@ type chanAnnouncement struct {
 // channel and contains four signatures binding the funding pub keys and
 // identity pub keys of both parties to the channel, and the second segment is
 // authenticated only by us and contains our directional routing policy for the
-// channel.
+// channel. The routing policy is the default one, unless the fees and time
+// lock delta of a previous channel update are passed.
 func (f *Manager) newChanAnnouncement(localPubKey, remotePubKey,
 	localFundingKey, remoteFundingKey *btcec.PublicKey,
 	shortChanID lnwire.ShortChannelID, chanID lnwire.ChannelID,
-	fwdMinHTLC, fwdMaxHTLC lnwire.MilliSatoshi) (*chanAnnouncement, error) {
+	fwdMinHTLC, fwdMaxHTLC lnwire.MilliSatoshi,
+	prevUpdate *lnwire.ChannelUpdate) (*chanAnnouncement, error) {
 
 	chainHash := *f.cfg.Wallet.Cfg.NetParams.GenesisHash
 
@@ -2891,6 +3270,16 @@ func (f *Manager) newChanAnnouncement(localPubKey, remotePubKey,
 		FeeRate: uint32(f.cfg.DefaultRoutingPolicy.FeeRate),
 	}
 
+	// If we announced the channel before, e.g. before it was spliced, we
+	// keep our fees, including the inbound fee carried in the extra
+	// opaque data, and the time lock delta.
+	if prevUpdate != nil {
+		chanUpdateAnn.TimeLockDelta = prevUpdate.TimeLockDelta
+		chanUpdateAnn.BaseFee = prevUpdate.BaseFee
+		chanUpdateAnn.FeeRate = prevUpdate.FeeRate
+		chanUpdateAnn.ExtraOpaqueData = prevUpdate.ExtraOpaqueData
+	}
+
 	// With the channel update announcement constructed, we'll generate a
 	// signature that signs a double-sha digest of the announcement.
 	// This'll serve to authenticate this announcement and any other future
@@ -2975,7 +3364,7 @@ func (f *Manager) announceChannel(localIDKey, remoteIDKey, localFundingKey,
 	// only use the channel announcement message from the returned struct.
 	ann, err := f.newChanAnnouncement(localIDKey, remoteIDKey,
 		localFundingKey, remoteFundingKey, shortChanID, chanID,
-		0, 0,
+		0, 0, nil,
 	)
 	if err != nil {
 		log.Errorf("can't generate channel announcement: %v", err)
@@ -3177,10 +3566,60 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 	//
 	// Before we init the channel, we'll also check to see what commitment
 	// format we can use with this peer. This is dependent on *both* us and
-	// the remote peer are signaling the proper feature bit.
-	commitType := commitmentType(
-		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
+	// the remote peer are signaling the proper feature bit. If the caller
+	// requested an explicit channel type, we'll fail the funding flow if
+	// the remote peer can't support it.
+	commitType, typeZeroConf, err := negotiateCommitmentType(
+		msg.ChannelType, msg.Peer.LocalFeatures(),
+		msg.Peer.RemoteFeatures(),
 	)
+	if err != nil {
+		log.Errorf("Unable to negotiate commitment type: %v", err)
+		msg.Err <- err
+		return
+	}
+
+	// A zero-conf channel requires both us and the remote peer to signal
+	// the zero-conf, scid-alias and explicit channel type feature bits,
+	// as it is negotiated through the channel type.
+	zeroConf := msg.ZeroConf || typeZeroConf
+	if zeroConf && !zeroConfSupported(
+		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
+	) {
+
+		msg.Err <- errZeroConfNotSupported
+		return
+	}
+
+	// If both sides support explicit channel type negotiation, we'll
+	// always send the channel type, so the responder can't silently pick
+	// a different one.
+	var chanType *lnwire.ChannelType
+	if hasFeatures(
+		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
+		lnwire.ExplicitChannelTypeOptional,
+	) {
+
+		chanType = commitmentTypeToChannelType(commitType, zeroConf)
+	}
+
+	// The funding transaction of a dual funded channel is constructed
+	// together with the remote party, and commits to a locktime we pick
+	// up front.
+	var lockTime uint32
+	if msg.DualFund {
+		if err := validateDualFundInit(msg, zeroConf); err != nil {
+			msg.Err <- err
+			return
+		}
+
+		_, height, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+		if err != nil {
+			msg.Err <- err
+			return
+		}
+		lockTime = uint32(height)
+	}
 
 	// First, we'll query the fee estimator for a fee that should get the
 	// commitment transaction confirmed by the next few blocks (conf target
@@ -3192,9 +3631,9 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
-	// For anchor channels cap the initial commit fee rate at our defined
-	// maximum.
-	if commitType == lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx &&
+	// For anchor channels, which includes taproot channels, cap the
+	// initial commit fee rate at our defined maximum.
+	if commitType.HasAnchors() &&
 		commitFeePerKw > f.cfg.MaxAnchorsCommitFeeRate {
 
 		commitFeePerKw = f.cfg.MaxAnchorsCommitFeeRate
@@ -3215,6 +3654,8 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		MinConfs:         msg.MinConfs,
 		CommitType:       commitType,
 		ChanFunder:       msg.ChanFunder,
+		ZeroConf:         zeroConf,
+		DualFund:         msg.DualFund,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -3223,6 +3664,22 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
+	// A zero-conf channel is identified by an alias until its funding
+	// transaction confirms.
+	if zeroConf {
+		alias, err := f.cfg.AliasManager.RequestAlias()
+		if err != nil {
+			if err := reservation.Cancel(); err != nil {
+				log.Errorf("unable to cancel reservation: %v",
+					err)
+			}
+
+			msg.Err <- err
+			return
+		}
+		reservation.AddAlias(alias)
+	}
+
 	// Set our upfront shutdown address in the existing reservation.
 	reservation.SetOurUpfrontShutdown(shutdown)
 
@@ -3272,11 +3729,19 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		remoteMaxValue: maxValue,
 		remoteMaxHtlcs: maxHtlcs,
 		maxLocalCsv:    maxCSV,
+		channelType:    chanType,
 		reservation:    reservation,
 		peer:           msg.Peer,
 		updates:        msg.Updates,
 		err:            msg.Err,
 	}
+	if msg.DualFund {
+		resCtx.dualFund = &dualFundFlow{
+			pendingChanID: chanID,
+			resCtx:        resCtx,
+			lockTime:      lockTime,
+		}
+	}
 	f.activeReservations[peerIDKey][chanID] = resCtx
 	f.resMtx.Unlock()
 
@@ -3315,8 +3780,16 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
 		ChannelFlags:          channelFlags,
 		UpfrontShutdownScript: shutdown,
+		ChannelType:           chanType,
+	}
+
+	var openMsg lnwire.Message = &fundingOpen
+	if msg.DualFund {
+		openMsg = newOpenChannel2(
+			&fundingOpen, msg.FundingFeePerKw, lockTime,
+		)
 	}
-	if err := msg.Peer.SendMessage(true, &fundingOpen); err != nil {
+	if err := msg.Peer.SendMessage(true, openMsg); err != nil {
 		e := fmt.Errorf("unable to send funding request message: %v",
 			err)
 		log.Errorf(e.Error())
@@ -3342,6 +3815,19 @@ func (f *Manager) handleErrorMsg(peer lnpeer.Peer,
 	chanID := msg.ChanID
 	peerKey := peer.IdentityKey()
 
+	// Errors for a dual funded channel may reference its channel ID
+	// instead of the pending channel ID. Once both commitments are signed,
+	// there's no reservation left to cancel, but the exchange of the tx
+	// signatures is given up on.
+	if flow, err := f.getDualFundFlow(peer, chanID); err == nil {
+		if flow.completeChan != nil {
+			f.abortDualFundTxSigs(flow, fmt.Errorf("received "+
+				"error: %v", msg.Error()))
+			return
+		}
+		chanID = flow.pendingChanID
+	}
+
 	// First, we'll attempt to retrieve and cancel the funding workflow
 	// that this error was tied to. If we're unable to do so, then we'll
 	// exit early as this was an unwarranted error.
@@ -3451,6 +3937,12 @@ func (f *Manager) cancelReservationCtx(peerKey *btcec.PublicKey,
 	if len(nodeReservations) == 0 {
 		delete(f.activeReservations, peerIDKey)
 	}
+
+	// A dual funded channel is also indexed by its channel ID once the
+	// interactive construction of its funding transaction started.
+	if ctx.dualFund != nil {
+		delete(f.dualFundFlows, ctx.dualFund.chanID)
+	}
 	return ctx, nil
 }
 
@@ -3507,10 +3999,17 @@ func (f *Manager) IsPendingChannel(pendingChanID [32]byte,
 
 	peerIDKey := newSerializedKey(peer.IdentityKey())
 	f.resMtx.RLock()
-	_, ok := f.activeReservations[peerIDKey][pendingChanID]
-	f.resMtx.RUnlock()
+	defer f.resMtx.RUnlock()
+
+	if _, ok := f.activeReservations[peerIDKey][pendingChanID]; ok {
+		return true
+	}
+
+	// Dual funded channels are referenced by their channel ID once both
+	// parties know each other's keys.
+	flow, ok := f.dualFundFlows[pendingChanID]
 
-	return ok
+	return ok && flow.remoteKey().IsEqual(peer.IdentityKey())
 }
 
 func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
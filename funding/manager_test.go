@@ -194,6 +194,7 @@ type testNode struct {
 	mockChanEvent   *mockChanEvent
 	testDir         string
 	shutdownChannel chan struct{}
+	localFeatures   []lnwire.FeatureBit
 	remoteFeatures  []lnwire.FeatureBit
 
 	remotePeer  *testNode
@@ -229,7 +230,9 @@ func (n *testNode) QuitSignal() <-chan struct{} {
 }
 
 func (n *testNode) LocalFeatures() *lnwire.FeatureVector {
-	return lnwire.NewFeatureVector(nil, nil)
+	return lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(n.localFeatures...), nil,
+	)
 }
 
 func (n *testNode) RemoteFeatures() *lnwire.FeatureVector {
@@ -815,8 +818,12 @@ func assertFundingMsgSent(t *testing.T, msgChan chan lnwire.Message,
 		ok      bool
 	)
 	switch msgType {
+	case "OpenChannel":
+		sentMsg, ok = msg.(*lnwire.OpenChannel)
 	case "AcceptChannel":
 		sentMsg, ok = msg.(*lnwire.AcceptChannel)
+	case "AcceptChannel2":
+		sentMsg, ok = msg.(*lnwire.AcceptChannel2)
 	case "FundingCreated":
 		sentMsg, ok = msg.(*lnwire.FundingCreated)
 	case "FundingSigned":
@@ -3520,3 +3527,446 @@ func TestWumboChannelConfig(t *testing.T) {
 	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
 	assertFundingMsgSent(t, bob.msgChan, "AcceptChannel")
 }
+
+// setDualFundUtxo sets up the wallet of the node to fund dual funded channels
+// from a single utxo of the given value. The utxo comes with its previous
+// transaction, which is announced to the remote party during the interactive
+// construction of the funding transaction.
+func setDualFundUtxo(t *testing.T, node *testNode, value btcutil.Amount) {
+	t.Helper()
+
+	// The mock signer of both nodes signs with Alice's key.
+	pkScript, err := input.CommitScriptUnencumbered(alicePrivKey.PubKey())
+	require.NoError(t, err)
+
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(&wire.TxIn{})
+	prevTx.AddTxOut(wire.NewTxOut(int64(value), pkScript))
+
+	wc := node.fundingMgr.cfg.Wallet.WalletController.(*mock.WalletController)
+	wc.Utxos = []*lnwallet.Utxo{{
+		AddressType:   lnwallet.WitnessPubKey,
+		Value:         value,
+		PkScript:      pkScript,
+		Confirmations: 6,
+		OutPoint: wire.OutPoint{
+			Hash:  prevTx.TxHash(),
+			Index: 0,
+		},
+		PrevTx: prevTx,
+	}}
+
+	node.localFeatures = []lnwire.FeatureBit{lnwire.DualFundOptional}
+	node.remoteFeatures = []lnwire.FeatureBit{lnwire.DualFundOptional}
+}
+
+// startDualFundChannel sets up Alice and Bob to open a dual funded channel, to
+// which Alice contributes localAmt and Bob remoteAmt. Bob's input is worth
+// more than Alice's, so Alice has to send the signatures of her input first.
+// The funding flow is taken to the point where Alice processed Bob's
+// accept_channel2 message, after which the funding transaction is constructed
+// interactively.
+func startDualFundChannel(t *testing.T, localAmt,
+	remoteAmt btcutil.Amount) (*testNode, *testNode,
+	chan *lnrpc.OpenStatusUpdate) {
+
+	t.Helper()
+
+	alice, bob := setupFundingManagers(t, func(cfg *Config) {
+		cfg.DualFundContribution = func(_ *btcec.PublicKey,
+			_ *lnwire.OpenChannel2) btcutil.Amount {
+
+			return remoteAmt
+		}
+	})
+
+	setDualFundUtxo(t, alice, btcutil.SatoshiPerBitcoin)
+	setDualFundUtxo(t, bob, 2*btcutil.SatoshiPerBitcoin)
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate, 1)
+	errChan := make(chan error, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: localAmt,
+		FundingFeePerKw: 1000,
+		DualFund:        true,
+		Updates:         updateChan,
+		Err:             errChan,
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	// Alice should open the channel with the v2 protocol.
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-errChan:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel2 message")
+	}
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel2)
+	require.True(t, ok, "expected OpenChannel2, got %T", aliceMsg)
+	require.Equal(t, localAmt, openChannelReq.FundingAmount)
+	require.EqualValues(t, fundingBroadcastHeight, openChannelReq.LockTime)
+
+	// Bob accepts the channel, adding his contribution.
+	bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+	acceptChannelResp := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel2",
+	).(*lnwire.AcceptChannel2)
+	require.Equal(t, remoteAmt, acceptChannelResp.FundingAmount)
+
+	assertNumPendingReservations(t, alice, bobPubKey, 1)
+	assertNumPendingReservations(t, bob, alicePubKey, 1)
+
+	alice.fundingMgr.ProcessFundingMsg(acceptChannelResp, bob)
+
+	return alice, bob, updateChan
+}
+
+// relayDualFundMsgs relays all messages between Alice and Bob until both of
+// them published the funding transaction of a dual funded channel, which is
+// returned. If stop returns true for a message, it's not relayed and nil is
+// returned for the transactions that weren't published yet.
+func relayDualFundMsgs(t *testing.T, alice, bob *testNode,
+	stop func(lnwire.Message, *testNode) bool) (*wire.MsgTx, *wire.MsgTx) {
+
+	t.Helper()
+
+	var alicePubl, bobPubl *wire.MsgTx
+	for alicePubl == nil || bobPubl == nil {
+		var (
+			msg      lnwire.Message
+			from, to *testNode
+		)
+		select {
+		case msg = <-alice.msgChan:
+			from, to = alice, bob
+		case msg = <-bob.msgChan:
+			from, to = bob, alice
+		case alicePubl = <-alice.publTxChan:
+			continue
+		case bobPubl = <-bob.publTxChan:
+			continue
+		case <-time.After(time.Second * 5):
+			t.Fatalf("dual funded channel open stalled")
+		}
+
+		if errMsg, ok := msg.(*lnwire.Error); ok {
+			t.Fatalf("funding failed: %v", errMsg.Error())
+		}
+		if stop != nil && stop(msg, from) {
+			return alicePubl, bobPubl
+		}
+
+		to.fundingMgr.ProcessFundingMsg(msg, from)
+	}
+
+	return alicePubl, bobPubl
+}
+
+// TestFundingManagerDualFund tests that a channel funded by both parties is
+// opened through the v2 channel establishment protocol, with the responder
+// contributing the amount its liquidity policy decides on.
+func TestFundingManagerDualFund(t *testing.T) {
+	t.Parallel()
+
+	const (
+		localAmt  = btcutil.Amount(500000)
+		remoteAmt = btcutil.Amount(300000)
+		capacity  = localAmt + remoteAmt
+	)
+
+	alice, bob, updateChan := startDualFundChannel(t, localAmt, remoteAmt)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	// From here on, the funding transaction is constructed and signed
+	// interactively. We relay all messages between Alice and Bob until
+	// both of them published the funding transaction. Alice must sign
+	// first, so Bob can't send his signatures before he got hers.
+	var aliceTxSigs bool
+	alicePubl, bobPubl := relayDualFundMsgs(
+		t, alice, bob, func(msg lnwire.Message, from *testNode) bool {
+			if _, ok := msg.(*lnwire.TxSignatures); !ok {
+				return false
+			}
+			if from == alice {
+				aliceTxSigs = true
+			}
+			require.True(t, aliceTxSigs, "bob signed first")
+
+			return false
+		},
+	)
+
+	// Both published the same funding transaction, spending the inputs of
+	// both parties to a funding output worth their total contribution.
+	fundingTx := alicePubl
+	require.Equal(t, fundingTx.TxHash(), bobPubl.TxHash())
+	require.EqualValues(t, fundingBroadcastHeight, fundingTx.LockTime)
+	require.Len(t, fundingTx.TxIn, 2)
+	for _, txIn := range fundingTx.TxIn {
+		require.NotEmpty(t, txIn.Witness)
+	}
+
+	select {
+	case update := <-updateChan:
+		_, ok := update.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
+		require.True(t, ok, "expected ChanPending, got %T",
+			update.Update)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenStatusUpdate_ChanPending")
+	}
+
+	// The reservations are gone, and both have the channel pending with
+	// their contribution as their balance.
+	assertNumPendingReservations(t, alice, bobPubKey, 0)
+	assertNumPendingReservations(t, bob, alicePubKey, 0)
+
+	aliceChans, err := alice.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, aliceChans, 1)
+
+	bobChans, err := bob.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, bobChans, 1)
+
+	fundingOutPoint := aliceChans[0].FundingOutpoint
+	require.Equal(t, fundingOutPoint, bobChans[0].FundingOutpoint)
+	require.Equal(t, fundingTx.TxHash(), fundingOutPoint.Hash)
+	require.EqualValues(
+		t, capacity, fundingTx.TxOut[fundingOutPoint.Index].Value,
+	)
+	require.Equal(t, capacity, aliceChans[0].Capacity)
+	require.True(t, aliceChans[0].IsInitiator)
+	require.False(t, bobChans[0].IsInitiator)
+	require.Equal(
+		t, lnwire.NewMSatFromSatoshis(remoteAmt),
+		bobChans[0].LocalCommitment.LocalBalance,
+	)
+	require.Equal(
+		t, lnwire.NewMSatFromSatoshis(remoteAmt),
+		aliceChans[0].LocalCommitment.RemoteBalance,
+	)
+
+	// Once the funding transaction confirms, the channel proceeds like a
+	// single funded one.
+	alice.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+	bob.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+
+	assertMarkedOpen(t, alice, bob, &fundingOutPoint)
+	assertFundingMsgSent(t, alice.msgChan, "FundingLocked")
+	assertFundingMsgSent(t, bob.msgChan, "FundingLocked")
+}
+
+// TestFundingManagerDualFundResume tests that the exchange of the signatures
+// of the funding transaction of a dual funded channel is resumed if a party
+// restarts after storing the channel as pending.
+func TestFundingManagerDualFundResume(t *testing.T) {
+	t.Parallel()
+
+	alice, bob, _ := startDualFundChannel(t, 500000, 300000)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	// Alice's signatures get lost, as she restarts right after sending
+	// them.
+	isTxSigs := func(msg lnwire.Message, _ *testNode) bool {
+		_, ok := msg.(*lnwire.TxSignatures)
+		return ok
+	}
+	alicePubl, bobPubl := relayDualFundMsgs(t, alice, bob, isTxSigs)
+	require.Nil(t, alicePubl)
+	require.Nil(t, bobPubl)
+
+	assertNumPendingChannelsBecomes(t, alice, 1)
+	assertNumPendingChannelsBecomes(t, bob, 1)
+
+	recreateAliceFundingManager(t, alice)
+
+	// Once Bob is online, Alice sends her signatures again and the
+	// exchange completes, after which both publish the funding
+	// transaction.
+	alicePubl, bobPubl = relayDualFundMsgs(t, alice, bob, nil)
+	require.Equal(t, alicePubl.TxHash(), bobPubl.TxHash())
+	for _, txIn := range alicePubl.TxIn {
+		require.NotEmpty(t, txIn.Witness)
+	}
+
+	pendingChans, err := alice.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, pendingChans, 1)
+	require.Equal(
+		t, alicePubl.TxHash(), pendingChans[0].FundingTxn.TxHash(),
+	)
+
+	_, err = alice.fundingMgr.fetchDualFundTxSigs(
+		&pendingChans[0].FundingOutpoint,
+	)
+	require.Equal(t, ErrChannelNotFound, err)
+
+}
+
+// TestFundingManagerDualFundCancel tests that a pending dual funded channel is
+// deleted if the exchange of the signatures of the funding transaction fails
+// before we sent ours, as the funding transaction can't be broadcast then.
+func TestFundingManagerDualFundCancel(t *testing.T) {
+	t.Parallel()
+
+	alice, bob, _ := startDualFundChannel(t, 500000, 300000)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	// Alice sends an error instead of her signatures. Bob hasn't sent his
+	// signatures, so he deletes the pending channel.
+	var txSigs *lnwire.TxSignatures
+	relayDualFundMsgs(t, alice, bob, func(msg lnwire.Message,
+		_ *testNode) bool {
+
+		txSigs, _ = msg.(*lnwire.TxSignatures)
+		return txSigs != nil
+	})
+	assertNumPendingChannelsBecomes(t, bob, 1)
+
+	bob.fundingMgr.ProcessFundingMsg(&lnwire.Error{
+		ChanID: txSigs.ChanID,
+		Data:   []byte("canceled"),
+	}, alice)
+	assertNumPendingChannelsBecomes(t, bob, 0)
+
+	// Alice did send her signatures, so she keeps the channel pending,
+	// in case Bob broadcasts the funding transaction.
+	assertNumPendingChannelsRemains(t, alice, 1)
+}
+
+// setTaprootFeatures makes the node and its view of the remote peer signal
+// support for simple taproot channels.
+func setTaprootFeatures(node *testNode) {
+	features := []lnwire.FeatureBit{
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+		lnwire.ExplicitChannelTypeOptional,
+		lnwire.SimpleTaprootChansOptional,
+	}
+	node.localFeatures = features
+	node.remoteFeatures = features
+}
+
+// TestFundingManagerTaprootChannel tests that a private simple taproot
+// channel can be negotiated through the explicit channel type, and that its
+// BIP-340 commitment signatures are accepted by both parties.
+func TestFundingManagerTaprootChannel(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	setTaprootFeatures(alice)
+	setTaprootFeatures(bob)
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate, 1)
+	errChan := make(chan error, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		FundingFeePerKw: 1000,
+		Private:         true,
+		ChannelType: lnwire.NewChannelType(
+			lnwire.SimpleTaprootChansRequired,
+		),
+		Updates: updateChan,
+		Err:     errChan,
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	openChannelReq := assertFundingMsgSent(
+		t, alice.msgChan, "OpenChannel",
+	).(*lnwire.OpenChannel)
+	require.Equal(t, initReq.ChannelType, openChannelReq.ChannelType)
+
+	bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+	acceptChannelResp := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+	require.Equal(t, initReq.ChannelType, acceptChannelResp.ChannelType)
+
+	alice.fundingMgr.ProcessFundingMsg(acceptChannelResp, bob)
+	fundingCreated := assertFundingMsgSent(
+		t, alice.msgChan, "FundingCreated",
+	).(*lnwire.FundingCreated)
+
+	bob.fundingMgr.ProcessFundingMsg(fundingCreated, alice)
+	fundingSigned := assertFundingMsgSent(
+		t, bob.msgChan, "FundingSigned",
+	).(*lnwire.FundingSigned)
+
+	alice.fundingMgr.ProcessFundingMsg(fundingSigned, bob)
+
+	var publ *wire.MsgTx
+	select {
+	case publ = <-alice.publTxChan:
+	case err := <-errChan:
+		t.Fatalf("error opening taproot channel: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not publish funding tx")
+	}
+
+	// The funding output of a taproot channel is a P2TR output.
+	fundingOut := publ.TxOut[fundingCreated.FundingPoint.Index]
+	require.True(t, input.IsPayToTaproot(fundingOut.PkScript))
+
+	// Both parties now track the channel as a pending taproot channel.
+	for _, node := range []*testNode{alice, bob} {
+		pendingChans, err := node.fundingMgr.cfg.Wallet.Cfg.Database.
+			FetchPendingChannels()
+		require.NoError(t, err)
+		require.Len(t, pendingChans, 1)
+		require.True(t, pendingChans[0].ChanType.IsTaproot())
+	}
+}
+
+// TestFundingManagerPublicTaprootChannel tests that we refuse to open a
+// public simple taproot channel, as taproot channels can't be announced yet.
+func TestFundingManagerPublicTaprootChannel(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	setTaprootFeatures(alice)
+	setTaprootFeatures(bob)
+
+	errChan := make(chan error, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		FundingFeePerKw: 1000,
+		ChannelType: lnwire.NewChannelType(
+			lnwire.SimpleTaprootChansRequired,
+		),
+		Updates: make(chan *lnrpc.OpenStatusUpdate, 1),
+		Err:     errChan,
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	select {
+	case err := <-errChan:
+		require.EqualError(
+			t, err,
+			lnwallet.ErrTaprootChanUnsupported("public").Error(),
+		)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("public taproot channel wasn't rejected")
+	}
+}
@@ -0,0 +1,842 @@
+package funding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrSpliceNotSupported is returned if a splice is requested, but
+	// either we or the peer don't signal the splice feature bit.
+	ErrSpliceNotSupported = errors.New("splicing requires both peers to " +
+		"signal the splice feature")
+
+	// errUnexpectedSpliceMsg is returned when a message arrives that
+	// doesn't fit the current state of the splice.
+	errUnexpectedSpliceMsg = errors.New("unexpected splice message")
+)
+
+// SpliceReq is a local request to splice funds into or out of a channel.
+type SpliceReq struct {
+	// ChanPoint is the funding outpoint of the channel to be spliced.
+	ChanPoint *wire.OutPoint
+
+	// Amount is the amount added to the channel from our wallet if
+	// positive, or the amount removed from the channel if negative.
+	Amount btcutil.Amount
+
+	// PkScript is the script the funds removed from the channel are paid
+	// to. It's only used when splicing out.
+	PkScript []byte
+
+	// FeeRate is the fee rate of the splice transaction. The fee is paid
+	// by the initiator, from its wallet when splicing in and from its
+	// channel balance when splicing out.
+	FeeRate chainfee.SatPerKWeight
+
+	// Txid receives the txid of the splice transaction once it has been
+	// signed by both parties and broadcast.
+	Txid chan chainhash.Hash
+
+	// Err receives any error that aborts the splice.
+	Err chan error
+}
+
+// SplicerCfg holds the dependencies of a Splicer.
+type SplicerCfg struct {
+	// Channel is the channel being spliced. Its link must have been
+	// removed from the switch, so its state doesn't change while the
+	// splice is negotiated.
+	Channel *lnwallet.LightningChannel
+
+	// Peer is the remote party of the channel.
+	Peer lnpeer.Peer
+
+	// Wallet is used to fund splice-ins and to publish the splice
+	// transaction.
+	Wallet *lnwallet.LightningWallet
+
+	// ChanUpdate is our last channel update of the channel. It's
+	// persisted with the splice candidate, so that the new funding output
+	// is announced with the same routing policy. It may be nil.
+	ChanUpdate *lnwire.ChannelUpdate
+}
+
+// Splicer negotiates a single splice of a channel with the remote party. The
+// initiator proposes the splice with a splice_init message, and the remote
+// party accepts it with a splice_ack message. Afterwards the splice
+// transaction, spending the current funding output, is constructed using the
+// interactive transaction protocol of dual funded channels, the commitments
+// spending the new funding output are signed, and finally both parties
+// exchange their signatures for the splice transaction.
+//
+// Only the initiator contributes to a splice. The commitments of the current
+// state spending the new funding output are persisted as a splice candidate
+// of the channel before we sign the splice transaction, so the chain watcher
+// can move the channel to the new funding output once it confirms.
+type Splicer struct {
+	cfg SplicerCfg
+
+	// req is the local request that started the splice. It's nil if the
+	// remote party initiated the splice.
+	req *SpliceReq
+
+	// chanID is the channel ID of the channel being spliced.
+	chanID lnwire.ChannelID
+
+	// initiator is true if we proposed the splice.
+	initiator bool
+
+	// contribution is the initiator's contribution to the channel, which
+	// is negative when splicing out.
+	contribution btcutil.Amount
+
+	// lockTime is the locktime of the splice transaction, as chosen by
+	// the initiator.
+	lockTime uint32
+
+	// intent holds the wallet inputs and change of a splice-in.
+	intent *chanfunding.FullIntent
+
+	// spliceOut is the output paying the funds removed from the channel
+	// to the initiator.
+	spliceOut *wire.TxOut
+
+	// fundingScript is the pkScript of the new funding output.
+	fundingScript []byte
+
+	// tx is the interactive construction of the splice transaction.
+	tx *interactiveTx
+
+	// pendingMsgs are the messages adding our inputs and outputs that we
+	// have yet to send. Only a single message is sent per turn.
+	pendingMsgs []lnwire.Message
+
+	// ourTurn is true if the next interactive tx message is ours to send.
+	ourTurn bool
+
+	// splice is the splice candidate. It's set once both parties signaled
+	// that the construction of the splice transaction is complete.
+	splice *lnwallet.SpliceCandidate
+
+	// sharedIndex is the index of the input spending the current funding
+	// output within the splice transaction.
+	sharedIndex int
+
+	// persisted is true once the remote party's commitment signature was
+	// received and the splice candidate was persisted.
+	persisted bool
+
+	// sharedSig is our signature for the input spending the current
+	// funding output. It's set once we sent our tx_signatures message.
+	sharedSig *lnwire.Sig
+
+	// done is true once the splice transaction has been broadcast.
+	done bool
+}
+
+// validateSpliceChannel checks whether the channel can be spliced with the
+// remote party.
+func validateSpliceChannel(cfg *SplicerCfg) error {
+	chanState := cfg.Channel.State()
+
+	switch {
+	case !hasFeatures(
+		cfg.Peer.LocalFeatures(), cfg.Peer.RemoteFeatures(),
+		lnwire.SpliceOptional,
+	):
+		return ErrSpliceNotSupported
+
+	case chanState.IsPending:
+		return fmt.Errorf("channel is pending")
+
+	case chanState.ChanStatus() != channeldb.ChanStatusDefault:
+		return fmt.Errorf("channel has status %v", chanState.ChanStatus())
+
+	// The short channel ID of these channels isn't derived from the
+	// funding outpoint, or the funding output is tied to a lease, so we
+	// don't move them to a new funding output.
+	case chanState.ChanType.IsZeroConf(),
+		chanState.ChanType.HasScidAliasChan(),
+		chanState.ChanType.IsFrozen():
+
+		return fmt.Errorf("channel type %v can't be spliced",
+			chanState.ChanType)
+
+	case !cfg.Channel.IsQuiescent():
+		return lnwallet.ErrChannelNotQuiescent
+	}
+
+	pendingSplices, err := chanState.SpliceCandidates()
+	if err != nil {
+		return err
+	}
+	if len(pendingSplices) != 0 {
+		return fmt.Errorf("channel already has a pending splice")
+	}
+
+	return nil
+}
+
+// validateContribution checks that the balance of the initiator stays above
+// its reserve once its contribution is applied.
+func validateContribution(chanState *channeldb.OpenChannel,
+	contribution btcutil.Amount, initiator bool) error {
+
+	balance := chanState.LocalCommitment.RemoteBalance.ToSatoshis()
+	reserve := chanState.RemoteChanCfg.ChanReserve
+	if initiator {
+		balance = chanState.LocalCommitment.LocalBalance.ToSatoshis()
+		reserve = chanState.LocalChanCfg.ChanReserve
+	}
+
+	if balance+contribution < reserve {
+		return fmt.Errorf("splice contribution %v leaves balance of "+
+			"%v below reserve of %v", contribution,
+			balance+contribution, reserve)
+	}
+
+	return nil
+}
+
+// NewSplicer creates the splicer of a locally initiated splice. For a
+// splice-in our wallet inputs are selected, and for a splice-out the fee is
+// taken from our channel balance. The returned splice_init message must be
+// sent to the remote party.
+func NewSplicer(cfg SplicerCfg, req *SpliceReq,
+	lockTime uint32) (*Splicer, *lnwire.SpliceInit, error) {
+
+	if err := validateSpliceChannel(&cfg); err != nil {
+		return nil, nil, err
+	}
+
+	chanState := cfg.Channel.State()
+	s := &Splicer{
+		cfg:       cfg,
+		req:       req,
+		chanID:    lnwire.NewChanIDFromOutPoint(&chanState.FundingOutpoint),
+		initiator: true,
+		lockTime:  lockTime,
+	}
+
+	// The initiator pays for the input spending the current funding
+	// output on top of the inputs and outputs it adds.
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(input.MultiSigWitnessSize)
+	sharedInputFee := req.FeeRate.FeeForWeight(
+		int64(weightEstimate.Weight()),
+	)
+
+	switch {
+	case req.Amount > 0:
+		wallet := cfg.Wallet
+		assembler := chanfunding.NewWalletAssembler(
+			chanfunding.WalletConfig{
+				CoinSource:       lnwallet.NewCoinSource(wallet),
+				CoinSelectLocker: wallet,
+				CoinLocker:       wallet,
+				Signer:           wallet.Cfg.Signer,
+				DustLimit:        lnwallet.DefaultDustLimit(),
+			},
+		)
+		intent, err := assembler.ProvisionChannel(&chanfunding.Request{
+			LocalAmt: req.Amount + sharedInputFee,
+			MinConfs: 1,
+			FeeRate:  req.FeeRate,
+			ChangeAddr: func() (btcutil.Address, error) {
+				return wallet.NewAddress(
+					lnwallet.WitnessPubKey, true,
+					lnwallet.DefaultAccountName,
+				)
+			},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		s.intent = intent.(*chanfunding.FullIntent)
+		s.contribution = req.Amount
+
+	case req.Amount < 0:
+		s.spliceOut = &wire.TxOut{
+			Value:    int64(-req.Amount),
+			PkScript: req.PkScript,
+		}
+		if -req.Amount < lnwallet.DefaultDustLimit() {
+			return nil, nil, fmt.Errorf("splice-out amount %v is "+
+				"dust", -req.Amount)
+		}
+
+		weightEstimate.AddP2WSHOutput()
+		weightEstimate.AddTxOutput(s.spliceOut)
+		fee := req.FeeRate.FeeForWeight(int64(weightEstimate.Weight()))
+		s.contribution = req.Amount - fee
+
+	default:
+		return nil, nil, fmt.Errorf("splice amount must not be zero")
+	}
+
+	err := validateContribution(chanState, s.contribution, true)
+	if err != nil {
+		s.cancel()
+		return nil, nil, err
+	}
+
+	log.Infof("Initiating splice of ChannelPoint(%v) with "+
+		"contribution %v", chanState.FundingOutpoint, s.contribution)
+
+	return s, &lnwire.SpliceInit{
+		ChanID:               s.chanID,
+		FundingContribution:  s.contribution,
+		FundingFeePerKWeight: uint32(req.FeeRate),
+		LockTime:             lockTime,
+		FundingKey:           chanState.LocalChanCfg.MultiSigKey.PubKey,
+	}, nil
+}
+
+// NewSpliceResponder creates the splicer of a splice initiated by the remote
+// party. We don't contribute to the splice. The returned splice_ack message
+// must be sent to the remote party.
+func NewSpliceResponder(cfg SplicerCfg,
+	msg *lnwire.SpliceInit) (*Splicer, *lnwire.SpliceAck, error) {
+
+	if err := validateSpliceChannel(&cfg); err != nil {
+		return nil, nil, err
+	}
+
+	chanState := cfg.Channel.State()
+	if !msg.FundingKey.IsEqual(chanState.RemoteChanCfg.MultiSigKey.PubKey) {
+		return nil, nil, fmt.Errorf("splice must keep the funding key")
+	}
+	err := validateContribution(chanState, msg.FundingContribution, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := &Splicer{
+		cfg:          cfg,
+		chanID:       msg.ChanID,
+		contribution: msg.FundingContribution,
+		lockTime:     msg.LockTime,
+	}
+	if err := s.startInteractiveTx(); err != nil {
+		return nil, nil, err
+	}
+
+	log.Infof("Accepting splice of ChannelPoint(%v) with remote "+
+		"contribution %v", chanState.FundingOutpoint, s.contribution)
+
+	return s, &lnwire.SpliceAck{
+		ChanID:     s.chanID,
+		FundingKey: chanState.LocalChanCfg.MultiSigKey.PubKey,
+	}, nil
+}
+
+// ChanID returns the channel ID of the channel being spliced.
+func (s *Splicer) ChanID() lnwire.ChannelID {
+	return s.chanID
+}
+
+// Signed returns true once we sent our signature for the splice transaction.
+// From then on the splice transaction may be broadcast by the remote party, so
+// the splice can no longer be aborted and the channel must not be used until
+// the splice transaction confirms.
+func (s *Splicer) Signed() bool {
+	return s.sharedSig != nil
+}
+
+// startInteractiveTx prepares the interactive construction of the splice
+// transaction. As the initiator, the messages adding the shared input, our
+// wallet inputs, the new funding output and our other outputs are queued.
+func (s *Splicer) startInteractiveTx() error {
+	chanState := s.cfg.Channel.State()
+
+	capacity := chanState.Capacity + s.contribution
+	_, fundingOutput, err := input.GenFundingPkScript(
+		chanState.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed(),
+		chanState.RemoteChanCfg.MultiSigKey.PubKey.SerializeCompressed(),
+		int64(capacity),
+	)
+	if err != nil {
+		return err
+	}
+	s.fundingScript = fundingOutput.PkScript
+
+	s.tx = newInteractiveTx(
+		s.chanID, s.initiator, s.lockTime,
+		chanState.LocalChanCfg.DustLimit,
+	)
+	s.ourTurn = s.initiator
+
+	// Both parties know the current funding output, so it's added without
+	// its previous transaction.
+	prevOut := chanState.FundingOutpoint
+	prevTxOut, err := fundingTxOut(chanState)
+	if err != nil {
+		return err
+	}
+	s.tx.SetSharedInput(prevOut, prevTxOut)
+
+	if !s.initiator {
+		return nil
+	}
+
+	msg, err := s.tx.AddSharedInput()
+	if err != nil {
+		return err
+	}
+	s.pendingMsgs = append(s.pendingMsgs, msg)
+
+	txOuts := []*wire.TxOut{fundingOutput}
+	if s.intent != nil {
+		for _, coin := range s.intent.InputCoins {
+			prevOut := coin.OutPoint
+			utxo, err := s.cfg.Wallet.FetchInputInfo(&prevOut)
+			if err != nil {
+				return err
+			}
+			if utxo.PrevTx == nil {
+				return fmt.Errorf("previous tx of input %v "+
+					"unknown", prevOut)
+			}
+
+			msg, err := s.tx.AddLocalInput(
+				utxo.PrevTx, prevOut.Index,
+				maxInteractiveTxSequence,
+			)
+			if err != nil {
+				return err
+			}
+			s.pendingMsgs = append(s.pendingMsgs, msg)
+		}
+		txOuts = append(txOuts, s.intent.ChangeOutputs...)
+	}
+	if s.spliceOut != nil {
+		txOuts = append(txOuts, s.spliceOut)
+	}
+
+	for _, txOut := range txOuts {
+		msg, err := s.tx.AddLocalOutput(txOut)
+		if err != nil {
+			return err
+		}
+		s.pendingMsgs = append(s.pendingMsgs, msg)
+	}
+
+	return nil
+}
+
+// fundingTxOut returns the current funding output of the channel.
+func fundingTxOut(chanState *channeldb.OpenChannel) (*wire.TxOut, error) {
+	_, txOut, err := input.GenFundingPkScript(
+		chanState.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed(),
+		chanState.RemoteChanCfg.MultiSigKey.PubKey.SerializeCompressed(),
+		int64(chanState.Capacity),
+	)
+
+	return txOut, err
+}
+
+// ProcessMsg advances the splice with a message sent by the remote party.
+// Once the splice transaction has been signed by both parties and broadcast,
+// true is returned.
+func (s *Splicer) ProcessMsg(msg lnwire.Message) (bool, error) {
+	var err error
+	switch msg := msg.(type) {
+	case *lnwire.SpliceAck:
+		err = s.handleSpliceAck(msg)
+
+	case *lnwire.TxAddInput, *lnwire.TxAddOutput, *lnwire.TxRemoveInput,
+		*lnwire.TxRemoveOutput, *lnwire.TxComplete:
+
+		err = s.handleInteractiveTxMsg(msg)
+
+	case *lnwire.CommitSig:
+		err = s.handleCommitSig(msg)
+
+	case *lnwire.TxSignatures:
+		err = s.handleTxSignatures(msg)
+
+	default:
+		err = fmt.Errorf("%w: %v", errUnexpectedSpliceMsg,
+			msg.MsgType())
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return s.done, nil
+}
+
+// handleSpliceAck processes the remote party's acceptance of our splice and
+// starts the interactive construction of the splice transaction by sending
+// our first input.
+func (s *Splicer) handleSpliceAck(msg *lnwire.SpliceAck) error {
+	if !s.initiator || s.tx != nil {
+		return fmt.Errorf("%w: splice_ack", errUnexpectedSpliceMsg)
+	}
+
+	chanState := s.cfg.Channel.State()
+	switch {
+	case !msg.FundingKey.IsEqual(chanState.RemoteChanCfg.MultiSigKey.PubKey):
+		return fmt.Errorf("splice must keep the funding key")
+
+	case msg.FundingContribution != 0:
+		return fmt.Errorf("remote contribution to splice not supported")
+	}
+
+	if err := s.startInteractiveTx(); err != nil {
+		return err
+	}
+
+	return s.sendInteractiveTxMsg()
+}
+
+// sendInteractiveTxMsg sends our next message of the interactive construction
+// of the splice transaction. Once all our inputs and outputs are added, we
+// signal that we're done with a tx_complete message.
+func (s *Splicer) sendInteractiveTxMsg() error {
+	var msg lnwire.Message
+	if len(s.pendingMsgs) > 0 {
+		msg = s.pendingMsgs[0]
+		s.pendingMsgs = s.pendingMsgs[1:]
+
+		// Any change to the transaction restarts the final round of
+		// tx_complete messages.
+		s.tx.resetComplete()
+	} else {
+		msg = s.tx.LocalComplete()
+	}
+	s.ourTurn = false
+
+	return s.cfg.Peer.SendMessage(true, msg)
+}
+
+// handleInteractiveTxMsg processes a message of the interactive construction
+// of the splice transaction sent by the remote party, and replies with our
+// next message. Once both parties sent a tx_complete message in a row, the
+// commitments spending the new funding output are signed.
+func (s *Splicer) handleInteractiveTxMsg(msg lnwire.Message) error {
+	if s.tx == nil || s.ourTurn || s.splice != nil {
+		return fmt.Errorf("%w: %v", errUnexpectedSpliceMsg,
+			msg.MsgType())
+	}
+
+	if err := s.tx.ProcessMsg(msg); err != nil {
+		return err
+	}
+	s.ourTurn = true
+
+	if !s.tx.IsComplete() {
+		if err := s.sendInteractiveTxMsg(); err != nil {
+			return err
+		}
+		if !s.tx.IsComplete() {
+			return nil
+		}
+	}
+
+	return s.finishInteractiveTx()
+}
+
+// finishInteractiveTx is called once the splice transaction is constructed.
+// After making sure the initiator pays for its contribution, we sign the
+// remote party's commitment spending the new funding output.
+func (s *Splicer) finishInteractiveTx() error {
+	spliceTx, err := s.tx.Tx()
+	if err != nil {
+		return err
+	}
+
+	chanState := s.cfg.Channel.State()
+	capacity := chanState.Capacity + s.contribution
+
+	// Only the initiator adds inputs and outputs, and there must be
+	// exactly one new funding output paying the new capacity.
+	var numFundingOutputs int
+	for _, txOut := range spliceTx.TxOut {
+		if bytes.Equal(txOut.PkScript, s.fundingScript) {
+			numFundingOutputs++
+		}
+	}
+	initiatorNet := s.tx.NetContribution(s.initiator, s.fundingScript)
+	switch {
+	case numFundingOutputs != 1:
+		return fmt.Errorf("splice tx has %d funding outputs",
+			numFundingOutputs)
+
+	case s.tx.NetContribution(!s.initiator, s.fundingScript) != 0:
+		return fmt.Errorf("non-initiator added inputs or outputs to " +
+			"splice tx")
+
+	case initiatorNet < s.contribution:
+		return fmt.Errorf("initiator inputs fund %v, expected at "+
+			"least %v", initiatorNet, s.contribution)
+	}
+
+	splice, err := lnwallet.NewSpliceCandidate(
+		spliceTx, chanState.LocalChanCfg.MultiSigKey.PubKey,
+		chanState.RemoteChanCfg.MultiSigKey.PubKey,
+	)
+	if err != nil {
+		return err
+	}
+	if splice.Capacity != capacity {
+		return fmt.Errorf("splice funding output pays %v, expected %v",
+			splice.Capacity, capacity)
+	}
+
+	sharedIndex, ok := s.tx.SharedInputIndex(spliceTx)
+	if !ok {
+		return fmt.Errorf("splice tx doesn't spend funding output")
+	}
+
+	log.Debugf("Constructed splice tx for ChannelPoint(%v): %v",
+		chanState.FundingOutpoint, spew.Sdump(spliceTx))
+
+	localContribution, remoteContribution := s.contribution,
+		btcutil.Amount(0)
+	if !s.initiator {
+		localContribution, remoteContribution = 0, s.contribution
+	}
+	commitSig, err := s.cfg.Channel.SignSpliceCommitment(
+		splice, localContribution, remoteContribution,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.splice = splice
+	s.sharedIndex = sharedIndex
+
+	return s.cfg.Peer.SendMessage(true, &lnwire.CommitSig{
+		ChanID:    s.chanID,
+		CommitSig: commitSig,
+	})
+}
+
+// handleCommitSig processes the remote party's signature for our commitment
+// spending the new funding output. Once it's verified the splice candidate
+// is persisted, and the non-initiator sends its signature for the splice
+// transaction, as it has no inputs of its own to protect.
+func (s *Splicer) handleCommitSig(msg *lnwire.CommitSig) error {
+	if s.splice == nil || s.persisted {
+		return fmt.Errorf("%w: commitment signature",
+			errUnexpectedSpliceMsg)
+	}
+
+	err := s.cfg.Channel.ReceiveSpliceCommitSig(s.splice, msg.CommitSig)
+	if err != nil {
+		return err
+	}
+
+	s.splice.ChannelUpdate = s.cfg.ChanUpdate
+	if err := s.cfg.Channel.AddPendingSplice(s.splice); err != nil {
+		return err
+	}
+	s.persisted = true
+
+	if s.initiator {
+		return nil
+	}
+
+	return s.sendTxSignatures()
+}
+
+// sendTxSignatures sends the witnesses of our inputs to the splice
+// transaction along with our signature for the shared input.
+func (s *Splicer) sendTxSignatures() error {
+	sharedSig, err := s.cfg.Channel.SignSpliceInput(
+		s.splice, s.sharedIndex,
+	)
+	if err != nil {
+		return err
+	}
+	s.sharedSig = &sharedSig
+
+	spliceTx := s.splice.SpliceTx
+	inputIndex := make(map[wire.OutPoint]int, len(spliceTx.TxIn))
+	for i, txIn := range spliceTx.TxIn {
+		inputIndex[txIn.PreviousOutPoint] = i
+	}
+
+	msg := &lnwire.TxSignatures{
+		ChanID:         s.chanID,
+		TxHash:         spliceTx.TxHash(),
+		SharedInputSig: &sharedSig,
+	}
+	for _, prevOut := range s.tx.LocalInputs() {
+		witness := spliceTx.TxIn[inputIndex[prevOut]].Witness
+		msg.Witnesses = append(msg.Witnesses, witness)
+	}
+
+	return s.cfg.Peer.SendMessage(true, msg)
+}
+
+// handleTxSignatures processes the remote party's signatures for the splice
+// transaction. Once they are verified, the initiator signs its inputs and
+// sends its signatures, and the fully signed splice transaction is
+// broadcast.
+func (s *Splicer) handleTxSignatures(msg *lnwire.TxSignatures) error {
+	if !s.persisted || s.done || (!s.initiator && s.sharedSig == nil) {
+		return fmt.Errorf("%w: tx signatures", errUnexpectedSpliceMsg)
+	}
+
+	spliceTx := s.splice.SpliceTx
+	remoteInputs := s.tx.RemoteInputs()
+	switch {
+	case msg.TxHash != spliceTx.TxHash():
+		return fmt.Errorf("tx signatures for tx %v, expected %v",
+			msg.TxHash, spliceTx.TxHash())
+
+	case msg.SharedInputSig == nil:
+		return fmt.Errorf("tx signatures lack shared input signature")
+
+	case len(msg.Witnesses) != len(remoteInputs):
+		return fmt.Errorf("received %d witnesses for %d inputs",
+			len(msg.Witnesses), len(remoteInputs))
+	}
+
+	chanState := s.cfg.Channel.State()
+
+	inputIndex := make(map[wire.OutPoint]int, len(spliceTx.TxIn))
+	for i, txIn := range spliceTx.TxIn {
+		inputIndex[txIn.PreviousOutPoint] = i
+	}
+	for i, prevOut := range remoteInputs {
+		spliceTx.TxIn[inputIndex[prevOut]].Witness = msg.Witnesses[i]
+	}
+
+	// Make sure each of the remote party's witnesses is valid, as we
+	// couldn't publish the splice transaction otherwise.
+	hashCache := txscript.NewTxSigHashes(spliceTx)
+	for _, prevOut := range remoteInputs {
+		output, _ := s.tx.PrevOutput(prevOut)
+		vm, err := txscript.NewEngine(
+			output.PkScript, spliceTx, inputIndex[prevOut],
+			txscript.StandardVerifyFlags, nil, hashCache,
+			output.Value,
+		)
+		if err == nil {
+			err = vm.Execute()
+		}
+		if err != nil {
+			return fmt.Errorf("invalid witness for input %v: %v",
+				prevOut, err)
+		}
+	}
+
+	// If the remote party signed first, it's our turn now.
+	if s.sharedSig == nil {
+		if s.intent != nil {
+			s.intent.BindKeys(
+				&chanState.LocalChanCfg.MultiSigKey,
+				chanState.RemoteChanCfg.MultiSigKey.PubKey,
+			)
+			remoteAmt := s.splice.Capacity -
+				s.intent.LocalFundingAmt()
+			err := s.intent.SignInteractiveFundingTx(
+				spliceTx, remoteAmt,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := s.sendTxSignatures(); err != nil {
+			return err
+		}
+	}
+
+	err := s.cfg.Channel.CompleteSpliceInput(
+		s.splice, s.sharedIndex, *s.sharedSig, *msg.SharedInputSig,
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Broadcasting splice tx %v of ChannelPoint(%v)",
+		spliceTx.TxHash(), chanState.FundingOutpoint)
+
+	// Both parties broadcast the splice transaction, as the channel is
+	// paused until it confirms.
+	label := labels.MakeLabel(
+		labels.LabelTypeSplice, &chanState.ShortChannelID,
+	)
+	if err := s.cfg.Wallet.PublishTransaction(spliceTx, label); err != nil {
+		log.Errorf("Unable to broadcast splice tx %v: %v",
+			spliceTx.TxHash(), err)
+	}
+	s.done = true
+
+	if s.req != nil {
+		s.req.Txid <- spliceTx.TxHash()
+	}
+
+	return nil
+}
+
+// Fail aborts the splice. Unless we already signed the splice transaction,
+// the wallet inputs of a splice-in are released and the splice candidate is
+// removed, so the channel can be used again.
+func (s *Splicer) Fail(err error) {
+	log.Errorf("Splice of ChannelID(%v) failed: %v", s.chanID, err)
+
+	if !s.Signed() {
+		s.cancel()
+	}
+
+	if s.persisted && !s.Signed() {
+		err := s.cfg.Channel.RemovePendingSplice(s.splice)
+		if err != nil {
+			log.Errorf("Unable to remove splice candidate %v of "+
+				"ChannelID(%v): %v", s.splice.FundingOutpoint,
+				s.chanID, err)
+		}
+	}
+
+	if s.req != nil {
+		s.req.Err <- err
+	}
+}
+
+// cancel releases the wallet inputs of a splice-in.
+func (s *Splicer) cancel() {
+	if s.intent != nil {
+		s.intent.Cancel()
+	}
+}
+
+// SpliceConfirmed resumes the announcement of a channel that was moved to the
+// funding output of a confirmed splice transaction. The new funding output is
+// added to the router graph and announced once it has six confirmations, just
+// like the funding output of a newly opened channel.
+func (f *Manager) SpliceConfirmed(channel *channeldb.OpenChannel) error {
+	shortChanID := channel.ShortChannelID
+	err := f.saveChannelOpeningState(
+		&channel.FundingOutpoint, fundingLockedSent, &shortChanID,
+	)
+	if err != nil {
+		return fmt.Errorf("error setting channel state to "+
+			"fundingLockedSent: %v", err)
+	}
+
+	f.wg.Add(1)
+	go f.advanceFundingState(channel, [32]byte{}, nil)
+
+	return nil
+}
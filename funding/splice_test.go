@@ -0,0 +1,281 @@
+package funding
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplice tests that a splice-in initiated by Alice is negotiated with Bob,
+// leaving both with the same fully signed splice transaction and a persisted
+// splice candidate.
+func TestSplice(t *testing.T) {
+	t.Parallel()
+
+	const spliceAmt = btcutil.Amount(50_000_000)
+
+	alice, bob := setupFundingManagers(t)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	aliceChan, bobChan, cleanUp, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	setDualFundUtxo(t, alice, btcutil.SatoshiPerBitcoin)
+
+	aliceWallet := alice.fundingMgr.cfg.Wallet
+	bobWallet := bob.fundingMgr.cfg.Wallet
+	aliceWallet.WalletController.(*mock.WalletController).
+		PublishedTransactions = make(chan *wire.MsgTx, 1)
+	bobWallet.WalletController.(*mock.WalletController).
+		PublishedTransactions = make(chan *wire.MsgTx, 1)
+
+	// The messages sent to Bob end up in Alice's message channel and vice
+	// versa. As both splicers are driven from the test, the channels are
+	// buffered.
+	alice.msgChan = make(chan lnwire.Message, 10)
+	bob.msgChan = make(chan lnwire.Message, 10)
+
+	chanUpdate := &lnwire.ChannelUpdate{
+		ShortChannelID:  aliceChan.ShortChanID(),
+		TimeLockDelta:   80,
+		HtlcMinimumMsat: 1000,
+		BaseFee:         2000,
+		FeeRate:         300,
+		ExtraOpaqueData: make([]byte, 0),
+	}
+	aliceCfg := SplicerCfg{
+		Channel:    aliceChan,
+		Peer:       bob,
+		Wallet:     aliceWallet,
+		ChanUpdate: chanUpdate,
+	}
+	bobCfg := SplicerCfg{
+		Channel: bobChan,
+		Peer:    alice,
+		Wallet:  bobWallet,
+	}
+
+	req := &SpliceReq{
+		ChanPoint: aliceChan.ChanPoint,
+		Amount:    spliceAmt,
+		FeeRate:   1000,
+		Txid:      make(chan chainhash.Hash, 1),
+		Err:       make(chan error, 1),
+	}
+
+	// Splicing requires both peers to signal the feature.
+	_, _, err = NewSplicer(aliceCfg, req, fundingBroadcastHeight)
+	require.Equal(t, ErrSpliceNotSupported, err)
+
+	for _, node := range []*testNode{alice, bob} {
+		node.localFeatures = []lnwire.FeatureBit{lnwire.SpliceOptional}
+		node.remoteFeatures = []lnwire.FeatureBit{lnwire.SpliceOptional}
+	}
+
+	aliceSplicer, spliceInit, err := NewSplicer(
+		aliceCfg, req, fundingBroadcastHeight,
+	)
+	require.NoError(t, err)
+	require.Equal(t, spliceAmt, spliceInit.FundingContribution)
+
+	// Bob rejects a splice changing Alice's funding key.
+	badInit := *spliceInit
+	badInit.FundingKey = bobChan.LocalFundingKey
+	_, _, err = NewSpliceResponder(bobCfg, &badInit)
+	require.Error(t, err)
+
+	bobSplicer, spliceAck, err := NewSpliceResponder(bobCfg, spliceInit)
+	require.NoError(t, err)
+
+	// From here on, the splice transaction is constructed and signed
+	// interactively. We relay all messages between Alice and Bob until
+	// both of them are done.
+	aliceDone, err := aliceSplicer.ProcessMsg(spliceAck)
+	require.NoError(t, err)
+
+	var bobDone, bobTxSigs bool
+	for !aliceDone || !bobDone {
+		select {
+		case msg := <-alice.msgChan:
+			// Bob must sign first, as he has no inputs at stake.
+			if _, ok := msg.(*lnwire.TxSignatures); ok {
+				require.True(t, bobTxSigs, "alice signed first")
+			}
+
+			bobDone, err = bobSplicer.ProcessMsg(msg)
+			require.NoError(t, err)
+
+		case msg := <-bob.msgChan:
+			if _, ok := msg.(*lnwire.TxSignatures); ok {
+				bobTxSigs = true
+			}
+
+			aliceDone, err = aliceSplicer.ProcessMsg(msg)
+			require.NoError(t, err)
+
+		case <-time.After(time.Second * 5):
+			t.Fatalf("splice stalled")
+		}
+	}
+
+	var txid chainhash.Hash
+	select {
+	case txid = <-req.Txid:
+	case err := <-req.Err:
+		t.Fatalf("splice failed: %v", err)
+	}
+
+	// Both published the same splice transaction, spending the funding
+	// output and Alice's input to a new funding output worth the old
+	// capacity plus the spliced amount.
+	alicePubl := <-aliceWallet.WalletController.(*mock.WalletController).
+		PublishedTransactions
+	bobPubl := <-bobWallet.WalletController.(*mock.WalletController).
+		PublishedTransactions
+	require.Equal(t, txid, alicePubl.TxHash())
+	require.Equal(t, txid, bobPubl.TxHash())
+	require.Len(t, alicePubl.TxIn, 2)
+	for i, txIn := range alicePubl.TxIn {
+		require.NotEmpty(t, txIn.Witness)
+		require.Equal(t, txIn.Witness, bobPubl.TxIn[i].Witness)
+	}
+
+	// Both persisted the splice candidate.
+	for _, lc := range []*lnwallet.LightningChannel{aliceChan, bobChan} {
+		candidates, err := lnwallet.FetchSpliceCandidates(lc.State())
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		require.Equal(t, txid, candidates[0].FundingOutpoint.Hash)
+		require.Equal(
+			t, aliceChan.Capacity+spliceAmt, candidates[0].Capacity,
+		)
+	}
+
+	// Alice's balance grew by the spliced amount, while Bob's is
+	// unchanged. Her channel update was persisted with the candidate, so
+	// the new funding output is announced with the same policy.
+	candidates, err := lnwallet.FetchSpliceCandidates(aliceChan.State())
+	require.NoError(t, err)
+	require.Equal(t, chanUpdate, candidates[0].ChannelUpdate)
+	localCommit := aliceChan.State().LocalCommitment
+	require.Equal(
+		t, localCommit.LocalBalance+lnwire.NewMSatFromSatoshis(spliceAmt),
+		candidates[0].LocalCommitment.LocalBalance,
+	)
+	require.Equal(
+		t, localCommit.RemoteBalance,
+		candidates[0].LocalCommitment.RemoteBalance,
+	)
+
+	// Another splice can't be started while this one is pending.
+	_, _, err = NewSplicer(aliceCfg, req, fundingBroadcastHeight)
+	require.Error(t, err)
+}
+
+// TestSpliceAbort tests that a splice aborted before we signed the splice
+// transaction drops the pending splice, while a splice aborted after that
+// keeps it, as the splice transaction may still confirm.
+func TestSpliceAbort(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	aliceChan, bobChan, cleanUp, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	setDualFundUtxo(t, alice, btcutil.SatoshiPerBitcoin)
+
+	alice.msgChan = make(chan lnwire.Message, 10)
+	bob.msgChan = make(chan lnwire.Message, 10)
+	for _, node := range []*testNode{alice, bob} {
+		node.localFeatures = []lnwire.FeatureBit{lnwire.SpliceOptional}
+		node.remoteFeatures = []lnwire.FeatureBit{lnwire.SpliceOptional}
+	}
+
+	req := &SpliceReq{
+		ChanPoint: aliceChan.ChanPoint,
+		Amount:    50_000_000,
+		FeeRate:   1000,
+		Txid:      make(chan chainhash.Hash, 1),
+		Err:       make(chan error, 1),
+	}
+	aliceSplicer, spliceInit, err := NewSplicer(
+		SplicerCfg{
+			Channel: aliceChan,
+			Peer:    bob,
+			Wallet:  alice.fundingMgr.cfg.Wallet,
+		}, req, fundingBroadcastHeight,
+	)
+	require.NoError(t, err)
+
+	bobSplicer, spliceAck, err := NewSpliceResponder(
+		SplicerCfg{
+			Channel: bobChan,
+			Peer:    alice,
+			Wallet:  bob.fundingMgr.cfg.Wallet,
+		}, spliceInit,
+	)
+	require.NoError(t, err)
+
+	_, err = aliceSplicer.ProcessMsg(spliceAck)
+	require.NoError(t, err)
+
+	// We relay all messages until Bob sends his transaction signatures,
+	// which Alice never receives.
+	var bobTxSigs bool
+	for !bobTxSigs {
+		select {
+		case msg := <-alice.msgChan:
+			_, err = bobSplicer.ProcessMsg(msg)
+			require.NoError(t, err)
+
+		case msg := <-bob.msgChan:
+			if _, ok := msg.(*lnwire.TxSignatures); ok {
+				bobTxSigs = true
+				continue
+			}
+
+			_, err = aliceSplicer.ProcessMsg(msg)
+			require.NoError(t, err)
+
+		case <-time.After(time.Second * 5):
+			t.Fatalf("splice stalled")
+		}
+	}
+
+	// Both persisted the splice candidate, but only Bob signed the splice
+	// transaction.
+	require.False(t, aliceSplicer.Signed())
+	require.True(t, bobSplicer.Signed())
+
+	abortErr := errors.New("splice aborted")
+	aliceSplicer.Fail(abortErr)
+	bobSplicer.Fail(abortErr)
+	require.Equal(t, abortErr, <-req.Err)
+
+	// Alice dropped the pending splice, so her channel can be used again,
+	// while Bob keeps it.
+	candidates, err := lnwallet.FetchSpliceCandidates(aliceChan.State())
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	candidates, err = lnwallet.FetchSpliceCandidates(bobChan.State())
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+}
@@ -200,6 +200,11 @@ func (c *PaymentCircuit) Decode(r io.Reader) error {
 		// Sphinx encrypter was used as this is a forwarded HTLC.
 		c.ErrorEncrypter = hop.NewSphinxErrorEncrypter()
 
+	case hop.EncrypterTypeIntroduction:
+		// Sphinx encrypter of the introduction node of a blinded
+		// route.
+		c.ErrorEncrypter = hop.NewIntroductionErrorEncrypter()
+
 	case hop.EncrypterTypeMock:
 		// Test encrypter.
 		c.ErrorEncrypter = NewMockObfuscator()
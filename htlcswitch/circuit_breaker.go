@@ -0,0 +1,505 @@
+package htlcswitch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultCircuitBreakerInterval is the interval at which the switch
+	// retries queued forwards while the circuit breaker holds any.
+	DefaultCircuitBreakerInterval = 100 * time.Millisecond
+
+	// maxQueuedForwards is the maximum number of forwards that the
+	// circuit breaker queues per incoming peer. Forwards beyond this
+	// number are failed.
+	maxQueuedForwards = 100
+)
+
+// CircuitBreakerMode determines how the circuit breaker handles forwards that
+// exceed the limits of their incoming peer.
+type CircuitBreakerMode uint8
+
+const (
+	// CircuitBreakerModeFail fails forwards that exceed the limits back to
+	// the incoming peer.
+	CircuitBreakerModeFail CircuitBreakerMode = iota
+
+	// CircuitBreakerModeQueue queues forwards that exceed the limits until
+	// the peer is within its limits again. Forwards are failed if the
+	// queue is full or if they are queued for longer than the htlc expiry
+	// of the switch.
+	CircuitBreakerModeQueue
+)
+
+// String returns a human readable representation of the mode.
+func (m CircuitBreakerMode) String() string {
+	switch m {
+	case CircuitBreakerModeFail:
+		return "fail"
+
+	case CircuitBreakerModeQueue:
+		return "queue"
+
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerLimits are the limits that the circuit breaker enforces for
+// the forwards of a single incoming peer. A zero value disables a limit, so
+// the zero CircuitBreakerLimits don't limit a peer at all.
+type CircuitBreakerLimits struct {
+	// MaxPendingHtlcs is the maximum number of htlcs from the peer that
+	// can be in flight on our outgoing channels at the same time.
+	MaxPendingHtlcs uint32
+
+	// MaxPendingMsat is the maximum total value of the htlcs from the peer
+	// that can be in flight on our outgoing channels at the same time.
+	MaxPendingMsat lnwire.MilliSatoshi
+
+	// HtlcRate is the number of new forwards per second that the peer is
+	// allowed on average.
+	HtlcRate float64
+
+	// HtlcBurst is the number of forwards that the peer can make at once
+	// when it didn't use its rate for a while. A burst of one forward is
+	// allowed if it is zero.
+	HtlcBurst uint32
+
+	// Mode determines what happens to forwards that exceed the limits.
+	Mode CircuitBreakerMode
+}
+
+// burst returns the size of the token bucket of the rate limit.
+func (l *CircuitBreakerLimits) burst() float64 {
+	if l.HtlcBurst == 0 {
+		return 1
+	}
+
+	return float64(l.HtlcBurst)
+}
+
+// CircuitBreakerCounters are the live counters of the circuit breaker for a
+// single incoming peer.
+type CircuitBreakerCounters struct {
+	// PendingHtlcs is the number of htlcs from the peer that are
+	// currently in flight on our outgoing channels.
+	PendingHtlcs uint32
+
+	// PendingMsat is the total value of the htlcs from the peer that are
+	// currently in flight on our outgoing channels.
+	PendingMsat lnwire.MilliSatoshi
+
+	// QueuedHtlcs is the number of forwards of the peer that are currently
+	// queued.
+	QueuedHtlcs uint32
+
+	// ForwardedHtlcs is the number of forwards of the peer that passed
+	// the circuit breaker since startup.
+	ForwardedHtlcs uint64
+
+	// RejectedHtlcs is the number of forwards of the peer that were
+	// failed by the circuit breaker since startup.
+	RejectedHtlcs uint64
+}
+
+// CircuitBreakerPeerStatus is the state of the circuit breaker for a single
+// incoming peer.
+type CircuitBreakerPeerStatus struct {
+	CircuitBreakerCounters
+
+	// Limits are the limits that apply to the peer.
+	Limits CircuitBreakerLimits
+
+	// CustomLimits is true if the limits were set for this peer
+	// specifically, and false if the default limits apply.
+	CustomLimits bool
+}
+
+// breakerDecision is the decision of the circuit breaker for a new forward.
+type breakerDecision uint8
+
+const (
+	// breakerAdmit indicates that the forward can proceed.
+	breakerAdmit breakerDecision = iota
+
+	// breakerQueue indicates that the forward was queued. It is returned
+	// from the queue once the peer is within its limits again.
+	breakerQueue
+
+	// breakerReject indicates that the forward must be failed.
+	breakerReject
+)
+
+// queuedForward is a forward that is waiting for its incoming peer to get
+// within its limits.
+type queuedForward struct {
+	packet   *htlcPacket
+	deadline time.Time
+}
+
+// peerBreaker tracks the forwards of a single incoming peer.
+type peerBreaker struct {
+	// pending holds the incoming amounts of the peer's forwards that are
+	// in flight.
+	pending     map[CircuitKey]lnwire.MilliSatoshi
+	pendingMsat lnwire.MilliSatoshi
+
+	// tokens is the content of the token bucket that limits the rate of
+	// new forwards. It was last refilled at lastRefill.
+	tokens     float64
+	lastRefill time.Time
+
+	queue []*queuedForward
+
+	forwarded uint64
+	rejected  uint64
+}
+
+// CircuitBreaker limits the htlcs that each incoming peer can have in flight
+// on our outgoing channels, and the rate at which it can add new ones. This
+// protects the channels of the node from being jammed by a single peer that
+// fills all of their htlc slots.
+//
+// The limits are persisted, so they survive a restart. In-flight htlcs are
+// tracked in memory only, so the limits only apply to forwards that were made
+// since startup.
+type CircuitBreaker struct {
+	clock clock.Clock
+
+	// store persists the default and per peer limits.
+	store *circuitBreakerStore
+
+	// queueExpiry is the maximum time that a forward is queued.
+	queueExpiry time.Duration
+
+	// queueSignal is signaled when in-flight forwards of a peer with
+	// queued forwards were released.
+	queueSignal chan struct{}
+
+	mu sync.Mutex
+
+	defaultLimits CircuitBreakerLimits
+	peerLimits    map[[33]byte]CircuitBreakerLimits
+
+	peers map[[33]byte]*peerBreaker
+
+	// pendingPeers maps the in-flight forwards to their incoming peer.
+	pendingPeers map[CircuitKey][33]byte
+}
+
+// NewCircuitBreaker creates a circuit breaker that applies the limits stored
+// in the given database. Peers aren't limited until limits are set. Forwards
+// are queued for at most queueExpiry.
+func NewCircuitBreaker(db *channeldb.DB, clock clock.Clock,
+	queueExpiry time.Duration) (*CircuitBreaker, error) {
+
+	store := newCircuitBreakerStore(db)
+	defaultLimits, peerLimits, err := store.fetchLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CircuitBreaker{
+		clock:         clock,
+		store:         store,
+		queueExpiry:   queueExpiry,
+		queueSignal:   make(chan struct{}, 1),
+		defaultLimits: defaultLimits,
+		peerLimits:    peerLimits,
+		peers:         make(map[[33]byte]*peerBreaker),
+		pendingPeers:  make(map[CircuitKey][33]byte),
+	}, nil
+}
+
+// DefaultLimits returns the limits that apply to peers without custom limits.
+func (c *CircuitBreaker) DefaultLimits() CircuitBreakerLimits {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.defaultLimits
+}
+
+// SetDefaultLimits sets and persists the limits that apply to peers without
+// custom limits.
+func (c *CircuitBreaker) SetDefaultLimits(limits CircuitBreakerLimits) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.store.storeDefaultLimits(&limits); err != nil {
+		return err
+	}
+
+	c.defaultLimits = limits
+	c.signalQueue()
+
+	return nil
+}
+
+// SetPeerLimits sets and persists custom limits for the given peer.
+func (c *CircuitBreaker) SetPeerLimits(peer [33]byte,
+	limits CircuitBreakerLimits) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.store.storePeerLimits(peer, &limits); err != nil {
+		return err
+	}
+
+	c.peerLimits[peer] = limits
+	c.signalQueue()
+
+	return nil
+}
+
+// ClearPeerLimits removes the custom limits of the given peer, so that the
+// default limits apply to it again.
+func (c *CircuitBreaker) ClearPeerLimits(peer [33]byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.store.deletePeerLimits(peer); err != nil {
+		return err
+	}
+
+	delete(c.peerLimits, peer)
+	c.signalQueue()
+
+	return nil
+}
+
+// Status returns the limits and counters of all peers that have custom limits
+// or forwarded through us since startup.
+func (c *CircuitBreaker) Status() map[[33]byte]*CircuitBreakerPeerStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := make(map[[33]byte]*CircuitBreakerPeerStatus)
+	for peer, limits := range c.peerLimits {
+		status[peer] = &CircuitBreakerPeerStatus{
+			Limits:       limits,
+			CustomLimits: true,
+		}
+	}
+
+	for peer, p := range c.peers {
+		peerStatus, ok := status[peer]
+		if !ok {
+			peerStatus = &CircuitBreakerPeerStatus{
+				Limits: c.defaultLimits,
+			}
+			status[peer] = peerStatus
+		}
+
+		peerStatus.CircuitBreakerCounters = CircuitBreakerCounters{
+			PendingHtlcs:   uint32(len(p.pending)),
+			PendingMsat:    p.pendingMsat,
+			QueuedHtlcs:    uint32(len(p.queue)),
+			ForwardedHtlcs: p.forwarded,
+			RejectedHtlcs:  p.rejected,
+		}
+	}
+
+	return status
+}
+
+// limits returns the limits that apply to the given peer.
+//
+// NOTE: The caller must hold the lock.
+func (c *CircuitBreaker) limits(peer [33]byte) CircuitBreakerLimits {
+	if limits, ok := c.peerLimits[peer]; ok {
+		return limits
+	}
+
+	return c.defaultLimits
+}
+
+// peer returns the state of the given peer, creating it if needed.
+//
+// NOTE: The caller must hold the lock.
+func (c *CircuitBreaker) peer(peer [33]byte) *peerBreaker {
+	p, ok := c.peers[peer]
+	if !ok {
+		limits := c.limits(peer)
+		p = &peerBreaker{
+			pending:    make(map[CircuitKey]lnwire.MilliSatoshi),
+			tokens:     limits.burst(),
+			lastRefill: c.clock.Now(),
+		}
+		c.peers[peer] = p
+	}
+
+	return p
+}
+
+// signalQueue wakes up the switch to retry queued forwards. It doesn't block
+// if a signal is already pending.
+func (c *CircuitBreaker) signalQueue() {
+	select {
+	case c.queueSignal <- struct{}{}:
+	default:
+	}
+}
+
+// tryAdmit admits the forward if the peer is within its limits.
+//
+// NOTE: The caller must hold the lock.
+func (c *CircuitBreaker) tryAdmit(peer [33]byte, p *peerBreaker,
+	limits CircuitBreakerLimits, pkt *htlcPacket) bool {
+
+	now := c.clock.Now()
+
+	// Refill the token bucket for the time that passed since the last
+	// refill. Without a rate limit the bucket is kept full, so that a
+	// rate limit that is set later starts with a full burst.
+	elapsed := now.Sub(p.lastRefill).Seconds()
+	p.tokens += elapsed * limits.HtlcRate
+	if limits.HtlcRate == 0 || p.tokens > limits.burst() {
+		p.tokens = limits.burst()
+	}
+	p.lastRefill = now
+
+	switch {
+	case limits.MaxPendingHtlcs > 0 &&
+		uint32(len(p.pending)) >= limits.MaxPendingHtlcs:
+
+		return false
+
+	case limits.MaxPendingMsat > 0 &&
+		p.pendingMsat+pkt.incomingAmount > limits.MaxPendingMsat:
+
+		return false
+
+	case limits.HtlcRate > 0 && p.tokens < 1:
+		return false
+	}
+
+	if limits.HtlcRate > 0 {
+		p.tokens--
+	}
+
+	key := pkt.inKey()
+	p.pending[key] = pkt.incomingAmount
+	p.pendingMsat += pkt.incomingAmount
+	p.forwarded++
+	c.pendingPeers[key] = peer
+
+	return true
+}
+
+// admit decides whether a new forward from the given peer can proceed. A
+// forward that was already admitted, for example from the queue, is always
+// admitted again.
+func (c *CircuitBreaker) admit(peer [33]byte,
+	pkt *htlcPacket) breakerDecision {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.pendingPeers[pkt.inKey()]; ok {
+		return breakerAdmit
+	}
+
+	limits := c.limits(peer)
+	p := c.peer(peer)
+
+	// Forwards are admitted in order, so a new forward can't overtake the
+	// ones that are already queued.
+	if len(p.queue) == 0 && c.tryAdmit(peer, p, limits, pkt) {
+		return breakerAdmit
+	}
+
+	if limits.Mode == CircuitBreakerModeQueue &&
+		len(p.queue) < maxQueuedForwards {
+
+		p.queue = append(p.queue, &queuedForward{
+			packet:   pkt,
+			deadline: c.clock.Now().Add(c.queueExpiry),
+		})
+
+		return breakerQueue
+	}
+
+	p.rejected++
+
+	return breakerReject
+}
+
+// release removes a forward from the in-flight forwards of its peer. It is a
+// no-op for forwards that weren't admitted by the circuit breaker.
+func (c *CircuitBreaker) release(key CircuitKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	peer, ok := c.pendingPeers[key]
+	if !ok {
+		return
+	}
+	delete(c.pendingPeers, key)
+
+	p := c.peers[peer]
+	p.pendingMsat -= p.pending[key]
+	delete(p.pending, key)
+
+	if len(p.queue) > 0 {
+		c.signalQueue()
+	}
+}
+
+// hasQueued returns true if any forwards are queued.
+func (c *CircuitBreaker) hasQueued() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, p := range c.peers {
+		if len(p.queue) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dequeue removes the queued forwards that can proceed now and the ones that
+// expired from the queues. The forwards that can proceed are admitted.
+func (c *CircuitBreaker) dequeue() ([]*htlcPacket, []*htlcPacket) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		now      = c.clock.Now()
+		admitted []*htlcPacket
+		expired  []*htlcPacket
+	)
+	for peer, p := range c.peers {
+		limits := c.limits(peer)
+
+		var remaining []*queuedForward
+		for _, fwd := range p.queue {
+			switch {
+			case !fwd.deadline.After(now):
+				p.rejected++
+				expired = append(expired, fwd.packet)
+
+			// Forwards are admitted in order. Once one of them
+			// has to wait, the ones behind it wait as well.
+			case len(remaining) == 0 &&
+				c.tryAdmit(peer, p, limits, fwd.packet):
+
+				admitted = append(admitted, fwd.packet)
+
+			default:
+				remaining = append(remaining, fwd)
+			}
+		}
+		p.queue = remaining
+	}
+
+	return admitted, expired
+}
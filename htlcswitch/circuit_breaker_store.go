@@ -0,0 +1,194 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"io"
+	"math"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// circuitBreakerBucketKey is used for the root level bucket that
+	// stores the limits of the circuit breaker.
+	circuitBreakerBucketKey = []byte("circuit-breaker-bucket")
+
+	// circuitBreakerDefaultKey is the key of the default limits within the
+	// circuit breaker bucket.
+	circuitBreakerDefaultKey = []byte("default-limits")
+
+	// circuitBreakerPeersBucketKey is the key of the sub bucket that
+	// stores the custom limits of each peer, keyed by the peer's public
+	// key.
+	circuitBreakerPeersBucketKey = []byte("peer-limits")
+)
+
+// serializeCircuitBreakerLimits serializes the circuit breaker limits.
+func serializeCircuitBreakerLimits(w io.Writer,
+	l *CircuitBreakerLimits) error {
+
+	return channeldb.WriteElements(
+		w, l.MaxPendingHtlcs, l.MaxPendingMsat,
+		math.Float64bits(l.HtlcRate), l.HtlcBurst, uint8(l.Mode),
+	)
+}
+
+// deserializeCircuitBreakerLimits deserializes the circuit breaker limits.
+func deserializeCircuitBreakerLimits(r io.Reader) (*CircuitBreakerLimits,
+	error) {
+
+	var (
+		l            CircuitBreakerLimits
+		htlcRateBits uint64
+		mode         uint8
+	)
+	err := channeldb.ReadElements(
+		r, &l.MaxPendingHtlcs, &l.MaxPendingMsat, &htlcRateBits,
+		&l.HtlcBurst, &mode,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	l.HtlcRate = math.Float64frombits(htlcRateBits)
+	l.Mode = CircuitBreakerMode(mode)
+
+	return &l, nil
+}
+
+// circuitBreakerStore persists the limits of the circuit breaker, so that the
+// limits set by the operator survive a restart. The counters of the circuit
+// breaker are not persisted.
+type circuitBreakerStore struct {
+	db *channeldb.DB
+}
+
+// newCircuitBreakerStore creates a store for the circuit breaker limits.
+func newCircuitBreakerStore(db *channeldb.DB) *circuitBreakerStore {
+	return &circuitBreakerStore{
+		db: db,
+	}
+}
+
+// putLimits stores the given limits under the key in the given bucket.
+func putLimits(bucket kvdb.RwBucket, key []byte,
+	limits *CircuitBreakerLimits) error {
+
+	var b bytes.Buffer
+	if err := serializeCircuitBreakerLimits(&b, limits); err != nil {
+		return err
+	}
+
+	return bucket.Put(key, b.Bytes())
+}
+
+// storeDefaultLimits stores the limits that apply to peers without custom
+// limits.
+func (s *circuitBreakerStore) storeDefaultLimits(
+	limits *CircuitBreakerLimits) error {
+
+	return kvdb.Update(s.db.Backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(circuitBreakerBucketKey)
+		if err != nil {
+			return err
+		}
+
+		return putLimits(bucket, circuitBreakerDefaultKey, limits)
+	}, func() {})
+}
+
+// storePeerLimits stores the custom limits of the given peer.
+func (s *circuitBreakerStore) storePeerLimits(peer [33]byte,
+	limits *CircuitBreakerLimits) error {
+
+	return kvdb.Update(s.db.Backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(circuitBreakerBucketKey)
+		if err != nil {
+			return err
+		}
+
+		peers, err := bucket.CreateBucketIfNotExists(
+			circuitBreakerPeersBucketKey,
+		)
+		if err != nil {
+			return err
+		}
+
+		return putLimits(peers, peer[:], limits)
+	}, func() {})
+}
+
+// deletePeerLimits removes the custom limits of the given peer.
+func (s *circuitBreakerStore) deletePeerLimits(peer [33]byte) error {
+	return kvdb.Update(s.db.Backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(circuitBreakerBucketKey)
+		if bucket == nil {
+			return nil
+		}
+
+		peers := bucket.NestedReadWriteBucket(
+			circuitBreakerPeersBucketKey,
+		)
+		if peers == nil {
+			return nil
+		}
+
+		return peers.Delete(peer[:])
+	}, func() {})
+}
+
+// fetchLimits returns the stored default limits and the custom limits of all
+// peers. The default limits are the zero limits if none were stored.
+func (s *circuitBreakerStore) fetchLimits() (CircuitBreakerLimits,
+	map[[33]byte]CircuitBreakerLimits, error) {
+
+	var (
+		defaultLimits CircuitBreakerLimits
+		peerLimits    map[[33]byte]CircuitBreakerLimits
+	)
+	err := kvdb.View(s.db.Backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(circuitBreakerBucketKey)
+		if bucket == nil {
+			return nil
+		}
+
+		if v := bucket.Get(circuitBreakerDefaultKey); v != nil {
+			limits, err := deserializeCircuitBreakerLimits(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			defaultLimits = *limits
+		}
+
+		peers := bucket.NestedReadBucket(circuitBreakerPeersBucketKey)
+		if peers == nil {
+			return nil
+		}
+
+		return peers.ForEach(func(k, v []byte) error {
+			var peer [33]byte
+			copy(peer[:], k)
+
+			limits, err := deserializeCircuitBreakerLimits(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			peerLimits[peer] = *limits
+
+			return nil
+		})
+	}, func() {
+		defaultLimits = CircuitBreakerLimits{}
+		peerLimits = make(map[[33]byte]CircuitBreakerLimits)
+	})
+	if err != nil {
+		return CircuitBreakerLimits{}, nil, err
+	}
+
+	return defaultLimits, peerLimits, nil
+}
@@ -0,0 +1,283 @@
+package htlcswitch
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testBreakerTime   = time.Unix(1600000000, 0)
+	testBreakerExpiry = time.Minute
+	testBreakerPeer   = [33]byte{1}
+	testBreakerPeer2  = [33]byte{2}
+)
+
+// newBreakerPacket returns a forward with the given incoming htlc id and
+// amount.
+func newBreakerPacket(htlcID uint64, amt lnwire.MilliSatoshi) *htlcPacket {
+	return &htlcPacket{
+		incomingChanID: lnwire.NewShortChanIDFromInt(1),
+		incomingHTLCID: htlcID,
+		incomingAmount: amt,
+	}
+}
+
+// newTestCircuitBreaker creates a circuit breaker that is backed by a fresh
+// channel database.
+func newTestCircuitBreaker(t *testing.T, clock clock.Clock) *CircuitBreaker {
+	t.Helper()
+
+	tempDir, err := ioutil.TempDir("", "circuitbreaker")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(tempDir)
+	})
+
+	db, err := channeldb.Open(tempDir)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	breaker, err := NewCircuitBreaker(db, clock, testBreakerExpiry)
+	require.NoError(t, err)
+
+	return breaker
+}
+
+// TestCircuitBreakerPendingLimits asserts that the circuit breaker limits the
+// number and value of the in-flight forwards of a peer, and that releasing a
+// forward frees its slot.
+func TestCircuitBreakerPendingLimits(t *testing.T) {
+	t.Parallel()
+
+	breaker := newTestCircuitBreaker(
+		t, clock.NewTestClock(testBreakerTime),
+	)
+
+	// Without limits, all forwards are admitted.
+	for i := uint64(0); i < 10; i++ {
+		decision := breaker.admit(
+			testBreakerPeer, newBreakerPacket(i, 1000),
+		)
+		require.Equal(t, breakerAdmit, decision)
+	}
+
+	err := breaker.SetDefaultLimits(CircuitBreakerLimits{
+		MaxPendingHtlcs: 11,
+		MaxPendingMsat:  15000,
+	})
+	require.NoError(t, err)
+
+	// A forward that was already admitted is admitted again.
+	pkt := newBreakerPacket(0, 1000)
+	require.Equal(t, breakerAdmit, breaker.admit(testBreakerPeer, pkt))
+
+	// The value limit is hit first.
+	pkt = newBreakerPacket(10, 6000)
+	require.Equal(t, breakerReject, breaker.admit(testBreakerPeer, pkt))
+
+	pkt = newBreakerPacket(10, 5000)
+	require.Equal(t, breakerAdmit, breaker.admit(testBreakerPeer, pkt))
+
+	// Now the htlc limit is hit.
+	pkt = newBreakerPacket(11, 0)
+	require.Equal(t, breakerReject, breaker.admit(testBreakerPeer, pkt))
+
+	// Other peers have their own limits.
+	require.Equal(t, breakerAdmit, breaker.admit(testBreakerPeer2, pkt))
+
+	// Releasing a forward frees its slot.
+	breaker.release(newBreakerPacket(0, 0).inKey())
+	pkt = newBreakerPacket(12, 0)
+	require.Equal(t, breakerAdmit, breaker.admit(testBreakerPeer, pkt))
+
+	status := breaker.Status()[testBreakerPeer]
+	require.Equal(t, &CircuitBreakerPeerStatus{
+		CircuitBreakerCounters: CircuitBreakerCounters{
+			PendingHtlcs:   11,
+			PendingMsat:    14000,
+			ForwardedHtlcs: 12,
+			RejectedHtlcs:  2,
+		},
+		Limits: CircuitBreakerLimits{
+			MaxPendingHtlcs: 11,
+			MaxPendingMsat:  15000,
+		},
+	}, status)
+
+	// Custom limits override the default limits.
+	err = breaker.SetPeerLimits(testBreakerPeer, CircuitBreakerLimits{})
+	require.NoError(t, err)
+	pkt = newBreakerPacket(13, 1000)
+	require.Equal(t, breakerAdmit, breaker.admit(testBreakerPeer, pkt))
+	require.True(t, breaker.Status()[testBreakerPeer].CustomLimits)
+
+	require.NoError(t, breaker.ClearPeerLimits(testBreakerPeer))
+	pkt = newBreakerPacket(14, 1000)
+	require.Equal(t, breakerReject, breaker.admit(testBreakerPeer, pkt))
+	require.False(t, breaker.Status()[testBreakerPeer].CustomLimits)
+}
+
+// TestCircuitBreakerRateLimit asserts that the circuit breaker limits the rate
+// of new forwards of a peer with a token bucket.
+func TestCircuitBreakerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(testBreakerTime)
+	breaker := newTestCircuitBreaker(t, testClock)
+
+	err := breaker.SetDefaultLimits(CircuitBreakerLimits{
+		HtlcRate:  2,
+		HtlcBurst: 3,
+	})
+	require.NoError(t, err)
+
+	// The bucket starts with a full burst.
+	for i := uint64(0); i < 3; i++ {
+		decision := breaker.admit(
+			testBreakerPeer, newBreakerPacket(i, 0),
+		)
+		require.Equal(t, breakerAdmit, decision)
+	}
+
+	pkt := newBreakerPacket(3, 0)
+	require.Equal(t, breakerReject, breaker.admit(testBreakerPeer, pkt))
+
+	// After half a second, one token is added.
+	testClock.SetTime(testBreakerTime.Add(500 * time.Millisecond))
+	require.Equal(t, breakerAdmit, breaker.admit(testBreakerPeer, pkt))
+
+	pkt = newBreakerPacket(4, 0)
+	require.Equal(t, breakerReject, breaker.admit(testBreakerPeer, pkt))
+
+	// The bucket never holds more than the burst.
+	testClock.SetTime(testBreakerTime.Add(time.Hour))
+	for i := uint64(4); i < 7; i++ {
+		decision := breaker.admit(
+			testBreakerPeer, newBreakerPacket(i, 0),
+		)
+		require.Equal(t, breakerAdmit, decision)
+	}
+
+	pkt = newBreakerPacket(7, 0)
+	require.Equal(t, breakerReject, breaker.admit(testBreakerPeer, pkt))
+}
+
+// TestCircuitBreakerQueue asserts that forwards that exceed the limits in
+// queue mode are queued, and that they are admitted in order or expire.
+func TestCircuitBreakerQueue(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(testBreakerTime)
+	breaker := newTestCircuitBreaker(t, testClock)
+
+	err := breaker.SetDefaultLimits(CircuitBreakerLimits{
+		MaxPendingHtlcs: 1,
+		Mode:            CircuitBreakerModeQueue,
+	})
+	require.NoError(t, err)
+	require.False(t, breaker.hasQueued())
+
+	pkt0 := newBreakerPacket(0, 0)
+	require.Equal(t, breakerAdmit, breaker.admit(testBreakerPeer, pkt0))
+
+	pkt1 := newBreakerPacket(1, 0)
+	require.Equal(t, breakerQueue, breaker.admit(testBreakerPeer, pkt1))
+
+	testClock.SetTime(testBreakerTime.Add(time.Second))
+	pkt2 := newBreakerPacket(2, 0)
+	require.Equal(t, breakerQueue, breaker.admit(testBreakerPeer, pkt2))
+	require.True(t, breaker.hasQueued())
+
+	// Nothing can proceed while the first forward is in flight.
+	admitted, expired := breaker.dequeue()
+	require.Empty(t, admitted)
+	require.Empty(t, expired)
+
+	// Drain the signal of setting the limits, so that we can assert that
+	// releasing a forward signals the queue.
+	<-breaker.queueSignal
+
+	breaker.release(pkt0.inKey())
+	select {
+	case <-breaker.queueSignal:
+	default:
+		t.Fatal("queue not signaled")
+	}
+
+	// The queued forwards are admitted in order.
+	admitted, expired = breaker.dequeue()
+	require.Equal(t, []*htlcPacket{pkt1}, admitted)
+	require.Empty(t, expired)
+
+	// Once the queue expiry passed, the remaining forward expires.
+	testClock.SetTime(testBreakerTime.Add(testBreakerExpiry + time.Second))
+	admitted, expired = breaker.dequeue()
+	require.Empty(t, admitted)
+	require.Equal(t, []*htlcPacket{pkt2}, expired)
+	require.False(t, breaker.hasQueued())
+
+	status := breaker.Status()[testBreakerPeer]
+	require.Equal(t, CircuitBreakerCounters{
+		PendingHtlcs:   1,
+		ForwardedHtlcs: 2,
+		RejectedHtlcs:  1,
+	}, status.CircuitBreakerCounters)
+}
+
+// TestCircuitBreakerPersistLimits asserts that the default and per peer limits
+// are restored when the circuit breaker is recreated.
+func TestCircuitBreakerPersistLimits(t *testing.T) {
+	t.Parallel()
+
+	tempDir, err := ioutil.TempDir("", "circuitbreaker")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	db, err := channeldb.Open(tempDir)
+	require.NoError(t, err)
+
+	testClock := clock.NewTestClock(testBreakerTime)
+	breaker, err := NewCircuitBreaker(db, testClock, testBreakerExpiry)
+	require.NoError(t, err)
+
+	defaultLimits := CircuitBreakerLimits{
+		MaxPendingHtlcs: 10,
+		MaxPendingMsat:  100000,
+		HtlcRate:        0.5,
+		HtlcBurst:       5,
+	}
+	peerLimits := CircuitBreakerLimits{
+		MaxPendingHtlcs: 1,
+		Mode:            CircuitBreakerModeQueue,
+	}
+	require.NoError(t, breaker.SetDefaultLimits(defaultLimits))
+	require.NoError(t, breaker.SetPeerLimits(testBreakerPeer, peerLimits))
+	require.NoError(t, breaker.SetPeerLimits(testBreakerPeer2, peerLimits))
+	require.NoError(t, breaker.ClearPeerLimits(testBreakerPeer2))
+
+	// Restart with the same database.
+	require.NoError(t, db.Close())
+	db, err = channeldb.Open(tempDir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	breaker, err = NewCircuitBreaker(db, testClock, testBreakerExpiry)
+	require.NoError(t, err)
+
+	require.Equal(t, defaultLimits, breaker.DefaultLimits())
+	require.Equal(t, map[[33]byte]*CircuitBreakerPeerStatus{
+		testBreakerPeer: {
+			Limits:       peerLimits,
+			CustomLimits: true,
+		},
+	}, breaker.Status())
+}
@@ -84,16 +84,16 @@ func initTestExtracter() {
 // newOnionProcessor creates starts a new htlcswitch.OnionProcessor using a temp
 // db and no garbage collection.
 func newOnionProcessor(t *testing.T) *hop.OnionProcessor {
-	sphinxRouter := sphinx.NewRouter(
+	onionProcessor := hop.NewOnionProcessor(
 		&keychain.PrivKeyECDH{PrivKey: sphinxPrivKey},
 		&bitcoinCfg.SimNetParams, sphinx.NewMemoryReplayLog(),
 	)
 
-	if err := sphinxRouter.Start(); err != nil {
+	if err := onionProcessor.Start(); err != nil {
 		t.Fatalf("unable to start sphinx router: %v", err)
 	}
 
-	return hop.NewOnionProcessor(sphinxRouter)
+	return onionProcessor
 }
 
 // newCircuitMap creates a new htlcswitch.CircuitMap using a temp db and a
@@ -57,6 +57,9 @@ type DecayedLog struct {
 
 	dbFileName string
 
+	// ldbCfg holds the tuning and compaction settings of the ldb.
+	ldbCfg *kvdb.LdbConfig
+
 	db kvdb.Backend
 
 	notifier chainntnfs.ChainNotifier
@@ -67,8 +70,9 @@ type DecayedLog struct {
 
 // NewDecayedLog creates a new DecayedLog, which caches recently seen hash
 // shared secrets. Entries are evicted as their cltv expires using block epochs
-// from the given notifier.
-func NewDecayedLog(dbPath, dbFileName string, // no bolt in js - boltCfg *kvdb.BoltConfig,
+// from the given notifier. The ldb is opened with the given settings, or the
+// default ones if ldbCfg is nil.
+func NewDecayedLog(dbPath, dbFileName string, ldbCfg *kvdb.LdbConfig,
 	notifier chainntnfs.ChainNotifier) *DecayedLog {
 
 	/* cfg := &kvdb.BoltBackendConfig{
@@ -85,10 +89,15 @@ func NewDecayedLog(dbPath, dbFileName string, // no bolt in js - boltCfg *kvdb.B
 		dbPath = defaultDbDirectory
 	}
 
+	if ldbCfg == nil {
+		ldbCfg = kvdb.DefaultLdbConfig()
+	}
+
 	return &DecayedLog{
 		// cfg:      cfg,
 		dbPath:     dbPath,
 		dbFileName: dbFileName,
+		ldbCfg:     ldbCfg,
 		notifier:   notifier,
 		quit:       make(chan struct{}),
 	}
@@ -104,7 +113,11 @@ func (d *DecayedLog) Start() error {
 
 	// Open the ldb for use.
 	var err error
-	d.db, err = kvdb.GetLdbBackend(d.dbPath, d.dbFileName)
+	d.db, err = kvdb.GetLdbBackend(&kvdb.LdbBackendConfig{
+		DBPath:     d.dbPath,
+		DBFileName: d.dbFileName,
+		LdbConfig:  *d.ldbCfg,
+	})
 	if err != nil {
 		return fmt.Errorf("could not open ldb: %v", err)
 	}
@@ -45,11 +45,11 @@ func startup(dbPath, dbFileName string, notifier bool) (sphinx.ReplayLog,
 
 		// Initialize the DecayedLog object
 		log = NewDecayedLog(
-			dbPath, dbFileName, &kvdb.BoltConfig{}, chainNotifier,
+			dbPath, dbFileName, kvdb.DefaultLdbConfig(), chainNotifier,
 		)
 	} else {
 		// Initialize the DecayedLog object
-		log = NewDecayedLog(dbPath, dbFileName, &kvdb.BoltConfig{}, nil)
+		log = NewDecayedLog(dbPath, dbFileName, kvdb.DefaultLdbConfig(), nil)
 	}
 
 	// Open the channeldb (start the garbage collector)
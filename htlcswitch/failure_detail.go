@@ -54,6 +54,10 @@ const (
 	// OutgoingFailureForwardsDisabled is returned when the switch is
 	// configured to disallow forwards.
 	OutgoingFailureForwardsDisabled
+
+	// OutgoingFailureCircuitBreaker is returned when a forward exceeds the
+	// circuit breaker limits of its incoming peer.
+	OutgoingFailureCircuitBreaker
 )
 
 // FailureString returns the string representation of a failure detail.
@@ -91,6 +95,9 @@ func (fd OutgoingFailure) FailureString() string {
 	case OutgoingFailureForwardsDisabled:
 		return "node configured to disallow forwards"
 
+	case OutgoingFailureCircuitBreaker:
+		return "incoming peer exceeds circuit breaker limits"
+
 	default:
 		return "unknown failure detail"
 	}
@@ -2,6 +2,7 @@ package hop
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"io"
 
@@ -25,6 +26,10 @@ const (
 
 	// EncrypterTypeMock is used to identify a mock obfuscator instance.
 	EncrypterTypeMock = 2
+
+	// EncrypterTypeIntroduction is used to identify the error encrypter of
+	// the introduction node of a blinded route.
+	EncrypterTypeIntroduction = 3
 )
 
 // ErrorEncrypterExtracter defines a function signature that extracts an
@@ -203,3 +208,106 @@ func (s *SphinxErrorEncrypter) Reextract(
 // A compile time check to ensure SphinxErrorEncrypter implements the
 // ErrorEncrypter interface.
 var _ ErrorEncrypter = (*SphinxErrorEncrypter)(nil)
+
+// IntroductionErrorEncrypter is the error encrypter used by the introduction
+// node of a blinded route. Any failure that occurs at the introduction node,
+// or that is sent back to it from within the blinded route, is replaced by an
+// invalid blinding failure, so that the sender isn't able to learn anything
+// about the blinded route.
+type IntroductionErrorEncrypter struct {
+	*SphinxErrorEncrypter
+
+	// OnionSHA256 is the hash of the onion packet of the incoming HTLC.
+	OnionSHA256 [sha256.Size]byte
+}
+
+// NewIntroductionErrorEncrypter initializes a blank introduction error
+// encrypter, that should be used to deserialize an encoded
+// IntroductionErrorEncrypter.
+func NewIntroductionErrorEncrypter() *IntroductionErrorEncrypter {
+	return &IntroductionErrorEncrypter{
+		SphinxErrorEncrypter: NewSphinxErrorEncrypter(),
+	}
+}
+
+// EncryptFirstHop replaces the failure message with an invalid blinding
+// failure and encrypts it.
+//
+// NOTE: Part of the ErrorEncrypter interface.
+func (i *IntroductionErrorEncrypter) EncryptFirstHop(
+	_ lnwire.FailureMessage) (lnwire.OpaqueReason, error) {
+
+	return i.SphinxErrorEncrypter.EncryptFirstHop(
+		&lnwire.FailInvalidBlinding{OnionSHA256: i.OnionSHA256},
+	)
+}
+
+// EncryptMalformedError replaces the malformed failure reported from within
+// the blinded route with an invalid blinding failure and encrypts it.
+//
+// NOTE: Part of the ErrorEncrypter interface.
+func (i *IntroductionErrorEncrypter) EncryptMalformedError(
+	_ lnwire.OpaqueReason) lnwire.OpaqueReason {
+
+	return i.invalidBlinding()
+}
+
+// IntermediateEncrypt replaces the failure sent back from within the blinded
+// route with an invalid blinding failure. The downstream failure is dropped
+// rather than wrapped, since it would otherwise reveal the node that failed
+// the HTLC to the sender.
+//
+// NOTE: Part of the ErrorEncrypter interface.
+func (i *IntroductionErrorEncrypter) IntermediateEncrypt(
+	_ lnwire.OpaqueReason) lnwire.OpaqueReason {
+
+	return i.invalidBlinding()
+}
+
+// invalidBlinding returns an invalid blinding failure encrypted with the key
+// shared with the sender, as if it originated at this node.
+func (i *IntroductionErrorEncrypter) invalidBlinding() lnwire.OpaqueReason {
+	reason, err := i.EncryptFirstHop(nil)
+	if err != nil {
+		// Encoding a fixed size failure should never fail, but if it
+		// does we still mustn't forward the downstream failure, so
+		// an empty failure is returned instead.
+		log.Errorf("unable to encode invalid blinding failure: %v",
+			err)
+
+		return i.EncryptError(true, nil)
+	}
+
+	return reason
+}
+
+// Type returns the identifier for an introduction error encrypter.
+func (i *IntroductionErrorEncrypter) Type() EncrypterType {
+	return EncrypterTypeIntroduction
+}
+
+// Encode serializes the error encrypter's ephemeral public key and the onion
+// hash to the provided io.Writer.
+func (i *IntroductionErrorEncrypter) Encode(w io.Writer) error {
+	if err := i.SphinxErrorEncrypter.Encode(w); err != nil {
+		return err
+	}
+
+	_, err := w.Write(i.OnionSHA256[:])
+	return err
+}
+
+// Decode reconstructs the error encrypter's ephemeral public key and the
+// onion hash from the provided io.Reader.
+func (i *IntroductionErrorEncrypter) Decode(r io.Reader) error {
+	if err := i.SphinxErrorEncrypter.Decode(r); err != nil {
+		return err
+	}
+
+	_, err := io.ReadFull(r, i.OnionSHA256[:])
+	return err
+}
+
+// A compile time check to ensure IntroductionErrorEncrypter implements the
+// ErrorEncrypter interface.
+var _ ErrorEncrypter = (*IntroductionErrorEncrypter)(nil)
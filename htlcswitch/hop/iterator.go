@@ -2,14 +2,51 @@ package hop
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
 
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 )
 
+// ErrInvalidBlinding is an error returned when the payload of a hop inside of
+// a blinded route couldn't be processed. Any failure of such a hop is reported
+// to the sender as an invalid blinding error, so that the sender can't probe
+// the blinded route.
+type ErrInvalidBlinding struct {
+	// Reason is the reason processing the blinded payload failed.
+	Reason error
+}
+
+// Error returns a human-readable description of the invalid blinding error.
+func (e ErrInvalidBlinding) Error() string {
+	return fmt.Sprintf("invalid blinded route payload: %v", e.Reason)
+}
+
+// blindingInfo contains the information required to process the payload of a
+// hop inside of a blinded route.
+type blindingInfo struct {
+	// nodeKey is our node's onion key, which is used to decrypt the
+	// encrypted data.
+	nodeKey sphinx.SingleKeyECDH
+
+	// blindingPoint is the blinding point that was sent along with the
+	// HTLC. It's nil if we're not inside of a blinded route, or if we're
+	// its introduction node.
+	blindingPoint *btcec.PublicKey
+
+	// incomingAmt is the amount of the incoming HTLC.
+	incomingAmt lnwire.MilliSatoshi
+
+	// incomingCltv is the expiry of the incoming HTLC.
+	incomingCltv uint32
+}
+
 // Iterator is an interface that abstracts away the routing information
 // included in HTLC's which includes the entirety of the payment path of an
 // HTLC. This interface provides two basic method which carry out: how to
@@ -46,16 +83,21 @@ type sphinxHopIterator struct {
 	// includes the information required to properly forward the packet to
 	// the next hop.
 	processedPacket *sphinx.ProcessedPacket
+
+	// blinding contains the information required to process the payload
+	// if we're a hop inside of a blinded route.
+	blinding blindingInfo
 }
 
 // makeSphinxHopIterator converts a processed packet returned from a sphinx
 // router and converts it into an hop iterator for usage in the link.
 func makeSphinxHopIterator(ogPacket *sphinx.OnionPacket,
-	packet *sphinx.ProcessedPacket) *sphinxHopIterator {
+	packet *sphinx.ProcessedPacket, blinding blindingInfo) *sphinxHopIterator {
 
 	return &sphinxHopIterator{
 		ogPacket:        ogPacket,
 		processedPacket: packet,
+		blinding:        blinding,
 	}
 }
 
@@ -89,9 +131,14 @@ func (r *sphinxHopIterator) HopPayload() (*Payload, error) {
 	// Otherwise, if this is the TLV payload, then we'll make a new stream
 	// to decode only what we need to make routing decisions.
 	case sphinx.PayloadTLV:
-		return NewPayloadFromReader(bytes.NewReader(
+		payload, err := NewPayloadFromReader(bytes.NewReader(
 			r.processedPacket.Payload.Payload,
 		))
+		if err != nil {
+			return nil, err
+		}
+
+		return r.unblindPayload(payload)
 
 	default:
 		return nil, fmt.Errorf("unknown sphinx payload type: %v",
@@ -99,6 +146,218 @@ func (r *sphinxHopIterator) HopPayload() (*Payload, error) {
 	}
 }
 
+// unblindPayload decrypts the encrypted data of a hop inside of a blinded
+// route and populates the forwarding info of the payload from it. Payloads of
+// hops outside of a blinded route are returned unchanged.
+func (r *sphinxHopIterator) unblindPayload(payload *Payload) (*Payload,
+	error) {
+
+	// The next hop of a blinded route is part of the encrypted data, so we
+	// rely on the onion packet to tell whether we're the final hop.
+	isFinalHop := r.processedPacket.Action == sphinx.ExitNode
+
+	switch {
+	// We're not inside of a blinded route.
+	case payload.EncryptedData == nil && r.blinding.blindingPoint == nil:
+		return payload, nil
+
+	// The final hop of a blinded route must receive the total amount of
+	// the payment, intermediate hops must not.
+	case isFinalHop && payload.TotalAmtMsat == 0:
+		return nil, ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: OmittedViolation,
+			FinalHop:  true,
+		}
+
+	case !isFinalHop && payload.TotalAmtMsat != 0:
+		return nil, ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: IncludedViolation,
+			FinalHop:  false,
+		}
+
+	// We received a blinding point along with the HTLC, so the payload
+	// must contain encrypted data for us.
+	case payload.EncryptedData == nil:
+		return nil, ErrInvalidPayload{
+			Type:      record.EncryptedDataOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// Only the introduction node receives the blinding point in the
+	// payload, all other hops receive it along with the HTLC.
+	case payload.BlindingPoint != nil && r.blinding.blindingPoint != nil:
+		return nil, ErrInvalidPayload{
+			Type:      record.BlindingPointOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	case payload.BlindingPoint == nil && r.blinding.blindingPoint == nil:
+		return nil, ErrInvalidPayload{
+			Type:      record.BlindingPointOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	blindingPoint := r.blinding.blindingPoint
+	if blindingPoint == nil {
+		blindingPoint = payload.BlindingPoint
+	}
+
+	if r.blinding.nodeKey == nil {
+		return nil, ErrInvalidBlinding{
+			Reason: fmt.Errorf("route blinding not supported"),
+		}
+	}
+
+	plainText, err := blindedpath.DecryptBlindedHopData(
+		r.blinding.nodeKey, blindingPoint, payload.EncryptedData,
+	)
+	if err != nil {
+		return nil, ErrInvalidBlinding{Reason: err}
+	}
+
+	data, err := record.DecodeBlindedRouteData(bytes.NewReader(plainText))
+	if err != nil {
+		return nil, ErrInvalidBlinding{Reason: err}
+	}
+
+	// Make sure the HTLC satisfies the constraints the creator of the
+	// blinded route set for us.
+	if c := data.Constraints; c != nil {
+		if r.blinding.incomingCltv > c.MaxCltvExpiry {
+			return nil, ErrInvalidBlinding{
+				Reason: fmt.Errorf("htlc expiry %v exceeds "+
+					"max expiry %v", r.blinding.incomingCltv,
+					c.MaxCltvExpiry),
+			}
+		}
+
+		if r.blinding.incomingAmt < c.HtlcMinimumMsat {
+			return nil, ErrInvalidBlinding{
+				Reason: fmt.Errorf("htlc amount %v below "+
+					"minimum %v", r.blinding.incomingAmt,
+					c.HtlcMinimumMsat),
+			}
+		}
+	}
+
+	// The final hop of the blinded route only uses the encrypted data to
+	// authenticate the payment, so the remaining forwarding info is taken
+	// from the payload.
+	if isFinalHop {
+		return unblindFinalPayload(payload, data)
+	}
+
+	return r.unblindForwardingPayload(payload, data, blindingPoint)
+}
+
+// unblindFinalPayload processes the payload of the final hop of a blinded
+// route. The path ID we set when creating the blinded route is the payment
+// address of the invoice, so we use it to build the MPP record the invoice
+// registry uses to look up and authenticate the payment.
+func unblindFinalPayload(payload *Payload,
+	data *record.BlindedRouteData) (*Payload, error) {
+
+	if data.ShortChannelID != nil {
+		return nil, ErrInvalidBlinding{
+			Reason: fmt.Errorf("final hop data contains next hop"),
+		}
+	}
+
+	if len(data.PathID) != 32 {
+		return nil, ErrInvalidBlinding{
+			Reason: fmt.Errorf("invalid path id length %d",
+				len(data.PathID)),
+		}
+	}
+
+	var payAddr [32]byte
+	copy(payAddr[:], data.PathID)
+
+	if payload.MPP != nil && payload.MPP.PaymentAddr() != payAddr {
+		return nil, ErrInvalidBlinding{
+			Reason: fmt.Errorf("payment addr doesn't match path id"),
+		}
+	}
+	payload.MPP = record.NewMPP(payload.TotalAmtMsat, payAddr)
+
+	return payload, nil
+}
+
+// unblindForwardingPayload derives the forwarding info of an intermediate hop
+// inside of a blinded route from the encrypted data.
+func (r *sphinxHopIterator) unblindForwardingPayload(payload *Payload,
+	data *record.BlindedRouteData,
+	blindingPoint *btcec.PublicKey) (*Payload, error) {
+
+	if data.ShortChannelID == nil || data.RelayInfo == nil {
+		return nil, ErrInvalidBlinding{
+			Reason: fmt.Errorf("forwarding data incomplete"),
+		}
+	}
+
+	relay := data.RelayInfo
+	amt, err := blindedAmtToForward(r.blinding.incomingAmt, relay)
+	if err != nil {
+		return nil, ErrInvalidBlinding{Reason: err}
+	}
+
+	if r.blinding.incomingCltv < uint32(relay.CltvExpiryDelta) {
+		return nil, ErrInvalidBlinding{
+			Reason: fmt.Errorf("htlc expiry %v below cltv delta %v",
+				r.blinding.incomingCltv, relay.CltvExpiryDelta),
+		}
+	}
+
+	nextBlinding := data.NextBlindingOverride
+	if nextBlinding == nil {
+		nextBlinding, err = blindedpath.NextBlindingPoint(
+			r.blinding.nodeKey, blindingPoint,
+		)
+		if err != nil {
+			return nil, ErrInvalidBlinding{Reason: err}
+		}
+	}
+
+	payload.FwdInfo = ForwardingInfo{
+		Network:         BitcoinNetwork,
+		NextHop:         *data.ShortChannelID,
+		AmountToForward: amt,
+		OutgoingCTLV: r.blinding.incomingCltv -
+			uint32(relay.CltvExpiryDelta),
+		NextBlinding: nextBlinding,
+	}
+
+	return payload, nil
+}
+
+// blindedAmtToForward computes the amount to forward from the incoming amount
+// and the fees of the hop, which is the inverse of adding the fees to the
+// outgoing amount, rounded up:
+//
+//  amt = ceil((incomingAmt - baseFee) * 1e6 / (1e6 + feeRate))
+func blindedAmtToForward(incomingAmt lnwire.MilliSatoshi,
+	relay *record.PaymentRelayInfo) (lnwire.MilliSatoshi, error) {
+
+	baseFee := lnwire.MilliSatoshi(relay.BaseFee)
+	if incomingAmt < baseFee {
+		return 0, fmt.Errorf("htlc amount %v below base fee %v",
+			incomingAmt, baseFee)
+	}
+
+	numerator := uint64(incomingAmt-baseFee) * 1_000_000
+	denominator := 1_000_000 + uint64(relay.FeeRate)
+
+	return lnwire.MilliSatoshi(
+		(numerator + denominator - 1) / denominator,
+	), nil
+}
+
 // ExtractErrorEncrypter decodes and returns the ErrorEncrypter for this hop,
 // along with a failure code to signal if the decoding was successful. The
 // ErrorEncrypter is used to encrypt errors back to the sender in the event that
@@ -122,11 +381,30 @@ func (r *sphinxHopIterator) ExtractErrorEncrypter(
 // tests dependent from the sphinx internal parts.
 type OnionProcessor struct {
 	router *sphinx.Router
+
+	// nodeKey is our node's onion key. It is used to decrypt the payloads
+	// of hops inside of a blinded route, and to derive the blinded key
+	// the onion packets of such hops are encrypted to.
+	nodeKey sphinx.SingleKeyECDH
+
+	// netParams are the parameters of the network the router operates on.
+	netParams *chaincfg.Params
+
+	// replayLog is the replay log shared between the router and the
+	// routers created to process onion packets inside of blinded routes.
+	replayLog sphinx.ReplayLog
 }
 
 // NewOnionProcessor creates new instance of decoder.
-func NewOnionProcessor(router *sphinx.Router) *OnionProcessor {
-	return &OnionProcessor{router}
+func NewOnionProcessor(nodeKey sphinx.SingleKeyECDH,
+	netParams *chaincfg.Params, replayLog sphinx.ReplayLog) *OnionProcessor {
+
+	return &OnionProcessor{
+		router:    sphinx.NewRouter(nodeKey, netParams, replayLog),
+		nodeKey:   nodeKey,
+		netParams: netParams,
+		replayLog: replayLog,
+	}
 }
 
 // Start spins up the onion processor's sphinx router.
@@ -181,7 +459,13 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte,
 		}
 	}
 
-	return makeSphinxHopIterator(onionPkt, sphinxPacket), lnwire.CodeNone
+	blinding := blindingInfo{
+		nodeKey:      p.nodeKey,
+		incomingCltv: incomingCltv,
+	}
+
+	return makeSphinxHopIterator(onionPkt, sphinxPacket, blinding),
+		lnwire.CodeNone
 }
 
 // ReconstructHopIterator attempts to decode a valid sphinx packet from the passed io.Reader
@@ -205,7 +489,14 @@ func (p *OnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte) (
 		return nil, err
 	}
 
-	return makeSphinxHopIterator(onionPkt, sphinxPacket), nil
+	// NOTE: The blinding point of HTLCs received inside of a blinded route
+	// isn't persisted, so only payloads outside of a blinded route or of
+	// its introduction node can be reconstructed.
+	blinding := blindingInfo{
+		nodeKey: p.nodeKey,
+	}
+
+	return makeSphinxHopIterator(onionPkt, sphinxPacket, blinding), nil
 }
 
 // DecodeHopIteratorRequest encapsulates all date necessary to process an onion
@@ -215,6 +506,15 @@ type DecodeHopIteratorRequest struct {
 	OnionReader  io.Reader
 	RHash        []byte
 	IncomingCltv uint32
+
+	// IncomingAmount is the amount of the incoming HTLC. It's required to
+	// compute the amount to forward inside of a blinded route.
+	IncomingAmount lnwire.MilliSatoshi
+
+	// BlindingPoint is the blinding point sent along with the HTLC if it
+	// was forwarded to us inside of a blinded route. In that case the
+	// onion packet is encrypted to our blinded node ID.
+	BlindingPoint *btcec.PublicKey
 }
 
 // DecodeHopIteratorResponse encapsulates the outcome of a batched sphinx onion
@@ -251,6 +551,11 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 
 	tx := p.router.BeginTxn(id, batchSize)
 
+	// Onion packets of HTLCs forwarded inside of a blinded route are
+	// encrypted to our blinded node ID, so they can't be part of the
+	// regular batch and are processed individually instead.
+	blindedPkts := make(map[int]*sphinx.ProcessedPacket)
+
 	for i, req := range reqs {
 		onionPkt := &onionPkts[i]
 		resp := &resps[i]
@@ -274,6 +579,21 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 			continue
 		}
 
+		if req.BlindingPoint != nil {
+			blindedPkt, err := p.processBlindedPacket(
+				id, uint16(i), onionPkt, req,
+			)
+			if err != nil {
+				log.Errorf("unable to process blinded onion "+
+					"packet: %v", err)
+				resp.FailCode = lnwire.CodeInvalidBlinding
+				continue
+			}
+
+			blindedPkts[i] = blindedPkt
+			continue
+		}
+
 		err = tx.ProcessOnionPacket(
 			uint16(i), onionPkt, req.RHash, req.IncomingCltv,
 		)
@@ -342,6 +662,22 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 			continue
 		}
 
+		blinding := blindingInfo{
+			nodeKey:       p.nodeKey,
+			blindingPoint: reqs[i].BlindingPoint,
+			incomingAmt:   reqs[i].IncomingAmount,
+			incomingCltv:  reqs[i].IncomingCltv,
+		}
+
+		// Blinded packets were already checked for replays when they
+		// were processed.
+		if blindedPkt, ok := blindedPkts[i]; ok {
+			resp.HopIterator = makeSphinxHopIterator(
+				&onionPkts[i], blindedPkt, blinding,
+			)
+			continue
+		}
+
 		// If this index is contained in the replay set, mark it with a
 		// temporary channel failure error code. We infer that the
 		// offending error was due to a replayed packet because this
@@ -355,12 +691,56 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 
 		// Finally, construct a hop iterator from our processed sphinx
 		// packet, simultaneously caching the original onion packet.
-		resp.HopIterator = makeSphinxHopIterator(&onionPkts[i], &packets[i])
+		resp.HopIterator = makeSphinxHopIterator(
+			&onionPkts[i], &packets[i], blinding,
+		)
 	}
 
 	return resps, nil
 }
 
+// processBlindedPacket processes the onion packet of an HTLC forwarded to us
+// inside of a blinded route. The packet is encrypted to our blinded node ID,
+// so we use a router with the blinded key that shares our replay log. Each
+// packet is committed in its own batch, identified by the batch ID and its
+// index, so that processing the same forwarding package again yields the same
+// result.
+func (p *OnionProcessor) processBlindedPacket(id []byte, seqNum uint16,
+	onionPkt *sphinx.OnionPacket,
+	req DecodeHopIteratorRequest) (*sphinx.ProcessedPacket, error) {
+
+	blindedKey, err := blindedpath.NewBlindedECDH(
+		p.nodeKey, req.BlindingPoint,
+	)
+	if err != nil {
+		return nil, err
+	}
+	router := sphinx.NewRouter(blindedKey, p.netParams, p.replayLog)
+
+	var blindedID [2]byte
+	binary.BigEndian.PutUint16(blindedID[:], seqNum)
+	batchID := append(append([]byte{}, id...), blindedID[:]...)
+
+	tx := router.BeginTxn(batchID, 1)
+	err = tx.ProcessOnionPacket(
+		0, onionPkt, req.RHash, req.IncomingCltv,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	packets, replays, err := tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	if replays.Contains(0) {
+		return nil, sphinx.ErrReplayedPacket
+	}
+
+	return &packets[0], nil
+}
+
 // ExtractErrorEncrypter takes an io.Reader which should contain the onion
 // packet as original received by a forwarding node and creates an
 // ErrorEncrypter instance using the derived shared secret. In the case that en
@@ -0,0 +1,447 @@
+package hop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// onionMessageReplyPathType is the type of the onion message payload
+	// record that carries a blinded path the recipient can reply to.
+	onionMessageReplyPathType tlv.Type = 2
+
+	// onionMessageEncryptedDataType is the type of the onion message
+	// payload record that carries the encrypted recipient data of a hop.
+	onionMessageEncryptedDataType tlv.Type = 4
+
+	// MinOnionMessageCustomType is the smallest type that can be used for
+	// application data in the payload of the final hop of an onion
+	// message.
+	MinOnionMessageCustomType = 64
+)
+
+var (
+	// ErrOnionMessageNoNextNode is returned when an onion message needs to
+	// be forwarded, but the recipient data doesn't specify the next node.
+	ErrOnionMessageNoNextNode = errors.New("onion message has no next node")
+
+	// ErrOnionMessageNoPath is returned when an onion message is created
+	// without a path or blinded destination.
+	ErrOnionMessageNoPath = errors.New("onion message has no path")
+)
+
+// OnionMessagePayload is the content of an onion message that is delivered to
+// its final recipient.
+type OnionMessagePayload struct {
+	// ReplyPath is an optional blinded path the recipient can use to reply
+	// to the message.
+	ReplyPath *blindedpath.BlindedPath
+
+	// PathID is the path ID of the recipient data of the final hop. It is
+	// only set for received messages, and allows the recipient to verify
+	// that a message was sent along a path it created itself.
+	PathID []byte
+
+	// CustomRecords are the application defined records of the message.
+	// All types must be at least MinOnionMessageCustomType.
+	CustomRecords record.CustomSet
+}
+
+// ProcessedOnionMessage is the result of processing an incoming onion message.
+// Either the message needs to be forwarded to the next node, or we're the
+// recipient and Payload is set.
+type ProcessedOnionMessage struct {
+	// NextNode is the node the message needs to be forwarded to.
+	NextNode *btcec.PublicKey
+
+	// NextMessage is the message to forward to the next node.
+	NextMessage *lnwire.OnionMessage
+
+	// Payload is the content of the message if we're its recipient.
+	Payload *OnionMessagePayload
+}
+
+// OnionMessageProcessor peels the onion of incoming onion messages and creates
+// new onion messages. Unlike the OnionProcessor, it doesn't use a replay log
+// as onion messages don't carry any value.
+type OnionMessageProcessor struct {
+	nodeKey   sphinx.SingleKeyECDH
+	netParams *chaincfg.Params
+}
+
+// NewOnionMessageProcessor creates a new onion message processor backed by
+// our node key.
+func NewOnionMessageProcessor(nodeKey sphinx.SingleKeyECDH,
+	netParams *chaincfg.Params) *OnionMessageProcessor {
+
+	return &OnionMessageProcessor{
+		nodeKey:   nodeKey,
+		netParams: netParams,
+	}
+}
+
+// ProcessOnionMessage peels one layer of the passed onion message.
+func (p *OnionMessageProcessor) ProcessOnionMessage(
+	msg *lnwire.OnionMessage) (*ProcessedOnionMessage, error) {
+
+	onionPkt := &sphinx.OnionPacket{}
+	if err := onionPkt.Decode(bytes.NewReader(msg.OnionBlob)); err != nil {
+		return nil, err
+	}
+
+	// The onion is encrypted to our blinded node ID, which we derive from
+	// the blinding point of the message.
+	blindedKey, err := blindedpath.NewBlindedECDH(
+		p.nodeKey, msg.BlindingPoint,
+	)
+	if err != nil {
+		return nil, err
+	}
+	router := sphinx.NewRouter(
+		blindedKey, p.netParams, &noopReplayLog{},
+	)
+
+	processed, err := router.ProcessOnionPacket(onionPkt, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if processed.Payload.Type != sphinx.PayloadTLV {
+		return nil, fmt.Errorf("onion message with legacy payload")
+	}
+
+	var (
+		replyPath     []byte
+		encryptedData []byte
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(onionMessageReplyPathType, &replyPath),
+		tlv.MakePrimitiveRecord(
+			onionMessageEncryptedDataType, &encryptedData,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(
+		bytes.NewReader(processed.Payload.Payload),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	plainText, err := blindedpath.DecryptBlindedHopData(
+		p.nodeKey, msg.BlindingPoint, encryptedData,
+	)
+	if err != nil {
+		return nil, err
+	}
+	data, err := record.DecodeBlindedRouteData(bytes.NewReader(plainText))
+	if err != nil {
+		return nil, err
+	}
+
+	if processed.Action == sphinx.ExitNode {
+		return processFinalOnionMessage(parsedTypes, replyPath, data)
+	}
+
+	// Onion messages are forwarded to the next node directly, we don't
+	// support resolving the next node from a short channel ID.
+	if data.NextNodeID == nil {
+		return nil, ErrOnionMessageNoNextNode
+	}
+
+	nextBlinding := data.NextBlindingOverride
+	if nextBlinding == nil {
+		nextBlinding, err = blindedpath.NextBlindingPoint(
+			p.nodeKey, msg.BlindingPoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var b bytes.Buffer
+	if err := processed.NextPacket.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return &ProcessedOnionMessage{
+		NextNode:    data.NextNodeID,
+		NextMessage: lnwire.NewOnionMessage(nextBlinding, b.Bytes()),
+	}, nil
+}
+
+// processFinalOnionMessage extracts the payload of an onion message we're the
+// recipient of.
+func processFinalOnionMessage(parsedTypes tlv.TypeMap, replyPath []byte,
+	data *record.BlindedRouteData) (*ProcessedOnionMessage, error) {
+
+	if data.NextNodeID != nil || data.ShortChannelID != nil {
+		return nil, fmt.Errorf("final onion message hop has next hop")
+	}
+
+	payload := &OnionMessagePayload{
+		PathID:        data.PathID,
+		CustomRecords: make(record.CustomSet),
+	}
+
+	if _, ok := parsedTypes[onionMessageReplyPathType]; ok {
+		path, err := blindedpath.DecodeBlindedPath(
+			bytes.NewReader(replyPath),
+		)
+		if err != nil {
+			return nil, err
+		}
+		payload.ReplyPath = path
+	}
+
+	for t, value := range parsedTypes {
+		// Known records are returned with a nil value.
+		if value == nil {
+			continue
+		}
+
+		switch {
+		case t >= MinOnionMessageCustomType:
+			payload.CustomRecords[uint64(t)] = value
+
+		case t%2 == 0:
+			return nil, fmt.Errorf("unknown required type %d in "+
+				"onion message", t)
+		}
+	}
+
+	return &ProcessedOnionMessage{
+		Payload: payload,
+	}, nil
+}
+
+// CreateOnionMessage creates an onion message that is sent along the passed
+// path of nodes. If a blinded destination is given, the message is sent to it
+// after the path, which then only needs to lead to its introduction node.
+// Otherwise the last node of the path is the recipient. The first node the
+// message needs to be sent to is returned along with the message.
+func CreateOnionMessage(path []*btcec.PublicKey,
+	destination *blindedpath.BlindedPath,
+	payload *OnionMessagePayload) (*btcec.PublicKey, *lnwire.OnionMessage,
+	error) {
+
+	if len(path) == 0 && destination == nil {
+		return nil, nil, ErrOnionMessageNoPath
+	}
+
+	// The hops of the path are blinded by us. If we're sending to a
+	// blinded destination, the last hop of the path hands the message over
+	// to its introduction node.
+	var (
+		firstNode     *btcec.PublicKey
+		blindingPoint *btcec.PublicKey
+		blindedHops   []*blindedpath.BlindedHop
+	)
+	if len(path) > 0 {
+		var lastHop *record.BlindedRouteData
+		if destination != nil {
+			lastHop = &record.BlindedRouteData{
+				NextNodeID:           destination.IntroductionPoint,
+				NextBlindingOverride: destination.BlindingPoint,
+			}
+		}
+
+		blindedPath, err := BuildOnionMessagePath(path, lastHop)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		firstNode = path[0]
+		blindingPoint = blindedPath.BlindingPoint
+		blindedHops = blindedPath.BlindedHops
+	} else {
+		firstNode = destination.IntroductionPoint
+		blindingPoint = destination.BlindingPoint
+	}
+
+	if destination != nil {
+		blindedHops = append(blindedHops, destination.BlindedHops...)
+	}
+
+	if len(blindedHops) > sphinx.NumMaxHops {
+		return nil, nil, fmt.Errorf("onion message path too long: %d",
+			len(blindedHops))
+	}
+
+	// Each hop receives its encrypted recipient data, the final hop
+	// additionally receives the actual message.
+	var sphinxPath sphinx.PaymentPath
+	for i, hop := range blindedHops {
+		records := []tlv.Record{
+			tlv.MakePrimitiveRecord(
+				onionMessageEncryptedDataType, &hop.CipherText,
+			),
+		}
+
+		if i == len(blindedHops)-1 {
+			finalRecords, err := finalOnionMessageRecords(payload)
+			if err != nil {
+				return nil, nil, err
+			}
+			records = append(records, finalRecords...)
+		}
+
+		tlv.SortRecords(records)
+		stream, err := tlv.NewStream(records...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var b bytes.Buffer
+		if err := stream.Encode(&b); err != nil {
+			return nil, nil, err
+		}
+
+		hopPayload, err := sphinx.NewHopPayload(nil, b.Bytes())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sphinxPath[i] = sphinx.OnionHop{
+			NodePub:    *hop.BlindedNodePub,
+			HopPayload: hopPayload,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	onionPkt, err := sphinx.NewOnionPacket(
+		&sphinxPath, sessionKey, nil, sphinx.DeterministicPacketFiller,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b bytes.Buffer
+	if err := onionPkt.Encode(&b); err != nil {
+		return nil, nil, err
+	}
+
+	return firstNode, lnwire.NewOnionMessage(blindingPoint, b.Bytes()), nil
+}
+
+// finalOnionMessageRecords returns the records of the payload for the final
+// hop of an onion message.
+func finalOnionMessageRecords(payload *OnionMessagePayload) ([]tlv.Record,
+	error) {
+
+	if payload == nil {
+		return nil, nil
+	}
+
+	var records []tlv.Record
+	if payload.ReplyPath != nil {
+		var b bytes.Buffer
+		if err := payload.ReplyPath.Encode(&b); err != nil {
+			return nil, err
+		}
+
+		replyPath := b.Bytes()
+		records = append(records, tlv.MakePrimitiveRecord(
+			onionMessageReplyPathType, &replyPath,
+		))
+	}
+
+	for t := range payload.CustomRecords {
+		if t < MinOnionMessageCustomType {
+			return nil, fmt.Errorf("custom record type %d below "+
+				"minimum of %d", t, MinOnionMessageCustomType)
+		}
+	}
+
+	return append(records, tlv.MapToRecords(payload.CustomRecords)...), nil
+}
+
+// BuildOnionMessagePath creates a blinded path for onion messages along the
+// passed nodes, which can be used as a reply path. Each hop learns the node ID
+// of the next hop. The recipient data of the last hop can optionally be set.
+func BuildOnionMessagePath(nodes []*btcec.PublicKey,
+	lastHop *record.BlindedRouteData) (*blindedpath.BlindedPath, error) {
+
+	hops := make([]*blindedpath.HopInfo, len(nodes))
+	for i, node := range nodes {
+		data := &record.BlindedRouteData{}
+		switch {
+		case i < len(nodes)-1:
+			data.NextNodeID = nodes[i+1]
+
+		case lastHop != nil:
+			data = lastHop
+		}
+
+		plainText, err := record.EncodeBlindedRouteData(data)
+		if err != nil {
+			return nil, err
+		}
+
+		hops[i] = &blindedpath.HopInfo{
+			NodePub:   node,
+			PlainText: plainText,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	return blindedpath.BuildBlindedPath(sessionKey, hops)
+}
+
+// noopReplayLog is a sphinx.ReplayLog that doesn't detect any replays. Onion
+// messages don't carry any value, so there is nothing to gain by replaying
+// them and they are rate limited per peer instead.
+type noopReplayLog struct{}
+
+// A compile time check to ensure noopReplayLog implements the
+// sphinx.ReplayLog interface.
+var _ sphinx.ReplayLog = (*noopReplayLog)(nil)
+
+// Start is a no-op.
+func (n *noopReplayLog) Start() error {
+	return nil
+}
+
+// Stop is a no-op.
+func (n *noopReplayLog) Stop() error {
+	return nil
+}
+
+// Get always returns sphinx.ErrLogEntryNotFound.
+func (n *noopReplayLog) Get(*sphinx.HashPrefix) (uint32, error) {
+	return 0, sphinx.ErrLogEntryNotFound
+}
+
+// Put is a no-op.
+func (n *noopReplayLog) Put(*sphinx.HashPrefix, uint32) error {
+	return nil
+}
+
+// Delete is a no-op.
+func (n *noopReplayLog) Delete(*sphinx.HashPrefix) error {
+	return nil
+}
+
+// PutBatch returns an empty replay set.
+func (n *noopReplayLog) PutBatch(*sphinx.Batch) (*sphinx.ReplaySet, error) {
+	return sphinx.NewReplaySet(), nil
+}
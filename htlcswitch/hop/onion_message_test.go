@@ -0,0 +1,136 @@
+package hop
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOnionMessageProcessor creates an onion message processor backed by a
+// fresh node key.
+func newTestOnionMessageProcessor(t *testing.T) (*btcec.PublicKey,
+	*OnionMessageProcessor) {
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	return priv.PubKey(), NewOnionMessageProcessor(
+		&sphinx.PrivKeyECDH{PrivKey: priv}, &chaincfg.RegressionNetParams,
+	)
+}
+
+// TestOnionMessage tests that an onion message is forwarded along its path and
+// delivers its payload, including the reply path, to the recipient.
+func TestOnionMessage(t *testing.T) {
+	t.Parallel()
+
+	alicePub, alice := newTestOnionMessageProcessor(t)
+	bobPub, bob := newTestOnionMessageProcessor(t)
+	carolPub, carol := newTestOnionMessageProcessor(t)
+
+	replyPath, err := BuildOnionMessagePath(
+		[]*btcec.PublicKey{bobPub, alicePub},
+		&record.BlindedRouteData{PathID: []byte{7}},
+	)
+	require.NoError(t, err)
+
+	payload := &OnionMessagePayload{
+		ReplyPath: replyPath,
+		CustomRecords: record.CustomSet{
+			MinOnionMessageCustomType: []byte{1, 2, 3},
+		},
+	}
+
+	// Alice sends a message to Carol through Bob.
+	firstNode, msg, err := CreateOnionMessage(
+		[]*btcec.PublicKey{bobPub, carolPub}, nil, payload,
+	)
+	require.NoError(t, err)
+	require.True(t, firstNode.IsEqual(bobPub))
+
+	processed, err := bob.ProcessOnionMessage(msg)
+	require.NoError(t, err)
+	require.Nil(t, processed.Payload)
+	require.True(t, processed.NextNode.IsEqual(carolPub))
+
+	// Alice must not be able to process the message meant for Carol.
+	_, err = alice.ProcessOnionMessage(processed.NextMessage)
+	require.Error(t, err)
+
+	processed, err = carol.ProcessOnionMessage(processed.NextMessage)
+	require.NoError(t, err)
+	require.Nil(t, processed.NextMessage)
+	require.Equal(t, payload.CustomRecords, processed.Payload.CustomRecords)
+	require.Equal(t, replyPath, processed.Payload.ReplyPath)
+
+	// Carol replies to Alice using the reply path, which is a blinded
+	// destination that starts at Bob.
+	reply := &OnionMessagePayload{
+		CustomRecords: record.CustomSet{
+			MinOnionMessageCustomType + 1: []byte{4, 5, 6},
+		},
+	}
+	firstNode, msg, err = CreateOnionMessage(
+		nil, processed.Payload.ReplyPath, reply,
+	)
+	require.NoError(t, err)
+	require.True(t, firstNode.IsEqual(bobPub))
+
+	processed, err = bob.ProcessOnionMessage(msg)
+	require.NoError(t, err)
+	require.True(t, processed.NextNode.IsEqual(alicePub))
+
+	processed, err = alice.ProcessOnionMessage(processed.NextMessage)
+	require.NoError(t, err)
+	require.Equal(t, reply.CustomRecords, processed.Payload.CustomRecords)
+	require.Nil(t, processed.Payload.ReplyPath)
+	require.Equal(t, []byte{7}, processed.Payload.PathID)
+
+	// Carol can also send a message to a blinded destination through a
+	// path of her own choosing.
+	alicePath, err := BuildOnionMessagePath(
+		[]*btcec.PublicKey{alicePub}, nil,
+	)
+	require.NoError(t, err)
+
+	firstNode, msg, err = CreateOnionMessage(
+		[]*btcec.PublicKey{bobPub}, alicePath, reply,
+	)
+	require.NoError(t, err)
+	require.True(t, firstNode.IsEqual(bobPub))
+
+	processed, err = bob.ProcessOnionMessage(msg)
+	require.NoError(t, err)
+	require.True(t, processed.NextNode.IsEqual(alicePub))
+
+	processed, err = alice.ProcessOnionMessage(processed.NextMessage)
+	require.NoError(t, err)
+	require.Equal(t, reply.CustomRecords, processed.Payload.CustomRecords)
+
+	// Custom records below the minimum type are rejected.
+	_, _, err = CreateOnionMessage(
+		[]*btcec.PublicKey{bobPub}, nil, &OnionMessagePayload{
+			CustomRecords: record.CustomSet{1: nil},
+		},
+	)
+	require.Error(t, err)
+
+	// A tampered onion must be rejected.
+	_, msg, err = CreateOnionMessage(
+		[]*btcec.PublicKey{bobPub}, nil, reply,
+	)
+	require.NoError(t, err)
+	msg.OnionBlob[100] ^= 1
+	_, err = bob.ProcessOnionMessage(msg)
+	require.Error(t, err)
+
+	_, err = bob.ProcessOnionMessage(&lnwire.OnionMessage{
+		BlindingPoint: bobPub,
+	})
+	require.Error(t, err)
+}
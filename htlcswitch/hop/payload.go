@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/btcsuite/btcd/btcec"
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
@@ -90,6 +91,24 @@ type Payload struct {
 	// a TLV onion payload.
 	AMP *record.AMP
 
+	// EncryptedData is the encrypted_recipient_data of a hop inside of a
+	// blinded route. It contains the forwarding instructions the creator of
+	// the blinded route encrypted for us.
+	EncryptedData []byte
+
+	// BlindingPoint is the blinding point that is included in the payload
+	// of the introduction node of a blinded route.
+	BlindingPoint *btcec.PublicKey
+
+	// TotalAmtMsat is the total amount of a payment to a blinded route.
+	// This is only set in the payload of the final hop of a blinded route.
+	TotalAmtMsat lnwire.MilliSatoshi
+
+	// TrampolineOnion is the serialized trampoline onion of a payment that
+	// is delegated to us. It is only included in the payload of the final
+	// hop.
+	TrampolineOnion []byte
+
 	// customRecords are user-defined records in the custom type range that
 	// were included in the payload.
 	customRecords record.CustomSet
@@ -115,11 +134,15 @@ func NewLegacyPayload(f *sphinx.HopData) *Payload {
 // should correspond to the bytes encapsulated in a TLV onion payload.
 func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 	var (
-		cid  uint64
-		amt  uint64
-		cltv uint32
-		mpp  = &record.MPP{}
-		amp  = &record.AMP{}
+		cid           uint64
+		amt           uint64
+		cltv          uint32
+		mpp           = &record.MPP{}
+		amp           = &record.AMP{}
+		encryptedData []byte
+		blindingPoint *btcec.PublicKey
+		totalAmt      uint64
+		trampoline    []byte
 	)
 
 	tlvStream, err := tlv.NewStream(
@@ -127,7 +150,11 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		record.NewLockTimeRecord(&cltv),
 		record.NewNextHopIDRecord(&cid),
 		mpp.Record(),
+		record.NewEncryptedDataRecord(&encryptedData),
+		record.NewBlindingPointRecord(&blindingPoint),
 		amp.Record(),
+		record.NewTotalAmtMsatBlindedRecord(&totalAmt),
+		record.NewTrampolineOnionRecord(&trampoline),
 	)
 	if err != nil {
 		return nil, err
@@ -139,9 +166,15 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 	}
 
 	// Validate whether the sender properly included or omitted tlv records
-	// in accordance with BOLT 04.
+	// in accordance with BOLT 04. The payload of a hop inside of a blinded
+	// route follows a different set of rules, as the forwarding
+	// instructions are contained in the encrypted data.
 	nextHop := lnwire.NewShortChanIDFromInt(cid)
-	err = ValidateParsedPayloadTypes(parsedTypes, nextHop)
+	if _, ok := parsedTypes[record.EncryptedDataOnionType]; ok {
+		err = validateBlindedPayloadTypes(parsedTypes)
+	} else {
+		err = ValidateParsedPayloadTypes(parsedTypes, nextHop)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -168,6 +201,16 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		amp = nil
 	}
 
+	// Only set the blinding point if it was actually included.
+	if _, ok := parsedTypes[record.BlindingPointOnionType]; !ok {
+		blindingPoint = nil
+	}
+
+	// Only set the trampoline onion if it was actually included.
+	if _, ok := parsedTypes[record.TrampolineOnionType]; !ok {
+		trampoline = nil
+	}
+
 	// Filter out the custom records.
 	customRecords := NewCustomRecords(parsedTypes)
 
@@ -178,9 +221,13 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 			AmountToForward: lnwire.MilliSatoshi(amt),
 			OutgoingCTLV:    cltv,
 		},
-		MPP:           mpp,
-		AMP:           amp,
-		customRecords: customRecords,
+		MPP:             mpp,
+		AMP:             amp,
+		EncryptedData:   encryptedData,
+		BlindingPoint:   blindingPoint,
+		TotalAmtMsat:    lnwire.MilliSatoshi(totalAmt),
+		TrampolineOnion: trampoline,
+		customRecords:   customRecords,
 	}, nil
 }
 
@@ -217,6 +264,9 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 	_, hasNextHop := parsedTypes[record.NextHopOnionType]
 	_, hasMPP := parsedTypes[record.MPPOnionType]
 	_, hasAMP := parsedTypes[record.AMPOnionType]
+	_, hasBlindingPoint := parsedTypes[record.BlindingPointOnionType]
+	_, hasTotalAmt := parsedTypes[record.TotalAmtMsatBlindedType]
+	_, hasTrampoline := parsedTypes[record.TrampolineOnionType]
 
 	switch {
 
@@ -261,6 +311,103 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 			Violation: IncludedViolation,
 			FinalHop:  isFinalHop,
 		}
+
+	// Intermediate nodes should never receive a trampoline onion.
+	case !isFinalHop && hasTrampoline:
+		return ErrInvalidPayload{
+			Type:      record.TrampolineOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// Hops outside of a blinded route should never receive a blinding
+	// point.
+	case hasBlindingPoint:
+		return ErrInvalidPayload{
+			Type:      record.BlindingPointOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// Hops outside of a blinded route should never receive the total
+	// amount of a blinded payment.
+	case hasTotalAmt:
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	return nil
+}
+
+// validateBlindedPayloadTypes checks the types parsed from the payload of a
+// hop inside of a blinded route. The payload of an intermediate hop only
+// contains the encrypted data, while the payload of the final hop also
+// contains the amount, the cltv expiry and the total amount of the payment.
+// The next hop id must never be included, as it's part of the encrypted data.
+func validateBlindedPayloadTypes(parsedTypes tlv.TypeMap) error {
+	_, hasAmt := parsedTypes[record.AmtOnionType]
+	_, hasLockTime := parsedTypes[record.LockTimeOnionType]
+	_, hasNextHop := parsedTypes[record.NextHopOnionType]
+	_, hasAMP := parsedTypes[record.AMPOnionType]
+	_, hasTotalAmt := parsedTypes[record.TotalAmtMsatBlindedType]
+
+	// We identify the final hop by the amount being present, the encrypted
+	// data will later be checked to be consistent with this.
+	isFinalHop := hasAmt
+
+	switch {
+
+	// The next hop is always part of the encrypted data.
+	case hasNextHop:
+		return ErrInvalidPayload{
+			Type:      record.NextHopOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// Spontaneous payments to blinded routes are not supported.
+	case hasAMP:
+		return ErrInvalidPayload{
+			Type:      record.AMPOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// The final hop must include a cltv expiry.
+	case isFinalHop && !hasLockTime:
+		return ErrInvalidPayload{
+			Type:      record.LockTimeOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  true,
+		}
+
+	// The final hop must include the total amount of the payment.
+	case isFinalHop && !hasTotalAmt:
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: OmittedViolation,
+			FinalHop:  true,
+		}
+
+	// Intermediate hops must not include a cltv expiry, as it's derived
+	// from the encrypted data.
+	case !isFinalHop && hasLockTime:
+		return ErrInvalidPayload{
+			Type:      record.LockTimeOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  false,
+		}
+
+	// Intermediate hops must not include the total amount.
+	case !isFinalHop && hasTotalAmt:
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: IncludedViolation,
+			FinalHop:  false,
+		}
 	}
 
 	return nil
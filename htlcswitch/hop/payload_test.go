@@ -216,6 +216,70 @@ var decodePayloadTests = []decodePayloadTest{
 			FinalHop:  false,
 		},
 	},
+	{
+		name:    "blinded intermediate hop valid",
+		payload: []byte{0x0a, 0x02, 0xaa, 0xbb},
+	},
+	{
+		name: "blinded final hop valid",
+		payload: []byte{0x02, 0x00, 0x04, 0x00, 0x0a, 0x01, 0xaa,
+			0x12, 0x01, 0x08,
+		},
+	},
+	{
+		name:    "blinded final hop no total amount",
+		payload: []byte{0x02, 0x00, 0x04, 0x00, 0x0a, 0x01, 0xaa},
+		expErr: hop.ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: hop.OmittedViolation,
+			FinalHop:  true,
+		},
+	},
+	{
+		name:    "blinded intermediate hop with expiry",
+		payload: []byte{0x04, 0x00, 0x0a, 0x01, 0xaa},
+		expErr: hop.ErrInvalidPayload{
+			Type:      record.LockTimeOnionType,
+			Violation: hop.IncludedViolation,
+			FinalHop:  false,
+		},
+	},
+	{
+		name: "blinded hop with next sid",
+		payload: []byte{0x06, 0x08, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x0a, 0x01, 0xaa,
+		},
+		expErr: hop.ErrInvalidPayload{
+			Type:      record.NextHopOnionType,
+			Violation: hop.IncludedViolation,
+			FinalHop:  false,
+		},
+	},
+	{
+		name:    "total amount without blinding",
+		payload: []byte{0x02, 0x00, 0x04, 0x00, 0x12, 0x01, 0x08},
+		expErr: hop.ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: hop.IncludedViolation,
+			FinalHop:  true,
+		},
+	},
+	{
+		name: "intermediate hop with trampoline onion",
+		payload: []byte{0x02, 0x00, 0x04, 0x00, 0x06, 0x08, 0x01, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x14, 0x02, 0xaa,
+			0xbb,
+		},
+		expErr: hop.ErrInvalidPayload{
+			Type:      record.TrampolineOnionType,
+			Violation: hop.IncludedViolation,
+			FinalHop:  false,
+		},
+	},
+	{
+		name:    "final hop with trampoline onion",
+		payload: []byte{0x02, 0x00, 0x04, 0x00, 0x14, 0x02, 0xaa, 0xbb},
+	},
 	{
 		name: "final hop with mpp",
 		payload: []byte{
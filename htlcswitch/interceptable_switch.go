@@ -3,14 +3,22 @@ package htlcswitch
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+const (
+	// interceptorFailureDetail is the failure detail that is logged for
+	// forwards that were failed by the interceptor.
+	interceptorFailureDetail = "failed by interceptor"
+)
+
 var (
 	// ErrFwdNotExists is an error returned when the caller tries to resolve
 	// a forward that doesn't exist anymore.
@@ -22,32 +30,231 @@ var (
 // intercepts forward requests. A reference to the Switch is held in order
 // to communicate back the interception result where the options are:
 // Resume - forwards the original request to the switch as is.
+// ResumeModified - forwards the request with a modified channel or amount.
 // Settle - routes UpdateFulfillHTLC to the originating link.
 // Fail - routes UpdateFailHTLC to the originating link.
+//
+// All intercepted forwards are tracked until they are resolved. If an
+// interceptor is required, forwards are held while no interceptor is
+// registered and handed to the next interceptor that registers. Otherwise
+// they are resumed when the interceptor is removed. Held forwards that come
+// within the cltv reject delta of their incoming expiry are failed back, so
+// that the incoming channel doesn't need to be force closed.
 type InterceptableSwitch struct {
+	// bestHeight is the best known height of the main chain. It must be
+	// used atomically.
+	bestHeight uint32
+
 	sync.RWMutex
 
 	// htlcSwitch is the underline switch
 	htlcSwitch *Switch
 
+	// notifier is used to receive new blocks, on which we fail back the
+	// held forwards that are about to expire.
+	notifier chainntnfs.ChainNotifier
+
+	// cltvRejectDelta is the number of blocks before the incoming expiry
+	// of a held forward at which it is failed back.
+	cltvRejectDelta uint32
+
 	// fwdInterceptor is the callback that is called for each forward of
 	// an incoming htlc. It should return true if it is interested in handling
 	// it.
 	fwdInterceptor ForwardInterceptor
+
+	// requireInterceptor indicates whether forwards are held instead of
+	// resumed while no interceptor is registered.
+	requireInterceptor bool
+
+	// heldForwards contains all intercepted forwards that are not resolved
+	// yet, keyed by their incoming circuit.
+	heldForwards map[channeldb.CircuitKey]*interceptedForward
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// InterceptableSwitchConfig contains the configuration of the
+// InterceptableSwitch.
+type InterceptableSwitchConfig struct {
+	// Switch is the switch that forwards are passed on to.
+	Switch *Switch
+
+	// Notifier is used to receive new blocks.
+	Notifier chainntnfs.ChainNotifier
+
+	// CltvRejectDelta is the number of blocks before the incoming expiry
+	// of a held forward at which it is failed back.
+	CltvRejectDelta uint32
+
+	// RequireInterceptor indicates whether forwards are never resumed
+	// without the decision of an interceptor.
+	RequireInterceptor bool
 }
 
 // NewInterceptableSwitch returns an instance of InterceptableSwitch.
-func NewInterceptableSwitch(s *Switch) *InterceptableSwitch {
-	return &InterceptableSwitch{htlcSwitch: s}
+func NewInterceptableSwitch(
+	cfg *InterceptableSwitchConfig) *InterceptableSwitch {
+
+	return &InterceptableSwitch{
+		bestHeight:         cfg.Switch.BestHeight(),
+		htlcSwitch:         cfg.Switch,
+		notifier:           cfg.Notifier,
+		cltvRejectDelta:    cfg.CltvRejectDelta,
+		requireInterceptor: cfg.RequireInterceptor,
+		heldForwards: make(
+			map[channeldb.CircuitKey]*interceptedForward,
+		),
+		quit: make(chan struct{}),
+	}
+}
+
+// Start subscribes to new blocks, so that held forwards are failed back before
+// they expire.
+func (s *InterceptableSwitch) Start() error {
+	blockEpochStream, err := s.notifier.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go s.blockHandler(blockEpochStream)
+
+	return nil
+}
+
+// Stop stops the InterceptableSwitch. Held forwards stay unresolved.
+func (s *InterceptableSwitch) Stop() error {
+	close(s.quit)
+	s.wg.Wait()
+
+	return nil
 }
 
-// SetInterceptor sets the ForwardInterceptor to be used.
+// blockHandler fails back the held forwards that are about to expire whenever
+// a new block arrives.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *InterceptableSwitch) blockHandler(
+	blockEpochStream *chainntnfs.BlockEpochEvent) {
+
+	defer s.wg.Done()
+	defer blockEpochStream.Cancel()
+
+	for {
+		select {
+		case blockEpoch, ok := <-blockEpochStream.Epochs:
+			if !ok {
+				return
+			}
+
+			height := uint32(blockEpoch.Height)
+			atomic.StoreUint32(&s.bestHeight, height)
+			s.failExpiring(height)
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// isExpiring returns true if the incoming htlc of the forward is within the
+// cltv reject delta of its expiry at the given height.
+func (s *InterceptableSwitch) isExpiring(fwd *interceptedForward,
+	height uint32) bool {
+
+	return fwd.packet.incomingTimeout <= height+s.cltvRejectDelta
+}
+
+// failExpiring fails back all held forwards that are about to expire at the
+// given height.
+func (s *InterceptableSwitch) failExpiring(height uint32) {
+	var expiring []*interceptedForward
+	s.RLock()
+	for _, fwd := range s.heldForwards {
+		if s.isExpiring(fwd, height) {
+			expiring = append(expiring, fwd)
+		}
+	}
+	s.RUnlock()
+
+	for _, fwd := range expiring {
+		log.Debugf("Failing held forward %v with incoming expiry %v at "+
+			"height %v", fwd.key(), fwd.packet.incomingTimeout,
+			height)
+
+		err := fwd.Fail()
+		if err != nil && err != ErrFwdNotExists {
+			log.Errorf("Failed to fail expiring held forward %v: %v",
+				fwd.key(), err)
+		}
+	}
+}
+
+// SetInterceptor sets the ForwardInterceptor to be used. Forwards that are
+// still held are handed to the new interceptor. If the interceptor is removed
+// and no interceptor is required, the held forwards are resumed.
 func (s *InterceptableSwitch) SetInterceptor(
 	interceptor ForwardInterceptor) {
 
 	s.Lock()
-	defer s.Unlock()
 	s.fwdInterceptor = interceptor
+
+	held := make([]*interceptedForward, 0, len(s.heldForwards))
+	for _, fwd := range s.heldForwards {
+		held = append(held, fwd)
+	}
+	s.Unlock()
+
+	if len(held) == 0 {
+		return
+	}
+
+	if interceptor == nil {
+		if s.requireInterceptor {
+			log.Infof("Interceptor removed, holding %v forwards "+
+				"until an interceptor is registered", len(held))
+
+			return
+		}
+
+		log.Infof("Interceptor removed, resuming %v held forwards",
+			len(held))
+
+		for _, fwd := range held {
+			err := fwd.Resume()
+			if err != nil && err != ErrFwdNotExists {
+				log.Errorf("Failed to resume held forward: %v",
+					err)
+			}
+		}
+
+		return
+	}
+
+	// The interceptor may only be able to accept forwards after it was
+	// registered, so the held forwards are replayed in the background.
+	log.Infof("Interceptor registered, replaying %v held forwards",
+		len(held))
+
+	go func() {
+		for _, fwd := range held {
+			if s.dispatch(fwd, interceptor) {
+				continue
+			}
+
+			// The interceptor isn't interested in the forward, so
+			// it's released and we forward it ourselves.
+			err := fwd.htlcSwitch.ForwardPackets(
+				fwd.linkQuit, fwd.packet,
+			)
+			if err != nil {
+				log.Errorf("Failed to forward replayed "+
+					"forward %v: %v", fwd.key(), err)
+			}
+		}
+	}()
 }
 
 // ForwardPackets attempts to forward the batch of htlcs through the
@@ -63,13 +270,13 @@ func (s *InterceptableSwitch) ForwardPackets(linkQuit chan struct{},
 	s.Unlock()
 
 	// Optimize for the case we don't have an interceptor.
-	if interceptor == nil {
+	if interceptor == nil && !s.requireInterceptor {
 		return s.htlcSwitch.ForwardPackets(linkQuit, packets...)
 	}
 
 	var notIntercepted []*htlcPacket
 	for _, p := range packets {
-		if !s.interceptForward(p, interceptor, linkQuit) {
+		if !s.interceptForward(p, linkQuit) {
 			notIntercepted = append(notIntercepted, p)
 		}
 	}
@@ -81,7 +288,7 @@ func (s *InterceptableSwitch) ForwardPackets(linkQuit chan struct{},
 // are being checked for interception. It can be extended in the future given
 // the right use case.
 func (s *InterceptableSwitch) interceptForward(packet *htlcPacket,
-	interceptor ForwardInterceptor, linkQuit chan struct{}) bool {
+	linkQuit chan struct{}) bool {
 
 	switch htlc := packet.htlc.(type) {
 	case *lnwire.UpdateAddHTLC:
@@ -91,52 +298,173 @@ func (s *InterceptableSwitch) interceptForward(packet *htlcPacket,
 		}
 
 		intercepted := &interceptedForward{
-			linkQuit:   linkQuit,
-			htlc:       htlc,
-			packet:     packet,
-			htlcSwitch: s.htlcSwitch,
+			linkQuit:    linkQuit,
+			htlc:        htlc,
+			packet:      packet,
+			htlcSwitch:  s.htlcSwitch,
+			interceptor: s,
+		}
+
+		// The forward is held before it is passed to the interceptor,
+		// so that it can be replayed if the interceptor is replaced in
+		// the meantime.
+		s.Lock()
+		s.heldForwards[intercepted.key()] = intercepted
+		interceptor := s.fwdInterceptor
+		s.Unlock()
+
+		// A forward that is about to expire is failed back right
+		// away, as it would be failed on the next block anyway.
+		if s.isExpiring(intercepted, atomic.LoadUint32(&s.bestHeight)) {
+			log.Debugf("Failing forward %v with incoming expiry %v",
+				intercepted.key(), packet.incomingTimeout)
+
+			err := intercepted.Fail()
+			if err != nil && err != ErrFwdNotExists {
+				log.Errorf("Failed to fail expiring forward "+
+					"%v: %v", intercepted.key(), err)
+			}
+
+			return true
+		}
+
+		// The interceptor may have been removed after the caller
+		// checked for it, in which case the held forwards were already
+		// resumed without this one.
+		if interceptor == nil {
+			if s.requireInterceptor {
+				log.Debugf("Holding forward %v until an "+
+					"interceptor is registered",
+					intercepted.key())
+
+				return true
+			}
+
+			// If the forward isn't held anymore, it's resolved
+			// already and mustn't be forwarded again.
+			return !s.release(intercepted.key())
 		}
 
 		// If this htlc was intercepted, don't handle the forward.
-		return interceptor(intercepted)
+		return s.dispatch(intercepted, interceptor)
+
 	default:
 		return false
 	}
 }
 
+// dispatch passes a held forward to the given interceptor. It returns true if
+// the forward is still held afterwards. If the interceptor isn't interested in
+// the forward and no interceptor is required, the forward is released so that
+// the caller can forward it.
+func (s *InterceptableSwitch) dispatch(fwd *interceptedForward,
+	interceptor ForwardInterceptor) bool {
+
+	if interceptor(fwd) || s.requireInterceptor {
+		return true
+	}
+
+	// The forward may have been resolved already, for example if it was
+	// resumed because the interceptor was removed. In that case it must
+	// not be forwarded again.
+	return !s.release(fwd.key())
+}
+
+// release removes a forward from the set of held forwards. It returns false if
+// the forward wasn't held, which means that it is resolved already.
+func (s *InterceptableSwitch) release(key channeldb.CircuitKey) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.heldForwards[key]; !ok {
+		return false
+	}
+	delete(s.heldForwards, key)
+
+	return true
+}
+
 // interceptedForward implements the InterceptedForward interface.
 // It is passed from the switch to external interceptors that are interested
 // in holding forwards and resolve them manually.
 type interceptedForward struct {
-	linkQuit   chan struct{}
-	htlc       *lnwire.UpdateAddHTLC
-	packet     *htlcPacket
-	htlcSwitch *Switch
+	linkQuit    chan struct{}
+	htlc        *lnwire.UpdateAddHTLC
+	packet      *htlcPacket
+	htlcSwitch  *Switch
+	interceptor *InterceptableSwitch
+}
+
+// key returns the incoming circuit key of the forward.
+func (f *interceptedForward) key() channeldb.CircuitKey {
+	return channeldb.CircuitKey{
+		ChanID: f.packet.incomingChanID,
+		HtlcID: f.packet.incomingHTLCID,
+	}
 }
 
 // Packet returns the intercepted htlc packet.
 func (f *interceptedForward) Packet() InterceptedPacket {
 	return InterceptedPacket{
-		IncomingCircuit: channeldb.CircuitKey{
-			ChanID: f.packet.incomingChanID,
-			HtlcID: f.packet.incomingHTLCID,
-		},
-		OutgoingChanID: f.packet.outgoingChanID,
-		Hash:           f.htlc.PaymentHash,
-		OutgoingExpiry: f.htlc.Expiry,
-		OutgoingAmount: f.htlc.Amount,
-		IncomingAmount: f.packet.incomingAmount,
-		IncomingExpiry: f.packet.incomingTimeout,
-		CustomRecords:  f.packet.customRecords,
-		OnionBlob:      f.htlc.OnionBlob,
+		IncomingCircuit: f.key(),
+		OutgoingChanID:  f.packet.outgoingChanID,
+		Hash:            f.htlc.PaymentHash,
+		OutgoingExpiry:  f.htlc.Expiry,
+		OutgoingAmount:  f.htlc.Amount,
+		IncomingAmount:  f.packet.incomingAmount,
+		IncomingExpiry:  f.packet.incomingTimeout,
+		CustomRecords:   f.packet.customRecords,
+		OnionBlob:       f.htlc.OnionBlob,
 	}
 }
 
 // Resume resumes the default behavior as if the packet was not intercepted.
 func (f *interceptedForward) Resume() error {
+	if !f.interceptor.release(f.key()) {
+		return ErrFwdNotExists
+	}
+
 	return f.htlcSwitch.ForwardPackets(f.linkQuit, f.packet)
 }
 
+// ResumeModified forwards the packet with the given outgoing channel and
+// amount instead of the original ones. The switch checks the forwarding
+// policy of the outgoing channel against the modified values as usual.
+func (f *interceptedForward) ResumeModified(
+	outgoingChanID *lnwire.ShortChannelID,
+	outgoingAmount *lnwire.MilliSatoshi) error {
+
+	// We never forward more than we received, because the difference
+	// would have to be paid by us.
+	if outgoingAmount != nil && *outgoingAmount > f.packet.incomingAmount {
+		return fmt.Errorf("outgoing amount %v exceeds incoming "+
+			"amount %v", *outgoingAmount, f.packet.incomingAmount)
+	}
+
+	if !f.interceptor.release(f.key()) {
+		return ErrFwdNotExists
+	}
+
+	// The packet and htlc are copied, so that the original forward stays
+	// untouched.
+	htlc := *f.htlc
+	packet := *f.packet
+	packet.htlc = &htlc
+
+	if outgoingChanID != nil {
+		packet.outgoingChanID = *outgoingChanID
+	}
+	if outgoingAmount != nil {
+		packet.amount = *outgoingAmount
+		htlc.Amount = *outgoingAmount
+	}
+
+	log.Debugf("Resuming forward %v with outgoing channel %v and amount "+
+		"%v", f.key(), packet.outgoingChanID, packet.amount)
+
+	return f.htlcSwitch.ForwardPackets(f.linkQuit, &packet)
+}
+
 // Fail forward a failed packet to the switch.
 func (f *interceptedForward) Fail() error {
 	update, err := f.htlcSwitch.cfg.FetchLastChannelUpdate(
@@ -146,15 +474,57 @@ func (f *interceptedForward) Fail() error {
 		return err
 	}
 
-	reason, err := f.packet.obfuscator.EncryptFirstHop(
-		lnwire.NewTemporaryChannelFailure(update),
-	)
+	return f.FailWithMessage(lnwire.NewTemporaryChannelFailure(update))
+}
+
+// FailWithMessage fails the packet back with the given failure message.
+func (f *interceptedForward) FailWithMessage(
+	failure lnwire.FailureMessage) error {
+
+	reason, err := f.packet.obfuscator.EncryptFirstHop(failure)
 	if err != nil {
 		return fmt.Errorf("failed to encrypt failure reason %v", err)
 	}
-	return f.resolve(&lnwire.UpdateFailHTLC{
+	return f.fail(reason, failure.Code())
+}
+
+// FailWithReason fails the packet back with an encrypted failure reason, which
+// is obfuscated like the failures that are returned by the outgoing channel of
+// a forward.
+func (f *interceptedForward) FailWithReason(
+	reason lnwire.OpaqueReason) error {
+
+	if len(reason) == 0 {
+		return errors.New("empty failure reason")
+	}
+
+	// The failure reason is opaque to us, so there is no failure code
+	// to log.
+	return f.fail(f.packet.obfuscator.IntermediateEncrypt(reason), 0)
+}
+
+// fail fails the packet back with the given encrypted failure reason and logs
+// it as a forward that was failed by our node. The failure code is logged
+// along with it, if known.
+func (f *interceptedForward) fail(reason lnwire.OpaqueReason,
+	code lnwire.FailCode) error {
+
+	err := f.resolve(&lnwire.UpdateFailHTLC{
 		Reason: reason,
 	})
+	if err != nil {
+		return err
+	}
+
+	// The fail packet is delivered to the incoming link directly, so the
+	// switch doesn't see it and we need to log the failure here.
+	f.htlcSwitch.logFailedForward(
+		f.packet.incomingChanID, f.packet.outgoingChanID,
+		f.packet.incomingAmount, f.packet.amount, false, code,
+		interceptorFailureDetail,
+	)
+
+	return nil
 }
 
 // Settle forwards a settled packet to the switch.
@@ -170,6 +540,10 @@ func (f *interceptedForward) Settle(preimage lntypes.Preimage) error {
 // resolve is used for both Settle and Fail and forwards the message to the
 // switch.
 func (f *interceptedForward) resolve(message lnwire.Message) error {
+	if !f.interceptor.release(f.key()) {
+		return ErrFwdNotExists
+	}
+
 	pkt := &htlcPacket{
 		incomingChanID: f.packet.incomingChanID,
 		incomingHTLCID: f.packet.incomingHTLCID,
@@ -1,6 +1,8 @@
 package htlcswitch
 
 import (
+	"time"
+
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/invoices"
@@ -41,6 +43,27 @@ type InvoiceDatabase interface {
 	HodlUnsubscribeAll(subscriber chan<- interface{})
 }
 
+// TrampolineRelayer is an interface that relays trampoline payments which are
+// delegated to us.
+type TrampolineRelayer interface {
+	// RelayHtlc processes an incoming htlc that carries a trampoline
+	// onion. If we are the final recipient of the trampoline onion, the
+	// htlc is handed to the invoice registry. Otherwise the payment is
+	// relayed to the next node of the trampoline onion once all htlcs of
+	// the payment, as indicated by the mpp record, have arrived. The
+	// return value describes how the htlc should be resolved. If the htlc
+	// cannot be resolved immediately, the resolution is sent on the passed
+	// in hodlChan later.
+	RelayHtlc(payHash lntypes.Hash, paidAmount lnwire.MilliSatoshi,
+		expiry uint32, currentHeight int32,
+		circuitKey channeldb.CircuitKey, hodlChan chan<- interface{},
+		trampolineOnion []byte, mpp *record.MPP) (
+		invoices.HtlcResolution, error)
+
+	// UnsubscribeAll unsubscribes from all htlc resolutions.
+	UnsubscribeAll(subscriber chan<- interface{})
+}
+
 // ChannelLink is an interface which represents the subsystem for managing the
 // incoming htlc requests, applying the changes to the channel, and also
 // propagating/forwarding it to htlc switch.
@@ -109,11 +132,12 @@ type ChannelLink interface {
 	// satisfy the current forwarding policy fo the target link. Otherwise,
 	// a LinkError with a valid protocol failure message should be returned
 	// in order to signal to the source of the HTLC, the policy consistency
-	// issue.
+	// issue. The inbound fee of the incoming link is added to the fee that
+	// the HTLC must pay.
 	CheckHtlcForward(payHash [32]byte, incomingAmt lnwire.MilliSatoshi,
 		amtToForward lnwire.MilliSatoshi,
 		incomingTimeout, outgoingTimeout uint32,
-		heightNow uint32) *LinkError
+		inboundFee lnwire.Fee, heightNow uint32) *LinkError
 
 	// CheckHtlcTransit should return a nil error if the passed HTLC details
 	// satisfy the current channel policy.  Otherwise, a LinkError with a
@@ -165,6 +189,15 @@ type ForwardingLog interface {
 	// sub-systems can then query the contents of the log for analysis,
 	// visualizations, etc.
 	AddForwardingEvents([]channeldb.ForwardingEvent) error
+
+	// AddFailedForwardingEvents is a method that should write out the set
+	// of failed forwarding events in a batch to persistent storage.
+	AddFailedForwardingEvents([]channeldb.FailedForwardingEvent) error
+
+	// PruneFailedForwardingEvents is a method that should delete the
+	// failed forwarding events that happened before the given time from
+	// persistent storage.
+	PruneFailedForwardingEvents(before time.Time) error
 }
 
 // TowerClient is the primary interface used by the daemon to backup pre-signed
@@ -241,7 +274,8 @@ type InterceptedPacket struct {
 // htlc. It contains all the information about the packet which accordingly
 // the interceptor decides if to hold or not.
 // In addition this interface allows a later resolution by calling either
-// Resume, Settle or Fail.
+// Resume, ResumeModified, Settle or one of the Fail methods. A forward can only
+// be resolved once, later resolutions return ErrFwdNotExists.
 type InterceptedForward interface {
 	// Packet returns the intercepted packet.
 	Packet() InterceptedPacket
@@ -251,12 +285,30 @@ type InterceptedForward interface {
 	// this htlc which usually means forward it.
 	Resume() error
 
+	// ResumeModified notifies the intention to resume an existing hold
+	// forward with a different outgoing channel and/or amount. Nil values
+	// leave the original value unchanged. The forwarding policy of the
+	// outgoing channel is checked against the modified values.
+	ResumeModified(outgoingChanID *lnwire.ShortChannelID,
+		outgoingAmount *lnwire.MilliSatoshi) error
+
 	// Settle notifies the intention to settle an existing hold
 	// forward with a given preimage.
 	Settle(lntypes.Preimage) error
 
-	// Fails notifies the intention to fail an existing hold forward
+	// Fails notifies the intention to fail an existing hold forward with a
+	// temporary channel failure.
 	Fail() error
+
+	// FailWithMessage notifies the intention to fail an existing hold
+	// forward with the given failure message.
+	FailWithMessage(failure lnwire.FailureMessage) error
+
+	// FailWithReason notifies the intention to fail an existing hold
+	// forward with an already encrypted failure reason, for example one
+	// that was returned by a downstream node. It is obfuscated like the
+	// failure of a regular forward.
+	FailWithReason(reason lnwire.OpaqueReason) error
 }
 
 // htlcNotifier is an interface which represents the input side of the
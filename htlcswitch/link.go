@@ -26,6 +26,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/queue"
+	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/ticker"
 )
 
@@ -86,6 +87,12 @@ type ForwardingPolicy struct {
 	// used to compute the required fee for a given HTLC.
 	FeeRate lnwire.MilliSatoshi
 
+	// InboundFee is the fee that must be paid for HTLCs that arrive
+	// through this link, on top of the fee of the outgoing link. It can be
+	// negative to give a discount on the fee of the outgoing link, but the
+	// total fee never drops below zero.
+	InboundFee lnwire.Fee
+
 	// TimeLockDelta is the absolute time-lock value, expressed in blocks,
 	// that will be subtracted from an incoming HTLC's timelock value to
 	// create the time-lock value for the forwarded outgoing HTLC. The
@@ -113,6 +120,23 @@ func ExpectedFee(f ForwardingPolicy,
 	return f.BaseFee + (htlcAmt*f.FeeRate)/1000000
 }
 
+// ExpectedTotalFee computes the expected fee for forwarding an htlc of the
+// given amount over a link with the given policy, if the htlc arrived through
+// a link with the given inbound fee. The inbound fee is computed on the amount
+// that includes the outgoing fee. A negative inbound fee reduces the total
+// fee, but never below zero.
+func ExpectedTotalFee(f ForwardingPolicy, inboundFee lnwire.Fee,
+	htlcAmt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	outFee := ExpectedFee(f, htlcAmt)
+	totalFee := int64(outFee) + inboundFee.CalcFee(htlcAmt+outFee)
+	if totalFee < 0 {
+		return 0
+	}
+
+	return lnwire.MilliSatoshi(totalFee)
+}
+
 // ChannelLinkConfig defines the configuration for the channel link. ALL
 // elements within the configuration MUST be non-nil for channel link to carry
 // out its duties.
@@ -167,6 +191,10 @@ type ChannelLinkConfig struct {
 	// in thread-safe manner.
 	Registry InvoiceDatabase
 
+	// TrampolineRelay relays the trampoline payments that are delegated
+	// to us. If nil, htlcs that carry a trampoline onion are rejected.
+	TrampolineRelay TrampolineRelayer
+
 	// PreimageCache is a global witness beacon that houses any new
 	// preimages discovered by other links. We'll use this to add new
 	// witnesses that we discover which will notify any sub-systems
@@ -515,6 +543,9 @@ func (l *channelLink) Stop() {
 	// As the link is stopping, we are no longer interested in htlc
 	// resolutions coming from the invoice registry.
 	l.cfg.Registry.HodlUnsubscribeAll(l.hodlQueue.ChanIn())
+	if l.cfg.TrampolineRelay != nil {
+		l.cfg.TrampolineRelay.UnsubscribeAll(l.hodlQueue.ChanIn())
+	}
 
 	if l.cfg.ChainEvents.Cancel != nil {
 		l.cfg.ChainEvents.Cancel()
@@ -690,6 +721,14 @@ func (l *channelLink) syncChanStates() error {
 			fundingLockedMsg := lnwire.NewFundingLocked(
 				l.ChanID(), nextRevocation,
 			)
+
+			// Channels that use an alias need to resend it, as
+			// the remote party might not have received it yet.
+			if l.channel.State().ChanType.HasScidAliasChan() {
+				alias := l.ShortChanID()
+				fundingLockedMsg.AliasScid = &alias
+			}
+
 			err = l.cfg.Peer.SendMessage(false, fundingLockedMsg)
 			if err != nil {
 				return fmt.Errorf("unable to re-send "+
@@ -1276,6 +1315,28 @@ func getResolutionFailure(resolution *invoices.HtlcFailResolution,
 		)
 	}
 
+	// Trampoline payments that are delegated to us are failed with the
+	// trampoline specific failures, so that the sender can adjust the
+	// fees and cltv delta it leaves for us.
+	switch resolution.Outcome {
+	case invoices.ResultTrampolineFeeInsufficient:
+		return NewDetailedLinkError(
+			&lnwire.FailTrampolineFeeInsufficient{},
+			resolution.Outcome,
+		)
+
+	case invoices.ResultTrampolineExpiryTooSoon:
+		return NewDetailedLinkError(
+			&lnwire.FailTrampolineExpiryTooSoon{},
+			resolution.Outcome,
+		)
+
+	case invoices.ResultTrampolineFailure:
+		return NewDetailedLinkError(
+			&lnwire.FailTemporaryNodeFailure{}, resolution.Outcome,
+		)
+	}
+
 	// If the htlc is not a MPP timeout, we fail it with
 	// FailIncorrectDetails. This error is sent for invoice payment
 	// failures such as underpayment/ expiry too soon and hodl invoices
@@ -1678,6 +1739,15 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			failure = &lnwire.FailInvalidOnionKey{
 				OnionSHA256: msg.ShaOnionBlob,
 			}
+
+		// A node inside of a blinded route failed the HTLC. If we're
+		// the introduction node, this is the error we're supposed to
+		// pass back to the sender.
+		case lnwire.CodeInvalidBlinding:
+			failure = &lnwire.FailInvalidBlinding{
+				OnionSHA256: msg.ShaOnionBlob,
+			}
+
 		default:
 			l.log.Warnf("unexpected failure code received in "+
 				"UpdateFailMailformedHTLC: %v", msg.FailureCode)
@@ -2190,7 +2260,7 @@ func (l *channelLink) UpdateForwardingPolicy(newPolicy ForwardingPolicy) {
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	incomingHtlcAmt, amtToForward lnwire.MilliSatoshi,
-	incomingTimeout, outgoingTimeout uint32,
+	incomingTimeout, outgoingTimeout uint32, inboundFee lnwire.Fee,
 	heightNow uint32) *LinkError {
 
 	l.RLock()
@@ -2207,8 +2277,9 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 
 	// Next, using the amount of the incoming HTLC, we'll calculate the
 	// expected fee this incoming HTLC must carry in order to satisfy the
-	// constraints of the outgoing link.
-	expectedFee := ExpectedFee(policy, amtToForward)
+	// constraints of the outgoing link and the inbound fee of the incoming
+	// link.
+	expectedFee := ExpectedTotalFee(policy, inboundFee, amtToForward)
 
 	// If the actual fee is less than our expected fee, then we'll reject
 	// this HTLC as it didn't provide a sufficient amount of fees, or the
@@ -2568,6 +2639,12 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 	l.log.Tracef("processing %d remote adds for height %d",
 		len(lockedInHtlcs), fwdPkg.Height)
 
+	// The inbound fee of this link applies to all htlcs that are forwarded
+	// from it.
+	l.RLock()
+	inboundFee := l.cfg.FwrdingPolicy.InboundFee
+	l.RUnlock()
+
 	decodeReqs := make(
 		[]hop.DecodeHopIteratorRequest, 0, len(lockedInHtlcs),
 	)
@@ -2583,9 +2660,11 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			onionReader := bytes.NewReader(pd.OnionBlob)
 
 			req := hop.DecodeHopIteratorRequest{
-				OnionReader:  onionReader,
-				RHash:        pd.RHash[:],
-				IncomingCltv: pd.Timeout,
+				OnionReader:    onionReader,
+				RHash:          pd.RHash[:],
+				IncomingCltv:   pd.Timeout,
+				IncomingAmount: pd.Amount,
+				BlindingPoint:  pd.BlindingPoint,
 			}
 
 			decodeReqs = append(decodeReqs, req)
@@ -2685,7 +2764,14 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			// for TLV payloads that also supports injecting invalid
 			// payloads. Deferring this non-trival effort till a
 			// later date
-			failure := lnwire.NewInvalidOnionPayload(failedType, 0)
+			var failure lnwire.FailureMessage
+			if _, ok := err.(hop.ErrInvalidBlinding); ok {
+				failure = lnwire.NewInvalidBlinding(onionBlob[:])
+			} else {
+				failure = lnwire.NewInvalidOnionPayload(
+					failedType, 0,
+				)
+			}
 			l.sendHTLCError(
 				pd, NewLinkError(failure), obfuscator, false,
 			)
@@ -2697,6 +2783,33 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 
 		fwdInfo := pld.ForwardingInfo()
 
+		// If we're the introduction node of a blinded route, any
+		// failure must be replaced by an invalid blinding error so
+		// that the sender can't probe the blinded route.
+		if pld.BlindingPoint != nil {
+			sphinxEncrypter, ok := obfuscator.(*hop.SphinxErrorEncrypter)
+			if ok {
+				obfuscator = &hop.IntroductionErrorEncrypter{
+					SphinxErrorEncrypter: sphinxEncrypter,
+					OnionSHA256:          sha256.Sum256(onionBlob[:]),
+				}
+			}
+		}
+
+		// We only act as the introduction node or the recipient of
+		// blinded routes, so HTLCs received inside of a blinded route
+		// can't be forwarded any further.
+		if pd.BlindingPoint != nil && fwdInfo.NextHop != hop.Exit {
+			l.log.Errorf("unable to forward htlc(%x) inside of "+
+				"blinded route", pd.RHash[:])
+
+			failure := lnwire.NewInvalidBlinding(onionBlob[:])
+			l.sendHTLCError(
+				pd, NewLinkError(failure), obfuscator, false,
+			)
+			continue
+		}
+
 		switch fwdInfo.NextHop {
 		case hop.Exit:
 			err := l.processExitHop(
@@ -2736,9 +2849,10 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 				// Otherwise, it was already processed, we can
 				// can collect it and continue.
 				addMsg := &lnwire.UpdateAddHTLC{
-					Expiry:      fwdInfo.OutgoingCTLV,
-					Amount:      fwdInfo.AmountToForward,
-					PaymentHash: pd.RHash,
+					Expiry:        fwdInfo.OutgoingCTLV,
+					Amount:        fwdInfo.AmountToForward,
+					PaymentHash:   pd.RHash,
+					BlindingPoint: fwdInfo.NextBlinding,
 				}
 
 				// Finally, we'll encode the onion packet for
@@ -2763,6 +2877,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					incomingTimeout: pd.Timeout,
 					outgoingTimeout: fwdInfo.OutgoingCTLV,
 					customRecords:   pld.CustomRecords(),
+					inboundFee:      inboundFee,
 				}
 				switchPackets = append(
 					switchPackets, updatePacket,
@@ -2778,9 +2893,10 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			// create the outgoing HTLC using the parameters as
 			// specified in the forwarding info.
 			addMsg := &lnwire.UpdateAddHTLC{
-				Expiry:      fwdInfo.OutgoingCTLV,
-				Amount:      fwdInfo.AmountToForward,
-				PaymentHash: pd.RHash,
+				Expiry:        fwdInfo.OutgoingCTLV,
+				Amount:        fwdInfo.AmountToForward,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: fwdInfo.NextBlinding,
 			}
 
 			// Finally, we'll encode the onion packet for the
@@ -2827,6 +2943,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					incomingTimeout: pd.Timeout,
 					outgoingTimeout: fwdInfo.OutgoingCTLV,
 					customRecords:   pld.CustomRecords(),
+					inboundFee:      inboundFee,
 				}
 
 				fwdPkg.FwdFilter.Set(idx)
@@ -2865,7 +2982,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 // returns a boolean indicating whether the commitment tx needs an update.
 func (l *channelLink) processExitHop(pd *lnwallet.PaymentDescriptor,
 	obfuscator hop.ErrorEncrypter, fwdInfo hop.ForwardingInfo,
-	heightNow uint32, payload invoices.Payload) error {
+	heightNow uint32, payload *hop.Payload) error {
 
 	// If hodl.ExitSettle is requested, we will not validate the final hop's
 	// ADD, nor will we settle the corresponding invoice or respond with the
@@ -2878,8 +2995,12 @@ func (l *channelLink) processExitHop(pd *lnwallet.PaymentDescriptor,
 
 	// As we're the exit hop, we'll double check the hop-payload included in
 	// the HTLC to ensure that it was crafted correctly by the sender and
-	// matches the HTLC we were extended.
-	if pd.Amount != fwdInfo.AmountToForward {
+	// matches the HTLC we were extended. The introduction node of a
+	// blinded route rounds up the amount it forwards, so we may receive
+	// slightly more than the sender intended in that case.
+	isBlinded := payload.EncryptedData != nil
+	if (!isBlinded && pd.Amount != fwdInfo.AmountToForward) ||
+		pd.Amount < fwdInfo.AmountToForward {
 
 		l.log.Errorf("onion payload of incoming htlc(%x) has incorrect "+
 			"value: expected %v, got %v", pd.RHash,
@@ -2894,8 +3015,12 @@ func (l *channelLink) processExitHop(pd *lnwallet.PaymentDescriptor,
 	}
 
 	// We'll also ensure that our time-lock value has been computed
-	// correctly.
-	if pd.Timeout != fwdInfo.OutgoingCTLV {
+	// correctly. The sender of a payment to a blinded route only knows
+	// the aggregated cltv delta of the route, so the time-lock may exceed
+	// the one in the payload in that case.
+	if (!isBlinded && pd.Timeout != fwdInfo.OutgoingCTLV) ||
+		pd.Timeout < fwdInfo.OutgoingCTLV {
+
 		l.log.Errorf("onion payload of incoming htlc(%x) has incorrect "+
 			"time-lock: expected %v, got %v",
 			pd.RHash[:], pd.Timeout, fwdInfo.OutgoingCTLV)
@@ -2918,10 +3043,39 @@ func (l *channelLink) processExitHop(pd *lnwallet.PaymentDescriptor,
 		HtlcID: pd.HtlcIndex,
 	}
 
-	event, err := l.cfg.Registry.NotifyExitHopHtlc(
-		invoiceHash, pd.Amount, pd.Timeout, int32(heightNow),
-		circuitKey, l.hodlQueue.ChanIn(), payload,
+	var (
+		event invoices.HtlcResolution
+		err   error
 	)
+	switch {
+	// Htlcs that carry a trampoline onion are relayed if we act as a
+	// trampoline node, and rejected otherwise.
+	case payload.TrampolineOnion != nil && l.cfg.TrampolineRelay == nil:
+		l.log.Errorf("rejecting trampoline htlc(%x), trampoline "+
+			"relay disabled", pd.RHash[:])
+
+		failure := NewLinkError(
+			lnwire.NewInvalidOnionPayload(
+				uint64(record.TrampolineOnionType), 0,
+			),
+		)
+		l.sendHTLCError(pd, failure, obfuscator, true)
+
+		return nil
+
+	case payload.TrampolineOnion != nil:
+		event, err = l.cfg.TrampolineRelay.RelayHtlc(
+			invoiceHash, pd.Amount, pd.Timeout, int32(heightNow),
+			circuitKey, l.hodlQueue.ChanIn(),
+			payload.TrampolineOnion, payload.MultiPath(),
+		)
+
+	default:
+		event, err = l.cfg.Registry.NotifyExitHopHtlc(
+			invoiceHash, pd.Amount, pd.Timeout, int32(heightNow),
+			circuitKey, l.hodlQueue.ChanIn(), payload,
+		)
+	}
 	if err != nil {
 		return err
 	}
@@ -3015,23 +3169,35 @@ func (l *channelLink) forwardBatch(packets ...*htlcPacket) {
 func (l *channelLink) sendHTLCError(pd *lnwallet.PaymentDescriptor,
 	failure *LinkError, e hop.ErrorEncrypter, isReceive bool) {
 
-	reason, err := e.EncryptFirstHop(failure.WireMessage())
-	if err != nil {
-		l.log.Errorf("unable to obfuscate error: %v", err)
-		return
-	}
+	// HTLCs received inside of a blinded route are always failed with an
+	// invalid blinding error, which is sent as a malformed HTLC error.
+	// This way the sender can't learn anything about the blinded route.
+	if pd.BlindingPoint != nil {
+		l.sendMalformedHTLCError(
+			pd.HtlcIndex, lnwire.CodeInvalidBlinding, pd.OnionBlob,
+			pd.SourceRef,
+		)
+	} else {
+		reason, err := e.EncryptFirstHop(failure.WireMessage())
+		if err != nil {
+			l.log.Errorf("unable to obfuscate error: %v", err)
+			return
+		}
 
-	err = l.channel.FailHTLC(pd.HtlcIndex, reason, pd.SourceRef, nil, nil)
-	if err != nil {
-		l.log.Errorf("unable cancel htlc: %v", err)
-		return
-	}
+		err = l.channel.FailHTLC(
+			pd.HtlcIndex, reason, pd.SourceRef, nil, nil,
+		)
+		if err != nil {
+			l.log.Errorf("unable cancel htlc: %v", err)
+			return
+		}
 
-	l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailHTLC{
-		ChanID: l.ChanID(),
-		ID:     pd.HtlcIndex,
-		Reason: reason,
-	})
+		l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailHTLC{
+			ChanID: l.ChanID(),
+			ID:     pd.HtlcIndex,
+			Reason: reason,
+		})
+	}
 
 	// Notify a link failure on our incoming link. Outgoing htlc information
 	// is not available at this point, because we have not decrypted the
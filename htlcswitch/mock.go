@@ -110,6 +110,8 @@ type mockForwardingLog struct {
 	sync.Mutex
 
 	events map[time.Time]channeldb.ForwardingEvent
+
+	failedEvents []channeldb.FailedForwardingEvent
 }
 
 func (m *mockForwardingLog) AddForwardingEvents(events []channeldb.ForwardingEvent) error {
@@ -123,6 +125,32 @@ func (m *mockForwardingLog) AddForwardingEvents(events []channeldb.ForwardingEve
 	return nil
 }
 
+func (m *mockForwardingLog) AddFailedForwardingEvents(
+	events []channeldb.FailedForwardingEvent) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.failedEvents = append(m.failedEvents, events...)
+
+	return nil
+}
+
+func (m *mockForwardingLog) PruneFailedForwardingEvents(before time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+
+	var remaining []channeldb.FailedForwardingEvent
+	for _, event := range m.failedEvents {
+		if !event.Timestamp.Before(before) {
+			remaining = append(remaining, event)
+		}
+	}
+	m.failedEvents = remaining
+
+	return nil
+}
+
 type mockServer struct {
 	started  int32 // To be used atomically.
 	shutdown int32 // To be used atomically.
@@ -722,7 +750,7 @@ func (f *mockChannelLink) HandleChannelUpdate(lnwire.Message) {
 func (f *mockChannelLink) UpdateForwardingPolicy(_ ForwardingPolicy) {
 }
 func (f *mockChannelLink) CheckHtlcForward([32]byte, lnwire.MilliSatoshi,
-	lnwire.MilliSatoshi, uint32, uint32, uint32) *LinkError {
+	lnwire.MilliSatoshi, uint32, uint32, lnwire.Fee, uint32) *LinkError {
 
 	return f.checkHtlcForwardResult
 }
@@ -43,6 +43,10 @@ type htlcPacket struct {
 	// amount is the value of the HTLC that is being created or modified.
 	amount lnwire.MilliSatoshi
 
+	// inboundFee is the inbound fee of the incoming link that must be paid
+	// on top of the fee of the outgoing link when forwarding the HTLC.
+	inboundFee lnwire.Fee
+
 	// htlc lnwire message type of which depends on switch request type.
 	htlc lnwire.Message
 
@@ -30,6 +30,10 @@ const (
 	// pending forwarding events to disk.
 	DefaultFwdEventInterval = 15 * time.Second
 
+	// DefaultFailedFwdRetention is the default duration that failed
+	// forwarding events are kept in the forwarding log.
+	DefaultFailedFwdRetention = 30 * 24 * time.Hour
+
 	// DefaultLogInterval is the duration between attempts to log statistics
 	// about forwarding events.
 	DefaultLogInterval = 10 * time.Second
@@ -127,9 +131,14 @@ type Config struct {
 	// FwdingLog is an interface that will be used by the switch to log
 	// forwarding events. A forwarding event happens each time a payment
 	// circuit is successfully completed. So when we forward an HTLC, and a
-	// settle is eventually received.
+	// settle is eventually received. Forwards that are failed back are
+	// logged as failed forwarding events.
 	FwdingLog ForwardingLog
 
+	// FailedFwdRetention is the time that failed forwarding events are
+	// kept in the forwarding log. If zero, they are never pruned.
+	FailedFwdRetention time.Duration
+
 	// LocalChannelClose kicks-off the workflow to execute a cooperative or
 	// forced unilateral closure of the channel initiated by a local
 	// subsystem.
@@ -192,6 +201,17 @@ type Config struct {
 	// will expiry this long after the Adds are added to a mailbox via
 	// AddPacket.
 	HTLCExpiry time.Duration
+
+	// FindBaseSCID resolves an alias, or the confirmed short channel ID of
+	// a zero-conf channel, to the short channel ID the channel's link is
+	// indexed by. This is optional and may be nil.
+	FindBaseSCID func(lnwire.ShortChannelID) (lnwire.ShortChannelID, error)
+
+	// CircuitBreakerTicker is a signal instructing the htlcswitch to retry
+	// the forwards that are queued by the circuit breaker. This is
+	// optional, a ticker with DefaultCircuitBreakerInterval is used if it
+	// is nil.
+	CircuitBreakerTicker ticker.Ticker
 }
 
 // Switch is the central messaging bus for all incoming/outgoing HTLCs.
@@ -280,6 +300,11 @@ type Switch struct {
 	fwdEventMtx         sync.Mutex
 	pendingFwdingEvents []channeldb.ForwardingEvent
 
+	// pendingFailedFwdingEvents is the set of failed forwarding events
+	// which have been collected during the current interval, but haven't
+	// yet been written to the forwarding log.
+	pendingFailedFwdingEvents []channeldb.FailedForwardingEvent
+
 	// blockEpochStream is an active block epoch event stream backed by an
 	// active ChainNotifier instance. This will be used to retrieve the
 	// lastest height of the chain.
@@ -289,6 +314,9 @@ type Switch struct {
 	// ack in the forwarding package of the outgoing link. This was added to
 	// make pipelining settles more efficient.
 	pendingSettleFails []channeldb.SettleFailRef
+
+	// circuitBreaker limits the forwards of each incoming peer.
+	circuitBreaker *CircuitBreaker
 }
 
 // New creates the new instance of htlc switch.
@@ -316,6 +344,19 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 		quit:              make(chan struct{}),
 	}
 
+	s.circuitBreaker, err = NewCircuitBreaker(
+		cfg.DB, cfg.Clock, cfg.HTLCExpiry,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cfg.CircuitBreakerTicker == nil {
+		s.cfg.CircuitBreakerTicker = ticker.New(
+			DefaultCircuitBreakerInterval,
+		)
+	}
+
 	s.mailOrchestrator = newMailOrchestrator(&mailOrchConfig{
 		fetchUpdate:    s.cfg.FetchLastChannelUpdate,
 		forwardPackets: s.ForwardPackets,
@@ -998,6 +1039,21 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return s.failAddPacket(packet, linkErr)
 		}
 
+		// Apply the circuit breaker limits of the incoming peer. A
+		// queued forward is handled again once it can proceed.
+		switch s.checkCircuitBreaker(packet) {
+		case breakerQueue:
+			log.Debugf("Queued forward of htlc(%x) from %v",
+				htlc.PaymentHash[:], packet.incomingChanID)
+
+			return nil
+
+		case breakerReject:
+			return s.failAddPacket(
+				packet, s.circuitBreakerFailure(packet),
+			)
+		}
+
 		s.indexMtx.RLock()
 		targetLink, err := s.getLinkByShortID(packet.outgoingChanID)
 		if err != nil {
@@ -1019,6 +1075,11 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 		interfaceLinks, _ := s.getLinks(targetPeerKey)
 		s.indexMtx.RUnlock()
 
+		// The sender may have used an alias of the target link, so
+		// we'll refer to the link by the short channel ID it is
+		// indexed by from here on.
+		packet.outgoingChanID = targetLink.ShortChanID()
+
 		// We'll keep track of any HTLC failures during the link
 		// selection process. This way we can return the error for
 		// precise link that the sender selected, while optimistically
@@ -1046,7 +1107,8 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				failure = link.CheckHtlcForward(
 					htlc.PaymentHash, packet.incomingAmount,
 					packet.amount, packet.incomingTimeout,
-					packet.outgoingTimeout, currentHeight,
+					packet.outgoingTimeout, packet.inboundFee,
+					currentHeight,
 				)
 			}
 
@@ -1116,6 +1178,11 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return nil
 		}
 
+		// The htlc is no longer in flight on the outgoing channel, so
+		// it no longer counts towards the circuit breaker limits of
+		// the incoming peer.
+		s.circuitBreaker.release(circuit.Incoming)
+
 		fail, isFail := htlc.(*lnwire.UpdateFailHTLC)
 		if isFail && !packet.hasSource {
 			switch {
@@ -1159,7 +1226,38 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 					fail.Reason,
 				)
 			}
-		} else if !isFail && circuit.Outgoing != nil {
+		}
+
+		// If this is a failed forward, we'll log a failed forwarding
+		// event. A resolution message means that the htlc was failed
+		// on chain by our node, and a link failure that the outgoing
+		// link failed it. Otherwise, the htlc was failed downstream.
+		if isFail && circuit.ErrorEncrypter != nil {
+			linkFailure := packet.linkFailure
+			if packet.isResolution {
+				linkFailure = NewLinkError(
+					&lnwire.FailPermanentChannelFailure{},
+				)
+			}
+
+			if linkFailure != nil {
+				s.logLinkFailure(
+					circuit.Incoming.ChanID,
+					packet.outgoingChanID,
+					circuit.IncomingAmount,
+					circuit.OutgoingAmount, linkFailure,
+				)
+			} else {
+				s.logFailedForward(
+					circuit.Incoming.ChanID,
+					packet.outgoingChanID,
+					circuit.IncomingAmount,
+					circuit.OutgoingAmount, true, 0, "",
+				)
+			}
+		}
+
+		if !isFail && circuit.Outgoing != nil {
 			// If this is an HTLC settle, and it wasn't from a
 			// locally initiated HTLC, then we'll log a forwarding
 			// event so we can flush it to disk later.
@@ -1236,10 +1334,71 @@ func checkCircularForward(incoming, outgoing lnwire.ShortChannelID,
 	)
 }
 
+// checkCircuitBreaker applies the circuit breaker limits of the incoming peer
+// to a forward. Forwards from links that aren't known to the switch are
+// admitted.
+func (s *Switch) checkCircuitBreaker(packet *htlcPacket) breakerDecision {
+	s.indexMtx.RLock()
+	incomingLink, err := s.getLinkByShortID(packet.incomingChanID)
+	s.indexMtx.RUnlock()
+	if err != nil {
+		return breakerAdmit
+	}
+
+	return s.circuitBreaker.admit(incomingLink.Peer().PubKey(), packet)
+}
+
+// circuitBreakerFailure returns the link error for forwards that are failed by
+// the circuit breaker.
+func (s *Switch) circuitBreakerFailure(packet *htlcPacket) *LinkError {
+	var failure lnwire.FailureMessage
+	update, err := s.cfg.FetchLastChannelUpdate(packet.outgoingChanID)
+	if err != nil {
+		failure = &lnwire.FailTemporaryNodeFailure{}
+	} else {
+		failure = lnwire.NewTemporaryChannelFailure(update)
+	}
+
+	return NewDetailedLinkError(failure, OutgoingFailureCircuitBreaker)
+}
+
+// handleQueuedForwards forwards the queued forwards that can proceed and fails
+// the ones that expired.
+func (s *Switch) handleQueuedForwards() {
+	admitted, expired := s.circuitBreaker.dequeue()
+
+	for _, packet := range admitted {
+		if err := s.handlePacketForward(packet); err != nil {
+			log.Debugf("Unable to forward queued htlc: %v", err)
+		}
+	}
+
+	for _, packet := range expired {
+		// We don't handle the error here since this method always
+		// returns an error.
+		_ = s.failAddPacket(packet, s.circuitBreakerFailure(packet))
+	}
+}
+
+// CircuitBreaker returns the circuit breaker that limits the forwards of each
+// incoming peer.
+func (s *Switch) CircuitBreaker() *CircuitBreaker {
+	return s.circuitBreaker
+}
+
 // failAddPacket encrypts a fail packet back to an add packet's source.
 // The ciphertext will be derived from the failure message proivded by context.
 // This method returns the failErr if all other steps complete successfully.
 func (s *Switch) failAddPacket(packet *htlcPacket, failure *LinkError) error {
+	// The htlc won't be forwarded, so it no longer counts towards the
+	// circuit breaker limits of the incoming peer.
+	s.circuitBreaker.release(packet.inKey())
+
+	s.logLinkFailure(
+		packet.incomingChanID, packet.outgoingChanID,
+		packet.incomingAmount, packet.amount, failure,
+	)
+
 	// Encrypt the failure so that the sender will be able to read the error
 	// message. Since we failed this packet, we use EncryptFirstHop to
 	// obfuscate the failure for their eyes only.
@@ -1286,6 +1445,47 @@ func (s *Switch) failAddPacket(packet *htlcPacket, failure *LinkError) error {
 	return failure
 }
 
+// logLinkFailure logs a forward that our node failed with the given link
+// error.
+func (s *Switch) logLinkFailure(incoming, outgoing lnwire.ShortChannelID,
+	amtIn, amtOut lnwire.MilliSatoshi, linkFailure *LinkError) {
+
+	var detail string
+	if linkFailure.FailureDetail != nil {
+		detail = linkFailure.FailureString()
+	}
+
+	s.logFailedForward(
+		incoming, outgoing, amtIn, amtOut, false,
+		linkFailure.WireMessage().Code(), detail,
+	)
+}
+
+// logFailedForward adds a failed forward to the set of failed forwarding events
+// that are flushed to the forwarding log. The failure code and detail are only
+// known for forwards that weren't failed further down the route.
+func (s *Switch) logFailedForward(incoming, outgoing lnwire.ShortChannelID,
+	amtIn, amtOut lnwire.MilliSatoshi, downstream bool,
+	code lnwire.FailCode, detail string) {
+
+	event := channeldb.FailedForwardingEvent{
+		Timestamp:      time.Now(),
+		IncomingChanID: incoming,
+		OutgoingChanID: outgoing,
+		AmtIn:          amtIn,
+		AmtOut:         amtOut,
+		Downstream:     downstream,
+		FailureCode:    code,
+		FailureDetail:  detail,
+	}
+
+	s.fwdEventMtx.Lock()
+	s.pendingFailedFwdingEvents = append(
+		s.pendingFailedFwdingEvents, event,
+	)
+	s.fwdEventMtx.Unlock()
+}
+
 // closeCircuit accepts a settle or fail htlc and the associated htlc packet and
 // attempts to determine the source that forwarded this htlc. This method will
 // set the incoming chan and htlc ID of the given packet if the source was
@@ -1557,6 +1757,8 @@ func (s *Switch) htlcForwarder() {
 
 	defer s.cfg.AckEventTicker.Stop()
 
+	defer s.cfg.CircuitBreakerTicker.Stop()
+
 out:
 	for {
 
@@ -1566,6 +1768,12 @@ out:
 			s.cfg.AckEventTicker.Resume()
 		}
 
+		// If the circuit breaker queued any forwards, reinstate its
+		// ticker so that they are retried.
+		if s.circuitBreaker.hasQueued() {
+			s.cfg.CircuitBreakerTicker.Resume()
+		}
+
 		select {
 		case blockEpoch, ok := <-s.blockEpochStream.Epochs:
 			if !ok {
@@ -1637,6 +1845,22 @@ out:
 		case cmd := <-s.htlcPlex:
 			cmd.err <- s.handlePacketForward(cmd.pkt)
 
+		// The circuit breaker released forwards of a peer with queued
+		// forwards, or its limits changed, so we'll retry the queued
+		// forwards.
+		case <-s.circuitBreaker.queueSignal:
+			s.handleQueuedForwards()
+
+		// The circuit breaker ticker fired, so we'll retry the queued
+		// forwards to account for the refilled rate limits and to fail
+		// the ones that expired.
+		case <-s.cfg.CircuitBreakerTicker.Ticks():
+			s.handleQueuedForwards()
+
+			if !s.circuitBreaker.hasQueued() {
+				s.cfg.CircuitBreakerTicker.Pause()
+			}
+
 		// When this time ticks, then it indicates that we should
 		// collect all the forwarding events since the last internal,
 		// and write them out to our log.
@@ -2048,6 +2272,22 @@ func (s *Switch) getLink(chanID lnwire.ChannelID) (ChannelLink, error) {
 // NOTE: This MUST be called with the indexMtx held.
 func (s *Switch) getLinkByShortID(chanID lnwire.ShortChannelID) (ChannelLink, error) {
 	link, ok := s.forwardingIndex[chanID]
+	if ok {
+		return link, nil
+	}
+
+	// The short channel ID might be an alias of a channel that is indexed
+	// by a different short channel ID, so we'll try to resolve it.
+	if s.cfg.FindBaseSCID == nil {
+		return nil, ErrChannelLinkNotFound
+	}
+
+	baseScid, err := s.cfg.FindBaseSCID(chanID)
+	if err != nil {
+		return nil, ErrChannelLinkNotFound
+	}
+
+	link, ok = s.forwardingIndex[baseScid]
 	if !ok {
 		return nil, ErrChannelLinkNotFound
 	}
@@ -2228,24 +2468,47 @@ func (s *Switch) FlushForwardingEvents() error {
 	// events.
 	s.fwdEventMtx.Lock()
 
-	// If we won't have any forwarding events, then we can exit early.
-	if len(s.pendingFwdingEvents) == 0 {
-		s.fwdEventMtx.Unlock()
-		return nil
-	}
-
 	events := make([]channeldb.ForwardingEvent, len(s.pendingFwdingEvents))
 	copy(events[:], s.pendingFwdingEvents[:])
 
+	failedEvents := make(
+		[]channeldb.FailedForwardingEvent,
+		len(s.pendingFailedFwdingEvents),
+	)
+	copy(failedEvents[:], s.pendingFailedFwdingEvents[:])
+
 	// With the copy obtained, we can now clear out the header pointer of
 	// the current slice. This way, we can re-use the underlying storage
 	// allocated for the slice.
 	s.pendingFwdingEvents = s.pendingFwdingEvents[:0]
+	s.pendingFailedFwdingEvents = s.pendingFailedFwdingEvents[:0]
 	s.fwdEventMtx.Unlock()
 
-	// Finally, we'll write out the copied events to the persistent
+	// Next, we'll write out the copied events to the persistent
 	// forwarding log.
-	return s.cfg.FwdingLog.AddForwardingEvents(events)
+	if len(events) > 0 {
+		err := s.cfg.FwdingLog.AddForwardingEvents(events)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(failedEvents) > 0 {
+		err := s.cfg.FwdingLog.AddFailedForwardingEvents(failedEvents)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Finally, we'll remove the failed forwarding events that exceeded
+	// the retention time.
+	if s.cfg.FailedFwdRetention == 0 {
+		return nil
+	}
+
+	return s.cfg.FwdingLog.PruneFailedForwardingEvents(
+		time.Now().Add(-s.cfg.FailedFwdRetention),
+	)
 }
 
 // BestHeight returns the best height known to the switch.
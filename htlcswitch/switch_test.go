@@ -1,6 +1,7 @@
 package htlcswitch
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"fmt"
@@ -10,10 +11,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/ticker"
@@ -284,6 +291,178 @@ func TestSwitchSendPending(t *testing.T) {
 	}
 }
 
+// TestSwitchCircuitBreaker checks that the switch queues and fails forwards
+// that exceed the circuit breaker limits of the incoming peer, and that queued
+// forwards proceed once an in-flight forward of the peer is settled.
+func TestSwitchCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to create alice server: %v", err)
+	}
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to create bob server: %v", err)
+	}
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	if err != nil {
+		t.Fatalf("unable to init switch: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// Allow a single htlc from alice in flight, and queue the others.
+	err = s.CircuitBreaker().SetPeerLimits(
+		alicePeer.PubKey(), CircuitBreakerLimits{
+			MaxPendingHtlcs: 1,
+			Mode:            CircuitBreakerModeQueue,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preimage, err := genPreimage()
+	if err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+	rhash := sha256.Sum256(preimage[:])
+
+	newAdd := func(htlcID uint64) *htlcPacket {
+		return &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: htlcID,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+		}
+	}
+
+	// The first forward is forwarded to bob.
+	packet := newAdd(0)
+	if err := s.ForwardPackets(nil, packet); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-bobChannelLink.packets:
+		if err := bobChannelLink.completeCircuit(packet); err != nil {
+			t.Fatalf("unable to complete payment circuit: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	// The second forward is queued.
+	queued := newAdd(1)
+	if err := s.ForwardPackets(nil, queued); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-bobChannelLink.packets:
+		t.Fatal("queued request was propagated to destination")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	status := s.CircuitBreaker().Status()[alicePeer.PubKey()]
+	if status.PendingHtlcs != 1 || status.QueuedHtlcs != 1 {
+		t.Fatalf("unexpected circuit breaker counters: %+v",
+			status.CircuitBreakerCounters)
+	}
+
+	// Settle the first forward, which lets the queued forward proceed.
+	settle := &htlcPacket{
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		outgoingHTLCID: 0,
+		amount:         1,
+		htlc: &lnwire.UpdateFulfillHTLC{
+			PaymentPreimage: preimage,
+		},
+	}
+	if err := s.ForwardPackets(nil, settle); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		if err := aliceChannelLink.deleteCircuit(pkt); err != nil {
+			t.Fatalf("unable to remove circuit: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("settle was not propagated to source")
+	}
+
+	select {
+	case <-bobChannelLink.packets:
+		if err := bobChannelLink.completeCircuit(queued); err != nil {
+			t.Fatalf("unable to complete payment circuit: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued request was not propagated to destination")
+	}
+
+	// In fail mode, a forward that exceeds the limits is failed back to
+	// alice.
+	err = s.CircuitBreaker().SetPeerLimits(
+		alicePeer.PubKey(), CircuitBreakerLimits{
+			MaxPendingHtlcs: 1,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ForwardPackets(nil, newAdd(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		if _, ok := pkt.htlc.(*lnwire.UpdateFailHTLC); !ok {
+			t.Fatalf("expected fail, got %T", pkt.htlc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("fail was not propagated to source")
+	}
+
+	status = s.CircuitBreaker().Status()[alicePeer.PubKey()]
+	expected := CircuitBreakerCounters{
+		PendingHtlcs:   1,
+		ForwardedHtlcs: 2,
+		RejectedHtlcs:  1,
+	}
+	if status.CircuitBreakerCounters != expected {
+		t.Fatalf("expected counters %+v, got %+v", expected,
+			status.CircuitBreakerCounters)
+	}
+}
+
 // TestSwitchForward checks the ability of htlc switch to forward add/settle
 // requests.
 func TestSwitchForward(t *testing.T) {
@@ -397,6 +576,165 @@ func TestSwitchForward(t *testing.T) {
 	}
 }
 
+// TestSwitchLogFailedForwards checks that the switch logs forwards that are
+// failed by the switch itself as well as forwards that are failed further
+// down the route.
+func TestSwitchLogFailedForwards(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to create alice server: %v", err)
+	}
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to create bob server: %v", err)
+	}
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	if err != nil {
+		t.Fatalf("unable to init switch: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	preimage, err := genPreimage()
+	if err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+	rhash := sha256.Sum256(preimage[:])
+
+	// First, we'll forward an htlc to an unknown channel, which the switch
+	// fails back to Alice right away.
+	unknownChanID := lnwire.NewShortChanIDFromInt(100)
+	packet := &htlcPacket{
+		incomingChanID: aliceChannelLink.ShortChanID(),
+		incomingHTLCID: 0,
+		outgoingChanID: unknownChanID,
+		incomingAmount: 2,
+		amount:         1,
+		obfuscator:     NewMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+	}
+	if err := s.ForwardPackets(nil, packet); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-aliceChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("failure was not propagated to alice")
+	}
+
+	// Next, we'll forward an htlc to Bob, which is failed by a node
+	// further down the route.
+	packet = &htlcPacket{
+		incomingChanID: aliceChannelLink.ShortChanID(),
+		incomingHTLCID: 1,
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		incomingAmount: 4,
+		amount:         3,
+		obfuscator:     NewMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      3,
+		},
+	}
+	if err := s.ForwardPackets(nil, packet); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-bobChannelLink.packets:
+		if err := bobChannelLink.completeCircuit(packet); err != nil {
+			t.Fatalf("unable to complete payment circuit: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	fail := &htlcPacket{
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		outgoingHTLCID: 0,
+		amount:         3,
+		htlc:           &lnwire.UpdateFailHTLC{},
+	}
+	if err := s.ForwardPackets(nil, fail); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		if err := aliceChannelLink.deleteCircuit(pkt); err != nil {
+			t.Fatalf("unable to remove circuit: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("failure was not propagated to alice")
+	}
+
+	// With both forwards failed, flushing the switch's forwarding events
+	// should add both of them to the failed forwarding log.
+	if err := s.FlushForwardingEvents(); err != nil {
+		t.Fatalf("unable to flush forwarding events: %v", err)
+	}
+
+	log, ok := s.cfg.FwdingLog.(*mockForwardingLog)
+	if !ok {
+		t.Fatalf("mockForwardingLog assertion failed")
+	}
+	log.Lock()
+	defer log.Unlock()
+
+	if len(log.failedEvents) != 2 {
+		t.Fatalf("expected 2 failed events, got: %v",
+			spew.Sdump(log.failedEvents))
+	}
+
+	localFail := log.failedEvents[0]
+	if localFail.IncomingChanID != aliceChanID ||
+		localFail.OutgoingChanID != unknownChanID ||
+		localFail.AmtIn != 2 || localFail.AmtOut != 1 ||
+		localFail.Downstream ||
+		localFail.FailureCode != lnwire.CodeUnknownNextPeer {
+
+		t.Fatalf("unexpected local failure: %v", spew.Sdump(localFail))
+	}
+
+	downstreamFail := log.failedEvents[1]
+	if downstreamFail.IncomingChanID != aliceChanID ||
+		downstreamFail.OutgoingChanID != bobChanID ||
+		downstreamFail.AmtIn != 4 || downstreamFail.AmtOut != 3 ||
+		!downstreamFail.Downstream || downstreamFail.FailureCode != 0 {
+
+		t.Fatalf("unexpected downstream failure: %v",
+			spew.Sdump(downstreamFail))
+	}
+}
+
 func TestSwitchForwardFailAfterFullAdd(t *testing.T) {
 	t.Parallel()
 
@@ -2500,20 +2838,217 @@ func TestUpdateFailMalformedHTLCErrorConversion(t *testing.T) {
 	})
 }
 
-// TestSwitchGetPaymentResult tests that the switch interacts as expected with
-// the circuit map and network result store when looking up the result of a
-// payment ID. This is important for not to lose results under concurrent
-// lookup and receiving results.
-func TestSwitchGetPaymentResult(t *testing.T) {
+// TestSwitchIntroductionNodeFailures tests that failures sent back through
+// the introduction node of a blinded route are replaced by an invalid
+// blinding failure, for both downstream and malformed failures.
+func TestSwitchIntroductionNodeFailures(t *testing.T) {
 	t.Parallel()
 
-	const paymentID = 123
-	var preimg lntypes.Preimage
-	preimg[0] = 3
-
-	s, err := initSwitchWithDB(testStartingHeight, nil)
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
 	if err != nil {
-		t.Fatalf("unable to init switch: %v", err)
+		t.Fatalf("unable to create alice server: %v", err)
+	}
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to create bob server: %v", err)
+	}
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	if err != nil {
+		t.Fatalf("unable to init switch: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// We act as the introduction node, so the error encrypter of the
+	// incoming htlc is derived from our node key and the sender's session
+	// key.
+	nodeKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate node key: %v", err)
+	}
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate session key: %v", err)
+	}
+	router := sphinx.NewRouter(
+		&keychain.PrivKeyECDH{PrivKey: nodeKey},
+		&chaincfg.SimNetParams, sphinx.NewMemoryReplayLog(),
+	)
+	onionEncrypter, err := sphinx.NewOnionErrorEncrypter(
+		router, sessionKey.PubKey(),
+	)
+	if err != nil {
+		t.Fatalf("unable to create onion error encrypter: %v", err)
+	}
+
+	var onionSHA256 [sha256.Size]byte
+	if _, err := rand.Read(onionSHA256[:]); err != nil {
+		t.Fatalf("unable to generate onion hash: %v", err)
+	}
+
+	obfuscator := &hop.IntroductionErrorEncrypter{
+		SphinxErrorEncrypter: &hop.SphinxErrorEncrypter{
+			OnionErrorEncrypter: onionEncrypter,
+			EphemeralKey:        sessionKey.PubKey(),
+		},
+		OnionSHA256: onionSHA256,
+	}
+
+	// The sender decrypts the failures with the shared secret of our
+	// node.
+	decrypter := sphinx.NewOnionErrorDecrypter(&sphinx.Circuit{
+		SessionKey:  sessionKey,
+		PaymentPath: []*btcec.PublicKey{nodeKey.PubKey()},
+	})
+
+	var malformedReason bytes.Buffer
+	err = lnwire.EncodeFailure(
+		&malformedReason, &lnwire.FailInvalidOnionKey{}, 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to encode failure: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		reason         lnwire.OpaqueReason
+		convertedError bool
+	}{
+		{
+			name:   "downstream failure",
+			reason: bytes.Repeat([]byte{1}, 292),
+		},
+		{
+			name:           "malformed failure",
+			reason:         malformedReason.Bytes(),
+			convertedError: true,
+		},
+	}
+
+	for i, test := range tests {
+		// Forward an htlc from Alice to Bob, which we received as the
+		// introduction node of a blinded route.
+		packet := &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: uint64(i),
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     obfuscator,
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: onionSHA256,
+				Amount:      1,
+			},
+		}
+		if err := s.ForwardPackets(nil, packet); err != nil {
+			t.Fatalf("%v: unable to forward htlc: %v", test.name,
+				err)
+		}
+
+		select {
+		case pkt := <-bobChannelLink.packets:
+			err := bobChannelLink.completeCircuit(pkt)
+			if err != nil {
+				t.Fatalf("%v: unable to complete payment "+
+					"circuit: %v", test.name, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%v: request was not propagated to "+
+				"destination", test.name)
+		}
+
+		// Fail the htlc back from Bob's side of the blinded route.
+		packet = &htlcPacket{
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			outgoingHTLCID: uint64(i),
+			amount:         1,
+			convertedError: test.convertedError,
+			htlc: &lnwire.UpdateFailHTLC{
+				Reason: test.reason,
+			},
+		}
+		if err := s.ForwardPackets(nil, packet); err != nil {
+			t.Fatalf("%v: unable to forward failure: %v",
+				test.name, err)
+		}
+
+		var fail *lnwire.UpdateFailHTLC
+		select {
+		case pkt := <-aliceChannelLink.packets:
+			err := aliceChannelLink.deleteCircuit(pkt)
+			if err != nil {
+				t.Fatalf("%v: unable to remove circuit: %v",
+					test.name, err)
+			}
+			fail = pkt.htlc.(*lnwire.UpdateFailHTLC)
+		case <-time.After(time.Second):
+			t.Fatalf("%v: failure was not propagated to source",
+				test.name)
+		}
+
+		// The sender must only learn that the blinded route failed at
+		// the introduction node.
+		decrypted, err := decrypter.DecryptError(fail.Reason)
+		if err != nil {
+			t.Fatalf("%v: unable to decrypt failure: %v",
+				test.name, err)
+		}
+		if !decrypted.Sender.IsEqual(nodeKey.PubKey()) {
+			t.Fatalf("%v: failure not attributed to the "+
+				"introduction node", test.name)
+		}
+
+		failure, err := lnwire.DecodeFailure(
+			bytes.NewReader(decrypted.Message), 0,
+		)
+		if err != nil {
+			t.Fatalf("%v: unable to decode failure: %v",
+				test.name, err)
+		}
+		expected := &lnwire.FailInvalidBlinding{
+			OnionSHA256: onionSHA256,
+		}
+		if !reflect.DeepEqual(failure, expected) {
+			t.Fatalf("%v: expected failure %v, got %v", test.name,
+				spew.Sdump(expected), spew.Sdump(failure))
+		}
+	}
+}
+
+// TestSwitchGetPaymentResult tests that the switch interacts as expected with
+// the circuit map and network result store when looking up the result of a
+// payment ID. This is important for not to lose results under concurrent
+// lookup and receiving results.
+func TestSwitchGetPaymentResult(t *testing.T) {
+	t.Parallel()
+
+	const paymentID = 123
+	var preimg lntypes.Preimage
+	preimg[0] = 3
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	if err != nil {
+		t.Fatalf("unable to init switch: %v", err)
 	}
 	if err := s.Start(); err != nil {
 		t.Fatalf("unable to start switch: %v", err)
@@ -3177,8 +3712,10 @@ func assertOutgoingLinkReceive(t *testing.T, targetLink *mockChannelLink,
 	}
 }
 
-func TestSwitchHoldForward(t *testing.T) {
-	t.Parallel()
+// newHoldForwardTestSwitch creates a started switch with a link to alice and
+// bob for the interceptor tests. The returned function stops the switch.
+func newHoldForwardTestSwitch(t *testing.T) (*Switch, *mockChannelLink,
+	*mockChannelLink, func()) {
 
 	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
 
@@ -3213,11 +3750,11 @@ func TestSwitchHoldForward(t *testing.T) {
 		t.Fatalf("unable to start switch: %v", err)
 	}
 
-	defer func() {
+	cleanUp := func() {
 		if err := s.Stop(); err != nil {
 			t.Fatalf(err.Error())
 		}
-	}()
+	}
 
 	aliceChannelLink := newMockChannelLink(
 		s, chanID1, aliceChanID, alicePeer, true,
@@ -3232,15 +3769,61 @@ func TestSwitchHoldForward(t *testing.T) {
 		t.Fatalf("unable to add bob link: %v", err)
 	}
 
+	return s, aliceChannelLink, bobChannelLink, cleanUp
+}
+
+// testHoldForwardExpiry is the incoming expiry of the forwards used in the
+// interceptor tests, which is far enough in the future to not be failed back.
+const testHoldForwardExpiry = testStartingHeight + 100
+
+// newTestInterceptableSwitch creates a started InterceptableSwitch with a cltv
+// reject delta of testDefaultDelta. The returned channel delivers blocks to
+// it, and the returned function stops it.
+func newTestInterceptableSwitch(t *testing.T, s *Switch,
+	requireInterceptor bool) (*InterceptableSwitch,
+	chan *chainntnfs.BlockEpoch, func()) {
+
+	epochChan := make(chan *chainntnfs.BlockEpoch)
+	interceptableSwitch := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch: s,
+			Notifier: &mock.ChainNotifier{
+				EpochChan: epochChan,
+			},
+			CltvRejectDelta:    testDefaultDelta,
+			RequireInterceptor: requireInterceptor,
+		},
+	)
+	if err := interceptableSwitch.Start(); err != nil {
+		t.Fatalf("unable to start interceptable switch: %v", err)
+	}
+
+	stop := func() {
+		if err := interceptableSwitch.Stop(); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	return interceptableSwitch, epochChan, stop
+}
+
+func TestSwitchHoldForward(t *testing.T) {
+	t.Parallel()
+
+	s, aliceChannelLink, bobChannelLink, cleanUp :=
+		newHoldForwardTestSwitch(t)
+	defer cleanUp()
+
 	// Create request which should be forwarded from Alice channel link to
 	// bob channel link.
 	preimage := [sha256.Size]byte{1}
 	rhash := sha256.Sum256(preimage[:])
 	ogPacket := &htlcPacket{
-		incomingChanID: aliceChannelLink.ShortChanID(),
-		incomingHTLCID: 0,
-		outgoingChanID: bobChannelLink.ShortChanID(),
-		obfuscator:     NewMockObfuscator(),
+		incomingChanID:  aliceChannelLink.ShortChanID(),
+		incomingHTLCID:  0,
+		outgoingChanID:  bobChannelLink.ShortChanID(),
+		incomingTimeout: testHoldForwardExpiry,
+		obfuscator:      NewMockObfuscator(),
 		htlc: &lnwire.UpdateAddHTLC{
 			PaymentHash: rhash,
 			Amount:      1,
@@ -3248,7 +3831,10 @@ func TestSwitchHoldForward(t *testing.T) {
 	}
 
 	forwardInterceptor := &mockForwardInterceptor{}
-	switchForwardInterceptor := NewInterceptableSwitch(s)
+	switchForwardInterceptor, _, stop := newTestInterceptableSwitch(
+		t, s, false,
+	)
+	defer stop()
 	switchForwardInterceptor.SetInterceptor(forwardInterceptor.InterceptForwardHtlc)
 	linkQuit := make(chan struct{})
 
@@ -3308,4 +3894,460 @@ func TestSwitchHoldForward(t *testing.T) {
 	assertOutgoingLinkReceive(t, bobChannelLink, false)
 	assertOutgoingLinkReceive(t, aliceChannelLink, true)
 	assertNumCircuits(t, s, 0, 0)
+
+	// A resolved forward can't be resolved again.
+	if err := forwardInterceptor.resume(); err != ErrFwdNotExists {
+		t.Fatalf("expected ErrFwdNotExists, got %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	// Test failing a hold forward with a failure message.
+	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	err := forwardInterceptor.intercepted.FailWithMessage(
+		&lnwire.FailTemporaryNodeFailure{},
+	)
+	if err != nil {
+		t.Fatalf("failed to fail forward: %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+	assertOutgoingLinkReceive(t, aliceChannelLink, true)
+	assertNumCircuits(t, s, 0, 0)
+
+	// Test failing a hold forward with an encrypted failure reason.
+	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	err = forwardInterceptor.intercepted.FailWithReason(nil)
+	if err == nil {
+		t.Fatal("expected empty failure reason to be rejected")
+	}
+	err = forwardInterceptor.intercepted.FailWithReason([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("failed to fail forward: %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+	assertOutgoingLinkReceive(t, aliceChannelLink, true)
+	assertNumCircuits(t, s, 0, 0)
+
+	// Test resuming a hold forward with a modified amount. The outgoing
+	// amount can't exceed the incoming amount.
+	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	outAmt := ogPacket.incomingAmount + 1
+	err = forwardInterceptor.intercepted.ResumeModified(nil, &outAmt)
+	if err == nil {
+		t.Fatal("expected outgoing amount to be rejected")
+	}
+	outAmt = ogPacket.incomingAmount
+	err = forwardInterceptor.intercepted.ResumeModified(nil, &outAmt)
+	if err != nil {
+		t.Fatalf("failed to resume forward: %v", err)
+	}
+
+	select {
+	case packet := <-bobChannelLink.packets:
+		htlc := packet.htlc.(*lnwire.UpdateAddHTLC)
+		if packet.amount != outAmt || htlc.Amount != outAmt {
+			t.Fatalf("expected modified amount %v, got %v",
+				outAmt, htlc.Amount)
+		}
+		if err := bobChannelLink.completeCircuit(packet); err != nil {
+			t.Fatalf("unable to complete payment circuit: %v", err)
+		}
+
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+	assertNumCircuits(t, s, 1, 1)
+
+	// Removing the interceptor resumes the held forwards, because no
+	// interceptor is required.
+	ogPacket.incomingHTLCID = 1
+	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	switchForwardInterceptor.SetInterceptor(nil)
+	assertOutgoingLinkReceive(t, bobChannelLink, true)
+	assertNumCircuits(t, s, 2, 2)
+}
+
+// TestSwitchHoldForwardLogFailures tests that forwards that are failed by the
+// interceptor are logged as failed by our node rather than downstream.
+func TestSwitchHoldForwardLogFailures(t *testing.T) {
+	t.Parallel()
+
+	s, aliceChannelLink, bobChannelLink, cleanUp :=
+		newHoldForwardTestSwitch(t)
+	defer cleanUp()
+
+	preimage := [sha256.Size]byte{1}
+	rhash := sha256.Sum256(preimage[:])
+	ogPacket := &htlcPacket{
+		incomingChanID:  aliceChannelLink.ShortChanID(),
+		incomingHTLCID:  0,
+		outgoingChanID:  bobChannelLink.ShortChanID(),
+		incomingTimeout: testHoldForwardExpiry,
+		obfuscator:      NewMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+	}
+
+	forwardInterceptor := &mockForwardInterceptor{}
+	switchForwardInterceptor, _, stop := newTestInterceptableSwitch(
+		t, s, false,
+	)
+	defer stop()
+	switchForwardInterceptor.SetInterceptor(
+		forwardInterceptor.InterceptForwardHtlc,
+	)
+	linkQuit := make(chan struct{})
+
+	// As the forwards never reach the outgoing link, there is no circuit
+	// to tear down, so we'll only check that the failure is sent back.
+	assertFailReceived := func(t *testing.T, link *mockChannelLink) {
+		t.Helper()
+
+		select {
+		case packet := <-link.packets:
+			if _, ok := packet.htlc.(*lnwire.UpdateFailHTLC); !ok {
+				t.Fatalf("expected fail, got: %T", packet.htlc)
+			}
+			link.mailBox.AckPacket(packet.inKey())
+
+		case <-time.After(time.Second):
+			t.Fatal("failure was not propagated to alice")
+		}
+	}
+
+	// Fail one forward with a failure message and one with an encrypted
+	// failure reason.
+	err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	if err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	err = forwardInterceptor.intercepted.FailWithMessage(
+		&lnwire.FailTemporaryNodeFailure{},
+	)
+	if err != nil {
+		t.Fatalf("failed to fail forward: %v", err)
+	}
+	assertFailReceived(t, aliceChannelLink)
+
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	if err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	err = forwardInterceptor.intercepted.FailWithReason([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("failed to fail forward: %v", err)
+	}
+	assertFailReceived(t, aliceChannelLink)
+
+	if err := s.FlushForwardingEvents(); err != nil {
+		t.Fatalf("unable to flush forwarding events: %v", err)
+	}
+
+	log, ok := s.cfg.FwdingLog.(*mockForwardingLog)
+	if !ok {
+		t.Fatalf("mockForwardingLog assertion failed")
+	}
+	log.Lock()
+	defer log.Unlock()
+
+	if len(log.failedEvents) != 2 {
+		t.Fatalf("expected 2 failed events, got: %v",
+			spew.Sdump(log.failedEvents))
+	}
+
+	// Both forwards were failed by our node. Only the failure code of
+	// the failure message is known.
+	expectedCodes := []lnwire.FailCode{
+		lnwire.CodeTemporaryNodeFailure, 0,
+	}
+	for i, event := range log.failedEvents {
+		if event.Downstream ||
+			event.FailureCode != expectedCodes[i] ||
+			event.FailureDetail != interceptorFailureDetail {
+
+			t.Fatalf("unexpected interceptor failure: %v",
+				spew.Sdump(event))
+		}
+	}
+}
+
+// TestSwitchHoldForwardRequiredInterceptor tests that forwards are held while
+// no interceptor is registered if an interceptor is required, and that they
+// are replayed to the next interceptor.
+func TestSwitchHoldForwardRequiredInterceptor(t *testing.T) {
+	t.Parallel()
+
+	s, aliceChannelLink, bobChannelLink, cleanUp :=
+		newHoldForwardTestSwitch(t)
+	defer cleanUp()
+
+	preimage := [sha256.Size]byte{1}
+	rhash := sha256.Sum256(preimage[:])
+	newPacket := func(htlcID uint64) *htlcPacket {
+		return &htlcPacket{
+			incomingChanID:  aliceChannelLink.ShortChanID(),
+			incomingHTLCID:  htlcID,
+			outgoingChanID:  bobChannelLink.ShortChanID(),
+			incomingTimeout: testHoldForwardExpiry,
+			obfuscator:      NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+		}
+	}
+
+	// The interceptor passes the forwards to the test, which may receive
+	// them from the goroutine that replays held forwards.
+	intercepted := make(chan InterceptedForward, 10)
+	interceptor := func(fwd InterceptedForward) bool {
+		intercepted <- fwd
+		return true
+	}
+	receiveIntercepted := func() InterceptedForward {
+		select {
+		case fwd := <-intercepted:
+			return fwd
+		case <-time.After(time.Second):
+			t.Fatal("forward not intercepted")
+		}
+		return nil
+	}
+
+	switchForwardInterceptor, _, stop := newTestInterceptableSwitch(
+		t, s, true,
+	)
+	defer stop()
+	linkQuit := make(chan struct{})
+
+	// Without an interceptor, the forward is held.
+	err := switchForwardInterceptor.ForwardPackets(linkQuit, newPacket(0))
+	if err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+	assertNumCircuits(t, s, 0, 0)
+
+	// The held forward is replayed once an interceptor is registered.
+	switchForwardInterceptor.SetInterceptor(interceptor)
+	fwd := receiveIntercepted()
+	if fwd.Packet().IncomingCircuit.HtlcID != 0 {
+		t.Fatalf("unexpected forward %v", fwd.Packet().IncomingCircuit)
+	}
+
+	// A new forward is intercepted while the interceptor is registered.
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, newPacket(1))
+	if err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	receiveIntercepted()
+
+	// Removing the interceptor doesn't resume the held forwards.
+	switchForwardInterceptor.SetInterceptor(nil)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	// Both forwards are replayed to the next interceptor, which resolves
+	// them.
+	switchForwardInterceptor.SetInterceptor(interceptor)
+	replayed := make(map[uint64]InterceptedForward)
+	for i := 0; i < 2; i++ {
+		fwd := receiveIntercepted()
+		replayed[fwd.Packet().IncomingCircuit.HtlcID] = fwd
+	}
+	if len(replayed) != 2 {
+		t.Fatalf("expected 2 replayed forwards, got %v", len(replayed))
+	}
+
+	if err := replayed[0].Resume(); err != nil {
+		t.Fatalf("failed to resume forward: %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, true)
+	assertNumCircuits(t, s, 1, 1)
+
+	// The failed forward never had a circuit, so it is delivered to alice
+	// without one.
+	if err := replayed[1].Fail(); err != nil {
+		t.Fatalf("failed to fail forward: %v", err)
+	}
+	select {
+	case packet := <-aliceChannelLink.packets:
+		if _, ok := packet.htlc.(*lnwire.UpdateFailHTLC); !ok {
+			t.Fatalf("expected fail, got %T", packet.htlc)
+		}
+
+	case <-time.After(time.Second):
+		t.Fatal("fail was not propagated to alice")
+	}
+
+	// The forward that was passed to the first interceptor has been
+	// resolved through the replayed one.
+	if err := fwd.Resume(); err != ErrFwdNotExists {
+		t.Fatalf("expected ErrFwdNotExists, got %v", err)
+	}
+}
+
+// TestSwitchHoldForwardNotIntercepted tests that held forwards the interceptor
+// isn't interested in are forwarded, both when they are replayed to a new
+// interceptor and when the interceptor was removed while they were being
+// intercepted.
+func TestSwitchHoldForwardNotIntercepted(t *testing.T) {
+	t.Parallel()
+
+	s, aliceChannelLink, bobChannelLink, cleanUp :=
+		newHoldForwardTestSwitch(t)
+	defer cleanUp()
+
+	preimage := [sha256.Size]byte{1}
+	rhash := sha256.Sum256(preimage[:])
+	newPacket := func(htlcID uint64) *htlcPacket {
+		return &htlcPacket{
+			incomingChanID:  aliceChannelLink.ShortChanID(),
+			incomingHTLCID:  htlcID,
+			outgoingChanID:  bobChannelLink.ShortChanID(),
+			incomingTimeout: testHoldForwardExpiry,
+			obfuscator:      NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+		}
+	}
+
+	switchForwardInterceptor, _, stop := newTestInterceptableSwitch(
+		t, s, false,
+	)
+	defer stop()
+	linkQuit := make(chan struct{})
+
+	// The first interceptor holds the forward.
+	switchForwardInterceptor.SetInterceptor(
+		func(InterceptedForward) bool {
+			return true
+		},
+	)
+	err := switchForwardInterceptor.ForwardPackets(linkQuit, newPacket(0))
+	if err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+	assertNumCircuits(t, s, 0, 0)
+
+	// The held forward is replayed to the next interceptor, which isn't
+	// interested in it, so it is forwarded.
+	switchForwardInterceptor.SetInterceptor(
+		func(InterceptedForward) bool {
+			return false
+		},
+	)
+	assertOutgoingLinkReceive(t, bobChannelLink, true)
+	assertNumCircuits(t, s, 1, 1)
+
+	// If the interceptor is removed after the caller checked for it, the
+	// forward isn't held but handed back to be forwarded.
+	switchForwardInterceptor.SetInterceptor(nil)
+	if switchForwardInterceptor.interceptForward(newPacket(1), linkQuit) {
+		t.Fatal("forward held without an interceptor")
+	}
+	if len(switchForwardInterceptor.heldForwards) != 0 {
+		t.Fatalf("expected no held forwards, got %v",
+			len(switchForwardInterceptor.heldForwards))
+	}
+}
+
+// TestSwitchHoldForwardExpiry tests that held forwards are failed back once
+// they come within the cltv reject delta of their incoming expiry.
+func TestSwitchHoldForwardExpiry(t *testing.T) {
+	t.Parallel()
+
+	s, aliceChannelLink, bobChannelLink, cleanUp :=
+		newHoldForwardTestSwitch(t)
+	defer cleanUp()
+
+	preimage := [sha256.Size]byte{1}
+	rhash := sha256.Sum256(preimage[:])
+	newPacket := func(htlcID uint64, expiry uint32) *htlcPacket {
+		return &htlcPacket{
+			incomingChanID:  aliceChannelLink.ShortChanID(),
+			incomingHTLCID:  htlcID,
+			outgoingChanID:  bobChannelLink.ShortChanID(),
+			incomingTimeout: expiry,
+			obfuscator:      NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+		}
+	}
+
+	assertFailed := func(expectFail bool) {
+		t.Helper()
+
+		select {
+		case packet := <-aliceChannelLink.packets:
+			if !expectFail {
+				t.Fatalf("unexpected packet %T", packet.htlc)
+			}
+			if _, ok := packet.htlc.(*lnwire.UpdateFailHTLC); !ok {
+				t.Fatalf("expected fail, got %T", packet.htlc)
+			}
+
+		case <-time.After(time.Second):
+			if expectFail {
+				t.Fatal("fail was not propagated to alice")
+			}
+		}
+	}
+
+	// No interceptor is registered, so the forwards are held.
+	switchForwardInterceptor, epochChan, stop := newTestInterceptableSwitch(
+		t, s, true,
+	)
+	defer stop()
+	linkQuit := make(chan struct{})
+
+	// A forward that is within the cltv reject delta of its expiry
+	// already is failed right away.
+	err := switchForwardInterceptor.ForwardPackets(
+		linkQuit, newPacket(0, testStartingHeight+testDefaultDelta),
+	)
+	if err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertFailed(true)
+
+	// Otherwise the forward is held until the block at which it comes
+	// within the delta.
+	expiry := uint32(testStartingHeight + testDefaultDelta + 2)
+	err = switchForwardInterceptor.ForwardPackets(
+		linkQuit, newPacket(1, expiry),
+	)
+	if err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertFailed(false)
+
+	epochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight + 1}
+	assertFailed(false)
+
+	epochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight + 2}
+	assertFailed(true)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	// The forward isn't held anymore, so it can't be resolved again.
+	interceptor := func(fwd InterceptedForward) bool {
+		t.Fatalf("unexpected forward %v", fwd.Packet().IncomingCircuit)
+		return true
+	}
+	switchForwardInterceptor.SetInterceptor(interceptor)
 }
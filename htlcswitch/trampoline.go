@@ -0,0 +1,577 @@
+package htlcswitch
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/trampoline"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+// ErrTrampolineExpiryTooSoon is returned when a trampoline payment that we
+// relay expires too soon to be paid.
+var ErrTrampolineExpiryTooSoon = errors.New("trampoline payment expires too " +
+	"soon")
+
+// TrampolinePayment describes a payment that we make on behalf of the sender
+// of a trampoline payment.
+type TrampolinePayment struct {
+	// PaymentHash is the payment hash of the trampoline payment.
+	PaymentHash lntypes.Hash
+
+	// Target is the next node of the trampoline payment.
+	Target *btcec.PublicKey
+
+	// Amount is the amount the next node receives.
+	Amount lnwire.MilliSatoshi
+
+	// FeeLimit is the maximum fee we may pay to reach the next node.
+	FeeLimit lnwire.MilliSatoshi
+
+	// FinalCltv is the minimum absolute cltv expiry of the htlc the next
+	// node receives.
+	FinalCltv uint32
+
+	// MaxCltv is the maximum absolute cltv expiry of the htlc we send out.
+	MaxCltv uint32
+
+	// PaymentAddr is the payment address of the next node.
+	PaymentAddr *[32]byte
+
+	// Features are the invoice features of a next node that doesn't
+	// support trampoline payments.
+	Features *lnwire.RawFeatureVector
+
+	// RouteHints are the invoice route hints of a next node that doesn't
+	// support trampoline payments.
+	RouteHints [][]zpay32.HopHint
+
+	// TrampolineOnion is the trampoline onion for the next node. It is
+	// nil if the next node doesn't support trampoline payments.
+	TrampolineOnion []byte
+}
+
+// ExpiresTooSoon returns true if the payment can't be made at the given
+// height, because the htlc the next node receives would already have expired
+// or because there's no cltv delta left for the route to the next node. A
+// direct channel to the next node doesn't need any cltv delta, so the maximum
+// expiry may equal the final expiry.
+func (p *TrampolinePayment) ExpiresTooSoon(height uint32) bool {
+	return p.FinalCltv <= height || p.MaxCltv < p.FinalCltv
+}
+
+// TrampolineRelayConfig contains the configuration of a TrampolineRelay.
+type TrampolineRelayConfig struct {
+	// NodeKey is used to process the trampoline onions addressed to us.
+	NodeKey sphinx.SingleKeyECDH
+
+	// FeeBase is the base fee we charge for relaying trampoline payments.
+	FeeBase lnwire.MilliSatoshi
+
+	// FeeRate is the proportional fee in parts per million we charge for
+	// relaying trampoline payments.
+	FeeRate lnwire.MilliSatoshi
+
+	// CltvDelta is the cltv delta we require for relaying trampoline
+	// payments.
+	CltvDelta uint16
+
+	// MppTimeout is the maximum time we wait for all htlcs of a
+	// trampoline payment that is split into multiple htlcs. If the set
+	// isn't complete by then, its htlcs are failed.
+	MppTimeout time.Duration
+
+	// Registry is the invoice registry that receives the trampoline
+	// payments that are addressed to us.
+	Registry InvoiceDatabase
+
+	// SendPayment pays the next node of a trampoline payment. It blocks
+	// until the payment succeeded or failed.
+	SendPayment func(*TrampolinePayment) (lntypes.Preimage, error)
+}
+
+// relayedHtlc is an incoming htlc of a trampoline payment that we relay.
+type relayedHtlc struct {
+	// amt is the amount of the htlc.
+	amt lnwire.MilliSatoshi
+
+	// expiry is the absolute cltv expiry of the htlc.
+	expiry uint32
+
+	// hodlChan is the channel the resolution of the htlc is sent on. It
+	// is nil while the link of the htlc is offline.
+	hodlChan chan<- interface{}
+}
+
+// relayedPayment is a trampoline payment that we are relaying. If the payment
+// is split into multiple htlcs, it isn't relayed before the set of htlcs is
+// complete.
+type relayedPayment struct {
+	// payment is the payment to the next node. It is derived from the
+	// trampoline onion of the first htlc of the set.
+	payment *TrampolinePayment
+
+	// fee is the fee we charge for relaying the payment.
+	fee lnwire.MilliSatoshi
+
+	// paymentAddr is the payment address of the outer onion that ties
+	// the htlcs of the set together.
+	paymentAddr [32]byte
+
+	// total is the total amount of the set of htlcs.
+	total lnwire.MilliSatoshi
+
+	// htlcs are the incoming htlcs of the payment.
+	htlcs map[channeldb.CircuitKey]*relayedHtlc
+
+	// relayed is true once the set of htlcs is complete and the payment
+	// to the next node is made.
+	relayed bool
+
+	// complete is closed once the set of htlcs is complete.
+	complete chan struct{}
+
+	// acceptHeight is the height at which the payment was accepted.
+	acceptHeight int32
+}
+
+// amountPaid returns the sum of the amounts of the htlcs of the set.
+func (r *relayedPayment) amountPaid() lnwire.MilliSatoshi {
+	var paid lnwire.MilliSatoshi
+	for _, htlc := range r.htlcs {
+		paid += htlc.amt
+	}
+
+	return paid
+}
+
+// minExpiry returns the lowest expiry of the htlcs of the set.
+func (r *relayedPayment) minExpiry() uint32 {
+	var expiry uint32
+	for _, htlc := range r.htlcs {
+		if expiry == 0 || htlc.expiry < expiry {
+			expiry = htlc.expiry
+		}
+	}
+
+	return expiry
+}
+
+// TrampolineRelay relays the trampoline payments that are delegated to us. The
+// incoming htlcs are held until the payment to the next node of the trampoline
+// onion is resolved.
+type TrampolineRelay struct {
+	cfg *TrampolineRelayConfig
+
+	// payments are the payments that are currently relayed, keyed by
+	// their payment hash.
+	payments map[lntypes.Hash]*relayedPayment
+
+	sync.Mutex
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// A compile-time check to ensure TrampolineRelay implements the
+// TrampolineRelayer interface.
+var _ TrampolineRelayer = (*TrampolineRelay)(nil)
+
+// NewTrampolineRelay creates a new trampoline relay.
+func NewTrampolineRelay(cfg *TrampolineRelayConfig) *TrampolineRelay {
+	return &TrampolineRelay{
+		cfg:      cfg,
+		payments: make(map[lntypes.Hash]*relayedPayment),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Stop stops the trampoline relay. Payments that are in flight are resumed
+// once the incoming htlcs are processed again after a restart.
+func (t *TrampolineRelay) Stop() {
+	close(t.quit)
+	t.wg.Wait()
+}
+
+// RelayHtlc processes an incoming htlc that carries a trampoline onion.
+//
+// NOTE: This is part of the TrampolineRelayer interface.
+func (t *TrampolineRelay) RelayHtlc(payHash lntypes.Hash,
+	paidAmount lnwire.MilliSatoshi, expiry uint32, currentHeight int32,
+	circuitKey channeldb.CircuitKey, hodlChan chan<- interface{},
+	trampolineOnion []byte, mpp *record.MPP) (invoices.HtlcResolution,
+	error) {
+
+	fail := func(outcome invoices.FailResolutionResult) (
+		invoices.HtlcResolution, error) {
+
+		return invoices.NewFailResolution(
+			circuitKey, currentHeight, outcome,
+		), nil
+	}
+
+	onion, err := trampoline.DecodeOnionPacket(trampolineOnion)
+	if err != nil {
+		log.Debugf("Invalid trampoline onion for htlc %v: %v",
+			circuitKey, err)
+
+		return fail(invoices.ResultTrampolineFailure)
+	}
+
+	// The trampoline onion is bound to the payment hash, so it can't be
+	// reused for a different payment.
+	processed, err := trampoline.ProcessOnionPacket(
+		t.cfg.NodeKey, onion, payHash[:],
+	)
+	if err != nil {
+		log.Debugf("Unable to process trampoline onion for htlc %v: "+
+			"%v", circuitKey, err)
+
+		return fail(invoices.ResultTrampolineFailure)
+	}
+
+	payload, err := trampoline.DecodePayload(processed.Payload)
+	if err != nil {
+		log.Debugf("Invalid trampoline payload for htlc %v: %v",
+			circuitKey, err)
+
+		return fail(invoices.ResultTrampolineFailure)
+	}
+
+	// The payment may be split into multiple htlcs, which are tied
+	// together by the payment address and total amount of the outer
+	// onion. A payment without them is made up of this htlc only.
+	var (
+		paymentAddr [32]byte
+		total       = paidAmount
+	)
+	if mpp != nil {
+		paymentAddr = mpp.PaymentAddr()
+		total = mpp.TotalMsat()
+	}
+
+	// If we are the recipient of the trampoline payment, the htlc is
+	// handed to the invoice registry like a regular payment. The registry
+	// collects the htlcs of a split payment by the payment address and
+	// total amount of the trampoline payload.
+	if payload.IsFinal() {
+		switch {
+		case processed.NextPacket != nil:
+			return fail(invoices.ResultTrampolineFailure)
+
+		case total < payload.AmtToForward:
+			return fail(invoices.ResultAmountTooLow)
+
+		case expiry < payload.OutgoingCltv:
+			return fail(invoices.ResultExpiryTooSoon)
+		}
+
+		return t.cfg.Registry.NotifyExitHopHtlc(
+			payHash, paidAmount, expiry, currentHeight, circuitKey,
+			hodlChan, &hop.Payload{
+				FwdInfo: hop.ForwardingInfo{
+					Network:         hop.BitcoinNetwork,
+					NextHop:         hop.Exit,
+					AmountToForward: payload.AmtToForward,
+					OutgoingCTLV:    payload.OutgoingCltv,
+				},
+				MPP: payload.MPP,
+			},
+		)
+	}
+
+	// Otherwise we relay the payment.
+	// The payment must leave enough fees and cltv delta for us.
+	fee := t.cfg.FeeBase + payload.AmtToForward*t.cfg.FeeRate/1000000
+	if total < payload.AmtToForward+fee {
+		return fail(invoices.ResultTrampolineFeeInsufficient)
+	}
+
+	if expiry < payload.OutgoingCltv+uint32(t.cfg.CltvDelta) {
+		return fail(invoices.ResultTrampolineExpiryTooSoon)
+	}
+
+	payment := &TrampolinePayment{
+		PaymentHash: payHash,
+		Target:      payload.OutgoingNodeID,
+		Amount:      payload.AmtToForward,
+		FinalCltv:   payload.OutgoingCltv,
+	}
+
+	// If the next node supports trampoline payments, it receives the
+	// remainder of the trampoline onion. Otherwise we pay it like a
+	// regular invoice.
+	if processed.NextPacket != nil {
+		payment.TrampolineOnion, err = processed.NextPacket.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		var paymentAddr [32]byte
+		if _, err := rand.Read(paymentAddr[:]); err != nil {
+			return nil, err
+		}
+		payment.PaymentAddr = &paymentAddr
+	} else {
+		if payload.MPP == nil {
+			return fail(invoices.ResultTrampolineFailure)
+		}
+
+		paymentAddr := payload.MPP.PaymentAddr()
+		payment.PaymentAddr = &paymentAddr
+		payment.Features = payload.InvoiceFeatures
+		payment.RouteHints = payload.InvoiceRouteHints
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	htlc := &relayedHtlc{
+		amt:      paidAmount,
+		expiry:   expiry,
+		hodlChan: hodlChan,
+	}
+
+	relayed, ok := t.payments[payHash]
+	switch {
+	// The first htlc of the payment creates the set.
+	case !ok:
+		relayed = &relayedPayment{
+			payment:     payment,
+			fee:         fee,
+			paymentAddr: paymentAddr,
+			total:       total,
+			htlcs: make(
+				map[channeldb.CircuitKey]*relayedHtlc,
+			),
+			complete:     make(chan struct{}),
+			acceptHeight: currentHeight,
+		}
+		t.payments[payHash] = relayed
+
+		if total > paidAmount {
+			log.Debugf("Waiting for all htlcs of trampoline "+
+				"payment %v", payHash)
+
+			t.wg.Add(1)
+			go t.waitForSet(payHash, relayed)
+		}
+
+	// An htlc of the set is processed again when its link restarts, in
+	// which case it is resolved along with the payment.
+	case relayed.htlcs[circuitKey] != nil:
+		relayed.htlcs[circuitKey].hodlChan = hodlChan
+		return nil, nil
+
+	case relayed.relayed:
+		log.Debugf("Rejecting trampoline htlc %v, payment %v is "+
+			"already relayed", circuitKey, payHash)
+
+		return fail(invoices.ResultHtlcSetOverpayment)
+
+	case relayed.paymentAddr != paymentAddr:
+		return fail(invoices.ResultAddressMismatch)
+
+	case relayed.total != total:
+		return fail(invoices.ResultHtlcSetTotalMismatch)
+
+	// All htlcs of the set must carry the same instructions for the
+	// payment to the next node.
+	case !relayed.payment.sameInstructions(payment):
+		log.Debugf("Rejecting trampoline htlc %v, its trampoline "+
+			"onion doesn't match the other htlcs of payment %v",
+			circuitKey, payHash)
+
+		return fail(invoices.ResultTrampolineFailure)
+	}
+
+	relayed.htlcs[circuitKey] = htlc
+
+	// We wait for the remaining htlcs if the set isn't complete yet.
+	if relayed.amountPaid() < relayed.total {
+		return nil, nil
+	}
+	close(relayed.complete)
+
+	// The fee limit is derived from the total amount of the set, and the
+	// expiry from the htlc of the set that expires first.
+	payment = relayed.payment
+	payment.FeeLimit = relayed.total - payment.Amount - relayed.fee
+	payment.MaxCltv = relayed.minExpiry() - uint32(t.cfg.CltvDelta)
+	if payment.ExpiresTooSoon(uint32(currentHeight)) {
+		// The resolution of this htlc is returned directly, the
+		// other htlcs of the set are failed along with it.
+		htlc.hodlChan = nil
+		delete(t.payments, payHash)
+		t.resolveSet(relayed, invoices.ResultTrampolineExpiryTooSoon)
+
+		return fail(invoices.ResultTrampolineExpiryTooSoon)
+	}
+	relayed.relayed = true
+
+	log.Infof("Relaying trampoline payment %v to %x with %d htlc(s)",
+		payHash, payment.Target.SerializeCompressed(),
+		len(relayed.htlcs))
+
+	t.wg.Add(1)
+	go t.relay(payment)
+
+	return nil, nil
+}
+
+// sameInstructions returns true if the other payment pays the same node the
+// same amount, in the same way.
+func (p *TrampolinePayment) sameInstructions(other *TrampolinePayment) bool {
+	switch {
+	case !p.Target.IsEqual(other.Target):
+		return false
+
+	case p.Amount != other.Amount || p.FinalCltv != other.FinalCltv:
+		return false
+
+	// The payment address is random if the next node supports
+	// trampoline payments.
+	case (p.TrampolineOnion == nil) != (other.TrampolineOnion == nil):
+		return false
+
+	case p.TrampolineOnion == nil && *p.PaymentAddr != *other.PaymentAddr:
+		return false
+	}
+
+	return true
+}
+
+// waitForSet fails the htlcs of a split trampoline payment if the set of htlcs
+// isn't complete before the mpp timeout.
+//
+// NOTE: This MUST be run as a goroutine.
+func (t *TrampolineRelay) waitForSet(payHash lntypes.Hash,
+	relayed *relayedPayment) {
+
+	defer t.wg.Done()
+
+	select {
+	case <-relayed.complete:
+		return
+
+	case <-time.After(t.cfg.MppTimeout):
+
+	case <-t.quit:
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	// The set may have been completed while we acquired the lock.
+	select {
+	case <-relayed.complete:
+		return
+	default:
+	}
+
+	log.Debugf("Trampoline payment %v timed out waiting for all htlcs",
+		payHash)
+
+	delete(t.payments, payHash)
+	t.resolveSet(relayed, invoices.ResultMppTimeout)
+}
+
+// resolveSet fails all htlcs of the set with the given outcome.
+//
+// NOTE: The caller must hold the lock.
+func (t *TrampolineRelay) resolveSet(relayed *relayedPayment,
+	outcome invoices.FailResolutionResult) {
+
+	for circuitKey, htlc := range relayed.htlcs {
+		if htlc.hodlChan == nil {
+			continue
+		}
+
+		resolution := invoices.NewFailResolution(
+			circuitKey, relayed.acceptHeight, outcome,
+		)
+
+		select {
+		case htlc.hodlChan <- resolution:
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// relay pays the next node of a trampoline payment and resolves the incoming
+// htlcs once the payment is resolved.
+func (t *TrampolineRelay) relay(payment *TrampolinePayment) {
+	defer t.wg.Done()
+
+	preimage, err := t.cfg.SendPayment(payment)
+	if err != nil {
+		log.Infof("Trampoline payment %v failed: %v",
+			payment.PaymentHash, err)
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	relayed, ok := t.payments[payment.PaymentHash]
+	if !ok {
+		return
+	}
+	delete(t.payments, payment.PaymentHash)
+
+	// Senders can adjust the cltv delta they leave for us if the
+	// payment expired too soon, so they are told.
+	if err != nil {
+		outcome := invoices.ResultTrampolineFailure
+		if err == ErrTrampolineExpiryTooSoon {
+			outcome = invoices.ResultTrampolineExpiryTooSoon
+		}
+		t.resolveSet(relayed, outcome)
+
+		return
+	}
+
+	for circuitKey, htlc := range relayed.htlcs {
+		if htlc.hodlChan == nil {
+			continue
+		}
+
+		resolution := invoices.NewSettleResolution(
+			preimage, circuitKey, relayed.acceptHeight,
+			invoices.ResultSettled,
+		)
+
+		select {
+		case htlc.hodlChan <- resolution:
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// UnsubscribeAll unsubscribes from all htlc resolutions. The htlcs remain
+// part of their set, so that they still count towards its total amount.
+//
+// NOTE: This is part of the TrampolineRelayer interface.
+func (t *TrampolineRelay) UnsubscribeAll(subscriber chan<- interface{}) {
+	t.Lock()
+	defer t.Unlock()
+
+	for _, relayed := range t.payments {
+		for _, htlc := range relayed.htlcs {
+			if htlc.hodlChan == subscriber {
+				htlc.hodlChan = nil
+			}
+		}
+	}
+}
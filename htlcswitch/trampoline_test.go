@@ -0,0 +1,298 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/trampoline"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTrampolineHeight    = 100
+	testTrampolineCltvDelta = 40
+	testTrampolineFinalCltv = 200
+	testTrampolineAmt       = 100000
+)
+
+// trampolineRelayTestCtx is a trampoline relay that pays the next node
+// through a mock payment function.
+type trampolineRelayTestCtx struct {
+	t        *testing.T
+	relay    *TrampolineRelay
+	nodeKey  *btcec.PrivateKey
+	nextNode *btcec.PrivateKey
+	payHash  lntypes.Hash
+	payments chan *TrampolinePayment
+	results  chan error
+}
+
+func newTrampolineRelayTestCtx(t *testing.T) *trampolineRelayTestCtx {
+	t.Helper()
+
+	nodeKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	nextNode, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	ctx := &trampolineRelayTestCtx{
+		t:        t,
+		nodeKey:  nodeKey,
+		nextNode: nextNode,
+		payHash:  lntypes.Hash{1},
+		payments: make(chan *TrampolinePayment, 1),
+		results:  make(chan error, 1),
+	}
+
+	ctx.relay = NewTrampolineRelay(&TrampolineRelayConfig{
+		NodeKey:    &sphinx.PrivKeyECDH{PrivKey: nodeKey},
+		FeeBase:    1000,
+		CltvDelta:  testTrampolineCltvDelta,
+		MppTimeout: time.Minute,
+		SendPayment: func(p *TrampolinePayment) (lntypes.Preimage,
+			error) {
+
+			ctx.payments <- p
+			return lntypes.Preimage{}, <-ctx.results
+		},
+	})
+	t.Cleanup(ctx.relay.Stop)
+
+	return ctx
+}
+
+// onion returns a trampoline onion that relays the payment through our node
+// to the next node.
+func (c *trampolineRelayTestCtx) onion() []byte {
+	relayPayload, err := (&trampoline.Payload{
+		AmtToForward:   testTrampolineAmt,
+		OutgoingCltv:   testTrampolineFinalCltv,
+		OutgoingNodeID: c.nextNode.PubKey(),
+	}).Encode()
+	require.NoError(c.t, err)
+
+	finalPayload, err := (&trampoline.Payload{
+		AmtToForward: testTrampolineAmt,
+		OutgoingCltv: testTrampolineFinalCltv,
+		MPP:          record.NewMPP(testTrampolineAmt, [32]byte{2}),
+	}).Encode()
+	require.NoError(c.t, err)
+
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(c.t, err)
+
+	onion, err := trampoline.NewOnionPacket(
+		[]trampoline.OnionHop{
+			{NodePub: c.nodeKey.PubKey(), Payload: relayPayload},
+			{NodePub: c.nextNode.PubKey(), Payload: finalPayload},
+		},
+		sessionKey, c.payHash[:],
+	)
+	require.NoError(c.t, err)
+
+	serialized, err := onion.Serialize()
+	require.NoError(c.t, err)
+
+	return serialized
+}
+
+// relayHtlc passes an htlc that pays the trampoline fee to the relay.
+func (c *trampolineRelayTestCtx) relayHtlc(htlcID uint64, expiry uint32,
+	mpp *record.MPP, hodlChan chan<- interface{}) invoices.HtlcResolution {
+
+	return c.relayPart(htlcID, testTrampolineAmt+1000, expiry, mpp, hodlChan)
+}
+
+// relayPart passes an htlc of the given amount to the relay.
+func (c *trampolineRelayTestCtx) relayPart(htlcID uint64,
+	amt lnwire.MilliSatoshi, expiry uint32, mpp *record.MPP,
+	hodlChan chan<- interface{}) invoices.HtlcResolution {
+
+	resolution, err := c.relay.RelayHtlc(
+		c.payHash, amt, expiry, testTrampolineHeight,
+		channeldb.CircuitKey{HtlcID: htlcID}, hodlChan, c.onion(),
+		mpp,
+	)
+	require.NoError(c.t, err)
+
+	return resolution
+}
+
+// assertNoPayment asserts that the relay doesn't pay the next node.
+func (c *trampolineRelayTestCtx) assertNoPayment() {
+	c.t.Helper()
+
+	select {
+	case <-c.payments:
+		c.t.Fatal("unexpected payment")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// assertFailed asserts that the resolution fails the htlc with the given
+// outcome.
+func assertFailed(t *testing.T, resolution interface{},
+	outcome invoices.FailResolutionResult) {
+
+	t.Helper()
+
+	fail, ok := resolution.(*invoices.HtlcFailResolution)
+	require.True(t, ok, "expected fail resolution, got %T", resolution)
+	require.Equal(t, outcome, fail.Outcome)
+}
+
+// TestTrampolineRelayExpiry tests that a trampoline payment that leaves
+// exactly our cltv delta is relayed, and that a payment that expires too soon
+// by the time it is sent fails with an expiry too soon failure.
+func TestTrampolineRelayExpiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTrampolineRelayTestCtx(t)
+
+	// An htlc that doesn't leave our cltv delta is rejected.
+	resolution := ctx.relayHtlc(
+		0, testTrampolineFinalCltv+testTrampolineCltvDelta-1, nil, nil,
+	)
+	assertFailed(t, resolution, invoices.ResultTrampolineExpiryTooSoon)
+
+	// An htlc that leaves exactly our cltv delta is relayed. The payment
+	// may only be made through a direct channel to the next node.
+	hodlChan := make(chan interface{}, 1)
+	resolution = ctx.relayHtlc(
+		1, testTrampolineFinalCltv+testTrampolineCltvDelta, nil,
+		hodlChan,
+	)
+	require.Nil(t, resolution)
+
+	var payment *TrampolinePayment
+	select {
+	case payment = <-ctx.payments:
+	case <-time.After(time.Second):
+		t.Fatal("payment not sent")
+	}
+	require.Equal(t, payment.FinalCltv, payment.MaxCltv)
+	require.False(t, payment.ExpiresTooSoon(testTrampolineHeight))
+	require.True(t, payment.ExpiresTooSoon(testTrampolineFinalCltv))
+
+	// If blocks are mined before the payment is sent, the sender is told
+	// that the payment expired too soon.
+	ctx.results <- ErrTrampolineExpiryTooSoon
+
+	select {
+	case resolution := <-hodlChan:
+		assertFailed(
+			t, resolution, invoices.ResultTrampolineExpiryTooSoon,
+		)
+
+	case <-time.After(time.Second):
+		t.Fatal("htlc not resolved")
+	}
+}
+
+// TestTrampolineRelayMpp tests that trampoline payments that are split into
+// multiple htlcs are relayed once all htlcs of the set have arrived.
+func TestTrampolineRelayMpp(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTrampolineRelayTestCtx(t)
+	expiry := uint32(testTrampolineFinalCltv + testTrampolineCltvDelta)
+
+	const total = testTrampolineAmt + 1000
+	paymentAddr := [32]byte{3}
+	mpp := record.NewMPP(total, paymentAddr)
+
+	// The first htlc of the set is held until the set is complete.
+	hodlChan1 := make(chan interface{}, 1)
+	resolution := ctx.relayPart(0, total/2, expiry+10, mpp, hodlChan1)
+	require.Nil(t, resolution)
+	ctx.assertNoPayment()
+
+	// Htlcs that don't match the set are rejected.
+	resolution = ctx.relayPart(
+		1, total/2, expiry, record.NewMPP(total, [32]byte{4}), nil,
+	)
+	assertFailed(t, resolution, invoices.ResultAddressMismatch)
+
+	resolution = ctx.relayPart(
+		1, total/2, expiry, record.NewMPP(2*total, paymentAddr), nil,
+	)
+	assertFailed(t, resolution, invoices.ResultHtlcSetTotalMismatch)
+
+	// An htlc of the set that is processed again doesn't count twice.
+	resolution = ctx.relayPart(0, total/2, expiry+10, mpp, hodlChan1)
+	require.Nil(t, resolution)
+	ctx.assertNoPayment()
+
+	// The last htlc completes the set, and the payment is relayed with
+	// the fee limit of the total amount and the expiry of the htlc that
+	// expires first.
+	hodlChan2 := make(chan interface{}, 1)
+	resolution = ctx.relayPart(2, total-total/2, expiry, mpp, hodlChan2)
+	require.Nil(t, resolution)
+
+	var payment *TrampolinePayment
+	select {
+	case payment = <-ctx.payments:
+	case <-time.After(time.Second):
+		t.Fatal("payment not sent")
+	}
+	require.Equal(t, lnwire.MilliSatoshi(testTrampolineAmt), payment.Amount)
+	require.Equal(t, lnwire.MilliSatoshi(0), payment.FeeLimit)
+	require.Equal(t, expiry-testTrampolineCltvDelta, payment.MaxCltv)
+
+	// Another htlc for the payment that is relayed is rejected.
+	resolution = ctx.relayPart(3, total/2, expiry, mpp, nil)
+	assertFailed(t, resolution, invoices.ResultHtlcSetOverpayment)
+
+	// All htlcs of the set are settled with the payment.
+	ctx.results <- nil
+
+	for _, hodlChan := range []chan interface{}{hodlChan1, hodlChan2} {
+		select {
+		case resolution := <-hodlChan:
+			_, ok := resolution.(*invoices.HtlcSettleResolution)
+			require.True(t, ok, "expected settle resolution, "+
+				"got %T", resolution)
+
+		case <-time.After(time.Second):
+			t.Fatal("htlc not resolved")
+		}
+	}
+}
+
+// TestTrampolineRelayMppTimeout tests that the htlcs of a split trampoline
+// payment are failed if the set isn't complete before the mpp timeout.
+func TestTrampolineRelayMppTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTrampolineRelayTestCtx(t)
+	ctx.relay.cfg.MppTimeout = 100 * time.Millisecond
+	expiry := uint32(testTrampolineFinalCltv + testTrampolineCltvDelta)
+
+	const total = testTrampolineAmt + 1000
+	mpp := record.NewMPP(total, [32]byte{3})
+
+	hodlChan := make(chan interface{}, 1)
+	resolution := ctx.relayPart(0, total/2, expiry, mpp, hodlChan)
+	require.Nil(t, resolution)
+
+	select {
+	case resolution := <-hodlChan:
+		assertFailed(t, resolution, invoices.ResultMppTimeout)
+
+	case <-time.After(time.Second):
+		t.Fatal("htlc not resolved")
+	}
+	ctx.assertNoPayment()
+
+	// A new htlc for the payment starts a new set.
+	resolution = ctx.relayPart(1, total/2, expiry, mpp, hodlChan)
+	require.Nil(t, resolution)
+}
@@ -223,10 +223,15 @@ func MakeHtlcSucceedInput(outpoint *wire.OutPoint,
 	signDescriptor *SignDescriptor, preimage []byte, heightHint,
 	blocksToMaturity uint32) HtlcSucceedInput {
 
+	witnessType := HtlcAcceptedRemoteSuccess
+	if signDescriptor.SignMethod.IsTaproot() {
+		witnessType = TaprootHtlcAcceptedRemoteSuccess
+	}
+
 	return HtlcSucceedInput{
 		inputKit: inputKit{
 			outpoint:        *outpoint,
-			witnessType:     HtlcAcceptedRemoteSuccess,
+			witnessType:     witnessType,
 			signDesc:        *signDescriptor,
 			heightHint:      heightHint,
 			blockToMaturity: blocksToMaturity,
@@ -246,9 +251,19 @@ func (h *HtlcSucceedInput) CraftInputScript(signer Signer, txn *wire.MsgTx,
 	desc.SigHashes = hashCache
 	desc.InputIndex = txinIdx
 
-	witness, err := SenderHtlcSpendRedeem(
-		signer, &desc, txn, h.preimage,
+	var (
+		witness wire.TxWitness
+		err     error
 	)
+	if desc.SignMethod.IsTaproot() {
+		witness, err = TaprootSenderHtlcSpendRedeem(
+			signer, &desc, txn, h.preimage,
+		)
+	} else {
+		witness, err = SenderHtlcSpendRedeem(
+			signer, &desc, txn, h.preimage,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -269,9 +284,10 @@ type HtlcSecondLevelAnchorInput struct {
 	SignedTx *wire.MsgTx
 
 	// createWitness creates a witness allowing the passed transaction to
-	// spend the input.
-	createWitness func(signer Signer, txn *wire.MsgTx,
-		hashCache *txscript.TxSigHashes, txinIdx int) (wire.TxWitness, error)
+	// spend the input described by the sign descriptor.
+	createWitness func(signer Signer, signDesc *SignDescriptor,
+		txn *wire.MsgTx, hashCache *txscript.TxSigHashes,
+		txinIdx int) (wire.TxWitness, error)
 }
 
 // RequiredTxOut returns the tx out needed to be present on the sweep tx for
@@ -295,7 +311,9 @@ func (i *HtlcSecondLevelAnchorInput) CraftInputScript(signer Signer,
 	txn *wire.MsgTx, hashCache *txscript.TxSigHashes,
 	txinIdx int) (*Script, error) {
 
-	witness, err := i.createWitness(signer, txn, hashCache, txinIdx)
+	witness, err := i.createWitness(
+		signer, i.SignDesc(), txn, hashCache, txinIdx,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -313,24 +331,36 @@ func MakeHtlcSecondLevelTimeoutAnchorInput(signedTx *wire.MsgTx,
 
 	// Spend an HTLC output on our local commitment tx using the
 	// 2nd timeout transaction.
-	createWitness := func(signer Signer, txn *wire.MsgTx,
-		hashCache *txscript.TxSigHashes,
+	createWitness := func(signer Signer, signDesc *SignDescriptor,
+		txn *wire.MsgTx, hashCache *txscript.TxSigHashes,
 		txinIdx int) (wire.TxWitness, error) {
 
-		desc := signDetails.SignDesc
+		desc := *signDesc
 		desc.SigHashes = txscript.NewTxSigHashes(txn)
 		desc.InputIndex = txinIdx
 
+		if desc.SignMethod.IsTaproot() {
+			return TaprootSenderHtlcSpendTimeout(
+				signDetails.PeerSig, signDetails.SigHashType,
+				signer, &desc, txn,
+			)
+		}
+
 		return SenderHtlcSpendTimeout(
 			signDetails.PeerSig, signDetails.SigHashType, signer,
 			&desc, txn,
 		)
 	}
 
+	witnessType := HtlcOfferedTimeoutSecondLevelInputConfirmed
+	if signDetails.SignDesc.SignMethod.IsTaproot() {
+		witnessType = TaprootHtlcOfferedTimeoutSecondLevelInputConfirmed
+	}
+
 	return HtlcSecondLevelAnchorInput{
 		inputKit: inputKit{
 			outpoint:    signedTx.TxIn[0].PreviousOutPoint,
-			witnessType: HtlcOfferedTimeoutSecondLevelInputConfirmed,
+			witnessType: witnessType,
 			signDesc:    signDetails.SignDesc,
 			heightHint:  heightHint,
 
@@ -351,24 +381,36 @@ func MakeHtlcSecondLevelSuccessAnchorInput(signedTx *wire.MsgTx,
 
 	// Spend an HTLC output on our local commitment tx using the 2nd
 	// success transaction.
-	createWitness := func(signer Signer, txn *wire.MsgTx,
-		hashCache *txscript.TxSigHashes,
+	createWitness := func(signer Signer, signDesc *SignDescriptor,
+		txn *wire.MsgTx, hashCache *txscript.TxSigHashes,
 		txinIdx int) (wire.TxWitness, error) {
 
-		desc := signDetails.SignDesc
+		desc := *signDesc
 		desc.SigHashes = hashCache
 		desc.InputIndex = txinIdx
 
+		if desc.SignMethod.IsTaproot() {
+			return TaprootReceiverHtlcSpendRedeem(
+				signDetails.PeerSig, signDetails.SigHashType,
+				preimage[:], signer, &desc, txn,
+			)
+		}
+
 		return ReceiverHtlcSpendRedeem(
 			signDetails.PeerSig, signDetails.SigHashType,
 			preimage[:], signer, &desc, txn,
 		)
 	}
 
+	witnessType := HtlcAcceptedSuccessSecondLevelInputConfirmed
+	if signDetails.SignDesc.SignMethod.IsTaproot() {
+		witnessType = TaprootHtlcAcceptedSuccessSecondLevelInputConfirmed
+	}
+
 	return HtlcSecondLevelAnchorInput{
 		inputKit: inputKit{
 			outpoint:    signedTx.TxIn[0].PreviousOutPoint,
-			witnessType: HtlcAcceptedSuccessSecondLevelInputConfirmed,
+			witnessType: witnessType,
 			signDesc:    signDetails.SignDesc,
 			heightHint:  heightHint,
 
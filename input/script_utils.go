@@ -326,6 +326,13 @@ func SenderHtlcSpendRevoke(signer Signer, signDesc *SignDescriptor,
 func IsHtlcSpendRevoke(txIn *wire.TxIn, signDesc *SignDescriptor) (
 	bool, error) {
 
+	// The revocation key is the internal key of taproot HTLC outputs, so
+	// only a revocation spend goes over the key path, which leaves just
+	// the signature in the witness.
+	if signDesc.SignMethod.IsTaproot() {
+		return len(txIn.Witness) == 1, nil
+	}
+
 	revokeKey, err := deriveRevokePubKey(signDesc)
 	if err != nil {
 		return false, err
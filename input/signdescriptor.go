@@ -3,6 +3,7 @@ package input
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/btcsuite/btcd/btcec"
@@ -17,6 +18,14 @@ var (
 	ErrTweakOverdose = errors.New("sign descriptor should only have one tweak")
 )
 
+const (
+	// taprootSignDescFlag is set in the serialized sighash type of a sign
+	// descriptor that is followed by the taproot fields. Sign descriptors
+	// of segwit v0 inputs are serialized without it, so that they remain
+	// readable by older versions.
+	taprootSignDescFlag = 1 << 31
+)
+
 // SignDescriptor houses the necessary information required to successfully
 // sign a given segwit output. This struct is used by the Signer interface in
 // order to gain access to critical data needed to generate a valid signature.
@@ -58,9 +67,23 @@ type SignDescriptor struct {
 	// WitnessScript is the full script required to properly redeem the
 	// output. This field should be set to the full script if a p2wsh
 	// output is being signed. For p2wkh it should be set to the hashed
-	// script (PkScript).
+	// script (PkScript). For taproot script path spends, it is the leaf
+	// script that is executed.
 	WitnessScript []byte
 
+	// SignMethod is the method used to sign the input. The zero value
+	// signs a segwit v0 input.
+	SignMethod SignMethod
+
+	// TapTweak is the root hash of the tapscript tree of a taproot output
+	// that is spent over the key path. The private key is tweaked with it
+	// to obtain the key of the output.
+	TapTweak []byte
+
+	// ControlBlock is the control block that proves the inclusion of the
+	// witness script in a taproot output spent over the script path.
+	ControlBlock []byte
+
 	// Output is the target output which should be signed. The PkScript and
 	// Value fields within the output should be properly populated,
 	// otherwise an invalid signature may be generated.
@@ -74,6 +97,11 @@ type SignDescriptor struct {
 	// generating the final sighash for signing.
 	SigHashes *txscript.TxSigHashes
 
+	// PrevOutputFetcher returns the outputs spent by all inputs of the
+	// transaction, which taproot signatures commit to. It may be left
+	// unset for transactions with a single input.
+	PrevOutputFetcher PrevOutputFetcher
+
 	// InputIndex is the target input within the transaction that should be
 	// signed.
 	InputIndex int
@@ -82,9 +110,9 @@ type SignDescriptor struct {
 // WriteSignDescriptor serializes a SignDescriptor struct into the passed
 // io.Writer stream.
 //
-// NOTE: We assume the SigHashes and InputIndex fields haven't been assigned
-// yet, since that is usually done just before broadcast by the witness
-// generator.
+// NOTE: We assume the SigHashes, PrevOutputFetcher and InputIndex fields
+// haven't been assigned yet, since that is usually done just before broadcast
+// by the witness generator.
 func WriteSignDescriptor(w io.Writer, sd *SignDescriptor) error {
 	err := binary.Write(w, binary.BigEndian, sd.KeyDesc.Family)
 	if err != nil {
@@ -127,13 +155,31 @@ func WriteSignDescriptor(w io.Writer, sd *SignDescriptor) error {
 		return err
 	}
 
+	// The taproot fields are only written if the input is a taproot
+	// input, which is signaled within the sighash type.
+	hashType := uint32(sd.HashType)
+	if sd.SignMethod.IsTaproot() {
+		hashType |= taprootSignDescFlag
+	}
+
 	var scratch [4]byte
-	binary.BigEndian.PutUint32(scratch[:], uint32(sd.HashType))
+	binary.BigEndian.PutUint32(scratch[:], hashType)
 	if _, err := w.Write(scratch[:]); err != nil {
 		return err
 	}
 
-	return nil
+	if !sd.SignMethod.IsTaproot() {
+		return nil
+	}
+
+	if _, err := w.Write([]byte{byte(sd.SignMethod)}); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, sd.TapTweak); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, sd.ControlBlock)
 }
 
 // ReadSignDescriptor deserializes a SignDescriptor struct from the passed
@@ -222,5 +268,40 @@ func ReadSignDescriptor(r io.Reader, sd *SignDescriptor) error {
 	}
 	sd.HashType = txscript.SigHashType(binary.BigEndian.Uint32(hashType[:]))
 
+	if sd.HashType&taprootSignDescFlag == 0 {
+		return nil
+	}
+	sd.HashType &^= taprootSignDescFlag
+
+	var signMethod [1]byte
+	if _, err := io.ReadFull(r, signMethod[:]); err != nil {
+		return err
+	}
+	sd.SignMethod = SignMethod(signMethod[0])
+	if !sd.SignMethod.IsTaproot() {
+		return fmt.Errorf("unknown taproot sign method: %v",
+			sd.SignMethod)
+	}
+
+	tapTweak, err := wire.ReadVarBytes(r, 0, 32, "tapTweak")
+	if err != nil {
+		return err
+	}
+	if len(tapTweak) != 0 {
+		sd.TapTweak = tapTweak
+	}
+
+	const maxControlBlockSize = ControlBlockBaseSize +
+		ControlBlockNodeSize*maxControlBlockNodes
+	controlBlock, err := wire.ReadVarBytes(
+		r, 0, maxControlBlockSize, "controlBlock",
+	)
+	if err != nil {
+		return err
+	}
+	if len(controlBlock) != 0 {
+		sd.ControlBlock = controlBlock
+	}
+
 	return nil
 }
@@ -11,6 +11,10 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 )
 
+var testPrivKey, _ = btcec.PrivKeyFromBytes(
+	btcec.S256(), bytes.Repeat([]byte{0x02}, 32),
+)
+
 func TestSignDescriptorSerialization(t *testing.T) {
 	keys := [][]byte{
 		{0x04, 0x11, 0xdb, 0x93, 0xe1, 0xdc, 0xdb, 0x8a,
@@ -91,12 +95,59 @@ func TestSignDescriptorSerialization(t *testing.T) {
 			},
 			HashType: txscript.SigHashAll,
 		},
+
+		// Test serializing a SignDescriptor of a taproot script path
+		// spend.
+		{
+			DoubleTweak: testPrivKey,
+			WitnessScript: []byte{
+				0x20, 0x79, 0xbe, 0x66, 0x7e, 0xf9, 0xdc, 0xbb,
+				0xac, 0x55, 0xa0, 0x62, 0x95, 0xce, 0x87, 0x0b,
+				0x07, 0x02, 0x9b, 0xfc, 0xdb, 0x2d, 0xce, 0x28,
+				0xd9, 0x59, 0xf2, 0x81, 0x5b, 0x16, 0xf8, 0x17,
+				0x98, 0xac,
+			},
+			SignMethod: TaprootScriptSpendSignMethod,
+			ControlBlock: append(
+				[]byte{TapscriptLeafVersion | 1},
+				bytes.Repeat([]byte{0x03}, 64)...,
+			),
+			Output: &wire.TxOut{
+				Value: 5000000000,
+				PkScript: append(
+					[]byte{0x51, 0x20},
+					bytes.Repeat([]byte{0x04}, 32)...,
+				),
+			},
+			HashType: SigHashDefault,
+		},
+
+		// Test serializing a SignDescriptor of a taproot key path
+		// spend.
+		{
+			// Key path spends have no witness script, which is
+			// read back as an empty slice.
+			WitnessScript: []byte{},
+			TapTweak:      bytes.Repeat([]byte{0x05}, 32),
+			SignMethod:    TaprootKeySpendSignMethod,
+			Output: &wire.TxOut{
+				Value: 5000000000,
+				PkScript: append(
+					[]byte{0x51, 0x20},
+					bytes.Repeat([]byte{0x04}, 32)...,
+				),
+			},
+			HashType: txscript.SigHashSingle |
+				txscript.SigHashAnyOneCanPay,
+		},
 	}
 
 	for i := 0; i < len(signDescriptors); i++ {
 		// Parse pubkeys for each sign descriptor.
 		sd := &signDescriptors[i]
-		pubkey, err := btcec.ParsePubKey(keys[i], btcec.S256())
+		pubkey, err := btcec.ParsePubKey(
+			keys[i%len(keys)], btcec.S256(),
+		)
 		if err != nil {
 			t.Fatalf("unable to parse pubkey: %v", err)
 		}
@@ -2,6 +2,8 @@ package input
 
 import (
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 // Signer represents an abstract object capable of generating raw signatures as
@@ -14,9 +16,17 @@ type Signer interface {
 	// according to the data within the passed SignDescriptor.
 	//
 	// NOTE: The resulting signature should be void of a sighash byte.
+	// Taproot inputs, as indicated by the sign method of the sign
+	// descriptor, are signed with a BIP-340 signature.
 	SignOutputRaw(tx *wire.MsgTx,
 		signDesc *SignDescriptor) (Signature, error)
 
+	// MuSig2Sign creates a MuSig2 partial signature for the signing
+	// session with the key of the key descriptor. The secret nonces are
+	// consumed, so they can't be used for another signature.
+	MuSig2Sign(keyDesc *keychain.KeyDescriptor, nonces *schnorr.Nonces,
+		session *schnorr.Session) (schnorr.PartialSig, error)
+
 	// ComputeInputScript generates a complete InputIndex for the passed
 	// transaction with the signature as defined within the passed
 	// SignDescriptor. This method should be capable of generating the
@@ -496,6 +496,260 @@ const (
 	//      - witness_script_length: 1 byte
 	//      - witness_script (anchor_script)
 	AnchorWitnessSize = 1 + 1 + 73 + 1 + AnchorScriptSize
+
+	// P2TRSize 34 bytes
+	//	- OP_1: 1 byte
+	//	- OP_DATA: 1 byte (x-only output key length)
+	//	- x-only output key: 32 bytes
+	P2TRSize = 1 + 1 + 32
+
+	// P2TROutputSize 43 bytes
+	//      - value: 8 bytes
+	//      - var_int: 1 byte (pkscript_length)
+	//      - pkscript (p2tr): 34 bytes
+	P2TROutputSize = 8 + 1 + P2TRSize
+
+	// TaprootSigSize 65 bytes
+	//	- schnorr signature: 64 bytes
+	//	- sighash type: 1 byte, omitted for SIGHASH_DEFAULT
+	TaprootSigSize = 64 + 1
+
+	// TaprootControlBlockSize 33 bytes
+	//	- leaf version and output key parity: 1 byte
+	//	- x-only internal key: 32 bytes
+	TaprootControlBlockSize = ControlBlockBaseSize
+
+	// TaprootTwoLeafControlBlockSize 65 bytes
+	//	- control block: 33 bytes
+	//	- sibling leaf hash: 32 bytes
+	TaprootTwoLeafControlBlockSize = ControlBlockBaseSize +
+		ControlBlockNodeSize
+
+	// TaprootKeyPathWitnessSize 67 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- sig_length: 1 byte
+	//	- sig: 65 bytes
+	TaprootKeyPathWitnessSize = 1 + 1 + TaprootSigSize
+
+	// TaprootMultiSigSize 68 bytes
+	//	- OP_DATA: 1 byte (x-only key length)
+	//	- key1: 32 bytes
+	//	- OP_CHECKSIGVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (x-only key length)
+	//	- key2: 32 bytes
+	//	- OP_CHECKSIG: 1 byte
+	TaprootMultiSigSize = 1 + 32 + 1 + 1 + 32 + 1
+
+	// TaprootFundingWitnessSize 234 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- sig2_length: 1 byte
+	//	- sig2: 64 bytes
+	//	- sig1_length: 1 byte
+	//	- sig1: 64 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (taproot multi-sig)
+	//	- control_block_length: 1 byte
+	//	- control_block: 33 bytes
+	TaprootFundingWitnessSize = 1 + 1 + 64 + 1 + 64 + 1 +
+		TaprootMultiSigSize + 1 + TaprootControlBlockSize
+
+	// TaprootCoopCloseWitnessSize 66 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- sig_length: 1 byte
+	//	- musig2 sig: 64 bytes
+	TaprootCoopCloseWitnessSize = 1 + 1 + 64
+
+	// TaprootWitnessCommitmentTxWeight 236 weight
+	TaprootWitnessCommitmentTxWeight = WitnessHeaderSize +
+		TaprootFundingWitnessSize
+
+	// TaprootCommitWeight 1136 weight
+	//
+	// The outputs of taproot commitments have the same size as the
+	// P2WSH outputs of anchor commitments.
+	TaprootCommitWeight = BaseAnchorCommitmentTxWeight +
+		TaprootWitnessCommitmentTxWeight
+
+	// TaprootDelayScriptSize 41 bytes
+	//	- OP_DATA: 1 byte
+	//	- delay_key: 32 bytes
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_DATA: 1 byte
+	//	- csv_delay: 4 bytes
+	//	- OP_CHECKSEQUENCEVERIFY: 1 byte
+	//	- OP_DROP: 1 byte
+	TaprootDelayScriptSize = 1 + 32 + 1 + 1 + 4 + 1 + 1
+
+	// TaprootRevokeScriptSize 68 bytes
+	//	- OP_DATA: 1 byte
+	//	- delay_key: 32 bytes
+	//	- OP_DROP: 1 byte
+	//	- OP_DATA: 1 byte
+	//	- revoke_key: 32 bytes
+	//	- OP_CHECKSIG: 1 byte
+	TaprootRevokeScriptSize = 1 + 32 + 1 + 1 + 32 + 1
+
+	// TaprootToLocalWitnessSize 175 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- delay_sig_length: 1 byte
+	//	- delay_sig: 65 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (delay script)
+	//	- control_block_length: 1 byte
+	//	- control_block: 65 bytes
+	TaprootToLocalWitnessSize = 1 + 1 + TaprootSigSize + 1 +
+		TaprootDelayScriptSize + 1 + TaprootTwoLeafControlBlockSize
+
+	// TaprootToLocalRevokeWitnessSize 202 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- revoke_sig_length: 1 byte
+	//	- revoke_sig: 65 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (revoke script)
+	//	- control_block_length: 1 byte
+	//	- control_block: 65 bytes
+	TaprootToLocalRevokeWitnessSize = 1 + 1 + TaprootSigSize + 1 +
+		TaprootRevokeScriptSize + 1 + TaprootTwoLeafControlBlockSize
+
+	// TaprootToRemoteScriptSize 37 bytes
+	//	- OP_DATA: 1 byte
+	//	- to_remote_key: 32 bytes
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_1: 1 byte
+	//	- OP_CHECKSEQUENCEVERIFY: 1 byte
+	//	- OP_DROP: 1 byte
+	TaprootToRemoteScriptSize = 1 + 32 + 1 + 1 + 1 + 1
+
+	// TaprootToRemoteWitnessSize 139 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- sig_length: 1 byte
+	//	- sig: 65 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (to_remote script)
+	//	- control_block_length: 1 byte
+	//	- control_block: 33 bytes
+	TaprootToRemoteWitnessSize = 1 + 1 + TaprootSigSize + 1 +
+		TaprootToRemoteScriptSize + 1 + TaprootControlBlockSize
+
+	// TaprootSecondLevelHtlcWitnessSize 143 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- delay_sig_length: 1 byte
+	//	- delay_sig: 65 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (delay script)
+	//	- control_block_length: 1 byte
+	//	- control_block: 33 bytes
+	TaprootSecondLevelHtlcWitnessSize = 1 + 1 + TaprootSigSize + 1 +
+		TaprootDelayScriptSize + 1 + TaprootControlBlockSize
+
+	// TaprootOfferedHtlcSuccessScriptSize 64 bytes
+	//	- OP_SIZE: 1 byte
+	//	- OP_DATA: 1 byte (32 length)
+	//	- 32: 1 byte
+	//	- OP_EQUALVERIFY: 1 byte
+	//	- OP_HASH160: 1 byte
+	//	- OP_DATA: 1 byte (RIPEMD160(payment_hash) length)
+	//	- RIPEMD160(payment_hash): 20 bytes
+	//	- OP_EQUALVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (remotekey length)
+	//	- remotekey: 32 bytes
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_1: 1 byte
+	//	- OP_CHECKSEQUENCEVERIFY: 1 byte
+	//	- OP_DROP: 1 byte
+	TaprootOfferedHtlcSuccessScriptSize = 5*1 + 1 + 20 + 1 + 1 + 32 + 4*1
+
+	// TaprootOfferedHtlcSuccessWitnessSize 231 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- receiver_sig_length: 1 byte
+	//	- receiver_sig: 65 bytes
+	//	- payment_preimage_length: 1 byte
+	//	- payment_preimage: 32 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (offered htlc success script)
+	//	- control_block_length: 1 byte
+	//	- control_block: 65 bytes
+	TaprootOfferedHtlcSuccessWitnessSize = 1 + 1 + TaprootSigSize + 1 + 32 +
+		1 + TaprootOfferedHtlcSuccessScriptSize + 1 +
+		TaprootTwoLeafControlBlockSize
+
+	// TaprootOfferedHtlcTimeoutWitnessSize 268 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- receiver_sig_length: 1 byte
+	//	- receiver_sig: 65 bytes
+	//	- sender_sig_length: 1 byte
+	//	- sender_sig: 65 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (offered htlc timeout script)
+	//	- control_block_length: 1 byte
+	//	- control_block: 65 bytes
+	//
+	// Input to second level timeout tx, spending the HTLC output.
+	TaprootOfferedHtlcTimeoutWitnessSize = 1 + 1 + TaprootSigSize + 1 +
+		TaprootSigSize + 1 + TaprootMultiSigSize + 1 +
+		TaprootTwoLeafControlBlockSize
+
+	// TaprootAcceptedHtlcSuccessScriptSize 95 bytes
+	//	- OP_SIZE: 1 byte
+	//	- OP_DATA: 1 byte (32 length)
+	//	- 32: 1 byte
+	//	- OP_EQUALVERIFY: 1 byte
+	//	- OP_HASH160: 1 byte
+	//	- OP_DATA: 1 byte (RIPEMD160(payment_hash) length)
+	//	- RIPEMD160(payment_hash): 20 bytes
+	//	- OP_EQUALVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (localkey length)
+	//	- localkey: 32 bytes
+	//	- OP_CHECKSIGVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (remotekey length)
+	//	- remotekey: 32 bytes
+	//	- OP_CHECKSIG: 1 byte
+	TaprootAcceptedHtlcSuccessScriptSize = 5*1 + 1 + 20 + 1 +
+		TaprootMultiSigSize
+
+	// TaprootAcceptedHtlcSuccessWitnessSize 328 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- sender_sig_length: 1 byte
+	//	- sender_sig: 65 bytes
+	//	- receiver_sig_length: 1 byte
+	//	- receiver_sig: 65 bytes
+	//	- payment_preimage_length: 1 byte
+	//	- payment_preimage: 32 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (accepted htlc success script)
+	//	- control_block_length: 1 byte
+	//	- control_block: 65 bytes
+	//
+	// Input to second level success tx, spending the HTLC output.
+	TaprootAcceptedHtlcSuccessWitnessSize = 1 + 1 + TaprootSigSize + 1 +
+		TaprootSigSize + 1 + 32 + 1 +
+		TaprootAcceptedHtlcSuccessScriptSize + 1 +
+		TaprootTwoLeafControlBlockSize
+
+	// TaprootAcceptedHtlcTimeoutScriptSize 44 bytes
+	//	- OP_DATA: 1 byte (remotekey length)
+	//	- remotekey: 32 bytes
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_1: 1 byte
+	//	- OP_CHECKSEQUENCEVERIFY: 1 byte
+	//	- OP_DROP: 1 byte
+	//	- OP_DATA: 1 byte (cltv_expiry length)
+	//	- cltv_expiry: 4 bytes
+	//	- OP_CHECKLOCKTIMEVERIFY: 1 byte
+	//	- OP_DROP: 1 byte
+	TaprootAcceptedHtlcTimeoutScriptSize = 1 + 32 + 4*1 + 1 + 4 + 2*1
+
+	// TaprootAcceptedHtlcTimeoutWitnessSize 178 bytes
+	//	- number_of_witness_elements: 1 byte
+	//	- sender_sig_length: 1 byte
+	//	- sender_sig: 65 bytes
+	//	- leaf_script_length: 1 byte
+	//	- leaf_script (accepted htlc timeout script)
+	//	- control_block_length: 1 byte
+	//	- control_block: 65 bytes
+	TaprootAcceptedHtlcTimeoutWitnessSize = 1 + 1 + TaprootSigSize + 1 +
+		TaprootAcceptedHtlcTimeoutScriptSize + 1 +
+		TaprootTwoLeafControlBlockSize
 )
 
 // EstimateCommitTxWeight estimate commitment transaction weight depending on
@@ -1,6 +1,7 @@
 package input_test
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 const (
@@ -45,6 +47,12 @@ var (
 
 	testPrivkey, _ = btcec.PrivKeyFromBytes(btcec.S256(), make([]byte, 32))
 
+	// testTaprootKey is a valid pubkey used in taproot script size
+	// calculation, as taproot outputs commit to a tweaked key.
+	_, testTaprootKey = btcec.PrivKeyFromBytes(
+		btcec.S256(), bytes.Repeat([]byte{1}, 32),
+	)
+
 	testTx = wire.NewMsgTx(2)
 
 	testOutPoint = wire.OutPoint{
@@ -334,6 +342,18 @@ func (s *maxDERSignature) Verify(_ []byte, _ *btcec.PublicKey) bool {
 	return true
 }
 
+type schnorrSignature struct{}
+
+func (s *schnorrSignature) Serialize() []byte {
+	// Schnorr signatures have a fixed length, excluding the optional
+	// sighash flag.
+	return make([]byte, schnorr.SignatureSize)
+}
+
+func (s *schnorrSignature) Verify(_ []byte, _ *btcec.PublicKey) bool {
+	return true
+}
+
 // dummySigner is a fake signer used for size (upper bound) calculations.
 type dummySigner struct {
 	input.Signer
@@ -344,9 +364,30 @@ type dummySigner struct {
 func (s *dummySigner) SignOutputRaw(tx *wire.MsgTx,
 	signDesc *input.SignDescriptor) (input.Signature, error) {
 
+	if signDesc.SignMethod.IsTaproot() {
+		return &schnorrSignature{}, nil
+	}
+
 	return &maxDERSignature{}, nil
 }
 
+// taprootSignDesc returns a sign descriptor spending the leaf of the tapscript
+// tree over the script path. The signature has a sighash flag, so that the
+// witness has the maximum size.
+func taprootSignDesc(t *testing.T, tree *input.TapscriptTree,
+	leaf int) *input.SignDescriptor {
+
+	controlBlock, err := tree.ControlBlock(leaf)
+	require.NoError(t, err)
+
+	return &input.SignDescriptor{
+		WitnessScript: tree.Leaves[leaf],
+		ControlBlock:  controlBlock,
+		SignMethod:    input.TaprootScriptSpendSignMethod,
+		HashType:      txscript.SigHashAll,
+	}
+}
+
 type witnessSizeTest struct {
 	name       string
 	expSize    int
@@ -834,6 +875,210 @@ var witnessSizeTests = []witnessSizeTest{
 				t.Fatal(err)
 			}
 
+			return witness
+		},
+	},
+	{
+		name:    "taproot funding",
+		expSize: input.TaprootFundingWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, _, err := input.TaprootFundingScript(
+				testTaprootKey, testTaprootKey,
+			)
+			require.NoError(t, err)
+
+			controlBlock, err := tree.ControlBlock(0)
+			require.NoError(t, err)
+
+			keyBytes := testTaprootKey.SerializeCompressed()
+
+			return input.TaprootSpendMultiSig(
+				tree.Leaves[0], controlBlock,
+				keyBytes, &schnorrSignature{},
+				keyBytes, &schnorrSignature{},
+			)
+		},
+	},
+	{
+		name:    "taproot key spend",
+		expSize: input.TaprootKeyPathWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			signDesc := &input.SignDescriptor{
+				SignMethod: input.TaprootKeySpendSignMethod,
+				HashType:   txscript.SigHashAll,
+			}
+
+			witness, err := input.TaprootKeySpend(
+				&dummySigner{}, signDesc, testTx,
+			)
+			require.NoError(t, err)
+
+			return witness
+		},
+	},
+	{
+		name:    "taproot to local timeout",
+		expSize: input.TaprootToLocalWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, err := input.TaprootCommitScriptToSelf(
+				testCSVDelay, testTaprootKey, testTaprootKey,
+			)
+			require.NoError(t, err)
+
+			signDesc := taprootSignDesc(
+				t, tree, input.TaprootDelayLeaf,
+			)
+			witness, err := input.TaprootCommitSpendTimeout(
+				&dummySigner{}, signDesc, testTx,
+			)
+			require.NoError(t, err)
+
+			return witness
+		},
+	},
+	{
+		name:    "taproot to local revoke",
+		expSize: input.TaprootToLocalRevokeWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, err := input.TaprootCommitScriptToSelf(
+				testCSVDelay, testTaprootKey, testTaprootKey,
+			)
+			require.NoError(t, err)
+
+			signDesc := taprootSignDesc(
+				t, tree, input.TaprootRevokeLeaf,
+			)
+			witness, err := input.TaprootCommitSpendRevoke(
+				&dummySigner{}, signDesc, testTx,
+			)
+			require.NoError(t, err)
+
+			return witness
+		},
+	},
+	{
+		name:    "taproot to remote",
+		expSize: input.TaprootToRemoteWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, err := input.TaprootCommitScriptToRemote(
+				testTaprootKey,
+			)
+			require.NoError(t, err)
+
+			signDesc := taprootSignDesc(t, tree, 0)
+			witness, err := input.TaprootCommitSpendToRemote(
+				&dummySigner{}, signDesc, testTx,
+			)
+			require.NoError(t, err)
+
+			return witness
+		},
+	},
+	{
+		name:    "taproot second level",
+		expSize: input.TaprootSecondLevelHtlcWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, err := input.TaprootSecondLevelHtlcScript(
+				testTaprootKey, testTaprootKey, testCSVDelay,
+			)
+			require.NoError(t, err)
+
+			signDesc := taprootSignDesc(t, tree, 0)
+			witness, err := input.TaprootCommitSpendTimeout(
+				&dummySigner{}, signDesc, testTx,
+			)
+			require.NoError(t, err)
+
+			return witness
+		},
+	},
+	{
+		name:    "taproot offered htlc timeout",
+		expSize: input.TaprootOfferedHtlcTimeoutWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, err := input.TaprootSenderHTLCScript(
+				testTaprootKey, testTaprootKey, testTaprootKey,
+				testHash160,
+			)
+			require.NoError(t, err)
+
+			signDesc := taprootSignDesc(
+				t, tree, input.TaprootTimeoutLeaf,
+			)
+			witness, err := input.TaprootSenderHtlcSpendTimeout(
+				&schnorrSignature{}, txscript.SigHashSingle|
+					txscript.SigHashAnyOneCanPay,
+				&dummySigner{}, signDesc, testTx,
+			)
+			require.NoError(t, err)
+
+			return witness
+		},
+	},
+	{
+		name:    "taproot offered htlc success",
+		expSize: input.TaprootOfferedHtlcSuccessWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, err := input.TaprootSenderHTLCScript(
+				testTaprootKey, testTaprootKey, testTaprootKey,
+				testHash160,
+			)
+			require.NoError(t, err)
+
+			signDesc := taprootSignDesc(
+				t, tree, input.TaprootSuccessLeaf,
+			)
+			witness, err := input.TaprootSenderHtlcSpendRedeem(
+				&dummySigner{}, signDesc, testTx,
+				testPreimage,
+			)
+			require.NoError(t, err)
+
+			return witness
+		},
+	},
+	{
+		name:    "taproot accepted htlc success",
+		expSize: input.TaprootAcceptedHtlcSuccessWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, err := input.TaprootReceiverHTLCScript(
+				testCLTVExpiry, testTaprootKey, testTaprootKey,
+				testTaprootKey, testHash160,
+			)
+			require.NoError(t, err)
+
+			signDesc := taprootSignDesc(
+				t, tree, input.TaprootSuccessLeaf,
+			)
+			witness, err := input.TaprootReceiverHtlcSpendRedeem(
+				&schnorrSignature{}, txscript.SigHashSingle|
+					txscript.SigHashAnyOneCanPay,
+				testPreimage, &dummySigner{}, signDesc, testTx,
+			)
+			require.NoError(t, err)
+
+			return witness
+		},
+	},
+	{
+		name:    "taproot accepted htlc timeout",
+		expSize: input.TaprootAcceptedHtlcTimeoutWitnessSize,
+		genWitness: func(t *testing.T) wire.TxWitness {
+			tree, err := input.TaprootReceiverHTLCScript(
+				testCLTVExpiry, testTaprootKey, testTaprootKey,
+				testTaprootKey, testHash160,
+			)
+			require.NoError(t, err)
+
+			signDesc := taprootSignDesc(
+				t, tree, input.TaprootTimeoutLeaf,
+			)
+			witness, err := input.TaprootReceiverHtlcSpendTimeout(
+				&dummySigner{}, signDesc, testTx,
+				testCLTVExpiry,
+			)
+			require.NoError(t, err)
+
 			return witness
 		},
 	},
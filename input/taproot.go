@@ -0,0 +1,636 @@
+package input
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/lightningnetwork/lnd/schnorr"
+)
+
+const (
+	// TapscriptLeafVersion is the leaf version of BIP-342 tapscripts,
+	// which is the only leaf version used by our scripts.
+	TapscriptLeafVersion = 0xc0
+
+	// SigHashDefault is the BIP-341 sighash type that commits to the whole
+	// transaction like SIGHASH_ALL, but doesn't append the sighash type to
+	// the signature.
+	SigHashDefault txscript.SigHashType = 0x00
+
+	// ControlBlockBaseSize is the size of a control block without any
+	// inclusion proof: the leaf version and output key parity byte,
+	// followed by the x-only internal key.
+	ControlBlockBaseSize = 1 + 32
+
+	// ControlBlockNodeSize is the size of a node of the inclusion proof
+	// of a control block.
+	ControlBlockNodeSize = 32
+
+	// maxTapscriptLeaves is the number of leaves that our tapscript trees
+	// support. None of our outputs have more than two spend paths besides
+	// the key path.
+	maxTapscriptLeaves = 2
+)
+
+var (
+	// TaprootNUMSKey is the x-only public key that is used as internal key
+	// of taproot outputs that mustn't be spendable over the key path. It
+	// is the hash of the uncompressed secp256k1 generator lifted to a
+	// point, so nobody knows its private key.
+	TaprootNUMSKey = mustParseXOnlyKey(
+		"50929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace" +
+			"803ac0",
+	)
+
+	// ErrUnsupportedSigHash is returned when a taproot signature is
+	// requested or found with a sighash type that isn't defined by
+	// BIP-341.
+	ErrUnsupportedSigHash = errors.New("unsupported taproot sighash type")
+)
+
+// mustParseXOnlyKey parses a hex encoded x-only public key, panicking if the
+// key is invalid.
+func mustParseXOnlyKey(keyHex string) *btcec.PublicKey {
+	keyBytes, err := hex.DecodeString(keyHex)
+	if err != nil {
+		panic(err)
+	}
+
+	key, err := schnorr.ParsePubKey(keyBytes)
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+// SignMethod defines how the input described by a SignDescriptor is signed.
+type SignMethod uint8
+
+const (
+	// WitnessV0SignMethod signs a segwit v0 input with an ECDSA signature
+	// over the BIP-143 sighash.
+	WitnessV0SignMethod SignMethod = 0
+
+	// TaprootKeySpendSignMethod signs a taproot input over the key path.
+	// The private key is tweaked with the tap tweak of the sign
+	// descriptor, which is the root hash of the output's tapscript tree.
+	TaprootKeySpendSignMethod SignMethod = 1
+
+	// TaprootScriptSpendSignMethod signs a taproot input over the script
+	// path. The witness script of the sign descriptor is the leaf script
+	// that is executed, and the private key is used untweaked.
+	TaprootScriptSpendSignMethod SignMethod = 2
+)
+
+// String returns a human readable version of the SignMethod.
+func (m SignMethod) String() string {
+	switch m {
+	case WitnessV0SignMethod:
+		return "WitnessV0SignMethod"
+
+	case TaprootKeySpendSignMethod:
+		return "TaprootKeySpendSignMethod"
+
+	case TaprootScriptSpendSignMethod:
+		return "TaprootScriptSpendSignMethod"
+
+	default:
+		return fmt.Sprintf("UnknownSignMethod(%d)", uint8(m))
+	}
+}
+
+// IsTaproot returns true if the sign method signs a taproot input.
+func (m SignMethod) IsTaproot() bool {
+	return m == TaprootKeySpendSignMethod ||
+		m == TaprootScriptSpendSignMethod
+}
+
+// TapLeafHash returns the BIP-341 hash of a tapscript leaf.
+func TapLeafHash(script []byte) [32]byte {
+	var b bytes.Buffer
+	b.WriteByte(TapscriptLeafVersion)
+	_ = wire.WriteVarBytes(&b, 0, script)
+
+	return schnorr.TaggedHash("TapLeaf", b.Bytes())
+}
+
+// TapBranchHash returns the BIP-341 hash of a branch of a tapscript tree,
+// which commits to the sorted hashes of its two children.
+func TapBranchHash(a, b [32]byte) [32]byte {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+
+	return schnorr.TaggedHash("TapBranch", a[:], b[:])
+}
+
+// TapTweakHash returns the tweak that is added to the internal key of a
+// taproot output to commit to the root hash of its tapscript tree. The root
+// hash is empty for outputs without scripts.
+func TapTweakHash(internalKey *btcec.PublicKey, rootHash []byte) [32]byte {
+	return schnorr.TaggedHash(
+		"TapTweak", schnorr.SerializePubKey(internalKey), rootHash,
+	)
+}
+
+// ComputeTaprootOutputKey returns the output key of a taproot output with the
+// passed internal key and tapscript root hash.
+func ComputeTaprootOutputKey(internalKey *btcec.PublicKey,
+	rootHash []byte) (*btcec.PublicKey, error) {
+
+	// Only the x coordinate of the internal key is committed to, so we
+	// tweak the point with an even y coordinate.
+	evenKey, err := schnorr.ParsePubKey(
+		schnorr.SerializePubKey(internalKey),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tweak := TapTweakHash(evenKey, rootHash)
+	if new(secp.ModNScalar).SetBytes(&tweak) != 0 {
+		return nil, errors.New("tap tweak exceeds the curve order")
+	}
+
+	tweakX, tweakY := btcec.S256().ScalarBaseMult(tweak[:])
+	x, y := btcec.S256().Add(evenKey.X, evenKey.Y, tweakX, tweakY)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("taproot output key is infinite")
+	}
+
+	return &btcec.PublicKey{Curve: btcec.S256(), X: x, Y: y}, nil
+}
+
+// TweakTaprootPrivKey returns the private key that spends a taproot output
+// over the key path, given the private key of the internal key and the
+// tapscript root hash of the output.
+func TweakTaprootPrivKey(privKey *btcec.PrivateKey,
+	rootHash []byte) *btcec.PrivateKey {
+
+	var d secp.ModNScalar
+	d.SetByteSlice(privKey.Serialize())
+
+	// The internal key is used with an even y coordinate, so the private
+	// key is negated if its public key is odd.
+	if privKey.PubKey().SerializeCompressed()[0] ==
+		secp.PubKeyFormatCompressedOdd {
+
+		d.Negate()
+	}
+
+	tweak := TapTweakHash(privKey.PubKey(), rootHash)
+
+	var t secp.ModNScalar
+	t.SetBytes(&tweak)
+	d.Add(&t)
+
+	tweaked := d.Bytes()
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), tweaked[:])
+
+	return priv
+}
+
+// PayToTaprootScript returns the pkScript of a taproot output with the passed
+// output key.
+func PayToTaprootScript(outputKey *btcec.PublicKey) ([]byte, error) {
+	bldr := txscript.NewScriptBuilder()
+
+	bldr.AddOp(txscript.OP_1)
+	bldr.AddData(schnorr.SerializePubKey(outputKey))
+	return bldr.Script()
+}
+
+// IsPayToTaproot returns true if the pkScript is a taproot output.
+func IsPayToTaproot(pkScript []byte) bool {
+	return len(pkScript) == 34 && pkScript[0] == txscript.OP_1 &&
+		pkScript[1] == txscript.OP_DATA_32
+}
+
+// TapscriptTree is a taproot output that commits to an internal key and a
+// tree of up to two tapscript leaves.
+type TapscriptTree struct {
+	// InternalKey is the internal key of the output. It can spend the
+	// output over the key path, once tweaked with the root hash.
+	InternalKey *btcec.PublicKey
+
+	// Leaves are the leaf scripts of the tree.
+	Leaves [][]byte
+
+	// RootHash is the root hash of the tree, or empty if there are no
+	// leaves.
+	RootHash []byte
+
+	// OutputKey is the tweaked output key.
+	OutputKey *btcec.PublicKey
+
+	// PkScript is the pkScript of the taproot output.
+	PkScript []byte
+}
+
+// NewTapscriptTree creates the taproot output for the passed internal key and
+// leaf scripts. At most two leaves are supported, so that every leaf is at
+// depth one.
+func NewTapscriptTree(internalKey *btcec.PublicKey,
+	leaves ...[]byte) (*TapscriptTree, error) {
+
+	if len(leaves) > maxTapscriptLeaves {
+		return nil, fmt.Errorf("%d tapscript leaves exceed the "+
+			"maximum of %d", len(leaves), maxTapscriptLeaves)
+	}
+
+	var rootHash []byte
+	switch len(leaves) {
+	case 1:
+		hash := TapLeafHash(leaves[0])
+		rootHash = hash[:]
+
+	case 2:
+		hash := TapBranchHash(
+			TapLeafHash(leaves[0]), TapLeafHash(leaves[1]),
+		)
+		rootHash = hash[:]
+	}
+
+	outputKey, err := ComputeTaprootOutputKey(internalKey, rootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	pkScript, err := PayToTaprootScript(outputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TapscriptTree{
+		InternalKey: internalKey,
+		Leaves:      leaves,
+		RootHash:    rootHash,
+		OutputKey:   outputKey,
+		PkScript:    pkScript,
+	}, nil
+}
+
+// ControlBlock returns the control block that proves the inclusion of the
+// leaf with the passed index in the output.
+func (t *TapscriptTree) ControlBlock(leaf int) ([]byte, error) {
+	if leaf < 0 || leaf >= len(t.Leaves) {
+		return nil, fmt.Errorf("unknown tapscript leaf %d", leaf)
+	}
+
+	// The first byte commits to the leaf version and the parity of the
+	// output key.
+	ctrlBlock := []byte{TapscriptLeafVersion}
+	if t.OutputKey.SerializeCompressed()[0] ==
+		secp.PubKeyFormatCompressedOdd {
+
+		ctrlBlock[0] |= 1
+	}
+	ctrlBlock = append(
+		ctrlBlock, schnorr.SerializePubKey(t.InternalKey)...,
+	)
+
+	// With two leaves, the inclusion proof of a leaf is the hash of its
+	// sibling.
+	if len(t.Leaves) == 2 {
+		sibling := TapLeafHash(t.Leaves[1-leaf])
+		ctrlBlock = append(ctrlBlock, sibling[:]...)
+	}
+
+	return ctrlBlock, nil
+}
+
+// SetScriptSpend prepares the sign descriptor to spend the output over the
+// script path of the leaf with the passed index. The signature commits to all
+// outputs of the transaction, unless the caller overrides the sighash type.
+func (t *TapscriptTree) SetScriptSpend(signDesc *SignDescriptor,
+	leaf int) error {
+
+	ctrlBlock, err := t.ControlBlock(leaf)
+	if err != nil {
+		return err
+	}
+
+	signDesc.SignMethod = TaprootScriptSpendSignMethod
+	signDesc.WitnessScript = t.Leaves[leaf]
+	signDesc.ControlBlock = ctrlBlock
+	signDesc.TapTweak = nil
+	signDesc.HashType = SigHashDefault
+
+	return nil
+}
+
+// SetKeySpend prepares the sign descriptor to spend the output over the key
+// path with the internal key of the tree.
+func (t *TapscriptTree) SetKeySpend(signDesc *SignDescriptor) {
+	signDesc.SignMethod = TaprootKeySpendSignMethod
+	signDesc.WitnessScript = nil
+	signDesc.ControlBlock = nil
+	signDesc.TapTweak = t.RootHash
+	signDesc.HashType = SigHashDefault
+}
+
+// PrevOutputFetcher returns the outputs spent by the inputs of a transaction.
+// Unlike segwit v0 signatures, taproot signatures commit to the amounts and
+// scripts of all of them.
+type PrevOutputFetcher interface {
+	// FetchPrevOutput returns the output spent by the outpoint, or nil if
+	// the output is unknown.
+	FetchPrevOutput(op wire.OutPoint) *wire.TxOut
+}
+
+// MultiPrevOutFetcher is a PrevOutputFetcher backed by a map of the spent
+// outputs.
+type MultiPrevOutFetcher map[wire.OutPoint]*wire.TxOut
+
+// A compile time check to ensure MultiPrevOutFetcher implements the
+// PrevOutputFetcher interface.
+var _ PrevOutputFetcher = (MultiPrevOutFetcher)(nil)
+
+// FetchPrevOutput returns the output spent by the outpoint, or nil if the
+// output is unknown.
+//
+// NOTE: This is part of the PrevOutputFetcher interface.
+func (m MultiPrevOutFetcher) FetchPrevOutput(op wire.OutPoint) *wire.TxOut {
+	return m[op]
+}
+
+// SetPrevOutputFetcher sets the PrevOutputFetcher of the sign descriptors of
+// all the passed inputs to the outputs they spend, so that taproot inputs
+// among them can be signed when they are swept in the same transaction.
+func SetPrevOutputFetcher(inputs ...Input) {
+	prevOuts := make(MultiPrevOutFetcher, len(inputs))
+	for _, inp := range inputs {
+		prevOuts[*inp.OutPoint()] = inp.SignDesc().Output
+	}
+
+	for _, inp := range inputs {
+		inp.SignDesc().PrevOutputFetcher = prevOuts
+	}
+}
+
+// singlePrevOutFetcher is a PrevOutputFetcher of a transaction that only has
+// a single input.
+type singlePrevOutFetcher struct {
+	txOut *wire.TxOut
+}
+
+// FetchPrevOutput returns the spent output for any outpoint.
+//
+// NOTE: This is part of the PrevOutputFetcher interface.
+func (s *singlePrevOutFetcher) FetchPrevOutput(wire.OutPoint) *wire.TxOut {
+	return s.txOut
+}
+
+// prevOutputFetcher returns the PrevOutputFetcher of the sign descriptor. If
+// none is set, a transaction with a single input can still be signed, as the
+// output of the sign descriptor is the only spent output.
+func prevOutputFetcher(tx *wire.MsgTx,
+	signDesc *SignDescriptor) (PrevOutputFetcher, error) {
+
+	switch {
+	case signDesc.PrevOutputFetcher != nil:
+		return signDesc.PrevOutputFetcher, nil
+
+	case len(tx.TxIn) == 1:
+		return &singlePrevOutFetcher{txOut: signDesc.Output}, nil
+
+	default:
+		return nil, errors.New("sign descriptor of a taproot input " +
+			"has no previous outputs")
+	}
+}
+
+// TaprootSigHash computes the BIP-341 sighash of the input with the passed
+// index. The leaf script is nil for key path spends, and the executed
+// tapscript leaf for script path spends.
+func TaprootSigHash(tx *wire.MsgTx, prevOuts PrevOutputFetcher, idx int,
+	hashType txscript.SigHashType, leafScript []byte) ([]byte, error) {
+
+	switch hashType {
+	case SigHashDefault, txscript.SigHashAll, txscript.SigHashNone,
+		txscript.SigHashSingle,
+		txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+		txscript.SigHashNone | txscript.SigHashAnyOneCanPay,
+		txscript.SigHashSingle | txscript.SigHashAnyOneCanPay:
+
+	default:
+		return nil, ErrUnsupportedSigHash
+	}
+
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, fmt.Errorf("input index %d out of range", idx)
+	}
+
+	spent := make([]*wire.TxOut, len(tx.TxIn))
+	for i, txIn := range tx.TxIn {
+		spent[i] = prevOuts.FetchPrevOutput(txIn.PreviousOutPoint)
+		if spent[i] == nil {
+			return nil, fmt.Errorf("spent output of input %d is "+
+				"unknown", i)
+		}
+	}
+
+	anyoneCanPay := hashType&txscript.SigHashAnyOneCanPay != 0
+	outputType := hashType & 0x03
+
+	var (
+		msg     bytes.Buffer
+		scratch [8]byte
+	)
+	writeUint32 := func(w *bytes.Buffer, v uint32) {
+		binary.LittleEndian.PutUint32(scratch[:4], v)
+		w.Write(scratch[:4])
+	}
+	writeUint64 := func(w *bytes.Buffer, v uint64) {
+		binary.LittleEndian.PutUint64(scratch[:], v)
+		w.Write(scratch[:])
+	}
+	writeOutPoint := func(w *bytes.Buffer, op wire.OutPoint) {
+		w.Write(op.Hash[:])
+		writeUint32(w, op.Index)
+	}
+	sha := func(b *bytes.Buffer) []byte {
+		hash := sha256.Sum256(b.Bytes())
+		return hash[:]
+	}
+
+	// The sighash epoch, the sighash type and the transaction fields
+	// come first.
+	msg.WriteByte(0x00)
+	msg.WriteByte(byte(hashType))
+	writeUint32(&msg, uint32(tx.Version))
+	writeUint32(&msg, tx.LockTime)
+
+	if !anyoneCanPay {
+		var prevOutPoints, amounts, scripts, sequences bytes.Buffer
+		for i, txIn := range tx.TxIn {
+			writeOutPoint(&prevOutPoints, txIn.PreviousOutPoint)
+			writeUint64(&amounts, uint64(spent[i].Value))
+			err := wire.WriteVarBytes(
+				&scripts, 0, spent[i].PkScript,
+			)
+			if err != nil {
+				return nil, err
+			}
+			writeUint32(&sequences, txIn.Sequence)
+		}
+
+		msg.Write(sha(&prevOutPoints))
+		msg.Write(sha(&amounts))
+		msg.Write(sha(&scripts))
+		msg.Write(sha(&sequences))
+	}
+
+	if outputType != txscript.SigHashNone &&
+		outputType != txscript.SigHashSingle {
+
+		var outputs bytes.Buffer
+		for _, txOut := range tx.TxOut {
+			err := wire.WriteTxOut(&outputs, 0, 0, txOut)
+			if err != nil {
+				return nil, err
+			}
+		}
+		msg.Write(sha(&outputs))
+	}
+
+	// The spend type commits to whether this is a script path spend.
+	// Annexes aren't supported.
+	var spendType byte
+	if leafScript != nil {
+		spendType = 2
+	}
+	msg.WriteByte(spendType)
+
+	if anyoneCanPay {
+		txIn := tx.TxIn[idx]
+		writeOutPoint(&msg, txIn.PreviousOutPoint)
+		writeUint64(&msg, uint64(spent[idx].Value))
+		err := wire.WriteVarBytes(&msg, 0, spent[idx].PkScript)
+		if err != nil {
+			return nil, err
+		}
+		writeUint32(&msg, txIn.Sequence)
+	} else {
+		writeUint32(&msg, uint32(idx))
+	}
+
+	if outputType == txscript.SigHashSingle {
+		if idx >= len(tx.TxOut) {
+			return nil, fmt.Errorf("no output for SIGHASH_SINGLE "+
+				"input %d", idx)
+		}
+
+		var output bytes.Buffer
+		err := wire.WriteTxOut(&output, 0, 0, tx.TxOut[idx])
+		if err != nil {
+			return nil, err
+		}
+		msg.Write(sha(&output))
+	}
+
+	// Script path spends additionally commit to the leaf, the key
+	// version and the position of the last executed OP_CODESEPARATOR,
+	// which our scripts don't use.
+	if leafScript != nil {
+		leafHash := TapLeafHash(leafScript)
+		msg.Write(leafHash[:])
+		msg.WriteByte(0x00)
+		writeUint32(&msg, 0xffffffff)
+	}
+
+	sigHash := schnorr.TaggedHash("TapSighash", msg.Bytes())
+
+	return sigHash[:], nil
+}
+
+// SignTaproot creates the BIP-340 signature of the taproot input described by
+// the sign descriptor. The private key must already include the single or
+// double tweak of the sign descriptor, while the taproot tweak of key path
+// spends is applied here. This is the taproot counterpart of
+// txscript.RawTxInWitnessSignature for the Signer implementations.
+func SignTaproot(tx *wire.MsgTx, signDesc *SignDescriptor,
+	privKey *btcec.PrivateKey) (Signature, error) {
+
+	prevOuts, err := prevOutputFetcher(tx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	var leafScript []byte
+	switch signDesc.SignMethod {
+	case TaprootKeySpendSignMethod:
+		privKey = TweakTaprootPrivKey(privKey, signDesc.TapTweak)
+
+	case TaprootScriptSpendSignMethod:
+		leafScript = signDesc.WitnessScript
+		if len(leafScript) == 0 {
+			return nil, errors.New("script path spend without " +
+				"leaf script")
+		}
+
+	default:
+		return nil, fmt.Errorf("%v isn't a taproot sign method",
+			signDesc.SignMethod)
+	}
+
+	sigHash, err := TaprootSigHash(
+		tx, prevOuts, signDesc.InputIndex, signDesc.HashType,
+		leafScript,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var digest [32]byte
+	copy(digest[:], sigHash)
+
+	sig, err := schnorr.Sign(privKey, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// TaprootWitnessSig returns the witness encoding of a taproot signature: the
+// sighash type is appended unless it is the default one.
+func TaprootWitnessSig(sig Signature, hashType txscript.SigHashType) []byte {
+	sigBytes := sig.Serialize()
+	if hashType == SigHashDefault {
+		return sigBytes
+	}
+
+	return append(sigBytes, byte(hashType))
+}
+
+// ParseTaprootWitnessSig parses a taproot signature from a witness element,
+// returning the signature and its sighash type.
+func ParseTaprootWitnessSig(sig []byte) (schnorr.Signature,
+	txscript.SigHashType, error) {
+
+	switch {
+	case len(sig) == schnorr.SignatureSize:
+		s, err := schnorr.ParseSignature(sig)
+		return s, SigHashDefault, err
+
+	case len(sig) == schnorr.SignatureSize+1 &&
+		sig[schnorr.SignatureSize] != byte(SigHashDefault):
+
+		s, err := schnorr.ParseSignature(sig[:schnorr.SignatureSize])
+		return s, txscript.SigHashType(sig[schnorr.SignatureSize]), err
+
+	default:
+		return schnorr.Signature{}, 0, fmt.Errorf("invalid taproot "+
+			"signature length %d", len(sig))
+	}
+}
@@ -0,0 +1,559 @@
+package input
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/schnorr"
+)
+
+const (
+	// TaprootDelayLeaf is the index of the leaf of a to_local or
+	// second-level HTLC output that pays to the owner after the CSV delay.
+	TaprootDelayLeaf = 0
+
+	// TaprootRevokeLeaf is the index of the leaf of a to_local output that
+	// pays to the revocation key.
+	TaprootRevokeLeaf = 1
+
+	// TaprootSuccessLeaf is the index of the leaf of an HTLC output that
+	// is spent with the payment preimage.
+	TaprootSuccessLeaf = 0
+
+	// TaprootTimeoutLeaf is the index of the leaf of an HTLC output that
+	// is spent once the HTLC timed out.
+	TaprootTimeoutLeaf = 1
+)
+
+// TaprootFundingScript creates the taproot funding output of a channel with
+// the passed funding keys. The internal key is the MuSig2 aggregate of both
+// keys, which is used to cooperatively close the channel over the key path.
+// Commitment transactions spend the 2-of-2 leaf, so that they can be signed
+// without exchanging nonces.
+//
+// Possible Input Scripts:
+//     COOP CLOSE: <musig2 sig>
+//     COMMITMENT: <sig of key 2> <sig of key 1>
+//
+// Leaf Script:
+//     <key 1> OP_CHECKSIGVERIFY <key 2> OP_CHECKSIG
+//
+// The key aggregation context returned is tweaked with the root hash of the
+// tree, so that it signs for the output key.
+func TaprootFundingScript(aPub, bPub *btcec.PublicKey) (*TapscriptTree,
+	*schnorr.KeyAggContext, error) {
+
+	keyAgg, err := schnorr.AggregateKeys(aPub, bPub)
+	if err != nil {
+		return nil, nil, err
+	}
+	internalKey := keyAgg.PubKey()
+
+	// The keys within the leaf are sorted like the keys of the segwit v0
+	// multi-sig script.
+	keys := schnorr.SortKeys([]*btcec.PublicKey{aPub, bPub})
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(schnorr.SerializePubKey(keys[0]))
+	builder.AddOp(txscript.OP_CHECKSIGVERIFY)
+	builder.AddData(schnorr.SerializePubKey(keys[1]))
+	builder.AddOp(txscript.OP_CHECKSIG)
+
+	leaf, err := builder.Script()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tree, err := NewTapscriptTree(internalKey, leaf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tweak := TapTweakHash(internalKey, tree.RootHash)
+	if err := keyAgg.ApplyTweak(tweak, true); err != nil {
+		return nil, nil, err
+	}
+
+	if !keyAgg.PubKey().IsEqual(tree.OutputKey) {
+		return nil, nil, errors.New("musig2 key doesn't match the " +
+			"funding output key")
+	}
+
+	return tree, keyAgg, nil
+}
+
+// GenTaprootFundingPkScript creates the taproot funding output of a channel
+// with the passed serialized funding keys and amount.
+func GenTaprootFundingPkScript(aPub, bPub []byte, amt int64) (*TapscriptTree,
+	*wire.TxOut, error) {
+
+	// As a sanity check, ensure that the passed amount is above zero.
+	if amt <= 0 {
+		return nil, nil, fmt.Errorf("can't create FundTx script with " +
+			"zero, or negative coins")
+	}
+
+	aKey, err := btcec.ParsePubKey(aPub, btcec.S256())
+	if err != nil {
+		return nil, nil, err
+	}
+	bKey, err := btcec.ParsePubKey(bPub, btcec.S256())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tree, _, err := TaprootFundingScript(aKey, bKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tree, wire.NewTxOut(amt, tree.PkScript), nil
+}
+
+// TaprootSpendMultiSig generates the witness that spends the 2-of-2 leaf of a
+// taproot funding output with the signatures of both funding keys.
+func TaprootSpendMultiSig(leafScript, controlBlock, pubA []byte,
+	sigA Signature, pubB []byte, sigB Signature) wire.TxWitness {
+
+	// The signature of the first key of the leaf must be on top of the
+	// stack, so it comes last.
+	witness := make(wire.TxWitness, 4)
+	if bytes.Compare(pubA, pubB) == 1 {
+		witness[0] = sigA.Serialize()
+		witness[1] = sigB.Serialize()
+	} else {
+		witness[0] = sigB.Serialize()
+		witness[1] = sigA.Serialize()
+	}
+	witness[2] = leafScript
+	witness[3] = controlBlock
+
+	return witness
+}
+
+// TaprootCommitScriptToSelf constructs the taproot output on the commitment
+// transaction paying to the owner of said commitment transaction. The internal
+// key is the NUMS key, so the output can only be spent over the script path.
+//
+// Possible Input Scripts:
+//     DELAY:  <delay sig>
+//     REVOKE: <revoke sig>
+//
+// Leaf Scripts:
+//     <delay key> OP_CHECKSIG <numRelativeBlocks> OP_CHECKSEQUENCEVERIFY
+//     OP_DROP
+//     <delay key> OP_DROP <revoke key> OP_CHECKSIG
+//
+// The delay key is part of the revocation leaf, so that a revocation spend
+// reveals it, as it is needed to reconstruct the tapscript tree of the output.
+func TaprootCommitScriptToSelf(csvTimeout uint32,
+	selfKey, revokeKey *btcec.PublicKey) (*TapscriptTree, error) {
+
+	delayLeaf, err := taprootDelayScript(selfKey, csvTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(schnorr.SerializePubKey(selfKey))
+	builder.AddOp(txscript.OP_DROP)
+	builder.AddData(schnorr.SerializePubKey(revokeKey))
+	builder.AddOp(txscript.OP_CHECKSIG)
+
+	revokeLeaf, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTapscriptTree(TaprootNUMSKey, delayLeaf, revokeLeaf)
+}
+
+// taprootDelayScript returns the leaf script that pays to the key after the
+// CSV delay.
+func taprootDelayScript(key *btcec.PublicKey, csvDelay uint32) ([]byte,
+	error) {
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(schnorr.SerializePubKey(key))
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddInt64(int64(csvDelay))
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_DROP)
+
+	return builder.Script()
+}
+
+// TaprootCommitScriptToRemote constructs the taproot output on the commitment
+// transaction paying to the remote party of said commitment transaction. The
+// money can only be spent after one confirmation.
+//
+// Possible Input Scripts:
+//     SWEEP: <sig>
+//
+// Leaf Script:
+//     <key> OP_CHECKSIG 1 OP_CHECKSEQUENCEVERIFY OP_DROP
+func TaprootCommitScriptToRemote(key *btcec.PublicKey) (*TapscriptTree,
+	error) {
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(schnorr.SerializePubKey(key))
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_1)
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_DROP)
+
+	leaf, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTapscriptTree(TaprootNUMSKey, leaf)
+}
+
+// TaprootCommitScriptAnchor constructs the taproot anchor output, which the
+// owner of the key can spend immediately over the key path, and anyone can
+// spend after 16 confirmations.
+//
+// Possible Input Scripts:
+//     By owner:                  <sig> (key path)
+//     By anyone (after 16 conf): <emptyvector>
+//
+// Leaf Script:
+//     OP_16 OP_CHECKSEQUENCEVERIFY
+func TaprootCommitScriptAnchor(key *btcec.PublicKey) (*TapscriptTree, error) {
+	builder := txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_16)
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+
+	leaf, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTapscriptTree(key, leaf)
+}
+
+// TaprootSenderHTLCScript constructs the taproot output of an HTLC offered by
+// the owner of the commitment transaction. The internal key is the revocation
+// key, so the receiver can sweep a revoked HTLC over the key path.
+//
+// Possible Input Scripts:
+//     SUCCESS: <recvr sig> <preimage>
+//     TIMEOUT: <recvr sig> <sender sig> (HTLC timeout transaction)
+//     REVOKE:  <revoke sig> (key path)
+//
+// Leaf Scripts:
+//     OP_SIZE 32 OP_EQUALVERIFY OP_HASH160 <ripemd160(payment hash)>
+//     OP_EQUALVERIFY <recvr htlc key> OP_CHECKSIG
+//     1 OP_CHECKSEQUENCEVERIFY OP_DROP
+//
+//     <sender htlc key> OP_CHECKSIGVERIFY <recvr htlc key> OP_CHECKSIG
+func TaprootSenderHTLCScript(senderHtlcKey, receiverHtlcKey,
+	revocationKey *btcec.PublicKey, paymentHash []byte) (*TapscriptTree,
+	error) {
+
+	builder := txscript.NewScriptBuilder()
+	addPreimageCheck(builder, paymentHash)
+	builder.AddData(schnorr.SerializePubKey(receiverHtlcKey))
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_1)
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_DROP)
+
+	successLeaf, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	builder = txscript.NewScriptBuilder()
+	builder.AddData(schnorr.SerializePubKey(senderHtlcKey))
+	builder.AddOp(txscript.OP_CHECKSIGVERIFY)
+	builder.AddData(schnorr.SerializePubKey(receiverHtlcKey))
+	builder.AddOp(txscript.OP_CHECKSIG)
+
+	timeoutLeaf, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTapscriptTree(revocationKey, successLeaf, timeoutLeaf)
+}
+
+// TaprootReceiverHTLCScript constructs the taproot output of an HTLC offered
+// to the owner of the commitment transaction. The internal key is the
+// revocation key, so the sender can sweep a revoked HTLC over the key path.
+//
+// Possible Input Scripts:
+//     SUCCESS: <sender sig> <recvr sig> <preimage> (HTLC success transaction)
+//     TIMEOUT: <sender sig>
+//     REVOKE:  <revoke sig> (key path)
+//
+// Leaf Scripts:
+//     OP_SIZE 32 OP_EQUALVERIFY OP_HASH160 <ripemd160(payment hash)>
+//     OP_EQUALVERIFY <recvr htlc key> OP_CHECKSIGVERIFY
+//     <sender htlc key> OP_CHECKSIG
+//
+//     <sender htlc key> OP_CHECKSIG 1 OP_CHECKSEQUENCEVERIFY OP_DROP
+//     <cltv expiry> OP_CHECKLOCKTIMEVERIFY OP_DROP
+func TaprootReceiverHTLCScript(cltvExpiry uint32, senderHtlcKey,
+	receiverHtlcKey, revocationKey *btcec.PublicKey,
+	paymentHash []byte) (*TapscriptTree, error) {
+
+	builder := txscript.NewScriptBuilder()
+	addPreimageCheck(builder, paymentHash)
+	builder.AddData(schnorr.SerializePubKey(receiverHtlcKey))
+	builder.AddOp(txscript.OP_CHECKSIGVERIFY)
+	builder.AddData(schnorr.SerializePubKey(senderHtlcKey))
+	builder.AddOp(txscript.OP_CHECKSIG)
+
+	successLeaf, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	builder = txscript.NewScriptBuilder()
+	builder.AddData(schnorr.SerializePubKey(senderHtlcKey))
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_1)
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_DROP)
+	builder.AddInt64(int64(cltvExpiry))
+	builder.AddOp(txscript.OP_CHECKLOCKTIMEVERIFY)
+	builder.AddOp(txscript.OP_DROP)
+
+	timeoutLeaf, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTapscriptTree(revocationKey, successLeaf, timeoutLeaf)
+}
+
+// addPreimageCheck adds the operations that verify that the top item of the
+// stack is the preimage of the payment hash.
+func addPreimageCheck(builder *txscript.ScriptBuilder, paymentHash []byte) {
+	builder.AddOp(txscript.OP_SIZE)
+	builder.AddInt64(32)
+	builder.AddOp(txscript.OP_EQUALVERIFY)
+	builder.AddOp(txscript.OP_HASH160)
+	builder.AddData(Ripemd160H(paymentHash))
+	builder.AddOp(txscript.OP_EQUALVERIFY)
+}
+
+// TaprootSecondLevelHtlcScript constructs the taproot output of the
+// second-level HTLC transactions. The internal key is the revocation key.
+//
+// Possible Input Scripts:
+//     DELAY:  <delay sig>
+//     REVOKE: <revoke sig> (key path)
+//
+// Leaf Script:
+//     <delay key> OP_CHECKSIG <delay in blocks> OP_CHECKSEQUENCEVERIFY OP_DROP
+func TaprootSecondLevelHtlcScript(revocationKey, delayKey *btcec.PublicKey,
+	csvDelay uint32) (*TapscriptTree, error) {
+
+	leaf, err := taprootDelayScript(delayKey, csvDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTapscriptTree(revocationKey, leaf)
+}
+
+// taprootScriptSpend signs the taproot input described by the sign descriptor
+// over the script path.
+func taprootScriptSpend(signer Signer, signDesc *SignDescriptor,
+	tx *wire.MsgTx) (Signature, error) {
+
+	if signDesc.SignMethod != TaprootScriptSpendSignMethod {
+		return nil, fmt.Errorf("script path spend with sign method "+
+			"%v", signDesc.SignMethod)
+	}
+	if len(signDesc.ControlBlock) < ControlBlockBaseSize {
+		return nil, errors.New("script path spend without control " +
+			"block")
+	}
+
+	return signer.SignOutputRaw(tx, signDesc)
+}
+
+// taprootScriptWitness returns the witness of a script path spend with the
+// passed stack items, followed by the leaf script and control block of the
+// sign descriptor.
+func taprootScriptWitness(signDesc *SignDescriptor,
+	stack ...[]byte) wire.TxWitness {
+
+	witness := make(wire.TxWitness, 0, len(stack)+2)
+	witness = append(witness, stack...)
+
+	return append(witness, signDesc.WitnessScript, signDesc.ControlBlock)
+}
+
+// TaprootKeySpend constructs the witness that spends a taproot output over the
+// key path. This is used to sweep anchors, and by the receiver of revoked HTLC
+// and second-level HTLC outputs.
+//
+// NOTE: The sign descriptor must have the root hash of the output as tap
+// tweak.
+func TaprootKeySpend(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	if signDesc.SignMethod != TaprootKeySpendSignMethod {
+		return nil, fmt.Errorf("key path spend with sign method %v",
+			signDesc.SignMethod)
+	}
+
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.TxWitness{
+		TaprootWitnessSig(sweepSig, signDesc.HashType),
+	}, nil
+}
+
+// TaprootCommitSpendTimeout constructs the witness allowing the owner of a
+// commitment transaction to sweep their taproot to_local output after the CSV
+// delay. The same witness spends a second-level HTLC output.
+func TaprootCommitSpendTimeout(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	// Ensure the transaction version supports the validation of sequence
+	// locks and CSV semantics.
+	if sweepTx.Version < 2 {
+		return nil, fmt.Errorf("version of passed transaction MUST "+
+			"be >= 2, not %v", sweepTx.Version)
+	}
+
+	sweepSig, err := taprootScriptSpend(signer, signDesc, sweepTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taprootScriptWitness(
+		signDesc, TaprootWitnessSig(sweepSig, signDesc.HashType),
+	), nil
+}
+
+// TaprootCommitSpendRevoke constructs the witness allowing a node to sweep the
+// taproot to_local output of a counterparty who broadcast a revoked commitment
+// transaction.
+func TaprootCommitSpendRevoke(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	sweepSig, err := taprootScriptSpend(signer, signDesc, sweepTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taprootScriptWitness(
+		signDesc, TaprootWitnessSig(sweepSig, signDesc.HashType),
+	), nil
+}
+
+// TaprootCommitSpendToRemote constructs the witness allowing a node to sweep
+// their taproot to_remote output on the counterparty's commitment transaction
+// once it has one confirmation.
+func TaprootCommitSpendToRemote(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	sweepSig, err := taprootScriptSpend(signer, signDesc, sweepTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taprootScriptWitness(
+		signDesc, TaprootWitnessSig(sweepSig, signDesc.HashType),
+	), nil
+}
+
+// TaprootCommitSpendAnchorAnyone constructs the witness allowing anyone to
+// spend a taproot anchor output after it has gotten 16 confirmations.
+func TaprootCommitSpendAnchorAnyone(leafScript,
+	controlBlock []byte) wire.TxWitness {
+
+	return wire.TxWitness{leafScript, controlBlock}
+}
+
+// TaprootSenderHtlcSpendTimeout constructs the witness allowing the sender of
+// an HTLC to spend the taproot HTLC output with the pre-signed HTLC timeout
+// transaction.
+func TaprootSenderHtlcSpendTimeout(receiverSig Signature,
+	receiverSigHash txscript.SigHashType, signer Signer,
+	signDesc *SignDescriptor, htlcTimeoutTx *wire.MsgTx) (
+	wire.TxWitness, error) {
+
+	sweepSig, err := taprootScriptSpend(signer, signDesc, htlcTimeoutTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taprootScriptWitness(
+		signDesc, TaprootWitnessSig(receiverSig, receiverSigHash),
+		TaprootWitnessSig(sweepSig, signDesc.HashType),
+	), nil
+}
+
+// TaprootSenderHtlcSpendRedeem constructs the witness allowing the receiver of
+// an HTLC to sweep the taproot HTLC output on the sender's commitment
+// transaction with the payment preimage.
+func TaprootSenderHtlcSpendRedeem(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx, paymentPreimage []byte) (wire.TxWitness, error) {
+
+	sweepSig, err := taprootScriptSpend(signer, signDesc, sweepTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taprootScriptWitness(
+		signDesc, TaprootWitnessSig(sweepSig, signDesc.HashType),
+		paymentPreimage,
+	), nil
+}
+
+// TaprootReceiverHtlcSpendRedeem constructs the witness allowing the receiver
+// of an HTLC to spend the taproot HTLC output with the pre-signed HTLC success
+// transaction.
+func TaprootReceiverHtlcSpendRedeem(senderSig Signature,
+	senderSigHash txscript.SigHashType, paymentPreimage []byte,
+	signer Signer, signDesc *SignDescriptor, htlcSuccessTx *wire.MsgTx) (
+	wire.TxWitness, error) {
+
+	sweepSig, err := taprootScriptSpend(signer, signDesc, htlcSuccessTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taprootScriptWitness(
+		signDesc, TaprootWitnessSig(senderSig, senderSigHash),
+		TaprootWitnessSig(sweepSig, signDesc.HashType),
+		paymentPreimage,
+	), nil
+}
+
+// TaprootReceiverHtlcSpendTimeout constructs the witness allowing the sender
+// of an HTLC to sweep the taproot HTLC output on the receiver's commitment
+// transaction after its absolute timeout. If the caller has already set the
+// lock time on the spending transaction, than a value of -1 can be passed for
+// the cltvExpiry value.
+func TaprootReceiverHtlcSpendTimeout(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx, cltvExpiry int32) (wire.TxWitness, error) {
+
+	if cltvExpiry != -1 {
+		sweepTx.LockTime = uint32(cltvExpiry)
+	}
+
+	sweepSig, err := taprootScriptSpend(signer, signDesc, sweepTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taprootScriptWitness(
+		signDesc, TaprootWitnessSig(sweepSig, signDesc.HashType),
+	), nil
+}
@@ -0,0 +1,906 @@
+package input
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/schnorr"
+	"github.com/stretchr/testify/require"
+)
+
+// taprootTestOutput creates a taproot output of the tree and a transaction
+// sweeping it.
+func taprootTestOutput(t *testing.T, tree *TapscriptTree,
+	amt btcutil.Amount) (*wire.TxOut, *wire.MsgTx, PrevOutputFetcher) {
+
+	txid, err := chainhash.NewHash(testHdSeed.CloneBytes())
+	require.NoError(t, err)
+
+	outPoint := wire.OutPoint{Hash: *txid}
+	output := wire.NewTxOut(int64(amt), tree.PkScript)
+
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(wire.NewTxIn(&outPoint, nil, nil))
+	sweepTx.AddTxOut(&wire.TxOut{
+		PkScript: []byte("doesn't matter"),
+		Value:    int64(amt) / 2,
+	})
+
+	return output, sweepTx, MultiPrevOutFetcher{outPoint: output}
+}
+
+// scriptSpendDesc returns the sign descriptor spending the leaf of the tree
+// with the passed key.
+func scriptSpendDesc(t *testing.T, tree *TapscriptTree, leaf int,
+	key *btcec.PublicKey, output *wire.TxOut) *SignDescriptor {
+
+	controlBlock, err := tree.ControlBlock(leaf)
+	require.NoError(t, err)
+
+	return &SignDescriptor{
+		KeyDesc: keychain.KeyDescriptor{
+			PubKey: key,
+		},
+		WitnessScript: tree.Leaves[leaf],
+		ControlBlock:  controlBlock,
+		SignMethod:    TaprootScriptSpendSignMethod,
+		Output:        output,
+		HashType:      SigHashDefault,
+	}
+}
+
+// keySpendDesc returns the sign descriptor spending the tree over the key
+// path with the passed key.
+func keySpendDesc(tree *TapscriptTree, key *btcec.PublicKey,
+	output *wire.TxOut) *SignDescriptor {
+
+	return &SignDescriptor{
+		KeyDesc: keychain.KeyDescriptor{
+			PubKey: key,
+		},
+		TapTweak:   tree.RootHash,
+		SignMethod: TaprootKeySpendSignMethod,
+		Output:     output,
+		HashType:   SigHashDefault,
+	}
+}
+
+// taprootTestCase is a witness that spends a taproot output, and whether it is
+// expected to be valid.
+type taprootTestCase struct {
+	name    string
+	witness func(t *testing.T) wire.TxWitness
+	valid   bool
+}
+
+// assertTaprootSpends verifies the witnesses of the test cases against the
+// taproot output spent by the sweep transaction.
+func assertTaprootSpends(t *testing.T, sweepTx *wire.MsgTx,
+	prevOuts PrevOutputFetcher, testCases []taprootTestCase) {
+
+	t.Helper()
+
+	for _, testCase := range testCases {
+		sweepTx.TxIn[0].Witness = testCase.witness(t)
+
+		err := VerifyTaprootSpend(sweepTx, 0, prevOuts)
+		if testCase.valid {
+			require.NoError(t, err, testCase.name)
+		} else {
+			require.Error(t, err, testCase.name)
+		}
+	}
+}
+
+// TestTaprootFundingSpends checks that the taproot funding output can be spent
+// with the signatures of both parties over the script path, and with a MuSig2
+// signature over the key path.
+func TestTaprootFundingSpends(t *testing.T) {
+	t.Parallel()
+
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		testWalletPrivKey)
+	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		bobsPrivKey)
+
+	aliceSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{aliceKeyPriv}}
+	bobSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{bobKeyPriv}}
+
+	tree, keyAgg, err := TaprootFundingScript(aliceKeyPub, bobKeyPub)
+	require.NoError(t, err)
+
+	// The funding output doesn't depend on the order of the keys.
+	_, fundingOutput, err := GenTaprootFundingPkScript(
+		bobKeyPub.SerializeCompressed(),
+		aliceKeyPub.SerializeCompressed(), 1e8,
+	)
+	require.NoError(t, err)
+	require.Equal(t, tree.PkScript, fundingOutput.PkScript)
+
+	output, commitTx, prevOuts := taprootTestOutput(t, tree, 1e8)
+
+	signLeaf := func(signer Signer, key *btcec.PublicKey) Signature {
+		signDesc := scriptSpendDesc(t, tree, 0, key, output)
+		sig, err := signer.SignOutputRaw(commitTx, signDesc)
+		require.NoError(t, err)
+
+		return sig
+	}
+
+	// The cooperative close is signed with MuSig2.
+	musig2Witness := func(t *testing.T) wire.TxWitness {
+		sigHash, err := TaprootSigHash(
+			commitTx, prevOuts, 0, SigHashDefault, nil,
+		)
+		require.NoError(t, err)
+
+		var msg [32]byte
+		copy(msg[:], sigHash)
+
+		aliceNonces, err := schnorr.GenNonces(aliceKeyPub, nil)
+		require.NoError(t, err)
+		bobNonces, err := schnorr.GenNonces(bobKeyPub, nil)
+		require.NoError(t, err)
+
+		session, err := schnorr.NewSession(keyAgg, []schnorr.PubNonce{
+			aliceNonces.PubNonce, bobNonces.PubNonce,
+		}, msg)
+		require.NoError(t, err)
+
+		aliceSig, err := aliceSigner.MuSig2Sign(
+			&keychain.KeyDescriptor{PubKey: aliceKeyPub},
+			aliceNonces, session,
+		)
+		require.NoError(t, err)
+		bobSig, err := bobSigner.MuSig2Sign(
+			&keychain.KeyDescriptor{PubKey: bobKeyPub},
+			bobNonces, session,
+		)
+		require.NoError(t, err)
+
+		sig, err := session.CombineSigs(
+			[]schnorr.PartialSig{aliceSig, bobSig},
+		)
+		require.NoError(t, err)
+
+		return wire.TxWitness{sig.Serialize()}
+	}
+
+	controlBlock, err := tree.ControlBlock(0)
+	require.NoError(t, err)
+
+	testCases := []taprootTestCase{
+		{
+			name: "commitment spend",
+			witness: func(t *testing.T) wire.TxWitness {
+				return TaprootSpendMultiSig(
+					tree.Leaves[0], controlBlock,
+					aliceKeyPub.SerializeCompressed(),
+					signLeaf(aliceSigner, aliceKeyPub),
+					bobKeyPub.SerializeCompressed(),
+					signLeaf(bobSigner, bobKeyPub),
+				)
+			},
+			valid: true,
+		},
+		{
+			name: "commitment spend with swapped signatures",
+			witness: func(t *testing.T) wire.TxWitness {
+				return TaprootSpendMultiSig(
+					tree.Leaves[0], controlBlock,
+					aliceKeyPub.SerializeCompressed(),
+					signLeaf(bobSigner, bobKeyPub),
+					bobKeyPub.SerializeCompressed(),
+					signLeaf(aliceSigner, aliceKeyPub),
+				)
+			},
+			valid: false,
+		},
+		{
+			name:    "musig2 key spend",
+			witness: musig2Witness,
+			valid:   true,
+		},
+		{
+			name: "single party key spend",
+			witness: func(t *testing.T) wire.TxWitness {
+				signDesc := keySpendDesc(
+					tree, aliceKeyPub, output,
+				)
+				witness, err := TaprootKeySpend(
+					aliceSigner, signDesc, commitTx,
+				)
+				require.NoError(t, err)
+
+				return witness
+			},
+			valid: false,
+		},
+	}
+
+	assertTaprootSpends(t, commitTx, prevOuts, testCases)
+}
+
+// TestTaprootCommitSpends checks the spend paths of the to_local, to_remote
+// and anchor outputs of a taproot commitment transaction.
+func TestTaprootCommitSpends(t *testing.T) {
+	t.Parallel()
+
+	const csvDelay = 5
+
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		testWalletPrivKey)
+	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		bobsPrivKey)
+
+	aliceSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{aliceKeyPriv}}
+	bobSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{bobKeyPriv}}
+
+	// Alice's commitment pays to her delay key, and can be revoked by
+	// Bob.
+	commitSecret, commitPoint := btcec.PrivKeyFromBytes(
+		btcec.S256(), testHdSeed.CloneBytes(),
+	)
+	revocationKey := DeriveRevocationPubkey(bobKeyPub, commitPoint)
+	delayKey := TweakPubKey(aliceKeyPub, commitPoint)
+	commitTweak := SingleTweakBytes(commitPoint, aliceKeyPub)
+
+	t.Run("to_local", func(t *testing.T) {
+		tree, err := TaprootCommitScriptToSelf(
+			csvDelay, delayKey, revocationKey,
+		)
+		require.NoError(t, err)
+
+		output, sweepTx, prevOuts := taprootTestOutput(t, tree, 1e8)
+
+		delaySpend := func(sequence uint32) func(
+			*testing.T) wire.TxWitness {
+
+			return func(t *testing.T) wire.TxWitness {
+				sweepTx.TxIn[0].Sequence = sequence
+
+				signDesc := scriptSpendDesc(
+					t, tree, TaprootDelayLeaf, aliceKeyPub,
+					output,
+				)
+				signDesc.SingleTweak = commitTweak
+
+				witness, err := TaprootCommitSpendTimeout(
+					aliceSigner, signDesc, sweepTx,
+				)
+				require.NoError(t, err)
+
+				return witness
+			}
+		}
+
+		testCases := []taprootTestCase{
+			{
+				name: "delay spend",
+				witness: delaySpend(
+					LockTimeToSequence(false, csvDelay),
+				),
+				valid: true,
+			},
+			{
+				name: "delay spend before csv",
+				witness: delaySpend(
+					LockTimeToSequence(false, csvDelay-1),
+				),
+				valid: false,
+			},
+			{
+				name: "revoke spend",
+				witness: func(t *testing.T) wire.TxWitness {
+					sweepTx.TxIn[0].Sequence = 0
+
+					signDesc := scriptSpendDesc(
+						t, tree, TaprootRevokeLeaf,
+						bobKeyPub, output,
+					)
+					signDesc.DoubleTweak = commitSecret
+
+					witness, err :=
+						TaprootCommitSpendRevoke(
+							bobSigner, signDesc,
+							sweepTx,
+						)
+					require.NoError(t, err)
+
+					return witness
+				},
+				valid: true,
+			},
+			{
+				name: "revoke spend by owner",
+				witness: func(t *testing.T) wire.TxWitness {
+					signDesc := scriptSpendDesc(
+						t, tree, TaprootRevokeLeaf,
+						aliceKeyPub, output,
+					)
+					signDesc.SingleTweak = commitTweak
+
+					witness, err :=
+						TaprootCommitSpendRevoke(
+							aliceSigner, signDesc,
+							sweepTx,
+						)
+					require.NoError(t, err)
+
+					return witness
+				},
+				valid: false,
+			},
+			{
+				name: "key spend with nums key",
+				witness: func(t *testing.T) wire.TxWitness {
+					sig := make(
+						[]byte, schnorr.SignatureSize,
+					)
+
+					return wire.TxWitness{sig}
+				},
+				valid: false,
+			},
+		}
+
+		assertTaprootSpends(t, sweepTx, prevOuts, testCases)
+	})
+
+	t.Run("to_remote", func(t *testing.T) {
+		tree, err := TaprootCommitScriptToRemote(bobKeyPub)
+		require.NoError(t, err)
+
+		output, sweepTx, prevOuts := taprootTestOutput(t, tree, 1e8)
+
+		remoteSpend := func(sequence uint32) func(
+			*testing.T) wire.TxWitness {
+
+			return func(t *testing.T) wire.TxWitness {
+				sweepTx.TxIn[0].Sequence = sequence
+
+				signDesc := scriptSpendDesc(
+					t, tree, 0, bobKeyPub, output,
+				)
+				witness, err := TaprootCommitSpendToRemote(
+					bobSigner, signDesc, sweepTx,
+				)
+				require.NoError(t, err)
+
+				return witness
+			}
+		}
+
+		testCases := []taprootTestCase{
+			{
+				name: "spend after one confirmation",
+				witness: remoteSpend(
+					LockTimeToSequence(false, 1),
+				),
+				valid: true,
+			},
+			{
+				name:    "spend without sequence",
+				witness: remoteSpend(wire.MaxTxInSequenceNum),
+				valid:   false,
+			},
+		}
+
+		assertTaprootSpends(t, sweepTx, prevOuts, testCases)
+	})
+
+	t.Run("anchor", func(t *testing.T) {
+		tree, err := TaprootCommitScriptAnchor(aliceKeyPub)
+		require.NoError(t, err)
+
+		output, sweepTx, prevOuts := taprootTestOutput(t, tree, 330)
+
+		controlBlock, err := tree.ControlBlock(0)
+		require.NoError(t, err)
+
+		anyoneSpend := func(sequence uint32) func(
+			*testing.T) wire.TxWitness {
+
+			return func(t *testing.T) wire.TxWitness {
+				sweepTx.TxIn[0].Sequence = sequence
+
+				return TaprootCommitSpendAnchorAnyone(
+					tree.Leaves[0], controlBlock,
+				)
+			}
+		}
+
+		testCases := []taprootTestCase{
+			{
+				name: "owner key spend",
+				witness: func(t *testing.T) wire.TxWitness {
+					signDesc := keySpendDesc(
+						tree, aliceKeyPub, output,
+					)
+					witness, err := TaprootKeySpend(
+						aliceSigner, signDesc, sweepTx,
+					)
+					require.NoError(t, err)
+
+					return witness
+				},
+				valid: true,
+			},
+			{
+				name: "key spend by other party",
+				witness: func(t *testing.T) wire.TxWitness {
+					signDesc := keySpendDesc(
+						tree, bobKeyPub, output,
+					)
+					witness, err := TaprootKeySpend(
+						bobSigner, signDesc, sweepTx,
+					)
+					require.NoError(t, err)
+
+					return witness
+				},
+				valid: false,
+			},
+			{
+				name: "anyone spend after 16 blocks",
+				witness: anyoneSpend(
+					LockTimeToSequence(false, 16),
+				),
+				valid: true,
+			},
+			{
+				name: "anyone spend before 16 blocks",
+				witness: anyoneSpend(
+					LockTimeToSequence(false, 15),
+				),
+				valid: false,
+			},
+		}
+
+		assertTaprootSpends(t, sweepTx, prevOuts, testCases)
+	})
+}
+
+// TestTaprootHtlcSpends checks the spend paths of the offered and accepted
+// taproot HTLC outputs, and of the second-level HTLC output.
+func TestTaprootHtlcSpends(t *testing.T) {
+	t.Parallel()
+
+	const (
+		cltvExpiry = 500
+		csvDelay   = 5
+	)
+
+	// Alice offers an HTLC to Bob on her commitment transaction, which
+	// Bob can revoke.
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		testWalletPrivKey)
+	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		bobsPrivKey)
+
+	aliceSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{aliceKeyPriv}}
+	bobSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{bobKeyPriv}}
+
+	commitSecret, commitPoint := btcec.PrivKeyFromBytes(
+		btcec.S256(), testHdSeed.CloneBytes(),
+	)
+	revocationKey := DeriveRevocationPubkey(bobKeyPub, commitPoint)
+
+	var preimage [32]byte
+	copy(preimage[:], bytes.Repeat([]byte{1}, 32))
+	paymentHash := sha256.Sum256(preimage[:])
+	wrongPreimage := bytes.Repeat([]byte{2}, 32)
+
+	// revokeSpend sweeps a revoked HTLC output over the key path.
+	revokeSpend := func(tree *TapscriptTree, output *wire.TxOut,
+		sweepTx *wire.MsgTx) func(*testing.T) wire.TxWitness {
+
+		return func(t *testing.T) wire.TxWitness {
+			signDesc := keySpendDesc(tree, bobKeyPub, output)
+			signDesc.DoubleTweak = commitSecret
+
+			witness, err := TaprootKeySpend(
+				bobSigner, signDesc, sweepTx,
+			)
+			require.NoError(t, err)
+
+			return witness
+		}
+	}
+
+	t.Run("offered", func(t *testing.T) {
+		tree, err := TaprootSenderHTLCScript(
+			aliceKeyPub, bobKeyPub, revocationKey, paymentHash[:],
+		)
+		require.NoError(t, err)
+
+		output, sweepTx, prevOuts := taprootTestOutput(t, tree, 1e6)
+
+		redeem := func(preimage []byte, sequence uint32) func(
+			*testing.T) wire.TxWitness {
+
+			return func(t *testing.T) wire.TxWitness {
+				sweepTx.TxIn[0].Sequence = sequence
+
+				signDesc := scriptSpendDesc(
+					t, tree, TaprootSuccessLeaf, bobKeyPub,
+					output,
+				)
+				witness, err := TaprootSenderHtlcSpendRedeem(
+					bobSigner, signDesc, sweepTx, preimage,
+				)
+				require.NoError(t, err)
+
+				return witness
+			}
+		}
+
+		// The HTLC timeout transaction is pre-signed by Bob with
+		// SINGLE|ANYONECANPAY.
+		timeout := func(bobHashType txscript.SigHashType) func(
+			*testing.T) wire.TxWitness {
+
+			return func(t *testing.T) wire.TxWitness {
+				sweepTx.TxIn[0].Sequence = 0
+
+				bobDesc := scriptSpendDesc(
+					t, tree, TaprootTimeoutLeaf, bobKeyPub,
+					output,
+				)
+				bobDesc.HashType = bobHashType
+				bobSig, err := bobSigner.SignOutputRaw(
+					sweepTx, bobDesc,
+				)
+				require.NoError(t, err)
+
+				aliceDesc := scriptSpendDesc(
+					t, tree, TaprootTimeoutLeaf,
+					aliceKeyPub, output,
+				)
+				witness, err := TaprootSenderHtlcSpendTimeout(
+					bobSig, txscript.SigHashSingle|
+						txscript.SigHashAnyOneCanPay,
+					aliceSigner, aliceDesc, sweepTx,
+				)
+				require.NoError(t, err)
+
+				return witness
+			}
+		}
+
+		testCases := []taprootTestCase{
+			{
+				name: "redeem with preimage",
+				witness: redeem(
+					preimage[:],
+					LockTimeToSequence(false, 1),
+				),
+				valid: true,
+			},
+			{
+				name: "redeem with wrong preimage",
+				witness: redeem(
+					wrongPreimage,
+					LockTimeToSequence(false, 1),
+				),
+				valid: false,
+			},
+			{
+				name: "redeem without sequence",
+				witness: redeem(
+					preimage[:], wire.MaxTxInSequenceNum,
+				),
+				valid: false,
+			},
+			{
+				name: "timeout transaction",
+				witness: timeout(
+					txscript.SigHashSingle |
+						txscript.SigHashAnyOneCanPay,
+				),
+				valid: true,
+			},
+			{
+				name:    "timeout with mismatched sighash",
+				witness: timeout(SigHashDefault),
+				valid:   false,
+			},
+			{
+				name:    "revoke",
+				witness: revokeSpend(tree, output, sweepTx),
+				valid:   true,
+			},
+		}
+
+		assertTaprootSpends(t, sweepTx, prevOuts, testCases)
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		// Bob offers the HTLC to Alice on her commitment transaction.
+		tree, err := TaprootReceiverHTLCScript(
+			cltvExpiry, bobKeyPub, aliceKeyPub, revocationKey,
+			paymentHash[:],
+		)
+		require.NoError(t, err)
+
+		output, sweepTx, prevOuts := taprootTestOutput(t, tree, 1e6)
+
+		// The HTLC success transaction is pre-signed by Bob.
+		success := func(preimage []byte) func(
+			*testing.T) wire.TxWitness {
+
+			return func(t *testing.T) wire.TxWitness {
+				sweepTx.LockTime = 0
+				sweepTx.TxIn[0].Sequence = 0
+
+				bobDesc := scriptSpendDesc(
+					t, tree, TaprootSuccessLeaf, bobKeyPub,
+					output,
+				)
+				bobDesc.HashType = txscript.SigHashSingle |
+					txscript.SigHashAnyOneCanPay
+				bobSig, err := bobSigner.SignOutputRaw(
+					sweepTx, bobDesc,
+				)
+				require.NoError(t, err)
+
+				aliceDesc := scriptSpendDesc(
+					t, tree, TaprootSuccessLeaf,
+					aliceKeyPub, output,
+				)
+				witness, err := TaprootReceiverHtlcSpendRedeem(
+					bobSig, bobDesc.HashType, preimage,
+					aliceSigner, aliceDesc, sweepTx,
+				)
+				require.NoError(t, err)
+
+				return witness
+			}
+		}
+
+		timeout := func(lockTime int32) func(
+			*testing.T) wire.TxWitness {
+
+			return func(t *testing.T) wire.TxWitness {
+				sweepTx.TxIn[0].Sequence = LockTimeToSequence(
+					false, 1,
+				)
+
+				signDesc := scriptSpendDesc(
+					t, tree, TaprootTimeoutLeaf, bobKeyPub,
+					output,
+				)
+				witness, err := TaprootReceiverHtlcSpendTimeout(
+					bobSigner, signDesc, sweepTx, lockTime,
+				)
+				require.NoError(t, err)
+
+				return witness
+			}
+		}
+
+		testCases := []taprootTestCase{
+			{
+				name:    "success transaction",
+				witness: success(preimage[:]),
+				valid:   true,
+			},
+			{
+				name:    "success with wrong preimage",
+				witness: success(wrongPreimage),
+				valid:   false,
+			},
+			{
+				name:    "timeout after expiry",
+				witness: timeout(cltvExpiry),
+				valid:   true,
+			},
+			{
+				name:    "timeout before expiry",
+				witness: timeout(cltvExpiry - 1),
+				valid:   false,
+			},
+			{
+				name:    "revoke",
+				witness: revokeSpend(tree, output, sweepTx),
+				valid:   true,
+			},
+		}
+
+		assertTaprootSpends(t, sweepTx, prevOuts, testCases)
+	})
+
+	t.Run("second level", func(t *testing.T) {
+		delayKey := TweakPubKey(aliceKeyPub, commitPoint)
+		commitTweak := SingleTweakBytes(commitPoint, aliceKeyPub)
+
+		tree, err := TaprootSecondLevelHtlcScript(
+			revocationKey, delayKey, csvDelay,
+		)
+		require.NoError(t, err)
+
+		output, sweepTx, prevOuts := taprootTestOutput(t, tree, 1e6)
+
+		delaySpend := func(sequence uint32) func(
+			*testing.T) wire.TxWitness {
+
+			return func(t *testing.T) wire.TxWitness {
+				sweepTx.TxIn[0].Sequence = sequence
+
+				signDesc := scriptSpendDesc(
+					t, tree, 0, aliceKeyPub, output,
+				)
+				signDesc.SingleTweak = commitTweak
+
+				witness, err := TaprootCommitSpendTimeout(
+					aliceSigner, signDesc, sweepTx,
+				)
+				require.NoError(t, err)
+
+				return witness
+			}
+		}
+
+		testCases := []taprootTestCase{
+			{
+				name: "delay spend",
+				witness: delaySpend(
+					LockTimeToSequence(false, csvDelay),
+				),
+				valid: true,
+			},
+			{
+				name: "delay spend before csv",
+				witness: delaySpend(
+					LockTimeToSequence(false, csvDelay-1),
+				),
+				valid: false,
+			},
+			{
+				name:    "revoke",
+				witness: revokeSpend(tree, output, sweepTx),
+				valid:   true,
+			},
+		}
+
+		assertTaprootSpends(t, sweepTx, prevOuts, testCases)
+	})
+}
+
+// TestTaprootSigHashMultipleInputs checks that taproot signatures commit to
+// the outputs spent by all inputs of a transaction.
+func TestTaprootSigHashMultipleInputs(t *testing.T) {
+	t.Parallel()
+
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		testWalletPrivKey)
+	aliceSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{aliceKeyPriv}}
+
+	tree, err := TaprootCommitScriptAnchor(aliceKeyPub)
+	require.NoError(t, err)
+
+	output, sweepTx, prevOuts := taprootTestOutput(t, tree, 330)
+
+	// Add a second input spending a wallet output.
+	walletOutPoint := wire.OutPoint{Index: 1}
+	walletOutput := wire.NewTxOut(1e6, []byte("doesn't matter"))
+	sweepTx.AddTxIn(wire.NewTxIn(&walletOutPoint, nil, nil))
+
+	// Without the other spent outputs, the input can't be signed.
+	signDesc := keySpendDesc(tree, aliceKeyPub, output)
+	_, err = TaprootKeySpend(aliceSigner, signDesc, sweepTx)
+	require.Error(t, err)
+
+	anchorInput := NewBaseInput(
+		&sweepTx.TxIn[0].PreviousOutPoint, TaprootCommitmentAnchor,
+		signDesc, 0,
+	)
+	walletInput := NewBaseInput(
+		&walletOutPoint, WitnessKeyHash, &SignDescriptor{
+			Output: walletOutput,
+		}, 0,
+	)
+	SetPrevOutputFetcher(anchorInput, walletInput)
+
+	inputScript, err := anchorInput.CraftInputScript(
+		aliceSigner, sweepTx, nil, 0,
+	)
+	require.NoError(t, err)
+	sweepTx.TxIn[0].Witness = inputScript.Witness
+
+	allPrevOuts := MultiPrevOutFetcher{
+		sweepTx.TxIn[0].PreviousOutPoint: output,
+		walletOutPoint:                   walletOutput,
+	}
+	require.NoError(t, VerifyTaprootSpend(sweepTx, 0, allPrevOuts))
+
+	// The signature is invalid if the amount of the other spent output
+	// differs.
+	allPrevOuts[walletOutPoint] = wire.NewTxOut(2e6, walletOutput.PkScript)
+	require.Error(t, VerifyTaprootSpend(sweepTx, 0, allPrevOuts))
+
+	// A transaction with only the taproot input can be signed without the
+	// prev output fetcher.
+	sweepTx.TxIn = sweepTx.TxIn[:1]
+	sweepTx.TxIn[0].Witness = nil
+
+	signDesc = keySpendDesc(tree, aliceKeyPub, output)
+	witness, err := TaprootKeySpend(aliceSigner, signDesc, sweepTx)
+	require.NoError(t, err)
+	sweepTx.TxIn[0].Witness = witness
+
+	require.NoError(t, VerifyTaprootSpend(sweepTx, 0, prevOuts))
+}
+
+// TestTaprootControlBlock checks that a control block only proves the
+// inclusion of its own leaf.
+func TestTaprootControlBlock(t *testing.T) {
+	t.Parallel()
+
+	_, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		testWalletPrivKey)
+	_, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(), bobsPrivKey)
+
+	tree, err := TaprootCommitScriptToSelf(5, aliceKeyPub, bobKeyPub)
+	require.NoError(t, err)
+
+	for leaf := range tree.Leaves {
+		controlBlock, err := tree.ControlBlock(leaf)
+		require.NoError(t, err)
+		require.Len(
+			t, controlBlock,
+			ControlBlockBaseSize+ControlBlockNodeSize,
+		)
+
+		require.NoError(t, verifyControlBlock(
+			controlBlock, tree.Leaves[leaf], tree.PkScript[2:],
+		))
+		require.Error(t, verifyControlBlock(
+			controlBlock, tree.Leaves[1-leaf], tree.PkScript[2:],
+		))
+
+		// Flipping the parity bit invalidates the control block.
+		controlBlock[0] ^= 1
+		require.Error(t, verifyControlBlock(
+			controlBlock, tree.Leaves[leaf], tree.PkScript[2:],
+		))
+	}
+
+	_, err = tree.ControlBlock(2)
+	require.Error(t, err)
+
+	_, err = NewTapscriptTree(
+		aliceKeyPub, tree.Leaves[0], tree.Leaves[1], tree.Leaves[0],
+	)
+	require.Error(t, err)
+}
+
+// TestTweakTaprootPrivKey checks that the tweaked private key matches the
+// output key, regardless of the parity of the internal key.
+func TestTweakTaprootPrivKey(t *testing.T) {
+	t.Parallel()
+
+	rootHash := bytes.Repeat([]byte{3}, 32)
+
+	for i := byte(1); i < 20; i++ {
+		privKey, pubKey := btcec.PrivKeyFromBytes(
+			btcec.S256(), bytes.Repeat([]byte{i}, 32),
+		)
+
+		outputKey, err := ComputeTaprootOutputKey(pubKey, rootHash)
+		require.NoError(t, err)
+
+		tweaked := TweakTaprootPrivKey(privKey, rootHash)
+		require.Equal(
+			t, schnorr.SerializePubKey(outputKey),
+			schnorr.SerializePubKey(tweaked.PubKey()),
+		)
+	}
+}
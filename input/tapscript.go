@@ -0,0 +1,594 @@
+package input
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/lightningnetwork/lnd/schnorr"
+)
+
+const (
+	// taprootAnnexTag is the first byte of an optional annex, the last
+	// witness element of a taproot spend.
+	taprootAnnexTag = 0x50
+
+	// maxControlBlockNodes is the maximum depth of a tapscript tree.
+	maxControlBlockNodes = 128
+
+	// maxTapscriptElementSize is the maximum size of a stack element.
+	maxTapscriptElementSize = 520
+
+	// maxTapscriptStackSize is the maximum number of stack elements.
+	maxTapscriptStackSize = 1000
+
+	// maxLockTimeNumLen is the maximum length of the script numbers that
+	// are checked by OP_CHECKLOCKTIMEVERIFY and OP_CHECKSEQUENCEVERIFY.
+	maxLockTimeNumLen = 5
+)
+
+var (
+	// ErrTapscriptFailed is returned when the execution of a tapscript
+	// leaf doesn't succeed.
+	ErrTapscriptFailed = errors.New("tapscript execution failed")
+
+	// ErrInvalidTaprootSig is returned when a taproot signature doesn't
+	// verify.
+	ErrInvalidTaprootSig = errors.New("invalid taproot signature")
+)
+
+// VerifyTaprootSpend verifies that the witness of the input with the passed
+// index validly spends its taproot output. Both key path and script path
+// spends are verified, where the tapscript leaf may only use the opcodes of
+// our channel scripts.
+//
+// NOTE: The script engine of btcd doesn't validate taproot spends yet and
+// treats them as anyone-can-spend, so this is used in its place to sanity
+// check the transactions we sign.
+func VerifyTaprootSpend(tx *wire.MsgTx, idx int,
+	prevOuts PrevOutputFetcher) error {
+
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return fmt.Errorf("input index %d out of range", idx)
+	}
+
+	txIn := tx.TxIn[idx]
+	spent := prevOuts.FetchPrevOutput(txIn.PreviousOutPoint)
+	if spent == nil {
+		return fmt.Errorf("spent output of input %d is unknown", idx)
+	}
+	if !IsPayToTaproot(spent.PkScript) {
+		return fmt.Errorf("input %d doesn't spend a taproot output",
+			idx)
+	}
+	outputKeyBytes := spent.PkScript[2:]
+
+	witness := txIn.Witness
+	if len(witness) >= 2 && len(witness[len(witness)-1]) > 0 &&
+		witness[len(witness)-1][0] == taprootAnnexTag {
+
+		return errors.New("taproot annex isn't supported")
+	}
+
+	switch len(witness) {
+	case 0:
+		return errors.New("empty taproot witness")
+
+	// A single witness element is a key path spend, which is a signature
+	// of the output key.
+	case 1:
+		outputKey, err := schnorr.ParsePubKey(outputKeyBytes)
+		if err != nil {
+			return err
+		}
+
+		sig, hashType, err := ParseTaprootWitnessSig(witness[0])
+		if err != nil {
+			return err
+		}
+
+		return verifyTaprootSig(
+			tx, idx, prevOuts, sig, hashType, outputKey, nil,
+		)
+	}
+
+	// Otherwise this is a script path spend, with the control block and
+	// the leaf script as last elements.
+	controlBlock := witness[len(witness)-1]
+	leafScript := witness[len(witness)-2]
+	err := verifyControlBlock(controlBlock, leafScript, outputKeyBytes)
+	if err != nil {
+		return err
+	}
+
+	vm := &tapscriptVM{
+		tx:       tx,
+		idx:      idx,
+		prevOuts: prevOuts,
+		leaf:     leafScript,
+	}
+	for _, item := range witness[:len(witness)-2] {
+		if len(item) > maxTapscriptElementSize {
+			return fmt.Errorf("witness element of %d bytes "+
+				"exceeds the maximum", len(item))
+		}
+		vm.push(item)
+	}
+
+	return vm.execute()
+}
+
+// verifyControlBlock verifies that the control block proves that the leaf
+// script is committed to by the taproot output key.
+func verifyControlBlock(controlBlock, leafScript,
+	outputKeyBytes []byte) error {
+
+	proofLen := len(controlBlock) - ControlBlockBaseSize
+	if proofLen < 0 || proofLen%ControlBlockNodeSize != 0 ||
+		proofLen/ControlBlockNodeSize > maxControlBlockNodes {
+
+		return fmt.Errorf("invalid control block length %d",
+			len(controlBlock))
+	}
+
+	if controlBlock[0]&0xfe != TapscriptLeafVersion {
+		return fmt.Errorf("unsupported leaf version %x",
+			controlBlock[0]&0xfe)
+	}
+
+	internalKey, err := schnorr.ParsePubKey(
+		controlBlock[1:ControlBlockBaseSize],
+	)
+	if err != nil {
+		return err
+	}
+
+	rootHash := TapLeafHash(leafScript)
+	for i := ControlBlockBaseSize; i < len(controlBlock); {
+		var node [32]byte
+		i += copy(node[:], controlBlock[i:])
+		rootHash = TapBranchHash(rootHash, node)
+	}
+
+	outputKey, err := ComputeTaprootOutputKey(internalKey, rootHash[:])
+	if err != nil {
+		return err
+	}
+
+	oddKey := outputKey.SerializeCompressed()[0] ==
+		secp.PubKeyFormatCompressedOdd
+	if !bytes.Equal(schnorr.SerializePubKey(outputKey), outputKeyBytes) ||
+		oddKey != (controlBlock[0]&1 == 1) {
+
+		return errors.New("control block doesn't commit to the " +
+			"output key")
+	}
+
+	return nil
+}
+
+// verifyTaprootSig verifies the BIP-340 signature of the input with the passed
+// index. The leaf script is nil for key path spends.
+func verifyTaprootSig(tx *wire.MsgTx, idx int, prevOuts PrevOutputFetcher,
+	sig schnorr.Signature, hashType txscript.SigHashType,
+	pubKey *btcec.PublicKey, leafScript []byte) error {
+
+	sigHash, err := TaprootSigHash(tx, prevOuts, idx, hashType, leafScript)
+	if err != nil {
+		return err
+	}
+
+	if !sig.Verify(sigHash, pubKey) {
+		return ErrInvalidTaprootSig
+	}
+
+	return nil
+}
+
+// tapscriptVM executes a tapscript leaf. Only the opcodes that are used by
+// our channel scripts are supported.
+type tapscriptVM struct {
+	tx       *wire.MsgTx
+	idx      int
+	prevOuts PrevOutputFetcher
+	leaf     []byte
+	stack    [][]byte
+}
+
+// push pushes the element on the stack.
+func (vm *tapscriptVM) push(item []byte) {
+	vm.stack = append(vm.stack, item)
+}
+
+// pop removes the top element of the stack.
+func (vm *tapscriptVM) pop() ([]byte, error) {
+	if len(vm.stack) == 0 {
+		return nil, fmt.Errorf("%w: stack underflow",
+			ErrTapscriptFailed)
+	}
+
+	item := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+
+	return item, nil
+}
+
+// peek returns the top element of the stack without removing it.
+func (vm *tapscriptVM) peek() ([]byte, error) {
+	if len(vm.stack) == 0 {
+		return nil, fmt.Errorf("%w: stack underflow",
+			ErrTapscriptFailed)
+	}
+
+	return vm.stack[len(vm.stack)-1], nil
+}
+
+// execute runs the leaf script on the stack of witness elements. The script
+// succeeds if it leaves a single true element on the stack.
+func (vm *tapscriptVM) execute() error {
+	script := vm.leaf
+	for len(script) > 0 {
+		op := script[0]
+		script = script[1:]
+
+		// Data pushes are handled first.
+		isPush := true
+		var pushLen int
+		switch {
+		case op >= txscript.OP_DATA_1 && op <= txscript.OP_DATA_75:
+			pushLen = int(op)
+
+		case op == txscript.OP_PUSHDATA1 && len(script) >= 1:
+			pushLen = int(script[0])
+			script = script[1:]
+
+		case op == txscript.OP_PUSHDATA2 && len(script) >= 2:
+			pushLen = int(script[0]) | int(script[1])<<8
+			script = script[2:]
+
+		case op == txscript.OP_PUSHDATA1 || op == txscript.OP_PUSHDATA2:
+			return fmt.Errorf("%w: truncated push",
+				ErrTapscriptFailed)
+
+		default:
+			isPush = false
+		}
+		if isPush {
+			if pushLen > len(script) {
+				return fmt.Errorf("%w: truncated push",
+					ErrTapscriptFailed)
+			}
+			if pushLen > maxTapscriptElementSize {
+				return fmt.Errorf("%w: push exceeds the "+
+					"maximum element size",
+					ErrTapscriptFailed)
+			}
+
+			vm.push(script[:pushLen])
+			script = script[pushLen:]
+
+			if err := vm.checkStackSize(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := vm.executeOp(op); err != nil {
+			return err
+		}
+		if err := vm.checkStackSize(); err != nil {
+			return err
+		}
+	}
+
+	if len(vm.stack) != 1 || !castToBool(vm.stack[0]) {
+		return fmt.Errorf("%w: script didn't leave a single true "+
+			"element on the stack", ErrTapscriptFailed)
+	}
+
+	return nil
+}
+
+// checkStackSize verifies that the stack doesn't exceed its maximum size.
+func (vm *tapscriptVM) checkStackSize() error {
+	if len(vm.stack) > maxTapscriptStackSize {
+		return fmt.Errorf("%w: stack size exceeds the maximum",
+			ErrTapscriptFailed)
+	}
+
+	return nil
+}
+
+// executeOp executes a single opcode that isn't a data push.
+func (vm *tapscriptVM) executeOp(op byte) error {
+	switch {
+	case op == txscript.OP_0:
+		vm.push(nil)
+		return nil
+
+	case op >= txscript.OP_1 && op <= txscript.OP_16:
+		vm.push(scriptNum(int64(op - txscript.OP_1 + 1)))
+		return nil
+	}
+
+	switch op {
+	case txscript.OP_DROP:
+		_, err := vm.pop()
+		return err
+
+	case txscript.OP_SIZE:
+		item, err := vm.peek()
+		if err != nil {
+			return err
+		}
+		vm.push(scriptNum(int64(len(item))))
+
+		return nil
+
+	case txscript.OP_EQUALVERIFY:
+		a, err := vm.pop()
+		if err != nil {
+			return err
+		}
+		b, err := vm.pop()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(a, b) {
+			return fmt.Errorf("%w: OP_EQUALVERIFY failed",
+				ErrTapscriptFailed)
+		}
+
+		return nil
+
+	case txscript.OP_HASH160:
+		item, err := vm.pop()
+		if err != nil {
+			return err
+		}
+		vm.push(btcutil.Hash160(item))
+
+		return nil
+
+	case txscript.OP_CHECKSIG, txscript.OP_CHECKSIGVERIFY:
+		success, err := vm.checkSig()
+		if err != nil {
+			return err
+		}
+
+		if op == txscript.OP_CHECKSIGVERIFY {
+			if !success {
+				return fmt.Errorf("%w: OP_CHECKSIGVERIFY "+
+					"failed", ErrTapscriptFailed)
+			}
+			return nil
+		}
+
+		if success {
+			vm.push([]byte{1})
+		} else {
+			vm.push(nil)
+		}
+
+		return nil
+
+	case txscript.OP_CHECKSEQUENCEVERIFY:
+		return vm.checkSequence()
+
+	case txscript.OP_CHECKLOCKTIMEVERIFY:
+		return vm.checkLockTime()
+
+	default:
+		return fmt.Errorf("%w: unsupported opcode %x",
+			ErrTapscriptFailed, op)
+	}
+}
+
+// checkSig pops a public key and a signature off the stack and verifies the
+// signature as defined by BIP-342. An empty signature is a failed signature
+// check, while an invalid non-empty signature fails the script.
+func (vm *tapscriptVM) checkSig() (bool, error) {
+	pubKeyBytes, err := vm.pop()
+	if err != nil {
+		return false, err
+	}
+	sigBytes, err := vm.pop()
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case len(pubKeyBytes) == 0:
+		return false, fmt.Errorf("%w: empty public key",
+			ErrTapscriptFailed)
+
+	case len(sigBytes) == 0:
+		return false, nil
+
+	// Public keys of other lengths are reserved for upgrades, so they
+	// aren't validated.
+	case len(pubKeyBytes) != 32:
+		return true, nil
+	}
+
+	pubKey, err := schnorr.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return false, err
+	}
+
+	sig, hashType, err := ParseTaprootWitnessSig(sigBytes)
+	if err != nil {
+		return false, err
+	}
+
+	err = verifyTaprootSig(
+		vm.tx, vm.idx, vm.prevOuts, sig, hashType, pubKey, vm.leaf,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// checkSequence verifies the relative lock time of the input as defined by
+// BIP-112.
+func (vm *tapscriptVM) checkSequence() error {
+	item, err := vm.peek()
+	if err != nil {
+		return err
+	}
+	csv, err := parseLockTimeNum(item)
+	if err != nil {
+		return err
+	}
+
+	// If the disable flag is set, the opcode behaves as a NOP.
+	if csv&int64(wire.SequenceLockTimeDisabled) != 0 {
+		return nil
+	}
+
+	if vm.tx.Version < 2 {
+		return fmt.Errorf("%w: relative lock time requires "+
+			"transaction version 2", ErrTapscriptFailed)
+	}
+
+	sequence := int64(vm.tx.TxIn[vm.idx].Sequence)
+	if sequence&int64(wire.SequenceLockTimeDisabled) != 0 {
+		return fmt.Errorf("%w: relative lock time of the input is "+
+			"disabled", ErrTapscriptFailed)
+	}
+
+	const lockTimeMask = int64(wire.SequenceLockTimeIsSeconds |
+		wire.SequenceLockTimeMask)
+
+	csv &= lockTimeMask
+	sequence &= lockTimeMask
+	isSeconds := int64(wire.SequenceLockTimeIsSeconds)
+	if csv&isSeconds != sequence&isSeconds || csv > sequence {
+		return fmt.Errorf("%w: relative lock time %d not satisfied "+
+			"by sequence %d", ErrTapscriptFailed, csv, sequence)
+	}
+
+	return nil
+}
+
+// checkLockTime verifies the absolute lock time of the transaction as defined
+// by BIP-65.
+func (vm *tapscriptVM) checkLockTime() error {
+	item, err := vm.peek()
+	if err != nil {
+		return err
+	}
+	cltv, err := parseLockTimeNum(item)
+	if err != nil {
+		return err
+	}
+
+	lockTime := int64(vm.tx.LockTime)
+	threshold := int64(txscript.LockTimeThreshold)
+	if (cltv < threshold) != (lockTime < threshold) || cltv > lockTime {
+		return fmt.Errorf("%w: lock time %d not satisfied by %d",
+			ErrTapscriptFailed, cltv, lockTime)
+	}
+
+	if vm.tx.TxIn[vm.idx].Sequence == wire.MaxTxInSequenceNum {
+		return fmt.Errorf("%w: lock time of the input is disabled",
+			ErrTapscriptFailed)
+	}
+
+	return nil
+}
+
+// parseLockTimeNum parses a minimally encoded, non-negative script number of
+// at most five bytes.
+func parseLockTimeNum(item []byte) (int64, error) {
+	if len(item) > maxLockTimeNumLen {
+		return 0, fmt.Errorf("%w: lock time number too long",
+			ErrTapscriptFailed)
+	}
+	if !bytes.Equal(item, scriptNum(decodeScriptNum(item))) {
+		return 0, fmt.Errorf("%w: lock time number isn't minimally "+
+			"encoded", ErrTapscriptFailed)
+	}
+
+	num := decodeScriptNum(item)
+	if num < 0 {
+		return 0, fmt.Errorf("%w: negative lock time",
+			ErrTapscriptFailed)
+	}
+
+	return num, nil
+}
+
+// decodeScriptNum decodes a little-endian script number, whose most
+// significant bit is the sign bit.
+func decodeScriptNum(item []byte) int64 {
+	if len(item) == 0 {
+		return 0
+	}
+
+	var num int64
+	for i, b := range item {
+		num |= int64(b) << uint(8*i)
+	}
+
+	// A set sign bit makes the number negative.
+	last := item[len(item)-1]
+	if last&0x80 != 0 {
+		num &= ^(int64(0x80) << uint(8*(len(item)-1)))
+		return -num
+	}
+
+	return num
+}
+
+// scriptNum returns the minimal encoding of a script number.
+func scriptNum(num int64) []byte {
+	if num == 0 {
+		return nil
+	}
+
+	negative := num < 0
+	if negative {
+		num = -num
+	}
+
+	var item []byte
+	for num > 0 {
+		item = append(item, byte(num&0xff))
+		num >>= 8
+	}
+
+	// If the most significant byte has its sign bit set, an extra byte
+	// holds the sign.
+	switch {
+	case item[len(item)-1]&0x80 != 0 && negative:
+		item = append(item, 0x80)
+
+	case item[len(item)-1]&0x80 != 0:
+		item = append(item, 0x00)
+
+	case negative:
+		item[len(item)-1] |= 0x80
+	}
+
+	return item
+}
+
+// castToBool returns the boolean value of a stack element, which is false
+// for any encoding of zero, including negative zero.
+func castToBool(item []byte) bool {
+	for i, b := range item {
+		if b != 0 {
+			return !(i == len(item)-1 && b == 0x80)
+		}
+	}
+
+	return false
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 var (
@@ -67,6 +69,10 @@ func (m *MockSigner) SignOutputRaw(tx *wire.MsgTx,
 		return nil, fmt.Errorf("mock signer does not have key")
 	}
 
+	if signDesc.SignMethod.IsTaproot() {
+		return SignTaproot(tx, signDesc, privKey)
+	}
+
 	sig, err := txscript.RawTxInWitnessSignature(tx, signDesc.SigHashes,
 		signDesc.InputIndex, signDesc.Output.Value, signDesc.WitnessScript,
 		signDesc.HashType, privKey)
@@ -77,6 +83,22 @@ func (m *MockSigner) SignOutputRaw(tx *wire.MsgTx,
 	return btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
 }
 
+// MuSig2Sign creates a MuSig2 partial signature for the signing session with
+// the key of the key descriptor.
+func (m *MockSigner) MuSig2Sign(keyDesc *keychain.KeyDescriptor,
+	nonces *schnorr.Nonces, session *schnorr.Session) (schnorr.PartialSig,
+	error) {
+
+	hash160 := btcutil.Hash160(keyDesc.PubKey.SerializeCompressed())
+	privKey := m.findKey(hash160, nil, nil)
+	if privKey == nil {
+		return schnorr.PartialSig{}, fmt.Errorf("mock signer does " +
+			"not have key")
+	}
+
+	return session.Sign(nonces, privKey)
+}
+
 // ComputeInputScript generates a complete InputIndex for the passed transaction
 // with the signature as defined within the passed SignDescriptor. This method
 // should be capable of generating the proper input script for both regular
@@ -145,6 +145,71 @@ const (
 	// CommitmentAnchor is a witness that allows us to spend our anchor on
 	// the commitment transaction.
 	CommitmentAnchor StandardWitnessType = 14
+
+	// TaprootCommitmentTimeLock is a witness that allows us to spend our
+	// taproot output on our local commitment transaction after a relative
+	// lock-time lockout.
+	TaprootCommitmentTimeLock StandardWitnessType = 17
+
+	// TaprootCommitmentToRemoteConfirmed is a witness that allows us to
+	// spend our taproot output on the counterparty's commitment
+	// transaction after a confirmation.
+	TaprootCommitmentToRemoteConfirmed StandardWitnessType = 18
+
+	// TaprootCommitmentAnchor is a witness that allows us to spend our
+	// taproot anchor on the commitment transaction over the key path.
+	TaprootCommitmentAnchor StandardWitnessType = 19
+
+	// TaprootCommitmentRevoke is a witness that allows us to sweep the
+	// taproot to_local output of a malicious counterparty who broadcasts
+	// a revoked commitment transaction.
+	TaprootCommitmentRevoke StandardWitnessType = 20
+
+	// TaprootHtlcOfferedRevoke is a witness that allows us to sweep a
+	// taproot HTLC which we offered to the remote party in the case that
+	// they broadcast a revoked commitment state.
+	TaprootHtlcOfferedRevoke StandardWitnessType = 21
+
+	// TaprootHtlcAcceptedRevoke is a witness that allows us to sweep a
+	// taproot HTLC output sent to us in the case that the remote party
+	// broadcasts a revoked commitment state.
+	TaprootHtlcAcceptedRevoke StandardWitnessType = 22
+
+	// TaprootHtlcOfferedTimeoutSecondLevel is a witness that allows us to
+	// sweep the taproot output of a confirmed second-level HTLC timeout
+	// transaction after the CSV delay.
+	TaprootHtlcOfferedTimeoutSecondLevel StandardWitnessType = 23
+
+	// TaprootHtlcOfferedTimeoutSecondLevelInputConfirmed is a witness
+	// that allows us to spend a taproot HTLC output that we offered with
+	// the second-level HTLC timeout transaction.
+	TaprootHtlcOfferedTimeoutSecondLevelInputConfirmed StandardWitnessType = 24
+
+	// TaprootHtlcAcceptedSuccessSecondLevel is a witness that allows us
+	// to sweep the taproot output of a confirmed second-level HTLC success
+	// transaction after the CSV delay.
+	TaprootHtlcAcceptedSuccessSecondLevel StandardWitnessType = 25
+
+	// TaprootHtlcAcceptedSuccessSecondLevelInputConfirmed is a witness
+	// that allows us to spend a taproot HTLC output offered to us with
+	// the second-level HTLC success transaction.
+	TaprootHtlcAcceptedSuccessSecondLevelInputConfirmed StandardWitnessType = 26
+
+	// TaprootHtlcOfferedRemoteTimeout is a witness that allows us to sweep
+	// a taproot HTLC that we offered to the remote party which lies in
+	// the commitment transaction of the remote party, after its absolute
+	// CLTV timeout.
+	TaprootHtlcOfferedRemoteTimeout StandardWitnessType = 27
+
+	// TaprootHtlcAcceptedRemoteSuccess is a witness that allows us to
+	// sweep a taproot HTLC that was offered to us by the remote party
+	// with the preimage, in the case that the remote party goes to chain.
+	TaprootHtlcAcceptedRemoteSuccess StandardWitnessType = 28
+
+	// TaprootHtlcSecondLevelRevoke is a witness that allows us to sweep
+	// the taproot output of a second-level HTLC transaction spent from a
+	// revoked commitment of the remote party.
+	TaprootHtlcSecondLevelRevoke StandardWitnessType = 29
 )
 
 // String returns a human readable version of the target WitnessType.
@@ -203,6 +268,45 @@ func (wt StandardWitnessType) String() string {
 	case NestedWitnessKeyHash:
 		return "NestedWitnessKeyHash"
 
+	case TaprootCommitmentTimeLock:
+		return "TaprootCommitmentTimeLock"
+
+	case TaprootCommitmentToRemoteConfirmed:
+		return "TaprootCommitmentToRemoteConfirmed"
+
+	case TaprootCommitmentAnchor:
+		return "TaprootCommitmentAnchor"
+
+	case TaprootCommitmentRevoke:
+		return "TaprootCommitmentRevoke"
+
+	case TaprootHtlcOfferedRevoke:
+		return "TaprootHtlcOfferedRevoke"
+
+	case TaprootHtlcAcceptedRevoke:
+		return "TaprootHtlcAcceptedRevoke"
+
+	case TaprootHtlcOfferedTimeoutSecondLevel:
+		return "TaprootHtlcOfferedTimeoutSecondLevel"
+
+	case TaprootHtlcOfferedTimeoutSecondLevelInputConfirmed:
+		return "TaprootHtlcOfferedTimeoutSecondLevelInputConfirmed"
+
+	case TaprootHtlcAcceptedSuccessSecondLevel:
+		return "TaprootHtlcAcceptedSuccessSecondLevel"
+
+	case TaprootHtlcAcceptedSuccessSecondLevelInputConfirmed:
+		return "TaprootHtlcAcceptedSuccessSecondLevelInputConfirmed"
+
+	case TaprootHtlcOfferedRemoteTimeout:
+		return "TaprootHtlcOfferedRemoteTimeout"
+
+	case TaprootHtlcAcceptedRemoteSuccess:
+		return "TaprootHtlcAcceptedRemoteSuccess"
+
+	case TaprootHtlcSecondLevelRevoke:
+		return "TaprootHtlcSecondLevelRevoke"
+
 	default:
 		return fmt.Sprintf("Unknown WitnessType: %v", uint32(wt))
 	}
@@ -350,6 +454,74 @@ func (wt StandardWitnessType) WitnessGenerator(signer Signer,
 				Witness: witness,
 			}, nil
 
+		case TaprootCommitmentTimeLock,
+			TaprootHtlcOfferedTimeoutSecondLevel,
+			TaprootHtlcAcceptedSuccessSecondLevel:
+
+			witness, err := TaprootCommitSpendTimeout(
+				signer, desc, tx,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Script{
+				Witness: witness,
+			}, nil
+
+		case TaprootCommitmentToRemoteConfirmed:
+			witness, err := TaprootCommitSpendToRemote(
+				signer, desc, tx,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Script{
+				Witness: witness,
+			}, nil
+
+		case TaprootCommitmentRevoke:
+			witness, err := TaprootCommitSpendRevoke(
+				signer, desc, tx,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Script{
+				Witness: witness,
+			}, nil
+
+		// The anchor and all revoked HTLC outputs are spent over the
+		// key path.
+		case TaprootCommitmentAnchor, TaprootHtlcOfferedRevoke,
+			TaprootHtlcAcceptedRevoke, TaprootHtlcSecondLevelRevoke:
+
+			witness, err := TaprootKeySpend(signer, desc, tx)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Script{
+				Witness: witness,
+			}, nil
+
+		case TaprootHtlcOfferedRemoteTimeout:
+			// We pass in a value of -1 for the timeout, as we
+			// expect the caller to have already set the lock time
+			// value.
+			witness, err := TaprootReceiverHtlcSpendTimeout(
+				signer, desc, tx, -1,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Script{
+				Witness: witness,
+			}, nil
+
 		case WitnessKeyHash:
 			fallthrough
 		case NestedWitnessKeyHash:
@@ -443,6 +615,52 @@ func (wt StandardWitnessType) SizeUpperBound() (int, bool, error) {
 	// The revocation output of a second level output of an HTLC.
 	case HtlcSecondLevelRevoke:
 		return ToLocalPenaltyWitnessSize, false, nil
+
+	// Taproot outputs on a past commitment transaction that pay directly
+	// to us.
+	case TaprootCommitmentTimeLock:
+		return TaprootToLocalWitnessSize, false, nil
+
+	// 1 CSV time locked taproot output to us on remote commitment.
+	case TaprootCommitmentToRemoteConfirmed:
+		return TaprootToRemoteWitnessSize, false, nil
+
+	// Taproot outputs that are spent over the key path.
+	case TaprootCommitmentAnchor, TaprootHtlcOfferedRevoke,
+		TaprootHtlcAcceptedRevoke, TaprootHtlcSecondLevelRevoke:
+
+		return TaprootKeyPathWitnessSize, false, nil
+
+	// The revocation leaf of a revoked taproot commitment transaction.
+	case TaprootCommitmentRevoke:
+		return TaprootToLocalRevokeWitnessSize, false, nil
+
+	// Taproot second layer HTLC's that have confirmed within the chain,
+	// and the output they produced is now mature enough to sweep.
+	case TaprootHtlcOfferedTimeoutSecondLevel,
+		TaprootHtlcAcceptedSuccessSecondLevel:
+
+		return TaprootSecondLevelHtlcWitnessSize, false, nil
+
+	// Input to the outgoing taproot HTLC second layer timeout
+	// transaction.
+	case TaprootHtlcOfferedTimeoutSecondLevelInputConfirmed:
+		return TaprootOfferedHtlcTimeoutWitnessSize, false, nil
+
+	// Input to the incoming taproot HTLC second layer success
+	// transaction.
+	case TaprootHtlcAcceptedSuccessSecondLevelInputConfirmed:
+		return TaprootAcceptedHtlcSuccessWitnessSize, false, nil
+
+	// A taproot HTLC on the commitment transaction of the remote party,
+	// that has had its absolute timelock expire.
+	case TaprootHtlcOfferedRemoteTimeout:
+		return TaprootAcceptedHtlcTimeoutWitnessSize, false, nil
+
+	// A taproot HTLC on the commitment transaction of the remote party,
+	// that can be swept with the preimage.
+	case TaprootHtlcAcceptedRemoteSuccess:
+		return TaprootOfferedHtlcSuccessWitnessSize, false, nil
 	}
 
 	return 0, false, fmt.Errorf("unexpected witness type: %v", wt)
@@ -27,6 +27,10 @@ var (
 	// ErrShuttingDown is returned when an operation failed because the
 	// invoice registry is shutting down.
 	ErrShuttingDown = errors.New("invoice registry shutting down")
+
+	// ErrInvoiceNotCanceled is returned when an invoice is attempted to be
+	// deleted that isn't canceled.
+	ErrInvoiceNotCanceled = errors.New("invoice not canceled")
 )
 
 const (
@@ -69,6 +73,12 @@ type RegistryConfig struct {
 	// canceled invoices on the fly.
 	GcCanceledInvoicesOnTheFly bool
 
+	// GcCanceledInvoicesAfter if non-zero, we'll garbage collect canceled
+	// invoices in the background once this duration has passed since they
+	// were canceled. Invoices canceled before the cancel time was recorded
+	// are aged from their creation date instead.
+	GcCanceledInvoicesAfter time.Duration
+
 	// KeysendHoldTime indicates for how long we want to accept and hold
 	// spontaneous keysend payments.
 	KeysendHoldTime time.Duration
@@ -95,6 +105,23 @@ func (r *htlcReleaseEvent) Less(other queue.PriorityQueueItem) bool {
 	return r.releaseTime.Before(other.(*htlcReleaseEvent).releaseTime)
 }
 
+// invoiceGcEvent describes the garbage collection of a canceled invoice.
+type invoiceGcEvent struct {
+	// deleteRef references the invoice to delete.
+	deleteRef channeldb.InvoiceDeleteRef
+
+	// deleteTime is the time at which to delete the invoice.
+	deleteTime time.Time
+}
+
+// Less is used to order PriorityQueueItem's by their delete time such that
+// items with the older delete time are at the top of the queue.
+//
+// NOTE: Part of the queue.PriorityQueueItem interface.
+func (g *invoiceGcEvent) Less(other queue.PriorityQueueItem) bool {
+	return g.deleteTime.Before(other.(*invoiceGcEvent).deleteTime)
+}
+
 // InvoiceRegistry is a central registry of all the outstanding invoices
 // created by the daemon. The registry is a thin wrapper around a map in order
 // to ensure that all updates/reads are thread safe.
@@ -130,6 +157,10 @@ type InvoiceRegistry struct {
 	// auto-released.
 	htlcAutoReleaseChan chan *htlcReleaseEvent
 
+	// invoiceGcChan contains the canceled invoices that need to be
+	// garbage collected.
+	invoiceGcChan chan *invoiceGcEvent
+
 	expiryWatcher *InvoiceExpiryWatcher
 
 	wg   sync.WaitGroup
@@ -154,6 +185,7 @@ func NewRegistry(cdb *channeldb.DB, expiryWatcher *InvoiceExpiryWatcher,
 		hodlReverseSubscriptions:  make(map[chan<- interface{}]map[channeldb.CircuitKey]struct{}),
 		cfg:                       cfg,
 		htlcAutoReleaseChan:       make(chan *htlcReleaseEvent),
+		invoiceGcChan:             make(chan *invoiceGcEvent),
 		expiryWatcher:             expiryWatcher,
 		quit:                      make(chan struct{}),
 	}
@@ -166,6 +198,7 @@ func (i *InvoiceRegistry) scanInvoicesOnStart() error {
 	var (
 		pending   []invoiceExpiry
 		removable []channeldb.InvoiceDeleteRef
+		gcEvents  []*invoiceGcEvent
 	)
 
 	reset := func() {
@@ -176,6 +209,7 @@ func (i *InvoiceRegistry) scanInvoicesOnStart() error {
 		// to retry for serializability).
 		pending = nil
 		removable = make([]channeldb.InvoiceDeleteRef, 0)
+		gcEvents = nil
 	}
 
 	scanFunc := func(
@@ -193,17 +227,21 @@ func (i *InvoiceRegistry) scanInvoicesOnStart() error {
 			// canceled. Invoices that are expired but not yet
 			// canceled, will be queued up for cancellation after
 			// startup and will be deleted afterwards.
-			ref := channeldb.InvoiceDeleteRef{
-				PayHash:     paymentHash,
-				AddIndex:    invoice.AddIndex,
-				SettleIndex: invoice.SettleIndex,
-			}
-
-			if invoice.Terms.PaymentAddr != channeldb.BlankPayAddr {
-				ref.PayAddr = &invoice.Terms.PaymentAddr
-			}
+			removable = append(
+				removable, newInvoiceDeleteRef(
+					paymentHash, invoice,
+				),
+			)
+		} else if i.cfg.GcCanceledInvoicesAfter > 0 &&
+			invoice.State == channeldb.ContractCanceled {
 
-			removable = append(removable, ref)
+			// Otherwise schedule the canceled invoice for garbage
+			// collection once it is old enough.
+			gcEvents = append(
+				gcEvents, i.newInvoiceGcEvent(
+					paymentHash, invoice,
+				),
+			)
 		}
 		return nil
 	}
@@ -228,6 +266,14 @@ func (i *InvoiceRegistry) scanInvoicesOnStart() error {
 		}
 	}
 
+	log.Debugf("Scheduling %d canceled invoices for garbage collection",
+		len(gcEvents))
+	for _, event := range gcEvents {
+		if err := i.scheduleInvoiceGc(event); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -241,8 +287,9 @@ func (i *InvoiceRegistry) Start() error {
 		return err
 	}
 
-	i.wg.Add(1)
+	i.wg.Add(2)
 	go i.invoiceEventLoop()
+	go i.invoiceGcLoop()
 
 	// Now scan all pending and removable invoices to the expiry watcher or
 	// delete them.
@@ -384,6 +431,109 @@ func (i *InvoiceRegistry) invoiceEventLoop() {
 	}
 }
 
+// invoiceGcLoop is the dedicated goroutine responsible for garbage collecting
+// canceled invoices once they are old enough. It is separate from the main
+// event loop and never acquires the registry lock, so that invoices can be
+// scheduled for garbage collection while the lock is held.
+func (i *InvoiceRegistry) invoiceGcLoop() {
+	defer i.wg.Done()
+
+	// Set up a heap for the invoices to garbage collect.
+	gcHeap := &queue.PriorityQueue{}
+
+	for {
+		// If there is something to delete, set up a delete tick
+		// channel.
+		var nextGcTick <-chan time.Time
+		if gcHeap.Len() > 0 {
+			head := gcHeap.Top().(*invoiceGcEvent)
+			nextGcTick = i.tickAt(head.deleteTime)
+		}
+
+		select {
+		// A canceled invoice was scheduled for garbage collection.
+		case event := <-i.invoiceGcChan:
+			gcHeap.Push(event)
+
+		// The invoice at the top of the heap needs to be deleted.
+		case <-nextGcTick:
+			event := gcHeap.Pop().(*invoiceGcEvent)
+			ref := event.deleteRef
+
+			err := i.cdb.DeleteInvoice(
+				[]channeldb.InvoiceDeleteRef{ref},
+			)
+			switch {
+			// The invoice may have been deleted in the meantime,
+			// for example through the rpc.
+			case err == channeldb.ErrInvoiceNotFound:
+				log.Debugf("Invoice(%v): already deleted",
+					ref.PayHash)
+
+			case err != nil:
+				log.Errorf("Invoice(%v): unable to garbage "+
+					"collect canceled invoice: %v",
+					ref.PayHash, err)
+
+			default:
+				log.Debugf("Invoice(%v): garbage collected "+
+					"canceled invoice", ref.PayHash)
+			}
+
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// newInvoiceGcEvent returns the garbage collection event of the given
+// canceled invoice.
+func (i *InvoiceRegistry) newInvoiceGcEvent(payHash lntypes.Hash,
+	invoice *channeldb.Invoice) *invoiceGcEvent {
+
+	// Invoices that were canceled before the cancel time was recorded
+	// don't have one, so we fall back to their creation date.
+	canceled := invoice.CancelDate
+	if canceled.IsZero() {
+		canceled = invoice.CreationDate
+	}
+
+	return &invoiceGcEvent{
+		deleteRef:  newInvoiceDeleteRef(payHash, invoice),
+		deleteTime: canceled.Add(i.cfg.GcCanceledInvoicesAfter),
+	}
+}
+
+// scheduleInvoiceGc schedules a canceled invoice for garbage collection. It is
+// safe to call while holding the registry lock.
+func (i *InvoiceRegistry) scheduleInvoiceGc(event *invoiceGcEvent) error {
+	select {
+	case i.invoiceGcChan <- event:
+		return nil
+
+	case <-i.quit:
+		return ErrShuttingDown
+	}
+}
+
+// newInvoiceDeleteRef returns the reference that is required to delete the
+// given invoice.
+func newInvoiceDeleteRef(payHash lntypes.Hash,
+	invoice *channeldb.Invoice) channeldb.InvoiceDeleteRef {
+
+	ref := channeldb.InvoiceDeleteRef{
+		PayHash:      payHash,
+		AddIndex:     invoice.AddIndex,
+		SettleIndex:  invoice.SettleIndex,
+		CreationDate: invoice.CreationDate,
+	}
+	if invoice.Terms.PaymentAddr != channeldb.BlankPayAddr {
+		ref.PayAddr = &invoice.Terms.PaymentAddr
+	}
+
+	return ref
+}
+
 // dispatchToSingleClients passes the supplied event to all notification clients
 // that subscribed to all the invoice this event applies to.
 func (i *InvoiceRegistry) dispatchToSingleClients(event *invoiceEvent) {
@@ -1296,18 +1446,11 @@ func (i *InvoiceRegistry) cancelInvoiceImpl(payHash lntypes.Hash,
 
 	// Attempt to also delete the invoice if requested through the registry
 	// config.
-	if i.cfg.GcCanceledInvoicesOnTheFly {
+	switch {
+	case i.cfg.GcCanceledInvoicesOnTheFly:
 		// Assemble the delete reference and attempt to delete through
 		// the invocice from the DB.
-		deleteRef := channeldb.InvoiceDeleteRef{
-			PayHash:     payHash,
-			AddIndex:    invoice.AddIndex,
-			SettleIndex: invoice.SettleIndex,
-		}
-		if invoice.Terms.PaymentAddr != channeldb.BlankPayAddr {
-			deleteRef.PayAddr = &invoice.Terms.PaymentAddr
-		}
-
+		deleteRef := newInvoiceDeleteRef(payHash, invoice)
 		err = i.cdb.DeleteInvoice(
 			[]channeldb.InvoiceDeleteRef{deleteRef},
 		)
@@ -1318,11 +1461,45 @@ func (i *InvoiceRegistry) cancelInvoiceImpl(payHash lntypes.Hash,
 			log.Warnf("Invoice%v could not be deleted: %v",
 				ref, err)
 		}
+
+	// Otherwise schedule the invoice for garbage collection once it is old
+	// enough. This also covers the expired invoices that are canceled by
+	// the expiry watcher.
+	case i.cfg.GcCanceledInvoicesAfter > 0:
+		err := i.scheduleInvoiceGc(i.newInvoiceGcEvent(payHash, invoice))
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// DeleteCanceledInvoice deletes the canceled invoice with the given payment
+// hash. The add and settle indexes of deleted invoices are never reused, so
+// that clients which subscribed to invoice updates by index keep a consistent
+// view.
+func (i *InvoiceRegistry) DeleteCanceledInvoice(payHash lntypes.Hash) error {
+	i.Lock()
+	defer i.Unlock()
+
+	ref := channeldb.InvoiceRefByHash(payHash)
+	invoice, err := i.cdb.LookupInvoice(ref)
+	if err != nil {
+		return err
+	}
+
+	if invoice.State != channeldb.ContractCanceled {
+		return ErrInvoiceNotCanceled
+	}
+
+	log.Debugf("Invoice%v: deleting canceled invoice", ref)
+
+	return i.cdb.DeleteInvoice([]channeldb.InvoiceDeleteRef{
+		newInvoiceDeleteRef(payHash, &invoice),
+	})
+}
+
 // notifyClients notifies all currently registered invoice notification clients
 // of a newly added/settled invoice.
 func (i *InvoiceRegistry) notifyClients(hash lntypes.Hash,
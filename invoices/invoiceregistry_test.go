@@ -1119,6 +1119,165 @@ func TestOldInvoiceRemovalOnStart(t *testing.T) {
 	require.Equal(t, expected, response.Invoices)
 }
 
+// TestCanceledInvoiceGc tests that canceled invoices are garbage collected in
+// the background once enough time has passed since they were canceled, and
+// that the add index of deleted invoices isn't reused.
+func TestCanceledInvoiceGc(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(testTime)
+	cdb, cleanup, err := newTestChannelDB(testClock)
+	defer cleanup()
+
+	require.NoError(t, err)
+
+	const gcAfter = time.Hour
+	cfg := RegistryConfig{
+		FinalCltvRejectDelta:    testFinalCltvRejectDelta,
+		Clock:                   testClock,
+		GcCanceledInvoicesAfter: gcAfter,
+	}
+
+	expiryWatcher := NewInvoiceExpiryWatcher(
+		cfg.Clock, 0, uint32(testCurrentHeight), nil, newMockNotifier(),
+	)
+	registry := NewRegistry(cdb, expiryWatcher, &cfg)
+
+	// addInvoice adds an invoice with the given creation date, cancel date
+	// and state directly to the database and returns its payment hash.
+	addInvoice := func(creationDate, cancelDate time.Time,
+		state channeldb.ContractState) lntypes.Hash {
+
+		var preimage lntypes.Preimage
+		_, err := rand.Read(preimage[:])
+		require.NoError(t, err)
+
+		invoice := newTestInvoice(t, preimage, creationDate, 0)
+		invoice.State = state
+		invoice.CancelDate = cancelDate
+
+		_, err = cdb.AddInvoice(invoice, preimage.Hash())
+		require.NoError(t, err)
+
+		return preimage.Hash()
+	}
+
+	// assertDeleted waits until the invoice with the given hash has been
+	// deleted from the database.
+	assertDeleted := func(hash lntypes.Hash) {
+		require.Eventually(t, func() bool {
+			_, err := cdb.LookupInvoice(
+				channeldb.InvoiceRefByHash(hash),
+			)
+			return err == channeldb.ErrInvoiceNotFound
+		}, time.Second, 10*time.Millisecond)
+	}
+
+	// assertExists asserts that the invoice with the given hash is still
+	// in the database.
+	assertExists := func(hash lntypes.Hash) {
+		_, err := cdb.LookupInvoice(channeldb.InvoiceRefByHash(hash))
+		require.NoError(t, err)
+	}
+
+	// Prefill the database with a canceled invoice that was canceled long
+	// enough ago to be collected right away, an old invoice that was only
+	// canceled recently, an old canceled invoice without a cancel date,
+	// which is aged from its creation date, and an old settled invoice
+	// that must never be collected.
+	oldCanceled := addInvoice(
+		testTime.Add(-3*gcAfter), testTime.Add(-2*gcAfter),
+		channeldb.ContractCanceled,
+	)
+	newCanceled := addInvoice(
+		testTime.Add(-3*gcAfter), testTime.Add(-gcAfter/2),
+		channeldb.ContractCanceled,
+	)
+	legacyCanceled := addInvoice(
+		testTime.Add(-2*gcAfter), time.Time{},
+		channeldb.ContractCanceled,
+	)
+	settled := addInvoice(
+		testTime.Add(-2*gcAfter), time.Time{},
+		channeldb.ContractSettled,
+	)
+
+	require.NoError(t, registry.Start())
+	defer registry.Stop()
+
+	assertDeleted(oldCanceled)
+	assertDeleted(legacyCanceled)
+	assertExists(newCanceled)
+
+	// Add an old invoice through the registry and cancel it, which should
+	// schedule it for garbage collection as well. Its age is measured
+	// from the time it is canceled rather than from its creation date.
+	var preimage lntypes.Preimage
+	_, err = rand.Read(preimage[:])
+	require.NoError(t, err)
+
+	invoice := newTestInvoice(
+		t, preimage, testTime.Add(-2*gcAfter), 24*time.Hour,
+	)
+	addIndex, err := registry.AddInvoice(invoice, preimage.Hash())
+	require.NoError(t, err)
+	require.EqualValues(t, 5, addIndex)
+	require.NoError(t, registry.CancelInvoice(preimage.Hash()))
+
+	canceled, err := registry.LookupInvoice(preimage.Hash())
+	require.NoError(t, err)
+	require.Equal(t, testTime, canceled.CancelDate)
+	assertExists(preimage.Hash())
+
+	// Once the prefilled canceled invoice is old enough, it should be
+	// deleted while the newly canceled invoice is kept.
+	testClock.SetTime(testTime.Add(gcAfter / 2))
+	assertDeleted(newCanceled)
+	assertExists(preimage.Hash())
+
+	testClock.SetTime(testTime.Add(gcAfter))
+	assertDeleted(preimage.Hash())
+	assertExists(settled)
+
+	// The add index of the deleted invoices must not be reused, so that
+	// clients that subscribe to invoices by index don't miss new ones.
+	_, err = rand.Read(preimage[:])
+	require.NoError(t, err)
+
+	invoice = newTestInvoice(t, preimage, testTime, 24*time.Hour)
+	addIndex, err = registry.AddInvoice(invoice, preimage.Hash())
+	require.NoError(t, err)
+	require.EqualValues(t, 6, addIndex)
+}
+
+// TestDeleteCanceledInvoice tests that only canceled invoices can be deleted
+// through the registry.
+func TestDeleteCanceledInvoice(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	hash := testInvoicePreimage.Hash()
+	_, err := ctx.registry.AddInvoice(testInvoice, hash)
+	require.NoError(t, err)
+
+	// An open invoice can't be deleted.
+	err = ctx.registry.DeleteCanceledInvoice(hash)
+	require.Equal(t, ErrInvoiceNotCanceled, err)
+
+	// Once canceled, the invoice can be deleted.
+	require.NoError(t, ctx.registry.CancelInvoice(hash))
+	require.NoError(t, ctx.registry.DeleteCanceledInvoice(hash))
+
+	_, err = ctx.registry.LookupInvoice(hash)
+	require.Equal(t, channeldb.ErrInvoiceNotFound, err)
+
+	// Deleting an unknown invoice fails.
+	err = ctx.registry.DeleteCanceledInvoice(hash)
+	require.Equal(t, channeldb.ErrInvoiceNotFound, err)
+}
+
 // TestHeightExpiryWithRegistry tests our height-based invoice expiry for
 // invoices paid with single and multiple htlcs, testing the case where the
 // invoice is settled before expiry (and thus not canceled), and the case
@@ -120,6 +120,18 @@ const (
 	// ResultAmpReconstruction is returned when the derived child
 	// hash/preimage pairs were invalid for at least one HTLC in the set.
 	ResultAmpReconstruction
+
+	// ResultTrampolineFeeInsufficient is returned when a trampoline
+	// payment that is delegated to us doesn't leave enough fees for us.
+	ResultTrampolineFeeInsufficient
+
+	// ResultTrampolineExpiryTooSoon is returned when a trampoline payment
+	// that is delegated to us doesn't leave enough cltv delta for us.
+	ResultTrampolineExpiryTooSoon
+
+	// ResultTrampolineFailure is returned when we failed to relay a
+	// trampoline payment that is delegated to us.
+	ResultTrampolineFailure
 )
 
 // String returns a string representation of the result.
@@ -189,6 +201,15 @@ func (f FailResolutionResult) FailureString() string {
 	case ResultAmpReconstruction:
 		return "amp reconstruction failed"
 
+	case ResultTrampolineFeeInsufficient:
+		return "trampoline fee insufficient"
+
+	case ResultTrampolineExpiryTooSoon:
+		return "trampoline expiry too soon"
+
+	case ResultTrampolineFailure:
+		return "trampoline payment failed"
+
 	default:
 		return "unknown failure resolution result"
 	}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"time"
 )
 
 const (
@@ -34,6 +36,38 @@ func fileExists(path string) bool {
 	return true
 }
 
+// lastCompactionDate returns the date the given database file was last
+// compacted or a zero time.Time if no compaction was recorded before. The
+// compaction date is read from a file in the same directory and with the same
+// name as the DB file, but with the suffix ".last-compacted".
+func lastCompactionDate(dbFile string) (time.Time, error) {
+	zeroTime := time.Unix(0, 0)
+
+	tsFile := fmt.Sprintf("%s%s", dbFile, LastCompactionFileNameSuffix)
+	if !fileExists(tsFile) {
+		return zeroTime, nil
+	}
+
+	tsBytes, err := ioutil.ReadFile(tsFile)
+	if err != nil {
+		return zeroTime, err
+	}
+
+	tsNano := byteOrder.Uint64(tsBytes)
+	return time.Unix(0, int64(tsNano)), nil
+}
+
+// updateLastCompactionDate stores the current time as a timestamp in a file
+// in the same directory and with the same name as the DB file, but with the
+// suffix ".last-compacted".
+func updateLastCompactionDate(dbFile string) error {
+	var tsBytes [8]byte
+	byteOrder.PutUint64(tsBytes[:], uint64(time.Now().UnixNano()))
+
+	tsFile := fmt.Sprintf("%s%s", dbFile, LastCompactionFileNameSuffix)
+	return ioutil.WriteFile(tsFile, tsBytes[:], 0600)
+}
+
 // GetTestBackend opens (or creates if doesn't exist) a bbolt or etcd
 // backed database (for testing), and returns a kvdb.Backend and a cleanup
 // func. Whether to create/open bbolt or embedded etcd database is based
@@ -44,7 +78,10 @@ func GetTestBackend(path, name string) (Backend, func(), error) {
 	empty := func() {}
 
 	if TestBackend == LdbBackendName {
-		db, err := GetLdbBackend(path, name)
+		db, err := GetLdbBackend(&LdbBackendConfig{
+			DBPath:     path,
+			DBFileName: name,
+		})
 		if err != nil {
 			return nil, nil, err
 		}
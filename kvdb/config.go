@@ -26,6 +26,39 @@ const (
 	// DefaultDBTimeout specifies the default timeout value when opening
 	// the bbolt database.
 	DefaultDBTimeout = time.Second * 60
+
+	// DefaultLdbBlockCacheSize is the default size in bytes of the
+	// leveldb block cache.
+	DefaultLdbBlockCacheSize = 8 * 1024 * 1024
+
+	// DefaultLdbWriteBufferSize is the default size in bytes of the
+	// leveldb write buffer (memtable).
+	DefaultLdbWriteBufferSize = 4 * 1024 * 1024
+
+	// DefaultLdbCompactionTableSize is the default size in bytes of a
+	// single sorted table created by a leveldb compaction.
+	DefaultLdbCompactionTableSize = 2 * 1024 * 1024
+
+	// DefaultLdbCompactionL0Trigger is the default number of level-0
+	// tables that trigger a leveldb compaction.
+	DefaultLdbCompactionL0Trigger = 4
+
+	// DefaultLdbWriteL0SlowdownTrigger is the default number of level-0
+	// tables at which leveldb starts slowing down writes.
+	DefaultLdbWriteL0SlowdownTrigger = 8
+
+	// DefaultLdbWriteL0PauseTrigger is the default number of level-0
+	// tables at which leveldb pauses writes until a compaction finished.
+	DefaultLdbWriteL0PauseTrigger = 12
+
+	// DefaultLdbBloomFilterBits is the default number of bits per key
+	// used for the leveldb bloom filter.
+	DefaultLdbBloomFilterBits = 10
+
+	// DefaultLdbAutoCompactMinAge is the default minimum time that must
+	// have passed since a leveldb database was last compacted for the
+	// compaction to be considered again.
+	DefaultLdbAutoCompactMinAge = time.Hour * 24 * 7
 )
 
 // BoltConfig holds bolt configuration.
@@ -41,4 +74,37 @@ type BoltConfig struct {
 
 // LdbConfig holds goleveldb configuration.
 type LdbConfig struct {
+	BlockCacheSize int `long:"block-cache-size" description:"The size in bytes of the cache for uncompressed data blocks."`
+
+	WriteBufferSize int `long:"write-buffer-size" description:"The size in bytes of the in-memory write buffer that is flushed to a sorted table on disk once full. Larger values increase write performance at the expense of memory usage and startup time."`
+
+	CompactionTableSize int `long:"compaction-table-size" description:"The size in bytes of a single sorted table created by a compaction."`
+
+	CompactionL0Trigger int `long:"compaction-l0-trigger" description:"The number of level-0 tables that triggers a compaction."`
+
+	WriteL0SlowdownTrigger int `long:"write-l0-slowdown-trigger" description:"The number of level-0 tables at which writes are slowed down."`
+
+	WriteL0PauseTrigger int `long:"write-l0-pause-trigger" description:"The number of level-0 tables at which writes are paused until a compaction finished."`
+
+	BloomFilterBits int `long:"bloom-filter-bits" description:"The number of bits per key used for the bloom filter that speeds up lookups of keys that don't exist. Set to 0 to disable the bloom filter."`
+
+	AutoCompact bool `long:"auto-compact" description:"Whether the databases used within lnd should automatically be compacted on every startup (and if the database has the configured minimum age). Unlike bolt, leveldb compacts the database in place and doesn't require additional disk space for a copy of the database."`
+
+	AutoCompactMinAge time.Duration `long:"auto-compact-min-age" description:"How long ago the last compaction of a database must be for it to be considered for auto compaction again. Can be set to 0 to compact on every startup."`
+
+	AutoCompactInterval time.Duration `long:"auto-compact-interval" description:"If set, the databases are also compacted periodically with the given interval while lnd is running. Requires auto-compact to be set."`
+}
+
+// DefaultLdbConfig returns a LdbConfig populated with the default values.
+func DefaultLdbConfig() *LdbConfig {
+	return &LdbConfig{
+		BlockCacheSize:         DefaultLdbBlockCacheSize,
+		WriteBufferSize:        DefaultLdbWriteBufferSize,
+		CompactionTableSize:    DefaultLdbCompactionTableSize,
+		CompactionL0Trigger:    DefaultLdbCompactionL0Trigger,
+		WriteL0SlowdownTrigger: DefaultLdbWriteL0SlowdownTrigger,
+		WriteL0PauseTrigger:    DefaultLdbWriteL0PauseTrigger,
+		BloomFilterBits:        DefaultLdbBloomFilterBits,
+		AutoCompactMinAge:      DefaultLdbAutoCompactMinAge,
+	}
 }
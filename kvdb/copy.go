@@ -0,0 +1,435 @@
+package kvdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	// DefaultCopyTxMaxSize is the default maximum number of key and value
+	// bytes that are written to the destination database in a single
+	// transaction when copying a database from one backend to another.
+	DefaultCopyTxMaxSize = 65536
+)
+
+var (
+	// ErrSourceTxRetried is returned when the read transaction of the
+	// source database is retried during a copy or dump. Parts of the
+	// copy or dump are already written to the destination at that point,
+	// so it can't be continued.
+	ErrSourceTxRetried = errors.New("source read transaction was " +
+		"retried")
+
+	// ErrDestinationNotEmpty is returned when attempting to copy a
+	// database into a destination backend that already contains top-level
+	// buckets.
+	ErrDestinationNotEmpty = errors.New("destination database is not " +
+		"empty")
+)
+
+// sequenceBucket is implemented by read buckets of backends that are able to
+// report the bucket sequence without requiring a read/write transaction.
+type sequenceBucket interface {
+	Sequence() uint64
+}
+
+// bucketSequence returns the sequence number of the given bucket or zero if
+// the backend doesn't expose it on read-only buckets.
+func bucketSequence(b RBucket) uint64 {
+	if seqBucket, ok := b.(sequenceBucket); ok {
+		return seqBucket.Sequence()
+	}
+
+	return 0
+}
+
+// copyWalkFunc is the type of the function called for each key (buckets and
+// "normal" values) discovered by walkBackend. keys is the list of keys to
+// descend to the bucket owning the discovered key/value pair k/v. For buckets
+// the value is nil and seq holds the sequence of the bucket.
+type copyWalkFunc func(keys [][]byte, k, v []byte, seq uint64) error
+
+// walkBackend recursively walks all top-level buckets of the given backend
+// within a single read transaction, calling walkFn for each key it finds.
+// Keys of a bucket are visited in lexicographical order, independent of the
+// order the backend itself returns them in, so the walk is deterministic
+// across backends. Backends like etcd may retry the read transaction, in which
+// case the walk starts over. The reset function is called before each attempt
+// and must discard the state of the previous one.
+func walkBackend(db Backend, walkFn copyWalkFunc, reset func()) error {
+	return View(db, func(tx RTx) error {
+		var topLevel [][]byte
+		err := tx.ForEachBucket(func(k []byte) error {
+			topLevel = append(topLevel, append([]byte(nil), k...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		sortKeys(topLevel)
+
+		for _, name := range topLevel {
+			log.Debugf("Walking top level bucket %s", name)
+
+			bucket := tx.ReadBucket(name)
+			if bucket == nil {
+				return fmt.Errorf("top level bucket %x not "+
+					"found", name)
+			}
+
+			err := walkBucket(
+				bucket, nil, name, bucketSequence(bucket),
+				walkFn,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, reset)
+}
+
+// walkBucket recursively walks through a bucket, calling fn for the bucket
+// itself first and then for each of its children.
+func walkBucket(b RBucket, keyPath [][]byte, name []byte, seq uint64,
+	fn copyWalkFunc) error {
+
+	if err := fn(keyPath, name, nil, seq); err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	err := b.ForEach(func(k, _ []byte) error {
+		keys = append(keys, append([]byte(nil), k...))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sortKeys(keys)
+
+	// Copy the key path so that appends in recursive calls don't
+	// overwrite the path of their siblings.
+	childPath := make([][]byte, len(keyPath), len(keyPath)+1)
+	copy(childPath, keyPath)
+	childPath = append(childPath, name)
+
+	for _, k := range keys {
+		if nested := b.NestedReadBucket(k); nested != nil {
+			err := walkBucket(
+				nested, childPath, k, bucketSequence(nested),
+				fn,
+			)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// Make sure a nil value is never confused with a bucket by the
+		// walk function.
+		v := b.Get(k)
+		if v == nil {
+			v = []byte{}
+		}
+
+		if err := fn(childPath, k, v, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortKeys sorts the given keys in lexicographical order.
+func sortKeys(keys [][]byte) {
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+}
+
+// chunkedWriter writes the elements reported by a walk of a database into a
+// destination backend, committing the current transaction every time the size
+// of the written keys and values would exceed txMaxSize.
+type chunkedWriter struct {
+	db        Backend
+	tx        RwTx
+	size      int64
+	txMaxSize int64
+}
+
+// newChunkedWriter makes sure the destination backend doesn't contain any
+// top-level buckets yet and returns a new chunkedWriter for it.
+func newChunkedWriter(db Backend, txMaxSize int64) (*chunkedWriter, error) {
+	if txMaxSize <= 0 {
+		txMaxSize = DefaultCopyTxMaxSize
+	}
+
+	// We refuse to merge into an existing database as we'd otherwise not
+	// be able to verify the result.
+	empty := true
+	err := View(db, func(tx RTx) error {
+		return tx.ForEachBucket(func(_ []byte) error {
+			empty = false
+			return nil
+		})
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+	if !empty {
+		return nil, ErrDestinationNotEmpty
+	}
+
+	tx, err := db.BeginReadWriteTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkedWriter{
+		db:        db,
+		tx:        tx,
+		txMaxSize: txMaxSize,
+	}, nil
+}
+
+// write writes a single bucket (if v is nil) or key/value pair to the
+// destination. It has the signature of a copyWalkFunc so it can be passed to
+// walkBackend directly.
+func (w *chunkedWriter) write(keys [][]byte, k, v []byte, seq uint64) error {
+	// On each key/value, check if we have exceeded tx size.
+	sz := int64(len(k) + len(v))
+	if w.size+sz > w.txMaxSize {
+		// Commit previous transaction and start a new one.
+		err := w.tx.Commit()
+		w.tx = nil
+		if err != nil {
+			return err
+		}
+
+		w.tx, err = w.db.BeginReadWriteTx()
+		if err != nil {
+			return err
+		}
+		w.size = 0
+	}
+	w.size += sz
+
+	// Create the bucket on the root transaction if this is the first
+	// level.
+	if len(keys) == 0 {
+		if v != nil {
+			return fmt.Errorf("value %x without bucket", k)
+		}
+
+		bucket, err := w.tx.CreateTopLevelBucket(k)
+		if err != nil {
+			return err
+		}
+
+		return bucket.SetSequence(seq)
+	}
+
+	// Otherwise descend to the parent bucket, which must have been
+	// created by an earlier call.
+	parent := w.tx.ReadWriteBucket(keys[0])
+	for _, key := range keys[1:] {
+		if parent == nil {
+			break
+		}
+		parent = parent.NestedReadWriteBucket(key)
+	}
+	if parent == nil {
+		return fmt.Errorf("parent bucket of key %x not found", k)
+	}
+
+	// A nil value denotes a nested bucket.
+	if v == nil {
+		bucket, err := parent.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+
+		return bucket.SetSequence(seq)
+	}
+
+	return parent.Put(k, v)
+}
+
+// commit commits the last pending transaction.
+func (w *chunkedWriter) commit() error {
+	err := w.tx.Commit()
+	w.tx = nil
+
+	return err
+}
+
+// rollback rolls back the current transaction if there is one. It is safe to
+// call rollback after commit.
+func (w *chunkedWriter) rollback() {
+	if w.tx != nil {
+		_ = w.tx.Rollback()
+		w.tx = nil
+	}
+}
+
+// CopyBackend copies the content of every top-level bucket of the source
+// backend into the destination backend. The source is read within a single
+// read transaction so the copy is a consistent snapshot, while the writes to
+// the destination are split into transactions of at most txMaxSize key and
+// value bytes so that large databases can be copied into backends that limit
+// the size of a single transaction. Bucket sequence numbers are carried over
+// if the source backend exposes them. The destination must not contain any
+// top-level buckets.
+func CopyBackend(src, dst Backend, txMaxSize int64) error {
+	w, err := newChunkedWriter(dst, txMaxSize)
+	if err != nil {
+		return err
+	}
+	defer w.rollback()
+
+	// The writes to the destination can't be undone once the first chunk
+	// is committed, so we fail the copy if the read transaction of the
+	// source is retried after the walk started.
+	var started, retried bool
+	write := func(keys [][]byte, k, v []byte, seq uint64) error {
+		if retried {
+			return ErrSourceTxRetried
+		}
+		started = true
+
+		return w.write(keys, k, v, seq)
+	}
+	reset := func() {
+		retried = started
+	}
+
+	if err := walkBackend(src, write, reset); err != nil {
+		return err
+	}
+	if retried {
+		return ErrSourceTxRetried
+	}
+
+	return w.commit()
+}
+
+// BucketChecksums returns a SHA256 checksum for each top-level bucket of the
+// given backend, keyed by the hex encoded bucket name. The checksum commits to
+// the names and sequence numbers of all nested buckets as well as all keys and
+// values and is independent of the storage engine, which makes it suitable to
+// verify that a database was copied between backends without any loss.
+func BucketChecksums(db Backend) (map[string][sha256.Size]byte, error) {
+	var (
+		checksums = make(map[string][sha256.Size]byte)
+		current   *string
+		hasher    = sha256.New()
+	)
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+
+		var sum [sha256.Size]byte
+		copy(sum[:], hasher.Sum(nil))
+		checksums[*current] = sum
+	}
+
+	writeElement := func(b []byte) {
+		var length [4]byte
+		byteOrder.PutUint32(length[:], uint32(len(b)))
+		_, _ = hasher.Write(length[:])
+		_, _ = hasher.Write(b)
+	}
+
+	err := walkBackend(db, func(keys [][]byte, k, v []byte,
+		seq uint64) error {
+
+		// A new top-level bucket starts a new checksum.
+		if len(keys) == 0 {
+			finish()
+			name := hex.EncodeToString(k)
+			current = &name
+			hasher.Reset()
+		}
+
+		// Commit to the full path of the element so that moving a key
+		// to another bucket results in a different checksum.
+		var depth [4]byte
+		byteOrder.PutUint32(depth[:], uint32(len(keys)))
+		_, _ = hasher.Write(depth[:])
+		for _, key := range keys {
+			writeElement(key)
+		}
+		writeElement(k)
+
+		if v == nil {
+			var seqBytes [8]byte
+			byteOrder.PutUint64(seqBytes[:], seq)
+			_, _ = hasher.Write([]byte{0})
+			_, _ = hasher.Write(seqBytes[:])
+
+			return nil
+		}
+
+		_, _ = hasher.Write([]byte{1})
+		writeElement(v)
+
+		return nil
+	}, func() {
+		checksums = make(map[string][sha256.Size]byte)
+		current = nil
+		hasher.Reset()
+	})
+	if err != nil {
+		return nil, err
+	}
+	finish()
+
+	return checksums, nil
+}
+
+// VerifyCopy compares the per bucket checksums of the source and destination
+// backends and returns an error describing the first mismatch found, if any.
+func VerifyCopy(src, dst Backend) error {
+	srcChecksums, err := BucketChecksums(src)
+	if err != nil {
+		return fmt.Errorf("unable to calculate source checksums: %v",
+			err)
+	}
+
+	dstChecksums, err := BucketChecksums(dst)
+	if err != nil {
+		return fmt.Errorf("unable to calculate destination "+
+			"checksums: %v", err)
+	}
+
+	if len(srcChecksums) != len(dstChecksums) {
+		return fmt.Errorf("top level bucket count mismatch, source "+
+			"has %d buckets, destination has %d",
+			len(srcChecksums), len(dstChecksums))
+	}
+
+	for name, srcSum := range srcChecksums {
+		dstSum, ok := dstChecksums[name]
+		if !ok {
+			return fmt.Errorf("top level bucket %s missing in "+
+				"destination", name)
+		}
+
+		if srcSum != dstSum {
+			return fmt.Errorf("checksum mismatch for top level "+
+				"bucket %s: source=%x, destination=%x", name,
+				srcSum, dstSum)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+// +build !js !wasm
+
+package kvdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestBoltDB creates a new bolt database in a temporary directory.
+func openTestBoltDB(t *testing.T) Backend {
+	t.Helper()
+
+	tempDir, err := ioutil.TempDir("", "kvdb-copy")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(tempDir)
+	})
+
+	db, err := GetBoltBackend(&BoltBackendConfig{
+		DBPath:     tempDir,
+		DBFileName: "test.db",
+		DBTimeout:  DefaultDBTimeout,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return db
+}
+
+// retryingBackend wraps a backend and runs each read transaction twice, like
+// etcd does when a read transaction has to be retried after a conflict.
+type retryingBackend struct {
+	Backend
+}
+
+// PrintStats returns all collected stats pretty printed into a string.
+func (r *retryingBackend) PrintStats() string {
+	return ""
+}
+
+// View runs f twice, calling reset before each run.
+func (r *retryingBackend) View(f func(tx walletdb.ReadTx) error,
+	reset func()) error {
+
+	for i := 0; i < 2; i++ {
+		reset()
+		if err := walletdb.View(r.Backend, f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update runs f once, calling reset before.
+func (r *retryingBackend) Update(f func(tx walletdb.ReadWriteTx) error,
+	reset func()) error {
+
+	reset()
+	return walletdb.Update(r.Backend, f)
+}
+
+// fillTestDB adds a top-level bucket with a nested bucket and a few values to
+// the given database.
+func fillTestDB(t *testing.T, db Backend) {
+	t.Helper()
+
+	err := Update(db, func(tx RwTx) error {
+		top, err := tx.CreateTopLevelBucket([]byte("top"))
+		if err != nil {
+			return err
+		}
+		if err := top.Put([]byte("k1"), []byte("v1")); err != nil {
+			return err
+		}
+
+		nested, err := top.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		return nested.Put([]byte("k2"), []byte("v2"))
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestWalkRetriedTx asserts that checksums aren't affected by a retried read
+// transaction and that a copy fails instead of writing duplicate data.
+func TestWalkRetriedTx(t *testing.T) {
+	t.Parallel()
+
+	src := openTestBoltDB(t)
+	fillTestDB(t, src)
+	retrying := &retryingBackend{Backend: src}
+
+	expected, err := BucketChecksums(src)
+	require.NoError(t, err)
+	checksums, err := BucketChecksums(retrying)
+	require.NoError(t, err)
+	require.Equal(t, expected, checksums)
+
+	err = CopyBackend(retrying, openTestBoltDB(t), 0)
+	require.Equal(t, ErrSourceTxRetried, err)
+}
+
+// TestCopyBackend asserts that all buckets, nested buckets, sequences and
+// key/value pairs are copied and that the copy passes verification, even if
+// the writes are split across many transactions.
+func TestCopyBackend(t *testing.T) {
+	t.Parallel()
+
+	src := openTestBoltDB(t)
+	dst := openTestBoltDB(t)
+
+	err := Update(src, func(tx RwTx) error {
+		top, err := tx.CreateTopLevelBucket([]byte("top"))
+		if err != nil {
+			return err
+		}
+		if err := top.SetSequence(42); err != nil {
+			return err
+		}
+		if err := top.Put([]byte("k1"), []byte("v1")); err != nil {
+			return err
+		}
+		if err := top.Put([]byte("empty"), []byte{}); err != nil {
+			return err
+		}
+
+		nested, err := top.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		for i := byte(0); i < 100; i++ {
+			err := nested.Put([]byte{i}, []byte{i, i, i})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.CreateTopLevelBucket([]byte("other"))
+		return err
+	}, func() {})
+	require.NoError(t, err)
+
+	// Use a tiny transaction size to force many intermediate commits.
+	require.NoError(t, CopyBackend(src, dst, 8))
+	require.NoError(t, VerifyCopy(src, dst))
+
+	err = View(dst, func(tx RTx) error {
+		top := tx.ReadBucket([]byte("top"))
+		require.NotNil(t, top)
+		require.Equal(t, uint64(42), bucketSequence(top))
+		require.Equal(t, []byte("v1"), top.Get([]byte("k1")))
+
+		nested := top.NestedReadBucket([]byte("nested"))
+		require.NotNil(t, nested)
+		require.Equal(t, []byte{7, 7, 7}, nested.Get([]byte{7}))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// Copying into a database that isn't empty must fail.
+	require.Equal(t, ErrDestinationNotEmpty, CopyBackend(src, dst, 0))
+
+	// Any modification must be detected by the verification.
+	err = Update(dst, func(tx RwTx) error {
+		nested := tx.ReadWriteBucket([]byte("top")).NestedReadWriteBucket(
+			[]byte("nested"),
+		)
+		return nested.Put([]byte{7}, []byte{0})
+	}, func() {})
+	require.NoError(t, err)
+	require.Error(t, VerifyCopy(src, dst))
+}
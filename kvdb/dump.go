@@ -0,0 +1,347 @@
+package kvdb
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+const (
+	// DumpVersion is the current version of the backend-neutral dump
+	// format written by DumpBackend.
+	DumpVersion = 0
+
+	// maxDumpElementSize is the maximum size of a single key or value we
+	// accept when reading a dump. This protects us from allocating huge
+	// amounts of memory when reading a corrupted dump.
+	maxDumpElementSize = 1 << 30
+
+	// maxDumpPathDepth is the maximum nesting level of buckets we accept
+	// when reading a dump.
+	maxDumpPathDepth = 64
+)
+
+// dumpRecordType denotes the type of a single record in a dump.
+type dumpRecordType uint8
+
+const (
+	// dumpRecordBucket is the type of a record that creates a bucket.
+	dumpRecordBucket dumpRecordType = 0
+
+	// dumpRecordValue is the type of a record that holds a key/value
+	// pair.
+	dumpRecordValue dumpRecordType = 1
+
+	// dumpRecordEnd is the type of the final record of a dump that holds
+	// the number of records and a checksum of all preceding bytes.
+	dumpRecordEnd dumpRecordType = 0xff
+)
+
+var (
+	// dumpMagic is written at the start of every dump so we can reject
+	// files that aren't database dumps early.
+	dumpMagic = [8]byte{'l', 'n', 'd', '-', 'k', 'v', 'd', 'b'}
+
+	// ErrInvalidDump is returned when reading a dump that is malformed,
+	// truncated or was modified after it was written.
+	ErrInvalidDump = errors.New("invalid database dump")
+)
+
+// dumpWriter serializes dump records to an underlying writer while counting
+// the number of records written.
+type dumpWriter struct {
+	w       io.Writer
+	records uint64
+	scratch [binary.MaxVarintLen64]byte
+}
+
+// writeUvarint writes a single unsigned varint.
+func (d *dumpWriter) writeUvarint(v uint64) error {
+	n := binary.PutUvarint(d.scratch[:], v)
+	_, err := d.w.Write(d.scratch[:n])
+	return err
+}
+
+// writeElement writes a length prefixed byte slice.
+func (d *dumpWriter) writeElement(b []byte) error {
+	if err := d.writeUvarint(uint64(len(b))); err != nil {
+		return err
+	}
+
+	_, err := d.w.Write(b)
+	return err
+}
+
+// writeRecord has the signature of a copyWalkFunc and writes a single bucket
+// or key/value record.
+func (d *dumpWriter) writeRecord(keys [][]byte, k, v []byte, seq uint64) error {
+	recordType := dumpRecordValue
+	if v == nil {
+		recordType = dumpRecordBucket
+	}
+
+	if _, err := d.w.Write([]byte{byte(recordType)}); err != nil {
+		return err
+	}
+	if err := d.writeUvarint(uint64(len(keys))); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := d.writeElement(key); err != nil {
+			return err
+		}
+	}
+	if err := d.writeElement(k); err != nil {
+		return err
+	}
+
+	var err error
+	if recordType == dumpRecordBucket {
+		err = d.writeUvarint(seq)
+	} else {
+		err = d.writeElement(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.records++
+
+	return nil
+}
+
+// DumpBackend writes a backend-neutral dump of all top-level buckets of the
+// given database to w. The whole database is read within a single read
+// transaction, so the dump is a consistent snapshot even if the database is
+// in use while the dump is created. The dump ends with a trailer that commits
+// to the number of records and contains a SHA256 checksum of the dump, which
+// allows RestoreBackend to detect truncated or corrupted dumps.
+//
+// The records are streamed to w while the database is walked. Backends like
+// etcd may retry the read transaction, in which case parts of the dump are
+// already written and ErrSourceTxRetried is returned.
+func DumpBackend(db Backend, w io.Writer) error {
+	hasher := sha256.New()
+	bw := bufio.NewWriter(io.MultiWriter(w, hasher))
+	d := &dumpWriter{
+		w: bw,
+	}
+
+	if _, err := bw.Write(dumpMagic[:]); err != nil {
+		return err
+	}
+	if err := d.writeUvarint(DumpVersion); err != nil {
+		return err
+	}
+
+	var started, retried bool
+	write := func(keys [][]byte, k, v []byte, seq uint64) error {
+		if retried {
+			return ErrSourceTxRetried
+		}
+		started = true
+
+		return d.writeRecord(keys, k, v, seq)
+	}
+	reset := func() {
+		retried = started
+	}
+	if err := walkBackend(db, write, reset); err != nil {
+		return err
+	}
+	if retried {
+		return ErrSourceTxRetried
+	}
+
+	// The trailer's type and record count are covered by the checksum,
+	// the checksum itself is written directly to the target writer.
+	if _, err := bw.Write([]byte{byte(dumpRecordEnd)}); err != nil {
+		return err
+	}
+	if err := d.writeUvarint(d.records); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(hasher.Sum(nil))
+	return err
+}
+
+// dumpReader reads dump records from an underlying reader while keeping track
+// of a running checksum.
+type dumpReader struct {
+	r      *bufio.Reader
+	hasher hash.Hash
+}
+
+// ReadByte reads and checksums a single byte. It implements io.ByteReader so
+// we can use binary.ReadUvarint.
+func (d *dumpReader) ReadByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	_, _ = d.hasher.Write([]byte{b})
+
+	return b, nil
+}
+
+// readUvarint reads a single unsigned varint.
+func (d *dumpReader) readUvarint() (uint64, error) {
+	v, err := binary.ReadUvarint(d)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidDump, err)
+	}
+
+	return v, nil
+}
+
+// readElement reads a length prefixed byte slice.
+func (d *dumpReader) readElement() ([]byte, error) {
+	length, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if length > maxDumpElementSize {
+		return nil, fmt.Errorf("%w: element of %d bytes exceeds "+
+			"maximum size", ErrInvalidDump, length)
+	}
+
+	b := make([]byte, length)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDump, err)
+	}
+	_, _ = d.hasher.Write(b)
+
+	return b, nil
+}
+
+// RestoreBackend reads a dump created by DumpBackend from r and writes its
+// content into the given database, which must not contain any top-level
+// buckets. The writes are split into transactions of at most txMaxSize key
+// and value bytes. The checksum in the trailer of the dump is verified before
+// the last transaction is committed, but as earlier transactions are already
+// committed at that point the destination should be discarded if an error is
+// returned.
+func RestoreBackend(db Backend, r io.Reader, txMaxSize int64) error {
+	d := &dumpReader{
+		r:      bufio.NewReader(r),
+		hasher: sha256.New(),
+	}
+
+	var magic [8]byte
+	if _, err := io.ReadFull(d.r, magic[:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDump, err)
+	}
+	if magic != dumpMagic {
+		return fmt.Errorf("%w: unknown magic %x", ErrInvalidDump, magic)
+	}
+	_, _ = d.hasher.Write(magic[:])
+
+	version, err := d.readUvarint()
+	if err != nil {
+		return err
+	}
+	if version != DumpVersion {
+		return fmt.Errorf("%w: unknown version %d", ErrInvalidDump,
+			version)
+	}
+
+	w, err := newChunkedWriter(db, txMaxSize)
+	if err != nil {
+		return err
+	}
+	defer w.rollback()
+
+	var records uint64
+	for {
+		recordType, err := d.ReadByte()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidDump, err)
+		}
+
+		if dumpRecordType(recordType) == dumpRecordEnd {
+			break
+		}
+
+		depth, err := d.readUvarint()
+		if err != nil {
+			return err
+		}
+		if depth > maxDumpPathDepth {
+			return fmt.Errorf("%w: bucket depth %d exceeds maximum",
+				ErrInvalidDump, depth)
+		}
+
+		keys := make([][]byte, depth)
+		for i := range keys {
+			keys[i], err = d.readElement()
+			if err != nil {
+				return err
+			}
+		}
+
+		k, err := d.readElement()
+		if err != nil {
+			return err
+		}
+
+		switch dumpRecordType(recordType) {
+		case dumpRecordBucket:
+			seq, err := d.readUvarint()
+			if err != nil {
+				return err
+			}
+
+			err = w.write(keys, k, nil, seq)
+			if err != nil {
+				return err
+			}
+
+		case dumpRecordValue:
+			v, err := d.readElement()
+			if err != nil {
+				return err
+			}
+
+			if err := w.write(keys, k, v, 0); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("%w: unknown record type %d",
+				ErrInvalidDump, recordType)
+		}
+
+		records++
+	}
+
+	numRecords, err := d.readUvarint()
+	if err != nil {
+		return err
+	}
+	if numRecords != records {
+		return fmt.Errorf("%w: trailer commits to %d records, read %d",
+			ErrInvalidDump, numRecords, records)
+	}
+
+	expectedSum := d.hasher.Sum(nil)
+	sum := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(d.r, sum); err != nil {
+		return fmt.Errorf("%w: unable to read checksum: %v",
+			ErrInvalidDump, err)
+	}
+	if !bytes.Equal(sum, expectedSum) {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidDump)
+	}
+
+	return w.commit()
+}
@@ -0,0 +1,86 @@
+// +build !js !wasm
+
+package kvdb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDumpRestore asserts that a database can be dumped and restored into an
+// empty database and that corrupted dumps are rejected.
+func TestDumpRestore(t *testing.T) {
+	t.Parallel()
+
+	src := openTestBoltDB(t)
+
+	err := Update(src, func(tx RwTx) error {
+		top, err := tx.CreateTopLevelBucket([]byte("top"))
+		if err != nil {
+			return err
+		}
+		if err := top.SetSequence(7); err != nil {
+			return err
+		}
+
+		nested, err := top.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		for i := byte(0); i < 50; i++ {
+			err := nested.Put([]byte{i}, bytes.Repeat([]byte{i}, 10))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	var dump bytes.Buffer
+	require.NoError(t, DumpBackend(src, &dump))
+
+	dst := openTestBoltDB(t)
+	require.NoError(t, RestoreBackend(dst, bytes.NewReader(dump.Bytes()), 32))
+	require.NoError(t, VerifyCopy(src, dst))
+
+	// Flipping a single bit of the last value must be detected. The value
+	// is followed by the end marker, the record count and the checksum.
+	corrupted := append([]byte(nil), dump.Bytes()...)
+	corrupted[len(corrupted)-35] ^= 1
+	err = RestoreBackend(
+		openTestBoltDB(t), bytes.NewReader(corrupted), 0,
+	)
+	require.True(t, errors.Is(err, ErrInvalidDump))
+
+	// And so must a truncated dump.
+	truncated := dump.Bytes()[:dump.Len()-10]
+	err = RestoreBackend(
+		openTestBoltDB(t), bytes.NewReader(truncated), 0,
+	)
+	require.True(t, errors.Is(err, ErrInvalidDump))
+}
+
+// TestDumpRetriedTx asserts that a dump fails instead of writing duplicate
+// records if the read transaction is retried.
+func TestDumpRetriedTx(t *testing.T) {
+	t.Parallel()
+
+	src := openTestBoltDB(t)
+	fillTestDB(t, src)
+
+	var dump bytes.Buffer
+	err := DumpBackend(&retryingBackend{Backend: src}, &dump)
+	require.Equal(t, ErrSourceTxRetried, err)
+
+	// Whatever was written before the retry must not be accepted as a
+	// valid dump.
+	err = RestoreBackend(
+		openTestBoltDB(t), bytes.NewReader(dump.Bytes()), 0,
+	)
+	require.True(t, errors.Is(err, ErrInvalidDump))
+}
@@ -4,9 +4,9 @@ package kvdb
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	_ "github.com/btcsuite/btcwallet/walletdb/bdb" // Import to register backend.
 )
@@ -172,36 +172,3 @@ func compactAndSwap(cfg *BoltBackendConfig) error {
 	// single file on disk once this method exits.
 	return os.Rename(tempDestFilePath, sourceFilePath)
 }
-
-// lastCompactionDate returns the date the given database file was last
-// compacted or a zero time.Time if no compaction was recorded before. The
-// compaction date is read from a file in the same directory and with the same
-// name as the DB file, but with the suffix ".last-compacted".
-func lastCompactionDate(dbFile string) (time.Time, error) {
-	zeroTime := time.Unix(0, 0)
-
-	tsFile := fmt.Sprintf("%s%s", dbFile, LastCompactionFileNameSuffix)
-	if !fileExists(tsFile) {
-		return zeroTime, nil
-	}
-
-	tsBytes, err := ioutil.ReadFile(tsFile)
-	if err != nil {
-		return zeroTime, err
-	}
-
-	tsNano := byteOrder.Uint64(tsBytes)
-	return time.Unix(0, int64(tsNano)), nil
-}
-
-// updateLastCompactionDate stores the current time as a timestamp in a file
-// in the same directory and with the same name as the DB file, but with the
-// suffix ".last-compacted".
-func updateLastCompactionDate(dbFile string) error {
-	var tsBytes [8]byte
-	byteOrder.PutUint64(tsBytes[:], uint64(time.Now().UnixNano()))
-
-	tsFile := fmt.Sprintf("%s%s", dbFile, LastCompactionFileNameSuffix)
-	return ioutil.WriteFile(tsFile, tsBytes[:], 0600)
-}
-
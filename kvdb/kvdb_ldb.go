@@ -1,36 +1,244 @@
 package kvdb
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	_ "github.com/btcsuite/btcwallet/walletdb/ldb" // Import to register backend.
+	"github.com/btcsuite/goleveldb/leveldb/filter"
+	"github.com/btcsuite/goleveldb/leveldb/opt"
+	"github.com/btcsuite/goleveldb/leveldb/util"
 )
 
+// LdbBackendConfig is a struct that holds settings specific to the goleveldb
+// database backend.
+type LdbBackendConfig struct {
+	// DBPath is the directory path in which the database should be
+	// stored.
+	DBPath string
+
+	// DBFileName is the name of the database.
+	DBFileName string
+
+	// LdbConfig holds the tuning and compaction settings of the database.
+	LdbConfig
+}
+
+// ldbCompacter is implemented by goleveldb backed databases that allow
+// triggering a manual compaction of a key range.
+type ldbCompacter interface {
+	// CompactRange compacts the underlying DB for the given key range. A
+	// zero range compacts the whole database.
+	CompactRange(r util.Range) error
+}
+
+// options returns the goleveldb options matching the configuration. Zero
+// values are left untouched so goleveldb falls back to its own defaults.
+func (cfg *LdbBackendConfig) options() *opt.Options {
+	opts := &opt.Options{
+		BlockCacheCapacity:     cfg.BlockCacheSize,
+		WriteBuffer:            cfg.WriteBufferSize,
+		CompactionTableSize:    cfg.CompactionTableSize,
+		CompactionL0Trigger:    cfg.CompactionL0Trigger,
+		WriteL0SlowdownTrigger: cfg.WriteL0SlowdownTrigger,
+		WriteL0PauseTrigger:    cfg.WriteL0PauseTrigger,
+	}
+
+	if cfg.BloomFilterBits > 0 {
+		opts.Filter = filter.NewBloomFilter(cfg.BloomFilterBits)
+	}
+
+	return opts
+}
+
+// ldbDriverArgs returns the arguments passed to the ldb walletdb driver when
+// creating or opening the database at the given path. The driver uses the
+// same calling convention as the bolt driver, the database path followed by
+// the no freelist sync flag and the timeout used when opening the database.
+// Leveldb has no freelist, so the flag is always set. The goleveldb options
+// derived from the config are passed as an optional last argument.
+func (cfg *LdbBackendConfig) ldbDriverArgs(path string) []interface{} {
+	return []interface{}{path, true, DefaultDBTimeout, cfg.options()}
+}
+
 // GetLdbBackend opens (or creates if doesn't exits) a goleveldb
 // backed database and returns a kvdb.Backend wrapping it.
-func GetLdbBackend(path, name string) (Backend, error) {
-	dbFilePath := filepath.Join(path, name)
+func GetLdbBackend(cfg *LdbBackendConfig) (Backend, error) {
+	dbFilePath := filepath.Join(cfg.DBPath, cfg.DBFileName)
 	var (
 		db  Backend
 		err error
 	)
 
 	if !fileExists(dbFilePath) {
-		if !fileExists(path) {
-			if err := os.MkdirAll(path, 0700); err != nil {
+		if !fileExists(cfg.DBPath) {
+			if err := os.MkdirAll(cfg.DBPath, 0700); err != nil {
 				return nil, err
 			}
 		}
 
-		db, err = Create(LdbBackendName, dbFilePath)
+		db, err = Create(
+			LdbBackendName, cfg.ldbDriverArgs(dbFilePath)...,
+		)
 	} else {
-		db, err = Open(LdbBackendName, dbFilePath)
+		db, err = Open(LdbBackendName, cfg.ldbDriverArgs(dbFilePath)...)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return db, nil
+	if !cfg.AutoCompact {
+		return db, nil
+	}
+
+	compacter, ok := db.(ldbCompacter)
+	if !ok {
+		log.Warnf("Database %v doesn't support manual compaction, "+
+			"skipping auto compaction", dbFilePath)
+		return db, nil
+	}
+
+	// Leveldb compacts in place, so unlike with bolt we can compact the
+	// already opened database.
+	err = compactLdb(compacter, dbFilePath, cfg.AutoCompactMinAge)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	if cfg.AutoCompactInterval == 0 {
+		return db, nil
+	}
+
+	return newPeriodicCompactLdb(
+		db, compacter, dbFilePath, cfg.AutoCompactInterval,
+	), nil
+}
+
+// compactLdb compacts the whole key range of the given leveldb database if
+// the last compaction happened longer than minAge ago and records the time of
+// the compaction in a file next to the database.
+func compactLdb(compacter ldbCompacter, dbFilePath string,
+	minAge time.Duration) error {
+
+	// Let's find out how long ago the last compaction of the database
+	// occurred and possibly skip compacting it again now.
+	lastCompactionDate, err := lastCompactionDate(dbFilePath)
+	if err != nil {
+		return fmt.Errorf("cannot determine last compaction date of "+
+			"database %v: %v", dbFilePath, err)
+	}
+	compactAge := time.Since(lastCompactionDate)
+	if minAge != 0 && compactAge <= minAge {
+		log.Infof("Not compacting database %v, it was last compacted "+
+			"at %v (%v ago), min age is set to %v", dbFilePath,
+			lastCompactionDate, compactAge.Truncate(time.Second),
+			minAge)
+		return nil
+	}
+
+	log.Infof("Compacting database %v", dbFilePath)
+
+	start := time.Now()
+	if err := compacter.CompactRange(util.Range{}); err != nil {
+		return fmt.Errorf("error during compact: %v", err)
+	}
+
+	log.Infof("DB compaction of %v successful after %v", dbFilePath,
+		time.Since(start).Truncate(time.Millisecond))
+
+	// We try to store the current timestamp in a file with the suffix
+	// .last-compacted so we can figure out how long ago the last compaction
+	// was. But since this shouldn't fail the compaction process itself, we
+	// only log the error. Worst case if this file cannot be written is that
+	// we compact on every startup.
+	err = updateLastCompactionDate(dbFilePath)
+	if err != nil {
+		log.Warnf("Could not update last compaction timestamp in "+
+			"%s%s: %v", dbFilePath, LastCompactionFileNameSuffix,
+			err)
+	}
+
+	return nil
+}
+
+// periodicCompactLdb wraps a goleveldb backed database and compacts it
+// periodically until the database is closed.
+type periodicCompactLdb struct {
+	Backend
+
+	compacter  ldbCompacter
+	dbFilePath string
+	interval   time.Duration
+
+	closeOnce sync.Once
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// newPeriodicCompactLdb wraps the given database and starts the goroutine
+// that compacts it with the given interval.
+func newPeriodicCompactLdb(db Backend, compacter ldbCompacter,
+	dbFilePath string, interval time.Duration) *periodicCompactLdb {
+
+	p := &periodicCompactLdb{
+		Backend:    db,
+		compacter:  compacter,
+		dbFilePath: dbFilePath,
+		interval:   interval,
+		quit:       make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.compactLoop()
+
+	return p
+}
+
+// compactLoop compacts the database every interval until quit is closed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *periodicCompactLdb) compactLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// The minimum age is already enforced by the ticker,
+			// so we always compact here.
+			err := compactLdb(p.compacter, p.dbFilePath, 0)
+			if err != nil {
+				log.Errorf("Periodic compaction of %v failed: "+
+					"%v", p.dbFilePath, err)
+			}
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// Close stops the periodic compaction and closes the underlying database.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (p *periodicCompactLdb) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.quit)
+	})
+	p.wg.Wait()
+
+	return p.Backend.Close()
+}
+
+// Batch passes the batch call through to the underlying database if it
+// supports batching.
+func (p *periodicCompactLdb) Batch(f func(tx RwTx) error) error {
+	return Batch(p.Backend, f)
 }
@@ -0,0 +1,94 @@
+// +build !js !wasm
+
+package kvdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/goleveldb/leveldb/opt"
+	"github.com/btcsuite/goleveldb/leveldb/util"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCompacter counts the number of compactions triggered.
+type mockCompacter struct {
+	compactions int
+}
+
+// CompactRange counts the compaction.
+func (m *mockCompacter) CompactRange(util.Range) error {
+	m.compactions++
+	return nil
+}
+
+// TestCompactLdbMinAge asserts that a database is only compacted again once
+// the configured minimum age since the last compaction has passed.
+func TestCompactLdbMinAge(t *testing.T) {
+	t.Parallel()
+
+	tempDir, err := ioutil.TempDir("", "kvdb-ldb")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	dbFilePath := filepath.Join(tempDir, "channel.db")
+	compacter := &mockCompacter{}
+
+	// Without a previous compaction the database is always compacted and
+	// the compaction date is recorded.
+	require.NoError(t, compactLdb(compacter, dbFilePath, time.Hour))
+	require.Equal(t, 1, compacter.compactions)
+
+	lastCompaction, err := lastCompactionDate(dbFilePath)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), lastCompaction, time.Minute)
+
+	// The last compaction was just now, so we skip it this time.
+	require.NoError(t, compactLdb(compacter, dbFilePath, time.Hour))
+	require.Equal(t, 1, compacter.compactions)
+
+	// A minimum age of zero compacts every time.
+	require.NoError(t, compactLdb(compacter, dbFilePath, 0))
+	require.Equal(t, 2, compacter.compactions)
+}
+
+// TestLdbOptions asserts that the config is translated into the matching
+// goleveldb options.
+func TestLdbOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := &LdbBackendConfig{
+		LdbConfig: *DefaultLdbConfig(),
+	}
+	opts := cfg.options()
+
+	require.Equal(t, DefaultLdbBlockCacheSize, opts.BlockCacheCapacity)
+	require.Equal(t, DefaultLdbWriteBufferSize, opts.WriteBuffer)
+	require.Equal(t, DefaultLdbCompactionTableSize, opts.CompactionTableSize)
+	require.Equal(t, DefaultLdbCompactionL0Trigger, opts.CompactionL0Trigger)
+	require.NotNil(t, opts.Filter)
+
+	cfg.BloomFilterBits = 0
+	require.Nil(t, cfg.options().Filter)
+}
+
+// TestLdbDriverArgs asserts that the ldb driver is called with the same
+// arguments as the bolt driver, followed by the goleveldb options.
+func TestLdbDriverArgs(t *testing.T) {
+	t.Parallel()
+
+	cfg := &LdbBackendConfig{
+		LdbConfig: *DefaultLdbConfig(),
+	}
+	args := cfg.ldbDriverArgs("test.db")
+
+	require.Len(t, args, 4)
+	require.Equal(t, "test.db", args[0])
+	require.Equal(t, true, args[1])
+	require.Equal(t, DefaultDBTimeout, args[2])
+	require.Equal(t, cfg.options().BlockCacheCapacity,
+		args[3].(*opt.Options).BlockCacheCapacity)
+}
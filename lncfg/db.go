@@ -34,8 +34,11 @@ type DB struct {
 func DefaultDB() *DB {
 	return &DB{
 		Backend: LdbBackend,
-		LevelDB: &kvdb.LdbConfig{
+		Bolt: &kvdb.BoltConfig{
+			AutoCompactMinAge: kvdb.DefaultBoltAutoCompactMinAge,
+			DBTimeout:         kvdb.DefaultDBTimeout,
 		},
+		LevelDB: kvdb.DefaultLdbConfig(),
 	}
 }
 
@@ -45,6 +48,12 @@ func (db *DB) Validate() error {
 	case BoltBackend:
 
 	case LdbBackend:
+		if db.LevelDB.AutoCompactInterval != 0 &&
+			!db.LevelDB.AutoCompact {
+
+			return fmt.Errorf("leveldb auto-compact-interval " +
+				"requires auto-compact to be set")
+		}
 
 	case EtcdBackend:
 		if !db.Etcd.Embedded && db.Etcd.Host == "" {
@@ -114,7 +123,11 @@ func (db *DB) GetBackends(ctx context.Context, dbPath string) (
 		}
 	}
 
-	localDB, err = kvdb.GetLdbBackend(dbPath, dbName)
+	localDB, err = kvdb.GetLdbBackend(&kvdb.LdbBackendConfig{
+		DBPath:     dbPath,
+		DBFileName: dbName,
+		LdbConfig:  *db.LevelDB,
+	})
 	if err != nil {
 		return nil, err
 	}
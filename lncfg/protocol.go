@@ -20,6 +20,27 @@ type ProtocolOptions struct {
 	// mini.
 	WumboChans bool `long:"wumbo-channels" description:"if set, then lnd will create and accept requests for channels larger chan 0.16 BTC"`
 
+	// OptionScidAlias should be set if we want to signal the
+	// option-scid-alias feature bit. This allows scid aliases and the
+	// option-scid-alias channel-type.
+	OptionScidAlias bool `long:"option-scid-alias" description:"enable support for option_scid_alias channels"`
+
+	// OptionZeroConf should be set if we want to signal the zero-conf
+	// feature bit.
+	OptionZeroConf bool `long:"zero-conf" description:"enable support for zero-conf channels, must have option-scid-alias set also"`
+
+	// OptionDualFund should be set if we want to signal the dual-fund
+	// feature bit.
+	OptionDualFund bool `long:"dual-fund" description:"enable support for dual funded channels opened with the v2 channel establishment protocol"`
+
+	// OptionSplice should be set if we want to signal the splice feature
+	// bit.
+	OptionSplice bool `long:"splice" description:"enable experimental support for splicing funds into and out of channels"`
+
+	// OptionTaprootChans should be set if we want to signal the simple
+	// taproot channels feature bit.
+	OptionTaprootChans bool `long:"simple-taproot-chans" description:"enable experimental support for private simple taproot channels"`
+
 	// NoAnchors should be set if we don't want to support opening or accepting
 	// channels having the anchor commitment type.
 	NoAnchors bool `long:"no-anchors" description:"disable support for anchor commitments"`
@@ -36,3 +57,29 @@ func (l *ProtocolOptions) Wumbo() bool {
 func (l *ProtocolOptions) NoAnchorCommitments() bool {
 	return l.NoAnchors
 }
+
+// ScidAlias returns true if we have enabled the option-scid-alias feature bit.
+func (l *ProtocolOptions) ScidAlias() bool {
+	return l.OptionScidAlias
+}
+
+// ZeroConf returns true if we have enabled the zero-conf feature bit.
+func (l *ProtocolOptions) ZeroConf() bool {
+	return l.OptionZeroConf
+}
+
+// DualFund returns true if we have enabled the dual-fund feature bit.
+func (l *ProtocolOptions) DualFund() bool {
+	return l.OptionDualFund
+}
+
+// Splice returns true if we have enabled the splice feature bit.
+func (l *ProtocolOptions) Splice() bool {
+	return l.OptionSplice
+}
+
+// TaprootChans returns true if we have enabled the simple taproot channels
+// feature bit.
+func (l *ProtocolOptions) TaprootChans() bool {
+	return l.OptionTaprootChans
+}
@@ -0,0 +1,91 @@
+package lncfg
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+const (
+	// DefaultTrampolineFeeBaseMsat is the default base fee a trampoline
+	// node charges, and the default base fee we leave for the trampoline
+	// node when we delegate a payment.
+	DefaultTrampolineFeeBaseMsat = 1000
+
+	// DefaultTrampolineFeeRatePPM is the default proportional fee a
+	// trampoline node charges, and the default proportional fee we leave
+	// for the trampoline node when we delegate a payment.
+	DefaultTrampolineFeeRatePPM = 1000
+
+	// DefaultTrampolineCltvDelta is the default cltv delta a trampoline
+	// node requires, and the default cltv delta we leave for the
+	// trampoline node when we delegate a payment. It covers the route
+	// from the trampoline node to the recipient.
+	DefaultTrampolineCltvDelta = 288
+)
+
+// Trampoline holds the configuration options for trampoline payments.
+type Trampoline struct {
+	Node string `long:"node" description:"The hex encoded public key of a trampoline node. If set, payments to destinations that are not known to our graph are delegated to this node."`
+
+	FeeBaseMsat uint64 `long:"feebasemsat" description:"The base fee in millisatoshis that is left for the trampoline node when a payment is delegated."`
+
+	FeeRatePPM uint64 `long:"feerateppm" description:"The proportional fee in parts per million that is left for the trampoline node when a payment is delegated."`
+
+	CltvDelta uint16 `long:"cltvdelta" description:"The cltv delta that is left for the trampoline node when a payment is delegated."`
+
+	Relay bool `long:"relay" description:"If true, we act as a trampoline node and relay trampoline payments of other nodes."`
+
+	RelayFeeBaseMsat uint64 `long:"relayfeebasemsat" description:"The base fee in millisatoshis we charge for relaying trampoline payments."`
+
+	RelayFeeRatePPM uint64 `long:"relayfeerateppm" description:"The proportional fee in parts per million we charge for relaying trampoline payments."`
+
+	RelayCltvDelta uint16 `long:"relaycltvdelta" description:"The cltv delta we require for relaying trampoline payments."`
+}
+
+// DefaultTrampoline returns the default trampoline config.
+func DefaultTrampoline() *Trampoline {
+	return &Trampoline{
+		FeeBaseMsat:      DefaultTrampolineFeeBaseMsat,
+		FeeRatePPM:       DefaultTrampolineFeeRatePPM,
+		CltvDelta:        DefaultTrampolineCltvDelta,
+		RelayFeeBaseMsat: DefaultTrampolineFeeBaseMsat,
+		RelayFeeRatePPM:  DefaultTrampolineFeeRatePPM,
+		RelayCltvDelta:   DefaultTrampolineCltvDelta,
+	}
+}
+
+// NodeKey returns the public key of the configured trampoline node, or nil if
+// none is configured.
+func (t *Trampoline) NodeKey() (*btcec.PublicKey, error) {
+	if t.Node == "" {
+		return nil, nil
+	}
+
+	nodeKey, err := hex.DecodeString(t.Node)
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(nodeKey, btcec.S256())
+}
+
+// Validate checks the trampoline config.
+//
+// NOTE: This is part of the Validator interface.
+func (t *Trampoline) Validate() error {
+	if _, err := t.NodeKey(); err != nil {
+		return fmt.Errorf("invalid trampoline node: %v", err)
+	}
+
+	if t.Node != "" && t.CltvDelta == 0 {
+		return fmt.Errorf("trampoline cltv delta must be positive")
+	}
+
+	if t.Relay && t.RelayCltvDelta == 0 {
+		return fmt.Errorf("trampoline relay cltv delta must be positive")
+	}
+
+	return nil
+}
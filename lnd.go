@@ -1657,6 +1657,12 @@ func initializeDatabases(ctx context.Context,
 			cfg.DB.Bolt.AutoCompact)
 	}
 
+	if cfg.DB.Backend == lncfg.LdbBackend {
+		ltndLog.Infof("Opening leveldb database, auto_compact=%v, "+
+			"auto_compact_interval=%v", cfg.DB.LevelDB.AutoCompact,
+			cfg.DB.LevelDB.AutoCompactInterval)
+	}
+
 	startOpenTime := time.Now()
 
 	databaseBackends, err := cfg.DB.GetBackends(ctx, cfg.localDatabaseDir())
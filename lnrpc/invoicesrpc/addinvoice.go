@@ -9,6 +9,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -68,6 +69,19 @@ type AddInvoiceConfig struct {
 	// GenAmpInvoiceFeatures returns a feature containing feature bits that
 	// should be advertised on freshly generated AMP invoices.
 	GenAmpInvoiceFeatures func() *lnwire.FeatureVector
+
+	// GetAlias returns the peer's alias SCID for the given channel, if
+	// one exists. It is used to populate route hints for channels that
+	// use aliases.
+	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// NodePubKey is the identity public key of our node. It is used as the
+	// final hop of blinded payment paths.
+	NodePubKey *btcec.PublicKey
+
+	// BestHeight returns the current best block height. It is used to
+	// compute the max cltv expiry of blinded payment paths.
+	BestHeight func() (uint32, error)
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -121,6 +135,10 @@ type AddInvoiceData struct {
 	// RouteHints are optional route hints that can each be individually used
 	// to assist in reaching the invoice's destination.
 	RouteHints [][]zpay32.HopHint
+
+	// Blind signals that the invoice should contain blinded payment paths
+	// from our peers to our node instead of route hints.
+	Blind bool
 }
 
 // paymentHashAndPreimage returns the payment hash and preimage for this invoice
@@ -255,6 +273,20 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 
 	amtMSat := invoice.Value
 
+	// Blinded payment paths replace route hints, and the payload of the
+	// final hop of a blinded route can't carry an AMP record.
+	if invoice.Blind {
+		switch {
+		case invoice.Private || len(invoice.RouteHints) > 0:
+			return nil, nil, fmt.Errorf("blinded invoices can't " +
+				"include route hints")
+
+		case invoice.Amp:
+			return nil, nil, fmt.Errorf("blinded invoices can't " +
+				"be AMP invoices")
+		}
+	}
+
 	// We also create an encoded payment request which allows the
 	// caller to compactly send the invoice to the payer. We'll create a
 	// list of options to be added to the encoded payment request. For now
@@ -326,6 +358,7 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 
 	// We'll use our current default CLTV value unless one was specified as
 	// an option on the command line when creating an invoice.
+	finalCltvDelta := uint64(cfg.DefaultCLTVExpiry)
 	switch {
 	case invoice.CltvExpiry > math.MaxUint16:
 		return nil, nil, fmt.Errorf("CLTV delta of %v is too large, max "+
@@ -339,6 +372,7 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 				routing.MinCLTVDelta, invoice.CltvExpiry)
 		}
 
+		finalCltvDelta = invoice.CltvExpiry
 		options = append(options,
 			zpay32.CLTVExpiry(invoice.CltvExpiry))
 	default:
@@ -406,6 +440,12 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	} else {
 		invoiceFeatures = cfg.GenInvoiceFeatures()
 	}
+
+	// Senders must support route blinding to pay blinded invoices.
+	if invoice.Blind {
+		invoiceFeatures = invoiceFeatures.Clone()
+		invoiceFeatures.Set(lnwire.RouteBlindingRequired)
+	}
 	options = append(options, zpay32.Features(invoiceFeatures))
 
 	// Generate and set a random payment address for this invoice. If the
@@ -417,6 +457,32 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	}
 	options = append(options, zpay32.PaymentAddr(paymentAddr))
 
+	// If requested, add blinded payment paths towards our node that use
+	// the payment address as path ID.
+	if invoice.Blind {
+		expiry := DefaultInvoiceExpiry
+		if invoice.Expiry > 0 {
+			expiry = time.Duration(invoice.Expiry) * time.Second
+		}
+
+		blindedPaths, err := blindedPaymentPaths(
+			cfg, &blindedPathParams{
+				amt:            amtMSat,
+				paymentAddr:    paymentAddr,
+				finalCltvDelta: uint16(finalCltvDelta),
+				expiry:         expiry,
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, path := range blindedPaths {
+			options = append(
+				options, zpay32.WithBlindedPaymentPath(path),
+			)
+		}
+	}
+
 	// Create and encode the payment request as a bech32 (zpay32) string.
 	creationDate := time.Now()
 	payReq, err := zpay32.NewInvoice(
@@ -480,6 +546,15 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 		return nil, false
 	}
 
+	return remoteChanPolicy(channel, cfg)
+}
+
+// remoteChanPolicy returns the policy of the remote node of the target channel
+// if the channel is active and the remote node is publicly advertised, which
+// makes the remote node eligible as the entry point of a route towards us.
+func remoteChanPolicy(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
+	*channeldb.ChannelEdgePolicy, bool) {
+
 	// Make sure the channel is active.
 	chanPoint := lnwire.NewChanIDFromOutPoint(
 		&channel.FundingOutpoint,
@@ -512,9 +587,12 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 		return nil, false
 	}
 
-	// Fetch the policies for each end of the channel.
-	chanID := channel.ShortChanID().ToUint64()
-	info, p1, p2, err := cfg.Graph.FetchChannelEdgesByID(chanID)
+	// Fetch the policies for each end of the channel. We look the edge up
+	// by its outpoint, as the SCID of an alias channel may not match the
+	// one the edge is stored under.
+	info, p1, p2, err := cfg.Graph.FetchChannelEdgesByOutpoint(
+		&channel.FundingOutpoint,
+	)
 	if err != nil {
 		log.Errorf("Unable to fetch the routing "+
 			"policies for the edges of the channel "+
@@ -534,14 +612,38 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 	return remotePolicy, true
 }
 
+// hopHintScid returns the SCID that should be used in a hop hint for the
+// passed channel. For channels that negotiated scid aliases, the alias our
+// peer sent us is used so that the sender can route through the channel
+// before it confirms and without leaking its funding outpoint.
+func hopHintScid(channel *channeldb.OpenChannel,
+	cfg *AddInvoiceConfig) lnwire.ShortChannelID {
+
+	if cfg.GetAlias == nil || !channel.ChanType.HasScidAliasChan() {
+		return channel.ShortChanID()
+	}
+
+	alias, err := cfg.GetAlias(lnwire.NewChanIDFromOutPoint(
+		&channel.FundingOutpoint,
+	))
+	if err != nil {
+		log.Debugf("Unable to fetch peer alias for channel %v, "+
+			"using base scid: %v", channel.FundingOutpoint, err)
+		return channel.ShortChanID()
+	}
+
+	return alias
+}
+
 // addHopHint creates a hop hint out of the passed channel and channel policy.
 // The new hop hint is appended to the passed slice.
 func addHopHint(hopHints *[]func(*zpay32.Invoice),
-	channel *channeldb.OpenChannel, chanPolicy *channeldb.ChannelEdgePolicy) {
+	channel *channeldb.OpenChannel, chanPolicy *channeldb.ChannelEdgePolicy,
+	scid lnwire.ShortChannelID) {
 
 	hopHint := zpay32.HopHint{
 		NodeID:      channel.IdentityPub,
-		ChannelID:   channel.ShortChanID().ToUint64(),
+		ChannelID:   scid.ToUint64(),
 		FeeBaseMSat: uint32(chanPolicy.FeeBaseMSat),
 		FeeProportionalMillionths: uint32(
 			chanPolicy.FeeProportionalMillionths,
@@ -583,7 +685,10 @@ func selectHopHints(amtMSat lnwire.MilliSatoshi, cfg *AddInvoiceConfig,
 
 		// Now that we now this channel use usable, add it as a hop
 		// hint and the indexes we'll use later.
-		addHopHint(&hopHints, channel, edgePolicy)
+		addHopHint(
+			&hopHints, channel, edgePolicy,
+			hopHintScid(channel, cfg),
+		)
 
 		hopHintChans[channel.FundingOutpoint] = struct{}{}
 		totalHintBandwidth += channel.LocalCommitment.RemoteBalance
@@ -627,7 +732,10 @@ func selectHopHints(amtMSat lnwire.MilliSatoshi, cfg *AddInvoiceConfig,
 
 		// Include the route hint in our set of options that will be
 		// used when creating the invoice.
-		addHopHint(&hopHints, channel, remotePolicy)
+		addHopHint(
+			&hopHints, channel, remotePolicy,
+			hopHintScid(channel, cfg),
+		)
 
 		// As we've just added a new hop hint, we'll accumulate it's
 		// available balance now to update our tally.
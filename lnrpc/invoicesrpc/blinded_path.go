@@ -0,0 +1,187 @@
+package invoicesrpc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// maxBlindedPaths is the maximum number of blinded payment paths we
+	// add to a single invoice.
+	maxBlindedPaths = 3
+
+	// blindedPathCltvBuffer is the number of blocks added on top of the
+	// invoice expiry and the cltv delta of a blinded path when computing
+	// its max cltv expiry. This leaves room for the sender to add shadow
+	// route padding to the final cltv.
+	blindedPathCltvBuffer = 2016
+
+	// blocksPerHour is the expected number of blocks mined per hour, which
+	// is used to convert the invoice expiry into a block height.
+	blocksPerHour = 6
+)
+
+// blindedPathParams contains the invoice parameters required to build the
+// blinded payment paths of an invoice.
+type blindedPathParams struct {
+	// amt is the amount of the invoice. It is zero if the payer can choose
+	// the amount.
+	amt lnwire.MilliSatoshi
+
+	// paymentAddr is the payment address of the invoice, which is used as
+	// the path ID of our own hop so we can authenticate blinded payments.
+	paymentAddr [32]byte
+
+	// finalCltvDelta is the final cltv delta of the invoice.
+	finalCltvDelta uint16
+
+	// expiry is the expiry of the invoice.
+	expiry time.Duration
+}
+
+// blindedPaymentPaths creates up to maxBlindedPaths blinded payment paths
+// towards our node. Each path uses one of our peers as the introduction node,
+// followed by our own node.
+func blindedPaymentPaths(cfg *AddInvoiceConfig,
+	params *blindedPathParams) ([]*zpay32.BlindedPaymentPath, error) {
+
+	if cfg.NodePubKey == nil || cfg.BestHeight == nil {
+		return nil, fmt.Errorf("blinded invoices not supported")
+	}
+
+	bestHeight, err := cfg.BestHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	openChannels, err := cfg.ChanDB.FetchAllChannels()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch all channels")
+	}
+
+	var paths []*zpay32.BlindedPaymentPath
+	for _, channel := range openChannels {
+		if len(paths) >= maxBlindedPaths {
+			break
+		}
+
+		// The introduction node must be able to forward the full
+		// amount of the invoice to us.
+		if channel.LocalCommitment.RemoteBalance < params.amt {
+			continue
+		}
+
+		remotePolicy, ok := remoteChanPolicy(channel, cfg)
+		if !ok || remotePolicy == nil {
+			continue
+		}
+
+		path, err := blindedPaymentPath(
+			cfg, params, channel, remotePolicy, bestHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no channels eligible for blinded " +
+			"payment paths")
+	}
+
+	return paths, nil
+}
+
+// blindedPaymentPath builds a blinded payment path from the remote node of the
+// passed channel to our node.
+func blindedPaymentPath(cfg *AddInvoiceConfig, params *blindedPathParams,
+	channel *channeldb.OpenChannel, remotePolicy *channeldb.ChannelEdgePolicy,
+	bestHeight uint32) (*zpay32.BlindedPaymentPath, error) {
+
+	cltvDelta := remotePolicy.TimeLockDelta + params.finalCltvDelta
+	expiryBlocks := uint32(params.expiry.Hours()*blocksPerHour) + 1
+	maxCltvExpiry := bestHeight + expiryBlocks + uint32(cltvDelta) +
+		blindedPathCltvBuffer
+
+	// The introduction node forwards the HTLC over the channel to us using
+	// the forwarding policy it advertised for it.
+	scid := hopHintScid(channel, cfg)
+	introData, err := record.EncodeBlindedRouteData(
+		&record.BlindedRouteData{
+			ShortChannelID: &scid,
+			RelayInfo: &record.PaymentRelayInfo{
+				CltvExpiryDelta: remotePolicy.TimeLockDelta,
+				FeeRate: uint32(
+					remotePolicy.FeeProportionalMillionths,
+				),
+				BaseFee: uint32(remotePolicy.FeeBaseMSat),
+			},
+			Constraints: &record.PaymentConstraints{
+				MaxCltvExpiry:   maxCltvExpiry,
+				HtlcMinimumMsat: remotePolicy.MinHTLC,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Our own hop only carries the path ID, which allows us to verify that
+	// the payment was made through a path we created for this invoice.
+	finalData, err := record.EncodeBlindedRouteData(
+		&record.BlindedRouteData{
+			PathID: params.paymentAddr[:],
+			Constraints: &record.PaymentConstraints{
+				MaxCltvExpiry:   maxCltvExpiry,
+				HtlcMinimumMsat: remotePolicy.MinHTLC,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	hops, err := blindedpath.BuildBlindedPath(
+		sessionKey, []*blindedpath.HopInfo{{
+			NodePub:   channel.IdentityPub,
+			PlainText: introData,
+		}, {
+			NodePub:   cfg.NodePubKey,
+			PlainText: finalData,
+		}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	htlcMax := channel.LocalCommitment.RemoteBalance
+	if remotePolicy.MessageFlags.HasMaxHtlc() &&
+		remotePolicy.MaxHTLC < htlcMax {
+
+		htlcMax = remotePolicy.MaxHTLC
+	}
+
+	return &zpay32.BlindedPaymentPath{
+		FeeBaseMsat:     uint32(remotePolicy.FeeBaseMSat),
+		FeeRate:         uint32(remotePolicy.FeeProportionalMillionths),
+		CltvExpiryDelta: cltvDelta,
+		HTLCMinMsat:     uint64(remotePolicy.MinHTLC),
+		HTLCMaxMsat:     uint64(htlcMax),
+		Features:        lnwire.EmptyFeatureVector(),
+		Hops:            hops,
+	}, nil
+}
@@ -59,4 +59,9 @@ type Config struct {
 	// GenAmpInvoiceFeatures returns a feature containing feature bits that
 	// should be advertised on freshly generated AMP invoices.
 	GenAmpInvoiceFeatures func() *lnwire.FeatureVector
+
+	// GetAlias returns the peer's alias SCID for the given channel, if
+	// one exists. It is used to populate route hints for channels that
+	// use aliases.
+	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
 }
@@ -0,0 +1,114 @@
+package invoicesrpc
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+// OfferInvoiceData contains the data required to create an invoice for an
+// invoice request that was received for one of our offers.
+type OfferInvoiceData struct {
+	// OfferID is the ID of the offer the invoice is created for.
+	OfferID [32]byte
+
+	// Memo is the description of the offer.
+	Memo string
+
+	// Value is the amount of the invoice.
+	Value lnwire.MilliSatoshi
+
+	// Expiry is the relative expiry of the invoice.
+	Expiry time.Duration
+
+	// Encode is called to sign and encode the invoice once its payment
+	// hash and blinded payment paths are known. The encoded invoice is
+	// stored as the payment request of the invoice.
+	Encode func(paymentHash lntypes.Hash, creationDate time.Time,
+		paths []*zpay32.BlindedPaymentPath) (string, error)
+}
+
+// AddOfferInvoice adds a new invoice that pays to blinded payment paths
+// towards our node and is bound to an offer. Unlike BOLT 11 invoices, the
+// payment request is encoded by the caller.
+func AddOfferInvoice(cfg *AddInvoiceConfig, data *OfferInvoiceData) (
+	*lntypes.Hash, *channeldb.Invoice, error) {
+
+	if len(data.Memo) > channeldb.MaxMemoSize {
+		return nil, nil, fmt.Errorf("memo too large: %v bytes "+
+			"(maxsize=%v)", len(data.Memo), channeldb.MaxMemoSize)
+	}
+
+	if data.Value == 0 {
+		return nil, nil, fmt.Errorf("offer invoices require an amount")
+	}
+
+	var preimage lntypes.Preimage
+	if _, err := rand.Read(preimage[:]); err != nil {
+		return nil, nil, err
+	}
+	paymentHash := preimage.Hash()
+
+	// The payment address is only used as the path ID of our own hop in
+	// the blinded payment paths, which authenticates the payment.
+	var paymentAddr [32]byte
+	if _, err := rand.Read(paymentAddr[:]); err != nil {
+		return nil, nil, err
+	}
+
+	expiry := data.Expiry
+	if expiry == 0 {
+		expiry = DefaultInvoiceExpiry
+	}
+
+	finalCltvDelta := uint16(cfg.DefaultCLTVExpiry)
+	paths, err := blindedPaymentPaths(cfg, &blindedPathParams{
+		amt:            data.Value,
+		paymentAddr:    paymentAddr,
+		finalCltvDelta: finalCltvDelta,
+		expiry:         expiry,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creationDate := time.Now()
+	payReq, err := data.Encode(paymentHash, creationDate, paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoiceFeatures := cfg.GenInvoiceFeatures().Clone()
+	invoiceFeatures.Set(lnwire.RouteBlindingRequired)
+
+	offerID := data.OfferID
+	newInvoice := &channeldb.Invoice{
+		CreationDate:   creationDate,
+		Memo:           []byte(data.Memo),
+		PaymentRequest: []byte(payReq),
+		Terms: channeldb.ContractTerm{
+			FinalCltvDelta:  int32(finalCltvDelta),
+			Expiry:          expiry,
+			Value:           data.Value,
+			PaymentPreimage: &preimage,
+			PaymentAddr:     paymentAddr,
+			Features:        invoiceFeatures,
+		},
+		OfferID: &offerID,
+	}
+
+	log.Debugf("Adding invoice %v for offer %x", paymentHash,
+		data.OfferID[:])
+
+	_, err = cfg.AddInvoice(newInvoice, paymentHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &paymentHash, newInvoice, nil
+}
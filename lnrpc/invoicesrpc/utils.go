@@ -19,8 +19,11 @@ import (
 func decodePayReq(invoice *channeldb.Invoice,
 	activeNetParams *chaincfg.Params) (*zpay32.Invoice, error) {
 
+	// The payment request of offer invoices is a BOLT 12 invoice, which
+	// only needs the payment hash to be reported, as all other fields are
+	// stored in the invoice itself.
 	paymentRequest := string(invoice.PaymentRequest)
-	if paymentRequest == "" {
+	if paymentRequest == "" || invoice.OfferID != nil {
 		preimage := invoice.Terms.PaymentPreimage
 		if preimage == nil {
 			return &zpay32.Invoice{}, nil
@@ -181,6 +184,10 @@ func CreateRPCInvoice(invoice *channeldb.Invoice,
 		rpcInvoice.RPreimage = preimage[:]
 	}
 
+	if invoice.OfferID != nil {
+		rpcInvoice.OfferId = invoice.OfferID[:]
+	}
+
 	return rpcInvoice, nil
 }
 
@@ -260,3 +267,22 @@ func CreateZpay32HopHints(routeHints []*lnrpc.RouteHint) ([][]zpay32.HopHint, er
 	}
 	return res, nil
 }
+
+// UnmarshallInvoiceState converts an rpc invoice state to the corresponding
+// channeldb.ContractState.
+func UnmarshallInvoiceState(state lnrpc.Invoice_InvoiceState) (
+	channeldb.ContractState, error) {
+
+	switch state {
+	case lnrpc.Invoice_OPEN:
+		return channeldb.ContractOpen, nil
+	case lnrpc.Invoice_SETTLED:
+		return channeldb.ContractSettled, nil
+	case lnrpc.Invoice_CANCELED:
+		return channeldb.ContractCanceled, nil
+	case lnrpc.Invoice_ACCEPTED:
+		return channeldb.ContractAccepted, nil
+	default:
+		return 0, fmt.Errorf("unknown invoice state %v", state)
+	}
+}
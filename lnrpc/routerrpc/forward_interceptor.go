@@ -7,6 +7,7 @@ import (
 
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -183,13 +184,43 @@ func (r *forwardInterceptor) resolveFromClient(
 	if !ok {
 		return ErrFwdNotExists
 	}
-	delete(r.holdForwards, circuitKey)
+
+	// The forward is only released if it was resolved, so that the client
+	// can retry a resolution that was rejected.
+	err := resolveForward(interceptedForward, in)
+	if err == nil || err == htlcswitch.ErrFwdNotExists {
+		delete(r.holdForwards, circuitKey)
+	}
+
+	return err
+}
+
+// resolveForward resolves a held forward according to the client resolution.
+func resolveForward(interceptedForward htlcswitch.InterceptedForward,
+	in *ForwardHtlcInterceptResponse) error {
 
 	switch in.Action {
 	case ResolveHoldForwardAction_RESUME:
 		return interceptedForward.Resume()
+
+	case ResolveHoldForwardAction_RESUME_MODIFIED:
+		var outChanID *lnwire.ShortChannelID
+		if in.OutChanId != 0 {
+			chanID := lnwire.NewShortChanIDFromInt(in.OutChanId)
+			outChanID = &chanID
+		}
+
+		var outAmt *lnwire.MilliSatoshi
+		if in.OutAmountMsat != 0 {
+			amt := lnwire.MilliSatoshi(in.OutAmountMsat)
+			outAmt = &amt
+		}
+
+		return interceptedForward.ResumeModified(outChanID, outAmt)
+
 	case ResolveHoldForwardAction_FAIL:
-		return interceptedForward.Fail()
+		return failForward(interceptedForward, in)
+
 	case ResolveHoldForwardAction_SETTLE:
 		if in.Preimage == nil {
 			return ErrMissingPreimage
@@ -204,18 +235,91 @@ func (r *forwardInterceptor) resolveFromClient(
 	}
 }
 
-// onDisconnect removes all previousely held forwards from
-// the store. Before they are removed it ensure to resume as the default
-// behavior.
+// failForward fails a held forward with the failure requested by the client.
+func failForward(interceptedForward htlcswitch.InterceptedForward,
+	in *ForwardHtlcInterceptResponse) error {
+
+	switch {
+	case len(in.FailureMessage) != 0 &&
+		in.FailureCode != lnrpc.Failure_RESERVED:
+
+		return errors.New("failure message and failure code are " +
+			"mutually exclusive")
+
+	case len(in.FailureMessage) != 0:
+		return interceptedForward.FailWithReason(in.FailureMessage)
+
+	case in.FailureCode == lnrpc.Failure_RESERVED,
+		in.FailureCode == lnrpc.Failure_TEMPORARY_CHANNEL_FAILURE:
+
+		return interceptedForward.Fail()
+	}
+
+	failure, err := unmarshallFailureCode(
+		in.FailureCode, interceptedForward.Packet(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return interceptedForward.FailWithMessage(failure)
+}
+
+// unmarshallFailureCode returns the failure message for the given failure code
+// of an intercepted packet. Failures that require a channel update aren't
+// supported, because the interceptor decides instead of the outgoing channel.
+func unmarshallFailureCode(code lnrpc.Failure_FailureCode,
+	packet htlcswitch.InterceptedPacket) (lnwire.FailureMessage, error) {
+
+	switch code {
+	case lnrpc.Failure_INVALID_REALM:
+		return &lnwire.FailInvalidRealm{}, nil
+
+	case lnrpc.Failure_EXPIRY_TOO_FAR:
+		return &lnwire.FailExpiryTooFar{}, nil
+
+	case lnrpc.Failure_INVALID_ONION_VERSION:
+		return lnwire.NewInvalidOnionVersion(packet.OnionBlob[:]), nil
+
+	case lnrpc.Failure_INVALID_ONION_HMAC:
+		return lnwire.NewInvalidOnionHmac(packet.OnionBlob[:]), nil
+
+	case lnrpc.Failure_INVALID_ONION_KEY:
+		return lnwire.NewInvalidOnionKey(packet.OnionBlob[:]), nil
+
+	case lnrpc.Failure_REQUIRED_NODE_FEATURE_MISSING:
+		return &lnwire.FailRequiredNodeFeatureMissing{}, nil
+
+	case lnrpc.Failure_REQUIRED_CHANNEL_FEATURE_MISSING:
+		return &lnwire.FailRequiredChannelFeatureMissing{}, nil
+
+	case lnrpc.Failure_UNKNOWN_NEXT_PEER:
+		return &lnwire.FailUnknownNextPeer{}, nil
+
+	case lnrpc.Failure_TEMPORARY_NODE_FAILURE:
+		return &lnwire.FailTemporaryNodeFailure{}, nil
+
+	case lnrpc.Failure_PERMANENT_NODE_FAILURE:
+		return &lnwire.FailPermanentNodeFailure{}, nil
+
+	case lnrpc.Failure_PERMANENT_CHANNEL_FAILURE:
+		return &lnwire.FailPermanentChannelFailure{}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported failure code %v", code)
+	}
+}
+
+// onDisconnect removes all previousely held forwards from the store. The
+// switch still tracks them and either resumes them as the default behavior or
+// holds them for the next interceptor if an interceptor is required.
 func (r *forwardInterceptor) onDisconnect() {
 	// Then close the channel so all go routine will exit.
 	close(r.quit)
 
-	log.Infof("RPC interceptor disconnected, resolving held packets")
-	for key, forward := range r.holdForwards {
-		if err := forward.Resume(); err != nil {
-			log.Errorf("failed to resume hold forward %v", err)
-		}
+	log.Infof("RPC interceptor disconnected, releasing %v held packets",
+		len(r.holdForwards))
+	for key := range r.holdForwards {
 		delete(r.holdForwards, key)
 	}
 	r.wg.Wait()
@@ -45,6 +45,12 @@ type RouterBackend struct {
 	// capacity of a channel to populate in responses.
 	FetchChannelCapacity func(chanID uint64) (btcutil.Amount, error)
 
+	// FetchAmountPairCapacity determines the maximal channel capacity
+	// between two nodes given a certain amount. It is zero if no channel
+	// between the nodes is known.
+	FetchAmountPairCapacity func(nodeFrom, nodeTo route.Vertex,
+		amount lnwire.MilliSatoshi) (btcutil.Amount, error)
+
 	// FetchChannelEndpoints returns the pubkeys of both endpoints of the
 	// given channel id.
 	FetchChannelEndpoints func(chanID uint64) (route.Vertex,
@@ -95,14 +101,18 @@ type RouterBackend struct {
 	// SetChannelAuto exposes the ability to restore automatic channel state
 	// management after manually setting channel status.
 	SetChannelAuto func(wire.OutPoint) error
+
+	// CircuitBreaker limits the forwards of each incoming peer.
+	CircuitBreaker *htlcswitch.CircuitBreaker
 }
 
 // MissionControl defines the mission control dependencies of routerrpc.
 type MissionControl interface {
 	// GetProbability is expected to return the success probability of a
-	// payment from fromNode to toNode.
+	// payment from fromNode to toNode. The capacity is the capacity of the
+	// channel between the nodes and is zero if unknown.
 	GetProbability(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
 
 	// ResetHistory resets the history of MissionControl returning it to a
 	// state as if no payment attempts have been made.
@@ -113,9 +123,10 @@ type MissionControl interface {
 	GetHistorySnapshot() *routing.MissionControlSnapshot
 
 	// ImportHistory imports the mission control snapshot to our internal
-	// state. This import will only be applied in-memory, and will not be
-	// persisted across restarts.
-	ImportHistory(*routing.MissionControlSnapshot) error
+	// state, merging it with the existing state according to the given
+	// policy. The import is persisted and merged again on startup.
+	ImportHistory(*routing.MissionControlSnapshot,
+		routing.MergePolicy) error
 
 	// GetPairHistorySnapshot returns the stored history for a given node
 	// pair.
@@ -257,7 +268,7 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	restrictions := &routing.RestrictParams{
 		FeeLimit: feeLimit,
 		ProbabilitySource: func(fromNode, toNode route.Vertex,
-			amt lnwire.MilliSatoshi) float64 {
+			amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 			if _, ok := ignoredNodes[fromNode]; ok {
 				return 0
@@ -276,7 +287,7 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 			}
 
 			return r.MissionControl.GetProbability(
-				fromNode, toNode, amt,
+				fromNode, toNode, amt, capacity,
 			)
 		},
 		DestCustomRecords: record.CustomSet(in.DestCustomRecords),
@@ -360,8 +371,15 @@ func (r *RouterBackend) getSuccessProbability(rt *route.Route) float64 {
 	for _, hop := range rt.Hops {
 		toNode := hop.PubKeyBytes
 
+		// If the capacity can't be fetched, we leave it at zero which
+		// means that it is unknown.
+		capacity, err := r.FetchChannelCapacity(hop.ChannelID)
+		if err != nil {
+			capacity = 0
+		}
+
 		probability := r.MissionControl.GetProbability(
-			fromNode, toNode, amtToFwd,
+			fromNode, toNode, amtToFwd, capacity,
 		)
 
 		successProb *= probability
@@ -897,6 +915,172 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	return payIntent, nil
 }
 
+// extractIntentFromProbeRequest attempts to parse the ProbePaymentRequest
+// into a LightningPayment that can be probed. The payment hash and address of
+// the probe are chosen by the router.
+func (r *RouterBackend) extractIntentFromProbeRequest(
+	rpcProbeReq *ProbePaymentRequest) (*routing.LightningPayment, error) {
+
+	payIntent := &routing.LightningPayment{
+		OutgoingChannelIDs: rpcProbeReq.OutgoingChanIds,
+	}
+
+	// Pass along a last hop restriction if specified.
+	if len(rpcProbeReq.LastHopPubkey) > 0 {
+		lastHop, err := route.NewVertexFromBytes(
+			rpcProbeReq.LastHopPubkey,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payIntent.LastHop = &lastHop
+	}
+
+	// Take the CLTV limit from the request if set, otherwise use the max.
+	cltvLimit, err := ValidateCLTVLimit(
+		uint32(rpcProbeReq.CltvLimit), r.MaxTotalTimelock,
+	)
+	if err != nil {
+		return nil, err
+	}
+	payIntent.CltvLimit = cltvLimit
+
+	maxParts := rpcProbeReq.MaxParts
+	if maxParts == 0 {
+		maxParts = DefaultMaxParts
+	}
+	payIntent.MaxParts = maxParts
+
+	if rpcProbeReq.MaxShardSizeMsat > 0 {
+		shardAmtMsat := lnwire.MilliSatoshi(rpcProbeReq.MaxShardSizeMsat)
+		payIntent.MaxShardAmt = &shardAmtMsat
+	}
+
+	payIntent.FeeLimit, err = lnrpc.UnmarshallAmt(
+		rpcProbeReq.FeeLimitSat, rpcProbeReq.FeeLimitMsat,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if rpcProbeReq.TimeoutSeconds == 0 {
+		return nil, errors.New("timeout_seconds must be specified")
+	}
+	payIntent.PayAttemptTimeout = time.Second *
+		time.Duration(rpcProbeReq.TimeoutSeconds)
+
+	routeHints, err := unmarshallRouteHints(rpcProbeReq.RouteHints)
+	if err != nil {
+		return nil, err
+	}
+	payIntent.RouteHints = routeHints
+
+	reqAmt, err := lnrpc.UnmarshallAmt(
+		rpcProbeReq.Amt, rpcProbeReq.AmtMsat,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// If an invoice is probed, the destination and its parameters are
+	// taken from it.
+	if rpcProbeReq.PaymentRequest != "" {
+		switch {
+		case len(rpcProbeReq.Dest) > 0:
+			return nil, errors.New("dest and payment_request " +
+				"cannot appear together")
+
+		case rpcProbeReq.FinalCltvDelta != 0:
+			return nil, errors.New("final_cltv_delta and " +
+				"payment_request cannot appear together")
+
+		case len(rpcProbeReq.DestFeatures) > 0:
+			return nil, errors.New("dest_features and " +
+				"payment_request cannot appear together")
+		}
+
+		payReq, err := zpay32.Decode(
+			rpcProbeReq.PaymentRequest, r.ActiveNetParams,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case payReq.MilliSat == nil && reqAmt == 0:
+			return nil, errors.New("amount must be specified " +
+				"when probing a zero amount invoice")
+
+		case payReq.MilliSat == nil:
+			payIntent.Amount = reqAmt
+
+		case reqAmt != 0:
+			return nil, errors.New("amount must not be specified " +
+				"when probing a non-zero amount invoice")
+
+		default:
+			payIntent.Amount = *payReq.MilliSat
+		}
+
+		if !payReq.Features.HasFeature(lnwire.MPPOptional) {
+			payIntent.MaxParts = 1
+		}
+
+		destKey := payReq.Destination.SerializeCompressed()
+		copy(payIntent.Target[:], destKey)
+
+		payIntent.FinalCLTVDelta = uint16(payReq.MinFinalCLTVExpiry())
+		payIntent.RouteHints = append(
+			payIntent.RouteHints, payReq.RouteHints...,
+		)
+		payIntent.DestFeatures = payReq.Features
+	} else {
+		target, err := route.NewVertexFromBytes(rpcProbeReq.Dest)
+		if err != nil {
+			return nil, err
+		}
+		payIntent.Target = target
+
+		if rpcProbeReq.FinalCltvDelta != 0 {
+			payIntent.FinalCLTVDelta =
+				uint16(rpcProbeReq.FinalCltvDelta)
+		} else {
+			payIntent.FinalCLTVDelta = r.DefaultFinalCltvDelta
+		}
+
+		if reqAmt == 0 {
+			return nil, errors.New("amount must be specified")
+		}
+		payIntent.Amount = reqAmt
+
+		features, err := UnmarshalFeatures(rpcProbeReq.DestFeatures)
+		if err != nil {
+			return nil, err
+		}
+
+		if features != nil {
+			err = feature.ValidateDeps(features)
+			if err != nil {
+				return nil, err
+			}
+		}
+		payIntent.DestFeatures = features
+	}
+
+	err = routing.ValidateCLTVLimit(
+		payIntent.CltvLimit, payIntent.FinalCLTVDelta, true,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if payIntent.Target == r.SelfNode {
+		return nil, errors.New("probing ourselves is not possible")
+	}
+
+	return payIntent, nil
+}
+
 // unmarshallRouteHints unmarshalls a list of route hints.
 func unmarshallRouteHints(rpcRouteHints []*lnrpc.RouteHint) (
 	[][]zpay32.HopHint, error) {
@@ -1402,6 +1586,29 @@ func convertPaymentStatus(dbStatus channeldb.PaymentStatus) (
 	}
 }
 
+// UnmarshallPaymentStatus converts an rpc payment status to the corresponding
+// channeldb.PaymentStatus.
+func UnmarshallPaymentStatus(status lnrpc.Payment_PaymentStatus) (
+	channeldb.PaymentStatus, error) {
+
+	switch status {
+	case lnrpc.Payment_UNKNOWN:
+		return channeldb.StatusUnknown, nil
+
+	case lnrpc.Payment_IN_FLIGHT:
+		return channeldb.StatusInFlight, nil
+
+	case lnrpc.Payment_SUCCEEDED:
+		return channeldb.StatusSucceeded, nil
+
+	case lnrpc.Payment_FAILED:
+		return channeldb.StatusFailed, nil
+
+	default:
+		return 0, fmt.Errorf("unknown payment status %v", status)
+	}
+}
+
 // marshallPaymentFailureReason marshalls the failure reason to the corresponding rpc
 // type.
 func marshallPaymentFailureReason(reason *channeldb.FailureReason) (
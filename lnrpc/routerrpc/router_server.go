@@ -1,12 +1,14 @@
 package routerrpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/btcsuite/btcutil"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -77,6 +80,10 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/routerrpc.Router/ProbePayment": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/QueryMissionControl": {{
 			Entity: "offchain",
 			Action: "read",
@@ -125,6 +132,14 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/GetCircuitBreaker": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/SetCircuitBreakerLimits": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -376,6 +391,52 @@ func (s *Server) EstimateRouteFee(ctx context.Context,
 	}, nil
 }
 
+// ProbePayment sends a probe with a random payment hash to find out whether
+// a payment can be delivered. The probe succeeds once all of its shards were
+// failed by the destination because of the unknown payment hash.
+func (s *Server) ProbePayment(ctx context.Context,
+	req *ProbePaymentRequest) (*ProbePaymentResponse, error) {
+
+	payment, err := s.cfg.RouterBackend.extractIntentFromProbeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := s.cfg.Router.ProbePayment(payment)
+	if err != nil {
+		// A failure reason means that the probe didn't reach the
+		// destination, which is a valid outcome of the probe.
+		reason, ok := err.(channeldb.FailureReason)
+		if !ok {
+			return nil, err
+		}
+
+		rpcReason, err := marshallPaymentFailureReason(&reason)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ProbePaymentResponse{
+			FailureReason: rpcReason,
+		}, nil
+	}
+
+	response := &ProbePaymentResponse{
+		Routes: make([]*lnrpc.Route, 0, len(routes)),
+	}
+	for _, rt := range routes {
+		rpcRoute, err := s.cfg.RouterBackend.MarshallRoute(rt)
+		if err != nil {
+			return nil, err
+		}
+
+		response.Routes = append(response.Routes, rpcRoute)
+		response.FeeMsat += int64(rt.TotalFees())
+	}
+
+	return response, nil
+}
+
 // SendToRouteV2 sends a payment through a predefined route. The response of this
 // call contains structured error information.
 func (s *Server) SendToRouteV2(ctx context.Context,
@@ -441,14 +502,39 @@ func (s *Server) GetMissionControlConfig(ctx context.Context,
 	error) {
 
 	cfg := s.cfg.RouterBackend.MissionControl.GetConfig()
+	rpcCfg := &MissionControlConfig{
+		MaximumPaymentResults:       uint32(cfg.MaxMcHistory),
+		MinimumFailureRelaxInterval: uint64(cfg.MinFailureRelaxInterval.Seconds()),
+	}
+
+	switch estimatorCfg := cfg.Estimator.Config().(type) {
+	case routing.AprioriConfig:
+		rpcCfg.Model = MissionControlConfig_APRIORI
+		rpcCfg.HalfLifeSeconds = uint64(
+			estimatorCfg.PenaltyHalfLife.Seconds(),
+		)
+		rpcCfg.HopProbability = float32(
+			estimatorCfg.AprioriHopProbability,
+		)
+		rpcCfg.Weight = float32(estimatorCfg.AprioriWeight)
+
+	case routing.BimodalConfig:
+		rpcCfg.Model = MissionControlConfig_BIMODAL
+		rpcCfg.Bimodal = &BimodalParameters{
+			NodeWeight: estimatorCfg.BimodalNodeWeight,
+			ScaleMsat:  uint64(estimatorCfg.BimodalScaleMsat),
+			DecayTime: uint64(
+				estimatorCfg.BimodalDecayTime.Seconds(),
+			),
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown estimator config type %T",
+			estimatorCfg)
+	}
+
 	return &GetMissionControlConfigResponse{
-		Config: &MissionControlConfig{
-			HalfLifeSeconds:             uint64(cfg.PenaltyHalfLife.Seconds()),
-			HopProbability:              float32(cfg.AprioriHopProbability),
-			Weight:                      float32(cfg.AprioriWeight),
-			MaximumPaymentResults:       uint32(cfg.MaxMcHistory),
-			MinimumFailureRelaxInterval: uint64(cfg.MinFailureRelaxInterval.Seconds()),
-		},
+		Config: rpcCfg,
 	}, nil
 }
 
@@ -457,14 +543,56 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 	req *SetMissionControlConfigRequest) (*SetMissionControlConfigResponse,
 	error) {
 
+	if req.Config == nil {
+		return nil, errors.New("mission control config required")
+	}
+
+	var (
+		estimator routing.Estimator
+		err       error
+	)
+	switch req.Config.Model {
+	case MissionControlConfig_APRIORI:
+		estimator, err = routing.NewAprioriEstimator(
+			routing.AprioriConfig{
+				PenaltyHalfLife: time.Duration(
+					req.Config.HalfLifeSeconds,
+				) * time.Second,
+				AprioriHopProbability: float64(
+					req.Config.HopProbability,
+				),
+				AprioriWeight: float64(req.Config.Weight),
+			},
+		)
+
+	case MissionControlConfig_BIMODAL:
+		if req.Config.Bimodal == nil {
+			return nil, errors.New("bimodal parameters required " +
+				"for bimodal model")
+		}
+
+		estimator, err = routing.NewBimodalEstimator(
+			routing.BimodalConfig{
+				BimodalNodeWeight: req.Config.Bimodal.NodeWeight,
+				BimodalScaleMsat: lnwire.MilliSatoshi(
+					req.Config.Bimodal.ScaleMsat,
+				),
+				BimodalDecayTime: time.Duration(
+					req.Config.Bimodal.DecayTime,
+				) * time.Second,
+			},
+		)
+
+	default:
+		return nil, fmt.Errorf("unknown probability model %v",
+			req.Config.Model)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &routing.MissionControlConfig{
-		ProbabilityEstimatorCfg: routing.ProbabilityEstimatorCfg{
-			PenaltyHalfLife: time.Duration(
-				req.Config.HalfLifeSeconds,
-			) * time.Second,
-			AprioriHopProbability: float64(req.Config.HopProbability),
-			AprioriWeight:         float64(req.Config.Weight),
-		},
+		Estimator:    estimator,
 		MaxMcHistory: int(req.Config.MaximumPaymentResults),
 		MinFailureRelaxInterval: time.Duration(
 			req.Config.MinimumFailureRelaxInterval,
@@ -524,7 +652,9 @@ func toRPCPairData(data *routing.TimedPairResult) *PairData {
 }
 
 // XImportMissionControl imports the state provided to our internal mission
-// control. Only entries that are fresher than our existing state will be used.
+// control. The imported entries are merged with our existing state using the
+// merge policy of the request, which by default only uses entries that are
+// fresher than our existing state.
 func (s *Server) XImportMissionControl(ctx context.Context,
 	req *XImportMissionControlRequest) (*XImportMissionControlResponse,
 	error) {
@@ -533,6 +663,11 @@ func (s *Server) XImportMissionControl(ctx context.Context,
 		return nil, errors.New("at least one pair required for import")
 	}
 
+	policy, err := unmarshallMergePolicy(req.MergePolicy)
+	if err != nil {
+		return nil, err
+	}
+
 	snapshot := &routing.MissionControlSnapshot{
 		Pairs: make(
 			[]routing.MissionControlPairSnapshot, len(req.Pairs),
@@ -548,7 +683,7 @@ func (s *Server) XImportMissionControl(ctx context.Context,
 		snapshot.Pairs[i] = *pairSnapshot
 	}
 
-	err := s.cfg.RouterBackend.MissionControl.ImportHistory(snapshot)
+	err = s.cfg.RouterBackend.MissionControl.ImportHistory(snapshot, policy)
 	if err != nil {
 		return nil, err
 	}
@@ -556,6 +691,27 @@ func (s *Server) XImportMissionControl(ctx context.Context,
 	return &XImportMissionControlResponse{}, nil
 }
 
+// unmarshallMergePolicy converts the rpc merge policy of a mission control
+// import to its routing equivalent.
+func unmarshallMergePolicy(
+	policy XImportMissionControlRequest_MergePolicy) (routing.MergePolicy,
+	error) {
+
+	switch policy {
+	case XImportMissionControlRequest_KEEP_NEWER:
+		return routing.MergeKeepNewer, nil
+
+	case XImportMissionControlRequest_OVERWRITE:
+		return routing.MergeOverwrite, nil
+
+	case XImportMissionControlRequest_AVERAGE:
+		return routing.MergeAverage, nil
+
+	default:
+		return 0, fmt.Errorf("unknown merge policy %v", policy)
+	}
+}
+
 func toPairSnapshot(pairResult *PairHistory) (*routing.MissionControlPairSnapshot,
 	error) {
 
@@ -576,10 +732,12 @@ func toPairSnapshot(pairResult *PairHistory) (*routing.MissionControlPairSnapsho
 			"differ", pairPrefix)
 	}
 
+	// A failure without an amount is an amount-independent failure, so
+	// only successes require an amount.
 	failAmt, failTime, err := getPair(
 		lnwire.MilliSatoshi(pairResult.History.FailAmtMsat),
 		btcutil.Amount(pairResult.History.FailAmtSat),
-		pairResult.History.FailTime,
+		pairResult.History.FailTime, true,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("%v invalid failure: %v", pairPrefix,
@@ -589,14 +747,14 @@ func toPairSnapshot(pairResult *PairHistory) (*routing.MissionControlPairSnapsho
 	successAmt, successTime, err := getPair(
 		lnwire.MilliSatoshi(pairResult.History.SuccessAmtMsat),
 		btcutil.Amount(pairResult.History.SuccessAmtSat),
-		pairResult.History.SuccessTime,
+		pairResult.History.SuccessTime, false,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("%v invalid success: %v", pairPrefix,
 			err)
 	}
 
-	if successAmt == 0 && failAmt == 0 {
+	if successTime.IsZero() && failTime.IsZero() {
 		return nil, fmt.Errorf("%v: either success or failure result "+
 			"required", pairPrefix)
 	}
@@ -617,9 +775,11 @@ func toPairSnapshot(pairResult *PairHistory) (*routing.MissionControlPairSnapsho
 }
 
 // getPair validates the values provided for a mission control result and
-// returns the msat amount and timestamp for it.
+// returns the msat amount and timestamp for it. If allowZeroAmt is set, a
+// result with a timestamp but without an amount is accepted.
 func getPair(amtMsat lnwire.MilliSatoshi, amtSat btcutil.Amount,
-	timestamp int64) (lnwire.MilliSatoshi, time.Time, error) {
+	timestamp int64, allowZeroAmt bool) (lnwire.MilliSatoshi, time.Time,
+	error) {
 
 	amt, err := getMsatPairValue(amtMsat, amtSat)
 	if err != nil {
@@ -632,7 +792,7 @@ func getPair(amtMsat lnwire.MilliSatoshi, amtSat btcutil.Amount,
 	)
 
 	switch {
-	case timeSet && amountSet:
+	case timeSet && (amountSet || allowZeroAmt):
 		return amt, time.Unix(timestamp, 0), nil
 
 	case timeSet && !amountSet:
@@ -693,8 +853,17 @@ func (s *Server) QueryProbability(ctx context.Context,
 
 	amt := lnwire.MilliSatoshi(req.AmtMsat)
 
+	// If the capacity can't be determined, we leave it at zero which
+	// means that it is unknown.
+	capacity, err := s.cfg.RouterBackend.FetchAmountPairCapacity(
+		fromNode, toNode, amt,
+	)
+	if err != nil {
+		capacity = 0
+	}
+
 	mc := s.cfg.RouterBackend.MissionControl
-	prob := mc.GetProbability(fromNode, toNode, amt)
+	prob := mc.GetProbability(fromNode, toNode, amt, capacity)
 	history := mc.GetPairHistorySnapshot(fromNode, toNode)
 
 	return &QueryProbabilityResponse{
@@ -927,3 +1096,154 @@ func (s *Server) UpdateChanStatus(ctx context.Context,
 	}
 	return &UpdateChanStatusResponse{}, nil
 }
+
+// GetCircuitBreaker returns the circuit breaker limits and the live counters
+// of the peers known to the circuit breaker.
+func (s *Server) GetCircuitBreaker(ctx context.Context,
+	req *GetCircuitBreakerRequest) (*GetCircuitBreakerResponse, error) {
+
+	breaker := s.cfg.RouterBackend.CircuitBreaker
+
+	resp := &GetCircuitBreakerResponse{
+		DefaultLimits: marshallCircuitBreakerLimits(
+			breaker.DefaultLimits(),
+		),
+	}
+
+	for peer, peerStatus := range breaker.Status() {
+		peer := peer
+
+		resp.Peers = append(resp.Peers, &CircuitBreakerPeer{
+			Peer: peer[:],
+			Limits: marshallCircuitBreakerLimits(
+				peerStatus.Limits,
+			),
+			CustomLimits:   peerStatus.CustomLimits,
+			PendingHtlcs:   peerStatus.PendingHtlcs,
+			PendingMsat:    uint64(peerStatus.PendingMsat),
+			QueuedHtlcs:    peerStatus.QueuedHtlcs,
+			ForwardedHtlcs: peerStatus.ForwardedHtlcs,
+			RejectedHtlcs:  peerStatus.RejectedHtlcs,
+		})
+	}
+
+	// Sort the peers so that the output is stable.
+	sort.Slice(resp.Peers, func(i, j int) bool {
+		return bytes.Compare(resp.Peers[i].Peer, resp.Peers[j].Peer) < 0
+	})
+
+	return resp, nil
+}
+
+// SetCircuitBreakerLimits sets the default circuit breaker limits or the
+// custom limits of a single peer.
+func (s *Server) SetCircuitBreakerLimits(ctx context.Context,
+	req *SetCircuitBreakerLimitsRequest) (*SetCircuitBreakerLimitsResponse,
+	error) {
+
+	breaker := s.cfg.RouterBackend.CircuitBreaker
+
+	// Without a peer, the default limits are set.
+	if len(req.Peer) == 0 {
+		if req.Limits == nil {
+			return nil, errors.New("limits required to set the " +
+				"default limits")
+		}
+
+		limits, err := unmarshallCircuitBreakerLimits(req.Limits)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("Setting default circuit breaker limits: %+v",
+			limits)
+
+		if err := breaker.SetDefaultLimits(*limits); err != nil {
+			return nil, err
+		}
+
+		return &SetCircuitBreakerLimitsResponse{}, nil
+	}
+
+	peer, err := route.NewVertexFromBytes(req.Peer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without limits, the peer falls back to the default limits.
+	if req.Limits == nil {
+		log.Infof("Clearing circuit breaker limits of peer %v", peer)
+
+		if err := breaker.ClearPeerLimits(peer); err != nil {
+			return nil, err
+		}
+
+		return &SetCircuitBreakerLimitsResponse{}, nil
+	}
+
+	limits, err := unmarshallCircuitBreakerLimits(req.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Setting circuit breaker limits of peer %v: %+v", peer,
+		limits)
+
+	if err := breaker.SetPeerLimits(peer, *limits); err != nil {
+		return nil, err
+	}
+
+	return &SetCircuitBreakerLimitsResponse{}, nil
+}
+
+// marshallCircuitBreakerLimits converts circuit breaker limits to their rpc
+// representation.
+func marshallCircuitBreakerLimits(
+	limits htlcswitch.CircuitBreakerLimits) *CircuitBreakerLimits {
+
+	rpcLimits := &CircuitBreakerLimits{
+		MaxPendingHtlcs: limits.MaxPendingHtlcs,
+		MaxPendingMsat:  uint64(limits.MaxPendingMsat),
+		HtlcRate:        limits.HtlcRate,
+		HtlcBurst:       limits.HtlcBurst,
+		Mode:            CircuitBreakerLimits_FAIL,
+	}
+
+	if limits.Mode == htlcswitch.CircuitBreakerModeQueue {
+		rpcLimits.Mode = CircuitBreakerLimits_QUEUE
+	}
+
+	return rpcLimits
+}
+
+// unmarshallCircuitBreakerLimits converts circuit breaker limits from their
+// rpc representation.
+func unmarshallCircuitBreakerLimits(
+	rpcLimits *CircuitBreakerLimits) (*htlcswitch.CircuitBreakerLimits,
+	error) {
+
+	if rpcLimits.HtlcRate < 0 {
+		return nil, errors.New("htlc rate must not be negative")
+	}
+
+	limits := &htlcswitch.CircuitBreakerLimits{
+		MaxPendingHtlcs: rpcLimits.MaxPendingHtlcs,
+		MaxPendingMsat:  lnwire.MilliSatoshi(rpcLimits.MaxPendingMsat),
+		HtlcRate:        rpcLimits.HtlcRate,
+		HtlcBurst:       rpcLimits.HtlcBurst,
+	}
+
+	switch rpcLimits.Mode {
+	case CircuitBreakerLimits_FAIL:
+		limits.Mode = htlcswitch.CircuitBreakerModeFail
+
+	case CircuitBreakerLimits_QUEUE:
+		limits.Mode = htlcswitch.CircuitBreakerModeQueue
+
+	default:
+		return nil, fmt.Errorf("unknown circuit breaker mode %v",
+			rpcLimits.Mode)
+	}
+
+	return limits, nil
+}
@@ -12,6 +12,10 @@ type RoutingConfig struct {
 	// to attempt the payment.
 	MinRouteProbability float64 `long:"minrtprob" description:"Minimum required route success probability to attempt the payment"`
 
+	// ProbabilityEstimatorType is the name of the probability estimator
+	// that mission control uses.
+	ProbabilityEstimatorType string `long:"estimator" choice:"apriori" choice:"bimodal" description:"Probability estimator used for pathfinding. The apriori estimator is configured through apriorihopprob, aprioriweight and penaltyhalflife, the bimodal estimator through the bimodal options."`
+
 	// AprioriHopProbability is the assumed success probability of a hop in
 	// a route when no other information is available.
 	AprioriHopProbability float64 `long:"apriorihopprob" description:"Assumed success probability of a hop in a route when no other information is available."`
@@ -43,4 +47,28 @@ type RoutingConfig struct {
 	// MaxMcHistory defines the maximum number of payment results that
 	// are held on disk by mission control.
 	MaxMcHistory int `long:"maxmchistory" description:"the maximum number of payment results that are held on disk by mission control"`
+
+	// BimodalConfig defines parameters for the bimodal probability
+	// estimator.
+	BimodalConfig *BimodalConfig `group:"bimodal" namespace:"bimodal"`
+}
+
+// BimodalConfig defines configuration for the bimodal probability estimator.
+type BimodalConfig struct {
+	// NodeWeight defines how strongly other previous forwardings on
+	// channels of a router should be taken into account when computing a
+	// channel's probability to route. The allowed values are in the range
+	// [0, 1], where a value of 0 means that only direct information about a
+	// channel is taken into account.
+	NodeWeight float64 `long:"nodeweight" description:"Defines how strongly non-routed channels of forwarders should be taken into account for probability estimation. Valid values are in [0, 1]."`
+
+	// Scale describes the scale over which channels still have some
+	// liquidity left on both channel ends. A very low value (compared to
+	// typical channel capacities) means that we assume unbalanced channels,
+	// a very high value means randomly balanced channels. Value in msat.
+	Scale int64 `long:"scale" description:"Defines the unbalancedness assumed for the network, the amount defined in msat."`
+
+	// DecayTime defines the information decay of knowledge about previous
+	// successes and failures in channels.
+	DecayTime time.Duration `long:"decaytime" description:"Describes the information decay of knowledge about previous successes and failures in channels."`
 }
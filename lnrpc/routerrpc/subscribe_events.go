@@ -194,6 +194,15 @@ func rpcFailureResolution(invoiceFailure invoices.FailResolutionResult) (
 	case invoices.ResultMppInProgress:
 		return FailureDetail_MPP_IN_PROGRESS, nil
 
+	case invoices.ResultTrampolineFeeInsufficient:
+		return FailureDetail_TRAMPOLINE_FEE_INSUFFICIENT, nil
+
+	case invoices.ResultTrampolineExpiryTooSoon:
+		return FailureDetail_TRAMPOLINE_EXPIRY_TOO_SOON, nil
+
+	case invoices.ResultTrampolineFailure:
+		return FailureDetail_TRAMPOLINE_FAILURE, nil
+
 	default:
 		return 0, fmt.Errorf("unknown fail resolution: %v",
 			invoiceFailure.FailureString())
@@ -238,6 +247,9 @@ func rpcOutgoingFailure(failureDetail htlcswitch.OutgoingFailure) (
 	case htlcswitch.OutgoingFailureForwardsDisabled:
 		return FailureDetail_FORWARDS_DISABLED, nil
 
+	case htlcswitch.OutgoingFailureCircuitBreaker:
+		return FailureDetail_CIRCUIT_BREAKER, nil
+
 	default:
 		return 0, fmt.Errorf("unknown outgoing failure "+
 			"detail: %v", failureDetail.FailureString())
@@ -683,6 +683,28 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 			witnessType = WitnessType_NESTED_WITNESS_KEY_HASH
 		case input.CommitmentAnchor:
 			witnessType = WitnessType_COMMITMENT_ANCHOR
+		case input.TaprootCommitmentTimeLock:
+			witnessType = WitnessType_TAPROOT_COMMITMENT_TIME_LOCK
+		case input.TaprootCommitmentToRemoteConfirmed:
+			witnessType = WitnessType_TAPROOT_COMMITMENT_TO_REMOTE_CONFIRMED
+		case input.TaprootCommitmentAnchor:
+			witnessType = WitnessType_TAPROOT_COMMITMENT_ANCHOR
+		case input.TaprootCommitmentRevoke:
+			witnessType = WitnessType_TAPROOT_COMMITMENT_REVOKE
+		case input.TaprootHtlcOfferedRevoke:
+			witnessType = WitnessType_TAPROOT_HTLC_OFFERED_REVOKE
+		case input.TaprootHtlcAcceptedRevoke:
+			witnessType = WitnessType_TAPROOT_HTLC_ACCEPTED_REVOKE
+		case input.TaprootHtlcOfferedTimeoutSecondLevel:
+			witnessType = WitnessType_TAPROOT_HTLC_OFFERED_TIMEOUT_SECOND_LEVEL
+		case input.TaprootHtlcAcceptedSuccessSecondLevel:
+			witnessType = WitnessType_TAPROOT_HTLC_ACCEPTED_SUCCESS_SECOND_LEVEL
+		case input.TaprootHtlcOfferedRemoteTimeout:
+			witnessType = WitnessType_TAPROOT_HTLC_OFFERED_REMOTE_TIMEOUT
+		case input.TaprootHtlcAcceptedRemoteSuccess:
+			witnessType = WitnessType_TAPROOT_HTLC_ACCEPTED_REMOTE_SUCCESS
+		case input.TaprootHtlcSecondLevelRevoke:
+			witnessType = WitnessType_TAPROOT_HTLC_SECOND_LEVEL_REVOKE
 		default:
 			log.Warnf("Unhandled witness type %v for input %v",
 				pendingInput.WitnessType, pendingInput.OutPoint)
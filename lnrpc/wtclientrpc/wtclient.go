@@ -209,6 +209,9 @@ func (c *WatchtowerClient) AddTower(ctx context.Context,
 	if err := c.cfg.AnchorClient.AddTower(towerAddr); err != nil {
 		return nil, err
 	}
+	if err := c.cfg.TaprootClient.AddTower(towerAddr); err != nil {
+		return nil, err
+	}
 
 	return &AddTowerResponse{}, nil
 }
@@ -250,6 +253,10 @@ func (c *WatchtowerClient) RemoveTower(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	err = c.cfg.TaprootClient.RemoveTower(pubKey, addr)
+	if err != nil {
+		return nil, err
+	}
 
 	return &RemoveTowerResponse{}, nil
 }
@@ -262,6 +269,11 @@ func (c *WatchtowerClient) ListTowers(ctx context.Context,
 		return nil, err
 	}
 
+	taprootTowers, err := c.cfg.TaprootClient.RegisteredTowers()
+	if err != nil {
+		return nil, err
+	}
+
 	anchorTowers, err := c.cfg.AnchorClient.RegisteredTowers()
 	if err != nil {
 		return nil, err
@@ -274,6 +286,9 @@ func (c *WatchtowerClient) ListTowers(ctx context.Context,
 
 	// Filter duplicates.
 	towers := make(map[wtdb.TowerID]*wtclient.RegisteredTower)
+	for _, tower := range taprootTowers {
+		towers[tower.Tower.ID] = tower
+	}
 	for _, tower := range anchorTowers {
 		towers[tower.Tower.ID] = tower
 	}
@@ -308,6 +323,9 @@ func (c *WatchtowerClient) GetTowerInfo(ctx context.Context,
 	if err == wtdb.ErrTowerNotFound {
 		tower, err = c.cfg.AnchorClient.LookupTower(pubKey)
 	}
+	if err == wtdb.ErrTowerNotFound {
+		tower, err = c.cfg.TaprootClient.LookupTower(pubKey)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -326,6 +344,7 @@ func (c *WatchtowerClient) Stats(ctx context.Context,
 	clientStats := []wtclient.ClientStats{
 		c.cfg.Client.Stats(),
 		c.cfg.AnchorClient.Stats(),
+		c.cfg.TaprootClient.Stats(),
 	}
 
 	var stats wtclient.ClientStats
@@ -364,6 +383,8 @@ func (c *WatchtowerClient) Policy(ctx context.Context,
 		policy = c.cfg.Client.Policy()
 	case PolicyType_ANCHOR:
 		policy = c.cfg.AnchorClient.Policy()
+	case PolicyType_TAPROOT:
+		policy = c.cfg.TaprootClient.Policy()
 	default:
 		return nil, fmt.Errorf("unknown policy type: %v",
 			req.PolicyType)
@@ -9,6 +9,8 @@ import (
 	"github.com/btcsuite/btcd/wire"
 
 	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 // DummySignature is a dummy Signature implementation.
@@ -35,6 +37,14 @@ func (d *DummySigner) SignOutputRaw(tx *wire.MsgTx,
 	return &DummySignature{}, nil
 }
 
+// MuSig2Sign returns an empty partial signature.
+func (d *DummySigner) MuSig2Sign(keyDesc *keychain.KeyDescriptor,
+	nonces *schnorr.Nonces, session *schnorr.Session) (schnorr.PartialSig,
+	error) {
+
+	return schnorr.PartialSig{}, nil
+}
+
 // ComputeInputScript returns nil for both values.
 func (d *DummySigner) ComputeInputScript(tx *wire.MsgTx,
 	signDesc *input.SignDescriptor) (*input.Script, error) {
@@ -70,6 +80,10 @@ func (s *SingleSigner) SignOutputRaw(tx *wire.MsgTx,
 			signDesc.DoubleTweak)
 	}
 
+	if signDesc.SignMethod.IsTaproot() {
+		return input.SignTaproot(tx, signDesc, privKey)
+	}
+
 	sig, err := txscript.RawTxInWitnessSignature(tx, signDesc.SigHashes,
 		signDesc.InputIndex, amt, witnessScript, signDesc.HashType,
 		privKey)
@@ -80,6 +94,19 @@ func (s *SingleSigner) SignOutputRaw(tx *wire.MsgTx,
 	return btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
 }
 
+// MuSig2Sign creates a MuSig2 partial signature for the signing session with
+// the stored private key.
+func (s *SingleSigner) MuSig2Sign(keyDesc *keychain.KeyDescriptor,
+	nonces *schnorr.Nonces, session *schnorr.Session) (schnorr.PartialSig,
+	error) {
+
+	if !s.Privkey.PubKey().IsEqual(keyDesc.PubKey) {
+		return schnorr.PartialSig{}, fmt.Errorf("incorrect key passed")
+	}
+
+	return session.Sign(nonces, s.Privkey)
+}
+
 // ComputeInputScript computes an input script with the stored private key
 // given a transaction and a SignDescriptor.
 func (s *SingleSigner) ComputeInputScript(tx *wire.MsgTx,
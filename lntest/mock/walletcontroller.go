@@ -43,6 +43,14 @@ func (w *WalletController) BackEnd() string {
 func (w *WalletController) FetchInputInfo(
 	prevOut *wire.OutPoint) (*lnwallet.Utxo, error) {
 
+	// If the output is one of the utxos the mock was set up with, then
+	// we'll return it as is.
+	for _, utxo := range w.Utxos {
+		if utxo.OutPoint == *prevOut {
+			return utxo, nil
+		}
+	}
+
 	utxo := &lnwallet.Utxo{
 		AddressType:   lnwallet.WitnessPubKey,
 		Value:         10 * btcutil.SatoshiPerBitcoin,
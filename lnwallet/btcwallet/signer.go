@@ -12,6 +12,7 @@ import (
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 // FetchInputInfo queries for the WalletController's knowledge of the passed
@@ -21,7 +22,7 @@ import (
 //
 // This is a part of the WalletController interface.
 func (b *BtcWallet) FetchInputInfo(prevOut *wire.OutPoint) (*lnwallet.Utxo, error) {
-	_, txOut, _, confirmations, err := b.wallet.FetchInputInfo(prevOut)
+	prevTx, txOut, _, confirmations, err := b.wallet.FetchInputInfo(prevOut)
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +42,7 @@ func (b *BtcWallet) FetchInputInfo(prevOut *wire.OutPoint) (*lnwallet.Utxo, erro
 		PkScript:      txOut.PkScript,
 		Confirmations: confirmations,
 		OutPoint:      *prevOut,
+		PrevTx:        prevTx,
 	}, nil
 }
 
@@ -194,6 +196,12 @@ func (b *BtcWallet) SignOutputRaw(tx *wire.MsgTx,
 		return nil, err
 	}
 
+	// Taproot inputs are signed with a BIP-340 signature over the
+	// BIP-341 sighash instead.
+	if signDesc.SignMethod.IsTaproot() {
+		return input.SignTaproot(tx, signDesc, privKey)
+	}
+
 	// TODO(roasbeef): generate sighash midstate if not present?
 
 	amt := signDesc.Output.Value
@@ -209,6 +217,23 @@ func (b *BtcWallet) SignOutputRaw(tx *wire.MsgTx,
 	return btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
 }
 
+// MuSig2Sign creates a MuSig2 partial signature for the signing session with
+// the private key of the key descriptor. The secret nonces are consumed, so
+// they can't be used for another signature.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) MuSig2Sign(keyDesc *keychain.KeyDescriptor,
+	nonces *schnorr.Nonces, session *schnorr.Session) (schnorr.PartialSig,
+	error) {
+
+	privKey, err := b.fetchPrivKey(keyDesc)
+	if err != nil {
+		return schnorr.PartialSig{}, err
+	}
+
+	return session.Sign(nonces, privKey)
+}
+
 // ComputeInputScript generates a complete InputScript for the passed
 // transaction with the signature as defined within the passed SignDescriptor.
 // This method is capable of generating the proper input script for both
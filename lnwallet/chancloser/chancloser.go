@@ -9,7 +9,9 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/labels"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
@@ -520,13 +522,16 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 		// Otherwise, we've agreed on a fee for the closing transaction! We'll
 		// craft the final closing transaction so we can broadcast it to the
 		// network.
+		chanType := c.cfg.Channel.State().ChanType
 		matchingSig := c.priorFeeOffers[remoteProposedFee].Signature
-		localSig, err := matchingSig.ToSignature()
+		localSig, err := parseCloseSig(chanType, matchingSig)
 		if err != nil {
 			return nil, false, err
 		}
 
-		remoteSig, err := closeSignedMsg.Signature.ToSignature()
+		remoteSig, err := parseCloseSig(
+			chanType, closeSignedMsg.Signature,
+		)
 		if err != nil {
 			return nil, false, err
 		}
@@ -739,3 +744,15 @@ func ParseUpfrontShutdownAddress(address string,
 
 	return txscript.PayToAddrScript(addr)
 }
+
+// parseCloseSig parses a signature of the closing transaction, which is a
+// BIP-340 signature for taproot channels.
+func parseCloseSig(chanType channeldb.ChannelType,
+	sig lnwire.Sig) (input.Signature, error) {
+
+	if chanType.IsTaproot() {
+		return sig.ToSchnorrSignature()
+	}
+
+	return sig.ToSignature()
+}
@@ -78,6 +78,10 @@ type Request struct {
 	// ChangeAddr is a closure that will provide the Assembler with a
 	// change address for the funding transaction if needed.
 	ChangeAddr func() (btcutil.Address, error)
+
+	// Taproot should be set if the funding output is the one of a taproot
+	// channel.
+	Taproot bool
 }
 
 // Intent is returned by an Assembler and represents the base functionality the
@@ -35,10 +35,15 @@ type ShimIntent struct {
 	// a normal channel. Until this height, it's considered frozen, so it
 	// can only be cooperatively closed by the responding party.
 	thawHeight uint32
+
+	// taproot indicates that the funding output is the one of a taproot
+	// channel.
+	taproot bool
 }
 
 // FundingOutput returns the witness script, and the output that creates the
-// funding output.
+// funding output. For taproot channels the witness script is the 2-of-2 leaf
+// of the funding output.
 //
 // NOTE: This method satisfies the chanfunding.Intent interface.
 func (s *ShimIntent) FundingOutput() ([]byte, *wire.TxOut, error) {
@@ -48,6 +53,19 @@ func (s *ShimIntent) FundingOutput() ([]byte, *wire.TxOut, error) {
 	}
 
 	totalAmt := s.localFundingAmt + s.remoteFundingAmt
+	if s.taproot {
+		tree, txOut, err := input.GenTaprootFundingPkScript(
+			s.localKey.PubKey.SerializeCompressed(),
+			s.remoteKey.SerializeCompressed(),
+			int64(totalAmt),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return tree.Leaves[0], txOut, nil
+	}
+
 	return input.GenFundingPkScript(
 		s.localKey.PubKey.SerializeCompressed(),
 		s.remoteKey.SerializeCompressed(),
@@ -207,6 +225,7 @@ func (c *CannedAssembler) ProvisionChannel(req *Request) (Intent, error) {
 		remoteKey:  c.remoteKey,
 		chanPoint:  &c.chanPoint,
 		thawHeight: c.thawHeight,
+		taproot:    req.Taproot,
 	}
 
 	if c.initiator {
@@ -496,6 +496,13 @@ func (p *PsbtAssembler) ProvisionChannel(req *Request) (Intent, error) {
 		return nil, fmt.Errorf("SubtractFees not supported for PSBT")
 	}
 
+	// The funding address of a PSBT is a P2WSH address, so taproot
+	// channels can't be funded this way.
+	if req.Taproot {
+		return nil, fmt.Errorf("taproot channels not supported for " +
+			"PSBT")
+	}
+
 	intent := &PsbtIntent{
 		ShimIntent: ShimIntent{
 			localFundingAmt: p.fundingAmt,
@@ -153,6 +153,81 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 	return fundingTx, nil
 }
 
+// SignInteractiveFundingTx is to be called after BindKeys, in place of
+// CompileFundingTx, when the funding transaction was constructed together
+// with the remote party, as is done when dual funding a channel. The amount
+// the remote party contributed to the funding output is recorded, all inputs
+// of this intent are signed in place, and the channel point is located.
+func (f *FullIntent) SignInteractiveFundingTx(fundingTx *wire.MsgTx,
+	remoteAmt btcutil.Amount) error {
+
+	f.remoteFundingAmt = remoteAmt
+
+	// The funding output must pay the exact value of both contributions
+	// to our multi-sig script.
+	_, fundingOutput, err := f.FundingOutput()
+	if err != nil {
+		return err
+	}
+	found, multiSigIndex := input.FindScriptOutputIndex(
+		fundingTx, fundingOutput.PkScript,
+	)
+	if !found {
+		return fmt.Errorf("funding output not found in funding tx")
+	}
+	if fundingTx.TxOut[multiSigIndex].Value != fundingOutput.Value {
+		return fmt.Errorf("funding output pays %v, expected %v",
+			fundingTx.TxOut[multiSigIndex].Value,
+			fundingOutput.Value)
+	}
+
+	// Only the coins we selected are signed. The remote party's inputs
+	// will be signed by them once the commitments have been exchanged.
+	ourCoins := make(map[wire.OutPoint]Coin, len(f.InputCoins))
+	for _, coin := range f.InputCoins {
+		ourCoins[coin.OutPoint] = coin
+	}
+
+	signDesc := input.SignDescriptor{
+		HashType:  txscript.SigHashAll,
+		SigHashes: txscript.NewTxSigHashes(fundingTx),
+	}
+	for i, txIn := range fundingTx.TxIn {
+		coin, ok := ourCoins[txIn.PreviousOutPoint]
+		if !ok {
+			continue
+		}
+		delete(ourCoins, txIn.PreviousOutPoint)
+
+		signDesc.Output = &wire.TxOut{
+			Value:    coin.Value,
+			PkScript: coin.PkScript,
+		}
+		signDesc.InputIndex = i
+
+		inputScript, err := f.signer.ComputeInputScript(
+			fundingTx, &signDesc,
+		)
+		if err != nil {
+			return err
+		}
+
+		txIn.SignatureScript = inputScript.SigScript
+		txIn.Witness = inputScript.Witness
+	}
+	if len(ourCoins) != 0 {
+		return fmt.Errorf("funding tx is missing %v of our inputs",
+			len(ourCoins))
+	}
+
+	f.chanPoint = &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: multiSigIndex,
+	}
+
+	return nil
+}
+
 // Inputs returns all inputs to the final funding transaction that we
 // know about. Since this funding transaction is created all from our wallet,
 // it will be all inputs.
@@ -342,6 +417,7 @@ func (w *WalletAssembler) ProvisionChannel(r *Request) (Intent, error) {
 			ShimIntent: ShimIntent{
 				localFundingAmt:  localContributionAmt,
 				remoteFundingAmt: r.RemoteAmt,
+				taproot:          r.Taproot,
 			},
 			InputCoins: selectedCoins,
 			coinLocker: w.cfg.CoinLocker,
@@ -23,6 +23,7 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -305,7 +306,7 @@ type PaymentDescriptor struct {
 	// local node. This signature is generated by the remote node and
 	// stored by the local node in the case that local node needs to
 	// broadcast their commitment transaction.
-	sig *btcec.Signature
+	sig input.Signature
 
 	// addCommitHeight[Remote|Local] encodes the height of the commitment
 	// which included this HTLC on either the remote or local commitment
@@ -328,6 +329,12 @@ type PaymentDescriptor struct {
 	// NOTE: Populated only on add payment descriptor entry types.
 	OnionBlob []byte
 
+	// BlindingPoint is the blinding point that was sent along with the
+	// HTLC if it's forwarded inside of a blinded route.
+	//
+	// NOTE: Populated only on add payment descriptor entry types.
+	BlindingPoint *btcec.PublicKey
+
 	// ShaOnionBlob is a sha of the onion blob.
 	//
 	// NOTE: Populated only in payment descriptor with MalformedFail type.
@@ -358,6 +365,13 @@ type PaymentDescriptor struct {
 	theirPkScript      []byte
 	theirWitnessScript []byte
 
+	// ourTapTree and theirTapTree are the tapscript trees of the HTLC
+	// outputs of taproot channels, from the context of our local and the
+	// remote commitment chain respectively. They're used instead of the
+	// witness scripts above to spend the outputs.
+	ourTapTree   *input.TapscriptTree
+	theirTapTree *input.TapscriptTree
+
 	// EntryType denotes the exact type of the PaymentDescriptor. In the
 	// case of a Timeout, or Settle type, then the Parent field will point
 	// into the log to the HTLC being modified.
@@ -409,6 +423,7 @@ func PayDescsFromRemoteLogUpdates(chanID lnwire.ShortChannelID, height uint64,
 			}
 			pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 			copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
+			pd.BlindingPoint = wireMsg.BlindingPoint
 
 		case *lnwire.UpdateFulfillHTLC:
 			pd = PaymentDescriptor{
@@ -729,6 +744,7 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 			HtlcIndex:     htlc.HtlcIndex,
 			LogIndex:      htlc.LogIndex,
 			Incoming:      false,
+			BlindingPoint: htlc.BlindingPoint,
 		}
 		h.OnionBlob = make([]byte, len(htlc.OnionBlob))
 		copy(h.OnionBlob[:], htlc.OnionBlob)
@@ -754,6 +770,7 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 			HtlcIndex:     htlc.HtlcIndex,
 			LogIndex:      htlc.LogIndex,
 			Incoming:      true,
+			BlindingPoint: htlc.BlindingPoint,
 		}
 		h.OnionBlob = make([]byte, len(htlc.OnionBlob))
 		copy(h.OnionBlob[:], htlc.OnionBlob)
@@ -782,11 +799,10 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 	// generated so we can easily locate them within the commitment
 	// transaction in the future.
 	var (
-		ourP2WSH, theirP2WSH                 []byte
-		ourWitnessScript, theirWitnessScript []byte
-		pd                                   PaymentDescriptor
-		err                                  error
-		chanType                             = lc.channelState.ChanType
+		ourScript, theirScript = &ScriptInfo{}, &ScriptInfo{}
+		pd                     PaymentDescriptor
+		err                    error
+		chanType               = lc.channelState.ChanType
 	)
 
 	// If the either outputs is dust from the local or remote node's
@@ -799,7 +815,7 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 		htlc.Amt.ToSatoshis(), lc.channelState.LocalChanCfg.DustLimit,
 	)
 	if !isDustLocal && localCommitKeys != nil {
-		ourP2WSH, ourWitnessScript, err = genHtlcScript(
+		ourScript, err = genHtlcScript(
 			chanType, htlc.Incoming, true, htlc.RefundTimeout,
 			htlc.RHash, localCommitKeys,
 		)
@@ -812,7 +828,7 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 		htlc.Amt.ToSatoshis(), lc.channelState.RemoteChanCfg.DustLimit,
 	)
 	if !isDustRemote && remoteCommitKeys != nil {
-		theirP2WSH, theirWitnessScript, err = genHtlcScript(
+		theirScript, err = genHtlcScript(
 			chanType, htlc.Incoming, false, htlc.RefundTimeout,
 			htlc.RHash, remoteCommitKeys,
 		)
@@ -845,12 +861,15 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 		HtlcIndex:          htlc.HtlcIndex,
 		LogIndex:           htlc.LogIndex,
 		OnionBlob:          htlc.OnionBlob,
+		BlindingPoint:      htlc.BlindingPoint,
 		localOutputIndex:   localOutputIndex,
 		remoteOutputIndex:  remoteOutputIndex,
-		ourPkScript:        ourP2WSH,
-		ourWitnessScript:   ourWitnessScript,
-		theirPkScript:      theirP2WSH,
-		theirWitnessScript: theirWitnessScript,
+		ourPkScript:        ourScript.PkScript,
+		ourWitnessScript:   ourScript.WitnessScript,
+		ourTapTree:         ourScript.TapTree,
+		theirPkScript:      theirScript.PkScript,
+		theirWitnessScript: theirScript.WitnessScript,
+		theirTapTree:       theirScript.TapTree,
 	}
 
 	return pd, nil
@@ -1296,6 +1315,10 @@ type LightningChannel struct {
 	// channel.
 	RemoteFundingKey *btcec.PublicKey
 
+	// pendingSplices is the set of splice transactions that spend the
+	// current funding output and have yet to be locked.
+	pendingSplices []*SpliceCandidate
+
 	// log is a channel-specific logging instance.
 	log btclog.Logger
 
@@ -1362,32 +1385,88 @@ func NewLightningChannel(signer input.Signer,
 // createSignDesc derives the SignDescriptor for commitment transactions from
 // other fields on the LightningChannel.
 func (lc *LightningChannel) createSignDesc() error {
-	localKey := lc.channelState.LocalChanCfg.MultiSigKey.PubKey.
-		SerializeCompressed()
-	remoteKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.
-		SerializeCompressed()
-
-	multiSigScript, err := input.GenMultiSigScript(localKey, remoteKey)
+	chanState := lc.channelState
+	signDesc, err := fundingSignDesc(
+		chanState.ChanType, chanState.LocalChanCfg.MultiSigKey,
+		chanState.RemoteChanCfg.MultiSigKey.PubKey, chanState.Capacity,
+	)
 	if err != nil {
 		return err
 	}
+	lc.signDesc = signDesc
 
-	fundingPkScript, err := input.WitnessScriptHash(multiSigScript)
+	return nil
+}
+
+// fundingSignDesc returns the SignDescriptor that spends the funding output
+// with the passed funding keys and capacity, which is used to sign the
+// commitment transactions of the channel. Taproot commitments spend the 2-of-2
+// leaf of the funding output.
+func fundingSignDesc(chanType channeldb.ChannelType,
+	localKey keychain.KeyDescriptor, remoteKey *btcec.PublicKey,
+	capacity btcutil.Amount) (*input.SignDescriptor, error) {
+
+	if chanType.IsTaproot() {
+		tree, _, err := input.TaprootFundingScript(
+			localKey.PubKey, remoteKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		signDesc := &input.SignDescriptor{
+			KeyDesc: localKey,
+			Output: &wire.TxOut{
+				PkScript: tree.PkScript,
+				Value:    int64(capacity),
+			},
+			InputIndex: 0,
+		}
+		if err := tree.SetScriptSpend(signDesc, 0); err != nil {
+			return nil, err
+		}
+
+		return signDesc, nil
+	}
+
+	multiSigScript, fundingOutput, err := input.GenFundingPkScript(
+		localKey.PubKey.SerializeCompressed(),
+		remoteKey.SerializeCompressed(), int64(capacity),
+	)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	lc.signDesc = &input.SignDescriptor{
-		KeyDesc:       lc.channelState.LocalChanCfg.MultiSigKey,
+
+	return &input.SignDescriptor{
+		KeyDesc:       localKey,
 		WitnessScript: multiSigScript,
-		Output: &wire.TxOut{
-			PkScript: fundingPkScript,
-			Value:    int64(lc.channelState.Capacity),
-		},
-		HashType:   txscript.SigHashAll,
-		InputIndex: 0,
+		Output:        fundingOutput,
+		HashType:      txscript.SigHashAll,
+		InputIndex:    0,
+	}, nil
+}
+
+// fundingSigHash computes the sighash of the passed commitment transaction,
+// which spends the funding output described by the sign descriptor.
+func fundingSigHash(commitTx *wire.MsgTx,
+	signDesc *input.SignDescriptor) ([]byte, error) {
+
+	if signDesc.SignMethod.IsTaproot() {
+		prevOuts := input.MultiPrevOutFetcher{
+			commitTx.TxIn[0].PreviousOutPoint: signDesc.Output,
+		}
+
+		return input.TaprootSigHash(
+			commitTx, prevOuts, 0, signDesc.HashType,
+			signDesc.WitnessScript,
+		)
 	}
 
-	return nil
+	hashCache := txscript.NewTxSigHashes(commitTx)
+	return txscript.CalcWitnessSigHash(
+		signDesc.WitnessScript, hashCache, signDesc.HashType,
+		commitTx, 0, signDesc.Output.Value,
+	)
 }
 
 // ResetState resets the state of the channel back to the default state. This
@@ -1437,13 +1516,14 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 		copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
+		pd.BlindingPoint = wireMsg.BlindingPoint
 
 		isDustRemote := htlcIsDust(
 			lc.channelState.ChanType, false, false, feeRate,
 			wireMsg.Amount.ToSatoshis(), remoteDustLimit,
 		)
 		if !isDustRemote {
-			theirP2WSH, theirWitnessScript, err := genHtlcScript(
+			theirScript, err := genHtlcScript(
 				lc.channelState.ChanType, false, false,
 				wireMsg.Expiry, wireMsg.PaymentHash,
 				remoteCommitKeys,
@@ -1452,8 +1532,9 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 				return nil, err
 			}
 
-			pd.theirPkScript = theirP2WSH
-			pd.theirWitnessScript = theirWitnessScript
+			pd.theirPkScript = theirScript.PkScript
+			pd.theirWitnessScript = theirScript.WitnessScript
+			pd.theirTapTree = theirScript.TapTree
 		}
 
 	// For HTLC's we're offered we'll fetch the original offered HTLC
@@ -1636,6 +1717,7 @@ func (lc *LightningChannel) remoteLogUpdateToPayDesc(logUpdate *channeldb.LogUpd
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 		copy(pd.OnionBlob, wireMsg.OnionBlob[:])
+		pd.BlindingPoint = wireMsg.BlindingPoint
 
 		// We don't need to generate an htlc script yet. This will be
 		// done once we sign our remote commitment.
@@ -2198,6 +2280,10 @@ type HtlcRetribution struct {
 	// broadcast/confirmed. We provide this as if the remote party attempts
 	// to go to the second level to claim the HTLC then we'll need to
 	// update the SignDesc above accordingly to sweep properly.
+	//
+	// NOTE: For taproot channels this is the delay leaf of the second
+	// level output. Its leaf hash is the tap tweak of the revocation key,
+	// which spends the output over the key path.
 	SecondLevelWitnessScript []byte
 
 	// IsIncoming is a boolean flag that indicates whether or not this
@@ -2317,16 +2403,13 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// number so we can have the proper witness script to sign and include
 	// within the final witness.
 	theirDelay := uint32(chanState.RemoteChanCfg.CsvDelay)
-	theirPkScript, err := input.CommitScriptToSelf(
-		theirDelay, keyRing.ToLocalKey, keyRing.RevocationKey,
+	theirScript, err := CommitScriptToSelf(
+		chanState.ChanType, theirDelay, keyRing.ToLocalKey,
+		keyRing.RevocationKey,
 	)
 	if err != nil {
 		return nil, err
 	}
-	theirWitnessHash, err := input.WitnessScriptHash(theirPkScript)
-	if err != nil {
-		return nil, err
-	}
 
 	// Since it is the remote breach we are reconstructing, the output going
 	// to us will be a to-remote script with our local params.
@@ -2349,7 +2432,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		switch {
 		case bytes.Equal(txOut.PkScript, ourScript.PkScript):
 			ourOutpoint.Index = uint32(i)
-		case bytes.Equal(txOut.PkScript, theirWitnessHash):
+		case bytes.Equal(txOut.PkScript, theirScript.PkScript):
 			theirOutpoint.Index = uint32(i)
 		}
 	}
@@ -2379,6 +2462,12 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 			},
 			HashType: txscript.SigHashAll,
 		}
+
+		// Our output on a taproot commitment is swept over the
+		// script path of its only leaf.
+		if err := ourScript.setScriptSpend(ourSignDesc, 0); err != nil {
+			return nil, err
+		}
 	}
 
 	// Similarly, if their balance exceeds the remote party's dust limit,
@@ -2387,13 +2476,22 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		theirSignDesc = &input.SignDescriptor{
 			KeyDesc:       chanState.LocalChanCfg.RevocationBasePoint,
 			DoubleTweak:   commitmentSecret,
-			WitnessScript: theirPkScript,
+			WitnessScript: theirScript.WitnessScript,
 			Output: &wire.TxOut{
-				PkScript: theirWitnessHash,
+				PkScript: theirScript.PkScript,
 				Value:    int64(theirAmt),
 			},
 			HashType: txscript.SigHashAll,
 		}
+
+		// Their output on a taproot commitment is swept over the
+		// revocation leaf.
+		err := theirScript.setScriptSpend(
+			theirSignDesc, input.TaprootRevokeLeaf,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// With the commitment outputs located, we'll now generate all the
@@ -2415,19 +2513,25 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		// as we'll need it if we're revoking an HTLC output on the
 		// remote commitment transaction, and *they* go to the second
 		// level.
-		secondLevelWitnessScript, err := input.SecondLevelHtlcScript(
-			keyRing.RevocationKey, keyRing.ToLocalKey, theirDelay,
+		secondLevelScript, err := SecondLevelHtlcScript(
+			chanState.ChanType, keyRing.RevocationKey,
+			keyRing.ToLocalKey, theirDelay,
 		)
 		if err != nil {
 			return nil, err
 		}
+		secondLevelWitnessScript := secondLevelScript.WitnessScript
+		if tree := secondLevelScript.TapTree; tree != nil {
+			delayLeaf := tree.Leaves[input.TaprootDelayLeaf]
+			secondLevelWitnessScript = delayLeaf
+		}
 
 		// If this is an incoming HTLC, then this means that they were
 		// the sender of the HTLC (relative to us). So we'll
 		// re-generate the sender HTLC script. Otherwise, is this was
 		// an outgoing HTLC that we sent, then from the PoV of the
 		// remote commitment state, they're the receiver of this HTLC.
-		htlcPkScript, htlcWitnessScript, err := genHtlcScript(
+		htlcScript, err := genHtlcScript(
 			chanState.ChanType, htlc.Incoming, false,
 			htlc.RefundTimeout, htlc.RHash, keyRing,
 		)
@@ -2435,17 +2539,23 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 			return nil, err
 		}
 
-		htlcRetributions = append(htlcRetributions, HtlcRetribution{
-			SignDesc: input.SignDescriptor{
-				KeyDesc:       chanState.LocalChanCfg.RevocationBasePoint,
-				DoubleTweak:   commitmentSecret,
-				WitnessScript: htlcWitnessScript,
-				Output: &wire.TxOut{
-					PkScript: htlcPkScript,
-					Value:    int64(htlc.Amt.ToSatoshis()),
-				},
-				HashType: txscript.SigHashAll,
+		signDesc := input.SignDescriptor{
+			KeyDesc:       chanState.LocalChanCfg.RevocationBasePoint,
+			DoubleTweak:   commitmentSecret,
+			WitnessScript: htlcScript.WitnessScript,
+			Output: &wire.TxOut{
+				PkScript: htlcScript.PkScript,
+				Value:    int64(htlc.Amt.ToSatoshis()),
 			},
+			HashType: txscript.SigHashAll,
+		}
+
+		// The revocation key is the internal key of taproot HTLC
+		// outputs, so they're swept over the key path.
+		htlcScript.setKeySpend(&signDesc)
+
+		htlcRetributions = append(htlcRetributions, HtlcRetribution{
+			SignDesc: signDesc,
 			OutPoint: wire.OutPoint{
 				Hash:  commitHash,
 				Index: uint32(htlc.OutputIndex),
@@ -3046,6 +3156,13 @@ func genRemoteHtlcSigJobs(keyRing *CommitmentKeyRing,
 			SigHashes:     txscript.NewTxSigHashes(sigJob.Tx),
 			InputIndex:    0,
 		}
+		err = setSecondLevelSpend(
+			&sigJob.SignDesc, htlc.theirTapTree,
+			input.TaprootTimeoutLeaf,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
 		sigJob.OutputIndex = htlc.remoteOutputIndex
 
 		sigBatch = append(sigBatch, sigJob)
@@ -3098,6 +3215,13 @@ func genRemoteHtlcSigJobs(keyRing *CommitmentKeyRing,
 			SigHashes:     txscript.NewTxSigHashes(sigJob.Tx),
 			InputIndex:    0,
 		}
+		err = setSecondLevelSpend(
+			&sigJob.SignDesc, htlc.theirTapTree,
+			input.TaprootSuccessLeaf,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
 		sigJob.OutputIndex = htlc.remoteOutputIndex
 
 		sigBatch = append(sigBatch, sigJob)
@@ -3159,11 +3283,12 @@ func (lc *LightningChannel) createCommitDiff(
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -3296,11 +3421,12 @@ func (lc *LightningChannel) getUnsignedAckedUpdates() []channeldb.LogUpdate {
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -4067,6 +4193,51 @@ func (lc *LightningChannel) computeView(view *htlcView, remoteChain bool,
 	return ourBalance, theirBalance, totalCommitWeight, filteredHTLCView, nil
 }
 
+// wireSigToSignature converts a commitment or second-level HTLC signature
+// received from the remote party, which is a BIP-340 signature for taproot
+// channels.
+func wireSigToSignature(chanType channeldb.ChannelType,
+	sig lnwire.Sig) (input.Signature, error) {
+
+	if chanType.IsTaproot() {
+		return sig.ToSchnorrSignature()
+	}
+
+	return sig.ToSignature()
+}
+
+// wireSigToBytes returns the serialized form of a signature received from the
+// remote party, which is a DER encoded ECDSA signature, or a 64-byte BIP-340
+// signature for taproot channels.
+func wireSigToBytes(chanType channeldb.ChannelType, sig lnwire.Sig) []byte {
+	if chanType.IsTaproot() {
+		sigBytes := make([]byte, len(sig))
+		copy(sigBytes, sig[:])
+
+		return sigBytes
+	}
+
+	return sig.ToSignatureBytes()
+}
+
+// htlcTaprootSigHash computes the sighash of a second-level HTLC transaction
+// spending the leaf with the passed index of a taproot HTLC output on our
+// commitment transaction.
+func htlcTaprootSigHash(htlcTx *wire.MsgTx, htlc *PaymentDescriptor,
+	sigHashType txscript.SigHashType, leaf int) ([]byte, error) {
+
+	prevOuts := input.MultiPrevOutFetcher{
+		htlcTx.TxIn[0].PreviousOutPoint: {
+			Value:    int64(htlc.Amount.ToSatoshis()),
+			PkScript: htlc.ourPkScript,
+		},
+	}
+
+	return input.TaprootSigHash(
+		htlcTx, prevOuts, 0, sigHashType, htlc.ourTapTree.Leaves[leaf],
+	)
+}
+
 // genHtlcSigValidationJobs generates a series of signatures verification jobs
 // meant to verify all the signatures for HTLC's attached to a newly created
 // commitment state. The jobs generated are fully populated, and can be sent
@@ -4097,7 +4268,7 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 		var (
 			htlcIndex uint64
 			sigHash   func() ([]byte, error)
-			sig       *btcec.Signature
+			sig       input.Signature
 			err       error
 		)
 
@@ -4130,6 +4301,13 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 					return nil, err
 				}
 
+				if chanType.IsTaproot() {
+					return htlcTaprootSigHash(
+						successTx, htlc, sigHashType,
+						input.TaprootSuccessLeaf,
+					)
+				}
+
 				hashCache := txscript.NewTxSigHashes(successTx)
 				sigHash, err := txscript.CalcWitnessSigHash(
 					htlc.ourWitnessScript, hashCache,
@@ -4152,7 +4330,7 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 			// With the sighash generated, we'll also store the
 			// signature so it can be written to disk if this state
 			// is valid.
-			sig, err = htlcSigs[i].ToSignature()
+			sig, err = wireSigToSignature(chanType, htlcSigs[i])
 			if err != nil {
 				return nil, err
 			}
@@ -4184,6 +4362,13 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 					return nil, err
 				}
 
+				if chanType.IsTaproot() {
+					return htlcTaprootSigHash(
+						timeoutTx, htlc, sigHashType,
+						input.TaprootTimeoutLeaf,
+					)
+				}
+
 				hashCache := txscript.NewTxSigHashes(timeoutTx)
 				sigHash, err := txscript.CalcWitnessSigHash(
 					htlc.ourWitnessScript, hashCache,
@@ -4206,7 +4391,7 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 			// With the sighash generated, we'll also store the
 			// signature so it can be written to disk if this state
 			// is valid.
-			sig, err = htlcSigs[i].ToSignature()
+			sig, err = wireSigToSignature(chanType, htlcSigs[i])
 			if err != nil {
 				return nil, err
 			}
@@ -4376,12 +4561,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 	// Construct the sighash of the commitment transaction corresponding to
 	// this newly proposed state update.
 	localCommitTx := localCommitmentView.txn
-	multiSigScript := lc.signDesc.WitnessScript
-	hashCache := txscript.NewTxSigHashes(localCommitTx)
-	sigHash, err := txscript.CalcWitnessSigHash(
-		multiSigScript, hashCache, txscript.SigHashAll,
-		localCommitTx, 0, int64(lc.channelState.Capacity),
-	)
+	sigHash, err := fundingSigHash(localCommitTx, lc.signDesc)
 	if err != nil {
 		// TODO(roasbeef): fetchview has already mutated the HTLCs...
 		//  * need to either roll-back, or make pure
@@ -4411,7 +4591,8 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 		Y:     lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.Y,
 		Curve: btcec.S256(),
 	}
-	cSig, err := commitSig.ToSignature()
+	chanType := lc.channelState.ChanType
+	cSig, err := wireSigToSignature(chanType, commitSig)
 	if err != nil {
 		return err
 	}
@@ -4426,7 +4607,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 		localCommitTx.Serialize(&txBytes)
 		return &InvalidCommitSigError{
 			commitHeight: nextHeight,
-			commitSig:    commitSig.ToSignatureBytes(),
+			commitSig:    wireSigToBytes(chanType, commitSig),
 			sigHash:      sigHash,
 			commitTx:     txBytes.Bytes(),
 		}
@@ -4456,7 +4637,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 			localCommitTx.Serialize(&txBytes)
 			return &InvalidHtlcSigError{
 				commitHeight: nextHeight,
-				htlcSig:      sig.ToSignatureBytes(),
+				htlcSig:      wireSigToBytes(chanType, sig),
 				htlcIndex:    htlcErr.HtlcIndex,
 				sigHash:      sigHash,
 				commitTx:     txBytes.Bytes(),
@@ -4466,7 +4647,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 
 	// The signature checks out, so we can now add the new commitment to
 	// our local commitment chain.
-	localCommitmentView.sig = commitSig.ToSignatureBytes()
+	localCommitmentView.sig = wireSigToBytes(chanType, commitSig)
 	lc.localCommitChain.addCommitment(localCommitmentView)
 
 	return nil
@@ -4762,11 +4943,12 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) (
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -4932,6 +5114,7 @@ func (lc *LightningChannel) AddHTLC(htlc *lnwire.UpdateAddHTLC,
 		LogIndex:       lc.localUpdateLog.logIndex,
 		HtlcIndex:      lc.localUpdateLog.htlcCounter,
 		OnionBlob:      htlc.OnionBlob[:],
+		BlindingPoint:  htlc.BlindingPoint,
 		OpenCircuitKey: openKey,
 	}
 
@@ -4979,13 +5162,14 @@ func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, err
 	}
 
 	pd := &PaymentDescriptor{
-		EntryType: Add,
-		RHash:     PaymentHash(htlc.PaymentHash),
-		Timeout:   htlc.Expiry,
-		Amount:    htlc.Amount,
-		LogIndex:  lc.remoteUpdateLog.logIndex,
-		HtlcIndex: lc.remoteUpdateLog.htlcCounter,
-		OnionBlob: htlc.OnionBlob[:],
+		EntryType:     Add,
+		RHash:         PaymentHash(htlc.PaymentHash),
+		Timeout:       htlc.Expiry,
+		Amount:        htlc.Amount,
+		LogIndex:      lc.remoteUpdateLog.logIndex,
+		HtlcIndex:     lc.remoteUpdateLog.htlcCounter,
+		OnionBlob:     htlc.OnionBlob[:],
+		BlindingPoint: htlc.BlindingPoint,
 	}
 
 	localACKedIndex := lc.remoteCommitChain.tail().ourMessageIndex
@@ -5301,8 +5485,8 @@ func (lc *LightningChannel) getSignedCommitTx() (*wire.MsgTx, error) {
 	localCommit := lc.channelState.LocalCommitment
 	commitTx := localCommit.CommitTx.Copy()
 
-	theirSig, err := btcec.ParseDERSignature(
-		localCommit.CommitSig, btcec.S256(),
+	theirSig, err := parseRemoteSig(
+		lc.channelState.ChanType, localCommit.CommitSig,
 	)
 	if err != nil {
 		return nil, err
@@ -5323,6 +5507,16 @@ func (lc *LightningChannel) getSignedCommitTx() (*wire.MsgTx, error) {
 	theirKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.
 		SerializeCompressed()
 
+	// Taproot commitments spend the 2-of-2 leaf of the funding output.
+	if lc.channelState.ChanType.IsTaproot() {
+		commitTx.TxIn[0].Witness = input.TaprootSpendMultiSig(
+			lc.signDesc.WitnessScript, lc.signDesc.ControlBlock,
+			ourKey, ourSig, theirKey, theirSig,
+		)
+
+		return commitTx, nil
+	}
+
 	commitTx.TxIn[0].Witness = input.SpendMultiSig(
 		lc.signDesc.WitnessScript, ourKey,
 		ourSig, theirKey, theirSig,
@@ -5461,19 +5655,24 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	var commitResolution *CommitOutputResolution
 	if selfPoint != nil {
 		localPayBase := chanState.LocalChanCfg.PaymentBasePoint
-		commitResolution = &CommitOutputResolution{
-			SelfOutPoint: *selfPoint,
-			SelfOutputSignDesc: input.SignDescriptor{
-				KeyDesc:       localPayBase,
-				SingleTweak:   keyRing.LocalCommitKeyTweak,
-				WitnessScript: selfScript.WitnessScript,
-				Output: &wire.TxOut{
-					Value:    localBalance,
-					PkScript: selfScript.PkScript,
-				},
-				HashType: txscript.SigHashAll,
+		signDesc := input.SignDescriptor{
+			KeyDesc:       localPayBase,
+			SingleTweak:   keyRing.LocalCommitKeyTweak,
+			WitnessScript: selfScript.WitnessScript,
+			Output: &wire.TxOut{
+				Value:    localBalance,
+				PkScript: selfScript.PkScript,
 			},
-			MaturityDelay: maturityDelay,
+			HashType: txscript.SigHashAll,
+		}
+		if err := selfScript.setScriptSpend(&signDesc, 0); err != nil {
+			return nil, err
+		}
+
+		commitResolution = &CommitOutputResolution{
+			SelfOutPoint:       *selfPoint,
+			SelfOutputSignDesc: signDesc,
+			MaturityDelay:      maturityDelay,
 		}
 	}
 
@@ -5649,7 +5848,7 @@ func newOutgoingHtlcResolution(signer input.Signer,
 
 	// First, we'll re-generate the script used to send the HTLC to
 	// the remote party within their commitment transaction.
-	htlcScriptHash, htlcScript, err := genHtlcScript(
+	htlcScript, err := genHtlcScript(
 		chanType, false, localCommit, htlc.RefundTimeout, htlc.RHash,
 		keyRing,
 	)
@@ -5663,20 +5862,28 @@ func newOutgoingHtlcResolution(signer input.Signer,
 	if !localCommit {
 		// With the script generated, we can completely populated the
 		// SignDescriptor needed to sweep the output.
+		sweepSignDesc := input.SignDescriptor{
+			KeyDesc:       localChanCfg.HtlcBasePoint,
+			SingleTweak:   keyRing.LocalHtlcKeyTweak,
+			WitnessScript: htlcScript.WitnessScript,
+			Output: &wire.TxOut{
+				PkScript: htlcScript.PkScript,
+				Value:    int64(htlc.Amt.ToSatoshis()),
+			},
+			HashType: txscript.SigHashAll,
+		}
+		err := htlcScript.setScriptSpend(
+			&sweepSignDesc, input.TaprootTimeoutLeaf,
+		)
+		if err != nil {
+			return nil, err
+		}
+
 		return &OutgoingHtlcResolution{
 			Expiry:        htlc.RefundTimeout,
 			ClaimOutpoint: op,
-			SweepSignDesc: input.SignDescriptor{
-				KeyDesc:       localChanCfg.HtlcBasePoint,
-				SingleTweak:   keyRing.LocalHtlcKeyTweak,
-				WitnessScript: htlcScript,
-				Output: &wire.TxOut{
-					PkScript: htlcScriptHash,
-					Value:    int64(htlc.Amt.ToSatoshis()),
-				},
-				HashType: txscript.SigHashAll,
-			},
-			CsvDelay: HtlcSecondLevelInputSequence(chanType),
+			SweepSignDesc: sweepSignDesc,
+			CsvDelay:      HtlcSecondLevelInputSequence(chanType),
 		}, nil
 	}
 
@@ -5706,14 +5913,20 @@ func newOutgoingHtlcResolution(signer input.Signer,
 	timeoutSignDesc := input.SignDescriptor{
 		KeyDesc:       localChanCfg.HtlcBasePoint,
 		SingleTweak:   keyRing.LocalHtlcKeyTweak,
-		WitnessScript: htlcScript,
+		WitnessScript: htlcScript.WitnessScript,
 		Output:        txOut,
 		HashType:      txscript.SigHashAll,
 		SigHashes:     txscript.NewTxSigHashes(timeoutTx),
 		InputIndex:    0,
 	}
+	err = htlcScript.setScriptSpend(
+		&timeoutSignDesc, input.TaprootTimeoutLeaf,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	htlcSig, err := btcec.ParseDERSignature(htlc.Signature, btcec.S256())
+	htlcSig, err := parseRemoteSig(chanType, htlc.Signature)
 	if err != nil {
 		return nil, err
 	}
@@ -5721,9 +5934,18 @@ func newOutgoingHtlcResolution(signer input.Signer,
 	// With the sign desc created, we can now construct the full witness
 	// for the timeout transaction, and populate it as well.
 	sigHashType := HtlcSigHashType(chanType)
-	timeoutWitness, err := input.SenderHtlcSpendTimeout(
-		htlcSig, sigHashType, signer, &timeoutSignDesc, timeoutTx,
-	)
+	var timeoutWitness wire.TxWitness
+	if chanType.IsTaproot() {
+		timeoutWitness, err = input.TaprootSenderHtlcSpendTimeout(
+			htlcSig, sigHashType, signer, &timeoutSignDesc,
+			timeoutTx,
+		)
+	} else {
+		timeoutWitness, err = input.SenderHtlcSpendTimeout(
+			htlcSig, sigHashType, signer, &timeoutSignDesc,
+			timeoutTx,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -5738,20 +5960,33 @@ func newOutgoingHtlcResolution(signer input.Signer,
 	// Finally, we'll generate the script output that the timeout
 	// transaction creates so we can generate the signDesc required to
 	// complete the claim process after a delay period.
-	htlcSweepScript, err := input.SecondLevelHtlcScript(
-		keyRing.RevocationKey, keyRing.ToLocalKey, csvDelay,
+	htlcSweepScript, err := SecondLevelHtlcScript(
+		chanType, keyRing.RevocationKey, keyRing.ToLocalKey, csvDelay,
 	)
 	if err != nil {
 		return nil, err
 	}
-	htlcSweepScriptHash, err := input.WitnessScriptHash(htlcSweepScript)
-	if err != nil {
-		return nil, err
-	}
 
 	localDelayTweak := input.SingleTweakBytes(
 		keyRing.CommitPoint, localChanCfg.DelayBasePoint.PubKey,
 	)
+	sweepSignDesc := input.SignDescriptor{
+		KeyDesc:       localChanCfg.DelayBasePoint,
+		SingleTweak:   localDelayTweak,
+		WitnessScript: htlcSweepScript.WitnessScript,
+		Output: &wire.TxOut{
+			PkScript: htlcSweepScript.PkScript,
+			Value:    int64(secondLevelOutputAmt),
+		},
+		HashType: txscript.SigHashAll,
+	}
+	err = htlcSweepScript.setScriptSpend(
+		&sweepSignDesc, input.TaprootDelayLeaf,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &OutgoingHtlcResolution{
 		Expiry:          htlc.RefundTimeout,
 		SignedTimeoutTx: timeoutTx,
@@ -5761,16 +5996,7 @@ func newOutgoingHtlcResolution(signer input.Signer,
 			Hash:  timeoutTx.TxHash(),
 			Index: 0,
 		},
-		SweepSignDesc: input.SignDescriptor{
-			KeyDesc:       localChanCfg.DelayBasePoint,
-			SingleTweak:   localDelayTweak,
-			WitnessScript: htlcSweepScript,
-			Output: &wire.TxOut{
-				PkScript: htlcSweepScriptHash,
-				Value:    int64(secondLevelOutputAmt),
-			},
-			HashType: txscript.SigHashAll,
-		},
+		SweepSignDesc: sweepSignDesc,
 	}, nil
 }
 
@@ -5794,7 +6020,7 @@ func newIncomingHtlcResolution(signer input.Signer,
 
 	// First, we'll re-generate the script the remote party used to
 	// send the HTLC to us in their commitment transaction.
-	htlcScriptHash, htlcScript, err := genHtlcScript(
+	htlcScript, err := genHtlcScript(
 		chanType, true, localCommit, htlc.RefundTimeout, htlc.RHash,
 		keyRing,
 	)
@@ -5807,19 +6033,27 @@ func newIncomingHtlcResolution(signer input.Signer,
 	if !localCommit {
 		// With the script generated, we can completely populated the
 		// SignDescriptor needed to sweep the output.
+		sweepSignDesc := input.SignDescriptor{
+			KeyDesc:       localChanCfg.HtlcBasePoint,
+			SingleTweak:   keyRing.LocalHtlcKeyTweak,
+			WitnessScript: htlcScript.WitnessScript,
+			Output: &wire.TxOut{
+				PkScript: htlcScript.PkScript,
+				Value:    int64(htlc.Amt.ToSatoshis()),
+			},
+			HashType: txscript.SigHashAll,
+		}
+		err := htlcScript.setScriptSpend(
+			&sweepSignDesc, input.TaprootSuccessLeaf,
+		)
+		if err != nil {
+			return nil, err
+		}
+
 		return &IncomingHtlcResolution{
 			ClaimOutpoint: op,
-			SweepSignDesc: input.SignDescriptor{
-				KeyDesc:       localChanCfg.HtlcBasePoint,
-				SingleTweak:   keyRing.LocalHtlcKeyTweak,
-				WitnessScript: htlcScript,
-				Output: &wire.TxOut{
-					PkScript: htlcScriptHash,
-					Value:    int64(htlc.Amt.ToSatoshis()),
-				},
-				HashType: txscript.SigHashAll,
-			},
-			CsvDelay: HtlcSecondLevelInputSequence(chanType),
+			SweepSignDesc: sweepSignDesc,
+			CsvDelay:      HtlcSecondLevelInputSequence(chanType),
 		}, nil
 	}
 
@@ -5843,14 +6077,20 @@ func newIncomingHtlcResolution(signer input.Signer,
 	successSignDesc := input.SignDescriptor{
 		KeyDesc:       localChanCfg.HtlcBasePoint,
 		SingleTweak:   keyRing.LocalHtlcKeyTweak,
-		WitnessScript: htlcScript,
+		WitnessScript: htlcScript.WitnessScript,
 		Output:        txOut,
 		HashType:      txscript.SigHashAll,
 		SigHashes:     txscript.NewTxSigHashes(successTx),
 		InputIndex:    0,
 	}
+	err = htlcScript.setScriptSpend(
+		&successSignDesc, input.TaprootSuccessLeaf,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	htlcSig, err := btcec.ParseDERSignature(htlc.Signature, btcec.S256())
+	htlcSig, err := parseRemoteSig(chanType, htlc.Signature)
 	if err != nil {
 		return nil, err
 	}
@@ -5860,9 +6100,18 @@ func newIncomingHtlcResolution(signer input.Signer,
 	// will be supplied by the contract resolver, either directly or when it
 	// becomes known.
 	sigHashType := HtlcSigHashType(chanType)
-	successWitness, err := input.ReceiverHtlcSpendRedeem(
-		htlcSig, sigHashType, nil, signer, &successSignDesc, successTx,
-	)
+	var successWitness wire.TxWitness
+	if chanType.IsTaproot() {
+		successWitness, err = input.TaprootReceiverHtlcSpendRedeem(
+			htlcSig, sigHashType, nil, signer, &successSignDesc,
+			successTx,
+		)
+	} else {
+		successWitness, err = input.ReceiverHtlcSpendRedeem(
+			htlcSig, sigHashType, nil, signer, &successSignDesc,
+			successTx,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -5877,20 +6126,33 @@ func newIncomingHtlcResolution(signer input.Signer,
 	// Finally, we'll generate the script that the second-level transaction
 	// creates so we can generate the proper signDesc to sweep it after the
 	// CSV delay has passed.
-	htlcSweepScript, err := input.SecondLevelHtlcScript(
-		keyRing.RevocationKey, keyRing.ToLocalKey, csvDelay,
+	htlcSweepScript, err := SecondLevelHtlcScript(
+		chanType, keyRing.RevocationKey, keyRing.ToLocalKey, csvDelay,
 	)
 	if err != nil {
 		return nil, err
 	}
-	htlcSweepScriptHash, err := input.WitnessScriptHash(htlcSweepScript)
-	if err != nil {
-		return nil, err
-	}
 
 	localDelayTweak := input.SingleTweakBytes(
 		keyRing.CommitPoint, localChanCfg.DelayBasePoint.PubKey,
 	)
+	sweepSignDesc := input.SignDescriptor{
+		KeyDesc:       localChanCfg.DelayBasePoint,
+		SingleTweak:   localDelayTweak,
+		WitnessScript: htlcSweepScript.WitnessScript,
+		Output: &wire.TxOut{
+			PkScript: htlcSweepScript.PkScript,
+			Value:    int64(secondLevelOutputAmt),
+		},
+		HashType: txscript.SigHashAll,
+	}
+	err = htlcSweepScript.setScriptSpend(
+		&sweepSignDesc, input.TaprootDelayLeaf,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &IncomingHtlcResolution{
 		SignedSuccessTx: successTx,
 		SignDetails:     txSignDetails,
@@ -5899,16 +6161,7 @@ func newIncomingHtlcResolution(signer input.Signer,
 			Hash:  successTx.TxHash(),
 			Index: 0,
 		},
-		SweepSignDesc: input.SignDescriptor{
-			KeyDesc:       localChanCfg.DelayBasePoint,
-			SingleTweak:   localDelayTweak,
-			WitnessScript: htlcSweepScript,
-			Output: &wire.TxOut{
-				PkScript: htlcSweepScriptHash,
-				Value:    int64(secondLevelOutputAmt),
-			},
-			HashType: txscript.SigHashAll,
-		},
+		SweepSignDesc: sweepSignDesc,
 	}, nil
 }
 
@@ -6127,16 +6380,13 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
-	selfScript, err := input.CommitScriptToSelf(
-		csvTimeout, keyRing.ToLocalKey, keyRing.RevocationKey,
+	selfScript, err := CommitScriptToSelf(
+		chanState.ChanType, csvTimeout, keyRing.ToLocalKey,
+		keyRing.RevocationKey,
 	)
 	if err != nil {
 		return nil, err
 	}
-	payToUsScriptHash, err := input.WitnessScriptHash(selfScript)
-	if err != nil {
-		return nil, err
-	}
 
 	// Locate the output index of the delayed commitment output back to us.
 	// We'll return the details of this output to the caller so they can
@@ -6146,7 +6396,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 		delayOut   *wire.TxOut
 	)
 	for i, txOut := range commitTx.TxOut {
-		if !bytes.Equal(payToUsScriptHash, txOut.PkScript) {
+		if !bytes.Equal(selfScript.PkScript, txOut.PkScript) {
 			continue
 		}
 
@@ -6164,22 +6414,30 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	var commitResolution *CommitOutputResolution
 	if delayOut != nil {
 		localBalance := delayOut.Value
+		signDesc := input.SignDescriptor{
+			KeyDesc:       chanState.LocalChanCfg.DelayBasePoint,
+			SingleTweak:   keyRing.LocalCommitKeyTweak,
+			WitnessScript: selfScript.WitnessScript,
+			Output: &wire.TxOut{
+				PkScript: delayOut.PkScript,
+				Value:    localBalance,
+			},
+			HashType: txscript.SigHashAll,
+		}
+		err := selfScript.setScriptSpend(
+			&signDesc, input.TaprootDelayLeaf,
+		)
+		if err != nil {
+			return nil, err
+		}
+
 		commitResolution = &CommitOutputResolution{
 			SelfOutPoint: wire.OutPoint{
 				Hash:  commitTx.TxHash(),
 				Index: delayIndex,
 			},
-			SelfOutputSignDesc: input.SignDescriptor{
-				KeyDesc:       chanState.LocalChanCfg.DelayBasePoint,
-				SingleTweak:   keyRing.LocalCommitKeyTweak,
-				WitnessScript: selfScript,
-				Output: &wire.TxOut{
-					PkScript: delayOut.PkScript,
-					Value:    localBalance,
-				},
-				HashType: txscript.SigHashAll,
-			},
-			MaturityDelay: csvTimeout,
+			SelfOutputSignDesc: signDesc,
+			MaturityDelay:      csvTimeout,
 		}
 	}
 
@@ -6336,6 +6594,29 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 		SerializeCompressed()
 	theirKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.
 		SerializeCompressed()
+
+	// Taproot channels are closed over the 2-of-2 leaf of the funding
+	// output, which our script engine can't validate, so we verify the
+	// witness ourselves.
+	if lc.channelState.ChanType.IsTaproot() {
+		closeTx.TxIn[0].Witness = input.TaprootSpendMultiSig(
+			lc.signDesc.WitnessScript, lc.signDesc.ControlBlock,
+			ourKey, localSig, theirKey, remoteSig,
+		)
+
+		prevOuts := input.MultiPrevOutFetcher{
+			closeTx.TxIn[0].PreviousOutPoint: lc.signDesc.Output,
+		}
+		err := input.VerifyTaprootSpend(closeTx, 0, prevOuts)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		lc.status = channelClosed
+
+		return closeTx, ourBalance, nil
+	}
+
 	witness := input.SpendMultiSig(
 		lc.signDesc.WitnessScript, ourKey, localSig, theirKey,
 		remoteSig,
@@ -6430,7 +6711,8 @@ func NewAnchorResolution(chanState *channeldb.OpenChannel,
 
 	// Derive our local anchor script.
 	localAnchor, _, err := CommitScriptAnchors(
-		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+		chanState.ChanType, &chanState.LocalChanCfg,
+		&chanState.RemoteChanCfg,
 	)
 	if err != nil {
 		return nil, err
@@ -6459,12 +6741,18 @@ func NewAnchorResolution(chanState *channeldb.OpenChannel,
 		HashType: txscript.SigHashAll,
 	}
 
+	// The taproot anchor is swept over the key path with our funding key.
+	localAnchor.setKeySpend(signDesc)
+
 	// Calculate commit tx weight. This commit tx doesn't yet include the
 	// witness spending the funding output, so we add the (worst case)
 	// weight for that too.
+	witnessWeight := int64(input.WitnessCommitmentTxWeight)
+	if chanState.ChanType.IsTaproot() {
+		witnessWeight = input.TaprootWitnessCommitmentTxWeight
+	}
 	utx := btcutil.NewTx(commitTx)
-	weight := blockchain.GetTransactionWeight(utx) +
-		input.WitnessCommitmentTxWeight
+	weight := blockchain.GetTransactionWeight(utx) + witnessWeight
 
 	// Calculate commit tx fee.
 	fee := chanState.Capacity
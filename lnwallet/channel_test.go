@@ -699,6 +699,12 @@ func TestCooperativeChannelClosure(t *testing.T) {
 			anchorAmt: anchorSize * 2,
 		})
 	})
+	t.Run("taproot", func(t *testing.T) {
+		testCoopClose(t, &coopCloseTestCase{
+			chanType:  taprootChanType,
+			anchorAmt: anchorSize * 2,
+		})
+	})
 }
 
 type coopCloseTestCase struct {
@@ -1147,6 +1153,222 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 	}
 }
 
+// taprootChanType is the channel type of the taproot test channels.
+const taprootChanType = channeldb.SingleFunderTweaklessBit |
+	channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit |
+	channeldb.SimpleTaprootBit
+
+// assertTaprootSpend asserts that the first input of the transaction validly
+// spends the passed taproot output.
+func assertTaprootSpend(t *testing.T, tx *wire.MsgTx, prevOut *wire.TxOut) {
+	t.Helper()
+
+	prevOuts := input.MultiPrevOutFetcher{
+		tx.TxIn[0].PreviousOutPoint: prevOut,
+	}
+	require.NoError(t, input.VerifyTaprootSpend(tx, 0, prevOuts))
+}
+
+// newTaprootSweepTx creates a transaction sweeping the passed output with the
+// passed sequence.
+func newTaprootSweepTx(op wire.OutPoint, value int64,
+	sequence uint32) *wire.MsgTx {
+
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: op,
+		Sequence:         sequence,
+	})
+	sweepTx.AddTxOut(&wire.TxOut{
+		PkScript: testHdSeed[:],
+		Value:    value,
+	})
+
+	return sweepTx
+}
+
+// TestTaprootForceClose checks that the commitment transaction of a taproot
+// channel, its second-level HTLC transactions and the sweeps of all of them
+// validly spend their outputs.
+func TestTaprootForceClose(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		taprootChanType,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	// Add an HTLC in each direction and lock both of them in, which has
+	// both parties exchange BIP-340 signatures for their commitments and
+	// second-level HTLC transactions.
+	htlcAmount := lnwire.NewMSatFromSatoshis(20000)
+	htlcAlice, _ := createHTLC(0, htlcAmount)
+	_, err = aliceChannel.AddHTLC(htlcAlice, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlcAlice)
+	require.NoError(t, err)
+
+	htlcBob, preimageBob := createHTLC(0, htlcAmount)
+	_, err = bobChannel.AddHTLC(htlcBob, nil)
+	require.NoError(t, err)
+	_, err = aliceChannel.ReceiveHTLC(htlcBob)
+	require.NoError(t, err)
+
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+
+	closeSummary, err := aliceChannel.ForceClose()
+	require.NoError(t, err)
+
+	// The commitment transaction spends the 2-of-2 leaf of the funding
+	// output.
+	commitTx := closeSummary.CloseTx
+	assertTaprootSpend(t, commitTx, aliceChannel.signDesc.Output)
+
+	signer := aliceChannel.Signer
+	csvDelay := uint32(aliceChannel.channelState.LocalChanCfg.CsvDelay)
+
+	// Our to_local output can be swept after the CSV delay.
+	commitRes := closeSummary.CommitResolution
+	require.NotNil(t, commitRes)
+	signDesc := &commitRes.SelfOutputSignDesc
+	sweepTx := newTaprootSweepTx(
+		commitRes.SelfOutPoint, signDesc.Output.Value, csvDelay,
+	)
+	sweepTx.TxIn[0].Witness, err = input.TaprootCommitSpendTimeout(
+		signer, signDesc, sweepTx,
+	)
+	require.NoError(t, err)
+	assertTaprootSpend(t, sweepTx, signDesc.Output)
+
+	// The anchor is swept over the key path.
+	anchorRes := closeSummary.AnchorResolution
+	require.NotNil(t, anchorRes)
+	signDesc = &anchorRes.AnchorSignDescriptor
+	sweepTx = newTaprootSweepTx(
+		anchorRes.CommitAnchor, signDesc.Output.Value, 0,
+	)
+	sweepTx.TxIn[0].Witness, err = input.TaprootKeySpend(
+		signer, signDesc, sweepTx,
+	)
+	require.NoError(t, err)
+	assertTaprootSpend(t, sweepTx, signDesc.Output)
+
+	// The outgoing HTLC is spent by the HTLC timeout transaction, whose
+	// output can be swept after the CSV delay.
+	require.Len(t, closeSummary.HtlcResolutions.OutgoingHTLCs, 1)
+	outRes := closeSummary.HtlcResolutions.OutgoingHTLCs[0]
+	timeoutTx := outRes.SignedTimeoutTx
+	htlcOutput := commitTx.TxOut[timeoutTx.TxIn[0].PreviousOutPoint.Index]
+	assertTaprootSpend(t, timeoutTx, htlcOutput)
+
+	signDesc = &outRes.SweepSignDesc
+	sweepTx = newTaprootSweepTx(
+		outRes.ClaimOutpoint, signDesc.Output.Value, csvDelay,
+	)
+	sweepTx.TxIn[0].Witness, err = input.TaprootCommitSpendTimeout(
+		signer, signDesc, sweepTx,
+	)
+	require.NoError(t, err)
+	assertTaprootSpend(t, sweepTx, signDesc.Output)
+
+	// The incoming HTLC is spent by the HTLC success transaction once the
+	// preimage is added to its witness.
+	require.Len(t, closeSummary.HtlcResolutions.IncomingHTLCs, 1)
+	inRes := closeSummary.HtlcResolutions.IncomingHTLCs[0]
+	successTx := inRes.SignedSuccessTx
+	successTx.TxIn[0].Witness[2] = preimageBob[:]
+	htlcOutput = commitTx.TxOut[successTx.TxIn[0].PreviousOutPoint.Index]
+	assertTaprootSpend(t, successTx, htlcOutput)
+
+	signDesc = &inRes.SweepSignDesc
+	sweepTx = newTaprootSweepTx(
+		inRes.ClaimOutpoint, signDesc.Output.Value, csvDelay,
+	)
+	sweepTx.TxIn[0].Witness, err = input.TaprootCommitSpendTimeout(
+		signer, signDesc, sweepTx,
+	)
+	require.NoError(t, err)
+	assertTaprootSpend(t, sweepTx, signDesc.Output)
+}
+
+// TestTaprootBreachRetribution checks that all outputs of a revoked taproot
+// commitment transaction can be swept with the breach retribution.
+func TestTaprootBreachRetribution(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		taprootChanType,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	// Lock in an HTLC from Alice to Bob, and capture the state of Bob's
+	// commitment that includes it.
+	htlcAmount := lnwire.NewMSatFromSatoshis(20000)
+	htlc, preimage := createHTLC(0, htlcAmount)
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	revokedStateNum := bobChannel.channelState.LocalCommitment.CommitHeight
+
+	// Settling the HTLC has Bob revoke that state.
+	require.NoError(t, bobChannel.SettleHTLC(preimage, 0, nil, nil, nil))
+	require.NoError(t, aliceChannel.ReceiveHTLCSettle(preimage, 0))
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+
+	breachRet, err := NewBreachRetribution(
+		aliceChannel.channelState, revokedStateNum, 100,
+	)
+	require.NoError(t, err)
+	breachTx := breachRet.BreachTransaction
+	signer := aliceChannel.Signer
+
+	// Our to_remote output can be swept after one confirmation.
+	signDesc := breachRet.LocalOutputSignDesc
+	require.NotNil(t, signDesc)
+	sweepTx := newTaprootSweepTx(
+		breachRet.LocalOutpoint, signDesc.Output.Value, 1,
+	)
+	sweepTx.TxIn[0].Witness, err = input.TaprootCommitSpendToRemote(
+		signer, signDesc, sweepTx,
+	)
+	require.NoError(t, err)
+	assertTaprootSpend(t, sweepTx, signDesc.Output)
+
+	// Bob's to_local output is swept with the revocation key.
+	signDesc = breachRet.RemoteOutputSignDesc
+	require.NotNil(t, signDesc)
+	sweepTx = newTaprootSweepTx(
+		breachRet.RemoteOutpoint, signDesc.Output.Value, 0,
+	)
+	sweepTx.TxIn[0].Witness, err = input.TaprootCommitSpendRevoke(
+		signer, signDesc, sweepTx,
+	)
+	require.NoError(t, err)
+	assertTaprootSpend(t, sweepTx, signDesc.Output)
+	require.Equal(
+		t, breachTx.TxOut[breachRet.RemoteOutpoint.Index],
+		signDesc.Output,
+	)
+
+	// The HTLC output is swept over the key path with the revocation key.
+	require.Len(t, breachRet.HtlcRetributions, 1)
+	htlcRet := breachRet.HtlcRetributions[0]
+	sweepTx = newTaprootSweepTx(
+		htlcRet.OutPoint, htlcRet.SignDesc.Output.Value, 0,
+	)
+	sweepTx.TxIn[0].Witness, err = input.TaprootKeySpend(
+		signer, &htlcRet.SignDesc, sweepTx,
+	)
+	require.NoError(t, err)
+	assertTaprootSpend(t, sweepTx, htlcRet.SignDesc.Output)
+}
+
 // TestForceCloseDustOutput tests that if either side force closes with an
 // active dust output (for only a single party due to asymmetric dust values),
 // then the force close summary is well crafted.
@@ -7881,6 +8103,49 @@ func TestChannelRestoreCommitHeight(t *testing.T) {
 	_ = restoreAndAssertCommitHeights(t, aliceChannel, false, 1, 3, 2)
 }
 
+// TestChannelRestoreBlindingPoint tests that the blinding point of an HTLC
+// that is part of a blinded route is restored along with the commitment
+// after a restart.
+func TestChannelRestoreBlindingPoint(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	blindingPriv, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	// Alice sends an HTLC with a blinding point, which is locked in on
+	// both commitments.
+	htlcAmount := lnwire.NewMSatFromSatoshis(20000)
+	htlc, _ := createHTLC(0, htlcAmount)
+	htlc.BlindingPoint = blindingPriv.PubKey()
+
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// After a restart, both parties should have the blinding point in
+	// their update logs again.
+	newAliceChannel, err := restartChannel(aliceChannel)
+	require.NoError(t, err)
+	newBobChannel, err := restartChannel(bobChannel)
+	require.NoError(t, err)
+
+	aliceHtlc := newAliceChannel.localUpdateLog.lookupHtlc(htlc.ID)
+	require.NotNil(t, aliceHtlc)
+	require.Equal(t, htlc.BlindingPoint, aliceHtlc.BlindingPoint)
+
+	bobHtlc := newBobChannel.remoteUpdateLog.lookupHtlc(htlc.ID)
+	require.NotNil(t, bobHtlc)
+	require.Equal(t, htlc.BlindingPoint, bobHtlc.BlindingPoint)
+}
+
 // TestForceCloseFailLocalDataLoss tests that we don't allow a force close of a
 // channel that's in a non-default state.
 func TestForceCloseFailLocalDataLoss(t *testing.T) {
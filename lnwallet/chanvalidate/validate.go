@@ -7,6 +7,7 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -183,6 +184,21 @@ func Validate(ctx *Context) (*wire.OutPoint, error) {
 		return nil, ErrInvalidSize
 	}
 
+	// The script VM doesn't validate taproot spends, so the commitment
+	// transaction of a taproot channel is verified on its own.
+	if input.IsPayToTaproot(fundingScript) {
+		commitTx := ctx.CommitCtx.FullySignedCommitTx
+		prevOuts := input.MultiPrevOutFetcher{
+			commitTx.TxIn[0].PreviousOutPoint: fundingOutput,
+		}
+		err := input.VerifyTaprootSpend(commitTx, 0, prevOuts)
+		if err != nil {
+			return nil, &ErrScriptValidateError{err: err}
+		}
+
+		return chanPoint, nil
+	}
+
 	// If we reach this point, then all other checks have succeeded, so
 	// we'll now attempt a full Script VM execution to ensure that we're
 	// able to close the channel using this initial state.
@@ -12,6 +12,7 @@ import (
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 // anchorSize is the constant anchor output size.
@@ -188,6 +189,144 @@ type ScriptInfo struct {
 	// output is being signed. For p2wkh it should be set equal to the
 	// PkScript.
 	WitnessScript []byte
+
+	// TapTree is the tapscript tree of the output of a taproot channel.
+	// The WitnessScript is empty for these outputs, as the leaf script
+	// to use depends on how the output is spent.
+	TapTree *input.TapscriptTree
+}
+
+// taprootScriptInfo wraps the tapscript tree of a taproot output in a
+// ScriptInfo.
+func taprootScriptInfo(tree *input.TapscriptTree) *ScriptInfo {
+	return &ScriptInfo{
+		PkScript: tree.PkScript,
+		TapTree:  tree,
+	}
+}
+
+// setScriptSpend prepares the sign descriptor to spend the leaf with the
+// passed index if this is a taproot output. The sign descriptor of a segwit v0
+// output is left untouched.
+func (s *ScriptInfo) setScriptSpend(signDesc *input.SignDescriptor,
+	leaf int) error {
+
+	if s.TapTree == nil {
+		return nil
+	}
+
+	return s.TapTree.SetScriptSpend(signDesc, leaf)
+}
+
+// setKeySpend prepares the sign descriptor to spend a taproot output over the
+// key path. The sign descriptor of a segwit v0 output is left untouched.
+func (s *ScriptInfo) setKeySpend(signDesc *input.SignDescriptor) {
+	if s.TapTree != nil {
+		s.TapTree.SetKeySpend(signDesc)
+	}
+}
+
+// setSecondLevelSpend prepares the sign descriptor of an HTLC output spent by
+// a second-level HTLC transaction. For taproot channels the leaf with the
+// passed index is spent, and the signatures keep using the HTLC sighash type
+// of the channel, which the script path spend would reset otherwise.
+func setSecondLevelSpend(signDesc *input.SignDescriptor,
+	tree *input.TapscriptTree, leaf int) error {
+
+	if tree == nil {
+		return nil
+	}
+
+	hashType := signDesc.HashType
+	if err := tree.SetScriptSpend(signDesc, leaf); err != nil {
+		return err
+	}
+	signDesc.HashType = hashType
+
+	return nil
+}
+
+// parseRemoteSig parses a stored commitment or second-level HTLC signature of
+// the remote party, which is a BIP-340 signature for taproot channels.
+func parseRemoteSig(chanType channeldb.ChannelType,
+	sig []byte) (input.Signature, error) {
+
+	if chanType.IsTaproot() {
+		return schnorr.ParseSignature(sig)
+	}
+
+	return btcec.ParseDERSignature(sig, btcec.S256())
+}
+
+// CommitScriptToSelf creates the script of the output paying to the owner of
+// the commitment transaction, which can be spent by the owner after the CSV
+// delay, or by the remote party with the revocation key.
+func CommitScriptToSelf(chanType channeldb.ChannelType, csvTimeout uint32,
+	selfKey, revokeKey *btcec.PublicKey) (*ScriptInfo, error) {
+
+	if chanType.IsTaproot() {
+		tree, err := input.TaprootCommitScriptToSelf(
+			csvTimeout, selfKey, revokeKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return taprootScriptInfo(tree), nil
+	}
+
+	toLocalRedeemScript, err := input.CommitScriptToSelf(
+		csvTimeout, selfKey, revokeKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	toLocalScriptHash, err := input.WitnessScriptHash(
+		toLocalRedeemScript,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScriptInfo{
+		PkScript:      toLocalScriptHash,
+		WitnessScript: toLocalRedeemScript,
+	}, nil
+}
+
+// SecondLevelHtlcScript creates the script of the output of HTLC success and
+// timeout transactions, which can be spent by the owner after the CSV delay,
+// or by the remote party with the revocation key.
+func SecondLevelHtlcScript(chanType channeldb.ChannelType,
+	revocationKey, delayKey *btcec.PublicKey,
+	csvDelay uint32) (*ScriptInfo, error) {
+
+	if chanType.IsTaproot() {
+		tree, err := input.TaprootSecondLevelHtlcScript(
+			revocationKey, delayKey, csvDelay,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return taprootScriptInfo(tree), nil
+	}
+
+	witnessScript, err := input.SecondLevelHtlcScript(
+		revocationKey, delayKey, csvDelay,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := input.WitnessScriptHash(witnessScript)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScriptInfo{
+		PkScript:      pkScript,
+		WitnessScript: witnessScript,
+	}, nil
 }
 
 // CommitScriptToRemote creates the script that will pay to the non-owner of
@@ -197,6 +336,17 @@ type ScriptInfo struct {
 func CommitScriptToRemote(chanType channeldb.ChannelType,
 	key *btcec.PublicKey) (*ScriptInfo, uint32, error) {
 
+	// Taproot channels use a tapscript version of the delayed to_remote
+	// script.
+	if chanType.IsTaproot() {
+		tree, err := input.TaprootCommitScriptToRemote(key)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return taprootScriptInfo(tree), 1, nil
+	}
+
 	// If this channel type has anchors, we derive the delayed to_remote
 	// script.
 	if chanType.HasAnchors() {
@@ -270,6 +420,12 @@ func HtlcSecondLevelInputSequence(chanType channeldb.ChannelType) uint32 {
 
 // CommitWeight returns the base commitment weight before adding HTLCs.
 func CommitWeight(chanType channeldb.ChannelType) int64 {
+	// Taproot commitments have larger outputs, but spend the funding
+	// output with a smaller witness.
+	if chanType.IsTaproot() {
+		return input.TaprootCommitWeight
+	}
+
 	// If this commitment has anchors, it will be slightly heavier.
 	if chanType.HasAnchors() {
 		return input.AnchorCommitWeight
@@ -315,12 +471,21 @@ func HtlcSuccessFee(chanType channeldb.ChannelType,
 
 // CommitScriptAnchors return the scripts to use for the local and remote
 // anchor.
-func CommitScriptAnchors(localChanCfg,
+func CommitScriptAnchors(chanType channeldb.ChannelType, localChanCfg,
 	remoteChanCfg *channeldb.ChannelConfig) (*ScriptInfo,
 	*ScriptInfo, error) {
 
 	// Helper to create anchor ScriptInfo from key.
 	anchorScript := func(key *btcec.PublicKey) (*ScriptInfo, error) {
+		if chanType.IsTaproot() {
+			tree, err := input.TaprootCommitScriptAnchor(key)
+			if err != nil {
+				return nil, err
+			}
+
+			return taprootScriptInfo(tree), nil
+		}
+
 		script, err := input.CommitScriptAnchor(key)
 		if err != nil {
 			return nil, err
@@ -374,6 +539,13 @@ func NewCommitmentBuilder(chanState *channeldb.OpenChannel) *CommitmentBuilder {
 		panic("invalid channel type combination")
 	}
 
+	// Taproot channels are only defined with anchors and zero-fee HTLC
+	// transactions.
+	chanType := chanState.ChanType
+	if chanType.IsTaproot() && !chanType.ZeroHtlcTxFee() {
+		panic("invalid channel type combination")
+	}
+
 	return &CommitmentBuilder{
 		chanState:  chanState,
 		obfuscator: createStateHintObfuscator(chanState),
@@ -622,19 +794,13 @@ func CreateCommitTx(chanType channeldb.ChannelType,
 	// output after a relative block delay, or the remote node can claim
 	// the funds with the revocation key if we broadcast a revoked
 	// commitment transaction.
-	toLocalRedeemScript, err := input.CommitScriptToSelf(
-		uint32(localChanCfg.CsvDelay), keyRing.ToLocalKey,
+	toLocalScript, err := CommitScriptToSelf(
+		chanType, uint32(localChanCfg.CsvDelay), keyRing.ToLocalKey,
 		keyRing.RevocationKey,
 	)
 	if err != nil {
 		return nil, err
 	}
-	toLocalScriptHash, err := input.WitnessScriptHash(
-		toLocalRedeemScript,
-	)
-	if err != nil {
-		return nil, err
-	}
 
 	// Next, we create the script paying to the remote.
 	toRemoteScript, _, err := CommitScriptToRemote(
@@ -654,7 +820,7 @@ func CreateCommitTx(chanType channeldb.ChannelType,
 	localOutput := amountToLocal >= localChanCfg.DustLimit
 	if localOutput {
 		commitTx.AddTxOut(&wire.TxOut{
-			PkScript: toLocalScriptHash,
+			PkScript: toLocalScript.PkScript,
 			Value:    int64(amountToLocal),
 		})
 	}
@@ -670,7 +836,7 @@ func CreateCommitTx(chanType channeldb.ChannelType,
 	// If this channel type has anchors, we'll also add those.
 	if chanType.HasAnchors() {
 		localAnchor, remoteAnchor, err := CommitScriptAnchors(
-			localChanCfg, remoteChanCfg,
+			chanType, localChanCfg, remoteChanCfg,
 		)
 		if err != nil {
 			return nil, err
@@ -741,10 +907,17 @@ func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
 
 // genHtlcScript generates the proper P2WSH public key scripts for the HTLC
 // output modified by two-bits denoting if this is an incoming HTLC, and if the
-// HTLC is being applied to their commitment transaction or ours.
+// HTLC is being applied to their commitment transaction or ours. For taproot
+// channels the P2TR public key script and tapscript tree are returned instead.
 func genHtlcScript(chanType channeldb.ChannelType, isIncoming, ourCommit bool,
 	timeout uint32, rHash [32]byte,
-	keyRing *CommitmentKeyRing) ([]byte, []byte, error) {
+	keyRing *CommitmentKeyRing) (*ScriptInfo, error) {
+
+	if chanType.IsTaproot() {
+		return genTaprootHtlcScript(
+			isIncoming, ourCommit, timeout, rHash, keyRing,
+		)
+	}
 
 	var (
 		witnessScript []byte
@@ -798,17 +971,62 @@ func genHtlcScript(chanType channeldb.ChannelType, isIncoming, ourCommit bool,
 		)
 	}
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Now that we have the redeem scripts, create the P2WSH public key
 	// script for the output itself.
 	htlcP2WSH, err := input.WitnessScriptHash(witnessScript)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	return &ScriptInfo{
+		PkScript:      htlcP2WSH,
+		WitnessScript: witnessScript,
+	}, nil
+}
+
+// genTaprootHtlcScript generates the taproot HTLC output of a taproot channel.
+// The sender and receiver roles are assigned exactly like they are for the
+// segwit v0 scripts in genHtlcScript.
+func genTaprootHtlcScript(isIncoming, ourCommit bool, timeout uint32,
+	rHash [32]byte, keyRing *CommitmentKeyRing) (*ScriptInfo, error) {
+
+	var (
+		tree *input.TapscriptTree
+		err  error
+	)
+	switch {
+	case isIncoming && ourCommit:
+		tree, err = input.TaprootReceiverHTLCScript(
+			timeout, keyRing.RemoteHtlcKey, keyRing.LocalHtlcKey,
+			keyRing.RevocationKey, rHash[:],
+		)
+
+	case isIncoming && !ourCommit:
+		tree, err = input.TaprootSenderHTLCScript(
+			keyRing.RemoteHtlcKey, keyRing.LocalHtlcKey,
+			keyRing.RevocationKey, rHash[:],
+		)
+
+	case !isIncoming && ourCommit:
+		tree, err = input.TaprootSenderHTLCScript(
+			keyRing.LocalHtlcKey, keyRing.RemoteHtlcKey,
+			keyRing.RevocationKey, rHash[:],
+		)
+
+	case !isIncoming && !ourCommit:
+		tree, err = input.TaprootReceiverHTLCScript(
+			timeout, keyRing.LocalHtlcKey, keyRing.RemoteHtlcKey,
+			keyRing.RevocationKey, rHash[:],
+		)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return htlcP2WSH, witnessScript, nil
+	return taprootScriptInfo(tree), nil
 }
 
 // addHTLC adds a new HTLC to the passed commitment transaction. One of four
@@ -825,7 +1043,7 @@ func addHTLC(commitTx *wire.MsgTx, ourCommit bool,
 	timeout := paymentDesc.Timeout
 	rHash := paymentDesc.RHash
 
-	p2wsh, witnessScript, err := genHtlcScript(
+	htlcScript, err := genHtlcScript(
 		chanType, isIncoming, ourCommit, timeout, rHash, keyRing,
 	)
 	if err != nil {
@@ -834,16 +1052,18 @@ func addHTLC(commitTx *wire.MsgTx, ourCommit bool,
 
 	// Add the new HTLC outputs to the respective commitment transactions.
 	amountPending := int64(paymentDesc.Amount.ToSatoshis())
-	commitTx.AddTxOut(wire.NewTxOut(amountPending, p2wsh))
+	commitTx.AddTxOut(wire.NewTxOut(amountPending, htlcScript.PkScript))
 
 	// Store the pkScript of this particular PaymentDescriptor so we can
 	// quickly locate it within the commitment transaction later.
 	if ourCommit {
-		paymentDesc.ourPkScript = p2wsh
-		paymentDesc.ourWitnessScript = witnessScript
+		paymentDesc.ourPkScript = htlcScript.PkScript
+		paymentDesc.ourWitnessScript = htlcScript.WitnessScript
+		paymentDesc.ourTapTree = htlcScript.TapTree
 	} else {
-		paymentDesc.theirPkScript = p2wsh
-		paymentDesc.theirWitnessScript = witnessScript
+		paymentDesc.theirPkScript = htlcScript.PkScript
+		paymentDesc.theirWitnessScript = htlcScript.WitnessScript
+		paymentDesc.theirTapTree = htlcScript.TapTree
 	}
 
 	return nil
@@ -152,6 +152,15 @@ func ErrChanTooLarge(chanSize, maxChanSize btcutil.Amount) ReservationError {
 	}
 }
 
+// ErrTaprootChanUnsupported returns an error indicating that a taproot channel
+// was requested with a feature taproot channels don't support yet, such as
+// being announced or dual funded.
+func ErrTaprootChanUnsupported(feature string) ReservationError {
+	return ReservationError{
+		fmt.Errorf("taproot channels can't be %v", feature),
+	}
+}
+
 // ErrHtlcIndexAlreadyFailed is returned when the HTLC index has already been
 // failed, but has not been committed by our commitment state.
 type ErrHtlcIndexAlreadyFailed uint64
@@ -78,6 +78,11 @@ type Utxo struct {
 	Confirmations int64
 	PkScript      []byte
 	wire.OutPoint
+
+	// PrevTx is the transaction that created the output, if known. It's
+	// required to contribute the output to a funding transaction that is
+	// constructed interactively.
+	PrevTx *wire.MsgTx
 }
 
 // TransactionDetail describes a transaction with either inputs which belong to
@@ -1,6 +1,7 @@
 package lnwallet
 
 import (
+	"fmt"
 	"net"
 	"sync"
 
@@ -34,8 +35,25 @@ const (
 	// requires second-level HTLC transactions to be signed using a
 	// zero-fee.
 	CommitmentTypeAnchorsZeroFeeHtlcTx
+
+	// CommitmentTypeSimpleTaproot is a commitment type that extends
+	// CommitmentTypeAnchorsZeroFeeHtlcTx with a taproot funding output
+	// and taproot outputs on the commitment and second-level HTLC
+	// transactions.
+	CommitmentTypeSimpleTaproot
 )
 
+// HasAnchors returns whether the commitment type has anchor outputs.
+func (c CommitmentType) HasAnchors() bool {
+	return c == CommitmentTypeAnchorsZeroFeeHtlcTx ||
+		c == CommitmentTypeSimpleTaproot
+}
+
+// IsTaproot returns whether the commitment type is a taproot one.
+func (c CommitmentType) IsTaproot() bool {
+	return c == CommitmentTypeSimpleTaproot
+}
+
 // String returns the name of the CommitmentType.
 func (c CommitmentType) String() string {
 	switch c {
@@ -45,6 +63,8 @@ func (c CommitmentType) String() string {
 		return "tweakless"
 	case CommitmentTypeAnchorsZeroFeeHtlcTx:
 		return "anchors-zero-fee-second-level"
+	case CommitmentTypeSimpleTaproot:
+		return "simple-taproot"
 	default:
 		return "invalid"
 	}
@@ -163,6 +183,10 @@ type ChannelReservation struct {
 
 	fundingIntent chanfunding.Intent
 
+	// dualFund is true if the channel is being funded by both parties
+	// through the interactive transaction construction protocol.
+	dualFund bool
+
 	// nextRevocationKeyLoc stores the key locator information for this
 	// channel.
 	nextRevocationKeyLoc keychain.KeyLocator
@@ -177,7 +201,8 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 	id uint64, pushMSat lnwire.MilliSatoshi, chainHash *chainhash.Hash,
 	flags lnwire.FundingFlag, commitType CommitmentType,
 	fundingAssembler chanfunding.Assembler,
-	pendingChanID [32]byte, thawHeight uint32) (*ChannelReservation, error) {
+	pendingChanID [32]byte, thawHeight uint32, zeroConf,
+	dualFund bool) (*ChannelReservation, error) {
 
 	var (
 		ourBalance   lnwire.MilliSatoshi
@@ -185,10 +210,26 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 		initiator    bool
 	)
 
+	// Taproot channels can't be announced yet, and their funding output
+	// can't be created by the interactive dual funding protocol.
+	if commitType.IsTaproot() {
+		switch {
+		case flags&lnwire.FFAnnounceChannel != 0:
+			return nil, ErrTaprootChanUnsupported("public")
+
+		case dualFund:
+			return nil, ErrTaprootChanUnsupported("dual funded")
+		}
+	}
+
 	// Based on the channel type, we determine the initial commit weight
 	// and fee.
 	commitWeight := int64(input.CommitWeight)
-	if commitType == CommitmentTypeAnchorsZeroFeeHtlcTx {
+	switch {
+	case commitType.IsTaproot():
+		commitWeight = input.TaprootCommitWeight
+
+	case commitType.HasAnchors():
 		commitWeight = input.AnchorCommitWeight
 	}
 	commitFee := commitFeePerKw.FeeForWeight(commitWeight)
@@ -201,14 +242,15 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 	// The total fee paid by the initiator will be the commitment fee in
 	// addition to the two anchor outputs.
 	feeMSat := lnwire.NewMSatFromSatoshis(commitFee)
-	if commitType == CommitmentTypeAnchorsZeroFeeHtlcTx {
+	if commitType.HasAnchors() {
 		feeMSat += 2 * lnwire.NewMSatFromSatoshis(anchorSize)
 	}
 
+	switch {
 	// If we're the responder to a single-funder reservation, then we have
 	// no initial balance in the channel unless the remote party is pushing
 	// some funds to us within the first commitment state.
-	if localFundingAmt == 0 {
+	case localFundingAmt == 0:
 		ourBalance = pushMSat
 		theirBalance = capacityMSat - feeMSat - pushMSat
 		initiator = false
@@ -221,7 +263,23 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 				int64(2*DefaultDustLimit()),
 			)
 		}
-	} else {
+
+	// If we're the responder to a dual funded reservation and contribute
+	// funds of our own, our initial balance is our contribution. The
+	// initiator still pays all the fees of the commitment transaction.
+	case dualFund && capacity != localFundingAmt:
+		ourBalance = localFundingMSat
+		theirBalance = capacityMSat - localFundingMSat - feeMSat
+		initiator = false
+
+		if int64(theirBalance) < 0 {
+			return nil, ErrFunderBalanceDust(
+				int64(commitFee), int64(theirBalance.ToSatoshis()),
+				int64(2*DefaultDustLimit()),
+			)
+		}
+
+	default:
 		// TODO(roasbeef): need to rework fee structure in general and
 		// also when we "unlock" dual funder within the daemon
 
@@ -281,12 +339,14 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 
 	// If either of the balances are zero at this point, or we have a
 	// non-zero push amt (there's no pushing for dual funder), then this is
-	// a single-funder channel.
-	if ourBalance == 0 || theirBalance == 0 || pushMSat != 0 {
+	// a single-funder channel. A channel dual funded through the
+	// interactive protocol has the same commitment format, as its
+	// initiator pays the fees like in a single-funder channel.
+	if ourBalance == 0 || theirBalance == 0 || pushMSat != 0 || dualFund {
 		// Both the tweakless type and the anchor type is tweakless,
 		// hence set the bit.
 		if commitType == CommitmentTypeTweakless ||
-			commitType == CommitmentTypeAnchorsZeroFeeHtlcTx {
+			commitType.HasAnchors() {
 			chanType |= channeldb.SingleFunderTweaklessBit
 		} else {
 			chanType |= channeldb.SingleFunderBit
@@ -322,17 +382,29 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 
 	// We are adding anchor outputs to our commitment. We only support this
 	// in combination with zero-fee second-levels HTLCs.
-	if commitType == CommitmentTypeAnchorsZeroFeeHtlcTx {
+	if commitType.HasAnchors() {
 		chanType |= channeldb.AnchorOutputsBit
 		chanType |= channeldb.ZeroHtlcTxFeeBit
 	}
 
+	// Taproot channels are built on top of the anchor commitment type.
+	if commitType.IsTaproot() {
+		chanType |= channeldb.SimpleTaprootBit
+	}
+
 	// If the channel is meant to be frozen, then we'll set the frozen bit
 	// now so once the channel is open, it can be interpreted properly.
 	if thawHeight != 0 {
 		chanType |= channeldb.FrozenBit
 	}
 
+	// A zero-conf channel can be used before its funding transaction
+	// confirms, so it needs an alias to be identified by until then.
+	if zeroConf {
+		chanType |= channeldb.ZeroConfBit
+		chanType |= channeldb.ScidAliasChanBit
+	}
+
 	return &ChannelReservation{
 		ourContribution: &ChannelContribution{
 			FundingAmount: ourBalance.ToSatoshis(),
@@ -369,6 +441,7 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 		reservationID: id,
 		wallet:        wallet,
 		chanFunder:    fundingAssembler,
+		dualFund:      dualFund,
 	}, nil
 }
 
@@ -384,6 +457,31 @@ func (r *ChannelReservation) SetNumConfsRequired(numConfs uint16) {
 	r.partialState.NumConfsRequired = numConfs
 }
 
+// IsZeroConf returns whether the reservation is for a zero-conf channel.
+func (r *ChannelReservation) IsZeroConf() bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.partialState.ChanType.IsZeroConf()
+}
+
+// IsTaproot returns whether the reservation is for a simple taproot channel.
+func (r *ChannelReservation) IsTaproot() bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.partialState.ChanType.IsTaproot()
+}
+
+// AddAlias stores the alias the channel will use as its ShortChannelID until
+// and after the funding transaction confirms.
+func (r *ChannelReservation) AddAlias(scid lnwire.ShortChannelID) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.ShortChannelID = scid
+}
+
 // CommitConstraints takes the constraints that the remote party specifies for
 // the type of commitments that we can generate for them. These constraints
 // include several parameters that serve as flow control restricting the amount
@@ -491,6 +589,52 @@ func (r *ChannelReservation) ProcessContribution(theirContribution *ChannelContr
 	return <-errChan
 }
 
+// AddRemoteFunding records the amount the remote party contributes to a dual
+// funded channel we initiated. That amount is only known once the remote
+// party accepted the channel, so the capacity of the channel and the remote
+// party's initial balance are increased by it here.
+func (r *ChannelReservation) AddRemoteFunding(amt btcutil.Amount) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.dualFund || !r.partialState.IsInitiator {
+		return fmt.Errorf("remote funding can only be added by the " +
+			"initiator of a dual funded channel")
+	}
+
+	theirBalance := lnwire.NewMSatFromSatoshis(amt)
+
+	r.partialState.Capacity += amt
+	r.partialState.LocalCommitment.RemoteBalance = theirBalance
+	r.partialState.RemoteCommitment.RemoteBalance = theirBalance
+	r.theirContribution.FundingAmount = amt
+
+	return nil
+}
+
+// ProcessInteractiveContribution records the counterparty's contribution to a
+// dual funded channel, along with the funding transaction both parties
+// constructed interactively. Once this message has been processed, the
+// wallet's inputs to the funding transaction are signed, and so is the
+// counterparty's version of the commitment transaction. The signatures of the
+// counterparty's inputs are exchanged only after the commitment signatures, so
+// the channel is completed with a call to CompleteReservation without any
+// input scripts.
+func (r *ChannelReservation) ProcessInteractiveContribution(
+	theirContribution *ChannelContribution, fundingTx *wire.MsgTx) error {
+
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &addInteractiveContributionMsg{
+		pendingFundingID: r.reservationID,
+		contribution:     theirContribution,
+		fundingTx:        fundingTx,
+		err:              errChan,
+	}
+
+	return <-errChan
+}
+
 // IsPsbt returns true if there is a PSBT funding intent mapped to this
 // reservation.
 func (r *ChannelReservation) IsPsbt() bool {
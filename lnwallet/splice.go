@@ -0,0 +1,551 @@
+package lnwallet
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrSpliceNotFound is returned when a splice transaction isn't among
+	// the pending splices of a channel.
+	ErrSpliceNotFound = fmt.Errorf("splice transaction not found")
+
+	// ErrDuplicateSplice is returned when a splice transaction is added
+	// to a channel twice.
+	ErrDuplicateSplice = fmt.Errorf("splice transaction already pending")
+
+	// ErrSpliceNotSpendingFunding is returned when a splice transaction
+	// doesn't spend the current funding output of the channel.
+	ErrSpliceNotSpendingFunding = fmt.Errorf("splice transaction " +
+		"doesn't spend the channel's funding output")
+
+	// ErrSpliceFundingOutputNotFound is returned when the new 2-of-2
+	// funding output can't be found within a splice transaction.
+	ErrSpliceFundingOutputNotFound = fmt.Errorf("splice funding output " +
+		"not found")
+
+	// ErrSpliceNotSigned is returned when a splice transaction is added
+	// before the commitments spending its funding output were signed by
+	// both parties.
+	ErrSpliceNotSigned = fmt.Errorf("splice commitments not signed")
+
+	// ErrChannelNotQuiescent is returned when a splice is attempted while
+	// the channel has HTLCs or updates that aren't locked in yet.
+	ErrChannelNotQuiescent = fmt.Errorf("channel has pending htlcs or " +
+		"updates")
+
+	// ErrSpliceTaproot is returned when a splice is attempted on a taproot
+	// channel, as splice transactions only create segwit v0 funding
+	// outputs.
+	ErrSpliceTaproot = fmt.Errorf("taproot channels can't be spliced")
+)
+
+// SpliceCandidate is a pending splice transaction of a channel. A splice
+// transaction spends the current funding output of the channel and creates a
+// new funding output with a different capacity. Until one of the candidates
+// confirms, the channel has to be valid for the current funding output as
+// well as all of the pending splices, as any of them may confirm.
+type SpliceCandidate struct {
+	// SpliceCandidate is the persisted part of the candidate: the splice
+	// transaction, the new funding outpoint and capacity as well as the
+	// commitments spending the new funding output.
+	channeldb.SpliceCandidate
+
+	// FundingPkScript is the pkScript of the new funding output.
+	FundingPkScript []byte
+
+	// FundingWitnessScript is the witness script of the new funding
+	// output.
+	FundingWitnessScript []byte
+
+	// LocalFundingKey is our key within the new 2-of-2 funding output.
+	LocalFundingKey *btcec.PublicKey
+
+	// RemoteFundingKey is the remote party's key within the new 2-of-2
+	// funding output.
+	RemoteFundingKey *btcec.PublicKey
+}
+
+// NewSpliceCandidate locates the 2-of-2 funding output of the given funding
+// keys within the splice transaction and returns the resulting splice
+// candidate.
+func NewSpliceCandidate(spliceTx *wire.MsgTx, localKey,
+	remoteKey *btcec.PublicKey) (*SpliceCandidate, error) {
+
+	witnessScript, err := input.GenMultiSigScript(
+		localKey.SerializeCompressed(), remoteKey.SerializeCompressed(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := input.WitnessScriptHash(witnessScript)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, txOut := range spliceTx.TxOut {
+		if !bytes.Equal(txOut.PkScript, pkScript) {
+			continue
+		}
+
+		return &SpliceCandidate{
+			SpliceCandidate: channeldb.SpliceCandidate{
+				SpliceTx: spliceTx,
+				FundingOutpoint: wire.OutPoint{
+					Hash:  spliceTx.TxHash(),
+					Index: uint32(i),
+				},
+				Capacity: btcutil.Amount(txOut.Value),
+			},
+			FundingPkScript:      pkScript,
+			FundingWitnessScript: witnessScript,
+			LocalFundingKey:      localKey,
+			RemoteFundingKey:     remoteKey,
+		}, nil
+	}
+
+	return nil, ErrSpliceFundingOutputNotFound
+}
+
+// FetchSpliceCandidates returns the persisted splice candidates of the passed
+// channel. The funding keys of a channel don't change when it is spliced.
+func FetchSpliceCandidates(
+	chanState *channeldb.OpenChannel) ([]*SpliceCandidate, error) {
+
+	dbCandidates, err := chanState.SpliceCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*SpliceCandidate
+	for _, dbCandidate := range dbCandidates {
+		candidate, err := NewSpliceCandidate(
+			dbCandidate.SpliceTx,
+			chanState.LocalChanCfg.MultiSigKey.PubKey,
+			chanState.RemoteChanCfg.MultiSigKey.PubKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+		candidate.SpliceCandidate = *dbCandidate
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// IsQuiescent returns true if the channel has no HTLCs and no updates that
+// aren't yet locked into both commitments. Only a quiescent channel can be
+// spliced, as the commitments spending the new funding output are created for
+// the current state.
+func (lc *LightningChannel) IsQuiescent() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.isQuiescent()
+}
+
+// isQuiescent is the internal version of IsQuiescent. This function expects
+// to be executed with a lock held.
+func (lc *LightningChannel) isQuiescent() bool {
+	localCommit := lc.channelState.LocalCommitment
+	remoteCommit := lc.channelState.RemoteCommitment
+
+	return len(localCommit.Htlcs) == 0 && len(remoteCommit.Htlcs) == 0 &&
+		lc.localUpdateLog.Len() == 0 && lc.remoteUpdateLog.Len() == 0 &&
+		lc.localCommitChain.tip() == lc.localCommitChain.tail() &&
+		lc.remoteCommitChain.tip() == lc.remoteCommitChain.tail() &&
+		!lc.oweCommitment(true) && !lc.oweCommitment(false)
+}
+
+// createSpliceCommitment creates the commitment transaction of the current
+// state spending the funding output of the splice candidate. The balances of
+// both parties are shifted by their contribution to the splice, while the
+// commitment fee remains the same.
+func (lc *LightningChannel) createSpliceCommitment(splice *SpliceCandidate,
+	isOurs bool, localContribution,
+	remoteContribution btcutil.Amount) (*channeldb.ChannelCommitment,
+	error) {
+
+	chanState := lc.channelState
+	if chanState.ChanType.IsTaproot() {
+		return nil, ErrSpliceTaproot
+	}
+
+	commit := chanState.RemoteCommitment
+	commitPoint := chanState.RemoteCurrentRevocation
+	if isOurs {
+		commit = chanState.LocalCommitment
+
+		commitSecret, err := chanState.RevocationProducer.AtIndex(
+			commit.CommitHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+		commitPoint = input.ComputeCommitmentPoint(commitSecret[:])
+	}
+
+	ourBalance := commit.LocalBalance.ToSatoshis() + localContribution
+	theirBalance := commit.RemoteBalance.ToSatoshis() + remoteContribution
+	if ourBalance < 0 || theirBalance < 0 {
+		return nil, fmt.Errorf("splice leaves negative balance: "+
+			"local=%v, remote=%v", ourBalance, theirBalance)
+	}
+
+	keyRing := DeriveCommitmentKeys(
+		commitPoint, isOurs, chanState.ChanType,
+		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+	)
+
+	fundingTxIn := *wire.NewTxIn(&splice.FundingOutpoint, nil, nil)
+
+	var (
+		commitTx *wire.MsgTx
+		err      error
+	)
+	if isOurs {
+		commitTx, err = CreateCommitTx(
+			chanState.ChanType, fundingTxIn, keyRing,
+			&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+			ourBalance, theirBalance, 0,
+		)
+	} else {
+		commitTx, err = CreateCommitTx(
+			chanState.ChanType, fundingTxIn, keyRing,
+			&chanState.RemoteChanCfg, &chanState.LocalChanCfg,
+			theirBalance, ourBalance, 0,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = SetStateNumHint(
+		commitTx, commit.CommitHeight,
+		createStateHintObfuscator(chanState),
+	)
+	if err != nil {
+		return nil, err
+	}
+	InPlaceCommitSort(commitTx, make([]uint32, len(commitTx.TxOut)))
+
+	if err := blockchain.CheckTransactionSanity(
+		btcutil.NewTx(commitTx),
+	); err != nil {
+		return nil, err
+	}
+
+	var totalOut btcutil.Amount
+	for _, txOut := range commitTx.TxOut {
+		totalOut += btcutil.Amount(txOut.Value)
+	}
+	if totalOut+commit.CommitFee > splice.Capacity {
+		return nil, fmt.Errorf("splice commitment consumes %v while "+
+			"capacity is %v", totalOut+commit.CommitFee,
+			splice.Capacity)
+	}
+
+	newCommit := commit
+	newCommit.LocalBalance = lnwire.NewMSatFromSatoshis(ourBalance)
+	newCommit.RemoteBalance = lnwire.NewMSatFromSatoshis(theirBalance)
+	newCommit.CommitTx = commitTx
+	newCommit.CommitSig = nil
+
+	return &newCommit, nil
+}
+
+// spliceSignDesc returns the sign descriptor for spending the funding output
+// of the splice candidate.
+func (lc *LightningChannel) spliceSignDesc(
+	splice *SpliceCandidate) *input.SignDescriptor {
+
+	signDesc := *lc.signDesc
+	signDesc.WitnessScript = splice.FundingWitnessScript
+	signDesc.Output = &wire.TxOut{
+		PkScript: splice.FundingPkScript,
+		Value:    int64(splice.Capacity),
+	}
+
+	return &signDesc
+}
+
+// SignSpliceCommitment creates both commitments of the current state spending
+// the funding output of the splice candidate, with the balances shifted by
+// the contributions of both parties, and returns our signature for the remote
+// commitment. The channel must be quiescent.
+func (lc *LightningChannel) SignSpliceCommitment(splice *SpliceCandidate,
+	localContribution, remoteContribution btcutil.Amount) (lnwire.Sig,
+	error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if !lc.isQuiescent() {
+		return lnwire.Sig{}, ErrChannelNotQuiescent
+	}
+
+	localCommit, err := lc.createSpliceCommitment(
+		splice, true, localContribution, remoteContribution,
+	)
+	if err != nil {
+		return lnwire.Sig{}, err
+	}
+	remoteCommit, err := lc.createSpliceCommitment(
+		splice, false, localContribution, remoteContribution,
+	)
+	if err != nil {
+		return lnwire.Sig{}, err
+	}
+
+	signDesc := lc.spliceSignDesc(splice)
+	signDesc.SigHashes = txscript.NewTxSigHashes(remoteCommit.CommitTx)
+	rawSig, err := lc.Signer.SignOutputRaw(remoteCommit.CommitTx, signDesc)
+	if err != nil {
+		return lnwire.Sig{}, err
+	}
+	sig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		return lnwire.Sig{}, err
+	}
+	remoteCommit.CommitSig = sig.ToSignatureBytes()
+
+	splice.LocalCommitment = *localCommit
+	splice.RemoteCommitment = *remoteCommit
+
+	return sig, nil
+}
+
+// ReceiveSpliceCommitSig verifies the remote party's signature for our
+// commitment spending the funding output of the splice candidate, which must
+// have been created by SignSpliceCommitment.
+func (lc *LightningChannel) ReceiveSpliceCommitSig(splice *SpliceCandidate,
+	commitSig lnwire.Sig) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	commitTx := splice.LocalCommitment.CommitTx
+	if commitTx == nil {
+		return ErrSpliceNotSigned
+	}
+
+	sigHash, err := txscript.CalcWitnessSigHash(
+		splice.FundingWitnessScript, txscript.NewTxSigHashes(commitTx),
+		txscript.SigHashAll, commitTx, 0, int64(splice.Capacity),
+	)
+	if err != nil {
+		return err
+	}
+
+	sig, err := commitSig.ToSignature()
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(sigHash, splice.RemoteFundingKey) {
+		return fmt.Errorf("invalid commitment signature for splice %v",
+			splice.FundingOutpoint)
+	}
+
+	splice.LocalCommitment.CommitSig = commitSig.ToSignatureBytes()
+
+	return nil
+}
+
+// SignSpliceInput signs the input of the splice transaction spending the
+// current funding output of the channel.
+func (lc *LightningChannel) SignSpliceInput(splice *SpliceCandidate,
+	inputIndex int) (lnwire.Sig, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.channelState.ChanType.IsTaproot() {
+		return lnwire.Sig{}, ErrSpliceTaproot
+	}
+
+	spliceTx := splice.SpliceTx
+	if inputIndex >= len(spliceTx.TxIn) ||
+		spliceTx.TxIn[inputIndex].PreviousOutPoint != *lc.ChanPoint {
+
+		return lnwire.Sig{}, ErrSpliceNotSpendingFunding
+	}
+
+	signDesc := *lc.signDesc
+	signDesc.InputIndex = inputIndex
+	signDesc.SigHashes = txscript.NewTxSigHashes(spliceTx)
+	rawSig, err := lc.Signer.SignOutputRaw(spliceTx, &signDesc)
+	if err != nil {
+		return lnwire.Sig{}, err
+	}
+
+	return lnwire.NewSigFromSignature(rawSig)
+}
+
+// CompleteSpliceInput adds the witness spending the current funding output
+// to the splice transaction using both signatures and validates it.
+func (lc *LightningChannel) CompleteSpliceInput(splice *SpliceCandidate,
+	inputIndex int, localSig, remoteSig lnwire.Sig) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	spliceTx := splice.SpliceTx
+	if inputIndex >= len(spliceTx.TxIn) ||
+		spliceTx.TxIn[inputIndex].PreviousOutPoint != *lc.ChanPoint {
+
+		return ErrSpliceNotSpendingFunding
+	}
+
+	ourSig, err := localSig.ToSignature()
+	if err != nil {
+		return err
+	}
+	theirSig, err := remoteSig.ToSignature()
+	if err != nil {
+		return err
+	}
+
+	ourKey := lc.channelState.LocalChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+	theirKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+	spliceTx.TxIn[inputIndex].Witness = input.SpendMultiSig(
+		lc.signDesc.WitnessScript, ourKey, ourSig, theirKey, theirSig,
+	)
+
+	// Validate the witness to ensure the remote party supplied a valid
+	// signature.
+	prevOut := lc.signDesc.Output
+	vm, err := txscript.NewEngine(
+		prevOut.PkScript, spliceTx, inputIndex,
+		txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(spliceTx), prevOut.Value,
+	)
+	if err != nil {
+		return err
+	}
+
+	return vm.Execute()
+}
+
+// AddPendingSplice adds a splice transaction to the set of pending splices of
+// the channel and persists it. The splice transaction must spend the current
+// funding output and the commitments spending its funding output must be
+// signed by both parties. Multiple pending splices may exist at once, e.g.
+// when a splice transaction is fee bumped, all of them spend the same funding
+// output and at most one of them will confirm.
+func (lc *LightningChannel) AddPendingSplice(splice *SpliceCandidate) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	spendsFunding := false
+	for _, txIn := range splice.SpliceTx.TxIn {
+		if txIn.PreviousOutPoint == *lc.ChanPoint {
+			spendsFunding = true
+			break
+		}
+	}
+	if !spendsFunding {
+		return ErrSpliceNotSpendingFunding
+	}
+
+	txid := splice.FundingOutpoint.Hash
+	for _, pending := range lc.pendingSplices {
+		if pending.FundingOutpoint.Hash == txid {
+			return ErrDuplicateSplice
+		}
+	}
+
+	if len(splice.LocalCommitment.CommitSig) == 0 ||
+		len(splice.RemoteCommitment.CommitSig) == 0 {
+
+		return ErrSpliceNotSigned
+	}
+
+	err := lc.channelState.AddSpliceCandidate(&splice.SpliceCandidate)
+	if err != nil {
+		return err
+	}
+
+	lc.log.Infof("Adding pending splice %v with capacity %v",
+		splice.FundingOutpoint, splice.Capacity)
+
+	lc.pendingSplices = append(lc.pendingSplices, splice)
+
+	return nil
+}
+
+// RemovePendingSplice removes a splice transaction from the set of pending
+// splices of the channel. This must only be done as long as we haven't signed
+// the splice transaction, as it could confirm otherwise.
+func (lc *LightningChannel) RemovePendingSplice(splice *SpliceCandidate) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	err := lc.channelState.RemoveSpliceCandidate(splice.FundingOutpoint)
+	if err != nil {
+		return err
+	}
+
+	lc.log.Infof("Removing pending splice %v", splice.FundingOutpoint)
+
+	pendingSplices := lc.pendingSplices[:0]
+	for _, pending := range lc.pendingSplices {
+		if pending.FundingOutpoint != splice.FundingOutpoint {
+			pendingSplices = append(pendingSplices, pending)
+		}
+	}
+	lc.pendingSplices = pendingSplices
+
+	return nil
+}
+
+// PendingSplices returns the set of pending splices of the channel.
+func (lc *LightningChannel) PendingSplices() []*SpliceCandidate {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	splices := make([]*SpliceCandidate, len(lc.pendingSplices))
+	copy(splices, lc.pendingSplices)
+
+	return splices
+}
+
+// SpliceLocked is to be called once the splice transaction with the given
+// txid is locked by both parties. As all pending splices spend the same
+// funding output, the remaining candidates can never confirm and are
+// discarded. The locked splice candidate is returned so the caller can
+// migrate the channel to its new funding output.
+func (lc *LightningChannel) SpliceLocked(
+	txid chainhash.Hash) (*SpliceCandidate, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	for _, splice := range lc.pendingSplices {
+		if splice.FundingOutpoint.Hash != txid {
+			continue
+		}
+
+		lc.log.Infof("Splice %v locked, discarding %d pending splices",
+			splice.FundingOutpoint, len(lc.pendingSplices)-1)
+
+		lc.pendingSplices = nil
+
+		return splice, nil
+	}
+
+	return nil, ErrSpliceNotFound
+}
@@ -0,0 +1,228 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSpliceTx creates a splice transaction spending the funding output of
+// the channel and creating a new funding output of the given value.
+func newTestSpliceTx(t *testing.T, lc *LightningChannel,
+	value int64) *SpliceCandidate {
+
+	candidate, err := NewSpliceCandidate(
+		&wire.MsgTx{}, lc.LocalFundingKey, lc.RemoteFundingKey,
+	)
+	require.Equal(t, ErrSpliceFundingOutputNotFound, err)
+	require.Nil(t, candidate)
+
+	_, txOut, err := input.GenFundingPkScript(
+		lc.LocalFundingKey.SerializeCompressed(),
+		lc.RemoteFundingKey.SerializeCompressed(), value,
+	)
+	require.NoError(t, err)
+
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(wire.NewTxIn(lc.ChanPoint, nil, nil))
+	spliceTx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	spliceTx.AddTxOut(txOut)
+
+	candidate, err = NewSpliceCandidate(
+		spliceTx, lc.LocalFundingKey, lc.RemoteFundingKey,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, candidate.FundingOutpoint.Index)
+	require.EqualValues(t, value, candidate.Capacity)
+
+	return candidate
+}
+
+// signTestSplice exchanges the commitment signatures of the splice candidate
+// between alice and bob, with alice contributing the difference in capacity.
+// Bob's view of the candidate is returned.
+func signTestSplice(t *testing.T, alice, bob *LightningChannel,
+	aliceSplice *SpliceCandidate) *SpliceCandidate {
+
+	bobSplice, err := NewSpliceCandidate(
+		aliceSplice.SpliceTx, bob.LocalFundingKey, bob.RemoteFundingKey,
+	)
+	require.NoError(t, err)
+
+	contribution := aliceSplice.Capacity - alice.Capacity
+
+	aliceSig, err := alice.SignSpliceCommitment(
+		aliceSplice, contribution, 0,
+	)
+	require.NoError(t, err)
+	bobSig, err := bob.SignSpliceCommitment(bobSplice, 0, contribution)
+	require.NoError(t, err)
+
+	// Alice's view of the commitments must match Bob's.
+	require.Equal(
+		t, aliceSplice.LocalCommitment.CommitTx.TxHash(),
+		bobSplice.RemoteCommitment.CommitTx.TxHash(),
+	)
+	require.Equal(
+		t, aliceSplice.RemoteCommitment.CommitTx.TxHash(),
+		bobSplice.LocalCommitment.CommitTx.TxHash(),
+	)
+
+	// A signature for the wrong commitment is rejected.
+	require.Error(t, alice.ReceiveSpliceCommitSig(aliceSplice, aliceSig))
+
+	require.NoError(t, alice.ReceiveSpliceCommitSig(aliceSplice, bobSig))
+	require.NoError(t, bob.ReceiveSpliceCommitSig(bobSplice, aliceSig))
+
+	return bobSplice
+}
+
+// TestPendingSplices tests that pending splices are tracked and persisted by
+// the channel until one of them is locked.
+func TestPendingSplices(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	// A splice not spending the funding output is rejected.
+	splice1 := newTestSpliceTx(t, aliceChannel, 2_000_000_000)
+	splice1.SpliceTx.TxIn[0].PreviousOutPoint.Index++
+	require.Equal(
+		t, ErrSpliceNotSpendingFunding,
+		aliceChannel.AddPendingSplice(splice1),
+	)
+
+	// A splice whose commitments aren't signed is rejected.
+	splice1 = newTestSpliceTx(t, aliceChannel, 2_000_000_000)
+	require.Equal(
+		t, ErrSpliceNotSigned, aliceChannel.AddPendingSplice(splice1),
+	)
+
+	// Add two conflicting splices, as would be the case if the first
+	// splice transaction was fee bumped.
+	signTestSplice(t, aliceChannel, bobChannel, splice1)
+	splice2 := newTestSpliceTx(t, aliceChannel, 1_999_999_000)
+	signTestSplice(t, aliceChannel, bobChannel, splice2)
+	require.NoError(t, aliceChannel.AddPendingSplice(splice1))
+	require.NoError(t, aliceChannel.AddPendingSplice(splice2))
+	require.Equal(
+		t, ErrDuplicateSplice, aliceChannel.AddPendingSplice(splice2),
+	)
+	require.Equal(
+		t, []*SpliceCandidate{splice1, splice2},
+		aliceChannel.PendingSplices(),
+	)
+
+	// A splice that is aborted before it was signed is removed again.
+	splice3 := newTestSpliceTx(t, aliceChannel, 1_999_998_000)
+	signTestSplice(t, aliceChannel, bobChannel, splice3)
+	require.NoError(t, aliceChannel.AddPendingSplice(splice3))
+	require.NoError(t, aliceChannel.RemovePendingSplice(splice3))
+	require.Equal(
+		t, []*SpliceCandidate{splice1, splice2},
+		aliceChannel.PendingSplices(),
+	)
+
+	// The splices were persisted.
+	persisted, err := FetchSpliceCandidates(aliceChannel.State())
+	require.NoError(t, err)
+	require.Len(t, persisted, 2)
+	for i, splice := range []*SpliceCandidate{splice1, splice2} {
+		require.Equal(
+			t, splice.FundingOutpoint, persisted[i].FundingOutpoint,
+		)
+		require.Equal(
+			t, splice.FundingPkScript, persisted[i].FundingPkScript,
+		)
+		require.Equal(
+			t, splice.LocalCommitment.CommitTx.TxHash(),
+			persisted[i].LocalCommitment.CommitTx.TxHash(),
+		)
+		require.Equal(
+			t, splice.LocalCommitment.CommitSig,
+			persisted[i].LocalCommitment.CommitSig,
+		)
+	}
+
+	// Locking an unknown splice fails.
+	_, err = aliceChannel.SpliceLocked(wire.OutPoint{}.Hash)
+	require.Equal(t, ErrSpliceNotFound, err)
+
+	// Once the second splice is locked, all pending splices are
+	// discarded.
+	locked, err := aliceChannel.SpliceLocked(
+		splice2.FundingOutpoint.Hash,
+	)
+	require.NoError(t, err)
+	require.Equal(t, splice2, locked)
+	require.Empty(t, aliceChannel.PendingSplices())
+}
+
+// TestSpliceCommitments tests that the commitments spending the new funding
+// output carry the balances shifted by the contributions and that the shared
+// funding input can be signed by both parties.
+func TestSpliceCommitments(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	// Alice splices out 1 BTC.
+	const spliceOut = btcutil.SatoshiPerBitcoin
+	aliceSplice := newTestSpliceTx(
+		t, aliceChannel, int64(aliceChannel.Capacity)-spliceOut,
+	)
+	bobSplice := signTestSplice(t, aliceChannel, bobChannel, aliceSplice)
+
+	oldLocal := aliceChannel.State().LocalCommitment
+	newLocal := aliceSplice.LocalCommitment
+	require.Equal(
+		t, oldLocal.LocalBalance.ToSatoshis()-spliceOut,
+		newLocal.LocalBalance.ToSatoshis(),
+	)
+	require.Equal(t, oldLocal.RemoteBalance, newLocal.RemoteBalance)
+	require.Equal(t, oldLocal.CommitHeight, newLocal.CommitHeight)
+	require.Equal(
+		t, aliceSplice.FundingOutpoint,
+		newLocal.CommitTx.TxIn[0].PreviousOutPoint,
+	)
+
+	// The splice transaction can't be completed with an invalid
+	// signature.
+	aliceSig, err := aliceChannel.SignSpliceInput(aliceSplice, 0)
+	require.NoError(t, err)
+	bobSig, err := bobChannel.SignSpliceInput(bobSplice, 0)
+	require.NoError(t, err)
+
+	require.Error(t, aliceChannel.CompleteSpliceInput(
+		aliceSplice, 0, aliceSig, aliceSig,
+	))
+	require.NoError(t, aliceChannel.CompleteSpliceInput(
+		aliceSplice, 0, aliceSig, bobSig,
+	))
+
+	// Only the funding input can be signed.
+	_, err = aliceChannel.SignSpliceInput(aliceSplice, 1)
+	require.Equal(t, ErrSpliceNotSpendingFunding, err)
+
+	// Once an HTLC is added, the channel can no longer be spliced.
+	htlc, _ := createHTLC(0, lnwire.MilliSatoshi(100_000_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	require.False(t, aliceChannel.IsQuiescent())
+
+	_, err = aliceChannel.SignSpliceCommitment(aliceSplice, -spliceOut, 0)
+	require.Equal(t, ErrChannelNotQuiescent, err)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
-	"github.com/lightningnetwork/lnd/input"
 )
 
 const (
@@ -65,12 +64,9 @@ func CreateHtlcSuccessTx(chanType channeldb.ChannelType,
 	// Next, we'll generate the script used as the output for all second
 	// level HTLC which forces a covenant w.r.t what can be done with all
 	// HTLC outputs.
-	witnessScript, err := input.SecondLevelHtlcScript(revocationKey, delayKey,
-		csvDelay)
-	if err != nil {
-		return nil, err
-	}
-	pkScript, err := input.WitnessScriptHash(witnessScript)
+	secondLevelScript, err := SecondLevelHtlcScript(
+		chanType, revocationKey, delayKey, csvDelay,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +75,7 @@ func CreateHtlcSuccessTx(chanType channeldb.ChannelType,
 	// required fees), paying to the timeout script.
 	successTx.AddTxOut(&wire.TxOut{
 		Value:    int64(htlcAmt),
-		PkScript: pkScript,
+		PkScript: secondLevelScript.PkScript,
 	})
 
 	return successTx, nil
@@ -124,12 +120,9 @@ func CreateHtlcTimeoutTx(chanType channeldb.ChannelType,
 	// Next, we'll generate the script used as the output for all second
 	// level HTLC which forces a covenant w.r.t what can be done with all
 	// HTLC outputs.
-	witnessScript, err := input.SecondLevelHtlcScript(revocationKey, delayKey,
-		csvDelay)
-	if err != nil {
-		return nil, err
-	}
-	pkScript, err := input.WitnessScriptHash(witnessScript)
+	secondLevelScript, err := SecondLevelHtlcScript(
+		chanType, revocationKey, delayKey, csvDelay,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +131,7 @@ func CreateHtlcTimeoutTx(chanType channeldb.ChannelType,
 	// required fees), paying to the regular second level HTLC script.
 	timeoutTx.AddTxOut(&wire.TxOut{
 		Value:    int64(htlcAmt),
-		PkScript: pkScript,
+		PkScript: secondLevelScript.PkScript,
 	})
 
 	return timeoutTx, nil
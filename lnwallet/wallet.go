@@ -151,6 +151,16 @@ type InitFundingReserveMsg struct {
 	// used.
 	ChanFunder chanfunding.Assembler
 
+	// ZeroConf is a boolean that is true if a zero-conf channel was
+	// negotiated. Such channels use an alias as their ShortChannelID.
+	ZeroConf bool
+
+	// DualFund is true if the channel is funded by both parties through
+	// the interactive transaction construction protocol. In that case the
+	// initiator's capacity is only its own contribution until
+	// AddRemoteFunding is called on the reservation.
+	DualFund bool
+
 	// err is a channel in which all errors will be sent across. Will be
 	// nil if this initial set is successful.
 	//
@@ -203,6 +213,25 @@ type continueContributionMsg struct {
 	err chan error
 }
 
+// addInteractiveContributionMsg represents a message executing the second
+// phase of a dual funded channel reservation workflow. This message carries
+// the counterparty's contribution to the channel, and the funding transaction
+// both parties constructed interactively. Once processed, our inputs to the
+// funding transaction are signed, along with the remote party's version of the
+// commitment transaction.
+type addInteractiveContributionMsg struct {
+	pendingFundingID uint64
+
+	contribution *ChannelContribution
+
+	// fundingTx is the unsigned funding transaction, with its inputs and
+	// outputs in the order both parties agreed upon.
+	fundingTx *wire.MsgTx
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
 // addSingleContributionMsg represents a message executing the second phase of
 // a single funder channel reservation workflow. This messages carries the
 // counterparty's "contribution" to the payment channel. As this message is
@@ -486,6 +515,8 @@ out:
 				l.handleSingleContribution(msg)
 			case *addContributionMsg:
 				l.handleContributionMsg(msg)
+			case *addInteractiveContributionMsg:
+				l.handleInteractiveContribution(msg)
 			case *continueContributionMsg:
 				l.handleChanPointReady(msg)
 			case *addSingleFunderSigsMsg:
@@ -754,6 +785,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 					WitnessPubKey, true, DefaultAccountName,
 				)
 			},
+			Taproot: req.CommitType.IsTaproot(),
 		}
 		fundingIntent, err = req.ChanFunder.ProvisionChannel(
 			fundingReq,
@@ -824,7 +856,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 	// available, as in bootstrapping phases. We only count public
 	// channels.
 	isPublic := req.Flags&lnwire.FFAnnounceChannel != 0
-	if req.CommitType == CommitmentTypeAnchorsZeroFeeHtlcTx &&
+	if req.CommitType.HasAnchors() &&
 		fundingIntent.LocalFundingAmt() > 0 && isPublic {
 		numAnchors++
 	}
@@ -857,7 +889,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 		capacity, localFundingAmt, req.CommitFeePerKw, l, id,
 		req.PushMSat, l.Cfg.NetParams.GenesisHash, req.Flags,
 		req.CommitType, req.ChanFunder, req.PendingChanID,
-		thawHeight,
+		thawHeight, req.ZeroConf, req.DualFund,
 	)
 	if err != nil {
 		fundingIntent.Cancel()
@@ -1375,6 +1407,88 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	})
 }
 
+// handleInteractiveContribution processes the second workflow step of a dual
+// funded channel reservation. The funding transaction has been constructed
+// with the remote party already, so we only need to sign our inputs to it
+// before both commitment transactions can be created.
+func (l *LightningWallet) handleInteractiveContribution(
+	req *addInteractiveContributionMsg) {
+
+	l.limboMtx.Lock()
+	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
+	l.limboMtx.Unlock()
+	if !ok {
+		req.err <- fmt.Errorf("attempted to update non-existent " +
+			"funding state")
+		return
+	}
+
+	pendingReservation.Lock()
+	defer pendingReservation.Unlock()
+
+	if !pendingReservation.dualFund {
+		req.err <- fmt.Errorf("reservation isn't dual funded")
+		return
+	}
+
+	intent := pendingReservation.fundingIntent
+	fundingIntent, ok := intent.(*chanfunding.FullIntent)
+	if !ok {
+		req.err <- fmt.Errorf("dual funding requires a wallet "+
+			"funding intent, got %T", intent)
+		return
+	}
+
+	pendingReservation.theirContribution = req.contribution
+	theirContribution := req.contribution
+
+	fundingIntent.BindKeys(
+		&pendingReservation.ourContribution.MultiSigKey,
+		theirContribution.MultiSigKey.PubKey,
+	)
+
+	// The funding output must pay the full capacity of the channel, which
+	// is made up of both our contribution and theirs.
+	fundingTx := req.fundingTx.Copy()
+	remoteAmt := pendingReservation.partialState.Capacity -
+		fundingIntent.LocalFundingAmt()
+	err := fundingIntent.SignInteractiveFundingTx(fundingTx, remoteAmt)
+	if err != nil {
+		req.err <- fmt.Errorf("unable to sign funding tx: %v", err)
+		return
+	}
+	chanPoint, err := fundingIntent.ChanPoint()
+	if err != nil {
+		req.err <- fmt.Errorf("unable to obtain chan point: %v", err)
+		return
+	}
+
+	pendingReservation.fundingTx = fundingTx
+	pendingReservation.partialState.FundingOutpoint = *chanPoint
+	pendingReservation.ourFundingInputScripts = nil
+	for _, txIn := range fundingTx.TxIn {
+		if len(txIn.Witness) == 0 && len(txIn.SignatureScript) == 0 {
+			continue
+		}
+
+		pendingReservation.ourFundingInputScripts = append(
+			pendingReservation.ourFundingInputScripts,
+			&input.Script{
+				Witness:   txIn.Witness,
+				SigScript: txIn.SignatureScript,
+			},
+		)
+	}
+
+	walletLog.Debugf("Interactive funding tx for ChannelPoint(%v): %v",
+		chanPoint, spew.Sdump(fundingTx))
+
+	l.handleChanPointReady(&continueContributionMsg{
+		pendingFundingID: req.pendingFundingID,
+		err:              req.err,
+	})
+}
+
 // handleChanPointReady continues the funding process once the channel point
 // is known and the funding transaction can be completed.
 func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
@@ -1468,9 +1582,17 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 	// both commitment transactions.
 	var stateObfuscator [StateHintSize]byte
 	if chanState.ChanType.IsSingleFunder() {
+		// The obfuscator is derived from the initiator's payment
+		// basepoint first. We're usually the initiator here, but not
+		// when responding to a dual funded channel.
+		initiatorPoint := ourContribution.PaymentBasePoint.PubKey
+		responderPoint := theirContribution.PaymentBasePoint.PubKey
+		if !chanState.IsInitiator {
+			initiatorPoint, responderPoint = responderPoint,
+				initiatorPoint
+		}
 		stateObfuscator = DeriveStateHintObfuscator(
-			ourContribution.PaymentBasePoint.PubKey,
-			theirContribution.PaymentBasePoint.PubKey,
+			initiatorPoint, responderPoint,
 		)
 	} else {
 		ourSer := ourContribution.PaymentBasePoint.PubKey.SerializeCompressed()
@@ -1513,8 +1635,10 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 	// Next, we'll obtain the funding witness script, and the funding
 	// output itself so we can generate a valid signature for the remote
 	// party.
-	fundingIntent := pendingReservation.fundingIntent
-	fundingWitnessScript, fundingOutput, err := fundingIntent.FundingOutput()
+	signDesc, err := fundingSignDesc(
+		chanState.ChanType, ourContribution.MultiSigKey,
+		theirContribution.MultiSigKey.PubKey, chanState.Capacity,
+	)
 	if err != nil {
 		req.err <- fmt.Errorf("unable to obtain funding output")
 		return
@@ -1522,16 +1646,10 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 
 	// Generate a signature for their version of the initial commitment
 	// transaction.
-	ourKey := ourContribution.MultiSigKey
-	signDesc := input.SignDescriptor{
-		WitnessScript: fundingWitnessScript,
-		KeyDesc:       ourKey,
-		Output:        fundingOutput,
-		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(theirCommitTx),
-		InputIndex:    0,
-	}
-	sigTheirCommit, err := l.Cfg.Signer.SignOutputRaw(theirCommitTx, &signDesc)
+	signDesc.SigHashes = txscript.NewTxSigHashes(theirCommitTx)
+	sigTheirCommit, err := l.Cfg.Signer.SignOutputRaw(
+		theirCommitTx, signDesc,
+	)
 	if err != nil {
 		req.err <- err
 		return
@@ -1689,10 +1807,9 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	// Re-generate both the witnessScript and p2sh output. We sign the
 	// witnessScript script, but include the p2sh output as the subscript
 	// for verification.
-	witnessScript, _, err := input.GenFundingPkScript(
-		ourKey.PubKey.SerializeCompressed(),
-		theirKey.PubKey.SerializeCompressed(),
-		int64(res.partialState.Capacity),
+	signDesc, err := fundingSignDesc(
+		res.partialState.ChanType, ourKey, theirKey.PubKey,
+		res.partialState.Capacity,
 	)
 	if err != nil {
 		msg.err <- err
@@ -1702,12 +1819,7 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 
 	// Next, create the spending scriptSig, and then verify that the script
 	// is complete, allowing us to spend from the funding transaction.
-	channelValue := int64(res.partialState.Capacity)
-	hashCache := txscript.NewTxSigHashes(commitTx)
-	sigHash, err := txscript.CalcWitnessSigHash(
-		witnessScript, hashCache, txscript.SigHashAll, commitTx,
-		0, channelValue,
-	)
+	sigHash, err := fundingSigHash(commitTx, signDesc)
 	if err != nil {
 		msg.err <- err
 		msg.completeChan <- nil
@@ -1848,13 +1960,11 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 	walletLog.Debugf("Remote commit tx for ChannelPoint(%v): %v",
 		req.fundingOutpoint, spew.Sdump(theirCommitTx))
 
-	channelValue := int64(pendingReservation.partialState.Capacity)
-	hashCache := txscript.NewTxSigHashes(ourCommitTx)
 	theirKey := pendingReservation.theirContribution.MultiSigKey
 	ourKey := pendingReservation.ourContribution.MultiSigKey
-	witnessScript, _, err := input.GenFundingPkScript(
-		ourKey.PubKey.SerializeCompressed(),
-		theirKey.PubKey.SerializeCompressed(), channelValue,
+	signDesc, err := fundingSignDesc(
+		chanState.ChanType, ourKey, theirKey.PubKey,
+		pendingReservation.partialState.Capacity,
 	)
 	if err != nil {
 		req.err <- err
@@ -1862,10 +1972,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 		return
 	}
 
-	sigHash, err := txscript.CalcWitnessSigHash(
-		witnessScript, hashCache, txscript.SigHashAll, ourCommitTx, 0,
-		channelValue,
-	)
+	sigHash, err := fundingSigHash(ourCommitTx, signDesc)
 	if err != nil {
 		req.err <- err
 		req.completeChan <- nil
@@ -1886,24 +1993,10 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 	// With their signature for our version of the commitment transactions
 	// verified, we can now generate a signature for their version,
 	// allowing the funding transaction to be safely broadcast.
-	p2wsh, err := input.WitnessScriptHash(witnessScript)
-	if err != nil {
-		req.err <- err
-		req.completeChan <- nil
-		return
-	}
-	signDesc := input.SignDescriptor{
-		WitnessScript: witnessScript,
-		KeyDesc:       ourKey,
-		Output: &wire.TxOut{
-			PkScript: p2wsh,
-			Value:    channelValue,
-		},
-		HashType:   txscript.SigHashAll,
-		SigHashes:  txscript.NewTxSigHashes(theirCommitTx),
-		InputIndex: 0,
-	}
-	sigTheirCommit, err := l.Cfg.Signer.SignOutputRaw(theirCommitTx, &signDesc)
+	signDesc.SigHashes = txscript.NewTxSigHashes(theirCommitTx)
+	sigTheirCommit, err := l.Cfg.Signer.SignOutputRaw(
+		theirCommitTx, signDesc,
+	)
 	if err != nil {
 		req.err <- err
 		req.completeChan <- nil
@@ -2015,22 +2108,10 @@ func (l *LightningWallet) ValidateChannel(channelState *channeldb.OpenChannel,
 		return err
 	}
 
-	// We'll also need the multi-sig witness script itself so the
+	// We'll also need the funding output script itself so the
 	// chanvalidate package can check it for correctness against the
 	// funding transaction, and also commitment validity.
-	localKey := channelState.LocalChanCfg.MultiSigKey.PubKey
-	remoteKey := channelState.RemoteChanCfg.MultiSigKey.PubKey
-	witnessScript, err := input.GenMultiSigScript(
-		localKey.SerializeCompressed(),
-		remoteKey.SerializeCompressed(),
-	)
-	if err != nil {
-		return err
-	}
-	pkScript, err := input.WitnessScriptHash(witnessScript)
-	if err != nil {
-		return err
-	}
+	pkScript := channel.signDesc.Output.PkScript
 
 	// Finally, we'll pass in all the necessary context needed to fully
 	// validate that this channel is indeed what we expect, and can be
@@ -94,6 +94,11 @@ type AcceptChannel struct {
 	// and its length followed by the script will be written if it is set.
 	UpfrontShutdownScript DeliveryAddress
 
+	// ChannelType is the explicit channel type the responder accepted.
+	// This field is optional, and must echo the channel type sent in the
+	// OpenChannel message if one was sent.
+	ChannelType *ChannelType
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -101,8 +106,8 @@ type AcceptChannel struct {
 	// NOTE: Since the upfront shutdown script MUST be present (though can
 	// be zero-length) if any TLV data is available, the script will be
 	// extracted and removed from this blob when decoding. ExtraData will
-	// contain all TLV records _except_ the DeliveryAddress record in that
-	// case.
+	// contain all TLV records _except_ the DeliveryAddress and ChannelType
+	// records in that case.
 	ExtraData ExtraOpaqueData
 }
 
@@ -116,10 +121,14 @@ var _ Message = (*AcceptChannel)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (a *AcceptChannel) Encode(w io.Writer, pver uint32) error {
-	// Since the upfront script is encoded as a TLV record, concatenate it
-	// with the ExtraData, and write them as one.
+	// Since the upfront script and channel type are encoded as TLV
+	// records, concatenate them with the ExtraData, and write them as one.
+	extraData, err := packChannelType(a.ChannelType, a.ExtraData)
+	if err != nil {
+		return err
+	}
 	tlvRecords, err := packShutdownScript(
-		a.UpfrontShutdownScript, a.ExtraData,
+		a.UpfrontShutdownScript, extraData,
 	)
 	if err != nil {
 		return err
@@ -179,13 +188,18 @@ func (a *AcceptChannel) Decode(r io.Reader, pver uint32) error {
 		return err
 	}
 
-	a.UpfrontShutdownScript, a.ExtraData, err = parseShutdownScript(
+	a.UpfrontShutdownScript, tlvRecords, err = parseShutdownScript(
 		tlvRecords,
 	)
 	if err != nil {
 		return err
 	}
 
+	a.ChannelType, a.ExtraData, err = parseChannelType(tlvRecords)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
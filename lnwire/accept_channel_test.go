@@ -2,6 +2,7 @@ package lnwire
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec"
@@ -69,3 +70,49 @@ func TestDecodeAcceptChannel(t *testing.T) {
 		})
 	}
 }
+
+// TestDecodeAcceptChannelType tests that the optional channel type survives a
+// round trip and is kept separate from the remaining TLV data.
+func TestDecodeAcceptChannelType(t *testing.T) {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("cannot create privkey: %v", err)
+	}
+	pk := priv.PubKey()
+
+	encoded := &AcceptChannel{
+		PendingChannelID:      [32]byte{},
+		FundingKey:            pk,
+		RevocationPoint:       pk,
+		PaymentPoint:          pk,
+		DelayedPaymentPoint:   pk,
+		HtlcPoint:             pk,
+		FirstCommitmentPoint:  pk,
+		UpfrontShutdownScript: []byte{},
+		ChannelType: NewChannelType(
+			StaticRemoteKeyRequired, AnchorsZeroFeeHtlcTxRequired,
+		),
+		ExtraData: []byte{3, 2, 0xff, 0xff},
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := WriteMessage(buf, encoded, 0); err != nil {
+		t.Fatalf("cannot write message: %v", err)
+	}
+
+	msg, err := ReadMessage(buf, 0)
+	if err != nil {
+		t.Fatalf("cannot read message: %v", err)
+	}
+
+	decoded := msg.(*AcceptChannel)
+	if !reflect.DeepEqual(decoded.ChannelType, encoded.ChannelType) {
+		t.Fatalf("decoded channel type: %v does not equal encoded "+
+			"channel type: %v", decoded.ChannelType,
+			encoded.ChannelType)
+	}
+	if !bytes.Equal(decoded.ExtraData, encoded.ExtraData) {
+		t.Fatalf("decoded extra data: %x does not equal encoded extra "+
+			"data: %x", decoded.ExtraData, encoded.ExtraData)
+	}
+}
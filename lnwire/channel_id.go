@@ -1,10 +1,13 @@
 package lnwire
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"math"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 )
@@ -52,6 +55,20 @@ func NewChanIDFromOutPoint(op *wire.OutPoint) ChannelID {
 	return cid
 }
 
+// NewChanIDFromRevocationBasepoints derives the ChannelID of a channel opened
+// with the v2 channel establishment protocol. The ID is the SHA256 of the
+// lesser of both revocation basepoints followed by the greater one, so both
+// parties can compute it before the funding transaction is known.
+func NewChanIDFromRevocationBasepoints(a, b *btcec.PublicKey) ChannelID {
+	aBytes := a.SerializeCompressed()
+	bBytes := b.SerializeCompressed()
+	if bytes.Compare(aBytes, bBytes) > 0 {
+		aBytes, bBytes = bBytes, aBytes
+	}
+
+	return ChannelID(sha256.Sum256(append(aBytes, bBytes...)))
+}
+
 // xorTxid performs the transformation needed to transform an OutPoint into a
 // ChannelID. To do this, we expect the cid parameter to contain the txid
 // unaltered and the outputIndex to be the output index
@@ -1,6 +1,11 @@
 package lnwire
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/require"
+)
 
 // TestChannelIDOutPointConversion ensures that the IsChanPoint always
 // recognizes its seed OutPoint for all possible values of an output index.
@@ -70,3 +75,21 @@ func TestGenPossibleOutPoints(t *testing.T) {
 		t.Fatalf("possible outpoints did not contain the root outpoint")
 	}
 }
+
+// TestChanIDFromRevocationBasepoints ensures that both parties of a dual funded
+// channel derive the same ChannelID regardless of the order of the basepoints.
+func TestChanIDFromRevocationBasepoints(t *testing.T) {
+	t.Parallel()
+
+	keyA, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	keyB, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	cidAB := NewChanIDFromRevocationBasepoints(keyA.PubKey(), keyB.PubKey())
+	cidBA := NewChanIDFromRevocationBasepoints(keyB.PubKey(), keyA.PubKey())
+	require.Equal(t, cidAB, cidBA)
+
+	cidAA := NewChanIDFromRevocationBasepoints(keyA.PubKey(), keyA.PubKey())
+	require.NotEqual(t, cidAB, cidAA)
+}
@@ -0,0 +1,127 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// ChannelTypeRecordType is the type of the optional TLV record in the
+	// OpenChannel and AcceptChannel messages that carries the explicit
+	// channel type the channel should use.
+	ChannelTypeRecordType tlv.Type = 1
+)
+
+// ChannelType represents a specific channel type as a set of feature bits
+// that comprise it. The feature bits used are the required (even) bits of the
+// corresponding features.
+type ChannelType RawFeatureVector
+
+// NewChannelType creates a new channel type with the passed feature bits set.
+func NewChannelType(bits ...FeatureBit) *ChannelType {
+	return (*ChannelType)(NewRawFeatureVector(bits...))
+}
+
+// featureBitLen returns the length in bytes of the encoded feature bits.
+func (c *ChannelType) featureBitLen() uint64 {
+	fv := (*RawFeatureVector)(c)
+	return uint64(fv.SerializeSize())
+}
+
+// NewRecord returns a TLV record that can be used to encode the channel type
+// within the ExtraData TLV stream of the OpenChannel and AcceptChannel
+// messages.
+func (c *ChannelType) NewRecord() tlv.Record {
+	return tlv.MakeDynamicRecord(
+		ChannelTypeRecordType, c, c.featureBitLen, channelTypeEncoder,
+		channelTypeDecoder,
+	)
+}
+
+// channelTypeEncoder is a custom TLV encoder for the ChannelType record.
+func channelTypeEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*ChannelType); ok {
+		return (*RawFeatureVector)(v).EncodeBase256(w)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "*lnwire.ChannelType")
+}
+
+// channelTypeDecoder is a custom TLV decoder for the ChannelType record.
+func channelTypeDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*ChannelType); ok {
+		fv := NewRawFeatureVector()
+		if err := fv.DecodeBase256(r, int(l)); err != nil {
+			return err
+		}
+		*v = ChannelType(*fv)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "*lnwire.ChannelType", l, l)
+}
+
+// packChannelType prepends the channel type, if set, as a TLV record to the
+// passed opaque data blob.
+func packChannelType(chanType *ChannelType, extraData ExtraOpaqueData) (
+	ExtraOpaqueData, error) {
+
+	if chanType == nil {
+		return extraData, nil
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := tlvRecords.PackRecords(chanType.NewRecord()); err != nil {
+		return nil, err
+	}
+
+	return append(tlvRecords, extraData...), nil
+}
+
+// parseChannelType reads and extracts the channel type from the head of the
+// passed data blob, if present. It returns the channel type and the remainder
+// of the data blob.
+//
+// Since TLV records are sorted by type, the channel type directly follows the
+// upfront shutdown script that has been stripped by parseShutdownScript.
+func parseChannelType(tlvRecords ExtraOpaqueData) (*ChannelType,
+	ExtraOpaqueData, error) {
+
+	if len(tlvRecords) == 0 {
+		return nil, tlvRecords, nil
+	}
+
+	var buf [8]byte
+	r := bytes.NewReader(tlvRecords)
+	recordType, err := tlv.ReadVarInt(r, &buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tlv.Type(recordType) != ChannelTypeRecordType {
+		return nil, tlvRecords, nil
+	}
+
+	length, err := tlv.ReadVarInt(r, &buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	if length > uint64(r.Len()) {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	var chanType ChannelType
+	err = channelTypeDecoder(r, &chanType, &buf, length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Remove the bytes encoding the channel type from the data blob.
+	tlvRecords = tlvRecords[len(tlvRecords)-r.Len():]
+
+	return &chanType, tlvRecords, nil
+}
@@ -0,0 +1,77 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// CustomTypeStart is the start of the custom type range for peer messages as
+// defined in BOLT 01.
+const CustomTypeStart MessageType = 32768
+
+// IsCustomOdd returns true if the given message type is in the custom range
+// and odd. Odd custom messages may be ignored by peers that don't understand
+// them, so these are the only custom messages we allow to be sent.
+func IsCustomOdd(msgType MessageType) bool {
+	return msgType >= CustomTypeStart && msgType%2 == 1
+}
+
+// Custom represents an application-defined wire message.
+type Custom struct {
+	// Type is the message type of the custom message.
+	Type MessageType
+
+	// Data is the opaque payload of the custom message.
+	Data []byte
+}
+
+// A compile time check to ensure Custom implements the lnwire.Message
+// interface.
+var _ Message = (*Custom)(nil)
+
+// NewCustom instantiates a new custom message. The message type must be an
+// odd type in the custom range.
+func NewCustom(msgType MessageType, data []byte) (*Custom, error) {
+	if !IsCustomOdd(msgType) {
+		return nil, fmt.Errorf("msg type %d is not an odd type in the "+
+			"custom range (>= %d)", msgType, CustomTypeStart)
+	}
+
+	return &Custom{
+		Type: msgType,
+		Data: data,
+	}, nil
+}
+
+// Decode deserializes a serialized Custom message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) Decode(r io.Reader, pver uint32) error {
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, r); err != nil {
+		return err
+	}
+
+	c.Data = b.Bytes()
+
+	return nil
+}
+
+// Encode serializes the target Custom message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) Encode(w io.Writer, pver uint32) error {
+	_, err := w.Write(c.Data)
+	return err
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) MsgType() MessageType {
+	return c.Type
+}
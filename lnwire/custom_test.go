@@ -0,0 +1,38 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCustomMessage asserts that only odd types in the custom range can be
+// used to create custom messages and that custom messages survive a round
+// trip over the wire.
+func TestCustomMessage(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCustom(MsgPing, nil)
+	require.Error(t, err)
+
+	_, err = NewCustom(CustomTypeStart, nil)
+	require.Error(t, err)
+
+	msg, err := NewCustom(CustomTypeStart+1, []byte{1, 2, 3})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	_, err = WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	readMsg, err := ReadMessage(&b, 0)
+	require.NoError(t, err)
+	require.Equal(t, msg, readMsg)
+
+	// Types below the custom range are still reported as unknown.
+	b.Reset()
+	b.Write([]byte{0x7f, 0xff})
+	_, err = ReadMessage(&b, 0)
+	require.IsType(t, &UnknownMessage{}, err)
+}
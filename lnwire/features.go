@@ -129,6 +129,24 @@ const (
 	// transactions, which also imply anchor commitments.
 	AnchorsZeroFeeHtlcTxOptional FeatureBit = 23
 
+	// RouteBlindingRequired is a required feature bit that signals that
+	// the node requires support for route blinding, i.e. forwarding HTLCs
+	// along blinded paths using encrypted recipient data.
+	RouteBlindingRequired FeatureBit = 24
+
+	// RouteBlindingOptional is an optional feature bit that signals that
+	// the node supports route blinding.
+	RouteBlindingOptional FeatureBit = 25
+
+	// DualFundRequired is a required feature bit that signals that the
+	// node requires support for the v2 channel establishment protocol,
+	// which allows both peers to contribute funds to a channel.
+	DualFundRequired FeatureBit = 28
+
+	// DualFundOptional is an optional feature bit that signals that the
+	// node supports the v2 channel establishment protocol.
+	DualFundOptional FeatureBit = 29
+
 	// AMPRequired is a required feature bit that signals that the receiver
 	// of a payment supports accepts spontaneous payments, i.e.
 	// sender-generated preimages according to BOLT XX.
@@ -139,6 +157,77 @@ const (
 	// sender-generated preimages according to BOLT XX.
 	AMPOptional FeatureBit = 31
 
+	// OnionMessagesRequired is a required feature bit that signals that
+	// the node forwards onion messages.
+	OnionMessagesRequired FeatureBit = 38
+
+	// OnionMessagesOptional is an optional feature bit that signals that
+	// the node forwards onion messages.
+	OnionMessagesOptional FeatureBit = 39
+
+	// ExplicitChannelTypeRequired is a required bit that denotes that a
+	// connection established with this node is to use explicit channel
+	// commitment types for negotiation instead of the existing implicit
+	// negotiation methods. With this bit, there is no longer a "default"
+	// implicit channel commitment type, allowing a connection to
+	// open/maintain types of several channels over its lifetime.
+	ExplicitChannelTypeRequired FeatureBit = 44
+
+	// ExplicitChannelTypeOptional is an optional bit that denotes that a
+	// connection established with this node is to use explicit channel
+	// commitment types for negotiation instead of the existing implicit
+	// negotiation methods.
+	ExplicitChannelTypeOptional FeatureBit = 45
+
+	// ScidAliasRequired is a required feature bit that signals that the
+	// node requires understanding of ShortChannelID aliases in the TLV
+	// segment of the funding_locked message.
+	ScidAliasRequired FeatureBit = 46
+
+	// ScidAliasOptional is an optional feature bit that signals that the
+	// node understands ShortChannelID aliases.
+	ScidAliasOptional FeatureBit = 47
+
+	// ZeroConfRequired is a required feature bit that signals that the
+	// node requires understanding of the zero-conf channel flow.
+	ZeroConfRequired FeatureBit = 50
+
+	// ZeroConfOptional is an optional feature bit that signals that the
+	// node understands the zero-conf channel flow, i.e. it is able to use
+	// a channel before its funding transaction confirmed.
+	ZeroConfOptional FeatureBit = 51
+
+	// TrampolineRoutingRequired is a required feature bit that signals
+	// that the node requires support for trampoline payments, i.e.
+	// payments that delegate the pathfinding to a trampoline node.
+	TrampolineRoutingRequired FeatureBit = 56
+
+	// TrampolineRoutingOptional is an optional feature bit that signals
+	// that the node relays and receives trampoline payments.
+	TrampolineRoutingOptional FeatureBit = 57
+
+	// SpliceRequired is a required feature bit that signals that the node
+	// requires support for splicing, i.e. replacing the funding output of
+	// an open channel to add or remove funds. This is an experimental bit,
+	// as our splices move the channel to a new channel ID, which isn't
+	// compatible with the splice protocol of the spec.
+	SpliceRequired FeatureBit = 154
+
+	// SpliceOptional is an optional feature bit that signals that the node
+	// supports our experimental splicing protocol.
+	SpliceOptional FeatureBit = 155
+
+	// SimpleTaprootChansRequired is a required feature bit that signals
+	// that the node requires support for simple taproot channels, i.e.
+	// channels with a P2TR funding output and tapscript commitment
+	// outputs. This is an experimental bit, as our commitment signatures
+	// are plain BIP-340 signatures rather than MuSig2 partial signatures.
+	SimpleTaprootChansRequired FeatureBit = 180
+
+	// SimpleTaprootChansOptional is an optional feature bit that signals
+	// that the node supports our experimental simple taproot channels.
+	SimpleTaprootChansOptional FeatureBit = 181
+
 	// maxAllowedSize is a maximum allowed size of feature vector.
 	//
 	// NOTE: Within the protocol, the maximum allowed message size is 65535
@@ -182,8 +271,26 @@ var Features = map[FeatureBit]string{
 	AnchorsZeroFeeHtlcTxOptional:  "anchors-zero-fee-htlc-tx",
 	WumboChannelsRequired:         "wumbo-channels",
 	WumboChannelsOptional:         "wumbo-channels",
+	RouteBlindingRequired:         "route-blinding",
+	RouteBlindingOptional:         "route-blinding",
+	DualFundRequired:              "dual-fund",
+	DualFundOptional:              "dual-fund",
 	AMPRequired:                   "amp",
 	AMPOptional:                   "amp",
+	OnionMessagesRequired:         "onion-messages",
+	OnionMessagesOptional:         "onion-messages",
+	ExplicitChannelTypeRequired:   "explicit-commitment-type",
+	ExplicitChannelTypeOptional:   "explicit-commitment-type",
+	ScidAliasRequired:             "scid-alias",
+	ScidAliasOptional:             "scid-alias",
+	TrampolineRoutingRequired:     "trampoline-routing",
+	TrampolineRoutingOptional:     "trampoline-routing",
+	ZeroConfRequired:              "zero-conf",
+	ZeroConfOptional:              "zero-conf",
+	SpliceRequired:                "splice-experimental",
+	SpliceOptional:                "splice-experimental",
+	SimpleTaprootChansRequired:    "simple-taproot-chans-x",
+	SimpleTaprootChansOptional:    "simple-taproot-chans-x",
 }
 
 // RawFeatureVector represents a set of feature bits as defined in BOLT-09.  A
@@ -254,6 +361,38 @@ func (fv *RawFeatureVector) Unset(feature FeatureBit) {
 	delete(fv.features, feature)
 }
 
+// IsEmpty returns whether the feature vector contains any feature bits.
+func (fv *RawFeatureVector) IsEmpty() bool {
+	return len(fv.features) == 0
+}
+
+// Equals determines whether two features vectors contain exactly the same
+// features.
+func (fv *RawFeatureVector) Equals(other *RawFeatureVector) bool {
+	if len(fv.features) != len(other.features) {
+		return false
+	}
+	for bit := range fv.features {
+		if !other.IsSet(bit) {
+			return false
+		}
+	}
+	return true
+}
+
+// OnlyContains determines whether only the specified feature bits are found.
+func (fv *RawFeatureVector) OnlyContains(bits ...FeatureBit) bool {
+	if len(bits) != len(fv.features) {
+		return false
+	}
+	for _, bit := range bits {
+		if !fv.IsSet(bit) {
+			return false
+		}
+	}
+	return true
+}
+
 // SerializeSize returns the number of bytes needed to represent feature vector
 // in byte format.
 func (fv *RawFeatureVector) SerializeSize() int {
@@ -0,0 +1,74 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// FeeRecordType is the type of the optional TLV record in the extra
+	// opaque data of a ChannelUpdate message that carries the inbound fee
+	// of the channel.
+	FeeRecordType tlv.Type = 55555
+
+	// feeRateParts is the total number of parts used to express fee rates.
+	feeRateParts = 1e6
+)
+
+// Fee is a fee schedule that consists of a base fee and a proportional fee
+// rate. Unlike the outbound fee of a ChannelUpdate, both values are signed,
+// so that the fee can also be a discount.
+type Fee struct {
+	// BaseFee is the base fee in millisatoshis.
+	BaseFee int32
+
+	// FeeRate is the proportional fee rate in parts per million.
+	FeeRate int32
+}
+
+// CalcFee returns the fee for the given amount. The result is negative for
+// discounts.
+func (f Fee) CalcFee(amt MilliSatoshi) int64 {
+	return int64(f.BaseFee) + int64(amt)*int64(f.FeeRate)/feeRateParts
+}
+
+// NewRecord returns a TLV record that can be used to encode the fee within
+// the ExtraOpaqueData TLV stream of a ChannelUpdate message.
+func (f *Fee) NewRecord() tlv.Record {
+	return tlv.MakeStaticRecord(FeeRecordType, f, 8, feeEncoder, feeDecoder)
+}
+
+// feeEncoder is a custom TLV encoder for the Fee record.
+func feeEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*Fee); ok {
+		err := tlv.EUint32T(w, uint32(v.BaseFee), buf)
+		if err != nil {
+			return err
+		}
+
+		return tlv.EUint32T(w, uint32(v.FeeRate), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "*lnwire.Fee")
+}
+
+// feeDecoder is a custom TLV decoder for the Fee record.
+func feeDecoder(r io.Reader, val interface{}, buf *[8]byte, l uint64) error {
+	if v, ok := val.(*Fee); ok && l == 8 {
+		var baseFee, feeRate uint32
+		if err := tlv.DUint32(r, &baseFee, buf, 4); err != nil {
+			return err
+		}
+		if err := tlv.DUint32(r, &feeRate, buf, 4); err != nil {
+			return err
+		}
+
+		v.BaseFee = int32(baseFee)
+		v.FeeRate = int32(feeRate)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "*lnwire.Fee", l, 8)
+}
@@ -0,0 +1,76 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+// TestFeeEncodeDecode tests that we're able to properly encode and decode
+// fees, including negative ones, within TLV streams.
+func TestFeeEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	fee := Fee{
+		BaseFee: -1000,
+		FeeRate: 250,
+	}
+
+	var extraData ExtraOpaqueData
+	err := extraData.PackRecords(fee.NewRecord())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fee2 Fee
+	tlvs, err := extraData.ExtractRecords(fee2.NewRecord())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tlvs[FeeRecordType]; !ok {
+		t.Fatalf("FeeRecordType not found in records")
+	}
+
+	if fee != fee2 {
+		t.Fatalf("fee mismatch: expected %v, got %v", fee, fee2)
+	}
+}
+
+// TestFeeCalcFee tests the fee calculation of positive and negative fees.
+func TestFeeCalcFee(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fee      Fee
+		amt      MilliSatoshi
+		expected int64
+	}{
+		{
+			fee:      Fee{BaseFee: 1000, FeeRate: 1000},
+			amt:      1000000,
+			expected: 2000,
+		},
+		{
+			fee:      Fee{BaseFee: -1000, FeeRate: -1000},
+			amt:      1000000,
+			expected: -2000,
+		},
+		{
+			fee:      Fee{BaseFee: 500, FeeRate: -1000},
+			amt:      1000000,
+			expected: -500,
+		},
+		{
+			fee:      Fee{},
+			amt:      1000000,
+			expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		fee := test.fee.CalcFee(test.amt)
+		if fee != test.expected {
+			t.Fatalf("expected fee %v for %v, got %v",
+				test.expected, test.fee, fee)
+		}
+	}
+}
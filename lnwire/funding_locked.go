@@ -4,6 +4,14 @@ import (
 	"io"
 
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// AliasScidRecordType is the type of the optional TLV record in the
+	// FundingLocked message that carries the alias short channel ID the
+	// sender wants the receiver to use for the channel.
+	AliasScidRecordType tlv.Type = 1
 )
 
 // FundingLocked is the message that both parties to a new channel creation
@@ -20,6 +28,12 @@ type FundingLocked struct {
 	// next commitment transaction for the channel.
 	NextPerCommitmentPoint *btcec.PublicKey
 
+	// AliasScid is an alias short channel ID the sender recognizes for
+	// this channel. The receiver can use it in routing hints instead of
+	// the confirmed short channel ID, which may not be known yet in case
+	// of a zero-conf channel. This field is optional.
+	AliasScid *ShortChannelID
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -46,11 +60,31 @@ var _ Message = (*FundingLocked)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *FundingLocked) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.NextPerCommitmentPoint,
 		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Parse the optional alias from the TLV records, leaving any other
+	// records in the extra data untouched.
+	var aliasScid uint64
+	typeMap, err := c.ExtraData.ExtractRecords(
+		tlv.MakePrimitiveRecord(AliasScidRecordType, &aliasScid),
+	)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[AliasScidRecordType]; ok && val == nil {
+		scid := NewShortChanIDFromInt(aliasScid)
+		c.AliasScid = &scid
+	}
+
+	return nil
 }
 
 // Encode serializes the target FundingLocked message into the passed io.Writer
@@ -59,6 +93,17 @@ func (c *FundingLocked) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *FundingLocked) Encode(w io.Writer, pver uint32) error {
+	// If an alias is set, we'll encode it as the only TLV record.
+	if c.AliasScid != nil {
+		aliasScid := c.AliasScid.ToUint64()
+		err := c.ExtraData.PackRecords(
+			tlv.MakePrimitiveRecord(AliasScidRecordType, &aliasScid),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	return WriteElements(w,
 		c.ChanID,
 		c.NextPerCommitmentPoint,
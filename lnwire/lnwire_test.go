@@ -240,7 +240,7 @@ func TestMaxOutPointIndex(t *testing.T) {
 func TestEmptyMessageUnknownType(t *testing.T) {
 	t.Parallel()
 
-	fakeType := MessageType(math.MaxUint16)
+	fakeType := CustomTypeStart - 1
 	if _, err := makeEmptyMessage(fakeType); err == nil {
 		t.Fatalf("should not be able to make an empty message of an " +
 			"unknown type")
@@ -373,11 +373,18 @@ func TestLightningWireProtocol(t *testing.T) {
 				req.UpfrontShutdownScript = []byte{}
 			}
 
+			// 1/2 chance of an explicit channel type.
+			if r.Intn(2) == 0 {
+				req.ChannelType = NewChannelType(
+					StaticRemoteKeyRequired,
+				)
+			}
+
 			// 1/2 chance how having more TLV data after the
 			// shutdown script.
 			if r.Intn(2) == 0 {
-				// TLV type 1 of length 2.
-				req.ExtraData = []byte{1, 2, 0xff, 0xff}
+				// TLV type 3 of length 2.
+				req.ExtraData = []byte{3, 2, 0xff, 0xff}
 			} else {
 				req.ExtraData = []byte{}
 			}
@@ -442,17 +449,251 @@ func TestLightningWireProtocol(t *testing.T) {
 			} else {
 				req.UpfrontShutdownScript = []byte{}
 			}
+			// 1/2 chance of an explicit channel type.
+			if r.Intn(2) == 0 {
+				req.ChannelType = NewChannelType(
+					StaticRemoteKeyRequired,
+				)
+			}
+
 			// 1/2 chance how having more TLV data after the
 			// shutdown script.
 			if r.Intn(2) == 0 {
-				// TLV type 1 of length 2.
-				req.ExtraData = []byte{1, 2, 0xff, 0xff}
+				// TLV type 3 of length 2.
+				req.ExtraData = []byte{3, 2, 0xff, 0xff}
 			} else {
 				req.ExtraData = []byte{}
 			}
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgOpenChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := OpenChannel2{
+				FundingFeePerKWeight:  uint32(r.Int63()),
+				CommitFeePerKWeight:   uint32(r.Int63()),
+				FundingAmount:         btcutil.Amount(r.Int63()),
+				DustLimit:             btcutil.Amount(r.Int63()),
+				MaxValueInFlight:      MilliSatoshi(r.Int63()),
+				HtlcMinimum:           MilliSatoshi(r.Int31()),
+				CsvDelay:              uint16(r.Int31()),
+				MaxAcceptedHTLCs:      uint16(r.Int31()),
+				LockTime:              uint32(r.Int63()),
+				ChannelFlags:          FundingFlag(uint8(r.Int31())),
+				UpfrontShutdownScript: []byte{},
+				ExtraData:             []byte{},
+			}
+
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to generate chain hash: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v", err)
+				return
+			}
+
+			keys := []**btcec.PublicKey{
+				&req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+			}
+			for _, key := range keys {
+				var err error
+				*key, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+			}
+
+			// 1/2 chance of an explicit channel type.
+			if r.Intn(2) == 0 {
+				req.ChannelType = NewChannelType(
+					StaticRemoteKeyRequired,
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgAcceptChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := AcceptChannel2{
+				FundingAmount:         btcutil.Amount(r.Int63()),
+				DustLimit:             btcutil.Amount(r.Int63()),
+				MaxValueInFlight:      MilliSatoshi(r.Int63()),
+				HtlcMinimum:           MilliSatoshi(r.Int31()),
+				MinAcceptDepth:        uint32(r.Int31()),
+				CsvDelay:              uint16(r.Int31()),
+				MaxAcceptedHTLCs:      uint16(r.Int31()),
+				UpfrontShutdownScript: []byte{},
+				ExtraData:             []byte{},
+			}
+
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v", err)
+				return
+			}
+
+			keys := []**btcec.PublicKey{
+				&req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+			}
+			for _, key := range keys {
+				var err error
+				*key, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+			}
+
+			// 1/2 chance of an explicit channel type.
+			if r.Intn(2) == 0 {
+				req.ChannelType = NewChannelType(
+					StaticRemoteKeyRequired,
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddInput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddInput{
+				SerialID:  uint64(r.Int63()),
+				PrevTx:    make([]byte, r.Intn(1000)),
+				PrevTxOut: uint32(r.Int63()),
+				Sequence:  uint32(r.Int63()),
+				ExtraData: make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.PrevTx); err != nil {
+				t.Fatalf("unable to generate prev tx: %v", err)
+				return
+			}
+
+			// Half the time, mark the input as the shared input of
+			// a splice.
+			if r.Intn(2) == 0 {
+				var txid chainhash.Hash
+				if _, err := r.Read(txid[:]); err != nil {
+					t.Fatalf("unable to generate txid: %v",
+						err)
+					return
+				}
+				req.PrevTx = []byte{}
+				req.SharedInputTxid = &txid
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddOutput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddOutput{
+				SerialID:  uint64(r.Int63()),
+				Amount:    btcutil.Amount(r.Int63()),
+				PkScript:  make([]byte, r.Intn(100)),
+				ExtraData: make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.PkScript); err != nil {
+				t.Fatalf("unable to generate pk script: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxSignatures: func(v []reflect.Value, r *rand.Rand) {
+			req := TxSignatures{
+				ExtraData: make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.TxHash[:]); err != nil {
+				t.Fatalf("unable to generate tx hash: %v", err)
+				return
+			}
+
+			numWitnesses := r.Intn(5)
+			for i := 0; i < numWitnesses; i++ {
+				witness := make(wire.TxWitness, r.Intn(4))
+				for j := range witness {
+					witness[j] = make([]byte, r.Intn(100))
+					if _, err := r.Read(witness[j]); err != nil {
+						t.Fatalf("unable to generate "+
+							"witness: %v", err)
+						return
+					}
+				}
+				req.Witnesses = append(req.Witnesses, witness)
+			}
+
+			// Half the time, include a shared input signature.
+			if r.Intn(2) == 0 {
+				sig, err := NewSigFromSignature(testSig)
+				if err != nil {
+					t.Fatalf("unable to parse sig: %v", err)
+					return
+				}
+				req.SharedInputSig = &sig
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceInit: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceInit{
+				FundingContribution:  btcutil.Amount(r.Int63() - r.Int63()),
+				FundingFeePerKWeight: uint32(r.Int63()),
+				LockTime:             uint32(r.Int63()),
+				ExtraData:            make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceAck: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceAck{
+				FundingContribution: btcutil.Amount(r.Int63() - r.Int63()),
+				ExtraData:           make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgFundingCreated: func(v []reflect.Value, r *rand.Rand) {
 			req := FundingCreated{
 				ExtraData: make([]byte, 0),
@@ -535,6 +776,58 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgUpdateAddHTLC: func(v []reflect.Value, r *rand.Rand) {
+			req := UpdateAddHTLC{
+				ID:        r.Uint64(),
+				Amount:    MilliSatoshi(r.Uint64()),
+				Expiry:    r.Uint32(),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.PaymentHash[:]); err != nil {
+				t.Fatalf("unable to generate hash: %v", err)
+				return
+			}
+			if _, err := r.Read(req.OnionBlob[:]); err != nil {
+				t.Fatalf("unable to generate onion: %v", err)
+				return
+			}
+
+			// Only half of the HTLCs are forwarded along a blinded
+			// route.
+			if r.Intn(2) == 0 {
+				var err error
+				req.BlindingPoint, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgOnionMessage: func(v []reflect.Value, r *rand.Rand) {
+			blindingPoint, err := randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			req := OnionMessage{
+				BlindingPoint: blindingPoint,
+				OnionBlob:     make([]byte, r.Intn(2000)),
+				ExtraData:     make([]byte, 0),
+			}
+			if _, err := r.Read(req.OnionBlob); err != nil {
+				t.Fatalf("unable to generate onion: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgCommitSig: func(v []reflect.Value, r *rand.Rand) {
 			req := NewCommitSig()
 			if _, err := r.Read(req.ChanID[:]); err != nil {
@@ -1043,6 +1336,78 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgOpenChannel2,
+			scenario: func(m OpenChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgAcceptChannel2,
+			scenario: func(m AcceptChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddInput,
+			scenario: func(m TxAddInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddOutput,
+			scenario: func(m TxAddOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveInput,
+			scenario: func(m TxRemoveInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveOutput,
+			scenario: func(m TxRemoveOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxComplete,
+			scenario: func(m TxComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxSignatures,
+			scenario: func(m TxSignatures) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceInit,
+			scenario: func(m SpliceInit) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceAck,
+			scenario: func(m SpliceAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceLocked,
+			scenario: func(m SpliceLocked) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgOnionMessage,
+			scenario: func(m OnionMessage) bool {
+				return mainScenario(&m)
+			},
+		},
 	}
 	for _, test := range tests {
 		var config *quick.Config
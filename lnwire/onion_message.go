@@ -0,0 +1,81 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// OnionMessage is sent to relay a message that is not tied to a payment
+// across the network. The message is wrapped in a Sphinx onion, and each hop
+// is addressed by its blinded node ID so that the hops only learn about the
+// previous and the next node of the route.
+type OnionMessage struct {
+	// BlindingPoint is the ephemeral public key the receiving node needs
+	// to derive its blinded node ID and to decrypt its recipient data.
+	BlindingPoint *btcec.PublicKey
+
+	// OnionBlob is the serialized Sphinx onion packet of the message.
+	OnionBlob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewOnionMessage creates a new onion message.
+func NewOnionMessage(blindingPoint *btcec.PublicKey,
+	onionBlob []byte) *OnionMessage {
+
+	return &OnionMessage{
+		BlindingPoint: blindingPoint,
+		OnionBlob:     onionBlob,
+	}
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Decode deserializes a serialized OnionMessage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElements(r, &o.BlindingPoint); err != nil {
+		return err
+	}
+
+	onionBlob, err := readU16Bytes(r)
+	if err != nil {
+		return err
+	}
+	o.OnionBlob = onionBlob
+
+	return ReadElements(r, &o.ExtraData)
+}
+
+// Encode serializes the target OnionMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w io.Writer, pver uint32) error {
+	if err := WriteElements(w, o.BlindingPoint); err != nil {
+		return err
+	}
+
+	if err := writeU16Bytes(w, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return WriteElements(w, o.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
@@ -129,6 +129,11 @@ type OpenChannel struct {
 	// and its length followed by the script will be written if it is set.
 	UpfrontShutdownScript DeliveryAddress
 
+	// ChannelType is the explicit channel type the initiator wishes to
+	// open. This field is optional. If it isn't set, the channel type is
+	// derived implicitly from the feature bits of both peers.
+	ChannelType *ChannelType
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -136,8 +141,8 @@ type OpenChannel struct {
 	// NOTE: Since the upfront shutdown script MUST be present (though can
 	// be zero-length) if any TLV data is available, the script will be
 	// extracted and removed from this blob when decoding. ExtraData will
-	// contain all TLV records _except_ the DeliveryAddress record in that
-	// case.
+	// contain all TLV records _except_ the DeliveryAddress and ChannelType
+	// records in that case.
 	ExtraData ExtraOpaqueData
 }
 
@@ -151,10 +156,14 @@ var _ Message = (*OpenChannel)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (o *OpenChannel) Encode(w io.Writer, pver uint32) error {
-	// Since the upfront script is encoded as a TLV record, concatenate it
-	// with the ExtraData, and write them as one.
+	// Since the upfront script and channel type are encoded as TLV
+	// records, concatenate them with the ExtraData, and write them as one.
+	extraData, err := packChannelType(o.ChannelType, o.ExtraData)
+	if err != nil {
+		return err
+	}
 	tlvRecords, err := packShutdownScript(
-		o.UpfrontShutdownScript, o.ExtraData,
+		o.UpfrontShutdownScript, extraData,
 	)
 	if err != nil {
 		return err
@@ -222,13 +231,18 @@ func (o *OpenChannel) Decode(r io.Reader, pver uint32) error {
 		return err
 	}
 
-	o.UpfrontShutdownScript, o.ExtraData, err = parseShutdownScript(
+	o.UpfrontShutdownScript, tlvRecords, err = parseShutdownScript(
 		tlvRecords,
 	)
 	if err != nil {
 		return err
 	}
 
+	o.ChannelType, o.ExtraData, err = parseChannelType(tlvRecords)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
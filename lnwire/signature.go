@@ -5,6 +5,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 // Sig is a fixed-sized ECDSA signature. Unlike Bitcoin, we use fixed sized
@@ -64,12 +65,17 @@ func NewSigFromRawSignature(sig []byte) (Sig, error) {
 }
 
 // NewSigFromSignature creates a new signature as used on the wire, from an
-// existing btcec.Signature.
+// existing btcec.Signature. BIP-340 signatures of taproot channels are already
+// 64 bytes long, so they're copied as is.
 func NewSigFromSignature(e input.Signature) (Sig, error) {
 	if e == nil {
 		return Sig{}, fmt.Errorf("cannot decode empty signature")
 	}
 
+	if sig, ok := e.(schnorr.Signature); ok {
+		return Sig(sig), nil
+	}
+
 	// Serialize the signature with all the checks that entails.
 	return NewSigFromRawSignature(e.Serialize())
 }
@@ -87,6 +93,12 @@ func (b *Sig) ToSignature() (*btcec.Signature, error) {
 	return sig, nil
 }
 
+// ToSchnorrSignature converts the fixed-sized signature to the BIP-340
+// signature it holds, as used by taproot channels.
+func (b *Sig) ToSchnorrSignature() (schnorr.Signature, error) {
+	return schnorr.ParseSignature(b[:])
+}
+
 // ToSignatureBytes serializes the target fixed-sized signature into the raw
 // bytes of a DER encoding.
 func (b *Sig) ToSignatureBytes() []byte {
@@ -0,0 +1,67 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+)
+
+// SpliceAck is sent in response to a SpliceInit message to accept the
+// proposed splice. Within it, the sender states the amount it contributes to
+// the splice, which may be zero.
+type SpliceAck struct {
+	// ChanID is the channel to be spliced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount the sender adds to the channel. A
+	// negative amount denotes funds being spliced out of the channel.
+	FundingContribution btcutil.Amount
+
+	// FundingKey is the key the sender will use within the 2-of-2
+	// multi-sig output of the splice transaction.
+	FundingKey *btcec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceAck implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAck)(nil)
+
+// Decode deserializes a serialized SpliceAck message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&s.ChanID,
+		&s.FundingContribution,
+		&s.FundingKey,
+		&s.ExtraData,
+	)
+}
+
+// Encode serializes the target SpliceAck into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Encode(w io.Writer, pver uint32) error {
+	return WriteElements(w,
+		s.ChanID,
+		s.FundingContribution,
+		s.FundingKey,
+		s.ExtraData,
+	)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MsgType() MessageType {
+	return MsgSpliceAck
+}
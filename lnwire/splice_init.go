@@ -0,0 +1,80 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+)
+
+// SpliceInit is sent by the initiator of a splice to propose replacing the
+// funding output of an existing channel. The new funding transaction spends
+// the current funding output and is constructed interactively using the
+// TxAddInput/TxAddOutput/TxComplete messages.
+type SpliceInit struct {
+	// ChanID is the channel to be spliced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount the sender adds to the channel. A
+	// negative amount denotes funds being spliced out of the channel.
+	FundingContribution btcutil.Amount
+
+	// FundingFeePerKWeight is the fee rate the initiator wishes to use for
+	// the splice transaction, expressed in sat per kilo-weight.
+	FundingFeePerKWeight uint32
+
+	// LockTime is the nLockTime the initiator wishes to use for the splice
+	// transaction.
+	LockTime uint32
+
+	// FundingKey is the key the sender will use within the 2-of-2
+	// multi-sig output of the splice transaction.
+	FundingKey *btcec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceInit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceInit)(nil)
+
+// Decode deserializes a serialized SpliceInit message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&s.ChanID,
+		&s.FundingContribution,
+		&s.FundingFeePerKWeight,
+		&s.LockTime,
+		&s.FundingKey,
+		&s.ExtraData,
+	)
+}
+
+// Encode serializes the target SpliceInit into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Encode(w io.Writer, pver uint32) error {
+	return WriteElements(w,
+		s.ChanID,
+		s.FundingContribution,
+		s.FundingFeePerKWeight,
+		s.LockTime,
+		s.FundingKey,
+		s.ExtraData,
+	)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MsgType() MessageType {
+	return MsgSpliceInit
+}
@@ -0,0 +1,51 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// SpliceLocked is sent by either side once a splice transaction reached
+// sufficient depth. Once both sides sent SpliceLocked, the splice transaction
+// becomes the new funding transaction of the channel.
+type SpliceLocked struct {
+	// ChanID is the channel that was spliced.
+	ChanID ChannelID
+
+	// SpliceTxid is the txid of the splice transaction that locked.
+	SpliceTxid chainhash.Hash
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceLocked implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceLocked)(nil)
+
+// Decode deserializes a serialized SpliceLocked message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &s.ChanID, s.SpliceTxid[:], &s.ExtraData)
+}
+
+// Encode serializes the target SpliceLocked into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) Encode(w io.Writer, pver uint32) error {
+	return WriteElements(w, s.ChanID, s.SpliceTxid[:], s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) MsgType() MessageType {
+	return MsgSpliceLocked
+}
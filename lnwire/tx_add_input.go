@@ -0,0 +1,165 @@
+package lnwire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// SharedInputTxidRecordType is the type of the optional TLV record in
+	// the TxAddInput message that marks the input as the shared funding
+	// output of the channel being spliced.
+	SharedInputTxidRecordType tlv.Type = 0
+)
+
+// TxAddInput is sent by either side during the interactive construction of a
+// funding transaction to add an input to the transaction.
+type TxAddInput struct {
+	// ChanID is the channel the funding transaction is being constructed
+	// for.
+	ChanID ChannelID
+
+	// SerialID uniquely identifies the input within the interactive
+	// construction. The initiator MUST use even serial IDs, the
+	// non-initiator odd ones. Inputs are sorted by their serial ID in the
+	// final transaction.
+	SerialID uint64
+
+	// PrevTx is the serialized transaction that contains the output being
+	// spent.
+	PrevTx []byte
+
+	// PrevTxOut is the index of the output being spent within PrevTx.
+	PrevTxOut uint32
+
+	// Sequence is the nSequence to use for this input.
+	Sequence uint32
+
+	// SharedInputTxid is set if the input spends the current funding
+	// output of a channel being spliced. In that case PrevTx is empty, as
+	// both sides already know the funding transaction. This field is
+	// optional.
+	SharedInputTxid *chainhash.Hash
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAddInput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddInput)(nil)
+
+// Decode deserializes a serialized TxAddInput message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElements(r, &t.ChanID, &t.SerialID); err != nil {
+		return err
+	}
+
+	prevTx, err := readU16Bytes(r)
+	if err != nil {
+		return err
+	}
+	t.PrevTx = prevTx
+
+	err = ReadElements(r, &t.PrevTxOut, &t.Sequence, &t.ExtraData)
+	if err != nil {
+		return err
+	}
+
+	// Parse the optional shared input txid from the TLV records, leaving
+	// any other records in the extra data untouched.
+	var sharedTxid [32]byte
+	typeMap, err := t.ExtraData.ExtractRecords(
+		tlv.MakePrimitiveRecord(SharedInputTxidRecordType, &sharedTxid),
+	)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[SharedInputTxidRecordType]; ok && val == nil {
+		txid := chainhash.Hash(sharedTxid)
+		t.SharedInputTxid = &txid
+	}
+
+	return nil
+}
+
+// Encode serializes the target TxAddInput into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) Encode(w io.Writer, pver uint32) error {
+	// If this is the shared input, we'll encode its txid as the only TLV
+	// record.
+	if t.SharedInputTxid != nil {
+		sharedTxid := [32]byte(*t.SharedInputTxid)
+		err := t.ExtraData.PackRecords(
+			tlv.MakePrimitiveRecord(
+				SharedInputTxidRecordType, &sharedTxid,
+			),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := WriteElements(w, t.ChanID, t.SerialID); err != nil {
+		return err
+	}
+
+	if err := writeU16Bytes(w, t.PrevTx); err != nil {
+		return err
+	}
+
+	return WriteElements(w, t.PrevTxOut, t.Sequence, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) MsgType() MessageType {
+	return MsgTxAddInput
+}
+
+// writeU16Bytes writes the passed byte slice prefixed by its length encoded
+// as a uint16.
+func writeU16Bytes(w io.Writer, b []byte) error {
+	if len(b) > math.MaxUint16 {
+		return fmt.Errorf("byte slice of length %d too long", len(b))
+	}
+
+	var l [2]byte
+	binary.BigEndian.PutUint16(l[:], uint16(len(b)))
+	if _, err := w.Write(l[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
+// readU16Bytes reads a byte slice prefixed by its length encoded as a uint16.
+func readU16Bytes(r io.Reader) ([]byte, error) {
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, binary.BigEndian.Uint16(l[:]))
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
@@ -0,0 +1,80 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcutil"
+)
+
+// TxAddOutput is sent by either side during the interactive construction of
+// a funding transaction to add an output to the transaction.
+type TxAddOutput struct {
+	// ChanID is the channel the funding transaction is being constructed
+	// for.
+	ChanID ChannelID
+
+	// SerialID uniquely identifies the output within the interactive
+	// construction. The initiator MUST use even serial IDs, the
+	// non-initiator odd ones. Outputs are sorted by their serial ID in the
+	// final transaction.
+	SerialID uint64
+
+	// Amount is the value of the output.
+	Amount btcutil.Amount
+
+	// PkScript is the public key script of the output.
+	PkScript []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAddOutput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddOutput)(nil)
+
+// Decode deserializes a serialized TxAddOutput message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(r, &t.ChanID, &t.SerialID, &t.Amount)
+	if err != nil {
+		return err
+	}
+
+	pkScript, err := readU16Bytes(r)
+	if err != nil {
+		return err
+	}
+	t.PkScript = pkScript
+
+	return ReadElements(r, &t.ExtraData)
+}
+
+// Encode serializes the target TxAddOutput into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) Encode(w io.Writer, pver uint32) error {
+	err := WriteElements(w, t.ChanID, t.SerialID, t.Amount)
+	if err != nil {
+		return err
+	}
+
+	if err := writeU16Bytes(w, t.PkScript); err != nil {
+		return err
+	}
+
+	return WriteElements(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) MsgType() MessageType {
+	return MsgTxAddOutput
+}
@@ -0,0 +1,47 @@
+package lnwire
+
+import (
+	"io"
+)
+
+// TxComplete is sent by either side during the interactive construction of a
+// funding transaction to signal that it has no further inputs or outputs to
+// add. The construction is finished once both sides sent TxComplete in a row.
+type TxComplete struct {
+	// ChanID is the channel the funding transaction is being constructed
+	// for.
+	ChanID ChannelID
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxComplete implements the lnwire.Message
+// interface.
+var _ Message = (*TxComplete)(nil)
+
+// Decode deserializes a serialized TxComplete message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxComplete) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &t.ChanID, &t.ExtraData)
+}
+
+// Encode serializes the target TxComplete into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxComplete) Encode(w io.Writer, pver uint32) error {
+	return WriteElements(w, t.ChanID, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxComplete) MsgType() MessageType {
+	return MsgTxComplete
+}
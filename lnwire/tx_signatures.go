@@ -0,0 +1,193 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// SharedInputSigRecordType is the type of the optional TLV record in
+	// the TxSignatures message that carries the sender's signature for
+	// the shared funding input of a splice transaction.
+	SharedInputSigRecordType tlv.Type = 0
+)
+
+// TxSignatures is sent by either side once the commitment signatures for a
+// dual funded channel have been exchanged. It carries the witnesses for the
+// inputs the sender contributed to the funding transaction, ordered by their
+// serial ID.
+type TxSignatures struct {
+	// ChanID is the channel the funding transaction was constructed for.
+	ChanID ChannelID
+
+	// TxHash is the txid of the funding transaction.
+	TxHash chainhash.Hash
+
+	// Witnesses is the set of witnesses for the inputs the sender added to
+	// the funding transaction.
+	Witnesses []wire.TxWitness
+
+	// SharedInputSig is the sender's signature for the 2-of-2 funding
+	// output a splice transaction spends. The input is not part of
+	// Witnesses as neither side can produce its witness alone. This field
+	// is optional.
+	SharedInputSig *Sig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxSignatures implements the lnwire.Message
+// interface.
+var _ Message = (*TxSignatures)(nil)
+
+// Decode deserializes a serialized TxSignatures message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) Decode(r io.Reader, pver uint32) error {
+	var numWitnesses uint16
+	err := ReadElements(r, &t.ChanID, t.TxHash[:], &numWitnesses)
+	if err != nil {
+		return err
+	}
+
+	t.Witnesses = nil
+	for i := uint16(0); i < numWitnesses; i++ {
+		witnessBytes, err := readU16Bytes(r)
+		if err != nil {
+			return err
+		}
+
+		witness, err := decodeWitness(witnessBytes)
+		if err != nil {
+			return err
+		}
+		t.Witnesses = append(t.Witnesses, witness)
+	}
+
+	if err := ReadElements(r, &t.ExtraData); err != nil {
+		return err
+	}
+
+	// Parse the optional shared input signature from the TLV records,
+	// leaving any other records in the extra data untouched.
+	var sharedSig [64]byte
+	typeMap, err := t.ExtraData.ExtractRecords(
+		tlv.MakePrimitiveRecord(SharedInputSigRecordType, &sharedSig),
+	)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[SharedInputSigRecordType]; ok && val == nil {
+		sig := Sig(sharedSig)
+		t.SharedInputSig = &sig
+	}
+
+	return nil
+}
+
+// Encode serializes the target TxSignatures into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) Encode(w io.Writer, pver uint32) error {
+	// If a shared input signature is set, we'll encode it as the only TLV
+	// record.
+	if t.SharedInputSig != nil {
+		sharedSig := [64]byte(*t.SharedInputSig)
+		err := t.ExtraData.PackRecords(
+			tlv.MakePrimitiveRecord(
+				SharedInputSigRecordType, &sharedSig,
+			),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := WriteElements(
+		w, t.ChanID, t.TxHash[:], uint16(len(t.Witnesses)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, witness := range t.Witnesses {
+		var b bytes.Buffer
+		if err := encodeWitness(&b, witness); err != nil {
+			return err
+		}
+
+		if err := writeU16Bytes(w, b.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return WriteElements(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) MsgType() MessageType {
+	return MsgTxSignatures
+}
+
+// encodeWitness serializes a witness stack using the bitcoin serialization.
+func encodeWitness(w io.Writer, witness wire.TxWitness) error {
+	err := wire.WriteVarInt(w, 0, uint64(len(witness)))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(w, 0, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeWitness deserializes a witness stack using the bitcoin serialization.
+func decodeWitness(b []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(b)
+
+	numItems, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each witness item takes up at least one byte, so we can reject
+	// impossibly large item counts before allocating.
+	if numItems > uint64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	witness := make(wire.TxWitness, 0, numItems)
+	for i := uint64(0); i < numItems; i++ {
+		item, err := wire.ReadVarBytes(
+			r, 0, uint32(len(b)), "witness item",
+		)
+		if err != nil {
+			return nil, err
+		}
+		witness = append(witness, item)
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes in witness", r.Len())
+	}
+
+	return witness, nil
+}
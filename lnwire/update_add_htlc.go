@@ -2,6 +2,16 @@ package lnwire
 
 import (
 	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// BlindingPointRecordType is the type of the optional TLV record in
+	// the UpdateAddHTLC message that carries the ephemeral blinding point
+	// of an HTLC forwarded along a blinded route.
+	BlindingPointRecordType tlv.Type = 0
 )
 
 // OnionPacketSize is the size of the serialized Sphinx onion packet included
@@ -53,6 +63,11 @@ type UpdateAddHTLC struct {
 	// used in the subsequent UpdateAddHTLC message.
 	OnionBlob [OnionPacketSize]byte
 
+	// BlindingPoint is the ephemeral blinding point the receiver needs to
+	// decrypt the onion and its encrypted recipient data if the HTLC is
+	// forwarded inside of a blinded route. This field is optional.
+	BlindingPoint *btcec.PublicKey
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -73,7 +88,7 @@ var _ Message = (*UpdateAddHTLC)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.ID,
 		&c.Amount,
@@ -82,6 +97,25 @@ func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
 		c.OnionBlob[:],
 		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Parse the optional blinding point from the TLV records, leaving any
+	// other records in the extra data untouched.
+	var blindingPoint *btcec.PublicKey
+	typeMap, err := c.ExtraData.ExtractRecords(
+		tlv.MakePrimitiveRecord(BlindingPointRecordType, &blindingPoint),
+	)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[BlindingPointRecordType]; ok && val == nil {
+		c.BlindingPoint = blindingPoint
+	}
+
+	return nil
 }
 
 // Encode serializes the target UpdateAddHTLC into the passed io.Writer observing
@@ -89,6 +123,18 @@ func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateAddHTLC) Encode(w io.Writer, pver uint32) error {
+	// If a blinding point is set, we'll encode it as the only TLV record.
+	if c.BlindingPoint != nil {
+		err := c.ExtraData.PackRecords(
+			tlv.MakePrimitiveRecord(
+				BlindingPointRecordType, &c.BlindingPoint,
+			),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	return WriteElements(w,
 		c.ChanID,
 		c.ID,
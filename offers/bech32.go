@@ -0,0 +1,82 @@
+package offers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+const (
+	// charset is the bech32 character set.
+	charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// separator separates the human readable part from the data.
+	separator = '1'
+)
+
+// encodeString encodes the data as a bech32 string without checksum, as the
+// messages are signed or meant to be scanned as a whole.
+func encodeString(hrp string, data []byte) (string, error) {
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(hrp)
+	b.WriteByte(separator)
+	for _, c := range converted {
+		b.WriteByte(charset[c])
+	}
+
+	return b.String(), nil
+}
+
+// decodeString decodes a bech32 string without checksum with the expected
+// human readable part. Parts of the string may be joined with a '+' followed
+// by optional whitespace.
+func decodeString(hrp, s string) ([]byte, error) {
+	// Remove any '+' joins along with the whitespace following them.
+	var joined strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '+' {
+			joined.WriteByte(s[i])
+			continue
+		}
+
+		if i == 0 || i == len(s)-1 {
+			return nil, fmt.Errorf("invalid '+' at position %d", i)
+		}
+		for i+1 < len(s) && isSpace(s[i+1]) {
+			i++
+		}
+	}
+	s = joined.String()
+
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return nil, fmt.Errorf("string uses mixed case")
+	}
+	s = strings.ToLower(s)
+
+	prefix := hrp + string(separator)
+	if !strings.HasPrefix(s, prefix) {
+		return nil, fmt.Errorf("expected prefix %v", prefix)
+	}
+
+	data := make([]byte, 0, len(s)-len(prefix))
+	for _, c := range s[len(prefix):] {
+		idx := strings.IndexRune(charset, c)
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		data = append(data, byte(idx))
+	}
+
+	return bech32.ConvertBits(data, 5, 8, false)
+}
+
+// isSpace returns true if the character is whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
@@ -0,0 +1,422 @@
+package offers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/schnorr"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// invoiceHRP is the human readable part of an encoded invoice.
+	invoiceHRP = "lni"
+
+	// invoiceMessage is the message name used in the signature digest of
+	// invoices.
+	invoiceMessage = "invoice"
+
+	invoicePathsType          = 160
+	invoiceBlindedPayType     = 162
+	invoiceCreatedAtType      = 164
+	invoiceRelativeExpiryType = 166
+	invoicePaymentHashType    = 168
+	invoiceAmountType         = 170
+	invoiceFallbacksType      = 172
+	invoiceFeaturesType       = 174
+	invoiceNodeIDType         = 176
+
+	// invoiceTypeEnd is the last type of the invoice records.
+	invoiceTypeEnd = 239
+
+	// DefaultRelativeExpiry is the expiry of invoices that don't specify
+	// one.
+	DefaultRelativeExpiry = 2 * time.Hour
+)
+
+var (
+	// knownInvoiceTypes are the invoice records we understand, including
+	// the invoice request and offer records.
+	knownInvoiceTypes = mergeTypes(knownInvReqTypes, map[uint64]struct{}{
+		invoicePathsType:          {},
+		invoiceBlindedPayType:     {},
+		invoiceCreatedAtType:      {},
+		invoiceRelativeExpiryType: {},
+		invoicePaymentHashType:    {},
+		invoiceAmountType:         {},
+		invoiceFallbacksType:      {},
+		invoiceFeaturesType:       {},
+		invoiceNodeIDType:         {},
+	})
+)
+
+// Invoice is a BOLT 12 invoice that the issuer of an offer returns in
+// response to an invoice request.
+type Invoice struct {
+	// Request is the invoice request the invoice was created for. Its
+	// records are mirrored in the invoice, except for its signature.
+	Request *InvoiceRequest
+
+	// Paths are the blinded payment paths to the issuer. At least one is
+	// required.
+	Paths []*zpay32.BlindedPaymentPath
+
+	// CreatedAt is the creation time of the invoice.
+	CreatedAt time.Time
+
+	// RelativeExpiry is the time after creation at which the invoice
+	// expires. If zero, DefaultRelativeExpiry applies.
+	RelativeExpiry time.Duration
+
+	// PaymentHash is the hash of the payment preimage.
+	PaymentHash lntypes.Hash
+
+	// Amount is the amount to pay.
+	Amount lnwire.MilliSatoshi
+
+	// Features are the features required to pay the invoice.
+	Features *lnwire.RawFeatureVector
+
+	// NodeID is the key that signs the invoice. It matches the node ID of
+	// the offer.
+	NodeID *btcec.PublicKey
+
+	// Signature is the signature of the invoice by the node ID.
+	Signature schnorr.Signature
+
+	// extra contains the unknown odd records of the invoice.
+	extra records
+}
+
+// records returns the TLV records of the invoice, optionally including the
+// signature.
+func (i *Invoice) records(withSig bool) (records, error) {
+	recs, err := i.Request.records(false)
+	if err != nil {
+		return nil, err
+	}
+	for typ, value := range i.extra {
+		recs[typ] = value
+	}
+
+	if len(i.Paths) == 0 {
+		return nil, fmt.Errorf("invoice has no paths")
+	}
+
+	var paths, payInfo bytes.Buffer
+	for _, path := range i.Paths {
+		if path.Hops == nil {
+			return nil, fmt.Errorf("invoice path has no hops")
+		}
+		if err := path.Hops.Encode(&paths); err != nil {
+			return nil, err
+		}
+		if err := encodeBlindedPayInfo(&payInfo, path); err != nil {
+			return nil, err
+		}
+	}
+	recs[invoicePathsType] = paths.Bytes()
+	recs[invoiceBlindedPayType] = payInfo.Bytes()
+
+	recs[invoiceCreatedAtType] = encodeTu64(uint64(i.CreatedAt.Unix()))
+	if i.RelativeExpiry != 0 {
+		recs[invoiceRelativeExpiryType] = encodeTu64(
+			uint64(i.RelativeExpiry / time.Second),
+		)
+	}
+	recs[invoicePaymentHashType] = i.PaymentHash[:]
+	recs[invoiceAmountType] = encodeTu64(uint64(i.Amount))
+	if i.Features != nil && !i.Features.IsEmpty() {
+		features, err := encodeFeatures(i.Features)
+		if err != nil {
+			return nil, err
+		}
+		recs[invoiceFeaturesType] = features
+	}
+	if i.NodeID == nil {
+		return nil, fmt.Errorf("invoice has no node id")
+	}
+	recs[invoiceNodeIDType] = i.NodeID.SerializeCompressed()
+
+	if withSig {
+		recs[signatureType] = i.Signature[:]
+	}
+
+	return recs, nil
+}
+
+// Sign signs the invoice with the passed signer, which must create a schnorr
+// signature with the key of the node ID.
+func (i *Invoice) Sign(
+	signer func(digest [32]byte) (schnorr.Signature, error)) error {
+
+	recs, err := i.records(false)
+	if err != nil {
+		return err
+	}
+
+	digest := signatureDigest(invoiceMessage, "signature", merkleRoot(recs))
+	i.Signature, err = signer(digest)
+
+	return err
+}
+
+// Serialize returns the binary serialization of the signed invoice.
+func (i *Invoice) Serialize() ([]byte, error) {
+	recs, err := i.records(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return recs.serialize(), nil
+}
+
+// Encode returns the bech32 encoding of the signed invoice.
+func (i *Invoice) Encode() (string, error) {
+	data, err := i.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	return encodeString(invoiceHRP, data)
+}
+
+// DecodeInvoice parses a serialized invoice and verifies its signature.
+func DecodeInvoice(data []byte) (*Invoice, error) {
+	recs, err := parseRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recs.checkTypes(
+		knownInvoiceTypes, [2]uint64{0, invoiceTypeEnd},
+		[2]uint64{signatureTypeStart, signatureTypeEnd},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature of the request isn't mirrored, so the request must be
+	// decoded without it.
+	request, err := decodeInvoiceRequestRecords(recs)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Invoice{
+		Request: request,
+		extra:   make(records),
+	}
+
+	var (
+		paths   []byte
+		payInfo []byte
+	)
+	for typ, value := range recs.filter(invReqTypeEnd+1, invoiceTypeEnd) {
+		switch typ {
+		case invoicePathsType:
+			paths = value
+
+		case invoiceBlindedPayType:
+			payInfo = value
+
+		case invoiceCreatedAtType:
+			var createdAt uint64
+			createdAt, err = decodeTu64(value)
+			i.CreatedAt = time.Unix(int64(createdAt), 0)
+
+		case invoiceRelativeExpiryType:
+			var expiry uint64
+			expiry, err = decodeTu64(value)
+			i.RelativeExpiry = time.Duration(expiry) * time.Second
+
+		case invoicePaymentHashType:
+			i.PaymentHash, err = lntypes.MakeHash(value)
+
+		case invoiceAmountType:
+			var amt uint64
+			amt, err = decodeTu64(value)
+			i.Amount = lnwire.MilliSatoshi(amt)
+
+		case invoiceFeaturesType:
+			i.Features, err = decodeFeatures(value)
+
+		case invoiceNodeIDType:
+			i.NodeID, err = decodePubKey(value)
+
+		// We don't support on-chain fallbacks, so we keep them as
+		// opaque data.
+		default:
+			i.extra[typ] = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice record %d: %v",
+				typ, err)
+		}
+	}
+
+	i.Paths, err = decodeInvoicePaths(paths, payInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case i.NodeID == nil:
+		return nil, fmt.Errorf("invoice has no node id")
+
+	case i.CreatedAt.IsZero():
+		return nil, fmt.Errorf("invoice has no creation time")
+
+	case recs[invoicePaymentHashType] == nil:
+		return nil, fmt.Errorf("invoice has no payment hash")
+
+	case recs[invoiceAmountType] == nil:
+		return nil, fmt.Errorf("invoice has no amount")
+	}
+
+	sig, ok := recs[signatureType]
+	if !ok || len(sig) != schnorr.SignatureSize {
+		return nil, fmt.Errorf("invoice has no valid signature")
+	}
+	copy(i.Signature[:], sig)
+
+	digest := signatureDigest(invoiceMessage, "signature", merkleRoot(recs))
+	if err := schnorr.Verify(i.NodeID, digest, i.Signature); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// DecodeInvoiceString parses a bech32 encoded invoice and verifies its
+// signature.
+func DecodeInvoiceString(s string) (*Invoice, error) {
+	data, err := decodeString(invoiceHRP, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeInvoice(data)
+}
+
+// Expiry returns the time after creation at which the invoice expires.
+func (i *Invoice) Expiry() time.Duration {
+	if i.RelativeExpiry == 0 {
+		return DefaultRelativeExpiry
+	}
+
+	return i.RelativeExpiry
+}
+
+// IsExpired returns true if the invoice has expired at the passed time.
+func (i *Invoice) IsExpired(now time.Time) bool {
+	return now.After(i.CreatedAt.Add(i.Expiry()))
+}
+
+// encodeBlindedPayInfo serializes the aggregated policy of a blinded payment
+// path:
+//
+//  fee_base_msat (4) | fee_proportional_millionths (4) |
+//  cltv_expiry_delta (2) | htlc_minimum_msat (8) | htlc_maximum_msat (8) |
+//  flen (2) | features (flen)
+func encodeBlindedPayInfo(w io.Writer, path *zpay32.BlindedPaymentPath) error {
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[:4], path.FeeBaseMsat)
+	if _, err := w.Write(b[:4]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(b[:4], path.FeeRate)
+	if _, err := w.Write(b[:4]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint16(b[:2], path.CltvExpiryDelta)
+	if _, err := w.Write(b[:2]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(b[:], path.HTLCMinMsat)
+	if _, err := w.Write(b[:]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(b[:], path.HTLCMaxMsat)
+	if _, err := w.Write(b[:]); err != nil {
+		return err
+	}
+
+	features := lnwire.NewRawFeatureVector()
+	if path.Features != nil {
+		features = path.Features.RawFeatureVector
+	}
+
+	return features.Encode(w)
+}
+
+// decodeInvoicePaths parses the blinded payment paths of an invoice from the
+// paths and the aggregated policies, which must have the same number of
+// entries.
+func decodeInvoicePaths(paths,
+	payInfo []byte) ([]*zpay32.BlindedPaymentPath, error) {
+
+	hops, err := decodeBlindedPaths(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(hops) == 0 {
+		return nil, fmt.Errorf("invoice has no paths")
+	}
+
+	var (
+		r      = bytes.NewReader(payInfo)
+		result = make([]*zpay32.BlindedPaymentPath, 0, len(hops))
+		b      [8]byte
+	)
+	for _, path := range hops {
+		p := &zpay32.BlindedPaymentPath{
+			Hops: path,
+		}
+
+		if _, err := io.ReadFull(r, b[:4]); err != nil {
+			return nil, err
+		}
+		p.FeeBaseMsat = binary.BigEndian.Uint32(b[:4])
+
+		if _, err := io.ReadFull(r, b[:4]); err != nil {
+			return nil, err
+		}
+		p.FeeRate = binary.BigEndian.Uint32(b[:4])
+
+		if _, err := io.ReadFull(r, b[:2]); err != nil {
+			return nil, err
+		}
+		p.CltvExpiryDelta = binary.BigEndian.Uint16(b[:2])
+
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		p.HTLCMinMsat = binary.BigEndian.Uint64(b[:])
+
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		p.HTLCMaxMsat = binary.BigEndian.Uint64(b[:])
+
+		features := lnwire.NewRawFeatureVector()
+		if err := features.Decode(r); err != nil {
+			return nil, err
+		}
+		p.Features = lnwire.NewFeatureVector(features, lnwire.Features)
+
+		result = append(result, p)
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("blinded pay info doesn't match paths")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package offers
+
+import (
+	"fmt"
+)
+
+const (
+	invErrErroneousFieldType = 1
+	invErrSuggestedValueType = 3
+	invErrErrorType          = 5
+)
+
+// InvoiceError is returned by the issuer of an offer instead of an invoice if
+// it can't handle an invoice request, or by the payer if it rejects an
+// invoice.
+type InvoiceError struct {
+	// ErroneousField is the type of the record that caused the error, if
+	// known.
+	ErroneousField *uint64
+
+	// SuggestedValue is a value for the erroneous field that would be
+	// accepted.
+	SuggestedValue []byte
+
+	// Message is a human readable description of the error.
+	Message string
+}
+
+// Error returns a human readable description of the error.
+//
+// NOTE: Part of the error interface.
+func (e *InvoiceError) Error() string {
+	if e.ErroneousField != nil {
+		return fmt.Sprintf("invoice error in field %d: %v",
+			*e.ErroneousField, e.Message)
+	}
+
+	return fmt.Sprintf("invoice error: %v", e.Message)
+}
+
+// Serialize returns the binary serialization of the error.
+func (e *InvoiceError) Serialize() []byte {
+	recs := make(records)
+	if e.ErroneousField != nil {
+		recs[invErrErroneousFieldType] = encodeTu64(*e.ErroneousField)
+	}
+	if e.SuggestedValue != nil {
+		recs[invErrSuggestedValueType] = e.SuggestedValue
+	}
+	recs[invErrErrorType] = []byte(e.Message)
+
+	return recs.serialize()
+}
+
+// DecodeInvoiceError parses a serialized invoice error.
+func DecodeInvoiceError(data []byte) (*InvoiceError, error) {
+	recs, err := parseRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &InvoiceError{
+		Message: string(recs[invErrErrorType]),
+	}
+	if value, ok := recs[invErrErroneousFieldType]; ok {
+		field, err := decodeTu64(value)
+		if err != nil {
+			return nil, err
+		}
+		e.ErroneousField = &field
+	}
+	if value, ok := recs[invErrSuggestedValueType]; ok {
+		e.SuggestedValue = value
+	}
+
+	return e, nil
+}
@@ -0,0 +1,379 @@
+package offers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/schnorr"
+)
+
+const (
+	// invoiceRequestMessage is the message name used in the signature
+	// digest of invoice requests.
+	invoiceRequestMessage = "invoice_request"
+
+	invReqMetadataType  = 0
+	invReqChainType     = 80
+	invReqAmountType    = 82
+	invReqFeaturesType  = 84
+	invReqQuantityType  = 86
+	invReqPayerIDType   = 88
+	invReqPayerNoteType = 89
+
+	// invReqTypeEnd is the last type of the invoice request records,
+	// which are mirrored in invoices.
+	invReqTypeEnd = 159
+)
+
+var (
+	// knownInvReqTypes are the invoice request records we understand,
+	// including the offer records.
+	knownInvReqTypes = mergeTypes(knownOfferTypes, map[uint64]struct{}{
+		invReqMetadataType: {},
+		invReqChainType:    {},
+		invReqAmountType:   {},
+		invReqFeaturesType: {},
+		invReqQuantityType: {},
+		invReqPayerIDType:  {},
+		signatureType:      {},
+	})
+
+	// ErrAmountTooLow is returned when the amount of an invoice request
+	// doesn't cover the amount of the offer.
+	ErrAmountTooLow = errors.New("amount below offer amount")
+)
+
+// mergeTypes returns the union of the passed type sets.
+func mergeTypes(sets ...map[uint64]struct{}) map[uint64]struct{} {
+	merged := make(map[uint64]struct{})
+	for _, set := range sets {
+		for typ := range set {
+			merged[typ] = struct{}{}
+		}
+	}
+
+	return merged
+}
+
+// InvoiceRequest is a request for an invoice for an offer, which the payer
+// sends to the issuer of the offer.
+type InvoiceRequest struct {
+	// Offer is the offer the invoice is requested for. Its records are
+	// mirrored in the request.
+	Offer *Offer
+
+	// Metadata is random data chosen by the payer.
+	Metadata []byte
+
+	// Chain is the chain the payer wants to pay on. If nil, bitcoin
+	// mainnet is implied.
+	Chain *chainhash.Hash
+
+	// Amount is the amount the payer intends to pay in millisatoshis. If
+	// zero, the amount is derived from the offer.
+	Amount uint64
+
+	// Features are the features the payer supports.
+	Features *lnwire.RawFeatureVector
+
+	// Quantity is the number of items requested. It must be set if the
+	// offer has a maximum quantity.
+	Quantity uint64
+
+	// PayerID is the transient key of the payer that signs the request.
+	PayerID *btcec.PublicKey
+
+	// PayerNote is a note for the issuer.
+	PayerNote string
+
+	// Signature is the signature of the request by the payer ID.
+	Signature schnorr.Signature
+
+	// extra contains the unknown odd records of the request.
+	extra records
+}
+
+// NewInvoiceRequest creates an invoice request for the offer that is signed
+// by the passed payer key.
+func NewInvoiceRequest(offer *Offer, chain chainhash.Hash, amount,
+	quantity uint64, payerNote string,
+	payerKey *btcec.PrivateKey) (*InvoiceRequest, error) {
+
+	metadata := make([]byte, 32)
+	if _, err := rand.Read(metadata); err != nil {
+		return nil, err
+	}
+
+	req := &InvoiceRequest{
+		Offer:     offer,
+		Metadata:  metadata,
+		Amount:    amount,
+		Quantity:  quantity,
+		PayerID:   payerKey.PubKey(),
+		PayerNote: payerNote,
+	}
+
+	// The chain only needs to be set if it isn't the default.
+	if chain != *chaincfg.MainNetParams.GenesisHash {
+		req.Chain = &chain
+	}
+
+	if _, err := req.AmountMsat(); err != nil {
+		return nil, err
+	}
+
+	recs, err := req.records(false)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := signatureDigest(
+		invoiceRequestMessage, "signature", merkleRoot(recs),
+	)
+	req.Signature, err = schnorr.Sign(payerKey, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// records returns the TLV records of the request, optionally including the
+// signature.
+func (r *InvoiceRequest) records(withSig bool) (records, error) {
+	recs, err := r.Offer.records()
+	if err != nil {
+		return nil, err
+	}
+	for typ, value := range r.extra {
+		recs[typ] = value
+	}
+
+	recs[invReqMetadataType] = r.Metadata
+	if r.Chain != nil {
+		recs[invReqChainType] = r.Chain[:]
+	}
+	if r.Amount != 0 {
+		recs[invReqAmountType] = encodeTu64(r.Amount)
+	}
+	if r.Features != nil && !r.Features.IsEmpty() {
+		features, err := encodeFeatures(r.Features)
+		if err != nil {
+			return nil, err
+		}
+		recs[invReqFeaturesType] = features
+	}
+	if r.Quantity != 0 {
+		recs[invReqQuantityType] = encodeTu64(r.Quantity)
+	}
+	if r.PayerID != nil {
+		recs[invReqPayerIDType] = r.PayerID.SerializeCompressed()
+	}
+	if r.PayerNote != "" {
+		recs[invReqPayerNoteType] = []byte(r.PayerNote)
+	}
+
+	if withSig {
+		recs[signatureType] = r.Signature[:]
+	}
+
+	return recs, nil
+}
+
+// Serialize returns the binary serialization of the signed request.
+func (r *InvoiceRequest) Serialize() ([]byte, error) {
+	recs, err := r.records(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return recs.serialize(), nil
+}
+
+// decodeInvoiceRequestRecords parses the invoice request records out of the
+// passed records. The offer records must be present, all other records
+// outside of the invoice request range are ignored.
+func decodeInvoiceRequestRecords(all records) (*InvoiceRequest, error) {
+	offer, err := decodeOfferRecords(all)
+	if err != nil {
+		return nil, err
+	}
+
+	recs := all.filter(0, invReqTypeEnd)
+
+	r := &InvoiceRequest{
+		Offer: offer,
+		extra: make(records),
+	}
+	for typ, value := range recs {
+		if typ >= offerTypeStart && typ <= offerTypeEnd {
+			continue
+		}
+
+		switch typ {
+		case invReqMetadataType:
+			r.Metadata = value
+
+		case invReqChainType:
+			if len(value) != chainhash.HashSize {
+				err = fmt.Errorf("invalid chain length")
+				break
+			}
+			var chain chainhash.Hash
+			copy(chain[:], value)
+			r.Chain = &chain
+
+		case invReqAmountType:
+			r.Amount, err = decodeTu64(value)
+
+		case invReqFeaturesType:
+			r.Features, err = decodeFeatures(value)
+
+		case invReqQuantityType:
+			r.Quantity, err = decodeTu64(value)
+
+		case invReqPayerIDType:
+			r.PayerID, err = decodePubKey(value)
+
+		case invReqPayerNoteType:
+			r.PayerNote = string(value)
+
+		default:
+			r.extra[typ] = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice request "+
+				"record %d: %v", typ, err)
+		}
+	}
+
+	if r.Metadata == nil {
+		return nil, fmt.Errorf("invoice request has no metadata")
+	}
+	if r.PayerID == nil {
+		return nil, fmt.Errorf("invoice request has no payer id")
+	}
+
+	return r, nil
+}
+
+// DecodeInvoiceRequest parses a serialized invoice request and verifies its
+// signature.
+func DecodeInvoiceRequest(data []byte) (*InvoiceRequest, error) {
+	recs, err := parseRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recs.checkTypes(
+		knownInvReqTypes, [2]uint64{0, invReqTypeEnd},
+		[2]uint64{signatureTypeStart, signatureTypeEnd},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := decodeInvoiceRequestRecords(recs)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, ok := recs[signatureType]
+	if !ok || len(sig) != schnorr.SignatureSize {
+		return nil, fmt.Errorf("invoice request has no valid " +
+			"signature")
+	}
+	copy(r.Signature[:], sig)
+
+	digest := signatureDigest(
+		invoiceRequestMessage, "signature", merkleRoot(recs),
+	)
+	if err := schnorr.Verify(r.PayerID, digest, r.Signature); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ChainHash returns the chain the payer wants to pay on.
+func (r *InvoiceRequest) ChainHash() chainhash.Hash {
+	if r.Chain != nil {
+		return *r.Chain
+	}
+
+	return *chaincfg.MainNetParams.GenesisHash
+}
+
+// AmountMsat returns the amount that needs to be paid for the request. It
+// checks that the quantity and amount are valid for the offer.
+func (r *InvoiceRequest) AmountMsat() (lnwire.MilliSatoshi, error) {
+	offer := r.Offer
+
+	switch {
+	case offer.QuantityMax == nil && r.Quantity != 0:
+		return 0, fmt.Errorf("offer doesn't support quantities")
+
+	case offer.QuantityMax != nil && r.Quantity == 0:
+		return 0, fmt.Errorf("offer requires a quantity")
+
+	case offer.QuantityMax != nil && *offer.QuantityMax != 0 &&
+		r.Quantity > *offer.QuantityMax:
+
+		return 0, fmt.Errorf("quantity %d exceeds maximum %d",
+			r.Quantity, *offer.QuantityMax)
+	}
+
+	// We can't convert other currencies, so the payer needs to tell us
+	// the amount in that case.
+	if offer.Currency != "" && r.Amount == 0 {
+		return 0, fmt.Errorf("offer in currency %v requires an "+
+			"amount", offer.Currency)
+	}
+
+	if offer.Amount == 0 || offer.Currency != "" {
+		if r.Amount == 0 {
+			return 0, fmt.Errorf("offer requires an amount")
+		}
+
+		return lnwire.MilliSatoshi(r.Amount), nil
+	}
+
+	quantity := r.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	expected := offer.Amount * quantity
+	if expected/quantity != offer.Amount {
+		return 0, fmt.Errorf("amount overflow")
+	}
+
+	if r.Amount == 0 {
+		return lnwire.MilliSatoshi(expected), nil
+	}
+	if r.Amount < expected {
+		return 0, ErrAmountTooLow
+	}
+
+	return lnwire.MilliSatoshi(r.Amount), nil
+}
+
+// mirrors returns true if the invoice request records mirrored in an invoice
+// match the records of this request.
+func (r *InvoiceRequest) mirrors(other *InvoiceRequest) (bool, error) {
+	recs, err := r.records(false)
+	if err != nil {
+		return false, err
+	}
+	otherRecs, err := other.records(false)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(recs.serialize(), otherRecs.serialize()), nil
+}
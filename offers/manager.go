@@ -0,0 +1,645 @@
+package offers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/schnorr"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// InvoiceRequestRecordType is the type of the onion message record
+	// that carries an invoice request.
+	InvoiceRequestRecordType = 64
+
+	// InvoiceRecordType is the type of the onion message record that
+	// carries an invoice.
+	InvoiceRecordType = 66
+
+	// InvoiceErrorRecordType is the type of the onion message record that
+	// carries an invoice error.
+	InvoiceErrorRecordType = 68
+
+	// metadataNonceSize is the size of the random nonce in the metadata of
+	// our offers.
+	metadataNonceSize = 16
+
+	// DefaultFetchTimeout is the default time we wait for the response to
+	// an invoice request.
+	DefaultFetchTimeout = time.Minute
+)
+
+var (
+	// ErrFetchTimeout is returned when the issuer of an offer doesn't
+	// respond to an invoice request in time.
+	ErrFetchTimeout = errors.New("timeout waiting for invoice")
+
+	// ErrUnknownOffer is returned when an invoice request is received for
+	// an offer we didn't create.
+	ErrUnknownOffer = errors.New("unknown offer")
+
+	// ErrOwnOffer is returned when we try to fetch an invoice for an offer
+	// we created ourselves.
+	ErrOwnOffer = errors.New("cannot fetch invoice for own offer")
+
+	// ErrManagerShuttingDown is returned when the manager is stopped while
+	// waiting for an invoice.
+	ErrManagerShuttingDown = errors.New("offer manager shutting down")
+)
+
+// EncodeInvoiceFunc signs and encodes the invoice for an invoice request once
+// its payment hash and blinded payment paths are known.
+type EncodeInvoiceFunc func(paymentHash lntypes.Hash, creationDate time.Time,
+	paths []*zpay32.BlindedPaymentPath) (string, error)
+
+// ManagerConfig contains the dependencies of the offer manager.
+type ManagerConfig struct {
+	// NodeKey is our node's identity public key. It is the node ID of our
+	// offers and signs the invoices we create for them.
+	NodeKey *btcec.PublicKey
+
+	// MetadataKey is the secret used to authenticate the metadata of our
+	// offers, which allows us to recognize them without storing them.
+	MetadataKey [32]byte
+
+	// ChainHash is the genesis hash of the chain we're on.
+	ChainHash chainhash.Hash
+
+	// SignInvoice creates a schnorr signature of the digest with our node
+	// key.
+	SignInvoice func(digest [32]byte) (schnorr.Signature, error)
+
+	// AddInvoice adds an invoice for one of our offers to the invoice
+	// registry. The invoice is encoded by the passed function.
+	AddInvoice func(offerID [32]byte, memo string,
+		amt lnwire.MilliSatoshi, expiry time.Duration,
+		encode EncodeInvoiceFunc) error
+
+	// SendOnionMessage sends an onion message along the passed path,
+	// optionally followed by a blinded destination.
+	SendOnionMessage func(path []*btcec.PublicKey,
+		destination *blindedpath.BlindedPath,
+		payload *hop.OnionMessagePayload) error
+
+	// FindOnionMessagePath returns a path of nodes that support onion
+	// messages from one of our peers to the target, including the target.
+	FindOnionMessagePath func(target *btcec.PublicKey) ([]*btcec.PublicKey,
+		error)
+
+	// FetchTimeout is the time we wait for the response to an invoice
+	// request.
+	FetchTimeout time.Duration
+}
+
+// OfferParams are the parameters of a new offer.
+type OfferParams struct {
+	// Amount is the amount per item in millisatoshis. If zero, the payer
+	// chooses the amount.
+	Amount lnwire.MilliSatoshi
+
+	// Description describes the purpose of the payment.
+	Description string
+
+	// Issuer describes the issuer of the offer.
+	Issuer string
+
+	// QuantityMax is the maximum quantity of items that can be requested.
+	// If nil, the quantity can't be set.
+	QuantityMax *uint64
+
+	// AbsoluteExpiry is the time after which the offer can no longer be
+	// used. If zero, the offer doesn't expire.
+	AbsoluteExpiry time.Time
+}
+
+// fetchResponse is the response to an invoice request we sent.
+type fetchResponse struct {
+	invoice []byte
+	err     *InvoiceError
+}
+
+// Manager creates offers and fetches invoices for them. It responds to the
+// invoice requests for our offers and hands the invoices we requested to the
+// payers waiting for them. All messages are exchanged as onion messages.
+type Manager struct {
+	cfg *ManagerConfig
+
+	// pending maps the path IDs of the reply paths of our outstanding
+	// invoice requests to the channel the response is delivered to.
+	pending map[[32]byte]chan *fetchResponse
+	mu      sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager creates a new offer manager.
+func NewManager(cfg *ManagerConfig) *Manager {
+	if cfg.FetchTimeout == 0 {
+		cfg.FetchTimeout = DefaultFetchTimeout
+	}
+
+	return &Manager{
+		cfg:     cfg,
+		pending: make(map[[32]byte]chan *fetchResponse),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start starts the offer manager.
+func (m *Manager) Start() error {
+	log.Info("Offer manager starting")
+
+	return nil
+}
+
+// Stop stops the offer manager and waits for all pending requests to be
+// handled.
+func (m *Manager) Stop() error {
+	log.Info("Offer manager shutting down")
+
+	close(m.quit)
+	m.wg.Wait()
+
+	return nil
+}
+
+// CreateOffer creates a new offer that is paid to our node. The offer isn't
+// stored, its metadata authenticates it when we receive an invoice request.
+func (m *Manager) CreateOffer(params *OfferParams) (*Offer, error) {
+	offer := &Offer{
+		Amount:         uint64(params.Amount),
+		Description:    params.Description,
+		Issuer:         params.Issuer,
+		QuantityMax:    params.QuantityMax,
+		AbsoluteExpiry: params.AbsoluteExpiry,
+		NodeID:         m.cfg.NodeKey,
+	}
+
+	// Offers without chains are only valid on mainnet.
+	if !offer.SupportsChain(m.cfg.ChainHash) {
+		offer.Chains = []chainhash.Hash{m.cfg.ChainHash}
+	}
+
+	if err := offer.validate(); err != nil {
+		return nil, err
+	}
+
+	var nonce [metadataNonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+
+	mac, err := m.metadataMAC(nonce[:], offer)
+	if err != nil {
+		return nil, err
+	}
+	offer.Metadata = append(nonce[:], mac...)
+
+	return offer, nil
+}
+
+// metadataMAC computes the MAC of the offer, which is stored in its metadata
+// together with the nonce. It covers all records of the offer except the
+// metadata itself.
+func (m *Manager) metadataMAC(nonce []byte, offer *Offer) ([]byte, error) {
+	recs, err := offer.records()
+	if err != nil {
+		return nil, err
+	}
+	delete(recs, offerMetadataType)
+
+	mac := hmac.New(sha256.New, m.cfg.MetadataKey[:])
+	mac.Write(nonce)
+	mac.Write(recs.serialize())
+
+	return mac.Sum(nil), nil
+}
+
+// verifyOffer checks that the offer was created by us.
+func (m *Manager) verifyOffer(offer *Offer) error {
+	if offer.NodeID == nil || !offer.NodeID.IsEqual(m.cfg.NodeKey) {
+		return ErrUnknownOffer
+	}
+
+	if len(offer.Metadata) != metadataNonceSize+sha256.Size {
+		return ErrUnknownOffer
+	}
+
+	nonce := offer.Metadata[:metadataNonceSize]
+	mac, err := m.metadataMAC(nonce, offer)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal(mac, offer.Metadata[metadataNonceSize:]) {
+		return ErrUnknownOffer
+	}
+
+	return nil
+}
+
+// HandleOnionMessage handles the offer messages contained in an onion message
+// that is addressed to us. It returns true if the message contained an offer
+// message.
+func (m *Manager) HandleOnionMessage(payload *hop.OnionMessagePayload) bool {
+	records := payload.CustomRecords
+
+	if data, ok := records[InvoiceRequestRecordType]; ok {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+
+			m.handleInvoiceRequest(data, payload.ReplyPath)
+		}()
+
+		return true
+	}
+
+	if data, ok := records[InvoiceRecordType]; ok {
+		m.handleResponse(payload.PathID, &fetchResponse{
+			invoice: data,
+		})
+
+		return true
+	}
+
+	if data, ok := records[InvoiceErrorRecordType]; ok {
+		invErr, err := DecodeInvoiceError(data)
+		if err != nil {
+			log.Debugf("Unable to decode invoice error: %v", err)
+			return true
+		}
+
+		m.handleResponse(payload.PathID, &fetchResponse{
+			err: invErr,
+		})
+
+		return true
+	}
+
+	return false
+}
+
+// handleInvoiceRequest creates an invoice for an invoice request for one of
+// our offers and sends it to the reply path of the request. If the request
+// can't be fulfilled, an invoice error is returned instead.
+func (m *Manager) handleInvoiceRequest(data []byte,
+	replyPath *blindedpath.BlindedPath) {
+
+	// Without a reply path, there is no way to respond.
+	if replyPath == nil {
+		log.Debugf("Ignoring invoice request without reply path")
+		return
+	}
+
+	reply := &hop.OnionMessagePayload{
+		CustomRecords: make(record.CustomSet),
+	}
+
+	invoice, invErr := m.createInvoice(data)
+	if invErr != nil {
+		log.Debugf("Rejecting invoice request: %v", invErr)
+		reply.CustomRecords[InvoiceErrorRecordType] = invErr.Serialize()
+	} else {
+		reply.CustomRecords[InvoiceRecordType] = invoice
+	}
+
+	if err := m.sendToBlindedPath(replyPath, reply); err != nil {
+		log.Errorf("Unable to respond to invoice request: %v", err)
+	}
+}
+
+// createInvoice creates and signs an invoice for the serialized invoice
+// request. The invoice is added to the invoice registry.
+func (m *Manager) createInvoice(data []byte) ([]byte, *InvoiceError) {
+	req, err := DecodeInvoiceRequest(data)
+	if err != nil {
+		return nil, &InvoiceError{Message: err.Error()}
+	}
+
+	offer := req.Offer
+	if err := m.verifyOffer(offer); err != nil {
+		return nil, &InvoiceError{Message: err.Error()}
+	}
+
+	if offer.IsExpired(time.Now()) {
+		field := uint64(offerAbsoluteExpiryType)
+		return nil, &InvoiceError{
+			ErroneousField: &field,
+			Message:        "offer expired",
+		}
+	}
+
+	if req.ChainHash() != m.cfg.ChainHash {
+		field := uint64(invReqChainType)
+		return nil, &InvoiceError{
+			ErroneousField: &field,
+			Message:        "unsupported chain",
+		}
+	}
+
+	amt, err := req.AmountMsat()
+	if err != nil {
+		field := uint64(invReqAmountType)
+		invErr := &InvoiceError{
+			ErroneousField: &field,
+			Message:        err.Error(),
+		}
+		if err == ErrAmountTooLow {
+			invErr.SuggestedValue = encodeTu64(offer.Amount)
+		}
+
+		return nil, invErr
+	}
+
+	offerID, err := offer.ID()
+	if err != nil {
+		return nil, &InvoiceError{Message: err.Error()}
+	}
+
+	// The invoice is encoded once the registry created the payment hash
+	// and the blinded payment paths.
+	var serialized []byte
+	encode := func(paymentHash lntypes.Hash, creationDate time.Time,
+		paths []*zpay32.BlindedPaymentPath) (string, error) {
+
+		invoice := &Invoice{
+			Request:        req,
+			Paths:          paths,
+			CreatedAt:      creationDate,
+			RelativeExpiry: DefaultRelativeExpiry,
+			PaymentHash:    paymentHash,
+			Amount:         amt,
+			NodeID:         m.cfg.NodeKey,
+		}
+		if err := invoice.Sign(m.cfg.SignInvoice); err != nil {
+			return "", err
+		}
+
+		invoiceBytes, err := invoice.Serialize()
+		if err != nil {
+			return "", err
+		}
+		serialized = invoiceBytes
+
+		return invoice.Encode()
+	}
+
+	err = m.cfg.AddInvoice(
+		offerID, offer.Description, amt, DefaultRelativeExpiry, encode,
+	)
+	if err != nil {
+		return nil, &InvoiceError{
+			Message: fmt.Sprintf("unable to create invoice: %v",
+				err),
+		}
+	}
+
+	log.Infof("Created invoice for offer %x, amount %v", offerID[:], amt)
+
+	return serialized, nil
+}
+
+// handleResponse delivers the response to one of our invoice requests to the
+// payer waiting for it. The response is identified by the path ID of the
+// reply path it was sent to.
+func (m *Manager) handleResponse(pathID []byte, resp *fetchResponse) {
+	var id [32]byte
+	if len(pathID) != len(id) {
+		log.Debugf("Ignoring offer response with invalid path id")
+		return
+	}
+	copy(id[:], pathID)
+
+	m.mu.Lock()
+	respChan, ok := m.pending[id]
+	delete(m.pending, id)
+	m.mu.Unlock()
+
+	if !ok {
+		log.Debugf("Ignoring unexpected offer response")
+		return
+	}
+
+	respChan <- resp
+}
+
+// FetchInvoice requests an invoice for the offer from its issuer and waits for
+// the response. The amount is only required if the offer doesn't specify one.
+// The returned invoice is verified to match the request.
+func (m *Manager) FetchInvoice(offer *Offer, amt lnwire.MilliSatoshi,
+	quantity uint64, payerNote string) (*Invoice, error) {
+
+	if offer.NodeID != nil && offer.NodeID.IsEqual(m.cfg.NodeKey) {
+		return nil, ErrOwnOffer
+	}
+
+	if offer.IsExpired(time.Now()) {
+		return nil, fmt.Errorf("offer expired")
+	}
+
+	if !offer.SupportsChain(m.cfg.ChainHash) {
+		return nil, fmt.Errorf("offer not valid for chain %v",
+			m.cfg.ChainHash)
+	}
+
+	if offer.Currency != "" && amt == 0 {
+		return nil, fmt.Errorf("offer in currency %v requires an "+
+			"amount", offer.Currency)
+	}
+
+	// Every request is signed by a fresh key, so requests can't be linked
+	// to each other or to our node.
+	payerKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := NewInvoiceRequest(
+		offer, m.cfg.ChainHash, uint64(amt), quantity, payerNote,
+		payerKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedAmt, err := req.AmountMsat()
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, err := req.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine the path to the issuer. If the offer contains blinded
+	// paths, we use the first one, otherwise we route to its node ID.
+	var (
+		path        []*btcec.PublicKey
+		destination *blindedpath.BlindedPath
+		replyNodes  []*btcec.PublicKey
+	)
+	if len(offer.Paths) > 0 {
+		destination = offer.Paths[0]
+
+		introPath, err := m.cfg.FindOnionMessagePath(
+			destination.IntroductionPoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		path = introPath[:len(introPath)-1]
+
+		// The issuer replies through the introduction node of its
+		// path, which must be close to it.
+		replyNodes = reverseNodes(introPath)
+	} else {
+		path, err = m.cfg.FindOnionMessagePath(offer.NodeID)
+		if err != nil {
+			return nil, err
+		}
+
+		replyNodes = reverseNodes(path[:len(path)-1])
+	}
+	replyNodes = append(replyNodes, m.cfg.NodeKey)
+
+	// The reply path carries a random path ID, which identifies the
+	// response to this request.
+	var pathID [32]byte
+	if _, err := rand.Read(pathID[:]); err != nil {
+		return nil, err
+	}
+
+	replyPath, err := hop.BuildOnionMessagePath(
+		replyNodes, &record.BlindedRouteData{PathID: pathID[:]},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	respChan := make(chan *fetchResponse, 1)
+	m.mu.Lock()
+	m.pending[pathID] = respChan
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.pending, pathID)
+		m.mu.Unlock()
+	}()
+
+	err = m.cfg.SendOnionMessage(
+		path, destination, &hop.OnionMessagePayload{
+			ReplyPath: replyPath,
+			CustomRecords: record.CustomSet{
+				InvoiceRequestRecordType: serialized,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *fetchResponse
+	select {
+	case resp = <-respChan:
+
+	case <-time.After(m.cfg.FetchTimeout):
+		return nil, ErrFetchTimeout
+
+	case <-m.quit:
+		return nil, ErrManagerShuttingDown
+	}
+
+	if resp.err != nil {
+		return nil, resp.err
+	}
+
+	invoice, err := DecodeInvoice(resp.invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyInvoice(req, invoice, expectedAmt); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// verifyInvoice checks that the invoice we received is a valid response to
+// our invoice request.
+func verifyInvoice(req *InvoiceRequest, invoice *Invoice,
+	expectedAmt lnwire.MilliSatoshi) error {
+
+	mirrors, err := req.mirrors(invoice.Request)
+	if err != nil {
+		return err
+	}
+	if !mirrors {
+		return fmt.Errorf("invoice doesn't match invoice request")
+	}
+
+	// The invoice must be signed by the issuer of the offer.
+	offerNode := req.Offer.NodeID
+	if offerNode != nil && !invoice.NodeID.IsEqual(offerNode) {
+		return fmt.Errorf("invoice not signed by offer node")
+	}
+
+	if invoice.Amount != expectedAmt {
+		return fmt.Errorf("invoice amount %v doesn't match expected "+
+			"amount %v", invoice.Amount, expectedAmt)
+	}
+
+	if len(invoice.Paths) == 0 {
+		return fmt.Errorf("invoice has no payment paths")
+	}
+
+	if invoice.IsExpired(time.Now()) {
+		return fmt.Errorf("invoice expired")
+	}
+
+	return nil
+}
+
+// sendToBlindedPath sends an onion message to a blinded destination. If the
+// introduction node isn't one of our peers, a path to it is found first.
+func (m *Manager) sendToBlindedPath(destination *blindedpath.BlindedPath,
+	payload *hop.OnionMessagePayload) error {
+
+	introPath, err := m.cfg.FindOnionMessagePath(
+		destination.IntroductionPoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	return m.cfg.SendOnionMessage(
+		introPath[:len(introPath)-1], destination, payload,
+	)
+}
+
+// reverseNodes returns the nodes in reverse order.
+func reverseNodes(nodes []*btcec.PublicKey) []*btcec.PublicKey {
+	reversed := make([]*btcec.PublicKey, len(nodes))
+	for i, node := range nodes {
+		reversed[len(nodes)-1-i] = node
+	}
+
+	return reversed
+}
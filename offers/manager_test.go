@@ -0,0 +1,206 @@
+package offers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/schnorr"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+// testNode is a node of the in-memory onion message network of the manager
+// tests.
+type testNode struct {
+	processor *hop.OnionMessageProcessor
+	manager   *Manager
+
+	// invoices are the invoices added for our offers.
+	invoices chan lntypes.Hash
+}
+
+// testNetwork is a network of nodes that are all directly connected.
+type testNetwork struct {
+	t     *testing.T
+	nodes map[route.Vertex]*testNode
+}
+
+// deliver processes an onion message hop by hop until it reaches its
+// recipient.
+func (n *testNetwork) deliver(firstNode *btcec.PublicKey,
+	msg *lnwire.OnionMessage) {
+
+	next := firstNode
+	for {
+		node, ok := n.nodes[route.NewVertex(next)]
+		if !ok {
+			return
+		}
+
+		processed, err := node.processor.ProcessOnionMessage(msg)
+		if err != nil {
+			return
+		}
+
+		if processed.Payload != nil {
+			node.manager.HandleOnionMessage(processed.Payload)
+			return
+		}
+
+		next, msg = processed.NextNode, processed.NextMessage
+	}
+}
+
+// addNode adds a node with an offer manager to the network.
+func (n *testNetwork) addNode() *testNode {
+	t := n.t
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	node := &testNode{
+		processor: hop.NewOnionMessageProcessor(
+			&sphinx.PrivKeyECDH{PrivKey: priv},
+			&chaincfg.RegressionNetParams,
+		),
+		invoices: make(chan lntypes.Hash, 1),
+	}
+
+	node.manager = NewManager(&ManagerConfig{
+		NodeKey:     priv.PubKey(),
+		MetadataKey: [32]byte{1},
+		ChainHash:   *chaincfg.RegressionNetParams.GenesisHash,
+		SignInvoice: func(digest [32]byte) (schnorr.Signature,
+			error) {
+
+			return schnorr.Sign(priv, digest)
+		},
+		AddInvoice: func(offerID [32]byte, memo string,
+			amt lnwire.MilliSatoshi, expiry time.Duration,
+			encode EncodeInvoiceFunc) error {
+
+			hash := lntypes.Hash{byte(amt)}
+			_, err := encode(hash, time.Now(), testPaymentPaths(t))
+			if err != nil {
+				return err
+			}
+
+			node.invoices <- hash
+			return nil
+		},
+		SendOnionMessage: func(path []*btcec.PublicKey,
+			destination *blindedpath.BlindedPath,
+			payload *hop.OnionMessagePayload) error {
+
+			firstNode, msg, err := hop.CreateOnionMessage(
+				path, destination, payload,
+			)
+			if err != nil {
+				return err
+			}
+
+			go n.deliver(firstNode, msg)
+			return nil
+		},
+		FindOnionMessagePath: func(
+			target *btcec.PublicKey) ([]*btcec.PublicKey, error) {
+
+			return []*btcec.PublicKey{target}, nil
+		},
+		FetchTimeout: 5 * time.Second,
+	})
+	require.NoError(t, node.manager.Start())
+	t.Cleanup(func() {
+		require.NoError(t, node.manager.Stop())
+	})
+
+	n.nodes[route.NewVertex(priv.PubKey())] = node
+
+	return node
+}
+
+// testPaymentPaths returns a blinded payment path to a random node.
+func testPaymentPaths(t *testing.T) []*zpay32.BlindedPaymentPath {
+	sessionKey := newTestKey(t)
+	hops, err := blindedpath.BuildBlindedPath(
+		sessionKey, []*blindedpath.HopInfo{{
+			NodePub:   newTestKey(t).PubKey(),
+			PlainText: []byte{1},
+		}},
+	)
+	require.NoError(t, err)
+
+	return []*zpay32.BlindedPaymentPath{{
+		CltvExpiryDelta: 80,
+		HTLCMinMsat:     1,
+		HTLCMaxMsat:     1000000,
+		Features:        lnwire.EmptyFeatureVector(),
+		Hops:            hops,
+	}}
+}
+
+// TestManagerFetchInvoice tests that a payer can fetch an invoice for an offer
+// from its issuer.
+func TestManagerFetchInvoice(t *testing.T) {
+	t.Parallel()
+
+	network := &testNetwork{
+		t:     t,
+		nodes: make(map[route.Vertex]*testNode),
+	}
+	alice := network.addNode()
+	bob := network.addNode()
+
+	offer, err := bob.manager.CreateOffer(&OfferParams{
+		Amount:      100,
+		Description: "coffee",
+	})
+	require.NoError(t, err)
+
+	// The offer survives an encoding round trip.
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+	offer, err = DecodeOffer(encoded)
+	require.NoError(t, err)
+
+	invoice, err := alice.manager.FetchInvoice(offer, 0, 0, "")
+	require.NoError(t, err)
+	require.Equal(t, lnwire.MilliSatoshi(100), invoice.Amount)
+
+	// The invoice was added to the registry of the issuer.
+	hash := <-bob.invoices
+	require.Equal(t, hash, invoice.PaymentHash)
+
+	// An overpayment is accepted.
+	invoice, err = alice.manager.FetchInvoice(offer, 120, 0, "")
+	require.NoError(t, err)
+	require.Equal(t, lnwire.MilliSatoshi(120), invoice.Amount)
+	<-bob.invoices
+
+	// We can't fetch invoices for our own offers.
+	_, err = bob.manager.FetchInvoice(offer, 0, 0, "")
+	require.Equal(t, ErrOwnOffer, err)
+
+	// An offer with tampered fields isn't recognized by the issuer, which
+	// responds with an invoice error.
+	offer.Amount = 1
+	_, err = alice.manager.FetchInvoice(offer, 0, 0, "")
+	require.IsType(t, &InvoiceError{}, err)
+
+	// Offers with the node ID of another node can't be fetched from it.
+	offer, err = alice.manager.CreateOffer(&OfferParams{
+		Description: "anything",
+	})
+	require.NoError(t, err)
+	offer.NodeID = bob.manager.cfg.NodeKey
+	_, err = alice.manager.FetchInvoice(offer, 10, 0, "")
+	require.IsType(t, &InvoiceError{}, err)
+}
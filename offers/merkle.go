@@ -0,0 +1,71 @@
+package offers
+
+import (
+	"bytes"
+
+	"github.com/lightningnetwork/lnd/schnorr"
+)
+
+// branchHash computes the hash of an inner node of the merkle tree from its
+// two children, which are sorted first.
+func branchHash(a, b [32]byte) [32]byte {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+
+	return schnorr.TaggedHash("LnBranch", a[:], b[:])
+}
+
+// merkleRoot computes the merkle root of the records as defined by BOLT 12.
+// Every record contributes a leaf that is paired with a nonce leaf, which
+// prevents revealing the content of a record when proving the inclusion of
+// another. Signature records are not part of the tree.
+func merkleRoot(recs records) [32]byte {
+	var (
+		nodes    [][32]byte
+		firstTLV []byte
+	)
+	for _, rec := range recs.sorted() {
+		if isSignatureType(rec.typ) {
+			continue
+		}
+
+		tlvBytes := rec.encode()
+		if firstTLV == nil {
+			firstTLV = tlvBytes
+		}
+
+		leaf := schnorr.TaggedHash("LnLeaf", tlvBytes)
+		nonce := schnorr.TaggedHash(
+			"LnNonce"+string(firstTLV), encodeBigSize(rec.typ),
+		)
+		nodes = append(nodes, branchHash(leaf, nonce))
+	}
+
+	if len(nodes) == 0 {
+		return [32]byte{}
+	}
+
+	// Pair up the nodes of each level. An odd node at the end of a level
+	// is carried up unchanged, so the tree is deepest at the lowest order
+	// records.
+	for len(nodes) > 1 {
+		var next [][32]byte
+		for i := 0; i < len(nodes); i += 2 {
+			if i+1 == len(nodes) {
+				next = append(next, nodes[i])
+				continue
+			}
+			next = append(next, branchHash(nodes[i], nodes[i+1]))
+		}
+		nodes = next
+	}
+
+	return nodes[0]
+}
+
+// signatureDigest returns the digest that is signed for the passed message
+// and field name given the merkle root of the message.
+func signatureDigest(messageName, fieldName string, root [32]byte) [32]byte {
+	return schnorr.TaggedHash("lightning"+messageName+fieldName, root[:])
+}
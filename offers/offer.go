@@ -0,0 +1,318 @@
+package offers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+)
+
+const (
+	// offerHRP is the human readable part of an encoded offer.
+	offerHRP = "lno"
+
+	offerChainsType         = 2
+	offerMetadataType       = 4
+	offerCurrencyType       = 6
+	offerAmountType         = 8
+	offerDescriptionType    = 10
+	offerFeaturesType       = 12
+	offerAbsoluteExpiryType = 14
+	offerPathsType          = 16
+	offerIssuerType         = 18
+	offerQuantityMaxType    = 20
+	offerNodeIDType         = 22
+
+	// offerTypeStart and offerTypeEnd delimit the types of the offer
+	// records, which are mirrored in invoice requests and invoices.
+	offerTypeStart = 1
+	offerTypeEnd   = 79
+)
+
+var (
+	// knownOfferTypes are the offer records we understand.
+	knownOfferTypes = map[uint64]struct{}{
+		offerChainsType:         {},
+		offerMetadataType:       {},
+		offerCurrencyType:       {},
+		offerAmountType:         {},
+		offerDescriptionType:    {},
+		offerFeaturesType:       {},
+		offerAbsoluteExpiryType: {},
+		offerPathsType:          {},
+		offerIssuerType:         {},
+		offerQuantityMaxType:    {},
+		offerNodeIDType:         {},
+	}
+
+	// ErrNoNodeID is returned when an offer has neither a node ID nor
+	// blinded paths to reach the issuer.
+	ErrNoNodeID = errors.New("offer has no node id or paths")
+)
+
+// Offer is a BOLT 12 offer. It is a long lived, reusable request for payment
+// from which payers fetch a fresh invoice for each payment.
+type Offer struct {
+	// Chains are the chains the offer is valid for. If empty, the offer
+	// is only valid for bitcoin mainnet.
+	Chains []chainhash.Hash
+
+	// Metadata is opaque data the issuer can use to recognize its offer.
+	Metadata []byte
+
+	// Currency is the ISO 4217 code of the currency Amount is
+	// denominated in. If empty, the amount is in millisatoshis.
+	Currency string
+
+	// Amount is the amount expected per item. If zero, the payer chooses
+	// the amount.
+	Amount uint64
+
+	// Description describes the purpose of the payment.
+	Description string
+
+	// Features are the features payers need to support.
+	Features *lnwire.RawFeatureVector
+
+	// AbsoluteExpiry is the time after which the offer can no longer be
+	// used. If zero, the offer doesn't expire.
+	AbsoluteExpiry time.Time
+
+	// Paths are blinded paths to the issuer, which can be used instead of
+	// contacting its node directly.
+	Paths []*blindedpath.BlindedPath
+
+	// Issuer describes the issuer of the offer.
+	Issuer string
+
+	// QuantityMax is the maximum quantity of items that can be requested.
+	// If nil, the quantity can't be set. A value of zero means there is no
+	// maximum.
+	QuantityMax *uint64
+
+	// NodeID is the public key of the issuer that signs the invoices.
+	NodeID *btcec.PublicKey
+
+	// extra contains the unknown odd records of the offer.
+	extra records
+}
+
+// records returns the TLV records of the offer.
+func (o *Offer) records() (records, error) {
+	recs := make(records)
+	for typ, value := range o.extra {
+		recs[typ] = value
+	}
+
+	if len(o.Chains) > 0 {
+		chains := make([]byte, 0, len(o.Chains)*chainhash.HashSize)
+		for _, chain := range o.Chains {
+			chains = append(chains, chain[:]...)
+		}
+		recs[offerChainsType] = chains
+	}
+	if o.Metadata != nil {
+		recs[offerMetadataType] = o.Metadata
+	}
+	if o.Currency != "" {
+		recs[offerCurrencyType] = []byte(o.Currency)
+	}
+	if o.Amount != 0 {
+		recs[offerAmountType] = encodeTu64(o.Amount)
+	}
+	if o.Description != "" {
+		recs[offerDescriptionType] = []byte(o.Description)
+	}
+	if o.Features != nil && !o.Features.IsEmpty() {
+		features, err := encodeFeatures(o.Features)
+		if err != nil {
+			return nil, err
+		}
+		recs[offerFeaturesType] = features
+	}
+	if !o.AbsoluteExpiry.IsZero() {
+		recs[offerAbsoluteExpiryType] = encodeTu64(
+			uint64(o.AbsoluteExpiry.Unix()),
+		)
+	}
+	if len(o.Paths) > 0 {
+		paths, err := encodeBlindedPaths(o.Paths)
+		if err != nil {
+			return nil, err
+		}
+		recs[offerPathsType] = paths
+	}
+	if o.Issuer != "" {
+		recs[offerIssuerType] = []byte(o.Issuer)
+	}
+	if o.QuantityMax != nil {
+		recs[offerQuantityMaxType] = encodeTu64(*o.QuantityMax)
+	}
+	if o.NodeID != nil {
+		recs[offerNodeIDType] = o.NodeID.SerializeCompressed()
+	}
+
+	return recs, nil
+}
+
+// decodeOfferRecords parses the offer records out of the passed records. All
+// records outside of the offer range are ignored.
+func decodeOfferRecords(all records) (*Offer, error) {
+	recs := all.filter(offerTypeStart, offerTypeEnd)
+
+	o := &Offer{
+		extra: make(records),
+	}
+	for typ, value := range recs {
+		var err error
+		switch typ {
+		case offerChainsType:
+			if len(value)%chainhash.HashSize != 0 {
+				return nil, fmt.Errorf("invalid chains length")
+			}
+			for i := 0; i < len(value); i += chainhash.HashSize {
+				var chain chainhash.Hash
+				copy(chain[:], value[i:])
+				o.Chains = append(o.Chains, chain)
+			}
+
+		case offerMetadataType:
+			o.Metadata = value
+
+		case offerCurrencyType:
+			o.Currency = string(value)
+
+		case offerAmountType:
+			o.Amount, err = decodeTu64(value)
+
+		case offerDescriptionType:
+			o.Description = string(value)
+
+		case offerFeaturesType:
+			o.Features, err = decodeFeatures(value)
+
+		case offerAbsoluteExpiryType:
+			var expiry uint64
+			expiry, err = decodeTu64(value)
+			o.AbsoluteExpiry = time.Unix(int64(expiry), 0)
+
+		case offerPathsType:
+			o.Paths, err = decodeBlindedPaths(value)
+			if err == nil && len(o.Paths) == 0 {
+				err = fmt.Errorf("empty offer paths")
+			}
+
+		case offerIssuerType:
+			o.Issuer = string(value)
+
+		case offerQuantityMaxType:
+			var quantityMax uint64
+			quantityMax, err = decodeTu64(value)
+			o.QuantityMax = &quantityMax
+
+		case offerNodeIDType:
+			o.NodeID, err = decodePubKey(value)
+
+		default:
+			o.extra[typ] = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid offer record %d: %v",
+				typ, err)
+		}
+	}
+
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// validate checks that the offer is well formed.
+func (o *Offer) validate() error {
+	if o.Amount != 0 && o.Description == "" {
+		return fmt.Errorf("offer with amount has no description")
+	}
+
+	if o.Currency != "" && o.Amount == 0 {
+		return fmt.Errorf("offer with currency has no amount")
+	}
+
+	if o.NodeID == nil && len(o.Paths) == 0 {
+		return ErrNoNodeID
+	}
+
+	return nil
+}
+
+// Encode returns the bech32 encoding of the offer.
+func (o *Offer) Encode() (string, error) {
+	if err := o.validate(); err != nil {
+		return "", err
+	}
+
+	recs, err := o.records()
+	if err != nil {
+		return "", err
+	}
+
+	return encodeString(offerHRP, recs.serialize())
+}
+
+// DecodeOffer parses a bech32 encoded offer.
+func DecodeOffer(s string) (*Offer, error) {
+	data, err := decodeString(offerHRP, s)
+	if err != nil {
+		return nil, err
+	}
+
+	recs, err := parseRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recs.checkTypes(
+		knownOfferTypes, [2]uint64{offerTypeStart, offerTypeEnd},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeOfferRecords(recs)
+}
+
+// ID returns the offer ID, which is the merkle root of its records.
+func (o *Offer) ID() ([32]byte, error) {
+	recs, err := o.records()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return merkleRoot(recs), nil
+}
+
+// SupportsChain returns true if the offer is valid for the passed chain.
+func (o *Offer) SupportsChain(chain chainhash.Hash) bool {
+	if len(o.Chains) == 0 {
+		return chain == *chaincfg.MainNetParams.GenesisHash
+	}
+
+	for _, c := range o.Chains {
+		if c == chain {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsExpired returns true if the offer has expired at the passed time.
+func (o *Offer) IsExpired(now time.Time) bool {
+	return !o.AbsoluteExpiry.IsZero() && now.After(o.AbsoluteExpiry)
+}
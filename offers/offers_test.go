@@ -0,0 +1,322 @@
+package offers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/schnorr"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKey returns a fresh private key.
+func newTestKey(t *testing.T) *btcec.PrivateKey {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	return priv
+}
+
+// TestOfferEncoding tests that offers survive an encoding round trip and
+// that malformed offers are rejected.
+func TestOfferEncoding(t *testing.T) {
+	t.Parallel()
+
+	quantityMax := uint64(5)
+	features := lnwire.NewRawFeatureVector(lnwire.FeatureBit(101))
+	regtest := *chaincfg.RegressionNetParams.GenesisHash
+	offer := &Offer{
+		Chains:         []chainhash.Hash{regtest},
+		Metadata:       []byte{1, 2, 3},
+		Amount:         1000,
+		Description:    "coffee",
+		Features:       features,
+		AbsoluteExpiry: time.Unix(2000000000, 0),
+		Issuer:         "shop",
+		QuantityMax:    &quantityMax,
+		NodeID:         newTestKey(t).PubKey(),
+	}
+
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "lno1"))
+
+	decoded, err := DecodeOffer(encoded)
+	require.NoError(t, err)
+	require.Equal(t, offer.Chains, decoded.Chains)
+	require.Equal(t, offer.Metadata, decoded.Metadata)
+	require.Equal(t, offer.Amount, decoded.Amount)
+	require.Equal(t, offer.Description, decoded.Description)
+	require.True(t, offer.Features.Equals(decoded.Features))
+	require.Equal(t, offer.AbsoluteExpiry, decoded.AbsoluteExpiry)
+	require.Equal(t, offer.Issuer, decoded.Issuer)
+	require.Equal(t, *offer.QuantityMax, *decoded.QuantityMax)
+	require.True(t, offer.NodeID.IsEqual(decoded.NodeID))
+
+	require.True(t, decoded.SupportsChain(regtest))
+	require.False(t, decoded.SupportsChain(
+		*chaincfg.MainNetParams.GenesisHash,
+	))
+
+	id, err := offer.ID()
+	require.NoError(t, err)
+	decodedID, err := decoded.ID()
+	require.NoError(t, err)
+	require.Equal(t, id, decodedID)
+
+	// Offers can be split with '+' and whitespace, and can be upper case.
+	split := encoded[:20] + "+\n  " + encoded[20:]
+	_, err = DecodeOffer(strings.ToUpper(split))
+	require.NoError(t, err)
+
+	_, err = DecodeOffer(encoded[:20] + "+")
+	require.Error(t, err)
+
+	// Offers without a node ID or paths can't be reached.
+	offer.NodeID = nil
+	_, err = offer.Encode()
+	require.Equal(t, ErrNoNodeID, err)
+
+	// Unknown even records are rejected, unknown odd ones are kept.
+	offer.NodeID = newTestKey(t).PubKey()
+	offer.extra = records{41: {1}}
+	encoded, err = offer.Encode()
+	require.NoError(t, err)
+	decoded, err = DecodeOffer(encoded)
+	require.NoError(t, err)
+	require.Equal(t, records{41: {1}}, decoded.extra)
+
+	offer.extra = records{42: {1}}
+	encoded, err = offer.Encode()
+	require.NoError(t, err)
+	_, err = DecodeOffer(encoded)
+	require.Error(t, err)
+
+	// Records outside of the offer range are rejected.
+	offer.extra = records{81: {1}}
+	encoded, err = offer.Encode()
+	require.NoError(t, err)
+	_, err = DecodeOffer(encoded)
+	require.Error(t, err)
+}
+
+// TestInvoiceRequestAmount tests the amount and quantity checks of invoice
+// requests.
+func TestInvoiceRequestAmount(t *testing.T) {
+	t.Parallel()
+
+	quantityMax := uint64(3)
+	unlimited := uint64(0)
+
+	tests := []struct {
+		name        string
+		offerAmt    uint64
+		quantityMax *uint64
+		amt         uint64
+		quantity    uint64
+		expected    lnwire.MilliSatoshi
+		expectErr   bool
+	}{{
+		name:     "offer amount",
+		offerAmt: 1000,
+		expected: 1000,
+	}, {
+		name:     "overpayment",
+		offerAmt: 1000,
+		amt:      1500,
+		expected: 1500,
+	}, {
+		name:      "underpayment",
+		offerAmt:  1000,
+		amt:       500,
+		expectErr: true,
+	}, {
+		name:     "payer chooses amount",
+		amt:      700,
+		expected: 700,
+	}, {
+		name:      "missing amount",
+		expectErr: true,
+	}, {
+		name:        "quantity",
+		offerAmt:    1000,
+		quantityMax: &quantityMax,
+		quantity:    3,
+		expected:    3000,
+	}, {
+		name:        "quantity too high",
+		offerAmt:    1000,
+		quantityMax: &quantityMax,
+		quantity:    4,
+		expectErr:   true,
+	}, {
+		name:        "unlimited quantity",
+		offerAmt:    1000,
+		quantityMax: &unlimited,
+		quantity:    10,
+		expected:    10000,
+	}, {
+		name:        "missing quantity",
+		offerAmt:    1000,
+		quantityMax: &quantityMax,
+		expectErr:   true,
+	}, {
+		name:      "unexpected quantity",
+		offerAmt:  1000,
+		quantity:  2,
+		expectErr: true,
+	}}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			req := &InvoiceRequest{
+				Offer: &Offer{
+					Amount:      test.offerAmt,
+					QuantityMax: test.quantityMax,
+				},
+				Amount:   test.amt,
+				Quantity: test.quantity,
+			}
+
+			amt, err := req.AmountMsat()
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, amt)
+		})
+	}
+}
+
+// TestInvoice tests that an invoice request and the invoice created for it
+// can be serialized, and that their signatures are verified.
+func TestInvoice(t *testing.T) {
+	t.Parallel()
+
+	nodeKey := newTestKey(t)
+	offer := &Offer{
+		Metadata:    []byte{9, 9, 9},
+		Amount:      5000,
+		Description: "book",
+		NodeID:      nodeKey.PubKey(),
+	}
+
+	payerKey := newTestKey(t)
+	req, err := NewInvoiceRequest(
+		offer, *chaincfg.MainNetParams.GenesisHash, 0, 0, "thanks",
+		payerKey,
+	)
+	require.NoError(t, err)
+	require.Nil(t, req.Chain)
+
+	serialized, err := req.Serialize()
+	require.NoError(t, err)
+
+	decodedReq, err := DecodeInvoiceRequest(serialized)
+	require.NoError(t, err)
+	require.Equal(t, "thanks", decodedReq.PayerNote)
+	require.Equal(t, offer.Description, decodedReq.Offer.Description)
+	require.True(t, payerKey.PubKey().IsEqual(decodedReq.PayerID))
+
+	amt, err := decodedReq.AmountMsat()
+	require.NoError(t, err)
+	require.Equal(t, lnwire.MilliSatoshi(5000), amt)
+
+	// Changing any field invalidates the signature.
+	decodedReq.PayerNote = "changed"
+	tampered, err := decodedReq.Serialize()
+	require.NoError(t, err)
+	_, err = DecodeInvoiceRequest(tampered)
+	require.Error(t, err)
+	decodedReq.PayerNote = req.PayerNote
+
+	// Create the invoice for the request.
+	introKey := newTestKey(t)
+	sessionKey := newTestKey(t)
+	hops, err := blindedpath.BuildBlindedPath(
+		sessionKey, []*blindedpath.HopInfo{{
+			NodePub:   introKey.PubKey(),
+			PlainText: []byte{1},
+		}, {
+			NodePub:   nodeKey.PubKey(),
+			PlainText: []byte{2},
+		}},
+	)
+	require.NoError(t, err)
+
+	invoice := &Invoice{
+		Request: decodedReq,
+		Paths: []*zpay32.BlindedPaymentPath{{
+			FeeBaseMsat:     1,
+			FeeRate:         100,
+			CltvExpiryDelta: 80,
+			HTLCMinMsat:     1,
+			HTLCMaxMsat:     100000,
+			Features:        lnwire.EmptyFeatureVector(),
+			Hops:            hops,
+		}},
+		CreatedAt:      time.Unix(1700000000, 0),
+		RelativeExpiry: time.Hour,
+		PaymentHash:    lntypes.Hash{1, 2, 3},
+		Amount:         amt,
+		NodeID:         nodeKey.PubKey(),
+	}
+	err = invoice.Sign(func(digest [32]byte) (schnorr.Signature, error) {
+		return schnorr.Sign(nodeKey, digest)
+	})
+	require.NoError(t, err)
+
+	encoded, err := invoice.Encode()
+	require.NoError(t, err)
+
+	decoded, err := DecodeInvoiceString(encoded)
+	require.NoError(t, err)
+	require.Equal(t, invoice.PaymentHash, decoded.PaymentHash)
+	require.Equal(t, invoice.Amount, decoded.Amount)
+	require.Equal(t, invoice.CreatedAt, decoded.CreatedAt)
+	require.Equal(t, time.Hour, decoded.Expiry())
+	require.Len(t, decoded.Paths, 1)
+	require.Equal(t, uint16(80), decoded.Paths[0].CltvExpiryDelta)
+	require.Equal(t, hops, decoded.Paths[0].Hops)
+
+	// The invoice mirrors the request.
+	mirrors, err := req.mirrors(decoded.Request)
+	require.NoError(t, err)
+	require.True(t, mirrors)
+
+	// An invoice signed by another key is rejected.
+	err = invoice.Sign(func(digest [32]byte) (schnorr.Signature, error) {
+		return schnorr.Sign(payerKey, digest)
+	})
+	require.NoError(t, err)
+	serialized, err = invoice.Serialize()
+	require.NoError(t, err)
+	_, err = DecodeInvoice(serialized)
+	require.Error(t, err)
+}
+
+// TestInvoiceError tests the serialization of invoice errors.
+func TestInvoiceError(t *testing.T) {
+	t.Parallel()
+
+	field := uint64(invReqAmountType)
+	invErr := &InvoiceError{
+		ErroneousField: &field,
+		SuggestedValue: encodeTu64(1000),
+		Message:        "amount too low",
+	}
+
+	decoded, err := DecodeInvoiceError(invErr.Serialize())
+	require.NoError(t, err)
+	require.Equal(t, invErr, decoded)
+}
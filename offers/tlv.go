@@ -0,0 +1,250 @@
+package offers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// signatureTypeStart is the first type of the range reserved for
+	// signature records, which are not covered by the merkle root.
+	signatureTypeStart = 240
+
+	// signatureTypeEnd is the last type of the signature range.
+	signatureTypeEnd = 1000
+
+	// signatureType is the type of the signature record of invoice
+	// requests and invoices.
+	signatureType = 240
+)
+
+var (
+	// ErrNotCanonical is returned when the records of a message are not
+	// in strictly ascending order.
+	ErrNotCanonical = errors.New("records not in canonical order")
+)
+
+// isSignatureType returns true if the type is in the signature range.
+func isSignatureType(typ uint64) bool {
+	return typ >= signatureTypeStart && typ <= signatureTypeEnd
+}
+
+// tlvRecord is a single TLV record with its raw value.
+type tlvRecord struct {
+	typ   uint64
+	value []byte
+}
+
+// encode returns the full serialization of the record including its type
+// and length.
+func (r *tlvRecord) encode() []byte {
+	var b bytes.Buffer
+	b.Write(encodeBigSize(r.typ))
+	b.Write(encodeBigSize(uint64(len(r.value))))
+	b.Write(r.value)
+
+	return b.Bytes()
+}
+
+// records is a TLV stream keyed by type. The raw values are kept so fields can
+// be mirrored byte for byte and signatures verified over fields we don't
+// understand.
+type records map[uint64][]byte
+
+// parseRecords parses a TLV stream. The records must be in strictly
+// ascending order.
+func parseRecords(data []byte) (records, error) {
+	var (
+		r    = bytes.NewReader(data)
+		buf  [8]byte
+		recs = make(records)
+		prev *uint64
+	)
+	for r.Len() > 0 {
+		typ, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+
+		if prev != nil && typ <= *prev {
+			return nil, ErrNotCanonical
+		}
+		prev = &typ
+
+		length, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if length > uint64(r.Len()) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		value := make([]byte, length)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+		recs[typ] = value
+	}
+
+	return recs, nil
+}
+
+// sorted returns the records in ascending type order.
+func (r records) sorted() []*tlvRecord {
+	sorted := make([]*tlvRecord, 0, len(r))
+	for typ, value := range r {
+		sorted = append(sorted, &tlvRecord{typ: typ, value: value})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].typ < sorted[j].typ
+	})
+
+	return sorted
+}
+
+// serialize returns the canonical serialization of the records.
+func (r records) serialize() []byte {
+	var b bytes.Buffer
+	for _, rec := range r.sorted() {
+		b.Write(rec.encode())
+	}
+
+	return b.Bytes()
+}
+
+// filter returns the records with a type in the range [start, end].
+func (r records) filter(start, end uint64) records {
+	filtered := make(records)
+	for typ, value := range r {
+		if typ >= start && typ <= end {
+			filtered[typ] = value
+		}
+	}
+
+	return filtered
+}
+
+// checkTypes returns an error if the records contain a type outside of the
+// allowed ranges, or an even type that isn't known.
+func (r records) checkTypes(known map[uint64]struct{},
+	ranges ...[2]uint64) error {
+
+	for typ := range r {
+		inRange := false
+		for _, rng := range ranges {
+			if typ >= rng[0] && typ <= rng[1] {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return fmt.Errorf("record type %d out of range", typ)
+		}
+
+		if _, ok := known[typ]; !ok && typ%2 == 0 {
+			return fmt.Errorf("unknown required record type %d",
+				typ)
+		}
+	}
+
+	return nil
+}
+
+// encodeBigSize returns the BigSize encoding of the value.
+func encodeBigSize(val uint64) []byte {
+	var (
+		b   bytes.Buffer
+		buf [8]byte
+	)
+	_ = tlv.WriteVarInt(&b, val, &buf)
+
+	return b.Bytes()
+}
+
+// encodeTu64 returns the truncated encoding of the value.
+func encodeTu64(val uint64) []byte {
+	var (
+		b   bytes.Buffer
+		buf [8]byte
+	)
+	_ = tlv.ETUint64T(&b, val, &buf)
+
+	return b.Bytes()
+}
+
+// decodeTu64 parses a truncated uint64.
+func decodeTu64(value []byte) (uint64, error) {
+	var (
+		val uint64
+		buf [8]byte
+	)
+	err := tlv.DTUint64(
+		bytes.NewReader(value), &val, &buf, uint64(len(value)),
+	)
+
+	return val, err
+}
+
+// decodePubKey parses a compressed public key.
+func decodePubKey(value []byte) (*btcec.PublicKey, error) {
+	return btcec.ParsePubKey(value, btcec.S256())
+}
+
+// encodeFeatures returns the serialization of the feature bits without a
+// length prefix.
+func encodeFeatures(features *lnwire.RawFeatureVector) ([]byte, error) {
+	var b bytes.Buffer
+	if err := features.EncodeBase256(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeFeatures parses feature bits without a length prefix.
+func decodeFeatures(value []byte) (*lnwire.RawFeatureVector, error) {
+	features := lnwire.NewRawFeatureVector()
+	err := features.DecodeBase256(bytes.NewReader(value), len(value))
+	if err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+// encodeBlindedPaths returns the concatenated serialization of the paths.
+func encodeBlindedPaths(paths []*blindedpath.BlindedPath) ([]byte, error) {
+	var b bytes.Buffer
+	for _, path := range paths {
+		if err := path.Encode(&b); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeBlindedPaths parses a list of concatenated blinded paths.
+func decodeBlindedPaths(value []byte) ([]*blindedpath.BlindedPath, error) {
+	var (
+		r     = bytes.NewReader(value)
+		paths []*blindedpath.BlindedPath
+	)
+	for r.Len() > 0 {
+		path, err := blindedpath.DecodeBlindedPath(r)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
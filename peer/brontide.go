@@ -16,6 +16,7 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
+	"golang.org/x/time/rate"
 
 	"github.com/lightningnetwork/lnd/buffer"
 	"github.com/lightningnetwork/lnd/chainntnfs"
@@ -68,6 +69,15 @@ const (
 
 	// ErrorBufferSize is the number of historic peer errors that we store.
 	ErrorBufferSize = 10
+
+	// onionMessageRate is the number of onion messages per second we
+	// accept from a single peer. Onion messages don't require any
+	// payment, so anything above this rate is dropped.
+	onionMessageRate = 10
+
+	// onionMessageBurst is the number of onion messages we accept from a
+	// single peer in a burst before the rate limit kicks in.
+	onionMessageBurst = 50
 )
 
 var (
@@ -240,6 +250,11 @@ type Config struct {
 	// invoice-related logic.
 	Invoices *invoices.InvoiceRegistry
 
+	// TrampolineRelay is passed to the ChannelLink on creation and relays
+	// the trampoline payments that are delegated to us. It is nil if
+	// trampoline relay is disabled.
+	TrampolineRelay htlcswitch.TrampolineRelayer
+
 	// ChannelNotifier is used by the link to notify other sub-systems about
 	// channel-related events and by the Brontide to subscribe to
 	// ActiveLinkEvents.
@@ -255,6 +270,10 @@ type Config struct {
 	// states.
 	AnchorTowerClient wtclient.Client
 
+	// TaprootTowerClient is used by simple taproot channels to backup
+	// revoked states.
+	TaprootTowerClient wtclient.Client
+
 	// DisconnectPeer is used to disconnect this peer if the cooperative close
 	// process fails.
 	DisconnectPeer func(*btcec.PublicKey) error
@@ -276,6 +295,24 @@ type Config struct {
 	// FundingManager is an implementation of the funding.Controller interface.
 	FundingManager funding.Controller
 
+	// HandleCustomMessage is called whenever a custom message is received
+	// from the peer.
+	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
+
+	// OnionMessageProcessor is used to decrypt the onion messages received
+	// from the peer. If nil, onion messages are dropped.
+	OnionMessageProcessor *hop.OnionMessageProcessor
+
+	// HandleOnionMessage is called whenever an onion message addressed to
+	// us is received from the peer.
+	HandleOnionMessage func(peer [33]byte,
+		payload *hop.OnionMessagePayload) error
+
+	// ForwardOnionMessage forwards an onion message to the next node in
+	// its path.
+	ForwardOnionMessage func(nextNode *btcec.PublicKey,
+		msg *lnwire.OnionMessage) error
+
 	// Hodl is used when creating ChannelLinks to specify HodlFlags as
 	// breakpoints in dev builds.
 	Hodl *hodl.Config
@@ -414,6 +451,30 @@ type Brontide struct {
 	// well as lnwire.ClosingSigned messages.
 	chanCloseMsgs chan *closeMsg
 
+	// spliceMtx protects access to the activeSplices map, which is read
+	// by the readHandler to route splice messages.
+	spliceMtx sync.RWMutex
+
+	// activeSplices is a map that keeps track of all the splices being
+	// negotiated. Any splice related messages are directed to one of
+	// these splicers. Once the splice transaction has been broadcast, the
+	// splicer will be deleted from the map.
+	activeSplices map[lnwire.ChannelID]*activeSplice
+
+	// localSpliceReqs is a channel in which any local requests to splice
+	// a particular channel are sent over.
+	localSpliceReqs chan *funding.SpliceReq
+
+	// spliceMsgs is a channel that any message related to a splice is
+	// sent over. This includes the splice_init and splice_ack messages,
+	// as well as the messages constructing and signing the splice
+	// transaction.
+	spliceMsgs chan *spliceMsg
+
+	// spliceTimeouts is a channel over which the splicers are sent whose
+	// negotiation didn't complete in time.
+	spliceTimeouts chan *funding.Splicer
+
 	// remoteFeatures is the feature vector received from the peer during
 	// the connection handshake.
 	remoteFeatures *lnwire.FeatureVector
@@ -424,6 +485,10 @@ type Brontide struct {
 	// peer's chansync message with its own over and over again.
 	resentChanSyncMsg map[lnwire.ChannelID]struct{}
 
+	// onionMsgLimiter limits the rate of onion messages we process from
+	// the peer.
+	onionMsgLimiter *rate.Limiter
+
 	queueQuit chan struct{}
 	quit      chan struct{}
 	wg        sync.WaitGroup
@@ -448,11 +513,17 @@ func NewBrontide(cfg Config) *Brontide {
 		localCloseChanReqs: make(chan *htlcswitch.ChanClose),
 		linkFailures:       make(chan linkFailureReport),
 		chanCloseMsgs:      make(chan *closeMsg),
+		activeSplices:      make(map[lnwire.ChannelID]*activeSplice),
+		localSpliceReqs:    make(chan *funding.SpliceReq),
+		spliceMsgs:         make(chan *spliceMsg),
+		spliceTimeouts:     make(chan *funding.Splicer),
 		resentChanSyncMsg:  make(map[lnwire.ChannelID]struct{}),
 		queueQuit:          make(chan struct{}),
 		quit:               make(chan struct{}),
 	}
 
+	p.onionMsgLimiter = rate.NewLimiter(onionMessageRate, onionMessageBurst)
+
 	return p
 }
 
@@ -707,11 +778,18 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 		// routing policy into a forwarding policy.
 		var forwardingPolicy *htlcswitch.ForwardingPolicy
 		if selfPolicy != nil {
+			inboundFee, err := selfPolicy.InboundFee()
+			if err != nil {
+				peerLog.Warnf("Unable to parse inbound fee "+
+					"for channel %v: %v", chanPoint, err)
+			}
+
 			forwardingPolicy = &htlcswitch.ForwardingPolicy{
 				MinHTLCOut:    selfPolicy.MinHTLC,
 				MaxHTLC:       selfPolicy.MaxHTLC,
 				BaseFee:       selfPolicy.FeeBaseMSat,
 				FeeRate:       selfPolicy.FeeProportionalMillionths,
+				InboundFee:    inboundFee,
 				TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
 			}
 		} else {
@@ -738,6 +816,20 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 			continue
 		}
 
+		// A channel with a pending splice stays paused until the
+		// splice transaction confirms, at which point the chain
+		// arbitrator moves it to the new funding output and the
+		// channel is added to the peer again.
+		spliceCandidates, err := dbChan.SpliceCandidates()
+		if err != nil {
+			return nil, err
+		}
+		if len(spliceCandidates) != 0 {
+			peerLog.Infof("ChannelPoint(%v) has a pending splice, "+
+				"won't start.", chanPoint)
+			continue
+		}
+
 		// Subscribe to the set of on-chain events for this channel.
 		chainEvents, err := p.cfg.ChainArb.SubscribeChannelEvents(
 			*chanPoint,
@@ -800,9 +892,12 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 	// okay if the clients are disabled altogether and these values are nil,
 	// as the link will check for nilness before using either.
 	var towerClient htlcswitch.TowerClient
-	if chanType.HasAnchors() {
+	switch {
+	case chanType.IsTaproot():
+		towerClient = p.cfg.TaprootTowerClient
+	case chanType.HasAnchors():
 		towerClient = p.cfg.AnchorTowerClient
-	} else {
+	default:
 		towerClient = p.cfg.TowerClient
 	}
 
@@ -813,6 +908,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		FetchLastChannelUpdate:  p.cfg.FetchLastChanUpdate,
 		HodlMask:                p.cfg.Hodl.Mask(),
 		Registry:                p.cfg.Invoices,
+		TrampolineRelay:         p.cfg.TrampolineRelay,
 		Switch:                  p.cfg.Switch,
 		Circuits:                p.cfg.Switch.CircuitModifier(),
 		ForwardPackets:          p.cfg.InterceptSwitch.ForwardPackets,
@@ -1348,6 +1444,20 @@ out:
 			isLinkUpdate bool
 		)
 
+		// Messages of a channel being spliced are handed to its
+		// splicer rather than the funding manager or the link.
+		chanID, ok := spliceMsgChanID(nextMsg)
+		if ok && p.isSplicing(chanID) {
+			select {
+			case p.spliceMsgs <- &spliceMsg{chanID, nextMsg}:
+			case <-p.quit:
+				break out
+			}
+
+			idleTimer.Reset(idleTimeout)
+			continue
+		}
+
 		switch msg := nextMsg.(type) {
 		case *lnwire.Pong:
 			// When we receive a Pong message in response to our
@@ -1366,10 +1476,29 @@ out:
 			*lnwire.AcceptChannel,
 			*lnwire.FundingCreated,
 			*lnwire.FundingSigned,
-			*lnwire.FundingLocked:
+			*lnwire.FundingLocked,
+			*lnwire.OpenChannel2,
+			*lnwire.AcceptChannel2,
+			*lnwire.TxAddInput,
+			*lnwire.TxAddOutput,
+			*lnwire.TxRemoveInput,
+			*lnwire.TxRemoveOutput,
+			*lnwire.TxComplete,
+			*lnwire.TxSignatures:
 
 			p.cfg.FundingManager.ProcessFundingMsg(msg, p)
 
+		// The initial commitment signature of a dual funded channel is
+		// handled by the funding manager, as there's no link for the
+		// channel yet.
+		case *lnwire.CommitSig:
+			targetChan = msg.ChanID
+			if p.cfg.FundingManager.IsPendingChannel(targetChan, p) {
+				p.cfg.FundingManager.ProcessFundingMsg(msg, p)
+				break
+			}
+			isLinkUpdate = p.isActiveChannel(targetChan)
+
 		case *lnwire.Shutdown:
 			select {
 			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
@@ -1383,6 +1512,13 @@ out:
 				break out
 			}
 
+		case *lnwire.SpliceInit:
+			select {
+			case p.spliceMsgs <- &spliceMsg{msg.ChanID, msg}:
+			case <-p.quit:
+				break out
+			}
+
 		case *lnwire.Error:
 			targetChan = msg.ChanID
 			isLinkUpdate = p.handleError(msg)
@@ -1422,6 +1558,16 @@ out:
 
 			discStream.AddMsg(msg)
 
+		case *lnwire.Custom:
+			err := p.handleCustomMessage(msg)
+			if err != nil {
+				p.storeError(err)
+				peerLog.Errorf("peer: %v, %v", p, err)
+			}
+
+		case *lnwire.OnionMessage:
+			p.handleOnionMessage(msg)
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -1459,6 +1605,68 @@ out:
 	peerLog.Tracef("readHandler for peer %v done", p)
 }
 
+// handleCustomMessage handles the given custom message if a handler is
+// registered.
+func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
+	if p.cfg.HandleCustomMessage == nil {
+		return fmt.Errorf("no custom message handler for "+
+			"message type %v", uint16(msg.MsgType()))
+	}
+
+	return p.cfg.HandleCustomMessage(p.PubKey(), msg)
+}
+
+// handleOnionMessage processes an onion message received from the peer. The
+// message is either handed to the onion message handler if we are its
+// recipient, or forwarded to the next node in its path. Invalid messages and
+// messages exceeding the rate limit of the peer are dropped.
+func (p *Brontide) handleOnionMessage(msg *lnwire.OnionMessage) {
+	if p.cfg.OnionMessageProcessor == nil {
+		peerLog.Debugf("Dropping onion message from %v: onion "+
+			"messages not supported", p)
+		return
+	}
+
+	if !p.onionMsgLimiter.Allow() {
+		peerLog.Debugf("Dropping onion message from %v: rate limit "+
+			"exceeded", p)
+		return
+	}
+
+	processed, err := p.cfg.OnionMessageProcessor.ProcessOnionMessage(msg)
+	if err != nil {
+		peerLog.Debugf("Unable to process onion message from %v: %v",
+			p, err)
+		return
+	}
+
+	if processed.Payload != nil {
+		if p.cfg.HandleOnionMessage == nil {
+			return
+		}
+
+		err := p.cfg.HandleOnionMessage(p.PubKey(), processed.Payload)
+		if err != nil {
+			peerLog.Errorf("Unable to handle onion message from "+
+				"%v: %v", p, err)
+		}
+		return
+	}
+
+	if p.cfg.ForwardOnionMessage == nil {
+		return
+	}
+
+	err = p.cfg.ForwardOnionMessage(
+		processed.NextNode, processed.NextMessage,
+	)
+	if err != nil {
+		peerLog.Debugf("Unable to forward onion message from %v to "+
+			"%x: %v", p, processed.NextNode.SerializeCompressed(),
+			err)
+	}
+}
+
 // isActiveChannel returns true if the provided channel id is active, otherwise
 // returns false.
 func (p *Brontide) isActiveChannel(chanID lnwire.ChannelID) bool {
@@ -2180,6 +2388,21 @@ out:
 				TimeLockDelta: defaultPolicy.TimeLockDelta,
 			}
 
+			// A channel moved to the funding output of a splice
+			// keeps the routing policy it had before the splice.
+			prevUpdate, err := newChan.SplicedChannelUpdate()
+			if err != nil {
+				peerLog.Warnf("Unable to fetch the channel "+
+					"update of spliced ChannelPoint(%v): "+
+					"%v", chanPoint, err)
+			}
+			if prevUpdate != nil {
+				forwardingPolicy = spliceForwardingPolicy(
+					prevUpdate,
+					newChan.LocalChanCfg.MaxPendingAmount,
+				)
+			}
+
 			// If we've reached this point, there are two possible scenarios.
 			// If the channel was in the active channels map as nil, then it
 			// was loaded from disk and we need to send reestablish. Else,
@@ -2223,6 +2446,21 @@ out:
 		case closeMsg := <-p.chanCloseMsgs:
 			p.handleCloseMsg(closeMsg)
 
+		// We've just received a local request to splice an active
+		// channel, which kicks off the splice negotiation.
+		case req := <-p.localSpliceReqs:
+			p.handleLocalSpliceReq(req)
+
+		// We've received a new splice related message from the remote
+		// peer, we'll use this message to advance the splicer.
+		case spliceMsg := <-p.spliceMsgs:
+			p.handleSpliceMsg(spliceMsg)
+
+		// The negotiation of a splice didn't complete in time, so
+		// we'll abort it.
+		case splicer := <-p.spliceTimeouts:
+			p.handleSpliceTimeout(splicer)
+
 		// The channel reannounce delay has elapsed, broadcast the
 		// reenabled channel updates to the network. This should only
 		// fire once, so we set the reenableTimeout channel to nil to
@@ -2255,6 +2493,8 @@ out:
 			}
 			p.activeChanMtx.Unlock()
 
+			p.failActiveSplices()
+
 			break out
 		}
 	}
@@ -6,11 +6,13 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/funding"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
@@ -984,6 +986,81 @@ func TestStaticRemoteDowngrade(t *testing.T) {
 	}
 }
 
+// TestPeerSpliceRejected tests that splices that can't be negotiated are
+// rejected without pausing the channel.
+func TestPeerSpliceRejected(t *testing.T) {
+	t.Parallel()
+
+	notifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	broadcastTxChan := make(chan *wire.MsgTx)
+
+	alicePeer, bobChan, cleanUp, err := createTestPeer(
+		notifier, broadcastTxChan, noUpdate,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	alicePeer.cfg.Features = lnwire.EmptyFeatureVector()
+	alicePeer.remoteFeatures = lnwire.EmptyFeatureVector()
+
+	chanID := lnwire.NewChanIDFromOutPoint(bobChan.ChannelPoint())
+
+	// Bob proposes a splice, but Alice doesn't signal the splice feature,
+	// so she responds with an error.
+	alicePeer.spliceMsgs <- &spliceMsg{
+		cid: chanID,
+		msg: &lnwire.SpliceInit{
+			ChanID:              chanID,
+			FundingContribution: 100_000,
+			FundingKey:          bobChan.LocalFundingKey,
+		},
+	}
+
+	select {
+	case outMsg := <-alicePeer.outgoingQueue:
+		errMsg, ok := outMsg.msg.(*lnwire.Error)
+		require.True(t, ok, "expected Error, got %T", outMsg.msg)
+		require.Equal(t, chanID, errMsg.ChanID)
+	case <-time.After(timeout):
+		t.Fatalf("did not receive error message")
+	}
+	require.False(t, alicePeer.isSplicing(chanID))
+
+	// A local splice of an unknown channel fails.
+	req := &funding.SpliceReq{
+		ChanPoint: &wire.OutPoint{Index: 5},
+		Amount:    100_000,
+		Txid:      make(chan chainhash.Hash, 1),
+		Err:       make(chan error, 1),
+	}
+	alicePeer.HandleLocalSpliceReq(req)
+
+	select {
+	case err := <-req.Err:
+		require.Error(t, err)
+	case <-time.After(timeout):
+		t.Fatalf("splice request not rejected")
+	}
+
+	// The same goes for the channel, as the splice feature isn't
+	// negotiated.
+	req.ChanPoint = bobChan.ChannelPoint()
+	alicePeer.HandleLocalSpliceReq(req)
+
+	select {
+	case err := <-req.Err:
+		require.Equal(t, funding.ErrSpliceNotSupported, err)
+	case <-time.After(timeout):
+		t.Fatalf("splice request not rejected")
+	}
+	require.False(t, alicePeer.isSplicing(chanID))
+	require.True(t, alicePeer.isActiveChannel(chanID))
+}
+
 // genScript creates a script paying out to the address provided, which must
 // be a valid address.
 func genScript(t *testing.T, address string) lnwire.DeliveryAddress {
@@ -0,0 +1,488 @@
+package peer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// spliceNegotiationTimeout is the time the negotiation of a splice may take
+// until we've signed the splice transaction. If it takes longer, the splice is
+// aborted and the channel is resumed.
+var spliceNegotiationTimeout = 2 * time.Minute
+
+// activeSplice tracks a splice that is being negotiated.
+type activeSplice struct {
+	splicer *funding.Splicer
+
+	// channel is the channel being spliced, which is resumed if the
+	// splice is aborted.
+	channel *lnwallet.LightningChannel
+
+	// policy is the forwarding policy of the channel's link before it was
+	// removed for the splice.
+	policy *htlcswitch.ForwardingPolicy
+
+	// timeout aborts the splice if the negotiation stalls.
+	timeout *time.Timer
+}
+
+// spliceMsg is a wrapper struct around any wire messages that deal with the
+// negotiation of a splice. This struct includes the raw channel ID targeted
+// along with the original message.
+type spliceMsg struct {
+	cid lnwire.ChannelID
+	msg lnwire.Message
+}
+
+// spliceMsgChanID returns the channel ID of messages that are part of a splice
+// negotiation. The interactive tx messages, commitment signatures and errors
+// only belong to a splice if the channel is being spliced.
+func spliceMsgChanID(msg lnwire.Message) (lnwire.ChannelID, bool) {
+	switch msg := msg.(type) {
+	case *lnwire.SpliceInit:
+		return msg.ChanID, true
+	case *lnwire.SpliceAck:
+		return msg.ChanID, true
+	case *lnwire.TxAddInput:
+		return msg.ChanID, true
+	case *lnwire.TxAddOutput:
+		return msg.ChanID, true
+	case *lnwire.TxRemoveInput:
+		return msg.ChanID, true
+	case *lnwire.TxRemoveOutput:
+		return msg.ChanID, true
+	case *lnwire.TxComplete:
+		return msg.ChanID, true
+	case *lnwire.TxSignatures:
+		return msg.ChanID, true
+	case *lnwire.CommitSig:
+		return msg.ChanID, true
+	case *lnwire.Error:
+		return msg.ChanID, true
+	}
+
+	return lnwire.ChannelID{}, false
+}
+
+// isSplicing returns true if the channel with the given ID is being spliced.
+func (p *Brontide) isSplicing(chanID lnwire.ChannelID) bool {
+	p.spliceMtx.RLock()
+	_, ok := p.activeSplices[chanID]
+	p.spliceMtx.RUnlock()
+	return ok
+}
+
+// fetchSpliceChannel returns the channel with the given ID if it can be
+// spliced, meaning that it's active and neither being closed nor spliced
+// already.
+func (p *Brontide) fetchSpliceChannel(
+	chanID lnwire.ChannelID) (*lnwallet.LightningChannel, error) {
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanID]
+	p.activeChanMtx.RUnlock()
+
+	switch {
+	case !ok || channel == nil:
+		return nil, fmt.Errorf("unable to splice channel, "+
+			"ChannelID(%v) is unknown", chanID)
+
+	case p.isSplicing(chanID):
+		return nil, fmt.Errorf("ChannelID(%v) is already being "+
+			"spliced", chanID)
+	}
+
+	if _, ok := p.activeChanCloses[chanID]; ok {
+		return nil, fmt.Errorf("ChannelID(%v) is being closed", chanID)
+	}
+
+	return channel, nil
+}
+
+// spliceForwardingPolicy returns the forwarding policy advertised by the given
+// channel update. The maximum HTLC is used if the update doesn't advertise
+// one.
+func spliceForwardingPolicy(update *lnwire.ChannelUpdate,
+	maxHTLC lnwire.MilliSatoshi) *htlcswitch.ForwardingPolicy {
+
+	if update.MessageFlags.HasMaxHtlc() {
+		maxHTLC = update.HtlcMaximumMsat
+	}
+
+	var inboundFee lnwire.Fee
+	_, err := update.ExtraOpaqueData.ExtractRecords(inboundFee.NewRecord())
+	if err != nil {
+		peerLog.Warnf("Unable to parse inbound fee of ShortChanID(%v): "+
+			"%v", update.ShortChannelID, err)
+		inboundFee = lnwire.Fee{}
+	}
+
+	return &htlcswitch.ForwardingPolicy{
+		MinHTLCOut:    update.HtlcMinimumMsat,
+		MaxHTLC:       maxHTLC,
+		BaseFee:       lnwire.MilliSatoshi(update.BaseFee),
+		FeeRate:       lnwire.MilliSatoshi(update.FeeRate),
+		InboundFee:    inboundFee,
+		TimeLockDelta: uint32(update.TimeLockDelta),
+	}
+}
+
+// fetchSpliceChanUpdate returns our last channel update of the channel being
+// spliced, or nil if it can't be fetched.
+func (p *Brontide) fetchSpliceChanUpdate(
+	channel *lnwallet.LightningChannel) *lnwire.ChannelUpdate {
+
+	if p.cfg.FetchLastChanUpdate == nil {
+		return nil
+	}
+
+	update, err := p.cfg.FetchLastChanUpdate(channel.ShortChanID())
+	if err != nil {
+		peerLog.Warnf("Unable to fetch channel update of "+
+			"ChannelPoint(%v), the splice will use the default "+
+			"routing policy: %v", channel.ChannelPoint(), err)
+		return nil
+	}
+
+	return update
+}
+
+// startSplice removes the link of the channel being spliced from the switch,
+// such that the channel state doesn't change until the splice transaction
+// confirms, and tracks the splicer. The splice is aborted if the negotiation
+// doesn't complete in time.
+func (p *Brontide) startSplice(channel *lnwallet.LightningChannel,
+	splicer *funding.Splicer, update *lnwire.ChannelUpdate) {
+
+	chanPoint := *channel.ChannelPoint()
+	p.cfg.Switch.RemoveLink(splicer.ChanID())
+
+	err := p.cfg.ChanStatusMgr.RequestDisable(chanPoint, false)
+	if err != nil {
+		peerLog.Warnf("Unable to disable ChannelPoint(%v) during "+
+			"splice: %v", chanPoint, err)
+	}
+
+	// We'll remember the policy of the link, so it can be restored if
+	// the splice is aborted.
+	maxHTLC := channel.State().LocalChanCfg.MaxPendingAmount
+	policy := &htlcswitch.ForwardingPolicy{
+		MinHTLCOut:    channel.FwdMinHtlc(),
+		MaxHTLC:       maxHTLC,
+		BaseFee:       p.cfg.RoutingPolicy.BaseFee,
+		FeeRate:       p.cfg.RoutingPolicy.FeeRate,
+		TimeLockDelta: p.cfg.RoutingPolicy.TimeLockDelta,
+	}
+	if update != nil {
+		policy = spliceForwardingPolicy(update, maxHTLC)
+	}
+
+	timeout := time.AfterFunc(spliceNegotiationTimeout, func() {
+		select {
+		case p.spliceTimeouts <- splicer:
+		case <-p.quit:
+		}
+	})
+
+	p.spliceMtx.Lock()
+	p.activeSplices[splicer.ChanID()] = &activeSplice{
+		splicer: splicer,
+		channel: channel,
+		policy:  policy,
+		timeout: timeout,
+	}
+	p.spliceMtx.Unlock()
+}
+
+// handleLocalSpliceReq starts the negotiation of a splice requested locally.
+func (p *Brontide) handleLocalSpliceReq(req *funding.SpliceReq) {
+	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
+
+	channel, err := p.fetchSpliceChannel(chanID)
+	if err != nil {
+		peerLog.Errorf(err.Error())
+		req.Err <- err
+		return
+	}
+
+	_, startingHeight, err := p.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		peerLog.Errorf("unable to obtain best block: %v", err)
+		req.Err <- fmt.Errorf("cannot obtain best block")
+		return
+	}
+
+	update := p.fetchSpliceChanUpdate(channel)
+	splicer, spliceInit, err := funding.NewSplicer(
+		funding.SplicerCfg{
+			Channel:    channel,
+			Peer:       p,
+			Wallet:     p.cfg.Wallet,
+			ChanUpdate: update,
+		}, req, uint32(startingHeight),
+	)
+	if err != nil {
+		peerLog.Errorf("cannot splice channel %v: %v", req.ChanPoint,
+			err)
+		req.Err <- err
+		return
+	}
+
+	p.startSplice(channel, splicer, update)
+	p.queueMsg(spliceInit, nil)
+}
+
+// handleSpliceMsg is called when a message related to a splice is received
+// from the remote peer. We'll use it to advance the splicer of the channel,
+// or to start a new one if the remote peer proposes a splice.
+func (p *Brontide) handleSpliceMsg(msg *spliceMsg) {
+	if spliceInit, ok := msg.msg.(*lnwire.SpliceInit); ok {
+		p.handleRemoteSpliceInit(spliceInit)
+		return
+	}
+
+	p.spliceMtx.RLock()
+	splice, ok := p.activeSplices[msg.cid]
+	p.spliceMtx.RUnlock()
+
+	// If the splice isn't known to us, we'll simply ignore this message.
+	if !ok {
+		return
+	}
+
+	if errMsg, ok := msg.msg.(*lnwire.Error); ok {
+		p.failSplice(splice, fmt.Errorf("remote error: %v",
+			errMsg.Error()), false)
+		return
+	}
+
+	done, err := splice.splicer.ProcessMsg(msg.msg)
+	if err != nil {
+		p.failSplice(splice, err, true)
+		return
+	}
+
+	if !done {
+		return
+	}
+
+	// The splice transaction has been broadcast. The channel stays paused
+	// until it confirms, at which point the chain arbitrator moves it to
+	// the new funding output and hands it back to us.
+	peerLog.Infof("Splice of ChannelID(%v) broadcast, waiting for "+
+		"confirmation", msg.cid)
+
+	splice.timeout.Stop()
+
+	p.spliceMtx.Lock()
+	delete(p.activeSplices, msg.cid)
+	p.spliceMtx.Unlock()
+
+	p.activeChanMtx.Lock()
+	delete(p.activeChannels, msg.cid)
+	p.activeChanMtx.Unlock()
+}
+
+// handleSpliceTimeout aborts the splice of the given splicer, as its
+// negotiation didn't complete in time.
+func (p *Brontide) handleSpliceTimeout(splicer *funding.Splicer) {
+	p.spliceMtx.RLock()
+	splice, ok := p.activeSplices[splicer.ChanID()]
+	p.spliceMtx.RUnlock()
+
+	// The splice may have completed or failed meanwhile.
+	if !ok || splice.splicer != splicer {
+		return
+	}
+
+	p.failSplice(splice, fmt.Errorf("splice negotiation timed out "+
+		"after %v", spliceNegotiationTimeout), true)
+}
+
+// handleRemoteSpliceInit handles a splice proposed by the remote peer.
+func (p *Brontide) handleRemoteSpliceInit(msg *lnwire.SpliceInit) {
+	channel, err := p.fetchSpliceChannel(msg.ChanID)
+	if err == nil {
+		var (
+			splicer   *funding.Splicer
+			spliceAck *lnwire.SpliceAck
+		)
+		update := p.fetchSpliceChanUpdate(channel)
+		splicer, spliceAck, err = funding.NewSpliceResponder(
+			funding.SplicerCfg{
+				Channel:    channel,
+				Peer:       p,
+				Wallet:     p.cfg.Wallet,
+				ChanUpdate: update,
+			}, msg,
+		)
+		if err == nil {
+			p.startSplice(channel, splicer, update)
+			p.queueMsg(spliceAck, nil)
+			return
+		}
+	}
+
+	// The remote peer is waiting for our splice_ack, so it will handle
+	// the error as part of its splice rather than failing the channel.
+	peerLog.Errorf("Rejecting splice of ChannelID(%v): %v", msg.ChanID,
+		err)
+
+	p.queueMsg(&lnwire.Error{
+		ChanID: msg.ChanID,
+		Data:   lnwire.ErrorData(err.Error()),
+	}, nil)
+}
+
+// failSplice aborts the splice after an error. Unless we already signed the
+// splice transaction, the pending splice is dropped and the link of the
+// channel is added back to the switch. Otherwise the channel stays paused, as
+// the splice transaction may still confirm.
+func (p *Brontide) failSplice(splice *activeSplice, err error,
+	notifyRemote bool) {
+
+	splicer := splice.splicer
+	chanID := splicer.ChanID()
+
+	splice.timeout.Stop()
+	splicer.Fail(err)
+
+	p.spliceMtx.Lock()
+	delete(p.activeSplices, chanID)
+	p.spliceMtx.Unlock()
+
+	if notifyRemote {
+		p.queueMsg(&lnwire.Error{
+			ChanID: chanID,
+			Data:   lnwire.ErrorData(err.Error()),
+		}, nil)
+	}
+
+	if splicer.Signed() {
+		peerLog.Warnf("ChannelID(%v) stays paused, as the failed "+
+			"splice may still confirm", chanID)
+
+		p.activeChanMtx.Lock()
+		delete(p.activeChannels, chanID)
+		p.activeChanMtx.Unlock()
+
+		return
+	}
+
+	p.resumeSplicedChannel(splice)
+}
+
+// restoreLink adds a link for the channel of an aborted splice to the switch.
+// The channel state is restored from disk, as the splice didn't change it.
+func (p *Brontide) restoreLink(
+	splice *activeSplice) (*lnwallet.LightningChannel, error) {
+
+	chanPoint := splice.channel.ChannelPoint()
+	lnChan, err := lnwallet.NewLightningChannel(
+		p.cfg.Signer, splice.channel.State(), p.cfg.SigPool,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	chainEvents, err := p.cfg.ChainArb.SubscribeChannelEvents(*chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.addLink(chanPoint, lnChan, splice.policy, chainEvents, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return lnChan, nil
+}
+
+// resumeSplicedChannel adds the link of a channel whose splice was aborted
+// back to the switch and re-enables the channel. If that fails, we'll
+// disconnect the peer to restore the channel on reconnection.
+func (p *Brontide) resumeSplicedChannel(splice *activeSplice) {
+	chanPoint := splice.channel.ChannelPoint()
+
+	lnChan, err := p.restoreLink(splice)
+	if err != nil {
+		peerLog.Errorf("Unable to resume ChannelPoint(%v) after "+
+			"failed splice, disconnecting peer %v: %v", chanPoint,
+			p, err)
+
+		go func() {
+			err := p.cfg.DisconnectPeer(p.IdentityKey())
+			if err != nil {
+				peerLog.Errorf("Unable to disconnect peer "+
+					"%v after failed splice: %v", p, err)
+			}
+		}()
+
+		return
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+	p.activeChanMtx.Lock()
+	p.activeChannels[chanID] = lnChan
+	p.activeChanMtx.Unlock()
+
+	peerLog.Infof("Resumed ChannelPoint(%v) after failed splice",
+		chanPoint)
+
+	err = p.cfg.ChanStatusMgr.RequestEnable(*chanPoint, false)
+	if err != nil {
+		peerLog.Warnf("Unable to enable ChannelPoint(%v) after "+
+			"failed splice: %v", chanPoint, err)
+	}
+}
+
+// failActiveSplices aborts all splices that are being negotiated, as the
+// peer is exiting.
+func (p *Brontide) failActiveSplices() {
+	p.spliceMtx.Lock()
+	defer p.spliceMtx.Unlock()
+
+	for chanID, splice := range p.activeSplices {
+		splice.timeout.Stop()
+		splice.splicer.Fail(lnpeer.ErrPeerExiting)
+		delete(p.activeSplices, chanID)
+	}
+}
+
+// HandleLocalSpliceReq accepts a *funding.SpliceReq and passes it onto the
+// channelManager goroutine, which will shut down the link and negotiate the
+// splice with the peer.
+func (p *Brontide) HandleLocalSpliceReq(req *funding.SpliceReq) {
+	select {
+	case p.localSpliceReqs <- req:
+		peerLog.Infof("Local splice request delivered to peer: %x",
+			p.PubKey())
+	case <-p.quit:
+		peerLog.Infof("Unable to deliver local splice request to "+
+			"peer %x", p.PubKey())
+		req.Err <- lnpeer.ErrPeerExiting
+	}
+}
+
+// HandleSplicedChannel replaces the channel spending the old funding outpoint
+// with the channel moved to the funding output of a confirmed splice
+// transaction, and adds a link for it to the switch.
+func (p *Brontide) HandleSplicedChannel(oldChanPoint wire.OutPoint,
+	newChan *channeldb.OpenChannel) error {
+
+	oldChanID := lnwire.NewChanIDFromOutPoint(&oldChanPoint)
+
+	p.activeChanMtx.Lock()
+	delete(p.activeChannels, oldChanID)
+	p.activeChanMtx.Unlock()
+
+	return p.AddNewChannel(newChan, p.quit)
+}
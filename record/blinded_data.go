@@ -0,0 +1,307 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// blindedPaddingType is the type of the padding record that can be
+	// used to make all encrypted payloads of a blinded route the same
+	// length.
+	blindedPaddingType tlv.Type = 1
+
+	// blindedScidType is the type of the record that carries the short
+	// channel ID of the channel to the next hop.
+	blindedScidType tlv.Type = 2
+
+	// blindedNextNodeType is the type of the record that carries the node
+	// ID of the next hop.
+	blindedNextNodeType tlv.Type = 4
+
+	// blindedPathIDType is the type of the record that carries the path ID
+	// the recipient set for itself.
+	blindedPathIDType tlv.Type = 6
+
+	// blindedNextBlindingType is the type of the record that overrides the
+	// blinding point for the next hop.
+	blindedNextBlindingType tlv.Type = 8
+
+	// blindedRelayType is the type of the record that carries the fees and
+	// cltv delta the hop charges for forwarding.
+	blindedRelayType tlv.Type = 10
+
+	// blindedConstraintsType is the type of the record that carries the
+	// constraints an HTLC must satisfy to be forwarded.
+	blindedConstraintsType tlv.Type = 12
+)
+
+// PaymentRelayInfo describes the fees and cltv delta a hop inside of a blinded
+// route charges for forwarding an HTLC.
+type PaymentRelayInfo struct {
+	// CltvExpiryDelta is the expiry delta of the hop.
+	CltvExpiryDelta uint16
+
+	// FeeRate is the proportional fee of the hop in millionths.
+	FeeRate uint32
+
+	// BaseFee is the base fee of the hop in millisatoshis.
+	BaseFee uint32
+}
+
+// PaymentConstraints describes the constraints an HTLC forwarded inside of a
+// blinded route must satisfy at a hop.
+type PaymentConstraints struct {
+	// MaxCltvExpiry is the maximum absolute expiry height of the HTLC,
+	// which bounds the lifetime of the blinded route.
+	MaxCltvExpiry uint32
+
+	// HtlcMinimumMsat is the minimum amount of the HTLC.
+	HtlcMinimumMsat lnwire.MilliSatoshi
+}
+
+// BlindedRouteData is the data the creator of a blinded route encrypts for
+// each of the hops. It is delivered to the hop in the encrypted_recipient_data
+// record of the onion payload.
+type BlindedRouteData struct {
+	// ShortChannelID is the channel the HTLC should be forwarded over.
+	// This is nil for the final hop.
+	ShortChannelID *lnwire.ShortChannelID
+
+	// NextNodeID is the node ID of the next hop. This is only used for
+	// onion messages and is otherwise optional.
+	NextNodeID *btcec.PublicKey
+
+	// PathID is an identifier the recipient can use to verify that the
+	// blinded route was created by itself. This is only set for the final
+	// hop.
+	PathID []byte
+
+	// NextBlindingOverride, if set, replaces the blinding point derived
+	// for the next hop.
+	NextBlindingOverride *btcec.PublicKey
+
+	// RelayInfo is the forwarding policy of the hop. This is nil for the
+	// final hop.
+	RelayInfo *PaymentRelayInfo
+
+	// Constraints are the constraints the HTLC must satisfy at this hop.
+	Constraints *PaymentConstraints
+}
+
+// EncodeBlindedRouteData serializes the passed blinded route data as a TLV
+// stream.
+func EncodeBlindedRouteData(data *BlindedRouteData) ([]byte, error) {
+	var (
+		records     []tlv.Record
+		scid        uint64
+		relay       []byte
+		constraints []byte
+	)
+
+	if data.ShortChannelID != nil {
+		scid = data.ShortChannelID.ToUint64()
+		records = append(
+			records, tlv.MakePrimitiveRecord(blindedScidType, &scid),
+		)
+	}
+
+	if data.NextNodeID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedNextNodeType, &data.NextNodeID,
+		))
+	}
+
+	if len(data.PathID) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedPathIDType, &data.PathID,
+		))
+	}
+
+	if data.NextBlindingOverride != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedNextBlindingType, &data.NextBlindingOverride,
+		))
+	}
+
+	if data.RelayInfo != nil {
+		var (
+			b   bytes.Buffer
+			buf [8]byte
+		)
+		info := data.RelayInfo
+		err := tlv.EUint16T(&b, info.CltvExpiryDelta, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if err := tlv.EUint32T(&b, info.FeeRate, &buf); err != nil {
+			return nil, err
+		}
+		if err := tlv.ETUint32T(&b, info.BaseFee, &buf); err != nil {
+			return nil, err
+		}
+
+		relay = b.Bytes()
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedRelayType, &relay,
+		))
+	}
+
+	if data.Constraints != nil {
+		var (
+			b   bytes.Buffer
+			buf [8]byte
+		)
+		c := data.Constraints
+		if err := tlv.EUint32T(&b, c.MaxCltvExpiry, &buf); err != nil {
+			return nil, err
+		}
+		err := tlv.ETUint64T(&b, uint64(c.HtlcMinimumMsat), &buf)
+		if err != nil {
+			return nil, err
+		}
+
+		constraints = b.Bytes()
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedConstraintsType, &constraints,
+		))
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeBlindedRouteData parses the blinded route data from the passed
+// reader. Unknown even types cause the data to be rejected.
+func DecodeBlindedRouteData(r io.Reader) (*BlindedRouteData, error) {
+	var (
+		data         BlindedRouteData
+		padding      []byte
+		scid         uint64
+		nextNode     *btcec.PublicKey
+		nextBlinding *btcec.PublicKey
+		relay        []byte
+		constraints  []byte
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(blindedPaddingType, &padding),
+		tlv.MakePrimitiveRecord(blindedScidType, &scid),
+		tlv.MakePrimitiveRecord(blindedNextNodeType, &nextNode),
+		tlv.MakePrimitiveRecord(blindedPathIDType, &data.PathID),
+		tlv.MakePrimitiveRecord(blindedNextBlindingType, &nextBlinding),
+		tlv.MakePrimitiveRecord(blindedRelayType, &relay),
+		tlv.MakePrimitiveRecord(blindedConstraintsType, &constraints),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for t, val := range parsedTypes {
+		if val != nil && t%2 == 0 {
+			return nil, fmt.Errorf("unknown required type %d in "+
+				"blinded route data", t)
+		}
+	}
+
+	if _, ok := parsedTypes[blindedScidType]; ok {
+		chanID := lnwire.NewShortChanIDFromInt(scid)
+		data.ShortChannelID = &chanID
+	}
+	if _, ok := parsedTypes[blindedNextNodeType]; ok {
+		data.NextNodeID = nextNode
+	}
+	if _, ok := parsedTypes[blindedNextBlindingType]; ok {
+		data.NextBlindingOverride = nextBlinding
+	}
+
+	if _, ok := parsedTypes[blindedRelayType]; ok {
+		data.RelayInfo, err = decodeRelayInfo(relay)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := parsedTypes[blindedConstraintsType]; ok {
+		data.Constraints, err = decodeConstraints(constraints)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &data, nil
+}
+
+// decodeRelayInfo parses the payment relay info from the raw record value.
+func decodeRelayInfo(b []byte) (*PaymentRelayInfo, error) {
+	// The record consists of a 2 byte cltv delta, a 4 byte fee rate and a
+	// truncated 4 byte base fee.
+	if len(b) < 6 || len(b) > 10 {
+		return nil, fmt.Errorf("invalid payment relay length: %d",
+			len(b))
+	}
+
+	var (
+		info PaymentRelayInfo
+		buf  [8]byte
+		r    = bytes.NewReader(b)
+	)
+	if err := tlv.DUint16(r, &info.CltvExpiryDelta, &buf, 2); err != nil {
+		return nil, err
+	}
+	if err := tlv.DUint32(r, &info.FeeRate, &buf, 4); err != nil {
+		return nil, err
+	}
+	err := tlv.DTUint32(r, &info.BaseFee, &buf, uint64(len(b)-6))
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// decodeConstraints parses the payment constraints from the raw record value.
+func decodeConstraints(b []byte) (*PaymentConstraints, error) {
+	// The record consists of a 4 byte max cltv expiry and a truncated 8
+	// byte minimum htlc amount.
+	if len(b) < 4 || len(b) > 12 {
+		return nil, fmt.Errorf("invalid payment constraints length: "+
+			"%d", len(b))
+	}
+
+	var (
+		c       PaymentConstraints
+		htlcMin uint64
+		buf     [8]byte
+		r       = bytes.NewReader(b)
+	)
+	if err := tlv.DUint32(r, &c.MaxCltvExpiry, &buf, 4); err != nil {
+		return nil, err
+	}
+	err := tlv.DTUint64(r, &htlcMin, &buf, uint64(len(b)-4))
+	if err != nil {
+		return nil, err
+	}
+	c.HtlcMinimumMsat = lnwire.MilliSatoshi(htlcMin)
+
+	return &c, nil
+}
@@ -0,0 +1,64 @@
+package record_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlindedRouteDataEncodeDecode tests that blinded route data can be
+// serialized and parsed again, and that unknown even types are rejected.
+func TestBlindedRouteDataEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	scid := lnwire.NewShortChanIDFromInt(12345)
+	testCases := []*record.BlindedRouteData{
+		{
+			ShortChannelID: &scid,
+			RelayInfo: &record.PaymentRelayInfo{
+				CltvExpiryDelta: 40,
+				FeeRate:         100,
+				BaseFee:         1000,
+			},
+			Constraints: &record.PaymentConstraints{
+				MaxCltvExpiry:   800_000,
+				HtlcMinimumMsat: 1,
+			},
+			NextBlindingOverride: priv.PubKey(),
+		},
+		{
+			PathID: []byte{1, 2, 3},
+			Constraints: &record.PaymentConstraints{
+				MaxCltvExpiry: 800_000,
+			},
+		},
+	}
+
+	for _, data := range testCases {
+		b, err := record.EncodeBlindedRouteData(data)
+		require.NoError(t, err)
+
+		decoded, err := record.DecodeBlindedRouteData(
+			bytes.NewReader(b),
+		)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+	}
+
+	// An unknown even type must cause the data to be rejected.
+	var unknown bytes.Buffer
+	var val uint64 = 1
+	stream := tlv.MustNewStream(tlv.MakePrimitiveRecord(14, &val))
+	require.NoError(t, stream.Encode(&unknown))
+
+	_, err = record.DecodeBlindedRouteData(&unknown)
+	require.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package record
 
 import (
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/lightningnetwork/lnd/tlv"
 )
 
@@ -16,6 +17,36 @@ const (
 	// NextHopOnionType is the type used in the onion to reference the ID
 	// of the next hop.
 	NextHopOnionType tlv.Type = 6
+
+	// EncryptedDataOnionType is the type used in the onion to reference
+	// the encrypted_recipient_data of a hop inside of a blinded route.
+	EncryptedDataOnionType tlv.Type = 10
+
+	// BlindingPointOnionType is the type used in the onion to reference
+	// the blinding point of the introduction node of a blinded route.
+	BlindingPointOnionType tlv.Type = 12
+
+	// OutgoingNodeIDOnionType is the type used in a trampoline onion to
+	// reference the node ID of the next trampoline node or recipient.
+	OutgoingNodeIDOnionType tlv.Type = 14
+
+	// TotalAmtMsatBlindedType is the type used in the onion to reference
+	// the total amount of a payment to a blinded route.
+	TotalAmtMsatBlindedType tlv.Type = 18
+
+	// TrampolineOnionType is the type used in the onion to reference the
+	// trampoline onion included in the payload of a trampoline node.
+	TrampolineOnionType tlv.Type = 20
+
+	// InvoiceFeaturesOnionType is the type used in a trampoline onion to
+	// reference the invoice features of a recipient that doesn't support
+	// trampoline payments.
+	InvoiceFeaturesOnionType tlv.Type = 66097
+
+	// InvoiceRoutingInfoOnionType is the type used in a trampoline onion
+	// to reference the invoice route hints of a recipient that doesn't
+	// support trampoline payments.
+	InvoiceRoutingInfoOnionType tlv.Type = 66099
 )
 
 // NewAmtToFwdRecord creates a tlv.Record that encodes the amount_to_forward
@@ -45,3 +76,38 @@ func NewLockTimeRecord(lockTime *uint32) tlv.Record {
 func NewNextHopIDRecord(cid *uint64) tlv.Record {
 	return tlv.MakePrimitiveRecord(NextHopOnionType, cid)
 }
+
+// NewEncryptedDataRecord creates a tlv.Record that encodes the
+// encrypted_recipient_data (type 10) for an onion payload.
+func NewEncryptedDataRecord(data *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(EncryptedDataOnionType, data)
+}
+
+// NewBlindingPointRecord creates a tlv.Record that encodes the
+// current_blinding_point (type 12) for an onion payload.
+func NewBlindingPointRecord(point **btcec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(BlindingPointOnionType, point)
+}
+
+// NewTotalAmtMsatBlindedRecord creates a tlv.Record that encodes the
+// total_amount_msat (type 18) for an onion payload.
+func NewTotalAmtMsatBlindedRecord(amt *uint64) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		TotalAmtMsatBlindedType, amt, func() uint64 {
+			return tlv.SizeTUint64(*amt)
+		},
+		tlv.ETUint64, tlv.DTUint64,
+	)
+}
+
+// NewOutgoingNodeIDRecord creates a tlv.Record that encodes the
+// outgoing_node_id (type 14) for a trampoline onion payload.
+func NewOutgoingNodeIDRecord(nodeID **btcec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(OutgoingNodeIDOnionType, nodeID)
+}
+
+// NewTrampolineOnionRecord creates a tlv.Record that encodes the
+// trampoline_onion_packet (type 20) for an onion payload.
+func NewTrampolineOnionRecord(onion *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(TrampolineOnionType, onion)
+}
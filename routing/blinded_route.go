@@ -0,0 +1,113 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+var (
+	// ErrSelfIntroduction is returned when a route to a blinded path is
+	// requested for which we are the introduction node.
+	ErrSelfIntroduction = errors.New("cannot pay blinded path with our " +
+		"node as introduction node")
+)
+
+// blindedPathFee returns the aggregated fee a blinded payment path charges to
+// deliver the passed amount to the recipient. The fee is rounded up, so the
+// hops of the path never receive less than they expect.
+func blindedPathFee(path *zpay32.BlindedPaymentPath,
+	amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	propFee := (uint64(amt)*uint64(path.FeeRate) + 999999) / 1000000
+
+	return lnwire.MilliSatoshi(uint64(path.FeeBaseMsat) + propFee)
+}
+
+// FindBlindedRoute attempts to find a route that delivers the passed amount to
+// the recipient of a blinded payment path. A route is found to the
+// introduction node of the path, which receives the amount plus the
+// aggregated fees of the path. The hops of the blinded path are then appended
+// to that route.
+func (r *ChannelRouter) FindBlindedRoute(amt lnwire.MilliSatoshi,
+	path *zpay32.BlindedPaymentPath,
+	restrictions *RestrictParams) (*route.Route, error) {
+
+	blindedPath := path.Hops
+	if blindedPath == nil || len(blindedPath.BlindedHops) == 0 {
+		return nil, fmt.Errorf("blinded path has no hops")
+	}
+
+	introNode := route.NewVertex(blindedPath.IntroductionPoint)
+	if introNode == r.selfNode.PubKeyBytes {
+		return nil, ErrSelfIntroduction
+	}
+
+	if uint64(amt) < path.HTLCMinMsat || uint64(amt) > path.HTLCMaxMsat {
+		return nil, fmt.Errorf("amount %v outside of blinded path "+
+			"range [%v, %v]", amt, path.HTLCMinMsat,
+			path.HTLCMaxMsat)
+	}
+
+	// The fees of the blinded path are paid to the introduction node, so
+	// they count towards the fee limit of the payment.
+	fee := blindedPathFee(path, amt)
+	if fee > restrictions.FeeLimit {
+		return nil, errNoPathFound
+	}
+	introRestrictions := *restrictions
+	introRestrictions.FeeLimit -= fee
+
+	// The route to the introduction node treats it as the final hop, with
+	// the aggregated cltv delta of the blinded path as its final cltv
+	// delta.
+	rt, err := r.FindRoute(
+		r.selfNode.PubKeyBytes, introNode, amt+fee,
+		&introRestrictions, nil, nil, path.CltvExpiryDelta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The final cltv expiry of the recipient is the cltv expiry of the
+	// introduction node minus the aggregated delta.
+	finalCltv := rt.TotalTimeLock
+	if len(rt.Hops) > 0 {
+		finalCltv = rt.Hops[len(rt.Hops)-1].OutgoingTimeLock
+	}
+	finalCltv -= uint32(path.CltvExpiryDelta)
+
+	// The last hop of the route becomes the introduction node. All hops of
+	// the blinded path forward the full amount, which attributes the fees
+	// of the path to the introduction node.
+	hops := rt.Hops
+	numBlindedHops := len(blindedPath.BlindedHops)
+	for i, blindedHop := range blindedPath.BlindedHops {
+		hop := &route.Hop{
+			PubKeyBytes:      route.NewVertex(blindedHop.BlindedNodePub),
+			AmtToForward:     amt,
+			OutgoingTimeLock: finalCltv,
+			EncryptedData:    blindedHop.CipherText,
+		}
+
+		if i == 0 {
+			hop.PubKeyBytes = introNode
+			hop.ChannelID = hops[len(hops)-1].ChannelID
+			hop.BlindingPoint = blindedPath.BlindingPoint
+			hops = hops[:len(hops)-1]
+		}
+
+		if i == numBlindedHops-1 {
+			hop.TotalAmtMsat = amt
+		}
+
+		hops = append(hops, hop)
+	}
+
+	return route.NewRouteFromHops(
+		rt.TotalAmount, rt.TotalTimeLock, rt.SourcePubKey, hops,
+	)
+}
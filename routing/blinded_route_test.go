@@ -0,0 +1,106 @@
+package routing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFindBlindedRoute tests that a route to a blinded payment path is routed
+// to its introduction node and carries the blinded hops of the path.
+func TestFindBlindedRoute(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtxFromFile(
+		startingBlockHeight, basicGraphFilePath,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	// Sophon is the introduction node of a blinded path to a recipient
+	// behind it.
+	sophon := ctx.aliases["sophon"]
+	introPub, err := btcec.ParsePubKey(sophon[:], btcec.S256())
+	require.NoError(t, err)
+	recipientKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	hops, err := blindedpath.BuildBlindedPath(
+		sessionKey, []*blindedpath.HopInfo{{
+			NodePub:   introPub,
+			PlainText: []byte{1},
+		}, {
+			NodePub:   recipientKey.PubKey(),
+			PlainText: []byte{2},
+		}},
+	)
+	require.NoError(t, err)
+
+	path := &zpay32.BlindedPaymentPath{
+		FeeBaseMsat:     1000,
+		FeeRate:         1,
+		CltvExpiryDelta: 100,
+		HTLCMinMsat:     1,
+		HTLCMaxMsat:     uint64(lnwire.NewMSatFromSatoshis(1000)),
+		Features:        lnwire.EmptyFeatureVector(),
+		Hops:            hops,
+	}
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	restrictions := &RestrictParams{
+		FeeLimit:          lnwire.NewMSatFromSatoshis(10),
+		ProbabilitySource: noProbabilitySource,
+		CltvLimit:         math.MaxUint32,
+	}
+
+	rt, err := ctx.router.FindBlindedRoute(paymentAmt, path, restrictions)
+	require.NoError(t, err)
+
+	// The route ends with the introduction node followed by the blinded
+	// recipient.
+	require.Len(t, rt.Hops, 3)
+	intro := rt.Hops[1]
+	require.Equal(t, ctx.aliases["sophon"], intro.PubKeyBytes)
+	require.Equal(t, hops.BlindingPoint, intro.BlindingPoint)
+	require.Equal(t, hops.BlindedHops[0].CipherText, intro.EncryptedData)
+	require.Zero(t, intro.TotalAmtMsat)
+
+	final := rt.Hops[2]
+	require.Equal(
+		t, route.NewVertex(hops.BlindedHops[1].BlindedNodePub),
+		final.PubKeyBytes,
+	)
+	require.Equal(t, paymentAmt, final.AmtToForward)
+	require.Equal(t, paymentAmt, final.TotalAmtMsat)
+	require.Equal(t, paymentAmt, rt.ReceiverAmt())
+	require.Equal(t, uint32(startingBlockHeight), final.OutgoingTimeLock)
+
+	// The fees of the blinded path are attributed to the introduction
+	// node.
+	require.Equal(t, blindedPathFee(path, paymentAmt), rt.HopFee(1))
+
+	// The route must be convertible into an onion.
+	_, err = rt.ToSphinxPath()
+	require.NoError(t, err)
+
+	// A fee limit that doesn't cover the fees of the blinded path fails.
+	restrictions.FeeLimit = 1000
+	_, err = ctx.router.FindBlindedRoute(paymentAmt, path, restrictions)
+	require.Error(t, err)
+
+	// Amounts above the maximum of the path are rejected.
+	restrictions.FeeLimit = lnwire.NewMSatFromSatoshis(10)
+	_, err = ctx.router.FindBlindedRoute(
+		lnwire.NewMSatFromSatoshis(2000), path, restrictions,
+	)
+	require.Error(t, err)
+}
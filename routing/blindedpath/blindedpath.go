@@ -0,0 +1,400 @@
+package blindedpath
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// MaxNumHops is the maximum number of hops of a serialized blinded
+	// path.
+	MaxNumHops = 20
+)
+
+var (
+	// ErrNoHops is returned when a blinded path is requested for an empty
+	// list of hops.
+	ErrNoHops = errors.New("blinded path requires at least one hop")
+
+	// ErrInvalidPoint is returned when a blinding point or public key is
+	// not on the curve.
+	ErrInvalidPoint = errors.New("point not on curve")
+
+	// blindedNodeIDKey is the HMAC key used to derive the tweak of a
+	// blinded node ID from the shared secret.
+	blindedNodeIDKey = []byte("blinded_node_id")
+
+	// rhoKey is the HMAC key used to derive the key used to encrypt the
+	// recipient data from the shared secret.
+	rhoKey = []byte("rho")
+
+	// zeroNonce is the nonce used to encrypt the recipient data. As each
+	// encryption key is only ever used once, a static nonce is safe.
+	zeroNonce [chacha20poly1305.NonceSize]byte
+)
+
+// HopInfo contains the node ID and the plaintext recipient data of a hop in a
+// blinded path that is to be built.
+type HopInfo struct {
+	// NodePub is the real node ID of the hop.
+	NodePub *btcec.PublicKey
+
+	// PlainText is the serialized recipient data for the hop.
+	PlainText []byte
+}
+
+// BlindedHop is a hop of a blinded path. Its real node ID is hidden behind a
+// blinded node ID and its recipient data is encrypted so that only the hop
+// itself can read it.
+type BlindedHop struct {
+	// BlindedNodePub is the blinded node ID of the hop.
+	BlindedNodePub *btcec.PublicKey
+
+	// CipherText is the encrypted recipient data for the hop.
+	CipherText []byte
+}
+
+// BlindedPath is a route towards a recipient in which all hops after the
+// introduction node are blinded.
+type BlindedPath struct {
+	// IntroductionPoint is the real node ID of the first hop of the path.
+	IntroductionPoint *btcec.PublicKey
+
+	// BlindingPoint is the ephemeral public key the introduction node
+	// needs to decrypt its recipient data.
+	BlindingPoint *btcec.PublicKey
+
+	// BlindedHops are the blinded hops of the path, including the
+	// introduction node and the recipient.
+	BlindedHops []*BlindedHop
+}
+
+// Encode serializes the blinded path:
+//
+//  introduction_point (33) | blinding_point (33) | num_hops (1) |
+//  num_hops * [blinded_node_pub (33) | cipher_text_len (2) | cipher_text]
+func (b *BlindedPath) Encode(w io.Writer) error {
+	if len(b.BlindedHops) == 0 {
+		return ErrNoHops
+	}
+	if len(b.BlindedHops) > MaxNumHops {
+		return fmt.Errorf("blinded path has too many hops: %d",
+			len(b.BlindedHops))
+	}
+
+	_, err := w.Write(b.IntroductionPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b.BlindingPoint.SerializeCompressed()); err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte{uint8(len(b.BlindedHops))}); err != nil {
+		return err
+	}
+	for _, hop := range b.BlindedHops {
+		_, err := w.Write(hop.BlindedNodePub.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		if len(hop.CipherText) > math.MaxUint16 {
+			return fmt.Errorf("cipher text too long: %d",
+				len(hop.CipherText))
+		}
+
+		var l [2]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(hop.CipherText)))
+		if _, err := w.Write(l[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(hop.CipherText); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeBlindedPath parses a blinded path that was serialized with Encode.
+func DecodeBlindedPath(r io.Reader) (*BlindedPath, error) {
+	introPoint, err := readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+	blindingPoint, err := readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var numHops [1]byte
+	if _, err := io.ReadFull(r, numHops[:]); err != nil {
+		return nil, err
+	}
+	if numHops[0] == 0 || numHops[0] > MaxNumHops {
+		return nil, fmt.Errorf("invalid number of blinded hops: %d",
+			numHops[0])
+	}
+
+	path := &BlindedPath{
+		IntroductionPoint: introPoint,
+		BlindingPoint:     blindingPoint,
+		BlindedHops:       make([]*BlindedHop, numHops[0]),
+	}
+	for i := range path.BlindedHops {
+		blindedPub, err := readPubKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var l [2]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return nil, err
+		}
+		cipherText := make([]byte, binary.BigEndian.Uint16(l[:]))
+		if _, err := io.ReadFull(r, cipherText); err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &BlindedHop{
+			BlindedNodePub: blindedPub,
+			CipherText:     cipherText,
+		}
+	}
+
+	return path, nil
+}
+
+// readPubKey reads a compressed public key from the passed reader.
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var b [33]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(b[:], btcec.S256())
+}
+
+// BuildBlindedPath creates a blinded path along the passed hops, using the
+// session key as the first ephemeral private key. The recipient data of each
+// hop is encrypted to the hop as described in BOLT 04:
+//
+//  ss(i) = SHA256(e(i) * N(i))
+//  B(i) = HMAC256("blinded_node_id", ss(i)) * N(i)
+//  rho(i) = HMAC256("rho", ss(i))
+//  e(i+1) = SHA256(E(i) || ss(i)) * e(i)
+func BuildBlindedPath(sessionKey *btcec.PrivateKey,
+	hops []*HopInfo) (*BlindedPath, error) {
+
+	if len(hops) == 0 {
+		return nil, ErrNoHops
+	}
+
+	path := &BlindedPath{
+		IntroductionPoint: hops[0].NodePub,
+		BlindingPoint:     sessionKey.PubKey(),
+		BlindedHops:       make([]*BlindedHop, len(hops)),
+	}
+
+	ephemPriv := sessionKey
+	for i, hop := range hops {
+		ephemECDH := &sphinx.PrivKeyECDH{PrivKey: ephemPriv}
+		sharedSecret, err := ephemECDH.ECDH(hop.NodePub)
+		if err != nil {
+			return nil, err
+		}
+
+		cipherText, err := encryptData(sharedSecret, hop.PlainText)
+		if err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &BlindedHop{
+			BlindedNodePub: tweakPubKey(
+				hop.NodePub, hmac256(blindedNodeIDKey, sharedSecret),
+			),
+			CipherText: cipherText,
+		}
+
+		// Derive the ephemeral key of the next hop.
+		blindingFactor := computeBlindingFactor(
+			ephemPriv.PubKey(), sharedSecret,
+		)
+		ephemPriv = tweakPrivKey(ephemPriv, blindingFactor)
+	}
+
+	return path, nil
+}
+
+// DecryptBlindedHopData decrypts the recipient data that was encrypted to our
+// node using the passed ephemeral blinding point.
+func DecryptBlindedHopData(nodeKey sphinx.SingleKeyECDH,
+	blindingPoint *btcec.PublicKey, cipherText []byte) ([]byte, error) {
+
+	sharedSecret, err := sharedSecret(nodeKey, blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptData(sharedSecret, cipherText)
+}
+
+// NextBlindingPoint derives the blinding point of the next hop from our
+// current one:
+//
+//  E(i+1) = SHA256(E(i) || ss(i)) * E(i)
+func NextBlindingPoint(nodeKey sphinx.SingleKeyECDH,
+	blindingPoint *btcec.PublicKey) (*btcec.PublicKey, error) {
+
+	sharedSecret, err := sharedSecret(nodeKey, blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	blindingFactor := computeBlindingFactor(blindingPoint, sharedSecret)
+
+	return tweakPubKey(blindingPoint, blindingFactor), nil
+}
+
+// BlindedECDH is a SingleKeyECDH that performs ECDH operations with the
+// blinded private key of our node inside of a blinded route. This can be used
+// to process onion packets that were encrypted to our blinded node ID.
+type BlindedECDH struct {
+	nodeKey sphinx.SingleKeyECDH
+	tweak   [sha256.Size]byte
+}
+
+// A compile time check to ensure BlindedECDH implements the SingleKeyECDH
+// interface.
+var _ sphinx.SingleKeyECDH = (*BlindedECDH)(nil)
+
+// NewBlindedECDH returns the SingleKeyECDH of our blinded node ID derived from
+// the passed blinding point.
+func NewBlindedECDH(nodeKey sphinx.SingleKeyECDH,
+	blindingPoint *btcec.PublicKey) (*BlindedECDH, error) {
+
+	sharedSecret, err := sharedSecret(nodeKey, blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlindedECDH{
+		nodeKey: nodeKey,
+		tweak:   hmac256(blindedNodeIDKey, sharedSecret),
+	}, nil
+}
+
+// PubKey returns our blinded node ID.
+//
+// NOTE: This is part of the SingleKeyECDH interface.
+func (b *BlindedECDH) PubKey() *btcec.PublicKey {
+	return tweakPubKey(b.nodeKey.PubKey(), b.tweak)
+}
+
+// ECDH performs an ECDH operation with our blinded private key. As the blinded
+// private key is our node key multiplied by the tweak, we can multiply the
+// remote public key by the tweak instead, which doesn't require access to our
+// node's private key.
+//
+// NOTE: This is part of the SingleKeyECDH interface.
+func (b *BlindedECDH) ECDH(pub *btcec.PublicKey) ([32]byte, error) {
+	return b.nodeKey.ECDH(tweakPubKey(pub, b.tweak))
+}
+
+// sharedSecret computes the shared secret with the blinding point, making sure
+// the point is valid first.
+func sharedSecret(nodeKey sphinx.SingleKeyECDH,
+	blindingPoint *btcec.PublicKey) ([32]byte, error) {
+
+	if blindingPoint == nil ||
+		!btcec.S256().IsOnCurve(blindingPoint.X, blindingPoint.Y) {
+
+		return [32]byte{}, ErrInvalidPoint
+	}
+
+	return nodeKey.ECDH(blindingPoint)
+}
+
+// encryptData encrypts the recipient data with the rho key derived from the
+// shared secret.
+func encryptData(sharedSecret [32]byte, plainText []byte) ([]byte, error) {
+	rho := hmac256(rhoKey, sharedSecret)
+	aead, err := chacha20poly1305.New(rho[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nil, zeroNonce[:], plainText, nil), nil
+}
+
+// decryptData decrypts the recipient data with the rho key derived from the
+// shared secret.
+func decryptData(sharedSecret [32]byte, cipherText []byte) ([]byte, error) {
+	rho := hmac256(rhoKey, sharedSecret)
+	aead, err := chacha20poly1305.New(rho[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, zeroNonce[:], cipherText, nil)
+}
+
+// computeBlindingFactor computes the factor the ephemeral key is tweaked with
+// for the next hop.
+func computeBlindingFactor(ephemPub *btcec.PublicKey,
+	sharedSecret [32]byte) [sha256.Size]byte {
+
+	h := sha256.New()
+	h.Write(ephemPub.SerializeCompressed())
+	h.Write(sharedSecret[:])
+
+	var factor [sha256.Size]byte
+	copy(factor[:], h.Sum(nil))
+
+	return factor
+}
+
+// hmac256 computes the HMAC-SHA256 of the message with the given key.
+func hmac256(key []byte, msg [32]byte) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg[:])
+
+	var result [sha256.Size]byte
+	copy(result[:], mac.Sum(nil))
+
+	return result
+}
+
+// tweakPubKey multiplies the public key with the passed scalar.
+func tweakPubKey(pub *btcec.PublicKey,
+	tweak [sha256.Size]byte) *btcec.PublicKey {
+
+	tweaked := &btcec.PublicKey{Curve: btcec.S256()}
+	tweaked.X, tweaked.Y = btcec.S256().ScalarMult(pub.X, pub.Y, tweak[:])
+
+	return tweaked
+}
+
+// tweakPrivKey multiplies the private key with the passed scalar.
+func tweakPrivKey(priv *btcec.PrivateKey,
+	tweak [sha256.Size]byte) *btcec.PrivateKey {
+
+	d := new(big.Int).SetBytes(tweak[:])
+	d.Mul(d, priv.D)
+	d.Mod(d, btcec.S256().N)
+
+	tweaked, _ := btcec.PrivKeyFromBytes(btcec.S256(), d.Bytes())
+
+	return tweaked
+}
@@ -0,0 +1,80 @@
+package blindedpath
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlindedPath tests that each hop of a blinded path is able to decrypt its
+// recipient data, derive the blinding point of the next hop and perform ECDH
+// operations with its blinded node ID.
+func TestBlindedPath(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 3
+
+	var (
+		nodeKeys []*sphinx.PrivKeyECDH
+		hops     []*HopInfo
+	)
+	for i := 0; i < numHops; i++ {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		require.NoError(t, err)
+
+		nodeKeys = append(nodeKeys, &sphinx.PrivKeyECDH{PrivKey: priv})
+		hops = append(hops, &HopInfo{
+			NodePub:   priv.PubKey(),
+			PlainText: []byte{byte(i), 1, 2, 3},
+		})
+	}
+
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	path, err := BuildBlindedPath(sessionKey, hops)
+	require.NoError(t, err)
+	require.Equal(t, hops[0].NodePub, path.IntroductionPoint)
+	require.Len(t, path.BlindedHops, numHops)
+
+	blindingPoint := path.BlindingPoint
+	for i, hop := range path.BlindedHops {
+		plainText, err := DecryptBlindedHopData(
+			nodeKeys[i], blindingPoint, hop.CipherText,
+		)
+		require.NoError(t, err)
+		require.Equal(t, hops[i].PlainText, plainText)
+
+		// The blinded node ID must match the one we derive ourselves,
+		// and ECDH with it must be symmetric.
+		blindedECDH, err := NewBlindedECDH(nodeKeys[i], blindingPoint)
+		require.NoError(t, err)
+		require.True(t, hop.BlindedNodePub.IsEqual(blindedECDH.PubKey()))
+
+		ephem, err := btcec.NewPrivateKey(btcec.S256())
+		require.NoError(t, err)
+
+		senderECDH := &sphinx.PrivKeyECDH{PrivKey: ephem}
+		expected, err := senderECDH.ECDH(hop.BlindedNodePub)
+		require.NoError(t, err)
+		actual, err := blindedECDH.ECDH(ephem.PubKey())
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+
+		// Another node must not be able to decrypt the data.
+		_, err = DecryptBlindedHopData(
+			nodeKeys[(i+1)%numHops], blindingPoint, hop.CipherText,
+		)
+		require.Error(t, err)
+
+		blindingPoint, err = NextBlindingPoint(
+			nodeKeys[i], blindingPoint,
+		)
+		require.NoError(t, err)
+	}
+
+	_, err = BuildBlindedPath(sessionKey, nil)
+	require.Equal(t, ErrNoHops, err)
+}
@@ -85,6 +85,16 @@ func (r *Manager) UpdatePolicy(newSchema routing.ChannelPolicy,
 			return nil
 		}
 
+		// The inbound fee may have been left unchanged, so we read
+		// it back from the updated edge.
+		inboundFee, err := edge.InboundFee()
+		if err != nil {
+			log.Warnf("Cannot read inbound fee for %v: %v\n",
+				info.ChannelPoint, err,
+			)
+			return nil
+		}
+
 		// Add updated edge to list of edges to send to gossiper.
 		edgesToUpdate = append(edgesToUpdate, discovery.EdgeWithInfo{
 			Info: info,
@@ -98,6 +108,7 @@ func (r *Manager) UpdatePolicy(newSchema routing.ChannelPolicy,
 			TimeLockDelta: uint32(edge.TimeLockDelta),
 			MinHTLCOut:    edge.MinHTLC,
 			MaxHTLC:       edge.MaxHTLC,
+			InboundFee:    inboundFee,
 		}
 
 		return nil
@@ -134,6 +145,15 @@ func (r *Manager) updateEdge(chanPoint wire.OutPoint,
 	)
 	edge.TimeLockDelta = uint16(newSchema.TimeLockDelta)
 
+	// The inbound fee is carried in the extra opaque data of the channel
+	// update. It's only changed if the new policy sets it.
+	if newSchema.InboundFee != nil {
+		err := edge.SetInboundFee(*newSchema.InboundFee)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Retrieve negotiated channel htlc amt limits.
 	amtMin, amtMax, err := r.getHtlcAmtLimits(chanPoint)
 	if err != nil {
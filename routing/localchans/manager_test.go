@@ -32,7 +32,12 @@ func TestManager(t *testing.T) {
 		},
 		TimeLockDelta: 80,
 		MaxHTLC:       5000,
+		InboundFee: &lnwire.Fee{
+			BaseFee: -10,
+			FeeRate: 20,
+		},
 	}
+	expectedInboundFee := *newPolicy.InboundFee
 
 	currentPolicy := channeldb.ChannelEdgePolicy{
 		MinHTLC:      minHTLC,
@@ -59,6 +64,9 @@ func TestManager(t *testing.T) {
 		if policy.MaxHTLC != newPolicy.MaxHTLC {
 			t.Fatal("unexpected max htlc")
 		}
+		if policy.InboundFee != expectedInboundFee {
+			t.Fatal("unexpected inbound fee")
+		}
 	}
 
 	propagateChanPolicyUpdate := func(
@@ -84,6 +92,13 @@ func TestManager(t *testing.T) {
 		if policy.MaxHTLC != newPolicy.MaxHTLC {
 			t.Fatal("unexpected max htlc")
 		}
+		inboundFee, err := policy.InboundFee()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inboundFee != expectedInboundFee {
+			t.Fatal("unexpected inbound fee")
+		}
 
 		return nil
 	}
@@ -145,4 +160,24 @@ func TestManager(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	// If no inbound fee is specified, the inbound fee should be kept
+	// unchanged.
+	noInboundFeePolicy := newPolicy
+	noInboundFeePolicy.InboundFee = nil
+
+	err = manager.UpdatePolicy(noInboundFeePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An inbound fee of zero removes the inbound fee.
+	expectedInboundFee = lnwire.Fee{}
+	zeroInboundFeePolicy := newPolicy
+	zeroInboundFeePolicy.InboundFee = &expectedInboundFee
+
+	err = manager.UpdatePolicy(zeroInboundFeePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
 }
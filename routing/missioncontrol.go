@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -68,11 +69,50 @@ var (
 	// ErrInvalidFailureInterval is returned if we get an invalid failure
 	// interval.
 	ErrInvalidFailureInterval = errors.New("failure interval must be >= 0")
+
+	// ErrNoEstimator is returned if no probability estimator is set.
+	ErrNoEstimator = errors.New("no probability estimator set")
 )
 
 // NodeResults contains previous results from a node to its peers.
 type NodeResults map[route.Vertex]TimedPairResult
 
+// MergePolicy defines how imported mission control results are merged with
+// the results that mission control already has for a node pair.
+type MergePolicy uint8
+
+const (
+	// MergeKeepNewer only uses imported success and failure results that
+	// are more recent than the ones we have for a pair.
+	MergeKeepNewer MergePolicy = iota
+
+	// MergeOverwrite replaces the results we have for a pair with the
+	// imported ones, regardless of their age.
+	MergeOverwrite
+
+	// MergeAverage averages the amounts and timestamps of the imported
+	// results with the ones we have for a pair. If only one side has a
+	// success or failure result, that result is used as is.
+	MergeAverage
+)
+
+// String returns a human readable name of the merge policy.
+func (p MergePolicy) String() string {
+	switch p {
+	case MergeKeepNewer:
+		return "keep-newer"
+
+	case MergeOverwrite:
+		return "overwrite"
+
+	case MergeAverage:
+		return "average"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(p))
+	}
+}
+
 // MissionControl contains state which summarizes the past attempts of HTLC
 // routing by external callers when sending payments throughout the network. It
 // acts as a shared memory during routing attempts with the goal to optimize the
@@ -98,7 +138,7 @@ type MissionControl struct {
 
 	// estimator is the probability estimator that is used with the payment
 	// results that mission control collects.
-	estimator *probabilityEstimator
+	estimator Estimator
 
 	sync.Mutex
 
@@ -111,9 +151,9 @@ type MissionControl struct {
 // MissionControlConfig defines parameters that control mission control
 // behaviour.
 type MissionControlConfig struct {
-	// ProbabilityEstimatorConfig is the config we will use for probability
-	// calculations.
-	ProbabilityEstimatorCfg
+	// Estimator is the probability estimator that mission control uses to
+	// estimate success probabilities from the payment results it collects.
+	Estimator Estimator
 
 	// MaxMcHistory defines the maximum number of payment results that are
 	// held on disk.
@@ -126,7 +166,11 @@ type MissionControlConfig struct {
 }
 
 func (c *MissionControlConfig) validate() error {
-	if err := c.ProbabilityEstimatorCfg.validate(); err != nil {
+	if c.Estimator == nil {
+		return ErrNoEstimator
+	}
+
+	if err := c.Estimator.Config().validate(); err != nil {
 		return err
 	}
 
@@ -143,11 +187,9 @@ func (c *MissionControlConfig) validate() error {
 
 // String returns a string representation of a mission control config.
 func (c *MissionControlConfig) String() string {
-	return fmt.Sprintf("Penalty Half Life: %v, Apriori Hop "+
-		"Probablity: %v, Maximum History: %v, Apriori Weight: %v, "+
-		"Minimum Failure Relax Interval: %v", c.PenaltyHalfLife,
-		c.AprioriHopProbability, c.MaxMcHistory, c.AprioriWeight,
-		c.MinFailureRelaxInterval)
+	return fmt.Sprintf("Maximum History: %v, Minimum Failure Relax "+
+		"Interval: %v, Estimator: %v", c.MaxMcHistory,
+		c.MinFailureRelaxInterval, c.Estimator)
 }
 
 // TimedPairResult describes a timestamped pair result.
@@ -214,17 +256,12 @@ func NewMissionControl(db kvdb.Backend, self route.Vertex,
 		return nil, err
 	}
 
-	estimator := &probabilityEstimator{
-		ProbabilityEstimatorCfg: cfg.ProbabilityEstimatorCfg,
-		prevSuccessProbability:  prevSuccessProbability,
-	}
-
 	mc := &MissionControl{
 		state:     newMissionControlState(cfg.MinFailureRelaxInterval),
 		now:       time.Now,
 		selfNode:  self,
 		store:     store,
-		estimator: estimator,
+		estimator: cfg.Estimator,
 	}
 
 	if err := mc.init(); err != nil {
@@ -245,25 +282,48 @@ func (m *MissionControl) init() error {
 		return err
 	}
 
-	for _, result := range results {
-		m.applyPaymentResult(result)
+	imports, err := m.store.fetchImports()
+	if err != nil {
+		return err
+	}
+
+	numResults, numImports := len(results), len(imports)
+
+	// Replay the payment results and imported snapshots in the order in
+	// which they were originally applied, because the outcome of an
+	// import depends on the state it is merged with.
+	for len(results) > 0 || len(imports) > 0 {
+		if len(imports) > 0 && (len(results) == 0 ||
+			imports[0].timestamp.Before(results[0].timeReply)) {
+
+			imp := imports[0]
+			m.state.importSnapshot(imp.snapshot, imp.policy)
+			imports = imports[1:]
+
+			continue
+		}
+
+		m.applyPaymentResult(results[0])
+		results = results[1:]
 	}
 
 	log.Debugf("Mission control state reconstruction finished: "+
-		"n=%v, time=%v", len(results), time.Since(start))
+		"n=%v, imports=%v, time=%v", numResults, numImports,
+		time.Since(start))
 
 	return nil
 }
 
 // GetConfig returns the config that mission control is currently configured
-// with. All fields are copied by value, so we do not need to worry about
+// with. All fields are copied by value, except for the estimator which is
+// never mutated and only replaced as a whole, so we do not need to worry about
 // mutation.
 func (m *MissionControl) GetConfig() *MissionControlConfig {
 	m.Lock()
 	defer m.Unlock()
 
 	return &MissionControlConfig{
-		ProbabilityEstimatorCfg: m.estimator.ProbabilityEstimatorCfg,
+		Estimator:               m.estimator,
 		MaxMcHistory:            m.store.maxRecords,
 		MinFailureRelaxInterval: m.state.minFailureRelaxInterval,
 	}
@@ -287,7 +347,7 @@ func (m *MissionControl) SetConfig(cfg *MissionControlConfig) error {
 
 	m.store.maxRecords = cfg.MaxMcHistory
 	m.state.minFailureRelaxInterval = cfg.MinFailureRelaxInterval
-	m.estimator.ProbabilityEstimatorCfg = cfg.ProbabilityEstimatorCfg
+	m.estimator = cfg.Estimator
 
 	return nil
 }
@@ -310,9 +370,10 @@ func (m *MissionControl) ResetHistory() error {
 }
 
 // GetProbability is expected to return the success probability of a payment
-// from fromNode along edge.
+// from fromNode along edge. The capacity is the capacity of the channel
+// between the nodes and is zero if unknown.
 func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
-	amt lnwire.MilliSatoshi) float64 {
+	amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 	m.Lock()
 	defer m.Unlock()
@@ -322,10 +383,10 @@ func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
 
 	// Use a distinct probability estimation function for local channels.
 	if fromNode == m.selfNode {
-		return m.estimator.getLocalPairProbability(now, results, toNode)
+		return m.estimator.LocalPairProbability(now, results, toNode)
 	}
 
-	return m.estimator.getPairProbability(now, results, toNode, amt)
+	return m.estimator.PairProbability(now, results, toNode, amt, capacity)
 }
 
 // GetHistorySnapshot takes a snapshot from the current mission control state
@@ -340,22 +401,40 @@ func (m *MissionControl) GetHistorySnapshot() *MissionControlSnapshot {
 }
 
 // ImportHistory imports the set of mission control results provided to our
-// in-memory state. These results are not persisted, so will not survive
-// restarts.
-func (m *MissionControl) ImportHistory(history *MissionControlSnapshot) error {
+// state, merging them with our existing results according to the given merge
+// policy. The imported results are persisted and merged again in the same
+// order on startup.
+func (m *MissionControl) ImportHistory(history *MissionControlSnapshot,
+	policy MergePolicy) error {
+
 	if history == nil {
 		return errors.New("cannot import nil history")
 	}
 
+	switch policy {
+	case MergeKeepNewer, MergeOverwrite, MergeAverage:
+	default:
+		return fmt.Errorf("unknown merge policy %v", policy)
+	}
+
 	m.Lock()
 	defer m.Unlock()
 
-	log.Infof("Importing history snapshot with %v pairs to mission control",
-		len(history.Pairs))
+	log.Infof("Importing history snapshot with %v pairs to mission "+
+		"control, merge policy: %v", len(history.Pairs), policy)
+
+	err := m.store.addImport(&importedSnapshot{
+		timestamp: m.now(),
+		policy:    policy,
+		snapshot:  history,
+	})
+	if err != nil {
+		return err
+	}
 
-	imported := m.state.importSnapshot(history)
+	imported := m.state.importSnapshot(history, policy)
 
-	log.Infof("Imported %v results to mission control", imported)
+	log.Infof("Imported %v pairs to mission control", imported)
 
 	return nil
 }
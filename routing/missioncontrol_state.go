@@ -3,6 +3,7 @@ package routing
 import (
 	"time"
 
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
 )
 
@@ -211,40 +212,64 @@ func (m *missionControlState) getSnapshot() *MissionControlSnapshot {
 }
 
 // importSnapshot takes an existing snapshot and merges it with our current
-// state if the result provided are fresher than our current results. It returns
-// the number of pairs that were used.
-func (m *missionControlState) importSnapshot(snapshot *MissionControlSnapshot) int {
+// state using the given merge policy. It returns the number of pairs that were
+// updated.
+func (m *missionControlState) importSnapshot(snapshot *MissionControlSnapshot,
+	policy MergePolicy) int {
+
 	var imported int
 
 	for _, pair := range snapshot.Pairs {
 		fromNode := pair.Pair.From
 		toNode := pair.Pair.To
 
-		results, found := m.getLastPairResult(fromNode)
-		if !found {
-			results = make(map[route.Vertex]TimedPairResult)
-		}
-
+		results, _ := m.getLastPairResult(fromNode)
 		lastResult := results[toNode]
 
-		failResult := failPairResult(pair.FailAmt)
-		imported += m.importResult(
-			lastResult.FailTime, pair.FailTime, failResult,
-			fromNode, toNode,
-		)
-
-		successResult := successPairResult(pair.SuccessAmt)
-		imported += m.importResult(
-			lastResult.SuccessTime, pair.SuccessTime, successResult,
-			fromNode, toNode,
-		)
+		switch policy {
+		case MergeOverwrite:
+			m.setPairResult(fromNode, toNode, pair.TimedPairResult)
+			imported++
+
+		case MergeAverage:
+			merged := averagePairResults(
+				lastResult, pair.TimedPairResult,
+			)
+			m.setPairResult(fromNode, toNode, merged)
+			imported++
+
+		default:
+			failResult := failPairResult(pair.FailAmt)
+			failImported := m.importResult(
+				lastResult.FailTime, pair.FailTime, failResult,
+				fromNode, toNode,
+			)
+
+			successResult := successPairResult(pair.SuccessAmt)
+			successImported := m.importResult(
+				lastResult.SuccessTime, pair.SuccessTime,
+				successResult, fromNode, toNode,
+			)
+
+			if failImported || successImported {
+				imported++
+			}
+		}
 	}
 
 	return imported
 }
 
+// importResult applies an imported result to a pair if it is more recent than
+// the current result of the pair. It returns true if the result was applied.
 func (m *missionControlState) importResult(currentTs, importedTs time.Time,
-	importedResult pairResult, fromNode, toNode route.Vertex) int {
+	importedResult pairResult, fromNode, toNode route.Vertex) bool {
+
+	// A zero timestamp means that the imported pair doesn't contain this
+	// kind of result, so there is nothing to apply.
+	if importedTs.IsZero() {
+		return false
+	}
 
 	if currentTs.After(importedTs) {
 		log.Debugf("Not setting pair result for %v->%v (%v) "+
@@ -252,10 +277,72 @@ func (m *missionControlState) importResult(currentTs, importedTs time.Time,
 			fromNode, toNode, importedResult.amt,
 			importedResult.success, importedTs, currentTs)
 
-		return 0
+		return false
 	}
 
 	m.setLastPairResult(fromNode, toNode, importedTs, &importedResult)
 
-	return 1
+	return true
+}
+
+// setPairResult replaces the results of a node pair. If the success amount of
+// the result reaches into its failure range, the more recent of the two
+// results takes precedence.
+func (m *missionControlState) setPairResult(fromNode, toNode route.Vertex,
+	result TimedPairResult) {
+
+	if !result.FailTime.IsZero() && result.SuccessAmt >= result.FailAmt {
+		switch {
+		case result.SuccessTime.After(result.FailTime):
+			result.FailAmt = result.SuccessAmt + 1
+
+		case result.FailAmt == 0:
+			result.SuccessAmt = 0
+
+		default:
+			result.SuccessAmt = result.FailAmt - 1
+		}
+	}
+
+	nodePairs, ok := m.lastPairResult[fromNode]
+	if !ok {
+		nodePairs = make(NodeResults)
+		m.lastPairResult[fromNode] = nodePairs
+	}
+
+	log.Debugf("Replacing %v->%v range with [%v-%v]", fromNode, toNode,
+		result.SuccessAmt, result.FailAmt)
+
+	nodePairs[toNode] = result
+}
+
+// averagePairResults averages the success and failure results of two pair
+// results.
+func averagePairResults(a, b TimedPairResult) TimedPairResult {
+	var merged TimedPairResult
+	merged.FailTime, merged.FailAmt = averageResult(
+		a.FailTime, a.FailAmt, b.FailTime, b.FailAmt,
+	)
+	merged.SuccessTime, merged.SuccessAmt = averageResult(
+		a.SuccessTime, a.SuccessAmt, b.SuccessTime, b.SuccessAmt,
+	)
+
+	return merged
+}
+
+// averageResult averages the timestamps and amounts of two results of the
+// same kind. A zero timestamp marks a missing result, in which case the other
+// result is returned.
+func averageResult(aTime time.Time, aAmt lnwire.MilliSatoshi, bTime time.Time,
+	bAmt lnwire.MilliSatoshi) (time.Time, lnwire.MilliSatoshi) {
+
+	switch {
+	case aTime.IsZero():
+		return bTime, bAmt
+
+	case bTime.IsZero():
+		return aTime, aAmt
+	}
+
+	return aTime.Add(bTime.Sub(aTime) / 2), (aAmt + bAmt) / 2
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
 )
 
 // TestMissionControlStateFailureResult tests setting failure results on the
@@ -45,3 +46,131 @@ func TestMissionControlStateFailureResult(t *testing.T) {
 		t.Fatalf("unexpected fail amount %v", result[to].FailAmt)
 	}
 }
+
+// TestMissionControlStateImportSnapshot tests merging imported snapshots with
+// the existing state using the different merge policies.
+func TestMissionControlStateImportSnapshot(t *testing.T) {
+	var (
+		from = route.Vertex{1}
+		to   = route.Vertex{2}
+		pair = NewDirectedNodePair(from, to)
+
+		// existing is the state of the pair before the import.
+		existing = TimedPairResult{
+			FailTime:    testTime,
+			FailAmt:     3000,
+			SuccessTime: testTime,
+			SuccessAmt:  1000,
+		}
+	)
+
+	tests := []struct {
+		name     string
+		policy   MergePolicy
+		imported TimedPairResult
+		expected TimedPairResult
+	}{
+		{
+			// Only the more recent success is used, the older
+			// failure is dropped.
+			name:   "keep newer",
+			policy: MergeKeepNewer,
+			imported: TimedPairResult{
+				FailTime:    testTime.Add(-time.Hour),
+				FailAmt:     2000,
+				SuccessTime: testTime.Add(time.Hour),
+				SuccessAmt:  2000,
+			},
+			expected: TimedPairResult{
+				FailTime:    testTime,
+				FailAmt:     3000,
+				SuccessTime: testTime.Add(time.Hour),
+				SuccessAmt:  2000,
+			},
+		},
+		{
+			// A pair without a failure doesn't clear the existing
+			// failure.
+			name:   "keep newer success only",
+			policy: MergeKeepNewer,
+			imported: TimedPairResult{
+				SuccessTime: testTime.Add(time.Hour),
+				SuccessAmt:  500,
+			},
+			expected: TimedPairResult{
+				FailTime:    testTime,
+				FailAmt:     3000,
+				SuccessTime: testTime.Add(time.Hour),
+				SuccessAmt:  1000,
+			},
+		},
+		{
+			name:   "overwrite",
+			policy: MergeOverwrite,
+			imported: TimedPairResult{
+				FailTime: testTime.Add(-time.Hour),
+				FailAmt:  2000,
+			},
+			expected: TimedPairResult{
+				FailTime: testTime.Add(-time.Hour),
+				FailAmt:  2000,
+			},
+		},
+		{
+			name:   "average",
+			policy: MergeAverage,
+			imported: TimedPairResult{
+				FailTime:    testTime.Add(2 * time.Hour),
+				FailAmt:     5000,
+				SuccessTime: testTime.Add(-2 * time.Hour),
+				SuccessAmt:  2000,
+			},
+			expected: TimedPairResult{
+				FailTime:    testTime.Add(time.Hour),
+				FailAmt:     4000,
+				SuccessTime: testTime.Add(-time.Hour),
+				SuccessAmt:  1500,
+			},
+		},
+		{
+			// The averaged success amount reaches into the failure
+			// range, so the more recent failure lowers it.
+			name:   "average overlapping",
+			policy: MergeAverage,
+			imported: TimedPairResult{
+				FailTime:    testTime.Add(2 * time.Hour),
+				FailAmt:     1000,
+				SuccessTime: testTime,
+				SuccessAmt:  5000,
+			},
+			expected: TimedPairResult{
+				FailTime:    testTime.Add(time.Hour),
+				FailAmt:     2000,
+				SuccessTime: testTime,
+				SuccessAmt:  1999,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			state := newMissionControlState(time.Minute)
+			state.setPairResult(from, to, existing)
+
+			imported := state.importSnapshot(
+				&MissionControlSnapshot{
+					Pairs: []MissionControlPairSnapshot{{
+						Pair:            pair,
+						TimedPairResult: test.imported,
+					}},
+				}, test.policy,
+			)
+			require.Equal(t, 1, imported)
+
+			results, _ := state.getLastPairResult(from)
+			require.Equal(t, test.expected, results[to])
+		})
+	}
+}
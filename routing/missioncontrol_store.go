@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/btcsuite/btcd/wire"
@@ -17,6 +18,10 @@ var (
 	// stored.
 	resultsKey = []byte("missioncontrol-results")
 
+	// importsKey is the fixed key under which the imported mission control
+	// snapshots are stored.
+	importsKey = []byte("missioncontrol-imports")
+
 	// Big endian is the preferred byte order, due to cursor scans over
 	// integer keys iterating in order.
 	byteOrder = binary.BigEndian
@@ -51,6 +56,12 @@ func newMissionControlStore(db kvdb.Backend, maxRecords int) (*missionControlSto
 				err)
 		}
 
+		_, err = tx.CreateTopLevelBucket(importsKey)
+		if err != nil {
+			return fmt.Errorf("cannot create imports bucket: %v",
+				err)
+		}
+
 		// Count initial number of results and track this number in
 		// memory to avoid calling Stats().KeyN. The reliability of
 		// Stats() is doubtful and seemed to have caused crashes in the
@@ -74,15 +85,20 @@ func newMissionControlStore(db kvdb.Backend, maxRecords int) (*missionControlSto
 	return store, nil
 }
 
-// clear removes all results from the db.
+// clear removes all results and imported snapshots from the db.
 func (b *missionControlStore) clear() error {
 	return kvdb.Update(b.db, func(tx kvdb.RwTx) error {
-		if err := tx.DeleteTopLevelBucket(resultsKey); err != nil {
-			return err
+		for _, key := range [][]byte{resultsKey, importsKey} {
+			if err := tx.DeleteTopLevelBucket(key); err != nil {
+				return err
+			}
+
+			if _, err := tx.CreateTopLevelBucket(key); err != nil {
+				return err
+			}
 		}
 
-		_, err := tx.CreateTopLevelBucket(resultsKey)
-		return err
+		return nil
 	}, func() {})
 }
 
@@ -271,3 +287,162 @@ func getResultKey(rp *paymentResult) []byte {
 
 	return keyBytes[:]
 }
+
+// importedSnapshot is a mission control snapshot that was imported together
+// with the time of the import and the merge policy that was used.
+type importedSnapshot struct {
+	timestamp time.Time
+	policy    MergePolicy
+	snapshot  *MissionControlSnapshot
+}
+
+// serializeTime serializes a timestamp as unix nanoseconds. The zero time,
+// which marks a missing result, is serialized as zero.
+func serializeTime(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return uint64(t.UnixNano())
+}
+
+// deserializeTime is the inverse of serializeTime.
+func deserializeTime(t uint64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, int64(t)).Local()
+}
+
+// serializeImport serializes an imported snapshot.
+func serializeImport(imp *importedSnapshot) ([]byte, error) {
+	var b bytes.Buffer
+
+	err := channeldb.WriteElements(
+		&b, uint8(imp.policy), uint32(len(imp.snapshot.Pairs)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range imp.snapshot.Pairs {
+		if _, err := b.Write(pair.Pair.From[:]); err != nil {
+			return nil, err
+		}
+		if _, err := b.Write(pair.Pair.To[:]); err != nil {
+			return nil, err
+		}
+
+		err := channeldb.WriteElements(
+			&b, serializeTime(pair.FailTime), pair.FailAmt,
+			serializeTime(pair.SuccessTime), pair.SuccessAmt,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// deserializeImport deserializes an imported snapshot.
+func deserializeImport(k, v []byte) (*importedSnapshot, error) {
+	imp := importedSnapshot{
+		timestamp: deserializeTime(byteOrder.Uint64(k)),
+		snapshot:  &MissionControlSnapshot{},
+	}
+
+	r := bytes.NewReader(v)
+
+	var (
+		policy   uint8
+		numPairs uint32
+	)
+	if err := channeldb.ReadElements(r, &policy, &numPairs); err != nil {
+		return nil, err
+	}
+	imp.policy = MergePolicy(policy)
+
+	imp.snapshot.Pairs = make([]MissionControlPairSnapshot, numPairs)
+	for i := range imp.snapshot.Pairs {
+		var (
+			pair                  = &imp.snapshot.Pairs[i]
+			failTime, successTime uint64
+		)
+		if _, err := io.ReadFull(r, pair.Pair.From[:]); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, pair.Pair.To[:]); err != nil {
+			return nil, err
+		}
+
+		err := channeldb.ReadElements(
+			r, &failTime, &pair.FailAmt, &successTime,
+			&pair.SuccessAmt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		pair.FailTime = deserializeTime(failTime)
+		pair.SuccessTime = deserializeTime(successTime)
+	}
+
+	return &imp, nil
+}
+
+// addImport stores an imported snapshot, so that it can be applied again on
+// startup.
+func (b *missionControlStore) addImport(imp *importedSnapshot) error {
+	v, err := serializeImport(imp)
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Update(b.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(importsKey)
+
+		// Identify imports by their time and a sequence number, so that
+		// they are kept sorted chronologically even if two imports
+		// happen at the same time.
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var k [16]byte
+		byteOrder.PutUint64(k[:], serializeTime(imp.timestamp))
+		byteOrder.PutUint64(k[8:], seq)
+
+		return bucket.Put(k[:], v)
+	}, func() {})
+}
+
+// fetchImports returns all imported snapshots currently stored in the
+// database, ordered by the time of their import.
+func (b *missionControlStore) fetchImports() ([]*importedSnapshot, error) {
+	var imports []*importedSnapshot
+
+	err := kvdb.View(b.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(importsKey)
+
+		return bucket.ForEach(func(k, v []byte) error {
+			imp, err := deserializeImport(k, v)
+			if err != nil {
+				return err
+			}
+
+			imports = append(imports, imp)
+
+			return nil
+		})
+	}, func() {
+		imports = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return imports, nil
+}
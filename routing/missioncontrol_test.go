@@ -9,6 +9,7 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -44,6 +45,10 @@ type mcTestContext struct {
 	mc  *MissionControl
 	now time.Time
 
+	// estimator is the probability estimator that mission control is
+	// created with.
+	estimator Estimator
+
 	db     kvdb.Backend
 	dbPath string
 
@@ -70,6 +75,15 @@ func createMcTestContext(t *testing.T) *mcTestContext {
 		t.Fatal(err)
 	}
 
+	ctx.estimator, err = NewAprioriEstimator(AprioriConfig{
+		PenaltyHalfLife:       testPenaltyHalfLife,
+		AprioriHopProbability: testAprioriHopProbability,
+		AprioriWeight:         testAprioriWeight,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	ctx.restartMc()
 
 	return ctx
@@ -80,11 +94,7 @@ func (ctx *mcTestContext) restartMc() {
 	mc, err := NewMissionControl(
 		ctx.db, mcTestSelf,
 		&MissionControlConfig{
-			ProbabilityEstimatorCfg: ProbabilityEstimatorCfg{
-				PenaltyHalfLife:       testPenaltyHalfLife,
-				AprioriHopProbability: testAprioriHopProbability,
-				AprioriWeight:         testAprioriWeight,
-			},
+			Estimator: ctx.estimator,
 		},
 	)
 	if err != nil {
@@ -105,7 +115,9 @@ func (ctx *mcTestContext) cleanup() {
 func (ctx *mcTestContext) expectP(amt lnwire.MilliSatoshi, expected float64) {
 	ctx.t.Helper()
 
-	p := ctx.mc.GetProbability(mcTestNode1, mcTestNode2, amt)
+	p := ctx.mc.GetProbability(
+		mcTestNode1, mcTestNode2, amt, testCapacity,
+	)
 	if p != expected {
 		ctx.t.Fatalf("expected probability %v but got %v", expected, p)
 	}
@@ -144,7 +156,9 @@ func TestMissionControl(t *testing.T) {
 
 	// For local channels, we expect a higher probability than our a prior
 	// test probability.
-	selfP := ctx.mc.GetProbability(mcTestSelf, mcTestNode1, 100)
+	selfP := ctx.mc.GetProbability(
+		mcTestSelf, mcTestNode1, 100, testCapacity,
+	)
 	if selfP != prevSuccessProbability {
 		t.Fatalf("expected prev success prob for untried local chans")
 	}
@@ -197,6 +211,68 @@ func TestMissionControl(t *testing.T) {
 	ctx.reportSuccess()
 }
 
+// TestMissionControlBimodal tests that mission control learns the liquidity
+// bounds of a channel with the bimodal estimator and that they are restored
+// after a restart.
+func TestMissionControlBimodal(t *testing.T) {
+	ctx := createMcTestContext(t)
+	defer ctx.cleanup()
+
+	estimator, err := NewBimodalEstimator(BimodalConfig{
+		BimodalScaleMsat:  lnwire.MilliSatoshi(10000000),
+		BimodalDecayTime:  time.Hour,
+		BimodalNodeWeight: 0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.estimator = estimator
+	ctx.restartMc()
+
+	const failAmt = lnwire.MilliSatoshi(50000000)
+
+	// Without any results, small amounts are expected to succeed.
+	untriedP := ctx.mc.GetProbability(
+		mcTestNode1, mcTestNode2, 1000, testCapacity,
+	)
+	if untriedP < 0.99 {
+		t.Fatalf("expected high probability for untried channel, "+
+			"got %v", untriedP)
+	}
+
+	// After a failure, the failed amount can't be sent anymore while a
+	// smaller amount still can.
+	ctx.reportFailure(failAmt, lnwire.NewTemporaryChannelFailure(nil))
+	ctx.expectP(failAmt, 0)
+
+	smallerP := ctx.mc.GetProbability(
+		mcTestNode1, mcTestNode2, failAmt/2, testCapacity,
+	)
+	if smallerP <= 0 || smallerP >= 1 {
+		t.Fatalf("expected probability in (0, 1), got %v", smallerP)
+	}
+
+	// The liquidity bounds are derived from the stored payment results,
+	// so they are restored after a restart.
+	ctx.restartMc()
+	ctx.expectP(failAmt, 0)
+
+	history := ctx.mc.GetPairHistorySnapshot(mcTestNode1, mcTestNode2)
+	if history.FailAmt != failAmt {
+		t.Fatalf("expected fail amount %v, got %v", failAmt,
+			history.FailAmt)
+	}
+
+	// Over time the upper liquidity bound relaxes back to the capacity.
+	ctx.now = ctx.now.Add(10 * time.Hour)
+	relaxedP := ctx.mc.GetProbability(
+		mcTestNode1, mcTestNode2, failAmt, testCapacity,
+	)
+	if relaxedP < 0.4 {
+		t.Fatalf("expected probability to recover, got %v", relaxedP)
+	}
+}
+
 // TestMissionControlChannelUpdate tests that the first channel update is not
 // penalizing the channel yet.
 func TestMissionControlChannelUpdate(t *testing.T) {
@@ -216,3 +292,55 @@ func TestMissionControlChannelUpdate(t *testing.T) {
 	)
 	ctx.expectP(100, 0)
 }
+
+// TestMissionControlImportPersisted tests that imported snapshots survive a
+// restart and are merged in the same order with the payment results.
+func TestMissionControlImportPersisted(t *testing.T) {
+	// Set time zone explicitly to keep test deterministic.
+	time.Local = time.UTC
+
+	ctx := createMcTestContext(t)
+	defer ctx.cleanup()
+
+	ctx.reportSuccess()
+
+	// Overwrite the pair with a failure that is more recent than the
+	// success. If the import were applied before the success on startup,
+	// the success would move the failure amount up.
+	ctx.now = ctx.now.Add(time.Hour)
+	err := ctx.mc.ImportHistory(&MissionControlSnapshot{
+		Pairs: []MissionControlPairSnapshot{
+			{
+				Pair: NewDirectedNodePair(
+					mcTestNode1, mcTestNode2,
+				),
+				TimedPairResult: TimedPairResult{
+					FailTime: ctx.now,
+					FailAmt:  500,
+				},
+			},
+		},
+	}, MergeOverwrite)
+	require.NoError(t, err)
+
+	expected := ctx.mc.GetPairHistorySnapshot(mcTestNode1, mcTestNode2)
+	require.Equal(t, TimedPairResult{
+		FailTime: ctx.now,
+		FailAmt:  500,
+	}, expected)
+
+	ctx.restartMc()
+	require.Equal(
+		t, expected,
+		ctx.mc.GetPairHistorySnapshot(mcTestNode1, mcTestNode2),
+	)
+
+	// Resetting mission control also removes the imported snapshots.
+	require.NoError(t, ctx.mc.ResetHistory())
+
+	ctx.restartMc()
+	require.Equal(
+		t, TimedPairResult{},
+		ctx.mc.GetPairHistorySnapshot(mcTestNode1, mcTestNode2),
+	)
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/btcsuite/btcutil"
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/feature"
@@ -41,8 +42,7 @@ const (
 // pathFinder defines the interface of a path finding algorithm.
 type pathFinder = func(g *graphParams, r *RestrictParams,
 	cfg *PathFindingConfig, source, target route.Vertex,
-	amt lnwire.MilliSatoshi, finalHtlcExpiry int32) (
-	[]*channeldb.ChannelEdgePolicy, error)
+	amt lnwire.MilliSatoshi, finalHtlcExpiry int32) ([]*pathEdge, error)
 
 var (
 	// DefaultAttemptCost is the default fixed virtual cost in path finding
@@ -79,6 +79,17 @@ type edgePolicyWithSource struct {
 	edge       *channeldb.ChannelEdgePolicy
 }
 
+// pathEdge is a channel on a path. It holds the policy of the node that
+// forwards over the channel and the inbound fee that the node at the other end
+// charges for htlcs that it receives through the channel.
+type pathEdge struct {
+	*channeldb.ChannelEdgePolicy
+
+	// inboundFee is the inbound fee of the node that the channel leads
+	// to. It is zero for channels from route hints.
+	inboundFee lnwire.Fee
+}
+
 // finalHopParams encapsulates various parameters for route construction that
 // apply to the final hop in a route. These features include basic payment data
 // such as amounts and cltvs, as well as more complex features like destination
@@ -89,6 +100,10 @@ type finalHopParams struct {
 	cltvDelta   uint16
 	records     record.CustomSet
 	paymentAddr *[32]byte
+
+	// trampolineOnion is the trampoline onion that is attached to the
+	// final hop if the payment is delegated to a trampoline node.
+	trampolineOnion []byte
 }
 
 // newRoute constructs a route using the provided path and final hop constraints.
@@ -102,7 +117,7 @@ type finalHopParams struct {
 // any feature vectors on all hops have been validated for transitive
 // dependencies.
 func newRoute(sourceVertex route.Vertex,
-	pathEdges []*channeldb.ChannelEdgePolicy, currentHeight uint32,
+	pathEdges []*pathEdge, currentHeight uint32,
 	finalHop finalHopParams) (*route.Route, error) {
 
 	var (
@@ -138,6 +153,7 @@ func newRoute(sourceVertex route.Vertex,
 			tlvPayload       bool
 			customRecords    record.CustomSet
 			mpp              *record.MPP
+			trampolineOnion  []byte
 		)
 
 		// Define a helper function that checks this edge's feature
@@ -202,6 +218,14 @@ func newRoute(sourceVertex route.Vertex,
 					*finalHop.paymentAddr,
 				)
 			}
+
+			// The trampoline onion can only be delivered inside
+			// of a TLV payload.
+			if !tlvPayload && finalHop.trampolineOnion != nil {
+				return nil, errors.New("cannot attach " +
+					"trampoline onion")
+			}
+			trampolineOnion = finalHop.trampolineOnion
 		} else {
 			// The amount that the current hop needs to forward is
 			// equal to the incoming amount of the next hop.
@@ -211,8 +235,12 @@ func newRoute(sourceVertex route.Vertex,
 			// based on the amount that this hop needs to forward
 			// and its policy for the outgoing channel. This policy
 			// is stored as part of the incoming channel of
-			// the next hop.
-			fee = pathEdges[i+1].ComputeFee(amtToForward)
+			// the next hop. The current hop adds the inbound fee
+			// of the channel that the htlc arrives through.
+			fee = forwardingFee(
+				pathEdges[i+1].ComputeFee(amtToForward),
+				edge.inboundFee, amtToForward,
+			)
 
 			// We'll take the total timelock of the preceding hop as
 			// the outgoing timelock or this hop. Then we'll
@@ -232,6 +260,7 @@ func newRoute(sourceVertex route.Vertex,
 			LegacyPayload:    !tlvPayload,
 			CustomRecords:    customRecords,
 			MPP:              mpp,
+			TrampolineOnion:  trampolineOnion,
 		}
 
 		hops = append([]*route.Hop{currentHop}, hops...)
@@ -272,6 +301,51 @@ func edgeWeight(lockedAmt lnwire.MilliSatoshi, fee lnwire.MilliSatoshi,
 	return int64(fee) + timeLockPenalty
 }
 
+// forwardingFee returns the total fee that a node charges for forwarding amt,
+// given the fee of its outgoing channel and the inbound fee of the channel
+// that the htlc arrives through. The inbound fee is computed over the amount
+// that includes the outgoing fee. A negative inbound fee is a discount, but it
+// never makes the total fee negative.
+func forwardingFee(outFee lnwire.MilliSatoshi, inboundFee lnwire.Fee,
+	amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	fee := int64(outFee) + inboundFee.CalcFee(amt+outFee)
+	if fee < 0 {
+		return 0
+	}
+
+	return lnwire.MilliSatoshi(fee)
+}
+
+// amtToForwardFromIncoming returns the largest amount that a node forwards
+// over the channel with the given policy when it receives incomingAmt through
+// a channel with the given inbound fee.
+func amtToForwardFromIncoming(policy *channeldb.ChannelEdgePolicy,
+	inboundFee lnwire.Fee,
+	incomingAmt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	// Without an inbound fee, the amount can be computed directly.
+	if inboundFee == (lnwire.Fee{}) {
+		return incomingAmt - policy.ComputeFeeFromIncoming(incomingAmt)
+	}
+
+	// Otherwise we search for the largest amount whose total, including
+	// the fee, doesn't exceed the incoming amount. The total increases
+	// with the forwarded amount, which allows a binary search.
+	var low, high lnwire.MilliSatoshi = 0, incomingAmt
+	for low < high {
+		mid := high - (high-low)/2
+		fee := forwardingFee(policy.ComputeFee(mid), inboundFee, mid)
+		if mid+fee <= incomingAmt {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return low
+}
+
 // graphParams wraps the set of graph parameters passed to findPath.
 type graphParams struct {
 	// graph is the ChannelGraph to be used during path finding.
@@ -296,9 +370,10 @@ type graphParams struct {
 // found path must adhere to.
 type RestrictParams struct {
 	// ProbabilitySource is a callback that is expected to return the
-	// success probability of traversing the channel from the node.
+	// success probability of traversing the channel from the node. The
+	// capacity of the channel is passed in and is zero if unknown.
 	ProbabilitySource func(route.Vertex, route.Vertex,
-		lnwire.MilliSatoshi) float64
+		lnwire.MilliSatoshi, btcutil.Amount) float64
 
 	// FeeLimit is a maximum fee amount allowed to be used on the path from
 	// the source to the target.
@@ -330,6 +405,11 @@ type RestrictParams struct {
 	// mitigate probing vectors and payment sniping attacks on overpaid
 	// invoices.
 	PaymentAddr *[32]byte
+
+	// TrampolineOnion is the trampoline onion that is attached to the
+	// final hop if the payment is delegated to a trampoline node. It is
+	// needed to account for the size of the final hop payload.
+	TrampolineOnion []byte
 }
 
 // PathFindingConfig defines global parameters that control the trade-off in
@@ -416,7 +496,7 @@ func getOutgoingBalance(node route.Vertex, outgoingChans map[uint64]struct{},
 // available bandwidth.
 func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	source, target route.Vertex, amt lnwire.MilliSatoshi,
-	finalHtlcExpiry int32) ([]*channeldb.ChannelEdgePolicy, error) {
+	finalHtlcExpiry int32) ([]*pathEdge, error) {
 
 	// Pathfinding can be a significant portion of the total payment
 	// latency, especially on low-powered devices. Log several metrics to
@@ -549,7 +629,8 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		LegacyPayload: !features.HasFeature(
 			lnwire.TLVOnionPayloadOptional,
 		),
-		MPP: mpp,
+		MPP:             mpp,
+		TrampolineOnion: r.TrampolineOnion,
 	}
 
 	// We can't always assume that the end destination is publicly
@@ -587,17 +668,31 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	// satisfy our specific requirements.
 	processEdge := func(fromVertex route.Vertex,
 		fromFeatures *lnwire.FeatureVector,
-		edge *channeldb.ChannelEdgePolicy, toNodeDist *nodeWithDist) {
+		edge *pathEdge, capacity btcutil.Amount,
+		toNodeDist *nodeWithDist) {
 
 		edgesExpanded++
 
 		// Calculate amount that the candidate node would have to send
-		// out.
+		// out. Unless the to node is the target, this includes the
+		// inbound fee that the to node charges for this channel. An
+		// inbound discount can't exceed the outbound fee of the to
+		// node.
 		amountToSend := toNodeDist.amountToReceive
+		var inboundFee int64
+		if toNodeDist.node != target {
+			inboundFee = edge.inboundFee.CalcFee(amountToSend)
+			if inboundFee < -int64(toNodeDist.outboundFee) {
+				inboundFee = -int64(toNodeDist.outboundFee)
+			}
+			amountToSend = lnwire.MilliSatoshi(
+				int64(amountToSend) + inboundFee,
+			)
+		}
 
 		// Request the success probability for this edge.
 		edgeProbability := r.ProbabilitySource(
-			fromVertex, toNodeDist.node, amountToSend,
+			fromVertex, toNodeDist.node, amountToSend, capacity,
 		)
 
 		log.Trace(newLogClosure(func() string {
@@ -665,8 +760,18 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// By adding fromVertex in the route, there will be an extra
 		// weight composed of the fee that this node will charge and
 		// the amount that will be locked for timeLockDelta blocks in
-		// the HTLC that is handed out to fromVertex.
-		weight := edgeWeight(amountToReceive, fee, timeLockDelta)
+		// the HTLC that is handed out to fromVertex. The inbound fee
+		// of the to node is accounted for here as well. Dijkstra
+		// doesn't support negative edge weights, so an inbound
+		// discount can at most offset the fee of fromVertex.
+		weightFee := int64(fee) + inboundFee
+		if weightFee < 0 {
+			weightFee = 0
+		}
+		weight := edgeWeight(
+			amountToReceive, lnwire.MilliSatoshi(weightFee),
+			timeLockDelta,
+		)
 
 		// Compute the tentative weight to this new channel/edge
 		// which is the weight from our toNode to the target node
@@ -745,6 +850,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			weight:          tempWeight,
 			node:            fromVertex,
 			amountToReceive: amountToReceive,
+			outboundFee:     fee,
 			incomingCltv:    incomingCltv,
 			probability:     probability,
 			nextHop:         edge,
@@ -817,7 +923,10 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		}
 
 		for _, reverseEdge := range additionalEdgesWithSrc[pivot] {
-			u.addPolicy(reverseEdge.sourceNode, reverseEdge.edge, 0)
+			u.addPolicy(
+				reverseEdge.sourceNode, reverseEdge.edge,
+				lnwire.Fee{}, 0,
+			)
 		}
 
 		amtToSend := partialPath.amountToReceive
@@ -862,7 +971,10 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 			// Check if this candidate node is better than what we
 			// already have.
-			processEdge(fromNode, fromFeatures, policy, partialPath)
+			processEdge(
+				fromNode, fromFeatures, policy,
+				unifiedPolicy.capacity(), partialPath,
+			)
 		}
 
 		if nodeHeap.Len() == 0 {
@@ -883,7 +995,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 	// Use the distance map to unravel the forward path from source to
 	// target.
-	var pathEdges []*channeldb.ChannelEdgePolicy
+	var pathEdges []*pathEdge
 	currentNode := source
 	for {
 		// Determine the next hop forward using the next map.
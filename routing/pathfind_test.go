@@ -102,7 +102,9 @@ var (
 
 // noProbabilitySource is used in testing to return the same probability 1 for
 // all edges.
-func noProbabilitySource(route.Vertex, route.Vertex, lnwire.MilliSatoshi) float64 {
+func noProbabilitySource(route.Vertex, route.Vertex, lnwire.MilliSatoshi,
+	btcutil.Amount) float64 {
+
 	return 1
 }
 
@@ -342,6 +344,7 @@ type testChannelPolicy struct {
 	LastUpdate  time.Time
 	Disabled    bool
 	Features    *lnwire.FeatureVector
+	InboundFee  lnwire.Fee
 }
 
 type testChannelEnd struct {
@@ -575,6 +578,10 @@ func createTestGraphFromChannels(testChannels []*testChannel, source string) (
 				FeeBaseMSat:               node1.FeeBaseMsat,
 				FeeProportionalMillionths: node1.FeeRate,
 			}
+			err := edgePolicy.SetInboundFee(node1.InboundFee)
+			if err != nil {
+				return nil, err
+			}
 			if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
 				return nil, err
 			}
@@ -603,6 +610,10 @@ func createTestGraphFromChannels(testChannels []*testChannel, source string) (
 				FeeBaseMSat:               node2.FeeBaseMsat,
 				FeeProportionalMillionths: node2.FeeRate,
 			}
+			err := edgePolicy.SetInboundFee(node2.InboundFee)
+			if err != nil {
+				return nil, err
+			}
 			if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
 				return nil, err
 			}
@@ -696,6 +707,147 @@ func TestFindLowestFeePath(t *testing.T) {
 	}
 }
 
+// TestFindPathInboundFee tests that inbound fees are taken into account when
+// selecting a path and constructing the route.
+func TestFindPathInboundFee(t *testing.T) {
+	t.Parallel()
+
+	// Set up a test graph with two paths from roasbeef to target. The path
+	// through a has the lowest outbound fees, but b gives a discount for
+	// htlcs that arrive through the channel from first, which makes the
+	// path through b cheaper. Because edge weights can't be negative, the
+	// discount can at most offset the fee that first charges.
+	testChannels := []*testChannel{
+		symmetricTestChannel("roasbeef", "first", 100000,
+			&testChannelPolicy{
+				Expiry:  144,
+				MinHTLC: 1,
+				MaxHTLC: 100000000,
+			},
+		),
+		symmetricTestChannel("first", "a", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: 500,
+			MinHTLC:     1,
+			MaxHTLC:     100000000,
+		}),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: 1000,
+			MinHTLC:     1,
+			MaxHTLC:     100000000,
+		}),
+		asymmetricTestChannel("first", "b", 100000,
+			&testChannelPolicy{
+				Expiry:      144,
+				FeeBaseMsat: 500,
+				MinHTLC:     1,
+				MaxHTLC:     100000000,
+			},
+			&testChannelPolicy{
+				Expiry:     144,
+				MinHTLC:    1,
+				MaxHTLC:    100000000,
+				InboundFee: lnwire.Fee{BaseFee: -1000},
+			}, 0,
+		),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: 1200,
+			MinHTLC:     1,
+			MaxHTLC:     100000000,
+		}),
+	}
+
+	ctx := newPathFindingTestContext(t, testChannels, "roasbeef")
+	defer ctx.cleanup()
+
+	const (
+		startingHeight = 100
+		finalHopCLTV   = 1
+	)
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := ctx.keyFromAlias("target")
+	path, err := ctx.findPath(target, paymentAmt)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+	route, err := newRoute(
+		ctx.source, path, startingHeight,
+		finalHopParams{
+			amt:       paymentAmt,
+			cltvDelta: finalHopCLTV,
+		},
+	)
+	if err != nil {
+		t.Fatalf("unable to create path: %v", err)
+	}
+
+	// The route should pass through b, which charges its outbound fee
+	// minus the inbound discount.
+	if route.Hops[1].PubKeyBytes != ctx.keyFromAlias("b") {
+		t.Fatalf("expected route to pass through b, "+
+			"but got a route through %v",
+			ctx.aliasFromKey(route.Hops[1].PubKeyBytes))
+	}
+	if route.HopFee(1) != 200 {
+		t.Fatalf("expected fee of 200 msat for b, got %v",
+			route.HopFee(1))
+	}
+	if route.TotalAmount != paymentAmt+700 {
+		t.Fatalf("expected total amount %v, got %v",
+			paymentAmt+700, route.TotalAmount)
+	}
+}
+
+// TestAmtToForwardFromIncoming tests that the amount to forward that is
+// derived from an incoming amount is the largest amount for which the
+// incoming amount covers the fees, including inbound fees.
+func TestAmtToForwardFromIncoming(t *testing.T) {
+	t.Parallel()
+
+	policy := &channeldb.ChannelEdgePolicy{
+		FeeBaseMSat:               1000,
+		FeeProportionalMillionths: 2000,
+	}
+
+	inboundFees := []lnwire.Fee{
+		{},
+		{BaseFee: 500, FeeRate: 1000},
+		{BaseFee: -200, FeeRate: -500},
+		{BaseFee: -5000},
+	}
+
+	for _, inboundFee := range inboundFees {
+		for _, incoming := range []lnwire.MilliSatoshi{
+			2000, 123456, 100000000,
+		} {
+			amt := amtToForwardFromIncoming(
+				policy, inboundFee, incoming,
+			)
+
+			total := func(amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+				return amt + forwardingFee(
+					policy.ComputeFee(amt), inboundFee,
+					amt,
+				)
+			}
+
+			if total(amt) > incoming {
+				t.Fatalf("inbound fee %v: amount %v exceeds "+
+					"incoming amount %v", inboundFee, amt,
+					incoming)
+			}
+			if amt < incoming && total(amt+1) <= incoming {
+				t.Fatalf("inbound fee %v: amount %v for "+
+					"incoming amount %v is not the "+
+					"largest", inboundFee, amt, incoming)
+			}
+		}
+	}
+}
+
 func getAliasFromPubKey(pubKey route.Vertex,
 	aliases map[string]route.Vertex) string {
 
@@ -998,7 +1150,7 @@ func TestPathFindingWithAdditionalEdges(t *testing.T) {
 	}
 
 	find := func(r *RestrictParams) (
-		[]*channeldb.ChannelEdgePolicy, error) {
+		[]*pathEdge, error) {
 
 		return dbFindPath(
 			graph.graph, additionalEdges, nil,
@@ -1065,27 +1217,34 @@ func TestNewRoute(t *testing.T) {
 	createHop := func(baseFee lnwire.MilliSatoshi,
 		feeRate lnwire.MilliSatoshi,
 		bandwidth lnwire.MilliSatoshi,
-		timeLockDelta uint16) *channeldb.ChannelEdgePolicy {
-
-		return &channeldb.ChannelEdgePolicy{
-			Node: &channeldb.LightningNode{
-				Features: lnwire.NewFeatureVector(
-					nil, nil,
-				),
+		timeLockDelta uint16) *pathEdge {
+
+		return &pathEdge{
+			ChannelEdgePolicy: &channeldb.ChannelEdgePolicy{
+				Node: &channeldb.LightningNode{
+					Features: lnwire.NewFeatureVector(
+						nil, nil,
+					),
+				},
+				FeeProportionalMillionths: feeRate,
+				FeeBaseMSat:               baseFee,
+				TimeLockDelta:             timeLockDelta,
 			},
-			FeeProportionalMillionths: feeRate,
-			FeeBaseMSat:               baseFee,
-			TimeLockDelta:             timeLockDelta,
 		}
 	}
 
+	withInboundFee := func(hop *pathEdge, fee lnwire.Fee) *pathEdge {
+		hop.inboundFee = fee
+		return hop
+	}
+
 	testCases := []struct {
 		// name identifies the test case in the test output.
 		name string
 
 		// hops is the list of hops (the route) that gets passed into
 		// the call to newRoute.
-		hops []*channeldb.ChannelEdgePolicy
+		hops []*pathEdge
 
 		// paymentAmount is the amount that is send into the route
 		// indicated by hops.
@@ -1134,7 +1293,7 @@ func TestNewRoute(t *testing.T) {
 			// For a single hop payment, no fees are expected to be paid.
 			name:          "single hop",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*pathEdge{
 				createHop(100, 1000, 1000000, 10),
 			},
 			expectedFees:          []lnwire.MilliSatoshi{0},
@@ -1147,7 +1306,7 @@ func TestNewRoute(t *testing.T) {
 			// a fee to receive the payment.
 			name:          "two hop",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*pathEdge{
 				createHop(0, 1000, 1000000, 10),
 				createHop(30, 1000, 1000000, 5),
 			},
@@ -1162,7 +1321,7 @@ func TestNewRoute(t *testing.T) {
 			name:          "two hop tlv onion feature",
 			destFeatures:  tlvFeatures,
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*pathEdge{
 				createHop(0, 1000, 1000000, 10),
 				createHop(30, 1000, 1000000, 5),
 			},
@@ -1179,7 +1338,7 @@ func TestNewRoute(t *testing.T) {
 			destFeatures:  tlvPayAddrFeatures,
 			paymentAddr:   &testPaymentAddr,
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*pathEdge{
 				createHop(0, 1000, 1000000, 10),
 				createHop(30, 1000, 1000000, 5),
 			},
@@ -1199,7 +1358,7 @@ func TestNewRoute(t *testing.T) {
 			// gets rounded down to 1.
 			name:          "three hop",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*pathEdge{
 				createHop(0, 10, 1000000, 10),
 				createHop(0, 10, 1000000, 5),
 				createHop(0, 10, 1000000, 3),
@@ -1214,7 +1373,7 @@ func TestNewRoute(t *testing.T) {
 			// because of the increase amount to forward.
 			name:          "three hop with fee carry over",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*pathEdge{
 				createHop(0, 10000, 1000000, 10),
 				createHop(0, 10000, 1000000, 5),
 				createHop(0, 10000, 1000000, 3),
@@ -1229,7 +1388,7 @@ func TestNewRoute(t *testing.T) {
 			// effect.
 			name:          "three hop with minimal fees for carry over",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*pathEdge{
 				createHop(0, 10000, 1000000, 10),
 
 				// First hop charges 0.1% so the second hop fee
@@ -1244,6 +1403,43 @@ func TestNewRoute(t *testing.T) {
 			expectedTotalAmount:   101101,
 			expectedTimeLocks:     []uint32{4, 1, 1},
 			expectedTotalTimeLock: 9,
+		}, {
+			// A three hop payment where the first hop charges an
+			// inbound fee on top of its outbound fee. The inbound
+			// fee rate applies to the amount that includes the
+			// outbound fee.
+			name:          "three hop with inbound fee",
+			paymentAmount: 100000,
+			hops: []*pathEdge{
+				withInboundFee(
+					createHop(0, 0, 1000000, 10),
+					lnwire.Fee{BaseFee: 100, FeeRate: 1000},
+				),
+				createHop(1000, 0, 1000000, 5),
+				createHop(0, 0, 1000000, 3),
+			},
+			expectedFees:          []lnwire.MilliSatoshi{1201, 0, 0},
+			expectedTotalAmount:   101201,
+			expectedTimeLocks:     []uint32{4, 1, 1},
+			expectedTotalTimeLock: 9,
+		}, {
+			// A three hop payment where the inbound discount of
+			// the first hop exceeds its outbound fee. The total fee
+			// of the hop can't drop below zero.
+			name:          "three hop with inbound discount",
+			paymentAmount: 100000,
+			hops: []*pathEdge{
+				withInboundFee(
+					createHop(0, 0, 1000000, 10),
+					lnwire.Fee{BaseFee: -2000},
+				),
+				createHop(1000, 0, 1000000, 5),
+				createHop(0, 0, 1000000, 3),
+			},
+			expectedFees:          []lnwire.MilliSatoshi{0, 0, 0},
+			expectedTotalAmount:   100000,
+			expectedTimeLocks:     []uint32{4, 1, 1},
+			expectedTotalTimeLock: 9,
 		}}
 
 	for _, testCase := range testCases {
@@ -1480,7 +1676,7 @@ func TestDestTLVGraphFallback(t *testing.T) {
 	}
 
 	find := func(r *RestrictParams,
-		target route.Vertex) ([]*channeldb.ChannelEdgePolicy, error) {
+		target route.Vertex) ([]*pathEdge, error) {
 
 		return dbFindPath(
 			ctx.graph, nil, nil,
@@ -2212,7 +2408,7 @@ func TestPathFindSpecExample(t *testing.T) {
 }
 
 func assertExpectedPath(t *testing.T, aliasMap map[string]route.Vertex,
-	path []*channeldb.ChannelEdgePolicy, nodeAliases ...string) {
+	path []*pathEdge, nodeAliases ...string) {
 
 	if len(path) != len(nodeAliases) {
 		t.Fatal("number of hops and number of aliases do not match")
@@ -2585,7 +2781,7 @@ func testProbabilityRouting(t *testing.T, paymentAmt btcutil.Amount,
 
 	// Configure a probability source with the test parameters.
 	ctx.restrictParams.ProbabilitySource = func(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64 {
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 		if amt == 0 {
 			t.Fatal("expected non-zero amount")
@@ -2668,7 +2864,7 @@ func TestEqualCostRouteSelection(t *testing.T) {
 	target := ctx.testGraphInstance.aliasMap["target"]
 
 	ctx.restrictParams.ProbabilitySource = func(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64 {
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 		switch {
 		case fromNode == alias["source"] && toNode == alias["a"]:
@@ -2872,7 +3068,7 @@ func (c *pathFindingTestContext) cleanup() {
 }
 
 func (c *pathFindingTestContext) findPath(target route.Vertex,
-	amt lnwire.MilliSatoshi) ([]*channeldb.ChannelEdgePolicy,
+	amt lnwire.MilliSatoshi) ([]*pathEdge,
 	error) {
 
 	return dbFindPath(
@@ -2881,7 +3077,7 @@ func (c *pathFindingTestContext) findPath(target route.Vertex,
 	)
 }
 
-func (c *pathFindingTestContext) assertPath(path []*channeldb.ChannelEdgePolicy, expected []uint64) {
+func (c *pathFindingTestContext) assertPath(path []*pathEdge, expected []uint64) {
 	if len(path) != len(expected) {
 		c.t.Fatalf("expected path of length %v, but got %v",
 			len(expected), len(path))
@@ -2902,7 +3098,7 @@ func dbFindPath(graph *channeldb.ChannelGraph,
 	bandwidthHints map[uint64]lnwire.MilliSatoshi,
 	r *RestrictParams, cfg *PathFindingConfig,
 	source, target route.Vertex, amt lnwire.MilliSatoshi,
-	finalHtlcExpiry int32) ([]*channeldb.ChannelEdgePolicy, error) {
+	finalHtlcExpiry int32) ([]*pathEdge, error) {
 
 	routingTx, err := newDbRoutingTx(graph)
 	if err != nil {
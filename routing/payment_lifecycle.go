@@ -22,6 +22,7 @@ var errShardHandlerExiting = fmt.Errorf("shard handler exiting")
 // needed to resume if from any point.
 type paymentLifecycle struct {
 	router        *ChannelRouter
+	control       ControlTower
 	totalAmount   lnwire.MilliSatoshi
 	feeLimit      lnwire.MilliSatoshi
 	identifier    lntypes.Hash
@@ -29,6 +30,13 @@ type paymentLifecycle struct {
 	shardTracker  shards.ShardTracker
 	timeoutChan   <-chan time.Time
 	currentHeight int32
+
+	// probe is true if this is a probe payment. The shards of a probe
+	// carry a payment hash that is unknown to the destination, so they
+	// can't settle. Instead a shard is settled with the control tower
+	// when the destination fails it because it doesn't know the hash,
+	// which proves that it reached the destination.
+	probe bool
 }
 
 // payemntState holds a number of key insights learned from a given MPPayment
@@ -70,8 +78,10 @@ func (p *paymentLifecycle) paymentState(payment *channeldb.MPPayment) (
 
 	// If either an HTLC settled, or the payment has a payment level
 	// failure recorded, it means we should terminate the moment all shards
-	// have returned with a result.
-	terminate := settle != nil || failure != nil
+	// have returned with a result. Settled shards of a probe only mean
+	// that part of the amount reached the destination, so a probe goes on
+	// until the full amount reached it.
+	terminate := failure != nil || (settle != nil && !p.probe)
 
 	activeShards := payment.InFlightHTLCs()
 	return &paymentState{
@@ -86,8 +96,10 @@ func (p *paymentLifecycle) paymentState(payment *channeldb.MPPayment) (
 func (p *paymentLifecycle) resumePayment() ([32]byte, *route.Route, error) {
 	shardHandler := &shardHandler{
 		router:       p.router,
+		control:      p.control,
 		identifier:   p.identifier,
 		shardTracker: p.shardTracker,
+		probe:        p.probe,
 		shardErrors:  make(chan error),
 		quit:         make(chan struct{}),
 	}
@@ -100,7 +112,7 @@ func (p *paymentLifecycle) resumePayment() ([32]byte, *route.Route, error) {
 	// If we had any existing attempts outstanding, we'll start by spinning
 	// up goroutines that'll collect their results and deliver them to the
 	// lifecycle loop below.
-	payment, err := p.router.cfg.Control.FetchPayment(
+	payment, err := p.control.FetchPayment(
 		p.identifier,
 	)
 	if err != nil {
@@ -130,7 +142,7 @@ lifecycle:
 		// the payment from the ControlTower. This ensures that we will
 		// act on the latest available information, whether we are
 		// resuming an existing payment or just sent a new attempt.
-		payment, err := p.router.cfg.Control.FetchPayment(
+		payment, err := p.control.FetchPayment(
 			p.identifier,
 		)
 		if err != nil {
@@ -155,9 +167,10 @@ lifecycle:
 		// ready to exit.
 		case state.terminate && state.numShardsInFlight == 0:
 			// Find the first successful shard and return
-			// the preimage and route.
+			// the preimage and route. The settled shards of a
+			// failed probe don't make it a success.
 			for _, a := range payment.HTLCs {
-				if a.Settle != nil {
+				if a.Settle != nil && !p.probe {
 					return a.Settle.Preimage, &a.Route, nil
 				}
 			}
@@ -165,6 +178,14 @@ lifecycle:
 			// Payment failed.
 			return [32]byte{}, nil, *payment.FailureReason
 
+		// All shards of the probe reached the destination, so the
+		// probe succeeded. Its routes can be fetched from the control
+		// tower.
+		case p.probe && state.remainingAmt == 0 &&
+			state.numShardsInFlight == 0:
+
+			return [32]byte{}, nil, nil
+
 		// If we either reached a terminal error condition (but had
 		// active shards still) or there is no remaining value to send,
 		// we'll wait for a shard outcome.
@@ -192,7 +213,7 @@ lifecycle:
 			// tower, no further shards will be launched and we'll
 			// return with an error the moment all active shards
 			// have finished.
-			saveErr := p.router.cfg.Control.Fail(
+			saveErr := p.control.Fail(
 				p.identifier, channeldb.FailureReasonTimeout,
 			)
 			if saveErr != nil {
@@ -231,7 +252,7 @@ lifecycle:
 					"failed with no route: %v",
 					p.identifier, failureCode)
 
-				saveErr := p.router.cfg.Control.Fail(
+				saveErr := p.control.Fail(
 					p.identifier, failureCode,
 				)
 				if saveErr != nil {
@@ -303,8 +324,12 @@ lifecycle:
 type shardHandler struct {
 	identifier   lntypes.Hash
 	router       *ChannelRouter
+	control      ControlTower
 	shardTracker shards.ShardTracker
 
+	// probe is true if the shards belong to a probe payment.
+	probe bool
+
 	// shardErrors is a channel where errors collected by calling
 	// collectResultAsync will be delivered. These results are meant to be
 	// inspected by calling waitForShard or checkShards, and the channel
@@ -398,7 +423,7 @@ func (p *shardHandler) launchShard(rt *route.Route,
 	// of the payment that we attempted to send, such that we can query the
 	// Switch for its whereabouts. The route is needed to handle the result
 	// when it eventually comes back.
-	err = p.router.cfg.Control.RegisterAttempt(p.identifier, attempt)
+	err = p.control.RegisterAttempt(p.identifier, attempt)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -566,6 +591,14 @@ func (p *shardHandler) collectResult(attempt *channeldb.HTLCAttemptInfo) (
 		return nil, errShardHandlerExiting
 	}
 
+	// A probe shard that was failed by the destination because it doesn't
+	// know the payment hash reached the destination, so we settle it.
+	if result.Error != nil && p.probe &&
+		probeReachedDestination(&attempt.Route, result.Error) {
+
+		return p.settleProbeAttempt(attempt, result.Error)
+	}
+
 	// In case of a payment failure, fail the attempt with the control
 	// tower and return.
 	if result.Error != nil {
@@ -595,7 +628,7 @@ func (p *shardHandler) collectResult(attempt *channeldb.HTLCAttemptInfo) (
 
 	// In case of success we atomically store settle result to the DB move
 	// the shard to the settled state.
-	htlcAttempt, err := p.router.cfg.Control.SettleAttempt(
+	htlcAttempt, err := p.control.SettleAttempt(
 		p.identifier, attempt.AttemptID,
 		&channeldb.HTLCSettleInfo{
 			Preimage:   result.Preimage,
@@ -735,7 +768,7 @@ func (p *shardHandler) handleSendError(attempt *channeldb.HTLCAttemptInfo,
 	log.Infof("Payment %v failed: final_outcome=%v, raw_err=%v",
 		p.identifier, *reason, sendErr)
 
-	err := p.router.cfg.Control.Fail(p.identifier, *reason)
+	err := p.control.Fail(p.identifier, *reason)
 	if err != nil {
 		return err
 	}
@@ -762,7 +795,7 @@ func (p *shardHandler) failAttempt(attempt *channeldb.HTLCAttemptInfo,
 		return nil, err
 	}
 
-	return p.router.cfg.Control.FailAttempt(
+	return p.control.FailAttempt(
 		p.identifier, attempt.AttemptID,
 		failInfo,
 	)
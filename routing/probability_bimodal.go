@@ -0,0 +1,388 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// BimodalEstimatorName is the name of the bimodal estimator.
+	BimodalEstimatorName = "bimodal"
+
+	// DefaultBimodalScaleMsat is the default value for BimodalScaleMsat in
+	// BimodalConfig. It describes the distribution of funds in the network
+	// based on empirical findings. We assume an unbalanced network by
+	// default.
+	DefaultBimodalScaleMsat = lnwire.MilliSatoshi(300000000)
+
+	// DefaultBimodalNodeWeight is the default value for the
+	// BimodalNodeWeight in BimodalConfig. It is chosen such that past
+	// forwardings on other channels of a router are only slightly taken
+	// into account.
+	DefaultBimodalNodeWeight = 0.2
+
+	// DefaultBimodalDecayTime is the default value for BimodalDecayTime.
+	// We will forget about previous learnings about channel liquidity on
+	// the timescale of about a week.
+	DefaultBimodalDecayTime = 7 * 24 * time.Hour
+
+	// unknownCapacity is the capacity that is assumed for channels of
+	// which we don't know the capacity, for example channels from route
+	// hints. It is chosen large enough to not restrict the amount that is
+	// sent through such a channel.
+	unknownCapacity = btcutil.Amount(10 * btcutil.SatoshiPerBitcoin)
+)
+
+var (
+	// ErrInvalidScale is returned when we get a scale below or equal
+	// zero.
+	ErrInvalidScale = errors.New("scale must be > 0")
+
+	// ErrInvalidNodeWeight is returned when we get a node weight that is
+	// out of range.
+	ErrInvalidNodeWeight = errors.New("node weight must be in [0, 1]")
+
+	// ErrInvalidDecayTime is returned when we get a decay time below or
+	// equal zero.
+	ErrInvalidDecayTime = errors.New("decay time must be larger than zero")
+)
+
+// BimodalConfig contains configuration for our bimodal probability estimator.
+type BimodalConfig struct {
+	// BimodalNodeWeight defines how strongly other previous forwardings on
+	// channels of a router should be taken into account when computing a
+	// channel's probability to route. The allowed values are in the range
+	// [0, 1], where a value of 0 means that only direct information about a
+	// channel is taken into account.
+	BimodalNodeWeight float64
+
+	// BimodalScaleMsat describes the scale over which channels
+	// statistically have some liquidity left. The value determines how
+	// quickly the bimodal distribution drops off from the edges of a
+	// channel. A larger value (compared to typical channel capacities)
+	// means that the distribution is closer to a uniform one.
+	BimodalScaleMsat lnwire.MilliSatoshi
+
+	// BimodalDecayTime is the scale for the exponential information decay
+	// over time for previous successes or failures. After this time, the
+	// liquidity bounds that were learned about a channel are mostly
+	// forgotten again.
+	BimodalDecayTime time.Duration
+}
+
+// validate checks the configuration of the estimator for allowed values.
+func (p BimodalConfig) validate() error {
+	if p.BimodalDecayTime <= 0 {
+		return ErrInvalidDecayTime
+	}
+
+	if p.BimodalNodeWeight < 0 || p.BimodalNodeWeight > 1 {
+		return ErrInvalidNodeWeight
+	}
+
+	if p.BimodalScaleMsat == 0 {
+		return ErrInvalidScale
+	}
+
+	return nil
+}
+
+// DefaultBimodalConfig returns the default configuration for the estimator.
+func DefaultBimodalConfig() BimodalConfig {
+	return BimodalConfig{
+		BimodalNodeWeight: DefaultBimodalNodeWeight,
+		BimodalScaleMsat:  DefaultBimodalScaleMsat,
+		BimodalDecayTime:  DefaultBimodalDecayTime,
+	}
+}
+
+// BimodalEstimator returns node and pair probabilities based on historical
+// payment results. It is based on the assumption that the liquidity of a
+// channel is mostly found at either side of the channel, which is described
+// by a bimodal distribution of the liquidity over the channel capacity.
+//
+// Mission control records for every channel the largest amount that was
+// forwarded successfully and the smallest amount that failed. Those amounts
+// are a lower and an upper bound for the liquidity of the channel. The
+// estimator conditions the liquidity distribution on those bounds, which
+// relax back to the full capacity of the channel over time (BimodalDecayTime).
+// Results of other channels of the same node can be mixed in
+// (BimodalNodeWeight).
+type BimodalEstimator struct {
+	// BimodalConfig contains configuration options for our estimator.
+	BimodalConfig
+}
+
+// NewBimodalEstimator creates a new BimodalEstimator.
+func NewBimodalEstimator(cfg BimodalConfig) (*BimodalEstimator, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &BimodalEstimator{
+		BimodalConfig: cfg,
+	}, nil
+}
+
+// Compile-time checks that interfaces are implemented.
+var _ Estimator = (*BimodalEstimator)(nil)
+var _ estimatorConfig = (*BimodalConfig)(nil)
+
+// Config returns the estimator's configuration.
+func (p *BimodalEstimator) Config() estimatorConfig {
+	return p.BimodalConfig
+}
+
+// String returns the estimator's configuration as a string representation.
+func (p *BimodalEstimator) String() string {
+	return fmt.Sprintf("estimator type: %v, decay time: %v, liquidity "+
+		"scale: %v, node weight: %v", BimodalEstimatorName,
+		p.BimodalDecayTime, p.BimodalScaleMsat, p.BimodalNodeWeight)
+}
+
+// PairProbability estimates the probability of successfully traversing to
+// toNode based on historical payment outcomes for the from node. Those outcomes
+// are passed in via the results parameter.
+//
+// NOTE: This is a part of the Estimator interface.
+func (p *BimodalEstimator) PairProbability(now time.Time,
+	results NodeResults, toNode route.Vertex, amt lnwire.MilliSatoshi,
+	capacity btcutil.Amount) float64 {
+
+	if capacity == 0 {
+		capacity = unknownCapacity
+	}
+
+	// We first compute the probability of the channel itself, taking the
+	// liquidity bounds into account that we learned about it.
+	directProbability := p.directProbability(
+		now, results, toNode, amt, lnwire.NewMSatFromSatoshis(capacity),
+	)
+
+	// The final probability also takes into account what we learned about
+	// the other channels of the node.
+	return p.calculateProbability(directProbability, now, results, toNode)
+}
+
+// LocalPairProbability estimates the probability of successfully traversing
+// our own local channels to toNode.
+//
+// NOTE: This is a part of the Estimator interface.
+func (p *BimodalEstimator) LocalPairProbability(now time.Time,
+	results NodeResults, toNode route.Vertex) float64 {
+
+	// For local channels we know exactly how much we can send, so we
+	// assume that they succeed.
+	directProbability := 1.0
+
+	// If a local channel failed unexpectedly, we reduce the probability
+	// for some time to avoid retrying it endlessly.
+	result, ok := results[toNode]
+	if ok && !result.FailTime.IsZero() {
+		directProbability -= p.decay(now, result.FailTime)
+	}
+
+	return directProbability
+}
+
+// decay returns a factor in the range [0, 1] that describes how much of the
+// information of a result at the given time is still retained. It is one for
+// fresh results and decays exponentially with the configured decay time.
+func (p *BimodalEstimator) decay(now, resultTime time.Time) float64 {
+	age := now.Sub(resultTime)
+
+	// Results from the future can happen if the clock went backwards, we
+	// consider them fresh.
+	if age < 0 {
+		age = 0
+	}
+
+	return math.Exp(-float64(age) / float64(p.BimodalDecayTime))
+}
+
+// canSend returns the lower bound of the liquidity of a channel, which is the
+// largest amount that was forwarded successfully relaxed back to zero over
+// time.
+func (p *BimodalEstimator) canSend(result TimedPairResult, now time.Time,
+	capacity lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	if result.SuccessTime.IsZero() {
+		return 0
+	}
+
+	successAmt := result.SuccessAmt
+	if successAmt > capacity {
+		successAmt = capacity
+	}
+
+	decay := p.decay(now, result.SuccessTime)
+
+	return lnwire.MilliSatoshi(float64(successAmt) * decay)
+}
+
+// cannotSend returns the upper bound of the liquidity of a channel, which is
+// the smallest amount that failed relaxed back to the capacity over time.
+func (p *BimodalEstimator) cannotSend(result TimedPairResult, now time.Time,
+	capacity lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	if result.FailTime.IsZero() {
+		return capacity
+	}
+
+	// A failure amount of zero means that the failure was independent of
+	// the amount, so nothing can be sent until the failure decayed.
+	failAmt := result.FailAmt
+	if failAmt > capacity {
+		failAmt = capacity
+	}
+
+	decay := p.decay(now, result.FailTime)
+
+	return capacity - lnwire.MilliSatoshi(float64(capacity-failAmt)*decay)
+}
+
+// directProbability computes the probability to send the amount through the
+// channel to toNode, based on the liquidity bounds that were learned about the
+// channel.
+func (p *BimodalEstimator) directProbability(now time.Time,
+	results NodeResults, toNode route.Vertex, amt,
+	capacity lnwire.MilliSatoshi) float64 {
+
+	// Without any results, the liquidity can be anywhere within the
+	// capacity of the channel.
+	successAmt := lnwire.MilliSatoshi(0)
+	failAmt := capacity
+
+	if result, ok := results[toNode]; ok {
+		successAmt = p.canSend(result, now, capacity)
+		failAmt = p.cannotSend(result, now, capacity)
+
+		// Mission control keeps the success amount below the fail
+		// amount, but capping both at the capacity can still make
+		// them overlap. In that case, we trust the most recent result.
+		if successAmt >= failAmt {
+			if result.FailTime.After(result.SuccessTime) {
+				successAmt = 0
+			} else {
+				failAmt = capacity
+			}
+		}
+	}
+
+	return p.probabilityFormula(capacity, successAmt, failAmt, amt)
+}
+
+// probabilityFormula computes the probability that the liquidity of a channel
+// is at least the amount, given that it is known to lie within the range
+// [successAmt, failAmt). The liquidity is assumed to follow the bimodal
+// distribution
+//
+//	P(x) ~ exp(-x/s) + exp((x-c)/s)
+//
+// with the capacity c and the scale s.
+func (p *BimodalEstimator) probabilityFormula(capacityMsat, successAmtMsat,
+	failAmtMsat, amtMsat lnwire.MilliSatoshi) float64 {
+
+	switch {
+	// Amounts that were forwarded successfully are assumed to succeed.
+	case amtMsat <= successAmtMsat:
+		return 1
+
+	// Amounts that failed or exceed the capacity can't be sent.
+	case amtMsat >= failAmtMsat || amtMsat > capacityMsat:
+		return 0
+	}
+
+	var (
+		capacity   = float64(capacityMsat)
+		successAmt = float64(successAmtMsat)
+		failAmt    = float64(failAmtMsat)
+		amt        = float64(amtMsat)
+		scale      = float64(p.BimodalScaleMsat)
+	)
+
+	// primitive is the antiderivative of the liquidity distribution, which
+	// we use to integrate over the liquidity range. It is scaled by 1/s,
+	// which cancels out in the ratio below.
+	primitive := func(x float64) float64 {
+		return -math.Exp(-x/scale) + math.Exp((x-capacity)/scale)
+	}
+
+	normalization := primitive(failAmt) - primitive(successAmt)
+
+	// If the scale is small compared to the liquidity range, the
+	// distribution may not be representable within the range. We fall
+	// back to a uniform distribution in that case.
+	if normalization <= 0 || math.IsNaN(normalization) ||
+		math.IsInf(normalization, 0) {
+
+		return (failAmt - amt) / (failAmt - successAmt)
+	}
+
+	probability := (primitive(failAmt) - primitive(amt)) / normalization
+
+	// Guard against rounding errors.
+	return math.Max(0, math.Min(1, probability))
+}
+
+// calculateProbability combines the direct probability of a channel with the
+// results of the other channels of the node we try to send from.
+func (p *BimodalEstimator) calculateProbability(directProbability float64,
+	now time.Time, results NodeResults, toNode route.Vertex) float64 {
+
+	// If we don't take other channels into account, we can return early.
+	if p.BimodalNodeWeight == 0 {
+		return directProbability
+	}
+
+	// If we have recent information about the channel itself, we only use
+	// the direct probability. Otherwise results of the other channels of
+	// the node would keep the probability up after a failure, which is
+	// needed to start splitting the payment.
+	if result, ok := results[toNode]; ok {
+		latest := result.SuccessTime
+		if result.FailTime.After(latest) {
+			latest = result.FailTime
+		}
+
+		if now.Sub(latest) < p.BimodalDecayTime {
+			return directProbability
+		}
+	}
+
+	// The direct probability is weighted fully, the results of the other
+	// channels are weighted by the node weight and by how recent they
+	// are. Successes count with probability one and failures with
+	// probability zero.
+	totalProbabilities := directProbability
+	totalWeights := 1.0
+
+	for peer, result := range results {
+		if peer == toNode {
+			continue
+		}
+
+		if !result.SuccessTime.IsZero() {
+			weight := p.BimodalNodeWeight *
+				p.decay(now, result.SuccessTime)
+
+			totalProbabilities += weight
+			totalWeights += weight
+		}
+
+		if !result.FailTime.IsZero() {
+			weight := p.BimodalNodeWeight *
+				p.decay(now, result.FailTime)
+
+			totalWeights += weight
+		}
+	}
+
+	return totalProbabilities / totalWeights
+}
@@ -0,0 +1,304 @@
+package routing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// bimodalTestCapacity is the capacity of the test channels.
+	bimodalTestCapacity = btcutil.Amount(1000000)
+
+	// bimodalTestScale is the liquidity scale of the test estimator.
+	bimodalTestScale = lnwire.MilliSatoshi(100000000)
+
+	// bimodalTestDecayTime is the decay time of the test estimator.
+	bimodalTestDecayTime = time.Hour
+)
+
+// newBimodalTestEstimator returns a bimodal estimator with the test parameters
+// and the given node weight.
+func newBimodalTestEstimator(t *testing.T,
+	nodeWeight float64) *BimodalEstimator {
+
+	estimator, err := NewBimodalEstimator(BimodalConfig{
+		BimodalScaleMsat:  bimodalTestScale,
+		BimodalNodeWeight: nodeWeight,
+		BimodalDecayTime:  bimodalTestDecayTime,
+	})
+	require.NoError(t, err)
+
+	return estimator
+}
+
+// TestBimodalConfigValidate asserts that invalid bimodal configurations are
+// rejected.
+func TestBimodalConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, DefaultBimodalConfig().validate())
+
+	cfg := DefaultBimodalConfig()
+	cfg.BimodalScaleMsat = 0
+	require.Equal(t, ErrInvalidScale, cfg.validate())
+
+	cfg = DefaultBimodalConfig()
+	cfg.BimodalNodeWeight = 1.1
+	require.Equal(t, ErrInvalidNodeWeight, cfg.validate())
+
+	cfg = DefaultBimodalConfig()
+	cfg.BimodalDecayTime = 0
+	require.Equal(t, ErrInvalidDecayTime, cfg.validate())
+}
+
+// TestBimodalProbabilityFormula tests the probability of the bimodal liquidity
+// distribution for different liquidity bounds.
+func TestBimodalProbabilityFormula(t *testing.T) {
+	t.Parallel()
+
+	estimator := newBimodalTestEstimator(t, 0)
+	capacity := lnwire.NewMSatFromSatoshis(bimodalTestCapacity)
+
+	tests := []struct {
+		name       string
+		successAmt lnwire.MilliSatoshi
+		failAmt    lnwire.MilliSatoshi
+		amt        lnwire.MilliSatoshi
+		expected   float64
+	}{
+		{
+			name:     "no info, small amount",
+			failAmt:  capacity,
+			amt:      1000,
+			expected: 1,
+		},
+		{
+			// The distribution is symmetric, so half the capacity
+			// has a probability of one half.
+			name:     "no info, half capacity",
+			failAmt:  capacity,
+			amt:      capacity / 2,
+			expected: 0.5,
+		},
+		{
+			name:     "no info, full capacity",
+			failAmt:  capacity,
+			amt:      capacity,
+			expected: 0,
+		},
+		{
+			name:     "above capacity",
+			failAmt:  capacity,
+			amt:      capacity + 1,
+			expected: 0,
+		},
+		{
+			name:       "below success amount",
+			successAmt: capacity / 4,
+			failAmt:    capacity,
+			amt:        capacity / 4,
+			expected:   1,
+		},
+		{
+			name:     "above fail amount",
+			failAmt:  capacity / 4,
+			amt:      capacity / 4,
+			expected: 0,
+		},
+		{
+			// With both bounds in the middle of the channel, the
+			// distribution is nearly flat between them.
+			name:       "between bounds",
+			successAmt: capacity * 4 / 10,
+			failAmt:    capacity * 6 / 10,
+			amt:        capacity / 2,
+			expected:   0.5,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			p := estimator.probabilityFormula(
+				capacity, test.successAmt, test.failAmt,
+				test.amt,
+			)
+			require.InDelta(t, test.expected, p, 0.001)
+		})
+	}
+}
+
+// TestBimodalProbabilityFormulaSmallScale asserts that a scale that is small
+// compared to the liquidity range doesn't lead to invalid probabilities.
+func TestBimodalProbabilityFormulaSmallScale(t *testing.T) {
+	t.Parallel()
+
+	estimator := newBimodalTestEstimator(t, 0)
+	estimator.BimodalScaleMsat = 1
+
+	capacity := lnwire.NewMSatFromSatoshis(bimodalTestCapacity)
+	p := estimator.probabilityFormula(
+		capacity, capacity/4, capacity*3/4, capacity/2,
+	)
+	require.False(t, math.IsNaN(p))
+	require.InDelta(t, 0.5, p, 0.001)
+}
+
+// TestBimodalPairProbability tests that the liquidity bounds that were learned
+// from results relax over time.
+func TestBimodalPairProbability(t *testing.T) {
+	t.Parallel()
+
+	estimator := newBimodalTestEstimator(t, 0)
+	capacity := lnwire.NewMSatFromSatoshis(bimodalTestCapacity)
+	toNode := route.Vertex{node1}
+
+	results := NodeResults{
+		toNode: {
+			SuccessTime: testTime,
+			SuccessAmt:  capacity / 4,
+			FailTime:    testTime,
+			FailAmt:     capacity * 3 / 4,
+		},
+	}
+
+	pairProbability := func(now time.Time,
+		amt lnwire.MilliSatoshi) float64 {
+
+		return estimator.PairProbability(
+			now, results, toNode, amt, bimodalTestCapacity,
+		)
+	}
+
+	// Fresh results determine the bounds exactly.
+	require.Equal(t, 1.0, pairProbability(testTime, capacity/4))
+	require.Equal(t, 0.0, pairProbability(testTime, capacity*3/4))
+	require.InDelta(t, 0.5, pairProbability(testTime, capacity/2), 0.001)
+
+	// After some time, the bounds relaxed, so the amount that
+	// succeeded before isn't certain to succeed anymore and the amount
+	// that failed may succeed again.
+	later := testTime.Add(bimodalTestDecayTime)
+	p := pairProbability(later, capacity/4)
+	require.True(t, p > 0 && p < 1, "unexpected probability %v", p)
+
+	p = pairProbability(later, capacity*3/4)
+	require.True(t, p > 0 && p < 1, "unexpected probability %v", p)
+
+	// An amount-independent failure prevents all amounts from being sent
+	// until it decayed.
+	results[toNode] = TimedPairResult{
+		FailTime: testTime,
+	}
+	require.Equal(t, 0.0, pairProbability(testTime, 1000))
+	require.InDelta(t, 1, pairProbability(
+		testTime.Add(100*bimodalTestDecayTime), 1000,
+	), 0.001)
+
+	// A channel with unknown capacity is treated as a large channel, so
+	// an amount that is large compared to the scale has a probability of
+	// one half instead of zero.
+	p = estimator.PairProbability(
+		testTime, NodeResults{}, toNode, 2*capacity, 0,
+	)
+	require.InDelta(t, 0.5, p, 0.001)
+}
+
+// TestBimodalNodeWeight tests that the results of other channels of a node are
+// taken into account if there is no recent information about the channel
+// itself.
+func TestBimodalNodeWeight(t *testing.T) {
+	t.Parallel()
+
+	estimator := newBimodalTestEstimator(t, 1)
+	capacity := lnwire.NewMSatFromSatoshis(bimodalTestCapacity)
+	amt := capacity / 2
+
+	// Without results of other channels, we get the direct probability
+	// of one half.
+	untriedP := estimator.PairProbability(
+		testTime, NodeResults{}, route.Vertex{untriedNode}, amt,
+		bimodalTestCapacity,
+	)
+	require.InDelta(t, 0.5, untriedP, 0.001)
+
+	// Fresh failures of other channels lower the probability, with a node
+	// weight of one two failures give a weight of two: 0.5 / (1 + 2).
+	results := NodeResults{
+		route.Vertex{node1}: {
+			FailTime: testTime,
+			FailAmt:  amt,
+		},
+		route.Vertex{node2}: {
+			FailTime: testTime,
+			FailAmt:  amt,
+		},
+	}
+	p := estimator.PairProbability(
+		testTime, results, route.Vertex{untriedNode}, amt,
+		bimodalTestCapacity,
+	)
+	require.InDelta(t, 0.5/3, p, 0.001)
+
+	// Fresh successes raise it: (0.5 + 2) / (1 + 2).
+	results = NodeResults{
+		route.Vertex{node1}: {
+			SuccessTime: testTime,
+			SuccessAmt:  amt,
+		},
+		route.Vertex{node2}: {
+			SuccessTime: testTime,
+			SuccessAmt:  amt,
+		},
+	}
+	p = estimator.PairProbability(
+		testTime, results, route.Vertex{untriedNode}, amt,
+		bimodalTestCapacity,
+	)
+	require.InDelta(t, 2.5/3, p, 0.001)
+
+	// Recent direct information takes precedence over other channels.
+	results[route.Vertex{node3}] = TimedPairResult{
+		FailTime: testTime,
+		FailAmt:  amt,
+	}
+	p = estimator.PairProbability(
+		testTime, results, route.Vertex{node3}, amt,
+		bimodalTestCapacity,
+	)
+	require.Equal(t, 0.0, p)
+}
+
+// TestBimodalLocalPairProbability tests the probability of local channels,
+// which recovers after an unexpected failure.
+func TestBimodalLocalPairProbability(t *testing.T) {
+	t.Parallel()
+
+	estimator := newBimodalTestEstimator(t, 0)
+	toNode := route.Vertex{node1}
+
+	require.Equal(t, 1.0, estimator.LocalPairProbability(
+		testTime, NodeResults{}, toNode,
+	))
+
+	results := NodeResults{
+		toNode: {
+			FailTime: testTime,
+		},
+	}
+	require.Equal(t, 0.0, estimator.LocalPairProbability(
+		testTime, results, toNode,
+	))
+
+	p := estimator.LocalPairProbability(
+		testTime.Add(bimodalTestDecayTime), results, toNode,
+	)
+	require.InDelta(t, 1-math.Exp(-1), p, 0.001)
+}
@@ -0,0 +1,370 @@
+package routing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/routing/shards"
+)
+
+var (
+	// errProbeUnknownPayment is returned by the probe control tower for
+	// payments other than the probe.
+	errProbeUnknownPayment = errors.New("unknown probe payment")
+
+	// errProbeSubscribe is returned by the probe control tower, because
+	// probes can't be subscribed to.
+	errProbeSubscribe = errors.New("probes can't be subscribed to")
+)
+
+// ProbePayment sends a probe for the given payment to find out whether the
+// network has enough liquidity to deliver it, without risking any funds. The
+// probe is sent through the regular payment lifecycle, but with a random
+// payment hash that is unknown to the destination. A shard that reaches the
+// destination is failed by it because it doesn't know the hash, which proves
+// that the route was able to carry the shard. The results of all shards are
+// reported to mission control like those of regular payments.
+//
+// Probes aren't stored in the database. If the probe succeeds, the routes of
+// all shards that reached the destination are returned. If it fails, the
+// returned error is the channeldb.FailureReason of the probe.
+func (r *ChannelRouter) ProbePayment(payment *LightningPayment) ([]*route.Route,
+	error) {
+
+	if payment.amp != nil {
+		return nil, errors.New("AMP payments can't be probed")
+	}
+
+	// The probe uses a random payment hash, so that the destination can't
+	// settle any of its shards.
+	var paymentHash lntypes.Hash
+	if _, err := rand.Read(paymentHash[:]); err != nil {
+		return nil, err
+	}
+	payment.paymentHash = &paymentHash
+
+	// Multi-part probes need a payment address to carry the mpp record.
+	// The destination fails the shards before it looks at the address,
+	// so any address will do.
+	payment.PaymentAddr = nil
+	if payment.MaxParts > 1 {
+		var paymentAddr [32]byte
+		if _, err := rand.Read(paymentAddr[:]); err != nil {
+			return nil, err
+		}
+		payment.PaymentAddr = &paymentAddr
+	}
+
+	paySession, err := r.cfg.SessionSource.NewPaymentSession(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	control := newProbeControlTower()
+	err = control.InitPayment(paymentHash, &channeldb.PaymentCreationInfo{
+		PaymentIdentifier: paymentHash,
+		Value:             payment.Amount,
+		CreationTime:      r.cfg.Clock.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Probing payment of %v to %v with hash %v",
+		payment.Amount, payment.Target, paymentHash)
+
+	p := &paymentLifecycle{
+		router:        r,
+		control:       control,
+		totalAmount:   payment.Amount,
+		feeLimit:      payment.FeeLimit,
+		identifier:    paymentHash,
+		paySession:    paySession,
+		shardTracker:  shards.NewSimpleShardTracker(paymentHash, nil),
+		currentHeight: int32(atomic.LoadUint32(&r.bestHeight)),
+		probe:         true,
+	}
+
+	if payment.PayAttemptTimeout != 0 {
+		p.timeoutChan = time.After(payment.PayAttemptTimeout)
+	}
+
+	if _, _, err := p.resumePayment(); err != nil {
+		return nil, err
+	}
+
+	probe, err := control.FetchPayment(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []*route.Route
+	for _, htlc := range probe.HTLCs {
+		htlc := htlc
+
+		if htlc.Settle != nil {
+			routes = append(routes, &htlc.Route)
+		}
+	}
+
+	log.Debugf("Probe %v reached %v with %v shards", paymentHash,
+		payment.Target, len(routes))
+
+	return routes, nil
+}
+
+// probeReachedDestination returns true if the given failure of a probe shard
+// was returned by the final hop of its route because it didn't know the
+// payment hash.
+func probeReachedDestination(rt *route.Route, sendErr error) bool {
+	fErr, ok := sendErr.(*htlcswitch.ForwardingError)
+	if !ok || fErr.FailureSourceIdx != len(rt.Hops) {
+		return false
+	}
+
+	_, ok = fErr.WireMessage().(*lnwire.FailIncorrectDetails)
+	return ok
+}
+
+// settleProbeAttempt reports a probe shard that reached the destination to
+// mission control and settles it with the control tower.
+func (p *shardHandler) settleProbeAttempt(attempt *channeldb.HTLCAttemptInfo,
+	sendErr error) (*shardResult, error) {
+
+	log.Debugf("Probe shard %v of %v reached the destination",
+		attempt.AttemptID, p.identifier)
+
+	// Mission control records the route as a success for failures of the
+	// destination that prove that the shard reached it. The final failure
+	// reason that it returns only applies to regular payments.
+	_ = p.router.processSendError(
+		attempt.AttemptID, &attempt.Route, sendErr,
+	)
+
+	htlcAttempt, err := p.control.SettleAttempt(
+		p.identifier, attempt.AttemptID, &channeldb.HTLCSettleInfo{
+			SettleTime: p.router.cfg.Clock.Now(),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shardResult{
+		attempt: htlcAttempt,
+	}, nil
+}
+
+// probeControlTower is an in-memory ControlTower that tracks a single probe
+// payment. Probes don't need to survive restarts, because their shards can't
+// be settled, so they aren't written to the database.
+type probeControlTower struct {
+	payment *channeldb.MPPayment
+	sync.Mutex
+}
+
+// newProbeControlTower creates a new control tower for a probe.
+func newProbeControlTower() *probeControlTower {
+	return &probeControlTower{}
+}
+
+// fetch returns the probe payment if it has the given hash.
+//
+// NOTE: The caller must hold the lock.
+func (p *probeControlTower) fetch(
+	paymentHash lntypes.Hash) (*channeldb.MPPayment, error) {
+
+	if p.payment == nil ||
+		p.payment.Info.PaymentIdentifier != paymentHash {
+
+		return nil, errProbeUnknownPayment
+	}
+
+	return p.payment, nil
+}
+
+// updateAttempt applies the given update to an attempt of the probe and
+// returns a copy of the updated attempt.
+func (p *probeControlTower) updateAttempt(paymentHash lntypes.Hash,
+	attemptID uint64, update func(*channeldb.HTLCAttempt)) (
+	*channeldb.HTLCAttempt, error) {
+
+	p.Lock()
+	defer p.Unlock()
+
+	payment, err := p.fetch(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range payment.HTLCs {
+		htlc := &payment.HTLCs[i]
+		if htlc.AttemptID != attemptID {
+			continue
+		}
+
+		if htlc.Settle != nil || htlc.Failure != nil {
+			return nil, fmt.Errorf("attempt %v already resolved",
+				attemptID)
+		}
+
+		update(htlc)
+		result := *htlc
+
+		return &result, nil
+	}
+
+	return nil, fmt.Errorf("attempt %v not found", attemptID)
+}
+
+// InitPayment records the probe payment.
+//
+// NOTE: Part of the ControlTower interface.
+func (p *probeControlTower) InitPayment(paymentHash lntypes.Hash,
+	info *channeldb.PaymentCreationInfo) error {
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.payment != nil {
+		return channeldb.ErrPaymentInFlight
+	}
+
+	p.payment = &channeldb.MPPayment{
+		Info:   info,
+		Status: channeldb.StatusInFlight,
+	}
+
+	return nil
+}
+
+// RegisterAttempt records a new shard of the probe.
+//
+// NOTE: Part of the ControlTower interface.
+func (p *probeControlTower) RegisterAttempt(paymentHash lntypes.Hash,
+	attempt *channeldb.HTLCAttemptInfo) error {
+
+	p.Lock()
+	defer p.Unlock()
+
+	payment, err := p.fetch(paymentHash)
+	if err != nil {
+		return err
+	}
+
+	if payment.FailureReason != nil {
+		return channeldb.ErrPaymentTerminal
+	}
+
+	sentAmt, _ := payment.SentAmt()
+	if sentAmt+attempt.Route.ReceiverAmt() > payment.Info.Value {
+		return channeldb.ErrValueExceedsAmt
+	}
+
+	payment.HTLCs = append(payment.HTLCs, channeldb.HTLCAttempt{
+		HTLCAttemptInfo: *attempt,
+	})
+
+	return nil
+}
+
+// SettleAttempt marks a shard of the probe as having reached the destination.
+//
+// NOTE: Part of the ControlTower interface.
+func (p *probeControlTower) SettleAttempt(paymentHash lntypes.Hash,
+	attemptID uint64, settleInfo *channeldb.HTLCSettleInfo) (
+	*channeldb.HTLCAttempt, error) {
+
+	return p.updateAttempt(
+		paymentHash, attemptID, func(htlc *channeldb.HTLCAttempt) {
+			htlc.Settle = settleInfo
+		},
+	)
+}
+
+// FailAttempt marks a shard of the probe as failed.
+//
+// NOTE: Part of the ControlTower interface.
+func (p *probeControlTower) FailAttempt(paymentHash lntypes.Hash,
+	attemptID uint64, failInfo *channeldb.HTLCFailInfo) (
+	*channeldb.HTLCAttempt, error) {
+
+	return p.updateAttempt(
+		paymentHash, attemptID, func(htlc *channeldb.HTLCAttempt) {
+			htlc.Failure = failInfo
+		},
+	)
+}
+
+// FetchPayment returns a copy of the probe payment.
+//
+// NOTE: Part of the ControlTower interface.
+func (p *probeControlTower) FetchPayment(
+	paymentHash lntypes.Hash) (*channeldb.MPPayment, error) {
+
+	p.Lock()
+	defer p.Unlock()
+
+	payment, err := p.fetch(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentCopy := *payment
+	paymentCopy.HTLCs = make([]channeldb.HTLCAttempt, len(payment.HTLCs))
+	copy(paymentCopy.HTLCs, payment.HTLCs)
+
+	return &paymentCopy, nil
+}
+
+// Fail marks the probe as failed with the given reason.
+//
+// NOTE: Part of the ControlTower interface.
+func (p *probeControlTower) Fail(paymentHash lntypes.Hash,
+	reason channeldb.FailureReason) error {
+
+	p.Lock()
+	defer p.Unlock()
+
+	payment, err := p.fetch(paymentHash)
+	if err != nil {
+		return err
+	}
+
+	payment.FailureReason = &reason
+	payment.Status = channeldb.StatusFailed
+
+	return nil
+}
+
+// FetchInFlightPayments returns no payments, because probes aren't resumed
+// after a restart.
+//
+// NOTE: Part of the ControlTower interface.
+func (p *probeControlTower) FetchInFlightPayments() ([]*channeldb.MPPayment,
+	error) {
+
+	return nil, nil
+}
+
+// SubscribePayment always fails, because probes can't be subscribed to.
+//
+// NOTE: Part of the ControlTower interface.
+func (p *probeControlTower) SubscribePayment(
+	paymentHash lntypes.Hash) (*ControlTowerSubscriber, error) {
+
+	return nil, errProbeSubscribe
+}
+
+// A compile time check to ensure probeControlTower implements the ControlTower
+// interface.
+var _ ControlTower = (*probeControlTower)(nil)
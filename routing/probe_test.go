@@ -0,0 +1,84 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProbePayment tests that a probe succeeds once its shard is failed by
+// the destination because of the unknown payment hash, and that the results
+// are reported to mission control without storing the probe.
+func TestProbePayment(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtxFromFile(
+		startingBlockHeight, basicGraphFilePath,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(1000)
+	payment := LightningPayment{
+		Target:   ctx.aliases["sophon"],
+		Amount:   paymentAmt,
+		FeeLimit: noFeeLimit,
+		MaxParts: 1,
+	}
+
+	// The route through son goku lacks liquidity, while the destination
+	// fails the probe shard that takes the route through pham nuwen,
+	// because it doesn't know the payment hash.
+	roasbeefSongoku := lnwire.NewShortChanIDFromInt(12345)
+	ctx.router.cfg.Payer.(*mockPaymentAttemptDispatcher).setPaymentResult(
+		func(firstHop lnwire.ShortChannelID) ([32]byte, error) {
+			if firstHop == roasbeefSongoku {
+				return [32]byte{}, htlcswitch.NewForwardingError(
+					&lnwire.FailTemporaryChannelFailure{},
+					1,
+				)
+			}
+
+			return [32]byte{}, htlcswitch.NewForwardingError(
+				&lnwire.FailIncorrectDetails{}, 2,
+			)
+		})
+
+	ctx.router.cfg.MissionControl.(*MissionControl).ResetHistory()
+
+	routes, err := ctx.router.ProbePayment(&payment)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, ctx.aliases["phamnuwen"], routes[0].Hops[0].PubKeyBytes)
+	require.Equal(t, paymentAmt, routes[0].ReceiverAmt())
+
+	// Mission control learned that the route reached the destination.
+	result := ctx.router.cfg.MissionControl.(*MissionControl).
+		GetPairHistorySnapshot(
+			ctx.aliases["phamnuwen"], ctx.aliases["sophon"],
+		)
+	require.Equal(t, paymentAmt, result.SuccessAmt)
+
+	// The probe isn't stored with the control tower of the router.
+	payments, err := ctx.router.cfg.Control.FetchInFlightPayments()
+	require.NoError(t, err)
+	require.Empty(t, payments)
+
+	// If no shard reaches the destination, the probe fails with the
+	// failure reason of the payment lifecycle.
+	ctx.router.cfg.Payer.(*mockPaymentAttemptDispatcher).setPaymentResult(
+		func(firstHop lnwire.ShortChannelID) ([32]byte, error) {
+			return [32]byte{}, htlcswitch.NewForwardingError(
+				&lnwire.FailUnknownNextPeer{}, 1,
+			)
+		})
+
+	ctx.router.cfg.MissionControl.(*MissionControl).ResetHistory()
+
+	_, err = ctx.router.ProbePayment(&payment)
+	require.Equal(t, channeldb.FailureReasonNoRoute, err)
+}
@@ -38,6 +38,12 @@ var (
 	// ErrAMPMissingMPP is returned when the caller tries to attach an AMP
 	// record but no MPP record is presented for the final hop.
 	ErrAMPMissingMPP = errors.New("cannot send AMP without MPP record")
+
+	// ErrIntermediateTrampolineHop is returned when a hop tries to deliver
+	// a trampoline onion to an intermediate hop, only final hops can
+	// receive trampoline onions.
+	ErrIntermediateTrampolineHop = errors.New("cannot send trampoline " +
+		"onion to intermediate")
 )
 
 // Vertex is a simple alias for the serialization of a compressed Bitcoin
@@ -119,6 +125,24 @@ type Hop struct {
 	// only be set for the final hop.
 	AMP *record.AMP
 
+	// EncryptedData is the encrypted recipient data of a hop inside of a
+	// blinded route. If set, the hop only learns its forwarding
+	// instructions from this data and PubKeyBytes is its blinded node ID.
+	EncryptedData []byte
+
+	// BlindingPoint is the blinding point of a blinded route. It is only
+	// set for the introduction node of the route.
+	BlindingPoint *btcec.PublicKey
+
+	// TotalAmtMsat is the total amount of a payment to a blinded route.
+	// It is only set for the final hop of a blinded route.
+	TotalAmtMsat lnwire.MilliSatoshi
+
+	// TrampolineOnion is the serialized trampoline onion of a payment that
+	// is delegated to a trampoline node. It is only set for the final hop,
+	// which is the trampoline node.
+	TrampolineOnion []byte
+
 	// CustomRecords if non-nil are a set of additional TLV records that
 	// should be included in the forwarding instructions for this node.
 	CustomRecords record.CustomSet
@@ -143,9 +167,29 @@ func (h *Hop) Copy() *Hop {
 		c.AMP = &a
 	}
 
+	if h.EncryptedData != nil {
+		c.EncryptedData = make([]byte, len(h.EncryptedData))
+		copy(c.EncryptedData, h.EncryptedData)
+	}
+
+	if h.TrampolineOnion != nil {
+		c.TrampolineOnion = make([]byte, len(h.TrampolineOnion))
+		copy(c.TrampolineOnion, h.TrampolineOnion)
+	}
+
 	return &c
 }
 
+// IsBlinded returns true if the hop is part of a blinded route.
+func (h *Hop) IsBlinded() bool {
+	return h.EncryptedData != nil
+}
+
+// isFinalBlinded returns true if the hop is the final hop of a blinded route.
+func (h *Hop) isFinalBlinded() bool {
+	return h.IsBlinded() && h.TotalAmtMsat != 0
+}
+
 // PackHopPayload writes to the passed io.Writer, the series of byes that can
 // be placed directly into the per-hop payload (EOB) for this hop. This will
 // include the required routing fields, as well as serializing any of the
@@ -161,6 +205,12 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 			"payloads")
 	}
 
+	// Hops inside of a blinded route receive their forwarding
+	// instructions in the encrypted data.
+	if h.IsBlinded() {
+		return h.packBlindedHopPayload(w)
+	}
+
 	// Otherwise, we'll need to make a new stream that includes our
 	// required routing fields, as well as these optional values.
 	var records []tlv.Record
@@ -205,6 +255,20 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 		}
 	}
 
+	// A trampoline onion is only ever destined for the final hop of the
+	// outer route, which is the trampoline node.
+	if h.TrampolineOnion != nil {
+		if nextChanID != 0 {
+			return ErrIntermediateTrampolineHop
+		}
+
+		records = append(
+			records, record.NewTrampolineOnionRecord(
+				&h.TrampolineOnion,
+			),
+		)
+	}
+
 	// Append any custom types destined for this hop.
 	tlvRecords := tlv.MapToRecords(h.CustomRecords)
 	records = append(records, tlvRecords...)
@@ -221,6 +285,48 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 	return tlvStream.Encode(w)
 }
 
+// packBlindedHopPayload writes the payload of a hop inside of a blinded route.
+// Intermediate hops only receive their encrypted data, and the introduction
+// node additionally receives the blinding point. The final hop also receives
+// the amount, the cltv expiry and the total amount of the payment.
+func (h *Hop) packBlindedHopPayload(w io.Writer) error {
+	if h.MPP != nil || h.AMP != nil {
+		return errors.New("cannot send MPP or AMP to blinded route")
+	}
+
+	records := []tlv.Record{
+		record.NewEncryptedDataRecord(&h.EncryptedData),
+	}
+
+	if h.BlindingPoint != nil {
+		records = append(
+			records, record.NewBlindingPointRecord(&h.BlindingPoint),
+		)
+	}
+
+	if h.isFinalBlinded() {
+		amt := uint64(h.AmtToForward)
+		totalAmt := uint64(h.TotalAmtMsat)
+		records = append(records,
+			record.NewAmtToFwdRecord(&amt),
+			record.NewLockTimeRecord(&h.OutgoingTimeLock),
+			record.NewTotalAmtMsatBlindedRecord(&totalAmt),
+		)
+	}
+
+	tlvRecords := tlv.MapToRecords(h.CustomRecords)
+	records = append(records, tlvRecords...)
+
+	tlv.SortRecords(records)
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
 // Size returns the total size this hop's payload would take up in the onion
 // packet.
 func (h *Hop) PayloadSize(nextChanID uint64) uint64 {
@@ -235,20 +341,46 @@ func (h *Hop) PayloadSize(nextChanID uint64) uint64 {
 			tlv.VarIntSize(length) + length
 	}
 
-	// Add amount size.
-	addRecord(record.AmtOnionType, tlv.SizeTUint64(uint64(h.AmtToForward)))
+	// Hops inside of a blinded route only include the amount and lock
+	// time if they are the final hop, and never the next hop.
+	includeFwdInfo := !h.IsBlinded() || h.isFinalBlinded()
 
-	// Add lock time size.
-	addRecord(
-		record.LockTimeOnionType,
-		tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
-	)
+	if includeFwdInfo {
+		// Add amount size.
+		addRecord(
+			record.AmtOnionType,
+			tlv.SizeTUint64(uint64(h.AmtToForward)),
+		)
+
+		// Add lock time size.
+		addRecord(
+			record.LockTimeOnionType,
+			tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
+		)
+	}
 
 	// Add next hop if present.
-	if nextChanID != 0 {
+	if nextChanID != 0 && !h.IsBlinded() {
 		addRecord(record.NextHopOnionType, 8)
 	}
 
+	// Add the blinded route fields if present.
+	if h.IsBlinded() {
+		addRecord(
+			record.EncryptedDataOnionType,
+			uint64(len(h.EncryptedData)),
+		)
+	}
+	if h.BlindingPoint != nil {
+		addRecord(record.BlindingPointOnionType, 33)
+	}
+	if h.isFinalBlinded() {
+		addRecord(
+			record.TotalAmtMsatBlindedType,
+			tlv.SizeTUint64(uint64(h.TotalAmtMsat)),
+		)
+	}
+
 	// Add mpp if present.
 	if h.MPP != nil {
 		addRecord(record.MPPOnionType, h.MPP.PayloadSize())
@@ -259,6 +391,14 @@ func (h *Hop) PayloadSize(nextChanID uint64) uint64 {
 		addRecord(record.AMPOnionType, h.AMP.PayloadSize())
 	}
 
+	// Add the trampoline onion if present.
+	if h.TrampolineOnion != nil {
+		addRecord(
+			record.TrampolineOnionType,
+			uint64(len(h.TrampolineOnion)),
+		)
+	}
+
 	// Add custom records.
 	for k, v := range h.CustomRecords {
 		addRecord(tlv.Type(k), uint64(len(v)))
@@ -159,6 +159,36 @@ func TestAMPHop(t *testing.T) {
 	}
 }
 
+// TestTrampolineHop asserts that a Hop will encode a trampoline onion to final
+// nodes, and fail when trying to send to intermediaries.
+func TestTrampolineHop(t *testing.T) {
+	t.Parallel()
+
+	hop := Hop{
+		ChannelID:        1,
+		OutgoingTimeLock: 44,
+		AmtToForward:     testAmt,
+		TrampolineOnion:  []byte{1, 2, 3},
+	}
+
+	// Encoding a trampoline onion to an intermediate hop should result in
+	// a failure.
+	var b bytes.Buffer
+	err := hop.PackHopPayload(&b, 2)
+	if err != ErrIntermediateTrampolineHop {
+		t.Fatalf("expected err: %v, got: %v",
+			ErrIntermediateTrampolineHop, err)
+	}
+
+	// Encoding a trampoline onion to a final hop should be successful.
+	hop.MPP = record.NewMPP(testAmt, testAddr)
+	b.Reset()
+	err = hop.PackHopPayload(&b, 0)
+	if err != nil {
+		t.Fatalf("expected err: %v, got: %v", nil, err)
+	}
+}
+
 // TestPayloadSize tests the payload size calculation that is provided by Hop
 // structs.
 func TestPayloadSize(t *testing.T) {
@@ -182,6 +212,7 @@ func TestPayloadSize(t *testing.T) {
 			OutgoingTimeLock: 700000,
 			MPP:              record.NewMPP(500, [32]byte{}),
 			AMP:              record.NewAMP([32]byte{}, [32]byte{}, 8),
+			TrampolineOnion:  bytes.Repeat([]byte{1}, 500),
 			CustomRecords: map[uint64][]byte{
 				100000:  {1, 2, 3},
 				1000000: {4, 5},
@@ -213,3 +244,63 @@ func TestPayloadSize(t *testing.T) {
 		}
 	}
 }
+
+// TestBlindedPayloadSize tests the payload size calculation of hops inside of
+// a blinded route.
+func TestBlindedPayloadSize(t *testing.T) {
+	hops := []*Hop{
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700000,
+			ChannelID:        63584534844,
+		},
+		{
+			PubKeyBytes:   testPubKeyBytes,
+			ChannelID:     3432483437438,
+			EncryptedData: []byte{1, 2, 3, 4, 5},
+			BlindingPoint: testPubKey,
+		},
+		{
+			PubKeyBytes:   testPubKeyBytes,
+			EncryptedData: []byte{6, 7},
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1000,
+			OutgoingTimeLock: 600000,
+			EncryptedData:    []byte{8, 9, 10},
+			TotalAmtMsat:     1000,
+		},
+	}
+
+	rt := Route{
+		Hops: hops,
+	}
+	path, err := rt.ToSphinxPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, onionHop := range path[:path.TrueRouteLength()] {
+		hop := hops[i]
+		var nextChan uint64
+		if i < len(hops)-1 {
+			nextChan = hops[i+1].ChannelID
+		}
+
+		expected := uint64(onionHop.HopPayload.NumBytes())
+		actual := hop.PayloadSize(nextChan)
+		if expected != actual {
+			t.Fatalf("unexpected payload size at hop %v: "+
+				"expected %v, got %v",
+				i, expected, actual)
+		}
+	}
+
+	// Blinded routes can't carry MPP records.
+	hops[3].MPP = record.NewMPP(1000, [32]byte{})
+	if _, err := rt.ToSphinxPath(); err == nil {
+		t.Fatalf("expected MPP to blinded route to fail")
+	}
+}
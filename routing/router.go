@@ -229,9 +229,10 @@ type MissionController interface {
 	ReportPaymentSuccess(attemptID uint64, rt *route.Route) error
 
 	// GetProbability is expected to return the success probability of a
-	// payment from fromNode along edge.
+	// payment from fromNode along edge. The capacity is the capacity of
+	// the channel between the nodes and is zero if unknown.
 	GetProbability(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
 }
 
 // FeeSchema is the set fee configuration for a Lightning Node on the network.
@@ -268,6 +269,12 @@ type ChannelPolicy struct {
 	// MinHTLC is the minimum HTLC size including fees we are allowed to
 	// forward over this channel.
 	MinHTLC *lnwire.MilliSatoshi
+
+	// InboundFee is the fee that is charged for payments that enter our
+	// node through this channel. Negative values give a discount on the
+	// outbound fee of the outgoing channel. If nil, the inbound fee is
+	// left unchanged.
+	InboundFee *lnwire.Fee
 }
 
 // Config defines the configuration for the ChannelRouter. ALL elements within
@@ -352,12 +359,23 @@ type Config struct {
 	// Clock is mockable time provider.
 	Clock clock.Clock
 
+	// IsAlias returns whether the given short channel ID is an alias.
+	// Channels announced with an alias can't be validated against the
+	// chain, so they are added to the graph as is. This is optional and
+	// may be nil.
+	IsAlias func(scid lnwire.ShortChannelID) bool
+
 	// StrictZombiePruning determines if we attempt to prune zombie
 	// channels according to a stricter criteria. If true, then we'll prune
 	// a channel if only *one* of the edges is considered a zombie.
 	// Otherwise, we'll only prune the channel when both edges have a very
 	// dated last update.
 	StrictZombiePruning bool
+
+	// Trampoline describes the trampoline node that payments to
+	// destinations which are not known to our graph are delegated to. If
+	// nil, payments are never delegated.
+	Trampoline *TrampolineConfig
 }
 
 // EdgeLocator is a struct used to identify a specific edge.
@@ -1349,8 +1367,12 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 		// If AssumeChannelValid is present, then we are unable to
 		// perform any of the expensive checks below, so we'll
 		// short-circuit our path straight to adding the edge to our
-		// graph.
-		if r.cfg.AssumeChannelValid {
+		// graph. The same applies to our own channels that are
+		// announced with an alias, as there is no funding transaction
+		// at the alias' location.
+		channelID := lnwire.NewShortChanIDFromInt(msg.ChannelID)
+		isAlias := r.cfg.IsAlias != nil && r.cfg.IsAlias(channelID)
+		if r.cfg.AssumeChannelValid || isAlias {
 			if err := r.cfg.Graph.AddChannelEdge(msg, op...); err != nil {
 				return fmt.Errorf("unable to add edge: %v", err)
 			}
@@ -1366,7 +1388,6 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 		// Before we can add the channel to the channel graph, we need
 		// to obtain the full funding outpoint that's encoded within
 		// the channel ID.
-		channelID := lnwire.NewShortChanIDFromInt(msg.ChannelID)
 		fundingTx, err := r.fetchFundingTx(&channelID)
 		if err != nil {
 			// In order to ensure we don't erroneously mark a
@@ -1867,6 +1888,12 @@ type LightningPayment struct {
 	//
 	// NOTE: This field is _optional_.
 	MaxShardAmt *lnwire.MilliSatoshi
+
+	// TrampolineOnion is the serialized trampoline onion that is attached
+	// to the final hop. It is set if the payment is delegated to a
+	// trampoline node, or if we relay a trampoline payment to the next
+	// trampoline node.
+	TrampolineOnion []byte
 }
 
 // AMPOptions houses information that must be known in order to send an AMP
@@ -1919,7 +1946,7 @@ func (l *LightningPayment) Identifier() [32]byte {
 func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte,
 	*route.Route, error) {
 
-	paySession, shardTracker, err := r.preparePayment(payment)
+	paySession, shardTracker, payment, err := r.preparePayment(payment)
 	if err != nil {
 		return [32]byte{}, nil, err
 	}
@@ -1938,7 +1965,7 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte,
 // SendPaymentAsync is the non-blocking version of SendPayment. The payment
 // result needs to be retrieved via the control tower.
 func (r *ChannelRouter) SendPaymentAsync(payment *LightningPayment) error {
-	paySession, shardTracker, err := r.preparePayment(payment)
+	paySession, shardTracker, payment, err := r.preparePayment(payment)
 	if err != nil {
 		return err
 	}
@@ -1988,16 +2015,31 @@ func spewPayment(payment *LightningPayment) logClosure {
 }
 
 // preparePayment creates the payment session and registers the payment with the
-// control tower.
+// control tower. If the payment is delegated to a trampoline node, the payment
+// to the trampoline node is returned, which is the payment that is actually
+// sent.
 func (r *ChannelRouter) preparePayment(payment *LightningPayment) (
-	PaymentSession, shards.ShardTracker, error) {
+	PaymentSession, shards.ShardTracker, *LightningPayment, error) {
+
+	// If the destination isn't known to our graph, we delegate the
+	// payment to our trampoline node if one is configured.
+	delegate, err := r.needsTrampoline(payment)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if delegate {
+		payment, err = r.newTrampolinePayment(payment)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
 
 	// Before starting the HTLC routing attempt, we'll create a fresh
 	// payment session which will report our errors back to mission
 	// control.
 	paySession, err := r.cfg.SessionSource.NewPaymentSession(payment)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// Record this payment hash with the ControlTower, ensuring it is not
@@ -2033,10 +2075,10 @@ func (r *ChannelRouter) preparePayment(payment *LightningPayment) (
 
 	err = r.cfg.Control.InitPayment(payment.Identifier(), info)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return paySession, shardTracker, nil
+	return paySession, shardTracker, payment, nil
 }
 
 // SendToRoute attempts to send a payment with the given hash through the
@@ -2105,6 +2147,7 @@ func (r *ChannelRouter) SendToRoute(htlcHash lntypes.Hash, rt *route.Route) (
 	// Launch a shard along the given route.
 	sh := &shardHandler{
 		router:       r,
+		control:      r.cfg.Control,
 		identifier:   paymentIdentifier,
 		shardTracker: shardTracker,
 	}
@@ -2206,6 +2249,7 @@ func (r *ChannelRouter) sendPayment(
 	// can resume the payment from the current state.
 	p := &paymentLifecycle{
 		router:        r,
+		control:       r.cfg.Control,
 		totalAmount:   totalAmt,
 		feeLimit:      feeLimit,
 		identifier:    identifier,
@@ -2773,6 +2817,9 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 	}()
 
 	// Traverse hops backwards to accumulate fees in the running amounts.
+	// The outbound fee of the previously processed hop is tracked, because
+	// an inbound discount of that hop can't exceed it.
+	var prevOutFee lnwire.MilliSatoshi
 	source := r.selfNode.PubKeyBytes
 	for i := len(hops) - 1; i >= 0; i-- {
 		toNode := hops[i]
@@ -2822,9 +2869,20 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 			}
 		}
 
+		// Add the inbound fee that the to node charges for this
+		// channel, unless it is the final hop.
+		if i < len(hops)-1 {
+			amtToForward := runningAmt - prevOutFee
+			runningAmt = amtToForward + forwardingFee(
+				prevOutFee, policy.inboundFee, amtToForward,
+			)
+		}
+
 		// Add fee for this hop.
+		prevOutFee = 0
 		if !localChan {
-			runningAmt += policy.ComputeFee(runningAmt)
+			prevOutFee = policy.ComputeFee(runningAmt)
+			runningAmt += prevOutFee
 		}
 
 		log.Tracef("Select channel %v at position %v", policy.ChannelID, i)
@@ -2836,7 +2894,7 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 	// total amount, we make a forward pass. Because the amount may have
 	// been increased in the backward pass, fees need to be recalculated and
 	// amount ranges re-checked.
-	var pathEdges []*channeldb.ChannelEdgePolicy
+	var pathEdges []*pathEdge
 	receiverAmt := runningAmt
 	for i, edge := range edges {
 		policy := edge.getPolicy(receiverAmt, bandwidthHints)
@@ -2849,8 +2907,11 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 
 		if i > 0 {
 			// Decrease the amount to send while going forward.
-			receiverAmt -= policy.ComputeFeeFromIncoming(
-				receiverAmt,
+			// The previous hop charges the inbound fee of the
+			// channel that it received the htlc through.
+			receiverAmt = amtToForwardFromIncoming(
+				policy.ChannelEdgePolicy,
+				pathEdges[i-1].inboundFee, receiverAmt,
 			)
 		}
 
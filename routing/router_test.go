@@ -93,12 +93,17 @@ func createTestCtxFromGraphInstanceAssumeValid(startingHeight uint32,
 		AttemptCost:    100,
 	}
 
+	estimator, err := NewAprioriEstimator(AprioriConfig{
+		PenaltyHalfLife:       time.Hour,
+		AprioriHopProbability: 0.9,
+		AprioriWeight:         0.5,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
 	mcConfig := &MissionControlConfig{
-		ProbabilityEstimatorCfg: ProbabilityEstimatorCfg{
-			PenaltyHalfLife:       time.Hour,
-			AprioriHopProbability: 0.9,
-			AprioriWeight:         0.5,
-		},
+		Estimator: estimator,
 	}
 
 	mc, err := NewMissionControl(
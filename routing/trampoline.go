@@ -0,0 +1,250 @@
+package routing
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/routing/trampoline"
+)
+
+// TrampolineConfig describes the trampoline node that payments to destinations
+// which are not known to our graph are delegated to, and the fees and cltv
+// delta that are left for it.
+type TrampolineConfig struct {
+	// Node is the trampoline node.
+	Node route.Vertex
+
+	// FeeBase is the base fee that is left for the trampoline node.
+	FeeBase lnwire.MilliSatoshi
+
+	// FeeRate is the proportional fee in parts per million that is left
+	// for the trampoline node.
+	FeeRate lnwire.MilliSatoshi
+
+	// CltvDelta is the cltv delta that is left for the trampoline node.
+	// It needs to cover the route from the trampoline node to the
+	// recipient.
+	CltvDelta uint16
+}
+
+// fee returns the fee that is left for the trampoline node to forward amt.
+func (t *TrampolineConfig) fee(amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+	return t.FeeBase + amt*t.FeeRate/1000000
+}
+
+// knownToGraph returns true if the given node is part of our graph.
+func (r *ChannelRouter) knownToGraph(node route.Vertex) (bool, error) {
+	_, err := r.cfg.Graph.FetchLightningNode(nil, node)
+	switch {
+	case err == channeldb.ErrGraphNodeNotFound:
+		return false, nil
+
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+// needsTrampoline returns true if the payment should be delegated to the
+// configured trampoline node. This is the case if neither the target nor the
+// entry points of its route hints are known to our graph, so that path finding
+// would never succeed.
+func (r *ChannelRouter) needsTrampoline(payment *LightningPayment) (bool,
+	error) {
+
+	cfg := r.cfg.Trampoline
+
+	switch {
+	case cfg == nil:
+		return false, nil
+
+	// Only simple payments to an invoice can be delegated, as the
+	// recipient must be given its payment address.
+	case payment.amp != nil || payment.PaymentAddr == nil:
+		return false, nil
+
+	// Custom records and restrictions of the route can't be delivered to
+	// or respected by the trampoline node.
+	case len(payment.DestCustomRecords) > 0 || payment.LastHop != nil:
+		return false, nil
+
+	// Payments that already carry a trampoline onion are relayed by us on
+	// behalf of another node, and are never delegated again.
+	case payment.TrampolineOnion != nil:
+		return false, nil
+
+	case payment.Target == cfg.Node ||
+		payment.Target == r.selfNode.PubKeyBytes:
+
+		return false, nil
+	}
+
+	known, err := r.knownToGraph(payment.Target)
+	if err != nil || known {
+		return false, err
+	}
+
+	for _, routeHint := range payment.RouteHints {
+		if len(routeHint) == 0 {
+			continue
+		}
+
+		entry := route.NewVertex(routeHint[0].NodeID)
+		known, err := r.knownToGraph(entry)
+		if err != nil || known {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// newTrampolinePayment returns the payment to the configured trampoline node
+// that delegates the given payment. The trampoline node learns the recipient
+// from the trampoline onion that is attached to the final hop. If the
+// recipient supports trampoline payments, the trampoline onion contains a hop
+// for the recipient as well. Otherwise the trampoline node is given the
+// invoice features and route hints of the recipient, so that it can pay the
+// recipient directly.
+func (r *ChannelRouter) newTrampolinePayment(payment *LightningPayment) (
+	*LightningPayment, error) {
+
+	cfg := r.cfg.Trampoline
+
+	fee := cfg.fee(payment.Amount)
+	if fee > payment.FeeLimit {
+		return nil, fmt.Errorf("trampoline fee %v exceeds fee limit %v",
+			fee, payment.FeeLimit)
+	}
+
+	trampolinePub, err := btcec.ParsePubKey(cfg.Node[:], btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	// The htlc the trampoline node forwards to the recipient must expire
+	// no earlier than the final cltv delta of the recipient requires.
+	height := atomic.LoadUint32(&r.bestHeight)
+	finalCltv := height + uint32(payment.FinalCLTVDelta)
+
+	recipientPub, err := btcec.ParsePubKey(
+		payment.Target[:], btcec.S256(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	trampolinePayload := &trampoline.Payload{
+		AmtToForward:   payment.Amount,
+		OutgoingCltv:   finalCltv,
+		OutgoingNodeID: recipientPub,
+	}
+
+	var hops []trampoline.OnionHop
+	recipientSupportsTrampoline := payment.DestFeatures != nil &&
+		payment.DestFeatures.HasFeature(
+			lnwire.TrampolineRoutingOptional,
+		)
+
+	// If the recipient doesn't support trampoline payments, the trampoline
+	// node pays it like a regular invoice.
+	if !recipientSupportsTrampoline {
+		trampolinePayload.MPP = record.NewMPP(
+			payment.Amount, *payment.PaymentAddr,
+		)
+		trampolinePayload.InvoiceRouteHints = payment.RouteHints
+		if payment.DestFeatures != nil {
+			trampolinePayload.InvoiceFeatures =
+				payment.DestFeatures.RawFeatureVector
+		}
+	}
+
+	encoded, err := trampolinePayload.Encode()
+	if err != nil {
+		return nil, err
+	}
+	hops = append(hops, trampoline.OnionHop{
+		NodePub: trampolinePub,
+		Payload: encoded,
+	})
+
+	if recipientSupportsTrampoline {
+		recipientPayload := &trampoline.Payload{
+			AmtToForward: payment.Amount,
+			OutgoingCltv: finalCltv,
+			MPP: record.NewMPP(
+				payment.Amount, *payment.PaymentAddr,
+			),
+		}
+		encoded, err := recipientPayload.Encode()
+		if err != nil {
+			return nil, err
+		}
+		hops = append(hops, trampoline.OnionHop{
+			NodePub: recipientPub,
+			Payload: encoded,
+		})
+	}
+
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	// The trampoline onion is bound to the payment hash, so it can't be
+	// reused for a different payment.
+	paymentHash := payment.Identifier()
+	onion, err := trampoline.NewOnionPacket(
+		hops, sessionKey, paymentHash[:],
+	)
+	if err != nil {
+		return nil, err
+	}
+	onionBytes, err := onion.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	// The payment to the trampoline node may be split into multiple
+	// htlcs. They are tied together by a random payment address, as the
+	// trampoline node is not the final recipient and has no invoice for
+	// it. The trampoline node waits for all of them before it pays the
+	// recipient.
+	var paymentAddr [32]byte
+	if _, err := rand.Read(paymentAddr[:]); err != nil {
+		return nil, err
+	}
+
+	// The payment is only split if the trampoline node announced support
+	// for multi-path payments.
+	trampolineNode, err := r.cfg.Graph.FetchLightningNode(nil, cfg.Node)
+	if err != nil && err != channeldb.ErrGraphNodeNotFound {
+		return nil, err
+	}
+
+	trampolinePayment := *payment
+	trampolinePayment.Target = cfg.Node
+	trampolinePayment.Amount = payment.Amount + fee
+	trampolinePayment.FeeLimit = payment.FeeLimit - fee
+	trampolinePayment.FinalCLTVDelta = payment.FinalCLTVDelta +
+		cfg.CltvDelta
+	trampolinePayment.RouteHints = nil
+	trampolinePayment.DestFeatures = nil
+	if trampolineNode != nil {
+		trampolinePayment.DestFeatures = trampolineNode.Features
+	}
+	trampolinePayment.PaymentAddr = &paymentAddr
+	trampolinePayment.TrampolineOnion = onionBytes
+
+	log.Infof("Delegating payment %x to trampoline node %v with fee %v",
+		paymentHash, cfg.Node, fee)
+
+	return &trampolinePayment, nil
+}
@@ -0,0 +1,444 @@
+package trampoline
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/tlv"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	// RoutingInfoSize is the size of the routing info of the trampoline
+	// onions we create. It is smaller than the routing info of payment
+	// onions, so that the trampoline onion fits into the payload of the
+	// final hop of a payment onion.
+	RoutingInfoSize = 400
+
+	// MaxRoutingInfoSize is the maximum size of the routing info of a
+	// trampoline onion we accept.
+	MaxRoutingInfoSize = 1000
+
+	// hmacSize is the size of the HMACs of the onion.
+	hmacSize = 32
+
+	// onionVersion is the only version of trampoline onions we support.
+	onionVersion = 0
+)
+
+var (
+	// ErrInvalidOnionVersion is returned when a trampoline onion with an
+	// unknown version is decoded.
+	ErrInvalidOnionVersion = errors.New("invalid trampoline onion version")
+
+	// ErrInvalidOnionHMAC is returned when the HMAC of a trampoline onion
+	// doesn't match its contents.
+	ErrInvalidOnionHMAC = errors.New("invalid trampoline onion hmac")
+
+	// ErrPayloadTooLarge is returned when the payloads of the hops don't
+	// fit into the routing info of a trampoline onion.
+	ErrPayloadTooLarge = errors.New("trampoline payloads exceed routing " +
+		"info size")
+
+	// zeroHMAC is the HMAC of the final hop of an onion.
+	zeroHMAC [hmacSize]byte
+)
+
+// OnionHop is a hop of a trampoline onion.
+type OnionHop struct {
+	// NodePub is the public key of the trampoline node or recipient.
+	NodePub *btcec.PublicKey
+
+	// Payload is the encoded tlv payload of the hop.
+	Payload []byte
+}
+
+// OnionPacket is a trampoline onion. It has the same format as a payment
+// onion, but its routing info has a variable size. It is included in the
+// payload of the final hop of a payment onion to a trampoline node.
+type OnionPacket struct {
+	// Version is the version of the onion.
+	Version byte
+
+	// EphemeralKey is the ephemeral key the node that processes the onion
+	// derives its shared secret with.
+	EphemeralKey *btcec.PublicKey
+
+	// RoutingInfo is the encrypted routing info of the onion.
+	RoutingInfo []byte
+
+	// HMAC authenticates the routing info and the associated data.
+	HMAC [hmacSize]byte
+}
+
+// ProcessedPacket is the result of processing a trampoline onion.
+type ProcessedPacket struct {
+	// Payload is the decrypted payload of our hop.
+	Payload []byte
+
+	// NextPacket is the onion for the next trampoline node or the
+	// recipient. It is nil if we are the final hop of the onion.
+	NextPacket *OnionPacket
+}
+
+// NewOnionPacket creates a trampoline onion that routes through the passed
+// hops. The associated data, typically the payment hash, is authenticated by
+// the HMAC of every hop.
+func NewOnionPacket(hops []OnionHop, sessionKey *btcec.PrivateKey,
+	assocData []byte) (*OnionPacket, error) {
+
+	if len(hops) == 0 {
+		return nil, errors.New("trampoline onion requires at least " +
+			"one hop")
+	}
+
+	var totalSize int
+	for _, hop := range hops {
+		totalSize += hopSize(hop.Payload)
+	}
+	if totalSize > RoutingInfoSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	sharedSecrets, err := sharedSecrets(hops, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	filler := generateFiller(hops, sharedSecrets)
+
+	// The unused part of the routing info is filled with random looking
+	// bytes derived from the session key.
+	var sessionSecret [32]byte
+	copy(sessionSecret[:], sessionKey.Serialize())
+	routingInfo := generateCipherStream(
+		generateKey("pad", sessionSecret), RoutingInfoSize,
+	)
+
+	// Wrap the payloads from the last hop to the first. Each hop shifts
+	// the routing info to the right to make room for its payload.
+	var nextHMAC [hmacSize]byte
+	for i := len(hops) - 1; i >= 0; i-- {
+		rho := generateKey("rho", sharedSecrets[i])
+		mu := generateKey("mu", sharedSecrets[i])
+
+		var payload bytes.Buffer
+		err := encodeHopPayload(&payload, hops[i].Payload, nextHMAC)
+		if err != nil {
+			return nil, err
+		}
+
+		shift := payload.Len()
+		copy(routingInfo[shift:], routingInfo[:RoutingInfoSize-shift])
+		copy(routingInfo, payload.Bytes())
+
+		xor(routingInfo, generateCipherStream(rho, RoutingInfoSize))
+
+		if i == len(hops)-1 {
+			copy(routingInfo[RoutingInfoSize-len(filler):], filler)
+		}
+
+		nextHMAC = calcHMAC(mu, routingInfo, assocData)
+	}
+
+	return &OnionPacket{
+		Version:      onionVersion,
+		EphemeralKey: sessionKey.PubKey(),
+		RoutingInfo:  routingInfo,
+		HMAC:         nextHMAC,
+	}, nil
+}
+
+// Encode serializes the onion into the passed writer.
+func (o *OnionPacket) Encode(w io.Writer) error {
+	if _, err := w.Write([]byte{o.Version}); err != nil {
+		return err
+	}
+
+	ephemeralKey := o.EphemeralKey.SerializeCompressed()
+	if _, err := w.Write(ephemeralKey); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(o.RoutingInfo); err != nil {
+		return err
+	}
+
+	_, err := w.Write(o.HMAC[:])
+
+	return err
+}
+
+// Serialize returns the serialized onion.
+func (o *OnionPacket) Serialize() ([]byte, error) {
+	var b bytes.Buffer
+	if err := o.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeOnionPacket parses a serialized trampoline onion. The size of the
+// routing info is derived from the size of the serialized onion.
+func DecodeOnionPacket(b []byte) (*OnionPacket, error) {
+	const overhead = 1 + btcec.PubKeyBytesLenCompressed + hmacSize
+
+	routingInfoSize := len(b) - overhead
+	if routingInfoSize <= 0 || routingInfoSize > MaxRoutingInfoSize {
+		return nil, fmt.Errorf("invalid trampoline onion size: %v",
+			len(b))
+	}
+
+	if b[0] != onionVersion {
+		return nil, ErrInvalidOnionVersion
+	}
+
+	ephemeralKey, err := btcec.ParsePubKey(
+		b[1:1+btcec.PubKeyBytesLenCompressed], btcec.S256(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &OnionPacket{
+		Version:      b[0],
+		EphemeralKey: ephemeralKey,
+		RoutingInfo: append(
+			[]byte(nil), b[1+btcec.PubKeyBytesLenCompressed:len(b)-
+				hmacSize]...,
+		),
+	}
+	copy(o.HMAC[:], b[len(b)-hmacSize:])
+
+	return o, nil
+}
+
+// ProcessOnionPacket removes our layer of the trampoline onion. It returns
+// our payload and the onion for the next hop, if any.
+func ProcessOnionPacket(nodeKey sphinx.SingleKeyECDH, o *OnionPacket,
+	assocData []byte) (*ProcessedPacket, error) {
+
+	sharedSecret, err := nodeKey.ECDH(o.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rho := generateKey("rho", sharedSecret)
+	mu := generateKey("mu", sharedSecret)
+
+	expectedHMAC := calcHMAC(mu, o.RoutingInfo, assocData)
+	if !hmac.Equal(expectedHMAC[:], o.HMAC[:]) {
+		return nil, ErrInvalidOnionHMAC
+	}
+
+	// Decrypt the routing info padded with zeroes, so that the routing
+	// info of the next hop keeps its size.
+	size := len(o.RoutingInfo)
+	hopInfo := make([]byte, 2*size)
+	copy(hopInfo, o.RoutingInfo)
+	xor(hopInfo, generateCipherStream(rho, 2*size))
+
+	r := bytes.NewReader(hopInfo)
+	payloadLen, err := tlv.ReadVarInt(r, &[8]byte{})
+	if err != nil {
+		return nil, err
+	}
+	if payloadLen == 0 || payloadLen > uint64(size) {
+		return nil, fmt.Errorf("invalid trampoline payload length: %v",
+			payloadLen)
+	}
+
+	payload := make([]byte, payloadLen)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	var nextHMAC [hmacSize]byte
+	if _, err := io.ReadFull(r, nextHMAC[:]); err != nil {
+		return nil, err
+	}
+
+	processed := &ProcessedPacket{
+		Payload: payload,
+	}
+	if nextHMAC == zeroHMAC {
+		return processed, nil
+	}
+
+	consumed := len(hopInfo) - r.Len()
+	blindingFactor := computeBlindingFactor(o.EphemeralKey, sharedSecret)
+	processed.NextPacket = &OnionPacket{
+		Version:      o.Version,
+		EphemeralKey: blindPubKey(o.EphemeralKey, blindingFactor),
+		RoutingInfo: append(
+			[]byte(nil), hopInfo[consumed:consumed+size]...,
+		),
+		HMAC: nextHMAC,
+	}
+
+	return processed, nil
+}
+
+// hopSize returns the number of bytes a hop with the passed payload occupies
+// in the routing info.
+func hopSize(payload []byte) int {
+	return int(tlv.VarIntSize(uint64(len(payload)))) + len(payload) +
+		hmacSize
+}
+
+// encodeHopPayload writes the payload of a hop, prefixed with its length and
+// followed by the HMAC for the next hop.
+func encodeHopPayload(w io.Writer, payload []byte,
+	nextHMAC [hmacSize]byte) error {
+
+	err := tlv.WriteVarInt(w, uint64(len(payload)), &[8]byte{})
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+
+	_, err = w.Write(nextHMAC[:])
+
+	return err
+}
+
+// sharedSecrets derives the shared secrets of all hops of the onion.
+func sharedSecrets(hops []OnionHop,
+	sessionKey *btcec.PrivateKey) ([][32]byte, error) {
+
+	ephemeralKey := new(big.Int).Set(sessionKey.D)
+	secrets := make([][32]byte, len(hops))
+	for i, hop := range hops {
+		if hop.NodePub == nil {
+			return nil, fmt.Errorf("hop %d has no node key", i)
+		}
+
+		ephemeralPriv, _ := btcec.PrivKeyFromBytes(
+			btcec.S256(), ephemeralKey.Bytes(),
+		)
+		ecdh := &sphinx.PrivKeyECDH{PrivKey: ephemeralPriv}
+		secret, err := ecdh.ECDH(hop.NodePub)
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = secret
+
+		blindingFactor := computeBlindingFactor(
+			ephemeralPriv.PubKey(), secret,
+		)
+		ephemeralKey.Mul(
+			ephemeralKey, new(big.Int).SetBytes(blindingFactor[:]),
+		)
+		ephemeralKey.Mod(ephemeralKey, btcec.S256().N)
+	}
+
+	return secrets, nil
+}
+
+// generateFiller generates the bytes at the end of the routing info of the
+// final hop. They match the bytes that the previous hops shift into the
+// routing info, so the HMACs of all hops can be computed upfront.
+func generateFiller(hops []OnionHop, sharedSecrets [][32]byte) []byte {
+	var fillerSize int
+	for _, hop := range hops[:len(hops)-1] {
+		fillerSize += hopSize(hop.Payload)
+	}
+
+	filler := make([]byte, fillerSize)
+	fillerStart := RoutingInfoSize
+	for i, hop := range hops[:len(hops)-1] {
+		fillerEnd := RoutingInfoSize + hopSize(hop.Payload)
+
+		stream := generateCipherStream(
+			generateKey("rho", sharedSecrets[i]),
+			2*RoutingInfoSize,
+		)
+		xor(filler, stream[fillerStart:fillerEnd])
+
+		fillerStart -= hopSize(hop.Payload)
+	}
+
+	return filler
+}
+
+// generateKey derives a key of the passed type from a shared secret.
+func generateKey(keyType string, sharedSecret [32]byte) [32]byte {
+	mac := hmac.New(sha256.New, []byte(keyType))
+	mac.Write(sharedSecret[:])
+
+	var key [32]byte
+	copy(key[:], mac.Sum(nil))
+
+	return key
+}
+
+// generateCipherStream generates a chacha20 stream of the passed size.
+func generateCipherStream(key [32]byte, size int) []byte {
+	var nonce [chacha20.NonceSize]byte
+	cipher, err := chacha20.NewUnauthenticatedCipher(key[:], nonce[:])
+	if err != nil {
+		panic(err)
+	}
+
+	stream := make([]byte, size)
+	cipher.XORKeyStream(stream, stream)
+
+	return stream
+}
+
+// calcHMAC computes the HMAC over the routing info and associated data.
+func calcHMAC(key [32]byte, routingInfo, assocData []byte) [hmacSize]byte {
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(routingInfo)
+	mac.Write(assocData)
+
+	var result [hmacSize]byte
+	copy(result[:], mac.Sum(nil))
+
+	return result
+}
+
+// computeBlindingFactor computes the factor the ephemeral key is blinded with
+// for the next hop.
+func computeBlindingFactor(ephemeralKey *btcec.PublicKey,
+	sharedSecret [32]byte) [32]byte {
+
+	h := sha256.New()
+	h.Write(ephemeralKey.SerializeCompressed())
+	h.Write(sharedSecret[:])
+
+	var factor [32]byte
+	copy(factor[:], h.Sum(nil))
+
+	return factor
+}
+
+// blindPubKey multiplies the public key with the blinding factor.
+func blindPubKey(pub *btcec.PublicKey,
+	blindingFactor [32]byte) *btcec.PublicKey {
+
+	blinded := &btcec.PublicKey{Curve: btcec.S256()}
+	blinded.X, blinded.Y = btcec.S256().ScalarMult(
+		pub.X, pub.Y, blindingFactor[:],
+	)
+
+	return blinded
+}
+
+// xor xors the stream into the data, up to the length of the shorter one.
+func xor(data, stream []byte) {
+	for i := 0; i < len(data) && i < len(stream); i++ {
+		data[i] ^= stream[i]
+	}
+}
@@ -0,0 +1,261 @@
+package trampoline
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+// Payload is the payload of a hop of a trampoline onion.
+type Payload struct {
+	// AmtToForward is the amount the trampoline node forwards to the next
+	// node, or the amount the recipient receives.
+	AmtToForward lnwire.MilliSatoshi
+
+	// OutgoingCltv is the cltv expiry of the htlc the trampoline node
+	// forwards to the next node, or the cltv expiry of the htlc the
+	// recipient receives.
+	OutgoingCltv uint32
+
+	// OutgoingNodeID is the node the trampoline node forwards the payment
+	// to. It is nil for the recipient.
+	OutgoingNodeID *btcec.PublicKey
+
+	// MPP contains the payment address and total amount of the payment.
+	// It is set for the recipient. It is also set for the last trampoline
+	// node if the recipient doesn't support trampoline payments, in
+	// which case the trampoline node forwards it to the recipient.
+	MPP *record.MPP
+
+	// InvoiceFeatures are the invoice features of a recipient that
+	// doesn't support trampoline payments.
+	InvoiceFeatures *lnwire.RawFeatureVector
+
+	// InvoiceRouteHints are the invoice route hints of a recipient that
+	// doesn't support trampoline payments.
+	InvoiceRouteHints [][]zpay32.HopHint
+}
+
+// IsFinal returns true if the payload is for the recipient of the payment.
+func (p *Payload) IsFinal() bool {
+	return p.OutgoingNodeID == nil
+}
+
+// Encode serializes the payload as a tlv stream.
+func (p *Payload) Encode() ([]byte, error) {
+	amt := uint64(p.AmtToForward)
+	records := []tlv.Record{
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&p.OutgoingCltv),
+	}
+
+	if p.MPP != nil {
+		records = append(records, p.MPP.Record())
+	}
+
+	if p.OutgoingNodeID != nil {
+		records = append(
+			records, record.NewOutgoingNodeIDRecord(
+				&p.OutgoingNodeID,
+			),
+		)
+	}
+
+	if p.InvoiceFeatures != nil {
+		var features bytes.Buffer
+		err := p.InvoiceFeatures.EncodeBase256(&features)
+		if err != nil {
+			return nil, err
+		}
+
+		featureBytes := features.Bytes()
+		records = append(records, tlv.MakePrimitiveRecord(
+			record.InvoiceFeaturesOnionType, &featureBytes,
+		))
+	}
+
+	if len(p.InvoiceRouteHints) > 0 {
+		routeHints, err := encodeRouteHints(p.InvoiceRouteHints)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			record.InvoiceRoutingInfoOnionType, &routeHints,
+		))
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodePayload parses the payload of a hop of a trampoline onion.
+func DecodePayload(b []byte) (*Payload, error) {
+	var (
+		amt            uint64
+		cltv           uint32
+		mpp            = &record.MPP{}
+		outgoingNodeID *btcec.PublicKey
+		features       []byte
+		routeHints     []byte
+	)
+
+	stream, err := tlv.NewStream(
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&cltv),
+		mpp.Record(),
+		record.NewOutgoingNodeIDRecord(&outgoingNodeID),
+		tlv.MakePrimitiveRecord(
+			record.InvoiceFeaturesOnionType, &features,
+		),
+		tlv.MakePrimitiveRecord(
+			record.InvoiceRoutingInfoOnionType, &routeHints,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := parsedTypes[record.AmtOnionType]; !ok {
+		return nil, fmt.Errorf("trampoline payload has no amount")
+	}
+	if _, ok := parsedTypes[record.LockTimeOnionType]; !ok {
+		return nil, fmt.Errorf("trampoline payload has no cltv expiry")
+	}
+
+	payload := &Payload{
+		AmtToForward: lnwire.MilliSatoshi(amt),
+		OutgoingCltv: cltv,
+	}
+
+	if _, ok := parsedTypes[record.MPPOnionType]; ok {
+		payload.MPP = mpp
+	}
+
+	if _, ok := parsedTypes[record.OutgoingNodeIDOnionType]; ok {
+		payload.OutgoingNodeID = outgoingNodeID
+	}
+
+	if _, ok := parsedTypes[record.InvoiceFeaturesOnionType]; ok {
+		payload.InvoiceFeatures = lnwire.NewRawFeatureVector()
+		err := payload.InvoiceFeatures.DecodeBase256(
+			bytes.NewReader(features), len(features),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := parsedTypes[record.InvoiceRoutingInfoOnionType]; ok {
+		payload.InvoiceRouteHints, err = decodeRouteHints(routeHints)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The recipient must be given the payment address and total amount,
+	// so it can tell apart the payments for its invoices.
+	if payload.IsFinal() && payload.MPP == nil {
+		return nil, fmt.Errorf("final trampoline payload has no " +
+			"payment data")
+	}
+
+	return payload, nil
+}
+
+// encodeRouteHints serializes route hints. Every route is prefixed with its
+// number of hops, followed by the node ID, channel ID, base fee, fee rate and
+// cltv delta of each hop.
+func encodeRouteHints(routeHints [][]zpay32.HopHint) ([]byte, error) {
+	var b bytes.Buffer
+	for _, routeHint := range routeHints {
+		if len(routeHint) == 0 || len(routeHint) > 255 {
+			return nil, fmt.Errorf("invalid route hint length: %v",
+				len(routeHint))
+		}
+
+		b.WriteByte(byte(len(routeHint)))
+		for _, hopHint := range routeHint {
+			b.Write(hopHint.NodeID.SerializeCompressed())
+
+			var buf [18]byte
+			binary.BigEndian.PutUint64(buf[:8], hopHint.ChannelID)
+			binary.BigEndian.PutUint32(buf[8:12], hopHint.FeeBaseMSat)
+			binary.BigEndian.PutUint32(
+				buf[12:16], hopHint.FeeProportionalMillionths,
+			)
+			binary.BigEndian.PutUint16(
+				buf[16:], hopHint.CLTVExpiryDelta,
+			)
+			b.Write(buf[:])
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeRouteHints parses the route hints serialized by encodeRouteHints.
+func decodeRouteHints(b []byte) ([][]zpay32.HopHint, error) {
+	r := bytes.NewReader(b)
+
+	var routeHints [][]zpay32.HopHint
+	for r.Len() > 0 {
+		numHops, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		routeHint := make([]zpay32.HopHint, 0, numHops)
+		for i := 0; i < int(numHops); i++ {
+			var nodeID [btcec.PubKeyBytesLenCompressed]byte
+			if _, err := io.ReadFull(r, nodeID[:]); err != nil {
+				return nil, err
+			}
+
+			pubKey, err := btcec.ParsePubKey(nodeID[:], btcec.S256())
+			if err != nil {
+				return nil, err
+			}
+
+			var buf [18]byte
+			if _, err := io.ReadFull(r, buf[:]); err != nil {
+				return nil, err
+			}
+
+			routeHint = append(routeHint, zpay32.HopHint{
+				NodeID:      pubKey,
+				ChannelID:   binary.BigEndian.Uint64(buf[:8]),
+				FeeBaseMSat: binary.BigEndian.Uint32(buf[8:12]),
+				FeeProportionalMillionths: binary.BigEndian.Uint32(
+					buf[12:16],
+				),
+				CLTVExpiryDelta: binary.BigEndian.Uint16(buf[16:]),
+			})
+		}
+
+		routeHints = append(routeHints, routeHint)
+	}
+
+	return routeHints, nil
+}
@@ -0,0 +1,135 @@
+package trampoline
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKey(t *testing.T) *btcec.PrivateKey {
+	t.Helper()
+
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	return key
+}
+
+// TestOnionRoundTrip tests that every hop of a trampoline onion recovers its
+// payload and that the final hop is recognized.
+func TestOnionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 3
+
+	var (
+		nodeKeys  []*btcec.PrivateKey
+		hops      []OnionHop
+		assocData = bytes.Repeat([]byte{1}, 32)
+	)
+	for i := 0; i < numHops; i++ {
+		nodeKey := newTestKey(t)
+		nodeKeys = append(nodeKeys, nodeKey)
+		hops = append(hops, OnionHop{
+			NodePub: nodeKey.PubKey(),
+			Payload: bytes.Repeat([]byte{byte(i + 1)}, 20+i*30),
+		})
+	}
+
+	onion, err := NewOnionPacket(hops, newTestKey(t), assocData)
+	require.NoError(t, err)
+
+	for i, nodeKey := range nodeKeys {
+		// Every hop receives the onion in serialized form.
+		serialized, err := onion.Serialize()
+		require.NoError(t, err)
+		require.Len(t, serialized, 1+33+RoutingInfoSize+hmacSize)
+
+		onion, err = DecodeOnionPacket(serialized)
+		require.NoError(t, err)
+
+		// The onion is bound to the associated data.
+		_, err = ProcessOnionPacket(
+			&sphinx.PrivKeyECDH{PrivKey: nodeKey}, onion, nil,
+		)
+		require.Equal(t, ErrInvalidOnionHMAC, err)
+
+		processed, err := ProcessOnionPacket(
+			&sphinx.PrivKeyECDH{PrivKey: nodeKey}, onion,
+			assocData,
+		)
+		require.NoError(t, err)
+		require.Equal(t, hops[i].Payload, processed.Payload)
+
+		if i == numHops-1 {
+			require.Nil(t, processed.NextPacket)
+			break
+		}
+
+		require.NotNil(t, processed.NextPacket)
+		onion = processed.NextPacket
+	}
+}
+
+// TestOnionTooLarge tests that payloads that exceed the routing info size are
+// rejected.
+func TestOnionTooLarge(t *testing.T) {
+	t.Parallel()
+
+	hops := []OnionHop{{
+		NodePub: newTestKey(t).PubKey(),
+		Payload: make([]byte, RoutingInfoSize),
+	}}
+
+	_, err := NewOnionPacket(hops, newTestKey(t), nil)
+	require.Equal(t, ErrPayloadTooLarge, err)
+}
+
+// TestPayloadEncoding tests the encoding of trampoline payloads.
+func TestPayloadEncoding(t *testing.T) {
+	t.Parallel()
+
+	features := lnwire.NewRawFeatureVector(
+		lnwire.PaymentAddrOptional, lnwire.MPPOptional,
+	)
+
+	payloads := []*Payload{{
+		AmtToForward: 1000,
+		OutgoingCltv: 500,
+		MPP:          record.NewMPP(1000, [32]byte{1}),
+	}, {
+		AmtToForward:    2000,
+		OutgoingCltv:    600,
+		OutgoingNodeID:  newTestKey(t).PubKey(),
+		MPP:             record.NewMPP(2000, [32]byte{2}),
+		InvoiceFeatures: features,
+		InvoiceRouteHints: [][]zpay32.HopHint{{{
+			NodeID:                    newTestKey(t).PubKey(),
+			ChannelID:                 12345,
+			FeeBaseMSat:               1,
+			FeeProportionalMillionths: 2,
+			CLTVExpiryDelta:           40,
+		}}},
+	}}
+
+	for _, payload := range payloads {
+		encoded, err := payload.Encode()
+		require.NoError(t, err)
+
+		decoded, err := DecodePayload(encoded)
+		require.NoError(t, err)
+		require.Equal(t, payload, decoded)
+	}
+
+	// A final payload must contain the payment data.
+	encoded, err := (&Payload{AmtToForward: 1, OutgoingCltv: 1}).Encode()
+	require.NoError(t, err)
+	_, err = DecodePayload(encoded)
+	require.Error(t, err)
+}
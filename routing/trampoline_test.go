@@ -0,0 +1,111 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/routing/trampoline"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrampolinePayment tests that payments to destinations that are not known
+// to the graph are delegated to the trampoline node.
+func TestTrampolinePayment(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtxFromFile(
+		startingBlockHeight, basicGraphFilePath,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	trampolineKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	recipientKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	ctx.router.cfg.Trampoline = &TrampolineConfig{
+		Node:      route.NewVertex(trampolineKey.PubKey()),
+		FeeBase:   1000,
+		FeeRate:   1000,
+		CltvDelta: 288,
+	}
+
+	paymentAddr := [32]byte{1}
+	newPayment := func(target route.Vertex) *LightningPayment {
+		payment := &LightningPayment{
+			Target:         target,
+			Amount:         100000,
+			FeeLimit:       10000,
+			FinalCLTVDelta: 40,
+			PaymentAddr:    &paymentAddr,
+		}
+		require.NoError(t, payment.SetPaymentHash(lntypes.Hash{2}))
+
+		return payment
+	}
+
+	// Payments to destinations in the graph are not delegated.
+	delegate, err := ctx.router.needsTrampoline(
+		newPayment(ctx.aliases["sophon"]),
+	)
+	require.NoError(t, err)
+	require.False(t, delegate)
+
+	// Payments to unknown destinations are delegated.
+	payment := newPayment(route.NewVertex(recipientKey.PubKey()))
+	delegate, err = ctx.router.needsTrampoline(payment)
+	require.NoError(t, err)
+	require.True(t, delegate)
+
+	// The fee left for the trampoline node must not exceed the fee limit.
+	payment.FeeLimit = 1000
+	_, err = ctx.router.newTrampolinePayment(payment)
+	require.Error(t, err)
+	payment.FeeLimit = 10000
+
+	trampolinePayment, err := ctx.router.newTrampolinePayment(payment)
+	require.NoError(t, err)
+	require.Equal(t, ctx.router.cfg.Trampoline.Node, trampolinePayment.Target)
+	require.Equal(
+		t, lnwire.MilliSatoshi(101100), trampolinePayment.Amount,
+	)
+	require.Equal(
+		t, lnwire.MilliSatoshi(8900), trampolinePayment.FeeLimit,
+	)
+	require.Equal(t, uint16(328), trampolinePayment.FinalCLTVDelta)
+	require.Equal(t, payment.Identifier(), trampolinePayment.Identifier())
+
+	// The trampoline node learns the recipient and its payment data, as
+	// the recipient doesn't support trampoline payments.
+	onion, err := trampoline.DecodeOnionPacket(
+		trampolinePayment.TrampolineOnion,
+	)
+	require.NoError(t, err)
+
+	paymentHash := payment.Identifier()
+	processed, err := trampoline.ProcessOnionPacket(
+		&sphinx.PrivKeyECDH{PrivKey: trampolineKey}, onion,
+		paymentHash[:],
+	)
+	require.NoError(t, err)
+	require.Nil(t, processed.NextPacket)
+
+	payload, err := trampoline.DecodePayload(processed.Payload)
+	require.NoError(t, err)
+	require.False(t, payload.IsFinal())
+	require.Equal(t, payment.Amount, payload.AmtToForward)
+	require.Equal(
+		t, uint32(startingBlockHeight+40), payload.OutgoingCltv,
+	)
+	require.Equal(
+		t, route.NewVertex(recipientKey.PubKey()),
+		route.NewVertex(payload.OutgoingNodeID),
+	)
+	require.Equal(t, paymentAddr, payload.MPP.PaymentAddr())
+}
@@ -38,9 +38,11 @@ func newUnifiedPolicies(sourceNode, toNode route.Vertex,
 }
 
 // addPolicy adds a single channel policy. Capacity may be zero if unknown
-// (light clients).
+// (light clients). The inbound fee is the fee that the to node charges for
+// htlcs that it receives through the channel.
 func (u *unifiedPolicies) addPolicy(fromNode route.Vertex,
-	edge *channeldb.ChannelEdgePolicy, capacity btcutil.Amount) {
+	edge *channeldb.ChannelEdgePolicy, inboundFee lnwire.Fee,
+	capacity btcutil.Amount) {
 
 	localChan := fromNode == u.sourceNode
 
@@ -61,15 +63,16 @@ func (u *unifiedPolicies) addPolicy(fromNode route.Vertex,
 	}
 
 	policy.edges = append(policy.edges, &unifiedPolicyEdge{
-		policy:   edge,
-		capacity: capacity,
+		policy:     edge,
+		inboundFee: inboundFee,
+		capacity:   capacity,
 	})
 }
 
 // addGraphPolicies adds all policies that are known for the toNode in the
 // graph.
 func (u *unifiedPolicies) addGraphPolicies(g routingGraph) error {
-	cb := func(edgeInfo *channeldb.ChannelEdgeInfo, _,
+	cb := func(edgeInfo *channeldb.ChannelEdgeInfo, outEdge,
 		inEdge *channeldb.ChannelEdgePolicy) error {
 
 		// If there is no edge policy for this candidate node, skip.
@@ -85,8 +88,17 @@ func (u *unifiedPolicies) addGraphPolicies(g routingGraph) error {
 			return err
 		}
 
+		// The inbound fee of the channel is part of the policy of the
+		// to node. An inbound fee that can't be parsed is ignored, in
+		// which case the to node will tell us what it expects if it
+		// fails the htlc.
+		var inboundFee lnwire.Fee
+		if outEdge != nil {
+			inboundFee, _ = outEdge.InboundFee()
+		}
+
 		// Add this policy to the unified policies map.
-		u.addPolicy(fromNode, inEdge, edgeInfo.Capacity)
+		u.addPolicy(fromNode, inEdge, inboundFee, edgeInfo.Capacity)
 
 		return nil
 	}
@@ -98,8 +110,9 @@ func (u *unifiedPolicies) addGraphPolicies(g routingGraph) error {
 // unifiedPolicyEdge is the individual channel data that is kept inside an
 // unifiedPolicy object.
 type unifiedPolicyEdge struct {
-	policy   *channeldb.ChannelEdgePolicy
-	capacity btcutil.Amount
+	policy     *channeldb.ChannelEdgePolicy
+	inboundFee lnwire.Fee
+	capacity   btcutil.Amount
 }
 
 // amtInRange checks whether an amount falls within the valid range for a
@@ -139,7 +152,7 @@ type unifiedPolicy struct {
 // specific amount to send. It differentiates between local and network
 // channels.
 func (u *unifiedPolicy) getPolicy(amt lnwire.MilliSatoshi,
-	bandwidthHints map[uint64]lnwire.MilliSatoshi) *channeldb.ChannelEdgePolicy {
+	bandwidthHints map[uint64]lnwire.MilliSatoshi) *pathEdge {
 
 	if u.localChan {
 		return u.getPolicyLocal(amt, bandwidthHints)
@@ -151,10 +164,10 @@ func (u *unifiedPolicy) getPolicy(amt lnwire.MilliSatoshi,
 // getPolicyLocal returns the optimal policy to use for this local connection
 // given a specific amount to send.
 func (u *unifiedPolicy) getPolicyLocal(amt lnwire.MilliSatoshi,
-	bandwidthHints map[uint64]lnwire.MilliSatoshi) *channeldb.ChannelEdgePolicy {
+	bandwidthHints map[uint64]lnwire.MilliSatoshi) *pathEdge {
 
 	var (
-		bestPolicy   *channeldb.ChannelEdgePolicy
+		bestEdge     *unifiedPolicyEdge
 		maxBandwidth lnwire.MilliSatoshi
 	)
 
@@ -196,22 +209,30 @@ func (u *unifiedPolicy) getPolicyLocal(amt lnwire.MilliSatoshi,
 		maxBandwidth = bandwidth
 
 		// Update best policy.
-		bestPolicy = edge.policy
+		bestEdge = edge
 	}
 
-	return bestPolicy
+	if bestEdge == nil {
+		return nil
+	}
+
+	return &pathEdge{
+		ChannelEdgePolicy: bestEdge.policy,
+		inboundFee:        bestEdge.inboundFee,
+	}
 }
 
 // getPolicyNetwork returns the optimal policy to use for this connection given
 // a specific amount to send. The goal is to return a policy that maximizes the
 // probability of a successful forward in a non-strict forwarding context.
 func (u *unifiedPolicy) getPolicyNetwork(
-	amt lnwire.MilliSatoshi) *channeldb.ChannelEdgePolicy {
+	amt lnwire.MilliSatoshi) *pathEdge {
 
 	var (
-		bestPolicy  *channeldb.ChannelEdgePolicy
-		maxFee      lnwire.MilliSatoshi
-		maxTimelock uint16
+		bestPolicy    *channeldb.ChannelEdgePolicy
+		maxFee        lnwire.MilliSatoshi
+		maxTimelock   uint16
+		maxInboundFee *lnwire.Fee
 	)
 
 	for _, edge := range u.edges {
@@ -233,6 +254,17 @@ func (u *unifiedPolicy) getPolicyNetwork(
 			maxTimelock = edge.policy.TimeLockDelta
 		}
 
+		// The channel that the routing node picks also determines
+		// the inbound fee of the to node, so we track the highest
+		// inbound fee of all candidates as well.
+		if maxInboundFee == nil ||
+			edge.inboundFee.CalcFee(amt) >
+				maxInboundFee.CalcFee(amt) {
+
+			inboundFee := edge.inboundFee
+			maxInboundFee = &inboundFee
+		}
+
 		// Use the policy that results in the highest fee for this
 		// specific amount.
 		fee := edge.policy.ComputeFee(amt)
@@ -262,7 +294,24 @@ func (u *unifiedPolicy) getPolicyNetwork(
 	modifiedPolicy := *bestPolicy
 	modifiedPolicy.TimeLockDelta = maxTimelock
 
-	return &modifiedPolicy
+	return &pathEdge{
+		ChannelEdgePolicy: &modifiedPolicy,
+		inboundFee:        *maxInboundFee,
+	}
+}
+
+// capacity returns the largest capacity of the channels of this connection.
+// Because of non-strict forwarding, the routing node may use any of them. It
+// returns zero if no capacity is known.
+func (u *unifiedPolicy) capacity() btcutil.Amount {
+	var capacity btcutil.Amount
+	for _, edge := range u.edges {
+		if edge.capacity > capacity {
+			capacity = edge.capacity
+		}
+	}
+
+	return capacity
 }
 
 // minAmt returns the minimum amount that can be forwarded on this connection.
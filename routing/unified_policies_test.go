@@ -36,15 +36,23 @@ func TestUnifiedPolicies(t *testing.T) {
 		MaxHTLC:                   400,
 		MinHTLC:                   100,
 	}
-	u.addPolicy(fromNode, &p1, 7)
-	u.addPolicy(fromNode, &p2, 7)
 
-	checkPolicy := func(policy *channeldb.ChannelEdgePolicy,
+	// The to node gives a discount on the first channel and charges an
+	// inbound fee on the second one.
+	u.addPolicy(fromNode, &p1, lnwire.Fee{BaseFee: -10}, 7)
+	u.addPolicy(fromNode, &p2, lnwire.Fee{BaseFee: 5}, 7)
+
+	checkPolicy := func(policy *pathEdge,
 		feeBase lnwire.MilliSatoshi, feeRate lnwire.MilliSatoshi,
-		timeLockDelta uint16) {
+		timeLockDelta uint16, inboundFee lnwire.Fee) {
 
 		t.Helper()
 
+		if policy.inboundFee != inboundFee {
+			t.Fatalf("expected inbound fee %v, got %v",
+				inboundFee, policy.inboundFee)
+		}
+
 		if policy.FeeBaseMSat != feeBase {
 			t.Fatalf("expected fee base %v, got %v",
 				feeBase, policy.FeeBaseMSat)
@@ -73,11 +81,12 @@ func TestUnifiedPolicies(t *testing.T) {
 
 	// For 200 sat, p1 yields the highest fee. Use that policy to forward,
 	// because it will also match p2 in case p1 does not have enough
-	// balance.
+	// balance. The inbound fee of p2 is the highest one, so it is used
+	// as well.
 	policy = u.policies[fromNode].getPolicy(200, bandwidthHints)
 	checkPolicy(
 		policy, p1.FeeBaseMSat, p1.FeeProportionalMillionths,
-		p1.TimeLockDelta,
+		p1.TimeLockDelta, lnwire.Fee{BaseFee: 5},
 	)
 
 	// For 400 sat, p2 yields the highest fee. Use that policy to forward,
@@ -86,6 +95,6 @@ func TestUnifiedPolicies(t *testing.T) {
 	policy = u.policies[fromNode].getPolicy(400, bandwidthHints)
 	checkPolicy(
 		policy, p2.FeeBaseMSat, p2.FeeProportionalMillionths,
-		p1.TimeLockDelta,
+		p1.TimeLockDelta, lnwire.Fee{BaseFee: 5},
 	)
 }
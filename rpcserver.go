@@ -1,6 +1,7 @@
 package lnd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/hex"
@@ -52,6 +53,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
@@ -60,10 +62,12 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/offers"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/rpcperms"
 	"github.com/lightningnetwork/lnd/signal"
@@ -300,6 +304,13 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/BatchOpenChannel": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/OpenChannelSync": {{
 			Entity: "onchain",
 			Action: "write",
@@ -318,6 +329,20 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/SpliceIn": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SpliceOut": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/GetInfo": {{
 			Entity: "info",
 			Action: "read",
@@ -358,6 +383,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ListAliases": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/SendPayment": {{
 			Entity: "offchain",
 			Action: "write",
@@ -382,6 +411,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "invoices",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/DeleteCanceledInvoice": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/ListInvoices": {{
 			Entity: "invoices",
 			Action: "read",
@@ -438,6 +471,14 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/DeletePayment": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/DeletePayments": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/DebugLevel": {{
 			Entity: "info",
 			Action: "write",
@@ -446,6 +487,18 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/DecodeOffer": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/CreateOffer": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/PayOffer": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/FeeReport": {{
 			Entity: "offchain",
 			Action: "read",
@@ -458,6 +511,14 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ForwardingAggregates": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/FailedForwardingHistory": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/RestoreChannelBackups": {{
 			Entity: "offchain",
 			Action: "write",
@@ -478,6 +539,16 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ExportDatabaseSnapshot": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "invoices",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ChannelAcceptor": {{
 			Entity: "onchain",
 			Action: "write",
@@ -505,6 +576,22 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "peers",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SendCustomMessage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeCustomMessages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SendOnionMessage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeOnionMessages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/FundingStateStep": {{
 			Entity: "onchain",
 			Action: "write",
@@ -626,6 +713,41 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			}
 			return info.Capacity, nil
 		},
+		FetchAmountPairCapacity: func(nodeFrom, nodeTo route.Vertex,
+			amount lnwire.MilliSatoshi) (btcutil.Amount, error) {
+
+			// Use the largest channel between the nodes that can
+			// carry the amount, because the forwarding node may
+			// pick any of them.
+			var capacity btcutil.Amount
+			err := graph.ForEachNodeChannel(nil, nodeFrom[:],
+				func(_ kvdb.RTx, info *channeldb.ChannelEdgeInfo,
+					_, _ *channeldb.ChannelEdgePolicy) error {
+
+					if info.NodeKey1Bytes != nodeTo &&
+						info.NodeKey2Bytes != nodeTo {
+
+						return nil
+					}
+
+					maxAmt := lnwire.NewMSatFromSatoshis(
+						info.Capacity,
+					)
+					if amount <= maxAmt &&
+						info.Capacity > capacity {
+
+						capacity = info.Capacity
+					}
+
+					return nil
+				},
+			)
+			if err != nil {
+				return 0, err
+			}
+
+			return capacity, nil
+		},
 		FetchChannelEndpoints: func(chanID uint64) (route.Vertex,
 			route.Vertex, error) {
 
@@ -656,6 +778,7 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			return s.chanStatusMgr.RequestDisable(outpoint, true)
 		},
 		SetChannelAuto: s.chanStatusMgr.RequestAuto,
+		CircuitBreaker: s.htlcSwitch.CircuitBreaker(),
 	}
 
 	genInvoiceFeatures := func() *lnwire.FeatureVector {
@@ -680,8 +803,9 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		s.htlcSwitch, r.cfg.ActiveNetParams.Params, s.chanRouter,
 		routerBackend, s.nodeSigner, s.localChanDB, s.remoteChanDB,
 		s.sweeper, tower, s.towerClient, s.anchorTowerClient,
-		r.cfg.net.ResolveTCPAddr, genInvoiceFeatures,
-		genAmpInvoiceFeatures, rpcsLog,
+		s.taprootTowerClient, r.cfg.net.ResolveTCPAddr,
+		genInvoiceFeatures, genAmpInvoiceFeatures,
+		s.aliasMgr.GetPeerAlias, rpcsLog,
 	)
 	if err != nil {
 		return err
@@ -1906,6 +2030,44 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 			err)
 	}
 
+	// If the user requested an explicit commitment type, we'll translate
+	// it to the channel type we'll propose to the remote peer.
+	var channelType *lnwire.ChannelType
+	switch in.CommitmentType {
+	case lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE:
+		break
+
+	case lnrpc.CommitmentType_LEGACY:
+		channelType = lnwire.NewChannelType()
+
+	case lnrpc.CommitmentType_STATIC_REMOTE_KEY:
+		channelType = lnwire.NewChannelType(
+			lnwire.StaticRemoteKeyRequired,
+		)
+
+	case lnrpc.CommitmentType_ANCHORS:
+		channelType = lnwire.NewChannelType(
+			lnwire.StaticRemoteKeyRequired,
+			lnwire.AnchorsZeroFeeHtlcTxRequired,
+		)
+
+	case lnrpc.CommitmentType_SIMPLE_TAPROOT:
+		// Taproot channels can't be announced yet, so we only allow
+		// them to be opened as private channels.
+		if !in.Private {
+			return nil, fmt.Errorf("taproot channels must be " +
+				"private")
+		}
+
+		channelType = lnwire.NewChannelType(
+			lnwire.SimpleTaprootChansRequired,
+		)
+
+	default:
+		return nil, fmt.Errorf("unhandled request channel type %v",
+			in.CommitmentType)
+	}
+
 	// Instruct the server to trigger the necessary events to attempt to
 	// open a new channel. A stream is returned in place, this stream will
 	// be used to consume updates of the state of the pending channel.
@@ -1923,6 +2085,9 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		MaxValueInFlight: maxValue,
 		MaxHtlcs:         maxHtlcs,
 		MaxLocalCsv:      uint16(in.MaxLocalCsv),
+		ZeroConf:         in.ZeroConf,
+		DualFund:         in.DualFund,
+		ChannelType:      channelType,
 	}, nil
 }
 
@@ -2026,6 +2191,82 @@ out:
 	return nil
 }
 
+// BatchOpenChannel attempts to open multiple single-funded channels in a
+// single transaction in an atomic way. This means either all channel open
+// requests succeed at once or all attempts are aborted if any of them fail.
+// This is the safer variant of using PSBTs to manually fund a batch of
+// channels through the OpenChannel RPC.
+func (r *rpcServer) BatchOpenChannel(ctx context.Context,
+	in *lnrpc.BatchOpenChannelRequest) (*lnrpc.BatchOpenChannelResponse,
+	error) {
+
+	if err := r.canOpenChannel(); err != nil {
+		return nil, err
+	}
+
+	// We need the wallet kit server to do the heavy lifting on the PSBT
+	// part. If we didn't rely on re-using the wallet kit server's logic we
+	// would need to re-implement everything here.
+	var walletKitServer walletrpc.WalletKitServer
+	for _, subServer := range r.subServers {
+		if server, ok := subServer.(walletrpc.WalletKitServer); ok {
+			walletKitServer = server
+		}
+	}
+	if walletKitServer == nil {
+		return nil, fmt.Errorf("batch channel open is only possible " +
+			"if walletrpc subserver is active")
+	}
+
+	rpcsLog.Debugf("[batchopenchannel] request to open batch of %d "+
+		"channels", len(in.Channels))
+
+	// Make sure there is at least one channel to open. We could say we
+	// want at least two channels for a batch. But maybe it's nice if
+	// developers can use the same API for a single channel as well as a
+	// batch of channels.
+	if len(in.Channels) == 0 {
+		return nil, fmt.Errorf("specify at least one channel")
+	}
+
+	// In case we remove a pending channel from the database, we need to
+	// set a close height, so we'll just use the current best known height.
+	_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching best block: %v", err)
+	}
+
+	// So far everything looks good and we can now start the heavy lifting
+	// that's done in the funding package.
+	requestParser := func(req *lnrpc.OpenChannelRequest) (
+		*funding.InitFundingMsg, error) {
+
+		return r.parseOpenChannelReq(req, false)
+	}
+	channelAbandoner := func(point *wire.OutPoint) error {
+		return r.abandonChan(point, uint32(bestHeight))
+	}
+	batcher := funding.NewBatcher(&funding.BatchConfig{
+		RequestParser:    requestParser,
+		ChannelAbandoner: channelAbandoner,
+		ChannelOpener:    r.server.OpenChannel,
+		WalletKitServer:  walletKitServer,
+		Wallet:           r.server.cc.Wallet,
+		NetParams:        &r.server.cc.Wallet.Cfg.NetParams,
+		Quit:             r.quit,
+	})
+	rpcPoints, err := batcher.BatchFund(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("batch funding failed: %v", err)
+	}
+
+	// Now all that's left to do is send back the response with the channel
+	// points we created.
+	return &lnrpc.BatchOpenChannelResponse{
+		PendingChannels: rpcPoints,
+	}, nil
+}
+
 // OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
 // call is meant to be consumed by clients to the REST proxy. As with all other
 // sync calls, all byte slices are instead to be populated as hex encoded
@@ -2431,24 +2672,146 @@ func (r *rpcServer) AbandonChannel(_ context.Context,
 		return nil, err
 	}
 
+	// Remove the channel from the graph, database and contract court.
+	if err := r.abandonChan(chanPoint, uint32(bestHeight)); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.AbandonChannelResponse{}, nil
+}
+
+// SpliceIn adds funds from the wallet to an active channel by negotiating a
+// splice transaction with the peer of the channel.
+func (r *rpcServer) SpliceIn(ctx context.Context,
+	in *lnrpc.SpliceInRequest) (*lnrpc.SpliceResponse, error) {
+
+	if in.Amount <= 0 {
+		return nil, fmt.Errorf("splice amount must be positive")
+	}
+
+	return r.splice(
+		ctx, in.ChannelPoint, btcutil.Amount(in.Amount), nil,
+		in.SatPerVbyte, in.TargetConf,
+	)
+}
+
+// SpliceOut removes funds from an active channel and sends them to an
+// on-chain address by negotiating a splice transaction with the peer of the
+// channel.
+func (r *rpcServer) SpliceOut(ctx context.Context,
+	in *lnrpc.SpliceOutRequest) (*lnrpc.SpliceResponse, error) {
+
+	if in.Amount <= 0 {
+		return nil, fmt.Errorf("splice amount must be positive")
+	}
+
+	// Decode the address receiving the funds, we need to check whether
+	// the address is valid for this network.
+	addr, err := btcutil.DecodeAddress(
+		in.Address, r.cfg.ActiveNetParams.Params,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !addr.IsForNet(r.cfg.ActiveNetParams.Params) {
+		return nil, fmt.Errorf("address: %v is not valid for this "+
+			"network: %v", addr.String(),
+			r.cfg.ActiveNetParams.Params.Name)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.splice(
+		ctx, in.ChannelPoint, -btcutil.Amount(in.Amount), pkScript,
+		in.SatPerVbyte, in.TargetConf,
+	)
+}
+
+// splice hands a splice request for the given channel to the peer of the
+// channel and waits until the splice transaction has been broadcast.
+func (r *rpcServer) splice(ctx context.Context,
+	rpcChanPoint *lnrpc.ChannelPoint, amt btcutil.Amount, pkScript []byte,
+	satPerVByte uint64, targetConf int32) (*lnrpc.SpliceResponse, error) {
+
+	if rpcChanPoint == nil {
+		return nil, fmt.Errorf("must specify channel point in splice")
+	}
+	txid, err := lnrpc.GetChanPointFundingTxid(rpcChanPoint)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, rpcChanPoint.OutputIndex)
+
+	channel, err := r.server.remoteChanDB.FetchChannel(*chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("unable to splice channel while peer "+
+			"is offline: %v", err)
+	}
+
+	feeRate, err := calculateFeeRate(
+		0, satPerVByte, uint32(targetConf), r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[splice] request for ChannelPoint(%v), amt=%v, "+
+		"sat/kw=%v", chanPoint, amt, int64(feeRate))
+
+	req := &funding.SpliceReq{
+		ChanPoint: chanPoint,
+		Amount:    amt,
+		PkScript:  pkScript,
+		FeeRate:   feeRate,
+		Txid:      make(chan chainhash.Hash, 1),
+		Err:       make(chan error, 1),
+	}
+	peer.HandleLocalSpliceReq(req)
+
+	select {
+	case spliceTxid := <-req.Txid:
+		return &lnrpc.SpliceResponse{Txid: spliceTxid.String()}, nil
+
+	case err := <-req.Err:
+		return nil, err
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-r.quit:
+		return nil, nil
+	}
+}
+
+// abandonChan removes a channel from the database, graph and contract court.
+func (r *rpcServer) abandonChan(chanPoint *wire.OutPoint,
+	bestHeight uint32) error {
+
 	// Abandoning a channel is a three step process: remove from the open
 	// channel state, remove from the graph, remove from the contract
 	// court. Between any step it's possible that the users restarts the
 	// process all over again. As a result, each of the steps below are
 	// intended to be idempotent.
-	err = r.server.remoteChanDB.AbandonChannel(chanPoint, uint32(bestHeight))
+	err := r.server.remoteChanDB.AbandonChannel(chanPoint, bestHeight)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	err = abandonChanFromGraph(
 		r.server.localChanDB.ChannelGraph(), chanPoint,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	err = r.server.chainArb.ResolveContract(*chanPoint)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// If this channel was in the process of being closed, but didn't fully
@@ -2457,14 +2820,14 @@ func (r *rpcServer) AbandonChannel(_ context.Context,
 	// state for this channel from the nursery.
 	err = r.server.utxoNursery.cfg.Store.RemoveChannel(chanPoint)
 	if err != nil && err != ErrContractNotFound {
-		return nil, err
+		return err
 	}
 
 	// Finally, notify the backup listeners that the channel can be removed
 	// from any channel backups.
 	r.server.channelNotifier.NotifyClosedChannelEvent(*chanPoint)
 
-	return &lnrpc.AbandonChannelResponse{}, nil
+	return nil
 }
 
 // GetInfo returns general information concerning the lightning node including
@@ -2791,64 +3154,299 @@ func (r *rpcServer) SubscribePeerEvents(req *lnrpc.PeerEventSubscription,
 	}
 }
 
-// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
-// confirmed unspent outputs and all unconfirmed unspent outputs under control
-// by the wallet. This method can be modified by having the request specify
-// only witness outputs should be factored into the final output sum.
-// TODO(roasbeef): add async hooks into wallet balance changes
-func (r *rpcServer) WalletBalance(ctx context.Context,
-	in *lnrpc.WalletBalanceRequest) (*lnrpc.WalletBalanceResponse, error) {
+// SendCustomMessage sends a custom peer message.
+func (r *rpcServer) SendCustomMessage(ctx context.Context,
+	req *lnrpc.SendCustomMessageRequest) (*lnrpc.SendCustomMessageResponse,
+	error) {
 
-	// Retrieve all existing wallet accounts. We'll compute the confirmed
-	// and unconfirmed balance for each and tally them up.
-	accounts, err := r.server.cc.Wallet.ListAccounts("", nil)
+	peer, err := route.NewVertexFromBytes(req.Peer)
 	if err != nil {
 		return nil, err
 	}
 
-	var totalBalance, confirmedBalance, unconfirmedBalance btcutil.Amount
-	rpcAccountBalances := make(
-		map[string]*lnrpc.WalletAccountBalance, len(accounts),
+	if req.Type > math.MaxUint16 {
+		return nil, fmt.Errorf("message type %v exceeds the maximum "+
+			"of %v", req.Type, math.MaxUint16)
+	}
+
+	err = r.server.SendCustomMessage(
+		peer, lnwire.MessageType(req.Type), req.Data,
 	)
-	for _, account := range accounts {
-		// There are two default accounts, one for NP2WKH outputs and
-		// another for P2WKH outputs. The balance will be computed for
-		// both given one call to ConfirmedBalance with the default
-		// wallet and imported account, so we'll skip the second
-		// instance to avoid inflating the balance.
-		switch account.AccountName {
-		case waddrmgr.ImportedAddrAccountName:
-			// Omit the imported account from the response unless we
-			// actually have any keys imported.
-			if account.ImportedKeyCount == 0 {
-				continue
-			}
+	switch {
+	case err == ErrPeerNotConnected:
+		return nil, status.Error(codes.NotFound, err.Error())
 
-			fallthrough
+	case err != nil:
+		return nil, err
+	}
 
-		case lnwallet.DefaultAccountName:
-			if _, ok := rpcAccountBalances[account.AccountName]; ok {
-				continue
-			}
+	return &lnrpc.SendCustomMessageResponse{}, nil
+}
 
-		default:
-		}
+// SubscribeCustomMessages subscribes to a stream of incoming custom peer
+// messages.
+func (r *rpcServer) SubscribeCustomMessages(
+	req *lnrpc.SubscribeCustomMessagesRequest,
+	server lnrpc.Lightning_SubscribeCustomMessagesServer) error {
 
-		// Get total balance, from txs that have >= 0 confirmations.
-		totalBal, err := r.server.cc.Wallet.ConfirmedBalance(
-			0, account.AccountName,
-		)
-		if err != nil {
-			return nil, err
-		}
-		totalBalance += totalBal
+	client, err := r.server.SubscribeCustomMessages()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
 
-		// Get confirmed balance, from txs that have >= 1 confirmations.
-		// TODO(halseth): get both unconfirmed and confirmed balance in
-		// one call, as this is racy.
-		confirmedBal, err := r.server.cc.Wallet.ConfirmedBalance(
-			1, account.AccountName,
-		)
+	for {
+		select {
+		case <-client.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-client.Updates():
+			customMsg := update.(*CustomMessage)
+
+			err := server.Send(&lnrpc.CustomMessage{
+				Peer: customMsg.Peer[:],
+				Data: customMsg.Msg.Data,
+				Type: uint32(customMsg.Msg.Type),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SendOnionMessage sends an onion message along a path of nodes, optionally
+// followed by a blinded destination.
+func (r *rpcServer) SendOnionMessage(ctx context.Context,
+	req *lnrpc.SendOnionMessageRequest) (*lnrpc.SendOnionMessageResponse,
+	error) {
+
+	path := make([]*btcec.PublicKey, len(req.Path))
+	for i, nodeBytes := range req.Path {
+		node, err := btcec.ParsePubKey(nodeBytes, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("invalid path node %x: %v",
+				nodeBytes, err)
+		}
+		path[i] = node
+	}
+
+	var destination *blindedpath.BlindedPath
+	if req.BlindedDestination != nil {
+		var err error
+		destination, err = unmarshalBlindedPath(req.BlindedDestination)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	payload := &hop.OnionMessagePayload{
+		CustomRecords: req.CustomRecords,
+	}
+
+	if req.IncludeReplyPath {
+		// The reply travels back through the intermediate nodes of
+		// the path in reverse order. Without a blinded destination,
+		// the last node of the path is the recipient itself.
+		intermediates := path
+		if destination == nil && len(path) > 0 {
+			intermediates = path[:len(path)-1]
+		}
+
+		replyNodes := make(
+			[]*btcec.PublicKey, 0, len(intermediates)+1,
+		)
+		for i := len(intermediates) - 1; i >= 0; i-- {
+			replyNodes = append(replyNodes, intermediates[i])
+		}
+		replyNodes = append(
+			replyNodes, r.server.identityECDH.PubKey(),
+		)
+
+		replyPath, err := hop.BuildOnionMessagePath(replyNodes, nil)
+		if err != nil {
+			return nil, err
+		}
+		payload.ReplyPath = replyPath
+	}
+
+	err := r.server.SendOnionMessage(path, destination, payload)
+	switch {
+	case err == ErrPeerNotConnected:
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnrpc.SendOnionMessageResponse{}, nil
+}
+
+// SubscribeOnionMessages subscribes to a stream of onion messages addressed to
+// us.
+func (r *rpcServer) SubscribeOnionMessages(
+	req *lnrpc.SubscribeOnionMessagesRequest,
+	server lnrpc.Lightning_SubscribeOnionMessagesServer) error {
+
+	client, err := r.server.SubscribeOnionMessages()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case <-client.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-client.Updates():
+			onionMsg := update.(*OnionMessage)
+
+			rpcUpdate := &lnrpc.OnionMessageUpdate{
+				CustomRecords: onionMsg.Payload.CustomRecords,
+			}
+			if onionMsg.Payload.ReplyPath != nil {
+				rpcUpdate.ReplyPath = marshalBlindedPath(
+					onionMsg.Payload.ReplyPath,
+				)
+			}
+
+			if err := server.Send(rpcUpdate); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// unmarshalBlindedPath converts an rpc blinded path into its native
+// counterpart.
+func unmarshalBlindedPath(rpcPath *lnrpc.BlindedPath) (*blindedpath.BlindedPath,
+	error) {
+
+	introNode, err := btcec.ParsePubKey(
+		rpcPath.IntroductionNode, btcec.S256(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid introduction node: %v", err)
+	}
+
+	blindingPoint, err := btcec.ParsePubKey(
+		rpcPath.BlindingPoint, btcec.S256(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blinding point: %v", err)
+	}
+
+	if len(rpcPath.BlindedHops) == 0 {
+		return nil, blindedpath.ErrNoHops
+	}
+
+	path := &blindedpath.BlindedPath{
+		IntroductionPoint: introNode,
+		BlindingPoint:     blindingPoint,
+	}
+	for _, rpcHop := range rpcPath.BlindedHops {
+		blindedNode, err := btcec.ParsePubKey(
+			rpcHop.BlindedNode, btcec.S256(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blinded node: %v", err)
+		}
+
+		path.BlindedHops = append(
+			path.BlindedHops, &blindedpath.BlindedHop{
+				BlindedNodePub: blindedNode,
+				CipherText:     rpcHop.EncryptedData,
+			},
+		)
+	}
+
+	return path, nil
+}
+
+// marshalBlindedPath converts a blinded path into its rpc counterpart.
+func marshalBlindedPath(path *blindedpath.BlindedPath) *lnrpc.BlindedPath {
+	rpcPath := &lnrpc.BlindedPath{
+		IntroductionNode: path.IntroductionPoint.SerializeCompressed(),
+		BlindingPoint:    path.BlindingPoint.SerializeCompressed(),
+	}
+	for _, blindedHop := range path.BlindedHops {
+		blindedNode := blindedHop.BlindedNodePub.SerializeCompressed()
+		rpcPath.BlindedHops = append(
+			rpcPath.BlindedHops, &lnrpc.BlindedHop{
+				BlindedNode:   blindedNode,
+				EncryptedData: blindedHop.CipherText,
+			},
+		)
+	}
+
+	return rpcPath
+}
+
+// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
+// confirmed unspent outputs and all unconfirmed unspent outputs under control
+// by the wallet. This method can be modified by having the request specify
+// only witness outputs should be factored into the final output sum.
+// TODO(roasbeef): add async hooks into wallet balance changes
+func (r *rpcServer) WalletBalance(ctx context.Context,
+	in *lnrpc.WalletBalanceRequest) (*lnrpc.WalletBalanceResponse, error) {
+
+	// Retrieve all existing wallet accounts. We'll compute the confirmed
+	// and unconfirmed balance for each and tally them up.
+	accounts, err := r.server.cc.Wallet.ListAccounts("", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalBalance, confirmedBalance, unconfirmedBalance btcutil.Amount
+	rpcAccountBalances := make(
+		map[string]*lnrpc.WalletAccountBalance, len(accounts),
+	)
+	for _, account := range accounts {
+		// There are two default accounts, one for NP2WKH outputs and
+		// another for P2WKH outputs. The balance will be computed for
+		// both given one call to ConfirmedBalance with the default
+		// wallet and imported account, so we'll skip the second
+		// instance to avoid inflating the balance.
+		switch account.AccountName {
+		case waddrmgr.ImportedAddrAccountName:
+			// Omit the imported account from the response unless we
+			// actually have any keys imported.
+			if account.ImportedKeyCount == 0 {
+				continue
+			}
+
+			fallthrough
+
+		case lnwallet.DefaultAccountName:
+			if _, ok := rpcAccountBalances[account.AccountName]; ok {
+				continue
+			}
+
+		default:
+		}
+
+		// Get total balance, from txs that have >= 0 confirmations.
+		totalBal, err := r.server.cc.Wallet.ConfirmedBalance(
+			0, account.AccountName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		totalBalance += totalBal
+
+		// Get confirmed balance, from txs that have >= 1 confirmations.
+		// TODO(halseth): get both unconfirmed and confirmed balance in
+		// one call, as this is racy.
+		confirmedBal, err := r.server.cc.Wallet.ConfirmedBalance(
+			1, account.AccountName,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -3359,6 +3957,31 @@ func (r *rpcServer) nurseryPopulateForceCloseResp(chanPoint *wire.OutPoint,
 	return nil
 }
 
+// ListAliases returns the set of all aliases that have ever existed with their
+// confirmed short channel id (if it exists) and the alias base short channel
+// id.
+func (r *rpcServer) ListAliases(ctx context.Context,
+	in *lnrpc.ListAliasesRequest) (*lnrpc.ListAliasesResponse, error) {
+
+	rpcsLog.Debugf("[listaliases]")
+
+	resp := &lnrpc.ListAliasesResponse{}
+	for base, aliases := range r.server.aliasMgr.ListAliases() {
+		aliasMap := &lnrpc.AliasMap{
+			BaseScid: base.ToUint64(),
+		}
+		for _, alias := range aliases {
+			aliasMap.Aliases = append(
+				aliasMap.Aliases, alias.ToUint64(),
+			)
+		}
+
+		resp.AliasMaps = append(resp.AliasMaps, aliasMap)
+	}
+
+	return resp, nil
+}
+
 // ClosedChannels returns a list of all the channels have been closed.
 // This does not include channels that are still in the process of closing.
 func (r *rpcServer) ClosedChannels(ctx context.Context,
@@ -3515,8 +4138,13 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 // type value.
 func rpcCommitmentType(chanType channeldb.ChannelType) lnrpc.CommitmentType {
 	// Extract the commitment type from the channel type flags. We must
-	// first check whether it has anchors, since in that case it would also
-	// be tweakless.
+	// first check whether it's a taproot channel, since in that case it
+	// would also have anchors, and whether it has anchors, since in that
+	// case it would also be tweakless.
+	if chanType.IsTaproot() {
+		return lnrpc.CommitmentType_SIMPLE_TAPROOT
+	}
+
 	if chanType.HasAnchors() {
 		return lnrpc.CommitmentType_ANCHORS
 	}
@@ -3614,6 +4242,28 @@ func createRPCOpenChannel(r *rpcServer, graph *channeldb.ChannelGraph,
 		CsvDelay:             uint32(dbChannel.LocalChanCfg.CsvDelay),
 		LocalChanReserveSat:  int64(dbChannel.LocalChanCfg.ChanReserve),
 		RemoteChanReserveSat: int64(dbChannel.RemoteChanCfg.ChanReserve),
+		ZeroConf:             dbChannel.ChanType.IsZeroConf(),
+	}
+
+	// Populate the set of aliases of the channel, as well as the alias
+	// our peer sent us for it.
+	for _, alias := range r.server.aliasMgr.GetAliases(
+		dbChannel.ShortChannelID,
+	) {
+
+		channel.AliasScids = append(
+			channel.AliasScids, alias.ToUint64(),
+		)
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+	if peerAlias, err := r.server.aliasMgr.GetPeerAlias(chanID); err == nil {
+		channel.PeerScidAlias = peerAlias.ToUint64()
+	}
+
+	if dbChannel.ChanType.IsZeroConf() {
+		channel.ZeroConfConfirmedScid = dbChannel.ZeroConfRealScid().
+			ToUint64()
 	}
 
 	for i, htlc := range localCommit.Htlcs {
@@ -4005,6 +4655,22 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 					},
 				}
 
+			// A spliced channel is reported as a newly opened
+			// channel spending the new funding outpoint.
+			case channelnotifier.SplicedChannelEvent:
+				channel, err := createRPCOpenChannel(r, graph,
+					event.Channel, true)
+				if err != nil {
+					return err
+				}
+
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_OPEN_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_OpenChannel{
+						OpenChannel: channel,
+					},
+				}
+
 			case channelnotifier.ClosedChannelEvent:
 				closedChannel, err := r.createRPCClosedChannel(
 					event.CloseSummary,
@@ -4794,26 +5460,7 @@ func (r *rpcServer) sendPaymentSync(ctx context.Context,
 func (r *rpcServer) AddInvoice(ctx context.Context,
 	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
 
-	defaultDelta := r.cfg.Bitcoin.TimeLockDelta
-	if r.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
-		defaultDelta = r.cfg.Litecoin.TimeLockDelta
-	}
-
-	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
-		AddInvoice:        r.server.invoices.AddInvoice,
-		IsChannelActive:   r.server.htlcSwitch.HasActiveLink,
-		ChainParams:       r.cfg.ActiveNetParams.Params,
-		NodeSigner:        r.server.nodeSigner,
-		DefaultCLTVExpiry: defaultDelta,
-		ChanDB:            r.server.remoteChanDB,
-		Graph:             r.server.localChanDB.ChannelGraph(),
-		GenInvoiceFeatures: func() *lnwire.FeatureVector {
-			return r.server.featureMgr.Get(feature.SetInvoice)
-		},
-		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
-			return r.server.featureMgr.Get(feature.SetInvoiceAmp)
-		},
-	}
+	addInvoiceCfg := r.server.addInvoiceConfig()
 
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
 	if err != nil {
@@ -4835,6 +5482,7 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		Private:         invoice.Private,
 		RouteHints:      routeHints,
 		Amp:             invoice.IsAmp,
+		Blind:           invoice.Blind,
 	}
 
 	if invoice.RPreimage != nil {
@@ -4912,6 +5560,28 @@ func (r *rpcServer) LookupInvoice(ctx context.Context,
 	return rpcInvoice, nil
 }
 
+// DeleteCanceledInvoice removes a canceled invoice from the database.
+func (r *rpcServer) DeleteCanceledInvoice(ctx context.Context,
+	req *lnrpc.DelCanceledInvoiceReq) (*lnrpc.DelCanceledInvoiceResp,
+	error) {
+
+	payHash, err := lntypes.MakeHashFromStr(req.InvoiceHash)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[deletecanceledinvoice] payment_hash=%v", payHash)
+
+	err = r.server.invoices.DeleteCanceledInvoice(payHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DelCanceledInvoiceResp{
+		Status: fmt.Sprintf("canceled invoice %v deleted", payHash),
+	}, nil
+}
+
 // ListInvoices returns a list of all the invoices currently stored within the
 // database. Any active debug invoices are ignored.
 func (r *rpcServer) ListInvoices(ctx context.Context,
@@ -4926,10 +5596,20 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 	// Next, we'll map the proto request into a format that is understood by
 	// the database.
 	q := channeldb.InvoiceQuery{
-		IndexOffset:    req.IndexOffset,
-		NumMaxInvoices: req.NumMaxInvoices,
-		PendingOnly:    req.PendingOnly,
-		Reversed:       req.Reversed,
+		IndexOffset:       req.IndexOffset,
+		NumMaxInvoices:    req.NumMaxInvoices,
+		PendingOnly:       req.PendingOnly,
+		Reversed:          req.Reversed,
+		CreationDateStart: int64(req.CreationDateStart),
+		CreationDateEnd:   int64(req.CreationDateEnd),
+	}
+
+	for _, rpcState := range req.States {
+		state, err := invoicesrpc.UnmarshallInvoiceState(rpcState)
+		if err != nil {
+			return nil, err
+		}
+		q.States = append(q.States, state)
 	}
 	invoiceSlice, err := r.server.remoteChanDB.QueryInvoices(q)
 	if err != nil {
@@ -5691,6 +6371,17 @@ func (r *rpcServer) ListPayments(ctx context.Context,
 		MaxPayments:       req.MaxPayments,
 		Reversed:          req.Reversed,
 		IncludeIncomplete: req.IncludeIncomplete,
+		CreationDateStart: int64(req.CreationDateStart),
+		CreationDateEnd:   int64(req.CreationDateEnd),
+		CountTotal:        req.CountTotalPayments,
+	}
+
+	for _, rpcStatus := range req.Statuses {
+		status, err := routerrpc.UnmarshallPaymentStatus(rpcStatus)
+		if err != nil {
+			return nil, err
+		}
+		query.Statuses = append(query.Statuses, status)
 	}
 
 	// If the maximum number of payments wasn't specified, then we'll
@@ -5707,6 +6398,7 @@ func (r *rpcServer) ListPayments(ctx context.Context,
 	paymentsResp := &lnrpc.ListPaymentsResponse{
 		LastIndexOffset:  paymentsQuerySlice.LastIndexOffset,
 		FirstIndexOffset: paymentsQuerySlice.FirstIndexOffset,
+		TotalNumPayments: paymentsQuerySlice.TotalCount,
 	}
 
 	for _, payment := range paymentsQuerySlice.Payments {
@@ -5744,6 +6436,66 @@ func (r *rpcServer) DeleteAllPayments(ctx context.Context,
 	return &lnrpc.DeleteAllPaymentsResponse{}, nil
 }
 
+// DeletePayment deletes an outgoing payment from DB.
+func (r *rpcServer) DeletePayment(ctx context.Context,
+	req *lnrpc.DeletePaymentRequest) (*lnrpc.DeletePaymentResponse, error) {
+
+	hash, err := lntypes.MakeHash(req.PaymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[DeletePayment] payment_hash=%v, failed_htlcs_only=%v",
+		hash, req.FailedHtlcsOnly)
+
+	paymentControl := channeldb.NewPaymentControl(r.server.remoteChanDB)
+	err = paymentControl.DeletePayment(hash, req.FailedHtlcsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DeletePaymentResponse{}, nil
+}
+
+// DeletePayments deletes the outgoing payments from DB that match the given
+// filters.
+func (r *rpcServer) DeletePayments(ctx context.Context,
+	req *lnrpc.DeletePaymentsRequest) (*lnrpc.DeletePaymentsResponse,
+	error) {
+
+	rpcsLog.Infof("[DeletePayments] status=%v, created_before=%v, "+
+		"failed_htlcs_only=%v", req.Status, req.CreatedBefore,
+		req.FailedHtlcsOnly)
+
+	status, err := routerrpc.UnmarshallPaymentStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	query := channeldb.DeletePaymentsQuery{
+		Status:          status,
+		FailedHtlcsOnly: req.FailedHtlcsOnly,
+	}
+
+	switch {
+	case req.CreatedBefore < 0:
+		return nil, fmt.Errorf("created_before must not be negative")
+
+	case req.CreatedBefore > 0:
+		query.CreatedBefore = time.Unix(req.CreatedBefore, 0)
+	}
+
+	paymentControl := channeldb.NewPaymentControl(r.server.remoteChanDB)
+	numDeleted, err := paymentControl.DeletePayments(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DeletePaymentsResponse{
+		NumDeleted: uint64(numDeleted),
+	}, nil
+}
+
 // DebugLevel allows a caller to programmatically set the logging verbosity of
 // lnd. The logging can be targeted according to a coarse daemon-wide logging
 // level, or in a granular fashion to specify the logging for a target
@@ -5842,11 +6594,205 @@ func (r *rpcServer) DecodePayReq(ctx context.Context,
 	}, nil
 }
 
-// feeBase is the fixed point that fee rate computation are performed over.
-// Nodes on the network advertise their fee rate using this point as a base.
-// This means that the minimal possible fee rate if 1e-6, or 0.000001, or
-// 0.0001%.
-const feeBase = 1000000
+// DecodeOffer takes an encoded BOLT 12 offer string and attempts to decode
+// it, returning a full description of the fields of the offer.
+func (r *rpcServer) DecodeOffer(ctx context.Context,
+	req *lnrpc.OfferString) (*lnrpc.Offer, error) {
+
+	rpcsLog.Tracef("[decodeoffer] decoding: %v", req.Offer)
+
+	offer, err := offers.DecodeOffer(req.Offer)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalOffer(offer)
+}
+
+// marshalOffer converts an offer into its rpc counterpart.
+func marshalOffer(offer *offers.Offer) (*lnrpc.Offer, error) {
+	offerID, err := offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcOffer := &lnrpc.Offer{
+		OfferId:     offerID[:],
+		Metadata:    offer.Metadata,
+		Currency:    offer.Currency,
+		Amount:      offer.Amount,
+		Description: offer.Description,
+		Issuer:      offer.Issuer,
+	}
+
+	for _, chain := range offer.Chains {
+		chain := chain
+		rpcOffer.Chains = append(rpcOffer.Chains, chain[:])
+	}
+
+	if offer.Features != nil {
+		rpcOffer.Features = invoicesrpc.CreateRPCFeatures(
+			lnwire.NewFeatureVector(offer.Features, lnwire.Features),
+		)
+	}
+
+	if !offer.AbsoluteExpiry.IsZero() {
+		rpcOffer.AbsoluteExpiry = offer.AbsoluteExpiry.Unix()
+	}
+
+	for _, path := range offer.Paths {
+		rpcOffer.Paths = append(rpcOffer.Paths, marshalBlindedPath(path))
+	}
+
+	if offer.QuantityMax != nil {
+		rpcOffer.HasQuantity = true
+		rpcOffer.QuantityMax = *offer.QuantityMax
+	}
+
+	if offer.NodeID != nil {
+		rpcOffer.NodeId = hex.EncodeToString(
+			offer.NodeID.SerializeCompressed(),
+		)
+	}
+
+	return rpcOffer, nil
+}
+
+// CreateOffer creates a new BOLT 12 offer for this node. Offers are not
+// stored, invoice requests for them are recognized by the metadata of the
+// offer.
+func (r *rpcServer) CreateOffer(ctx context.Context,
+	req *lnrpc.CreateOfferRequest) (*lnrpc.CreateOfferResponse, error) {
+
+	params := &offers.OfferParams{
+		Amount:      lnwire.MilliSatoshi(req.AmountMsat),
+		Description: req.Description,
+		Issuer:      req.Issuer,
+	}
+
+	if req.AllowQuantity {
+		quantityMax := req.QuantityMax
+		params.QuantityMax = &quantityMax
+	} else if req.QuantityMax != 0 {
+		return nil, fmt.Errorf("quantity_max requires allow_quantity")
+	}
+
+	if req.AbsoluteExpiry != 0 {
+		params.AbsoluteExpiry = time.Unix(req.AbsoluteExpiry, 0)
+	}
+
+	offer, err := r.server.offerManager.CreateOffer(params)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := offer.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	offerID, err := offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[createoffer] created offer %x", offerID[:])
+
+	return &lnrpc.CreateOfferResponse{
+		Offer:   encoded,
+		OfferId: offerID[:],
+	}, nil
+}
+
+// PayOffer fetches an invoice for a BOLT 12 offer from its issuer and pays it
+// over one of its blinded payment paths. The call blocks until the payment
+// succeeded or failed.
+func (r *rpcServer) PayOffer(ctx context.Context,
+	req *lnrpc.PayOfferRequest) (*lnrpc.SendResponse, error) {
+
+	// We don't allow payments to be sent while the daemon itself is still
+	// syncing as we may be trying to sent a payment over a "stale"
+	// channel.
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	offer, err := offers.DecodeOffer(req.Offer)
+	if err != nil {
+		return nil, err
+	}
+
+	if !offer.SupportsChain(*r.cfg.ActiveNetParams.GenesisHash) {
+		return nil, fmt.Errorf("offer is not valid for chain %v",
+			r.cfg.ActiveNetParams.Name)
+	}
+
+	invoice, err := r.server.offerManager.FetchInvoice(
+		offer, lnwire.MilliSatoshi(req.AmtMsat), req.Quantity,
+		req.PayerNote,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch invoice: %v", err)
+	}
+
+	rpcsLog.Debugf("[payoffer] fetched invoice %v for %v",
+		invoice.PaymentHash, invoice.Amount)
+
+	// Find a route over the first blinded payment path that we can pay.
+	restrictions := &routing.RestrictParams{
+		FeeLimit: lnrpc.CalculateFeeLimit(
+			req.FeeLimit, invoice.Amount,
+		),
+		CltvLimit:         r.routerBackend.MaxTotalTimelock,
+		ProbabilitySource: r.server.missionControl.GetProbability,
+	}
+
+	var (
+		rt       *route.Route
+		routeErr = fmt.Errorf("invoice has no blinded payment paths")
+	)
+	for _, path := range invoice.Paths {
+		rt, routeErr = r.server.chanRouter.FindBlindedRoute(
+			invoice.Amount, path, restrictions,
+		)
+		if routeErr == nil {
+			break
+		}
+
+		rpcsLog.Debugf("[payoffer] unable to route to blinded path: "+
+			"%v", routeErr)
+	}
+	if rt == nil {
+		return nil, fmt.Errorf("unable to find route: %v", routeErr)
+	}
+
+	attempt, err := r.server.chanRouter.SendToRoute(invoice.PaymentHash, rt)
+	if err != nil {
+		rpcsLog.Warnf("Unable to send payment: %v", err)
+
+		return &lnrpc.SendResponse{
+			PaymentError: err.Error(),
+			PaymentHash:  invoice.PaymentHash[:],
+		}, nil
+	}
+
+	rpcRoute, err := r.routerBackend.MarshallRoute(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendResponse{
+		PaymentHash:     invoice.PaymentHash[:],
+		PaymentPreimage: attempt.Settle.Preimage[:],
+		PaymentRoute:    rpcRoute,
+	}, nil
+}
+
+// feeBase is the fixed point that fee rate computation are performed over.
+// Nodes on the network advertise their fee rate using this point as a base.
+// This means that the minimal possible fee rate if 1e-6, or 0.000001, or
+// 0.0001%.
+const feeBase = 1000000
 
 // FeeReport allows the caller to obtain a report detailing the current fee
 // schedule enforced by the node globally for each channel.
@@ -5881,13 +6827,20 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 		feeRateFixedPoint := edgePolicy.FeeProportionalMillionths
 		feeRate := float64(feeRateFixedPoint) / float64(feeBase)
 
+		inboundFee, err := edgePolicy.InboundFee()
+		if err != nil {
+			return err
+		}
+
 		// TODO(roasbeef): also add stats for revenue for each channel
 		feeReports = append(feeReports, &lnrpc.ChannelFeeReport{
-			ChanId:       chanInfo.ChannelID,
-			ChannelPoint: chanInfo.ChannelPoint.String(),
-			BaseFeeMsat:  int64(edgePolicy.FeeBaseMSat),
-			FeePerMil:    int64(feeRateFixedPoint),
-			FeeRate:      feeRate,
+			ChanId:             chanInfo.ChannelID,
+			ChannelPoint:       chanInfo.ChannelPoint.String(),
+			BaseFeeMsat:        int64(edgePolicy.FeeBaseMSat),
+			FeePerMil:          int64(feeRateFixedPoint),
+			FeeRate:            feeRate,
+			InboundBaseFeeMsat: inboundFee.BaseFee,
+			InboundFeeRatePpm:  inboundFee.FeeRate,
 		})
 
 		return nil
@@ -6054,19 +7007,27 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 		minHtlc = &min
 	}
 
+	var inboundFee *lnwire.Fee
+	if req.InboundFeeSpecified {
+		inboundFee = &lnwire.Fee{
+			BaseFee: req.InboundBaseFeeMsat,
+			FeeRate: req.InboundFeeRatePpm,
+		}
+	}
+
 	chanPolicy := routing.ChannelPolicy{
 		FeeSchema:     feeSchema,
 		TimeLockDelta: req.TimeLockDelta,
 		MaxHTLC:       maxHtlc,
 		MinHTLC:       minHtlc,
+		InboundFee:    inboundFee,
 	}
 
 	rpcsLog.Debugf("[updatechanpolicy] updating channel policy base_fee=%v, "+
 		"rate_float=%v, rate_fixed=%v, time_lock_delta: %v, "+
-		"min_htlc=%v, max_htlc=%v, targets=%v",
+		"min_htlc=%v, max_htlc=%v, inbound_fee=%v, targets=%v",
 		req.BaseFeeMsat, req.FeeRate, feeRateFixed, req.TimeLockDelta,
-		minHtlc, maxHtlc,
-		spew.Sdump(targetChans))
+		minHtlc, maxHtlc, inboundFee, spew.Sdump(targetChans))
 
 	// With the scope resolved, we'll now send this to the local channel
 	// manager so it can propagate the new policy for our target channel(s).
@@ -6102,6 +7063,52 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 			"events: %v", err)
 	}
 
+	// Next, we'll map the proto request into a format that is understood by
+	// the forwarding log.
+	eventQuery := unmarshallForwardingEventQuery(req)
+	timeSlice, err := r.server.remoteChanDB.ForwardingLog().Query(eventQuery)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query forwarding log: %v", err)
+	}
+
+	// TODO(roasbeef): add settlement latency?
+	//  * use FPE on all records?
+
+	// With the events retrieved, we'll now map them into the proper proto
+	// response.
+	//
+	// TODO(roasbeef): show in ns for the outside?
+	resp := &lnrpc.ForwardingHistoryResponse{
+		ForwardingEvents: make([]*lnrpc.ForwardingEvent, len(timeSlice.ForwardingEvents)),
+		LastOffsetIndex:  timeSlice.LastIndexOffset,
+	}
+	for i, event := range timeSlice.ForwardingEvents {
+		amtInMsat := event.AmtIn
+		amtOutMsat := event.AmtOut
+		feeMsat := event.AmtIn - event.AmtOut
+
+		resp.ForwardingEvents[i] = &lnrpc.ForwardingEvent{
+			Timestamp:   uint64(event.Timestamp.Unix()),
+			TimestampNs: uint64(event.Timestamp.UnixNano()),
+			ChanIdIn:    event.IncomingChanID.ToUint64(),
+			ChanIdOut:   event.OutgoingChanID.ToUint64(),
+			AmtIn:       uint64(amtInMsat.ToSatoshis()),
+			AmtOut:      uint64(amtOutMsat.ToSatoshis()),
+			Fee:         uint64(feeMsat.ToSatoshis()),
+			FeeMsat:     uint64(feeMsat),
+			AmtInMsat:   uint64(amtInMsat),
+			AmtOutMsat:  uint64(amtOutMsat),
+		}
+	}
+
+	return resp, nil
+}
+
+// unmarshallForwardingEventQuery maps a forwarding history request into a
+// query of the forwarding log.
+func unmarshallForwardingEventQuery(
+	req *lnrpc.ForwardingHistoryRequest) channeldb.ForwardingEventQuery {
+
 	var (
 		startTime, endTime time.Time
 
@@ -6126,52 +7133,246 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 		numEvents = 100
 	}
 
-	// Next, we'll map the proto request into a format that is understood by
-	// the forwarding log.
-	eventQuery := channeldb.ForwardingEventQuery{
+	return channeldb.ForwardingEventQuery{
 		StartTime:    startTime,
 		EndTime:      endTime,
 		IndexOffset:  req.IndexOffset,
 		NumMaxEvents: numEvents,
 	}
-	timeSlice, err := r.server.remoteChanDB.ForwardingLog().Query(eventQuery)
+}
+
+// FailedForwardingHistory allows the caller to query the htlcswitch for a
+// record of all HTLC forwards that were failed back within the target time
+// range. The query is paginated in the same way as ForwardingHistory.
+func (r *rpcServer) FailedForwardingHistory(ctx context.Context,
+	req *lnrpc.ForwardingHistoryRequest) (
+	*lnrpc.FailedForwardingHistoryResponse, error) {
+
+	rpcsLog.Debugf("[failedforwardinghistory]")
+
+	// Flush any pending events to disk first, so that they are included
+	// in the response.
+	if err := r.server.htlcSwitch.FlushForwardingEvents(); err != nil {
+		return nil, fmt.Errorf("unable to flush forwarding "+
+			"events: %v", err)
+	}
+
+	eventQuery := unmarshallForwardingEventQuery(req)
+	timeSlice, err := r.server.remoteChanDB.ForwardingLog().QueryFailed(
+		eventQuery,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("unable to query forwarding log: %v", err)
+		return nil, fmt.Errorf("unable to query failed forwarding "+
+			"log: %v", err)
 	}
 
-	// TODO(roasbeef): add settlement latency?
-	//  * use FPE on all records?
+	resp := &lnrpc.FailedForwardingHistoryResponse{
+		FailedForwardingEvents: make(
+			[]*lnrpc.FailedForwardingEvent,
+			len(timeSlice.FailedForwardingEvents),
+		),
+		LastOffsetIndex: timeSlice.LastIndexOffset,
+	}
+	for i, event := range timeSlice.FailedForwardingEvents {
+		rpcEvent := &lnrpc.FailedForwardingEvent{
+			TimestampNs:   uint64(event.Timestamp.UnixNano()),
+			ChanIdIn:      event.IncomingChanID.ToUint64(),
+			ChanIdOut:     event.OutgoingChanID.ToUint64(),
+			AmtInMsat:     uint64(event.AmtIn),
+			AmtOutMsat:    uint64(event.AmtOut),
+			Downstream:    event.Downstream,
+			FailureDetail: event.FailureDetail,
+		}
 
-	// With the events retrieved, we'll now map them into the proper proto
-	// response.
-	//
-	// TODO(roasbeef): show in ns for the outside?
-	resp := &lnrpc.ForwardingHistoryResponse{
-		ForwardingEvents: make([]*lnrpc.ForwardingEvent, len(timeSlice.ForwardingEvents)),
-		LastOffsetIndex:  timeSlice.LastIndexOffset,
+		if event.FailureCode != 0 {
+			rpcEvent.WireFailureCode = uint32(event.FailureCode)
+			rpcEvent.Failure = event.FailureCode.String()
+		}
+
+		resp.FailedForwardingEvents[i] = rpcEvent
 	}
-	for i, event := range timeSlice.ForwardingEvents {
-		amtInMsat := event.AmtIn
-		amtOutMsat := event.AmtOut
-		feeMsat := event.AmtIn - event.AmtOut
 
-		resp.ForwardingEvents[i] = &lnrpc.ForwardingEvent{
-			Timestamp:   uint64(event.Timestamp.Unix()),
-			TimestampNs: uint64(event.Timestamp.UnixNano()),
-			ChanIdIn:    event.IncomingChanID.ToUint64(),
-			ChanIdOut:   event.OutgoingChanID.ToUint64(),
-			AmtIn:       uint64(amtInMsat.ToSatoshis()),
-			AmtOut:      uint64(amtOutMsat.ToSatoshis()),
-			Fee:         uint64(feeMsat.ToSatoshis()),
-			FeeMsat:     uint64(feeMsat),
-			AmtInMsat:   uint64(amtInMsat),
-			AmtOutMsat:  uint64(amtOutMsat),
+	return resp, nil
+}
+
+// ForwardingAggregates returns the forwarding events of a time range summed up
+// by incoming and outgoing channel, peer and time bucket.
+func (r *rpcServer) ForwardingAggregates(ctx context.Context,
+	req *lnrpc.ForwardingAggregatesRequest) (
+	*lnrpc.ForwardingAggregatesResponse, error) {
+
+	rpcsLog.Debugf("[forwardingaggregates]")
+
+	// Flush any pending events to disk first, so that they are included
+	// in the aggregates.
+	if err := r.server.htlcSwitch.FlushForwardingEvents(); err != nil {
+		return nil, fmt.Errorf("unable to flush forwarding "+
+			"events: %v", err)
+	}
+
+	query := channeldb.ForwardingAggregateQuery{
+		StartTime: time.Unix(int64(req.StartTime), 0),
+		EndTime:   time.Now(),
+	}
+	if req.EndTime != 0 {
+		query.EndTime = time.Unix(int64(req.EndTime), 0)
+	}
+
+	switch req.TimeBucket {
+	// Without time buckets, the aggregates span the whole time range.
+	case lnrpc.ForwardingTimeBucket_NO_TIME_BUCKET:
+
+	case lnrpc.ForwardingTimeBucket_HOUR:
+		query.Interval = time.Hour
+
+	case lnrpc.ForwardingTimeBucket_DAY:
+		query.Interval = 24 * time.Hour
+
+	default:
+		return nil, fmt.Errorf("unknown time bucket %v",
+			req.TimeBucket)
+	}
+
+	var groupChanIn, groupChanOut, groupPeerIn, groupPeerOut bool
+	for _, groupBy := range req.GroupBy {
+		switch groupBy {
+		case lnrpc.ForwardingGroupBy_INCOMING_CHANNEL:
+			groupChanIn = true
+
+		case lnrpc.ForwardingGroupBy_OUTGOING_CHANNEL:
+			groupChanOut = true
+
+		case lnrpc.ForwardingGroupBy_INCOMING_PEER:
+			groupPeerIn = true
+
+		case lnrpc.ForwardingGroupBy_OUTGOING_PEER:
+			groupPeerOut = true
+
+		default:
+			return nil, fmt.Errorf("unknown group by %v", groupBy)
+		}
+	}
+
+	aggregates, err := r.server.remoteChanDB.ForwardingLog().
+		QueryAggregates(query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query forwarding "+
+			"aggregates: %v", err)
+	}
+
+	// The peers of the channels are only needed if the aggregates are
+	// grouped by any of them.
+	var chanPeers map[lnwire.ShortChannelID]string
+	if len(req.GroupBy) > 0 {
+		chanPeers, err = r.fetchChannelPeers()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// forwardingGroup identifies the rpc aggregate that an aggregate of
+	// the forwarding log is added to.
+	type forwardingGroup struct {
+		bucketStart uint64
+		chanIn      uint64
+		chanOut     uint64
+		peerIn      string
+		peerOut     string
+	}
+
+	resp := &lnrpc.ForwardingAggregatesResponse{}
+	groups := make(map[forwardingGroup]*lnrpc.ForwardingAggregate)
+	for _, aggregate := range aggregates {
+		var group forwardingGroup
+		if query.Interval != 0 {
+			group.bucketStart = uint64(aggregate.BucketStart.Unix())
+		}
+		if groupChanIn {
+			group.chanIn = aggregate.IncomingChanID.ToUint64()
+		}
+		if groupChanOut {
+			group.chanOut = aggregate.OutgoingChanID.ToUint64()
+		}
+		if groupChanIn || groupPeerIn {
+			group.peerIn = chanPeers[aggregate.IncomingChanID]
 		}
+		if groupChanOut || groupPeerOut {
+			group.peerOut = chanPeers[aggregate.OutgoingChanID]
+		}
+
+		rpcAggregate, ok := groups[group]
+		if !ok {
+			rpcAggregate = &lnrpc.ForwardingAggregate{
+				BucketStart: group.bucketStart,
+				ChanIdIn:    group.chanIn,
+				ChanIdOut:   group.chanOut,
+				PeerIn:      group.peerIn,
+				PeerOut:     group.peerOut,
+			}
+			groups[group] = rpcAggregate
+			resp.Aggregates = append(resp.Aggregates, rpcAggregate)
+		}
+
+		rpcAggregate.Count += aggregate.Count
+		rpcAggregate.AmtInMsat += uint64(aggregate.AmtIn)
+		rpcAggregate.AmtOutMsat += uint64(aggregate.AmtOut)
+		rpcAggregate.FeeMsat += uint64(aggregate.Fee())
 	}
 
+	sort.Slice(resp.Aggregates, func(i, j int) bool {
+		a, b := resp.Aggregates[i], resp.Aggregates[j]
+
+		switch {
+		case a.BucketStart != b.BucketStart:
+			return a.BucketStart < b.BucketStart
+
+		case a.ChanIdIn != b.ChanIdIn:
+			return a.ChanIdIn < b.ChanIdIn
+
+		case a.ChanIdOut != b.ChanIdOut:
+			return a.ChanIdOut < b.ChanIdOut
+
+		case a.PeerIn != b.PeerIn:
+			return a.PeerIn < b.PeerIn
+
+		default:
+			return a.PeerOut < b.PeerOut
+		}
+	})
+
 	return resp, nil
 }
 
+// fetchChannelPeers returns the hex encoded public keys of the peers of all
+// open and closed channels, indexed by short channel ID.
+func (r *rpcServer) fetchChannelPeers() (map[lnwire.ShortChannelID]string,
+	error) {
+
+	chanPeers := make(map[lnwire.ShortChannelID]string)
+
+	openChannels, err := r.server.remoteChanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range openChannels {
+		chanPeers[channel.ShortChanID()] = hex.EncodeToString(
+			channel.IdentityPub.SerializeCompressed(),
+		)
+	}
+
+	closedChannels, err := r.server.remoteChanDB.FetchClosedChannels(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range closedChannels {
+		chanPeers[channel.ShortChanID] = hex.EncodeToString(
+			channel.RemotePub.SerializeCompressed(),
+		)
+	}
+
+	return chanPeers, nil
+}
+
 // ExportChannelBackup attempts to return an encrypted static channel backup
 // for the target channel identified by it channel point. The backup is
 // encrypted with a key generated from the aezeed seed of the user. The
@@ -6516,6 +7717,60 @@ func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription
 	}
 }
 
+// dbSnapshotChunkSize is the maximum number of bytes we send in a single
+// chunk of a database snapshot stream.
+const dbSnapshotChunkSize = 1 << 20
+
+// snapshotStreamWriter is an io.Writer that sends everything written to it as
+// a chunk of a database snapshot stream.
+type snapshotStreamWriter struct {
+	stream lnrpc.Lightning_ExportDatabaseSnapshotServer
+}
+
+// Write sends p as a single snapshot chunk.
+//
+// NOTE: This is part of the io.Writer interface.
+func (w *snapshotStreamWriter) Write(p []byte) (int, error) {
+	// The caller is allowed to re-use p after we return, so we need to
+	// copy it before handing it to the stream.
+	chunk := &lnrpc.DatabaseSnapshotChunk{
+		Data: append([]byte(nil), p...),
+	}
+	if err := w.stream.Send(chunk); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// ExportDatabaseSnapshot streams a consistent, backend-neutral snapshot of the
+// channel database while lnd is running. The whole snapshot is created within
+// a single read transaction so it reflects the state of the database at a
+// single point in time.
+//
+// NOTE: Only the channel database is exported. The wallet, macaroon and
+// watchtower databases are separate databases and need to be backed up
+// separately.
+func (r *rpcServer) ExportDatabaseSnapshot(req *lnrpc.DatabaseSnapshotRequest,
+	stream lnrpc.Lightning_ExportDatabaseSnapshotServer) error {
+
+	db := r.server.localChanDB
+	if req.Remote {
+		db = r.server.remoteChanDB
+	}
+
+	rpcsLog.Infof("[exportdbsnapshot] remote=%v", req.Remote)
+
+	w := bufio.NewWriterSize(
+		&snapshotStreamWriter{stream: stream}, dbSnapshotChunkSize,
+	)
+	if err := kvdb.DumpBackend(db, w); err != nil {
+		return fmt.Errorf("unable to create database snapshot: %v", err)
+	}
+
+	return w.Flush()
+}
+
 // ChannelAcceptor dispatches a bi-directional streaming RPC in which
 // OpenChannel requests are sent to the client and the client responds with
 // a boolean that tells LND whether or not to accept the channel. This allows
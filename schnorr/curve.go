@@ -0,0 +1,163 @@
+package schnorr
+
+import (
+	"crypto/subtle"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// projectivePoint is a point on the secp256k1 curve in homogeneous projective
+// coordinates, where the affine point is (x/z, y/z). All coordinates are kept
+// normalized. The point at infinity is (0, 1, 0).
+type projectivePoint struct {
+	x, y, z secp.FieldVal
+}
+
+// fieldMul returns the normalized product a*b.
+func fieldMul(a, b *secp.FieldVal) secp.FieldVal {
+	var r secp.FieldVal
+	r.Mul2(a, b).Normalize()
+
+	return r
+}
+
+// fieldAdd returns the normalized sum a+b.
+func fieldAdd(a, b *secp.FieldVal) secp.FieldVal {
+	var r secp.FieldVal
+	r.Set(a).Add(b).Normalize()
+
+	return r
+}
+
+// fieldSub returns the normalized difference a-b.
+func fieldSub(a, b *secp.FieldVal) secp.FieldVal {
+	var r secp.FieldVal
+	r.NegateVal(b, 1).Add(a).Normalize()
+
+	return r
+}
+
+// fieldMulB3 returns the normalized product 3*b*a, where b = 7 is the curve
+// constant.
+func fieldMulB3(a *secp.FieldVal) secp.FieldVal {
+	var r secp.FieldVal
+	r.Set(a).MulInt(21).Normalize()
+
+	return r
+}
+
+// addProjective returns p1 + p2 using the complete addition formula for short
+// Weierstrass curves with a = 0 from "Complete addition formulas for prime
+// order elliptic curves" (Renes, Costello, Batina), algorithm 7. The formula
+// has no exceptional cases, so it also doubles points and handles the point
+// at infinity without branching, which makes it run in constant time.
+func addProjective(p1, p2 *projectivePoint) projectivePoint {
+	t0 := fieldMul(&p1.x, &p2.x)
+	t1 := fieldMul(&p1.y, &p2.y)
+	t2 := fieldMul(&p1.z, &p2.z)
+
+	t3 := fieldAdd(&p1.x, &p1.y)
+	t4 := fieldAdd(&p2.x, &p2.y)
+	t3 = fieldMul(&t3, &t4)
+	t4 = fieldAdd(&t0, &t1)
+	t3 = fieldSub(&t3, &t4)
+
+	t4 = fieldAdd(&p1.y, &p1.z)
+	x3 := fieldAdd(&p2.y, &p2.z)
+	t4 = fieldMul(&t4, &x3)
+	x3 = fieldAdd(&t1, &t2)
+	t4 = fieldSub(&t4, &x3)
+
+	x3 = fieldAdd(&p1.x, &p1.z)
+	y3 := fieldAdd(&p2.x, &p2.z)
+	x3 = fieldMul(&x3, &y3)
+	y3 = fieldAdd(&t0, &t2)
+	y3 = fieldSub(&x3, &y3)
+
+	x3 = fieldAdd(&t0, &t0)
+	t0 = fieldAdd(&x3, &t0)
+	t2 = fieldMulB3(&t2)
+	z3 := fieldAdd(&t1, &t2)
+	t1 = fieldSub(&t1, &t2)
+	y3 = fieldMulB3(&y3)
+
+	x3 = fieldMul(&t4, &y3)
+	t2 = fieldMul(&t3, &t1)
+	x3 = fieldSub(&t2, &x3)
+	y3 = fieldMul(&y3, &t0)
+	t1 = fieldMul(&t1, &z3)
+	y3 = fieldAdd(&t1, &y3)
+	t0 = fieldMul(&t0, &t3)
+	z3 = fieldMul(&z3, &t4)
+	z3 = fieldAdd(&z3, &t0)
+
+	return projectivePoint{x: x3, y: y3, z: z3}
+}
+
+// selectPoint sets p to the entry of the table at the given index. Every
+// entry is read, so the memory access pattern doesn't depend on the index.
+func selectPoint(p *projectivePoint, table []projectivePoint, index byte) {
+	var x, y, z [32]byte
+	for i := range table {
+		match := subtle.ConstantTimeByteEq(byte(i), index)
+		subtle.ConstantTimeCopy(match, x[:], table[i].x.Bytes()[:])
+		subtle.ConstantTimeCopy(match, y[:], table[i].y.Bytes()[:])
+		subtle.ConstantTimeCopy(match, z[:], table[i].z.Bytes()[:])
+	}
+
+	p.x.SetBytes(&x)
+	p.y.SetBytes(&y)
+	p.z.SetBytes(&z)
+}
+
+// scalarBaseMult computes k*G in constant time and stores the result in
+// result. The secp256k1 package only offers a variable time base point
+// multiplication, which must not be used with secret scalars such as private
+// keys and nonces.
+func scalarBaseMult(k *secp.ModNScalar, result *secp.JacobianPoint) {
+	// The generator isn't secret, so it's fine to derive it with the
+	// variable time multiplication.
+	var one secp.ModNScalar
+	one.SetInt(1)
+
+	var g secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&one, &g)
+	g.ToAffine()
+
+	// Precompute i*G for all values of a 4 bit window.
+	var table [16]projectivePoint
+	table[0].y.SetInt(1)
+	table[1].x.Set(&g.X)
+	table[1].y.Set(&g.Y)
+	table[1].z.SetInt(1)
+	for i := 2; i < len(table); i++ {
+		table[i] = addProjective(&table[i-1], &table[1])
+	}
+
+	// Process the scalar one window at a time, starting with the most
+	// significant one.
+	var acc, entry projectivePoint
+	acc.y.SetInt(1)
+
+	kBytes := k.Bytes()
+	for _, b := range kBytes {
+		for _, window := range []byte{b >> 4, b & 0x0f} {
+			for i := 0; i < 4; i++ {
+				acc = addProjective(&acc, &acc)
+			}
+
+			selectPoint(&entry, table[:], window)
+			acc = addProjective(&acc, &entry)
+		}
+	}
+	for i := range kBytes {
+		kBytes[i] = 0
+	}
+
+	// Convert to jacobian coordinates, where the affine point is
+	// (x/z^2, y/z^3).
+	result.X = fieldMul(&acc.x, &acc.z)
+	zSquared := fieldMul(&acc.z, &acc.z)
+	result.Y = fieldMul(&acc.y, &zSquared)
+	result.Z.Set(&acc.z)
+}
@@ -0,0 +1,534 @@
+package schnorr
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	// PubNonceSize is the size of a MuSig2 public nonce, which consists
+	// of two compressed points.
+	PubNonceSize = 66
+
+	// PartialSigSize is the size of a MuSig2 partial signature.
+	PartialSigSize = 32
+)
+
+var (
+	// ErrNonceReused is returned when the secret nonces of a signer are
+	// used for a second signature, which would leak the private key.
+	ErrNonceReused = errors.New("musig2 nonces already used")
+
+	// ErrInvalidPartialSig is returned when a partial signature doesn't
+	// verify.
+	ErrInvalidPartialSig = errors.New("invalid partial signature")
+)
+
+// PubNonce is the public nonce of a MuSig2 signer. It must be sent to the
+// other signers before signing.
+type PubNonce [PubNonceSize]byte
+
+// PartialSig is a MuSig2 partial signature, which is combined with the
+// partial signatures of the other signers into a BIP-340 signature.
+type PartialSig [PartialSigSize]byte
+
+// isInfinity returns true if the point is the point at infinity.
+func isInfinity(p *secp.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+// parsePoint parses a compressed point into its jacobian representation.
+func parsePoint(b []byte) (secp.JacobianPoint, error) {
+	var p secp.JacobianPoint
+
+	pub, err := secp.ParsePubKey(b)
+	if err != nil {
+		return p, err
+	}
+	pub.AsJacobian(&p)
+
+	return p, nil
+}
+
+// serializePoint returns the compressed encoding of a point in affine
+// coordinates.
+func serializePoint(p *secp.JacobianPoint) []byte {
+	return secp.NewPublicKey(&p.X, &p.Y).SerializeCompressed()
+}
+
+// toPubKey converts a point in affine coordinates to a public key.
+func toPubKey(p *secp.JacobianPoint) *btcec.PublicKey {
+	pub, _ := btcec.ParsePubKey(serializePoint(p), btcec.S256())
+
+	return pub
+}
+
+// SortKeys returns the public keys sorted by their compressed encoding.
+func SortKeys(keys []*btcec.PublicKey) []*btcec.PublicKey {
+	sorted := make([]*btcec.PublicKey, len(keys))
+	copy(sorted, keys)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(
+			sorted[i].SerializeCompressed(),
+			sorted[j].SerializeCompressed(),
+		) < 0
+	})
+
+	return sorted
+}
+
+// KeyAggContext is the BIP-327 aggregate of the public keys of a set of
+// signers, including any tweaks applied to the aggregate key.
+type KeyAggContext struct {
+	// keys are the compressed public keys of the signers.
+	keys [][]byte
+
+	// keysHash is the hash of the list of keys, which is committed to
+	// by the key coefficients.
+	keysHash [32]byte
+
+	// secondKey is the first key that differs from the first key of the
+	// list. Its coefficient is one.
+	secondKey []byte
+
+	// q is the aggregate public key in affine coordinates.
+	q secp.JacobianPoint
+
+	// gacc and tacc accumulate the sign flips and the tweaks applied to
+	// the aggregate key.
+	gacc secp.ModNScalar
+	tacc secp.ModNScalar
+}
+
+// AggregateKeys aggregates the public keys of the signers. The keys are sorted
+// first, so the aggregate key doesn't depend on the order of the keys.
+func AggregateKeys(keys ...*btcec.PublicKey) (*KeyAggContext, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no keys to aggregate")
+	}
+
+	var serialized [][]byte
+	for _, key := range SortKeys(keys) {
+		serialized = append(serialized, key.SerializeCompressed())
+	}
+
+	return aggregateKeys(serialized)
+}
+
+// aggregateKeys runs the BIP-327 key aggregation on the compressed public
+// keys in the passed order.
+func aggregateKeys(keys [][]byte) (*KeyAggContext, error) {
+	ctx := &KeyAggContext{
+		keys: keys,
+	}
+
+	ctx.keysHash = TaggedHash("KeyAgg list", ctx.keys...)
+	for _, key := range ctx.keys[1:] {
+		if !bytes.Equal(key, ctx.keys[0]) {
+			ctx.secondKey = key
+			break
+		}
+	}
+
+	for _, key := range ctx.keys {
+		p, err := parsePoint(key)
+		if err != nil {
+			return nil, err
+		}
+
+		a := ctx.coefficient(key)
+
+		var aP secp.JacobianPoint
+		secp.ScalarMultNonConst(&a, &p, &aP)
+		secp.AddNonConst(&ctx.q, &aP, &ctx.q)
+	}
+	if isInfinity(&ctx.q) {
+		return nil, errors.New("aggregate key is infinite")
+	}
+	ctx.q.ToAffine()
+
+	ctx.gacc.SetInt(1)
+
+	return ctx, nil
+}
+
+// coefficient returns the key aggregation coefficient of the passed key.
+func (c *KeyAggContext) coefficient(key []byte) secp.ModNScalar {
+	var a secp.ModNScalar
+	if bytes.Equal(key, c.secondKey) {
+		a.SetInt(1)
+		return a
+	}
+
+	hash := TaggedHash("KeyAgg coefficient", c.keysHash[:], key)
+	a.SetBytes(&hash)
+
+	return a
+}
+
+// hasKey returns true if the passed key is one of the aggregated keys.
+func (c *KeyAggContext) hasKey(key []byte) bool {
+	for _, k := range c.keys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApplyTweak tweaks the aggregate key by adding tweak*G. An x-only tweak, as
+// used by taproot, is applied to the aggregate key with an even y coordinate.
+func (c *KeyAggContext) ApplyTweak(tweak [32]byte, xOnly bool) error {
+	var g secp.ModNScalar
+	g.SetInt(1)
+	if xOnly && c.q.Y.IsOdd() {
+		g.Negate()
+	}
+
+	var t secp.ModNScalar
+	if overflow := t.SetBytes(&tweak); overflow != 0 {
+		return errors.New("tweak exceeds the curve order")
+	}
+
+	var gQ, tG secp.JacobianPoint
+	secp.ScalarMultNonConst(&g, &c.q, &gQ)
+	secp.ScalarBaseMultNonConst(&t, &tG)
+	secp.AddNonConst(&gQ, &tG, &c.q)
+	if isInfinity(&c.q) {
+		return errors.New("tweaked key is infinite")
+	}
+	c.q.ToAffine()
+
+	c.gacc.Mul(&g)
+	c.tacc.Mul(&g).Add(&t)
+
+	return nil
+}
+
+// PubKey returns the aggregate public key, including all tweaks.
+func (c *KeyAggContext) PubKey() *btcec.PublicKey {
+	return toPubKey(&c.q)
+}
+
+// Nonces are the nonces of a MuSig2 signer for a single signature. The
+// public nonce is shared with the other signers, while the secret nonces are
+// erased once they've been used to sign.
+type Nonces struct {
+	// PubNonce is the public nonce that is sent to the other signers.
+	PubNonce PubNonce
+
+	// secNonce holds the two secret nonces.
+	secNonce [64]byte
+
+	// pubKey is the compressed public key of the signer.
+	pubKey []byte
+
+	// used is true once the secret nonces were used to sign.
+	used bool
+}
+
+// GenNonces generates fresh nonces for the signer with the passed public key.
+// The aggregate key is optional, but mixed into the nonces if known.
+func GenNonces(pub *btcec.PublicKey, aggKey *btcec.PublicKey) (*Nonces,
+	error) {
+
+	var random [32]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return nil, err
+	}
+
+	return genNonces(pub, aggKey, random)
+}
+
+// genNonces derives the nonces of the signer from the passed randomness as
+// specified by BIP-327. No message is committed to.
+func genNonces(pub *btcec.PublicKey, aggKey *btcec.PublicKey,
+	random [32]byte) (*Nonces, error) {
+
+	pubKey := pub.SerializeCompressed()
+
+	var aggPub []byte
+	if aggKey != nil {
+		aggPub = SerializePubKey(aggKey)
+	}
+
+	nonces := &Nonces{
+		pubKey: pubKey,
+	}
+	for i := 0; i < 2; i++ {
+		hash := TaggedHash(
+			"MuSig/nonce", random[:], []byte{byte(len(pubKey))},
+			pubKey, []byte{byte(len(aggPub))}, aggPub,
+			[]byte{0}, []byte{0, 0, 0, 0}, []byte{byte(i)},
+		)
+
+		var k secp.ModNScalar
+		k.SetBytes(&hash)
+		if k.IsZero() {
+			return nil, errors.New("invalid nonce")
+		}
+
+		var r secp.JacobianPoint
+		scalarBaseMult(&k, &r)
+		r.ToAffine()
+
+		k.PutBytesUnchecked(nonces.secNonce[i*32 : (i+1)*32])
+		copy(nonces.PubNonce[i*33:(i+1)*33], serializePoint(&r))
+		k.Zero()
+	}
+
+	return nonces, nil
+}
+
+// Session holds the values that all signers derive from the aggregate key,
+// their public nonces and the message in order to create and combine the
+// partial signatures.
+type Session struct {
+	ctx *KeyAggContext
+
+	// msg is the 32 byte message being signed.
+	msg [32]byte
+
+	// b is the nonce coefficient.
+	b secp.ModNScalar
+
+	// r is the final nonce point in affine coordinates.
+	r secp.JacobianPoint
+
+	// e is the BIP-340 challenge.
+	e secp.ModNScalar
+}
+
+// NewSession creates a signing session for the message, given the public
+// nonces of all signers.
+func NewSession(ctx *KeyAggContext, pubNonces []PubNonce,
+	msg [32]byte) (*Session, error) {
+
+	// Aggregate the nonces of the signers.
+	var aggNonce [2]secp.JacobianPoint
+	for _, nonce := range pubNonces {
+		for j := 0; j < 2; j++ {
+			r, err := parsePoint(nonce[j*33 : (j+1)*33])
+			if err != nil {
+				return nil, fmt.Errorf("invalid nonce: %v", err)
+			}
+
+			secp.AddNonConst(&aggNonce[j], &r, &aggNonce[j])
+		}
+	}
+
+	var aggNonceBytes [PubNonceSize]byte
+	for j := range aggNonce {
+		if isInfinity(&aggNonce[j]) {
+			continue
+		}
+
+		aggNonce[j].ToAffine()
+		copy(aggNonceBytes[j*33:], serializePoint(&aggNonce[j]))
+	}
+
+	s := &Session{
+		ctx: ctx,
+		msg: msg,
+	}
+
+	qBytes := SerializePubKey(ctx.PubKey())
+	bHash := TaggedHash(
+		"MuSig/noncecoef", aggNonceBytes[:], qBytes, msg[:],
+	)
+	s.b.SetBytes(&bHash)
+
+	// R = R1 + b*R2, or the generator if that is infinite.
+	var bR2 secp.JacobianPoint
+	secp.ScalarMultNonConst(&s.b, &aggNonce[1], &bR2)
+	secp.AddNonConst(&aggNonce[0], &bR2, &s.r)
+	if isInfinity(&s.r) {
+		var one secp.ModNScalar
+		one.SetInt(1)
+		secp.ScalarBaseMultNonConst(&one, &s.r)
+	}
+	s.r.ToAffine()
+
+	var rBytes [32]byte
+	s.r.X.PutBytesUnchecked(rBytes[:])
+	eHash := TaggedHash("BIP0340/challenge", rBytes[:], qBytes, msg[:])
+	s.e.SetBytes(&eHash)
+
+	return s, nil
+}
+
+// keyParity returns the scalar g, which is -1 if the aggregate key has an odd
+// y coordinate, and 1 otherwise.
+func (s *Session) keyParity() secp.ModNScalar {
+	var g secp.ModNScalar
+	g.SetInt(1)
+	if s.ctx.q.Y.IsOdd() {
+		g.Negate()
+	}
+
+	return g
+}
+
+// Sign creates the partial signature of the signer with the passed private
+// key. The secret nonces are erased, so they can't be used a second time.
+func (s *Session) Sign(nonces *Nonces,
+	priv *btcec.PrivateKey) (PartialSig, error) {
+
+	var sig PartialSig
+	if nonces.used {
+		return sig, ErrNonceReused
+	}
+
+	pubKey := priv.PubKey().SerializeCompressed()
+	if !bytes.Equal(pubKey, nonces.pubKey) {
+		return sig, errors.New("nonces belong to a different key")
+	}
+	if !s.ctx.hasKey(pubKey) {
+		return sig, errors.New("key is not part of the aggregate key")
+	}
+
+	// Mark the nonces as used before anything else, so that they are
+	// never used again, even if signing fails.
+	nonces.used = true
+	defer func() {
+		for i := range nonces.secNonce {
+			nonces.secNonce[i] = 0
+		}
+	}()
+
+	var k1, k2, d secp.ModNScalar
+	defer k1.Zero()
+	defer k2.Zero()
+	defer d.Zero()
+	if k1.SetByteSlice(nonces.secNonce[:32]) || k1.IsZero() ||
+		k2.SetByteSlice(nonces.secNonce[32:]) || k2.IsZero() {
+
+		return sig, errors.New("invalid secret nonce")
+	}
+	if d.SetByteSlice(priv.Serialize()) || d.IsZero() {
+		return sig, errors.New("invalid private key")
+	}
+
+	if s.r.Y.IsOdd() {
+		k1.Negate()
+		k2.Negate()
+	}
+
+	// d = g*gacc*d'
+	g := s.keyParity()
+	d.Mul(&g).Mul(&s.ctx.gacc)
+
+	// s = k1 + b*k2 + e*a*d
+	a := s.ctx.coefficient(pubKey)
+	var sum, bk2 secp.ModNScalar
+	sum.Mul2(&s.e, &a).Mul(&d)
+	bk2.Mul2(&s.b, &k2)
+	sum.Add(&bk2).Add(&k1)
+	sum.PutBytesUnchecked(sig[:])
+	sum.Zero()
+
+	return sig, nil
+}
+
+// VerifyPartialSig verifies the partial signature of the signer with the
+// passed public nonce and public key.
+func (s *Session) VerifyPartialSig(sig PartialSig, pubNonce PubNonce,
+	pub *btcec.PublicKey) error {
+
+	pubKey := pub.SerializeCompressed()
+	if !s.ctx.hasKey(pubKey) {
+		return errors.New("key is not part of the aggregate key")
+	}
+
+	var sigScalar secp.ModNScalar
+	if sigScalar.SetByteSlice(sig[:]) {
+		return ErrInvalidPartialSig
+	}
+
+	r1, err := parsePoint(pubNonce[:33])
+	if err != nil {
+		return err
+	}
+	r2, err := parsePoint(pubNonce[33:])
+	if err != nil {
+		return err
+	}
+
+	// Re = R1 + b*R2, negated if the final nonce has an odd y
+	// coordinate.
+	var bR2, re secp.JacobianPoint
+	secp.ScalarMultNonConst(&s.b, &r2, &bR2)
+	secp.AddNonConst(&r1, &bR2, &re)
+	if s.r.Y.IsOdd() {
+		re.ToAffine()
+		re.Y.Negate(1).Normalize()
+	}
+
+	p, err := parsePoint(pubKey)
+	if err != nil {
+		return err
+	}
+
+	// s*G == Re + e*a*g*gacc*P
+	g := s.keyParity()
+	a := s.ctx.coefficient(pubKey)
+	var scalar secp.ModNScalar
+	scalar.Mul2(&s.e, &a).Mul(&g).Mul(&s.ctx.gacc)
+
+	var sG, eP, want secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&sigScalar, &sG)
+	secp.ScalarMultNonConst(&scalar, &p, &eP)
+	secp.AddNonConst(&re, &eP, &want)
+
+	if isInfinity(&sG) || isInfinity(&want) {
+		return ErrInvalidPartialSig
+	}
+
+	sG.ToAffine()
+	want.ToAffine()
+	if !sG.X.Equals(&want.X) || !sG.Y.Equals(&want.Y) {
+		return ErrInvalidPartialSig
+	}
+
+	return nil
+}
+
+// CombineSigs combines the partial signatures of all signers into a BIP-340
+// signature that is valid under the aggregate key.
+func (s *Session) CombineSigs(sigs []PartialSig) (Signature, error) {
+	var sum secp.ModNScalar
+	for _, sig := range sigs {
+		var sigScalar secp.ModNScalar
+		if sigScalar.SetByteSlice(sig[:]) {
+			return Signature{}, ErrInvalidPartialSig
+		}
+
+		sum.Add(&sigScalar)
+	}
+
+	// s = sum + e*g*tacc
+	g := s.keyParity()
+	var et secp.ModNScalar
+	et.Mul2(&s.e, &g).Mul(&s.ctx.tacc)
+	sum.Add(&et)
+
+	var sig Signature
+	s.r.X.PutBytesUnchecked(sig[:32])
+	sum.PutBytesUnchecked(sig[32:])
+
+	var msg [32]byte
+	copy(msg[:], s.msg[:])
+	if err := Verify(s.ctx.PubKey(), msg, sig); err != nil {
+		return Signature{}, err
+	}
+
+	return sig, nil
+}
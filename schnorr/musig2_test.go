@@ -0,0 +1,200 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/require"
+)
+
+// musigSigner bundles the key and nonces of a signer in a test session.
+type musigSigner struct {
+	priv   *btcec.PrivateKey
+	nonces *Nonces
+}
+
+// newMuSigSession creates the signers and a signing session for the message,
+// optionally applying an x-only tweak to the aggregate key.
+func newMuSigSession(t *testing.T, numSigners int, tweak *[32]byte,
+	msg [32]byte) ([]*musigSigner, *Session) {
+
+	signers := make([]*musigSigner, numSigners)
+	keys := make([]*btcec.PublicKey, numSigners)
+	for i := range signers {
+		signers[i] = &musigSigner{priv: newTestKey(t)}
+		keys[i] = signers[i].priv.PubKey()
+	}
+
+	ctx, err := AggregateKeys(keys...)
+	require.NoError(t, err)
+
+	if tweak != nil {
+		require.NoError(t, ctx.ApplyTweak(*tweak, true))
+	}
+
+	pubNonces := make([]PubNonce, numSigners)
+	for i, signer := range signers {
+		signer.nonces, err = GenNonces(keys[i], ctx.PubKey())
+		require.NoError(t, err)
+
+		pubNonces[i] = signer.nonces.PubNonce
+	}
+
+	session, err := NewSession(ctx, pubNonces, msg)
+	require.NoError(t, err)
+
+	return signers, session
+}
+
+// TestMuSig2 tests that the combined partial signatures of all signers form a
+// valid BIP-340 signature under the aggregate key, with and without a tweak.
+func TestMuSig2(t *testing.T) {
+	t.Parallel()
+
+	msg := sha256.Sum256([]byte("musig2"))
+	tweak := sha256.Sum256([]byte("tweak"))
+
+	for _, numSigners := range []int{1, 2, 3} {
+		for _, tweak := range []*[32]byte{nil, &tweak} {
+			signers, session := newMuSigSession(
+				t, numSigners, tweak, msg,
+			)
+
+			var sigs []PartialSig
+			for _, signer := range signers {
+				sig, err := session.Sign(
+					signer.nonces, signer.priv,
+				)
+				require.NoError(t, err)
+
+				err = session.VerifyPartialSig(
+					sig, signer.nonces.PubNonce,
+					signer.priv.PubKey(),
+				)
+				require.NoError(t, err)
+
+				sigs = append(sigs, sig)
+			}
+
+			sig, err := session.CombineSigs(sigs)
+			require.NoError(t, err)
+			err = Verify(session.ctx.PubKey(), msg, sig)
+			require.NoError(t, err)
+		}
+	}
+}
+
+// TestMuSig2KeyOrder tests that the aggregate key doesn't depend on the order
+// of the keys.
+func TestMuSig2KeyOrder(t *testing.T) {
+	t.Parallel()
+
+	key1, key2 := newTestKey(t).PubKey(), newTestKey(t).PubKey()
+
+	ctx1, err := AggregateKeys(key1, key2)
+	require.NoError(t, err)
+	ctx2, err := AggregateKeys(key2, key1)
+	require.NoError(t, err)
+
+	require.True(t, ctx1.PubKey().IsEqual(ctx2.PubKey()))
+}
+
+// TestMuSig2Invalid tests that invalid partial signatures are rejected and
+// that nonces can't be used twice.
+func TestMuSig2Invalid(t *testing.T) {
+	t.Parallel()
+
+	msg := sha256.Sum256([]byte("musig2"))
+	signers, session := newMuSigSession(t, 2, nil, msg)
+
+	sig0, err := session.Sign(signers[0].nonces, signers[0].priv)
+	require.NoError(t, err)
+
+	// The nonces must not be usable a second time.
+	_, err = session.Sign(signers[0].nonces, signers[0].priv)
+	require.Equal(t, ErrNonceReused, err)
+
+	// A tampered signature, or one checked against the wrong signer, must
+	// be rejected.
+	tampered := sig0
+	tampered[31] ^= 1
+	err = session.VerifyPartialSig(
+		tampered, signers[0].nonces.PubNonce, signers[0].priv.PubKey(),
+	)
+	require.Equal(t, ErrInvalidPartialSig, err)
+
+	err = session.VerifyPartialSig(
+		sig0, signers[1].nonces.PubNonce, signers[1].priv.PubKey(),
+	)
+	require.Equal(t, ErrInvalidPartialSig, err)
+
+	// A key that isn't part of the aggregate key can't sign.
+	outsider := newTestKey(t)
+	nonces, err := GenNonces(outsider.PubKey(), nil)
+	require.NoError(t, err)
+	_, err = session.Sign(nonces, outsider)
+	require.Error(t, err)
+
+	// Combining with a tampered partial signature doesn't produce a valid
+	// signature.
+	sig1, err := session.Sign(signers[1].nonces, signers[1].priv)
+	require.NoError(t, err)
+	_, err = session.CombineSigs([]PartialSig{tampered, sig1})
+	require.Error(t, err)
+}
+
+// TestKeyAggVectors tests the key aggregation against the BIP-327 test
+// vectors.
+func TestKeyAggVectors(t *testing.T) {
+	t.Parallel()
+
+	pubKeys := []string{
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113" +
+			"BCE036F9",
+		"03DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B" +
+			"502BA659",
+		"023590A94E768F8E1815C2F24B4D80A8E3149316C3518CE7B7AD338368" +
+			"D038CA66",
+	}
+
+	vectors := []struct {
+		indices []int
+		aggKey  string
+	}{{
+		indices: []int{0, 1, 2},
+		aggKey: "90539EEDE565F5D054F32CC0C220126889ED1E5D193BAF15AEF3" +
+			"44FE59D4610C",
+	}, {
+		indices: []int{2, 1, 0},
+		aggKey: "6204DE8B083426DC6EAF9502D27024D53FC826BF7D2012148A05" +
+			"75435DF54B2B",
+	}, {
+		indices: []int{0, 0, 0},
+		aggKey: "B436E3BAD62B8CD409969A224731C193D051162D8C5AE8B10930" +
+			"6127DA3AA935",
+	}, {
+		indices: []int{0, 0, 1, 1},
+		aggKey: "69BC22BFA5D106306E48A20679DE1D7389386124D07571D0D872" +
+			"686028C26A3E",
+	}}
+
+	for _, v := range vectors {
+		var keys [][]byte
+		for _, i := range v.indices {
+			key, err := hex.DecodeString(pubKeys[i])
+			require.NoError(t, err)
+
+			keys = append(keys, key)
+		}
+
+		ctx, err := aggregateKeys(keys)
+		require.NoError(t, err)
+		require.Equal(
+			t, strings.ToLower(v.aggKey),
+			hex.EncodeToString(SerializePubKey(ctx.PubKey())),
+		)
+	}
+}
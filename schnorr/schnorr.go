@@ -0,0 +1,231 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	// SignatureSize is the size of a BIP-340 schnorr signature.
+	SignatureSize = 64
+
+	// PubKeySize is the size of a BIP-340 public key, which only consists
+	// of the x coordinate of the point.
+	PubKeySize = 32
+)
+
+var (
+	// ErrInvalidSignature is returned when a schnorr signature doesn't
+	// verify.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Signature is a BIP-340 schnorr signature.
+type Signature [SignatureSize]byte
+
+// ParseSignature parses a BIP-340 schnorr signature.
+func ParseSignature(sig []byte) (Signature, error) {
+	var s Signature
+	if len(sig) != SignatureSize {
+		return s, fmt.Errorf("invalid schnorr signature length %d",
+			len(sig))
+	}
+	copy(s[:], sig)
+
+	return s, nil
+}
+
+// Serialize returns the 64 byte encoding of the signature.
+func (s Signature) Serialize() []byte {
+	sig := make([]byte, SignatureSize)
+	copy(sig, s[:])
+
+	return sig
+}
+
+// Verify returns true if the signature is valid for the passed 32 byte digest
+// under the public key. Only the x coordinate of the public key is used.
+func (s Signature) Verify(digest []byte, pub *btcec.PublicKey) bool {
+	if len(digest) != sha256.Size {
+		return false
+	}
+
+	var d [32]byte
+	copy(d[:], digest)
+
+	return Verify(pub, d, s) == nil
+}
+
+// TaggedHash computes the BIP-340 tagged hash of the concatenated messages:
+//
+//  SHA256(SHA256(tag) || SHA256(tag) || msg)
+func TaggedHash(tag string, msgs ...[]byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, msg := range msgs {
+		h.Write(msg)
+	}
+
+	var hash [32]byte
+	copy(hash[:], h.Sum(nil))
+
+	return hash
+}
+
+// SerializePubKey returns the 32 byte BIP-340 encoding of the public key,
+// which is its x coordinate.
+func SerializePubKey(pub *btcec.PublicKey) []byte {
+	return pub.SerializeCompressed()[1:]
+}
+
+// ParsePubKey parses a 32 byte BIP-340 public key. The returned key is the
+// point with the passed x coordinate and an even y coordinate.
+func ParsePubKey(pubKey []byte) (*btcec.PublicKey, error) {
+	if len(pubKey) != PubKeySize {
+		return nil, fmt.Errorf("invalid schnorr public key length %d",
+			len(pubKey))
+	}
+
+	return btcec.ParsePubKey(
+		append([]byte{secp.PubKeyFormatCompressedEven}, pubKey...),
+		btcec.S256(),
+	)
+}
+
+// Sign creates a BIP-340 schnorr signature of the 32 byte digest with the
+// passed private key. Fresh auxiliary randomness is used for every signature.
+func Sign(priv *btcec.PrivateKey, digest [32]byte) (Signature, error) {
+	var aux [32]byte
+	if _, err := rand.Read(aux[:]); err != nil {
+		return Signature{}, err
+	}
+
+	return signWithAux(priv, digest, aux)
+}
+
+// signWithAux creates a BIP-340 schnorr signature using the passed auxiliary
+// randomness. The private key and the nonce are only handled as constant time
+// scalars and multiplied with the generator in constant time, so that the time
+// it takes to sign doesn't leak information about them.
+func signWithAux(priv *btcec.PrivateKey, digest, aux [32]byte) (Signature,
+	error) {
+
+	var sig Signature
+
+	var d secp.ModNScalar
+	if overflow := d.SetByteSlice(priv.Serialize()); overflow ||
+		d.IsZero() {
+
+		return sig, errors.New("invalid private key")
+	}
+	defer d.Zero()
+
+	// The secret key is negated if its public key has an odd y
+	// coordinate, as only the x coordinate is used as public key.
+	var p secp.JacobianPoint
+	scalarBaseMult(&d, &p)
+	p.ToAffine()
+	if p.Y.IsOdd() {
+		d.Negate()
+	}
+
+	var pubBytes [32]byte
+	p.X.PutBytesUnchecked(pubBytes[:])
+
+	// Derive the nonce from the secret key masked with the auxiliary
+	// randomness, the public key and the message.
+	t := d.Bytes()
+	auxHash := TaggedHash("BIP0340/aux", aux[:])
+	for i := range t {
+		t[i] ^= auxHash[i]
+	}
+	nonceHash := TaggedHash("BIP0340/nonce", t[:], pubBytes[:], digest[:])
+
+	var k secp.ModNScalar
+	k.SetBytes(&nonceHash)
+	defer k.Zero()
+	if k.IsZero() {
+		return sig, errors.New("invalid nonce")
+	}
+
+	var r secp.JacobianPoint
+	scalarBaseMult(&k, &r)
+	r.ToAffine()
+	if r.Y.IsOdd() {
+		k.Negate()
+	}
+	r.X.PutBytesUnchecked(sig[:32])
+
+	challenge := TaggedHash(
+		"BIP0340/challenge", sig[:32], pubBytes[:], digest[:],
+	)
+	var e secp.ModNScalar
+	e.SetBytes(&challenge)
+
+	// s = k + e*d
+	s := new(secp.ModNScalar).Mul2(&e, &d).Add(&k)
+	s.PutBytesUnchecked(sig[32:])
+
+	if err := Verify(priv.PubKey(), digest, sig); err != nil {
+		return sig, err
+	}
+
+	return sig, nil
+}
+
+// Verify verifies a BIP-340 schnorr signature of the digest. Only the x
+// coordinate of the public key is used.
+func Verify(pub *btcec.PublicKey, digest [32]byte, sig Signature) error {
+	// Lift the x coordinate of the public key to the point with an even y
+	// coordinate.
+	pubBytes := SerializePubKey(pub)
+	pubKey, err := secp.ParsePubKey(
+		append([]byte{secp.PubKeyFormatCompressedEven}, pubBytes...),
+	)
+	if err != nil {
+		return err
+	}
+
+	var p secp.JacobianPoint
+	pubKey.AsJacobian(&p)
+
+	var (
+		r secp.FieldVal
+		s secp.ModNScalar
+	)
+	if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) {
+		return ErrInvalidSignature
+	}
+
+	challenge := TaggedHash(
+		"BIP0340/challenge", sig[:32], pubBytes, digest[:],
+	)
+	var e secp.ModNScalar
+	e.SetBytes(&challenge)
+
+	// R = s*G - e*P. Only public values are involved, so the variable
+	// time operations can be used.
+	var sG, eP, rPoint secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&s, &sG)
+	secp.ScalarMultNonConst(e.Negate(), &p, &eP)
+	secp.AddNonConst(&sG, &eP, &rPoint)
+
+	if (rPoint.X.IsZero() && rPoint.Y.IsZero()) || rPoint.Z.IsZero() {
+		return ErrInvalidSignature
+	}
+
+	rPoint.ToAffine()
+	if rPoint.Y.IsOdd() || !rPoint.X.Equals(&r) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
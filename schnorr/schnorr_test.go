@@ -0,0 +1,117 @@
+package schnorr
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKey returns a fresh private key.
+func newTestKey(t *testing.T) *btcec.PrivateKey {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	return priv
+}
+
+// TestSchnorrVectors tests our schnorr signatures against the BIP-340 test
+// vectors.
+func TestSchnorrVectors(t *testing.T) {
+	t.Parallel()
+
+	vectors := []struct {
+		secKey string
+		pubKey string
+		aux    string
+		msg    string
+		sig    string
+	}{{
+		secKey: "00000000000000000000000000000000000000000000000000" +
+			"00000000000003",
+		pubKey: "F9308A019258C31049344F85F89D5229B531C845836F99B086" +
+			"01F113BCE036F9",
+		aux: "0000000000000000000000000000000000000000000000000000" +
+			"000000000000",
+		msg: "0000000000000000000000000000000000000000000000000000" +
+			"000000000000",
+		sig: "E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55" +
+			"F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FD" +
+			"B2172F477DF4900D310536C0",
+	}, {
+		secKey: "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A7" +
+			"84D9045190CFEF",
+		pubKey: "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843" +
+			"240F7B502BA659",
+		aux: "0000000000000000000000000000000000000000000000000000" +
+			"000000000001",
+		msg: "243F6A8885A308D313198A2E03707344A4093822299F31D0082E" +
+			"FA98EC4E6C89",
+		sig: "6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8D" +
+			"CF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB6" +
+			"39EA871CFA95F6DE339E4B0A",
+	}}
+
+	decode32 := func(s string) [32]byte {
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err)
+
+		var r [32]byte
+		copy(r[:], b)
+		return r
+	}
+
+	for _, v := range vectors {
+		secKey := decode32(v.secKey)
+		priv, pub := btcec.PrivKeyFromBytes(btcec.S256(), secKey[:])
+
+		pubX := decode32(v.pubKey)
+		require.Equal(t, pubX[:], pub.SerializeCompressed()[1:])
+
+		sig, err := signWithAux(
+			priv, decode32(v.msg), decode32(v.aux),
+		)
+		require.NoError(t, err)
+		require.Equal(
+			t, strings.ToLower(v.sig), hex.EncodeToString(sig[:]),
+		)
+
+		require.NoError(t, Verify(pub, decode32(v.msg), sig))
+
+		sig[0] ^= 1
+		require.Error(t, Verify(pub, decode32(v.msg), sig))
+	}
+}
+
+// TestScalarBaseMult tests that the constant time base point multiplication
+// matches the variable time one of the secp256k1 package.
+func TestScalarBaseMult(t *testing.T) {
+	scalars := []*secp.ModNScalar{
+		new(secp.ModNScalar).SetInt(1),
+		new(secp.ModNScalar).SetInt(2),
+		new(secp.ModNScalar).SetInt(15),
+		new(secp.ModNScalar).SetInt(16),
+		new(secp.ModNScalar).SetInt(1).Negate(),
+	}
+	for i := 0; i < 20; i++ {
+		priv := newTestKey(t)
+
+		var k secp.ModNScalar
+		k.SetByteSlice(priv.Serialize())
+		scalars = append(scalars, &k)
+	}
+
+	for _, k := range scalars {
+		var want, got secp.JacobianPoint
+		secp.ScalarBaseMultNonConst(k, &want)
+		scalarBaseMult(k, &got)
+
+		want.ToAffine()
+		got.ToAffine()
+		require.True(t, want.X.Equals(&got.X))
+		require.True(t, want.Y.Equals(&got.Y))
+	}
+}
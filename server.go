@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"image/color"
@@ -24,7 +25,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/go-errors/errors"
-	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/aliasmgr"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/brontide"
 	"github.com/lightningnetwork/lnd/cert"
@@ -49,19 +50,24 @@ import (
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/nat"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/offers"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 	"github.com/lightningnetwork/lnd/routing/localchans"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/schnorr"
 	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/lightningnetwork/lnd/ticker"
@@ -226,6 +232,10 @@ type server struct {
 
 	htlcSwitch *htlcswitch.Switch
 
+	// aliasMgr allocates and stores the aliases of channels that use one,
+	// like zero-conf channels.
+	aliasMgr *aliasmgr.Manager
+
 	interceptableSwitch *htlcswitch.InterceptableSwitch
 
 	invoices *invoices.InvoiceRegistry
@@ -236,6 +246,14 @@ type server struct {
 
 	htlcNotifier *htlcswitch.HtlcNotifier
 
+	// customMessageServer dispatches custom messages received from peers
+	// to all subscribed clients.
+	customMessageServer *subscribe.Server
+
+	// onionMessageServer dispatches onion messages addressed to us to all
+	// subscribed clients.
+	onionMessageServer *subscribe.Server
+
 	witnessBeacon contractcourt.WitnessBeacon
 
 	breachArbiter *breachArbiter
@@ -258,10 +276,22 @@ type server struct {
 
 	sphinx *hop.OnionProcessor
 
+	onionMessageProcessor *hop.OnionMessageProcessor
+
+	// offerManager creates offers, responds to the invoice requests for
+	// them and fetches invoices for the offers we pay.
+	offerManager *offers.Manager
+
+	// trampolineRelay relays the trampoline payments that are delegated to
+	// us. It is nil if trampoline relay is disabled.
+	trampolineRelay *htlcswitch.TrampolineRelay
+
 	towerClient wtclient.Client
 
 	anchorTowerClient wtclient.Client
 
+	taprootTowerClient wtclient.Client
+
 	connMgr *connmgr.ConnManager
 
 	sigPool *lnwallet.SigPool
@@ -387,13 +417,9 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// the same directory as the channel graph database. We don't need to
 	// replicate this data, so we'll store it locally.
 	replayLog := htlcswitch.NewDecayedLog(
-		cfg.localDatabaseDir(), defaultSphinxDbName, // no bolt in js - cfg.DB.Bolt,
+		cfg.localDatabaseDir(), defaultSphinxDbName, cfg.DB.LevelDB,
 		cc.ChainNotifier,
 	)
-	sphinxRouter := sphinx.NewRouter(
-		nodeKeyECDH, cfg.ActiveNetParams.Params, replayLog,
-	)
-
 	writeBufferPool := pool.NewWriteBuffer(
 		pool.DefaultWriteBufferGCInterval,
 		pool.DefaultWriteBufferExpiryInterval,
@@ -417,6 +443,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NoStaticRemoteKey: cfg.ProtocolOptions.NoStaticRemoteKey(),
 		NoAnchors:         cfg.ProtocolOptions.NoAnchorCommitments(),
 		NoWumbo:           !cfg.ProtocolOptions.Wumbo(),
+		NoScidAlias:       !cfg.ProtocolOptions.ScidAlias(),
+		NoZeroConf:        !cfg.ProtocolOptions.ZeroConf(),
+		NoDualFund:        !cfg.ProtocolOptions.DualFund(),
+		NoSplice:          !cfg.ProtocolOptions.Splice(),
+		NoTaprootChans:    !cfg.ProtocolOptions.TaprootChans(),
+		NoTrampoline:      !cfg.Trampoline.Relay,
 	})
 	if err != nil {
 		return nil, err
@@ -430,6 +462,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		AcceptAMP:                   cfg.AcceptAMP,
 		GcCanceledInvoicesOnStartup: cfg.GcCanceledInvoicesOnStartup,
 		GcCanceledInvoicesOnTheFly:  cfg.GcCanceledInvoicesOnTheFly,
+		GcCanceledInvoicesAfter:     cfg.GcCanceledInvoicesAfter,
 		KeysendHoldTime:             cfg.KeysendHoldTime,
 	}
 
@@ -452,7 +485,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 		// TODO(roasbeef): derive proper onion key based on rotation
 		// schedule
-		sphinx: hop.NewOnionProcessor(sphinxRouter),
+		sphinx: hop.NewOnionProcessor(
+			nodeKeyECDH, cfg.ActiveNetParams.Params, replayLog,
+		),
+		onionMessageProcessor: hop.NewOnionMessageProcessor(
+			nodeKeyECDH, cfg.ActiveNetParams.Params,
+		),
 
 		torController: torController,
 
@@ -494,6 +532,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	s.htlcNotifier = htlcswitch.NewHtlcNotifier(time.Now)
 
+	s.aliasMgr, err = aliasmgr.NewManager(remoteChanDB)
+	if err != nil {
+		return nil, err
+	}
+
 	s.htlcSwitch, err = htlcswitch.New(htlcswitch.Config{
 		DB: remoteChanDB,
 		LocalChannelClose: func(pubKey []byte,
@@ -511,6 +554,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			peer.HandleLocalCloseChanReqs(request)
 		},
 		FwdingLog:              remoteChanDB.ForwardingLog(),
+		FailedFwdRetention:     cfg.FailedFwdRetention,
 		SwitchPackager:         channeldb.NewSwitchPackager(),
 		ExtractErrorEncrypter:  s.sphinx.ExtractErrorEncrypter,
 		FetchLastChannelUpdate: s.fetchLastChanUpdate(),
@@ -523,11 +567,19 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RejectHTLC:             cfg.RejectHTLC,
 		Clock:                  clock.NewDefaultClock(),
 		HTLCExpiry:             htlcswitch.DefaultHTLCExpiry,
+		FindBaseSCID:           s.aliasMgr.FindBaseSCID,
 	}, uint32(currentHeight))
 	if err != nil {
 		return nil, err
 	}
-	s.interceptableSwitch = htlcswitch.NewInterceptableSwitch(s.htlcSwitch)
+	s.interceptableSwitch = htlcswitch.NewInterceptableSwitch(
+		&htlcswitch.InterceptableSwitchConfig{
+			Switch:             s.htlcSwitch,
+			Notifier:           s.cc.ChainNotifier,
+			CltvRejectDelta:    lncfg.DefaultFinalCltvRejectDelta,
+			RequireInterceptor: cfg.RequireInterceptor,
+		},
+	)
 
 	chanStatusMgrCfg := &netann.ChanStatusConfig{
 		ChanStatusSampleInterval: cfg.ChanStatusSampleInterval,
@@ -729,16 +781,39 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// servers, the mission control instance itself can be moved there too.
 	routingConfig := routerrpc.GetRoutingConfig(cfg.SubRPCServers.RouterRPC)
 
-	estimatorCfg := routing.ProbabilityEstimatorCfg{
-		AprioriHopProbability: routingConfig.AprioriHopProbability,
-		PenaltyHalfLife:       routingConfig.PenaltyHalfLife,
-		AprioriWeight:         routingConfig.AprioriWeight,
+	var estimator routing.Estimator
+	switch routingConfig.ProbabilityEstimatorType {
+	case routing.AprioriEstimatorName:
+		aCfg := routing.AprioriConfig{
+			AprioriHopProbability: routingConfig.AprioriHopProbability,
+			PenaltyHalfLife:       routingConfig.PenaltyHalfLife,
+			AprioriWeight:         routingConfig.AprioriWeight,
+		}
+		estimator, err = routing.NewAprioriEstimator(aCfg)
+
+	case routing.BimodalEstimatorName:
+		bCfg := routing.BimodalConfig{
+			BimodalNodeWeight: routingConfig.BimodalConfig.NodeWeight,
+			BimodalScaleMsat: lnwire.MilliSatoshi(
+				routingConfig.BimodalConfig.Scale,
+			),
+			BimodalDecayTime: routingConfig.BimodalConfig.DecayTime,
+		}
+		estimator, err = routing.NewBimodalEstimator(bCfg)
+
+	default:
+		return nil, fmt.Errorf("unknown estimator type %v",
+			routingConfig.ProbabilityEstimatorType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't create probability estimator: "+
+			"%v", err)
 	}
 
 	s.missionControl, err = routing.NewMissionControl(
 		remoteChanDB, selfNode.PubKeyBytes,
 		&routing.MissionControlConfig{
-			ProbabilityEstimatorCfg: estimatorCfg,
+			Estimator:               estimator,
 			MaxMcHistory:            routingConfig.MaxMcHistory,
 			MinFailureRelaxInterval: routing.DefaultMinFailureRelaxInterval,
 		},
@@ -772,6 +847,26 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	s.controlTower = routing.NewControlTower(paymentControl)
 
+	// If a trampoline node is configured, payments to destinations that
+	// are not known to our graph are delegated to it.
+	var trampolineCfg *routing.TrampolineConfig
+	trampolineNode, err := cfg.Trampoline.NodeKey()
+	if err != nil {
+		return nil, err
+	}
+	if trampolineNode != nil {
+		trampolineCfg = &routing.TrampolineConfig{
+			Node: route.NewVertex(trampolineNode),
+			FeeBase: lnwire.MilliSatoshi(
+				cfg.Trampoline.FeeBaseMsat,
+			),
+			FeeRate: lnwire.MilliSatoshi(
+				cfg.Trampoline.FeeRatePPM,
+			),
+			CltvDelta: cfg.Trampoline.CltvDelta,
+		}
+	}
+
 	strictPruning := (cfg.Bitcoin.Node == "neutrino" ||
 		cfg.Routing.StrictZombiePruning)
 	s.chanRouter, err = routing.New(routing.Config{
@@ -791,11 +886,31 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PathFindingConfig:   pathFindingConfig,
 		Clock:               clock.NewDefaultClock(),
 		StrictZombiePruning: strictPruning,
+		IsAlias:             aliasmgr.IsAlias,
+		Trampoline:          trampolineCfg,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %v", err)
 	}
 
+	if cfg.Trampoline.Relay {
+		s.trampolineRelay = htlcswitch.NewTrampolineRelay(
+			&htlcswitch.TrampolineRelayConfig{
+				NodeKey: nodeKeyECDH,
+				FeeBase: lnwire.MilliSatoshi(
+					cfg.Trampoline.RelayFeeBaseMsat,
+				),
+				FeeRate: lnwire.MilliSatoshi(
+					cfg.Trampoline.RelayFeeRatePPM,
+				),
+				CltvDelta:   cfg.Trampoline.RelayCltvDelta,
+				MppTimeout:  invoices.DefaultHtlcHoldDuration,
+				Registry:    s.invoices,
+				SendPayment: s.sendTrampolinePayment,
+			},
+		)
+	}
+
 	chanSeries := discovery.NewChanSeries(s.localChanDB.ChannelGraph())
 	gossipMessageStore, err := discovery.NewMessageStore(s.remoteChanDB)
 	if err != nil {
@@ -999,6 +1114,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Sweeper:                       s.sweeper,
 		Registry:                      s.invoices,
 		NotifyClosedChannel:           s.channelNotifier.NotifyClosedChannelEvent,
+		NotifySplicedChannel:          s.notifySplicedChannel,
 		OnionProcessor:                s.sphinx,
 		PaymentsExpirationGracePeriod: cfg.PaymentsExpirationGracePeriod,
 		IsForwardedHTLC:               s.htlcSwitch.IsForwardedHTLC,
@@ -1034,6 +1150,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	// For inbound dual funded channels, we match the opener's
+	// contribution, up to our configured maximum.
+	dualFundContribution := func(_ *btcec.PublicKey,
+		msg *lnwire.OpenChannel2) btcutil.Amount {
+
+		maxAmt := btcutil.Amount(cfg.DualFundMaxContribution)
+		if msg.FundingAmount < maxAmt {
+			return msg.FundingAmount
+		}
+		return maxAmt
+	}
+
 	s.fundingMgr, err = funding.NewFundingManager(funding.Config{
 		NoWumboChans:       !cfg.ProtocolOptions.Wumbo(),
 		IDKey:              nodeKeyECDH.PubKey(),
@@ -1218,6 +1346,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MaxPendingChannels:            cfg.MaxPendingChannels,
 		RejectPush:                    cfg.RejectPush,
 		MaxLocalCSVDelay:              chainCfg.MaxLocalDelay,
+		DualFundContribution:          dualFundContribution,
 		NotifyOpenChannelEvent:        s.channelNotifier.NotifyOpenChannelEvent,
 		OpenChannelPredicate:          chanPredicate,
 		NotifyPendingOpenChannelEvent: s.channelNotifier.NotifyPendingOpenChannelEvent,
@@ -1225,6 +1354,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RegisteredChains:              cfg.registeredChains,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
+		AliasManager: s.aliasMgr,
+		DeleteAliasEdge: func(scid lnwire.ShortChannelID) error {
+			return chanGraph.DeleteChannelEdges(
+				false, scid.ToUint64(),
+			)
+		},
 	})
 	if err != nil {
 		return nil, err
@@ -1252,6 +1387,44 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// to peer online and offline events.
 	s.peerNotifier = peernotifier.New()
 
+	// Create a subscription server that forwards the custom messages we
+	// receive from our peers.
+	s.customMessageServer = subscribe.NewServer()
+
+	// Create a subscription server that forwards the onion messages
+	// addressed to us.
+	s.onionMessageServer = subscribe.NewServer()
+
+	// The metadata of our offers is authenticated with a secret that is
+	// derived from our node key, so we can recognize our offers without
+	// storing them.
+	offerSecret, err := nodeKeyECDH.ECDH(nodeKeyECDH.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	offerMetadataKey := sha256.Sum256(
+		append([]byte("offer metadata"), offerSecret[:]...),
+	)
+
+	s.offerManager = offers.NewManager(&offers.ManagerConfig{
+		NodeKey:     nodeKeyECDH.PubKey(),
+		MetadataKey: offerMetadataKey,
+		ChainHash:   *cfg.ActiveNetParams.GenesisHash,
+		SignInvoice: func(digest [32]byte) (schnorr.Signature,
+			error) {
+
+			nodeKey, err := cc.KeyRing.DerivePrivKey(*nodeKeyDesc)
+			if err != nil {
+				return schnorr.Signature{}, err
+			}
+
+			return schnorr.Sign(nodeKey, digest)
+		},
+		AddInvoice:           s.addOfferInvoice,
+		SendOnionMessage:     s.SendOnionMessage,
+		FindOnionMessagePath: s.findOnionMessagePath,
+	})
+
 	// Create a channel event store which monitors all open channels.
 	s.chanEventStore = chanfitness.NewChannelEventStore(&chanfitness.Config{
 		SubscribeChannelEvents: func() (subscribe.Subscription, error) {
@@ -1333,6 +1506,29 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		if err != nil {
 			return nil, err
 		}
+
+		// Copy the policy for legacy channels and set the blob flag
+		// signalling support for simple taproot channels.
+		taprootPolicy := policy
+		taprootPolicy.TxPolicy.BlobType |=
+			blob.Type(blob.FlagTaprootChannel)
+
+		s.taprootTowerClient, err = wtclient.New(&wtclient.Config{
+			Signer:         cc.Wallet.Cfg.Signer,
+			NewAddress:     newSweepPkScriptGen(cc.Wallet),
+			SecretKeyRing:  s.cc.KeyRing,
+			Dial:           cfg.net.Dial,
+			AuthDial:       authDial,
+			DB:             towerClientDB,
+			Policy:         taprootPolicy,
+			ChainHash:      *s.cfg.ActiveNetParams.GenesisHash,
+			MinBackoff:     10 * time.Second,
+			MaxBackoff:     5 * time.Minute,
+			ForceQuitDelay: wtclient.DefaultForceQuitDelay,
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if len(cfg.ExternalHosts) != 0 {
@@ -1563,6 +1759,25 @@ func (s *server) Start() error {
 		cleanup = cleanup.add(func() error {
 			return s.peerNotifier.Stop()
 		})
+
+		if err := s.customMessageServer.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.customMessageServer.Stop)
+
+		if err := s.onionMessageServer.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.onionMessageServer.Stop)
+
+		if err := s.offerManager.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.offerManager.Stop)
+
 		if err := s.htlcNotifier.Start(); err != nil {
 			startErr = err
 			return
@@ -1589,6 +1804,13 @@ func (s *server) Start() error {
 			}
 			cleanup = cleanup.add(s.anchorTowerClient.Stop)
 		}
+		if s.taprootTowerClient != nil {
+			if err := s.taprootTowerClient.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.taprootTowerClient.Stop)
+		}
 
 		if err := s.htlcSwitch.Start(); err != nil {
 			startErr = err
@@ -1596,6 +1818,12 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.htlcSwitch.Stop)
 
+		if err := s.interceptableSwitch.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.interceptableSwitch.Stop)
+
 		if err := s.sweeper.Start(); err != nil {
 			startErr = err
 			return
@@ -1824,6 +2052,10 @@ func (s *server) Stop() error {
 		if err := s.chanRouter.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanRouter: %v", err)
 		}
+		if err := s.interceptableSwitch.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop interceptableSwitch: %v",
+				err)
+		}
 		if err := s.htlcSwitch.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcSwitch: %v", err)
 		}
@@ -1851,6 +2083,20 @@ func (s *server) Stop() error {
 		if err := s.peerNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop peerNotifier: %v", err)
 		}
+		if err := s.customMessageServer.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop customMessageServer: %v",
+				err)
+		}
+		if err := s.onionMessageServer.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop onionMessageServer: %v",
+				err)
+		}
+		if err := s.offerManager.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop offerManager: %v", err)
+		}
+		if s.trampolineRelay != nil {
+			s.trampolineRelay.Stop()
+		}
 		if err := s.htlcNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcNotifier: %v", err)
 		}
@@ -1894,6 +2140,12 @@ func (s *server) Stop() error {
 					"tower client: %v", err)
 			}
 		}
+		if s.taprootTowerClient != nil {
+			if err := s.taprootTowerClient.Stop(); err != nil {
+				srvrLog.Warnf("Unable to shut down taproot "+
+					"tower client: %v", err)
+			}
+		}
 
 		if s.hostAnn != nil {
 			if err := s.hostAnn.Stop(); err != nil {
@@ -2867,6 +3119,363 @@ func (s *server) findPeerByPubStr(pubStr string) (*peer.Brontide, error) {
 	return peer, nil
 }
 
+// CustomMessage is a custom message that is received from a peer.
+type CustomMessage struct {
+	// Peer is the pubkey of the peer that sent the message.
+	Peer [33]byte
+
+	// Msg is the custom wire message.
+	Msg *lnwire.Custom
+}
+
+// SendCustomMessage sends a custom message to the peer with the specified
+// pubkey.
+func (s *server) SendCustomMessage(peerPub [33]byte, msgType lnwire.MessageType,
+	data []byte) error {
+
+	msg, err := lnwire.NewCustom(msgType, data)
+	if err != nil {
+		return err
+	}
+
+	peer, err := s.FindPeerByPubStr(string(peerPub[:]))
+	if err != nil {
+		return err
+	}
+
+	// Send the message as low-priority. For now we assume that all
+	// application-defined messages are low priority.
+	return peer.SendMessageLazy(true, msg)
+}
+
+// handleCustomMessage dispatches an incoming custom peer message to
+// subscribers.
+func (s *server) handleCustomMessage(peer [33]byte, msg *lnwire.Custom) error {
+	srvrLog.Debugf("Custom message received: peer=%x, type=%d",
+		peer, msg.Type)
+
+	return s.customMessageServer.SendUpdate(&CustomMessage{
+		Peer: peer,
+		Msg:  msg,
+	})
+}
+
+// SubscribeCustomMessages subscribes to a stream of incoming custom peer
+// messages.
+func (s *server) SubscribeCustomMessages() (*subscribe.Client, error) {
+	return s.customMessageServer.Subscribe()
+}
+
+// OnionMessage is an onion message addressed to us that is received from a
+// peer.
+type OnionMessage struct {
+	// Peer is the pubkey of the peer that delivered the message. This is
+	// not necessarily the sender of the message.
+	Peer [33]byte
+
+	// Payload is the decrypted content of the message.
+	Payload *hop.OnionMessagePayload
+}
+
+// SendOnionMessage sends an onion message along the passed path of node
+// pubkeys, optionally followed by a blinded destination. The first node of the
+// message must be one of our peers.
+func (s *server) SendOnionMessage(path []*btcec.PublicKey,
+	destination *blindedpath.BlindedPath,
+	payload *hop.OnionMessagePayload) error {
+
+	firstNode, msg, err := hop.CreateOnionMessage(
+		path, destination, payload,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.forwardOnionMessage(firstNode, msg)
+}
+
+// forwardOnionMessage sends an onion message to the peer with the specified
+// pubkey if it supports onion messages.
+func (s *server) forwardOnionMessage(nextNode *btcec.PublicKey,
+	msg *lnwire.OnionMessage) error {
+
+	peer, err := s.FindPeer(nextNode)
+	if err != nil {
+		return err
+	}
+
+	if !peer.RemoteFeatures().HasFeature(lnwire.OnionMessagesOptional) {
+		return fmt.Errorf("peer %x doesn't support onion messages",
+			nextNode.SerializeCompressed())
+	}
+
+	// Onion messages are low priority, as they don't affect any of our
+	// channels. We don't wait for the message to be sent so forwarding
+	// doesn't block the read handler of the incoming peer.
+	return peer.SendMessageLazy(false, msg)
+}
+
+// handleOnionMessage dispatches an onion message addressed to us to
+// subscribers.
+func (s *server) handleOnionMessage(peer [33]byte,
+	payload *hop.OnionMessagePayload) error {
+
+	srvrLog.Debugf("Onion message received: peer=%x, num_records=%d",
+		peer, len(payload.CustomRecords))
+
+	// Messages that belong to offers are handled by the offer manager.
+	// They are still dispatched to subscribers.
+	s.offerManager.HandleOnionMessage(payload)
+
+	return s.onionMessageServer.SendUpdate(&OnionMessage{
+		Peer:    peer,
+		Payload: payload,
+	})
+}
+
+// SubscribeOnionMessages subscribes to a stream of onion messages addressed to
+// us.
+func (s *server) SubscribeOnionMessages() (*subscribe.Client, error) {
+	return s.onionMessageServer.Subscribe()
+}
+
+// maxOnionMessagePathLength is the maximum number of nodes of a path we find
+// to send an onion message to a node we're not connected to.
+const maxOnionMessagePathLength = 8
+
+// findOnionMessagePath finds the shortest path of nodes that support onion
+// messages from one of our peers to the target. The first node of the path is
+// a peer we're connected to, the last node is the target.
+func (s *server) findOnionMessagePath(
+	target *btcec.PublicKey) ([]*btcec.PublicKey, error) {
+
+	targetVertex := route.NewVertex(target)
+	supportsOnionMessages := func(peer *peer.Brontide) bool {
+		return peer.RemoteFeatures().HasFeature(
+			lnwire.OnionMessagesOptional,
+		)
+	}
+
+	// The path starts at one of our connected peers that supports onion
+	// messages.
+	parents := make(map[route.Vertex]*route.Vertex)
+	var queue []route.Vertex
+	for _, p := range s.Peers() {
+		if !supportsOnionMessages(p) {
+			continue
+		}
+
+		vertex := route.NewVertex(p.IdentityKey())
+		if vertex == targetVertex {
+			return []*btcec.PublicKey{target}, nil
+		}
+
+		parents[vertex] = nil
+		queue = append(queue, vertex)
+	}
+
+	// Search the graph breadth first for the target. Intermediate nodes
+	// must advertise support for onion messages.
+	graph := s.localChanDB.ChannelGraph()
+	selfVertex := route.NewVertex(s.identityECDH.PubKey())
+	for depth := 1; depth < maxOnionMessagePathLength; depth++ {
+		var next []route.Vertex
+		for _, vertex := range queue {
+			err := graph.ForEachNodeChannel(nil, vertex[:],
+				func(_ kvdb.RTx, info *channeldb.ChannelEdgeInfo,
+					_, _ *channeldb.ChannelEdgePolicy) error {
+
+					neighbor := info.NodeKey1Bytes
+					if neighbor == vertex {
+						neighbor = info.NodeKey2Bytes
+					}
+
+					_, seen := parents[neighbor]
+					if seen || neighbor == selfVertex {
+						return nil
+					}
+
+					if neighbor != targetVertex {
+						node, err := graph.FetchLightningNode(
+							nil, neighbor,
+						)
+						if err != nil || !node.Features.HasFeature(
+							lnwire.OnionMessagesOptional,
+						) {
+							return nil
+						}
+					}
+
+					parent := vertex
+					parents[neighbor] = &parent
+					next = append(next, neighbor)
+
+					return nil
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := parents[targetVertex]; ok {
+				return onionMessagePathTo(targetVertex, parents)
+			}
+		}
+
+		queue = next
+	}
+
+	return nil, fmt.Errorf("no onion message path to %x",
+		target.SerializeCompressed())
+}
+
+// onionMessagePathTo reconstructs the path to the target from the parents
+// found during the path search.
+func onionMessagePathTo(target route.Vertex,
+	parents map[route.Vertex]*route.Vertex) ([]*btcec.PublicKey, error) {
+
+	var path []*btcec.PublicKey
+	for vertex := &target; vertex != nil; vertex = parents[*vertex] {
+		pub, err := btcec.ParsePubKey(vertex[:], btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+
+		path = append([]*btcec.PublicKey{pub}, path...)
+	}
+
+	return path, nil
+}
+
+// addInvoiceConfig returns the configuration used to add new invoices.
+func (s *server) addInvoiceConfig() *invoicesrpc.AddInvoiceConfig {
+	defaultDelta := s.cfg.Bitcoin.TimeLockDelta
+	if s.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
+		defaultDelta = s.cfg.Litecoin.TimeLockDelta
+	}
+
+	return &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        s.invoices.AddInvoice,
+		IsChannelActive:   s.htlcSwitch.HasActiveLink,
+		ChainParams:       s.cfg.ActiveNetParams.Params,
+		NodeSigner:        s.nodeSigner,
+		DefaultCLTVExpiry: defaultDelta,
+		ChanDB:            s.remoteChanDB,
+		Graph:             s.localChanDB.ChannelGraph(),
+		GenInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoice)
+		},
+		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoiceAmp)
+		},
+		GetAlias:   s.aliasMgr.GetPeerAlias,
+		NodePubKey: s.identityECDH.PubKey(),
+		BestHeight: func() (uint32, error) {
+			_, height, err := s.cc.ChainIO.GetBestBlock()
+			return uint32(height), err
+		},
+	}
+}
+
+// addOfferInvoice adds an invoice for an invoice request for one of our
+// offers.
+func (s *server) addOfferInvoice(offerID [32]byte, memo string,
+	amt lnwire.MilliSatoshi, expiry time.Duration,
+	encode offers.EncodeInvoiceFunc) error {
+
+	_, _, err := invoicesrpc.AddOfferInvoice(
+		s.addInvoiceConfig(), &invoicesrpc.OfferInvoiceData{
+			OfferID: offerID,
+			Memo:    memo,
+			Value:   amt,
+			Expiry:  expiry,
+			Encode:  encode,
+		},
+	)
+
+	return err
+}
+
+// sendTrampolinePayment pays the next node of a trampoline payment that we
+// relay. If the payment is already in flight, for example because we
+// restarted while relaying it, we wait for its result instead.
+func (s *server) sendTrampolinePayment(
+	p *htlcswitch.TrampolinePayment) (lntypes.Preimage, error) {
+
+	_, height, err := s.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return lntypes.Preimage{}, err
+	}
+
+	// The relay checked the expiry when it accepted the payment, but
+	// blocks may have been mined since.
+	if p.ExpiresTooSoon(uint32(height)) {
+		return lntypes.Preimage{}, htlcswitch.ErrTrampolineExpiryTooSoon
+	}
+
+	payment := &routing.LightningPayment{
+		Target:            route.NewVertex(p.Target),
+		Amount:            p.Amount,
+		FeeLimit:          p.FeeLimit,
+		CltvLimit:         p.MaxCltv - uint32(height),
+		FinalCLTVDelta:    uint16(p.FinalCltv - uint32(height)),
+		PayAttemptTimeout: routing.DefaultPayAttemptTimeout,
+		RouteHints:        p.RouteHints,
+		PaymentAddr:       p.PaymentAddr,
+		MaxParts:          1,
+		TrampolineOnion:   p.TrampolineOnion,
+	}
+	if p.Features != nil {
+		payment.DestFeatures = lnwire.NewFeatureVector(
+			p.Features, lnwire.Features,
+		)
+	}
+	if err := payment.SetPaymentHash(p.PaymentHash); err != nil {
+		return lntypes.Preimage{}, err
+	}
+
+	preimage, _, err := s.chanRouter.SendPayment(payment)
+	switch err {
+	case nil:
+		return preimage, nil
+
+	case channeldb.ErrPaymentInFlight, channeldb.ErrAlreadyPaid:
+
+	default:
+		return lntypes.Preimage{}, err
+	}
+
+	subscription, err := s.controlTower.SubscribePayment(p.PaymentHash)
+	if err != nil {
+		return lntypes.Preimage{}, err
+	}
+	defer subscription.Close()
+
+	for {
+		select {
+		case item, ok := <-subscription.Updates:
+			if !ok {
+				return lntypes.Preimage{}, fmt.Errorf("payment " +
+					"subscription closed")
+			}
+
+			payment := item.(*channeldb.MPPayment)
+			settle, failure := payment.TerminalInfo()
+			switch {
+			case settle != nil:
+				return settle.Preimage, nil
+
+			case failure != nil:
+				return lntypes.Preimage{}, fmt.Errorf("payment "+
+					"failed: %v", *failure)
+			}
+
+		case <-s.quit:
+			return lntypes.Preimage{}, ErrServerShuttingDown
+		}
+	}
+}
+
 // nextPeerBackoff computes the next backoff duration for a peer's pubkey using
 // exponential backoff. If no previous backoff was known, the default is
 // returned.
@@ -3215,6 +3824,13 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		}
 	}
 
+	// Only hand the trampoline relay to the peer if it is enabled, so that
+	// the links reject trampoline payments otherwise.
+	var trampolineRelay htlcswitch.TrampolineRelayer
+	if s.trampolineRelay != nil {
+		trampolineRelay = s.trampolineRelay
+	}
+
 	// Now that we've established a connection, create a peer, and it to the
 	// set of currently active peers. Configure the peer with the incoming
 	// and outgoing broadcast deltas to prevent htlcs from being accepted or
@@ -3250,10 +3866,12 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		Sphinx:                  s.sphinx,
 		WitnessBeacon:           s.witnessBeacon,
 		Invoices:                s.invoices,
+		TrampolineRelay:         trampolineRelay,
 		ChannelNotifier:         s.channelNotifier,
 		HtlcNotifier:            s.htlcNotifier,
 		TowerClient:             s.towerClient,
 		AnchorTowerClient:       s.anchorTowerClient,
+		TaprootTowerClient:      s.taprootTowerClient,
 		DisconnectPeer:          s.DisconnectPeer,
 		GenNodeAnnouncement:     s.genNodeAnnouncement,
 
@@ -3263,6 +3881,12 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 
 		FundingManager: s.fundingMgr,
 
+		HandleCustomMessage: s.handleCustomMessage,
+
+		OnionMessageProcessor: s.onionMessageProcessor,
+		HandleOnionMessage:    s.handleOnionMessage,
+		ForwardOnionMessage:   s.forwardOnionMessage,
+
 		Hodl:                    s.cfg.Hodl,
 		UnsafeReplay:            s.cfg.UnsafeReplay,
 		MaxOutgoingCltvExpiry:   s.cfg.MaxOutgoingCltvExpiry,
@@ -3954,3 +4578,29 @@ func newSweepPkScriptGen(
 		return txscript.PayToAddrScript(sweepAddr)
 	}
 }
+
+// notifySplicedChannel is called by the chain arbitrator once a channel was
+// moved to the funding output of a confirmed splice transaction. The new
+// funding output is announced, the channel is handed back to the peer and the
+// channel notifier is informed.
+func (s *server) notifySplicedChannel(oldChanPoint wire.OutPoint,
+	newChan *channeldb.OpenChannel) {
+
+	if err := s.fundingMgr.SpliceConfirmed(newChan); err != nil {
+		srvrLog.Errorf("Unable to announce spliced ChannelPoint(%v): %v",
+			newChan.FundingOutpoint, err)
+	}
+
+	// If the peer is offline, the channel is loaded from the database
+	// once it connects.
+	peer, err := s.FindPeer(newChan.IdentityPub)
+	if err == nil {
+		err := peer.HandleSplicedChannel(oldChanPoint, newChan)
+		if err != nil {
+			srvrLog.Errorf("Unable to add spliced ChannelPoint(%v) "+
+				"to peer: %v", newChan.FundingOutpoint, err)
+		}
+	}
+
+	s.channelNotifier.NotifySplicedChannelEvent(oldChanPoint, newChan)
+}
@@ -259,6 +259,9 @@ func createSweepTx(inputs []input.Input, outputs []*wire.TxOut,
 
 	hashCache := txscript.NewTxSigHashes(sweepTx)
 
+	// Taproot inputs commit to all the outputs spent by the transaction.
+	input.SetPrevOutputFetcher(idxs...)
+
 	// With all the inputs in place, use each output's unique input script
 	// function to generate the final witness required for spending.
 	addInputScript := func(idx int, tso input.Input) error {
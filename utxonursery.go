@@ -367,10 +367,14 @@ func (u *utxoNursery) IncubateOutputs(chanPoint wire.OutPoint,
 	// second-layer HTLC output. We effectively skip the baby stage (as the
 	// timelock is zero), and enter the kid stage.
 	for _, htlcRes := range incomingHtlcs {
+		witnessType := input.HtlcAcceptedSuccessSecondLevel
+		if htlcRes.SweepSignDesc.SignMethod.IsTaproot() {
+			witnessType = input.TaprootHtlcAcceptedSuccessSecondLevel
+		}
+
 		htlcOutput := makeKidOutput(
 			&htlcRes.ClaimOutpoint, &chanPoint, htlcRes.CsvDelay,
-			input.HtlcAcceptedSuccessSecondLevel,
-			&htlcRes.SweepSignDesc, 0,
+			witnessType, &htlcRes.SweepSignDesc, 0,
 		)
 
 		if htlcOutput.Amount() > 0 {
@@ -400,10 +404,14 @@ func (u *utxoNursery) IncubateOutputs(chanPoint wire.OutPoint,
 		// CLTV lock has expired. We set the CSV delay what the
 		// resolution encodes, since the sequence number must be set
 		// accordingly.
+		witnessType := input.HtlcOfferedRemoteTimeout
+		if htlcRes.SweepSignDesc.SignMethod.IsTaproot() {
+			witnessType = input.TaprootHtlcOfferedRemoteTimeout
+		}
+
 		htlcOutput := makeKidOutput(
 			&htlcRes.ClaimOutpoint, &chanPoint, htlcRes.CsvDelay,
-			input.HtlcOfferedRemoteTimeout,
-			&htlcRes.SweepSignDesc, htlcRes.Expiry,
+			witnessType, &htlcRes.SweepSignDesc, htlcRes.Expiry,
 		)
 		kidOutputs = append(kidOutputs, htlcOutput)
 	}
@@ -515,13 +523,17 @@ func (u *utxoNursery) NurseryReport(
 				// confirmation of the commitment transaction.
 				switch kid.WitnessType() {
 
-				case input.HtlcAcceptedSuccessSecondLevel:
+				case input.HtlcAcceptedSuccessSecondLevel,
+					input.TaprootHtlcAcceptedSuccessSecondLevel:
+
 					// An HTLC output on our commitment transaction
 					// where the second-layer transaction hasn't
 					// yet confirmed.
 					report.AddLimboStage1SuccessHtlc(&kid)
 
-				case input.HtlcOfferedRemoteTimeout:
+				case input.HtlcOfferedRemoteTimeout,
+					input.TaprootHtlcOfferedRemoteTimeout:
+
 					// This is an HTLC output on the
 					// commitment transaction of the remote
 					// party. We are waiting for the CLTV
@@ -536,7 +548,9 @@ func (u *utxoNursery) NurseryReport(
 				// types.
 				switch kid.WitnessType() {
 
-				case input.HtlcOfferedRemoteTimeout:
+				case input.HtlcOfferedRemoteTimeout,
+					input.TaprootHtlcOfferedRemoteTimeout:
+
 					// This is an HTLC output on the
 					// commitment transaction of the remote
 					// party. The CLTV timelock has
@@ -544,9 +558,11 @@ func (u *utxoNursery) NurseryReport(
 					// it.
 					report.AddLimboDirectHtlc(&kid)
 
-				case input.HtlcAcceptedSuccessSecondLevel:
-					fallthrough
-				case input.HtlcOfferedTimeoutSecondLevel:
+				case input.HtlcAcceptedSuccessSecondLevel,
+					input.HtlcOfferedTimeoutSecondLevel,
+					input.TaprootHtlcAcceptedSuccessSecondLevel,
+					input.TaprootHtlcOfferedTimeoutSecondLevel:
+
 					// The htlc timeout or success
 					// transaction has confirmed, and the
 					// CSV delay has begun ticking.
@@ -560,11 +576,13 @@ func (u *utxoNursery) NurseryReport(
 				// balance.
 				switch kid.WitnessType() {
 
-				case input.HtlcAcceptedSuccessSecondLevel:
-					fallthrough
-				case input.HtlcOfferedTimeoutSecondLevel:
-					fallthrough
-				case input.HtlcOfferedRemoteTimeout:
+				case input.HtlcAcceptedSuccessSecondLevel,
+					input.HtlcOfferedTimeoutSecondLevel,
+					input.HtlcOfferedRemoteTimeout,
+					input.TaprootHtlcAcceptedSuccessSecondLevel,
+					input.TaprootHtlcOfferedTimeoutSecondLevel,
+					input.TaprootHtlcOfferedRemoteTimeout:
+
 					// This htlc output successfully
 					// resides in a p2wkh output belonging
 					// to the user.
@@ -1210,6 +1228,9 @@ func makeBabyOutput(chanPoint *wire.OutPoint,
 	htlcOutpoint := htlcResolution.ClaimOutpoint
 	blocksToMaturity := htlcResolution.CsvDelay
 	witnessType := input.HtlcOfferedTimeoutSecondLevel
+	if htlcResolution.SweepSignDesc.SignMethod.IsTaproot() {
+		witnessType = input.TaprootHtlcOfferedTimeoutSecondLevel
+	}
 
 	kid := makeKidOutput(
 		&htlcOutpoint, chanPoint, blocksToMaturity, witnessType,
@@ -1297,7 +1318,9 @@ func makeKidOutput(outpoint, originChanPoint *wire.OutPoint,
 	// transaction, or is an outgoing HTLC on the commitment transaction of
 	// the remote peer.
 	isHtlc := (witnessType == input.HtlcAcceptedSuccessSecondLevel ||
-		witnessType == input.HtlcOfferedRemoteTimeout)
+		witnessType == input.HtlcOfferedRemoteTimeout ||
+		witnessType == input.TaprootHtlcAcceptedSuccessSecondLevel ||
+		witnessType == input.TaprootHtlcOfferedRemoteTimeout)
 
 	// heightHint can be safely set to zero here, because after this
 	// function returns, nursery will set a proper confirmation height in
@@ -81,6 +81,12 @@ var (
 		"cannot obtain commit to-remote p2wkh output script from blob",
 	)
 
+	// ErrNotTaprootChannel is returned when trying to retrieve a tapscript
+	// tree from a blob that isn't meant for a taproot channel.
+	ErrNotTaprootChannel = errors.New(
+		"cannot obtain tapscript tree from non-taproot blob",
+	)
+
 	// ErrSweepAddressToLong is returned when trying to encode or decode a
 	// sweep address with length greater than the maximum length of 42
 	// bytes, which supports p2wkh and p2sh addresses.
@@ -133,7 +139,8 @@ type JusticeKit struct {
 	CSVDelay uint32
 
 	// CommitToLocalSig is a signature under RevocationPubKey using
-	// SIGHASH_ALL.
+	// SIGHASH_ALL. For taproot channels, this is a BIP-340 signature using
+	// SIGHASH_DEFAULT.
 	CommitToLocalSig lnwire.Sig
 
 	// CommitToRemotePubKey is the public key in the to-remote output of the revoked
@@ -144,6 +151,8 @@ type JusticeKit struct {
 	CommitToRemotePubKey PubKey
 
 	// CommitToRemoteSig is a signature under CommitToRemotePubKey using SIGHASH_ALL.
+	// For taproot channels, this is a BIP-340 signature using
+	// SIGHASH_DEFAULT.
 	//
 	// NOTE: This value is only used if CommitToRemotePubKey contains a valid
 	// compressed public key.
@@ -151,8 +160,18 @@ type JusticeKit struct {
 }
 
 // CommitToLocalWitnessScript returns the serialized witness script for the
-// commitment to-local output.
+// commitment to-local output. For taproot channels, this is the revocation
+// leaf script of the to-local output.
 func (b *JusticeKit) CommitToLocalWitnessScript() ([]byte, error) {
+	if b.BlobType.IsTaprootChannel() {
+		toLocalTree, err := b.CommitToLocalTapscriptTree()
+		if err != nil {
+			return nil, err
+		}
+
+		return toLocalTree.Leaves[input.TaprootRevokeLeaf], nil
+	}
+
 	revocationPubKey, err := btcec.ParsePubKey(
 		b.RevocationPubKey[:], btcec.S256(),
 	)
@@ -172,10 +191,52 @@ func (b *JusticeKit) CommitToLocalWitnessScript() ([]byte, error) {
 	)
 }
 
+// CommitToLocalTapscriptTree returns the tapscript tree of the taproot
+// commitment to-local output, which is needed to locate the output and to
+// prove the inclusion of its revocation leaf.
+func (b *JusticeKit) CommitToLocalTapscriptTree() (*input.TapscriptTree,
+	error) {
+
+	if !b.BlobType.IsTaprootChannel() {
+		return nil, ErrNotTaprootChannel
+	}
+
+	revocationPubKey, err := btcec.ParsePubKey(
+		b.RevocationPubKey[:], btcec.S256(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	localDelayedPubKey, err := btcec.ParsePubKey(
+		b.LocalDelayPubKey[:], btcec.S256(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return input.TaprootCommitScriptToSelf(
+		b.CSVDelay, localDelayedPubKey, revocationPubKey,
+	)
+}
+
 // CommitToLocalRevokeWitnessStack constructs a witness stack spending the
 // revocation clause of the commitment to-local output.
 //   <revocation-sig> 1
+//
+// For taproot channels, the witness stack only consists of the BIP-340
+// signature, which satisfies the revocation leaf.
+//   <revocation-sig>
 func (b *JusticeKit) CommitToLocalRevokeWitnessStack() ([][]byte, error) {
+	if b.BlobType.IsTaprootChannel() {
+		toLocalSig, err := b.CommitToLocalSig.ToSchnorrSignature()
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]byte{toLocalSig.Serialize()}, nil
+	}
+
 	toLocalSig, err := b.CommitToLocalSig.ToSignature()
 	if err != nil {
 		return nil, err
@@ -197,13 +258,24 @@ func (b *JusticeKit) HasCommitToRemoteOutput() bool {
 
 // CommitToRemoteWitnessScript returns the witness script for the commitment
 // to-remote output given the blob type. The script returned will either be for
-// a p2wpkh to-remote output or an p2wsh anchor to-remote output which includes
-// a CSV delay.
+// a p2wpkh to-remote output, an p2wsh anchor to-remote output which includes
+// a CSV delay, or the leaf script of a taproot to-remote output.
 func (b *JusticeKit) CommitToRemoteWitnessScript() ([]byte, error) {
 	if !btcec.IsCompressedPubKey(b.CommitToRemotePubKey[:]) {
 		return nil, ErrNoCommitToRemoteOutput
 	}
 
+	// If this is a blob for a taproot channel, we'll return the single
+	// leaf of the to-remote output, which also contains a CSV delay of 1.
+	if b.BlobType.IsTaprootChannel() {
+		toRemoteTree, err := b.CommitToRemoteTapscriptTree()
+		if err != nil {
+			return nil, err
+		}
+
+		return toRemoteTree.Leaves[0], nil
+	}
+
 	// If this is a blob for an anchor channel, we'll return the p2wsh
 	// output containing a CSV delay of 1.
 	if b.BlobType.IsAnchorChannel() {
@@ -220,11 +292,41 @@ func (b *JusticeKit) CommitToRemoteWitnessScript() ([]byte, error) {
 	return b.CommitToRemotePubKey[:], nil
 }
 
+// CommitToRemoteTapscriptTree returns the tapscript tree of the taproot
+// commitment to-remote output.
+func (b *JusticeKit) CommitToRemoteTapscriptTree() (*input.TapscriptTree,
+	error) {
+
+	if !b.BlobType.IsTaprootChannel() {
+		return nil, ErrNotTaprootChannel
+	}
+
+	if !btcec.IsCompressedPubKey(b.CommitToRemotePubKey[:]) {
+		return nil, ErrNoCommitToRemoteOutput
+	}
+
+	pk, err := btcec.ParsePubKey(b.CommitToRemotePubKey[:], btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	return input.TaprootCommitScriptToRemote(pk)
+}
+
 // CommitToRemoteWitnessStack returns a witness stack spending the commitment
 // to-remote output, which consists of a single signature satisfying either the
-// legacy or anchor witness scripts.
+// legacy, anchor or taproot witness scripts.
 //   <to-remote-sig>
 func (b *JusticeKit) CommitToRemoteWitnessStack() ([][]byte, error) {
+	if b.BlobType.IsTaprootChannel() {
+		toRemoteSig, err := b.CommitToRemoteSig.ToSchnorrSignature()
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]byte{toRemoteSig.Serialize()}, nil
+	}
+
 	toRemoteSig, err := b.CommitToRemoteSig.ToSignature()
 	if err != nil {
 		return nil, err
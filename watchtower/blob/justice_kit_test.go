@@ -12,6 +12,7 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/schnorr"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/stretchr/testify/require"
 )
@@ -81,6 +82,19 @@ var descriptorTests = []descriptorTest{
 		commitToRemotePubKey: makePubKey(2),
 		commitToRemoteSig:    makeSig(2),
 	},
+	{
+		name:                 "taproot to-local and to-remote",
+		encVersion:           blob.TypeAltruistTaprootCommit,
+		decVersion:           blob.TypeAltruistTaprootCommit,
+		sweepAddr:            makeAddr(34),
+		revPubKey:            makePubKey(0),
+		delayPubKey:          makePubKey(1),
+		csvDelay:             144,
+		commitToLocalSig:     makeSig(1),
+		hasCommitToRemote:    true,
+		commitToRemotePubKey: makePubKey(2),
+		commitToRemoteSig:    makeSig(2),
+	},
 	{
 		name:             "unknown encrypt version",
 		encVersion:       0,
@@ -386,3 +400,105 @@ func TestJusticeKitToLocalWitnessConstruction(t *testing.T) {
 	}
 	require.Equal(t, expWitnessStack, toLocalWitnessStack)
 }
+
+// TestJusticeKitTaprootWitnessConstruction tests that a JusticeKit for a
+// taproot channel returns the leaf scripts and tapscript trees of the to-local
+// and to-remote outputs, along with witness stacks consisting of a single
+// BIP-340 signature.
+func TestJusticeKitTaprootWitnessConstruction(t *testing.T) {
+	csvDelay := uint32(144)
+
+	// Generate the revocation, delay and to-remote private keys.
+	revPrivKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.Nil(t, err)
+
+	delayPrivKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.Nil(t, err)
+
+	toRemotePrivKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.Nil(t, err)
+
+	// Copy the pubkeys into the format expected by our justice kit.
+	var revPubKey, delayPubKey, toRemotePubKey blob.PubKey
+	copy(revPubKey[:], revPrivKey.PubKey().SerializeCompressed())
+	copy(delayPubKey[:], delayPrivKey.PubKey().SerializeCompressed())
+	copy(toRemotePubKey[:], toRemotePrivKey.PubKey().SerializeCompressed())
+
+	// Sign a message using the revocation and to-remote private keys. The
+	// exact message doesn't matter as we won't be validating the
+	// signatures.
+	var digest [32]byte
+	copy(digest[:], bytes.Repeat([]byte("a"), 32))
+
+	rawRevSig, err := schnorr.Sign(revPrivKey, digest)
+	require.Nil(t, err)
+
+	rawToRemoteSig, err := schnorr.Sign(toRemotePrivKey, digest)
+	require.Nil(t, err)
+
+	// BIP-340 signatures are stored as is in the fixed-size sigs.
+	commitToLocalSig, err := lnwire.NewSigFromSignature(rawRevSig)
+	require.Nil(t, err)
+
+	commitToRemoteSig, err := lnwire.NewSigFromSignature(rawToRemoteSig)
+	require.Nil(t, err)
+
+	justiceKit := &blob.JusticeKit{
+		BlobType:             blob.TypeAltruistTaprootCommit,
+		CSVDelay:             csvDelay,
+		RevocationPubKey:     revPubKey,
+		LocalDelayPubKey:     delayPubKey,
+		CommitToLocalSig:     commitToLocalSig,
+		CommitToRemotePubKey: toRemotePubKey,
+		CommitToRemoteSig:    commitToRemoteSig,
+	}
+
+	// The to-local tapscript tree must match the one of the commitment
+	// transaction, and the witness script must be its revocation leaf.
+	expToLocalTree, err := input.TaprootCommitScriptToSelf(
+		csvDelay, delayPrivKey.PubKey(), revPrivKey.PubKey(),
+	)
+	require.Nil(t, err)
+
+	toLocalTree, err := justiceKit.CommitToLocalTapscriptTree()
+	require.Nil(t, err)
+	require.Equal(t, expToLocalTree.PkScript, toLocalTree.PkScript)
+
+	toLocalScript, err := justiceKit.CommitToLocalWitnessScript()
+	require.Nil(t, err)
+	require.Equal(
+		t, expToLocalTree.Leaves[input.TaprootRevokeLeaf],
+		toLocalScript,
+	)
+
+	// The to-local witness stack is only the signature, without a sighash
+	// flag.
+	toLocalWitnessStack, err := justiceKit.CommitToLocalRevokeWitnessStack()
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{rawRevSig.Serialize()}, toLocalWitnessStack)
+
+	// The same holds for the to-remote output.
+	expToRemoteTree, err := input.TaprootCommitScriptToRemote(
+		toRemotePrivKey.PubKey(),
+	)
+	require.Nil(t, err)
+
+	toRemoteTree, err := justiceKit.CommitToRemoteTapscriptTree()
+	require.Nil(t, err)
+	require.Equal(t, expToRemoteTree.PkScript, toRemoteTree.PkScript)
+
+	toRemoteScript, err := justiceKit.CommitToRemoteWitnessScript()
+	require.Nil(t, err)
+	require.Equal(t, expToRemoteTree.Leaves[0], toRemoteScript)
+
+	toRemoteWitnessStack, err := justiceKit.CommitToRemoteWitnessStack()
+	require.Nil(t, err)
+	require.Equal(
+		t, [][]byte{rawToRemoteSig.Serialize()}, toRemoteWitnessStack,
+	)
+
+	// Tapscript trees can't be obtained from blobs of other channel types.
+	justiceKit.BlobType = blob.TypeAltruistAnchorCommit
+	_, err = justiceKit.CommitToLocalTapscriptTree()
+	require.Equal(t, blob.ErrNotTaprootChannel, err)
+}
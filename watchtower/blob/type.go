@@ -24,6 +24,11 @@ const (
 	// channel, and therefore must expect a P2WSH-style to-remote output if
 	// one exists.
 	FlagAnchorChannel Flag = 1 << 2
+
+	// FlagTaprootChannel signals that this blob is meant to spend a simple
+	// taproot channel, and therefore must expect taproot to-local and
+	// to-remote outputs that are spent over a tapscript leaf.
+	FlagTaprootChannel Flag = 1 << 3
 )
 
 // Type returns a Type consisting solely of this flag enabled.
@@ -40,6 +45,8 @@ func (f Flag) String() string {
 		return "FlagCommitOutputs"
 	case FlagAnchorChannel:
 		return "FlagAnchorChannel"
+	case FlagTaprootChannel:
+		return "FlagTaprootChannel"
 	default:
 		return "FlagUnknown"
 	}
@@ -62,6 +69,11 @@ const (
 	// not give the tower a reward.
 	TypeAltruistAnchorCommit = Type(FlagCommitOutputs | FlagAnchorChannel)
 
+	// TypeAltruistTaprootCommit sweeps only commitment outputs from a
+	// simple taproot commitment to a sweep address controlled by the user,
+	// and does not give the tower a reward.
+	TypeAltruistTaprootCommit = Type(FlagCommitOutputs | FlagTaprootChannel)
+
 	// TypeRewardCommit sweeps only commitment outputs to a sweep address
 	// controlled by the user, and pays a negotiated reward to the tower.
 	TypeRewardCommit = Type(FlagCommitOutputs | FlagReward)
@@ -87,11 +99,18 @@ func (t Type) IsAnchorChannel() bool {
 	return t.Has(FlagAnchorChannel)
 }
 
+// IsTaprootChannel returns true if the blob type is for a simple taproot
+// channel.
+func (t Type) IsTaprootChannel() bool {
+	return t.Has(FlagTaprootChannel)
+}
+
 // knownFlags maps the supported flags to their name.
 var knownFlags = map[Flag]struct{}{
-	FlagReward:        {},
-	FlagCommitOutputs: {},
-	FlagAnchorChannel: {},
+	FlagReward:         {},
+	FlagCommitOutputs:  {},
+	FlagAnchorChannel:  {},
+	FlagTaprootChannel: {},
 }
 
 // String returns a human readable description of a Type.
@@ -138,9 +157,10 @@ func (t Type) String() string {
 // supportedTypes is the set of all configurations known to be supported by the
 // package.
 var supportedTypes = map[Type]struct{}{
-	TypeAltruistCommit:       {},
-	TypeRewardCommit:         {},
-	TypeAltruistAnchorCommit: {},
+	TypeAltruistCommit:        {},
+	TypeRewardCommit:          {},
+	TypeAltruistAnchorCommit:  {},
+	TypeAltruistTaprootCommit: {},
 }
 
 // IsSupportedType returns true if the given type is supported by the package.
@@ -18,17 +18,22 @@ var typeStringTests = []typeStringTest{
 	{
 		name:   "commit no-reward",
 		typ:    blob.TypeAltruistCommit,
-		expStr: "[No-FlagAnchorChannel|FlagCommitOutputs|No-FlagReward]",
+		expStr: "[No-FlagTaprootChannel|No-FlagAnchorChannel|FlagCommitOutputs|No-FlagReward]",
 	},
 	{
 		name:   "commit reward",
 		typ:    blob.TypeRewardCommit,
-		expStr: "[No-FlagAnchorChannel|FlagCommitOutputs|FlagReward]",
+		expStr: "[No-FlagTaprootChannel|No-FlagAnchorChannel|FlagCommitOutputs|FlagReward]",
+	},
+	{
+		name:   "taproot commit no-reward",
+		typ:    blob.TypeAltruistTaprootCommit,
+		expStr: "[FlagTaprootChannel|No-FlagAnchorChannel|FlagCommitOutputs|No-FlagReward]",
 	},
 	{
 		name:   "unknown flag",
 		typ:    unknownFlag.Type(),
-		expStr: "0000000000010000[No-FlagAnchorChannel|No-FlagCommitOutputs|No-FlagReward]",
+		expStr: "0000000000010000[No-FlagTaprootChannel|No-FlagAnchorChannel|No-FlagCommitOutputs|No-FlagReward]",
 	},
 }
 
@@ -129,6 +134,12 @@ func TestSupportedTypes(t *testing.T) {
 			blob.TypeAltruistAnchorCommit)
 	}
 
+	// Assert that the altruist taproot commit types are supported.
+	if !blob.IsSupportedType(blob.TypeAltruistTaprootCommit) {
+		t.Fatalf("default type %s is not supported",
+			blob.TypeAltruistTaprootCommit)
+	}
+
 	// Assert that all claimed supported types are actually supported.
 	for _, supType := range blob.SupportedTypes() {
 		if blob.IsSupportedType(supType) {
@@ -60,16 +60,37 @@ func (p *JusticeDescriptor) commitToLocalInput() (*breachedInput, error) {
 		return nil, err
 	}
 
-	// Compute the witness script hash, which will be used to locate the
-	// input on the breaching commitment transaction.
-	toLocalWitnessHash, err := input.WitnessScriptHash(toLocalScript)
-	if err != nil {
-		return nil, err
+	// Compute the output script, which will be used to locate the input on
+	// the breaching commitment transaction. For taproot channels, this is
+	// the taproot output committing to the revocation leaf, whose inclusion
+	// is proven by the control block in the witness.
+	var (
+		toLocalPkScript []byte
+		controlBlock    []byte
+	)
+	if p.JusticeKit.BlobType.IsTaprootChannel() {
+		toLocalTree, err := p.JusticeKit.CommitToLocalTapscriptTree()
+		if err != nil {
+			return nil, err
+		}
+
+		toLocalPkScript = toLocalTree.PkScript
+		controlBlock, err = toLocalTree.ControlBlock(
+			input.TaprootRevokeLeaf,
+		)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		toLocalPkScript, err = input.WitnessScriptHash(toLocalScript)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Locate the to-local output on the breaching commitment transaction.
 	toLocalIndex, toLocalTxOut, err := findTxOutByPkScript(
-		p.BreachedCommitTx, toLocalWitnessHash,
+		p.BreachedCommitTx, toLocalPkScript,
 	)
 	if err != nil {
 		return nil, err
@@ -92,7 +113,9 @@ func (p *JusticeDescriptor) commitToLocalInput() (*breachedInput, error) {
 	return &breachedInput{
 		txOut:    toLocalTxOut,
 		outPoint: toLocalOutPoint,
-		witness:  buildWitness(witnessStack, toLocalScript),
+		witness: buildWitness(
+			witnessStack, toLocalScript, controlBlock,
+		),
 	}, nil
 }
 
@@ -108,8 +131,24 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 	var (
 		toRemoteScriptHash []byte
 		toRemoteSequence   uint32
+		controlBlock       []byte
 	)
-	if p.JusticeKit.BlobType.IsAnchorChannel() {
+	switch {
+	case p.JusticeKit.BlobType.IsTaprootChannel():
+		toRemoteTree, err := p.JusticeKit.CommitToRemoteTapscriptTree()
+		if err != nil {
+			return nil, err
+		}
+
+		toRemoteScriptHash = toRemoteTree.PkScript
+		controlBlock, err = toRemoteTree.ControlBlock(0)
+		if err != nil {
+			return nil, err
+		}
+
+		toRemoteSequence = 1
+
+	case p.JusticeKit.BlobType.IsAnchorChannel():
 		toRemoteScriptHash, err = input.WitnessScriptHash(
 			toRemoteScript,
 		)
@@ -118,7 +157,8 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 		}
 
 		toRemoteSequence = 1
-	} else {
+
+	default:
 		// Since the to-remote witness script should just be a regular p2wkh
 		// output, we'll parse it to retrieve the public key.
 		toRemotePubKey, err := btcec.ParsePubKey(toRemoteScript, btcec.S256())
@@ -161,7 +201,9 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 	return &breachedInput{
 		txOut:    toRemoteTxOut,
 		outPoint: toRemoteOutPoint,
-		witness:  buildWitness(witnessStack, toRemoteScript),
+		witness: buildWitness(
+			witnessStack, toRemoteScript, controlBlock,
+		),
 		sequence: toRemoteSequence,
 	}, nil
 }
@@ -177,11 +219,11 @@ func (p *JusticeDescriptor) assembleJusticeTxn(txWeight int64,
 	// First, construct add the breached inputs to our justice transaction
 	// and compute the total amount that will be swept.
 	var totalAmt btcutil.Amount
-	for _, input := range inputs {
-		totalAmt += btcutil.Amount(input.txOut.Value)
+	for _, inp := range inputs {
+		totalAmt += btcutil.Amount(inp.txOut.Value)
 		justiceTxn.AddTxIn(&wire.TxIn{
-			PreviousOutPoint: input.outPoint,
-			Sequence:         input.sequence,
+			PreviousOutPoint: inp.outPoint,
+			Sequence:         inp.sequence,
 		})
 	}
 
@@ -216,18 +258,35 @@ func (p *JusticeDescriptor) assembleJusticeTxn(txWeight int64,
 		inputIndex[txIn.PreviousOutPoint] = i
 	}
 
+	// Taproot signatures commit to all outputs spent by the transaction,
+	// so collect them to be able to verify the taproot witnesses.
+	prevOuts := make(input.MultiPrevOutFetcher, len(inputs))
+	for _, inp := range inputs {
+		prevOuts[inp.outPoint] = inp.txOut
+	}
+
 	// Attach each of the provided witnesses to the transaction.
-	for _, input := range inputs {
+	for _, inp := range inputs {
 		// Lookup the input's new post-sort position.
-		i := inputIndex[input.outPoint]
-		justiceTxn.TxIn[i].Witness = input.witness
+		i := inputIndex[inp.outPoint]
+		justiceTxn.TxIn[i].Witness = inp.witness
 
 		// Validate the reconstructed witnesses to ensure they are valid
-		// for the breached inputs.
+		// for the breached inputs. The script engine doesn't validate
+		// taproot spends, so we verify those ourselves.
+		if input.IsPayToTaproot(inp.txOut.PkScript) {
+			err := input.VerifyTaprootSpend(justiceTxn, i, prevOuts)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
 		vm, err := txscript.NewEngine(
-			input.txOut.PkScript, justiceTxn, i,
+			inp.txOut.PkScript, justiceTxn, i,
 			txscript.StandardVerifyFlags,
-			nil, nil, input.txOut.Value,
+			nil, nil, inp.txOut.Value,
 		)
 		if err != nil {
 			return nil, err
@@ -286,9 +345,16 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 	// values on the sweep transaction, so we mimic the original bug to
 	// avoid invalidating signatures by older clients. For anchor channels
 	// we correct this and use the correct witness size.
-	if p.JusticeKit.BlobType.IsAnchorChannel() {
+	switch {
+	case p.JusticeKit.BlobType.IsTaprootChannel():
+		weightEstimate.AddWitnessInput(
+			input.TaprootToLocalRevokeWitnessSize,
+		)
+
+	case p.JusticeKit.BlobType.IsAnchorChannel():
 		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize)
-	} else {
+
+	default:
 		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize - 1)
 	}
 
@@ -304,9 +370,16 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 		}
 		sweepInputs = append(sweepInputs, toRemoteInput)
 
-		if p.JusticeKit.BlobType.IsAnchorChannel() {
+		switch {
+		case p.JusticeKit.BlobType.IsTaprootChannel():
+			weightEstimate.AddWitnessInput(
+				input.TaprootToRemoteWitnessSize,
+			)
+
+		case p.JusticeKit.BlobType.IsAnchorChannel():
 			weightEstimate.AddWitnessInput(input.ToRemoteConfirmedWitnessSize)
-		} else {
+
+		default:
 			weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
 		}
 	}
@@ -334,11 +407,18 @@ func findTxOutByPkScript(txn *wire.MsgTx,
 	return index, txn.TxOut[index], nil
 }
 
-// buildWitness appends the witness script to a given witness stack.
-func buildWitness(witnessStack [][]byte, witnessScript []byte) [][]byte {
-	witness := make([][]byte, len(witnessStack)+1)
+// buildWitness appends the witness script to a given witness stack. For
+// taproot script spends, the control block of the leaf is appended as well.
+func buildWitness(witnessStack [][]byte, witnessScript,
+	controlBlock []byte) [][]byte {
+
+	witness := make([][]byte, len(witnessStack)+1, len(witnessStack)+2)
 	lastIdx := copy(witness, witnessStack)
 	witness[lastIdx] = witnessScript
 
+	if controlBlock != nil {
+		witness = append(witness, controlBlock)
+	}
+
 	return witness
 }
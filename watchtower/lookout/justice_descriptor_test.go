@@ -52,6 +52,8 @@ var (
 	altruistCommitType = blob.FlagCommitOutputs.Type()
 
 	altruistAnchorCommitType = blob.TypeAltruistAnchorCommit
+
+	altruistTaprootCommitType = blob.TypeAltruistTaprootCommit
 )
 
 // TestJusticeDescriptor asserts that a JusticeDescriptor is able to produce the
@@ -73,6 +75,10 @@ func TestJusticeDescriptor(t *testing.T) {
 			name:     "altruist anchor commit type",
 			blobType: altruistAnchorCommitType,
 		},
+		{
+			name:     "altruist taproot commit type",
+			blobType: altruistTaprootCommitType,
+		},
 	}
 
 	for _, test := range tests {
@@ -84,6 +90,7 @@ func TestJusticeDescriptor(t *testing.T) {
 
 func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	isAnchorChannel := blobType.IsAnchorChannel()
+	isTaprootChannel := blobType.IsTaprootChannel()
 
 	const (
 		localAmount  = btcutil.Amount(100000)
@@ -109,15 +116,32 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		toRemoteKeyLoc = signer.AddPrivKey(toRemoteSK)
 	)
 
-	// Construct the to-local witness script.
-	toLocalScript, err := input.CommitScriptToSelf(
-		csvDelay, toLocalPK, revPK,
+	// Construct the to-local witness script and compute its witness script
+	// hash. For taproot channels, the witness script is the revocation leaf
+	// of the to-local output.
+	var (
+		toLocalScript     []byte
+		toLocalScriptHash []byte
+		toLocalTree       *input.TapscriptTree
+		err               error
 	)
-	require.Nil(t, err)
+	if isTaprootChannel {
+		toLocalTree, err = input.TaprootCommitScriptToSelf(
+			csvDelay, toLocalPK, revPK,
+		)
+		require.Nil(t, err)
 
-	// Compute the to-local witness script hash.
-	toLocalScriptHash, err := input.WitnessScriptHash(toLocalScript)
-	require.Nil(t, err)
+		toLocalScript = toLocalTree.Leaves[input.TaprootRevokeLeaf]
+		toLocalScriptHash = toLocalTree.PkScript
+	} else {
+		toLocalScript, err = input.CommitScriptToSelf(
+			csvDelay, toLocalPK, revPK,
+		)
+		require.Nil(t, err)
+
+		toLocalScriptHash, err = input.WitnessScriptHash(toLocalScript)
+		require.Nil(t, err)
+	}
 
 	// Compute the to-remote redeem script, witness script hash, and
 	// sequence numbers.
@@ -141,8 +165,18 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		toRemoteRedeemScript  []byte
 		toRemoteScriptHash    []byte
 		toRemoteSigningScript []byte
+		toRemoteTree          *input.TapscriptTree
 	)
-	if isAnchorChannel {
+	switch {
+	case isTaprootChannel:
+		toRemoteSequence = 1
+		toRemoteTree, err = input.TaprootCommitScriptToRemote(toRemotePK)
+		require.Nil(t, err)
+
+		toRemoteRedeemScript = toRemoteTree.Leaves[0]
+		toRemoteScriptHash = toRemoteTree.PkScript
+
+	case isAnchorChannel:
 		toRemoteSequence = 1
 		toRemoteRedeemScript, err = input.CommitScriptToRemoteConfirmed(
 			toRemotePK,
@@ -157,7 +191,7 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		// As it should be.
 		toRemoteSigningScript = toRemoteRedeemScript
 
-	} else {
+	default:
 		toRemoteRedeemScript = toRemotePK.SerializeCompressed()
 		toRemoteScriptHash, err = input.CommitScriptUnencumbered(
 			toRemotePK,
@@ -194,15 +228,19 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	// values on the sweep transaction, so we mimic the original bug and
 	// create signatures using the original weight estimate. For anchor
 	// channels we fix this and use the correct witness size.
-	if isAnchorChannel {
-		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize)
-	} else {
-		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize - 1)
-	}
+	switch {
+	case isTaprootChannel:
+		weightEstimate.AddWitnessInput(
+			input.TaprootToLocalRevokeWitnessSize,
+		)
+		weightEstimate.AddWitnessInput(input.TaprootToRemoteWitnessSize)
 
-	if isAnchorChannel {
+	case isAnchorChannel:
+		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize)
 		weightEstimate.AddWitnessInput(input.ToRemoteConfirmedWitnessSize)
-	} else {
+
+	default:
+		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize - 1)
 		weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
 	}
 	weightEstimate.AddP2WKHOutput()
@@ -297,6 +335,26 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		HashType:      txscript.SigHashAll,
 	}
 
+	// Taproot channels are spent over the script path of the revocation
+	// and to-remote leaves, with signatures committing to both spent
+	// outputs.
+	if isTaprootChannel {
+		prevOuts := input.MultiPrevOutFetcher{
+			justiceTxn.TxIn[0].PreviousOutPoint: breachTxn.TxOut[0],
+			justiceTxn.TxIn[1].PreviousOutPoint: breachTxn.TxOut[1],
+		}
+
+		err = toLocalTree.SetScriptSpend(
+			toLocalSignDesc, input.TaprootRevokeLeaf,
+		)
+		require.Nil(t, err)
+		toLocalSignDesc.PrevOutputFetcher = prevOuts
+
+		err = toRemoteTree.SetScriptSpend(toRemoteSignDesc, 0)
+		require.Nil(t, err)
+		toRemoteSignDesc.PrevOutputFetcher = prevOuts
+	}
+
 	// Verify that our test justice transaction is sane.
 	btx := btcutil.NewTx(justiceTxn)
 	err = blockchain.CheckTransactionSanity(btx)
@@ -353,6 +411,34 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		t.Fatalf("punisher did not publish justice txn")
 	}
 
+	if isTaprootChannel {
+		// Construct the test's to-local witness, which spends the
+		// revocation leaf with a signature without sighash flag.
+		toLocalCtrlBlock, err := toLocalTree.ControlBlock(
+			input.TaprootRevokeLeaf,
+		)
+		require.Nil(t, err)
+
+		justiceTxn.TxIn[0].Witness = [][]byte{
+			toLocalSigRaw.Serialize(), toLocalScript,
+			toLocalCtrlBlock,
+		}
+
+		// Construct the test's to-remote witness.
+		toRemoteCtrlBlock, err := toRemoteTree.ControlBlock(0)
+		require.Nil(t, err)
+
+		justiceTxn.TxIn[1].Witness = [][]byte{
+			toRemoteSigRaw.Serialize(), toRemoteRedeemScript,
+			toRemoteCtrlBlock,
+		}
+
+		// Assert that the watchtower derives the same justice txn.
+		require.Equal(t, justiceTxn, wtJusticeTxn)
+
+		return
+	}
+
 	// Construct the test's to-local witness.
 	justiceTxn.TxIn[0].Witness = make([][]byte, 3)
 	justiceTxn.TxIn[0].Witness[0] = append(toLocalSigRaw.Serialize(),
@@ -80,9 +80,14 @@ func newBackupTask(chanID *lnwire.ChannelID,
 	// to that output as local, though relative to their commitment, it is
 	// paying to-the-remote party (which is us).
 	if breachInfo.RemoteOutputSignDesc != nil {
+		witnessType := input.CommitmentRevoke
+		if chanType.IsTaproot() {
+			witnessType = input.TaprootCommitmentRevoke
+		}
+
 		toLocalInput = input.NewBaseInput(
 			&breachInfo.RemoteOutpoint,
-			input.CommitmentRevoke,
+			witnessType,
 			breachInfo.RemoteOutputSignDesc,
 			0,
 		)
@@ -91,6 +96,8 @@ func newBackupTask(chanID *lnwire.ChannelID,
 	if breachInfo.LocalOutputSignDesc != nil {
 		var witnessType input.WitnessType
 		switch {
+		case chanType.IsTaproot():
+			witnessType = input.TaprootCommitmentToRemoteConfirmed
 		case chanType.HasAnchors():
 			witnessType = input.CommitmentToRemoteConfirmed
 		case chanType.IsTweakless():
@@ -99,11 +106,12 @@ func newBackupTask(chanID *lnwire.ChannelID,
 			witnessType = input.CommitmentNoDelay
 		}
 
-		// Anchor channels have a CSV-encumbered to-remote output. We'll
-		// construct a CSV input in that case and assign the proper CSV
-		// delay of 1, otherwise we fallback to the a regular P2WKH
-		// to-remote output for tweaked or tweakless channels.
-		if chanType.HasAnchors() {
+		// Anchor and taproot channels have a CSV-encumbered to-remote
+		// output. We'll construct a CSV input in that case and assign
+		// the proper CSV delay of 1, otherwise we fallback to the a
+		// regular P2WKH to-remote output for tweaked or tweakless
+		// channels.
+		if chanType.HasAnchors() || chanType.IsTaproot() {
 			toRemoteInput = input.NewCsvInput(
 				&breachInfo.LocalOutpoint,
 				witnessType,
@@ -171,11 +179,18 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody) error {
 		// original weight estimate. For anchor channels we'll go ahead
 		// an use the correct penalty witness when signing our justice
 		// transactions.
-		if t.chanType.HasAnchors() {
+		switch {
+		case t.chanType.IsTaproot():
+			weightEstimate.AddWitnessInput(
+				input.TaprootToLocalRevokeWitnessSize,
+			)
+
+		case t.chanType.HasAnchors():
 			weightEstimate.AddWitnessInput(
 				input.ToLocalPenaltyWitnessSize,
 			)
-		} else {
+
+		default:
 			weightEstimate.AddWitnessInput(
 				input.ToLocalPenaltyWitnessSize - 1,
 			)
@@ -184,10 +199,18 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody) error {
 	if t.toRemoteInput != nil {
 		// Legacy channels (both tweaked and non-tweaked) spend from
 		// P2WKH output. Anchor channels spend a to-remote confirmed
-		// P2WSH  output.
-		if t.chanType.HasAnchors() {
+		// P2WSH  output, and taproot channels the leaf of a taproot
+		// to-remote output.
+		switch {
+		case t.chanType.IsTaproot():
+			weightEstimate.AddWitnessInput(
+				input.TaprootToRemoteWitnessSize,
+			)
+
+		case t.chanType.HasAnchors():
 			weightEstimate.AddWitnessInput(input.ToRemoteConfirmedWitnessSize)
-		} else {
+
+		default:
 			weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
 		}
 	}
@@ -201,12 +224,21 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody) error {
 		weightEstimate.AddP2WKHOutput()
 	}
 
-	if t.chanType.HasAnchors() != session.Policy.IsAnchorChannel() {
+	// Taproot channels also have anchor outputs, but are backed up to
+	// sessions of their own blob type.
+	hasAnchors := t.chanType.HasAnchors() && !t.chanType.IsTaproot()
+	if hasAnchors != session.Policy.IsAnchorChannel() {
 		log.Criticalf("Invalid task (has_anchors=%t) for session "+
-			"(has_anchors=%t)", t.chanType.HasAnchors(),
+			"(has_anchors=%t)", hasAnchors,
 			session.Policy.IsAnchorChannel())
 	}
 
+	if t.chanType.IsTaproot() != session.Policy.IsTaprootChannel() {
+		log.Criticalf("Invalid task (is_taproot=%t) for session "+
+			"(is_taproot=%t)", t.chanType.IsTaproot(),
+			session.Policy.IsTaprootChannel())
+	}
+
 	// Now, compute the output values depending on whether FlagReward is set
 	// in the current session's policy.
 	outputs, err := session.Policy.ComputeJusticeTxOuts(
@@ -287,6 +319,14 @@ func (t *backupTask) craftSessionPayload(
 	// Construct a sighash cache to improve signing performance.
 	hashCache := txscript.NewTxSigHashes(justiceTxn)
 
+	// Taproot signatures commit to all outputs spent by the justice
+	// transaction, so each input needs to be able to look them up.
+	inputList := make([]input.Input, 0, len(inputs))
+	for _, inp := range inputs {
+		inputList = append(inputList, inp)
+	}
+	input.SetPrevOutputFetcher(inputList...)
+
 	// Since the transaction inputs could have been reordered as a result of
 	// the BIP69 sort, create an index mapping each prevout to it's new
 	// index.
@@ -310,15 +350,12 @@ func (t *backupTask) craftSessionPayload(
 			return hint, nil, err
 		}
 
-		// Parse the DER-encoded signature from the first position of
-		// the resulting witness. We trim an extra byte to remove the
-		// sighash flag.
-		witness := inputScript.Witness
-		rawSignature := witness[0][:len(witness[0])-1]
-
-		// Reencode the DER signature into a fixed-size 64 byte
+		// Parse the signature from the first position of the resulting
+		// witness, and reencode it into a fixed-size 64 byte
 		// signature.
-		signature, err := lnwire.NewSigFromRawSignature(rawSignature)
+		signature, err := parseWitnessSig(
+			inputScript.Witness, inp.WitnessType(),
+		)
 		if err != nil {
 			return hint, nil, err
 		}
@@ -328,6 +365,8 @@ func (t *backupTask) craftSessionPayload(
 		// field.
 		switch inp.WitnessType() {
 		case input.CommitmentRevoke:
+			fallthrough
+		case input.TaprootCommitmentRevoke:
 			copy(justiceKit.CommitToLocalSig[:], signature[:])
 
 		case input.CommitSpendNoDelayTweakless:
@@ -335,6 +374,8 @@ func (t *backupTask) craftSessionPayload(
 		case input.CommitmentNoDelay:
 			fallthrough
 		case input.CommitmentToRemoteConfirmed:
+			fallthrough
+		case input.TaprootCommitmentToRemoteConfirmed:
 			copy(justiceKit.CommitToRemoteSig[:], signature[:])
 		default:
 			return hint, nil, fmt.Errorf("invalid witness type: %v",
@@ -358,6 +399,37 @@ func (t *backupTask) craftSessionPayload(
 	return hint, encBlob, nil
 }
 
+// parseWitnessSig extracts the signature from the first position of a witness
+// of the given type as a fixed-size 64 byte signature. Taproot witnesses carry
+// a BIP-340 signature that is copied as is, otherwise the DER-encoded
+// signature is reencoded after trimming the sighash flag.
+func parseWitnessSig(witness wire.TxWitness,
+	witnessType input.WitnessType) (lnwire.Sig, error) {
+
+	if len(witness) == 0 {
+		return lnwire.Sig{}, fmt.Errorf("empty witness for witness "+
+			"type %v", witnessType)
+	}
+
+	switch witnessType {
+	case input.TaprootCommitmentRevoke,
+		input.TaprootCommitmentToRemoteConfirmed:
+
+		sig, _, err := input.ParseTaprootWitnessSig(witness[0])
+		if err != nil {
+			return lnwire.Sig{}, err
+		}
+
+		return lnwire.NewSigFromSignature(sig)
+
+	default:
+		// We trim an extra byte to remove the sighash flag.
+		rawSignature := witness[0][:len(witness[0])-1]
+
+		return lnwire.NewSigFromRawSignature(rawSignature)
+	}
+}
+
 // toBlobPubKey serializes the given pubkey into a blob.PubKey that can be set
 // as a field on a blob.JusticeKit.
 func toBlobPubKey(pubKey *btcec.PublicKey) blob.PubKey {
@@ -95,9 +95,12 @@ func genTaskTest(
 	bindErr error,
 	chanType channeldb.ChannelType) backupTaskTest {
 
-	// Set the anchor flag in the blob type if the session needs to support
-	// anchor channels.
-	if chanType.HasAnchors() {
+	// Set the anchor or taproot flag in the blob type if the session needs
+	// to support anchor or taproot channels.
+	switch {
+	case chanType.IsTaproot():
+		blobType |= blob.Type(blob.FlagTaprootChannel)
+	case chanType.HasAnchors():
 		blobType |= blob.Type(blob.FlagAnchorChannel)
 	}
 
@@ -152,6 +155,26 @@ func genTaskTest(
 			},
 			HashType: txscript.SigHashAll,
 		}
+
+		// Taproot to-local outputs are swept over their revocation
+		// leaf.
+		if chanType.IsTaproot() {
+			tree, err := input.TaprootCommitScriptToSelf(
+				csvDelay, toLocalPK, revPK,
+			)
+			if err != nil {
+				panic(err)
+			}
+
+			toLocalSignDesc.Output.PkScript = tree.PkScript
+			err = tree.SetScriptSpend(
+				toLocalSignDesc, input.TaprootRevokeLeaf,
+			)
+			if err != nil {
+				panic(err)
+			}
+		}
+
 		breachInfo.RemoteOutputSignDesc = toLocalSignDesc
 		breachTxn.AddTxOut(toLocalSignDesc.Output)
 	}
@@ -166,6 +189,23 @@ func genTaskTest(
 			},
 			HashType: txscript.SigHashAll,
 		}
+
+		// Taproot to-remote outputs are swept over their only leaf.
+		if chanType.IsTaproot() {
+			tree, err := input.TaprootCommitScriptToRemote(
+				toRemotePK,
+			)
+			if err != nil {
+				panic(err)
+			}
+
+			toRemoteSignDesc.Output.PkScript = tree.PkScript
+			err = tree.SetScriptSpend(toRemoteSignDesc, 0)
+			if err != nil {
+				panic(err)
+			}
+		}
+
 		breachInfo.LocalOutputSignDesc = toRemoteSignDesc
 		breachTxn.AddTxOut(toRemoteSignDesc.Output)
 	}
@@ -185,9 +225,14 @@ func genTaskTest(
 			Hash:  txid,
 			Index: index,
 		}
+		witnessType := input.CommitmentRevoke
+		if chanType.IsTaproot() {
+			witnessType = input.TaprootCommitmentRevoke
+		}
+
 		toLocalInput = input.NewBaseInput(
 			&breachInfo.RemoteOutpoint,
-			input.CommitmentRevoke,
+			witnessType,
 			breachInfo.RemoteOutputSignDesc,
 			0,
 		)
@@ -201,6 +246,8 @@ func genTaskTest(
 
 		var witnessType input.WitnessType
 		switch {
+		case chanType.IsTaproot():
+			witnessType = input.TaprootCommitmentToRemoteConfirmed
 		case chanType.HasAnchors():
 			witnessType = input.CommitmentToRemoteConfirmed
 		case chanType.IsTweakless():
@@ -278,6 +325,8 @@ func TestBackupTask(t *testing.T) {
 		channeldb.SingleFunderBit,
 		channeldb.SingleFunderTweaklessBit,
 		channeldb.AnchorOutputsBit,
+		channeldb.SingleFunderTweaklessBit | channeldb.AnchorOutputsBit |
+			channeldb.ZeroHtlcTxFeeBit | channeldb.SimpleTaprootBit,
 	}
 
 	var backupTaskTests []backupTaskTest
@@ -289,6 +338,8 @@ func TestBackupTask(t *testing.T) {
 		//   - anchor to-remote outputs require a P2WSH sweep rather
 		//     than a P2WKH sweep.
 		//   - the to-local weight estimate fixes an off-by-one.
+		//   - taproot outputs are swept over a tapscript leaf, whose
+		//     witness includes the leaf script and a control block.
 		// In tests related to the dust threshold, the size difference
 		// between the channel types makes it so that the threshold fee
 		// rate is slightly lower (since the transactions are heavier).
@@ -302,7 +353,18 @@ func TestBackupTask(t *testing.T) {
 			sweepFeeRateNoRewardRemoteDust chainfee.SatPerKWeight = 227500
 			sweepFeeRateRewardRemoteDust   chainfee.SatPerKWeight = 175000
 		)
-		if chanType.HasAnchors() {
+		switch {
+		case chanType.IsTaproot():
+			expSweepCommitNoRewardBoth = 299165
+			expSweepCommitNoRewardLocal = 199468
+			expSweepCommitNoRewardRemote = 99531
+			expSweepCommitRewardBoth = 296041
+			expSweepCommitRewardLocal = 197344
+			expSweepCommitRewardRemote = 98407
+			sweepFeeRateNoRewardRemoteDust = 212500
+			sweepFeeRateRewardRemoteDust = 166250
+
+		case chanType.HasAnchors():
 			expSweepCommitNoRewardBoth = 299236
 			expSweepCommitNoRewardLocal = 199513
 			expSweepCommitNoRewardRemote = 99557
@@ -44,11 +44,14 @@ const (
 )
 
 // genActiveSessionFilter generates a filter that selects active sessions that
-// also match the desired channel type, either legacy or anchor.
-func genActiveSessionFilter(anchor bool) func(*wtdb.ClientSession) bool {
+// also match the desired channel type, either legacy, anchor or taproot.
+func genActiveSessionFilter(anchor,
+	taproot bool) func(*wtdb.ClientSession) bool {
+
 	return func(s *wtdb.ClientSession) bool {
 		return s.Status == wtdb.CSessionActive &&
-			anchor == s.Policy.IsAnchorChannel()
+			anchor == s.Policy.IsAnchorChannel() &&
+			taproot == s.Policy.IsTaprootChannel()
 	}
 }
 
@@ -282,7 +285,10 @@ func New(config *Config) (*TowerClient, error) {
 	}
 
 	prefix := "(legacy)"
-	if cfg.Policy.IsAnchorChannel() {
+	switch {
+	case cfg.Policy.IsTaprootChannel():
+		prefix = "(taproot)"
+	case cfg.Policy.IsAnchorChannel():
 		prefix = "(anchor)"
 	}
 	plog := build.NewPrefixLog(prefix, log)
@@ -292,7 +298,10 @@ func New(config *Config) (*TowerClient, error) {
 	// the current policy of the client, otherwise they will be ignored and
 	// new sessions will be requested.
 	isAnchorClient := cfg.Policy.IsAnchorChannel()
-	activeSessionFilter := genActiveSessionFilter(isAnchorClient)
+	isTaprootClient := cfg.Policy.IsTaprootChannel()
+	activeSessionFilter := genActiveSessionFilter(
+		isAnchorClient, isTaprootClient,
+	)
 	candidateSessions, err := getClientSessions(
 		cfg.DB, cfg.SecretKeyRing, nil, activeSessionFilter,
 	)
@@ -1085,7 +1094,10 @@ func (c *TowerClient) handleNewTower(msg *newTowerMsg) error {
 
 	// Include all of its corresponding sessions to our set of candidates.
 	isAnchorClient := c.cfg.Policy.IsAnchorChannel()
-	activeSessionFilter := genActiveSessionFilter(isAnchorClient)
+	isTaprootClient := c.cfg.Policy.IsTaprootChannel()
+	activeSessionFilter := genActiveSessionFilter(
+		isAnchorClient, isTaprootClient,
+	)
 	sessions, err := getClientSessions(
 		c.cfg.DB, c.cfg.SecretKeyRing, &tower.ID, activeSessionFilter,
 	)
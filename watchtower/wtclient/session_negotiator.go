@@ -121,13 +121,16 @@ func newSessionNegotiator(cfg *NegotiatorConfig) *sessionNegotiator {
 	// Generate the set of features the negitator will present to the tower
 	// upon connection. For anchor channels, we'll conditionally signal that
 	// we require support for anchor channels depdening on the requested
-	// policy.
+	// policy. The same holds for simple taproot channels.
 	features := []lnwire.FeatureBit{
 		wtwire.AltruistSessionsRequired,
 	}
 	if cfg.Policy.IsAnchorChannel() {
 		features = append(features, wtwire.AnchorCommitRequired)
 	}
+	if cfg.Policy.IsTaprootChannel() {
+		features = append(features, wtwire.TaprootCommitRequired)
+	}
 
 	localInit := wtwire.NewInitMessage(
 		lnwire.NewRawFeatureVector(features...),
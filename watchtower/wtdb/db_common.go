@@ -67,7 +67,7 @@ func createDBIfNotExist(dbPath, name string,
 	}
 
 	ldb, err := kvdb.Create(
-		kvdb.LdbBackendName, path, dbTimeout,
+		kvdb.LdbBackendName, path, true, dbTimeout,
 	)
 	if err != nil {
 		return nil, false, err
@@ -8,6 +8,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/schnorr"
 )
 
 // MockSigner is an input.Signer that allows one to add arbitrary private keys
@@ -42,6 +43,10 @@ func (s *MockSigner) SignOutputRaw(tx *wire.MsgTx,
 		panic("cannot sign w/ unknown key")
 	}
 
+	if signDesc.SignMethod.IsTaproot() {
+		return input.SignTaproot(tx, signDesc, privKey)
+	}
+
 	sig, err := txscript.RawTxInWitnessSignature(
 		tx, signDesc.SigHashes, signDesc.InputIndex, amt,
 		witnessScript, signDesc.HashType, privKey,
@@ -53,6 +58,23 @@ func (s *MockSigner) SignOutputRaw(tx *wire.MsgTx,
 	return btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
 }
 
+// MuSig2Sign creates a MuSig2 partial signature for the signing session with
+// the key of the key descriptor's locator.
+func (s *MockSigner) MuSig2Sign(keyDesc *keychain.KeyDescriptor,
+	nonces *schnorr.Nonces, session *schnorr.Session) (schnorr.PartialSig,
+	error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	privKey, ok := s.keys[keyDesc.KeyLocator]
+	if !ok {
+		panic("cannot sign w/ unknown key")
+	}
+
+	return session.Sign(nonces, privKey)
+}
+
 // ComputeInputScript is not implemented.
 func (s *MockSigner) ComputeInputScript(tx *wire.MsgTx,
 	signDesc *input.SignDescriptor) (*input.Script, error) {
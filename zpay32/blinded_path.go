@@ -0,0 +1,145 @@
+package zpay32
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+)
+
+// BlindedPaymentPath is a blinded route towards the receiver of an invoice,
+// along with the aggregated forwarding policy of all hops in the route. The
+// sender treats the whole path as a single hop with the aggregated policy.
+type BlindedPaymentPath struct {
+	// FeeBaseMsat is the aggregated base fee of the path in
+	// millisatoshis.
+	FeeBaseMsat uint32
+
+	// FeeRate is the aggregated proportional fee of the path in
+	// millionths.
+	FeeRate uint32
+
+	// CltvExpiryDelta is the aggregated time-lock delta of the path,
+	// including the final cltv delta of the receiver.
+	CltvExpiryDelta uint16
+
+	// HTLCMinMsat is the minimum amount an HTLC must carry to be forwarded
+	// along the path.
+	HTLCMinMsat uint64
+
+	// HTLCMaxMsat is the maximum amount an HTLC can carry to be forwarded
+	// along the path.
+	HTLCMaxMsat uint64
+
+	// Features are the features that need to be supported by the sender to
+	// use the path.
+	Features *lnwire.FeatureVector
+
+	// Hops is the blinded route itself.
+	Hops *blindedpath.BlindedPath
+}
+
+// encodeBlindedPaymentPath serializes a blinded payment path:
+//
+//  fee_base_msat (4) | fee_rate (4) | cltv_expiry_delta (2) |
+//  htlc_min_msat (8) | htlc_max_msat (8) | features (2 + len) |
+//  blinded_path
+func encodeBlindedPaymentPath(w io.Writer, path *BlindedPaymentPath) error {
+	if path.Hops == nil {
+		return blindedpath.ErrNoHops
+	}
+
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[:4], path.FeeBaseMsat)
+	if _, err := w.Write(b[:4]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(b[:4], path.FeeRate)
+	if _, err := w.Write(b[:4]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint16(b[:2], path.CltvExpiryDelta)
+	if _, err := w.Write(b[:2]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(b[:], path.HTLCMinMsat)
+	if _, err := w.Write(b[:]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(b[:], path.HTLCMaxMsat)
+	if _, err := w.Write(b[:]); err != nil {
+		return err
+	}
+
+	features := lnwire.NewRawFeatureVector()
+	if path.Features != nil {
+		features = path.Features.RawFeatureVector
+	}
+	if err := features.Encode(w); err != nil {
+		return err
+	}
+
+	return path.Hops.Encode(w)
+}
+
+// decodeBlindedPaymentPath parses a blinded payment path that was serialized
+// with encodeBlindedPaymentPath.
+func decodeBlindedPaymentPath(r io.Reader) (*BlindedPaymentPath, error) {
+	var (
+		path BlindedPaymentPath
+		b    [8]byte
+	)
+
+	if _, err := io.ReadFull(r, b[:4]); err != nil {
+		return nil, err
+	}
+	path.FeeBaseMsat = binary.BigEndian.Uint32(b[:4])
+
+	if _, err := io.ReadFull(r, b[:4]); err != nil {
+		return nil, err
+	}
+	path.FeeRate = binary.BigEndian.Uint32(b[:4])
+
+	if _, err := io.ReadFull(r, b[:2]); err != nil {
+		return nil, err
+	}
+	path.CltvExpiryDelta = binary.BigEndian.Uint16(b[:2])
+
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+	path.HTLCMinMsat = binary.BigEndian.Uint64(b[:])
+
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+	path.HTLCMaxMsat = binary.BigEndian.Uint64(b[:])
+
+	features := lnwire.NewRawFeatureVector()
+	if err := features.Decode(r); err != nil {
+		return nil, err
+	}
+	path.Features = lnwire.NewFeatureVector(features, lnwire.Features)
+
+	hops, err := blindedpath.DecodeBlindedPath(r)
+	if err != nil {
+		return nil, err
+	}
+	path.Hops = hops
+
+	return &path, nil
+}
+
+// parseBlindedPaymentPath converts the data (encoded in base32) into a blinded
+// payment path.
+func parseBlindedPaymentPath(data []byte) (*BlindedPaymentPath, error) {
+	base256Data, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBlindedPaymentPath(bytes.NewReader(base256Data))
+}
@@ -68,6 +68,9 @@ const (
 	// supported or required by the receiver.
 	fieldType9 = 5
 
+	// fieldTypeB contains a blinded payment path towards the receiver.
+	fieldTypeB = 20
+
 	// fieldTypeS contains a 32-byte payment address, which is a nonce
 	// included in the final hop's payload to prevent intermediaries from
 	// probing the recipient.
@@ -180,6 +183,13 @@ type Invoice struct {
 	// NOTE: This is optional.
 	RouteHints [][]HopHint
 
+	// BlindedPaymentPaths are blinded routes towards the destination,
+	// which hide the identity of the destination and its channels from the
+	// sender. Route hints and blinded payment paths can't be combined.
+	//
+	// NOTE: This is optional.
+	BlindedPaymentPaths []*BlindedPaymentPath
+
 	// Features represents an optional field used to signal optional or
 	// required support for features by the receiver.
 	Features *lnwire.FeatureVector
@@ -256,6 +266,14 @@ func RouteHint(routeHint []HopHint) func(*Invoice) {
 	}
 }
 
+// WithBlindedPaymentPath is a functional option that allows callers of
+// NewInvoice to add a blinded route towards the destination.
+func WithBlindedPaymentPath(path *BlindedPaymentPath) func(*Invoice) {
+	return func(i *Invoice) {
+		i.BlindedPaymentPaths = append(i.BlindedPaymentPaths, path)
+	}
+}
+
 // Features is a functional option that allows callers of NewInvoice to set the
 // desired feature bits that are advertised on the invoice. If this option is
 // not used, an empty feature vector will automatically be populated.
@@ -365,6 +383,13 @@ func validateInvoice(invoice *Invoice) error {
 			len(invoice.Destination.SerializeCompressed()))
 	}
 
+	// Route hints reveal the channels of the destination, so they can't be
+	// combined with blinded payment paths.
+	if len(invoice.RouteHints) > 0 && len(invoice.BlindedPaymentPaths) > 0 {
+		return fmt.Errorf("both route hints and blinded payment paths " +
+			"set")
+	}
+
 	// Ensure that all invoices have feature vectors.
 	if invoice.Features == nil {
 		return fmt.Errorf("missing feature vector")
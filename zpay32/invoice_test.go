@@ -18,6 +18,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 
 	litecoinCfg "github.com/ltcsuite/ltcd/chaincfg"
 )
@@ -870,6 +871,76 @@ func TestNewInvoice(t *testing.T) {
 	}
 }
 
+// TestBlindedPaymentPath tests that blinded payment paths survive an encoding
+// round trip and can't be combined with route hints.
+func TestBlindedPaymentPath(t *testing.T) {
+	t.Parallel()
+
+	features := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.RouteBlindingRequired),
+		lnwire.Features,
+	)
+	path := &BlindedPaymentPath{
+		FeeBaseMsat:     1000,
+		FeeRate:         10,
+		CltvExpiryDelta: 184,
+		HTLCMinMsat:     1,
+		HTLCMaxMsat:     100000000,
+		Features:        features,
+		Hops: &blindedpath.BlindedPath{
+			IntroductionPoint: testHopHintPubkey1,
+			BlindingPoint:     testHopHintPubkey2,
+			BlindedHops: []*blindedpath.BlindedHop{{
+				BlindedNodePub: testHopHintPubkey1,
+				CipherText:     []byte{1, 2, 3, 4},
+			}, {
+				BlindedNodePub: testPubKey,
+				CipherText:     bytes.Repeat([]byte{5}, 50),
+			}},
+		},
+	}
+
+	invoice, err := NewInvoice(
+		&chaincfg.MainNetParams, testPaymentHash,
+		time.Unix(1496314658, 0), Amount(testMillisat20mBTC),
+		Description(testCupOfCoffee), WithBlindedPaymentPath(path),
+	)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+
+	encoded, err := invoice.Encode(testMessageSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+
+	decoded, err := Decode(encoded, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+
+	if len(decoded.BlindedPaymentPaths) != 1 {
+		t.Fatalf("expected 1 blinded payment path, got %d",
+			len(decoded.BlindedPaymentPaths))
+	}
+	if !reflect.DeepEqual(path, decoded.BlindedPaymentPaths[0]) {
+		t.Fatalf("expected blinded payment path %v, got %v", path,
+			decoded.BlindedPaymentPaths[0])
+	}
+
+	// Adding a route hint to the invoice must make it invalid.
+	_, err = NewInvoice(
+		&chaincfg.MainNetParams, testPaymentHash,
+		time.Unix(1496314658, 0), Amount(testMillisat20mBTC),
+		Description(testCupOfCoffee), WithBlindedPaymentPath(path),
+		RouteHint(testSingleHop),
+	)
+	if err == nil {
+		t.Fatalf("expected invoice with route hints and blinded " +
+			"payment paths to be invalid")
+	}
+}
+
 // TestMaxInvoiceLength tests that attempting to decode an invoice greater than
 // maxInvoiceLength fails with ErrInvoiceTooLarge.
 func TestMaxInvoiceLength(t *testing.T) {